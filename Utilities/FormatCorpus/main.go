@@ -0,0 +1,47 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/FactomProject/factomd/common/serialization"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func main() {
+	out := flag.String("out", "", "Write a fixture of every message and block type, in every format version, to this directory")
+	check := flag.String("check", "", "Check that every fixture in this directory reads and writes the same in every format version")
+	flag.Parse()
+
+	if *out == "" && *check == "" {
+		fmt.Println("Usage:")
+		fmt.Println("FormatCorpus -out common/serialization/testdata")
+		fmt.Println("FormatCorpus -check common/serialization/testdata")
+		os.Exit(1)
+	}
+
+	if *out != "" {
+		fixtures := testHelper.CreateTestFormatCorpus()
+		if err := serialization.WriteCorpus(*out, fixtures); err != nil {
+			fmt.Println("Cannot write the corpus:", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Wrote %d fixtures to %s\n", len(fixtures), *out)
+	}
+
+	if *check != "" {
+		checked, problems, err := serialization.CheckCorpus(*check)
+		if err != nil {
+			fmt.Println("Cannot read the corpus:", err)
+			os.Exit(1)
+		}
+		for _, p := range problems {
+			fmt.Println(p)
+		}
+		fmt.Printf("Checked %d fixtures in %s, %d problems\n", checked, *check, len(problems))
+		if len(problems) > 0 {
+			os.Exit(1)
+		}
+	}
+}
@@ -0,0 +1,23 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package anchor
+
+import (
+	"encoding/binary"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+)
+
+// BitcoinAnchorData returns the OP_RETURN data anchoring a directory block into Bitcoin:
+// "Fa", the height as 6 bytes and the KeyMR
+func BitcoinAnchorData(dbHeight uint32, keyMR interfaces.IHash) []byte {
+	data := make([]byte, 2+6+32)
+	copy(data, "Fa")
+	var height [8]byte
+	binary.BigEndian.PutUint64(height[:], uint64(dbHeight))
+	copy(data[2:], height[2:])
+	copy(data[8:], keyMR.Bytes())
+	return data
+}
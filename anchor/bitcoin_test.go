@@ -0,0 +1,22 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package anchor_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	. "github.com/FactomProject/factomd/anchor"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+func TestBitcoinAnchorData(t *testing.T) {
+	keyMR, _ := primitives.HexToHash("637b6010cb6121f76c65b200a6cf94cb6655881fb4cac48979f8950e7a349da1")
+	data := BitcoinAnchorData(0x1234, keyMR)
+	expected := "4661" + "000000001234" + "637b6010cb6121f76c65b200a6cf94cb6655881fb4cac48979f8950e7a349da1"
+	if hex.EncodeToString(data) != expected {
+		t.Errorf("Wrong OP_RETURN data %x", data)
+	}
+}
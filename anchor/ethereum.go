@@ -0,0 +1,160 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package anchor
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+)
+
+// Selector of setAnchor(uint256 blockNumber, uint256 merkleRoot), the first four bytes of
+// the Keccak-256 of the signature, as the Factom anchor contract declares it
+var SetAnchorSelector = []byte{0xbb, 0xcc, 0x0c, 0x80}
+
+// ErrAnchorReverted is returned for an anchor transaction that was mined but failed
+var ErrAnchorReverted = errors.New("Anchor transaction reverted")
+
+// EthereumClient anchors directory block KeyMRs into an Ethereum contract, through the
+// JSON-RPC API of an Ethereum node holding the unlocked account they are sent from.
+type EthereumClient struct {
+	URL      string // JSON-RPC endpoint of the Ethereum node
+	From     string // Account the transactions are sent from, 0x prefixed
+	Contract string // Address of the anchor contract, 0x prefixed
+	GasLimit uint64 // 0 leaves it to the node to estimate
+
+	HTTPClient *http.Client
+
+	id uint64
+}
+
+func NewEthereumClient(url string, from string, contract string) *EthereumClient {
+	c := new(EthereumClient)
+	c.URL = url
+	c.From = from
+	c.Contract = contract
+	c.HTTPClient = &http.Client{Timeout: 30 * time.Second}
+	return c
+}
+
+// SetAnchorData returns the call data of setAnchor for a directory block
+func SetAnchorData(dbHeight uint32, keyMR interfaces.IHash) []byte {
+	data := make([]byte, 4+32+32)
+	copy(data, SetAnchorSelector)
+	binary.BigEndian.PutUint32(data[4+28:], dbHeight)
+	copy(data[4+32:], keyMR.Bytes())
+	return data
+}
+
+// SubmitAnchor sends the transaction anchoring a directory block KeyMR, returning its
+// transaction hash.  The anchor isn't in a block until TransactionReceipt says so.
+func (c *EthereumClient) SubmitAnchor(dbHeight uint32, keyMR interfaces.IHash) (string, error) {
+	tx := map[string]string{
+		"from": c.From,
+		"to":   c.Contract,
+		"data": "0x" + hex.EncodeToString(SetAnchorData(dbHeight, keyMR)),
+	}
+	if c.GasLimit > 0 {
+		tx["gas"] = "0x" + strconv.FormatUint(c.GasLimit, 16)
+	}
+	var txID string
+	if err := c.call("eth_sendTransaction", []interface{}{tx}, &txID); err != nil {
+		return "", err
+	}
+	return txID, nil
+}
+
+// TransactionReceipt returns where an anchor transaction was mined, or nil if it hasn't
+// been yet.  A transaction the contract reverted is an error.
+func (c *EthereumClient) TransactionReceipt(txID string) (*EthereumStruct, error) {
+	var receipt *struct {
+		BlockHash        string `json:"blockHash"`
+		BlockNumber      string `json:"blockNumber"`
+		TransactionIndex string `json:"transactionIndex"`
+		Status           string `json:"status"`
+	}
+	if err := c.call("eth_getTransactionReceipt", []interface{}{txID}, &receipt); err != nil {
+		return nil, err
+	}
+	if receipt == nil || receipt.BlockNumber == "" {
+		return nil, nil
+	}
+	if receipt.Status == "0x0" {
+		return nil, ErrAnchorReverted
+	}
+	height, err := parseQuantity(receipt.BlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	offset, err := parseQuantity(receipt.TransactionIndex)
+	if err != nil {
+		return nil, err
+	}
+	eth := new(EthereumStruct)
+	eth.Address = c.Contract
+	eth.TXID = txID
+	eth.BlockHeight = height
+	eth.BlockHash = receipt.BlockHash
+	eth.Offset = offset
+	return eth, nil
+}
+
+// parseQuantity decodes a 0x prefixed hex number, as Ethereum JSON-RPC returns them
+func parseQuantity(s string) (int64, error) {
+	if !strings.HasPrefix(s, "0x") {
+		return 0, fmt.Errorf("Invalid quantity %q", s)
+	}
+	return strconv.ParseInt(s[2:], 16, 64)
+}
+
+type ethRequest struct {
+	JSONRPC string        `json:"jsonrpc"`
+	ID      uint64        `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+type ethResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func (c *EthereumClient) call(method string, params []interface{}, result interface{}) error {
+	body, err := json.Marshal(&ethRequest{JSONRPC: "2.0", ID: atomic.AddUint64(&c.id, 1), Method: method, Params: params})
+	if err != nil {
+		return err
+	}
+	resp, err := c.HTTPClient.Post(c.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	r := new(ethResponse)
+	if err := json.Unmarshal(data, r); err != nil {
+		return fmt.Errorf("%s: unexpected response (%s): %v", method, resp.Status, err)
+	}
+	if r.Error != nil {
+		return fmt.Errorf("%s: %d %s", method, r.Error.Code, r.Error.Message)
+	}
+	return json.Unmarshal(r.Result, result)
+}
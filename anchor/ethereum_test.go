@@ -0,0 +1,110 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package anchor_test
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/FactomProject/factomd/anchor"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// fakeEthereumNode answers eth_sendTransaction with txID, and eth_getTransactionReceipt
+// with receipt, keeping the last transaction sent
+type fakeEthereumNode struct {
+	txID    string
+	receipt interface{}
+	sent    map[string]string
+}
+
+func (n *fakeEthereumNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ID     uint64            `json:"id"`
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+	}
+	json.NewDecoder(r.Body).Decode(&req)
+	resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+	switch req.Method {
+	case "eth_sendTransaction":
+		json.Unmarshal(req.Params[0], &n.sent)
+		resp["result"] = n.txID
+	case "eth_getTransactionReceipt":
+		resp["result"] = n.receipt
+	default:
+		resp["error"] = map[string]interface{}{"code": -32601, "message": "method not found"}
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+func TestSetAnchorData(t *testing.T) {
+	keyMR, _ := primitives.HexToHash("637b6010cb6121f76c65b200a6cf94cb6655881fb4cac48979f8950e7a349da1")
+	data := SetAnchorData(0x1234, keyMR)
+	expected := "bbcc0c80" +
+		"0000000000000000000000000000000000000000000000000000000000001234" +
+		"637b6010cb6121f76c65b200a6cf94cb6655881fb4cac48979f8950e7a349da1"
+	if hex.EncodeToString(data) != expected {
+		t.Errorf("Wrong call data %x", data)
+	}
+}
+
+func TestEthereumSubmitAnchor(t *testing.T) {
+	node := &fakeEthereumNode{txID: "0x50ea0effc383542811a58704a6d6842ed6d76439a2d942d941896ad097c06a78"}
+	server := httptest.NewServer(node)
+	defer server.Close()
+
+	c := NewEthereumClient(server.URL, "0x01", "0x30aa981f6d2fce81083e584c8ee2f822b548752f")
+	keyMR, _ := primitives.HexToHash("637b6010cb6121f76c65b200a6cf94cb6655881fb4cac48979f8950e7a349da1")
+	txID, err := c.SubmitAnchor(8, keyMR)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if txID != node.txID {
+		t.Errorf("Wrong txid %s", txID)
+	}
+	if node.sent["from"] != "0x01" || node.sent["to"] != c.Contract {
+		t.Errorf("Wrong transaction sent: %v", node.sent)
+	}
+	if node.sent["data"] != "0x"+hex.EncodeToString(SetAnchorData(8, keyMR)) {
+		t.Errorf("Wrong call data sent: %v", node.sent["data"])
+	}
+}
+
+func TestEthereumTransactionReceipt(t *testing.T) {
+	node := new(fakeEthereumNode)
+	server := httptest.NewServer(node)
+	defer server.Close()
+	c := NewEthereumClient(server.URL, "0x01", "0x30aa981f6d2fce81083e584c8ee2f822b548752f")
+
+	// Not mined yet
+	eth, err := c.TransactionReceipt("0xab")
+	if err != nil || eth != nil {
+		t.Errorf("Expected no receipt, got %v, %v", eth, err)
+	}
+
+	node.receipt = map[string]string{
+		"blockHash":        "0x3b504616495fc9cf7be9b5b776692a9abbfb95491fa62abf62dcdf4d53ff5979",
+		"blockNumber":      "0x4788b",
+		"transactionIndex": "0x2",
+		"status":           "0x1",
+	}
+	eth, err = c.TransactionReceipt("0xab")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if eth == nil || eth.BlockHeight != 293003 || eth.Offset != 2 || eth.TXID != "0xab" || eth.Address != c.Contract {
+		t.Errorf("Wrong receipt %+v", eth)
+	}
+
+	node.receipt.(map[string]string)["status"] = "0x0"
+	_, err = c.TransactionReceipt("0xab")
+	if err != ErrAnchorReverted {
+		t.Errorf("Expected the transaction to have reverted, got %v", err)
+	}
+}
@@ -0,0 +1,3 @@
+# Builds factomd for 64 bit ARM (Graviton, Raspberry Pi 3 and 4 running a 64 bit OS, and
+# the like) as ./factomd-arm64, with the arm64 crypto provider.  See ARM64.md.
+GOOS=linux GOARCH=arm64 go build -tags sha256simd -o factomd-arm64 -ldflags "-X github.com/FactomProject/factomd/engine.Build=`git rev-parse HEAD` -X github.com/FactomProject/factomd/engine.FactomdVersion=`cat VERSION`" -v
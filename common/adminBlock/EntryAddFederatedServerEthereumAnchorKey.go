@@ -0,0 +1,138 @@
+package adminBlock
+
+import (
+	"fmt"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// Ethereum Anchor Key Entry -------------------------
+// The Ethereum account a server anchors from, as the Bitcoin anchor key entry does for
+// Bitcoin.  An Ethereum account is named by a 20 byte address, so there is no key type.
+type AddFederatedServerEthereumAnchorKey struct {
+	IdentityChainID interfaces.IHash       `json:"identitychainid"`
+	KeyPriority     byte                   `json:"keypriority"`
+	EthereumAddress primitives.ByteSlice20 `json:"ethereumaddress"`
+}
+
+var _ interfaces.IABEntry = (*AddFederatedServerEthereumAnchorKey)(nil)
+var _ interfaces.BinaryMarshallable = (*AddFederatedServerEthereumAnchorKey)(nil)
+
+func (e *AddFederatedServerEthereumAnchorKey) Init() {
+	if e.IdentityChainID == nil {
+		e.IdentityChainID = primitives.NewZeroHash()
+	}
+}
+
+func (e *AddFederatedServerEthereumAnchorKey) String() string {
+	e.Init()
+	var out primitives.Buffer
+	out.WriteString(fmt.Sprintf("    E: %35s -- %17s %8x %12s %8x %12s %8s",
+		"AddFederatedServerEthereumAnchorKey",
+		"IdentityChainID", e.IdentityChainID.Bytes()[3:5],
+		"KeyPriority", e.KeyPriority,
+		"EthereumAddress", e.EthereumAddress.String()[:8]))
+	return (string)(out.DeepCopyBytes())
+}
+
+func (c *AddFederatedServerEthereumAnchorKey) UpdateState(state interfaces.IState) error {
+	c.Init()
+	state.UpdateAuthorityFromABEntry(c)
+	return nil
+}
+
+// Create a new Ethereum Anchor Key Entry
+func NewAddFederatedServerEthereumAnchorKey(identityChainID interfaces.IHash, keyPriority byte, ethereumAddress primitives.ByteSlice20) (e *AddFederatedServerEthereumAnchorKey) {
+	e = new(AddFederatedServerEthereumAnchorKey)
+	e.IdentityChainID = identityChainID
+	e.KeyPriority = keyPriority
+	e.EthereumAddress = ethereumAddress
+	return
+}
+
+func (e *AddFederatedServerEthereumAnchorKey) Type() byte {
+	return constants.TYPE_ADD_ETH_ANCHOR_KEY
+}
+
+func (e *AddFederatedServerEthereumAnchorKey) MarshalBinary() ([]byte, error) {
+	e.Init()
+	var buf primitives.Buffer
+
+	err := buf.PushByte(e.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	err = buf.PushBinaryMarshallable(e.IdentityChainID)
+	if err != nil {
+		return nil, err
+	}
+	err = buf.PushByte(e.KeyPriority)
+	if err != nil {
+		return nil, err
+	}
+	err = buf.PushBinaryMarshallable(&e.EthereumAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.DeepCopyBytes(), nil
+}
+
+func (e *AddFederatedServerEthereumAnchorKey) UnmarshalBinaryData(data []byte) ([]byte, error) {
+	buf := primitives.NewBuffer(data)
+	b, err := buf.PopByte()
+	if err != nil {
+		return nil, err
+	}
+	if b != e.Type() {
+		return nil, fmt.Errorf("Invalid Entry type")
+	}
+
+	e.IdentityChainID = new(primitives.Hash)
+	err = buf.PopBinaryMarshallable(e.IdentityChainID)
+	if err != nil {
+		return nil, err
+	}
+	e.KeyPriority, err = buf.PopByte()
+	if err != nil {
+		return nil, err
+	}
+	err = buf.PopBinaryMarshallable(&e.EthereumAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.DeepCopyBytes(), nil
+}
+
+func (e *AddFederatedServerEthereumAnchorKey) UnmarshalBinary(data []byte) (err error) {
+	_, err = e.UnmarshalBinaryData(data)
+	return
+}
+
+func (e *AddFederatedServerEthereumAnchorKey) JSONByte() ([]byte, error) {
+	return primitives.EncodeJSON(e)
+}
+
+func (e *AddFederatedServerEthereumAnchorKey) JSONString() (string, error) {
+	return primitives.EncodeJSONString(e)
+}
+
+func (e *AddFederatedServerEthereumAnchorKey) IsInterpretable() bool {
+	return false
+}
+
+func (e *AddFederatedServerEthereumAnchorKey) Interpret() string {
+	return ""
+}
+
+func (e *AddFederatedServerEthereumAnchorKey) Hash() interfaces.IHash {
+	bin, err := e.MarshalBinary()
+	if err != nil {
+		panic(err)
+	}
+	return primitives.Sha(bin)
+}
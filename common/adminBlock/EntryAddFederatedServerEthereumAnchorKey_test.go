@@ -0,0 +1,115 @@
+package adminBlock_test
+
+import (
+	"testing"
+
+	. "github.com/FactomProject/factomd/common/adminBlock"
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestAddFederatedServerEthereumAnchorKeyTypeIDCheck(t *testing.T) {
+	a := new(AddFederatedServerEthereumAnchorKey)
+	b, err := a.MarshalBinary()
+	if err != nil {
+		t.Errorf("%v", err)
+	}
+	if b[0] != a.Type() {
+		t.Errorf("Invalid byte marshalled")
+	}
+	a2 := new(AddFederatedServerEthereumAnchorKey)
+	err = a2.UnmarshalBinary(b)
+	if err != nil {
+		t.Errorf("%v", err)
+	}
+
+	b[0] = (b[0] + 1) % 255
+	err = a2.UnmarshalBinary(b)
+	if err == nil {
+		t.Errorf("No error caught")
+	}
+}
+
+func TestUnmarshalNilAddFederatedServerEthereumAnchorKey(t *testing.T) {
+	defer func() {
+		if r := recover(); r != nil {
+			t.Errorf("Panic caught during the test - %v", r)
+		}
+	}()
+
+	a := new(AddFederatedServerEthereumAnchorKey)
+	err := a.UnmarshalBinary(nil)
+	if err == nil {
+		t.Errorf("Error is nil when it shouldn't be")
+	}
+
+	err = a.UnmarshalBinary([]byte{})
+	if err == nil {
+		t.Errorf("Error is nil when it shouldn't be")
+	}
+}
+
+func TestAddFederatedServerEthereumAnchorKeyMarshalUnmarshal(t *testing.T) {
+	identity := testHelper.NewRepeatingHash(0xAB)
+	addr := new(primitives.ByteSlice20)
+	err := addr.UnmarshalBinary(testHelper.NewRepeatingHash(0xCD).Bytes())
+	if err != nil {
+		t.Error(err)
+	}
+	var keyPriority byte = 2
+
+	afek := NewAddFederatedServerEthereumAnchorKey(identity, keyPriority, *addr)
+	if afek.Type() != constants.TYPE_ADD_ETH_ANCHOR_KEY {
+		t.Errorf("Invalid type")
+	}
+	tmp, err := afek.MarshalBinary()
+	if err != nil {
+		t.Error(err)
+	}
+
+	afek = new(AddFederatedServerEthereumAnchorKey)
+	err = afek.UnmarshalBinary(tmp)
+	if err != nil {
+		t.Error(err)
+	}
+	if afek.IdentityChainID.IsSameAs(identity) == false {
+		t.Errorf("Invalid IdentityChainID")
+	}
+	if afek.KeyPriority != keyPriority {
+		t.Errorf("Invalid KeyPriority")
+	}
+	if afek.EthereumAddress.String() != addr.String() {
+		t.Errorf("Invalid EthereumAddress")
+	}
+}
+
+func TestAdminBlockEthereumAnchorKey(t *testing.T) {
+	identity := testHelper.NewRepeatingHash(0x01)
+	var addr [20]byte
+	addr[0] = 0xEE
+
+	block := new(AdminBlock)
+	block.Init()
+	err := block.AddFederatedServerEthereumAnchorKey(identity, 1, addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := block.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	block2 := new(AdminBlock)
+	err = block2.UnmarshalBinary(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := block2.GetABEntries()
+	if len(entries) != 1 || entries[0].Type() != constants.TYPE_ADD_ETH_ANCHOR_KEY {
+		t.Fatalf("Ethereum anchor key entry not unmarshalled: %v", entries)
+	}
+	if entries[0].(*AddFederatedServerEthereumAnchorKey).EthereumAddress != addr {
+		t.Errorf("Invalid EthereumAddress")
+	}
+}
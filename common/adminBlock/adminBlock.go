@@ -185,6 +185,20 @@ func (c *AdminBlock) AddFederatedServerBitcoinAnchorKey(identityChainID interfac
 	return nil
 }
 
+func (c *AdminBlock) AddFederatedServerEthereumAnchorKey(identityChainID interfaces.IHash, keyPriority byte, ethereumAddress [20]byte) error {
+	if identityChainID == nil {
+		return fmt.Errorf("No identityChainID provided")
+	}
+
+	b := new(primitives.ByteSlice20)
+	err := b.UnmarshalBinary(ethereumAddress[:])
+	if err != nil {
+		return err
+	}
+	entry := NewAddFederatedServerEthereumAnchorKey(identityChainID, keyPriority, *b)
+	return c.AddEntry(entry)
+}
+
 func (c *AdminBlock) AddEntry(entry interfaces.IABEntry) error {
 	if entry == nil {
 		return fmt.Errorf("No entry provided")
@@ -389,6 +403,8 @@ func (b *AdminBlock) UnmarshalBinaryData(data []byte) ([]byte, error) {
 			b.ABEntries[i] = new(AddFederatedServerBitcoinAnchorKey)
 		case constants.TYPE_SERVER_FAULT:
 			b.ABEntries[i] = new(ServerFault)
+		case constants.TYPE_ADD_ETH_ANCHOR_KEY:
+			b.ABEntries[i] = new(AddFederatedServerEthereumAnchorKey)
 		default:
 			fmt.Printf("AB UNDEFINED ENTRY %x for block %v\n", t, b.GetHeader().GetDBHeight())
 			panic("Undefined Admin Block Entry Type")
@@ -0,0 +1,97 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package constants
+
+import (
+	"math"
+)
+
+// Activations of consensus changes.  A change that old nodes would fork on takes effect
+// on mainnet and testnet only from the height a release schedules for it; until then it
+// is ActivationUnscheduled there, and nodes keep the old rules and the old bytes.  Local
+// networks run every change from the first block, and custom networks do too, unless the
+// change only applies to them; those are scheduled for custom networks the same way.
+
+// The height of a change no release has scheduled yet
+const ActivationUnscheduled uint32 = math.MaxUint32
+
+const (
+	ACTIVATION_MULTISIG_RCD     = "multisig-rcd"
+	ACTIVATION_ELECTIONS        = "election-messages"
+	ACTIVATION_ETH_ANCHOR       = "eth-anchor-keys"
+	ACTIVATION_BATCH_ACKS       = "batch-acks"
+	ACTIVATION_AUTHORITY_LIMITS = "authority-limits"
+)
+
+// Activation is a consensus change, and the heights it takes effect at
+type Activation struct {
+	Name        string
+	Description string
+	Main        uint32 // Height it takes effect at on mainnet
+	Test        uint32 // Height it takes effect at on testnet
+	Custom      uint32 // Height it takes effect at on custom networks
+}
+
+// Activations are the consensus changes that take effect at a height, in the order
+// they were made
+var Activations = []Activation{
+	{
+		Name:        ACTIVATION_MULTISIG_RCD,
+		Description: "Type 2 RCDs name all n keys of an m of n multisig, and inputs signed by m of them are accepted",
+		Main:        ActivationUnscheduled,
+		Test:        ActivationUnscheduled,
+	},
+	{
+		Name:        ACTIVATION_ELECTIONS,
+		Description: "Faulted leaders are replaced by elections with ElectionVolunteer and ElectionAccept messages, not by fault negotiation",
+		Main:        ActivationUnscheduled,
+		Test:        ActivationUnscheduled,
+	},
+	{
+		Name:        ACTIVATION_ETH_ANCHOR,
+		Description: "The Ethereum anchor keys of authorities go in the admin block",
+		Main:        ActivationUnscheduled,
+		Test:        ActivationUnscheduled,
+	},
+	{
+		Name:        ACTIVATION_BATCH_ACKS,
+		Description: "Leaders may send the acks of a minute as one BatchAck under a single signature",
+		Main:        ActivationUnscheduled,
+		Test:        ActivationUnscheduled,
+	},
+	{
+		Name:        ACTIVATION_AUTHORITY_LIMITS,
+		Description: "Custom networks limit the servers a block adds or removes, and how young an identity can be promoted",
+		Main:        ActivationUnscheduled,
+		Test:        ActivationUnscheduled,
+		Custom:      ActivationUnscheduled,
+	},
+}
+
+// ActivationHeight returns the height the named change takes effect at on the network,
+// ActivationUnscheduled for a change it doesn't know
+func ActivationHeight(name string, networkID uint32) uint32 {
+	for _, a := range Activations {
+		if a.Name != name {
+			continue
+		}
+		switch networkID {
+		case MAIN_NETWORK_ID:
+			return a.Main
+		case TEST_NETWORK_ID:
+			return a.Test
+		case LOCAL_NETWORK_ID:
+			return 0
+		}
+		return a.Custom
+	}
+	return ActivationUnscheduled
+}
+
+// IsActive returns true if the named change is in effect on the network at the height
+func IsActive(name string, networkID uint32, height uint32) bool {
+	h := ActivationHeight(name, networkID)
+	return h != ActivationUnscheduled && height >= h
+}
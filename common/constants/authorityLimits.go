@@ -0,0 +1,32 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package constants
+
+// AuthorityLimits bound how fast the authority set of a network can change.  Every node
+// drops the changes past them as they are processed, so they are part of consensus, and
+// fixed by the network rather than set by each node.
+type AuthorityLimits struct {
+	ChangesPerBlock int // Most servers a block adds or removes, 0 for no limit
+	MinIdentityAge  int // Fewest blocks old an identity must be to be promoted, 0 for no limit
+}
+
+// Custom networks are small, so the operator of a compromised identity could otherwise
+// replace the whole authority set within a block or two
+var CustomAuthorityLimits = AuthorityLimits{ChangesPerBlock: 1, MinIdentityAge: 10}
+
+// NetworkAuthorityLimits returns the authority limits of the network at a directory block
+// height.  Mainnet, testnet and local networks have none; adding them to mainnet or testnet
+// would be a fork.  Custom networks have them from the height ACTIVATION_AUTHORITY_LIMITS
+// takes effect at, so their nodes don't fork from those not yet upgraded.
+func NetworkAuthorityLimits(networkID uint32, dbheight uint32) AuthorityLimits {
+	switch networkID {
+	case MAIN_NETWORK_ID, TEST_NETWORK_ID, LOCAL_NETWORK_ID:
+		return AuthorityLimits{}
+	}
+	if !IsActive(ACTIVATION_AUTHORITY_LIMITS, networkID, dbheight) {
+		return AuthorityLimits{}
+	}
+	return CustomAuthorityLimits
+}
@@ -4,9 +4,15 @@
 
 package constants
 
+import (
+	"fmt"
+	"sync"
+)
+
 //---------------------------------------------------------------------
 // Checkpoints Directory Block KeyMR
 //---------------------------------------------------------------------
+// Compiled in, and added to at runtime by AddCheckPoints, so read them with GetCheckPoint
 var CheckPoints = map[uint32]string{
 	2:     "5328d4bbe7ea6efc31cf7bfc45192378454cf4e1908c56a35e6a64456a691751",
 	10:    "3a5ec711a1dc1c6e463b0c0344560f830eb0b56e42def141cb423b0d8487a1dc",
@@ -115,3 +121,45 @@ var CheckPoints = map[uint32]string{
 	91000: "632324ab4ac692f5cf7153a4bff6f27b8e295ac531d69338a31bc7354de64c4b",
 	92000: "5f14802d2b60a874d54535a37f5edbff77d11fbbf3c3fa1410fb688f14569a94",
 }
+
+var checkPointsMutex sync.RWMutex
+
+// GetCheckPoint returns the KeyMR checkpointed at a directory block height, if any
+func GetCheckPoint(ht uint32) (string, bool) {
+	checkPointsMutex.RLock()
+	defer checkPointsMutex.RUnlock()
+	keyMR, ok := CheckPoints[ht]
+	return keyMR, ok
+}
+
+// CheckPointCount returns how many checkpoints there are, and the highest
+func CheckPointCount() (count int, highest uint32) {
+	checkPointsMutex.RLock()
+	defer checkPointsMutex.RUnlock()
+	for ht := range CheckPoints {
+		if ht > highest {
+			highest = ht
+		}
+	}
+	return len(CheckPoints), highest
+}
+
+// AddCheckPoints adds checkpoints at runtime, returning how many were new.  If any
+// contradicts a checkpoint already known, none are added.
+func AddCheckPoints(points map[uint32]string) (int, error) {
+	checkPointsMutex.Lock()
+	defer checkPointsMutex.Unlock()
+	for ht, keyMR := range points {
+		if known, ok := CheckPoints[ht]; ok && known != keyMR {
+			return 0, fmt.Errorf("checkpoint at %d is %s, not %s", ht, known, keyMR)
+		}
+	}
+	added := 0
+	for ht, keyMR := range points {
+		if _, ok := CheckPoints[ht]; !ok {
+			CheckPoints[ht] = keyMR
+			added++
+		}
+	}
+	return added, nil
+}
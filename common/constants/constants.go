@@ -43,9 +43,19 @@ const (
 
 	MISSING_ENTRY_BLOCKS //27
 	ENTRY_BLOCK_RESPONSE //28
+
+	THROTTLE_MSG  // 29
+	BATCH_ACK_MSG // 30
+	STEP_DOWN_MSG // 31
+
+	ELECTION_VOLUNTEER_MSG // 32
+	ELECTION_ACCEPT_MSG    // 33
+
+	SYNC_REQUEST_MSG // 34
+	SYNC_CHUNK_MSG   // 35
 )
 
-const NUM_MESSAGES = 29
+const NUM_MESSAGES = 36
 
 const (
 	// Limits for keeping inputs from flooding our execution
@@ -70,6 +80,7 @@ const (
 	// length of a Private Key
 	SIGNATURE_LENGTH     = 64    // Length of a signature
 	MAX_TRANSACTION_SIZE = 10240 // 10K like everything else?
+	MAX_ENTRY_SIZE       = 10240 // Bytes of an entry past its 35 byte header
 	// Not sure if we need a minimum amount.  Set at 1 Factoshi
 
 	// Database
@@ -113,6 +124,10 @@ const (
 	TEST_NETWORK_ID  uint32 = 0xFA92E5A3
 	LOCAL_NETWORK_ID uint32 = 0xFA92E5A4
 	MaxBlocksPerMsg         = 500
+
+	// Mainnet height after which commits may not take an EC balance below zero.  Other
+	// networks enforce it from the start.
+	EC_OVERSPEND_HEIGHT uint32 = 97886
 )
 
 const (
@@ -159,7 +174,8 @@ const (
 	TYPE_REMOVE_FED_SERVER               // 7
 	TYPE_ADD_FED_SERVER_KEY              // 8
 	TYPE_ADD_BTC_ANCHOR_KEY              // 9
-	TYPE_SERVER_FAULT
+	TYPE_SERVER_FAULT                    // 10
+	TYPE_ADD_ETH_ANCHOR_KEY              // 11
 )
 
 //---------------------------------------------------------------------
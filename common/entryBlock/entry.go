@@ -5,8 +5,6 @@
 package entryBlock
 
 import (
-	"crypto/sha256"
-	"crypto/sha512"
 	"encoding/binary"
 	"fmt"
 
@@ -121,9 +119,10 @@ func NewChainID(e interfaces.IEBEntry) interfaces.IHash {
 
 func ExternalIDsToChainID(extIDs [][]byte) interfaces.IHash {
 	id := new(primitives.Hash)
-	sum := sha256.New()
+	crypto := primitives.GetCryptoProvider()
+	sum := crypto.NewSha256()
 	for _, v := range extIDs {
-		x := sha256.Sum256(v)
+		x := crypto.Sha256(v)
 		sum.Write(x[:])
 	}
 	id.SetBytes(sum.Sum(nil))
@@ -168,8 +167,9 @@ func (e *Entry) GetHash() interfaces.IHash {
 			return h
 		}
 
-		h1 := sha512.Sum512(entry)
-		h2 := sha256.Sum256(append(h1[:], entry[:]...))
+		crypto := primitives.GetCryptoProvider()
+		h1 := crypto.Sha512(entry)
+		h2 := crypto.Sha256(append(h1[:], entry[:]...))
 		h.SetBytes(h2[:])
 		e.hash = h
 	}
@@ -48,6 +48,9 @@ func NewRCD_1(publicKey []byte) interfaces.IRCD {
 	return a
 }
 
+// NewRCD_2 returns a type 2 RCD in the layout from before multisig took effect, naming
+// m of n addresses.  No input it guards is accepted; use NewMultisigRCD for a multisig
+// once it is in effect.
 func NewRCD_2(n int, m int, addresses []interfaces.IAddress) (interfaces.IRCD, error) {
 	if len(addresses) != m {
 		return nil, fmt.Errorf("Improper number of addresses.  m = %d n = %d #addresses = %d", m, n, len(addresses))
@@ -56,12 +59,43 @@ func NewRCD_2(n int, m int, addresses []interfaces.IAddress) (interfaces.IRCD, e
 	au := new(RCD_2)
 	au.N = n
 	au.M = m
+	au.legacy = true
 	au.N_Addresses = make([]interfaces.IAddress, len(addresses), len(addresses))
 	copy(au.N_Addresses, addresses)
 
 	return au, nil
 }
 
+// NewMultisigRCD returns a multisig RCD any m of whose n public keys must sign
+func NewMultisigRCD(m int, n int, publicKeys []interfaces.IAddress) (interfaces.IRCD, error) {
+	if len(publicKeys) != n {
+		return nil, fmt.Errorf("Improper number of keys.  m = %d n = %d #keys = %d", m, n, len(publicKeys))
+	}
+	if m < 1 || m > n || n > MaxMultisigKeys {
+		return nil, fmt.Errorf("Improper multisig.  m = %d n = %d, at most %d keys", m, n, MaxMultisigKeys)
+	}
+	for i, key := range publicKeys {
+		if key == nil || len(key.Bytes()) != constants.ADDRESS_LENGTH {
+			return nil, fmt.Errorf("Key %d is not a public key", i)
+		}
+		for _, other := range publicKeys[:i] {
+			if key.IsSameAs(other) {
+				return nil, fmt.Errorf("Key %d is named twice", i)
+			}
+		}
+	}
+
+	au := new(RCD_2)
+	au.M = m
+	au.N = n
+	au.N_Addresses = make([]interfaces.IAddress, len(publicKeys), len(publicKeys))
+	for i, key := range publicKeys {
+		au.N_Addresses[i] = CreateAddress(key)
+	}
+
+	return au, nil
+}
+
 func CreateRCD(data []byte) interfaces.IRCD {
 	switch data[0] {
 	case 1:
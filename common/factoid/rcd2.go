@@ -5,10 +5,14 @@
 package factoid
 
 import (
+	"bytes"
 	"encoding/binary"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 
+	"github.com/FactomProject/ed25519"
+	"github.com/FactomProject/factomd/common/constants"
 	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/common/primitives"
 )
@@ -19,37 +23,93 @@ import (
 
 // Type 2 RCD implement multisig
 // m of n
-// Must have m addresses from which to choose, no fewer, no more
-// Must have n RCD, no fewer no more.
-// NOTE: This does mean you can have a multisig nested in a
-// multisig.  It just works.
+// Must have n public keys, no fewer, no more, any m of which must sign.
+// The signature block of the input holds m signatures, in any order;
+// each must be by a different key.  Signatures not yet made are left
+// zero, so a transaction can be passed from signer to signer.
+//
+// The address of the input is the hash of the RCD, as for type 1.
+//
+// Before multisig takes effect at its activation height, a type 2 RCD keeps the layout
+// it had: n, m, then m addresses.  It names no keys that can sign, and no input it
+// guards is accepted.  Blocks below the height are read in that layout.
+
+// Most keys a multisig RCD may name; bounds the signature checks an input costs
+const MaxMultisigKeys = 15
 
 type RCD_2 struct {
 	M           int                   // Number signatures required
 	N           int                   // Total sigatures possible
-	N_Addresses []interfaces.IAddress // n addresses
+	N_Addresses []interfaces.IAddress // The n ed25519 public keys that may sign
+
+	legacy bool // Laid out as before multisig took effect
 }
 
-var _ interfaces.IRCD = (*RCD_2)(nil)
+// multisigHeight is the height multisig takes effect at on the network the node runs.
+// It is set once with SetMultisigHeight at startup, before any goroutine reads blocks,
+// and only read after that.
+var multisigHeight = constants.ActivationUnscheduled
 
-/*************************************
- *       Stubs
- *************************************/
+// SetMultisigHeight sets the height multisig takes effect at.  Call it once, at startup.
+func SetMultisigHeight(height uint32) {
+	multisigHeight = height
+}
 
-func (b RCD_2) GetAddress() (interfaces.IAddress, error) {
-	return nil, nil
+// MultisigActive returns true if multisig RCDs are in effect at the height
+func MultisigActive(height uint32) bool {
+	return multisigHeight != constants.ActivationUnscheduled && height >= multisigHeight
 }
 
-func (b RCD_2) NumberOfSignatures() int {
-	return 1
+// HasMultisigInput returns true if any input of the transaction is guarded by a type 2 RCD
+func HasMultisigInput(trans interfaces.ITransaction) bool {
+	for _, rcd := range trans.GetRCDs() {
+		if _, ok := rcd.(*RCD_2); ok {
+			return true
+		}
+	}
+	return false
 }
 
+var _ interfaces.IRCD = (*RCD_2)(nil)
+
 /***************************************
  *       Methods
  ***************************************/
 
+func (b RCD_2) GetAddress() (interfaces.IAddress, error) {
+	if b.legacy {
+		return nil, nil
+	}
+	data, err := b.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return CreateAddress(primitives.Shad(data)), nil
+}
+
+// NumberOfSignatures returns how many signatures the signature block of the input holds
+func (b RCD_2) NumberOfSignatures() int {
+	if b.legacy {
+		return 1
+	}
+	return b.M
+}
+
+// IsSameAs compares the RCDs as written, so an RCD read in either layout is the same as
+// one written in the other when their bytes are
 func (b RCD_2) IsSameAs(rcd interfaces.IRCD) bool {
-	return b.String() == rcd.String()
+	if rcd == nil {
+		return false
+	}
+	data, err := b.MarshalBinary()
+	if err != nil {
+		return false
+	}
+	data2, err := rcd.MarshalBinary()
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(data, data2)
 }
 
 func (b RCD_2) UnmarshalBinary(data []byte) error {
@@ -57,20 +117,80 @@ func (b RCD_2) UnmarshalBinary(data []byte) error {
 	return err
 }
 
+// signers returns, for each signature in the block, the index of the key that made it,
+// or -1 if none did.  A key is only counted for the first signature it made.
+func (b RCD_2) signers(trans interfaces.ITransaction, sigblk interfaces.ISignatureBlock) []int {
+	if b.legacy || sigblk == nil || b.M < 1 || b.M > b.N || len(b.N_Addresses) != b.N {
+		return nil
+	}
+	data, err := trans.MarshalBinarySig()
+	if err != nil {
+		return nil
+	}
+	used := make([]bool, b.N)
+	sigs := sigblk.GetSignatures()
+	signers := make([]int, len(sigs))
+	for s, signature := range sigs {
+		signers[s] = -1
+		if signature == nil {
+			continue
+		}
+		cryptosig := signature.GetSignature()
+		if cryptosig == nil || *cryptosig == [constants.SIGNATURE_LENGTH]byte{} {
+			continue
+		}
+		for i, address := range b.N_Addresses {
+			if used[i] {
+				continue
+			}
+			var key [constants.ADDRESS_LENGTH]byte
+			copy(key[:], address.Bytes())
+			if ed25519.VerifyCanonical(&key, data, cryptosig) {
+				used[i] = true
+				signers[s] = i
+				break
+			}
+		}
+	}
+	return signers
+}
+
+// SignedBy returns the indexes of the keys that signed the transaction in the
+// signature block given.  A key is only counted once, however often it signed.
+func (b RCD_2) SignedBy(trans interfaces.ITransaction, sigblk interfaces.ISignatureBlock) []int {
+	var keys []int
+	for _, i := range b.signers(trans, sigblk) {
+		if i >= 0 {
+			keys = append(keys, i)
+		}
+	}
+	return keys
+}
+
 func (b RCD_2) CheckSig(trans interfaces.ITransaction, sigblk interfaces.ISignatureBlock) bool {
-	return false
+	if b.legacy {
+		return false
+	}
+	return len(b.SignedBy(trans, sigblk)) >= b.M
 }
 
 func (e *RCD_2) JSONByte() ([]byte, error) {
 	return primitives.EncodeJSON(e)
 }
 
-// TODO: Fix Json marshaling of RCD_2. Right now the RCD type
-// is not included in the json marshal.
 func (e *RCD_2) JSONString() (string, error) {
 	return primitives.EncodeJSONString(e)
 }
 
+// MarshalJSON will prepend the RCD type
+func (e *RCD_2) MarshalJSON() ([]byte, error) {
+	data, err := e.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(fmt.Sprintf("%x", data))
+}
+
 func (b RCD_2) String() string {
 	txt, err := b.CustomMarshalText()
 	if err != nil {
@@ -83,6 +203,7 @@ func (w RCD_2) Clone() interfaces.IRCD {
 	c := new(RCD_2)
 	c.M = w.M
 	c.N = w.N
+	c.legacy = w.legacy
 	c.N_Addresses = make([]interfaces.IAddress, len(w.N_Addresses))
 	for i, address := range w.N_Addresses {
 		c.N_Addresses[i] = CreateAddress(address)
@@ -99,7 +220,34 @@ func (t *RCD_2) UnmarshalBinaryData(data []byte) (newData []byte, err error) {
 	if typ != 2 {
 		return nil, fmt.Errorf("Bad data fed to RCD_2 UnmarshalBinaryData()")
 	}
+	if t.legacy {
+		return t.unmarshalLegacy(data)
+	}
 
+	t.M, data = int(binary.BigEndian.Uint16(data[0:2])), data[2:]
+	t.N, data = int(binary.BigEndian.Uint16(data[0:2])), data[2:]
+	if t.M < 1 || t.M > t.N || t.N > MaxMultisigKeys {
+		return nil, fmt.Errorf("Bad multisig RCD: %d of %d keys", t.M, t.N)
+	}
+	if len(data) < t.N*constants.ADDRESS_LENGTH {
+		return nil, fmt.Errorf("Data source too short to unmarshal %d keys: %d", t.N, len(data))
+	}
+
+	t.N_Addresses = make([]interfaces.IAddress, t.N, t.N)
+
+	for i, _ := range t.N_Addresses {
+		t.N_Addresses[i] = new(Address)
+		data, err = t.N_Addresses[i].UnmarshalBinaryData(data)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return data, nil
+}
+
+// unmarshalLegacy reads the layout from before multisig took effect: n, m, then m addresses
+func (t *RCD_2) unmarshalLegacy(data []byte) (newData []byte, err error) {
 	t.N, data = int(binary.BigEndian.Uint16(data[0:2])), data[2:]
 	t.M, data = int(binary.BigEndian.Uint16(data[0:2])), data[2:]
 
@@ -117,6 +265,30 @@ func (t *RCD_2) UnmarshalBinaryData(data []byte) (newData []byte, err error) {
 }
 
 func (a RCD_2) MarshalBinary() ([]byte, error) {
+	if a.legacy {
+		return a.marshalLegacy()
+	}
+	if len(a.N_Addresses) != a.N {
+		return nil, fmt.Errorf("Multisig RCD has %d keys, not %d", len(a.N_Addresses), a.N)
+	}
+
+	var out primitives.Buffer
+
+	binary.Write(&out, binary.BigEndian, uint8(2))
+	binary.Write(&out, binary.BigEndian, uint16(a.M))
+	binary.Write(&out, binary.BigEndian, uint16(a.N))
+	for i := 0; i < a.N; i++ {
+		data, err := a.N_Addresses[i].MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		out.Write(data)
+	}
+
+	return out.DeepCopyBytes(), nil
+}
+
+func (a RCD_2) marshalLegacy() ([]byte, error) {
 	var out primitives.Buffer
 
 	binary.Write(&out, binary.BigEndian, uint8(2))
@@ -136,17 +308,78 @@ func (a RCD_2) MarshalBinary() ([]byte, error) {
 func (a RCD_2) CustomMarshalText() ([]byte, error) {
 	var out primitives.Buffer
 
+	if a.legacy {
+		primitives.WriteNumber8(&out, uint8(2)) // Type 2 Authorization
+		out.WriteString("\n n: ")
+		primitives.WriteNumber16(&out, uint16(a.N))
+		out.WriteString(" m: ")
+		primitives.WriteNumber16(&out, uint16(a.M))
+		out.WriteString("\n")
+		for i := 0; i < a.M; i++ {
+			out.WriteString("  m: ")
+			out.WriteString(hex.EncodeToString(a.N_Addresses[i].Bytes()))
+			out.WriteString("\n")
+		}
+		return out.DeepCopyBytes(), nil
+	}
+
+	out.WriteString("RCD 2: ")
 	primitives.WriteNumber8(&out, uint8(2)) // Type 2 Authorization
-	out.WriteString("\n n: ")
-	primitives.WriteNumber16(&out, uint16(a.N))
-	out.WriteString(" m: ")
+	out.WriteString("\n m: ")
 	primitives.WriteNumber16(&out, uint16(a.M))
+	out.WriteString(" n: ")
+	primitives.WriteNumber16(&out, uint16(a.N))
 	out.WriteString("\n")
-	for i := 0; i < a.M; i++ {
-		out.WriteString("  m: ")
-		out.WriteString(hex.EncodeToString(a.N_Addresses[i].Bytes()))
+	for _, address := range a.N_Addresses {
+		out.WriteString("  key: ")
+		out.WriteString(hex.EncodeToString(address.Bytes()))
 		out.WriteString("\n")
 	}
 
 	return out.DeepCopyBytes(), nil
 }
+
+// AddMultisigSignature adds a signature of one of the keys of the multisig RCD of input i
+// to its signature block, so signers can sign in turn without sharing keys.  Signatures
+// that do not verify are dropped from the block.  Returns how many keys have signed.
+func (t *Transaction) AddMultisigSignature(i int, signature []byte) (int, error) {
+	if i < 0 || i >= len(t.Inputs) || i >= len(t.RCDs) {
+		return 0, fmt.Errorf("The transaction has no input %d", i)
+	}
+	rcd, ok := t.RCDs[i].(*RCD_2)
+	if !ok {
+		return 0, fmt.Errorf("Input %d is not a multisig input", i)
+	}
+	if len(signature) != constants.SIGNATURE_LENGTH {
+		return 0, fmt.Errorf("A signature is %d bytes, not %d", constants.SIGNATURE_LENGTH, len(signature))
+	}
+	sig := new(FactoidSignature)
+	copy(sig.Signature[:], signature)
+
+	blk := t.GetSignatureBlock(i)
+	var kept []interfaces.ISignature
+	signed := make(map[int]bool)
+	for s, key := range rcd.signers(t, blk) {
+		if key >= 0 {
+			kept = append(kept, blk.GetSignatures()[s])
+			signed[key] = true
+		}
+	}
+	if len(kept) >= rcd.M {
+		return len(kept), fmt.Errorf("Input %d is already signed by %d of %d keys", i, len(kept), rcd.M)
+	}
+
+	key := rcd.signers(t, &SignatureBlock{Signatures: []interfaces.ISignature{sig}})
+	if len(key) != 1 || key[0] < 0 {
+		return len(kept), fmt.Errorf("The signature is not by any key of input %d", i)
+	}
+	if signed[key[0]] {
+		return len(kept), fmt.Errorf("Key %d already signed input %d", key[0], i)
+	}
+	kept = append(kept, sig)
+
+	nb := NewSignatureBlock(rcd.M)
+	copy(nb.Signatures, kept)
+	t.SetSignatureBlock(i, nb)
+	return len(kept), nil
+}
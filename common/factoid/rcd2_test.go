@@ -10,6 +10,7 @@ import (
 
 	. "github.com/FactomProject/factomd/common/factoid"
 	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/testHelper"
 )
 
 func TestUnmarshalNilRCD_2(t *testing.T) {
@@ -108,3 +109,135 @@ func nextAuth2_rcd2() *RCD_2 {
 	rcd, _ := NewRCD_2(n, m, addresses)
 	return rcd.(*RCD_2)
 }
+
+// multisigTrans returns a transaction spending from a 2 of 3 multisig input, and the
+// private keys of the three signers
+func multisigTrans(t *testing.T) (*Transaction, [][]byte) {
+	var privs [][]byte
+	var keys []interfaces.IAddress
+	for i := uint64(0); i < 3; i++ {
+		privs = append(privs, testHelper.NewPrivKey(i))
+		keys = append(keys, NewAddress(testHelper.PrivateKeyToEDPub(privs[i])))
+	}
+	rcd, err := NewMultisigRCD(2, 3, keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	address, err := rcd.GetAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx := new(Transaction)
+	tx.AddInput(address, 1000)
+	tx.AddOutput(testHelper.NewFactoidAddress(4), 900)
+	tx.AddAuthorization(rcd)
+	return tx, privs
+}
+
+func TestNewMultisigRCDBounds(t *testing.T) {
+	keys := []interfaces.IAddress{nextAddress(), nextAddress()}
+	if _, err := NewMultisigRCD(0, 2, keys); err == nil {
+		t.Error("Took a multisig needing no signatures")
+	}
+	if _, err := NewMultisigRCD(3, 2, keys); err == nil {
+		t.Error("Took a multisig needing more signatures than keys")
+	}
+	if _, err := NewMultisigRCD(1, 2, []interfaces.IAddress{keys[0], keys[0]}); err == nil {
+		t.Error("Took a multisig naming a key twice")
+	}
+	if _, err := NewMultisigRCD(1, 3, keys); err == nil {
+		t.Error("Took a multisig with the wrong number of keys")
+	}
+}
+
+func TestRCD2MultisigSign(t *testing.T) {
+	tx, privs := multisigTrans(t)
+	if err := tx.Validate(1); err != nil {
+		t.Fatal(err)
+	}
+	data, err := tx.MarshalBinarySig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig := func(i int) []byte { return NewED25519Signature(privs[i], data).Bytes() }
+
+	if tx.ValidateSignatures() == nil {
+		t.Error("Unsigned multisig input passed")
+	}
+	if _, err := tx.AddMultisigSignature(0, NewED25519Signature(testHelper.NewPrivKey(9), data).Bytes()); err == nil {
+		t.Error("Took a signature by a key not in the RCD")
+	}
+
+	if n, err := tx.AddMultisigSignature(0, sig(2)); err != nil || n != 1 {
+		t.Fatalf("Signed by %d keys, error %v", n, err)
+	}
+	if _, err := tx.AddMultisigSignature(0, sig(2)); err == nil {
+		t.Error("Took a second signature by the same key")
+	}
+	if tx.ValidateSignatures() == nil {
+		t.Error("Multisig input passed with 1 of 2 signatures")
+	}
+
+	// A partly signed transaction passes from signer to signer
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx2 := new(Transaction)
+	if err := tx2.UnmarshalBinary(raw); err != nil {
+		t.Fatal(err)
+	}
+	if !tx2.IsSameAs(tx) {
+		t.Error("Partly signed transaction did not round trip")
+	}
+
+	if n, err := tx2.AddMultisigSignature(0, sig(0)); err != nil || n != 2 {
+		t.Fatalf("Signed by %d keys, error %v", n, err)
+	}
+	if err := tx2.ValidateSignatures(); err != nil {
+		t.Error(err)
+	}
+	if _, err := tx2.AddMultisigSignature(0, sig(1)); err == nil {
+		t.Error("Took a signature past the number required")
+	}
+	signers := tx2.RCDs[0].(*RCD_2).SignedBy(tx2, tx2.GetSignatureBlock(0))
+	if len(signers) != 2 || signers[0] != 2 || signers[1] != 0 {
+		t.Errorf("Signed by %v, not [2 0]", signers)
+	}
+}
+
+func TestRCD2LegacyLayout(t *testing.T) {
+	// Before multisig is in effect a type 2 RCD is n, m, then m addresses, and no input
+	// it guards is accepted
+	addresses := []interfaces.IAddress{nextAddress()}
+	rcd, err := NewRCD_2(3, 1, addresses)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := rcd.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 5+32 || data[0] != 2 || data[2] != 3 || data[4] != 1 {
+		t.Errorf("Unexpected legacy layout %x", data)
+	}
+	if rcd.NumberOfSignatures() != 1 {
+		t.Error("A legacy RCD takes one signature")
+	}
+
+	tx := new(Transaction)
+	tx.AddInput(nextAddress(), 1000)
+	tx.AddAuthorization(rcd)
+	if tx.ValidateSignatures() == nil {
+		t.Error("Accepted an input guarded by a legacy RCD")
+	}
+
+	// The layout as read now doesn't take more keys required than named
+	if _, err := new(RCD_2).UnmarshalBinaryData(data); err == nil {
+		t.Error("Read a legacy RCD of 3 of 1 keys as a multisig")
+	}
+	if MultisigActive(0) {
+		t.Error("Multisig is in effect before its height is set")
+	}
+}
@@ -116,17 +116,38 @@ func (s SignatureBlock) CustomMarshalText() ([]byte, error) {
 	return out.DeepCopyBytes(), nil
 }
 
+// UnmarshalBinaryData reads as many signatures as the block holds, one if it holds none
 func (s *SignatureBlock) UnmarshalBinaryData(data []byte) ([]byte, error) {
 	buf := primitives.NewBuffer(data)
-	s.Signatures = make([]interfaces.ISignature, 1)
-	s.Signatures[0] = new(FactoidSignature)
-	err := buf.PopBinaryMarshallable(s.Signatures[0])
-	if err != nil {
-		return nil, err
+	n := len(s.Signatures)
+	if n == 0 {
+		n = 1
+	}
+	s.Signatures = make([]interfaces.ISignature, n)
+	for i := range s.Signatures {
+		s.Signatures[i] = new(FactoidSignature)
+		err := buf.PopBinaryMarshallable(s.Signatures[i])
+		if err != nil {
+			return nil, err
+		}
 	}
 	return buf.DeepCopyBytes(), nil
 }
 
+// NewSignatureBlock returns a block of n blank signatures, as an RCD calling for n
+// signatures reads and writes them
+func NewSignatureBlock(n int) *SignatureBlock {
+	s := new(SignatureBlock)
+	if n < 1 {
+		n = 1
+	}
+	s.Signatures = make([]interfaces.ISignature, n)
+	for i := range s.Signatures {
+		s.Signatures[i] = new(FactoidSignature)
+	}
+	return s
+}
+
 func NewSingleSignatureBlock(priv, data []byte) *SignatureBlock {
 	s := new(SignatureBlock)
 	s.AddSignature(NewED25519Signature(priv, data))
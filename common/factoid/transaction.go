@@ -166,18 +166,27 @@ func (t *Transaction) SetTimestamp(ts interfaces.Timestamp) {
 
 func (t *Transaction) SetSignatureBlock(i int, sig interfaces.ISignatureBlock) {
 	for len(t.SigBlocks) <= i {
-		t.SigBlocks = append(t.SigBlocks, new(SignatureBlock))
+		t.SigBlocks = append(t.SigBlocks, t.blankSignatureBlock(len(t.SigBlocks)))
 	}
 	t.SigBlocks[i] = sig
 }
 
 func (t *Transaction) GetSignatureBlock(i int) interfaces.ISignatureBlock {
 	for len(t.SigBlocks) <= i {
-		t.SigBlocks = append(t.SigBlocks, new(SignatureBlock))
+		t.SigBlocks = append(t.SigBlocks, t.blankSignatureBlock(len(t.SigBlocks)))
 	}
 	return t.SigBlocks[i]
 }
 
+// blankSignatureBlock returns the signature block of input i before it is signed, with
+// as many blank signatures as its RCD calls for
+func (t *Transaction) blankSignatureBlock(i int) interfaces.ISignatureBlock {
+	if i < len(t.RCDs) && t.RCDs[i] != nil {
+		return NewSignatureBlock(t.RCDs[i].NumberOfSignatures())
+	}
+	return new(SignatureBlock)
+}
+
 func (t *Transaction) AddRCD(rcd interfaces.IRCD) {
 	t.RCDs = append(t.RCDs, rcd)
 	t.clearCaches()
@@ -391,7 +400,7 @@ func (t *Transaction) GetSignatureBlocks() []interfaces.ISignatureBlock {
 		return t.SigBlocks
 	}
 	for i := len(t.SigBlocks); i < len(t.Inputs); i++ { // If too short, then
-		t.SigBlocks = append(t.SigBlocks, new(SignatureBlock)) // pad it with
+		t.SigBlocks = append(t.SigBlocks, t.blankSignatureBlock(i)) // pad it with
 	} // signature blocks.
 	return t.SigBlocks
 }
@@ -427,6 +436,12 @@ func (t *Transaction) GetRCD(i int) (interfaces.IRCD, error) {
 // UnmarshalBinary assumes that the Binary is all good.  We do error
 // out if there isn't enough data, or the transaction is too large.
 func (t *Transaction) UnmarshalBinaryData(data []byte) ([]byte, error) {
+	return t.unmarshalBinaryData(data, false)
+}
+
+// unmarshalBinaryData reads the transaction, with any multisig RCDs in the layout from
+// before multisig took effect if legacyMultisig is set, as in blocks below its height
+func (t *Transaction) unmarshalBinaryData(data []byte, legacyMultisig bool) ([]byte, error) {
 	buf := primitives.NewBuffer(data)
 
 	v, err := buf.PopVarInt()
@@ -498,11 +513,14 @@ func (t *Transaction) UnmarshalBinaryData(data []byte) ([]byte, error) {
 			return nil, err
 		}
 		t.RCDs[i] = CreateRCD([]byte{b})
+		if rcd, ok := t.RCDs[i].(*RCD_2); ok {
+			rcd.legacy = legacyMultisig
+		}
 		err = buf.PopBinaryMarshallable(t.RCDs[i])
 		if err != nil {
 			return nil, err
 		}
-		t.SigBlocks[i] = new(SignatureBlock)
+		t.SigBlocks[i] = NewSignatureBlock(t.RCDs[i].NumberOfSignatures())
 		err = buf.PopBinaryMarshallable(t.SigBlocks[i])
 		if err != nil {
 			return nil, err
@@ -597,12 +615,20 @@ func (t Transaction) MarshalBinary() ([]byte, error) {
 		// we don't want to restrict what might be required to
 		// sign an input.
 		if len(t.SigBlocks) <= i {
-			t.SigBlocks = append(t.SigBlocks, new(SignatureBlock))
+			t.SigBlocks = append(t.SigBlocks, t.blankSignatureBlock(len(t.SigBlocks)))
 		}
 		err = buf.PushBinaryMarshallable(t.SigBlocks[i])
 		if err != nil {
 			return nil, err
 		}
+		// A multisig input still being signed may hold fewer signatures than its
+		// RCD calls for; the rest are written blank, as they are read back by count
+		for n := len(t.SigBlocks[i].GetSignatures()); n < rcd.NumberOfSignatures(); n++ {
+			err = buf.PushBinaryMarshallable(new(FactoidSignature))
+			if err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	return buf.DeepCopyBytes(), nil
@@ -687,7 +713,7 @@ func (t *Transaction) CustomMarshalText() (text []byte, err error) {
 		out.Write(text)
 
 		for len(t.SigBlocks) <= i {
-			t.SigBlocks = append(t.SigBlocks, new(SignatureBlock))
+			t.SigBlocks = append(t.SigBlocks, t.blankSignatureBlock(len(t.SigBlocks)))
 		}
 		text, err := t.SigBlocks[i].CustomMarshalText()
 		if err != nil {
@@ -31,6 +31,8 @@ func (im *IdentityManager) ProcessABlockEntry(entry interfaces.IABEntry) error {
 		return im.ApplyAddFederatedServerSigningKey(entry)
 	case constants.TYPE_ADD_BTC_ANCHOR_KEY:
 		return im.ApplyAddFederatedServerBitcoinAnchorKey(entry)
+	case constants.TYPE_ADD_ETH_ANCHOR_KEY:
+		return im.ApplyAddFederatedServerEthereumAnchorKey(entry)
 	case constants.TYPE_SERVER_FAULT:
 		return im.ApplyServerFault(entry)
 	}
@@ -140,6 +142,25 @@ func (im *IdentityManager) ApplyAddFederatedServerBitcoinAnchorKey(entry interfa
 	return nil
 }
 
+func (im *IdentityManager) ApplyAddFederatedServerEthereumAnchorKey(entry interfaces.IABEntry) error {
+	e := entry.(*adminBlock.AddFederatedServerEthereumAnchorKey)
+
+	auth := im.GetAuthority(e.IdentityChainID)
+	if auth == nil {
+		return fmt.Errorf("Authority %v not found", e.IdentityChainID.String())
+	}
+
+	var ask AnchorSigningKey
+	ask.BlockChain = "ETH"
+	ask.SigningKey = e.EthereumAddress
+	ask.KeyLevel = e.KeyPriority
+
+	auth.AnchorKeys = append(auth.AnchorKeys, ask)
+
+	im.SetAuthority(e.IdentityChainID, auth)
+	return nil
+}
+
 func (im *IdentityManager) ApplyServerFault(entry interfaces.IABEntry) error {
 	//	e := entry.(*adminBlock.ServerFault)
 	return nil
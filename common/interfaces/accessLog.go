@@ -0,0 +1,23 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package interfaces
+
+// APIAccessLogConfig says where and how much of the API calls made are logged
+type APIAccessLogConfig struct {
+	Path       string   // No log if empty
+	SampleRate float64  // Fraction of the calls that succeed to log; failed calls always are
+	Redact     []string // Parameters whose values are left out
+	Anonymize  bool     // Cut client addresses to their network
+	MaxSize    int      // Megabytes the log grows to before it is rotated
+	MaxFiles   int      // Rotated logs kept
+}
+
+// APIAuditLogConfig says where the API calls that write to the chain are logged, all of
+// them, with who made them
+type APIAuditLogConfig struct {
+	Path     string // No log if empty
+	MaxSize  int    // Megabytes the log grows to before it is rotated
+	MaxFiles int    // Rotated logs kept
+}
@@ -0,0 +1,17 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package interfaces
+
+// AlertStatus is where an operator defined alert rule stands
+type AlertStatus struct {
+	Name      string  `json:"name"`
+	Condition string  `json:"condition"`
+	For       float64 `json:"for"`    // Seconds the condition must hold before the alert fires
+	Action    string  `json:"action"` // "log", "webhook <url>" or "exec <command>"
+	State     string  `json:"state"`  // "ok", "pending", "firing" or "error"
+	Value     float64 `json:"value"`  // The left side of the condition when last checked
+	Since     int64   `json:"since"`  // Unix time the condition started holding, 0 if it doesn't
+	Error     string  `json:"error,omitempty"`
+}
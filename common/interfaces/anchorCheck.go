@@ -0,0 +1,27 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package interfaces
+
+// AnchorMismatch is a directory block whose KeyMR in our database differs from the one
+// recorded in a signed anchor record, i.e. a long-range fork or local corruption.
+type AnchorMismatch struct {
+	DBHeight     uint32 `json:"dbheight"`
+	LocalKeyMR   string `json:"localkeymr"`
+	AnchorKeyMR  string `json:"anchorkeymr"`
+	EntryHash    string `json:"entryhash"` // The anchor chain entry holding the record
+	BitcoinTXID  string `json:"bitcointxid,omitempty"`
+	EthereumTXID string `json:"ethereumtxid,omitempty"`
+}
+
+// AnchorCheckStatus reports how far saved directory blocks have been compared against
+// the anchor records, and any mismatches found.
+type AnchorCheckStatus struct {
+	Checked       int              `json:"checked"`       // Anchor records compared so far
+	Pending       int              `json:"pending"`       // Records for blocks we don't have yet
+	HighestHeight uint32           `json:"highestheight"` // Highest directory block compared
+	LastCheck     int64            `json:"lastcheck"`     // Unix time of the last pass
+	Forked        bool             `json:"forked"`
+	Mismatches    []AnchorMismatch `json:"mismatches"`
+}
@@ -0,0 +1,16 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package interfaces
+
+// APIAuthConfig says who authenticates the clients of the API, and what they may do
+type APIAuthConfig struct {
+	Provider         string              // "basic" for the RPC user and password, or "oidc"
+	IntrospectionURL string              // OIDC token introspection endpoint
+	ClientID         string              // Credentials of the node at the introspection endpoint
+	ClientSecret     string              //
+	RolesClaim       string              // Claim holding the roles of the token, dotted for a nested claim
+	RolePermissions  map[string][]string // Permissions each role grants
+	CacheSeconds     int                 // How long an introspected token is trusted before asking again
+}
@@ -0,0 +1,27 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package interfaces
+
+// What became of a message submitted through the API
+const (
+	APISubmitAccepted = "accepted" // Queued with nothing ahead of it
+	APISubmitQueued   = "queued"   // Queued behind QueueDepth others
+	APISubmitRejected = "rejected" // Not taken; submit again after RetryAfter seconds
+)
+
+// APISubmission is what became of a message submitted through the API, or what would
+// become of one submitted now, so clients can back off before the node has to turn them
+// away
+type APISubmission struct {
+	Status     string `json:"status"`
+	QueueDepth int    `json:"queuedepth"` // Messages waiting in the API queue
+	QueueCap   int    `json:"queuecapacity"`
+	Syncing    bool   `json:"syncing"`              // Still loading or catching up on blocks
+	Overloaded bool   `json:"overloaded,omitempty"` // Too far behind on messages to take new commits
+	RetryAfter int    `json:"retryafter,omitempty"` // Seconds, when rejected
+
+	// First directory block the submission can appear in, unless the node is syncing
+	EarliestBlock uint32 `json:"earliestblock,omitempty"`
+}
@@ -0,0 +1,19 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package interfaces
+
+import "time"
+
+// APIV1Config says how the deprecated v1 API is served on its way out
+type APIV1Config struct {
+	Sunset            time.Time // When the v1 API is to be removed; zero if no date is set
+	Notice            string    // URL of the migration notes, linked from v1 responses
+	RefuseAfterSunset bool      // Answer v1 calls with 410 Gone once the sunset has passed
+}
+
+// Refused returns true if v1 calls are no longer served at the given time
+func (c APIV1Config) Refused(now time.Time) bool {
+	return c.RefuseAfterSunset && !c.Sunset.IsZero() && !now.Before(c.Sunset)
+}
@@ -0,0 +1,16 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package interfaces
+
+// AuthorityStats are the signature statistics gathered for one authority identity, from
+// the messages it has sent us.
+type AuthorityStats struct {
+	IdentityChainID   string  `json:"identitychainid"`
+	ValidSignatures   int64   `json:"validsignatures"`
+	InvalidSignatures int64   `json:"invalidsignatures"`
+	LastMessage       int64   `json:"lastmessage"` // Unix time of the last message seen
+	DBSigs            int64   `json:"dbsigs"`
+	AvgDBSigDelay     float64 `json:"avgdbsigdelay"` // Milliseconds from the start of the block
+}
@@ -0,0 +1,24 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package interfaces
+
+// How loaded a node is, by the messages waiting in its inbound queue
+const (
+	LoadNormal     = "normal"
+	LoadBusy       = "busy"       // Missing message requests are turned away
+	LoadOverloaded = "overloaded" // Missing message responses are shed, and new commits rejected
+)
+
+// Backpressure is how saturated the node's inbound queue is, so clients and operators can
+// back off before the node starts turning work away
+type Backpressure struct {
+	Level          string `json:"level"`
+	QueueDepth     int    `json:"queuedepth"` // Messages waiting in the inbound queue
+	QueueCap       int    `json:"queuecapacity"`
+	BusyAt         int    `json:"busyat"`               // Depth above which the node is busy
+	OverloadedAt   int    `json:"overloadedat"`         // Depth above which the node is overloaded
+	RetryAfter     int    `json:"retryafter,omitempty"` // Seconds, while not normal
+	ThrottledPeers int    `json:"throttledpeers"`       // Peers that told us they are overloaded
+}
@@ -24,6 +24,7 @@ type IFBlock interface {
 	GetChainID() IHash
 	// Validation functions
 	Validate() error
+	ValidateStructure() error // Validate, but not the signatures of the transactions
 	ValidateTransaction(int, ITransaction) error
 	// Marshal just the header for the block. This is to include the header
 	// in the FullHash
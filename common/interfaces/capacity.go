@@ -0,0 +1,43 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package interfaces
+
+// CapacityReport is what an operator needs to plan the capacity of a node: the load it
+// sustains against what it could take, the headroom of its queues, how fast its database
+// grows, and how long the garbage collector pauses it.
+type CapacityReport struct {
+	WindowSeconds       int64   `json:"windowseconds"`       // Time the rates are measured over
+	EntriesPerSecond    float64 `json:"entriespersecond"`    // Entries and chains sustained over the window
+	MaxEntriesPerSecond float64 `json:"maxentriespersecond"` // Estimated from the time this node takes to execute an entry; 0 until it has seen one
+	EntryExecuteMs      float64 `json:"entryexecutems"`      // Average time to execute a commit and its reveal
+	Utilization         float64 `json:"utilization"`         // EntriesPerSecond over MaxEntriesPerSecond
+
+	Queues []QueueHeadroom `json:"queues"`
+
+	DatabaseBytes       int64   `json:"databasebytes"`
+	GrowthBytesPerDay   float64 `json:"growthbytesperday"`
+	DiskFreeBytes       uint64  `json:"diskfreebytes"`       // 0 if unknown
+	DaysUntilDiskFull   float64 `json:"daysuntildiskfull"`   // -1 if the disk isn't filling, or free space is unknown
+	GrowthWindowSeconds int64   `json:"growthwindowseconds"` // Time the growth is measured over
+
+	GCPauses GCPauseStats `json:"gcpauses"`
+}
+
+// QueueHeadroom is how full a queue is
+type QueueHeadroom struct {
+	Name     string  `json:"name"`
+	Depth    int     `json:"depth"`
+	Cap      int     `json:"cap"`
+	Headroom float64 `json:"headroom"` // Fraction of the queue free
+}
+
+// GCPauseStats are the percentiles of the recent garbage collector pauses, in milliseconds
+type GCPauseStats struct {
+	Count int64   `json:"count"` // Collections since the node started
+	P50   float64 `json:"p50"`
+	P90   float64 `json:"p90"`
+	P99   float64 `json:"p99"`
+	Max   float64 `json:"max"`
+}
@@ -0,0 +1,25 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package interfaces
+
+// CoinbaseDiscrepancy is a factoid block whose coinbase doesn't pay what was expected
+type CoinbaseDiscrepancy struct {
+	DBHeight uint32   `json:"dbheight"`
+	TxID     string   `json:"txid"`     // The coinbase transaction, if the block has one
+	Paid     uint64   `json:"paid"`     // Factoshis the coinbase paid
+	Expected uint64   `json:"expected"` // Factoshis it should have paid
+	Problems []string `json:"problems"`
+}
+
+// CoinbaseAuditReport is the audit of the coinbase of every factoid block scanned
+type CoinbaseAuditReport struct {
+	AuditedHeight uint32                `json:"auditedheight"` // Factoid blocks up to here are audited
+	Complete      bool                  `json:"complete"`      // Caught up with the saved blocks
+	Blocks        int                   `json:"blocks"`        // Blocks audited since the node started
+	TotalPaid     uint64                `json:"totalpaid"`
+	TotalExpected uint64                `json:"totalexpected"`
+	Discrepancies int                   `json:"discrepancies"` // Blocks found with a discrepancy
+	Recent        []CoinbaseDiscrepancy `json:"recent"`        // The most recent discrepancies, oldest first
+}
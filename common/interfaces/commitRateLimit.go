@@ -0,0 +1,22 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package interfaces
+
+// CommitRateLimits is how fast this node, as a leader, takes the commits paid from each
+// entry credit address, and how the addresses that committed lately stand against it
+type CommitRateLimits struct {
+	Enabled   bool              `json:"enabled"`
+	PerMinute int               `json:"perminute"` // Commits an address may make a minute
+	Burst     int               `json:"burst"`     // Commits an address may make at once
+	Addresses []CommitRateUsage `json:"addresses"` // Most limited first
+}
+
+// CommitRateUsage is how an entry credit address stands against the commit rate limit
+type CommitRateUsage struct {
+	Address   string  `json:"address"`
+	Available float64 `json:"available"` // Commits it may make now
+	Allowed   uint64  `json:"allowed"`   // Commits taken since it was last idle
+	Limited   uint64  `json:"limited"`   // Commits held back since it was last idle
+}
@@ -42,3 +42,30 @@ type DatabaseBlockWithEntries interface {
 	GetEntryHashes() []IHash
 	GetEntrySigHashes() []IHash
 }
+
+// ISnapshotDatabase is implemented by databases that can take a consistent, point in time
+// snapshot of themselves while writes carry on.
+type ISnapshotDatabase interface {
+	Snapshot() (IDatabaseSnapshot, error)
+}
+
+// IDatabaseSnapshot is a read only view of a database at the moment the snapshot was taken
+type IDatabaseSnapshot interface {
+	// WriteTo writes the snapshot out as a new database at the given path, which must
+	// not already exist.
+	WriteTo(path string) error
+	Release()
+}
+
+// ITieredDatabase is implemented by databases that can move old records to a slower,
+// cheaper store, from which they are still served.
+type ITieredDatabase interface {
+	// Demote moves a record to the cold store
+	Demote(bucket, key []byte) error
+	// Prefetch loads records from the cold store ahead of their being asked for
+	Prefetch(bucket []byte, keys [][]byte)
+
+	// Height below which the directory blocks have been moved to the cold store
+	GetColdHeight() (uint32, error)
+	SetColdHeight(height uint32) error
+}
@@ -8,6 +8,8 @@
 
 package interfaces
 
+import "context"
+
 //A simplified DBOverlay to make sure we are not calling functions that could cause problems
 type DBOverlaySimple interface {
 	Close() error
@@ -31,6 +33,16 @@ type DBOverlaySimple interface {
 	FetchHeadIndexByChainID(chainID IHash) (IHash, error)
 	FetchIncludedIn(hash IHash) (IHash, error)
 	FetchPaidFor(hash IHash) (IHash, error)
+	FetchReferencingEntries(hash IHash) ([]EntryReference, error)
+	FetchChainIndex(chainID IHash, start uint32, count int) ([]ChainIndexBlock, error)
+	FindChainIndexHeight(chainID IHash, dbheight uint32) (sequence uint32, ok bool, err error)
+	FetchAddressTransactions(address IHash, start, count int) ([]AddressTransaction, int, error)
+	FetchEntriesByExtID(chainID IHash, prefix []byte, start, count int) ([]ExtIDMatch, int, error)
+	FetchEthereumAnchor(keyMR IHash) (IAnchorRecord, error)
+	FetchObject(namespace string, key IHash) ([]byte, error)
+	SaveObject(namespace string, key IHash, data []byte) error
+	DeleteObject(namespace string, key IHash) error
+	FetchObjectKeys(namespace string) ([]IHash, error)
 	FetchAllEBlocksByChain(IHash) ([]IEntryBlock, error)
 	InsertEntryMultiBatch(entry IEBEntry) error
 	ProcessABlockMultiBatch(block DatabaseBatchable) error
@@ -47,6 +59,12 @@ type DBOverlaySimple interface {
 	FetchAllEntriesByChainID(chainID IHash) ([]IEBEntry, error)
 }
 
+// ContextDBOverlay is an overlay whose reads can be bound to the context of a request,
+// so they stop once the request is cancelled
+type ContextDBOverlay interface {
+	WithContext(ctx context.Context) DBOverlaySimple
+}
+
 // Db defines a generic interface that is used to request and insert data into db
 type DBOverlay interface {
 	// We let Database method calls flow through.
@@ -249,6 +267,24 @@ type DBOverlay interface {
 	RebuildDirBlockInfo() error
 
 	FetchPaidFor(hash IHash) (IHash, error)
+	FetchReferencingEntries(hash IHash) ([]EntryReference, error)
+	FetchEthereumAnchor(keyMR IHash) (IAnchorRecord, error)
+
+	// The entry hashes of a chain's entry blocks, by sequence number
+	FetchChainIndex(chainID IHash, start uint32, count int) ([]ChainIndexBlock, error)
+	FindChainIndexHeight(chainID IHash, dbheight uint32) (sequence uint32, ok bool, err error)
+
+	// The factoid transactions of an address, in block order, and how many there are
+	FetchAddressTransactions(address IHash, start, count int) ([]AddressTransaction, int, error)
+
+	// The entries of a chain with an external ID starting with prefix, and how many there are
+	FetchEntriesByExtID(chainID IHash, prefix []byte, start, count int) ([]ExtIDMatch, int, error)
+
+	// Auxiliary data of co-located services, by namespace and hash
+	FetchObject(namespace string, key IHash) ([]byte, error)
+	SaveObject(namespace string, key IHash, data []byte) error
+	DeleteObject(namespace string, key IHash) error
+	FetchObjectKeys(namespace string) ([]IHash, error)
 
 	FetchFactoidTransaction(hash IHash) (ITransaction, error)
 	FetchECTransaction(hash IHash) (IECBlockEntry, error)
@@ -0,0 +1,13 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package interfaces
+
+// ECRateChange is one change in the entry credit exchange rate, in factoshis per entry
+// credit, and the first directory block height it applied to.
+type ECRateChange struct {
+	DBHeight  uint32 `json:"dbheight"`
+	Rate      uint64 `json:"rate"`
+	Timestamp int64  `json:"timestamp"` // Directory block timestamp, in seconds
+}
@@ -22,6 +22,27 @@ type IEntry interface {
 	KSize() int
 }
 
+// An entry referring to another entry or chain by its hash
+type EntryReference struct {
+	EntryHash IHash `json:"entryhash"`
+	ChainID   IHash `json:"chainid"`
+}
+
+// An entry found by one of its external IDs in the external ID index
+type ExtIDMatch struct {
+	EntryHash IHash  `json:"entryhash"`
+	ExtID     string `json:"extid"` // Hex of the external ID, up to the first 256 bytes
+}
+
+// What the chain index keeps of an entry block: its place in the chain, and the hashes of
+// its body in order, minute markers included
+type ChainIndexBlock struct {
+	Sequence    uint32
+	DBHeight    uint32
+	KeyMR       IHash
+	EntryHashes []IHash
+}
+
 type IPendingEntry struct {
 	EntryHash IHash  `json:"entryhash"`
 	ChainID   IHash  `json:"chainid"`
@@ -0,0 +1,71 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package interfaces
+
+// Types of the events applications can subscribe to
+const (
+	EventDirectoryBlock     = "directory-block"     // A directory block was saved
+	EventEntry              = "entry"               // An entry was saved in a chain
+	EventFactoidTransaction = "factoid-transaction" // A factoid transaction was saved
+	EventNodeStatus         = "node-status"         // The node changed status, as a StatusEvent
+)
+
+// Event is pushed to subscribers as blocks are saved and the node changes status.  Only
+// the fields that apply to the type are set.
+type Event struct {
+	Type      string `json:"type"`
+	DBHeight  uint32 `json:"dbheight"`
+	Timestamp int64  `json:"timestamp"`
+
+	KeyMR string `json:"keymr,omitempty"` // Directory block, or the entry block of an entry
+
+	ChainID   string `json:"chainid,omitempty"`
+	EntryHash string `json:"entryhash,omitempty"`
+
+	TxID      string   `json:"txid,omitempty"`
+	Addresses []string `json:"addresses,omitempty"` // FA and EC addresses the transaction pays from or to
+
+	Status *StatusEvent `json:"status,omitempty"`
+}
+
+// EventFilter picks the events a subscriber gets.  With no types, every type is sent.
+// Chain IDs limit entry events to those chains, and addresses limit factoid transaction
+// events to those that touch one of them; either left empty lets all through.
+type EventFilter struct {
+	Types     []string `json:"types,omitempty"`
+	ChainIDs  []string `json:"chainids,omitempty"`
+	Addresses []string `json:"addresses,omitempty"`
+}
+
+// Matches returns true if the subscriber wants the event
+func (f *EventFilter) Matches(e *Event) bool {
+	if len(f.Types) > 0 && !containsString(f.Types, e.Type) {
+		return false
+	}
+	switch e.Type {
+	case EventEntry:
+		return len(f.ChainIDs) == 0 || containsString(f.ChainIDs, e.ChainID)
+	case EventFactoidTransaction:
+		if len(f.Addresses) == 0 {
+			return true
+		}
+		for _, a := range e.Addresses {
+			if containsString(f.Addresses, a) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func containsString(list []string, s string) bool {
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,21 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package interfaces
+
+// HoldingReview counts what one ReviewHolding pass did with the messages in holding
+type HoldingReview struct {
+	Time      int64  `json:"time"` // Unix milliseconds, 0 if no pass has run
+	Forced    bool   `json:"forced"`
+	Skipped   string `json:"skipped,omitempty"` // Why the pass didn't run, if it didn't
+	Reviewed  int    `json:"reviewed"`          // In holding at the start
+	Requeued  int    `json:"requeued"`          // Moved to XReview to be processed again
+	Expired   int    `json:"expired"`           // Timed out, or outside the replay window
+	Stale     int    `json:"stale"`             // For heights already saved, or dropped while far behind
+	Invalid   int    `json:"invalid"`           // Deleted as invalid
+	Resent    int    `json:"resent"`            // Sent to peers again, and kept
+	Waiting   int    `json:"waiting"`           // Kept, waiting on something to arrive
+	Remaining int    `json:"remaining"`         // In holding at the end
+	Duration  int64  `json:"duration"`          // Nanoseconds
+}
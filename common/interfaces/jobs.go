@@ -0,0 +1,19 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package interfaces
+
+// JobStatus describes a periodic maintenance job run by the node
+type JobStatus struct {
+	Name         string  `json:"name"`
+	Interval     float64 `json:"interval"` // Seconds between runs, 0 if only run when triggered
+	Jitter       float64 `json:"jitter"`   // Most seconds a run is put off by, to spread the load
+	Enabled      bool    `json:"enabled"`
+	Runs         int     `json:"runs"`
+	Failures     int     `json:"failures"`
+	LastRun      int64   `json:"lastrun"`      // Unix time, 0 if never run
+	LastDuration float64 `json:"lastduration"` // Seconds
+	LastError    string  `json:"lasterror,omitempty"`
+	NextRun      int64   `json:"nextrun"` // Unix time, 0 if not scheduled
+}
@@ -0,0 +1,14 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package interfaces
+
+// MessageDelay is a delay injected into the messages of one type a node receives from
+// its peers, for testing.  Each message is held for a random time between Min and Max.
+type MessageDelay struct {
+	MessageType byte   `json:"type"`
+	Name        string `json:"name"`
+	MinMs       int64  `json:"min"` // Milliseconds
+	MaxMs       int64  `json:"max"` // Milliseconds
+}
@@ -0,0 +1,35 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package interfaces
+
+// ActivationHeight is the directory block height a change in the consensus rules took
+// effect on this network
+type ActivationHeight struct {
+	Name        string `json:"name"`
+	Height      uint32 `json:"height"`
+	Description string `json:"description"`
+}
+
+// NetworkParameters are the consensus parameters in effect on the network the node is
+// running on, so clients can configure themselves for mainnet, testnet or a custom net.
+type NetworkParameters struct {
+	NetworkName       string             `json:"networkname"` // MAIN, TEST, LOCAL or CUSTOM
+	NetworkID         string             `json:"networkid"`   // Hex, as in directory block headers
+	BootstrapIdentity string             `json:"bootstrapidentity"`
+	BootstrapKey      string             `json:"bootstrapkey"`
+	BlockTime         int                `json:"blocktime"` // Seconds per directory block
+	MinutesPerBlock   int                `json:"minutesperblock"`
+	FactoshisPerEC    uint64             `json:"factoshisperec"`
+	FaultTimeout      int                `json:"faulttimeout"` // Seconds
+	FaultWait         int                `json:"faultwait"`    // Seconds
+	Checkpoints       int                `json:"checkpoints"`
+	HighestCheckpoint uint32             `json:"highestcheckpoint"`
+	ActivationHeights []ActivationHeight `json:"activationheights"`
+
+	// Most servers a block adds or removes, and the fewest blocks old an identity must
+	// be to be promoted; 0 for no limit
+	AuthorityChangesPerBlock int `json:"authoritychangesperblock"`
+	AuthorityMinIdentityAge  int `json:"authorityminidentityage"`
+}
@@ -0,0 +1,17 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package interfaces
+
+// PeerReputation is how a peer of the network has behaved
+type PeerReputation struct {
+	PeerHash    string         `json:"peerhash"`
+	Address     string         `json:"address"`
+	Special     bool           `json:"special"`               // Special peers are never demoted or banned
+	Score       float64        `json:"score"`                 // Penalty points, fading with time; 0 is a clean record
+	Standing    string         `json:"standing"`              // good, demoted or banned
+	Offenses    map[string]int `json:"offenses"`              // Count of each offense scored
+	LastOffense int64          `json:"lastoffense,omitempty"` // Unix time
+	Pending     int            `json:"pending"`               // Requests sent it that it has yet to answer
+}
@@ -0,0 +1,33 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package interfaces
+
+// PendingPool is what waits to go in a block: the commits in the mempool, highest paying
+// first, and the reveals held, paying the most per credit needed first
+type PendingPool struct {
+	Commits    []PendingCommit `json:"commits"`
+	Reveals    []PendingReveal `json:"reveals"`
+	MaxCommits int             `json:"maxcommits"` // 0 for no limit
+	MaxAge     int64           `json:"maxage"`     // Seconds, 0 if only the replay filter expires commits
+}
+
+// PendingCommit is a commit waiting on its reveal, or on a block to take them, or on its ack
+type PendingCommit struct {
+	EntryHash   string `json:"entryhash"`
+	ChainIDHash string `json:"chainidhash,omitempty"` // Chain commits only
+	Credits     int    `json:"credits"`
+	Received    int64  `json:"received"` // Unix milliseconds
+	Revealed    bool   `json:"revealed"` // Its reveal is held too
+	Paid        bool   `json:"paid"`     // Acked and processed, so never evicted for age or room
+}
+
+// PendingReveal is a reveal held, waiting on its commit, its chain, or room in a block
+type PendingReveal struct {
+	EntryHash string `json:"entryhash"`
+	ChainID   string `json:"chainid"`
+	KSize     int    `json:"ksize"`
+	Credits   int    `json:"credits"` // Paid by its commit, 0 if none has come
+	Needed    int    `json:"needed"`  // What it costs, with the chain if it starts one
+}
@@ -0,0 +1,16 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package interfaces
+
+// PinnedChainStatus is how complete the local history of a pinned chain is, as of the
+// last backfill pass over it.
+type PinnedChainStatus struct {
+	ChainID  string `json:"chainid"`
+	EBlocks  int    `json:"eblocks"`
+	Entries  int    `json:"entries"`
+	Missing  int    `json:"missing"`
+	Complete bool   `json:"complete"`
+	LastScan int64  `json:"lastscan"` // Unix time of the last pass, 0 if not scanned yet
+}
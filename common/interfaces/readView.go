@@ -0,0 +1,24 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package interfaces
+
+// IReadView is an immutable view of the state published as each block is saved, so the
+// API can answer queries without locking the maps the consensus loop is working on.
+// Balances include the changes made by the block being built, as of its last minute.
+type IReadView interface {
+	GetDBHeight() uint32 // Height of the last block saved when the view was published
+	GetKeyMR() IHash     // KeyMR of that block
+	GetTimestamp() int64 // Unix time the view was last updated
+
+	GetFactoidBalance(address [32]byte) int64
+	GetECBalance(address [32]byte) int64
+
+	// True if the chain has entries in a block that isn't saved yet
+	IsChainPending(chainID IHash) bool
+
+	GetFedServers() []IServer
+	GetAuditServers() []IServer
+	GetAuthorities() []IAuthority
+}
@@ -0,0 +1,31 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package interfaces
+
+import "encoding/json"
+
+// ReceiptSubscription asks for the receipt of an entry once the anchor of its directory
+// block is deep enough in Bitcoin
+type ReceiptSubscription struct {
+	ID            string `json:"id"`
+	EntryHash     string `json:"entryhash"`
+	Confirmations int    `json:"confirmations"`     // Bitcoin confirmations wanted
+	Webhook       string `json:"webhook,omitempty"` // Where the notice is posted; empty for websocket subscribers
+	Created       int64  `json:"created"`           // Unix time
+	Expires       int64  `json:"expires"`           // Unix time the subscription is dropped if not yet confirmed
+	Attempts      int    `json:"attempts"`          // Times the webhook was tried
+	LastError     string `json:"lasterror,omitempty"`
+}
+
+// ReceiptNotice is pushed once a subscribed entry's anchor is deep enough.  The receipt is
+// the full receipt, as the receipt API call returns it.
+type ReceiptNotice struct {
+	SubscriptionID     string          `json:"subscriptionid"`
+	EntryHash          string          `json:"entryhash"`
+	DBHeight           uint32          `json:"dbheight"`
+	BitcoinBlockHeight int32           `json:"bitcoinblockheight"`
+	Confirmations      int             `json:"confirmations"`
+	Receipt            json.RawMessage `json:"receipt"`
+}
@@ -0,0 +1,21 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package interfaces
+
+// Rejection is a block or message this node turned away, and why
+type Rejection struct {
+	Kind      string `json:"kind"`           // "block" or "message"
+	Hash      string `json:"hash"`           // KeyMR of the directory block, or the message hash
+	DBHeight  uint32 `json:"dbheight"`       // Height of the block, or the height being built when the message came
+	Message   string `json:"message"`        // Type of the message
+	Reason    string `json:"reason"`         // Why it was turned away, i.e. "prev-keymr-mismatch"
+	Peer      string `json:"peer,omitempty"` // Peer it came from, empty if it was local
+	Timestamp int64  `json:"timestamp"`
+}
+
+// RejectionReport holds the rejections recorded, oldest first
+type RejectionReport struct {
+	Rejections []Rejection `json:"rejections"`
+}
@@ -0,0 +1,16 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package interfaces
+
+// SendOutStats counts the calls to SendOut for the messages of one type.  A message is
+// broadcast by the first call; any later call for the same message is a duplicate,
+// either sent again or suppressed by SendOut.
+type SendOutStats struct {
+	MessageType byte   `json:"type"`
+	Name        string `json:"name"`
+	Broadcasts  uint64 `json:"broadcasts"` // First sends of a message
+	Repeats     uint64 `json:"repeats"`    // Duplicates sent again
+	Suppressed  uint64 `json:"suppressed"` // Calls that SendOut dropped
+}
@@ -0,0 +1,15 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package interfaces
+
+// ShutdownStatus reports how far along the node is in shutting down
+type ShutdownStatus struct {
+	Step      string   `json:"step"`      // Empty while the node is running
+	StepIndex int      `json:"stepindex"` // 1 based position of the step in Steps
+	Steps     int      `json:"steps"`
+	Elapsed   int64    `json:"elapsed"` // Seconds since the shutdown began
+	Complete  bool     `json:"complete"`
+	Errors    []string `json:"errors,omitempty"` // Steps that failed, and why
+}
@@ -0,0 +1,34 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package interfaces
+
+// SlowRound is a minute (EOMs) or block start (DBSigs) that ran past its expected window,
+// and the federated server whose message was processed last.
+type SlowRound struct {
+	Kind        string `json:"kind"` // "EOM" or "DBSig"
+	DBHeight    uint32 `json:"dbheight"`
+	Minute      int    `json:"minute"`
+	LastServer  string `json:"lastserver"`
+	LastArrival int64  `json:"lastarrival"` // Milliseconds after the round started
+	Window      int64  `json:"window"`      // Milliseconds the round was expected to take
+	Excess      int64  `json:"excess"`      // Milliseconds the last message was past the window
+	Timestamp   int64  `json:"timestamp"`   // Unix time the round completed
+}
+
+// SlowServerStats totals, over the recent slow rounds, how often a server was the last
+// to be heard from and how late it was.
+type SlowServerStats struct {
+	IdentityChainID string `json:"identitychainid"`
+	EOMLast         int    `json:"eomlast"`
+	DBSigLast       int    `json:"dbsiglast"`
+	TotalExcess     int64  `json:"totalexcess"` // Milliseconds
+	MaxExcess       int64  `json:"maxexcess"`   // Milliseconds
+}
+
+// SlowRoundReport is the rolling report of recent slow rounds
+type SlowRoundReport struct {
+	Rounds  []SlowRound       `json:"rounds"` // Oldest first
+	Servers []SlowServerStats `json:"servers"`
+}
@@ -0,0 +1,18 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package interfaces
+
+// StartupStatus reports how far along the node is in booting
+type StartupStatus struct {
+	Phase      string  `json:"phase"`
+	PhaseIndex int     `json:"phaseindex"` // 1 based position of the phase in Phases
+	Phases     int     `json:"phases"`
+	Done       uint64  `json:"done"`
+	Total      uint64  `json:"total"` // 0 if the size of the phase is not known
+	Percent    float64 `json:"percent"`
+	ETA        int64   `json:"eta"`     // Seconds left in the phase, -1 if unknown
+	Elapsed    int64   `json:"elapsed"` // Seconds since the node started
+	Complete   bool    `json:"complete"`
+}
@@ -4,6 +4,8 @@
 
 package interfaces
 
+import "time"
+
 type DBStateSent struct {
 	DBHeight uint32
 	Sent     Timestamp
@@ -74,6 +76,11 @@ type IState interface {
 	GetRpcAuthHash() []byte
 	GetTlsInfo() (bool, string, string)
 	GetFactomdLocations() string
+	GetAPIAccessLog() APIAccessLogConfig
+	GetAPIAuditLog() APIAuditLogConfig
+	GetAPIAuth() APIAuthConfig
+	GetAPIV1() APIV1Config
+	GetAPIRequestTimeout() time.Duration
 
 	// Routine for handling the syncroniztion of the leader and follower processes
 	// and how they process messages.
@@ -116,6 +123,10 @@ type IState interface {
 	AckQueue() chan IMsg // Leader Queue
 	MsgQueue() chan IMsg // Follower Queue
 
+	// Submissions from the API, turned away while the node can't take them
+	SubmitAPIMsg(msg IMsg) APISubmission
+	GetAPIQueueStatus() APISubmission // What a message submitted now would get
+
 	// Lists and Maps
 	// =====
 	GetAuditHeartBeats() []IMsg // The checklist of HeartBeats for this period
@@ -201,17 +212,22 @@ type IState interface {
 	GetSystemMsg(dbheight, height uint32) IMsg // Return the system message at the given height.
 	SendDBSig(dbheight uint32, vmIndex int)    // If a Leader, we have to send a DBSig out for the previous block
 
-	FollowerExecuteMsg(IMsg)          // Messages that go into the process list
-	FollowerExecuteEOM(IMsg)          // Messages that go into the process list
-	FollowerExecuteAck(IMsg)          // Ack Msg calls this function.
-	FollowerExecuteDBState(IMsg)      // Add the given DBState to this server
-	FollowerExecuteSFault(IMsg)       // Handling of Server Fault Messages
-	FollowerExecuteFullFault(IMsg)    // Handle Server Full-Fault Messages
-	FollowerExecuteMMR(IMsg)          // Handle Missing Message Responses
-	FollowerExecuteDataResponse(IMsg) // Handle Data Response
-	FollowerExecuteMissingMsg(IMsg)   // Handle requests for missing messages
-	FollowerExecuteCommitChain(IMsg)  // CommitChain needs to look for a Reveal Entry
-	FollowerExecuteCommitEntry(IMsg)  // CommitEntry needs to look for a Reveal Entry
+	FollowerExecuteMsg(IMsg)               // Messages that go into the process list
+	FollowerExecuteEOM(IMsg)               // Messages that go into the process list
+	FollowerExecuteAck(IMsg)               // Ack Msg calls this function.
+	FollowerExecuteDBState(IMsg)           // Add the given DBState to this server
+	FollowerExecuteSFault(IMsg)            // Handling of Server Fault Messages
+	FollowerExecuteFullFault(IMsg)         // Handle Server Full-Fault Messages
+	FollowerExecuteStepDown(IMsg)          // Handle a leader volunteering to be replaced
+	FollowerExecuteElectionVolunteer(IMsg) // Handle an audit server volunteering in an election
+	FollowerExecuteElectionAccept(IMsg)    // Handle a leader accepting the volunteer of an election
+	FollowerExecuteMMR(IMsg)               // Handle Missing Message Responses
+	FollowerExecuteDataResponse(IMsg)      // Handle Data Response
+	FollowerExecuteMissingMsg(IMsg)        // Handle requests for missing messages
+	FollowerExecuteSyncRequest(IMsg)       // Handle requests to stream blocks to a node catching up
+	FollowerExecuteSyncChunk(IMsg)         // Handle blocks streamed to us as we catch up
+	FollowerExecuteCommitChain(IMsg)       // CommitChain needs to look for a Reveal Entry
+	FollowerExecuteCommitEntry(IMsg)       // CommitEntry needs to look for a Reveal Entry
 	FollowerExecuteRevealEntry(IMsg)
 
 	ProcessAddServer(dbheight uint32, addServerMsg IMsg) bool
@@ -312,6 +328,110 @@ type IState interface {
 	LoadHoldingMap() map[[32]byte]IMsg
 	LoadAcksMap() map[[32]byte]IMsg
 
+	// Status transition events
+	SubscribeStatusEvents() (int, <-chan *StatusEvent)
+	UnsubscribeStatusEvents(id int)
+
+	// Events of saved blocks and status changes, as the filter picks them
+	SubscribeEvents(filter EventFilter) (int, <-chan *Event)
+	UnsubscribeEvents(id int)
+
+	// Write a consistent copy of the database, returning the height it holds
+	BackupDatabase(path string) (uint32, error)
+
+	// Signature statistics per authority identity
+	RecordAuthoritySignature(identity IHash, msgHash IHash, valid bool)
+	GetAuthorityStats() []AuthorityStats
+
+	// Chains whose full history is always kept
+	IsChainPinned(chainID IHash) bool
+	PinChain(chainID IHash, pin bool)
+	GetPinnedChains() []PinnedChainStatus
+
+	// Progress through booting
+	GetStartupStatus() StartupStatus
+
+	// Minutes and block starts that ran long, by the server heard from last
+	GetSlowRounds() SlowRoundReport
+
+	// Snapshot of the consensus state machine and its sync flags
+	GetSyncState() SyncState
+
+	// How far saved blocks have been checked against the anchor records
+	GetAnchorCheck() AnchorCheckStatus
+
+	// Periodic maintenance jobs
+	GetJobs() []JobStatus
+	TriggerJob(name string) error
+	SetJobEnabled(name string, enabled bool) error
+
+	// Passes over the messages in holding
+	ReviewHoldingNow() (HoldingReview, error) // Force a pass on the consensus thread
+	GetLastHoldingReview() HoldingReview
+
+	// Serving our saved blocks and entries to the followers of the operator's cluster
+	IsClusterCacheServer() bool
+
+	// The commits and reveals waiting to go in a block
+	GetPendingPool() PendingPool
+
+	// How saturated the inbound queue is
+	GetBackpressure() Backpressure
+
+	// How fast this leader takes the commits of each entry credit address
+	GetCommitRateLimits() CommitRateLimits
+
+	// How each peer of the network has behaved, worst first
+	GetPeerReputations() []PeerReputation
+
+	// Delays injected into messages from peers by type, for testing
+	SetMessageDelay(msgType byte, min, max time.Duration)
+	GetMessageDelays() []MessageDelay
+	GetMessageDelay(msgType byte) time.Duration
+	InjectMessage(msg IMsg) bool // Queue a message as though a peer sent it
+
+	// Factoid supply, with the movements of up to the last blocks blocks
+	GetSupply(blocks int) SupplyStatus
+
+	// Changes of the entry credit exchange rate in the saved blocks
+	GetECRateHistory() ([]ECRateChange, error)
+
+	// Minutes and blocks that took far more or less time than they should have
+	GetTimingAnomalies() TimingAnomalyReport
+
+	// Blocks and messages this node turned away, and why; just those of hash if not empty
+	GetRejections(hash string) RejectionReport
+
+	// Load against capacity, queue headroom, database growth and GC pauses
+	GetCapacityReport() CapacityReport
+
+	// Calls to SendOut, whether the message went out, and the counts by message type
+	NoteSendOut(msg IMsg, sent bool)
+	GetSendOutStats() []SendOutStats
+
+	// Coinbase payouts of the saved blocks checked against the expected ones
+	GetCoinbaseAudit() CoinbaseAuditReport
+
+	// Operator defined alert rules, and whether they are firing
+	GetAlerts() []AlertStatus
+
+	// Receipts pushed once the anchor of an entry's directory block is deep enough
+	SubscribeReceipt(entryHash IHash, confirmations int, webhook string) (ReceiptSubscription, error)
+	UnsubscribeReceipt(id string) bool
+	GetReceiptSubscriptions() []ReceiptSubscription
+	WatchReceiptNotices() (int, <-chan *ReceiptNotice)
+	UnwatchReceiptNotices(id int)
+
+	// Snapshot of the state as of the last saved block, for answering queries.  Nil
+	// until the first block is saved.
+	GetReadView() IReadView
+
+	// The consensus parameters in effect on this network
+	GetNetworkParameters() NetworkParameters
+
+	// Log the progress of a submitted message against the application's trace
+	TraceMessage(msgHash IHash, trace *TraceContext)
+
 	// Plugins
 	UsingTorrent() bool
 	GetMissingDBState(height uint32) error
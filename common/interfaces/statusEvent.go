@@ -0,0 +1,30 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package interfaces
+
+// Status event types pushed to subscribers when the node changes state
+const (
+	StatusEventSyncing       = "syncing"
+	StatusEventInSync        = "in-sync"
+	StatusEventLeader        = "leader"
+	StatusEventFollower      = "follower"
+	StatusEventElectionStart = "election-started"
+	StatusEventElectionEnd   = "election-finished"
+	StatusEventStalled       = "stalled"
+	StatusEventUnstalled     = "stall-cleared"
+	StatusEventForkDetected  = "fork-detected"
+)
+
+// StatusEvent describes a single transition in the status of a node.  VMIndex
+// is only meaningful for leader and election events, and is -1 otherwise.
+type StatusEvent struct {
+	Type      string `json:"type"`
+	NodeName  string `json:"nodename"`
+	DBHeight  uint32 `json:"dbheight"`
+	Minute    int    `json:"minute"`
+	VMIndex   int    `json:"vmindex"`
+	Timestamp int64  `json:"timestamp"`
+	Detail    string `json:"detail,omitempty"`
+}
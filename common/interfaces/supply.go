@@ -0,0 +1,27 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package interfaces
+
+// SupplyBlock is the movement of factoids in one factoid block, in factoshis
+type SupplyBlock struct {
+	DBHeight  uint32 `json:"dbheight"`
+	Timestamp int64  `json:"timestamp"` // Unix time of the block's coinbase
+	Issued    uint64 `json:"issued"`    // Created by coinbase transactions, and the genesis block
+	Burned    uint64 `json:"burned"`    // Converted to entry credits
+	Fees      uint64 `json:"fees"`      // Paid by transactions, and destroyed
+}
+
+// SupplyStatus is the factoid supply as of the last factoid block scanned, in factoshis
+type SupplyStatus struct {
+	ScannedHeight  uint32        `json:"scannedheight"` // Factoid blocks up to here are counted
+	Complete       bool          `json:"complete"`      // Caught up with the saved blocks
+	TotalIssued    uint64        `json:"totalissued"`
+	TotalBurned    uint64        `json:"totalburned"`
+	TotalFees      uint64        `json:"totalfees"`
+	TotalSupply    uint64        `json:"totalsupply"`    // Issued, less burned and fees
+	NonCirculating uint64        `json:"noncirculating"` // Held by the addresses configured as not circulating
+	Circulating    uint64        `json:"circulating"`    // An estimate: the supply less what isn't circulating
+	History        []SupplyBlock `json:"history"`        // The most recent blocks, oldest first
+}
@@ -0,0 +1,113 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package interfaces
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// SyncStateVM is the sync status of one VM of the leader process list
+type SyncStateVM struct {
+	VMIndex      int    `json:"vmindex"`
+	Leader       string `json:"leader"`       // Federated server leading the VM this minute
+	Height       int    `json:"height"`       // Messages processed
+	Acked        int    `json:"acked"`        // Messages acknowledged
+	LeaderMinute int    `json:"leaderminute"` // Where the leader is in acknowledging messages
+	Synced       bool   `json:"synced"`       // Its EOM or DBSig has been processed
+	Signed       bool   `json:"signed"`       // Its DBSig for the previous block has been processed
+	Faulted      bool   `json:"faulted"`
+	FaultFlag    int    `json:"faultflag"` // 0 = EOM missing, 1 = negotiation issue
+}
+
+// SyncStateNode is a phase of the consensus state machine
+type SyncStateNode struct {
+	ID     string `json:"id"`
+	Label  string `json:"label"`
+	Active bool   `json:"active"` // The phase the node is in
+}
+
+// SyncStateEdge is a move between phases of the consensus state machine
+type SyncStateEdge struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Label string `json:"label"` // What makes the move
+}
+
+// SyncState is a snapshot of the consensus state machine: the sync flags, how far the
+// EOMs and DBSigs of the current round have got, and each VM, along with the phases the
+// flags put the node in.
+type SyncState struct {
+	DBHeight      uint32 `json:"dbheight"`
+	Minute        int    `json:"minute"`
+	Phase         string `json:"phase"` // ID of the active node
+	Leader        bool   `json:"leader"`
+	LeaderVMIndex int    `json:"leadervmindex"`
+
+	Syncing    bool `json:"syncing"` // Looking for messages from leaders to sync
+	Saving     bool `json:"saving"`  // Building the next block
+	EOMsyncing bool `json:"eomsyncing"`
+
+	EOM          bool `json:"eom"` // Processing the EOMs of a minute
+	EOMMinute    int  `json:"eomminute"`
+	EOMProcessed int  `json:"eomprocessed"`
+	EOMLimit     int  `json:"eomlimit"`
+	EOMDone      bool `json:"eomdone"`
+	EOMSys       bool `json:"eomsys"` // The EOMs have covered the system list
+
+	DBSig          bool `json:"dbsig"` // Processing the DBSigs of a block
+	DBSigProcessed int  `json:"dbsigprocessed"`
+	DBSigLimit     int  `json:"dbsiglimit"`
+	DBSigDone      bool `json:"dbsigdone"`
+	DBSigSys       bool `json:"dbsigsys"` // The DBSigs have covered the system list
+
+	SystemHeight  int  `json:"systemheight"`  // Faults processed in the system list
+	SystemLength  int  `json:"systemlength"`  // Faults in the system list
+	SysHighest    int  `json:"syshighest"`    // Highest system height an EOM asked for
+	FaultPending  bool `json:"faultpending"`  // A fault is in the system list but not processed
+	FaultsWaiting bool `json:"faultswaiting"` // The EOMs wait on faults we haven't processed
+
+	VMs   []SyncStateVM   `json:"vms"`
+	Nodes []SyncStateNode `json:"nodes"`
+	Edges []SyncStateEdge `json:"edges"`
+}
+
+// DOT renders the snapshot as a Graphviz digraph, the active phase filled in and each VM
+// as a record in its own cluster.
+func (s *SyncState) DOT() string {
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "digraph consensus {\n")
+	fmt.Fprintf(&out, "\tlabel=%q;\n\tlabelloc=t;\n\tnode [shape=ellipse];\n", fmt.Sprintf("dbheight %d minute %d", s.DBHeight, s.Minute))
+	for _, n := range s.Nodes {
+		style := ""
+		if n.Active {
+			style = ", style=filled, fillcolor=lightblue"
+		}
+		fmt.Fprintf(&out, "\t%q [label=%q%s];\n", n.ID, n.Label, style)
+	}
+	for _, e := range s.Edges {
+		fmt.Fprintf(&out, "\t%q -> %q [label=%q];\n", e.From, e.To, e.Label)
+	}
+
+	fmt.Fprintf(&out, "\tsubgraph cluster_vms {\n\t\tlabel=\"VMs\";\n\t\tnode [shape=record];\n")
+	for _, vm := range s.VMs {
+		color := "white"
+		switch {
+		case vm.Faulted:
+			color = "salmon"
+		case vm.Synced:
+			color = "palegreen"
+		}
+		leader := vm.Leader
+		if len(leader) > 10 {
+			leader = leader[:10]
+		}
+		label := fmt.Sprintf("{vm %d|%s|height %d/%d|minute %d|synced %v|signed %v|faulted %v}",
+			vm.VMIndex, leader, vm.Height, vm.Acked, vm.LeaderMinute, vm.Synced, vm.Signed, vm.Faulted)
+		fmt.Fprintf(&out, "\t\t\"vm%d\" [label=%q, style=filled, fillcolor=%s];\n", vm.VMIndex, label, color)
+	}
+	fmt.Fprintf(&out, "\t}\n}\n")
+	return out.String()
+}
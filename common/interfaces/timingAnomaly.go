@@ -0,0 +1,22 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package interfaces
+
+// TimingAnomaly is a minute or block that took far more or less time than it should have
+type TimingAnomaly struct {
+	Kind      string `json:"kind"`     // "short-minute", "long-block" or "timestamp-backwards"
+	Source    string `json:"source"`   // "local" for what this node saw, "header" for block timestamps
+	DBHeight  uint32 `json:"dbheight"` // The block, or the block the minute is in
+	Minute    int    `json:"minute"`   // Only set for minutes
+	Duration  int64  `json:"duration"` // Milliseconds the minute or block took
+	Limit     int64  `json:"limit"`    // Milliseconds the duration was checked against
+	Timestamp int64  `json:"timestamp"`
+}
+
+// TimingAnomalyReport holds the anomalies found, oldest first
+type TimingAnomalyReport struct {
+	ScannedHeight uint32          `json:"scannedheight"` // Block timestamps are checked up to here
+	Anomalies     []TimingAnomaly `json:"anomalies"`
+}
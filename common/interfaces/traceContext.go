@@ -0,0 +1,28 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package interfaces
+
+import (
+	"fmt"
+)
+
+// TraceContext is the W3C trace context (the traceparent header) an application sent
+// with a submission, so the message's progress through consensus can be tied back to
+// the application's own traces.
+type TraceContext struct {
+	Version  byte
+	TraceID  [16]byte
+	ParentID [8]byte
+	Flags    byte
+}
+
+// TraceParent renders the context as a traceparent header, with spanID as the parent
+func (t *TraceContext) TraceParent(spanID [8]byte) string {
+	return fmt.Sprintf("%02x-%x-%x-%02x", t.Version, t.TraceID, spanID, t.Flags)
+}
+
+func (t *TraceContext) String() string {
+	return t.TraceParent(t.ParentID)
+}
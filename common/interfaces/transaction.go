@@ -95,3 +95,11 @@ type IPendingTransaction struct {
 	ECOutputs     []ITransAddress `json:"ecoutputs"`
 	Fees          uint64          `json:"fees"`
 }
+
+// A factoid transaction in the address index: the transaction, and its place in the factoid
+// blocks
+type AddressTransaction struct {
+	TxID     IHash  `json:"txid"`
+	DBHeight uint32 `json:"height"`
+	Index    uint32 `json:"index"` // Of the transaction in its factoid block
+}
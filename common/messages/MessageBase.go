@@ -53,19 +53,26 @@ func (m *MessageBase) PutAck(ack interfaces.IMsg) {
 	m.Ack = ack
 }
 
+// MaxSendOuts is the most times SendOut sends a message, the first time included
+const MaxSendOuts = 5
+
 func (m *MessageBase) SendOut(state interfaces.IState, msg interfaces.IMsg) {
 	// Dont' resend if we are behind
 	if m.ResendCnt > 1 && state.GetHighestKnownBlock()-state.GetHighestSavedBlk() > 4 {
+		state.NoteSendOut(msg, false)
 		return
 	}
 	if m.NoResend {
+		state.NoteSendOut(msg, false)
 		return
 	}
 
-	if m.ResendCnt > 4 {
+	if m.ResendCnt >= MaxSendOuts {
+		state.NoteSendOut(msg, false)
 		return
 	}
 	m.ResendCnt++
+	state.NoteSendOut(msg, true)
 
 	switch msg.(interface{}).(type) {
 	//case ServerFault:
@@ -35,12 +35,19 @@ type Ack struct {
 	authvalid   bool
 	Response    bool // A response to a missing data request
 	BalanceHash interfaces.IHash
+	batch       *BatchAck // The batch that signed the ack, if it came in one
 }
 
 var _ interfaces.IMsg = (*Ack)(nil)
 var _ Signable = (*Ack)(nil)
 var AckBalanceHash = true
 
+// GetBatch returns the batch ack the ack was unpacked from, nil if it came on its own.  An
+// unpacked ack has no signature of its own; the batch is its proof.
+func (m *Ack) GetBatch() *BatchAck {
+	return m.batch
+}
+
 func (m *Ack) GetRepeatHash() interfaces.IHash {
 	return m.GetMsgHash()
 }
@@ -90,6 +97,10 @@ func (m *Ack) Validate(state interfaces.IState) int {
 	}
 
 	if !m.authvalid {
+		// An ack out of a batch only has the batch's signature
+		if m.Signature == nil {
+			return -1
+		}
 		// Check signature
 		bytes, err := m.MarshalForSignature()
 		if err != nil {
@@ -102,11 +113,14 @@ func (m *Ack) Validate(state interfaces.IState) int {
 		//ackSigned, err := m.VerifySignature()
 		if err != nil {
 			//fmt.Println("Err is not nil on Ack sig check: ", err)
+			state.RecordAuthoritySignature(m.LeaderChainID, m.GetMsgHash(), false)
 			return -1
 		}
 		if ackSigned <= 0 {
+			state.RecordAuthoritySignature(m.LeaderChainID, m.GetMsgHash(), false)
 			return -1
 		}
+		state.RecordAuthoritySignature(m.LeaderChainID, m.GetMsgHash(), true)
 	}
 
 	m.authvalid = true
@@ -0,0 +1,421 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package messages
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Most acks a batch may carry
+const MaxBatchAcks = 256
+
+// BatchAck carries the acks a leader made for consecutive messages of its VM within a
+// minute, as one message under one signature.  The acks go without their own signatures;
+// the batch signs the Merkle root of their hashes instead, so once the batch checks out,
+// each of its acks is as good as signed by the leader.
+type BatchAck struct {
+	MessageBase
+	Timestamp  interfaces.Timestamp // Timestamp of the batch by the leader
+	DBHeight   uint32               // Directory Block Height that owns the acks
+	MerkleRoot interfaces.IHash     // Root of the hashes of the acks
+	Acks       []*Ack               // In order of height, unsigned
+
+	Signature interfaces.IFullSignature
+	//Not marshalled
+	authvalid bool
+}
+
+var _ interfaces.IMsg = (*BatchAck)(nil)
+var _ Signable = (*BatchAck)(nil)
+
+// NewBatchAck batches the acks of a VM within a minute, leaving their signatures out.  The
+// batch must be signed before it is sent.
+func NewBatchAck(timestamp interfaces.Timestamp, acks []*Ack) (*BatchAck, error) {
+	if len(acks) == 0 {
+		return nil, errors.New("No acks to batch")
+	}
+	m := new(BatchAck)
+	m.Timestamp = timestamp
+	m.VMIndex = acks[0].VMIndex
+	m.Minute = acks[0].Minute
+	m.LeaderChainID = acks[0].LeaderChainID
+	m.DBHeight = acks[0].DBHeight
+	for _, a := range acks {
+		data, err := a.MarshalForSignature()
+		if err != nil {
+			return nil, err
+		}
+		ack := new(Ack)
+		if err := ack.UnmarshalBinary(data); err != nil {
+			return nil, err
+		}
+		m.Acks = append(m.Acks, ack)
+	}
+	if err := m.checkAcks(); err != nil {
+		return nil, err
+	}
+	m.MerkleRoot = m.ComputeMerkleRoot()
+	return m, nil
+}
+
+// checkAcks returns an error unless the acks follow each other in the VM and minute of the
+// batch
+func (m *BatchAck) checkAcks() error {
+	if len(m.Acks) == 0 || len(m.Acks) > MaxBatchAcks {
+		return fmt.Errorf("Batch of %d acks", len(m.Acks))
+	}
+	for i, a := range m.Acks {
+		if a.VMIndex != m.VMIndex || a.Minute != m.Minute || a.DBHeight != m.DBHeight {
+			return fmt.Errorf("Ack %d is of another VM or minute than its batch", i)
+		}
+		if a.LeaderChainID == nil || !a.LeaderChainID.IsSameAs(m.LeaderChainID) {
+			return fmt.Errorf("Ack %d is of another leader than its batch", i)
+		}
+		if i > 0 && a.Height != m.Acks[i-1].Height+1 {
+			return fmt.Errorf("Ack %d doesn't follow the one before it", i)
+		}
+	}
+	return nil
+}
+
+// ComputeMerkleRoot returns the root of the hashes of the acks of the batch
+func (m *BatchAck) ComputeMerkleRoot() interfaces.IHash {
+	hashes := make([]interfaces.IHash, 0, len(m.Acks))
+	for _, a := range m.Acks {
+		hashes = append(hashes, a.GetMsgHash())
+	}
+	return primitives.ComputeMerkleRoot(hashes)
+}
+
+// Unpack returns the acks of a batch that has been validated, taken as signed by the leader;
+// none if it hasn't.  They are not sent out on their own, as their signature is the batch's,
+// and each keeps the batch so it can be served to a peer missing it along with its proof.
+func (m *BatchAck) Unpack() []*Ack {
+	if !m.authvalid {
+		return nil
+	}
+	for _, a := range m.Acks {
+		a.authvalid = true
+		a.NoResend = true
+		a.batch = m
+	}
+	return m.Acks
+}
+
+// AckOf returns the ack of the batch for the message with the given hash, nil if the
+// batch has none, or hasn't been validated
+func (m *BatchAck) AckOf(msgHash interfaces.IHash) *Ack {
+	for _, a := range m.Unpack() {
+		if a.MessageHash.IsSameAs(msgHash) {
+			return a
+		}
+	}
+	return nil
+}
+
+func (a *BatchAck) IsSameAs(b *BatchAck) bool {
+	if b == nil {
+		return false
+	}
+	if a.Timestamp.GetTimeMilli() != b.Timestamp.GetTimeMilli() {
+		return false
+	}
+	if a.VMIndex != b.VMIndex || a.Minute != b.Minute || a.DBHeight != b.DBHeight {
+		return false
+	}
+	if !a.LeaderChainID.IsSameAs(b.LeaderChainID) || !a.MerkleRoot.IsSameAs(b.MerkleRoot) {
+		return false
+	}
+	if len(a.Acks) != len(b.Acks) {
+		return false
+	}
+	for i := range a.Acks {
+		if !a.Acks[i].GetMsgHash().IsSameAs(b.Acks[i].GetMsgHash()) {
+			return false
+		}
+	}
+	if a.Signature == nil && b.Signature != nil {
+		return false
+	}
+	if a.Signature != nil {
+		if a.Signature.IsSameAs(b.Signature) == false {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (m *BatchAck) GetRepeatHash() interfaces.IHash {
+	return m.GetMsgHash()
+}
+
+func (m *BatchAck) GetHash() interfaces.IHash {
+	return m.GetMsgHash()
+}
+
+// The signed part of the batch commits to the acks through the Merkle root
+func (m *BatchAck) GetMsgHash() interfaces.IHash {
+	if m.MsgHash == nil {
+		data, err := m.MarshalForSignature()
+		if err != nil {
+			return nil
+		}
+		m.MsgHash = primitives.Sha(data)
+	}
+	return m.MsgHash
+}
+
+func (m *BatchAck) Type() byte {
+	return constants.BATCH_ACK_MSG
+}
+
+func (m *BatchAck) GetTimestamp() interfaces.Timestamp {
+	return m.Timestamp
+}
+
+func (m *BatchAck) VerifySignature() (bool, error) {
+	return VerifyMessage(m)
+}
+
+// Validate the message, given the state.  Three possible results:
+//
+//	< 0 -- Message is invalid.  Discard
+//	0   -- Cannot tell if message is Valid
+//	1   -- Message is valid
+func (m *BatchAck) Validate(state interfaces.IState) int {
+	// Batches are only taken from the height they are activated at
+	if !constants.IsActive(constants.ACTIVATION_BATCH_ACKS, state.GetNetworkID(), m.DBHeight) {
+		return -1
+	}
+
+	// If too old, it isn't valid.
+	if m.DBHeight <= state.GetHighestSavedBlk() {
+		return -1
+	}
+
+	if err := m.checkAcks(); err != nil {
+		return -1
+	}
+	if m.MerkleRoot == nil || !m.ComputeMerkleRoot().IsSameAs(m.MerkleRoot) {
+		return -1
+	}
+
+	if !m.authvalid {
+		if m.Signature == nil {
+			return -1
+		}
+		bytes, err := m.MarshalForSignature()
+		if err != nil {
+			return -1
+		}
+		signed, err := state.VerifyAuthoritySignature(bytes, m.Signature.GetSignature(), m.DBHeight)
+		if err != nil || signed <= 0 {
+			state.RecordAuthoritySignature(m.LeaderChainID, m.GetMsgHash(), false)
+			return -1
+		}
+		state.RecordAuthoritySignature(m.LeaderChainID, m.GetMsgHash(), true)
+	}
+
+	m.authvalid = true
+	return 1
+}
+
+func (m *BatchAck) ComputeVMIndex(state interfaces.IState) {
+}
+
+// Execute the leader functions of the given message
+// Leader, follower, do the same thing.
+func (m *BatchAck) LeaderExecute(state interfaces.IState) {
+	m.FollowerExecute(state)
+}
+
+// The batch is unpacked, and its acks executed one by one
+func (m *BatchAck) FollowerExecute(state interfaces.IState) {
+	state.FollowerExecuteAck(m)
+}
+
+// Batch acks do not go into the process list.
+func (e *BatchAck) Process(dbheight uint32, state interfaces.IState) bool {
+	panic("BatchAck object should never have its Process() method called")
+}
+
+func (e *BatchAck) JSONByte() ([]byte, error) {
+	return primitives.EncodeJSON(e)
+}
+
+func (e *BatchAck) JSONString() (string, error) {
+	return primitives.EncodeJSONString(e)
+}
+
+func (m *BatchAck) Sign(key interfaces.Signer) error {
+	signature, err := SignSignable(m, key)
+	if err != nil {
+		return err
+	}
+	m.Signature = signature
+	return nil
+}
+
+func (m *BatchAck) GetSignature() interfaces.IFullSignature {
+	return m.Signature
+}
+
+func (m *BatchAck) UnmarshalBinaryData(data []byte) (newData []byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("Error unmarshalling BatchAck Message: %v", r)
+		}
+	}()
+	newData = data
+	if newData[0] != m.Type() {
+		return nil, fmt.Errorf("Invalid Message type")
+	}
+	newData = newData[1:]
+
+	m.VMIndex, newData = int(newData[0]), newData[1:]
+
+	m.Timestamp = new(primitives.Timestamp)
+	newData, err = m.Timestamp.UnmarshalBinaryData(newData)
+	if err != nil {
+		return nil, err
+	}
+
+	m.LeaderChainID = new(primitives.Hash)
+	newData, err = m.LeaderChainID.UnmarshalBinaryData(newData)
+	if err != nil {
+		return nil, err
+	}
+
+	m.DBHeight, newData = binary.BigEndian.Uint32(newData[0:4]), newData[4:]
+	m.Minute, newData = newData[0], newData[1:]
+
+	m.MerkleRoot = new(primitives.Hash)
+	newData, err = m.MerkleRoot.UnmarshalBinaryData(newData)
+	if err != nil {
+		return nil, err
+	}
+
+	var count uint64
+	count, newData = primitives.DecodeVarInt(newData)
+	if count > MaxBatchAcks {
+		return nil, fmt.Errorf("Batch of %d acks is too large", count)
+	}
+	m.Acks = make([]*Ack, int(count))
+	for i := range m.Acks {
+		var l uint64
+		l, newData = primitives.DecodeVarInt(newData)
+		m.Acks[i] = new(Ack)
+		if err = m.Acks[i].UnmarshalBinary(newData[:int(l)]); err != nil {
+			return nil, err
+		}
+		newData = newData[int(l):]
+	}
+
+	if len(newData) > 0 {
+		m.Signature = new(primitives.Signature)
+		newData, err = m.Signature.UnmarshalBinaryData(newData)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return
+}
+
+func (m *BatchAck) UnmarshalBinary(data []byte) error {
+	_, err := m.UnmarshalBinaryData(data)
+	return err
+}
+
+// MarshalForSignature marshals the header of the batch, which holds the Merkle root of its
+// acks
+func (m *BatchAck) MarshalForSignature() ([]byte, error) {
+	var buf primitives.Buffer
+
+	binary.Write(&buf, binary.BigEndian, m.Type())
+	binary.Write(&buf, binary.BigEndian, byte(m.VMIndex))
+
+	t := m.GetTimestamp()
+	data, err := t.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	buf.Write(data)
+
+	data, err = m.LeaderChainID.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	buf.Write(data)
+
+	binary.Write(&buf, binary.BigEndian, m.DBHeight)
+	binary.Write(&buf, binary.BigEndian, m.Minute)
+
+	data, err = m.MerkleRoot.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	buf.Write(data)
+
+	return buf.DeepCopyBytes(), nil
+}
+
+func (m *BatchAck) MarshalBinary() (data []byte, err error) {
+	resp, err := m.MarshalForSignature()
+	if err != nil {
+		return nil, err
+	}
+	buf := primitives.NewBuffer(resp)
+
+	primitives.EncodeVarInt(buf, uint64(len(m.Acks)))
+	for _, a := range m.Acks {
+		data, err := a.MarshalForSignature()
+		if err != nil {
+			return nil, err
+		}
+		primitives.EncodeVarInt(buf, uint64(len(data)))
+		buf.Write(data)
+	}
+
+	sig := m.GetSignature()
+	if sig != nil {
+		sigBytes, err := sig.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(sigBytes)
+	}
+	return buf.DeepCopyBytes(), nil
+}
+
+func (m *BatchAck) String() string {
+	var first, last uint32
+	if len(m.Acks) > 0 {
+		first, last = m.Acks[0].Height, m.Acks[len(m.Acks)-1].Height
+	}
+	return fmt.Sprintf("%6s-VM%3d: PL:%5d-%-5d DBHt:%5d -- Leader[:3]=%x root[:3]=%x",
+		"BACK",
+		m.VMIndex,
+		first,
+		last,
+		m.DBHeight,
+		m.LeaderChainID.Bytes()[:3],
+		m.MerkleRoot.Bytes()[:3])
+
+}
+
+func (m *BatchAck) LogFields() log.Fields {
+	return log.Fields{"category": "message", "messagetype": "batchack", "dbheight": m.DBHeight, "vm": m.VMIndex,
+		"minute": m.Minute, "acks": len(m.Acks), "server": m.LeaderChainID.String(),
+		"hash": m.GetHash().String()}
+}
@@ -0,0 +1,128 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package messages_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/common/constants"
+	. "github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestUnmarshalNilBatchAck(t *testing.T) {
+	defer func() {
+		if r := recover(); r != nil {
+			t.Errorf("Panic caught during the test - %v", r)
+		}
+	}()
+
+	a := new(BatchAck)
+	err := a.UnmarshalBinary(nil)
+	if err == nil {
+		t.Errorf("Error is nil when it shouldn't be")
+	}
+
+	err = a.UnmarshalBinary([]byte{})
+	if err == nil {
+		t.Errorf("Error is nil when it shouldn't be")
+	}
+}
+
+// newAcks returns n signed acks that follow each other in a VM
+func newAcks(n int) []*Ack {
+	var acks []*Ack
+	for i := 0; i < n; i++ {
+		ack := newSignedAck()
+		ack.Height += uint32(i)
+		ack.MessageHash = primitives.Sha([]byte{byte(i)})
+		acks = append(acks, ack)
+	}
+	return acks
+}
+
+func TestMarshalUnmarshalBatchAck(t *testing.T) {
+	msg, err := NewBatchAck(primitives.NewTimestampNow(), newAcks(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := primitives.NewPrivateKeyFromHex("07c0d52cb74f4ca3106d80c4a70488426886bccc6ebc10c6bafb37bf8a65f4c38cee85c62a9e48039d4ac294da97943c2001be1539809ea5f54721f0c5477a0a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := msg.Sign(key); err != nil {
+		t.Fatal(err)
+	}
+
+	hex, err := msg.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg2, err := UnmarshalMessage(hex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg2.Type() != constants.BATCH_ACK_MSG {
+		t.Error("Invalid message type unmarshalled")
+	}
+	batch := msg2.(*BatchAck)
+	if !msg.IsSameAs(batch) {
+		t.Errorf("Batch acks don't match: %v, %v", msg, batch)
+	}
+	if ok, err := batch.VerifySignature(); !ok {
+		t.Errorf("Signature doesn't check out: %v", err)
+	}
+	for _, a := range batch.Acks {
+		if a.Signature != nil {
+			t.Error("An ack in the batch carries its own signature")
+		}
+	}
+
+	// Nothing to unpack until the batch is validated
+	if acks := batch.Unpack(); acks != nil {
+		t.Errorf("Unpacked %d acks of a batch not yet validated", len(acks))
+	}
+}
+
+func TestNewBatchAck(t *testing.T) {
+	ts := primitives.NewTimestampNow()
+	if _, err := NewBatchAck(ts, nil); err == nil {
+		t.Error("Batched no acks")
+	}
+
+	acks := newAcks(3)
+	acks[2].Height++
+	if _, err := NewBatchAck(ts, acks); err == nil {
+		t.Error("Batched acks with a gap")
+	}
+
+	acks = newAcks(2)
+	acks[1].VMIndex = 1
+	if _, err := NewBatchAck(ts, acks); err == nil {
+		t.Error("Batched acks of two VMs")
+	}
+}
+
+func TestValidateBatchAck(t *testing.T) {
+	s := testHelper.CreateEmptyTestState()
+
+	msg, err := NewBatchAck(primitives.NewTimestampNow(), newAcks(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// An ack swapped for another no longer matches the signed root
+	msg.Acks[1].SerialHash = primitives.Sha([]byte("another"))
+	msg.Acks[1].MsgHash = nil
+	if msg.Validate(s) >= 0 {
+		t.Error("Accepted a batch whose acks don't match its root")
+	}
+
+	// Nor is an unsigned batch taken
+	msg.MerkleRoot = msg.ComputeMerkleRoot()
+	if msg.Validate(s) >= 0 {
+		t.Error("Accepted an unsigned batch")
+	}
+}
@@ -66,8 +66,14 @@ func (m *ChangeServerKeyMsg) Validate(state interfaces.IState) int {
 		return -1
 	}
 
-	// Should only be 20 bytes in the hash if btc key add
-	if m.AdminBlockChange == constants.TYPE_ADD_BTC_ANCHOR_KEY {
+	// Old nodes can't read ETH keys in the admin block, so they only go there once in effect
+	if m.AdminBlockChange == constants.TYPE_ADD_ETH_ANCHOR_KEY &&
+		!constants.IsActive(constants.ACTIVATION_ETH_ANCHOR, state.GetNetworkID(), state.GetLLeaderHeight()) {
+		return -1
+	}
+
+	// Should only be 20 bytes in the hash if btc or eth key add
+	if m.AdminBlockChange == constants.TYPE_ADD_BTC_ANCHOR_KEY || m.AdminBlockChange == constants.TYPE_ADD_ETH_ANCHOR_KEY {
 		for _, b := range m.Key.Bytes()[21:] {
 			if b != 0 {
 				fmt.Println("ChangeServerKey Error. Newkey is invalid length")
@@ -256,6 +262,8 @@ func (m *ChangeServerKeyMsg) String() string {
 		mtype = "Signing Key"
 	} else if m.AdminBlockChange == constants.TYPE_ADD_BTC_ANCHOR_KEY {
 		mtype = "BTC Key"
+	} else if m.AdminBlockChange == constants.TYPE_ADD_ETH_ANCHOR_KEY {
+		mtype = "ETH Key"
 	} else {
 		mtype = "other"
 	}
@@ -99,6 +99,13 @@ func (m *CommitChainMsg) Type() byte {
 	return constants.COMMIT_CHAIN_MSG
 }
 
+func (m *CommitChainMsg) PreVerifySignature() bool {
+	if !m.validsig && m.CommitChain.IsValid() {
+		m.validsig = true
+	}
+	return m.validsig
+}
+
 // Validate the message, given the state.  Three possible results:
 //  < 0 -- Message is invalid.  Discard
 //  0   -- Cannot tell if message is Valid
@@ -208,6 +208,13 @@ func (m *CommitEntryMsg) LogFields() log.Fields {
 		"hash":        m.GetHash().String()}
 }
 
+func (m *CommitEntryMsg) PreVerifySignature() bool {
+	if !m.validsig && m.CommitEntry.IsValid() {
+		m.validsig = true
+	}
+	return m.validsig
+}
+
 // Validate the message, given the state.  Three possible results:
 //  < 0 -- Message is invalid.  Discard
 //  0   -- Cannot tell if message is Valid
@@ -161,3 +161,16 @@ func newSignedCommitEntry() *CommitEntryMsg {
 
 	return addserv
 }
+
+func TestCommitEntryPreVerifySignature(t *testing.T) {
+	msg := newCommitEntry()
+	if !msg.PreVerifySignature() {
+		t.Error("Good signature failed to pre-verify")
+	}
+
+	bad := newCommitEntry()
+	bad.CommitEntry.Credits = 2
+	if bad.PreVerifySignature() {
+		t.Error("Bad signature pre-verified")
+	}
+}
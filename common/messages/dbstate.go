@@ -176,7 +176,7 @@ func (m *DBStateMsg) Validate(state interfaces.IState) int {
 	}
 
 	if m.DirectoryBlock.GetHeader().GetNetworkID() == constants.MAIN_NETWORK_ID {
-		key := constants.CheckPoints[dbheight]
+		key, _ := constants.GetCheckPoint(dbheight)
 		if key != "" {
 			if key != m.DirectoryBlock.DatabasePrimaryIndex().String() {
 				state.AddStatus(fmt.Sprintf("DBStateMsg.Validate() Fail  ht: %d checkpoint failure. Had %s Expected %s",
@@ -193,6 +193,11 @@ func (m *DBStateMsg) Validate(state interfaces.IState) int {
 func (m *DBStateMsg) ValidateSignatures(state interfaces.IState) int {
 	// Validate Signatures
 
+	// A checkpointed block needs no signatures
+	if key, ok := constants.GetCheckPoint(m.DirectoryBlock.GetDatabaseHeight()); ok && key == m.DirectoryBlock.DatabasePrimaryIndex().String() {
+		goto ValidSignatures
+	}
+
 	// If this is the next block that we need, we can validate it by signatures. If it is a past block
 	// we can validate by prevKeyMr of the block that follows this one
 	if m.DirectoryBlock.GetDatabaseHeight() == state.GetHighestSavedBlk()+1 {
@@ -169,11 +169,14 @@ func (m *DirectoryBlockSignature) Validate(state interfaces.IState) int {
 		// if there is an error during signature verification
 		// or if the signature is invalid
 		// the message is considered invalid
+		state.RecordAuthoritySignature(m.ServerIdentityChainID, m.GetMsgHash(), false)
 		return -1
 	}
 
 	marshalledMsg, _ := m.MarshalForSignature()
 	authorityLevel, err := state.VerifyAuthoritySignature(marshalledMsg, m.Signature.GetSignature(), m.DBHeight)
+	// An audit server's signature is still a good signature, just not one we can use here
+	state.RecordAuthoritySignature(m.ServerIdentityChainID, m.GetMsgHash(), err == nil && authorityLevel >= 0)
 	if err != nil || authorityLevel < 1 {
 		//This authority is not a Fed Server (it's either an Audit or not an Authority at all)
 		vlog("Fail to Verify Sig (not from a Fed Server) %s -- RAW: %x", m.String(), raw)
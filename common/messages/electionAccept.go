@@ -0,0 +1,242 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package messages
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ElectionAccept is sent by a leader accepting the volunteer of a round of the election
+// to replace a faulted leader.  Its signature over the core is its vote, and once a
+// majority of the leaders have voted the volunteer takes the faulted leader's place.
+type ElectionAccept struct {
+	MessageBase
+	ElectionCore
+	Round uint32 // The round of the election; not signed
+
+	Signature interfaces.IFullSignature
+
+	//Not marshalled
+	sigvalid bool
+}
+
+var _ interfaces.IMsg = (*ElectionAccept)(nil)
+var _ Signable = (*ElectionAccept)(nil)
+
+func (a *ElectionAccept) IsSameAs(b *ElectionAccept) bool {
+	if b == nil {
+		return false
+	}
+	if a.Round != b.Round || !a.IsSameCore(&b.ElectionCore) {
+		return false
+	}
+
+	if a.Signature == nil && b.Signature != nil {
+		return false
+	}
+	if a.Signature != nil {
+		if a.Signature.IsSameAs(b.Signature) == false {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Acceptances do not go into the process list.
+func (m *ElectionAccept) Process(uint32, interfaces.IState) bool {
+	panic("ElectionAccept object should never have its Process() method called")
+}
+
+func (m *ElectionAccept) GetRepeatHash() interfaces.IHash {
+	return m.GetMsgHash()
+}
+
+func (m *ElectionAccept) GetHash() interfaces.IHash {
+	return m.GetMsgHash()
+}
+
+func (m *ElectionAccept) GetMsgHash() interfaces.IHash {
+	if m.MsgHash == nil {
+		data, err := m.MarshalBinary()
+		if err != nil {
+			return nil
+		}
+		m.MsgHash = primitives.Sha(data)
+	}
+	return m.MsgHash
+}
+
+func (m *ElectionAccept) GetTimestamp() interfaces.Timestamp {
+	return m.Timestamp
+}
+
+func (m *ElectionAccept) Type() byte {
+	return constants.ELECTION_ACCEPT_MSG
+}
+
+func (m *ElectionAccept) UnmarshalBinaryData(data []byte) (newData []byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("Error unmarshalling ElectionAccept: %v", r)
+		}
+	}()
+	newData = data
+	if newData[0] != m.Type() {
+		return nil, fmt.Errorf("Invalid Message type")
+	}
+
+	newData, err = m.unmarshalCore(newData[1:])
+	if err != nil {
+		return nil, err
+	}
+	m.Round, newData = binary.BigEndian.Uint32(newData[0:4]), newData[4:]
+
+	if len(newData) > 0 {
+		sig := new(primitives.Signature)
+		newData, err = sig.UnmarshalBinaryData(newData)
+		if err != nil {
+			return nil, err
+		}
+		m.Signature = sig
+	}
+
+	return newData, nil
+}
+
+func (m *ElectionAccept) UnmarshalBinary(data []byte) error {
+	_, err := m.UnmarshalBinaryData(data)
+	return err
+}
+
+// MarshalForSignature returns the core alone, so the signature is a vote a
+// FullServerFault carries
+func (m *ElectionAccept) MarshalForSignature() (data []byte, err error) {
+	return m.MarshalCore()
+}
+
+func (m *ElectionAccept) MarshalBinary() (data []byte, err error) {
+	core, err := m.MarshalCore()
+	if err != nil {
+		return nil, err
+	}
+
+	var buf primitives.Buffer
+	buf.Write([]byte{m.Type()})
+	buf.Write(core)
+	binary.Write(&buf, binary.BigEndian, m.Round)
+
+	if sig := m.GetSignature(); sig != nil {
+		sigBytes, err := sig.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(sigBytes)
+	}
+	return buf.DeepCopyBytes(), nil
+}
+
+func (m *ElectionAccept) String() string {
+	return fmt.Sprintf("ElectionAccept vm%02d[%d] (%x) AuditID %x DBHt %d SysHt %d round %d",
+		m.ElectionCore.VMIndex, m.Height, m.ServerID.Bytes()[3:6], m.AuditServerID.Bytes()[3:6], m.DBHeight, m.SystemHeight, m.Round)
+}
+
+func (m *ElectionAccept) LogFields() log.Fields {
+	return log.Fields{"category": "message", "messagetype": "electionaccept",
+		"vm":        m.ElectionCore.VMIndex,
+		"dbheight":  m.DBHeight,
+		"leaderid":  m.ServerID.String(),
+		"auditid":   m.AuditServerID.String(),
+		"sysheight": m.SystemHeight,
+		"round":     m.Round}
+}
+
+func (m *ElectionAccept) GetDBHeight() uint32 {
+	return m.DBHeight
+}
+
+// Validate the message, given the state.  Three possible results:
+//
+//	< 0 -- Message is invalid.  Discard
+//	0   -- Cannot tell if message is Valid
+//	1   -- Message is valid
+func (m *ElectionAccept) Validate(state interfaces.IState) int {
+	if m.DBHeight <= state.GetHighestSavedBlk() {
+		return -1
+	}
+	if m.DBHeight > state.GetLLeaderHeight() {
+		return 0
+	}
+	if m.ServerID == nil || m.AuditServerID == nil || m.GetSignature() == nil {
+		return -1
+	}
+
+	// Only a leader can accept
+	if !m.sigvalid {
+		core, err := m.MarshalCore()
+		if err != nil {
+			return -1
+		}
+		signed, err := state.FastVerifyAuthoritySignature(core, m.Signature, m.DBHeight)
+		if err != nil || signed != 1 {
+			return -1
+		}
+		m.sigvalid = true
+	}
+
+	return 1
+}
+
+func (m *ElectionAccept) ComputeVMIndex(state interfaces.IState) {
+}
+
+// Execute the leader functions of the given message
+func (m *ElectionAccept) LeaderExecute(state interfaces.IState) {
+	m.FollowerExecute(state)
+}
+
+func (m *ElectionAccept) FollowerExecute(state interfaces.IState) {
+	state.FollowerExecuteElectionAccept(m)
+}
+
+func (e *ElectionAccept) JSONByte() ([]byte, error) {
+	return primitives.EncodeJSON(e)
+}
+
+func (e *ElectionAccept) JSONString() (string, error) {
+	return primitives.EncodeJSONString(e)
+}
+
+func (m *ElectionAccept) Sign(key interfaces.Signer) error {
+	signature, err := SignSignable(m, key)
+	if err != nil {
+		return err
+	}
+	m.Signature = signature
+	return nil
+}
+
+func (m *ElectionAccept) GetSignature() interfaces.IFullSignature {
+	return m.Signature
+}
+
+func (m *ElectionAccept) VerifySignature() (bool, error) {
+	return VerifyMessage(m)
+}
+
+// NewElectionAccept returns an unsigned acceptance of the volunteer of a round
+func NewElectionAccept(volunteer *ElectionVolunteer) *ElectionAccept {
+	msg := new(ElectionAccept)
+	msg.ElectionCore = volunteer.ElectionCore
+	msg.Round = volunteer.Round
+	return msg
+}
@@ -0,0 +1,66 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package messages_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/common/constants"
+	. "github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+func TestUnmarshalNilElectionAccept(t *testing.T) {
+	defer func() {
+		if r := recover(); r != nil {
+			t.Errorf("Panic caught during the test - %v", r)
+		}
+	}()
+
+	a := new(ElectionAccept)
+	err := a.UnmarshalBinary(nil)
+	if err == nil {
+		t.Errorf("Error is nil when it shouldn't be")
+	}
+
+	err = a.UnmarshalBinary([]byte{})
+	if err == nil {
+		t.Errorf("Error is nil when it shouldn't be")
+	}
+}
+
+func TestMarshalUnmarshalElectionAccept(t *testing.T) {
+	volunteer := NewElectionVolunteer(newTestElectionCore(), 1)
+	msg := NewElectionAccept(volunteer)
+	if !msg.IsSameCore(&volunteer.ElectionCore) || msg.Round != volunteer.Round {
+		t.Error("The acceptance isn't of the volunteer")
+	}
+
+	key, err := primitives.NewPrivateKeyFromHex("07c0d52cb74f4ca3106d80c4a70488426886bccc6ebc10c6bafb37bf8a65f4c38cee85c62a9e48039d4ac294da97943c2001be1539809ea5f54721f0c5477a0a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := msg.Sign(key); err != nil {
+		t.Fatal(err)
+	}
+
+	hex, err := msg.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg2, err := UnmarshalMessage(hex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg2.Type() != constants.ELECTION_ACCEPT_MSG {
+		t.Error("Invalid message type unmarshalled")
+	}
+	if !msg.IsSameAs(msg2.(*ElectionAccept)) {
+		t.Errorf("Acceptances don't match: %v, %v", msg, msg2)
+	}
+	if valid, err := msg2.(*ElectionAccept).VerifySignature(); err != nil || !valid {
+		t.Errorf("Signature is not valid - %v", err)
+	}
+}
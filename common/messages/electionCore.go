@@ -0,0 +1,105 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package messages
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// ElectionCore is what an audit server volunteering to replace a faulted leader, and the
+// leaders accepting it, sign.  It is laid out like the core of a ServerFault, so their
+// signatures carry over into the FullServerFault that records the outcome of the election.
+type ElectionCore struct {
+	ServerID      interfaces.IHash // The faulted leader
+	AuditServerID interfaces.IHash // The audit server volunteering to replace it
+	VMIndex       byte
+	DBHeight      uint32
+	Height        uint32 // The height of the VM the volunteer takes over at
+	SystemHeight  uint32 // Where the outcome goes in the System list
+	Timestamp     interfaces.Timestamp
+}
+
+func (c *ElectionCore) MarshalCore() (data []byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("Error marshalling Election Core: %v", r)
+		}
+	}()
+
+	var buf primitives.Buffer
+
+	if d, err := c.ServerID.MarshalBinary(); err != nil {
+		return nil, err
+	} else {
+		buf.Write(d)
+	}
+	if d, err := c.AuditServerID.MarshalBinary(); err != nil {
+		return nil, err
+	} else {
+		buf.Write(d)
+	}
+
+	buf.WriteByte(c.VMIndex)
+	binary.Write(&buf, binary.BigEndian, uint32(c.DBHeight))
+	binary.Write(&buf, binary.BigEndian, uint32(c.Height))
+	binary.Write(&buf, binary.BigEndian, uint32(c.SystemHeight))
+
+	if d, err := c.Timestamp.MarshalBinary(); err != nil {
+		return nil, err
+	} else {
+		buf.Write(d)
+	}
+
+	return buf.DeepCopyBytes(), nil
+}
+
+func (c *ElectionCore) unmarshalCore(data []byte) (newData []byte, err error) {
+	newData = data
+
+	c.ServerID = primitives.NewZeroHash()
+	newData, err = c.ServerID.UnmarshalBinaryData(newData)
+	if err != nil {
+		return nil, err
+	}
+	c.AuditServerID = primitives.NewZeroHash()
+	newData, err = c.AuditServerID.UnmarshalBinaryData(newData)
+	if err != nil {
+		return nil, err
+	}
+
+	c.VMIndex, newData = newData[0], newData[1:]
+	c.DBHeight, newData = binary.BigEndian.Uint32(newData[0:4]), newData[4:]
+	c.Height, newData = binary.BigEndian.Uint32(newData[0:4]), newData[4:]
+	c.SystemHeight, newData = binary.BigEndian.Uint32(newData[0:4]), newData[4:]
+
+	c.Timestamp = new(primitives.Timestamp)
+	return c.Timestamp.UnmarshalBinaryData(newData)
+}
+
+// GetCoreHash returns the hash of the core, the same for the volunteer and its acceptances
+func (c *ElectionCore) GetCoreHash() interfaces.IHash {
+	data, err := c.MarshalCore()
+	if err != nil {
+		return nil
+	}
+	return primitives.Sha(data)
+}
+
+// ServerFault returns the fault the core stands for, to build the FullServerFault from
+func (c *ElectionCore) ServerFault() *ServerFault {
+	return NewServerFault(c.ServerID, c.AuditServerID, int(c.VMIndex), c.DBHeight, c.Height, int(c.SystemHeight), c.Timestamp)
+}
+
+func (c *ElectionCore) IsSameCore(b *ElectionCore) bool {
+	if b == nil {
+		return false
+	}
+	h1, h2 := c.GetCoreHash(), b.GetCoreHash()
+	return h1 != nil && h1.IsSameAs(h2)
+}
@@ -0,0 +1,242 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package messages
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ElectionVolunteer is sent by the audit server whose turn it is in a round of the
+// election to replace a faulted leader.  Its signature over the core is its pledge to
+// take the leader's place.
+type ElectionVolunteer struct {
+	MessageBase
+	ElectionCore
+	Round uint32 // The round of the election; not signed
+
+	Signature interfaces.IFullSignature
+
+	//Not marshalled
+	sigvalid bool
+}
+
+var _ interfaces.IMsg = (*ElectionVolunteer)(nil)
+var _ Signable = (*ElectionVolunteer)(nil)
+
+func (a *ElectionVolunteer) IsSameAs(b *ElectionVolunteer) bool {
+	if b == nil {
+		return false
+	}
+	if a.Round != b.Round || !a.IsSameCore(&b.ElectionCore) {
+		return false
+	}
+
+	if a.Signature == nil && b.Signature != nil {
+		return false
+	}
+	if a.Signature != nil {
+		if a.Signature.IsSameAs(b.Signature) == false {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Volunteers do not go into the process list.
+func (m *ElectionVolunteer) Process(uint32, interfaces.IState) bool {
+	panic("ElectionVolunteer object should never have its Process() method called")
+}
+
+func (m *ElectionVolunteer) GetRepeatHash() interfaces.IHash {
+	return m.GetMsgHash()
+}
+
+func (m *ElectionVolunteer) GetHash() interfaces.IHash {
+	return m.GetMsgHash()
+}
+
+func (m *ElectionVolunteer) GetMsgHash() interfaces.IHash {
+	if m.MsgHash == nil {
+		data, err := m.MarshalBinary()
+		if err != nil {
+			return nil
+		}
+		m.MsgHash = primitives.Sha(data)
+	}
+	return m.MsgHash
+}
+
+func (m *ElectionVolunteer) GetTimestamp() interfaces.Timestamp {
+	return m.Timestamp
+}
+
+func (m *ElectionVolunteer) Type() byte {
+	return constants.ELECTION_VOLUNTEER_MSG
+}
+
+func (m *ElectionVolunteer) UnmarshalBinaryData(data []byte) (newData []byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("Error unmarshalling ElectionVolunteer: %v", r)
+		}
+	}()
+	newData = data
+	if newData[0] != m.Type() {
+		return nil, fmt.Errorf("Invalid Message type")
+	}
+
+	newData, err = m.unmarshalCore(newData[1:])
+	if err != nil {
+		return nil, err
+	}
+	m.Round, newData = binary.BigEndian.Uint32(newData[0:4]), newData[4:]
+
+	if len(newData) > 0 {
+		sig := new(primitives.Signature)
+		newData, err = sig.UnmarshalBinaryData(newData)
+		if err != nil {
+			return nil, err
+		}
+		m.Signature = sig
+	}
+
+	return newData, nil
+}
+
+func (m *ElectionVolunteer) UnmarshalBinary(data []byte) error {
+	_, err := m.UnmarshalBinaryData(data)
+	return err
+}
+
+// MarshalForSignature returns the core alone, so the signature is the pledge a
+// FullServerFault carries
+func (m *ElectionVolunteer) MarshalForSignature() (data []byte, err error) {
+	return m.MarshalCore()
+}
+
+func (m *ElectionVolunteer) MarshalBinary() (data []byte, err error) {
+	core, err := m.MarshalCore()
+	if err != nil {
+		return nil, err
+	}
+
+	var buf primitives.Buffer
+	buf.Write([]byte{m.Type()})
+	buf.Write(core)
+	binary.Write(&buf, binary.BigEndian, m.Round)
+
+	if sig := m.GetSignature(); sig != nil {
+		sigBytes, err := sig.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(sigBytes)
+	}
+	return buf.DeepCopyBytes(), nil
+}
+
+func (m *ElectionVolunteer) String() string {
+	return fmt.Sprintf("ElectionVolunteer vm%02d[%d] (%x) AuditID %x DBHt %d SysHt %d round %d",
+		m.ElectionCore.VMIndex, m.Height, m.ServerID.Bytes()[3:6], m.AuditServerID.Bytes()[3:6], m.DBHeight, m.SystemHeight, m.Round)
+}
+
+func (m *ElectionVolunteer) LogFields() log.Fields {
+	return log.Fields{"category": "message", "messagetype": "electionvolunteer",
+		"vm":        m.ElectionCore.VMIndex,
+		"dbheight":  m.DBHeight,
+		"leaderid":  m.ServerID.String(),
+		"auditid":   m.AuditServerID.String(),
+		"sysheight": m.SystemHeight,
+		"round":     m.Round}
+}
+
+func (m *ElectionVolunteer) GetDBHeight() uint32 {
+	return m.DBHeight
+}
+
+// Validate the message, given the state.  Three possible results:
+//
+//	< 0 -- Message is invalid.  Discard
+//	0   -- Cannot tell if message is Valid
+//	1   -- Message is valid
+func (m *ElectionVolunteer) Validate(state interfaces.IState) int {
+	if m.DBHeight <= state.GetHighestSavedBlk() {
+		return -1
+	}
+	if m.DBHeight > state.GetLLeaderHeight() {
+		return 0
+	}
+	if m.ServerID == nil || m.AuditServerID == nil || m.GetSignature() == nil {
+		return -1
+	}
+
+	// Only an audit server can volunteer
+	if !m.sigvalid {
+		core, err := m.MarshalCore()
+		if err != nil {
+			return -1
+		}
+		signed, err := state.FastVerifyAuthoritySignature(core, m.Signature, m.DBHeight)
+		if err != nil || signed != 0 {
+			return -1
+		}
+		m.sigvalid = true
+	}
+
+	return 1
+}
+
+func (m *ElectionVolunteer) ComputeVMIndex(state interfaces.IState) {
+}
+
+// Execute the leader functions of the given message
+func (m *ElectionVolunteer) LeaderExecute(state interfaces.IState) {
+	m.FollowerExecute(state)
+}
+
+func (m *ElectionVolunteer) FollowerExecute(state interfaces.IState) {
+	state.FollowerExecuteElectionVolunteer(m)
+}
+
+func (e *ElectionVolunteer) JSONByte() ([]byte, error) {
+	return primitives.EncodeJSON(e)
+}
+
+func (e *ElectionVolunteer) JSONString() (string, error) {
+	return primitives.EncodeJSONString(e)
+}
+
+func (m *ElectionVolunteer) Sign(key interfaces.Signer) error {
+	signature, err := SignSignable(m, key)
+	if err != nil {
+		return err
+	}
+	m.Signature = signature
+	return nil
+}
+
+func (m *ElectionVolunteer) GetSignature() interfaces.IFullSignature {
+	return m.Signature
+}
+
+func (m *ElectionVolunteer) VerifySignature() (bool, error) {
+	return VerifyMessage(m)
+}
+
+// NewElectionVolunteer returns an unsigned volunteer for the round of an election
+func NewElectionVolunteer(core ElectionCore, round int) *ElectionVolunteer {
+	msg := new(ElectionVolunteer)
+	msg.ElectionCore = core
+	msg.Round = uint32(round)
+	return msg
+}
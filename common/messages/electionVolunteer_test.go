@@ -0,0 +1,84 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package messages_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/common/constants"
+	. "github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+func newTestElectionCore() ElectionCore {
+	return ElectionCore{
+		ServerID:      primitives.NewHash([]byte("leader")),
+		AuditServerID: primitives.NewHash([]byte("audit")),
+		VMIndex:       2,
+		DBHeight:      123,
+		Height:        7,
+		SystemHeight:  1,
+		Timestamp:     primitives.NewTimestampNow(),
+	}
+}
+
+func TestUnmarshalNilElectionVolunteer(t *testing.T) {
+	defer func() {
+		if r := recover(); r != nil {
+			t.Errorf("Panic caught during the test - %v", r)
+		}
+	}()
+
+	a := new(ElectionVolunteer)
+	err := a.UnmarshalBinary(nil)
+	if err == nil {
+		t.Errorf("Error is nil when it shouldn't be")
+	}
+
+	err = a.UnmarshalBinary([]byte{})
+	if err == nil {
+		t.Errorf("Error is nil when it shouldn't be")
+	}
+}
+
+func TestMarshalUnmarshalElectionVolunteer(t *testing.T) {
+	msg := NewElectionVolunteer(newTestElectionCore(), 3)
+
+	key, err := primitives.NewPrivateKeyFromHex("07c0d52cb74f4ca3106d80c4a70488426886bccc6ebc10c6bafb37bf8a65f4c38cee85c62a9e48039d4ac294da97943c2001be1539809ea5f54721f0c5477a0a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := msg.Sign(key); err != nil {
+		t.Fatal(err)
+	}
+
+	hex, err := msg.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg2, err := UnmarshalMessage(hex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg2.Type() != constants.ELECTION_VOLUNTEER_MSG {
+		t.Error("Invalid message type unmarshalled")
+	}
+	v := msg2.(*ElectionVolunteer)
+	if !msg.IsSameAs(v) || v.Round != 3 {
+		t.Errorf("Volunteers don't match: %v, %v", msg, msg2)
+	}
+	if valid, err := v.VerifySignature(); err != nil || !valid {
+		t.Errorf("Signature is not valid - %v", err)
+	}
+
+	// The pledge is a signature over the core alone, the same as the fault's
+	core, err := msg.ServerFault().MarshalForSignature()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !v.Signature.Verify(core) {
+		t.Error("The pledge doesn't sign the core of the fault")
+	}
+}
@@ -149,8 +149,10 @@ func (m *EOM) Validate(state interfaces.IState) int {
 		if !eomSigned {
 			vlog("[1] Failed to verify, not signed. Msg: %s", m.String())
 		}
+		state.RecordAuthoritySignature(m.ChainID, m.GetMsgHash(), false)
 		return -1
 	}
+	state.RecordAuthoritySignature(m.ChainID, m.GetMsgHash(), true)
 	// if !eomSigned {
 	// 	state.Logf("warning", "[EOM Validate (2)] Failed to verify signature. Msg: %s", err.Error(), m.String())
 	// 	return -1
@@ -197,6 +199,12 @@ func (m *EOM) VerifySignature() (bool, error) {
 	return VerifyMessage(m)
 }
 
+// PreVerifySignature checks the signature, which VerifyMessage remembers for Validate
+func (m *EOM) PreVerifySignature() bool {
+	isVer, err := m.VerifySignature()
+	return err == nil && isVer
+}
+
 func (m *EOM) UnmarshalBinaryData(data []byte) (newData []byte, err error) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -25,6 +25,7 @@ type FactoidTransaction struct {
 	//Not marshalled
 	hash      interfaces.IHash
 	processed bool
+	sigvalid  bool
 }
 
 var _ interfaces.IMsg = (*FactoidTransaction)(nil)
@@ -87,6 +88,13 @@ func (m *FactoidTransaction) Type() byte {
 	return constants.FACTOID_TRANSACTION_MSG
 }
 
+func (m *FactoidTransaction) PreVerifySignature() bool {
+	if !m.sigvalid && m.Transaction.ValidateSignatures() == nil {
+		m.sigvalid = true
+	}
+	return m.sigvalid
+}
+
 // Validate the message, given the state.  Three possible results:
 //  < 0 -- Message is invalid.  Discard
 //  0   -- Cannot tell if message is Valid
@@ -98,10 +106,18 @@ func (m *FactoidTransaction) Validate(state interfaces.IState) int {
 		return -1 // No, object!
 	}
 
+	// Multisig inputs are only accepted once multisig is in effect
+	if factoid.HasMultisigInput(m.Transaction) && !factoid.MultisigActive(state.GetLLeaderHeight()) {
+		return -1
+	}
+
 	// Is the transaction properly signed?
-	err = m.Transaction.ValidateSignatures()
-	if err != nil {
-		return -1 // No, object!
+	if !m.sigvalid {
+		err = m.Transaction.ValidateSignatures()
+		if err != nil {
+			return -1 // No, object!
+		}
+		m.sigvalid = true
 	}
 
 	// Is the transaction valid at this point in time?
@@ -25,6 +25,30 @@ func UnmarshalMessage(data []byte) (interfaces.IMsg, error) {
 	return msg, err
 }
 
+// MaxRevealEntryMsgSize is the largest a reveal entry message can be: its type, its
+// timestamp, and an entry of MAX_ENTRY_SIZE past the entry header
+const MaxRevealEntryMsgSize = 1 + 6 + 35 + constants.MAX_ENTRY_SIZE
+
+// maxMessageSizes caps the size of messages of each type
+var maxMessageSizes = map[byte]int{
+	constants.REVEAL_ENTRY_MSG: MaxRevealEntryMsgSize,
+}
+
+// ErrOversized is returned for a message larger than its type allows
+var ErrOversized = errors.New("message is oversized")
+
+// CheckMessageSize rejects a marshaled message larger than its type allows, so a peer's
+// junk can be dropped before it is unmarshaled and held.
+func CheckMessageSize(data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+	if max, ok := maxMessageSizes[data[0]]; ok && len(data) > max {
+		return ErrOversized
+	}
+	return nil
+}
+
 func UnmarshalMessageData(data []byte) (newdata []byte, msg interfaces.IMsg, err error) {
 	if data == nil {
 		return nil, nil, fmt.Errorf("No data provided")
@@ -87,6 +111,20 @@ func UnmarshalMessageData(data []byte) (newdata []byte, msg interfaces.IMsg, err
 		msg = new(Bounce)
 	case constants.BOUNCEREPLY_MSG:
 		msg = new(BounceReply)
+	case constants.THROTTLE_MSG:
+		msg = new(Throttle)
+	case constants.BATCH_ACK_MSG:
+		msg = new(BatchAck)
+	case constants.STEP_DOWN_MSG:
+		msg = new(StepDown)
+	case constants.ELECTION_VOLUNTEER_MSG:
+		msg = new(ElectionVolunteer)
+	case constants.ELECTION_ACCEPT_MSG:
+		msg = new(ElectionAccept)
+	case constants.SYNC_REQUEST_MSG:
+		msg = new(SyncRequest)
+	case constants.SYNC_CHUNK_MSG:
+		msg = new(SyncChunk)
 	default:
 		fmt.Sprintf("Transaction Failed to Validate %x", data[0])
 		return data, nil, fmt.Errorf("Unknown message type %d %x", messageType, data[0])
@@ -152,6 +190,20 @@ func MessageName(Type byte) string {
 		return "Bounce Message"
 	case constants.BOUNCEREPLY_MSG:
 		return "Bounce Reply Message"
+	case constants.THROTTLE_MSG:
+		return "Throttle"
+	case constants.BATCH_ACK_MSG:
+		return "Batch Ack"
+	case constants.STEP_DOWN_MSG:
+		return "Step Down"
+	case constants.ELECTION_VOLUNTEER_MSG:
+		return "Election Volunteer"
+	case constants.ELECTION_ACCEPT_MSG:
+		return "Election Accept"
+	case constants.SYNC_REQUEST_MSG:
+		return "Sync Request"
+	case constants.SYNC_CHUNK_MSG:
+		return "Sync Chunk"
 	default:
 		return "Unknown:" + fmt.Sprintf(" %d", Type)
 	}
@@ -165,6 +217,13 @@ type Signable interface {
 	SetValid()     // Mark as validated so we don't have to repeat.
 }
 
+// PreVerifier is a message whose signature can be checked without the state, so off the
+// consensus thread.  PreVerifySignature returns whether the signature is good, and if it
+// is, remembers that so Validate doesn't check it again.
+type PreVerifier interface {
+	PreVerifySignature() bool
+}
+
 func SignSignable(s Signable, key interfaces.Signer) (interfaces.IFullSignature, error) {
 	toSign, err := s.MarshalForSignature()
 	if err != nil {
@@ -313,6 +313,14 @@ func (m *Heartbeat) GetSignature() interfaces.IFullSignature {
 	return m.Signature
 }
 
+func (m *Heartbeat) PreVerifySignature() bool {
+	if !m.sigvalid {
+		isVer, err := m.VerifySignature()
+		m.sigvalid = err == nil && isVer
+	}
+	return m.sigvalid
+}
+
 func (m *Heartbeat) VerifySignature() (bool, error) {
 	return VerifyMessage(m)
 }
@@ -111,7 +111,12 @@ func (m *MissingMsgResponse) UnmarshalBinaryData(data []byte) (newData []byte, e
 	b, newData := newData[0], newData[1:]
 
 	if b == 1 {
-		m.AckResponse = new(Ack)
+		// An ack that came in a batch is served as the batch, which carries its signature
+		if newData[0] == constants.BATCH_ACK_MSG {
+			m.AckResponse = new(BatchAck)
+		} else {
+			m.AckResponse = new(Ack)
+		}
 		newData, err = m.AckResponse.UnmarshalBinaryData(newData)
 
 		if err != nil {
@@ -181,6 +186,9 @@ func (m *MissingMsgResponse) MarshalBinary() ([]byte, error) {
 
 func (m *MissingMsgResponse) String() string {
 	ack, ok := m.AckResponse.(*Ack)
+	if batch, isBatch := m.AckResponse.(*BatchAck); isBatch {
+		return fmt.Sprint("MissingMsgResponse (batch ", batch.String(), ") <-- ", m.MsgResponse.String())
+	}
 	if !ok {
 		return fmt.Sprint("MissingMsgResponse (no Ack) <-- ", m.MsgResponse.String())
 	}
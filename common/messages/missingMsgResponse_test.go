@@ -63,3 +63,32 @@ func TestMissingMessageResponseMarshaling(t *testing.T) {
 	}
 
 }
+
+// An ack that came in a batch is served as its batch, which must come back as one
+func TestMissingMessageResponseBatchAck(t *testing.T) {
+	s := testHelper.CreateEmptyTestState()
+
+	batch, err := NewBatchAck(primitives.NewTimestampNow(), newAcks(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := new(Bounce)
+	b.Timestamp = primitives.NewTimestampNow()
+	m := NewMissingMsgResponse(s, b, batch)
+	d, err := m.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m2 := new(MissingMsgResponse)
+	if err := m2.UnmarshalBinary(d); err != nil {
+		t.Fatal(err)
+	}
+	batch2, ok := m2.AckResponse.(*BatchAck)
+	if !ok {
+		t.Fatalf("Ack response came back as %T", m2.AckResponse)
+	}
+	if !batch.IsSameAs(batch2) {
+		t.Error("Unmarshal gave back a different batch")
+	}
+}
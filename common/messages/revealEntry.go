@@ -5,7 +5,6 @@
 package messages
 
 import (
-	"crypto/sha256"
 	"encoding/binary"
 	"fmt"
 
@@ -83,9 +82,10 @@ func (m *RevealEntryMsg) Type() byte {
 // Checks to make sure these External IDs actually produce a ChainID that machtes the Chain ID in
 // the CommitChainMsg
 func CheckChainID(state interfaces.IState, ExternalIDs [][]byte, msg *RevealEntryMsg) bool {
-	sum := sha256.New()
+	crypto := primitives.GetCryptoProvider()
+	sum := crypto.NewSha256()
 	for _, v := range ExternalIDs {
-		x := sha256.Sum256(v)
+		x := crypto.Sha256(v)
 		sum.Write(x[:])
 	}
 	originalHash := sum.Sum(nil)
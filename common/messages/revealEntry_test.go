@@ -109,6 +109,25 @@ func TestValidRevealMsg(t *testing.T) {
 	}
 }
 
+func TestCheckRevealEntrySize(t *testing.T) {
+	// The ExtIDs of newRevealEntryWithContentSizeX take 19 bytes of the entry
+	largest, err := newRevealEntryWithContentSizeX(constants.MAX_ENTRY_SIZE - 19).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckMessageSize(largest); err != nil {
+		t.Errorf("Rejected the largest entry, %d bytes: %v", len(largest), err)
+	}
+
+	oversized, err := newRevealEntryWithContentSizeX(constants.MAX_ENTRY_SIZE - 18).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckMessageSize(oversized); err != ErrOversized {
+		t.Errorf("Accepted an oversized entry, %d bytes", len(oversized))
+	}
+}
+
 func testValid(ecs uint8, dataSize int, s *state.State) int {
 	com := NewCommitEntryMsg()
 	com.CommitEntry = entryCreditBlock.NewCommitEntry()
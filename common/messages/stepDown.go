@@ -0,0 +1,304 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package messages
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Why a leader steps down, as flags
+const (
+	StepDownLag     uint8 = 1 << iota // Its minutes run late
+	StepDownMissing                   // It is asking for too many missing messages
+	StepDownDisk                      // Its database writes are slow
+)
+
+// StepDown is sent by a federated server that finds itself unhealthy, to volunteer to be
+// replaced by an audit server.  Its VM is taken as faulted straight away, rather than once
+// it has fallen far enough behind to be faulted.
+type StepDown struct {
+	MessageBase
+	Timestamp       interfaces.Timestamp
+	DBHeight        uint32
+	Minute          byte
+	VM              byte // The VM of the server in the minute
+	IdentityChainID interfaces.IHash
+	Reasons         uint8
+
+	Signature interfaces.IFullSignature
+
+	//Not marshalled
+	sigvalid bool
+}
+
+var _ interfaces.IMsg = (*StepDown)(nil)
+var _ Signable = (*StepDown)(nil)
+
+func (a *StepDown) IsSameAs(b *StepDown) bool {
+	if b == nil {
+		return false
+	}
+	if a.Timestamp.GetTimeMilli() != b.Timestamp.GetTimeMilli() {
+		return false
+	}
+	if a.DBHeight != b.DBHeight || a.Minute != b.Minute || a.VM != b.VM || a.Reasons != b.Reasons {
+		return false
+	}
+
+	if a.IdentityChainID == nil && b.IdentityChainID != nil {
+		return false
+	}
+	if a.IdentityChainID != nil {
+		if a.IdentityChainID.IsSameAs(b.IdentityChainID) == false {
+			return false
+		}
+	}
+
+	if a.Signature == nil && b.Signature != nil {
+		return false
+	}
+	if a.Signature != nil {
+		if a.Signature.IsSameAs(b.Signature) == false {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Step downs do not go into the process list.
+func (m *StepDown) Process(uint32, interfaces.IState) bool {
+	panic("StepDown object should never have its Process() method called")
+}
+
+func (m *StepDown) GetRepeatHash() interfaces.IHash {
+	return m.GetMsgHash()
+}
+
+func (m *StepDown) GetHash() interfaces.IHash {
+	return m.GetMsgHash()
+}
+
+func (m *StepDown) GetMsgHash() interfaces.IHash {
+	if m.MsgHash == nil {
+		data, err := m.MarshalBinary()
+		if err != nil {
+			return nil
+		}
+		m.MsgHash = primitives.Sha(data)
+	}
+	return m.MsgHash
+}
+
+func (m *StepDown) GetTimestamp() interfaces.Timestamp {
+	return m.Timestamp
+}
+
+func (m *StepDown) Type() byte {
+	return constants.STEP_DOWN_MSG
+}
+
+func (m *StepDown) UnmarshalBinaryData(data []byte) (newData []byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("Error unmarshalling StepDown: %v", r)
+		}
+	}()
+	newData = data
+	if newData[0] != m.Type() {
+		return nil, fmt.Errorf("Invalid Message type")
+	}
+	newData = newData[1:]
+
+	m.Timestamp = new(primitives.Timestamp)
+	newData, err = m.Timestamp.UnmarshalBinaryData(newData)
+	if err != nil {
+		return nil, err
+	}
+
+	m.DBHeight, newData = binary.BigEndian.Uint32(newData[0:4]), newData[4:]
+	m.Minute, newData = newData[0], newData[1:]
+	m.VM, newData = newData[0], newData[1:]
+
+	hash := new(primitives.Hash)
+	newData, err = hash.UnmarshalBinaryData(newData)
+	if err != nil {
+		return nil, err
+	}
+	m.IdentityChainID = hash
+
+	m.Reasons, newData = newData[0], newData[1:]
+
+	if len(newData) > 0 {
+		sig := new(primitives.Signature)
+		newData, err = sig.UnmarshalBinaryData(newData)
+		if err != nil {
+			return nil, err
+		}
+		m.Signature = sig
+	}
+
+	return newData, nil
+}
+
+func (m *StepDown) UnmarshalBinary(data []byte) error {
+	_, err := m.UnmarshalBinaryData(data)
+	return err
+}
+
+func (m *StepDown) MarshalForSignature() (data []byte, err error) {
+	if m.IdentityChainID == nil {
+		return nil, fmt.Errorf("Message is incomplete")
+	}
+
+	var buf primitives.Buffer
+	buf.Write([]byte{m.Type()})
+	if d, err := m.Timestamp.MarshalBinary(); err != nil {
+		return nil, err
+	} else {
+		buf.Write(d)
+	}
+
+	binary.Write(&buf, binary.BigEndian, m.DBHeight)
+	buf.WriteByte(m.Minute)
+	buf.WriteByte(m.VM)
+
+	if d, err := m.IdentityChainID.MarshalBinary(); err != nil {
+		return nil, err
+	} else {
+		buf.Write(d)
+	}
+
+	buf.WriteByte(m.Reasons)
+
+	return buf.DeepCopyBytes(), nil
+}
+
+func (m *StepDown) MarshalBinary() (data []byte, err error) {
+	resp, err := m.MarshalForSignature()
+	if err != nil {
+		return nil, err
+	}
+	sig := m.GetSignature()
+	if sig != nil {
+		sigBytes, err := sig.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		return append(resp, sigBytes...), nil
+	}
+	return resp, nil
+}
+
+func (m *StepDown) String() string {
+	return fmt.Sprintf("StepDown ID[%x] dbht %d min %d vm %d reasons %d", m.IdentityChainID.Bytes()[3:6], m.DBHeight, m.Minute, m.VM, m.Reasons)
+}
+
+func (m *StepDown) LogFields() log.Fields {
+	return log.Fields{"category": "message", "messagetype": "stepdown",
+		"dbheight": m.DBHeight,
+		"minute":   m.Minute,
+		"vm":       m.VM,
+		"server":   m.IdentityChainID.String(),
+		"reasons":  m.Reasons}
+}
+
+// Validate the message, given the state.  Three possible results:
+//
+//	< 0 -- Message is invalid.  Discard
+//	0   -- Cannot tell if message is Valid
+//	1   -- Message is valid
+func (m *StepDown) Validate(state interfaces.IState) int {
+	now := state.GetTimestamp()
+	if now.GetTimeSeconds()-m.Timestamp.GetTimeSeconds() > 60 {
+		return -1
+	}
+
+	if m.GetSignature() == nil {
+		return -1
+	}
+
+	// Only the block being built can be faulted
+	if m.DBHeight < state.GetLeaderHeight() {
+		return -1
+	}
+	if m.DBHeight > state.GetLeaderHeight() {
+		return 0
+	}
+
+	if !m.sigvalid {
+		isVer, err := m.VerifySignature()
+		if err != nil || !isVer {
+			return -1
+		}
+		m.sigvalid = true
+	}
+
+	return 1
+}
+
+func (m *StepDown) ComputeVMIndex(state interfaces.IState) {
+}
+
+// Execute the leader functions of the given message
+func (m *StepDown) LeaderExecute(state interfaces.IState) {
+	m.FollowerExecute(state)
+}
+
+func (m *StepDown) FollowerExecute(state interfaces.IState) {
+	state.FollowerExecuteStepDown(m)
+}
+
+func (e *StepDown) JSONByte() ([]byte, error) {
+	return primitives.EncodeJSON(e)
+}
+
+func (e *StepDown) JSONString() (string, error) {
+	return primitives.EncodeJSONString(e)
+}
+
+func (m *StepDown) Sign(key interfaces.Signer) error {
+	signature, err := SignSignable(m, key)
+	if err != nil {
+		return err
+	}
+	m.Signature = signature
+	return nil
+}
+
+func (m *StepDown) GetSignature() interfaces.IFullSignature {
+	return m.Signature
+}
+
+func (m *StepDown) PreVerifySignature() bool {
+	if !m.sigvalid {
+		isVer, err := m.VerifySignature()
+		m.sigvalid = err == nil && isVer
+	}
+	return m.sigvalid
+}
+
+func (m *StepDown) VerifySignature() (bool, error) {
+	return VerifyMessage(m)
+}
+
+// NewStepDown returns an unsigned step down of the server in the VM of the block being built
+func NewStepDown(state interfaces.IState, vm int, reasons uint8) *StepDown {
+	msg := new(StepDown)
+	msg.Timestamp = state.GetTimestamp()
+	msg.DBHeight = state.GetLeaderHeight()
+	msg.Minute = byte(state.GetCurrentMinute())
+	msg.VM = byte(vm)
+	msg.IdentityChainID = state.GetIdentityChainID()
+	msg.Reasons = reasons
+	return msg
+}
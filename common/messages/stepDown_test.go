@@ -0,0 +1,68 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package messages_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/common/constants"
+	. "github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+func TestUnmarshalNilStepDown(t *testing.T) {
+	defer func() {
+		if r := recover(); r != nil {
+			t.Errorf("Panic caught during the test - %v", r)
+		}
+	}()
+
+	a := new(StepDown)
+	err := a.UnmarshalBinary(nil)
+	if err == nil {
+		t.Errorf("Error is nil when it shouldn't be")
+	}
+
+	err = a.UnmarshalBinary([]byte{})
+	if err == nil {
+		t.Errorf("Error is nil when it shouldn't be")
+	}
+}
+
+func TestMarshalUnmarshalStepDown(t *testing.T) {
+	msg := new(StepDown)
+	msg.Timestamp = primitives.NewTimestampNow()
+	msg.DBHeight = 123
+	msg.Minute = 4
+	msg.VM = 2
+	msg.IdentityChainID = primitives.NewZeroHash()
+	msg.Reasons = StepDownLag | StepDownMissing
+
+	key, err := primitives.NewPrivateKeyFromHex("07c0d52cb74f4ca3106d80c4a70488426886bccc6ebc10c6bafb37bf8a65f4c38cee85c62a9e48039d4ac294da97943c2001be1539809ea5f54721f0c5477a0a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := msg.Sign(key); err != nil {
+		t.Fatal(err)
+	}
+
+	hex, err := msg.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg2, err := UnmarshalMessage(hex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg2.Type() != constants.STEP_DOWN_MSG {
+		t.Error("Invalid message type unmarshalled")
+	}
+	if !msg.IsSameAs(msg2.(*StepDown)) {
+		t.Errorf("Step downs don't match: %v, %v", msg, msg2)
+	}
+	if valid, err := msg2.(*StepDown).VerifySignature(); err != nil || !valid {
+		t.Errorf("Signature is not valid - %v", err)
+	}
+}
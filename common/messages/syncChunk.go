@@ -0,0 +1,263 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package messages
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// SyncChunk is a run of directory block states in a stream asked for with a
+// SyncRequest.  It ends with a resume token, the KeyMR of its last block, which a node
+// whose stream is cut off asks again with, to pick up where the stream left off.
+type SyncChunk struct {
+	MessageBase
+	Timestamp interfaces.Timestamp
+
+	DBHeightStart uint32        // Height of the first block in the chunk
+	Final         bool          // The last chunk of the stream
+	DBStates      []*DBStateMsg // Blocks of the heights from DBHeightStart on
+	ResumeToken   interfaces.IHash
+
+	//Not signed!
+}
+
+var _ interfaces.IMsg = (*SyncChunk)(nil)
+
+func (a *SyncChunk) IsSameAs(b *SyncChunk) bool {
+	if b == nil {
+		return false
+	}
+	if a.Timestamp.GetTimeMilli() != b.Timestamp.GetTimeMilli() {
+		return false
+	}
+	if a.DBHeightStart != b.DBHeightStart || a.Final != b.Final || len(a.DBStates) != len(b.DBStates) {
+		return false
+	}
+	for i := range a.DBStates {
+		if !a.DBStates[i].IsSameAs(b.DBStates[i]) {
+			return false
+		}
+	}
+	if a.ResumeToken == nil || !a.ResumeToken.IsSameAs(b.ResumeToken) {
+		return false
+	}
+	return true
+}
+
+func (m *SyncChunk) GetRepeatHash() interfaces.IHash {
+	return m.GetMsgHash()
+}
+
+func (m *SyncChunk) GetHash() interfaces.IHash {
+	return m.GetMsgHash()
+}
+
+func (m *SyncChunk) GetMsgHash() interfaces.IHash {
+	if m.MsgHash == nil {
+		data, err := m.MarshalBinary()
+		if err != nil {
+			return nil
+		}
+		m.MsgHash = primitives.Sha(data)
+	}
+	return m.MsgHash
+}
+
+func (m *SyncChunk) Type() byte {
+	return constants.SYNC_CHUNK_MSG
+}
+
+func (m *SyncChunk) GetTimestamp() interfaces.Timestamp {
+	return m.Timestamp
+}
+
+// Validate the message, given the state.  Three possible results:
+//
+//	< 0 -- Message is invalid.  Discard
+//	0   -- Cannot tell if message is Valid
+//	1   -- Message is valid
+//
+// Only the shape of the chunk is checked here: its blocks have to be of the heights from
+// its start on, and its resume token the KeyMR of the last.  Whether they follow on from
+// the blocks the node has is for the state to check as the chunk is applied.
+func (m *SyncChunk) Validate(state interfaces.IState) int {
+	if len(m.DBStates) == 0 || m.ResumeToken == nil {
+		return -1
+	}
+	for i, dbs := range m.DBStates {
+		if dbs == nil || dbs.DirectoryBlock == nil {
+			return -1
+		}
+		if dbs.DirectoryBlock.GetDatabaseHeight() != m.DBHeightStart+uint32(i) {
+			return -1
+		}
+	}
+	last := m.DBStates[len(m.DBStates)-1]
+	if !last.DirectoryBlock.GetKeyMR().IsSameAs(m.ResumeToken) {
+		return -1
+	}
+	return 1
+}
+
+func (m *SyncChunk) ComputeVMIndex(state interfaces.IState) {
+}
+
+// Execute the leader functions of the given message
+func (m *SyncChunk) LeaderExecute(state interfaces.IState) {
+	m.FollowerExecute(state)
+}
+
+func (m *SyncChunk) FollowerExecute(state interfaces.IState) {
+	state.FollowerExecuteSyncChunk(m)
+}
+
+// Sync chunks do not go into the process list.
+func (m *SyncChunk) Process(uint32, interfaces.IState) bool {
+	panic("SyncChunk object should never have its Process() method called")
+}
+
+func (e *SyncChunk) JSONByte() ([]byte, error) {
+	return primitives.EncodeJSON(e)
+}
+
+func (e *SyncChunk) JSONString() (string, error) {
+	return primitives.EncodeJSONString(e)
+}
+
+func (m *SyncChunk) UnmarshalBinaryData(data []byte) (newData []byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("Error unmarshalling Sync Chunk Message: %v", r)
+		}
+	}()
+	newData = data
+	if newData[0] != m.Type() {
+		return nil, fmt.Errorf("Invalid Message type")
+	}
+	newData = newData[1:]
+
+	m.Peer2Peer = true // This is always a Peer2peer message
+
+	m.Timestamp = new(primitives.Timestamp)
+	newData, err = m.Timestamp.UnmarshalBinaryData(newData)
+	if err != nil {
+		return nil, err
+	}
+
+	m.DBHeightStart, newData = binary.BigEndian.Uint32(newData[0:4]), newData[4:]
+	m.Final, newData = newData[0] != 0, newData[1:]
+
+	var count uint32
+	count, newData = binary.BigEndian.Uint32(newData[0:4]), newData[4:]
+	if uint64(count) > uint64(len(newData)/4) {
+		return nil, fmt.Errorf("Sync chunk of %d blocks in %d bytes", count, len(newData))
+	}
+	m.DBStates = make([]*DBStateMsg, count)
+	for i := range m.DBStates {
+		size, rest := binary.BigEndian.Uint32(newData[0:4]), newData[4:]
+		if uint64(size) > uint64(len(rest)) {
+			return nil, fmt.Errorf("Sync chunk block of %d bytes in %d bytes", size, len(rest))
+		}
+		dbs := new(DBStateMsg)
+		if err := dbs.UnmarshalBinary(rest[:size]); err != nil {
+			return nil, err
+		}
+		m.DBStates[i] = dbs
+		newData = rest[size:]
+	}
+
+	hash := new(primitives.Hash)
+	newData, err = hash.UnmarshalBinaryData(newData)
+	if err != nil {
+		return nil, err
+	}
+	m.ResumeToken = hash
+
+	return newData, nil
+}
+
+func (m *SyncChunk) UnmarshalBinary(data []byte) error {
+	_, err := m.UnmarshalBinaryData(data)
+	return err
+}
+
+func (m *SyncChunk) MarshalForSignature() ([]byte, error) {
+	if m.ResumeToken == nil {
+		return nil, fmt.Errorf("Message is incomplete")
+	}
+
+	var buf primitives.Buffer
+
+	binary.Write(&buf, binary.BigEndian, m.Type())
+
+	t := m.GetTimestamp()
+	data, err := t.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	buf.Write(data)
+
+	binary.Write(&buf, binary.BigEndian, m.DBHeightStart)
+	if m.Final {
+		buf.WriteByte(1)
+	} else {
+		buf.WriteByte(0)
+	}
+
+	binary.Write(&buf, binary.BigEndian, uint32(len(m.DBStates)))
+	for _, dbs := range m.DBStates {
+		data, err := dbs.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		binary.Write(&buf, binary.BigEndian, uint32(len(data)))
+		buf.Write(data)
+	}
+
+	data, err = m.ResumeToken.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	buf.Write(data)
+
+	return buf.DeepCopyBytes(), nil
+}
+
+func (m *SyncChunk) MarshalBinary() ([]byte, error) {
+	return m.MarshalForSignature()
+}
+
+func (m *SyncChunk) String() string {
+	final := ""
+	if m.Final {
+		final = " final"
+	}
+	return fmt.Sprintf("SyncChunk: %d blocks from %d%s", len(m.DBStates), m.DBHeightStart, final)
+}
+
+func (m *SyncChunk) LogFields() log.Fields {
+	return log.Fields{"category": "message", "messagetype": "syncchunk",
+		"dbheightstart": m.DBHeightStart,
+		"blocks":        len(m.DBStates),
+		"final":         m.Final}
+}
+
+// NewSyncChunk returns an empty chunk of a stream, starting at the given height
+func NewSyncChunk(state interfaces.IState, start uint32) *SyncChunk {
+	msg := new(SyncChunk)
+
+	msg.Peer2Peer = true // Always a peer2peer response.
+	msg.Timestamp = state.GetTimestamp()
+	msg.DBHeightStart = start
+
+	return msg
+}
@@ -0,0 +1,130 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package messages_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/common/constants"
+	. "github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+func TestUnmarshalNilSyncMessages(t *testing.T) {
+	defer func() {
+		if r := recover(); r != nil {
+			t.Errorf("Panic caught during the test - %v", r)
+		}
+	}()
+
+	for _, m := range []interface {
+		UnmarshalBinary([]byte) error
+	}{new(SyncRequest), new(SyncChunk)} {
+		if err := m.UnmarshalBinary(nil); err == nil {
+			t.Errorf("Error is nil when it shouldn't be")
+		}
+		if err := m.UnmarshalBinary([]byte{}); err == nil {
+			t.Errorf("Error is nil when it shouldn't be")
+		}
+	}
+}
+
+func TestMarshalUnmarshalSyncRequest(t *testing.T) {
+	msg := new(SyncRequest)
+	msg.Timestamp = primitives.NewTimestampNow()
+	msg.DBHeightStart = 100
+	msg.DBHeightEnd = 2099
+	msg.ResumeToken = primitives.Sha([]byte("block 99"))
+
+	hex, err := msg.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg2, err := UnmarshalMessage(hex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg2.Type() != constants.SYNC_REQUEST_MSG {
+		t.Error("Invalid message type unmarshalled")
+	}
+	if !msg.IsSameAs(msg2.(*SyncRequest)) {
+		t.Errorf("Sync requests don't match: %v, %v", msg, msg2)
+	}
+	if !msg2.IsPeer2Peer() {
+		t.Error("Expected a sync request to go to one peer")
+	}
+
+	// A request without a resume token carries the zero hash
+	msg.ResumeToken = nil
+	hex, err = msg.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg2, err = UnmarshalMessage(hex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !msg2.(*SyncRequest).GetResumeToken().IsZero() {
+		t.Error("Expected no resume token")
+	}
+}
+
+func newSyncChunk() *SyncChunk {
+	dbs := newDBStateMsg()
+	msg := new(SyncChunk)
+	msg.Timestamp = primitives.NewTimestampNow()
+	msg.DBHeightStart = dbs.DirectoryBlock.GetDatabaseHeight()
+	msg.Final = true
+	msg.DBStates = []*DBStateMsg{dbs}
+	msg.ResumeToken = dbs.DirectoryBlock.GetKeyMR()
+	return msg
+}
+
+func TestMarshalUnmarshalSyncChunk(t *testing.T) {
+	msg := newSyncChunk()
+
+	hex, err := msg.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg2, err := UnmarshalMessage(hex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg2.Type() != constants.SYNC_CHUNK_MSG {
+		t.Error("Invalid message type unmarshalled")
+	}
+	if !msg.IsSameAs(msg2.(*SyncChunk)) {
+		t.Errorf("Sync chunks don't match: %v, %v", msg, msg2)
+	}
+	if msg2.Validate(nil) != 1 {
+		t.Error("Expected the chunk to be valid")
+	}
+
+	// A chunk that claims more blocks than it holds doesn't unmarshal
+	if _, err := UnmarshalMessage(hex[:len(hex)-40]); err == nil {
+		t.Error("Expected a cut off chunk not to unmarshal")
+	}
+}
+
+func TestSyncChunkValidate(t *testing.T) {
+	msg := newSyncChunk()
+	msg.ResumeToken = primitives.Sha([]byte("another block"))
+	if msg.Validate(nil) != -1 {
+		t.Error("Expected a chunk whose resume token isn't its last block to be invalid")
+	}
+
+	msg = newSyncChunk()
+	msg.DBHeightStart++
+	if msg.Validate(nil) != -1 {
+		t.Error("Expected a chunk whose blocks aren't of its heights to be invalid")
+	}
+
+	msg = newSyncChunk()
+	msg.DBStates = nil
+	if msg.Validate(nil) != -1 {
+		t.Error("Expected an empty chunk to be invalid")
+	}
+}
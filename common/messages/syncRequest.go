@@ -0,0 +1,212 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package messages
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// SyncRequest asks a peer to stream the directory block states of a range of heights,
+// in SyncChunks, to a node catching up.  A request taking up a stream that was cut off
+// carries its resume token, the KeyMR of the block before the start, so the stream is
+// only served by a peer whose blocks it follows on from.
+type SyncRequest struct {
+	MessageBase
+	Timestamp interfaces.Timestamp
+
+	DBHeightStart uint32           // First block asked for
+	DBHeightEnd   uint32           // Last block asked for
+	ResumeToken   interfaces.IHash // KeyMR of the block before the first, zero if not known
+
+	//Not signed!
+}
+
+var _ interfaces.IMsg = (*SyncRequest)(nil)
+
+func (a *SyncRequest) IsSameAs(b *SyncRequest) bool {
+	if b == nil {
+		return false
+	}
+	if a.Timestamp.GetTimeMilli() != b.Timestamp.GetTimeMilli() {
+		return false
+	}
+	if a.DBHeightStart != b.DBHeightStart || a.DBHeightEnd != b.DBHeightEnd {
+		return false
+	}
+	if !a.GetResumeToken().IsSameAs(b.GetResumeToken()) {
+		return false
+	}
+	return true
+}
+
+// GetResumeToken returns the resume token, the zero hash if there is none
+func (m *SyncRequest) GetResumeToken() interfaces.IHash {
+	if m.ResumeToken == nil {
+		return primitives.NewZeroHash()
+	}
+	return m.ResumeToken
+}
+
+func (m *SyncRequest) GetRepeatHash() interfaces.IHash {
+	return m.GetMsgHash()
+}
+
+func (m *SyncRequest) GetHash() interfaces.IHash {
+	return m.GetMsgHash()
+}
+
+func (m *SyncRequest) GetMsgHash() interfaces.IHash {
+	if m.MsgHash == nil {
+		data, err := m.MarshalBinary()
+		if err != nil {
+			return nil
+		}
+		m.MsgHash = primitives.Sha(data)
+	}
+	return m.MsgHash
+}
+
+func (m *SyncRequest) Type() byte {
+	return constants.SYNC_REQUEST_MSG
+}
+
+func (m *SyncRequest) GetTimestamp() interfaces.Timestamp {
+	return m.Timestamp
+}
+
+// Validate the message, given the state.  Three possible results:
+//
+//	< 0 -- Message is invalid.  Discard
+//	0   -- Cannot tell if message is Valid
+//	1   -- Message is valid
+func (m *SyncRequest) Validate(state interfaces.IState) int {
+	if m.DBHeightStart > m.DBHeightEnd {
+		return -1
+	}
+	return 1
+}
+
+func (m *SyncRequest) ComputeVMIndex(state interfaces.IState) {
+}
+
+// Execute the leader functions of the given message
+func (m *SyncRequest) LeaderExecute(state interfaces.IState) {
+	m.FollowerExecute(state)
+}
+
+func (m *SyncRequest) FollowerExecute(state interfaces.IState) {
+	state.FollowerExecuteSyncRequest(m)
+}
+
+// Sync requests do not go into the process list.
+func (m *SyncRequest) Process(uint32, interfaces.IState) bool {
+	panic("SyncRequest object should never have its Process() method called")
+}
+
+func (e *SyncRequest) JSONByte() ([]byte, error) {
+	return primitives.EncodeJSON(e)
+}
+
+func (e *SyncRequest) JSONString() (string, error) {
+	return primitives.EncodeJSONString(e)
+}
+
+func (m *SyncRequest) UnmarshalBinaryData(data []byte) (newData []byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("Error unmarshalling Sync Request Message: %v", r)
+		}
+	}()
+	newData = data
+	if newData[0] != m.Type() {
+		return nil, fmt.Errorf("Invalid Message type")
+	}
+	newData = newData[1:]
+
+	m.Peer2Peer = true // This is always a Peer2peer message
+
+	m.Timestamp = new(primitives.Timestamp)
+	newData, err = m.Timestamp.UnmarshalBinaryData(newData)
+	if err != nil {
+		return nil, err
+	}
+
+	m.DBHeightStart, newData = binary.BigEndian.Uint32(newData[0:4]), newData[4:]
+	m.DBHeightEnd, newData = binary.BigEndian.Uint32(newData[0:4]), newData[4:]
+
+	hash := new(primitives.Hash)
+	newData, err = hash.UnmarshalBinaryData(newData)
+	if err != nil {
+		return nil, err
+	}
+	m.ResumeToken = hash
+
+	return newData, nil
+}
+
+func (m *SyncRequest) UnmarshalBinary(data []byte) error {
+	_, err := m.UnmarshalBinaryData(data)
+	return err
+}
+
+func (m *SyncRequest) MarshalForSignature() ([]byte, error) {
+	var buf primitives.Buffer
+
+	binary.Write(&buf, binary.BigEndian, m.Type())
+
+	t := m.GetTimestamp()
+	data, err := t.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	buf.Write(data)
+
+	binary.Write(&buf, binary.BigEndian, m.DBHeightStart)
+	binary.Write(&buf, binary.BigEndian, m.DBHeightEnd)
+
+	data, err = m.GetResumeToken().MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	buf.Write(data)
+
+	return buf.DeepCopyBytes(), nil
+}
+
+func (m *SyncRequest) MarshalBinary() ([]byte, error) {
+	return m.MarshalForSignature()
+}
+
+func (m *SyncRequest) String() string {
+	return fmt.Sprintf("SyncRequest: %d-%d resume %x", m.DBHeightStart, m.DBHeightEnd, m.GetResumeToken().Bytes()[:4])
+}
+
+func (m *SyncRequest) LogFields() log.Fields {
+	return log.Fields{"category": "message", "messagetype": "syncrequest",
+		"dbheightstart": m.DBHeightStart,
+		"dbheightend":   m.DBHeightEnd,
+		"resumetoken":   m.GetResumeToken().String()}
+}
+
+// NewSyncRequest returns a request for a stream of the blocks from start to end.  The
+// resume token is the KeyMR of the block before start, or nil if it isn't known.
+func NewSyncRequest(state interfaces.IState, start uint32, end uint32, resumeToken interfaces.IHash) *SyncRequest {
+	msg := new(SyncRequest)
+
+	msg.Peer2Peer = true // Always a peer2peer request.
+	msg.Timestamp = state.GetTimestamp()
+	msg.DBHeightStart = start
+	msg.DBHeightEnd = end
+	msg.ResumeToken = resumeToken
+
+	return msg
+}
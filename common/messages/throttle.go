@@ -0,0 +1,210 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package messages
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Levels of load a throttle reports
+const (
+	ThrottleBusy       uint8 = iota + 1 // Missing message requests are turned away
+	ThrottleOverloaded                  // Missing message responses are shed too
+)
+
+// Longest a peer may ask to be passed over for, in seconds
+const MaxThrottleRetryAfter = 60
+
+// Throttle tells the peer that sent a request we turned away that we are too far behind
+// on our own messages to serve it, and to ask other peers for RetryAfter seconds.  It is
+// only ever sent to the one peer, and never relayed.
+type Throttle struct {
+	MessageBase
+	Timestamp interfaces.Timestamp
+
+	Level      uint8
+	QueueDepth uint32 // Messages waiting in our inbound queue
+	RetryAfter uint16 // Seconds
+
+	//Not signed!  A peer can only throttle itself.
+}
+
+var _ interfaces.IMsg = (*Throttle)(nil)
+
+func (a *Throttle) IsSameAs(b *Throttle) bool {
+	if b == nil {
+		return false
+	}
+	if a.Timestamp.GetTimeMilli() != b.Timestamp.GetTimeMilli() {
+		return false
+	}
+	if a.Level != b.Level {
+		return false
+	}
+	if a.QueueDepth != b.QueueDepth {
+		return false
+	}
+	if a.RetryAfter != b.RetryAfter {
+		return false
+	}
+
+	return true
+}
+
+func (m *Throttle) GetRepeatHash() interfaces.IHash {
+	return m.GetMsgHash()
+}
+
+func (m *Throttle) GetHash() interfaces.IHash {
+	return m.GetMsgHash()
+}
+
+func (m *Throttle) GetMsgHash() interfaces.IHash {
+	if m.MsgHash == nil {
+		data, err := m.MarshalBinary()
+		if err != nil {
+			return nil
+		}
+		m.MsgHash = primitives.Sha(data)
+	}
+	return m.MsgHash
+}
+
+func (m *Throttle) Type() byte {
+	return constants.THROTTLE_MSG
+}
+
+func (m *Throttle) GetTimestamp() interfaces.Timestamp {
+	return m.Timestamp
+}
+
+// Validate the message, given the state.  Three possible results:
+//
+//	< 0 -- Message is invalid.  Discard
+//	0   -- Cannot tell if message is Valid
+//	1   -- Message is valid
+func (m *Throttle) Validate(state interfaces.IState) int {
+	if m.Level < ThrottleBusy || m.Level > ThrottleOverloaded {
+		return -1
+	}
+	if m.RetryAfter == 0 || m.RetryAfter > MaxThrottleRetryAfter {
+		return -1
+	}
+	return 1
+}
+
+func (m *Throttle) ComputeVMIndex(state interfaces.IState) {
+}
+
+// Execute the leader functions of the given message
+func (m *Throttle) LeaderExecute(state interfaces.IState) {
+	m.FollowerExecute(state)
+}
+
+// Throttles are taken as they come off the network, as they are most likely to be sent us
+// while our peers are busy; there is nothing left to do by the time they are executed.
+func (m *Throttle) FollowerExecute(state interfaces.IState) {
+}
+
+// Throttles do not go into the process list.
+func (e *Throttle) Process(dbheight uint32, state interfaces.IState) bool {
+	panic("Throttle object should never have its Process() method called")
+}
+
+func (e *Throttle) JSONByte() ([]byte, error) {
+	return primitives.EncodeJSON(e)
+}
+
+func (e *Throttle) JSONString() (string, error) {
+	return primitives.EncodeJSONString(e)
+}
+
+func (m *Throttle) UnmarshalBinaryData(data []byte) (newData []byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("Error unmarshalling Throttle Message: %v", r)
+		}
+	}()
+	newData = data
+	if newData[0] != m.Type() {
+		return nil, fmt.Errorf("Invalid Message type")
+	}
+	newData = newData[1:]
+
+	m.Peer2Peer = true // This is always a Peer2peer message
+
+	m.Timestamp = new(primitives.Timestamp)
+	newData, err = m.Timestamp.UnmarshalBinaryData(newData)
+	if err != nil {
+		return nil, err
+	}
+
+	m.Level, newData = newData[0], newData[1:]
+	m.QueueDepth, newData = binary.BigEndian.Uint32(newData[0:4]), newData[4:]
+	m.RetryAfter, newData = binary.BigEndian.Uint16(newData[0:2]), newData[2:]
+
+	return
+}
+
+func (m *Throttle) UnmarshalBinary(data []byte) error {
+	_, err := m.UnmarshalBinaryData(data)
+	return err
+}
+
+func (m *Throttle) MarshalForSignature() ([]byte, error) {
+	var buf primitives.Buffer
+
+	binary.Write(&buf, binary.BigEndian, m.Type())
+
+	t := m.GetTimestamp()
+	data, err := t.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	buf.Write(data)
+
+	binary.Write(&buf, binary.BigEndian, m.Level)
+	binary.Write(&buf, binary.BigEndian, m.QueueDepth)
+	binary.Write(&buf, binary.BigEndian, m.RetryAfter)
+
+	return buf.DeepCopyBytes(), nil
+}
+
+func (m *Throttle) MarshalBinary() ([]byte, error) {
+	return m.MarshalForSignature()
+}
+
+func (m *Throttle) String() string {
+	return fmt.Sprintf("Throttle: level %d queue %d retry after %ds", m.Level, m.QueueDepth, m.RetryAfter)
+}
+
+func (m *Throttle) LogFields() log.Fields {
+	return log.Fields{"category": "message", "messagetype": "throttle",
+		"level":      m.Level,
+		"queuedepth": m.QueueDepth,
+		"retryafter": m.RetryAfter}
+}
+
+// NewThrottle returns a throttle for the peer that sent request
+func NewThrottle(state interfaces.IState, request interfaces.IMsg, level uint8, queueDepth int, retryAfter int) interfaces.IMsg {
+	msg := new(Throttle)
+
+	msg.Peer2Peer = true // Always a peer2peer message.
+	msg.Timestamp = state.GetTimestamp()
+	msg.Level = level
+	msg.QueueDepth = uint32(queueDepth)
+	msg.RetryAfter = uint16(retryAfter)
+	msg.SetOrigin(request.GetOrigin())
+	msg.SetNetworkOrigin(request.GetNetworkOrigin())
+
+	return msg
+}
@@ -0,0 +1,78 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package messages_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/common/constants"
+	. "github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+func TestUnmarshalNilThrottle(t *testing.T) {
+	defer func() {
+		if r := recover(); r != nil {
+			t.Errorf("Panic caught during the test - %v", r)
+		}
+	}()
+
+	a := new(Throttle)
+	err := a.UnmarshalBinary(nil)
+	if err == nil {
+		t.Errorf("Error is nil when it shouldn't be")
+	}
+
+	err = a.UnmarshalBinary([]byte{})
+	if err == nil {
+		t.Errorf("Error is nil when it shouldn't be")
+	}
+}
+
+func TestMarshalUnmarshalThrottle(t *testing.T) {
+	msg := new(Throttle)
+	msg.Timestamp = primitives.NewTimestampNow()
+	msg.Level = ThrottleOverloaded
+	msg.QueueDepth = 1234
+	msg.RetryAfter = 15
+
+	hex, err := msg.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg2, err := UnmarshalMessage(hex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg2.Type() != constants.THROTTLE_MSG {
+		t.Error("Invalid message type unmarshalled")
+	}
+	if !msg.IsSameAs(msg2.(*Throttle)) {
+		t.Errorf("Throttles don't match: %v, %v", msg, msg2)
+	}
+	if !msg2.IsPeer2Peer() {
+		t.Error("Throttle unmarshalled as a broadcast")
+	}
+}
+
+func TestValidateThrottle(t *testing.T) {
+	for _, tc := range []struct {
+		level uint8
+		retry uint16
+		valid int
+	}{
+		{ThrottleBusy, 5, 1},
+		{ThrottleOverloaded, MaxThrottleRetryAfter, 1},
+		{0, 5, -1},
+		{ThrottleOverloaded + 1, 5, -1},
+		{ThrottleBusy, 0, -1},
+		{ThrottleBusy, MaxThrottleRetryAfter + 1, -1},
+	} {
+		msg := &Throttle{Level: tc.level, RetryAfter: tc.retry}
+		if got := msg.Validate(nil); got != tc.valid {
+			t.Errorf("Level %d retry %d validated %d, expected %d", tc.level, tc.retry, got, tc.valid)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package primitives
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"hash"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/FactomProject/ed25519"
+)
+
+// CryptoProvider does the hashing and ed25519 work for the node.  Hashing dominates the
+// CPU while catching up, so the implementation can be swapped for one using the SHA
+// extensions or AVX2 where the hardware has them.  Every provider must produce exactly
+// the same results as the standard one.
+type CryptoProvider interface {
+	Name() string
+	NewSha256() hash.Hash
+	Sha256(data []byte) [32]byte
+	Sha512(data []byte) [64]byte
+	GetPublicKey(priv *[ed25519.PrivateKeySize]byte) *[ed25519.PublicKeySize]byte
+	Sign(priv *[ed25519.PrivateKeySize]byte, msg []byte) *[ed25519.SignatureSize]byte
+	Verify(pub *[ed25519.PublicKeySize]byte, msg []byte, sig *[ed25519.SignatureSize]byte) bool
+}
+
+// StandardCryptoProvider uses the Go standard library and the FactomProject ed25519 package
+type StandardCryptoProvider struct{}
+
+var _ CryptoProvider = (*StandardCryptoProvider)(nil)
+
+func (StandardCryptoProvider) Name() string {
+	return "standard"
+}
+
+func (StandardCryptoProvider) NewSha256() hash.Hash {
+	return sha256.New()
+}
+
+func (StandardCryptoProvider) Sha256(data []byte) [32]byte {
+	return sha256.Sum256(data)
+}
+
+func (StandardCryptoProvider) Sha512(data []byte) [64]byte {
+	return sha512.Sum512(data)
+}
+
+func (StandardCryptoProvider) GetPublicKey(priv *[ed25519.PrivateKeySize]byte) *[ed25519.PublicKeySize]byte {
+	return ed25519.GetPublicKey(priv)
+}
+
+func (StandardCryptoProvider) Sign(priv *[ed25519.PrivateKeySize]byte, msg []byte) *[ed25519.SignatureSize]byte {
+	return ed25519.Sign(priv, msg)
+}
+
+func (StandardCryptoProvider) Verify(pub *[ed25519.PublicKeySize]byte, msg []byte, sig *[ed25519.SignatureSize]byte) bool {
+	return ed25519.VerifyCanonical(pub, msg, sig)
+}
+
+var cryptoProviders = map[string]CryptoProvider{"standard": StandardCryptoProvider{}}
+var cryptoProvidersMutex sync.Mutex
+
+// The provider in use, boxed so providers of different types can be stored.  Tests and
+// tools may change it while other goroutines hash, so it is swapped atomically.
+var cryptoProvider atomic.Value
+
+type cryptoProviderBox struct {
+	provider CryptoProvider
+}
+
+func init() {
+	cryptoProvider.Store(cryptoProviderBox{StandardCryptoProvider{}})
+}
+
+// RegisterCryptoProvider makes a provider available to UseCryptoProvider.  Accelerated
+// providers register themselves from init() in files behind build tags.
+func RegisterCryptoProvider(p CryptoProvider) {
+	cryptoProvidersMutex.Lock()
+	defer cryptoProvidersMutex.Unlock()
+	cryptoProviders[p.Name()] = p
+}
+
+// CryptoProviderNames lists the registered providers
+func CryptoProviderNames() []string {
+	cryptoProvidersMutex.Lock()
+	defer cryptoProvidersMutex.Unlock()
+	return cryptoProviderNamesLocked()
+}
+
+// UseCryptoProvider selects a registered provider by name.  An empty name selects the
+// standard provider.  Work already under way finishes with the provider it started with.
+func UseCryptoProvider(name string) error {
+	if name == "" {
+		name = "standard"
+	}
+	cryptoProvidersMutex.Lock()
+	defer cryptoProvidersMutex.Unlock()
+	p, ok := cryptoProviders[name]
+	if !ok {
+		return fmt.Errorf("Unknown crypto provider %q, this build has: %v", name, cryptoProviderNamesLocked())
+	}
+	cryptoProvider.Store(cryptoProviderBox{p})
+	return nil
+}
+
+func cryptoProviderNamesLocked() []string {
+	var names []string
+	for name := range cryptoProviders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetCryptoProvider returns the provider in use.  Each call loads it atomically, so code
+// that hashes in a loop takes it once and uses it throughout.
+func GetCryptoProvider() CryptoProvider {
+	return cryptoProvider.Load().(cryptoProviderBox).provider
+}
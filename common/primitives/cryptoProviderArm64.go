@@ -0,0 +1,38 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+//go:build arm64 && sha256simd
+// +build arm64,sha256simd
+
+package primitives
+
+import (
+	"github.com/FactomProject/ed25519"
+)
+
+// How many verified signatures the arm64 provider remembers
+const arm64VerifyCacheSize = 8192
+
+// Arm64CryptoProvider is for ARMv8 servers and boards.  SHA-256 runs on the ARMv8
+// cryptography extensions through sha256-simd.  ed25519 is still done by the reference
+// implementation, since any difference in which signatures pass would fork the node, but
+// signatures that have verified before are not checked again.
+type Arm64CryptoProvider struct {
+	SimdCryptoProvider
+	verified *VerifyCache
+}
+
+var _ CryptoProvider = (*Arm64CryptoProvider)(nil)
+
+func init() {
+	RegisterCryptoProvider(&Arm64CryptoProvider{verified: NewVerifyCache(arm64VerifyCacheSize)})
+}
+
+func (*Arm64CryptoProvider) Name() string {
+	return "arm64"
+}
+
+func (p *Arm64CryptoProvider) Verify(pub *[ed25519.PublicKeySize]byte, msg []byte, sig *[ed25519.SignatureSize]byte) bool {
+	return p.verified.Verify(pub, msg, sig, ed25519.VerifyCanonical)
+}
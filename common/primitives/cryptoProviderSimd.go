@@ -0,0 +1,39 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+//go:build sha256simd
+// +build sha256simd
+
+package primitives
+
+import (
+	"hash"
+
+	sha256simd "github.com/minio/sha256-simd"
+)
+
+// SimdCryptoProvider hashes with sha256-simd, which picks the SHA extensions, AVX-512, AVX2
+// or AVX at runtime depending on the CPU, and falls back to the standard library
+// otherwise.  SHA-512 and ed25519 are left to the standard provider.
+type SimdCryptoProvider struct {
+	StandardCryptoProvider
+}
+
+var _ CryptoProvider = (*SimdCryptoProvider)(nil)
+
+func init() {
+	RegisterCryptoProvider(SimdCryptoProvider{})
+}
+
+func (SimdCryptoProvider) Name() string {
+	return "sha256-simd"
+}
+
+func (SimdCryptoProvider) NewSha256() hash.Hash {
+	return sha256simd.New()
+}
+
+func (SimdCryptoProvider) Sha256(data []byte) [32]byte {
+	return sha256simd.Sum256(data)
+}
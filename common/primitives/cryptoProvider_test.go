@@ -0,0 +1,94 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package primitives_test
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/FactomProject/ed25519"
+	. "github.com/FactomProject/factomd/common/primitives"
+)
+
+// countingProvider counts the hashes it is asked for
+type countingProvider struct {
+	StandardCryptoProvider
+	count int
+}
+
+func (*countingProvider) Name() string {
+	return "counting"
+}
+
+func (p *countingProvider) Sha256(data []byte) [32]byte {
+	p.count++
+	return p.StandardCryptoProvider.Sha256(data)
+}
+
+func TestUseCryptoProvider(t *testing.T) {
+	defer UseCryptoProvider("standard")
+
+	if err := UseCryptoProvider("no-such-provider"); err == nil {
+		t.Error("Expected an error for an unknown provider")
+	}
+	if GetCryptoProvider().Name() != "standard" {
+		t.Errorf("Provider changed to %s after a bad name", GetCryptoProvider().Name())
+	}
+
+	p := new(countingProvider)
+	RegisterCryptoProvider(p)
+	if err := UseCryptoProvider("counting"); err != nil {
+		t.Fatal(err)
+	}
+	h := Sha([]byte("abc"))
+	Shad([]byte("abc"))
+	if p.count != 3 {
+		t.Errorf("Provider was asked for %d hashes, expected 3", p.count)
+	}
+	expected := sha256.Sum256([]byte("abc"))
+	if h.Fixed() != expected {
+		t.Errorf("Wrong hash %x", h.Bytes())
+	}
+
+	if err := UseCryptoProvider(""); err != nil || GetCryptoProvider().Name() != "standard" {
+		t.Errorf("Empty name should select the standard provider: %v", err)
+	}
+}
+
+// The benchmarks below give the numbers for a provider on a machine; run them with
+// -tags sha256simd to include the accelerated providers.  See ARM64.md.
+
+func benchmarkProviders(b *testing.B, f func(b *testing.B)) {
+	defer UseCryptoProvider("standard")
+	for _, name := range CryptoProviderNames() {
+		if err := UseCryptoProvider(name); err != nil {
+			b.Fatal(err)
+		}
+		b.Run(name, f)
+	}
+}
+
+func BenchmarkSha256(b *testing.B) {
+	data := make([]byte, 1024)
+	benchmarkProviders(b, func(b *testing.B) {
+		b.SetBytes(int64(len(data)))
+		for i := 0; i < b.N; i++ {
+			Sha(data)
+		}
+	})
+}
+
+func BenchmarkVerify(b *testing.B) {
+	pk := RandomPrivateKey()
+	msg := []byte("benchmark")
+	sig := pk.Sign(msg).GetSignature()
+	pub := (*[ed25519.PublicKeySize]byte)(pk.Pub)
+	benchmarkProviders(b, func(b *testing.B) {
+		p := GetCryptoProvider()
+		for i := 0; i < b.N; i++ {
+			p.Verify(pub, msg, sig)
+		}
+	})
+}
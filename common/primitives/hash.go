@@ -6,8 +6,6 @@ package primitives
 
 import (
 	"bytes"
-	"crypto/sha256"
-	"crypto/sha512"
 	"encoding"
 	"encoding/hex"
 	"encoding/json"
@@ -87,7 +85,7 @@ func (Hash) GetHash() interfaces.IHash {
 }
 
 func CreateHash(entities ...interfaces.BinaryMarshallable) (h interfaces.IHash, err error) {
-	sha := sha256.New()
+	sha := GetCryptoProvider().NewSha256()
 	h = new(Hash)
 	for _, entity := range entities {
 		data, err := entity.MarshalBinary()
@@ -154,11 +152,10 @@ func NewShaHash(newHash []byte) (*Hash, error) {
 
 // Create a Sha512[:256] Hash from a byte array
 func Sha512Half(p []byte) (h *Hash) {
-	sha := sha512.New()
-	sha.Write(p)
+	sum := GetCryptoProvider().Sha512(p)
 
 	h = new(Hash)
-	copy(h[:], sha.Sum(nil)[:constants.HASH_LENGTH])
+	copy(h[:], sum[:constants.HASH_LENGTH])
 	return h
 }
 
@@ -226,15 +223,16 @@ func (e *Hash) JSONString() (string, error) {
 // Create a Sha256 Hash from a byte array
 func Sha(p []byte) interfaces.IHash {
 	h := new(Hash)
-	b := sha256.Sum256(p)
+	b := GetCryptoProvider().Sha256(p)
 	h.SetBytes(b[:])
 	return h
 }
 
 // Shad Double Sha256 Hash; sha256(sha256(data))
 func Shad(data []byte) interfaces.IHash {
-	h1 := sha256.Sum256(data)
-	h2 := sha256.Sum256(h1[:])
+	crypto := GetCryptoProvider()
+	h1 := crypto.Sha256(data)
+	h2 := crypto.Sha256(h1[:])
 	h := new(Hash)
 	h.SetBytes(h2[:])
 	return h
@@ -253,8 +251,9 @@ func NewHash(b []byte) interfaces.IHash {
 
 // shad Double Sha256 Hash; sha256(sha256(data))
 func DoubleSha(data []byte) []byte {
-	h1 := sha256.Sum256(data)
-	h2 := sha256.Sum256(h1[:])
+	crypto := GetCryptoProvider()
+	h1 := crypto.Sha256(data)
+	h2 := crypto.Sha256(h1[:])
 	return h2[:]
 }
 
@@ -82,7 +82,7 @@ func NewPrivateKeyFromHexBytes(privKeybytes []byte) *PrivateKey {
 	pk := new(PrivateKey)
 	pk.AllocateNew()
 	copy(pk.Key[:], privKeybytes)
-	pk.Pub.UnmarshalBinary(ed25519.GetPublicKey(pk.Key)[:])
+	pk.Pub.UnmarshalBinary(GetCryptoProvider().GetPublicKey(pk.Key)[:])
 	return pk
 }
 
@@ -90,7 +90,7 @@ func NewPrivateKeyFromHexBytes(privKeybytes []byte) *PrivateKey {
 func (pk *PrivateKey) Sign(msg []byte) (sig interfaces.IFullSignature) {
 	sig = new(Signature)
 	sig.SetPub(pk.Pub[:])
-	s := ed25519.Sign(pk.Key, msg)
+	s := GetCryptoProvider().Sign(pk.Key, msg)
 	sig.SetSignature(s[:])
 	return
 }
@@ -182,7 +182,7 @@ func PubKeyFromString(instr string) (pk PublicKey) {
 }
 
 func (k *PublicKey) Verify(msg []byte, sig *[ed25519.SignatureSize]byte) bool {
-	return ed25519.VerifyCanonical((*[32]byte)(k), msg, sig)
+	return GetCryptoProvider().Verify((*[32]byte)(k), msg, sig)
 }
 
 func (k *PublicKey) MarshalBinary() ([]byte, error) {
@@ -206,7 +206,7 @@ func (k *PublicKey) UnmarshalBinary(p []byte) (err error) {
 
 // Verify returns true iff sig is a valid signature of message by publicKey.
 func Verify(publicKey *[ed25519.PublicKeySize]byte, message []byte, sig *[ed25519.SignatureSize]byte) bool {
-	return ed25519.VerifyCanonical(publicKey, message, sig)
+	return GetCryptoProvider().Verify(publicKey, message, sig)
 }
 
 // Verify returns true iff sig is a valid signature of message by publicKey.
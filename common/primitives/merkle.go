@@ -31,12 +31,20 @@ func NextPowerOfTwo(n int) int {
 // nodes, and returns the hash of their concatenation.  This is a helper
 // function used to aid in the generation of a merkle tree.
 func HashMerkleBranches(left interfaces.IHash, right interfaces.IHash) interfaces.IHash {
+	return hashMerkleBranches(GetCryptoProvider(), left, right)
+}
+
+// hashMerkleBranches hashes the two nodes with the given provider, so a whole tree is
+// built with the one taken at the start
+func hashMerkleBranches(crypto CryptoProvider, left interfaces.IHash, right interfaces.IHash) interfaces.IHash {
 	// Concatenate the left and right nodes.
 	var barray []byte = make([]byte, constants.ADDRESS_LENGTH*2)
 	copy(barray[:constants.ADDRESS_LENGTH], left.Bytes())
 	copy(barray[constants.ADDRESS_LENGTH:], right.Bytes())
 
-	newSha := Sha(barray)
+	sum := crypto.Sha256(barray)
+	newSha := new(Hash)
+	newSha.SetBytes(sum[:])
 	return newSha
 }
 
@@ -48,6 +56,10 @@ func ComputeMerkleRoot(hashes []interfaces.IHash) interfaces.IHash {
 
 // The root of the Merkle Tree is returned in merkles[len(merkles)-1]
 func BuildMerkleTreeStore(hashes []interfaces.IHash) (merkles []interfaces.IHash) {
+	return buildMerkleTreeStore(GetCryptoProvider(), hashes)
+}
+
+func buildMerkleTreeStore(crypto CryptoProvider, hashes []interfaces.IHash) (merkles []interfaces.IHash) {
 	if len(hashes) == 0 {
 		return append(make([]interfaces.IHash, 0, 1), new(Hash))
 	}
@@ -58,13 +70,13 @@ func BuildMerkleTreeStore(hashes []interfaces.IHash) (merkles []interfaces.IHash
 	for i := 0; i < len(hashes); i += 2 {
 		var node interfaces.IHash
 		if i+1 == len(hashes) {
-			node = HashMerkleBranches(hashes[i], hashes[i])
+			node = hashMerkleBranches(crypto, hashes[i], hashes[i])
 		} else {
-			node = HashMerkleBranches(hashes[i], hashes[i+1])
+			node = hashMerkleBranches(crypto, hashes[i], hashes[i+1])
 		}
 		nextLevel = append(nextLevel, node)
 	}
-	nextIteration := BuildMerkleTreeStore(nextLevel)
+	nextIteration := buildMerkleTreeStore(crypto, nextLevel)
 	return append(hashes, nextIteration...)
 }
 
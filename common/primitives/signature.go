@@ -157,7 +157,7 @@ func (ds *DetachedSignature) String() string {
 // Verify returns true iff sig is a valid signature of msg by PublicKey.
 func (sig *Signature) Verify(msg []byte) bool {
 	sig.Init()
-	return ed25519.VerifyCanonical((*[32]byte)(sig.Pub), msg, (*[ed25519.SignatureSize]byte)(sig.Sig))
+	return GetCryptoProvider().Verify((*[32]byte)(sig.Pub), msg, (*[ed25519.SignatureSize]byte)(sig.Sig))
 }
 
 func SignSignable(priv []byte, data interfaces.ISignable) ([]byte, error) {
@@ -174,13 +174,13 @@ func Sign(priv, data []byte) []byte {
 		copy(priv2[:], priv[:])
 	} else if len(priv) == 32 {
 		copy(priv2[:], priv[:])
-		pub := ed25519.GetPublicKey(&priv2)
+		pub := GetCryptoProvider().GetPublicKey(&priv2)
 		copy(priv2[:], append(priv, pub[:]...)[:])
 	} else {
 		return nil
 	}
 
-	return ed25519.Sign(&priv2, data)[:constants.SIGNATURE_LENGTH]
+	return GetCryptoProvider().Sign(&priv2, data)[:constants.SIGNATURE_LENGTH]
 }
 
 func VerifySignature(data, publicKey, signature []byte) error {
@@ -199,7 +199,7 @@ func VerifySignature(data, publicKey, signature []byte) error {
 		return fmt.Errorf("Invalid signature length")
 	}
 
-	valid := ed25519.VerifyCanonical(&pub, data, &sig)
+	valid := GetCryptoProvider().Verify(&pub, data, &sig)
 	if valid == false {
 		return fmt.Errorf("Invalid signature")
 	}
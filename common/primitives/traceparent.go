@@ -0,0 +1,59 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package primitives
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+)
+
+// ParseTraceParent parses a W3C traceparent header, i.e.
+// 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01
+func ParseTraceParent(header string) (*interfaces.TraceContext, error) {
+	parts := strings.Split(strings.TrimSpace(header), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return nil, fmt.Errorf("Malformed traceparent %q", header)
+	}
+	// Only version 00 is defined; later versions may add fields, but must keep these four
+	if parts[0] == "00" && len(parts) != 4 {
+		return nil, fmt.Errorf("Malformed traceparent %q", header)
+	}
+	for _, p := range parts[:4] {
+		if strings.ToLower(p) != p {
+			return nil, fmt.Errorf("Malformed traceparent %q", header)
+		}
+	}
+
+	t := new(interfaces.TraceContext)
+	version, err := hex.DecodeString(parts[0])
+	if err != nil || version[0] == 0xff {
+		return nil, fmt.Errorf("Invalid traceparent version in %q", header)
+	}
+	t.Version = version[0]
+	if _, err := hex.Decode(t.TraceID[:], []byte(parts[1])); err != nil || t.TraceID == [16]byte{} {
+		return nil, fmt.Errorf("Invalid trace id in %q", header)
+	}
+	if _, err := hex.Decode(t.ParentID[:], []byte(parts[2])); err != nil || t.ParentID == [8]byte{} {
+		return nil, fmt.Errorf("Invalid parent id in %q", header)
+	}
+	flags, err := hex.DecodeString(parts[3])
+	if err != nil {
+		return nil, fmt.Errorf("Invalid trace flags in %q", header)
+	}
+	t.Flags = flags[0]
+	return t, nil
+}
+
+// NewSpanID returns a random span id
+func NewSpanID() (id [8]byte) {
+	for id == [8]byte{} {
+		rand.Read(id[:])
+	}
+	return
+}
@@ -0,0 +1,47 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package primitives_test
+
+import (
+	"testing"
+
+	. "github.com/FactomProject/factomd/common/primitives"
+)
+
+func TestParseTraceParent(t *testing.T) {
+	header := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	tc, err := ParseTraceParent(header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tc.String() != header || tc.Flags != 1 || tc.Version != 0 {
+		t.Errorf("Parsed %s into %+v", header, tc)
+	}
+	span := NewSpanID()
+	if tc.TraceParent(span) == header || len(tc.TraceParent(span)) != len(header) {
+		t.Errorf("Bad child traceparent %s", tc.TraceParent(span))
+	}
+
+	// Later versions may carry more fields
+	if _, err := ParseTraceParent("01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra"); err != nil {
+		t.Errorf("Future version rejected: %v", err)
+	}
+
+	bad := []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e473g-00f067aa0ba902b7-01",
+	}
+	for _, b := range bad {
+		if _, err := ParseTraceParent(b); err == nil {
+			t.Errorf("Accepted bad traceparent %q", b)
+		}
+	}
+}
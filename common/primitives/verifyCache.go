@@ -0,0 +1,65 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package primitives
+
+import (
+	"crypto/sha256"
+	"sync"
+
+	"github.com/FactomProject/ed25519"
+)
+
+// VerifyCache remembers signatures that have verified.  The same signatures from the
+// federated servers (acks, EOMs, DBSigs) arrive from every peer and are checked again and
+// again; hashing them is much cheaper than another ed25519 verification, especially on
+// ARM.  Only successes are kept, so a cache can never make a bad signature pass, and the
+// oldest are forgotten once it is full.
+type VerifyCache struct {
+	mutex sync.Mutex
+	seen  map[[32]byte]bool
+	order [][32]byte // Ring of the keys in seen, oldest at next
+	next  int
+}
+
+func NewVerifyCache(size int) *VerifyCache {
+	c := new(VerifyCache)
+	c.seen = make(map[[32]byte]bool, size)
+	c.order = make([][32]byte, size)
+	return c
+}
+
+func verifyCacheKey(pub *[ed25519.PublicKeySize]byte, msg []byte, sig *[ed25519.SignatureSize]byte) [32]byte {
+	h := sha256.New()
+	h.Write(pub[:])
+	h.Write(sig[:])
+	h.Write(msg)
+	var key [32]byte
+	h.Sum(key[:0])
+	return key
+}
+
+// Verify checks the signature with check, unless it has verified before
+func (c *VerifyCache) Verify(pub *[ed25519.PublicKeySize]byte, msg []byte, sig *[ed25519.SignatureSize]byte,
+	check func(*[ed25519.PublicKeySize]byte, []byte, *[ed25519.SignatureSize]byte) bool) bool {
+	key := verifyCacheKey(pub, msg, sig)
+	c.mutex.Lock()
+	ok := c.seen[key]
+	c.mutex.Unlock()
+	if ok {
+		return true
+	}
+	if !check(pub, msg, sig) {
+		return false
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if !c.seen[key] {
+		delete(c.seen, c.order[c.next])
+		c.order[c.next] = key
+		c.seen[key] = true
+		c.next = (c.next + 1) % len(c.order)
+	}
+	return true
+}
@@ -0,0 +1,44 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package primitives_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/ed25519"
+	. "github.com/FactomProject/factomd/common/primitives"
+)
+
+func TestVerifyCache(t *testing.T) {
+	c := NewVerifyCache(2)
+	checks := 0
+	check := func(pub *[ed25519.PublicKeySize]byte, msg []byte, sig *[ed25519.SignatureSize]byte) bool {
+		checks++
+		return string(msg) != "bad"
+	}
+	pub := new([ed25519.PublicKeySize]byte)
+	sig := new([ed25519.SignatureSize]byte)
+
+	if !c.Verify(pub, []byte("a"), sig, check) || !c.Verify(pub, []byte("a"), sig, check) || checks != 1 {
+		t.Errorf("Expected the second verification to come from the cache, %d checks", checks)
+	}
+	if c.Verify(pub, []byte("bad"), sig, check) || c.Verify(pub, []byte("bad"), sig, check) || checks != 3 {
+		t.Errorf("Expected failures to be checked every time, %d checks", checks)
+	}
+
+	// Fill the cache past its size; the oldest is forgotten
+	c.Verify(pub, []byte("b"), sig, check)
+	c.Verify(pub, []byte("c"), sig, check)
+	checks = 0
+	c.Verify(pub, []byte("a"), sig, check)
+	if checks != 1 {
+		t.Errorf("Expected the oldest signature to be forgotten, %d checks", checks)
+	}
+	checks = 0
+	c.Verify(pub, []byte("c"), sig, check)
+	if checks != 0 {
+		t.Errorf("Expected a recent signature to be kept")
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package serialization
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+)
+
+// A corpus is a directory of fixtures, each written in every version of the format:
+// <kind>-<name>.legacy and <kind>-<name>.v1.  Checking a corpus written by one build of
+// the node with another shows whether the two still read and write the same bytes.
+
+// Fixture is a named object for the corpus
+type Fixture struct {
+	Name   string
+	Kind   Kind
+	Object interfaces.BinaryMarshallable
+}
+
+// FileName is the name of the fixture's file for the given version, without a directory
+func (f Fixture) FileName(version Version) string {
+	return fmt.Sprintf("%s-%s.%s", f.Kind, f.Name, versionExtension(version))
+}
+
+func versionExtension(version Version) string {
+	if version == Legacy {
+		return "legacy"
+	}
+	return fmt.Sprintf("v%d", version)
+}
+
+// ParseKind returns the kind with the given name, as written by Kind.String
+func ParseKind(name string) (Kind, error) {
+	for _, k := range Kinds {
+		if k.String() == name {
+			return k, nil
+		}
+	}
+	return 0, fmt.Errorf("Unknown kind %q", name)
+}
+
+// WriteCorpus writes each fixture to dir in every version of the format
+func WriteCorpus(dir string, fixtures []Fixture) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for _, f := range fixtures {
+		for _, version := range []Version{Legacy, V1} {
+			data, err := Marshal(version, f.Kind, f.Object)
+			if err != nil {
+				return fmt.Errorf("%s: %v", f.FileName(version), err)
+			}
+			if err := ioutil.WriteFile(filepath.Join(dir, f.FileName(version)), data, 0644); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// CheckCorpus reads every fixture in dir, and returns a problem for each that doesn't
+// round trip: the legacy file must decode and encode to the same bytes, the versioned
+// file must decode as that version, both must be equivalent, and encoding the object in
+// the version must give the versioned file back.
+func CheckCorpus(dir string) (checked int, problems []error, err error) {
+	names, err := filepath.Glob(filepath.Join(dir, "*."+versionExtension(Legacy)))
+	if err != nil {
+		return 0, nil, err
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		base := strings.TrimSuffix(filepath.Base(name), "."+versionExtension(Legacy))
+		kind, err := ParseKind(strings.SplitN(base, "-", 2)[0])
+		if err != nil {
+			problems = append(problems, fmt.Errorf("%s: %v", base, err))
+			continue
+		}
+		for _, p := range checkFixture(dir, base, kind) {
+			problems = append(problems, fmt.Errorf("%s: %v", base, p))
+		}
+		checked++
+	}
+	return checked, problems, nil
+}
+
+func checkFixture(dir string, base string, kind Kind) (problems []error) {
+	legacy, err := ioutil.ReadFile(filepath.Join(dir, base+"."+versionExtension(Legacy)))
+	if err != nil {
+		return []error{err}
+	}
+	obj, version, err := Unmarshal(kind, legacy)
+	if err != nil {
+		return []error{fmt.Errorf("legacy doesn't decode: %v", err)}
+	}
+	if version != Legacy {
+		problems = append(problems, fmt.Errorf("legacy decodes as version %d", version))
+	}
+	if again, err := Marshal(Legacy, kind, obj); err != nil || !bytes.Equal(again, legacy) {
+		problems = append(problems, fmt.Errorf("legacy doesn't encode to the same bytes: %v", err))
+	}
+
+	for _, v := range []Version{V1} {
+		file := base + "." + versionExtension(v)
+		data, err := ioutil.ReadFile(filepath.Join(dir, file))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			problems = append(problems, err)
+			continue
+		}
+		if _, got, err := Unmarshal(kind, data); err != nil || got != v {
+			problems = append(problems, fmt.Errorf("%s decodes as version %d: %v", file, got, err))
+			continue
+		}
+		if same, err := Equivalent(kind, legacy, data); err != nil || !same {
+			problems = append(problems, fmt.Errorf("%s isn't equivalent to legacy: %v", file, err))
+		}
+		if again, err := Marshal(v, kind, obj); err != nil || !bytes.Equal(again, data) {
+			problems = append(problems, fmt.Errorf("%s isn't what the legacy object encodes to: %v", file, err))
+		}
+	}
+	return problems
+}
@@ -0,0 +1,225 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+// Package serialization reads and writes messages and blocks in either the current
+// binary format or the versioned format that is to replace it, so nodes and tools can
+// accept both while the network moves over.
+//
+// The versioned format wraps the current encoding in an envelope:
+//
+//	magic    2 bytes  0xFA 0xC7
+//	version  1 byte   V1
+//	kind     1 byte   KindMessage, KindDirectoryBlock, ...
+//	length   varint   of the body
+//	body              the encoding for that version; for V1, the current binary format
+//	checksum 4 bytes  the first 4 bytes of the SHA256 of the body
+//
+// Later versions are free to change the body, since a reader can always tell which
+// version it was handed.  Data without the envelope is the current (Legacy) format.
+package serialization
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/FactomProject/factomd/common/adminBlock"
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/directoryBlock"
+	"github.com/FactomProject/factomd/common/entryBlock"
+	"github.com/FactomProject/factomd/common/entryCreditBlock"
+	"github.com/FactomProject/factomd/common/factoid"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// Version of the format
+type Version byte
+
+const (
+	Legacy Version = 0 // The binary format without an envelope
+	V1     Version = 1 // The envelope around the legacy binary format
+)
+
+// The newest version this package writes
+const Latest = V1
+
+// Kind of object encoded
+type Kind byte
+
+const (
+	KindMessage Kind = iota + 1
+	KindDirectoryBlock
+	KindAdminBlock
+	KindEntryCreditBlock
+	KindFactoidBlock
+	KindEntryBlock
+	KindEntry
+)
+
+// Kinds lists every kind, in order
+var Kinds = []Kind{KindMessage, KindDirectoryBlock, KindAdminBlock, KindEntryCreditBlock, KindFactoidBlock, KindEntryBlock, KindEntry}
+
+func (k Kind) String() string {
+	switch k {
+	case KindMessage:
+		return "message"
+	case KindDirectoryBlock:
+		return "dblock"
+	case KindAdminBlock:
+		return "ablock"
+	case KindEntryCreditBlock:
+		return "ecblock"
+	case KindFactoidBlock:
+		return "fblock"
+	case KindEntryBlock:
+		return "eblock"
+	case KindEntry:
+		return "entry"
+	}
+	return fmt.Sprintf("kind-%d", byte(k))
+}
+
+// Magic starts every versioned encoding
+var Magic = [2]byte{0xFA, 0xC7}
+
+const checksumSize = 4
+
+// Marshal encodes obj, which must be of the given kind, in the given version of the format
+func Marshal(version Version, kind Kind, obj interfaces.BinaryMarshallable) ([]byte, error) {
+	if obj == nil {
+		return nil, errors.New("Nothing to marshal")
+	}
+	body, err := obj.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	switch version {
+	case Legacy:
+		return body, nil
+	case V1:
+		return wrap(version, kind, body), nil
+	}
+	return nil, fmt.Errorf("Unknown format version %d", version)
+}
+
+// Unmarshal decodes an object of the given kind from either format, and returns the
+// version it was in
+func Unmarshal(kind Kind, data []byte) (interfaces.BinaryMarshallable, Version, error) {
+	version, body, err := Unwrap(kind, data)
+	if err != nil {
+		return nil, version, err
+	}
+	obj, err := unmarshalBody(kind, body)
+	return obj, version, err
+}
+
+// Detect returns the version data is in, without decoding it.  It may be a version this
+// build can't read.
+func Detect(kind Kind, data []byte) Version {
+	version, _, _ := Unwrap(kind, data)
+	return version
+}
+
+// Convert re-encodes data of the given kind in another version of the format.  The
+// object is decoded and encoded again, so data that doesn't decode isn't converted.
+func Convert(kind Kind, data []byte, to Version) ([]byte, error) {
+	obj, _, err := Unmarshal(kind, data)
+	if err != nil {
+		return nil, err
+	}
+	return Marshal(to, kind, obj)
+}
+
+// Equivalent is true if a and b, in any versions, decode to objects of the given kind that
+// encode to the same legacy binary
+func Equivalent(kind Kind, a []byte, b []byte) (bool, error) {
+	la, err := Convert(kind, a, Legacy)
+	if err != nil {
+		return false, err
+	}
+	lb, err := Convert(kind, b, Legacy)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(la, lb), nil
+}
+
+func wrap(version Version, kind Kind, body []byte) []byte {
+	buf := primitives.NewBuffer(nil)
+	buf.Write(Magic[:])
+	buf.WriteByte(byte(version))
+	buf.WriteByte(byte(kind))
+	primitives.EncodeVarInt(buf, uint64(len(body)))
+	buf.Write(body)
+	buf.Write(primitives.Sha(body).Bytes()[:checksumSize])
+	return buf.DeepCopyBytes()
+}
+
+// Unwrap returns the version data is in, and the body within the envelope.  Data that
+// doesn't carry an envelope for this kind, with a matching length and checksum, is taken
+// as legacy, since a legacy entry block can start with any bytes at all.
+func Unwrap(kind Kind, data []byte) (Version, []byte, error) {
+	if len(data) == 0 {
+		return Legacy, nil, errors.New("No data provided")
+	}
+	if len(data) < 4 || data[0] != Magic[0] || data[1] != Magic[1] || Kind(data[3]) != kind {
+		return Legacy, data, nil
+	}
+	version := Version(data[2])
+	length, rest := primitives.DecodeVarInt(data[4:])
+	if length > uint64(len(rest)) || uint64(len(rest))-length != checksumSize {
+		return Legacy, data, nil
+	}
+	body := rest[:length]
+	if !bytes.Equal(primitives.Sha(body).Bytes()[:checksumSize], rest[length:]) {
+		return Legacy, data, nil
+	}
+	if version != V1 {
+		return version, nil, fmt.Errorf("Unknown format version %d", version)
+	}
+	return version, body, nil
+}
+
+// unmarshalBody decodes the legacy binary of an object
+func unmarshalBody(kind Kind, body []byte) (interfaces.BinaryMarshallable, error) {
+	switch kind {
+	case KindMessage:
+		return unmarshalMessage(body)
+	case KindDirectoryBlock:
+		return directoryBlock.UnmarshalDBlock(body)
+	case KindAdminBlock:
+		return adminBlock.UnmarshalABlock(body)
+	case KindEntryCreditBlock:
+		return entryCreditBlock.UnmarshalECBlock(body)
+	case KindFactoidBlock:
+		return factoid.UnmarshalFBlock(body)
+	case KindEntryBlock:
+		return entryBlock.UnmarshalEBlock(body)
+	case KindEntry:
+		return entryBlock.UnmarshalEntry(body)
+	}
+	return nil, fmt.Errorf("Unknown kind %d", kind)
+}
+
+// unmarshalMessage decodes every message type, including those peers never send
+func unmarshalMessage(body []byte) (interfaces.IMsg, error) {
+	if len(body) == 0 {
+		return nil, errors.New("No data provided")
+	}
+	var msg interfaces.IMsg
+	switch body[0] {
+	case constants.MISSING_ENTRY_BLOCKS:
+		msg = new(messages.MissingEntryBlocks)
+	case constants.ENTRY_BLOCK_RESPONSE:
+		msg = new(messages.EntryBlockResponse)
+	default:
+		return messages.UnmarshalMessage(body)
+	}
+	if err := msg.UnmarshalBinary(body); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
@@ -0,0 +1,139 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package serialization_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	. "github.com/FactomProject/factomd/common/serialization"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestRoundTrip(t *testing.T) {
+	fixtures := testHelper.CreateTestFormatCorpus()
+	for _, f := range fixtures {
+		legacy, err := Marshal(Legacy, f.Kind, f.Object)
+		if err != nil {
+			t.Fatalf("%s: %v", f.FileName(Legacy), err)
+		}
+		v1, err := Marshal(V1, f.Kind, f.Object)
+		if err != nil {
+			t.Fatalf("%s: %v", f.FileName(V1), err)
+		}
+		if Detect(f.Kind, legacy) != Legacy || Detect(f.Kind, v1) != V1 {
+			t.Errorf("%s: versions not detected", f.FileName(Legacy))
+		}
+		if same, err := Equivalent(f.Kind, legacy, v1); err != nil || !same {
+			t.Errorf("%s: legacy and v1 not equivalent: %v", f.FileName(Legacy), err)
+		}
+		back, err := Convert(f.Kind, v1, Legacy)
+		if err != nil || !bytes.Equal(back, legacy) {
+			t.Errorf("%s: v1 converted to different legacy bytes: %v", f.FileName(Legacy), err)
+		}
+	}
+}
+
+func TestCorpusCoversEveryType(t *testing.T) {
+	kinds := map[Kind]bool{}
+	msgTypes := map[byte]bool{}
+	for _, f := range testHelper.CreateTestFormatCorpus() {
+		kinds[f.Kind] = true
+		if msg, ok := f.Object.(interfaces.IMsg); ok {
+			msgTypes[msg.Type()] = true
+		}
+	}
+	for _, k := range Kinds {
+		if !kinds[k] {
+			t.Errorf("No %s in the corpus", k)
+		}
+	}
+	for i := byte(0); i < constants.NUM_MESSAGES; i++ {
+		// Invalid acks were never given a message
+		if !msgTypes[i] && i != constants.INVALID_ACK_MSG {
+			t.Errorf("No message of type %d in the corpus", i)
+		}
+	}
+}
+
+func TestCorpus(t *testing.T) {
+	// The fixtures written by earlier builds must still read and write the same
+	checked, problems, err := CheckCorpus("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range problems {
+		t.Error(p)
+	}
+	if checked != len(testHelper.CreateTestFormatCorpus()) {
+		t.Errorf("Checked %d fixtures in testdata, run FormatCorpus -out to add new ones", checked)
+	}
+
+	// And a corpus written now must check out too
+	dir, err := ioutil.TempDir("", "corpus")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := WriteCorpus(dir, testHelper.CreateTestFormatCorpus()); err != nil {
+		t.Fatal(err)
+	}
+	if _, problems, err := CheckCorpus(dir); err != nil || len(problems) > 0 {
+		t.Errorf("Fresh corpus: %v %v", problems, err)
+	}
+}
+
+func TestUnwrap(t *testing.T) {
+	entry := testHelper.CreateTestEntry(1)
+	v1, err := Marshal(V1, KindEntry, entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The envelope of another kind isn't taken for this one
+	if Detect(KindEntryBlock, v1) != Legacy {
+		t.Error("Entry envelope taken for an entry block")
+	}
+
+	// Nor is one with a bad checksum
+	bad := append([]byte(nil), v1...)
+	bad[len(bad)-1]++
+	if Detect(KindEntry, bad) != Legacy {
+		t.Error("Bad checksum accepted")
+	}
+
+	// A version from the future is recognised, but can't be read
+	future := append([]byte(nil), v1...)
+	future[2] = 9
+	if Detect(KindEntry, future) != 9 {
+		t.Error("Future version not detected")
+	}
+	if _, version, err := Unmarshal(KindEntry, future); version != 9 || err == nil {
+		t.Errorf("Future version read as %d: %v", version, err)
+	}
+
+	// A legacy entry block whose chain ID starts with the magic is still legacy
+	eblock, _ := testHelper.CreateTestEntryBlock(nil)
+	chainID := make([]byte, 32)
+	copy(chainID, []byte{Magic[0], Magic[1], byte(V1), byte(KindEntryBlock)})
+	h, _ := primitives.NewShaHash(chainID)
+	eblock.GetHeader().SetChainID(h)
+	legacy, err := Marshal(Legacy, KindEntryBlock, eblock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, version, err := Unmarshal(KindEntryBlock, legacy); version != Legacy || err != nil {
+		t.Errorf("Legacy entry block read as version %d: %v", version, err)
+	}
+
+	if _, _, err := Unmarshal(KindMessage, nil); err == nil {
+		t.Error("Nothing unmarshalled")
+	}
+}
@@ -170,7 +170,7 @@ function updateTransactions() {
 
 // 3 Queriers in Batch
 function updateHeight() {
-  resp = batchQueryState("myHeight,leaderHeight,completeHeight,servercount,channelLength",function(resp){
+  resp = batchQueryState("myHeight,leaderHeight,completeHeight,servercount,channelLength,startupProgress,shutdownProgress",function(resp){
     obj = JSON.parse(resp)
     myHeight = obj[0].Height
     lHeight = obj[1].Height
@@ -178,6 +178,8 @@ function updateHeight() {
     feds = obj[3].fed
     auds = obj[3].aud
     respFive = obj[4].length
+    updateStartup(obj[5])
+    updateShutdown(obj[6])
 
     $("#serverfedcount").val(feds)
     $("#serveraudcount").val(auds)
@@ -213,6 +215,36 @@ function updateHeight() {
   })
 }
 
+// Shows the phase of booting until the node is in sync
+function updateStartup(startup) {
+  if(startup.phase == "" || startup.complete) {
+    $("#startup").hide()
+    return
+  }
+  $("#startup").show()
+  updateProgressBar("#startupProgress > .progress-meter", startup.percent, 100)
+  eta = ""
+  if(startup.eta >= 0) {
+    eta = ", about " + startup.eta + "s left"
+  }
+  $('#startupProgress > .progress-meter > .progress-meter-text').text("Phase " + startup.phaseindex + " of " + startup.phases + ": " + startup.phase + " " + Math.floor(startup.percent) + "%" + eta)
+}
+
+// Shows the step of shutting down, once the node has begun to
+function updateShutdown(shutdown) {
+  if(shutdown.step == "") {
+    $("#shutdown").hide()
+    return
+  }
+  $("#shutdown").show()
+  updateProgressBar("#shutdownProgress > .progress-meter", shutdown.stepindex, shutdown.steps)
+  text = "Step " + shutdown.stepindex + " of " + shutdown.steps + ": " + shutdown.step + ", " + shutdown.elapsed + "s"
+  if(shutdown.errors) {
+    text += " (" + shutdown.errors.join("; ") + ")"
+  }
+  $('#shutdownProgress > .progress-meter > .progress-meter-text').text(text)
+}
+
 function updateProgressBar(id, current, max) {
   if(max == 0) {
     percent = (current/max) * 100
@@ -350,6 +350,23 @@ func factomdQuery(item string, value string, batchQueried bool) []byte {
 		h := DisplayState.CurrentEBDBHeight
 		DisplayStateMutex.RUnlock()
 		return HeightToJsonStruct(h)
+	case "startupProgress":
+		DisplayStateMutex.RLock()
+		status := DisplayState.Startup
+		DisplayStateMutex.RUnlock()
+		data, err := json.Marshal(status)
+		if err != nil {
+			return []byte(`{"phase":""}`)
+		}
+		return data
+	case "shutdownProgress":
+		// Asked of the state itself, as the display state is no longer updated once the
+		// node stops processing
+		data, err := json.Marshal(StatePointer.GetShutdownStatus())
+		if err != nil {
+			return []byte(`{"step":""}`)
+		}
+		return data
 	case "connections":
 	case "dataDump":
 		data := GetDataDumps()
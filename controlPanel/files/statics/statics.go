@@ -143,11 +143,11 @@ var staticFiles = map[string]*staticFilesFile{
 		size:  0,
 	},
 	"js/controlPanel.js": {
-		data:  "\x1f\x8b\b\x00\x00\tn\x88\x02\xff\xec\\[s\xdb8\xb2~\u05ef\xe8a\xb2+r,Qr2\x93:gm\xb9ʎ';\xde\xc9mb\x9f=\x0f9~\x80HHBB\x02\f\x00\xdaVM\xfc\xdfO\xe1B\x12\xa0H]ffS\xfb\xb0S5\xb1\x05t\u007f\xdd\xe8n4\x1a\x17\xf9\x0eqHJ\xce1\x95?c\xb2\\I\x98\xc1t\xa0Z3\x8cR̝Ɓ\xc0\xf2\x8aJ\xcc\xefP\x16\x96E\x8a$\xfe\xf9\xe6\xcd\xeb\xd1\xf3\xe9t\x1a\x9dh\x1e\x81\xf9\x1d\xe6\xefhF(\x86\x19,P&\xf0`2\x81\xff\x118\x05\xc9\xc0p\x81`9\x06\xb9\"t) \xc3B\xc0\x82\xe3/%\xa62[\x1b\x98Ϥ\xa8$\xd50\x83\xa7\xe1=\xa1)\xbb\x8f⌡4\x1c\x00\x00,J\x9aH\xc2h\x18\xc1o\xba\x01\xa0\xd1,\x8cl\x93\xc0\xf2\x86䘕2\xac\x18\xc0\xe1\xe8\xe5{\x1c\xc1\xb1\x19\x9c\xfe4\x88N\x06\x83\x1a\xc0\xa5\xd7POc\xf4\t=\x84\xc3x2\x1cYlQ&\t\x16\xe2o\x8e\x9e\xbf\xd5:y\xa6\x92\xbc\xc4F\xcaH\xff\xc0\x9c3\xbe\a\x9f\xb1\x8dQ\x0f\xe0Qi\b@\x16\x10~\xe7\x12Vc}\x1a\x06OL\xfbXH$K\x11D\xb1\xc4\x0f2\f^\xa1D\xb2<\x85\xb7L\u0087\x92RB\x97\x811\x03ǲ\xe4T\x81\x03\xce\x04\xde\x1b\xc9Ey\xac\xb4Rl\x84\xa6\xf8\x81\xa2\xbbq\x8e\b\r\xa2x\x85\xc4\xcb\f\t\x11\x06D\x8cQ\"\xc9\x1d\x0e\xa2J\xe3\xc9\x04\xde B\xe1\x06\xcd\a\x8e\x97tT\x86\x118m\xe7Y\xf6\x1ec.\xac\xf7&\x13\xb8dX\x00\xbe\xc3|\r\x882\xb9\xc2\x1c\x92u\x92\x19s\x91E\xf8\x9d\x1bg\x91\x1f?7\x1cQ\x81\xb4\xedE\x13G~\\6>s-\x03\xdd\xe1[\xbb\xc8ВE\xcb\x16\x8c\xe3=lq\x89%\"\x19N}{\xa0K\xf5\u007f\x99\x17F\xd5\xc7\xc1\xe0q\xa0\xa7\x9d\x99n\xf7+L\xe1\x1e\x83\xb8'2Y\x81Ds1x\x1a\x06\xb1\xfae\x9c0*9\xcb\xc6\x05\xa28\x83\x8c\x00\n\xa28\xc9H\xf29\xf4\x83ϙD\x03o\xe2\r\xc0\xeaךA\x8f#x>\x9dF\x83\xc7H\xcd\xdd\xe0IZ\xe6\x85\x16\x87\b\xc5\x1c\x9e,\xca,\x13\tǘ\x8eY\xa1\xb0j\xc1\xad\xb0\x97\x0f\xf2\x9cc\x043\xf8\xf4k\x89\xf9:\x94+\"\xa2X\x90y\xa6RH\x18Ď\xb1\x1a\xfaX\xb2\xe52\xc3֞\x8d4M\xe3!y\x84h.XVJ<\xee\xd0o+\xe3\x82<ഓKY@\xf9\xe3\x86\x15\xda\xfa\xc0(h\xcf\x0f6\xe6\x03\x9cu:@[XM O\xfc\x96h٘\xac\xd2\t\xe8 \x8a9\xce\xd9]\xa5\xf9\x8a\xa4\xd8\xcevE\x9a\xb1\x04e;hR\x1bqA\x14\xa34m\xd3<\xd6N\xf7\x02\xfc[\r\xaeC#\u007fd\xbd\x04ΰ\xbaGoF\xe6/\x02\xee\xf4\xd3Jq,\n\x98\xc1\x175\x9ak\x89$\x0e\x83\x1ax\x04A0\xaaǮ(m\xe6a\xf3O0\x83\u007f\\\xbf{\x1b\x17\x88\vl\xfa\x1a\xcdʼ8\x06\xfd\xe3zŸ\xac\xf2-\x9b\u007f\x8a+\xf9Ǳ\xeeR\xbfv2~@\xf7\xddl\x1fнa\xf2\xb8\x9em\xe5z\xd6\xc3\xf5|+\xd7\xf3\x1e\xae\x1f\f\xd7y)W]l?Ī\x87q\"\t\x16Q\x17\xe7U\x8a\xa9\xecf\xd5]\xfd\x9co\xd6o\x99\xf2n\x17\xab\xe9k\xe9\xfa\xa3\xe1{\xc9h\xcf \u007fl\x06\xd9\xc1w\xdd\xe3\xbd\x1fcՃӊ\xf11Ri\xbcUo\xf8\xebR_\xb4q\x9c`*]\xda`tx\xd8M&v\x95\xba\xbc\xb8\xc8X\xf2٬\xba\x95\xea\x11|7\x03\xad?\xe18\x91\x8c\xaf5Q|ya\xe8\x9a\xfa\xca@\xfc\x82\xd7o>\xd8\fЌ\xdd\xe7\xd54\x91\xc7v\xc1ҵn\xde\xc2V\xd3\xf8\xac\xaf\xca,\xfb\x19\x89\xd5\x16Ί\xa4%S\xf5\xa9\xc5NH\xa4sA\x1f{M\xd3\xc1\xef[k\x9b\xa1\x1c^\xe3\xb8qZQ\x8e\xe7\x8at/\x10\x8bB\x16\x9aLWa$uC@\xf9\x8b\x96YS\xe9h\xe7wQ\xc6\v\xc6\u007fBɪ\xc9\xd1:\xc1\xfa\x153Y\x98\xd6\xf8\x86I\x94]Ѣ\x94p\x06\xd3x:\x9d\x1e\xb7kk\x1bG\x05\xa2V\x9a\x803PY\xfb\xe15\x11\x8aM\xceY\xba\x86'\x01\x1c\x81\x05}\xb8\xba\x8c\xe2\fӥ\\)\xd86b\xab\xe4\xaa\xfe\xdbCJ\x10\xc5\x05\xc7\x05\xa6i\x18\xfc_\x8b\xfdTr \xe9l\xe8\xeb\x01G\x10\f\xcfڴ\x86>=;E\x9ae\xa1\xcbޱ\xc0\x88'\xabqF\xe8\xe7!\xc8u\x81m\x0fIQ\xf2yx\xb6\t|:Ag\xa7\x13\x99\xf6\xe2;,\x8d\xa15\xe3\x81L\xe2P\xaew\xa5\xdc\xcav:\x91\xfc,\x88Z\xadU\x99\xbf\xcb\xd9g y\xe0\xb8\xf8x\xba\xe1\xe4==\ng\x06\t\t\x19V\xebvh7mn\xc4\xf8\x014\xe8\xfa\xfd\xb1\xde\xfa\xf9\xd3\xe9'*9\xc1}S\xc8\xf6nN\x1bL%_\xfb\xa3\xd2e\xa0D\xd9\xc0\x1f\xa2\x9d\xf8\x9aa,\x15A\xbd\xa1\n\x95[\xac\x19*=v\x1b\xf4\b\x82\xc8\xf3\x8d\xe3\x97\nE\xcf7-2\xd6Ip\xcb|\xb3388jȕ\bx\x92\xac\x10\xa1W\x97MZ\xd7낡\xd2}$\x8d:\xa7\xe9\x1eP>J\xaf\xfb\x9a\xb1\xed\xab^\xf0\x9e3\xb51\xd7\xfb\xd3=\xb53\xae\xd1\xff\xaeT\xa3BDR\xf20P\xd3\\\xd5u\xba/خg\x8f\x9a8I\x98\x90\x1d&\xfc\xe9\xe5K&\xe4\xde:z0\x1eB\u007f\xf0we\xd2\xdd\xe1֟F\xdd$\xea+ؕDOe\xaa\xa9[\xe6\x1d\xeeΫ\x9a\xb6Ϊ\xbe\xa4-Y\xb5\x12h#c\x0fA\x9ar\x85Q\xeaJ\xb2Q\t{J3\x9eq\x01\x8c_z\x92kWj\xed\x98\xc0\xbf;\xaf\ue0f3;\xa9\xee̡\x83Μ\xe7\xe4;\xbb6n\xc9x\a\xac!u\xca3\x95\xf3d\x02\xcfA\xed(\t\xe6\x02\b\x85\v$\x93\xd5\xc6\x01^u\x94\xe4\x94\xd2sE\xf8\xabSO\xe7kC6r\x0fEG\tˋ\fW\x10#s\x1c\x96\xb0\x92\xcaQ\xb2B\x94\xe2\xec\xb5V\xec\xe0»\x12\a\xba\xc0\xfe8\xbd\x8d\xcdgݙy}\xc7^\x9f\xd2\xc8\xeb~\xe6u/p*l\xc7\xf3\xdbx\x81S݊J\xb7\x15\x95\xa9=\xf6\x13\xc5+r\x87m\xcf\x0f\xb7\xd6ʃ\xd6\xf1\xdf\x02\xa7z\xc8A\x14ߡ,T\"\xa2\x16\t*=\x12%ϖ\xab\xed\x93gm\x88+*\xc3\xca\x02\r\x14e)\xaeKj\x05Ӑ\x18\xb3\xf8\xc7\xd55R\xe6\x02\x19\x97\xbf\xe7lɱ\x10\x17\x88+\x1d\xd74yE\xb8\x8e\xaa\xb8\xb0]\xe3\x1cK̃\x91\xaf\xe1ȓb \v\xccU$\xeb\x13r\x9b\xe2}Uf\xeeb\xdaP\x1fO\xa7]g\x87\rA艞x\x92\xe1\xfb\x9a\xdfey\x83\xe4*^d\x8c\xf1\xd06F\x83fn>\r\x87\xdb\x06\xbb\xd92V\xb3qh'e%\xe5\b\x82\xbf\xc0\xf5\x9a&8\x05=O}\x1f\xaae\x88-@uxf\xb0s\xd3\xee+;\x1d\xdaĿ?\xb1\\o6\x01\xbeݡ\xd78a4\xed\xf6\xa8?k\xfb]j1\xfe\\\xc7֠\xa1\xaf\xc7n\xff֜\x9b^6]]\xbe\xee\xb3\xc3~ζܛ.\xf7\xfd\xd3\xebs\xd7\xe5pID\x91!\x93Q\xe1\xa5ɏ`s\x8a%I\x18\x15,\xc3qƖa\xa0H\xc0$п\x05\xa3:\x1f\xf5\x9e\x8c\xb8A@\xd2z\xe6\x8e G\x0f\xd5\x11c\x98\xa3\a\xcfq\x9b\xd3m\xa2\xc9\x1b\xfb?\rI\x1a\xc5\xf7$\x95\xab08\x9eN\xffbV\x18\u05f9\x87\x81Xje\xd4\xeaDq\xa0o\xbd\n\x8c\xb9\xaa_\xb0\xca\xc6\x1f\x83\xe0V/a\xcf\xec\x12ֿ\x825\x17\x1fZ\x9f\x8d\xc5K\xe1\xea\xe5W\x8cԯ\"\x18\x81\xb7\x1e}@\xf7ۖ$\xd5]\xaf\b\xef(\xae\x17\xa5\xba\xb1^\x8a\x9a\xf3X%N)\xf5\xd2\xe6}\x1dU\x8a֮\"MX\x18ͪ\x19feT+\xba?\xc7ꛨ*\xcaUE\xa4J4\xb6h\x94\x9bAP\xd2\x14/\bũSۛ\x9c\xa3\xc6_\x15\x10\v\xc6\xf4O5\x17tǗ\x12eD\xae\xeb*dj\xeb\xaf\xd6D>\x1ci\xc1x\x8e䯦Qo'\x95i\xec\xe7\xf3\xbbe\xe4\x9e\x00\xf5\x02\x97\x85\x8fw\xb1\x96X\xd4\x06ӟ\xae1\x95ڞ\xa3\xca\x1e\xf1\x1b,\x04Z\x9a\xae\xfd\xe4\xa4\xec\x9e\xee\x94\xf4\x01'\x98\xdc\xe1\xb4GZ\xd5\x1d\xb99I9\x1b\xcd3\f\x17,]\xbb\x0e\xef\xf6vKMS\xf0\xe9r\x0f{{\xecȭm\xbd\x1b\x84\xaahݬL7\"\xa9c\xee\xd5\xf1\t莑\x14V%M\xb9*\xa0\xd8\x02(\xbe\x87\x95\xcc3\xc0\x19\xce1\x95\xc2N\xc5TMR\x04_J\x92|\x06Q :\x02\"\xe1\x9ed\x19\xcc1d$'\x12\xa7\xb1\x86\xa6\xf8^\xcf\xdaz}Y0\x0e\xa1\xbe.Q z1t\x16\x0f\xcca\xa6\x1b?j\x92[\xa7\xc3\xe8\x1d\x17\xa5P\xc9\x05s\x1d]\xee\xa1f\xb5\xeb\x84#M\xbfu\xaf\x9f0\xaa\xd6[E\xf6\x92Q\x8a\xb5\x8d\a\x1b\xfbl\x1fjA\xd4.\xf0\t)\xecJ\xae\xf7\xaf\xbe*\xde\x0ed\x1b\x846[\x15\u007f\t\xa3\x1a\xe2<MUj\x8f\xf6İj\xb44\x98L\xe0\x9f(\xb3W\xb6\xbb@R\"\x123\xfez\x9b\u007f\xa7\x98\x83Qk`\x83\x1dp\xac\xa4)2\x81:\xd8\xdc+\xed2he\r{\xd0@d\x86\x03m]e\x99\xc6Ao\x99ĭ\xe3Y\x1b\x990\xdb\xc7\xda\xee\xd1\xd8=IV\x86k\x85\xc4Xjk\xea\x98\v-dt\x02\xfe\x98c\xc9Xf\b\xf1\x97P\xf1G\xb1\x9a\x1da\x97\x92'08\xd8\x0e\xd6\x13*\xa17\xf1\xe5\x83\xeb\xb5n\xdf(k\xe3uB\x1d\x14..b\x1d\xb9mȁ\u007fz\xeeN2\x9c\xaa\xec\xa7_\nD\xf0\x9b\x92\xfd\x16\x9b'3*\x85\xa9\x9f\x98\xa6\x9b'4N\x0e\xb7g2Z\x9f\r5;r\xa1\xb7c\xdf\xd3\x0f\xfe\xda\xe6y\xc2Y\xd1\xf6\xf5\xc2&\xda\x06P\x87\x0f\xc8\"\xfc\xaeK\xc5\xfa\xba\xd8_mېQ\x14m\x9et\xb5\xa0ܛ\xdfh\x17q}\x95\xbcCn\x8f\xe1\xf7\xb4\xbc\xc0\xf5\xf69r\x97K\xf8\xfa\x15\xf6\xe3\xaa\x1cU\x17\n\xfb\xba\xc9\x01i\xf1\x1f4C\x84s=\xeb\x96\x15\x1e\xe6H\xab\xd8Y\xb3\xec\x1f\xa5\xdc\x16\x1f\x87۫\xcd\xe9٬\xaei\xf6\xb4[\v\xac\x03\xe7 \xfb9p\xfd6\xac\xb0};\xb6\xaa\xb1Cl\x993\x95\xf1;\xf3o\xabF\xb8!9~\xa5u\x92\xfbۨ\x1b~;\xf2AV\xdb\x14`\xf7\xb7[$l\x18i\xe3\x84ܩ\xdd\xea_\x8f\xe0سi\xddq\nϦ6\xa7_-\x80\xdda\x0eϦ\xba\x82Ի\xb1\x110\x9a\xad!c(\x85g\xd3\x18\xfeW\x15\x8bK,\x81\xe3\x1c\x11J\xe8\x12(~\x90P !\xe2\xf6e\x82\xad\x8d^q\x96߰\xe2F\xbfFr\x17\x92\xaeS\xdf\xcd5c\xaf\xeb\xd0ڶ[oC59)\x86g\xa7\xaa\xb0\x80\x14I4\xb6\xd5\x01$*MΆ\xb6\xaa\x00Ɋ!\xe8\x8af6\x1c\x9e\xbdf(%t\x19\xc7\xf1\xe9D\xb1n\xbd\x135\xe7\xf7\x95S\x87\xbbi\x9d\xa5f\x0f\xeaV\xd0\xec\xc1\xa1\x92\xdb\x10t\x818\x1b\x8e\x8f\xa7{\xb0T\xf3y\u007f\xb6ꢢ)M\x87\x95M祔\x8c\x82$t\r(\xc3\\\x0e\xcf.k\xaa\xdeۉ\xaeK\x86m\xf7ꛑ\x83\x8a\xff\x04\xce\u007f\x02\xa7\xaf\xa8y\xf47\xff/3\x8chY\xc0\aVJB\xf1\xe0wl\xf1U\xf1\xe7m\U0007bdcdjÒde\x8aE\x18\xd8\xf8\bܺO\xc1؇\xa8\"l\xb6\xd0#\xe8ƮV\xbd\xc8˨;\x8e\x1a\xfa\xd6\x0e\xb2\b\xf7\x19\x81>\xc8jb;\x0e\x0e9\xe6\u061c\xc3{\x88\xf4\xa5m\x0edP\x9f\xa0<F`\xef\xd7\xce\xd3\x142\"$\xa6j\xa5\x93\f\x9a\x10\x03\x13Z\xfa\xa5\xb1\xcd\x16\x8c\x86Ü\x95\x02\x97\xc5p\xe4\xf8\x1d\xdc\xddvsWf\x170\xef\x15\xa5C\xe7\x8f\xc9ݢG\xad\x03\xce\xdd\x17n\xf6\x95ǹ~\xad\xafM\x9fbJ\xbc\x03Ī\xc6PtWi\xcf9A\xfd\xac4%\x02\xcd3\x05p\x00\xbbqå\x95<\xe8t\xe5\x1fT\xe3\x10EΥ\xc4y!\x9bo\x02<ڣ\xf7h0\xb8f\\V\xe5\x86y\a\xdf]\x8a\x98\xbe\xc9\x04\x14\x03\xa1\xcb\xeaW\x98\xaf\xe1\xb2\xe4\xfa`dP%\x81qj[ڱ\x02NL\xe8oZ\x84A,\f\xe0\x98\xe4\xcb\xee\a\xbdd\x11\xbaZ\x1aU\xdc/Bx\"\xc7\nςm{\xf7\xdc\xcbd\x02P\xf0$\x18\x05$_N\xca\".\xaao?\xb4\x9f+\xffk%\xa7\xec\x9e6\xb2U2F\x9c\xa3\xb5=\t\xeaʶK,u\xfa\xb8C\xd9\xf9\x0e\xd2\u07ba\xda`8\u0096*)\xa0L\xf9 \xacԾ\x12\xaf\xb1\x107+DG\x86nT\xcbԼ\x9bR\x03{\x94\x84j\x9a\x9e@k|\xad\x03ԉ\xb3\xab\xf7M\x84\xe9d\xfe\xadb\x8b\x14\a\xf9\xd6'?8\x9e\xfeTi\xdf\"\x86\x9a\xf5gk\xec\x90\xe2\x0fGM+ \xae1\x95MH\u0603\x89o\x15\x14J\xdcA\x8ej3\x1c\x1c\x18\u007f\xba\xc4o\x11\x1c\xd6+[##\x17\xcb?\x1c\x1a\xbf#\x9fT\xc7)M\b9g3\xdf*\x8c*\x91\a9\xb6\x8b\xe9\xe0p\xfa\x97I\xfe\x16a\xe5x\xea\xdf&\xb4\xea\xeb|W\x81\xcc\xca~e;]\x1dJl\xb4\xa8\x1e5\xf4\x87\x8bW\xa6U\xdfC\xf4ܺYș:O\x8f\fS\x989\x02\xe3\xfa\xb5Ƃq{U9\x83\xe9\x89\xf9.\x1b\x9cVL\xb6\xe1\xe8\xa8RC\xe6\xc5?\xf5W!\x1b,\xf7\x1aS\xe6\x05\xcc\xcc\xf8\xaa\xe6\xaa*\xef\x1f\x9aQBU\xf4F\xfa\x18\x8eO\xe0\x13\x9c\xc1\xf8\x18\xfe\xfaW\xf8\xaem\xc0Б\xfd\xe96&\x94b~\x83\x1f\xe4\xc8j״D'\xf0i<v\xf7z.\xeb\xd1\xf1\xad?\x90O\xb75\x1drI\x90\xdf\xfb\xd8U\xcfo\x1d¿\xe9\b\x8co6\x01\x8d\x12\x83\r\x14\x99\x176\xa6̭\xbb\xe9\xf5\x1e\xf0x\xb3-D#\x98ױm\x9fw \xfdj[H\xaev#\xf0\xf5+\xd8\xf6\xb9\xdb^\r\xd8ʙZ\xb1d\x11\xa2\xf6\x1d\xc0\xbc\xeb́ǧ\x12\xc3u\x91\x11\xa9\f\x11\v\xf5[\x18\x84:7\xe9\xef\xfb\xa9f\xdd\xff\xf1\xf8\xb6\xea\x06\xd3mb=a\xf4\x0e\xab\xe85g\xf4\x9a\xe9\xe3\xf4vd\xd8?\x1e\xdf\xea,2\xafd\xcc}\x19s+c\xde-c\xde)c^˘\xbb2\x94\x01\x14\xfd\xa9fk\x8d\xf6\xd8w\xcet\xe0y\xc6-\xbe\xfe4ǌ+\x99\xb5]́\xc3\xdc\xfd\xa8\xbaM\x02BMޙ\x9b\x96yӢƦ\x1aOu_\xe7ت|es\x15\x9cj\xe0\x13 GG\xf6d\x80,·e>\xc7<\x9c\u007f$\xb7\xe6\xec\xe5-z\x1b\xb4\x9f\x1e\xd9#\xfe\xe6+ܖ\v\xf9\\-\xa6\xa9;o\xdaL\xa7\xe0J>L\xe0\x99\xcf\xdb#־0\xab]\xba\xb9\x17s\x1c\x8b\xaeq\xe2ƕy\x01(B\xa4\xfd\xd3\xd39\x8f\x06\xed\xd7{h\xa4\xa1F\xc1\xd7`4\x1fiN[\xdb\x18\t3\x95\xe3\xd4<\xac?\xf5\xc5H\xc5r:3(-\x0f\xeb':v\xd9rske\x04\xd5\xff\xaaZ\xf8<;l\fC\x92\x1c\xb7\xc3[\xb5\xed\x13\xc9\xe6\x8f_h\x1c\x95\xf2H\x8e\x9d\xf9zb m\u007f\x95yN\xe1Y7\xda\x00\xec]\x91\\a\x8e\x81\b@0\x85\x9c\xd0ɊORU\x03\x10\tb\xc5\xca,\x05!\xf5u\x11\xc7Hbn\x18\xe5\nQ\xc8\xd8=\xe6\x90b\xcarB\xb5\xbbc8OSB\x97p\f\t\xbb\xd3\xc7u+\fSH\x90\xb6\x8dU\xee\xe3\xf4\xf6\xe8\xc8SW\xa5\x9e\xe64U\xe0\xa4\xf9{\x04V\xed\x86\x15\xbe7S\xae\x0e\xd7N\x8c\x9c\xd0\xed\x18/\xa6\xbbAV|;\xc6\xf3\x17\xd3=PR\xb4\xde\x0e\xf3_/~08ݡc\xd2.ճp\x04&F\xea\x102\x1f\x1di\xbf\\l\b3\xac\xe6\xa5hK\xdf6\xf7\x9b\x1d\xdc;\x01\xfe\xbe\x1f@{\xa4\xe6\x94|\x85\xd6B\xa2\xe4\xf3\b(\xc6iV\x97a*\xf0Uf\xad\xfamt\x9b\xef\x89ܯH\x86!$^-B\x16P\xa3}$\xb70\x9b\xcdZ\x98\xe0\xe51U\xf4\xd5_;q\xaf\x14l\xbf.kO<\xad\x97X^\xbd\xd7OO\xf9:D\xf6\xed\xd8o\x03\x98|\x0fOU\xdd\xff\x8f\xebwo\xc3\xe1J\xca\xe2o\x93\t)\b]\xb0\x98\xb0\xc9\x10\x8e\xc0R\xc3\x11\f\xdd\xfd[\x8a$\xb2\t\xd6Ms\xaa9N\x8c \xf7\xaf\xa5@pu\xfd^?\x95\xd6\x14\x8c/\xf5\x03xx\xc7ɒЦò\x9a\xd7\xf1\xfat\xf5\xfbI\xf5\xb7;\x84\x9a\xac\xf2\x9eAƖDH\x92\xd4ڈf\xa4\xfe\xa3\x93/\xee\x03\x1c\xb2\xa8>\xc3\xd9\xcc\xfd\x16P\xa5\"G\xf4\xf3x\xa9\xff\"\x86\x178\x0e\xd7\xf8\xc7\x1e.\x96\xa5AO\xca5\x14\x1c\x1b\x02\xcf/\ue6c5\xb9\xfaw\x04\xb9}\xa3\xd0\xfc\x11\bݡք\xfa\x19\xafZ(*:\xaf\xa3\xad\xdb\x14\xc2)\xfc27\xa6T\v\x16\xcc\xea%R\xa3N\xe0\x18\x1f=\x8fb\xc9^\x91\a\x9c\x86\xc7Q%\x14N]\x13)ƹ~\xd7\xfe˅g\x1c\b]\xa4\x17\xd1&ۦ\xbc\x17-y.\xfc\x9b\x8b\r3\xf6\xc1\xfc\xf7\x16\x98\xbf_TC\xceaV\xdbʎ-7_\x02\xab\xb5\xcc\x1b\xf8ڪ\x13Cі\xa0ь\x1d\x02\xbfNԭ:\x90\xe76z\x1f\xff?\x00\x00\xff\xffy\xcd;2KJ\x00\x00",
-		hash:  "0869984dd0709d9f41ea5b5698ba8f90842d92dc74e3df452dee73ea597fa817",
+		data:  "\x1f\x8b\b\x00\x00\tn\x88\x02\xff\xec|[s\x1b7\xb2\xf0\xfb\xfc\x8a\xceػ\x9cY\x91C\xcaN\\\xdf\x17\x89\xaa\xb2\xacx\xa3\x93\xd8q,\x9f=\x0f>z\x00g@\x12\xf6\x10\x18\x03\x18\xc9,G\xff\xfd\x14.3\x03̅\x97\\\\\xfb\xb0\xa9\x8a%\x02}Cw\xa3\xd1h4u\x878\xa4%\xe7\x98\xca\x1f1Y\xad%\xcca\x16\xa8\xd1\x1c\xa3\fsg0\x10X^S\x89\xf9\x1dʣ\xb2Ȑ\xc4?\xbe{\xf5\xf3\xf8\xe9l6\x8b\xcf4\x8e\xc0\xfc\x0e\xf3_hN(\x869,Q.p0\x9d\xc2\x7f\v\x9c\x81d`\xb0@\xb0\r\x06\xb9&t% \xc7B\xc0\x92\xe3O%\xa62\xdf\x1a2\x1fIQq\xaa\xc9\x04\x8f\xa3{B3v\x1f'9CY\x14\x00\x00,K\x9aJ\xc2h\x14\xc3\x17=\x00\xd0H\x16\xc5vH`\xf9\x8el0+eT!\x80\x831\x88\xf70\x86S\xb38\xfd)\x88ς\xa0&\xe0\xc2kR\x8f\x13\xf4\x01}\x8eF\xc9t4\xb6\xb4E\x99\xa6X\x88\xef\x1d9\xbf\xd42y\xaa\x92\xbcĆ\xcbX\xff\xc0\x9c3~\x00\x9eэ\x11\x0f\xe0AI\b@\x96\x10}\xe3\x02Vk}\x1c\x85\x8f\xcc\xf8DH$K\x11Ɖğe\x14\xbeD\xa9d\x9b\f^3\toKJ\t]\x85F\r\x1c˒SE\x1cp.\xf0\xc1\x94\\*\x0f\x95T\n\x8d\xd0\f\x7f\xa6\xe8n\xb2A\x84\x86q\xb2F\xe2E\x8e\x84\x88B\"&(\x95\xe4\x0e\x87q%\xf1t\n\xaf\x10\xa1\xf0\x0e-\x02\xc7J\xda+\xa3\x18\x9c\xb1\xe7y\xfe\x06c.\xac\xf5\xa6S\xb8bX\x00\xbe\xc3|\v\x882\xb9\xc6\x1c\xd2m\x9a\x1bu\x91e\xf4\x8d\xebg\xb1\xef?\xef8\xa2\x02i\u074bƏ|\xbfll\xe6j\x06\xfaݷ6\x91\x81%˖.\x18\xc7\a\xe8\xe2\nKDr\x9c\xf9\xfa@W\xea\xffrS\x18Q\x1f\x82\xe0!\xd0\xdbN/\x05\xeeט\xc2=\x06qOd\xba\x06\x89\x16\"x\x1c\x85\x89\xfae\x922*9\xcb'\x05\xa28\x87\x9c\x00\n\xe3$\xcdI\xfa1\xf2\x9d\xcf\xd9D\x81\xb7\xf1\x02\x80/\x8d,\xcd\x0ez\x18\xc3\xd3\xd9,\x0e\x1eb\xb5w\xc3GY\xb9)4;D(\xe6\xf0hY\xe6\xb9H9\xc6t\xc2\nE\xabf\xdcr{\xf9Y>\xe7\x18\xc1\x1c>\xfcZb\xbe\x8d䚈8\x11d\x91\xab\x10\x12\x85\x89\xa3\xac\x06>\x91l\xb5ʱ\xd5g\xc3M\xc3x\x94<@\xb4\x10,/%\x9e\xf4ȷ\x13qI>\xe3\xac\x17Ki@\xd9\xe3\x1d+\xb4\xf6\x81QЖ\x0f:\xfb\x01.z\r\x00_\xec\x06\xf2\xd8\xef\xf0\x96\xcef\x95\x8eC\x87q\xc2\xf1\x86\xddU\x92\xafI\x86ø\x06\xcdY\x8a\xf2=0\x99\xf5\xb80NP\x96\xb5a\x1ej\xa3{\x0e\xfe\xb5\x16\xd7#\x91\xbf\xb2A\x00gY\xfd\xab7+\xf3\x0f\x01w\xfbi\xa18\x16\x05\xcc\xe1\x93Z͍D\x12GaMx\fa8\xae\u05ee m\xe4a\x8b\x0f0\x87\xff\xba\xf9\xe5uR .\xb0\x99k$+7\xc5)\xe8\x1f7k\xc6e\x15o\xd9\xe2CR\xf1?M\xf4\x94\xfa\xb5\x17\xf1-\xba\xefG{\x8b\xee\r\x92\x87\xf5d'֓\x01\xac\xa7;\xb1\x9e\x0e`}k\xb0\x9e\x97r݇\xf6m\xa2f\x18'\x92`\x11\xf7a^g\x98\xca~T=5\x8c\xf9j\xfb\x9ae\xb8\x1f\xd5̵d\xfd\xce\xe0\xbd`t`\x91\xdf5\x8b\xec\xc1\xbb\x19\xb0\xdew\x89\x9a\xc1Y\x85\xf8\x10\xab0\xde\xca7\xfcsi\xc8\xdb8N1\x95.l8>\xde\xed\xa6S{J]]^\xe6,\xfdhN\xddJ\xf4\x18\xbe\x99\x83\x96\x9fp\x9cJƷ\x1a(\xb9\xba4pM~eH\xfc\x84\xb7\xaf\xde\xda\bЬ\xdd\xc7\xd50\xb1\x87vɲ\xad\x1eށV\xc3\xf8\xa8/\xcb<\xff\x11\x89\xf5\x0e\xcc\n\xa4\xc5Sͩ\xc3NH\xb4)v\xa0\xd70=\xf8\xbe\xb6v)\xca\xc15\x86\x9bd\x15\xe4d\xa1@\x0f\"b\xa9\x90\xa5\x06\xd3Y\x18\xc9\\\x17P\xf6\xa2e\xded:\x00\x03\x90ɒ\xf1\x1fP\xbanb\xb4\x0e\xb0~\xc6L\x96f4y\xc7$ʯiQJ\xb8\x80Y2\x9b\xcdN}H\xa8\xb2\x9d\x02Q\xcbM\xc0\x05\xa8\xa8\xfd\xf9g\"\x14\x9a\\\xb0l\v\x8fB8\x01K\xf4\xf3\xf5U\x9c䘮\xe4Z\x91mSl\xa5\\\xd5\x7f\ap\t\xe3\xa4\xe0\xb8\xc04\x8b\xc2\xffm\xa1\x9fK\x0e$\x9b\x8f|9\xe0\x04\xc2\xd1E\x1b\xd6\xc0g\x17\xe7H\xa3,u\xda;\x11\x18\xf1t=\xc9\t\xfd8\x02\xb9-\xb0\x9d!\x19J?\x8e.\xba\x84ϧ\xe8\xe2|*\xb3A\xfa\x0eJ\xa3h\x8dx$\x928\x16\xeb\x97R\xeeD;\x9fJ~\x11ƭ\xd1*\xcd\xdfg\xec\v\x90<tL|:\xeb\x18\xf9@\x8b\u0085\xa1\x84\x84\x8c\xaas;\xb2\x97\xb6\xe6\xbf\a\xf0\x1d(\xe8\xfb\xfd\xa1\xbe\xfa\xf9\xdb\xe9\a*9\xc1C[\xc8\xcev\xb7\r\xa6\x92o\xfdU\xe94P\xa2<\xf0\x97h7\xbeF\x98H\x05P_\xa8\"e\x16\xab\x86J\x8e\xfd\n=\x810\xf6l\xe3إ\xa2\xa2\xf7\x9bf\x99\xe8 \xb8c\xbf\xd9\x1d\x1c\x9e4\xe0\x8a\x05<J\u05c8\xd0\xeb+@\u07b9`\xa0\xf4\x1c\xc9\xe2\xdemz\x00)\x9fʠ\xf9\x9a\xb5\x1d*^\xf8\x863u1\xd7\xf7\xd3\x03\xa53\xa6\xd1\xff\xaeՠ\xa2\x88\xa4\xe4Q\xa8\xb6\xb9\xca\xeb\xf4\\\xb8[\xce\x011q\x9a2!{T\xf8Ë\x17Lȃe\xf4\xc8x\x14\x86\x9d\xbf/\x92\xeew\xb7\xe10\xea\x06Q_\xc0\xbe z.3\r\xddR\xefh\x7f\\հuT\xf59툪\x15C\xeb\x19\a0Ґk\x8c2\x97\x93\xf5J8\x90\x9b\xb1\x8cK\xc0\xd8e \xb8\xf6\x85֞\r\xfc\xbb\xe3\xea!t\xf6\aս14\xe8\x8dyN\xbc\xb3g㎈w\xc4\x19R\x87<\x939O\xa7\xf0\x14ԍ\x92`.\x80P\xb8D2]w\nxU)\xc9I\xa5\x17\n\xf0W'\x9f\xdel\r\xd8\xd8-\x8a\x8eS\xb6)r\\\x91\x18\x9brX\xcaJ*\xc7\xe9\x1aQ\x8a\xf3\x9f\xb5`c!\x11\x97e\xf1\x86\xb3\x15\xc7B\x8cź\x94\x19\xbb\xa7\xd5\xc0љy%\x0f\xe8\f\xfc\xfd\xec61\x9f\xf5d\xee͝zsJdo\xfa\x897\xbdę\xb0\x13Oo\x93%\xce\xf4(*\xddQTf\xb6.(\x8a\x97\xe4\x0eۙoo\xad\x19\x9cRЍY\xb7:A\xdf\x7fw\x1b\xbb3V\x03z\xea٭s\xc12Z\\\xe2L+2\x8c\x13UmVr\xc5-\x10Tz JHK\xa6]\xcf\xd6ڻ\xa62\xaa\xd4\u0590\xa2,\xc3u\xa2\xae\xc84 F\x97~\x11\xbc\xa6\x94\xbb\x84̒*c^\"\xaed\xdc\xd2\xf4%\xe1\xdaW\x93\xc2NM6Xb\x1e\x8e}\t\xc7\x1e\x17C\xb2\xc0\\\xed\x0f]w\xb7\a\x87/\xca\xdc=\xa2\x1b\xe8\xd3٬\xaf\"\xd9\x00D\x1e\xeb\xa9\xc7\x19\xfeQ\xe3\xbb(\xaf\x90\\'˜1\x1e\xd9\xc18hv\xfc\xe3h\xb4k\xb1ݑ\x89\xda\xe3#\xbb\xd5+.'\x10\xfe\rn\xb64\xc5\x19\xe8\xdd\xef\xdb\xf0\x04B`KP\x13\x9e\x1a쎷\xb7\xd5^\x836\x9b\xc6߮\xae5\x9b]\xb1۠78e4뷨\x1f\v\x86Mji\xfc\xb9\x86\xad\x89F\xbe\x1c\xfb\xed[cv\xadl\xa6\xfal=\xa4\x87Ìm\xb1\xbb&\xf7\xed3hs\xd7\xe4pED\x91#\x13\xa7ᅉ\xba\xe0\x04\xa2\xe9\x14RF\x05\xcbq\x92\xb3U\x14*\x100a\xf9\xfbp\\\a1\xefԸY\xb3{\x01r\x8d\xa1X#\x81\x95\x14\v\xc6$\xa1+(\xa9$\xb9\x9eR\x8e\x06D\x9f+J%\xedc\xa5\n}6\xf4W5\xce\xeasb(\xcf\xe7\x10\x86\xf0\xdboP\rW:\xf0\x1el̜z\x1d \x19\x8eگ2A\x1bJ\xacٽ\x86\xeauc\xff(\xea\xf5\xe5ZFc/\xfd\b\xa6\xe8a\x89@\t\xec\xafD\x8d^8^k\xa1ƀ\x16\xac\x94\xda~.\xe8\t\x84\x02r\xbc\x94a-\xfch\xbfT{<+|\xa3\xd5\xe9\xf2\xd2\n\xd6\xd5gד\xbcY}\xb3\xfd\xbe;\xae\x11Ԩ\xb3'Z:\x89\xb5\xfb*\x18,Q\xd7o\x84ąb\xa9\x0ez\xed7\xea\xac\x1b\x03\xa3)n\x9cg\x8d\x04,\xf0\xaa\xa4 Y\xc7}\xaa\xf3\xb1J\x15\x1a\a\xb2\x03\x89\xe6\xa1\x1d\xc8s\x16;\xbd\xcf[\x1a\xb0\xdd\xee\xd2\xcaT\xfa\xfd\xc5\x15I\xab\xbc5\xa6\xcfne(\xe5\x187Jn\xad\xf3\x0e\x9ag)\x8f\x80c)w\\\r\x8f\xfda\x9c\xa3B`\x1d^D\xd8R\x99~T\xad\xcbXZ\xa0\x939\x84&\xfc\xb4\xa0\x92\x0f\x8c\xd0(<\x8306ǌ\xeb\xae{\xb5\xb2\xc7_\xd5?}\xd5]W\xf9$\xab\xf3\x841l\xd0\xe7\xda\x036\xe8\xb3wLt\x0f\xf7\xa9\x06o\xa2\xfd\xe3\x88dqrO2\xb9\x8e\xc2\xd3\xd9\xeco&Kv\x8f\x92\xe3\x88Xh\xbd\a\xec\xabH\xa0_\xee\v\x8c\xb9\xba\x83a\x01sx\x1f\x86\xb7zc<\xb1i\xf8p\x16\xde<\xdejy:\t\xb8\xa2\xab\xaf\x10b\xac~\x15\xe1\x18\xbc\x94\xf9-\xbaߕ5\xab\xe9:i\xfd\x85\xe2:o\xae\a\xebl\xb9ySR\xec\x94P/l\x96\xa9-\xa7`m\xa2\xdb\x1cBF\xb2\xea<\xb7<,T\xebD\xafwbu\xa6\xaa[\x9d\xbaf\xb2e#\xdc\x1c\u0092fxI(\xce\xc2\x06\xd7d8j\xfd\xd5%hɘ\xfe\xa9\xfcMO|*QN䶾I\xcd\xec\x1d\xb2\x956\x1cOi\xc9\xf8\x06\xc9_͠.\x89)\xd5\xd8\xcf\xcf\xefV\xb1[\xc5\x1e$\\\x16>\xbd˭ĢV\x98\xfet\xa3\x1e.\x94>Ǖ>\x92WX\b\xb42S\x87\xf1\xb1\xa1m7\xa7\xb78\xc5\xe4\x0eg\x03ܪ\xe9\xd8̀\x94\xb1\xd1\"Ǡ\x9e\x1c\\\x83\xf7[\xbb%\xa6\xb9\xb4\xea++\xf6\xea\x84N=\xb1\xf5\xc2\\]\xbc\xbb\xb7\xeb\x8e'\xf5\xec\xbd\xda?\x01\xdd1\x92\xc1\xba\xa4\x19Ǚ\x00\xb6\x04\x8a\xefa-79\xe0\x1co0\x95\xc2n\xc5\f\b\x05\x04\x9fJ\x92~\x04Q :\x06\"\xe1\x9e\xe49,0\xe4dC$\xce\x12M\x9a\xe2{\xbdk\xeblv\xc98D&\x94\x13\xaa5\xea沘\xc3\\\x0f\xbe\xd7 \xb7΄\x91;)J\xa1\x82\v\xe6\xc9\x1b;\x18\a~\xe5\fN4\xfc\xceze\xca(\xcc\r\xd8\vF)\xd6:\x0e:\xb5B\x9fԒ\xa8J\xd6#R\xd8{\x83\xae\xc1\xf9\xa2\xc0\x97V\xd1v\x88\x84V[\xe5\x7f)\xa3\x9a\xc4\xf3,S\xa1=>\x90\x86\x15\xa3%\xc1t\n\xffB\xb9m;\xd9G$#\"5\xeb\xafK\x95w\n9\x1c\xb7\x16\x16\xec!\xc7J\x9a!\xe3\xa8A\xb7\u07b3O\xa1\x956\x8c\x04\x92\xc8\x1c\x87Z\xbbJ3\x8d\x81^3\x89[OL\xd63a~\x88\xb6\xdd\xf2\xfe=I\xd7\x06k\x8d\xc4Djmj\x9f\x8b,\xc9\xf8\f\xfc5'\x92\xb1\xdc\x00\xe2O\x91\u008f\x13\xb5;\xa2>!\xcf 8Z\x0f\xd6\x128\xb3\x86\xedр>\xeb\x0e\xf5\xb26\xbd^RG\xb9\x8bK\xb1\xf6\xdc6\xc9\xc0\x7f\x01t7\x19\xce`n\xbb\x9db\xf8\xa2x\xbfƦ\xedO\x850\xf5\x13Ӭ[evb\xb8\xad+ky:b\xf6\xc4B\xaf\xeax\xa0\x1d\xfc\xb3ͳ\x84s\xa2\x1dj\x85.\xb5\x0e\xa1\x1e\x1b\xa8\u07b3>\x11\xeb\x96\x17\xff\xb4m\x93\x8c\xe3\xb8[\xado\x91r\xbbW\xe2}\xc0u;\xcc\x1e\xbe\x03\x8a?P\xf3\x02\xd7ź\xd8=.\xd5\xfd\xf70\xac\xcaPu\xa2p\xa8\x99\x1c\"-\xfc\xa3v\x88pZLܴ£9\xd6\"\xf6\xe6,\x87{)\xb7\xc9\xc7\xf1\xfajcz:\xabs\x9a\x03\xf5\xd6\"\xd6C\xe7(\xfd9\xe4\x86uX\xd1\xf6\xf5\xd8\xcaƎ\xd1冩\x88\xdf\x1b\x7f[9\x82j\xe7x\xa9e\x92\x87먟\xfcn\xcaGi\xad\xcb\xc0V\xd3vp\xe8(\xa9\xf3\xca\xe7\xe4n\xf5\xaf'p\xea鴞8\x87'3\x1bӯ\x97\xc0\xee0\x87'3\x85\xa7\xc5\x15\xaa\xb2\x91oA\xb5eÓY\x02\xff\xa3\x92\xc5\x15\x96\xc0\xb1\xeai$t\x05T]\xb6\v$D\xd2~\x10\xb5\xb9\xd1K\xce6\xefX\xf1NwT\xba\aI\xdf\xcbU\xf7\xcc8\xa8\xa5\xa3\xd6\xedΎ\x0e\rN\x8a\xd1ŹJ,@u\xeeMlv\x00\xa9\n\x93\xf3\x91\xcd*@\xb2b\x04:\xa3\x99\x8fF\x17?3\x94\x11\xbaJ\x92\xe4|\xaaPw\xf6uh.\xb5QG\xfba\x9d\xa3\xe6\x00\xe8\x96\xd3\x1c\x80\xa1\x82\xdb\bt\x828\x1fMNg\a\xa0T\xfb\xf9p\xb4걵IMG\x95N\x17\xa5\x94\x8c\x82$t\v(\xc7\\\x8e.\xaej\xa8\xc1\x17־\x87\xd2]\xbdA]\xcfA\xc5\x7f\x1c\xe7?\x8e3\x94\xd4<\xf8\x97\xff\x179F\xb4,\xe0-+%\xa18\xf8\x1dW|\x95\xfcyW\xfc\xfek\xa3\xba\xb0\xa4y\x99a\x11\x85\xd6?B7\xefSdl3\xbd\x88\x9a+\xf4\x18\xfaiW\xa7^\xecE\xd4=\xa5\x86\xa1\xb3\x83,\xa3CV\xa0\vY\x8do'\xe11e\x8e\xee\x1e>\x80\xa5ϭ\xbb\x90\xa0\xae\xa0<\xc4`\xab\xf6ϳ\fr\"$\xa6\x98\v\x90\f\x1a\x17\x03\xe3Z\xfa\xdb\x126Z0\x1a\x8d6\xac\x14\xb8,Fc\xc7\xee\xe0\u07b6\x9b\xe7|{\x80y\x9d\xe0\x0e\x9c\xbf&\xf7\x8a\x1e\xb7\n\x9c\xfb{\x02l\xa7\xdas\xfd\x8d#\xad\xfa\fS\xe2\x15\x10\xab\x1cC\xc1]g\x03u\x82\xba5>#B\x95\xba\xb20>\x02ݘ\xe1\xcar\x0ezM\xf9\a\xc58F\x90\xe7R\xe2M!\x9bo3=؇\xbe8\bT\xb7u\x95n\x98\xef\xf2\xf4\xa7\"fN\xbd\xef0\xae(U\xbf\xc2b\vW%ׅ\x91\xa0\n\x02\x93̎\xb4}\x05\x1c\x9f\xf8b^a\x12a\bN\xc8f\xd5\xff\xa5\x04\xb2\x8c\\)\x8d(\xees\x8f\xc7r\xa2\xe8Yb\xbb\xbe\xbb1\x88d\x1cP\xf04\x1c\x87d\xb3\x9a\xaa\xf7\xae\xea\x1b\\\xed\xaf\\\xfc\xb5\x9c\xf5CL\xe1~{\fq\x8e\xb60\xafɴ\xa3\xed\nK\x1d>\xeeP\xfe|\x0f\xe8`^mh8\xccV*(\xa0\\\xd9 \xaaľ\x16?c!ޭUaTÍk\x9e\x1a\xb7\xcb5\xb4\xa5$T\xc3\f8Zck\xed\xa0\x8e\x9f]\xbfi<\x8c\x14_ѷHq\x94mI\xb1˪{\xfd\xe9O\xe5\xf65|\xa89\x7fv\xfa\x0e)\xfe\xb0״\x1cB\xd5\x15\x1a\x97\xb0\x85\x89\xaf\xe5\x14\x8a\xddQ\x86j#\x1c\xed\x18\x7f:ǯ\xe1\x1c\xd6*;=c#V\x7f\xd85~G<\xa9\xca)\x8d\v9\xb5\x99\xaf\xe5F\x15ˣ\fۇt\xb4;\xfde\x9c\xbf\x86[9\x96\xfa\xb7q\xad\xcaI<\x01r\xcb\xfb\xa5\x9dte(\xb1\x91\xa2jj\x18v\x17w\xa6\xfe.\xb5g\xd6n\"g\xf2<\xbd2La\xee0l\x9aT\x97\x8cۧ\xca9\xcc\xce\xcc\xf7q\xe1\xbcB\xb2\x03''\x95\x18rS\xfc\v\xe5\x1e-\xf7\x19Sn\n\x98\x03r\x87\xab\xac|xiF\b\x95\xd1\x1b\xee\x138=\x83\x0fp\x01\x93S\xf8\xfb\xdfᛶ\x02#\x87\xf7\x87ۄP\x8a\xf9;\xfcY\x8e\xadt\xcdH|\x06\x1f&\x93\x86\x0f\xb8b\x7f89\xbd\xf5\x17\xf2ᶆC.\b\xf2g\x1f\xfa\xf2\xf9\x9dK\xf87]\x81\xb1M\x97\xa0\x11\"\xe8P\x91\x9b\xc2\xfa\x94yu7\xb3^\x03\x8f\xb7\xdb\"4\x86E\xed۶\xbd\x03\xe9o\x9e\b\xc9\xd5mD\x15\xf1\xed\xf8\xc2\x1d\xaf\x16l\xf9\xcc,[\xb2\x8cP\xfb\r`\xd1\xd7s\xe0\xe1\x05\x00\xe8\xa6ȉT\x8aH\x84\xfaM5\xd7\xc7j\\}\xeb\x14\xe6v^u\x89\xdbi0\xd3\xc6\xd7SF\xef\xb0\xf2^S\xa3\xd7H\xefg\xb7c\x83\xfe\xfe\xd44n/*\x1e\v\x9f\xc7\xc2\xf2X\xf4\xf3X\xf4\xf2X\xd4<\x16.\x0f\xa5\x00\x05\x7f\xae\xd1Z\xab=\xf5\x8d3\v<ː\xe2/0̤\xe2Y\xeb\xd5\x14\x1c\x16\xeeG5m\x02\x10j\xe2\xce\u008c,\x9a\x11\xb565x\xae\xe7z\xd7V\xc5+\x1b\xab\xe0\\\x13>\x03rrb+\x03d\x19\xbd.7\ṿ\xc5{rkj/\xaf\xd1\xeb\xb0\xddz\x04\xa7\xee&n\xb0\x90\x8f\xd5B\x9a\xb9\xfb\xa6\x8dt\x0e.\xe7\xe3\x18^\xf8\xb8\x03lm\x87Ym\xd2\xee]\xcc1,\xba\xc1\xa9\xebW\xa6\xdfXDH\xdbg`r\x11\a\xed^a4֤\xc6\xe1o\xe1x1֘6\xb71\x1c\xe6*Ʃ}X\x7f\x1a\xf2\x91\n\xe5|n\xa8\xb4,\xac[t\xec\xb1\xe5\xc6\xd6J\tj\xfeeu\xf0yz\xe8,C\x92\rn\xbb\xb7\x1a;ē\xcd\x1f\xf0\xd1t`\xae\xb1\x9c\xfdzfH\xda\xf9*\xf2\x9cÓ~j\x01ط\"\xb9\xc6\x1c\x03\x11\x80`\x06\x1bB\xa7k>\xcdT\x0e@$\x885+\xf3\f\x84\xd4\xcfE\x1c#\x89\xb9A\x94kD!g\xf7\x98C\x86)\xdb\x10\xaa͝\xa8b\x1d\xa1+8\x85T=B\x99f\xdb\x19\xa4H\xeb\xc6\n\xf7~v{r≫BOSM\x158\r\xe3\x96\xd8\r\xaa\xeax\xf4\xfeLK/\x8d\r\xa1\xbbi<\x9b\xed'\xb2\xe6\xbbi<}6;\x80J\x86\xb6\xbb\xc9\xfc\xbfg\xdf\xcefîc\xc2.ջp\f\xc6G\x9cvu\xf5\xd1\xe1\xf6\xd3e\x87\x99A5\x9d\xa2-y\xdbد\xf6`\xef%\xf0\xcf\xc3\b\xb4Wj\xaa\xe4k\xb4\x15\x12\xa5\x1f\xc7@1\xce\xf2:\rS\x8eO`\x0eռ\xf5\xee3=y\xbf&9\x86\x88x\xb9\x88z\x1c\xad\xa0ߓ[\x98\xcf\xe7-\x9a\xe0\xc51\x95\xf4\x9d\x05\xd0}R\xb0\xf3:\xad=\xf3\xa4^ay\xfdF\xb7\x9e\xf2m\x84l\xefؗ\x00\xa6\xff\x80\xc7*\xefW5\xe0h\xb4\x96\xb2\xf8~:%\x05\xa1K\x96\x106\x1d\xc1\tXh8\x81\x91{\x7fS\xefQ6\xc0\xbaaN\r'\xa9a\xe4v\x8bCx}\xf3FwFk\b\xc6W\xba\x0f\x1a~\xe1dEh3aQ\x9b&\xe9\xf8\x1f\xd3\xea\xef\x0f\xa9/\u0602\xbcg\x90\xb3\x15\x11\x92\xa4\xb54\xa2Y\xa9\xdft\xf2\xc9m\xc0!\xcb\xea3\\\xcc\xddo2V\"rD?NV\xfa\xaf\xfax\x8e\xe3`M\xbe\x1b\xc0by\x16\x0e\x84\\\x03\xc1\xb1\x01\xf0\xec\xe2\xf6,,Կc\xd8\xd8\x1e\x85Jf0\x13\xeaL\xa8\xdbx\xd5AQ\xc1y\x13m\xd9f\x10\xcd\xe0\xa7E\xddo\xbe\x80y}Dj\xaaS8\xc5'O\xe3D\xb2\x97\xea\x0f\x0eE\xa7q\xc5\x14\xce]\x15)ą\xb2\n\xfct\xe9)\a\"\x97ҳ\xb8\x8b\xd6\xe5\xf7\xac\xc5\xcf%\xff겣\xc6!2\xff\x7f\a\x99\x7f^VK\xde\xc0\xbc֕]\xdb\xc6|\x91\xb5\x96rӐ\xaf aj \xda\x1c45\xa3\x87\xd0\xcf\x13\xf5\xa8v\xe4\x85\xf5އ\xff\x1b\x00\xb2\x1e\xb9!\x0fO\x00\x00",
+		hash:  "fea320c49b4e2af7f79b250aeb0890c1e26f50d54adbaf257266de68c07a1478",
 		mime:  "application/javascript",
-		mtime: time.Unix(1507657100, 0),
-		size:  19019,
+		mtime: time.Unix(1792260377, 0),
+		size:  20239,
 	},
 	"js/factomd-ajax.js": {
 		data:  "\x1f\x8b\b\x00\x00\tn\x88\x02\xff\xdcW]o\xdb6\x14}\u05ef\xb8劅Be)[\xf6\xd4T\r\xd0u[1t\xe9Vw\xc0^i\xe9:b,\x93\nI\xc56V\xff\xf7\x81\x1f\xb2$\xc7N\xe3\x15\xd8\xc3\x1e\x02$\xe2\xe1\xb9_\xe7\x1e)\xf3V\x14\x86K\x01w-\xaa\xcd\xd40\x83\x94\x1b\\&p\xcf\xea\x16\x13\xb0\x80\x18\xfe\x8e\x00\xee\x99\x02\x85w\x90\x83\xc0\x15\xfc\xf5\xdb\xfbw\xc64\x1f\xf1\xaeEmh\x1cE`OS)\x14\xb2r\xa3-SQ1q\x83\x90C\x17\x85z&\x00>\xa7\x16\xec\xa0.(\xe49\xfcН\x02dY!\x85\x965\xa6\xb5\xbcq\t\xc1\v 0\x01\x02/\xc0\xdfԍ\x14\x1a\xe3p\xc1F\xa0\x0f\x0f\xb6\x91\xffq\x995((\xf9\xe5\xa7O$\x01\x92fsV\x18\xb9,\xaf,yni\xbb(ߺ\xcaݣ\xd0\x03\xa3Z\xc7gY4\x8a\x92\xc6\xd16\x8av\xad\x9b1ST\u007f\xec\xf7\xef\xff\u07b87\xb6\xea+W\xfb\xae}\xc7Z\xf5\x9c\x92o\xfc\xb5\x89F\xa6\x8a\x8a\xc4iQ\xf3bA\xf7\n|NI:\x02NP)\xa9H\x9cꚗ\xf8gC\xe1\xe2\xfc\x1c\xe2h\x1b\x1f`\x9d\xe8v\xb6\xe4\xe6\x18\xb9\a\xbdaj\xea`Ա<\x8cXHa\x18\x17h\xa3.p\xd3(Ժ\xa7\xc2~\xa6\v\xdc@\x0e\x98\xae*^T\xf0\xf93\xa0\xc5\xff(K\xbc\x8c준>\xa3\x0e\x93\xc3w\x17q7#\x85\xa6U\"\xb4\xf7`J\xbd\xb2\x1e\x1c\xefb\xaf\x8f\xa9\t`\xfdt)\xad\x8f\vi(\xa3\xf5\x03\xd5\xc8\xd9-\xe4\xf0\xeb\xf4\xc3u\xda0\xa5\xf1\x00\xc4\xd6/g\xb7\xe9\xa7M\xe3\xb8I9\xabe\xb1x\x87\xfc\xa62\xa4\x0f\x04\xb0⢔\xab\xb4\x96\x05sU\xe7@|\xe1W\\4\xadq\xea\xb2L\xbb\x055\x9b\x06sOG\x02\xcb\x16\xb0\xd68\x0e\xfa,\ar-\x05\x9e\x1c\xec\x90\\\xefYM\xe3>z\x97\x93\r\xd4qg\x99\u0092+,\f\xfdj\xce\x04H#\xb5!\t\f:\vY\x06S\xb9DSqq\x03sيr\\~_\xe6\x17\x16\xe9\xad\\\tzq~\x1e\xef.<>\xef\xed\xc8\x14\xac\x00\xe7R-\xdf2Â\x0e\u007f\x0e\u007f\xd2\xd8j\xbf;LY\xd3X\x13 6gYZ\xff\xe8\x8a?\x84\ng\xc9\xf1f9\xb7\\\aG\xfa\xfd\xc34X\x92k\x95\u05fe3\x9d\x8e\xb93\x9f\x99,7$N\xa5\xa0gK\xd9jl\x9b\xb3\x84h\xf4K\xb6\xe7!5\x17\v\x92\xec\xef\xbbq*\x86[g\xf3\xd4T\\\xc7)3FQbO\\\xec\x8a\xe9j\x1fbp\xed\x97\xf2?\xd9\xd9S\xb7\xf2\xe0\x82\xf09\xed,\xcd\x1aWܟ<my\\\x1bF\x9a6\x83\x1d\xe9\x17u\x18\xe5\xfba\x02\xbb0~\xca\xd9\x13#8\xe1\r\xd5\xfaŕ<\xcc\xf3\xef6\x8f\xcf\xc7f\xa7\x1b,8\xab'\xcc\xcdo2głħ\xb9У\x8d\xfc\xfa\x85\x1f\u007f)\x9c\xb2\xf2\xef\xb9X<\xba\xf6\x16\xf0\xb4\xd5\x1f!w\xebo+?\x8aZ\b\xb9\x12$\xf13?\xcd\x0e,\x8f\u007f\xc3f\x19|\f\u0080\x157\x15\xd8+\xd6\x03\r\nӿ\u007fw\xe2iU\x9d\x80\xaf$\xe9`\xfd\xcb\xd8\xcd\fr;\x83W\xee\xf7\xd7d\xe4\x0e\t\x90\x8a\x97%\x8a`c\x1dA\xc0\b\xb6t\x98\xf0\x98\f\xfd\xe29={e\xd3\u007f}\x96\xec\x86\xed\xf3x\xd9\xe5\x13\x9ez\xa5\xbd\x84V\xd5vf\xbe\xfc\xd04\x97ThH\xf8\x92\xb8\x8c\xb6\x97\xd1\xe0SC\xe0\xda\\\xcb\x12\x83\xd5X5@>\xfc\xaf\x80t\b\x92\x90\x81?Z`\x10\xb6u\xed\xa2U\n\x85\x99\bY\xe2D\xb4˙\xfb\x8cr6\xe8\x90>\xb5m\xf4O\x00\x00\x00\xff\xff\xe0\xe4EHx\f\x00\x00",
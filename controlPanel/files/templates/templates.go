@@ -73,11 +73,11 @@ var staticFiles = map[string]*staticFilesFile{
 		size:  383,
 	},
 	"index/localTop.html": {
-		data:  "\x1f\x8b\b\x00\x00\tn\x88\x02\xff\xdcX_\x8f\x1a7\x10\u007fϧp,U:\xa4:\v\xe9\xa9=\x91]Kw\x17\xe5Z\xa9\x89\xa2\x12U\xea\xa3Y\x0f`e\xd7\xdeڳ\x1c\b\xf1\xdd+{w\xe1\x8e\x00\xbbp!\x0f\xbd\x97\x03{\xe673\xbf\xf9g\xb1ZI\x98(\r\x84f&\x15\xd9\x17S\xd0\xf5\xfa\x15\xa9\xffb\a)*\xa3\x89\x92I%@\xf9\xe62\bH5'i&\x9cK\xa85\x8f;\xb7\xbb\x12\xa9\xc9\xca\\\xbb=R\x95\xb1\\d\x19\x8f]!*\x83\x0e\xec\x1c,s(\xb0t\x94Ǒ\xbf\xf1\xff\x82\xdc~\x8cـ8\\f\x90\xd0G%q6\x1c\xf4\xfb?\xbd\xa3\xfc\x1fSZ\xf2\xc9Hh\xac\xccW\xab7\u007f\x83u\xca\xe8\xf5\xba\x81\xac\xee\x1a\x80If\x04\x0e\xad\x9a\xce\xf0\x1d\xe5\x0f\n\xc9]\xa929$\xab՛\a\x85\xe1\xcb\x13\xddh6\xe0d\xbfS\xaf\x19\xab\xed\x12\xa3\xd3L\xa5_\x13\xaaa\x81ޡ\xab\x1e\xe5\xb1\xe0\x9f`\x81\xc1\xc18\x12\x9b\x10\u007fn\xbc\xbd/\xad\x05\x8d\xc3-7iu´\x91\xc0t\x99\x8f\xc1R\xdeߡ\x880\xb6'!\x91T\xf3\x9d,\xee9:)\xb1\x99\xb0S`\xbf\x90\x1c\xa4*svM\x82}6xKZR\xfe\x04#\a\xb4*= \x18\x8431\x86\x8cL\x8cM\xa8\x0f\xfbw\xf0\xa9\xa9s{\x97\x99\xf4+\xa9\x8e\x86q\x14D\x8f@)]\x94HpY@B\x11\x16H\x03\xa9OP\x89\x169<?\x91ʉq\x062\xa1hK\xa0d.\xb2\x12\x12\xca\x0e\xc5\xf6-\xa9/\x0e\xdb-u\xfaAY\x87\x94\x87b\x1e-uJF\xa1?\xc8\xd5\xc0!)\x84s\xbd\x0e\xf1{\aB\x8bm\x00\x1b\u007f\nk\xa6\x16\x9c\xa3\xc4\x1a\xdf\x05\xcd\xf7\xb1\xb0\x94\xa0\x18+-a\x91\xd0>%\xc2*\xc1\x02\t\xda<&\xf4\xed\xb3\xa3\\\xe9\x1d!OsB\a\xfd>)\xc0\xa6\xa0\xf1\x99\xb8X\x84\xbb#<T#\xc2\xd7\xff\x8e\xa7,\a\x04K\x9f\xf7=\xf1\x8d߂\x16\x10\x8b\xc0\x03\x82C\xa5\xa7t?6\v%\xc2=d\xa0\x1c$\xb9\xea\x133!\xfd^\x1c\x15-.W-y8\x15G\xca\xe4B\x154\x82\xd4h\xb9\xaf\x84\xdejyV\tՈ?\xa8\x86n\xae\u007fL\t\xdd\\w\xac\xa0\xffeѴ.\x80C\xd2\xd5\xec\xff\xb5e\xf4\x1f,а\xf4' SSj\xa4\xfc\x03H\xb0\x02A\xb6Vd\xcbp\xdf\x01\xae\a\xfc\xee\xe9\x89C\xbe\x03뗢H\x94\rE\xb7\xa5T\xf8}\xe8ـ\xd6\xf4\\\x8a\x90\xd3\v\xf8\xd0\xf17\xaf\x90\x9b\xe6\x15\xf2\xdb\xc5_!\x05\x80\xfdS\xf9m\xbc}\x98\xa1A\x91}\x06\xb0\xf7Ur\xeaV&\xf7F\xeb\xea1\xed:\fW\xf4\x9c\a\xbc\xad\x8d\xe3|\xe3\f\x84\xec\x90}\xb4\xedB5\xe0\xc6>S\x05\xe5\u007f|&\xb1ʧ$\fG?i7\xe3\xde\x19\xeb\x97'\xf3\xb73%\x81>Ud\xfe\xd6_Q\x12\xf18\xc2Yg\xf3\xbc\xdaJ\xa7\xe9\x9c$\xbd\xf5S\x96V\xf8\xdcP\xfe\xbe\xfetF\xb0\rȹ!\xbfḟ\xd0x\x104\xf7\xbd\xe0[\xa3q~5\xf2\x11h<#\n\xaf|~Ҷ8\x16RPs\x90\x94\xffU\u007f:Ù\x06\xe4\x05Ut[5]7\xa58j\xeb\x0f\x8f\xd3\xdai1\x8e\x8d\\\xb6\x02u\x10\u00891\xf8]\xdbZ\U0008f8c7\xd1\xd5\xfb\xdb/\xb7\xbd8B\xd9]\xef$\xe9M\x0e\xff-E\xa6pI\xf9\xa5\x8d\x95\x05\xe5}\xff\xc6\xfax\xd7;][\x9aG}\xa6~G_;\xd5\xd6\xf1t\xc7QX\f/]\x9c;GqT\xff\xcc\xc3_\xadV\xa0\xe5z\xfd_\x00\x00\x00\xff\xff\xa3\xd5h\x9a\x16\x12\x00\x00",
-		hash:  "09f57d070f90d3c82e7c27d32529c415f066f952752f5055e393093dc2bf67ab",
+		data:  "\x1f\x8b\b\x00\x00\tn\x88\x02\xff\xecXmo\xdb6\x10\xfe\xde_q%P \x01\xa6J\xee\x8a-p$\x02I\x8af\x03\xd6\"X\x8a\x01\xfbH\x8b瘨Lj\xe4ɉa\xf8\xbf\x0fԋ\x9d(\xb6%;/X\x81}\xb2}<>\xbc{\xee!y\xf4b!q\xac4\x02\xcbL*\xb2o&g\xcb\xe5\x1b\x00\x00\x00\x80\xd8aJ\xcahP2\xa9\x1c\x18_\r\x02\x00\xc4R\xcd ̈́s\t\xb3\xe6\xb65\xda\xf6HMVL\xb5\xdb\xe0U-6\x15Y\xc6c\x97\x8bjA\x87v\x866p$\xa8p\x8cǡ\x1f\xf1\x1f\xa5\xdff\x8c\xc9\x00\x1c\xcd3Lح\x924\x19\x0e\xa2\xe8\xdd)\xe3\x7f\x9b\xc2\xc2W#\xb1Ye\xb6X\xbc\xff\v\xadSF/\x97\rd5\xd6\x00\x8c3#hh\xd5̈́N\x19\xbfT\x04\xe7\x85\xca\xe4\x10\x16\x8b\xf7\x97\x8a\xca\x1f\xf7憓\x01\x87\xcdA\xbd\r\x82z]0:\xcdT\xfa=a\x1a\xef\xc8\att\xccx,\xf8W\xbc\xa32\xc08\x14\xab\x14\x7fj\xa2\xbd(\xacEM\xc357ie\t\xb4\x91\x18\xe8b:B\xcbxԢ\b\x82`CAB\xa9f\xfcM\x97i\xaf\xc2f\xc2\xde`\xf03LQ\xaab\x1a|\x84r\xfd`\xf0\x01:J~\x0fc\x8adU\xba\xc5\x11\x00 \xce\xc4\b3\x18\x1b\x9b0\x9f\xf6o\xe8KS\xd7\xf6<3\xe9w\xa8L\xc38,]w@)\x9d\x17\x044\xcf1a\x84w\xc4JR\uf842\x16S|h\x91ʉQ\x862ad\vd0\x13Y\x81\t\v\xb6\xe5\xf6\x98\xd4\x1diW\x82'a\xa9\xc8Y\xa3@\xa9\\\x9e\x89\xf9P\x1b\x8d\xa7=\x99\xa91\xae\xac\xb9\xb1\xe8\x1c\xe3\xa5\xea\xaf+k\x0ff|hJ>\xc6i\xe2\xcdW\x06k2\\\xff\x1e\tˀ\xc4Hi\x89w\t\x8b\x18\b\xabDP\x92\xa4\xcdm\xcb2U\xbae\xf1UHX\x049\xda\x145=p\x16w\t\x1bD\xd1\x0e\x06\x00\x00\xaa\xcd\xd1\n3\x98\"\xa1e\x0f\x0f\x05\x88\xdeu`\x01\x00\xc4\xf9f\xb0\xa0\x14\f/)U\xfa&\x0e\U000cee2aM\xb9\x9d\xf2\x1dB9@C\x93\x82\xa4\xb9\xd5O\x13Q\r\xd2VQm\xdeGFm\xa4\xffu\xf4HG&\xcf\xffS:꩑\xb9N?+\xeb\xa8\x11\xc7\\\xa7\xfe\x9c\xa1\xc2\xc1\xd1\xc0\x11\xe4¹\xe3}\xa4\xb2\x02|\xb2F>\xf4\x12\xc9 z%\x99\xf8&\xa4\x9fP<\x0f\x84Ο+l\xa7j<dI9J8\x8a\xc0\x8c!:\xfe\x11\x15t\x8d\xa9\xd1r\x93\x84>hy\x90\x84j\xc4W\xd2\xd0\xc9\xc7ב\xd0\xc9\xc7g8j~X\xd1t6\xa3ۼ\xab>\xf4\x97\x8e6t\xab@\xcb\a\xc8\x18ej\nM\x8c\x7fF\x89V\x10\xcaNEv4\x9a-\xe0\xba\xd9l[\xf7l8{\xb0\xfeR\x14\x89\xa2\xa1謐\x8a\x9e\x87\x1eQ<\xa4\xe7\xa5\b\xd9_\xc0\xdb̏^D'͋\xe8\xd7\x17\x7f\x11\xe5\x88\xf6\x0f\xe5o\xe3\xf5#\x91\f\x89\xec\n\xd1^Tũ\xb72\\\x18\xad\xab\x87\xbd\xebq\xb8\x92\xe7\xbc\xc4[\xaf\xb1\x9bo\x9a\xa0\x90=\xaaO\xb6۩\x06\\\xad\x1f\xa8\x9c\xf1߯ V\xd3\x1b(\x0fG\x7fҮ\x8e{gʦ<\xf0\xa3\x13%\x91ݟ\x18\xf8Q?\xc4 \xe4qH\x93\xde\xcb\xf3\xeaV\xdao\xce^\xde\xeb8ea\x85\xaf\r\xe3\x9f\xeao\a$ۀ\x1c\x9a\xf2\xdb \xf0)4\x11\x9437\xfd\x9bЙ\x8d\xf3W#\xbfFM\ad\xe1'\x1f^\xb45\x8e\xc5\x14\xd5\f%\xe3\x7f\xd6\xdf\x0e\b\xa6\x01y\x82\x8aΪM\xd7oR\x1cv\xed\x8f8\xec\xb1\xd3b\x1a\x199\xef\x04\xea\xe1Dcc\xe8Y\xb7\xb5\xe4_\xae/\xaf\x8f>\x9d};;\x8eC\x92\xfd\xe7\xed彪\xe1?\x85\xc8\x14\xcd\x19\x7f\xe9Ŋ\x9c\xf1\xc8\xf7X_Ώ\xf7\x9f]\xbe\xdf\x0f\x9b\xdf3\xd6^\xda\xda]\xee8,/\x86\xa7^\x9c-S\x1c\xd6\x7f9\xf37\x8b\x05j\xb9\\\xfe;\x00\xa8L\xc6y\xa2\x16\x00\x00",
+		hash:  "35f943267e00c1c5170758275e407d36cdf50559ea16cc55d4fb7b78f1a34bdd",
 		mime:  "text/html; charset=utf-8",
-		mtime: time.Unix(1491149617, 0),
-		size:  4630,
+		mtime: time.Unix(1792260377, 0),
+		size:  5794,
 	},
 	"index/transactionsummary.html": {
 		data:  "\x1f\x8b\b\x00\x00\tn\x88\x02\xff\xc4V\xddn\xdb:\f\xbeN\x9e\x82P\x81\x83s.\f\x9f\xeerS\f\xaci\x8b\x16\xeb0`\xe8\v(\x163\v\x95%C\xa2\xbb\x1aA\xde}\x90\x1c\x1bn\xfe\xeat\xe8\xcfMe\x92\x9f\xfc\x91\x1fI\aV+\x89Ke\x10\x189a\xbc\xc8IY\xe3\xeb\xb2\x14\xaea\xeb\xf5\x94{\x8c&Pr\xf6,\x84eS\x00\x00.\xd5#\xe4Zx?c\xce\xfe\xdeX\xb7=\xb9\xd5u\xd9c\xfa\x88\xe2<\xbb\x1f\\\t\xff\x88\xb2\xfa\x02W\x86\x9cB\xcf\xd3\xe2<\x9bN&\x13^\xeb\xee\x1e\x12\v\xcf@\n\x12I8FR\xf8$\xcaJc4\xb0\b\x98p\xad\x86\x88\x84\x14i\x04\xe5\x93\xf0\xa2Gd\x19\x17P8\\\xce\xd8Y%̵\xc8\xc9*\xe9\x19\b\xa7D\xe2QcN\x18ӭ\x91e\x9d\x9b\xfbJ\xb4ep\x98\xa3\xa1d\xd9:\x12\xb2$4\xcb\xfe\xfd\xff?\x9e\x86\x98\x8c\xa7\"\xe3\xa9VG\xc8lQؤ̲y!\x94I\xc3c\x03s[\x96\x8a<\xec\xbc\x18\x83\xfb\xd8ka\xebo\f\x85K\xe50'\xeb\x9a\vm\xf3\a\x96\xdd\tO\xd0\x1b!Z!\xd9%#\xbb\x90d\xd1\x02\xf7\x15\x81\xa7\xb5n\x0f\x83\xa6\x88Trk\b\r\rD\xedL\xfb\x95\xdd\xc6W\u00a0\x1eH\x1b\xa9\rE\xddS\r\x12\x8b\xd0\x0emC?\xdd)O{\xa2\xda\xc8\x02\x85\xdc\xefk\xfd\xee\xb0ss\xc1\xb0\xc3\xe1\xf6\x92\xa7T\x8c\xc0\x04m\xe1\xd6T5\x8d\x03\x9c\xb5\xc1\xf0UJ\x87އ\xe9\x19\a\xfbQ\xd3\t8\x9e\x1e\xca8\xe0\x0e֊\xd3\xc2ʦ\xf3\x1d\xc2\x0fc\x9e9\x82\\\x1b\xf9S\xa9\x1e\x8fuB\xaf\u007f?Q\x1f-\u007f;\xcb7\xc2\x17\xe3$\x89\x1b`t\xa3\\\xcdan=\xbd\xa9j\u007f\xaf\xd7N\xccq\xed\xb6W\xd1\aK\b\xb9\xd5a\xa5\xcdا\x03k\xf1\xd6,\xad+E\x18\xf1w\x98\x9fWe!\xb3o\xd8|\xff\xf9\x99\xa7$_\x8c\x8d\x95\xbd\xbc\x88\x88\xf8\x99\b\xcf\xf1sW&\x1e\x85ˋD+\xf3\xc0\x80\x9a\ngL\xf6\x9b?\xac\xfcc\xf7\x1f\xce\u007ft\x1a\x17V6pz.\x01\xd6\xe5\xf3\xd6\x14\xafk\xad\xe3ğ\xc40\xa0\x02\xe8\x1d\bޫ\x12=\x89\xb2:\xad\x84A\xe5\x1e\xfa\x0e4\xdb\xe1\xbaA\xf5\xab\xa0ә\xb6\xb8\xd7\xd3|y\xc3\xed:\xba\xafS\u007f\xea\x0e\x9b\xff<\xdd\xfc\x9cΦ\xab\x15\x1a\xb9^\xff\t\x00\x00\xff\xfff\x97\xc7~\x81\v\x00\x00",
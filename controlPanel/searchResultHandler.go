@@ -508,6 +508,14 @@ func getAblock(hash string) *AblockHolder {
 			}
 			disp.Type = "Add Bitcoin Server Key"
 			disp.OtherInfo = "Identity ChainID: <a href='' id='factom-search-link' type='chainhead'>" + b.IdentityChainID.String() + "</a>"
+		case constants.TYPE_ADD_ETH_ANCHOR_KEY:
+			e := new(adminBlock.AddFederatedServerEthereumAnchorKey)
+			err := e.UnmarshalBinary(data)
+			if err != nil {
+				continue
+			}
+			disp.Type = "Add Ethereum Server Key"
+			disp.OtherInfo = "Identity ChainID: <a href='' id='factom-search-link' type='chainhead'>" + e.IdentityChainID.String() + "</a><br />Address: 0x" + e.EthereumAddress.String()
 		}
 		holder.ABDisplay = append(holder.ABDisplay, *disp)
 	}
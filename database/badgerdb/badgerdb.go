@@ -0,0 +1,330 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+// Package badgerdb stores the database in Badger, which keeps values in an append-only
+// value log and only keys in its LSM tree.  Large blocks and entries are written once
+// rather than rewritten on every compaction, which is where Bolt and LevelDB spend their
+// IO on archival nodes.  The price is a value log that has to be garbage collected, which
+// a goroutine does every ValueLogGCInterval.
+package badgerdb
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+
+	"github.com/dgraph-io/badger"
+	log "github.com/sirupsen/logrus"
+)
+
+var badgerLogger = log.WithFields(log.Fields{"package": "badgerdb"})
+
+// Value log GC tuning, used by databases opened after these are set.  Every
+// ValueLogGCInterval, value log files with at least ValueLogGCDiscardRatio of their space
+// taken by deleted or overwritten values are rewritten.  An interval of 0 never collects.
+var (
+	ValueLogGCInterval     = 10 * time.Minute
+	ValueLogGCDiscardRatio = 0.5
+	// Size of each value log file, in bytes.  Smaller files are collected sooner, at the
+	// cost of more of them.  0 leaves the Badger default.
+	ValueLogFileSize int64
+)
+
+type BadgerDB struct {
+	// lock preventing multiple entry
+	dbLock sync.RWMutex
+	db     *badger.DB
+	stopGC chan struct{}
+	gcDone chan struct{}
+}
+
+var _ interfaces.IDatabase = (*BadgerDB)(nil)
+
+// NewBadgerDB opens the database in the directory, creating it if need be, and starts
+// collecting its value log
+func NewBadgerDB(dir string) (*BadgerDB, error) {
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return nil, err
+	}
+	opts := badger.DefaultOptions
+	opts.Dir = dir
+	opts.ValueDir = dir
+	if ValueLogFileSize > 0 {
+		opts.ValueLogFileSize = ValueLogFileSize
+	}
+	bdb, err := badger.Open(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	db := new(BadgerDB)
+	db.db = bdb
+	db.stopGC = make(chan struct{})
+	db.gcDone = make(chan struct{})
+	go db.collectValueLog(ValueLogGCInterval, ValueLogGCDiscardRatio)
+	return db, nil
+}
+
+// collectValueLog runs the value log GC every interval until the database is closed.  Each
+// successful run rewrites a single file, so it runs until there is nothing left to rewrite.
+func (db *BadgerDB) collectValueLog(interval time.Duration, discardRatio float64) {
+	defer close(db.gcDone)
+	if interval <= 0 {
+		<-db.stopGC
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-db.stopGC:
+			return
+		case <-ticker.C:
+		}
+		start := time.Now()
+		rewritten := 0
+		var err error
+		for {
+			err = db.db.RunValueLogGC(discardRatio)
+			if err != nil {
+				break
+			}
+			rewritten++
+			BadgerValueLogRewrites.Inc()
+		}
+		if err != badger.ErrNoRewrite {
+			badgerLogger.Warnf("Value log GC stopped after rewriting %d files: %v", rewritten, err)
+		} else if rewritten > 0 {
+			badgerLogger.Infof("Value log GC rewrote %d files in %s", rewritten, time.Since(start))
+		}
+	}
+}
+
+func (db *BadgerDB) Close() error {
+	db.dbLock.Lock()
+	defer db.dbLock.Unlock()
+
+	// The GC must be done before the database closes under it
+	close(db.stopGC)
+	<-db.gcDone
+	return db.db.Close()
+}
+
+func ExtendBucket(bucket []byte) []byte {
+	return append(bucket, ';')
+}
+
+func CombineBucketAndKey(bucket []byte, key []byte) []byte {
+	bKey := ExtendBucket(bucket)
+	bKey = append(bKey, key...)
+	return bKey
+}
+
+func (db *BadgerDB) Put(bucket []byte, key []byte, data interfaces.BinaryMarshallable) error {
+	db.dbLock.Lock()
+	defer db.dbLock.Unlock()
+
+	BadgerPuts.Inc()
+
+	hex, err := data.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	return db.db.Update(func(txn *badger.Txn) error {
+		return txn.Set(CombineBucketAndKey(bucket, key), hex)
+	})
+}
+
+// PutInBatch writes the records in as few transactions as Badger allows; a batch too big
+// for one transaction is committed in parts.
+func (db *BadgerDB) PutInBatch(records []interfaces.Record) error {
+	db.dbLock.Lock()
+	defer db.dbLock.Unlock()
+
+	txn := db.db.NewTransaction(true)
+	defer func() { txn.Discard() }()
+
+	for _, v := range records {
+		bKey := CombineBucketAndKey(v.Bucket, v.Key)
+		hex, err := v.Data.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		err = txn.Set(bKey, hex)
+		if err == badger.ErrTxnTooBig {
+			if err = txn.Commit(nil); err != nil {
+				return err
+			}
+			txn = db.db.NewTransaction(true)
+			err = txn.Set(bKey, hex)
+		}
+		if err != nil {
+			return err
+		}
+		BadgerPuts.Inc()
+	}
+	return txn.Commit(nil)
+}
+
+func (db *BadgerDB) Get(bucket []byte, key []byte, destination interfaces.BinaryMarshallable) (interfaces.BinaryMarshallable, error) {
+	db.dbLock.RLock()
+	defer db.dbLock.RUnlock()
+
+	BadgerGets.Inc()
+
+	var data []byte
+	err := db.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(CombineBucketAndKey(bucket, key))
+		if err != nil {
+			return err
+		}
+		data, err = item.ValueCopy(nil)
+		return err
+	})
+	if err == badger.ErrKeyNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = destination.UnmarshalBinaryData(data)
+	if err != nil {
+		return nil, err
+	}
+	return destination, nil
+}
+
+func (db *BadgerDB) Delete(bucket []byte, key []byte) error {
+	db.dbLock.Lock()
+	defer db.dbLock.Unlock()
+
+	return db.db.Update(func(txn *badger.Txn) error {
+		return txn.Delete(CombineBucketAndKey(bucket, key))
+	})
+}
+
+func (db *BadgerDB) DoesKeyExist(bucket, key []byte) (bool, error) {
+	db.dbLock.RLock()
+	defer db.dbLock.RUnlock()
+
+	err := db.db.View(func(txn *badger.Txn) error {
+		_, err := txn.Get(CombineBucketAndKey(bucket, key))
+		return err
+	})
+	if err == badger.ErrKeyNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (db *BadgerDB) ListAllKeys(bucket []byte) ([][]byte, error) {
+	db.dbLock.RLock()
+	defer db.dbLock.RUnlock()
+
+	prefix := ExtendBucket(bucket)
+	var answer [][]byte
+	err := db.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchValues = false
+		iter := txn.NewIterator(opts)
+		defer iter.Close()
+
+		for iter.Seek(prefix); iter.ValidForPrefix(prefix); iter.Next() {
+			key := iter.Item().Key()
+			tmp := make([]byte, len(key)-len(prefix))
+			copy(tmp, key[len(prefix):])
+			answer = append(answer, tmp)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return answer, nil
+}
+
+func (db *BadgerDB) GetAll(bucket []byte, sample interfaces.BinaryMarshallableAndCopyable) ([]interfaces.BinaryMarshallableAndCopyable, [][]byte, error) {
+	db.dbLock.RLock()
+	defer db.dbLock.RUnlock()
+
+	prefix := ExtendBucket(bucket)
+	answer := []interfaces.BinaryMarshallableAndCopyable{}
+	keys := [][]byte{}
+	err := db.db.View(func(txn *badger.Txn) error {
+		iter := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer iter.Close()
+
+		for iter.Seek(prefix); iter.ValidForPrefix(prefix); iter.Next() {
+			item := iter.Item()
+			v, err := item.ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+			tmp := sample.New()
+			err = tmp.UnmarshalBinary(v)
+			if err != nil {
+				return err
+			}
+			key := item.Key()
+			k := make([]byte, len(key)-len(prefix))
+			copy(k, key[len(prefix):])
+			keys = append(keys, k)
+			answer = append(answer, tmp)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return answer, keys, nil
+}
+
+func (db *BadgerDB) Clear(bucket []byte) error {
+	keys, err := db.ListAllKeys(bucket)
+	if err != nil {
+		return err
+	}
+
+	db.dbLock.Lock()
+	defer db.dbLock.Unlock()
+
+	txn := db.db.NewTransaction(true)
+	defer func() { txn.Discard() }()
+
+	for _, key := range keys {
+		bKey := CombineBucketAndKey(bucket, key)
+		err = txn.Delete(bKey)
+		if err == badger.ErrTxnTooBig {
+			if err = txn.Commit(nil); err != nil {
+				return err
+			}
+			txn = db.db.NewTransaction(true)
+			err = txn.Delete(bKey)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return txn.Commit(nil)
+}
+
+func (db *BadgerDB) ListAllBuckets() ([][]byte, error) {
+	// Buckets are only a prefix of the keys, as in LevelDB
+	return nil, fmt.Errorf("Unable to fetch buckets due to Badger design")
+}
+
+// Can't trim a real database; reports the size of the LSM tree and value log instead
+func (db *BadgerDB) Trim() {
+	lsm, vlog := db.db.Size()
+	BadgerLSMSize.Set(float64(lsm))
+	BadgerValueLogSize.Set(float64(vlog))
+}
@@ -0,0 +1,241 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package badgerdb_test
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/common/primitives/random"
+	. "github.com/FactomProject/factomd/database/badgerdb"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+type TestData struct {
+	Str string
+}
+
+func (t *TestData) New() interfaces.BinaryMarshallableAndCopyable {
+	return new(TestData)
+}
+
+func (t *TestData) MarshalBinary() ([]byte, error) {
+	return []byte(t.Str), nil
+}
+
+func (t *TestData) UnmarshalBinaryData(data []byte) ([]byte, error) {
+	t.Str = string(data)
+	return nil, nil
+}
+
+func (t *TestData) UnmarshalBinary(data []byte) (err error) {
+	_, err = t.UnmarshalBinaryData(data)
+	return
+}
+
+var _ interfaces.BinaryMarshallable = (*TestData)(nil)
+
+var dbFilename string = "badgerTest"
+
+func newTestDB(t *testing.T) *BadgerDB {
+	m, err := NewBadgerDB(dbFilename)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	return m
+}
+
+func CleanupTest(t *testing.T, b *BadgerDB) {
+	err := b.Close()
+	if err != nil {
+		t.Errorf("%v", err)
+	}
+	err = os.RemoveAll(dbFilename)
+	if err != nil {
+		t.Errorf("%v", err)
+	}
+}
+
+func TestPutGetDelete(t *testing.T) {
+	m := newTestDB(t)
+	defer CleanupTest(t, m)
+
+	key := []byte("key")
+	bucket := []byte("bucket")
+
+	test := new(TestData)
+	test.Str = "testtest"
+
+	err := m.Put(bucket, key, test)
+	if err != nil {
+		t.Errorf("%v", err)
+	}
+
+	resp, err := m.Get(bucket, key, new(TestData))
+	if err != nil {
+		t.Errorf("%v", err)
+	}
+	if resp == nil {
+		t.Fatalf("resp is nil")
+	}
+	if resp.(*TestData).Str != test.Str {
+		t.Errorf("data mismatch")
+	}
+
+	err = m.Delete(bucket, key)
+	if err != nil {
+		t.Errorf("%v", err)
+	}
+
+	resp, err = m.Get(bucket, key, new(TestData))
+	if err != nil {
+		t.Errorf("%v", err)
+	}
+	if resp != nil {
+		t.Errorf("resp is not nil while it should be")
+	}
+}
+
+func TestMultiValue(t *testing.T) {
+	m := newTestDB(t)
+	defer CleanupTest(t, m)
+
+	bucket := []byte("bucket")
+	other := []byte("bucket2")
+	batch := []interfaces.Record{}
+	for i := 0; i < 10; i++ {
+		r := interfaces.Record{}
+		r.Key = []byte(fmt.Sprintf("%v", i))
+		r.Bucket = bucket
+		td := new(TestData)
+		td.Str = fmt.Sprintf("Data %v", i)
+		r.Data = td
+		batch = append(batch, r)
+	}
+	// A bucket whose name starts with the other's must not show up in it
+	batch = append(batch, interfaces.Record{Bucket: other, Key: []byte("x"), Data: &TestData{Str: "other"}})
+
+	err := m.PutInBatch(batch)
+	if err != nil {
+		t.Error(err)
+	}
+
+	keys, err := m.ListAllKeys(bucket)
+	if err != nil {
+		t.Error(err)
+	}
+	if len(keys) != 10 {
+		t.Errorf("Invalid length of keys - %v", len(keys))
+	}
+	for i := range keys {
+		if string(keys[i]) != fmt.Sprintf("%v", i) {
+			t.Error("Wrong key returned")
+		}
+	}
+
+	all, _, err := m.GetAll(bucket, new(TestData))
+	if err != nil {
+		t.Error(err)
+	}
+	if len(all) != 10 {
+		t.Errorf("Invalid length of values - %v", len(all))
+	}
+	for i := range all {
+		v := all[i].(*TestData)
+		if v.Str != fmt.Sprintf("Data %v", i) {
+			t.Error("Wrong data returned")
+		}
+	}
+	err = m.Clear(bucket)
+	if err != nil {
+		t.Error(err)
+	}
+
+	keys, err = m.ListAllKeys(bucket)
+	if err != nil {
+		t.Error(err)
+	}
+	if len(keys) != 0 {
+		t.Error("Keys not cleared from database properly")
+	}
+	keys, err = m.ListAllKeys(other)
+	if err != nil {
+		t.Error(err)
+	}
+	if len(keys) != 1 {
+		t.Error("Clearing a bucket cleared another")
+	}
+}
+
+func TestDoesKeyExist(t *testing.T) {
+	m := newTestDB(t)
+	defer CleanupTest(t, m)
+
+	for i := 0; i < 100; i++ {
+		key := random.RandNonEmptyByteSlice()
+		bucket := random.RandNonEmptyByteSlice()
+
+		err := m.Put(bucket, key, &TestData{Str: "testtest"})
+		if err != nil {
+			t.Errorf("%v", err)
+		}
+
+		exists, err := m.DoesKeyExist(bucket, key)
+		if err != nil {
+			t.Errorf("%v", err)
+		}
+		if exists == false {
+			t.Errorf("Key does not exist")
+		}
+
+		exists, err = m.DoesKeyExist(bucket, append(key, 0xff))
+		if err != nil {
+			t.Errorf("%v", err)
+		}
+		if exists == true {
+			t.Errorf("Key does exist while it shouldn't")
+		}
+	}
+}
+
+func TestGetAll(t *testing.T) {
+	m := newTestDB(t)
+	defer CleanupTest(t, m)
+
+	dbo := databaseOverlay.NewOverlay(m)
+	testHelper.PopulateTestDatabaseOverlay(dbo)
+
+	_, keys, err := dbo.GetAll(databaseOverlay.INCLUDED_IN, primitives.NewZeroHash())
+	if err != nil {
+		t.Errorf("%v", err)
+	}
+	if len(keys) != 150 {
+		t.Errorf("Invalid amount of keys returned - expected 150, got %v", len(keys))
+	}
+}
+
+func TestCloseStopsValueLogGC(t *testing.T) {
+	defer func(interval time.Duration) { ValueLogGCInterval = interval }(ValueLogGCInterval)
+	ValueLogGCInterval = time.Millisecond
+
+	m := newTestDB(t)
+	time.Sleep(10 * time.Millisecond)
+
+	done := make(chan struct{})
+	go func() {
+		CleanupTest(t, m)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close did not stop the value log GC")
+	}
+}
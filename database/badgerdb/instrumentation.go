@@ -0,0 +1,45 @@
+package badgerdb
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	BadgerGets = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "factomd_database_badger_gets",
+		Help: "Counts gets from the database",
+	})
+	BadgerPuts = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "factomd_database_badger_puts",
+		Help: "Count puts to the database",
+	})
+	BadgerValueLogRewrites = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "factomd_database_badger_value_log_rewrites",
+		Help: "Counts value log files rewritten by the value log GC",
+	})
+	BadgerLSMSize = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "factomd_database_badger_lsm_bytes",
+		Help: "Size of the Badger LSM tree on disk",
+	})
+	BadgerValueLogSize = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "factomd_database_badger_value_log_bytes",
+		Help: "Size of the Badger value log on disk",
+	})
+)
+
+var registered = false
+
+// RegisterPrometheus registers the variables to be exposed. This can only be run once, hence the
+// boolean flag to prevent panics if launched more than once. This is called in NetStart
+func RegisterPrometheus() {
+	if registered {
+		return
+	}
+	registered = true
+
+	prometheus.MustRegister(BadgerGets)
+	prometheus.MustRegister(BadgerPuts)
+	prometheus.MustRegister(BadgerValueLogRewrites)
+	prometheus.MustRegister(BadgerLSMSize)
+	prometheus.MustRegister(BadgerValueLogSize)
+}
@@ -0,0 +1,78 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package badgerdb
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+
+	"github.com/dgraph-io/badger"
+)
+
+type BadgerDBSnapshot struct {
+	txn *badger.Txn
+}
+
+var _ interfaces.ISnapshotDatabase = (*BadgerDB)(nil)
+var _ interfaces.IDatabaseSnapshot = (*BadgerDBSnapshot)(nil)
+
+// Snapshot takes a consistent view of the database.  A read-only Badger transaction is
+// one, so writes carry on while the snapshot is copied out.
+func (db *BadgerDB) Snapshot() (interfaces.IDatabaseSnapshot, error) {
+	db.dbLock.Lock()
+	defer db.dbLock.Unlock()
+
+	return &BadgerDBSnapshot{txn: db.db.NewTransaction(false)}, nil
+}
+
+// WriteTo copies every record in the snapshot into a new Badger database at path
+func (s *BadgerDBSnapshot) WriteTo(path string) error {
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("%s already exists", path)
+	}
+	if err := os.MkdirAll(path, 0750); err != nil {
+		return err
+	}
+	opts := badger.DefaultOptions
+	opts.Dir = path
+	opts.ValueDir = path
+	target, err := badger.Open(opts)
+	if err != nil {
+		return err
+	}
+	defer target.Close()
+
+	iter := s.txn.NewIterator(badger.DefaultIteratorOptions)
+	defer iter.Close()
+
+	txn := target.NewTransaction(true)
+	defer func() { txn.Discard() }()
+	for iter.Rewind(); iter.Valid(); iter.Next() {
+		item := iter.Item()
+		key := item.KeyCopy(nil)
+		value, err := item.ValueCopy(nil)
+		if err != nil {
+			return err
+		}
+		err = txn.Set(key, value)
+		if err == badger.ErrTxnTooBig {
+			if err = txn.Commit(nil); err != nil {
+				return err
+			}
+			txn = target.NewTransaction(true)
+			err = txn.Set(key, value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return txn.Commit(nil)
+}
+
+func (s *BadgerDBSnapshot) Release() {
+	s.txn.Discard()
+}
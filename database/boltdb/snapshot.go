@@ -0,0 +1,45 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package boltdb
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/FactomProject/bolt"
+	"github.com/FactomProject/factomd/common/interfaces"
+)
+
+// BoltDBSnapshot is an open read transaction.  Bolt writers carry on while it is open,
+// though they may wait on it if the database file has to grow.
+type BoltDBSnapshot struct {
+	tx *bolt.Tx
+}
+
+var _ interfaces.ISnapshotDatabase = (*BoltDB)(nil)
+var _ interfaces.IDatabaseSnapshot = (*BoltDBSnapshot)(nil)
+
+func (db *BoltDB) Snapshot() (interfaces.IDatabaseSnapshot, error) {
+	db.Sem.Lock()
+	defer db.Sem.Unlock()
+
+	tx, err := db.db.Begin(false)
+	if err != nil {
+		return nil, err
+	}
+	return &BoltDBSnapshot{tx: tx}, nil
+}
+
+// WriteTo copies the database file, as of the snapshot, to path
+func (s *BoltDBSnapshot) WriteTo(path string) error {
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("%s already exists", path)
+	}
+	return s.tx.CopyFile(path, 0600)
+}
+
+func (s *BoltDBSnapshot) Release() {
+	s.tx.Rollback()
+}
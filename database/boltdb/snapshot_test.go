@@ -0,0 +1,51 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package boltdb_test
+
+import (
+	"os"
+	"testing"
+
+	. "github.com/FactomProject/factomd/database/boltdb"
+)
+
+func TestSnapshotWriteTo(t *testing.T) {
+	m := NewBoltDB(nil, dbFilename)
+	defer CleanupTest(t, m)
+
+	backup := dbFilename + ".backup"
+	defer os.Remove(backup)
+
+	bucket := []byte("bucket")
+	if err := m.Put(bucket, []byte("before"), &TestData{Str: "kept"}); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	snap, err := m.Snapshot()
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	err = snap.WriteTo(backup)
+	snap.Release()
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	// Writes carry on once the snapshot is released
+	if err := m.Put(bucket, []byte("after"), &TestData{Str: "dropped"}); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	b := NewBoltDB(nil, backup)
+	defer b.Close()
+
+	resp, err := b.Get(bucket, []byte("before"), new(TestData))
+	if err != nil || resp == nil || resp.(*TestData).Str != "kept" {
+		t.Errorf("Backup is missing data written before the snapshot - %v %v", resp, err)
+	}
+	resp, err = b.Get(bucket, []byte("after"), new(TestData))
+	if err != nil || resp != nil {
+		t.Errorf("Backup has data written after the snapshot - %v %v", resp, err)
+	}
+}
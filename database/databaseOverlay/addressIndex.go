@@ -0,0 +1,147 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package databaseOverlay
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"sort"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+var (
+	// The factoid transactions touching each factoid or entry credit address, in a bucket
+	// per address, by the height of the factoid block and the place in it
+	ADDRESS_INDEX = []byte("AddressIndex")
+)
+
+var ErrNoAddressIndex = errors.New("The address index is not enabled")
+
+// EnableAddressIndex records, as factoid blocks are saved, the transactions spending from,
+// paying to or buying entry credits for each address.  It must be called before the
+// overlay is shared.  Only blocks saved after it is enabled are indexed.
+func (db *Overlay) EnableAddressIndex() {
+	db.indexAddresses = true
+}
+
+func addressIndexBucket(address interfaces.IHash) []byte {
+	bucket := make([]byte, 0, len(ADDRESS_INDEX)+32)
+	bucket = append(bucket, ADDRESS_INDEX...)
+	return append(bucket, address.Bytes()...)
+}
+
+func addressIndexKey(dbheight, index uint32) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint32(key, dbheight)
+	binary.BigEndian.PutUint32(key[4:], index)
+	return key
+}
+
+// TransactionAddresses returns the addresses a transaction touches: its inputs, outputs
+// and entry credit outputs, without repeats
+func TransactionAddresses(tx interfaces.ITransaction) []interfaces.IHash {
+	var addresses []interfaces.IHash
+	seen := map[[32]byte]bool{}
+	for _, list := range [][]interfaces.ITransAddress{tx.GetInputs(), tx.GetOutputs(), tx.GetECOutputs()} {
+		for _, ta := range list {
+			a := ta.GetAddress()
+			if a == nil || seen[a.Fixed()] {
+				continue
+			}
+			seen[a.Fixed()] = true
+			addresses = append(addresses, a)
+		}
+	}
+	return addresses
+}
+
+// addressIndexRecords returns the records indexing the transactions of a factoid block by
+// address, none if the index isn't enabled or the block isn't a factoid block
+func (db *Overlay) addressIndexRecords(block interfaces.DatabaseBlockWithEntries) []interfaces.Record {
+	if !db.indexAddresses {
+		return nil
+	}
+	fblock, ok := block.(interfaces.IFBlock)
+	if !ok {
+		return nil
+	}
+	var records []interfaces.Record
+	for i, tx := range fblock.GetTransactions() {
+		key := addressIndexKey(fblock.GetDatabaseHeight(), uint32(i))
+		for _, a := range TransactionAddresses(tx) {
+			records = append(records, interfaces.Record{Bucket: addressIndexBucket(a), Key: key, Data: tx.GetSigHash()})
+		}
+	}
+	return records
+}
+
+func (db *Overlay) saveAddressIndex(block interfaces.DatabaseBlockWithEntries) error {
+	records := db.addressIndexRecords(block)
+	if len(records) == 0 {
+		return nil
+	}
+	return db.PutInBatch(records)
+}
+
+func (db *Overlay) saveAddressIndexMultiBatch(block interfaces.DatabaseBlockWithEntries) {
+	records := db.addressIndexRecords(block)
+	if len(records) == 0 {
+		return
+	}
+	db.PutInMultiBatch(records)
+}
+
+// deleteAddressIndex drops a factoid block about to be deleted from the address index
+func (db *Overlay) deleteAddressIndex(fblock interfaces.IFBlock) error {
+	if !db.indexAddresses {
+		return nil
+	}
+	for i, tx := range fblock.GetTransactions() {
+		key := addressIndexKey(fblock.GetDatabaseHeight(), uint32(i))
+		for _, a := range TransactionAddresses(tx) {
+			if err := db.Delete(addressIndexBucket(a), key); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// FetchAddressTransactions returns up to count of the transactions touching an address, in
+// block order from the start'th, and how many there are in all
+func (db *Overlay) FetchAddressTransactions(address interfaces.IHash, start, count int) ([]interfaces.AddressTransaction, int, error) {
+	if !db.indexAddresses {
+		return nil, 0, ErrNoAddressIndex
+	}
+	bucket := addressIndexBucket(address)
+	keys, err := db.ListAllKeys(bucket)
+	if err != nil {
+		return nil, 0, err
+	}
+	sort.Slice(keys, func(i, j int) bool { return bytes.Compare(keys[i], keys[j]) < 0 })
+
+	txs := []interfaces.AddressTransaction{}
+	for i := start; i >= 0 && i < len(keys) && len(txs) < count; i++ {
+		if len(keys[i]) != 8 {
+			continue
+		}
+		txid, err := db.Get(bucket, keys[i], primitives.NewZeroHash())
+		if err != nil {
+			return nil, 0, err
+		}
+		if txid == nil {
+			continue
+		}
+		txs = append(txs, interfaces.AddressTransaction{
+			TxID:     txid.(interfaces.IHash),
+			DBHeight: binary.BigEndian.Uint32(keys[i]),
+			Index:    binary.BigEndian.Uint32(keys[i][4:]),
+		})
+	}
+	return txs, len(keys), nil
+}
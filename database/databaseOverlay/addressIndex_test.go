@@ -0,0 +1,66 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package databaseOverlay_test
+
+import (
+	"testing"
+
+	. "github.com/FactomProject/factomd/database/databaseOverlay"
+	"github.com/FactomProject/factomd/database/mapdb"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestAddressIndex(t *testing.T) {
+	dbo := NewOverlay(new(mapdb.MapDB))
+	defer dbo.Close()
+
+	fct := testHelper.NewFactoidAddress(0)
+	ec := testHelper.NewECAddress(0)
+	if _, _, err := dbo.FetchAddressTransactions(fct, 0, 10); err != ErrNoAddressIndex {
+		t.Errorf("Expected ErrNoAddressIndex, got %v", err)
+	}
+
+	// Each block pays the coinbase to the factoid address, which then buys entry credits
+	dbo.EnableAddressIndex()
+	fb0 := testHelper.CreateTestFactoidBlock(nil)
+	fb1 := testHelper.CreateTestFactoidBlock(fb0)
+	if err := dbo.ProcessFBlockBatch(fb0); err != nil {
+		t.Fatal(err)
+	}
+	dbo.StartMultiBatch()
+	if err := dbo.ProcessFBlockMultiBatch(fb1); err != nil {
+		t.Fatal(err)
+	}
+	if err := dbo.ExecuteMultiBatch(); err != nil {
+		t.Fatal(err)
+	}
+
+	txs, total, err := dbo.FetchAddressTransactions(fct, 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 4 || len(txs) != 2 {
+		t.Fatalf("Got %d of %d transactions", len(txs), total)
+	}
+	if txs[0].DBHeight != fb0.GetDatabaseHeight() || txs[0].Index != 1 || txs[1].DBHeight != fb1.GetDatabaseHeight() || txs[1].Index != 0 {
+		t.Errorf("Transactions out of order: %v", txs)
+	}
+	if !txs[1].TxID.IsSameAs(fb1.GetTransactions()[0].GetSigHash()) {
+		t.Errorf("Wrong transaction %v", txs[1].TxID)
+	}
+
+	txs, total, err = dbo.FetchAddressTransactions(ec, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 2 || len(txs) != 2 || txs[0].Index != 1 || txs[1].Index != 1 {
+		t.Errorf("Wrong entry credit transactions %v of %d", txs, total)
+	}
+
+	// Past the end
+	if txs, total, _ = dbo.FetchAddressTransactions(ec, 5, 10); total != 2 || len(txs) != 0 {
+		t.Errorf("Got %d transactions past the end", len(txs))
+	}
+}
@@ -58,6 +58,15 @@ func (dbo *Overlay) SaveAnchorInfoFromEntry(entry interfaces.IEBEntry) error {
 	if ar == nil {
 		return nil
 	}
+	if ar.Ethereum != nil {
+		if err := dbo.SaveEthereumAnchor(ar); err != nil {
+			return err
+		}
+	}
+	// Records may anchor into Ethereum alone
+	if ar.Bitcoin == nil {
+		return nil
+	}
 	dbi, err := AnchorRecordToDirBlockInfo(ar)
 	if err != nil {
 		return err
@@ -79,6 +88,14 @@ func (dbo *Overlay) SaveAnchorInfoFromEntryMultiBatch(entry interfaces.IEBEntry)
 	if ar == nil {
 		return nil
 	}
+	if ar.Ethereum != nil {
+		if err := dbo.saveEthereumAnchorMultiBatch(ar); err != nil {
+			return err
+		}
+	}
+	if ar.Bitcoin == nil {
+		return nil
+	}
 	dbi, err := AnchorRecordToDirBlockInfo(ar)
 	if err != nil {
 		return err
@@ -118,6 +135,9 @@ func (dbo *Overlay) SaveAnchorInfoAsDirBlockInfo(ars []*anchor.AnchorRecord) err
 	sort.Sort(ByAnchorDBHeightAccending(ars))
 
 	for _, v := range ars {
+		if v.Bitcoin == nil {
+			continue
+		}
 		dbi, err := AnchorRecordToDirBlockInfo(v)
 		if err != nil {
 			return err
@@ -0,0 +1,180 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package databaseOverlay
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+var (
+	// The entry hashes of each entry block of a chain, in a bucket per chain, by the
+	// sequence number of the entry block
+	CHAIN_INDEX = []byte("ChainIndex")
+)
+
+// The chain index lets the entries of a chain be paged through in chain order with a
+// lookup per entry block, rather than fetching every entry block back from the head.
+// Entry blocks saved before the index was added have no records; FetchChainIndex stops
+// at the first missing one, and callers fall back to walking the chain.
+
+// chainIndexRecord stores a ChainIndexBlock; the sequence is the key, so isn't stored
+type chainIndexRecord struct {
+	interfaces.ChainIndexBlock
+}
+
+func (r *chainIndexRecord) MarshalBinary() ([]byte, error) {
+	buf := primitives.NewBuffer(nil)
+	buf.PushUInt32(r.DBHeight)
+	buf.Push(r.KeyMR.Bytes())
+	buf.PushVarInt(uint64(len(r.EntryHashes)))
+	for _, h := range r.EntryHashes {
+		buf.Push(h.Bytes())
+	}
+	return buf.DeepCopyBytes(), nil
+}
+
+func (r *chainIndexRecord) UnmarshalBinaryData(data []byte) ([]byte, error) {
+	buf := primitives.NewBuffer(data)
+	var err error
+	if r.DBHeight, err = buf.PopUInt32(); err != nil {
+		return nil, err
+	}
+	keyMR, err := buf.PopLen(32)
+	if err != nil {
+		return nil, err
+	}
+	r.KeyMR = primitives.NewHash(keyMR)
+	n, err := buf.PopVarInt()
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(buf.Len()/32) {
+		return nil, fmt.Errorf("Chain index record has %d entries in %d bytes", n, buf.Len())
+	}
+	r.EntryHashes = make([]interfaces.IHash, 0, n)
+	for i := uint64(0); i < n; i++ {
+		h, err := buf.PopLen(32)
+		if err != nil {
+			return nil, err
+		}
+		r.EntryHashes = append(r.EntryHashes, primitives.NewHash(h))
+	}
+	return buf.DeepCopyBytes(), nil
+}
+
+func (r *chainIndexRecord) UnmarshalBinary(data []byte) error {
+	_, err := r.UnmarshalBinaryData(data)
+	return err
+}
+
+func chainIndexBucket(chainID interfaces.IHash) []byte {
+	bucket := make([]byte, 0, len(CHAIN_INDEX)+32)
+	bucket = append(bucket, CHAIN_INDEX...)
+	return append(bucket, chainID.Bytes()...)
+}
+
+func chainIndexKey(sequence uint32) []byte {
+	key := make([]byte, 4)
+	binary.BigEndian.PutUint32(key, sequence)
+	return key
+}
+
+// chainIndexRecords returns the chain index record of an entry block, none if the block
+// isn't one
+func chainIndexRecords(block interfaces.DatabaseBlockWithEntries) ([]interfaces.Record, error) {
+	eblock, ok := block.(interfaces.IEntryBlock)
+	if !ok {
+		return nil, nil
+	}
+	keyMR, err := eblock.KeyMR()
+	if err != nil {
+		return nil, err
+	}
+	r := new(chainIndexRecord)
+	r.DBHeight = eblock.GetHeader().GetDBHeight()
+	r.KeyMR = keyMR
+	r.EntryHashes = eblock.GetBody().GetEBEntries()
+	return []interfaces.Record{{
+		Bucket: chainIndexBucket(eblock.GetChainID()),
+		Key:    chainIndexKey(eblock.GetHeader().GetEBSequence()),
+		Data:   r,
+	}}, nil
+}
+
+func (db *Overlay) saveChainIndex(block interfaces.DatabaseBlockWithEntries) error {
+	records, err := chainIndexRecords(block)
+	if err != nil || len(records) == 0 {
+		return err
+	}
+	return db.PutInBatch(records)
+}
+
+func (db *Overlay) saveChainIndexMultiBatch(block interfaces.DatabaseBlockWithEntries) error {
+	records, err := chainIndexRecords(block)
+	if err != nil || len(records) == 0 {
+		return err
+	}
+	db.PutInMultiBatch(records)
+	return nil
+}
+
+// deleteChainIndex drops an entry block about to be deleted from the chain index
+func (db *Overlay) deleteChainIndex(eblock interfaces.IEntryBlock) error {
+	return db.Delete(chainIndexBucket(eblock.GetChainID()), chainIndexKey(eblock.GetHeader().GetEBSequence()))
+}
+
+// FetchChainIndex returns the index of up to count entry blocks of a chain, in order from
+// the sequence number start.  It stops short at the head of the chain, or at an entry
+// block with no record.
+func (db *Overlay) FetchChainIndex(chainID interfaces.IHash, start uint32, count int) ([]interfaces.ChainIndexBlock, error) {
+	bucket := chainIndexBucket(chainID)
+	var blocks []interfaces.ChainIndexBlock
+	for seq := start; len(blocks) < count; seq++ {
+		data, err := db.Get(bucket, chainIndexKey(seq), new(chainIndexRecord))
+		if err != nil {
+			return nil, err
+		}
+		if data == nil {
+			break
+		}
+		block := data.(*chainIndexRecord).ChainIndexBlock
+		block.Sequence = seq
+		blocks = append(blocks, block)
+	}
+	return blocks, nil
+}
+
+// FindChainIndexHeight returns the sequence number of the first entry block of a chain at
+// or above a directory block height, searching the chain index.  ok is false if the index
+// lacks blocks of the chain, and can't say.  The sequence is one past the head if the
+// chain has no block that high.
+func (db *Overlay) FindChainIndexHeight(chainID interfaces.IHash, dbheight uint32) (sequence uint32, ok bool, err error) {
+	head, err := db.FetchEBlockHead(chainID)
+	if err != nil || head == nil {
+		return 0, false, err
+	}
+	bucket := chainIndexBucket(chainID)
+	lo, hi := uint32(0), head.GetHeader().GetEBSequence()+1
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		data, err := db.Get(bucket, chainIndexKey(mid), new(chainIndexRecord))
+		if err != nil {
+			return 0, false, err
+		}
+		if data == nil {
+			return 0, false, nil
+		}
+		if data.(*chainIndexRecord).DBHeight < dbheight {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	return lo, true, nil
+}
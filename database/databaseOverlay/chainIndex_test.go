@@ -0,0 +1,65 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package databaseOverlay_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/common/entryBlock"
+	. "github.com/FactomProject/factomd/database/databaseOverlay"
+	"github.com/FactomProject/factomd/database/mapdb"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestChainIndex(t *testing.T) {
+	dbo := NewOverlay(new(mapdb.MapDB))
+	defer dbo.Close()
+
+	// Blocks at heights 0, 2, 4 ...
+	var blocks []*entryBlock.EBlock
+	var prev *entryBlock.EBlock
+	for i := 0; i < 10; i++ {
+		b, _ := testHelper.CreateTestEntryBlock(prev)
+		b.GetHeader().SetEBSequence(uint32(i))
+		b.GetHeader().SetDBHeight(uint32(2 * i))
+		if err := dbo.ProcessEBlockBatch(b, false); err != nil {
+			t.Fatal(err)
+		}
+		blocks = append(blocks, b)
+		prev = b
+	}
+	chainID := blocks[0].GetChainID()
+
+	index, err := dbo.FetchChainIndex(chainID, 3, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(index) != 4 {
+		t.Fatalf("Got %d blocks of the index, expected 4", len(index))
+	}
+	for i, b := range index {
+		want := blocks[3+i]
+		keyMR, _ := want.KeyMR()
+		if b.Sequence != uint32(3+i) || b.DBHeight != want.GetHeader().GetDBHeight() || !b.KeyMR.IsSameAs(keyMR) {
+			t.Errorf("Index of block %d is %+v", 3+i, b)
+		}
+		hashes := want.GetBody().GetEBEntries()
+		if len(b.EntryHashes) != len(hashes) || !b.EntryHashes[0].IsSameAs(hashes[0]) {
+			t.Errorf("Index of block %d has entries %v, expected %v", 3+i, b.EntryHashes, hashes)
+		}
+	}
+
+	// The index stops at the head
+	if index, _ := dbo.FetchChainIndex(chainID, 8, 10); len(index) != 2 {
+		t.Errorf("Got %d blocks past the head", len(index)-2)
+	}
+
+	for _, c := range []struct{ height, sequence uint32 }{{0, 0}, {5, 3}, {6, 3}, {18, 9}, {19, 10}} {
+		seq, ok, err := dbo.FindChainIndexHeight(chainID, c.height)
+		if err != nil || !ok || seq != c.sequence {
+			t.Errorf("First block at or above %d is %d (%v %v), expected %d", c.height, seq, ok, err, c.sequence)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package databaseOverlay
+
+import (
+	"fmt"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+)
+
+// How many blocks ahead are read from the cold store when blocks are being fetched in order
+const prefetchDepth = 8
+
+// MoveToColdStorage moves the blocks saved at a directory block height to the cold store
+// of a tiered database.  Entries are moved with their entry blocks, unless keep returns
+// true for their chain.  The height and secondary indexes stay in the local database, so
+// lookups only go to the cold store once they have found the block they want.
+func (db *Overlay) MoveToColdStorage(dbheight uint32, keep func(chainID interfaces.IHash) bool) error {
+	tiered, ok := db.DB.(interfaces.ITieredDatabase)
+	if !ok {
+		return fmt.Errorf("Database does not support cold storage")
+	}
+
+	bs, err := db.FetchBlockSetByHeight(dbheight)
+	if err != nil {
+		return err
+	}
+	if bs == nil {
+		return nil
+	}
+
+	for _, eblock := range bs.EBlocks {
+		if eblock == nil {
+			continue
+		}
+		chainID := eblock.GetChainID()
+		if keep == nil || !keep(chainID) {
+			for _, h := range eblock.GetEntryHashes() {
+				if h.IsMinuteMarker() {
+					continue
+				}
+				if err := tiered.Demote(chainID.Bytes(), h.Bytes()); err != nil {
+					return err
+				}
+			}
+		}
+		if err := demoteBlock(tiered, ENTRYBLOCK, eblock); err != nil {
+			return err
+		}
+	}
+	if bs.ABlock != nil {
+		if err := demoteBlock(tiered, ADMINBLOCK, bs.ABlock); err != nil {
+			return err
+		}
+	}
+	if bs.ECBlock != nil {
+		if err := demoteBlock(tiered, ENTRYCREDITBLOCK, bs.ECBlock); err != nil {
+			return err
+		}
+	}
+	if bs.FBlock != nil {
+		if err := demoteBlock(tiered, FACTOIDBLOCK, bs.FBlock); err != nil {
+			return err
+		}
+	}
+	// The directory block goes last, so an interrupted move is picked up again from it
+	return demoteBlock(tiered, DIRECTORYBLOCK, bs.DBlock)
+}
+
+func demoteBlock(tiered interfaces.ITieredDatabase, bucket []byte, block interfaces.DatabaseBatchable) error {
+	return tiered.Demote(bucket, block.DatabasePrimaryIndex().Bytes())
+}
+
+// prefetchAfter asks a tiered database to read the next few blocks into its cache when
+// blocks are being fetched by consecutive heights, as when a client walks the chain
+func (db *Overlay) prefetchAfter(heightBucket, blockBucket []byte, blockHeight uint32) {
+	if db.parent != nil {
+		db.parent.prefetchAfter(heightBucket, blockBucket, blockHeight)
+		return
+	}
+	tiered, ok := db.DB.(interfaces.ITieredDatabase)
+	if !ok {
+		return
+	}
+
+	db.prefetchMutex.Lock()
+	if db.lastHeights == nil {
+		db.lastHeights = make(map[string]uint32)
+	}
+	last, seen := db.lastHeights[string(heightBucket)]
+	db.lastHeights[string(heightBucket)] = blockHeight
+	db.prefetchMutex.Unlock()
+
+	if !seen || blockHeight != last+1 {
+		return
+	}
+	// Records already in the cache are skipped by the database, so most of these are cheap
+	keys := make([][]byte, 0, prefetchDepth)
+	for h := blockHeight + 1; h <= blockHeight+prefetchDepth; h++ {
+		index, err := db.FetchBlockIndexByHeight(heightBucket, h)
+		if err != nil || index == nil {
+			break
+		}
+		keys = append(keys, index.Bytes())
+	}
+	if len(keys) > 0 {
+		tiered.Prefetch(blockBucket, keys)
+	}
+}
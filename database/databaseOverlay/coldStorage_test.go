@@ -0,0 +1,67 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package databaseOverlay_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	. "github.com/FactomProject/factomd/database/databaseOverlay"
+	"github.com/FactomProject/factomd/database/tieredDB"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestMoveToColdStorage(t *testing.T) {
+	dbo := testHelper.CreateAndPopulateTestDatabaseOverlay()
+	defer dbo.Close()
+
+	if err := dbo.MoveToColdStorage(1, nil); err == nil {
+		t.Error("Moved blocks without a tiered database")
+	}
+
+	dir, err := ioutil.TempDir("", "coldStorageTest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	cold, err := tieredDB.NewDirColdStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hot := dbo.DB
+	dbo.DB = tieredDB.NewTieredDB(hot, cold, 0)
+
+	before, err := dbo.FetchBlockSetByHeightWithEntries(1)
+	if err != nil || before == nil {
+		t.Fatalf("Could not load block set 1 - %v", err)
+	}
+
+	if err := dbo.MoveToColdStorage(1, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	exist, err := hot.DoesKeyExist(DIRECTORYBLOCK, before.DBlock.DatabasePrimaryIndex().Bytes())
+	if err != nil || exist {
+		t.Errorf("Directory block still in the local database, %v", err)
+	}
+
+	after, err := dbo.FetchBlockSetByHeightWithEntries(1)
+	if err != nil || after == nil {
+		t.Fatalf("Could not load block set 1 from cold storage - %v", err)
+	}
+	if !after.DBlock.GetKeyMR().IsSameAs(before.DBlock.GetKeyMR()) {
+		t.Error("Directory block changed")
+	}
+	if len(after.EBlocks) != len(before.EBlocks) || len(after.Entries) != len(before.Entries) {
+		t.Errorf("Expected %d eblocks and %d entries, found %d and %d", len(before.EBlocks), len(before.Entries), len(after.EBlocks), len(after.Entries))
+	}
+	// Minute markers have no entry, and are nil in both
+	for i, e := range after.Entries {
+		if (e == nil) != (before.Entries[i] == nil) || (e != nil && !e.GetHash().IsSameAs(before.Entries[i].GetHash())) {
+			t.Errorf("Entry %d differs", i)
+		}
+	}
+}
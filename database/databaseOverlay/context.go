@@ -0,0 +1,72 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package databaseOverlay
+
+import (
+	"context"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+)
+
+// contextDB fails every read once its context is done, so a request that has timed out or
+// whose client has gone away stops reading from the database at the next record
+type contextDB struct {
+	interfaces.IDatabase
+	ctx context.Context
+}
+
+func (c *contextDB) Get(bucket, key []byte, destination interfaces.BinaryMarshallable) (interfaces.BinaryMarshallable, error) {
+	if err := c.ctx.Err(); err != nil {
+		return nil, err
+	}
+	return c.IDatabase.Get(bucket, key, destination)
+}
+
+func (c *contextDB) GetAll(bucket []byte, sample interfaces.BinaryMarshallableAndCopyable) ([]interfaces.BinaryMarshallableAndCopyable, [][]byte, error) {
+	if err := c.ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+	return c.IDatabase.GetAll(bucket, sample)
+}
+
+func (c *contextDB) ListAllKeys(bucket []byte) ([][]byte, error) {
+	if err := c.ctx.Err(); err != nil {
+		return nil, err
+	}
+	return c.IDatabase.ListAllKeys(bucket)
+}
+
+func (c *contextDB) ListAllBuckets() ([][]byte, error) {
+	if err := c.ctx.Err(); err != nil {
+		return nil, err
+	}
+	return c.IDatabase.ListAllBuckets()
+}
+
+func (c *contextDB) DoesKeyExist(bucket, key []byte) (bool, error) {
+	if err := c.ctx.Err(); err != nil {
+		return false, err
+	}
+	return c.IDatabase.DoesKeyExist(bucket, key)
+}
+
+// WithContext returns an overlay reading through this one's database for as long as ctx
+// lasts; once ctx is done its fetches fail with ctx.Err().  It shares the read cache and
+// indexes of this overlay, and is meant for the reads of a single API request: multi
+// batches are to be written through the overlay itself.
+func (db *Overlay) WithContext(ctx context.Context) interfaces.DBOverlaySimple {
+	answer := new(Overlay)
+	answer.DB = &contextDB{IDatabase: db.DB, ctx: ctx}
+	answer.ExportData = db.ExportData
+	answer.ExportDataPath = db.ExportDataPath
+	answer.BlockExtractor = db.BlockExtractor
+	answer.readCache = db.readCache
+	answer.indexReferences = db.indexReferences
+	answer.indexAddresses = db.indexAddresses
+	answer.indexExtIDs = db.indexExtIDs
+	answer.objectStore = db.objectStore
+	answer.parent = db
+	return answer
+}
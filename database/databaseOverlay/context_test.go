@@ -0,0 +1,38 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package databaseOverlay_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestWithContext(t *testing.T) {
+	dbo := testHelper.CreateAndPopulateTestDatabaseOverlay()
+	defer dbo.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	bound := dbo.WithContext(ctx)
+	block, err := bound.FetchDBlockByHeight(1)
+	if err != nil || block == nil {
+		t.Fatalf("Could not load dblock 1 - %v", err)
+	}
+
+	// Once the context is done, so are the reads
+	cancel()
+	if _, err := bound.FetchDBlockByHeight(2); err != context.Canceled {
+		t.Errorf("Fetched after the context was cancelled - %v", err)
+	}
+	if _, err := bound.FetchEntry(block.GetKeyMR()); err != context.Canceled {
+		t.Errorf("Fetched after the context was cancelled - %v", err)
+	}
+
+	// The overlay itself reads on
+	if block, err := dbo.FetchDBlockByHeight(2); err != nil || block == nil {
+		t.Errorf("Could not load dblock 2 - %v", err)
+	}
+}
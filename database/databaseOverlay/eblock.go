@@ -18,6 +18,10 @@ func (db *Overlay) ProcessEBlockBatch(eblock interfaces.DatabaseBlockWithEntries
 	if err != nil {
 		return err
 	}
+	err = db.saveChainIndex(eblock)
+	if err != nil {
+		return err
+	}
 	return db.SaveIncludedInMultiFromBlock(eblock, checkForDuplicateEntries)
 }
 
@@ -28,6 +32,10 @@ func (db *Overlay) ProcessEBlockBatchWithoutHead(eblock interfaces.DatabaseBlock
 	if err != nil {
 		return err
 	}
+	err = db.saveChainIndex(eblock)
+	if err != nil {
+		return err
+	}
 	return db.SaveIncludedInMultiFromBlock(eblock, checkForDuplicateEntries)
 }
 
@@ -38,6 +46,10 @@ func (db *Overlay) ProcessEBlockMultiBatchWithoutHead(eblock interfaces.Database
 	if err != nil {
 		return err
 	}
+	err = db.saveChainIndexMultiBatch(eblock)
+	if err != nil {
+		return err
+	}
 	return db.SaveIncludedInMultiFromBlockMultiBatch(eblock, checkForDuplicateEntries)
 }
 
@@ -48,6 +60,10 @@ func (db *Overlay) ProcessEBlockMultiBatch(eblock interfaces.DatabaseBlockWithEn
 	if err != nil {
 		return err
 	}
+	err = db.saveChainIndexMultiBatch(eblock)
+	if err != nil {
+		return err
+	}
 	return db.SaveIncludedInMultiFromBlockMultiBatch(eblock, checkForDuplicateEntries)
 }
 
@@ -19,6 +19,8 @@ func (db *Overlay) InsertEntry(entry interfaces.IEBEntry) error {
 	batch := []interfaces.Record{}
 	batch = append(batch, interfaces.Record{entry.GetChainID().Bytes(), entry.DatabasePrimaryIndex().Bytes(), entry})
 	batch = append(batch, interfaces.Record{ENTRY, entry.DatabasePrimaryIndex().Bytes(), entry.GetChainIDHash()})
+	batch = append(batch, db.referenceRecords(entry)...)
+	batch = append(batch, db.extIDIndexRecords(entry)...)
 
 	err := db.PutInBatch(batch)
 	if err != nil {
@@ -42,6 +44,8 @@ func (db *Overlay) InsertEntryMultiBatch(entry interfaces.IEBEntry) error {
 	batch := []interfaces.Record{}
 	batch = append(batch, interfaces.Record{entry.GetChainID().Bytes(), entry.DatabasePrimaryIndex().Bytes(), entry})
 	batch = append(batch, interfaces.Record{ENTRY, entry.DatabasePrimaryIndex().Bytes(), entry.GetChainIDHash()})
+	batch = append(batch, db.referenceRecords(entry)...)
+	batch = append(batch, db.extIDIndexRecords(entry)...)
 
 	db.PutInMultiBatch(batch)
 	if entry.GetChainID().String() == AnchorBlockID {
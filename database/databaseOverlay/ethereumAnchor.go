@@ -0,0 +1,77 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package databaseOverlay
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/FactomProject/factomd/anchor"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+var (
+	// Anchor records of directory blocks anchored into Ethereum, by KeyMR
+	ETHEREUM_ANCHOR = []byte("EthereumAnchor")
+)
+
+// ethereumAnchorRecord stores an anchor record as the JSON it is written to the anchor
+// chain as
+type ethereumAnchorRecord struct {
+	anchor.AnchorRecord
+}
+
+func (r *ethereumAnchorRecord) MarshalBinary() ([]byte, error) {
+	return r.Marshal()
+}
+
+func (r *ethereumAnchorRecord) UnmarshalBinaryData(data []byte) ([]byte, error) {
+	return nil, json.Unmarshal(data, &r.AnchorRecord)
+}
+
+func (r *ethereumAnchorRecord) UnmarshalBinary(data []byte) error {
+	_, err := r.UnmarshalBinaryData(data)
+	return err
+}
+
+func ethereumAnchorKey(ar *anchor.AnchorRecord) ([]byte, error) {
+	if ar.Ethereum == nil {
+		return nil, fmt.Errorf("Anchor record of dbheight %d has no Ethereum anchor", ar.DBHeight)
+	}
+	keyMR, err := primitives.NewShaHashFromStr(ar.KeyMR)
+	if err != nil {
+		return nil, err
+	}
+	return keyMR.Bytes(), nil
+}
+
+// SaveEthereumAnchor saves where a directory block was anchored into Ethereum
+func (dbo *Overlay) SaveEthereumAnchor(ar *anchor.AnchorRecord) error {
+	key, err := ethereumAnchorKey(ar)
+	if err != nil {
+		return err
+	}
+	return dbo.Put(ETHEREUM_ANCHOR, key, &ethereumAnchorRecord{*ar})
+}
+
+func (dbo *Overlay) saveEthereumAnchorMultiBatch(ar *anchor.AnchorRecord) error {
+	key, err := ethereumAnchorKey(ar)
+	if err != nil {
+		return err
+	}
+	dbo.PutInMultiBatch([]interfaces.Record{{Bucket: ETHEREUM_ANCHOR, Key: key, Data: &ethereumAnchorRecord{*ar}}})
+	return nil
+}
+
+// FetchEthereumAnchor returns the anchor record of a directory block anchored into
+// Ethereum, or nil if we don't know of one.  The record is an *anchor.AnchorRecord.
+func (dbo *Overlay) FetchEthereumAnchor(keyMR interfaces.IHash) (interfaces.IAnchorRecord, error) {
+	data, err := dbo.Get(ETHEREUM_ANCHOR, keyMR.Bytes(), new(ethereumAnchorRecord))
+	if err != nil || data == nil {
+		return nil, err
+	}
+	return &data.(*ethereumAnchorRecord).AnchorRecord, nil
+}
@@ -0,0 +1,50 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package databaseOverlay_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/anchor"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestSaveFetchEthereumAnchor(t *testing.T) {
+	dbo := testHelper.CreateEmptyTestDatabaseOverlay()
+	keyMR, _ := primitives.HexToHash("637b6010cb6121f76c65b200a6cf94cb6655881fb4cac48979f8950e7a349da1")
+
+	record, err := dbo.FetchEthereumAnchor(keyMR)
+	if err != nil || record != nil {
+		t.Errorf("Expected no anchor, got %v, %v", record, err)
+	}
+
+	ar := new(anchor.AnchorRecord)
+	ar.AnchorRecordVer = 1
+	ar.DBHeight = 8
+	ar.KeyMR = keyMR.String()
+	ar.RecordHeight = 8
+	if err := dbo.SaveEthereumAnchor(ar); err == nil {
+		t.Errorf("Saved an anchor record with no Ethereum anchor")
+	}
+	ar.Ethereum = &anchor.EthereumStruct{Address: "0x30aa981f6d2fce81083e584c8ee2f822b548752f", TXID: "0x50ea", BlockHeight: 293003}
+	if err := dbo.SaveEthereumAnchor(ar); err != nil {
+		t.Fatal(err)
+	}
+
+	record, err = dbo.FetchEthereumAnchor(keyMR)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, ok := record.(*anchor.AnchorRecord)
+	if !ok || got.DBHeight != 8 || got.Ethereum == nil || got.Ethereum.BlockHeight != 293003 {
+		t.Errorf("Wrong anchor fetched: %v", record)
+	}
+
+	// Records with only an Ethereum anchor leave the Bitcoin dirblock info alone
+	if err := dbo.SaveAnchorInfoAsDirBlockInfo([]*anchor.AnchorRecord{ar}); err != nil {
+		t.Error(err)
+	}
+}
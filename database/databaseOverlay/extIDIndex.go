@@ -0,0 +1,116 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package databaseOverlay
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"sort"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+var (
+	// The entries of each chain by their external IDs, in a bucket per chain, keyed by the
+	// external ID and the entry hash
+	EXTID_INDEX = []byte("ExtIDIndex")
+)
+
+// Most bytes of an external ID indexed; longer ones are indexed by their start
+const MaxIndexedExtIDLength = 256
+
+var (
+	ErrNoExtIDIndex       = errors.New("The external ID index is not enabled")
+	ErrExtIDPrefixTooLong = errors.New("External ID prefixes are indexed up to 256 bytes")
+)
+
+// EnableExtIDIndex records, as entries are saved, the external IDs of each, so the entries
+// of a chain can be found by them.  It must be called before the overlay is shared.  Only
+// entries saved after it is enabled are indexed.
+func (db *Overlay) EnableExtIDIndex() {
+	db.indexExtIDs = true
+}
+
+func extIDIndexBucket(chainID interfaces.IHash) []byte {
+	bucket := make([]byte, 0, len(EXTID_INDEX)+32)
+	bucket = append(bucket, EXTID_INDEX...)
+	return append(bucket, chainID.Bytes()...)
+}
+
+func extIDIndexKey(extID []byte, entryHash interfaces.IHash) []byte {
+	if len(extID) > MaxIndexedExtIDLength {
+		extID = extID[:MaxIndexedExtIDLength]
+	}
+	key := make([]byte, 0, len(extID)+32)
+	key = append(key, extID...)
+	return append(key, entryHash.Bytes()...)
+}
+
+// extIDIndexRecords returns the records indexing an entry by its external IDs, none if the
+// index isn't enabled
+func (db *Overlay) extIDIndexRecords(entry interfaces.IEBEntry) []interfaces.Record {
+	if !db.indexExtIDs {
+		return nil
+	}
+	var records []interfaces.Record
+	bucket := extIDIndexBucket(entry.GetChainID())
+	for _, extID := range entry.ExternalIDs() {
+		records = append(records, interfaces.Record{Bucket: bucket, Key: extIDIndexKey(extID, entry.GetHash()), Data: entry.GetHash()})
+	}
+	return records
+}
+
+// deleteExtIDIndex drops an entry about to be deleted from the external ID index
+func (db *Overlay) deleteExtIDIndex(entryHash interfaces.IHash) error {
+	if !db.indexExtIDs {
+		return nil
+	}
+	entry, err := db.FetchEntry(entryHash)
+	if err != nil || entry == nil {
+		return err
+	}
+	bucket := extIDIndexBucket(entry.GetChainID())
+	for _, extID := range entry.ExternalIDs() {
+		if err := db.Delete(bucket, extIDIndexKey(extID, entryHash)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FetchEntriesByExtID returns up to count of the entries of a chain with an external ID
+// starting with prefix, in external ID order from the start'th, and how many there are in
+// all.  An entry matching with more than one of its external IDs is listed for each.
+func (db *Overlay) FetchEntriesByExtID(chainID interfaces.IHash, prefix []byte, start, count int) ([]interfaces.ExtIDMatch, int, error) {
+	if !db.indexExtIDs {
+		return nil, 0, ErrNoExtIDIndex
+	}
+	if len(prefix) > MaxIndexedExtIDLength {
+		return nil, 0, ErrExtIDPrefixTooLong
+	}
+	keys, err := db.ListAllKeys(extIDIndexBucket(chainID))
+	if err != nil {
+		return nil, 0, err
+	}
+	var matched [][]byte
+	for _, key := range keys {
+		if len(key) >= 32 && bytes.HasPrefix(key[:len(key)-32], prefix) {
+			matched = append(matched, key)
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool { return bytes.Compare(matched[i], matched[j]) < 0 })
+
+	entries := []interfaces.ExtIDMatch{}
+	for i := start; i >= 0 && i < len(matched) && len(entries) < count; i++ {
+		key := matched[i]
+		entries = append(entries, interfaces.ExtIDMatch{
+			EntryHash: primitives.NewHash(key[len(key)-32:]),
+			ExtID:     hex.EncodeToString(key[:len(key)-32]),
+		})
+	}
+	return entries, len(matched), nil
+}
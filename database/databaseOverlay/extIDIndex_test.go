@@ -0,0 +1,67 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package databaseOverlay_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/FactomProject/factomd/common/primitives"
+	. "github.com/FactomProject/factomd/database/databaseOverlay"
+	"github.com/FactomProject/factomd/database/mapdb"
+)
+
+func TestExtIDIndex(t *testing.T) {
+	dbo := NewOverlay(new(mapdb.MapDB))
+	defer dbo.Close()
+
+	chainID := primitives.RandomHash()
+	if _, _, err := dbo.FetchEntriesByExtID(chainID, nil, 0, 10); err != ErrNoExtIDIndex {
+		t.Errorf("Expected ErrNoExtIDIndex, got %v", err)
+	}
+
+	dbo.EnableExtIDIndex()
+	long := bytes.Repeat([]byte{'x'}, MaxIndexedExtIDLength+10)
+	invoice1 := newReferenceTestEntry(chainID, [][]byte{[]byte("invoice-1"), []byte("customer-a")}, "one")
+	invoice2 := newReferenceTestEntry(chainID, [][]byte{[]byte("invoice-2"), long}, "two")
+	elsewhere := newReferenceTestEntry(primitives.RandomHash(), [][]byte{[]byte("invoice-3")}, "three")
+	for _, err := range []error{dbo.InsertEntry(invoice1), dbo.InsertEntry(invoice2), dbo.InsertEntry(elsewhere)} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	found, total, err := dbo.FetchEntriesByExtID(chainID, []byte("invoice-"), 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 2 || len(found) != 2 || !found[0].EntryHash.IsSameAs(invoice1.GetHash()) || !found[1].EntryHash.IsSameAs(invoice2.GetHash()) {
+		t.Errorf("Wrong entries for the prefix: %d %v", total, found)
+	}
+	if found[0].ExtID != hex.EncodeToString([]byte("invoice-1")) {
+		t.Errorf("Wrong external ID %s", found[0].ExtID)
+	}
+
+	// Paging
+	found, total, _ = dbo.FetchEntriesByExtID(chainID, []byte("invoice-"), 1, 10)
+	if total != 2 || len(found) != 1 || !found[0].EntryHash.IsSameAs(invoice2.GetHash()) {
+		t.Errorf("Wrong second page: %d %v", total, found)
+	}
+
+	// Long external IDs are found by their start
+	found, _, _ = dbo.FetchEntriesByExtID(chainID, long[:MaxIndexedExtIDLength], 0, 10)
+	if len(found) != 1 || !found[0].EntryHash.IsSameAs(invoice2.GetHash()) {
+		t.Errorf("Long external ID not found: %v", found)
+	}
+	if _, _, err := dbo.FetchEntriesByExtID(chainID, long, 0, 10); err != ErrExtIDPrefixTooLong {
+		t.Errorf("Expected ErrExtIDPrefixTooLong, got %v", err)
+	}
+
+	// Every external ID of the chain, with an empty prefix
+	if _, total, _ = dbo.FetchEntriesByExtID(chainID, nil, 0, 10); total != 4 {
+		t.Errorf("Expected 4 external IDs in the chain, found %d", total)
+	}
+}
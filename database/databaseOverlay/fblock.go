@@ -13,6 +13,10 @@ func (db *Overlay) ProcessFBlockBatch(block interfaces.DatabaseBlockWithEntries)
 	if err != nil {
 		return err
 	}
+	err = db.saveAddressIndex(block)
+	if err != nil {
+		return err
+	}
 	return db.SaveIncludedInMultiFromBlock(block, false)
 }
 
@@ -21,6 +25,10 @@ func (db *Overlay) ProcessFBlockBatchWithoutHead(block interfaces.DatabaseBlockW
 	if err != nil {
 		return err
 	}
+	err = db.saveAddressIndex(block)
+	if err != nil {
+		return err
+	}
 	return db.SaveIncludedInMultiFromBlock(block, false)
 }
 
@@ -29,6 +37,7 @@ func (db *Overlay) ProcessFBlockMultiBatch(block interfaces.DatabaseBlockWithEnt
 	if err != nil {
 		return err
 	}
+	db.saveAddressIndexMultiBatch(block)
 	return db.SaveIncludedInMultiFromBlockMultiBatch(block, true)
 }
 
@@ -119,6 +119,11 @@ var (
 		Name: "factomd_database_overlay_gets_paidfor",
 		Help: "Counts gets from the database",
 	})
+
+	OverlayReadCacheVec = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "factomd_database_overlay_read_cache_vec",
+		Help: "Counts gets through the read cache, by hit, negative (a remembered miss) or miss",
+	}, []string{"result"})
 )
 
 var registered = false
@@ -154,6 +159,7 @@ func RegisterPrometheus() {
 	prometheus.MustRegister(OverlayDBGetsDirBlockInfoSecondary)
 	prometheus.MustRegister(OverlayDBGetsInvludeIn)
 	prometheus.MustRegister(OverlayDBGetsPaidFor)
+	prometheus.MustRegister(OverlayReadCacheVec)
 }
 
 func GetBucket(bucket []byte) {
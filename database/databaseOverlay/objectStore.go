@@ -0,0 +1,135 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package databaseOverlay
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+var (
+	// Auxiliary data stored by co-located services, in a bucket per namespace
+	OBJECT_STORE = []byte("ObjectStore")
+)
+
+// Longest namespace of the object store
+const MaxObjectNamespace = 64
+
+var (
+	ErrNoObjectStore       = errors.New("The object store is not enabled")
+	ErrInvalidNamespace    = fmt.Errorf("Namespaces are 1 to %d letters, digits, '.', '-' or '_'", MaxObjectNamespace)
+	ErrObjectTooLarge      = errors.New("The object is larger than the object store allows")
+	ErrObjectNamespaceFull = errors.New("The namespace holds as many objects as the object store allows")
+)
+
+type objectStoreLimits struct {
+	maxSize    int
+	maxObjects int
+}
+
+// EnableObjectStore lets services store objects of up to maxSize bytes, and up to
+// maxObjects in a namespace, keyed by hash; 0 for no limit.  It must be called before the
+// overlay is shared.
+func (db *Overlay) EnableObjectStore(maxSize, maxObjects int) {
+	db.objectStore = &objectStoreLimits{maxSize: maxSize, maxObjects: maxObjects}
+}
+
+func validObjectNamespace(namespace string) bool {
+	if len(namespace) == 0 || len(namespace) > MaxObjectNamespace {
+		return false
+	}
+	for _, c := range namespace {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '.', c == '-', c == '_':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func objectBucket(namespace string) []byte {
+	bucket := make([]byte, 0, len(OBJECT_STORE)+1+len(namespace))
+	bucket = append(bucket, OBJECT_STORE...)
+	bucket = append(bucket, ':')
+	return append(bucket, namespace...)
+}
+
+// checkObjectNamespace returns the error for a request of the namespace, if any
+func (db *Overlay) checkObjectNamespace(namespace string) error {
+	if db.objectStore == nil {
+		return ErrNoObjectStore
+	}
+	if !validObjectNamespace(namespace) {
+		return ErrInvalidNamespace
+	}
+	return nil
+}
+
+// FetchObject returns an object of a namespace, nil if there is none
+func (db *Overlay) FetchObject(namespace string, key interfaces.IHash) ([]byte, error) {
+	if err := db.checkObjectNamespace(namespace); err != nil {
+		return nil, err
+	}
+	answer, err := db.Get(objectBucket(namespace), key.Bytes(), new(primitives.ByteSlice))
+	if err != nil || answer == nil {
+		return nil, err
+	}
+	return answer.(*primitives.ByteSlice).Bytes, nil
+}
+
+// SaveObject stores an object in a namespace, replacing any under the same key
+func (db *Overlay) SaveObject(namespace string, key interfaces.IHash, data []byte) error {
+	if err := db.checkObjectNamespace(namespace); err != nil {
+		return err
+	}
+	if db.objectStore.maxSize > 0 && len(data) > db.objectStore.maxSize {
+		return ErrObjectTooLarge
+	}
+	bucket := objectBucket(namespace)
+	if db.objectStore.maxObjects > 0 {
+		exists, err := db.DoesKeyExist(bucket, key.Bytes())
+		if err != nil {
+			return err
+		}
+		if !exists {
+			keys, err := db.ListAllKeys(bucket)
+			if err != nil {
+				return err
+			}
+			if len(keys) >= db.objectStore.maxObjects {
+				return ErrObjectNamespaceFull
+			}
+		}
+	}
+	return db.Put(bucket, key.Bytes(), &primitives.ByteSlice{Bytes: data})
+}
+
+// DeleteObject removes an object from a namespace
+func (db *Overlay) DeleteObject(namespace string, key interfaces.IHash) error {
+	if err := db.checkObjectNamespace(namespace); err != nil {
+		return err
+	}
+	return db.Delete(objectBucket(namespace), key.Bytes())
+}
+
+// FetchObjectKeys returns the keys of the objects in a namespace
+func (db *Overlay) FetchObjectKeys(namespace string) ([]interfaces.IHash, error) {
+	if err := db.checkObjectNamespace(namespace); err != nil {
+		return nil, err
+	}
+	keys, err := db.ListAllKeys(objectBucket(namespace))
+	if err != nil {
+		return nil, err
+	}
+	hashes := make([]interfaces.IHash, 0, len(keys))
+	for _, key := range keys {
+		hashes = append(hashes, primitives.NewHash(key))
+	}
+	return hashes, nil
+}
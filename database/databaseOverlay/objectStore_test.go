@@ -0,0 +1,69 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package databaseOverlay_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/FactomProject/factomd/common/primitives"
+	. "github.com/FactomProject/factomd/database/databaseOverlay"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestObjectStore(t *testing.T) {
+	dbo := testHelper.CreateEmptyTestDatabaseOverlay()
+	defer dbo.Close()
+	key := primitives.RandomHash()
+
+	if err := dbo.SaveObject("index", key, []byte("data")); err != ErrNoObjectStore {
+		t.Errorf("Expected %v before the store is enabled, got %v", ErrNoObjectStore, err)
+	}
+
+	dbo.EnableObjectStore(8, 2)
+	if err := dbo.SaveObject("index", key, []byte("data")); err != nil {
+		t.Fatal(err)
+	}
+	data, err := dbo.FetchObject("index", key)
+	if err != nil || !bytes.Equal(data, []byte("data")) {
+		t.Errorf("Fetched %q - %v", data, err)
+	}
+	// Namespaces are kept apart
+	if data, err := dbo.FetchObject("other", key); err != nil || data != nil {
+		t.Errorf("Fetched %q from another namespace - %v", data, err)
+	}
+
+	for _, ns := range []string{"", "a/b", "name space", string(make([]byte, MaxObjectNamespace+1))} {
+		if err := dbo.SaveObject(ns, key, nil); err != ErrInvalidNamespace {
+			t.Errorf("Namespace %q: expected %v, got %v", ns, ErrInvalidNamespace, err)
+		}
+	}
+	if err := dbo.SaveObject("index", key, make([]byte, 9)); err != ErrObjectTooLarge {
+		t.Errorf("Expected %v, got %v", ErrObjectTooLarge, err)
+	}
+
+	second := primitives.RandomHash()
+	if err := dbo.SaveObject("index", second, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := dbo.SaveObject("index", primitives.RandomHash(), nil); err != ErrObjectNamespaceFull {
+		t.Errorf("Expected %v, got %v", ErrObjectNamespaceFull, err)
+	}
+	// Replacing an object doesn't need room
+	if err := dbo.SaveObject("index", key, []byte("new")); err != nil {
+		t.Error(err)
+	}
+
+	keys, err := dbo.FetchObjectKeys("index")
+	if err != nil || len(keys) != 2 {
+		t.Errorf("Listed %v - %v", keys, err)
+	}
+	if err := dbo.DeleteObject("index", key); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := dbo.FetchObject("index", key); err != nil || data != nil {
+		t.Errorf("Fetched %q after deleting it - %v", data, err)
+	}
+}
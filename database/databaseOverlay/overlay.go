@@ -105,6 +105,12 @@ func init() {
 
 	ConstantNamesMap[string(PAID_FOR)] = "PaidFor"
 
+	ConstantNamesMap[string(CHAIN_INDEX)] = "ChainIndex"
+
+	ConstantNamesMap[string(ADDRESS_INDEX)] = "AddressIndex"
+
+	ConstantNamesMap[string(EXTID_INDEX)] = "ExtIDIndex"
+
 	RegisterPrometheus()
 }
 
@@ -117,10 +123,36 @@ type Overlay struct {
 	BatchSemaphore sync.Mutex
 	MultiBatch     []interfaces.Record
 	BlockExtractor blockExtractor.BlockExtractor
+
+	// Held while entry blocks are pruned or rolled back, so the two don't interleave
+	pruneMutex sync.Mutex
+
+	// Last height fetched from each height bucket, to spot sequential reads
+	prefetchMutex sync.Mutex
+	lastHeights   map[string]uint32
+
+	// Hot records and failed lookups kept in memory; nil unless EnableReadCache is called
+	readCache *readCache
+
+	// Index the entries and chains referred to by saved entries; see EnableReferenceIndex
+	indexReferences bool
+
+	// Index the factoid transactions by the addresses they touch; see EnableAddressIndex
+	indexAddresses bool
+
+	// Index the entries of each chain by their external IDs; see EnableExtIDIndex
+	indexExtIDs bool
+
+	// Limits of the object store; nil unless EnableObjectStore is called
+	objectStore *objectStoreLimits
+
+	// The overlay this one was made from by WithContext, which keeps track of prefetching
+	parent *Overlay
 }
 
 var _ interfaces.IDatabase = (*Overlay)(nil)
 var _ interfaces.DBOverlay = (*Overlay)(nil)
+var _ interfaces.ContextDBOverlay = (*Overlay)(nil)
 
 func (db *Overlay) ListAllBuckets() ([][]byte, error) {
 	return db.DB.ListAllBuckets()
@@ -150,11 +182,22 @@ func (db *Overlay) ExecuteMultiBatch() error {
 }
 
 func (db *Overlay) PutInBatch(records []interfaces.Record) error {
-	return db.DB.PutInBatch(records)
+	for _, r := range records {
+		db.uncache(r.Bucket, r.Key)
+	}
+	err := db.DB.PutInBatch(records)
+	// Again, as a read that started during the write could have cached what it replaced
+	for _, r := range records {
+		db.uncache(r.Bucket, r.Key)
+	}
+	return err
 }
 
 func (db *Overlay) Put(bucket, key []byte, data interfaces.BinaryMarshallable) error {
-	return db.DB.Put(bucket, key, data)
+	db.uncache(bucket, key)
+	err := db.DB.Put(bucket, key, data)
+	db.uncache(bucket, key)
+	return err
 }
 
 func (db *Overlay) ListAllKeys(bucket []byte) ([][]byte, error) {
@@ -167,11 +210,22 @@ func (db *Overlay) GetAll(bucket []byte, sample interfaces.BinaryMarshallableAnd
 
 func (db *Overlay) Get(bucket, key []byte, destination interfaces.BinaryMarshallable) (interfaces.BinaryMarshallable, error) {
 	GetBucket(bucket)
+	if db.readCache != nil && readCacheBuckets[string(bucket)] {
+		return db.readCache.get(db.DB, bucket, key, destination)
+	}
 	return db.DB.Get(bucket, key, destination)
 }
 
 func (db *Overlay) Clear(bucket []byte) error {
-	return db.DB.Clear(bucket)
+	cached := db.readCache != nil && readCacheBuckets[string(bucket)]
+	if cached {
+		db.readCache.clear()
+	}
+	err := db.DB.Clear(bucket)
+	if cached {
+		db.readCache.clear()
+	}
+	return err
 }
 
 func (db *Overlay) Close() (err error) {
@@ -186,7 +240,10 @@ func (db *Overlay) Trim() {
 }
 
 func (db *Overlay) Delete(bucket, key []byte) error {
-	return db.DB.Delete(bucket, key)
+	db.uncache(bucket, key)
+	err := db.DB.Delete(bucket, key)
+	db.uncache(bucket, key)
+	return err
 }
 
 func NewOverlay(db interfaces.IDatabase) *Overlay {
@@ -203,6 +260,7 @@ func (db *Overlay) FetchBlockByHeight(heightBucket []byte, blockBucket []byte, b
 	if index == nil {
 		return nil, nil
 	}
+	db.prefetchAfter(heightBucket, blockBucket, blockHeight)
 	return db.FetchBlock(blockBucket, index, dst)
 }
 
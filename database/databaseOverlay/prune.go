@@ -0,0 +1,181 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package databaseOverlay
+
+import (
+	"encoding/binary"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+var (
+	// Directory block height below which entry blocks have been pruned
+	PRUNING        = []byte("Pruning")
+	PRUNING_HEIGHT = []byte("PrunedHeight")
+)
+
+// PruneEntryBlocks deletes the entry blocks saved at directory block heights from up to,
+// but not including, to, with the entries first included in them that no entry block of
+// the chain left standing holds again, unless keep returns true for their chain.  The entry
+// block a chain head points at is kept, so every chain still has a head to look up and
+// extend.  Directory, admin, factoid and entry credit blocks are all kept; they are a
+// small part of the database, and the balances are rebuilt from them on boot.  The pruned
+// height is saved once the range is done.
+func (db *Overlay) PruneEntryBlocks(from, to uint32, keep func(chainID interfaces.IHash) bool) error {
+	db.pruneMutex.Lock()
+	defer db.pruneMutex.Unlock()
+
+	// The entry blocks to delete, by chain, in the order of their heights
+	pruned := make(map[[32]byte][]interfaces.IEntryBlock)
+	for h := from; h < to; h++ {
+		bs, err := db.FetchBlockSetByHeight(h)
+		if err != nil {
+			return err
+		}
+		if bs == nil {
+			continue
+		}
+		for _, eblock := range bs.EBlocks {
+			if eblock == nil {
+				continue
+			}
+			chainID := eblock.GetChainID()
+			if keep != nil && keep(chainID) {
+				continue
+			}
+			head, err := db.FetchHeadIndexByChainID(chainID)
+			if err != nil {
+				return err
+			}
+			if head != nil && head.IsSameAs(eblock.DatabasePrimaryIndex()) {
+				continue
+			}
+			pruned[chainID.Fixed()] = append(pruned[chainID.Fixed()], eblock)
+		}
+	}
+
+	for _, eblocks := range pruned {
+		kept, err := db.entriesKeptLater(eblocks)
+		if err != nil {
+			return err
+		}
+		for _, eblock := range eblocks {
+			err = db.deleteEBlockEntries(eblock, kept)
+			if err != nil {
+				return err
+			}
+			err = db.deleteChainIndex(eblock)
+			if err != nil {
+				return err
+			}
+			numberBucket := append(ENTRYBLOCK_CHAIN_NUMBER, eblock.GetChainID().Bytes()...)
+			err = db.deleteIndexes(ENTRYBLOCK, numberBucket, ENTRYBLOCK_SECONDARYINDEX, eblock.GetDatabaseHeight(),
+				eblock.DatabasePrimaryIndex(), eblock.DatabaseSecondaryIndex())
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if to > from {
+		return db.SavePrunedHeight(to)
+	}
+	return nil
+}
+
+// entriesKeptLater returns the entries of the entry blocks of a chain about to be pruned
+// that show up again in a later entry block of the chain that stays.  Pruning goes up from
+// the bottom, so every block above the first pruned one is read once, going forward, and
+// those not pruned along with it hold entries that must stay.  An entry hash covers its
+// chain ID, so no other chain can hold it.
+func (db *Overlay) entriesKeptLater(eblocks []interfaces.IEntryBlock) (map[[32]byte]bool, error) {
+	chainID := eblocks[0].GetChainID()
+	numberBucket := append(ENTRYBLOCK_CHAIN_NUMBER, chainID.Bytes()...)
+	indexBucket := chainIndexBucket(chainID)
+
+	hashes := make(map[[32]byte]bool)
+	prunedSeqs := make(map[uint32]bool)
+	first := eblocks[0].GetHeader().GetEBSequence()
+	for _, eblock := range eblocks {
+		seq := eblock.GetHeader().GetEBSequence()
+		prunedSeqs[seq] = true
+		if seq < first {
+			first = seq
+		}
+		for _, e := range eblock.GetEntryHashes() {
+			if !e.IsMinuteMarker() {
+				hashes[e.Fixed()] = true
+			}
+		}
+	}
+	// Blocks of the chain left missing by a pass cut short are passed over, up to the head
+	headKeyMR, err := db.FetchHeadIndexByChainID(chainID)
+	if err != nil || headKeyMR == nil {
+		return nil, err
+	}
+	head, err := db.FetchEBlock(headKeyMR)
+	if err != nil || head == nil {
+		return nil, err
+	}
+	last := head.GetHeader().GetEBSequence()
+
+	kept := make(map[[32]byte]bool)
+	for seq := first + 1; seq <= last && len(kept) < len(hashes); seq++ {
+		if prunedSeqs[seq] {
+			continue
+		}
+		var later []interfaces.IHash
+		// The chain index holds the entry hashes without the block; older blocks have
+		// no record, so are fetched
+		data, err := db.Get(indexBucket, chainIndexKey(seq), new(chainIndexRecord))
+		if err != nil {
+			return nil, err
+		}
+		if data != nil {
+			later = data.(*chainIndexRecord).EntryHashes
+		} else {
+			keyMR, err := db.FetchBlockIndexByHeight(numberBucket, seq)
+			if err != nil {
+				return nil, err
+			}
+			if keyMR == nil {
+				continue
+			}
+			block, err := db.FetchEBlock(keyMR)
+			if err != nil {
+				return nil, err
+			}
+			if block == nil {
+				continue
+			}
+			later = block.GetEntryHashes()
+		}
+		for _, e := range later {
+			if hashes[e.Fixed()] {
+				kept[e.Fixed()] = true
+			}
+		}
+	}
+	return kept, nil
+}
+
+// FetchPrunedHeight returns the height below which entry blocks have been pruned
+func (db *Overlay) FetchPrunedHeight() (uint32, error) {
+	answer, err := db.Get(PRUNING, PRUNING_HEIGHT, new(primitives.ByteSlice))
+	if err != nil || answer == nil {
+		return 0, err
+	}
+	data := answer.(*primitives.ByteSlice).Bytes
+	if len(data) != 4 {
+		return 0, nil
+	}
+	return binary.BigEndian.Uint32(data), nil
+}
+
+func (db *Overlay) SavePrunedHeight(height uint32) error {
+	data := make([]byte, 4)
+	binary.BigEndian.PutUint32(data, height)
+	return db.Put(PRUNING, PRUNING_HEIGHT, &primitives.ByteSlice{Bytes: data})
+}
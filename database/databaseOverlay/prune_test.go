@@ -0,0 +1,141 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package databaseOverlay_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/common/entryBlock"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestPruneEntryBlocks(t *testing.T) {
+	dbo := testHelper.CreateAndPopulateTestDatabaseOverlay()
+	defer dbo.Close()
+
+	target := uint32(1)
+	bs, err := dbo.FetchBlockSetByHeightWithEntries(target)
+	if err != nil || bs == nil || len(bs.EBlocks) == 0 {
+		t.Fatalf("Could not load block set %d - %v", target, err)
+	}
+	pinned := bs.EBlocks[0].GetChainID()
+	keep := func(chainID interfaces.IHash) bool { return chainID.IsSameAs(pinned) }
+
+	err = dbo.PruneEntryBlocks(target, target+1, keep)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, eb := range bs.EBlocks {
+		keyMR := eb.DatabasePrimaryIndex()
+		head, err := dbo.FetchHeadIndexByChainID(eb.GetChainID())
+		if err != nil {
+			t.Error(err)
+		}
+		kept := eb.GetChainID().IsSameAs(pinned) || (head != nil && head.IsSameAs(keyMR))
+
+		got, err := dbo.FetchEBlock(keyMR)
+		if err != nil {
+			t.Error(err)
+		}
+		if kept && got == nil {
+			t.Errorf("Entry block %x should have been kept", keyMR.Bytes())
+		}
+		if !kept && got != nil {
+			t.Errorf("Entry block %x should have been pruned", keyMR.Bytes())
+		}
+
+		for _, h := range eb.GetEntryHashes() {
+			if h.IsMinuteMarker() {
+				continue
+			}
+			entry, err := dbo.FetchEntry(h)
+			if err != nil {
+				t.Error(err)
+			}
+			if kept && entry == nil {
+				t.Errorf("Entry %x should have been kept", h.Bytes())
+			}
+			if !kept && entry != nil {
+				t.Errorf("Entry %x should have been pruned", h.Bytes())
+			}
+		}
+	}
+
+	// Everything but the entry blocks stays
+	after, err := dbo.FetchBlockSetByHeight(target)
+	if err != nil || after == nil || after.DBlock == nil || after.ABlock == nil || after.FBlock == nil || after.ECBlock == nil {
+		t.Errorf("Pruning removed more than entry blocks - %v", err)
+	}
+	if h, err := dbo.FetchPrunedHeight(); err != nil || h != target+1 {
+		t.Errorf("Expected pruned height %d, got %d - %v", target+1, h, err)
+	}
+}
+
+func TestPruneKeepsEntriesHeldLater(t *testing.T) {
+	dbo := testHelper.CreateAndPopulateTestDatabaseOverlay()
+	defer dbo.Close()
+
+	target := uint32(1)
+	bs, err := dbo.FetchBlockSetByHeightWithEntries(target)
+	if err != nil || bs == nil || len(bs.EBlocks) == 0 {
+		t.Fatalf("Could not load block set %d - %v", target, err)
+	}
+	eb := bs.EBlocks[0]
+	var held interfaces.IHash
+	for _, h := range eb.GetEntryHashes() {
+		if !h.IsMinuteMarker() {
+			held = h
+		}
+	}
+	entry, err := dbo.FetchEntry(held)
+	if err != nil || entry == nil {
+		t.Fatalf("Could not load entry %x - %v", held.Bytes(), err)
+	}
+
+	// The chain holds the entry again in a later block
+	head, err := dbo.FetchEBlockHead(eb.GetChainID())
+	if err != nil || head == nil {
+		t.Fatalf("No head of the chain - %v", err)
+	}
+	later := entryBlock.NewEBlock()
+	later.GetHeader().SetChainID(eb.GetChainID())
+	later.GetHeader().SetEBSequence(head.GetHeader().GetEBSequence() + 1)
+	later.GetHeader().SetPrevKeyMR(head.DatabasePrimaryIndex())
+	later.GetHeader().SetDBHeight(head.GetHeader().GetDBHeight() + 1)
+	later.AddEBEntry(entry)
+	later.AddEndOfMinuteMarker(1)
+	if err := dbo.ProcessEBlockBatch(later, true); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := dbo.PruneEntryBlocks(target, target+1, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := dbo.FetchEBlock(eb.DatabasePrimaryIndex()); got != nil {
+		t.Error("Entry block not pruned")
+	}
+	if got, _ := dbo.FetchEntry(held); got == nil {
+		t.Error("Pruned an entry a later entry block holds")
+	}
+}
+
+func TestPrunedHeight(t *testing.T) {
+	dbo := testHelper.CreateEmptyTestDatabaseOverlay()
+	defer dbo.Close()
+
+	h, err := dbo.FetchPrunedHeight()
+	if err != nil || h != 0 {
+		t.Errorf("Expected no pruned height, got %d - %v", h, err)
+	}
+	if err := dbo.SavePrunedHeight(1234); err != nil {
+		t.Fatal(err)
+	}
+	h, err = dbo.FetchPrunedHeight()
+	if err != nil || h != 1234 {
+		t.Errorf("Expected pruned height 1234, got %d - %v", h, err)
+	}
+}
@@ -0,0 +1,226 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package databaseOverlay
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+)
+
+// DefaultReadCacheSize is how many records the read cache keeps when no size is given
+const DefaultReadCacheSize = 8192
+
+// ReadCacheNegativeTTL is how long a lookup that found nothing is remembered.  Writes
+// through the overlay drop it sooner; the TTL covers records that appear any other way.
+const ReadCacheNegativeTTL = 2 * time.Second
+
+// The buckets the read cache keeps records from: the blocks, their height and secondary
+// indexes, and the chain heads, which the API and the authority set are read from over
+// and over.  Entries and the other per-record buckets are too many to be worth it.
+var readCacheBuckets = map[string]bool{
+	string(DIRECTORYBLOCK):                  true,
+	string(DIRECTORYBLOCK_NUMBER):           true,
+	string(DIRECTORYBLOCK_SECONDARYINDEX):   true,
+	string(ADMINBLOCK):                      true,
+	string(ADMINBLOCK_NUMBER):               true,
+	string(ADMINBLOCK_SECONDARYINDEX):       true,
+	string(FACTOIDBLOCK):                    true,
+	string(FACTOIDBLOCK_NUMBER):             true,
+	string(FACTOIDBLOCK_SECONDARYINDEX):     true,
+	string(ENTRYCREDITBLOCK):                true,
+	string(ENTRYCREDITBLOCK_NUMBER):         true,
+	string(ENTRYCREDITBLOCK_SECONDARYINDEX): true,
+	string(CHAIN_HEAD):                      true,
+	string(DIRBLOCKINFO):                    true,
+	string(DIRBLOCKINFO_NUMBER):             true,
+}
+
+// readCache keeps the records of hot buckets most recently read through the overlay, as
+// they were marshalled, and remembers for a short while the ones that weren't found.
+// Each hit is unmarshalled into the caller's destination, so callers never share an
+// object.
+type readCache struct {
+	mutex      sync.Mutex
+	size       int
+	order      *list.List // Most recently used at the front
+	records    map[string]*list.Element
+	generation uint64 // Bumped as writes start and end, so a read racing one isn't cached
+	bytes      int    // Held by the ids and data of the records
+}
+
+type readCacheRecord struct {
+	id      string
+	data    []byte    // nil for a record that wasn't found
+	expires time.Time // When a record that wasn't found is looked up again
+}
+
+func (r *readCacheRecord) bytes() int {
+	return len(r.id) + len(r.data)
+}
+
+func newReadCache(size int) *readCache {
+	if size <= 0 {
+		size = DefaultReadCacheSize
+	}
+	c := new(readCache)
+	c.size = size
+	c.order = list.New()
+	c.records = make(map[string]*list.Element)
+	return c
+}
+
+func readCacheID(bucket, key []byte) string {
+	return string(bucket) + "\x00" + string(key)
+}
+
+// lookup returns the cached record, if any, whether it was found, and the generation to
+// add what is read from the database with
+func (c *readCache) lookup(bucket, key []byte, now time.Time) (data []byte, cached bool, generation uint64) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	id := readCacheID(bucket, key)
+	e := c.records[id]
+	if e == nil {
+		return nil, false, c.generation
+	}
+	r := e.Value.(*readCacheRecord)
+	if r.data == nil && now.After(r.expires) {
+		c.drop(e)
+		return nil, false, c.generation
+	}
+	c.order.MoveToFront(e)
+	return r.data, true, c.generation
+}
+
+// add caches what was read from the database, unless something was written since the
+// read started
+func (c *readCache) add(bucket, key, data []byte, generation uint64, now time.Time) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if generation != c.generation {
+		return
+	}
+	r := &readCacheRecord{id: readCacheID(bucket, key), data: data}
+	if data == nil {
+		r.expires = now.Add(ReadCacheNegativeTTL)
+	}
+	if e := c.records[r.id]; e != nil {
+		c.bytes += r.bytes() - e.Value.(*readCacheRecord).bytes()
+		e.Value = r
+		c.order.MoveToFront(e)
+		return
+	}
+	c.records[r.id] = c.order.PushFront(r)
+	c.bytes += r.bytes()
+	for c.order.Len() > c.size {
+		c.drop(c.order.Back())
+	}
+}
+
+// drop removes a record from the cache; the mutex must be held
+func (c *readCache) drop(e *list.Element) {
+	r := e.Value.(*readCacheRecord)
+	c.order.Remove(e)
+	delete(c.records, r.id)
+	c.bytes -= r.bytes()
+}
+
+func (c *readCache) remove(bucket, key []byte) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.generation++
+	if e := c.records[readCacheID(bucket, key)]; e != nil {
+		c.drop(e)
+	}
+}
+
+func (c *readCache) clear() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.generation++
+	c.order.Init()
+	c.records = make(map[string]*list.Element)
+	c.bytes = 0
+}
+
+// len returns how many records are cached, found or not
+func (c *readCache) len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.order.Len()
+}
+
+// byteLen returns the bytes held by the cached records
+func (c *readCache) byteLen() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.bytes
+}
+
+// get reads a record through the cache
+func (c *readCache) get(db interfaces.IDatabase, bucket, key []byte, destination interfaces.BinaryMarshallable) (interfaces.BinaryMarshallable, error) {
+	data, cached, generation := c.lookup(bucket, key, time.Now())
+	if cached {
+		if data == nil {
+			OverlayReadCacheVec.WithLabelValues("negative").Inc()
+			return nil, nil
+		}
+		if _, err := destination.UnmarshalBinaryData(data); err == nil {
+			OverlayReadCacheVec.WithLabelValues("hit").Inc()
+			return destination, nil
+		}
+		// Leave a record that no longer unmarshals to be read again
+		c.remove(bucket, key)
+	}
+	OverlayReadCacheVec.WithLabelValues("miss").Inc()
+
+	answer, err := db.Get(bucket, key, destination)
+	if err != nil {
+		return nil, err
+	}
+	if answer == nil {
+		c.add(bucket, key, nil, generation, time.Now())
+		return nil, nil
+	}
+	if data, err := answer.MarshalBinary(); err == nil && data != nil {
+		c.add(bucket, key, data, generation, time.Now())
+	}
+	return answer, nil
+}
+
+// EnableReadCache keeps up to size records of the hot buckets in memory, along with the
+// lookups that found nothing; 0 uses DefaultReadCacheSize.  It must be called before the
+// overlay is shared.
+func (db *Overlay) EnableReadCache(size int) {
+	db.readCache = newReadCache(size)
+}
+
+// ReadCacheLen returns how many records the read cache holds, 0 when it isn't enabled
+func (db *Overlay) ReadCacheLen() int {
+	if db.readCache == nil {
+		return 0
+	}
+	return db.readCache.len()
+}
+
+// ReadCacheBytes returns the bytes held by the records in the read cache, 0 when it isn't
+// enabled
+func (db *Overlay) ReadCacheBytes() int {
+	if db.readCache == nil {
+		return 0
+	}
+	return db.readCache.byteLen()
+}
+
+// uncache drops a record from the read cache.  Writes call it before and after writing,
+// as a read that looked the record up between the two could cache what was there before.
+func (db *Overlay) uncache(bucket, key []byte) {
+	if db.readCache != nil && readCacheBuckets[string(bucket)] {
+		db.readCache.remove(bucket, key)
+	}
+}
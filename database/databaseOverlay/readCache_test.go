@@ -0,0 +1,140 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package databaseOverlay_test
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	. "github.com/FactomProject/factomd/database/databaseOverlay"
+	"github.com/FactomProject/factomd/database/mapdb"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestReadCache(t *testing.T) {
+	dbo := testHelper.CreateAndPopulateTestDatabaseOverlay()
+	defer dbo.Close()
+	dbo.EnableReadCache(0)
+
+	first, err := dbo.FetchDBlockByHeight(1)
+	if err != nil || first == nil {
+		t.Fatalf("Could not load dblock 1 - %v", err)
+	}
+	if dbo.ReadCacheLen() == 0 {
+		t.Fatal("Nothing cached")
+	}
+
+	// Once cached, the block is served without the database
+	key := make([]byte, 4)
+	binary.BigEndian.PutUint32(key, 1)
+	if err := dbo.DB.Delete(DIRECTORYBLOCK_NUMBER, key); err != nil {
+		t.Fatal(err)
+	}
+	second, err := dbo.FetchDBlockByHeight(1)
+	if err != nil || second == nil {
+		t.Fatalf("Cached dblock 1 not served - %v", err)
+	}
+	if second == first || !second.GetKeyMR().IsSameAs(first.GetKeyMR()) {
+		t.Error("Expected a copy of the same block")
+	}
+
+	// Writes through the overlay drop the cached record
+	if err := dbo.Delete(DIRECTORYBLOCK_NUMBER, key); err != nil {
+		t.Fatal(err)
+	}
+	if dblock, err := dbo.FetchDBlockByHeight(1); err != nil || dblock != nil {
+		t.Fatalf("Deleted dblock 1 still served - %v", err)
+	}
+
+	// Lookups that find nothing are remembered until the record is written
+	if err := dbo.DB.Put(DIRECTORYBLOCK_NUMBER, key, first.DatabasePrimaryIndex()); err != nil {
+		t.Fatal(err)
+	}
+	if dblock, _ := dbo.FetchDBlockByHeight(1); dblock != nil {
+		t.Error("Missing dblock 1 not remembered")
+	}
+	if err := dbo.Put(DIRECTORYBLOCK_NUMBER, key, first.DatabasePrimaryIndex()); err != nil {
+		t.Fatal(err)
+	}
+	if dblock, _ := dbo.FetchDBlockByHeight(1); dblock == nil || !dblock.GetKeyMR().IsSameAs(first.GetKeyMR()) {
+		t.Error("Written dblock 1 not served")
+	}
+
+	// Buckets that aren't hot are not cached
+	before := dbo.ReadCacheLen()
+	if _, err := dbo.Get([]byte("Cold"), key, new(primitives.Hash)); err != nil {
+		t.Fatal(err)
+	}
+	if dbo.ReadCacheLen() != before {
+		t.Error("Cached a record of a bucket that isn't hot")
+	}
+}
+
+func TestReadCacheBytes(t *testing.T) {
+	dbo := testHelper.CreateAndPopulateTestDatabaseOverlay()
+	defer dbo.Close()
+	if dbo.ReadCacheBytes() != 0 {
+		t.Error("Bytes counted without a read cache")
+	}
+	dbo.EnableReadCache(2)
+
+	for i := uint32(0); i < 4; i++ {
+		if _, err := dbo.FetchDBlockByHeight(i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	n := dbo.ReadCacheBytes()
+	if n == 0 {
+		t.Fatal("No bytes counted for the cached blocks")
+	}
+
+	// Dropped records are no longer counted
+	key := make([]byte, 4)
+	for i := uint32(0); i < 4; i++ {
+		binary.BigEndian.PutUint32(key, i)
+		if err := dbo.Delete(DIRECTORYBLOCK_NUMBER, key); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if dbo.ReadCacheBytes() >= n {
+		t.Errorf("Bytes not released: %d, was %d", dbo.ReadCacheBytes(), n)
+	}
+}
+
+// slowPutDB reads through the overlay in the middle of each Put, as a reader racing the
+// write would
+type slowPutDB struct {
+	interfaces.IDatabase
+	during func()
+}
+
+func (db *slowPutDB) Put(bucket, key []byte, data interfaces.BinaryMarshallable) error {
+	db.during()
+	return db.IDatabase.Put(bucket, key, data)
+}
+
+func TestReadCacheWriteRace(t *testing.T) {
+	db := &slowPutDB{IDatabase: new(mapdb.MapDB), during: func() {}}
+	dbo := NewOverlay(db)
+	dbo.EnableReadCache(0)
+
+	key := []byte("head")
+	old, updated := primitives.NewHash([]byte("old")), primitives.NewHash([]byte("new"))
+	if err := dbo.Put(CHAIN_HEAD, key, old); err != nil {
+		t.Fatal(err)
+	}
+
+	// A read between the cache being dropped and the write landing sees the old record
+	db.during = func() { dbo.Get(CHAIN_HEAD, key, new(primitives.Hash)) }
+	if err := dbo.Put(CHAIN_HEAD, key, updated); err != nil {
+		t.Fatal(err)
+	}
+	got, err := dbo.Get(CHAIN_HEAD, key, new(primitives.Hash))
+	if err != nil || got == nil || !got.(interfaces.IHash).IsSameAs(updated) {
+		t.Errorf("Read %v after the write, expected %v - %v", got, updated, err)
+	}
+}
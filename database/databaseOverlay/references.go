@@ -0,0 +1,147 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package databaseOverlay
+
+import (
+	"errors"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+var (
+	// Entries referring to an entry or chain, in a bucket per referenced hash
+	REFERENCED_BY = []byte("ReferencedBy")
+)
+
+// Most hashes taken from one entry, which bounds the lookups made saving it
+const MaxEntryReferences = 32
+
+var ErrNoReferenceIndex = errors.New("The reference index is not enabled")
+
+// EnableReferenceIndex records, as entries are saved, which saved entries and chains they
+// refer to.  It must be called before the overlay is shared.  Only entries saved after it
+// is enabled are indexed, and a reference to an entry or chain saved in the same batch
+// is missed.
+func (db *Overlay) EnableReferenceIndex() {
+	db.indexReferences = true
+}
+
+// EntryReferences returns the hashes an entry may be referring to: external IDs of 32
+// bytes, and external IDs or runs of content of exactly 64 hex characters.  The entry's
+// own hash and chain ID are left out, as are repeats.
+func EntryReferences(entry interfaces.IEBEntry) []interfaces.IHash {
+	var refs []interfaces.IHash
+	seen := map[[32]byte]bool{}
+	seen[entry.GetHash().Fixed()] = true
+	seen[entry.GetChainID().Fixed()] = true
+	add := func(h interfaces.IHash) {
+		if len(refs) < MaxEntryReferences && !seen[h.Fixed()] {
+			seen[h.Fixed()] = true
+			refs = append(refs, h)
+		}
+	}
+
+	for _, extID := range entry.ExternalIDs() {
+		if len(extID) == 32 {
+			add(primitives.NewHash(extID))
+		}
+		for _, h := range hexHashes(extID) {
+			add(h)
+		}
+	}
+	for _, h := range hexHashes(entry.GetContent()) {
+		add(h)
+	}
+	return refs
+}
+
+// hexHashes finds the runs of exactly 64 hex characters in data
+func hexHashes(data []byte) []interfaces.IHash {
+	var hashes []interfaces.IHash
+	start := 0
+	for i := 0; i <= len(data); i++ {
+		if i < len(data) && isHexChar(data[i]) {
+			continue
+		}
+		if i-start == 64 {
+			if h, err := primitives.HexToHash(string(data[start:i])); err == nil {
+				hashes = append(hashes, h)
+			}
+		}
+		start = i + 1
+	}
+	return hashes
+}
+
+func isHexChar(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+func referencedByBucket(hash interfaces.IHash) []byte {
+	bucket := make([]byte, 0, len(REFERENCED_BY)+32)
+	bucket = append(bucket, REFERENCED_BY...)
+	return append(bucket, hash.Bytes()...)
+}
+
+// referenceRecords returns the records indexing what saved entries and chains an entry
+// refers to, none if the index isn't enabled
+func (db *Overlay) referenceRecords(entry interfaces.IEBEntry) []interfaces.Record {
+	if !db.indexReferences {
+		return nil
+	}
+	var records []interfaces.Record
+	for _, h := range EntryReferences(entry) {
+		if !db.isEntryOrChain(h) {
+			continue
+		}
+		records = append(records, interfaces.Record{Bucket: referencedByBucket(h), Key: entry.GetHash().Bytes(), Data: entry.GetChainIDHash()})
+	}
+	return records
+}
+
+func (db *Overlay) isEntryOrChain(hash interfaces.IHash) bool {
+	if ok, err := db.DoesKeyExist(ENTRY, hash.Bytes()); err == nil && ok {
+		return true
+	}
+	ok, err := db.DoesKeyExist(CHAIN_HEAD, hash.Bytes())
+	return err == nil && ok
+}
+
+// deleteReferences drops an entry about to be deleted from the reference index
+func (db *Overlay) deleteReferences(entryHash interfaces.IHash) error {
+	if !db.indexReferences {
+		return nil
+	}
+	entry, err := db.FetchEntry(entryHash)
+	if err != nil || entry == nil {
+		return err
+	}
+	for _, h := range EntryReferences(entry) {
+		if err := db.Delete(referencedByBucket(h), entryHash.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FetchReferencingEntries returns the saved entries referring to an entry or chain
+func (db *Overlay) FetchReferencingEntries(hash interfaces.IHash) ([]interfaces.EntryReference, error) {
+	if !db.indexReferences {
+		return nil, ErrNoReferenceIndex
+	}
+	chains, keys, err := db.GetAll(referencedByBucket(hash), primitives.NewZeroHash())
+	if err != nil {
+		return nil, err
+	}
+	refs := make([]interfaces.EntryReference, 0, len(keys))
+	for i, key := range keys {
+		refs = append(refs, interfaces.EntryReference{
+			EntryHash: primitives.NewHash(key),
+			ChainID:   chains[i].(interfaces.IHash),
+		})
+	}
+	return refs, nil
+}
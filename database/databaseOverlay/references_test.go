@@ -0,0 +1,78 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package databaseOverlay_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/common/entryBlock"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	. "github.com/FactomProject/factomd/database/databaseOverlay"
+	"github.com/FactomProject/factomd/database/mapdb"
+)
+
+func newReferenceTestEntry(chainID interfaces.IHash, extIDs [][]byte, content string) *entryBlock.Entry {
+	e := entryBlock.NewEntry()
+	e.ChainID = chainID
+	for _, x := range extIDs {
+		e.ExtIDs = append(e.ExtIDs, primitives.ByteSlice{Bytes: x})
+	}
+	e.Content = primitives.ByteSlice{Bytes: []byte(content)}
+	return e
+}
+
+func TestEntryReferences(t *testing.T) {
+	chainID := primitives.RandomHash()
+	a := primitives.RandomHash()
+	b := primitives.RandomHash()
+	e := newReferenceTestEntry(chainID, [][]byte{a.Bytes(), []byte(b.String()), []byte("short")},
+		"see "+b.String()+" and "+chainID.String()+", not "+b.String()+"0")
+
+	refs := EntryReferences(e)
+	if len(refs) != 2 || !refs[0].IsSameAs(a) || !refs[1].IsSameAs(b) {
+		t.Errorf("Wrong references %v", refs)
+	}
+}
+
+func TestReferenceIndex(t *testing.T) {
+	dbo := NewOverlay(new(mapdb.MapDB))
+	defer dbo.Close()
+
+	target := newReferenceTestEntry(primitives.RandomHash(), nil, "target")
+	if _, err := dbo.FetchReferencingEntries(target.GetHash()); err != ErrNoReferenceIndex {
+		t.Errorf("Expected ErrNoReferenceIndex, got %v", err)
+	}
+
+	dbo.EnableReferenceIndex()
+	if err := dbo.InsertEntry(target); err != nil {
+		t.Fatal(err)
+	}
+	otherChain := primitives.RandomHash()
+	if err := dbo.Put(CHAIN_HEAD, otherChain.Bytes(), primitives.RandomHash()); err != nil {
+		t.Fatal(err)
+	}
+
+	unknown := primitives.RandomHash()
+	referrer := newReferenceTestEntry(primitives.RandomHash(), [][]byte{target.GetHash().Bytes()},
+		otherChain.String()+" "+unknown.String())
+	if err := dbo.InsertEntry(referrer); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, h := range []interfaces.IHash{target.GetHash(), otherChain} {
+		refs, err := dbo.FetchReferencingEntries(h)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(refs) != 1 || !refs[0].EntryHash.IsSameAs(referrer.GetHash()) || !refs[0].ChainID.IsSameAs(referrer.GetChainID()) {
+			t.Errorf("Wrong references to %x: %v", h.Bytes()[:4], refs)
+		}
+	}
+	refs, err := dbo.FetchReferencingEntries(unknown)
+	if err != nil || len(refs) != 0 {
+		t.Errorf("Hashes of nothing saved should not be indexed, got %v %v", refs, err)
+	}
+}
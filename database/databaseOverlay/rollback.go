@@ -0,0 +1,280 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package databaseOverlay
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/FactomProject/factomd/common/entryCreditBlock"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// rollbackBlock is what we need to remove a block and the IncludedIn records pointing at it
+type rollbackBlock interface {
+	GetDatabaseHeight() uint32
+	DatabasePrimaryIndex() interfaces.IHash
+	DatabaseSecondaryIndex() interfaces.IHash
+	GetEntryHashes() []interfaces.IHash
+	GetEntrySigHashes() []interfaces.IHash
+}
+
+// RollbackToHeight deletes every block above the given directory block height, along
+// with the entries and indexes those blocks introduced and the anchor records of the
+// directory blocks, and points the chain heads back at the blocks that remain.  The
+// directory block at the given height must exist.
+func (db *Overlay) RollbackToHeight(height uint32) error {
+	db.pruneMutex.Lock()
+	defer db.pruneMutex.Unlock()
+
+	head, err := db.FetchDBlockHead()
+	if err != nil {
+		return err
+	}
+	if head == nil {
+		return fmt.Errorf("No directory block head found")
+	}
+
+	keep, err := db.FetchBlockSetByHeight(height)
+	if err != nil {
+		return err
+	}
+	if keep == nil || keep.DBlock == nil || keep.ABlock == nil || keep.FBlock == nil || keep.ECBlock == nil {
+		return fmt.Errorf("Incomplete block set at height %d, cannot roll back to it", height)
+	}
+
+	for h := head.GetDatabaseHeight(); h > height; h-- {
+		bs, err := db.FetchBlockSetByHeight(h)
+		if err != nil {
+			return err
+		}
+		if bs == nil {
+			continue
+		}
+		err = db.DeleteBlockSet(bs)
+		if err != nil {
+			return fmt.Errorf("Failed deleting blocks at height %d: %v", h, err)
+		}
+	}
+
+	// Entry chain heads were moved back as their blocks were deleted, now fix the
+	// heads of the system chains.
+	chainIDs := []interfaces.IHash{keep.DBlock.GetChainID(), keep.ABlock.GetChainID(), keep.FBlock.GetChainID(), keep.ECBlock.GetChainID()}
+	keyMRs := []interfaces.IHash{keep.DBlock.DatabasePrimaryIndex(), keep.ABlock.DatabasePrimaryIndex(), keep.FBlock.DatabasePrimaryIndex(), keep.ECBlock.DatabasePrimaryIndex()}
+	return db.SetChainHeads(keyMRs, chainIDs)
+}
+
+// DeleteBlockSet removes all the blocks recorded at one directory block height.  Entries
+// are only removed if they were first included in one of the deleted entry blocks.
+func (db *Overlay) DeleteBlockSet(bs *BlockSet) error {
+	for _, eblock := range bs.EBlocks {
+		if eblock == nil {
+			continue
+		}
+		err := db.deleteEBlock(eblock)
+		if err != nil {
+			return err
+		}
+	}
+
+	if bs.ECBlock != nil {
+		err := db.deletePaidFor(bs.ECBlock)
+		if err != nil {
+			return err
+		}
+		err = db.deleteBlock(ENTRYCREDITBLOCK, ENTRYCREDITBLOCK_NUMBER, ENTRYCREDITBLOCK_SECONDARYINDEX, bs.ECBlock)
+		if err != nil {
+			return err
+		}
+	}
+	if bs.FBlock != nil {
+		err := db.deleteAddressIndex(bs.FBlock)
+		if err != nil {
+			return err
+		}
+		err = db.deleteBlock(FACTOIDBLOCK, FACTOIDBLOCK_NUMBER, FACTOIDBLOCK_SECONDARYINDEX, bs.FBlock)
+		if err != nil {
+			return err
+		}
+	}
+	if bs.ABlock != nil {
+		err := db.deleteIndexes(ADMINBLOCK, ADMINBLOCK_NUMBER, ADMINBLOCK_SECONDARYINDEX, bs.ABlock.GetDatabaseHeight(),
+			bs.ABlock.DatabasePrimaryIndex(), bs.ABlock.DatabaseSecondaryIndex())
+		if err != nil {
+			return err
+		}
+	}
+	if bs.DBlock != nil {
+		if err := db.deleteAnchorRecords(bs.DBlock); err != nil {
+			return err
+		}
+	}
+	return db.deleteBlock(DIRECTORYBLOCK, DIRECTORYBLOCK_NUMBER, DIRECTORYBLOCK_SECONDARYINDEX, bs.DBlock)
+}
+
+// deleteAnchorRecords removes what we know of the anchoring of a directory block: its
+// DirBlockInfo, confirmed or not, and its Ethereum anchor record
+func (db *Overlay) deleteAnchorRecords(dblock interfaces.IDirectoryBlock) error {
+	keyMR := dblock.DatabasePrimaryIndex()
+	dbi, err := db.FetchDirBlockInfoByKeyMR(keyMR)
+	if err != nil {
+		return err
+	}
+	if dbi != nil {
+		// Infos made from anchor records share a zero hash, so the secondary index is
+		// only removed if it is this block's
+		secondary := dbi.DatabaseSecondaryIndex()
+		if index, err := db.FetchPrimaryIndexBySecondaryIndex(DIRBLOCKINFO_SECONDARYINDEX, secondary); err != nil {
+			return err
+		} else if index == nil || !index.IsSameAs(keyMR) {
+			secondary = nil
+		}
+		if err := db.Delete(DIRBLOCKINFO_UNCONFIRMED, keyMR.Bytes()); err != nil {
+			return err
+		}
+		err = db.deleteIndexes(DIRBLOCKINFO, DIRBLOCKINFO_NUMBER, DIRBLOCKINFO_SECONDARYINDEX, dbi.GetDatabaseHeight(), keyMR, secondary)
+		if err != nil {
+			return err
+		}
+	}
+	return db.Delete(ETHEREUM_ANCHOR, keyMR.Bytes())
+}
+
+func (db *Overlay) deleteEBlock(eblock interfaces.IEntryBlock) error {
+	keyMR := eblock.DatabasePrimaryIndex()
+	chainID := eblock.GetChainID()
+
+	err := db.deleteEBlockEntries(eblock, nil)
+	if err != nil {
+		return err
+	}
+	err = db.deleteChainIndex(eblock)
+	if err != nil {
+		return err
+	}
+
+	numberBucket := append(ENTRYBLOCK_CHAIN_NUMBER, chainID.Bytes()...)
+	err = db.deleteIndexes(ENTRYBLOCK, numberBucket, ENTRYBLOCK_SECONDARYINDEX, eblock.GetDatabaseHeight(),
+		keyMR, eblock.DatabaseSecondaryIndex())
+	if err != nil {
+		return err
+	}
+
+	// Blocks are deleted from the top down, so the last head we set for a chain is the
+	// one below the lowest deleted block.
+	prev := eblock.GetHeader().GetPrevKeyMR()
+	if prev == nil || prev.IsZero() {
+		return db.Delete(CHAIN_HEAD, chainID.Bytes())
+	}
+	return db.SetChainHeads([]interfaces.IHash{prev}, []interfaces.IHash{chainID})
+}
+
+// deleteEBlockEntries removes the entries first included in the entry block, but for those
+// in keep, which another entry block still holds
+func (db *Overlay) deleteEBlockEntries(eblock interfaces.IEntryBlock, keep map[[32]byte]bool) error {
+	keyMR := eblock.DatabasePrimaryIndex()
+	chainID := eblock.GetChainID()
+
+	for _, e := range eblock.GetEntryHashes() {
+		if e.IsMinuteMarker() || keep[e.Fixed()] {
+			continue
+		}
+		in, err := db.FetchIncludedIn(e)
+		if err != nil {
+			return err
+		}
+		// The same entry can show up again later; only delete it where it was first recorded
+		if in == nil || !in.IsSameAs(keyMR) {
+			continue
+		}
+		if err := db.deleteReferences(e); err != nil {
+			return err
+		}
+		if err := db.deleteExtIDIndex(e); err != nil {
+			return err
+		}
+		if err := db.Delete(chainID.Bytes(), e.Bytes()); err != nil {
+			return err
+		}
+		if err := db.Delete(ENTRY, e.Bytes()); err != nil {
+			return err
+		}
+		if err := db.Delete(INCLUDED_IN, e.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (db *Overlay) deletePaidFor(ecblock interfaces.IEntryCreditBlock) error {
+	for _, entry := range ecblock.GetBody().GetEntries() {
+		var entryHash interfaces.IHash
+		switch entry.ECID() {
+		case entryCreditBlock.ECIDChainCommit:
+			entryHash = entry.(*entryCreditBlock.CommitChain).EntryHash
+		case entryCreditBlock.ECIDEntryCommit:
+			entryHash = entry.(*entryCreditBlock.CommitEntry).EntryHash
+		default:
+			continue
+		}
+		paid, err := db.FetchPaidFor(entryHash)
+		if err != nil {
+			return err
+		}
+		if paid != nil && paid.IsSameAs(entry.GetSigHash()) {
+			if err := db.Delete(PAID_FOR, entryHash.Bytes()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// deleteBlock removes a block, its indexes, and the IncludedIn records that point at it
+func (db *Overlay) deleteBlock(blockBucket, numberBucket, secondaryIndexBucket []byte, block rollbackBlock) error {
+	keyMR := block.DatabasePrimaryIndex()
+	hashes := append(block.GetEntryHashes(), block.GetEntrySigHashes()...)
+	for _, h := range hashes {
+		if h.IsMinuteMarker() {
+			continue
+		}
+		in, err := db.FetchIncludedIn(h)
+		if err != nil {
+			return err
+		}
+		if in != nil && in.IsSameAs(keyMR) {
+			if err := db.Delete(INCLUDED_IN, h.Bytes()); err != nil {
+				return err
+			}
+		}
+	}
+	return db.deleteIndexes(blockBucket, numberBucket, secondaryIndexBucket, block.GetDatabaseHeight(),
+		keyMR, block.DatabaseSecondaryIndex())
+}
+
+func (db *Overlay) deleteIndexes(blockBucket, numberBucket, secondaryIndexBucket []byte, height uint32, primary, secondary interfaces.IHash) error {
+	if err := db.Delete(blockBucket, primary.Bytes()); err != nil {
+		return err
+	}
+
+	bytes := make([]byte, 4)
+	binary.BigEndian.PutUint32(bytes, height)
+	// Only remove the height index if it still points at this block
+	index, err := db.Get(numberBucket, bytes, new(primitives.Hash))
+	if err != nil {
+		return err
+	}
+	if index != nil && index.(interfaces.IHash).IsSameAs(primary) {
+		if err := db.Delete(numberBucket, bytes); err != nil {
+			return err
+		}
+	}
+
+	if secondary != nil {
+		return db.Delete(secondaryIndexBucket, secondary.Bytes())
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package databaseOverlay_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/anchor"
+	"github.com/FactomProject/factomd/common/directoryBlock/dbInfo"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestRollbackToHeight(t *testing.T) {
+	dbo := testHelper.CreateAndPopulateTestDatabaseOverlay()
+	defer dbo.Close()
+
+	head, err := dbo.FetchDBlockHead()
+	if err != nil {
+		t.Fatal(err)
+	}
+	top := head.GetDatabaseHeight()
+	target := top / 2
+
+	removed, err := dbo.FetchBlockSetByHeightWithEntries(target + 1)
+	if err != nil || removed == nil {
+		t.Fatalf("Could not load block set %d - %v", target+1, err)
+	}
+	kept, err := dbo.FetchBlockSetByHeightWithEntries(target)
+	if err != nil || kept == nil {
+		t.Fatalf("Could not load block set %d - %v", target, err)
+	}
+
+	// What we know of the anchoring of each block goes with it
+	for _, dblock := range []interfaces.IDirectoryBlock{removed.DBlock, kept.DBlock} {
+		if err := dbo.SaveDirBlockInfo(dbInfo.NewDirBlockInfoFromDirBlock(dblock)); err != nil {
+			t.Fatal(err)
+		}
+		ar := anchor.CreateAnchorRecordFromDBlock(dblock)
+		ar.Ethereum = &anchor.EthereumStruct{TXID: "0x50ea"}
+		if err := dbo.SaveEthereumAnchor(ar); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err = dbo.RollbackToHeight(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if dbi, err := dbo.FetchDirBlockInfoByKeyMR(removed.DBlock.GetKeyMR()); err != nil || dbi != nil {
+		t.Errorf("DirBlockInfo of a removed block was kept: %v", err)
+	}
+	if ar, err := dbo.FetchEthereumAnchor(removed.DBlock.GetKeyMR()); err != nil || ar != nil {
+		t.Errorf("Ethereum anchor of a removed block was kept: %v", err)
+	}
+	if dbi, err := dbo.FetchDirBlockInfoByKeyMR(kept.DBlock.GetKeyMR()); err != nil || dbi == nil {
+		t.Errorf("DirBlockInfo of a kept block was removed: %v", err)
+	}
+	if ar, err := dbo.FetchEthereumAnchor(kept.DBlock.GetKeyMR()); err != nil || ar == nil {
+		t.Errorf("Ethereum anchor of a kept block was removed: %v", err)
+	}
+
+	head, err = dbo.FetchDBlockHead()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if head.GetDatabaseHeight() != target {
+		t.Errorf("Head is at %d, expected %d", head.GetDatabaseHeight(), target)
+	}
+	for h := target + 1; h <= top; h++ {
+		bs, err := dbo.FetchBlockSetByHeight(h)
+		if err != nil {
+			t.Error(err)
+		}
+		if bs != nil {
+			t.Errorf("Block set at height %d was not removed", h)
+		}
+	}
+
+	ablock, err := dbo.FetchABlockHead()
+	if err != nil {
+		t.Error(err)
+	}
+	if !ablock.DatabasePrimaryIndex().IsSameAs(kept.ABlock.DatabasePrimaryIndex()) {
+		t.Error("Admin block head was not rolled back")
+	}
+
+	for _, eb := range kept.EBlocks {
+		h, err := dbo.FetchHeadIndexByChainID(eb.GetChainID())
+		if err != nil {
+			t.Error(err)
+		}
+		if h == nil || !h.IsSameAs(eb.DatabasePrimaryIndex()) {
+			t.Errorf("Chain head for %x was not rolled back", eb.GetChainID().Bytes())
+		}
+	}
+
+	for _, e := range removed.Entries {
+		if e == nil {
+			continue
+		}
+		entry, err := dbo.FetchEntry(e.GetHash())
+		if err != nil {
+			t.Error(err)
+		}
+		if entry != nil {
+			t.Errorf("Entry %x should have been removed", e.GetHash().Bytes())
+		}
+	}
+	for _, e := range kept.Entries {
+		if e == nil {
+			continue
+		}
+		entry, err := dbo.FetchEntry(e.GetHash())
+		if err != nil {
+			t.Error(err)
+		}
+		if entry == nil {
+			t.Errorf("Entry %x should have been kept", e.GetHash().Bytes())
+		}
+	}
+
+	// Can't roll forward
+	if err := dbo.RollbackToHeight(top); err == nil {
+		t.Error("Expected an error rolling back to a missing height")
+	}
+}
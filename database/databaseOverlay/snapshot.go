@@ -0,0 +1,38 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package databaseOverlay
+
+import (
+	"encoding/binary"
+
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+var (
+	// Directory block height of the state snapshot a database was bootstrapped from.  The
+	// blocks below it aren't in the database.
+	SNAPSHOT        = []byte("Snapshot")
+	SNAPSHOT_HEIGHT = []byte("SnapshotHeight")
+)
+
+// FetchSnapshotHeight returns the height of the snapshot the database was bootstrapped
+// from, 0 if it holds the whole chain
+func (db *Overlay) FetchSnapshotHeight() (uint32, error) {
+	answer, err := db.Get(SNAPSHOT, SNAPSHOT_HEIGHT, new(primitives.ByteSlice))
+	if err != nil || answer == nil {
+		return 0, err
+	}
+	data := answer.(*primitives.ByteSlice).Bytes
+	if len(data) != 4 {
+		return 0, nil
+	}
+	return binary.BigEndian.Uint32(data), nil
+}
+
+func (db *Overlay) SaveSnapshotHeight(height uint32) error {
+	data := make([]byte, 4)
+	binary.BigEndian.PutUint32(data, height)
+	return db.Put(SNAPSHOT, SNAPSHOT_HEIGHT, &primitives.ByteSlice{Bytes: data})
+}
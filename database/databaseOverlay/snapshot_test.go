@@ -0,0 +1,28 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package databaseOverlay_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestSnapshotHeight(t *testing.T) {
+	dbo := testHelper.CreateEmptyTestDatabaseOverlay()
+	defer dbo.Close()
+
+	h, err := dbo.FetchSnapshotHeight()
+	if err != nil || h != 0 {
+		t.Errorf("Expected no snapshot height, got %d - %v", h, err)
+	}
+	if err := dbo.SaveSnapshotHeight(4321); err != nil {
+		t.Fatal(err)
+	}
+	h, err = dbo.FetchSnapshotHeight()
+	if err != nil || h != 4321 {
+		t.Errorf("Expected snapshot height 4321, got %d - %v", h, err)
+	}
+}
@@ -28,6 +28,13 @@ type LevelDB struct {
 
 var _ interfaces.IDatabase = (*LevelDB)(nil)
 
+// Memory given to databases opened after these are set, in bytes.  0 leaves the LevelDB
+// defaults.
+var (
+	BlockCacheCapacity int
+	WriteBuffer        int
+)
+
 func (db *LevelDB) ListAllBuckets() ([][]byte, error) {
 	//TODO: fix Level to solve this issue
 	return nil, fmt.Errorf("Unable to fetch buckets due to LevelDB design")
@@ -278,6 +285,8 @@ func NewLevelDB(filename string, create bool) (interfaces.IDatabase, error) {
 	opts := &opt.Options{
 		OpenFilesCacheCapacity: 50, //this solves the "too many files open problem.  macs have a default of 250 max open files.
 		// setting this lower lessens contention with other programs for the scarce open file limit.
+		BlockCacheCapacity: BlockCacheCapacity,
+		WriteBuffer:        WriteBuffer,
 	}
 
 	tlDB, err = leveldb.OpenFile(filename, opts)
@@ -0,0 +1,74 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package leveldb
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/goleveldb/leveldb"
+	"github.com/FactomProject/goleveldb/leveldb/opt"
+)
+
+// Number of records copied per batch when writing a snapshot out
+const snapshotBatchSize = 1000
+
+type LevelDBSnapshot struct {
+	snap *leveldb.Snapshot
+}
+
+var _ interfaces.ISnapshotDatabase = (*LevelDB)(nil)
+var _ interfaces.IDatabaseSnapshot = (*LevelDBSnapshot)(nil)
+
+// Snapshot takes a consistent view of the database.  LevelDB snapshots are cheap, and
+// writes carry on while the snapshot is copied out.
+func (db *LevelDB) Snapshot() (interfaces.IDatabaseSnapshot, error) {
+	db.dbLock.Lock()
+	defer db.dbLock.Unlock()
+
+	snap, err := db.lDB.GetSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	return &LevelDBSnapshot{snap: snap}, nil
+}
+
+// WriteTo copies every record in the snapshot into a new LevelDB database at path
+func (s *LevelDBSnapshot) WriteTo(path string) error {
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("%s already exists", path)
+	}
+	if err := os.MkdirAll(path, 0750); err != nil {
+		return err
+	}
+	target, err := leveldb.OpenFile(path, &opt.Options{ErrorIfExist: true})
+	if err != nil {
+		return err
+	}
+	defer target.Close()
+
+	iter := s.snap.NewIterator(nil, nil)
+	defer iter.Release()
+
+	batch := new(leveldb.Batch)
+	for iter.Next() {
+		batch.Put(iter.Key(), iter.Value())
+		if batch.Len() >= snapshotBatchSize {
+			if err := target.Write(batch, nil); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return err
+	}
+	return target.Write(batch, nil)
+}
+
+func (s *LevelDBSnapshot) Release() {
+	s.snap.Release()
+}
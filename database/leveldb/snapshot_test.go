@@ -0,0 +1,66 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package leveldb_test
+
+import (
+	"os"
+	"testing"
+
+	. "github.com/FactomProject/factomd/database/leveldb"
+)
+
+func TestSnapshotWriteTo(t *testing.T) {
+	m, err := NewLevelDB(dbFilename, true)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	defer CleanupTest(t, m)
+
+	backup := dbFilename + ".backup"
+	defer os.RemoveAll(backup)
+
+	bucket := []byte("bucket")
+	if err := m.Put(bucket, []byte("before"), &TestData{Str: "kept"}); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	snap, err := m.(*LevelDB).Snapshot()
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	// Writes after the snapshot must not show up in the backup
+	if err := m.Put(bucket, []byte("after"), &TestData{Str: "dropped"}); err != nil {
+		t.Fatalf("%v", err)
+	}
+	err = snap.WriteTo(backup)
+	snap.Release()
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	b, err := NewLevelDB(backup, false)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	defer b.Close()
+
+	resp, err := b.Get(bucket, []byte("before"), new(TestData))
+	if err != nil || resp == nil || resp.(*TestData).Str != "kept" {
+		t.Errorf("Backup is missing data written before the snapshot - %v %v", resp, err)
+	}
+	resp, err = b.Get(bucket, []byte("after"), new(TestData))
+	if err != nil || resp != nil {
+		t.Errorf("Backup has data written after the snapshot - %v %v", resp, err)
+	}
+
+	snap, err = m.(*LevelDB).Snapshot()
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	defer snap.Release()
+	if err := snap.WriteTo(backup); err == nil {
+		t.Error("Expected an error writing over an existing backup")
+	}
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/database/badgerdb"
 	"github.com/FactomProject/factomd/database/boltdb"
 	"github.com/FactomProject/factomd/database/leveldb"
 	"github.com/FactomProject/factomd/database/mapdb"
@@ -209,8 +210,13 @@ func (db *EncryptedDB) Init(filename string, dbtype string) {
 		}
 	case "Bolt":
 		db.db = boltdb.NewBoltDB(nil, filename)
+	case "Badger":
+		db.db, err = badgerdb.NewBadgerDB(filename)
+		if err != nil {
+			panic(err)
+		}
 	default:
-		panic(fmt.Sprintf("%s is not a valid option. Expect 'Map', 'LDB', or 'Bolt'", dbtype))
+		panic(fmt.Sprintf("%s is not a valid option. Expect 'Map', 'LDB', 'Bolt', or 'Badger'", dbtype))
 	}
 }
 
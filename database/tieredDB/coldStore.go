@@ -0,0 +1,115 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package tieredDB
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// ColdStore holds the records moved out of the local database.  Records are only ever
+// written once and read many times, so a store can sit behind a cache on object storage.
+// Implementations must be safe for concurrent use.
+type ColdStore interface {
+	// Get returns nil, with no error, if the record isn't in the store
+	Get(bucket, key []byte) ([]byte, error)
+	Put(bucket, key, data []byte) error
+	Delete(bucket, key []byte) error
+	ListKeys(bucket []byte) ([][]byte, error)
+}
+
+// DirColdStore keeps each record in its own file under a directory, which can be a
+// mount of object storage (i.e. s3fs or gcsfuse) or a cheaper, slower local disk.
+type DirColdStore struct {
+	Path string
+}
+
+var _ ColdStore = (*DirColdStore)(nil)
+
+func NewDirColdStore(path string) (*DirColdStore, error) {
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return nil, err
+	}
+	return &DirColdStore{Path: path}, nil
+}
+
+// The records of a bucket are spread over 256 directories by the first byte of their key,
+// so no one directory gets too large
+func (d *DirColdStore) bucketDir(bucket []byte) string {
+	return filepath.Join(d.Path, hex.EncodeToString(bucket))
+}
+
+func (d *DirColdStore) file(bucket, key []byte) string {
+	k := hex.EncodeToString(key)
+	fan := "_"
+	if len(k) >= 2 {
+		fan = k[:2]
+	}
+	return filepath.Join(d.bucketDir(bucket), fan, k)
+}
+
+func (d *DirColdStore) Get(bucket, key []byte) ([]byte, error) {
+	data, err := ioutil.ReadFile(d.file(bucket, key))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return data, err
+}
+
+// Put writes the record to a temporary file and renames it into place, so readers never
+// see part of a record
+func (d *DirColdStore) Put(bucket, key, data []byte) error {
+	name := d.file(bucket, key)
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		return err
+	}
+	tmp := name + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, name)
+}
+
+func (d *DirColdStore) Delete(bucket, key []byte) error {
+	err := os.Remove(d.file(bucket, key))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+func (d *DirColdStore) ListKeys(bucket []byte) ([][]byte, error) {
+	fans, err := ioutil.ReadDir(d.bucketDir(bucket))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var keys [][]byte
+	for _, fan := range fans {
+		if !fan.IsDir() {
+			continue
+		}
+		files, err := ioutil.ReadDir(filepath.Join(d.bucketDir(bucket), fan.Name()))
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range files {
+			if filepath.Ext(f.Name()) == ".tmp" {
+				continue
+			}
+			key, err := hex.DecodeString(f.Name())
+			if err != nil {
+				return nil, fmt.Errorf("Unexpected file %s in cold store", f.Name())
+			}
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
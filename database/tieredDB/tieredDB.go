@@ -0,0 +1,311 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package tieredDB
+
+import (
+	"container/list"
+	"encoding/binary"
+	"sync"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// Where the height moved to the cold store so far is kept, in the hot database
+var (
+	COLD_STORAGE        = []byte("ColdStorage")
+	COLD_STORAGE_HEIGHT = []byte("Height")
+)
+
+// DefaultCacheSize is how many records read from the cold store are kept in memory
+const DefaultCacheSize = 4096
+
+// TieredDB serves records from a fast local database, falling back to a cold store for
+// the records that have been demoted to it.  Everything is written to the local
+// database; old records are moved to the cold store with Demote.  Both stores must be
+// safe for concurrent use.  Records are only demoted once they are no longer written,
+// and are in one store or the other at all times, so no lock is held while the slow
+// cold store is read.
+type TieredDB struct {
+	hot   interfaces.IDatabase
+	cold  ColdStore
+	cache *recordCache
+}
+
+var _ interfaces.IDatabase = (*TieredDB)(nil)
+var _ interfaces.ITieredDatabase = (*TieredDB)(nil)
+
+func NewTieredDB(hot interfaces.IDatabase, cold ColdStore, cacheSize int) *TieredDB {
+	answer := new(TieredDB)
+	answer.hot = hot
+	answer.cold = cold
+	answer.cache = newRecordCache(cacheSize)
+	return answer
+}
+
+func (db *TieredDB) ListAllBuckets() ([][]byte, error) {
+	return db.hot.ListAllBuckets()
+}
+
+func (db *TieredDB) Trim() {
+	db.cache.clear()
+	db.hot.Trim()
+}
+
+func (db *TieredDB) Close() error {
+	return db.hot.Close()
+}
+
+func (db *TieredDB) Put(bucket, key []byte, data interfaces.BinaryMarshallable) error {
+	db.cache.remove(bucket, key)
+	return db.hot.Put(bucket, key, data)
+}
+
+func (db *TieredDB) PutInBatch(records []interfaces.Record) error {
+	for _, r := range records {
+		db.cache.remove(r.Bucket, r.Key)
+	}
+	return db.hot.PutInBatch(records)
+}
+
+func (db *TieredDB) Get(bucket, key []byte, destination interfaces.BinaryMarshallable) (interfaces.BinaryMarshallable, error) {
+	answer, err := db.hot.Get(bucket, key, destination)
+	if err != nil || answer != nil {
+		return answer, err
+	}
+
+	data, err := db.getCold(bucket, key)
+	if err != nil || data == nil {
+		return nil, err
+	}
+	_, err = destination.UnmarshalBinaryData(data)
+	if err != nil {
+		return nil, err
+	}
+	return destination, nil
+}
+
+// getCold reads a record from the cache, or the cold store
+func (db *TieredDB) getCold(bucket, key []byte) ([]byte, error) {
+	if data := db.cache.get(bucket, key); data != nil {
+		return data, nil
+	}
+	data, err := db.cold.Get(bucket, key)
+	if err != nil || data == nil {
+		return nil, err
+	}
+	db.cache.add(bucket, key, data)
+	return data, nil
+}
+
+func (db *TieredDB) Delete(bucket, key []byte) error {
+	db.cache.remove(bucket, key)
+	if err := db.hot.Delete(bucket, key); err != nil {
+		return err
+	}
+	return db.cold.Delete(bucket, key)
+}
+
+func (db *TieredDB) ListAllKeys(bucket []byte) ([][]byte, error) {
+	keys, err := db.hot.ListAllKeys(bucket)
+	if err != nil {
+		return nil, err
+	}
+	coldKeys, err := db.coldOnlyKeys(bucket, keys)
+	if err != nil {
+		return nil, err
+	}
+	return append(keys, coldKeys...), nil
+}
+
+func (db *TieredDB) GetAll(bucket []byte, sample interfaces.BinaryMarshallableAndCopyable) ([]interfaces.BinaryMarshallableAndCopyable, [][]byte, error) {
+	all, keys, err := db.hot.GetAll(bucket, sample)
+	if err != nil {
+		return nil, nil, err
+	}
+	coldKeys, err := db.coldOnlyKeys(bucket, keys)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, k := range coldKeys {
+		data, err := db.cold.Get(bucket, k)
+		if err != nil {
+			return nil, nil, err
+		}
+		if data == nil {
+			continue
+		}
+		v := sample.New()
+		if _, err := v.UnmarshalBinaryData(data); err != nil {
+			return nil, nil, err
+		}
+		all = append(all, v)
+		keys = append(keys, k)
+	}
+	return all, keys, nil
+}
+
+// coldOnlyKeys returns the keys in the cold store's bucket that aren't in hotKeys
+func (db *TieredDB) coldOnlyKeys(bucket []byte, hotKeys [][]byte) ([][]byte, error) {
+	coldKeys, err := db.cold.ListKeys(bucket)
+	if err != nil || len(coldKeys) == 0 {
+		return nil, err
+	}
+	hot := make(map[string]bool, len(hotKeys))
+	for _, k := range hotKeys {
+		hot[string(k)] = true
+	}
+	var answer [][]byte
+	for _, k := range coldKeys {
+		if !hot[string(k)] {
+			answer = append(answer, k)
+		}
+	}
+	return answer, nil
+}
+
+func (db *TieredDB) Clear(bucket []byte) error {
+	if err := db.hot.Clear(bucket); err != nil {
+		return err
+	}
+	keys, err := db.cold.ListKeys(bucket)
+	if err != nil {
+		return err
+	}
+	for _, k := range keys {
+		db.cache.remove(bucket, k)
+		if err := db.cold.Delete(bucket, k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (db *TieredDB) DoesKeyExist(bucket, key []byte) (bool, error) {
+	exist, err := db.hot.DoesKeyExist(bucket, key)
+	if err != nil || exist {
+		return exist, err
+	}
+	data, err := db.getCold(bucket, key)
+	return data != nil, err
+}
+
+// Demote moves a record from the local database to the cold store.  It is written to
+// the cold store before it is deleted locally, so it can always be read.
+func (db *TieredDB) Demote(bucket, key []byte) error {
+	answer, err := db.hot.Get(bucket, key, new(primitives.ByteSlice))
+	if err != nil || answer == nil {
+		return err
+	}
+	if err := db.cold.Put(bucket, key, answer.(*primitives.ByteSlice).Bytes); err != nil {
+		return err
+	}
+	return db.hot.Delete(bucket, key)
+}
+
+// Prefetch reads records from the cold store into the cache in the background
+func (db *TieredDB) Prefetch(bucket []byte, keys [][]byte) {
+	go func() {
+		for _, k := range keys {
+			if exist, _ := db.hot.DoesKeyExist(bucket, k); exist {
+				continue
+			}
+			if _, err := db.getCold(bucket, k); err != nil {
+				return
+			}
+		}
+	}()
+}
+
+func (db *TieredDB) GetColdHeight() (uint32, error) {
+	answer, err := db.hot.Get(COLD_STORAGE, COLD_STORAGE_HEIGHT, new(primitives.ByteSlice))
+	if err != nil || answer == nil {
+		return 0, err
+	}
+	data := answer.(*primitives.ByteSlice).Bytes
+	if len(data) != 4 {
+		return 0, nil
+	}
+	return binary.BigEndian.Uint32(data), nil
+}
+
+func (db *TieredDB) SetColdHeight(height uint32) error {
+	data := make([]byte, 4)
+	binary.BigEndian.PutUint32(data, height)
+	return db.hot.Put(COLD_STORAGE, COLD_STORAGE_HEIGHT, &primitives.ByteSlice{Bytes: data})
+}
+
+// recordCache keeps the records most recently read from the cold store
+type recordCache struct {
+	mutex   sync.Mutex
+	size    int
+	order   *list.List // Most recently used at the front
+	records map[string]*list.Element
+}
+
+type cachedRecord struct {
+	id   string
+	data []byte
+}
+
+func newRecordCache(size int) *recordCache {
+	if size <= 0 {
+		size = DefaultCacheSize
+	}
+	c := new(recordCache)
+	c.size = size
+	c.order = list.New()
+	c.records = make(map[string]*list.Element)
+	return c
+}
+
+func cacheID(bucket, key []byte) string {
+	return string(bucket) + "\x00" + string(key)
+}
+
+func (c *recordCache) get(bucket, key []byte) []byte {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	e := c.records[cacheID(bucket, key)]
+	if e == nil {
+		return nil
+	}
+	c.order.MoveToFront(e)
+	return e.Value.(*cachedRecord).data
+}
+
+func (c *recordCache) add(bucket, key, data []byte) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	id := cacheID(bucket, key)
+	if e := c.records[id]; e != nil {
+		e.Value.(*cachedRecord).data = data
+		c.order.MoveToFront(e)
+		return
+	}
+	c.records[id] = c.order.PushFront(&cachedRecord{id: id, data: data})
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.records, oldest.Value.(*cachedRecord).id)
+	}
+}
+
+func (c *recordCache) remove(bucket, key []byte) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	id := cacheID(bucket, key)
+	if e := c.records[id]; e != nil {
+		c.order.Remove(e)
+		delete(c.records, id)
+	}
+}
+
+func (c *recordCache) clear() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.order.Init()
+	c.records = make(map[string]*list.Element)
+}
@@ -0,0 +1,128 @@
+package tieredDB_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/database/mapdb"
+	. "github.com/FactomProject/factomd/database/tieredDB"
+)
+
+type TestData struct {
+	Str string
+}
+
+func (t *TestData) New() interfaces.BinaryMarshallableAndCopyable {
+	return new(TestData)
+}
+
+func (t *TestData) MarshalBinary() ([]byte, error) {
+	return []byte(t.Str), nil
+}
+
+func (t *TestData) UnmarshalBinaryData(data []byte) ([]byte, error) {
+	t.Str = string(data)
+	return nil, nil
+}
+
+func (t *TestData) UnmarshalBinary(data []byte) (err error) {
+	_, err = t.UnmarshalBinaryData(data)
+	return
+}
+
+var _ interfaces.BinaryMarshallable = (*TestData)(nil)
+
+func newTestDB(t *testing.T) (*TieredDB, func()) {
+	dir, err := ioutil.TempDir("", "tieredTest")
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	cold, err := NewDirColdStore(dir)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	hot := new(mapdb.MapDB)
+	hot.Init(nil)
+	return NewTieredDB(hot, cold, 2), func() { os.RemoveAll(dir) }
+}
+
+func TestDemote(t *testing.T) {
+	m, cleanup := newTestDB(t)
+	defer cleanup()
+
+	bucket := []byte("bucket")
+	keys := [][]byte{[]byte("a"), []byte("b"), []byte("c")}
+	for _, k := range keys {
+		if err := m.Put(bucket, k, &TestData{Str: "data " + string(k)}); err != nil {
+			t.Errorf("%v", err)
+		}
+	}
+
+	for _, k := range keys[:2] {
+		if err := m.Demote(bucket, k); err != nil {
+			t.Errorf("%v", err)
+		}
+	}
+
+	// Demoted records are still served, whether or not they are in the cache
+	for i := 0; i < 2; i++ {
+		for _, k := range keys {
+			resp, err := m.Get(bucket, k, new(TestData))
+			if err != nil {
+				t.Errorf("%v", err)
+			}
+			if resp == nil || resp.(*TestData).Str != "data "+string(k) {
+				t.Errorf("Wrong data for %s, %v", k, resp)
+			}
+			exist, err := m.DoesKeyExist(bucket, k)
+			if err != nil || !exist {
+				t.Errorf("Key %s not found, %v", k, err)
+			}
+		}
+	}
+
+	all, allKeys, err := m.GetAll(bucket, new(TestData))
+	if err != nil {
+		t.Errorf("%v", err)
+	}
+	if len(all) != 3 || len(allKeys) != 3 {
+		t.Errorf("Expected 3 records, found %d", len(all))
+	}
+
+	if err := m.Delete(bucket, keys[0]); err != nil {
+		t.Errorf("%v", err)
+	}
+	resp, err := m.Get(bucket, keys[0], new(TestData))
+	if err != nil {
+		t.Errorf("%v", err)
+	}
+	if resp != nil {
+		t.Errorf("resp is not nil while it should be")
+	}
+	listed, err := m.ListAllKeys(bucket)
+	if err != nil {
+		t.Errorf("%v", err)
+	}
+	if len(listed) != 2 {
+		t.Errorf("Expected 2 keys, found %d", len(listed))
+	}
+}
+
+func TestColdHeight(t *testing.T) {
+	m, cleanup := newTestDB(t)
+	defer cleanup()
+
+	h, err := m.GetColdHeight()
+	if err != nil || h != 0 {
+		t.Errorf("Expected height 0, found %d %v", h, err)
+	}
+	if err := m.SetColdHeight(1234); err != nil {
+		t.Errorf("%v", err)
+	}
+	h, err = m.GetColdHeight()
+	if err != nil || h != 1234 {
+		t.Errorf("Expected height 1234, found %d %v", h, err)
+	}
+}
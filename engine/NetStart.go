@@ -14,12 +14,15 @@ import (
 	"os"
 	"time"
 
+	"github.com/FactomProject/factomd/common/constants"
 	"github.com/FactomProject/factomd/common/identity"
 	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/common/messages"
 	"github.com/FactomProject/factomd/common/primitives"
 	"github.com/FactomProject/factomd/controlPanel"
+	"github.com/FactomProject/factomd/database/badgerdb"
 	"github.com/FactomProject/factomd/database/leveldb"
+	"github.com/FactomProject/factomd/grpcapi"
 	"github.com/FactomProject/factomd/p2p"
 	"github.com/FactomProject/factomd/state"
 	"github.com/FactomProject/factomd/util"
@@ -63,6 +66,7 @@ func NetStart(s *state.State, p *FactomParams, listenToStdin bool) {
 	s.TimeOffset = primitives.NewTimestampFromMilliseconds(uint64(p.timeOffset))
 	s.StartDelayLimit = p.StartDelay * 1000
 	s.Journaling = p.Journaling
+	s.ConsensusRecordFile = p.RecordConsensus
 	s.FactomdVersion = FactomdVersion
 
 	log.SetOutput(os.Stdout)
@@ -168,7 +172,11 @@ func NetStart(s *state.State, p *FactomParams, listenToStdin bool) {
 			p2pProxy.stopProxy()
 		}
 		fmt.Print("Waiting...\r\n")
-		time.Sleep(3 * time.Second)
+		for _, fnode := range fnodes {
+			if !fnode.State.WaitShutdown() {
+				fmt.Print(fnode.State.FactomNodeName, " is taking too long to shut down\r\n")
+			}
+		}
 		os.Exit(0)
 	})
 
@@ -231,6 +239,18 @@ func NetStart(s *state.State, p *FactomParams, listenToStdin bool) {
 	os.Stderr.WriteString(fmt.Sprintf("%20s \"%s\"\n", "net spec", pnet))
 	os.Stderr.WriteString(fmt.Sprintf("%20s %d\n", "Msgs droped", p.DropRate))
 	os.Stderr.WriteString(fmt.Sprintf("%20s \"%s\"\n", "journal", p.Journal))
+	if p.RecordConsensus != "" {
+		os.Stderr.WriteString(fmt.Sprintf("%20s \"%s\"\n", "consensus record", p.RecordConsensus))
+	}
+	if p.ReplayConsensus != "" {
+		os.Stderr.WriteString(fmt.Sprintf("%20s \"%s\"\n", "consensus replay", p.ReplayConsensus))
+	}
+	if p.ExportSnapshot != "" {
+		os.Stderr.WriteString(fmt.Sprintf("%20s \"%s\"\n", "export snapshot", p.ExportSnapshot))
+	}
+	if p.ImportSnapshot != "" {
+		os.Stderr.WriteString(fmt.Sprintf("%20s \"%s\"\n", "import snapshot", p.ImportSnapshot))
+	}
 	os.Stderr.WriteString(fmt.Sprintf("%20s \"%s\"\n", "database", p.Db))
 	os.Stderr.WriteString(fmt.Sprintf("%20s \"%s\"\n", "database for clones", p.CloneDB))
 	os.Stderr.WriteString(fmt.Sprintf("%20s \"%s\"\n", "peers", p.Peers))
@@ -246,6 +266,9 @@ func NetStart(s *state.State, p *FactomParams, listenToStdin bool) {
 	os.Stderr.WriteString(fmt.Sprintf("%20s %v\n", "Network", s.Network))
 	os.Stderr.WriteString(fmt.Sprintf("%20s %x\n", "customnet", p.customNet))
 	os.Stderr.WriteString(fmt.Sprintf("%20s %v\n", "deadline (ms)", p.deadline))
+	os.Stderr.WriteString(fmt.Sprintf("%20s %v\n", "admission pow bits", p.admissionPoW))
+	os.Stderr.WriteString(fmt.Sprintf("%20s %v\n", "admission req rate", p.admissionRequestRate))
+	os.Stderr.WriteString(fmt.Sprintf("%20s %v\n", "admission msg rate", p.admissionMessageRate))
 	os.Stderr.WriteString(fmt.Sprintf("%20s %v\n", "tls", s.FactomdTLSEnable))
 	os.Stderr.WriteString(fmt.Sprintf("%20s %v\n", "selfaddr", s.FactomdLocations))
 	os.Stderr.WriteString(fmt.Sprintf("%20s \"%s\"\n", "rpcuser", s.RpcUser))
@@ -315,6 +338,16 @@ func NetStart(s *state.State, p *FactomParams, listenToStdin bool) {
 	connectionMetricsChannel := make(chan interface{}, p2p.StandardChannelSize)
 	p2p.NetworkDeadline = time.Duration(p.deadline) * time.Millisecond
 
+	setCostlyAppTypes()
+	setResponseAppTypes()
+	setCompressedAppTypes()
+	if p.admissionPoW > 0 && p.admissionPoW < 256 {
+		p2p.AdmissionDifficulty = uint8(p.admissionPoW)
+	}
+	p2p.AdmissionRequestRate = p.admissionRequestRate
+	p2p.AdmissionMessageRate = p.admissionMessageRate
+	p2p.PassOverPeer = fnodes[0].State.Backpressure.Throttled
+
 	if p.EnableNet {
 		if 0 < p.NetworkPortOverride {
 			networkPort = fmt.Sprintf("%d", p.NetworkPortOverride)
@@ -328,9 +361,18 @@ func NetStart(s *state.State, p *FactomParams, listenToStdin bool) {
 			SeedURL:                  seedURL,
 			SpecialPeers:             specialPeers,
 			ConnectionMetricsChannel: connectionMetricsChannel,
+			Encryption:               s.P2PEncryption,
+			NonRoutable:              s.IsReadReplica(),
+			PeerStore:                state.NewPeerStore(s),
+			Compression:              s.P2PCompression,
 		}
 		p2pNetwork = new(p2p.Controller).Init(ci)
 		fnodes[0].State.NetworkControler = p2pNetwork
+		p2p.ScheduledPeerSaves = true
+		fnodes[0].State.Jobs.Add("peer-save", p2p.PeerSaveInterval, 5*time.Second, func() error {
+			p2pNetwork.SavePeers()
+			return nil
+		})
 		p2pNetwork.StartNetwork()
 		p2pProxy = new(P2PProxy).Init(fnodes[0].State.FactomNodeName, "P2P Network").(*P2PProxy)
 		p2pProxy.FromNetwork = p2pNetwork.FromNetwork
@@ -472,6 +514,26 @@ func NetStart(s *state.State, p *FactomParams, listenToStdin bool) {
 		fnodes[0].State.SetUseTorrent(false)
 	}
 
+	if p.ReplayConsensus != "" {
+		ReplayConsensus(fnodes[0].State, p.ReplayConsensus)
+		os.Exit(0)
+	}
+
+	if p.ExportSnapshot != "" {
+		if err := ExportSnapshot(fnodes[0].State, p.ExportSnapshot); err != nil {
+			fmt.Println("Snapshot export failed:", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	if p.ImportSnapshot != "" {
+		if err := ImportSnapshot(fnodes[0].State, p.ImportSnapshot); err != nil {
+			fmt.Println("Snapshot import failed:", err)
+			os.Exit(1)
+		}
+	}
+
 	if p.Journal != "" {
 		go LoadJournal(s, p.Journal)
 		startServers(false)
@@ -479,8 +541,13 @@ func NetStart(s *state.State, p *FactomParams, listenToStdin bool) {
 		startServers(true)
 	}
 
-	// Start the webserver
-	go wsapi.Start(fnodes[0].State)
+	// Start the webserver, then the gRPC API once the webserver has made the TLS certificate
+	go func() {
+		wsapi.Start(fnodes[0].State)
+		if fnodes[0].State.GrpcPort > 0 {
+			grpcapi.Start(fnodes[0].State, fnodes[0].State.GrpcPort)
+		}
+	}()
 
 	// Start prometheus on port
 	launchPrometheus(9876)
@@ -488,6 +555,7 @@ func NetStart(s *state.State, p *FactomParams, listenToStdin bool) {
 	state.RegisterPrometheus()
 	p2p.RegisterPrometheus()
 	leveldb.RegisterPrometheus()
+	badgerdb.RegisterPrometheus()
 	RegisterPrometheus()
 
 	go controlPanel.ServeControlPanel(fnodes[0].State.ControlPanelChannel, fnodes[0].State, connectionMetricsChannel, p2pNetwork, Build)
@@ -529,6 +597,22 @@ func startServers(load bool) {
 			go state.LoadDatabase(fnode.State)
 		}
 		go fnode.State.GoSyncEntries()
+		go fnode.State.GoHandleDataResponses()
+		go fnode.State.GoUptimeBeacon()
+		go fnode.State.GoBackfillPinnedChains()
+		go fnode.State.GoTrackStartup()
+		go fnode.State.GoCheckAnchors()
+		go fnode.State.GoAnchorEthereum()
+		go fnode.State.GoSyncFromCluster()
+		go fnode.State.GoMoveToColdStorage()
+		go fnode.State.GoPruneBlocks()
+		go fnode.State.GoVerifySignatures()
+		go fnode.State.GoTrackSupply()
+		go fnode.State.GoTrackECRateHistory()
+		go fnode.State.GoCheckBlockTiming()
+		go fnode.State.GoCheckAlerts()
+		go fnode.State.GoCheckReceiptSubscriptions()
+		go fnode.State.GoAuditCoinbases()
 		go Timer(fnode.State)
 		go fnode.State.ValidatorLoop()
 	}
@@ -580,3 +664,25 @@ func networkHousekeeping() {
 		p2pProxy.SetWeight(p2pNetwork.GetNumberConnections())
 	}
 }
+
+// setResponseAppTypes says which answers peers owe to our requests, so those that leave
+// them unanswered lose reputation
+func setResponseAppTypes() {
+	p2p.ResponseAppTypes[fmt.Sprintf("%d", constants.MISSING_MSG)] = fmt.Sprintf("%d", constants.MISSING_MSG_RESPONSE)
+}
+
+// setCostlyAppTypes marks the requests that make a node read from the database to answer
+// them, which need a stamp when peers ask for one
+func setCostlyAppTypes() {
+	for _, t := range []byte{constants.MISSING_MSG, constants.MISSING_DATA, constants.DBSTATE_MISSING_MSG, constants.MISSING_ENTRY_BLOCKS, constants.SYNC_REQUEST_MSG} {
+		p2p.CostlyAppTypes[fmt.Sprintf("%d", t)] = true
+	}
+}
+
+// setCompressedAppTypes marks the large messages a node catching up asks for, which go
+// compressed to the peers that take them
+func setCompressedAppTypes() {
+	for _, t := range []byte{constants.DBSTATE_MSG, constants.MISSING_MSG_RESPONSE, constants.SYNC_CHUNK_MSG} {
+		p2p.CompressedAppTypes[fmt.Sprintf("%d", t)] = true
+	}
+}
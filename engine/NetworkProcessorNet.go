@@ -13,6 +13,8 @@ import (
 	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/common/messages"
 	"github.com/FactomProject/factomd/log"
+	"github.com/FactomProject/factomd/p2p"
+	"github.com/FactomProject/factomd/state"
 )
 
 var _ = log.Printf
@@ -78,12 +80,23 @@ func Peers(fnode *FactomNode) {
 				}
 				// Set the highest ack height seen and allow through
 				ackHeight = amsg.(*messages.Ack).DBHeight
+			case constants.BATCH_ACK_MSG:
+				if amsg.(*messages.BatchAck).DBHeight <= ackHeight {
+					return true
+				}
+				ackHeight = amsg.(*messages.BatchAck).DBHeight
 			}
 		}
 		return false
 	}
 
 	for {
+		// A node shutting down takes nothing more from the API or its peers
+		if fnode.State.IsStopping() {
+			time.Sleep(100 * time.Millisecond)
+			continue
+		}
+
 		for i := 0; i < 100 && fnode.State.APIQueue().Length() > 0; i++ {
 			msg := fnode.State.APIQueue().Dequeue()
 			if msg != nil {
@@ -109,7 +122,7 @@ func Peers(fnode *FactomNode) {
 					msg.GetTimestamp(),
 					fnode.State.GetTimestamp()) {
 					//fnode.MLog.add2(fnode, false, fnode.State.FactomNodeName, "API", true, msg)
-					if fnode.State.InMsgQueue().Length() < 9000 {
+					if fnode.State.InMsgBacklog() < fnode.State.InMsgQueue().Cap()*9/10 {
 						fnode.State.InMsgQueue().Enqueue(msg)
 					}
 				} else {
@@ -151,6 +164,34 @@ func Peers(fnode *FactomNode) {
 
 				msg.SetOrigin(i + 1)
 
+				// Simulated peers have no network origin, so go by the peer's name
+				from := msg.GetNetworkOrigin()
+				if from == "" {
+					from = peer.GetNameTo()
+				}
+				// Faults injected into the simulated network drop messages before anything
+				// sees them, as a lossy network would
+				var faultDelay time.Duration
+				if SimFaults.active() {
+					var drop bool
+					if drop, faultDelay = SimFaults.Apply(nodeIndex(peer.GetNameTo()), fnode.Index, msg); drop {
+						continue
+					}
+				}
+
+				fnode.State.ObservePeerHeight(from, msg)
+
+				// Throttles are taken at once, as the peer is busy now
+				if fnode.State.ObserveThrottle(from, msg) {
+					continue
+				}
+
+				// A read replica takes only blocks and entries from its peers
+				if fnode.State.IsReadReplica() && !state.ReplicaTakes(msg.Type()) {
+					ReplicaFilteredMsgs.WithLabelValues("in").Inc()
+					continue
+				}
+
 				// Make sure message isn't a FCT transaction in a block
 				_, bv := fnode.State.Replay.Valid(constants.BLOCK_REPLAY,
 					msg.GetRepeatHash().Fixed(),
@@ -173,8 +214,19 @@ func Peers(fnode *FactomNode) {
 					fnode.MLog.Add2(fnode, false, peer.GetNameTo(), nme, true, msg)
 
 					// Ignore messages if there are too many.
-					if fnode.State.InMsgQueue().Length() < 9000 && !ignoreMsg(msg) {
-						fnode.State.InMsgQueue().Enqueue(msg)
+					if fnode.State.InMsgBacklog() < fnode.State.InMsgQueue().Cap()*9/10 && !ignoreMsg(msg) {
+						// Messages of a type with an injected delay are held before being queued
+						d := fnode.State.GetMessageDelay(msg.Type())
+						if faultDelay > d {
+							d = faultDelay
+						}
+						if d > 0 {
+							s := fnode.State
+							delayed := msg
+							time.AfterFunc(d, func() { s.QueuePeerMsg(delayed) })
+						} else {
+							fnode.State.QueuePeerMsg(msg)
+						}
 					}
 				} else {
 					RepeatMsgs.Inc()
@@ -202,6 +254,11 @@ func NetworkOutputs(fnode *FactomNode) {
 		// generated by the timer for the leaders which needs to be processed, but replaced
 		// by an updated version when the block is ready.
 		if !msg.IsLocal() {
+			// A read replica sends nothing of consensus
+			if fnode.State.IsReadReplica() && !state.ReplicaSends(msg.Type()) {
+				ReplicaFilteredMsgs.WithLabelValues("out").Inc()
+				continue
+			}
 			// Don't do a rand int if drop rate is 0
 			if fnode.State.GetDropRate() > 0 && rand.Int()%1000 < fnode.State.GetDropRate() {
 				//drop the message, rather than processing it normally
@@ -228,7 +285,7 @@ func NetworkOutputs(fnode *FactomNode) {
 					// Must have a Peer to send a message to a peer
 					if len(fnode.Peers) > 0 {
 						if p < 0 {
-							p = rand.Int() % len(fnode.Peers)
+							p = randomPeer(fnode)
 						}
 						fnode.MLog.Add2(fnode, true, fnode.Peers[p].GetNameTo(), "P2P out", true, msg)
 						if !fnode.State.GetNetStateOff() {
@@ -268,18 +325,30 @@ func NetworkOutputs(fnode *FactomNode) {
 	}
 }
 
-// Just throw away the trash
+// randomPeer picks a peer for a message for any peer, passing over those that throttled us
+// if any other will do
+func randomPeer(fnode *FactomNode) int {
+	start := rand.Int() % len(fnode.Peers)
+	for i := range fnode.Peers {
+		p := (start + i) % len(fnode.Peers)
+		if !fnode.State.Backpressure.Throttled(fnode.Peers[p].GetNameTo()) {
+			return p
+		}
+	}
+	return start
+}
+
+// InvalidOutputs charges the peers that sent invalid messages against their reputation.
+// The consensus system doesn't limit the messages it puts here to ones that indicate an
+// attack, so an invalid message costs the peer little, and the cost fades; only a peer
+// that keeps sending them is demoted or banned.
 func InvalidOutputs(fnode *FactomNode) {
 	for {
 		time.Sleep(1 * time.Millisecond)
-		_ = <-fnode.State.NetworkInvalidMsgQueue()
-		//fmt.Println(invalidMsg)
-
-		// The following code was giving a demerit for each instance of a message in the NetworkInvalidMsgQueue.
-		// However the concensus system is not properly limiting the messages going into this queue to be ones
-		//  indicating an attack.  So the demerits are turned off for now.
-		// if len(invalidMsg.GetNetworkOrigin()) > 0 {
-		// 	p2pNetwork.AdjustPeerQuality(invalidMsg.GetNetworkOrigin(), -2)
-		// }
+		invalidMsg := <-fnode.State.NetworkInvalidMsgQueue()
+
+		if network := fnode.State.NetworkControler; network != nil && len(invalidMsg.GetNetworkOrigin()) > 0 {
+			network.ReportPeer(invalidMsg.GetNetworkOrigin(), p2p.OffenseInvalid)
+		}
 	}
 }
@@ -0,0 +1,75 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/wsapi/client"
+)
+
+// "factomd capacity" prints the capacity report of a running node, from its "capacity"
+// API method: the entries it sustains against what it could take, the headroom of its
+// queues, how fast its database grows and when the disk fills, and its GC pauses.
+
+// Capacity implements "factomd capacity"
+func Capacity(args []string) error {
+	var url string
+	var asJSON bool
+	flags := flag.NewFlagSet("capacity", flag.ContinueOnError)
+	flags.StringVar(&url, "api", client.DefaultURL, "API of the node to report on")
+	flags.BoolVar(&asJSON, "json", false, "Report as JSON")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	report, err := client.NewClient(url).Capacity()
+	if err != nil {
+		return err
+	}
+	if asJSON {
+		data, _ := json.MarshalIndent(report, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+	printCapacityReport(report)
+	return nil
+}
+
+func printCapacityReport(r *interfaces.CapacityReport) {
+	window := time.Duration(r.WindowSeconds) * time.Second
+	fmt.Printf("Entries/sec        %10.2f over %s\n", r.EntriesPerSecond, window)
+	if r.MaxEntriesPerSecond > 0 {
+		fmt.Printf("Max entries/sec    %10.2f (%.3fms an entry)\n", r.MaxEntriesPerSecond, r.EntryExecuteMs)
+		fmt.Printf("Utilization        %9.1f%%\n", r.Utilization*100)
+	} else {
+		fmt.Printf("Max entries/sec    %10s (no entries executed yet)\n", "-")
+	}
+
+	fmt.Printf("\n%-8s %8s %8s %9s\n", "Queue", "Depth", "Cap", "Headroom")
+	for _, q := range r.Queues {
+		fmt.Printf("%-8s %8d %8d %8.1f%%\n", q.Name, q.Depth, q.Cap, q.Headroom*100)
+	}
+
+	fmt.Printf("\nDatabase           %10.1f MB\n", float64(r.DatabaseBytes)/1e6)
+	fmt.Printf("Growth             %10.1f MB/day over %s\n", r.GrowthBytesPerDay/1e6, time.Duration(r.GrowthWindowSeconds)*time.Second)
+	if r.DiskFreeBytes > 0 {
+		fmt.Printf("Disk free          %10.1f GB\n", float64(r.DiskFreeBytes)/1e9)
+	} else {
+		fmt.Printf("Disk free          %10s\n", "unknown")
+	}
+	if r.DaysUntilDiskFull >= 0 {
+		fmt.Printf("Disk full in       %10.0f days\n", r.DaysUntilDiskFull)
+	} else {
+		fmt.Printf("Disk full in       %10s\n", "-")
+	}
+
+	g := r.GCPauses
+	fmt.Printf("\nGC pauses (ms)     p50 %.3f  p90 %.3f  p99 %.3f  max %.3f  of %d\n", g.P50, g.P90, g.P99, g.Max, g.Count)
+}
@@ -0,0 +1,275 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/state"
+	"github.com/FactomProject/factomd/wsapi"
+)
+
+// "factomd devnet up --nodes N" gives application developers a local network of N nodes
+// in one process, on the simulator.  Once the first blocks are made it generates
+// identities, promotes the leaders and audit servers asked for, and starts an API for
+// every node on ports of its own.  The control API lists the nodes and passes commands
+// to the simulator, as typed at its prompt.
+
+// DevnetConfig is the network "devnet up" makes
+type DevnetConfig struct {
+	Nodes       int
+	Leaders     int
+	Audits      int
+	Port        int // API of the first node; the others follow it
+	ControlPort int // Control API, 0 for the port after the last node's API
+	BlockTime   int
+	DB          string
+	FactomHome  string
+}
+
+// ParseDevnetArgs parses the arguments of "devnet up"
+func ParseDevnetArgs(args []string) (*DevnetConfig, error) {
+	c := new(DevnetConfig)
+	flags := flag.NewFlagSet("devnet up", flag.ContinueOnError)
+	flags.IntVar(&c.Nodes, "nodes", 3, "Nodes in the network")
+	flags.IntVar(&c.Leaders, "leaders", 0, "Nodes made leaders, 0 for all of them")
+	flags.IntVar(&c.Audits, "audits", 0, "Nodes made audit servers")
+	flags.IntVar(&c.Port, "port", 8088, "API port of the first node; node i serves on port+i")
+	flags.IntVar(&c.ControlPort, "control", 0, "Port of the control API, 0 for the port after the last node's API")
+	flags.IntVar(&c.BlockTime, "blktime", 30, "Seconds per block")
+	flags.StringVar(&c.DB, "db", "Map", "Database of the nodes. Options Map, LDB, Bolt, or Badger")
+	flags.StringVar(&c.FactomHome, "factomhome", "", "Set the factom home directory, for the config and the databases")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if c.Leaders == 0 {
+		c.Leaders = c.Nodes - c.Audits
+	}
+	if c.ControlPort == 0 {
+		c.ControlPort = c.Port + c.Nodes
+	}
+	if err := c.check(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// check says what is wrong with the network, if anything
+func (c *DevnetConfig) check() error {
+	switch {
+	case c.Nodes < 1:
+		return fmt.Errorf("a network needs a node")
+	case c.Leaders < 1:
+		return fmt.Errorf("a network needs a leader")
+	case c.Audits < 0 || c.Leaders+c.Audits > c.Nodes:
+		return fmt.Errorf("%d leaders and %d audit servers need %d nodes, not %d", c.Leaders, c.Audits, c.Leaders+c.Audits, c.Nodes)
+	case c.BlockTime < 10:
+		return fmt.Errorf("blocks under 10 seconds leave no time for the minutes")
+	case c.Port < 1024 || c.Port+c.Nodes+2 > 65535:
+		return fmt.Errorf("ports %d to %d are out of range", c.Port, c.Port+c.Nodes+2)
+	}
+	return nil
+}
+
+// FactomdArgs returns the command line of the simulator running the network.  The nodes
+// talk over simulated peers, so there is no p2p port; the control panel and pprof take
+// the ports after the control API.
+func (c *DevnetConfig) FactomdArgs() []string {
+	args := []string{
+		"-network=LOCAL",
+		"-enablenet=false",
+		"-net=alot+",
+		fmt.Sprintf("-count=%d", c.Nodes),
+		fmt.Sprintf("-blktime=%d", c.BlockTime),
+		fmt.Sprintf("-db=%s", c.DB),
+		fmt.Sprintf("-port=%d", c.Port),
+		fmt.Sprintf("-ControlPanelPort=%d", c.ControlPort+1),
+		fmt.Sprintf("-logPort=%d", c.ControlPort+2),
+		"-startdelay=0",
+		"-faulttimeout=20",
+	}
+	if c.DB == "Map" {
+		args = append(args, "-fast=false")
+	}
+	if c.FactomHome != "" {
+		args = append(args, "-factomhome="+c.FactomHome)
+	}
+	return args
+}
+
+// Devnet implements "factomd devnet"
+func Devnet(args []string) error {
+	if len(args) == 0 || args[0] != "up" {
+		return fmt.Errorf("usage: factomd devnet up [--nodes N] [--leaders L] [--audits A] [--port P]")
+	}
+	c, err := ParseDevnetArgs(args[1:])
+	if err != nil {
+		return err
+	}
+	return DevnetUp(c)
+}
+
+// DevnetUp starts the network and runs it until interrupted
+func DevnetUp(c *DevnetConfig) error {
+	state0 := Factomd(ParseCmdLine(c.FactomdArgs()), false).(*state.State)
+	for len(GetFnodes()) < c.Nodes {
+		time.Sleep(100 * time.Millisecond)
+	}
+	fmt.Fprintf(os.Stderr, "Waiting for the first block of %d nodes\n", c.Nodes)
+	waitForHeight(state0, 1)
+
+	// Every node serves an API of its own
+	for i, fnode := range GetFnodes() {
+		if i > 0 {
+			fnode.State.SetPort(c.Port + i)
+			wsapi.Start(fnode.State)
+		}
+	}
+
+	promoteNodes(state0, c.Leaders, c.Audits)
+
+	control := &http.Server{Addr: fmt.Sprintf(":%d", c.ControlPort), Handler: DevnetControlHandler()}
+	go func() {
+		if err := control.ListenAndServe(); err != nil {
+			fmt.Fprintf(os.Stderr, "Control API stopped: %v\n", err)
+		}
+	}()
+
+	fmt.Fprintf(os.Stderr, "%-8s %-10s %-10s %s\n", "Node", "Role", "Identity", "API")
+	for _, n := range DevnetNodes() {
+		fmt.Fprintf(os.Stderr, "%-8s %-10s %-10s %s\n", n.Name, n.Role, n.Identity[:10], n.API)
+	}
+	fmt.Fprintf(os.Stderr, "Control API on http://localhost:%d, control panel on http://localhost:%d\n", c.ControlPort, c.ControlPort+1)
+
+	// The interrupt handler NetStart set up stops the nodes and exits
+	for state0.Running() {
+		time.Sleep(time.Second)
+	}
+	return nil
+}
+
+// promoteNodes makes nodes 1 on leaders, and the audit servers after them.  Node 0 is the
+// leader the network starts with.  Identities have to be in a block before their nodes can
+// be promoted, and each promotion moves to the next node.
+func promoteNodes(state0 *state.State, leaders, audits int) {
+	promoted := leaders - 1 + audits
+	if promoted <= 0 {
+		return
+	}
+	simCommand("0")
+	simCommand(fmt.Sprintf("g%d", promoted))
+	waitForHeight(state0, state0.GetLLeaderHeight()+2)
+	simCommand("1")
+	for i := 1; i < leaders; i++ {
+		simCommand("l")
+	}
+	for i := 0; i < audits; i++ {
+		simCommand("o")
+	}
+	simCommand("0")
+	waitForHeight(state0, state0.GetLLeaderHeight()+1)
+}
+
+// Commands are run by SimControl one at a time
+var simCommandMutex sync.Mutex
+
+// simCommand runs a command of the simulator, as typed at its prompt
+func simCommand(cmd string) {
+	simCommandMutex.Lock()
+	defer simCommandMutex.Unlock()
+	InputChan <- cmd
+	<-ProcessChan
+}
+
+func waitForHeight(s *state.State, ht uint32) {
+	for s.GetLLeaderHeight() < ht {
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// DevnetNode is what the control API says of a node
+type DevnetNode struct {
+	Index    int    `json:"index"`
+	Name     string `json:"name"`
+	Identity string `json:"identity"`
+	Role     string `json:"role"` // leader, audit or follower
+	API      string `json:"api"`
+	Height   uint32 `json:"height"`
+	Minute   int    `json:"minute"`
+}
+
+// DevnetNodes describes the nodes of the network
+func DevnetNodes() []DevnetNode {
+	var nodes []DevnetNode
+	for i, fnode := range GetFnodes() {
+		s := fnode.State
+		n := DevnetNode{
+			Index:    i,
+			Name:     s.GetFactomNodeName(),
+			Identity: s.GetIdentityChainID().String(),
+			Role:     "follower",
+			API:      fmt.Sprintf("http://localhost:%d/v2", s.GetPort()),
+			Height:   s.GetLLeaderHeight(),
+			Minute:   s.GetCurrentMinute(),
+		}
+		if s.Leader {
+			n.Role = "leader"
+		} else if pl := s.ProcessLists.Get(s.LLeaderHeight); pl != nil {
+			if audit, _ := pl.GetAuditServerIndexHash(s.GetIdentityChainID()); audit {
+				n.Role = "audit"
+			}
+		}
+		nodes = append(nodes, n)
+	}
+	return nodes
+}
+
+// DevnetControlHandler serves the control API.  GET /nodes returns the nodes, their roles,
+// heights and APIs; POST /command runs the command of the simulator in the body.
+func DevnetControlHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/nodes", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "GET only", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(DevnetNodes())
+	})
+	mux.HandleFunc("/command", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "POST only", http.StatusMethodNotAllowed)
+			return
+		}
+		body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, 1024))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		cmd := strings.TrimSpace(string(body))
+		if cmd == "" {
+			http.Error(w, "no command", http.StatusBadRequest)
+			return
+		}
+		// Node numbers switch the simulator's focus, so they have to name a node
+		if n, err := strconv.Atoi(cmd); err == nil && (n < 0 || n >= len(GetFnodes())) {
+			http.Error(w, "no such node", http.StatusBadRequest)
+			return
+		}
+		simCommand(cmd)
+		w.WriteHeader(http.StatusAccepted)
+	})
+	return mux
+}
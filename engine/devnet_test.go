@@ -0,0 +1,43 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package engine_test
+
+import (
+	"testing"
+
+	. "github.com/FactomProject/factomd/engine"
+)
+
+func TestParseDevnetArgs(t *testing.T) {
+	c, err := ParseDevnetArgs([]string{"--nodes", "5", "--audits", "2", "--port", "9000"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Leaders != 3 || c.ControlPort != 9005 {
+		t.Errorf("Got %d leaders and control port %d, expected 3 and 9005", c.Leaders, c.ControlPort)
+	}
+
+	args := map[string]bool{}
+	for _, a := range c.FactomdArgs() {
+		args[a] = true
+	}
+	for _, a := range []string{"-count=5", "-port=9000", "-ControlPanelPort=9006", "-logPort=9007", "-enablenet=false", "-network=LOCAL"} {
+		if !args[a] {
+			t.Errorf("Simulator isn't run with %s: %v", a, c.FactomdArgs())
+		}
+	}
+
+	for _, bad := range [][]string{
+		{"--nodes", "0"},
+		{"--nodes", "3", "--leaders", "2", "--audits", "2"},
+		{"--nodes", "3", "--audits", "3"},
+		{"--blktime", "5"},
+		{"--port", "65534"},
+	} {
+		if _, err := ParseDevnetArgs(bad); err == nil {
+			t.Errorf("Accepted %v", bad)
+		}
+	}
+}
@@ -18,6 +18,10 @@ type FactomParams struct {
 	DropRate                 int
 	Journal                  string
 	Journaling               bool
+	RecordConsensus          string
+	ReplayConsensus          string
+	ExportSnapshot           string
+	ImportSnapshot           string
 	Follower                 bool
 	Leader                   bool
 	Db                       string
@@ -57,6 +61,9 @@ type FactomParams struct {
 	exposeProfiling          bool
 	useLogstash              bool
 	logstashURL              string
+	admissionPoW             int
+	admissionRequestRate     float64
+	admissionMessageRate     float64
 }
 
 func (f *FactomParams) Init() {
@@ -107,6 +114,9 @@ func (f *FactomParams) Init() {
 	f.torUpload = false
 	f.Sim_Stdin = true
 	f.exposeProfiling = false
+	f.admissionPoW = 0
+	f.admissionRequestRate = 0
+	f.admissionMessageRate = 0
 }
 
 func ParseCmdLine(args []string) *FactomParams {
@@ -122,9 +132,13 @@ func ParseCmdLine(args []string) *FactomParams {
 	dropPtr := flag.Int("drop", 0, "Number of messages to drop out of every thousand")
 	journalPtr := flag.String("journal", "", "Rerun a Journal of messages")
 	journalingPtr := flag.Bool("journaling", false, "Write a journal of all messages recieved. Default is off.")
+	recordConsensusPtr := flag.String("recordconsensus", "", "Record every message the consensus loop takes to this file, for -replayconsensus")
+	replayConsensusPtr := flag.String("replayconsensus", "", "Load the database, replay a consensus record through the node with no network or timer, print where it ended and exit")
+	exportSnapshotPtr := flag.String("export-snapshot", "", "Load the database, write a snapshot of the state at the highest saved block to this file and exit")
+	importSnapshotPtr := flag.String("import-snapshot", "", "Bootstrap an empty database from a snapshot made with -export-snapshot, then carry on from it")
 	followerPtr := flag.Bool("follower", false, "If true, force node to be a follower.  Only used when replaying a journal.")
 	leaderPtr := flag.Bool("leader", true, "If true, force node to be a leader.  Only used when replaying a journal.")
-	dbPtr := flag.String("db", "", "Override the Database in the Config file and use this Database implementation. Options Map, LDB, or Bolt")
+	dbPtr := flag.String("db", "", "Override the Database in the Config file and use this Database implementation. Options Map, LDB, Bolt, or Badger")
 	cloneDBPtr := flag.String("clonedb", "", "Override the main node and use this database for the clones in a Network.")
 	networkNamePtr := flag.String("network", "", "Network to join: MAIN, TEST or LOCAL")
 	peersPtr := flag.String("peers", "", "Array of peer addresses. ")
@@ -172,6 +186,11 @@ func ParseCmdLine(args []string) *FactomParams {
 	logstash := flag.Bool("logstash", false, "If true, use Logstash")
 	logstashURL := flag.String("logurl", "localhost:8345", "Endpoint URL for Logstash")
 
+	// Admission control of messages from regular peers
+	admissionPoWPtr := flag.Int("admissionpow", 0, "Bits of proof of work required on missing message and dbstate requests from regular peers, and done on ours. 0 for none")
+	admissionRequestRatePtr := flag.Float64("admissionrequestrate", 0, "Missing message and dbstate requests a second accepted from each regular peer. 0 for no limit")
+	admissionMessageRatePtr := flag.Float64("admissionmsgrate", 0, "Other messages a second accepted from each regular peer. 0 for no limit")
+
 	flag.CommandLine.Parse(args)
 
 	p.AckbalanceHash = *ackBalanceHashPtr
@@ -184,6 +203,10 @@ func ParseCmdLine(args []string) *FactomParams {
 	p.DropRate = *dropPtr
 	p.Journal = *journalPtr
 	p.Journaling = *journalingPtr
+	p.RecordConsensus = *recordConsensusPtr
+	p.ReplayConsensus = *replayConsensusPtr
+	p.ExportSnapshot = *exportSnapshotPtr
+	p.ImportSnapshot = *importSnapshotPtr
 	p.Follower = *followerPtr
 	p.Leader = *leaderPtr
 	p.Db = *dbPtr
@@ -225,6 +248,10 @@ func ParseCmdLine(args []string) *FactomParams {
 	p.useLogstash = *logstash
 	p.logstashURL = *logstashURL
 
+	p.admissionPoW = *admissionPoWPtr
+	p.admissionRequestRate = *admissionRequestRatePtr
+	p.admissionMessageRate = *admissionMessageRatePtr
+
 	if *factomHomePtr != "" {
 		os.Setenv("FACTOM_HOME", *factomHomePtr)
 	}
@@ -21,6 +21,23 @@ var (
 		Help: "How many messages are dropped due to full queues",
 	})
 
+	OversizedMsgs = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "factomd_state_oversized_msg_drop_total",
+		Help: "How many messages from peers are dropped for being over the size of their type",
+	})
+	ReplicaFilteredMsgs = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "factomd_state_replica_filtered_msg_total",
+		Help: "How many consensus messages a read replica didn't take from its peers (in) or send them (out)",
+	}, []string{"direction"})
+	SimFaultDrops = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "factomd_state_sim_fault_drop_total",
+		Help: "How many messages between simulated nodes injected faults dropped",
+	})
+	SimFaultDelays = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "factomd_state_sim_fault_delay_total",
+		Help: "How many messages between simulated nodes injected faults delayed",
+	})
+
 	// NetworkReplayFilter
 	TotalNetworkReplayFilter = prometheus.NewCounter(prometheus.CounterOpts{
 		Name: "factomd_state_network_replay_filter_total",
@@ -61,6 +78,10 @@ func RegisterPrometheus() {
 	prometheus.MustRegister(RepeatMsgs)
 	prometheus.MustRegister(BroadInCastQueue)
 	prometheus.MustRegister(BroadCastInQueueDrop)
+	prometheus.MustRegister(OversizedMsgs)
+	prometheus.MustRegister(ReplicaFilteredMsgs)
+	prometheus.MustRegister(SimFaultDrops)
+	prometheus.MustRegister(SimFaultDelays)
 
 	// NetworkReplayFilter
 	prometheus.MustRegister(TotalNetworkReplayFilter)
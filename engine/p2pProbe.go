@@ -0,0 +1,460 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/p2p"
+	"github.com/FactomProject/factomd/util"
+)
+
+// The results of a probe check
+const (
+	ProbePass = "pass"
+	ProbeFail = "fail"
+	ProbeSkip = "skip"
+)
+
+// The checks made by a probe, in the order they are reported
+var probeChecks = []string{"connect", "handshake", "headers", "ping", "peers", "announce", "messages", "block"}
+
+type ProbeCheck struct {
+	Name   string `json:"name"`
+	Result string `json:"result"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// ProbeReport is what a probe learned of a peer.  Times are in milliseconds, 0 if the
+// peer never answered.
+type ProbeReport struct {
+	Address       string         `json:"address"`
+	NodeID        uint64         `json:"nodeid"`
+	Version       uint16         `json:"version"`
+	ListenPort    string         `json:"listenport"`
+	ConnectMs     int64          `json:"connectms"`
+	FirstParcelMs int64          `json:"firstparcelms"`
+	PingMs        int64          `json:"pingms"`
+	BlockHeight   int64          `json:"blockheight"` // Height of the block asked for, -1 if none was
+	BlockMs       int64          `json:"blockms"`
+	Received      map[string]int `json:"received"` // Parcels received, by parcel or message type
+	Checks        []ProbeCheck   `json:"checks"`
+}
+
+// Failed returns the checks that failed
+func (r *ProbeReport) Failed() (failed []ProbeCheck) {
+	for _, c := range r.Checks {
+		if c.Result == ProbeFail {
+			failed = append(failed, c)
+		}
+	}
+	return failed
+}
+
+func (r *ProbeReport) String() string {
+	var out primitives.Buffer
+	out.WriteString(fmt.Sprintf("Peer %s", r.Address))
+	if r.NodeID != 0 {
+		out.WriteString(fmt.Sprintf(", node %x, protocol %d, listening on %s", r.NodeID, r.Version, r.ListenPort))
+	}
+	out.WriteString("\n")
+	for _, c := range r.Checks {
+		out.WriteString(fmt.Sprintf("  %-4s %-10s %s\n", strings.ToUpper(c.Result), c.Name, c.Detail))
+	}
+	if len(r.Received) > 0 {
+		out.WriteString("  Received:")
+		for _, k := range sortedKeys(r.Received) {
+			out.WriteString(fmt.Sprintf(" %s %d", k, r.Received[k]))
+		}
+		out.WriteString("\n")
+	}
+	return out.String()
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// P2PProbe implements "factomd p2p-probe <addr>".  It connects to a peer as a node would,
+// asks it for peers, pings it and asks it for a recent directory block, then reports how
+// well the peer follows the protocol and how long it took to answer.  It returns an error
+// if any check failed, so scripts can use the exit status.
+func P2PProbe(args []string) error {
+	cfg := util.ReadConfig(util.GetConfigFilename("m2"))
+
+	flags := flag.NewFlagSet("p2p-probe", flag.ContinueOnError)
+	networkPtr := flags.String("network", cfg.App.Network, "Network the peer is on: MAIN, TEST, LOCAL or CUSTOM")
+	customNetPtr := flags.String("customnet", "", "The custom network ID, with -network CUSTOM")
+	timeoutPtr := flags.Duration("timeout", 30*time.Second, "How long to wait for the peer to answer")
+	heightPtr := flags.Int("height", -1, "Directory block to ask for; by default the last one the peer is heard to have saved")
+	powPtr := flags.Int("pow", 0, "Leading zero bits of proof of work to stamp the block request with, for peers that require it")
+	capturePtr := flags.String("capture", "", "File to write every parcel sent and received to, as JSON lines")
+	jsonPtr := flags.Bool("json", false, "Print the report as JSON")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: factomd p2p-probe [flags] <host[:port]>")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return fmt.Errorf("a peer address is required")
+	}
+
+	var network p2p.NetworkID
+	var port string
+	switch strings.ToUpper(*networkPtr) {
+	case "MAIN":
+		network, port = p2p.MainNet, cfg.App.MainNetworkPort
+	case "TEST":
+		network, port = p2p.TestNet, cfg.App.TestNetworkPort
+	case "LOCAL":
+		network, port = p2p.LocalNet, cfg.App.LocalNetworkPort
+	case "CUSTOM":
+		network = CustomNetworkID(*customNetPtr)
+		port = cfg.App.LocalNetworkPort
+	default:
+		return fmt.Errorf("unknown network %q", *networkPtr)
+	}
+	address := flags.Arg(0)
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		address = net.JoinHostPort(address, port)
+	}
+
+	p2p.CurrentNetwork = network
+	setCostlyAppTypes()
+	if *powPtr > 0 && *powPtr < 256 {
+		p2p.AdmissionDifficulty = uint8(*powPtr)
+	}
+
+	report, capture := ProbePeer(address, network, *heightPtr, *timeoutPtr)
+
+	if *capturePtr != "" {
+		if err := writeProbeCapture(*capturePtr, capture); err != nil {
+			return err
+		}
+	}
+	if *jsonPtr {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+	} else {
+		fmt.Print(report.String())
+	}
+
+	if failed := report.Failed(); len(failed) > 0 {
+		return fmt.Errorf("%d of %d checks failed", len(failed), len(report.Checks))
+	}
+	return nil
+}
+
+// CustomNetworkID is the network ID of the custom network with the given name, as the
+// -customnet flag makes it
+func CustomNetworkID(name string) p2p.NetworkID {
+	return p2p.NetworkID(binary.BigEndian.Uint32(primitives.Sha([]byte(name)).Bytes()[:4]))
+}
+
+func writeProbeCapture(filename string, capture []p2p.CapturedParcel) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	enc := json.NewEncoder(f)
+	for _, c := range capture {
+		if err := enc.Encode(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// probeRun is the state of one probe of a peer
+type probeRun struct {
+	probe   *p2p.Probe
+	network p2p.NetworkID
+	report  *ProbeReport
+	checks  map[string]ProbeCheck
+
+	pingSent      time.Duration
+	requested     bool
+	requestSent   time.Duration
+	learnedHeight int64 // Highest block the peer's messages show it has saved, -1 if none
+	headerErrors  int
+	firstProblem  string
+	decoded       int
+	undecoded     int
+}
+
+func (r *probeRun) set(name string, result string, format string, v ...interface{}) {
+	r.checks[name] = ProbeCheck{Name: name, Result: result, Detail: fmt.Sprintf(format, v...)}
+}
+
+func (r *probeRun) done(name string) bool {
+	_, ok := r.checks[name]
+	return ok
+}
+
+// ProbePeer probes the peer at address, asking it for the directory block at height, or
+// when height is negative, for the last block the peer is heard to have saved.  It
+// returns the report, and every parcel sent and received.
+func ProbePeer(address string, network p2p.NetworkID, height int, timeout time.Duration) (*ProbeReport, []p2p.CapturedParcel) {
+	r := new(probeRun)
+	r.network = network
+	r.learnedHeight = -1
+	r.checks = make(map[string]ProbeCheck)
+	r.report = new(ProbeReport)
+	r.report.Address = address
+	r.report.BlockHeight = -1
+	r.report.Received = make(map[string]int)
+	defer r.finish()
+
+	probe, err := p2p.DialProbe(address, network, timeout)
+	if err != nil {
+		r.set("connect", ProbeFail, "%v", err)
+		return r.report, nil
+	}
+	defer probe.Close()
+	r.probe = probe
+	r.report.ConnectMs = probe.ConnectTime.Nanoseconds() / 1e6
+	r.set("connect", ProbePass, "%s", probe.ConnectTime)
+
+	// Go online as a connection does, asking for peers, then ping
+	r.send(p2p.TypePeerRequest, []byte("Peer Request"))
+	r.pingSent = probe.Elapsed()
+	r.send(p2p.TypePing, []byte("Ping"))
+	if height >= 0 {
+		r.requestBlock(uint32(height))
+	}
+
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) && !(r.done("ping") && r.done("peers") && r.done("block")) {
+		// Without a height, wait for the peer to show how far it is, for half the time
+		if !r.requested && (r.learnedHeight >= 0 || time.Now().After(deadline.Add(-timeout/2))) {
+			if r.learnedHeight >= 0 {
+				r.requestBlock(uint32(r.learnedHeight))
+			} else {
+				r.requestBlock(0)
+			}
+		}
+		parcel, err := probe.Receive(time.Second)
+		if err == p2p.ErrProbeTimeout {
+			continue
+		}
+		if err != nil {
+			r.set("handshake", ProbeFail, "%v", err)
+			break
+		}
+		r.handle(parcel)
+	}
+	return r.report, probe.Capture()
+}
+
+func (r *probeRun) send(t p2p.ParcelCommandType, payload []byte) {
+	parcel := p2p.NewParcel(r.network, payload)
+	parcel.Header.Type = t
+	if err := r.probe.Send(parcel); err != nil && !r.done("handshake") {
+		r.set("handshake", ProbeFail, "cannot send %s: %v", p2p.CommandStrings[t], err)
+	}
+}
+
+func (r *probeRun) requestBlock(height uint32) {
+	msg := new(messages.DBStateMissing)
+	msg.Peer2Peer = true
+	msg.Timestamp = primitives.NewTimestampNow()
+	msg.DBHeightStart = height
+	msg.DBHeightEnd = height
+	payload, err := msg.MarshalBinary()
+	if err != nil {
+		r.set("block", ProbeFail, "cannot make the request: %v", err)
+		return
+	}
+	parcel := p2p.NewParcel(r.network, payload)
+	parcel.Header.Type = p2p.TypeMessage
+	parcel.Header.AppHash = msg.GetMsgHash().String()
+	parcel.Header.AppType = fmt.Sprintf("%d", msg.Type())
+	if err := r.probe.Send(parcel); err != nil {
+		r.set("block", ProbeFail, "cannot send the request: %v", err)
+	}
+	r.requested = true
+	r.requestSent = r.probe.Elapsed()
+	r.report.BlockHeight = int64(height)
+}
+
+func (r *probeRun) handle(parcel *p2p.Parcel) {
+	now := r.probe.Elapsed()
+	if !r.done("handshake") {
+		r.report.FirstParcelMs = now.Nanoseconds() / 1e6
+		r.report.NodeID = parcel.Header.NodeID
+		r.report.Version = parcel.Header.Version
+		r.report.ListenPort = parcel.Header.PeerPort
+		r.set("handshake", ProbePass, "first parcel (%s) after %s", p2p.CommandStrings[parcel.Header.Type], now)
+	}
+	if problems := p2p.ParcelProblems(parcel, r.network); len(problems) > 0 {
+		r.headerErrors++
+		if r.firstProblem == "" {
+			r.firstProblem = fmt.Sprintf("%s: %s", p2p.CommandStrings[parcel.Header.Type], strings.Join(problems, ", "))
+		}
+	}
+
+	switch parcel.Header.Type {
+	case p2p.TypePing:
+		r.send(p2p.TypePong, []byte("Pong"))
+	case p2p.TypePong:
+		if !r.done("ping") {
+			rtt := now - r.pingSent
+			r.report.PingMs = rtt.Nanoseconds() / 1e6
+			r.set("ping", ProbePass, "pong after %s", rtt)
+		}
+	case p2p.TypePeerResponse:
+		var peers []p2p.Peer
+		if err := json.Unmarshal(parcel.Payload, &peers); err != nil {
+			r.set("peers", ProbeFail, "peer list does not decode: %v", err)
+		} else {
+			r.set("peers", ProbePass, "%d peers shared", len(peers))
+		}
+	case p2p.TypePeerAnnounce:
+		a := new(p2p.PeerAnnouncement)
+		if err := json.Unmarshal(parcel.Payload, a); err != nil {
+			r.set("announce", ProbeFail, "announcement does not decode: %v", err)
+		} else if err := a.Verify(p2p.NodeID, time.Now()); err != nil {
+			r.set("announce", ProbeFail, "%v", err)
+		} else {
+			r.set("announce", ProbePass, "signed by %s", a.PublicKey)
+		}
+	case p2p.TypeMessage:
+		r.handleMessage(parcel, now)
+		return
+	case p2p.TypeMessagePart:
+		r.report.Received[fmt.Sprintf("%s %d/%d", p2p.CommandStrings[parcel.Header.Type], parcel.Header.PartNo+1, parcel.Header.PartsTotal)]++
+		return
+	}
+	r.report.Received[p2p.CommandStrings[parcel.Header.Type]]++
+}
+
+func (r *probeRun) handleMessage(parcel *p2p.Parcel, now time.Duration) {
+	msg, err := messages.UnmarshalMessage(parcel.Payload)
+	if err != nil {
+		r.undecoded++
+		r.report.Received["undecodable"]++
+		return
+	}
+	r.decoded++
+	r.report.Received[messages.MessageName(msg.Type())]++
+
+	// The block being built, as the peer sees it, is one past the last it saved
+	var building uint32
+	switch m := msg.(type) {
+	case *messages.EOM:
+		building = m.DBHeight
+	case *messages.Ack:
+		building = m.DBHeight
+	case *messages.DirectoryBlockSignature:
+		building = m.DBHeight
+	case *messages.Heartbeat:
+		building = m.DBHeight
+	case *messages.DBStateMsg:
+		r.handleBlock(m, now)
+	}
+	if building > 0 && int64(building)-1 > r.learnedHeight {
+		r.learnedHeight = int64(building) - 1
+	}
+}
+
+func (r *probeRun) handleBlock(m *messages.DBStateMsg, now time.Duration) {
+	if !r.requested || r.done("block") || m.DirectoryBlock == nil {
+		return
+	}
+	got := m.DirectoryBlock.GetDatabaseHeight()
+	if int64(got) != r.report.BlockHeight {
+		return
+	}
+	took := now - r.requestSent
+	r.report.BlockMs = took.Nanoseconds() / 1e6
+	if err := dbStateComplete(m); err != nil {
+		r.set("block", ProbeFail, "block %d after %s: %v", got, took, err)
+		return
+	}
+	r.set("block", ProbePass, "block %d (%s) after %s", got, m.DirectoryBlock.GetKeyMR().String()[:10], took)
+}
+
+// dbStateComplete checks the blocks of a DBState belong together
+func dbStateComplete(m *messages.DBStateMsg) error {
+	if m.AdminBlock == nil || m.FactoidBlock == nil || m.EntryCreditBlock == nil {
+		return fmt.Errorf("blocks are missing")
+	}
+	keys := map[string]interfaces.IHash{}
+	for _, e := range m.DirectoryBlock.GetDBEntries() {
+		keys[e.GetChainID().String()] = e.GetKeyMR()
+	}
+	for _, b := range []interfaces.DatabaseBatchable{m.AdminBlock, m.EntryCreditBlock, m.FactoidBlock} {
+		chain := b.GetChainID().String()
+		want, ok := keys[chain]
+		if !ok {
+			return fmt.Errorf("the directory block has no entry for chain %s", chain)
+		}
+		if !want.IsSameAs(b.DatabasePrimaryIndex()) && !want.IsSameAs(b.DatabaseSecondaryIndex()) {
+			return fmt.Errorf("the block of chain %s is not the one the directory block lists", chain)
+		}
+	}
+	return nil
+}
+
+// finish fills in the checks the probe never got to, and puts them in order
+func (r *probeRun) finish() {
+	switch {
+	case r.probe == nil:
+	case r.headerErrors > 0:
+		r.set("headers", ProbeFail, "%d parcels broke the protocol, first %s", r.headerErrors, r.firstProblem)
+	case r.done("handshake") && r.checks["handshake"].Result == ProbePass:
+		r.set("headers", ProbePass, "network %x, protocol %d", uint32(r.network), r.report.Version)
+	}
+	if r.probe != nil {
+		total := r.decoded + r.undecoded
+		switch {
+		case r.undecoded > 0:
+			r.set("messages", ProbeFail, "%d of %d messages did not decode", r.undecoded, total)
+		case total > 0:
+			r.set("messages", ProbePass, "%d messages decoded", total)
+		}
+	}
+
+	missing := map[string]string{
+		"handshake": "nothing received",
+		"ping":      "no pong",
+		"peers":     "no answer to the peer request",
+		"block":     "no answer to the block request",
+	}
+	for _, name := range probeChecks {
+		if r.done(name) {
+			r.report.Checks = append(r.report.Checks, r.checks[name])
+			continue
+		}
+		if reason, ok := missing[name]; ok && r.probe != nil {
+			r.report.Checks = append(r.report.Checks, ProbeCheck{Name: name, Result: ProbeFail, Detail: reason})
+		} else {
+			r.report.Checks = append(r.report.Checks, ProbeCheck{Name: name, Result: ProbeSkip})
+		}
+	}
+}
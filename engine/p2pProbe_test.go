@@ -0,0 +1,136 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package engine_test
+
+import (
+	"encoding/gob"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
+	. "github.com/FactomProject/factomd/engine"
+	"github.com/FactomProject/factomd/p2p"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+// fakePeer answers a probe the way a node does: pong to a ping, peers to a peer request,
+// and the DBState asked for.  It starts by sending an EOM, so the probe learns its height.
+func fakePeer(t *testing.T, network p2p.NetworkID, set *testHelper.BlockSet, badCRC bool) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		defer l.Close()
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		enc, dec := gob.NewEncoder(conn), gob.NewDecoder(conn)
+		send := func(typ p2p.ParcelCommandType, payload []byte) {
+			parcel := p2p.NewParcel(network, payload)
+			parcel.Header.Type = typ
+			parcel.Header.NodeID = 77
+			parcel.Header.PeerPort = "8110"
+			if badCRC {
+				parcel.Header.Crc32++
+			}
+			enc.Encode(parcel)
+		}
+
+		eom := new(messages.EOM)
+		eom.Timestamp = primitives.NewTimestampNow()
+		eom.ChainID = primitives.NewZeroHash()
+		eom.DBHeight = uint32(set.Height) + 1
+		data, _ := eom.MarshalBinary()
+		send(p2p.TypeMessage, data)
+
+		for {
+			var parcel p2p.Parcel
+			if err := dec.Decode(&parcel); err != nil {
+				return
+			}
+			switch parcel.Header.Type {
+			case p2p.TypePing:
+				send(p2p.TypePong, []byte("Pong"))
+			case p2p.TypePeerRequest:
+				peers, _ := json.Marshal([]p2p.Peer{{Address: "10.0.0.1", Port: "8110"}})
+				send(p2p.TypePeerResponse, peers)
+			case p2p.TypeMessage:
+				msg, err := messages.UnmarshalMessage(parcel.Payload)
+				if err != nil {
+					t.Errorf("Probe sent a bad message: %v", err)
+					continue
+				}
+				missing, ok := msg.(*messages.DBStateMissing)
+				if !ok || missing.DBHeightStart != uint32(set.Height) {
+					t.Errorf("Unexpected request %s", msg.String())
+					continue
+				}
+				dbstate := messages.NewDBStateMsg(primitives.NewTimestampNow(), set.DBlock, set.ABlock, set.FBlock, set.ECBlock,
+					[]interfaces.IEntryBlock{set.EBlock, set.AnchorEBlock}, nil, nil)
+				data, _ := dbstate.MarshalBinary()
+				send(p2p.TypeMessage, data)
+			}
+		}
+	}()
+	return l.Addr().String()
+}
+
+func TestProbePeer(t *testing.T) {
+	set := testHelper.CreateTestBlockSet(nil)
+	set = testHelper.CreateTestBlockSet(set)
+	set = testHelper.CreateTestBlockSet(set)
+
+	report, capture := ProbePeer(fakePeer(t, p2p.LocalNet, set, false), p2p.LocalNet, -1, 5*time.Second)
+	if failed := report.Failed(); len(failed) > 0 {
+		t.Errorf("Checks failed: %+v", failed)
+	}
+	if report.NodeID != 77 || report.ListenPort != "8110" || report.BlockHeight != int64(set.Height) {
+		t.Errorf("Unexpected report %+v", report)
+	}
+	results := map[string]string{}
+	for _, c := range report.Checks {
+		results[c.Name] = c.Result
+	}
+	for name, expected := range map[string]string{"connect": ProbePass, "handshake": ProbePass, "headers": ProbePass,
+		"ping": ProbePass, "peers": ProbePass, "announce": ProbeSkip, "messages": ProbePass, "block": ProbePass} {
+		if results[name] != expected {
+			t.Errorf("%s: got %q, expected %q", name, results[name], expected)
+		}
+	}
+	// Peer request, ping and block request sent; EOM, pong, peers and block received
+	if len(capture) != 7 || !capture[0].Sent {
+		t.Errorf("Captured %d parcels", len(capture))
+	}
+
+	// A peer on another network, with bad checksums, fails
+	report, _ = ProbePeer(fakePeer(t, p2p.TestNet, set, true), p2p.LocalNet, -1, 2*time.Second)
+	for _, c := range report.Checks {
+		if c.Name == "headers" && c.Result != ProbeFail {
+			t.Errorf("Bad headers passed: %+v", c)
+		}
+	}
+
+	// Nothing listening
+	l, _ := net.Listen("tcp", "127.0.0.1:0")
+	addr := l.Addr().String()
+	l.Close()
+	report, _ = ProbePeer(addr, p2p.LocalNet, 0, time.Second)
+	if len(report.Failed()) != 1 || report.Failed()[0].Name != "connect" {
+		t.Errorf("Expected only connect to fail, got %+v", report.Checks)
+	}
+}
+
+func TestP2PProbeNeedsAddress(t *testing.T) {
+	if err := P2PProbe([]string{}); err == nil {
+		t.Error("Expected an error when no address is given")
+	}
+}
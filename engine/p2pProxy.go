@@ -302,6 +302,7 @@ func (f *P2PProxy) ManageOutChannel() {
 				parcel.Header.TargetPeer = fmessage.PeerHash
 				parcel.Header.AppHash = fmessage.AppHash
 				parcel.Header.AppType = fmessage.AppType
+				p2p.StampParcel(&parcel)
 				parcel.Trace("P2PProxy.ManageOutChannel()", "b")
 				p2p.BlockFreeChannelSend(f.ToNetwork, parcel)
 			}
@@ -318,6 +319,15 @@ func (f *P2PProxy) ManageInChannel() {
 		case p2p.Parcel:
 			parcel := data.(p2p.Parcel)
 			f.trace(parcel.Header.AppHash, parcel.Header.AppType, "P2PProxy.ManageInChannel()", "M")
+			if err := messages.CheckMessageSize(parcel.Payload); err != nil {
+				// Dropped here rather than late in validation, after it has sat in holding
+				OversizedMsgs.Inc()
+				proxyLogger.WithField("peer", parcel.Header.TargetPeer).WithField("size", len(parcel.Payload)).Warn("Dropping oversized message")
+				if p2pNetwork != nil {
+					p2pNetwork.ReportPeer(parcel.Header.TargetPeer, p2p.OffenseOversized)
+				}
+				continue
+			}
 			message := FactomMessage{Message: parcel.Payload, PeerHash: parcel.Header.TargetPeer, AppHash: parcel.Header.AppHash, AppType: parcel.Header.AppType}
 			removed := p2p.BlockFreeChannelSend(f.BroadcastIn, message)
 			BroadInCastQueue.Inc()
@@ -349,6 +349,13 @@ func faultSummary() string {
 						prt = prt + fmt.Sprintf(" %x/%x:%d/%d/%d(%s)", ff.ServerID.Bytes()[2:5], ff.AuditServerID.Bytes()[2:5], len(ff.LocalVoteMap), ff.SignatureList.Length, ff.SigTally(fnode.State), pledgeDoneString)
 					}
 
+					prt = prt + fmt.Sprintf("| Elections:")
+					for i := 0; i < len(pl.FedServers); i++ {
+						if e := pl.Elections[i]; e != nil {
+							prt = prt + fmt.Sprintf(" %s", e.String())
+						}
+					}
+
 					prt = prt + fmt.Sprintf("| Watch VM: ")
 					for i := 0; i < len(pl.FedServers); i++ {
 						if pl.VMs[i].WhenFaulted > 0 {
@@ -0,0 +1,35 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/FactomProject/factomd/state"
+)
+
+// ReplayConsensus runs a consensus record made with -recordconsensus through the node, and
+// prints where the node ended up.  The node's database should be a copy of the recording
+// node's from before the record was started.
+func ReplayConsensus(s *state.State, path string) {
+	f, err := os.Open(path)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer f.Close()
+
+	fmt.Println("Replaying consensus record", path)
+	start := time.Now()
+	n, err := s.ReplayConsensus(f)
+	if err != nil {
+		fmt.Println("Replay stopped:", err)
+	}
+	fmt.Printf("Replayed %d messages in %s\n", n, time.Since(start))
+	fmt.Printf("Saved height %d, leader height %d, minute %d, %d messages in holding\n",
+		s.GetHighestSavedBlk(), s.GetLeaderHeight(), s.CurrentMinute, len(s.Holding))
+}
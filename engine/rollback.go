@@ -0,0 +1,85 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+	"github.com/FactomProject/factomd/state"
+	"github.com/FactomProject/factomd/util"
+)
+
+// Rollback implements "factomd rollback --height H".  It truncates the database of a
+// stopped node back to the given directory block height, and removes the fastboot
+// file so it is rebuilt from the truncated database on the next boot.  Rollback is
+// refused on the main network.
+func Rollback(args []string) error {
+	flags := flag.NewFlagSet("rollback", flag.ContinueOnError)
+	heightPtr := flags.Int("height", -1, "Directory block height to roll the database back to")
+	networkNamePtr := flags.String("network", "", "Network of the database to roll back: TEST, LOCAL or CUSTOM")
+	dbPtr := flags.String("db", "", "Override the Database in the Config file. Options LDB or Bolt")
+	fastLocationPtr := flags.String("fastlocation", "", "Directory the fast-boot file is kept in.")
+	factomHomePtr := flags.String("factomhome", "", "Set the factom home directory.")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *heightPtr < 0 {
+		flags.Usage()
+		return fmt.Errorf("a height to roll back to is required")
+	}
+	if *factomHomePtr != "" {
+		os.Setenv("FACTOM_HOME", *factomHomePtr)
+	}
+
+	s := new(state.State)
+	s.LoadConfig(util.GetConfigFilename("m2"), *networkNamePtr)
+	if len(*dbPtr) > 0 {
+		s.DBType = *dbPtr
+	}
+	if *fastLocationPtr != "" {
+		s.StateSaverStruct.FastBootLocation = *fastLocationPtr
+	}
+
+	if s.Network == "MAIN" {
+		return fmt.Errorf("rollback is disabled on the main network")
+	}
+
+	var err error
+	switch s.DBType {
+	case "LDB":
+		err = s.InitLevelDB()
+	case "Bolt":
+		err = s.InitBoltDB()
+	case "Badger":
+		err = s.InitBadgerDB()
+	default:
+		return fmt.Errorf("cannot roll back a %q database", s.DBType)
+	}
+	if err != nil {
+		return err
+	}
+	defer s.DB.Close()
+
+	dbo, ok := s.DB.(*databaseOverlay.Overlay)
+	if !ok {
+		return fmt.Errorf("unexpected database type %T", s.DB)
+	}
+
+	fmt.Printf("Rolling back the %s database to height %d\n", s.Network, *heightPtr)
+	err = dbo.RollbackToHeight(uint32(*heightPtr))
+	if err != nil {
+		return err
+	}
+
+	err = s.StateSaverStruct.DeleteSaveState(s.Network)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	fmt.Println("Rollback complete. The fastboot file will be regenerated on the next boot.")
+	return nil
+}
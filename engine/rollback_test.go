@@ -0,0 +1,20 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package engine_test
+
+import (
+	"testing"
+
+	. "github.com/FactomProject/factomd/engine"
+)
+
+func TestRollbackNeedsHeight(t *testing.T) {
+	if err := Rollback([]string{}); err == nil {
+		t.Error("Expected an error when no height is given")
+	}
+	if err := Rollback([]string{"--height", "notanumber"}); err == nil {
+		t.Error("Expected an error for a bad height")
+	}
+}
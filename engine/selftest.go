@@ -0,0 +1,397 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/FactomProject/factom"
+	"github.com/FactomProject/factomd/common/entryBlock"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/state"
+	"github.com/FactomProject/factomd/wsapi"
+)
+
+// "factomd selftest" is a smoke test of the binary, for operators after an upgrade and for
+// packagers in their pipelines.  It runs a network of simulated nodes in the process, on a
+// home directory of its own, and takes it through block production, an election, a
+// brainswap and entry commits.  Each step passes or fails within a timeout, and the report
+// gives how long each took.
+
+// SelftestConfig is the network the self test runs, and how long a step may take
+type SelftestConfig struct {
+	DevnetConfig
+	StepTimeout time.Duration
+	JSON        bool // Report as JSON rather than a table
+}
+
+// SelftestStep is the result of a step of the self test
+type SelftestStep struct {
+	Name     string        `json:"name"`
+	Passed   bool          `json:"passed"`
+	Duration time.Duration `json:"-"`
+	Seconds  float64       `json:"seconds"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// SelftestReport is the result of the self test
+type SelftestReport struct {
+	Passed bool           `json:"passed"`
+	Steps  []SelftestStep `json:"steps"`
+}
+
+// ParseSelftestArgs parses the arguments of "selftest"
+func ParseSelftestArgs(args []string) (*SelftestConfig, error) {
+	c := new(SelftestConfig)
+	var timeout int
+	flags := flag.NewFlagSet("selftest", flag.ContinueOnError)
+	flags.IntVar(&c.Nodes, "nodes", 4, "Nodes in the network")
+	flags.IntVar(&c.Leaders, "leaders", 3, "Nodes made leaders")
+	flags.IntVar(&c.Audits, "audits", 1, "Nodes made audit servers")
+	flags.IntVar(&c.Port, "port", 18088, "API port of the first node; node i serves on port+i")
+	flags.IntVar(&c.BlockTime, "blktime", 20, "Seconds per block")
+	flags.IntVar(&timeout, "steptimeout", 300, "Seconds a step may take before it fails")
+	flags.BoolVar(&c.JSON, "json", false, "Report as JSON")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	c.DB = "Map"
+	c.ControlPort = c.Port + c.Nodes
+	c.StepTimeout = time.Duration(timeout) * time.Second
+	if err := c.check(); err != nil {
+		return nil, err
+	}
+	switch {
+	case c.Leaders < 3:
+		return nil, fmt.Errorf("an election needs 3 leaders, to keep a majority with one faulted")
+	case c.Audits < 1:
+		return nil, fmt.Errorf("an election and a brainswap need an audit server")
+	case timeout < 1:
+		return nil, fmt.Errorf("steps need a timeout")
+	}
+	return c, nil
+}
+
+// Selftest implements "factomd selftest".  It fails if a step does.
+func Selftest(args []string) error {
+	c, err := ParseSelftestArgs(args)
+	if err != nil {
+		return err
+	}
+	home, err := ioutil.TempDir("", "factomd-selftest")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(home)
+	c.FactomHome = home
+
+	report := RunSelftest(c)
+	if c.JSON {
+		data, _ := json.MarshalIndent(report, "", "  ")
+		fmt.Println(string(data))
+	} else {
+		fmt.Printf("%-12s %-6s %10s %s\n", "Step", "Result", "Time", "Error")
+		for _, step := range report.Steps {
+			result := "pass"
+			if !step.Passed {
+				result = "FAIL"
+			}
+			fmt.Printf("%-12s %-6s %10s %s\n", step.Name, result, step.Duration.Round(time.Millisecond), step.Error)
+		}
+	}
+	if !report.Passed {
+		return fmt.Errorf("selftest failed")
+	}
+	return nil
+}
+
+// RunSelftest runs the steps of the self test in order, stopping at the first to fail
+func RunSelftest(c *SelftestConfig) SelftestReport {
+	var state0 *state.State
+	steps := []struct {
+		name string
+		run  func() error
+	}{
+		{"start", func() error {
+			state0 = Factomd(ParseCmdLine(c.FactomdArgs()), false).(*state.State)
+			if err := waitUntil(c.StepTimeout, "the first block", func() bool {
+				return len(GetFnodes()) == c.Nodes && state0.GetLLeaderHeight() >= 1
+			}); err != nil {
+				return err
+			}
+			for i, fnode := range GetFnodes() {
+				if i > 0 {
+					fnode.State.SetPort(c.Port + i)
+					wsapi.Start(fnode.State)
+				}
+			}
+			promoteNodes(state0, c.Leaders, c.Audits)
+			return checkRoles(c.Leaders, c.Audits)
+		}},
+		{"blocks", func() error { return selftestBlocks(state0, c.StepTimeout) }},
+		{"election", func() error { return selftestElection(state0, c.StepTimeout) }},
+		{"brainswap", func() error { return selftestBrainswap(state0, c.StepTimeout) }},
+		{"entries", func() error { return selftestEntries(state0, c.StepTimeout) }},
+	}
+
+	report := SelftestReport{Passed: true}
+	for _, step := range steps {
+		fmt.Fprintf(os.Stderr, "Selftest: %s\n", step.name)
+		start := time.Now()
+		err := step.run()
+		result := SelftestStep{Name: step.name, Passed: err == nil, Duration: time.Since(start)}
+		result.Seconds = result.Duration.Seconds()
+		if err != nil {
+			result.Error = err.Error()
+			report.Passed = false
+		}
+		report.Steps = append(report.Steps, result)
+		if err != nil {
+			break
+		}
+	}
+	return report
+}
+
+// waitUntil polls done until it is true, or fails after timeout
+func waitUntil(timeout time.Duration, what string, done func() bool) error {
+	deadline := time.Now().Add(timeout)
+	for !done() {
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for %s", what)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return nil
+}
+
+// allAtHeight is true once every node online has saved the block at height
+func allAtHeight(ht uint32) bool {
+	for _, fnode := range GetFnodes() {
+		if !fnode.State.GetNetStateOff() && fnode.State.GetHighestSavedBlk() < ht {
+			return false
+		}
+	}
+	return true
+}
+
+// checkRoles checks the network has as many leaders and audit servers as it should
+func checkRoles(leaders, audits int) error {
+	roles := map[string]int{}
+	for _, n := range DevnetNodes() {
+		roles[n.Role]++
+	}
+	if roles["leader"] != leaders || roles["audit"] != audits {
+		return fmt.Errorf("%d leaders and %d audit servers, expected %d and %d", roles["leader"], roles["audit"], leaders, audits)
+	}
+	return nil
+}
+
+// selftestBlocks checks the nodes make blocks, and agree on them
+func selftestBlocks(state0 *state.State, timeout time.Duration) error {
+	target := state0.GetHighestSavedBlk() + 3
+	if err := waitUntil(timeout, fmt.Sprintf("block %d on every node", target), func() bool { return allAtHeight(target) }); err != nil {
+		return err
+	}
+	return agreeOnBlocks(target-2, target)
+}
+
+// agreeOnBlocks checks every node has the same directory blocks from height from to to
+func agreeOnBlocks(from, to uint32) error {
+	for ht := from; ht <= to; ht++ {
+		var keymr interfaces.IHash
+		for _, fnode := range GetFnodes() {
+			dblock, err := fnode.State.DB.FetchDBlockByHeight(ht)
+			if err != nil || dblock == nil {
+				return fmt.Errorf("%s has no block %d - %v", fnode.State.GetFactomNodeName(), ht, err)
+			}
+			if keymr == nil {
+				keymr = dblock.GetKeyMR()
+			} else if !keymr.IsSameAs(dblock.GetKeyMR()) {
+				return fmt.Errorf("%s disagrees on block %d", fnode.State.GetFactomNodeName(), ht)
+			}
+		}
+	}
+	return nil
+}
+
+// isFederated is true if the identity is a leader of the block state0 is building
+func isFederated(state0 *state.State, id interfaces.IHash) bool {
+	pl := state0.ProcessLists.Get(state0.GetLLeaderHeight())
+	if pl == nil {
+		return false
+	}
+	for _, fed := range pl.FedServers {
+		if fed.GetChainID().IsSameAs(id) {
+			return true
+		}
+	}
+	return false
+}
+
+// nodesByRole returns the index of each node of the role but node 0, which runs the API
+func nodesByRole(role string) []int {
+	var found []int
+	for _, n := range DevnetNodes() {
+		if n.Index > 0 && n.Role == role {
+			found = append(found, n.Index)
+		}
+	}
+	return found
+}
+
+// selftestElection takes a leader off the network, and checks an audit server replaces it
+func selftestElection(state0 *state.State, timeout time.Duration) error {
+	leaders, audits := nodesByRole("leader"), nodesByRole("audit")
+	if len(leaders) == 0 || len(audits) == 0 {
+		return fmt.Errorf("no leader to fault, or audit server to replace it")
+	}
+	faulted := GetFnodes()[leaders[len(leaders)-1]].State
+	faultedID := faulted.GetIdentityChainID()
+	toggle := fmt.Sprint(leaders[len(leaders)-1])
+
+	simCommand(toggle)
+	simCommand("x")
+	simCommand("0")
+	err := waitUntil(timeout, "an audit server to replace the faulted leader", func() bool {
+		if isFederated(state0, faultedID) {
+			return false
+		}
+		for _, i := range audits {
+			if isFederated(state0, GetFnodes()[i].State.GetIdentityChainID()) {
+				return true
+			}
+		}
+		return false
+	})
+	simCommand(toggle)
+	simCommand("x")
+	simCommand("0")
+	if err != nil {
+		return err
+	}
+
+	// The faulted leader catches up, and the network goes on
+	target := state0.GetHighestSavedBlk() + 2
+	return waitUntil(timeout, fmt.Sprintf("block %d on every node after the election", target), func() bool {
+		return allAtHeight(target) && faulted.GetHighestSavedBlk() >= target
+	})
+}
+
+// selftestBrainswap swaps the identities of a leader and an audit server, and checks they
+// trade roles
+func selftestBrainswap(state0 *state.State, timeout time.Duration) error {
+	leaders, audits := nodesByRole("leader"), nodesByRole("audit")
+	if len(leaders) == 0 || len(audits) == 0 {
+		return fmt.Errorf("no leader and audit server to swap")
+	}
+	leader, audit := GetFnodes()[leaders[0]].State, GetFnodes()[audits[0]].State
+	leaderID, leaderKey := leader.GetIdentityChainID(), leader.LocalServerPrivKey
+	auditID, auditKey := audit.GetIdentityChainID(), audit.LocalServerPrivKey
+
+	at := state0.GetLLeaderHeight() + 2
+	leader.SimScheduleBrainswap(at, auditID, auditKey)
+	audit.SimScheduleBrainswap(at, leaderID, leaderKey)
+	if err := waitUntil(timeout, fmt.Sprintf("block %d on every node after the brainswap", at+1), func() bool { return allAtHeight(at + 1) }); err != nil {
+		return err
+	}
+	if !audit.GetIdentityChainID().IsSameAs(leaderID) || !leader.GetIdentityChainID().IsSameAs(auditID) {
+		return fmt.Errorf("the nodes didn't swap identities")
+	}
+	if !audit.Leader || leader.Leader {
+		return fmt.Errorf("%s should lead and %s not after the brainswap", audit.GetFactomNodeName(), leader.GetFactomNodeName())
+	}
+	return nil
+}
+
+// selftestEntries makes a chain with entries through the API of node 0, and checks every
+// node saves them
+func selftestEntries(state0 *state.State, timeout time.Duration) error {
+	if err := fundWallet(state0, 2e7); err != nil {
+		return err
+	}
+	funded := state0.GetHighestSavedBlk() + 1
+	if err := waitUntil(timeout, "the entry credits", func() bool { return state0.GetHighestSavedBlk() >= funded }); err != nil {
+		return err
+	}
+
+	sec, _ := hex.DecodeString(ecSec)
+	ec, _ := factom.MakeECAddress(sec[:32])
+	first := new(factom.Entry)
+	first.ExtIDs = [][]byte{[]byte("factomd selftest"), []byte(time.Now().String())}
+	first.Content = []byte("selftest chain")
+	chain := factom.NewChain(first)
+
+	var hashes []interfaces.IHash
+	submit := func(commitMethod, revealMethod, commit, reveal string) error {
+		if commit == "" || reveal == "" {
+			return fmt.Errorf("couldn't compose the %s", commitMethod)
+		}
+		hash, err := revealHash(reveal)
+		if err != nil {
+			return err
+		}
+		hashes = append(hashes, hash)
+		if _, err := v2Request(primitives.NewJSON2Request(commitMethod, 0, &wsapi.MessageRequest{Message: commit}), state0.GetPort()); err != nil {
+			return err
+		}
+		_, err = v2Request(primitives.NewJSON2Request(revealMethod, 0, &wsapi.EntryRequest{Entry: reveal}), state0.GetPort())
+		return err
+	}
+
+	commit, reveal := getMessageStringChain(chain, ec)
+	if err := submit("commit-chain", "reveal-chain", commit, reveal); err != nil {
+		return err
+	}
+	// Entries go in a chain that is saved already
+	if err := waitUntil(timeout, "the chain", func() bool {
+		entry, err := state0.DB.FetchEntry(hashes[0])
+		return err == nil && entry != nil
+	}); err != nil {
+		return err
+	}
+	for i := 0; i < 3; i++ {
+		e := new(factom.Entry)
+		e.ChainID = chain.ChainID
+		e.ExtIDs = [][]byte{[]byte(fmt.Sprint(i))}
+		e.Content = []byte(fmt.Sprintf("selftest entry %d", i))
+		commit, reveal := getMessageStringEntry(e, ec)
+		if err := submit("commit-entry", "reveal-entry", commit, reveal); err != nil {
+			return err
+		}
+	}
+
+	return waitUntil(timeout, fmt.Sprintf("the %d entries on every node", len(hashes)), func() bool {
+		for _, fnode := range GetFnodes() {
+			for _, hash := range hashes {
+				if entry, err := fnode.State.DB.FetchEntry(hash); err != nil || entry == nil {
+					return false
+				}
+			}
+		}
+		return true
+	})
+}
+
+// revealHash returns the hash of the entry in a reveal
+func revealHash(reveal string) (interfaces.IHash, error) {
+	data, err := hex.DecodeString(reveal)
+	if err != nil {
+		return nil, err
+	}
+	entry := entryBlock.NewEntry()
+	if _, err := entry.UnmarshalBinaryData(data); err != nil {
+		return nil, err
+	}
+	return entry.GetHash(), nil
+}
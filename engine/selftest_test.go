@@ -0,0 +1,33 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package engine_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/FactomProject/factomd/engine"
+)
+
+func TestParseSelftestArgs(t *testing.T) {
+	c, err := ParseSelftestArgs([]string{"--nodes", "5", "--audits", "2", "--steptimeout", "60"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Leaders != 3 || c.DB != "Map" || c.ControlPort != c.Port+5 || c.StepTimeout != time.Minute {
+		t.Errorf("Unexpected config %+v", c)
+	}
+
+	for _, bad := range [][]string{
+		{"--leaders", "2", "--nodes", "3"},
+		{"--audits", "0", "--nodes", "3"},
+		{"--nodes", "3"},
+		{"--steptimeout", "0"},
+	} {
+		if _, err := ParseSelftestArgs(bad); err == nil {
+			t.Errorf("Accepted %v", bad)
+		}
+	}
+}
@@ -0,0 +1,275 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/state"
+)
+
+// Fault injection for the simulator.  A fault drops or delays the messages between the
+// simulated nodes that it matches, by type, VM and the nodes sending and receiving them.
+// A fault script puts faults in place a step at a time, each for some blocks, and checks
+// the network recovers once they are taken away, so consensus regressions show up in
+// long running tests.
+
+// SimFault drops or delays the messages it matches.  Empty Types, From or To match any
+// message type or node.
+type SimFault struct {
+	Name        string
+	Types       []byte
+	VM          int   // VM of the messages that carry one, like acks and DBSigs; -1 for any
+	From        []int // Nodes sending the messages
+	To          []int // Nodes receiving them
+	DropPercent int   // Percent of the messages matched dropped
+	Delay       time.Duration
+}
+
+// DropAcks drops percent of the acks of the VM
+func DropAcks(vm int, percent int) *SimFault {
+	return &SimFault{Name: fmt.Sprintf("drop %d%% of the acks of vm %d", percent, vm), Types: []byte{constants.ACK_MSG}, VM: vm, DropPercent: percent}
+}
+
+// DelayMessages holds every message of the type for d
+func DelayMessages(msgType byte, d time.Duration) *SimFault {
+	return &SimFault{Name: fmt.Sprintf("delay %s by %s", messages.MessageName(msgType), d), Types: []byte{msgType}, VM: -1, Delay: d}
+}
+
+// Partition drops every message between the nodes of a and those of b
+func Partition(a, b []int) []*SimFault {
+	return []*SimFault{
+		{Name: fmt.Sprintf("partition %v from %v", a, b), VM: -1, From: a, To: b, DropPercent: 100},
+		{Name: fmt.Sprintf("partition %v from %v", b, a), VM: -1, From: b, To: a, DropPercent: 100},
+	}
+}
+
+func (f *SimFault) matches(from, to int, msg interfaces.IMsg) bool {
+	if len(f.Types) > 0 && !containsByte(f.Types, msg.Type()) {
+		return false
+	}
+	if f.VM >= 0 && msg.GetVMIndex() != f.VM {
+		return false
+	}
+	if len(f.From) > 0 && !containsInt(f.From, from) {
+		return false
+	}
+	return len(f.To) == 0 || containsInt(f.To, to)
+}
+
+func containsByte(list []byte, b byte) bool {
+	for _, v := range list {
+		if v == b {
+			return true
+		}
+	}
+	return false
+}
+
+func containsInt(list []int, i int) bool {
+	for _, v := range list {
+		if v == i {
+			return true
+		}
+	}
+	return false
+}
+
+// SimFaultInjector holds the faults in place
+type SimFaultInjector struct {
+	mutex  sync.Mutex
+	faults []*SimFault
+}
+
+// SimFaults are the faults injected into the messages between the simulated nodes
+var SimFaults = new(SimFaultInjector)
+
+// Add puts faults in place
+func (fi *SimFaultInjector) Add(faults ...*SimFault) {
+	fi.mutex.Lock()
+	defer fi.mutex.Unlock()
+	fi.faults = append(fi.faults, faults...)
+}
+
+// Clear takes every fault away
+func (fi *SimFaultInjector) Clear() {
+	fi.mutex.Lock()
+	defer fi.mutex.Unlock()
+	fi.faults = nil
+}
+
+// Faults returns the faults in place
+func (fi *SimFaultInjector) Faults() []*SimFault {
+	fi.mutex.Lock()
+	defer fi.mutex.Unlock()
+	return append([]*SimFault{}, fi.faults...)
+}
+
+// Apply returns whether to drop the message node from sent to node to, and if not, how
+// long to hold it.  Of the faults that match, the longest delay is taken.
+func (fi *SimFaultInjector) Apply(from, to int, msg interfaces.IMsg) (drop bool, delay time.Duration) {
+	fi.mutex.Lock()
+	defer fi.mutex.Unlock()
+	for _, f := range fi.faults {
+		if !f.matches(from, to, msg) {
+			continue
+		}
+		if f.DropPercent > 0 && rand.Intn(100) < f.DropPercent {
+			SimFaultDrops.Inc()
+			return true, 0
+		}
+		if f.Delay > delay {
+			delay = f.Delay
+		}
+	}
+	if delay > 0 {
+		SimFaultDelays.Inc()
+	}
+	return false, delay
+}
+
+// active is true if any fault is in place, so the messages of a network without faults
+// aren't looked at
+func (fi *SimFaultInjector) active() bool {
+	fi.mutex.Lock()
+	defer fi.mutex.Unlock()
+	return len(fi.faults) > 0
+}
+
+// nodeIndex returns the index of the simulated node of the name, -1 if there is none
+func nodeIndex(name string) int {
+	for _, fnode := range GetFnodes() {
+		if fnode.State.FactomNodeName == name {
+			return fnode.Index
+		}
+	}
+	return -1
+}
+
+// FaultStep puts faults in place for some blocks, then takes them away
+type FaultStep struct {
+	Name   string
+	Faults []*SimFault
+	Blocks int // Blocks the faults are in place, by the block time; the network may not make them
+}
+
+// FaultScript is a list of fault steps, run in order.  After each step every node has to
+// save RecoverBlocks more blocks, and agree on them, within Timeout.
+type FaultScript struct {
+	Steps         []FaultStep
+	RecoverBlocks int
+	Timeout       time.Duration
+}
+
+// DefaultFaultScript drops acks of VM 2, delays DBSigs, and partitions nodes 0-3 from
+// nodes 4-6, which takes a network of at least 7 nodes
+func DefaultFaultScript() *FaultScript {
+	return &FaultScript{
+		Steps: []FaultStep{
+			{Name: "drop-acks", Faults: []*SimFault{DropAcks(2, 30)}, Blocks: 2},
+			{Name: "delay-dbsigs", Faults: []*SimFault{DelayMessages(constants.DIRECTORY_BLOCK_SIGNATURE_MSG, 3*time.Second)}, Blocks: 2},
+			{Name: "partition", Faults: Partition([]int{0, 1, 2, 3}, []int{4, 5, 6}), Blocks: 2},
+		},
+		RecoverBlocks: 2,
+		Timeout:       5 * time.Minute,
+	}
+}
+
+// check says what is wrong with the script, if anything
+func (fs *FaultScript) check() error {
+	switch {
+	case len(fs.Steps) == 0:
+		return fmt.Errorf("a fault script needs a step")
+	case fs.RecoverBlocks < 1:
+		return fmt.Errorf("recovery needs at least a block")
+	case fs.Timeout <= 0:
+		return fmt.Errorf("steps need a timeout")
+	}
+	for _, step := range fs.Steps {
+		if step.Blocks < 1 {
+			return fmt.Errorf("step %s puts its faults in place for no blocks", step.Name)
+		}
+		for _, f := range step.Faults {
+			if f.DropPercent < 0 || f.DropPercent > 100 || f.Delay < 0 {
+				return fmt.Errorf("fault %s of step %s is out of range", f.Name, step.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// StartSimNetwork starts the simulated network of the config, for a fault script to run
+// on, and waits for its leaders and audit servers to be promoted
+func StartSimNetwork(c *DevnetConfig, timeout time.Duration) (*state.State, error) {
+	if err := c.check(); err != nil {
+		return nil, err
+	}
+	state0 := Factomd(ParseCmdLine(c.FactomdArgs()), false).(*state.State)
+	if err := waitUntil(timeout, "the first block", func() bool {
+		return len(GetFnodes()) == c.Nodes && state0.GetLLeaderHeight() >= 1
+	}); err != nil {
+		return nil, err
+	}
+	promoteNodes(state0, c.Leaders, c.Audits)
+	return state0, checkRoles(c.Leaders, c.Audits)
+}
+
+// RunFaultScript runs the steps of the script on the simulated network state0 leads,
+// stopping at the first the network doesn't recover from.  Each step is reported with
+// how long it and the recovery took.
+func RunFaultScript(state0 *state.State, fs *FaultScript) SelftestReport {
+	report := SelftestReport{Passed: true}
+	if err := fs.check(); err != nil {
+		report.Passed = false
+		report.Steps = append(report.Steps, SelftestStep{Name: "check", Error: err.Error()})
+		return report
+	}
+	defer SimFaults.Clear()
+
+	blockTime := time.Duration(state0.GetDirectoryBlockInSeconds()) * time.Second
+	for _, step := range fs.Steps {
+		fmt.Fprintf(os.Stderr, "Fault script: %s\n", step.Name)
+		start := time.Now()
+		SimFaults.Add(step.Faults...)
+		time.Sleep(time.Duration(step.Blocks) * blockTime)
+		SimFaults.Clear()
+
+		err := recoverFrom(fs.RecoverBlocks, fs.Timeout)
+		result := SelftestStep{Name: step.Name, Passed: err == nil, Duration: time.Since(start)}
+		result.Seconds = result.Duration.Seconds()
+		if err != nil {
+			result.Error = err.Error()
+			report.Passed = false
+		}
+		report.Steps = append(report.Steps, result)
+		if err != nil {
+			break
+		}
+	}
+	return report
+}
+
+// recoverFrom waits for every node to save blocks more blocks than the furthest along
+// has, and checks they agree on them
+func recoverFrom(blocks int, timeout time.Duration) error {
+	var highest uint32
+	for _, fnode := range GetFnodes() {
+		if ht := fnode.State.GetHighestSavedBlk(); ht > highest {
+			highest = ht
+		}
+	}
+	target := highest + uint32(blocks)
+	if err := waitUntil(timeout, fmt.Sprintf("block %d on every node", target), func() bool { return allAtHeight(target) }); err != nil {
+		return err
+	}
+	return agreeOnBlocks(highest+1, target)
+}
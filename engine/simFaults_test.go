@@ -0,0 +1,88 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package engine_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/messages"
+	. "github.com/FactomProject/factomd/engine"
+)
+
+func TestSimFaultInjector(t *testing.T) {
+	fi := new(SimFaultInjector)
+	ack := new(messages.Ack)
+	ack.VMIndex = 2
+	dbsig := new(messages.DirectoryBlockSignature)
+
+	fi.Add(DropAcks(2, 100), DelayMessages(constants.DIRECTORY_BLOCK_SIGNATURE_MSG, 3*time.Second))
+	fi.Add(Partition([]int{0, 1}, []int{2, 3})...)
+
+	if drop, _ := fi.Apply(4, 5, ack); !drop {
+		t.Error("Expected the ack of vm 2 to be dropped")
+	}
+	ack.VMIndex = 1
+	if drop, d := fi.Apply(4, 5, ack); drop || d != 0 {
+		t.Error("Expected the ack of vm 1 to go through")
+	}
+	if drop, d := fi.Apply(4, 5, dbsig); drop || d != 3*time.Second {
+		t.Errorf("Expected the DBSig to be delayed 3s, got %v", d)
+	}
+	if drop, _ := fi.Apply(3, 1, dbsig); !drop {
+		t.Error("Expected the partition to drop messages from 3 to 1")
+	}
+	if drop, _ := fi.Apply(0, 1, dbsig); drop {
+		t.Error("Expected messages within a side of the partition to go through")
+	}
+
+	fi.Clear()
+	if len(fi.Faults()) != 0 {
+		t.Error("Expected no faults once cleared")
+	}
+	if drop, d := fi.Apply(3, 1, dbsig); drop || d != 0 {
+		t.Error("Expected no faults once cleared")
+	}
+}
+
+func TestFaultScriptCheck(t *testing.T) {
+	for _, bad := range []*FaultScript{
+		{RecoverBlocks: 1, Timeout: time.Minute},
+		{Steps: []FaultStep{{Name: "none"}}, RecoverBlocks: 1, Timeout: time.Minute},
+		{Steps: []FaultStep{{Name: "over", Faults: []*SimFault{DropAcks(0, 101)}, Blocks: 1}}, RecoverBlocks: 1, Timeout: time.Minute},
+		{Steps: []FaultStep{{Name: "norecovery", Blocks: 1}}, Timeout: time.Minute},
+	} {
+		if report := RunFaultScript(nil, bad); report.Passed {
+			t.Errorf("Ran %+v", bad)
+		}
+	}
+}
+
+// TestFaultScript runs the default fault script on a network of 7 nodes, which takes some
+// minutes.  It needs a process of its own: go test ./engine -run TestFaultScript$
+func TestFaultScript(t *testing.T) {
+	if testing.Short() {
+		t.Skip("long test")
+	}
+	if len(GetFnodes()) > 0 {
+		t.Skip("a network is running already")
+	}
+
+	c := &DevnetConfig{Nodes: 7, Leaders: 5, Audits: 2, Port: 38088, BlockTime: 20, DB: "Map"}
+	c.ControlPort = c.Port + c.Nodes
+	state0, err := StartSimNetwork(c, 5*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report := RunFaultScript(state0, DefaultFaultScript())
+	for _, step := range report.Steps {
+		t.Logf("%-12s %-5v %s %s", step.Name, step.Passed, step.Duration.Round(time.Second), step.Error)
+	}
+	if !report.Passed {
+		t.Fatal("The network didn't recover from the faults")
+	}
+}
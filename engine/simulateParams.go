@@ -0,0 +1,413 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"bufio"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/FactomProject/factomd/common/entryCreditBlock"
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+	"github.com/FactomProject/factomd/state"
+	"github.com/FactomProject/factomd/util"
+)
+
+// Changing the block time, the fault timeout or the queue sizes of the network is a
+// governance decision, and it is better made with numbers.  simulate-params takes the
+// traffic of a journal, or of the last blocks in a database, and plays it through a
+// model of a node under the current and the proposed parameters.  The model is simple
+// on purpose: traffic arrives at the rate it did in history, a node processes messages
+// at a fixed rate, the lateness of the network carries over unchanged, and a minute
+// running later than the fault timeout costs an election.  The reports are projections
+// to compare, not predictions.
+
+// SimMemoryOverhead is how much more memory a message takes in a node than marshaled
+const SimMemoryOverhead = 3
+
+// Rough marshaled size of an ack, which the blocks don't keep
+const ackSize = 200
+
+// SimMinute is the traffic of one minute of history
+type SimMinute struct {
+	Messages int
+	Bytes    int
+	Length   time.Duration // How long the minute took, 0 if unknown
+}
+
+// SimTraffic is the history a simulation replays
+type SimTraffic struct {
+	MinuteLength time.Duration // Of the network the history was recorded on
+	Minutes      []SimMinute
+}
+
+// SimParams are the parameters a simulation plays the traffic under
+type SimParams struct {
+	BlockTime    time.Duration
+	FaultTimeout time.Duration
+	InMsgQueue   int     // Messages waiting to be processed, past which they are dropped
+	Throughput   float64 // Messages a node processes a second
+}
+
+// SimReport is what a simulation projects
+type SimReport struct {
+	Params          SimParams
+	Minutes         int
+	Messages        int
+	MeanLatency     time.Duration // From a message arriving to its minute closing
+	P99Latency      time.Duration
+	PeakQueue       int
+	Dropped         int
+	Elections       int
+	ElectionsPerDay float64
+	PeakMemory      int64 // Bytes held in process lists and the queue
+}
+
+// Simulate plays the traffic through a node with the given parameters.  Traffic keeps
+// the rate it had, so a shorter minute has fewer messages.  A minute ends late by as
+// much as it did in history, and by the backlog left in the queue; each fault timeout
+// it is late by costs an election.  A node holds the messages of the last two blocks,
+// and those waiting in the queue.
+func Simulate(traffic SimTraffic, p SimParams) SimReport {
+	report := SimReport{Params: p, Minutes: len(traffic.Minutes)}
+	if traffic.MinuteLength <= 0 || p.BlockTime <= 0 || p.Throughput <= 0 || len(traffic.Minutes) == 0 {
+		return report
+	}
+	oldMinute := traffic.MinuteLength.Seconds()
+	minute := p.BlockTime.Seconds() / 10
+	scale := minute / oldMinute
+
+	type sample struct{ latency, weight float64 }
+	var samples []sample
+	var queue, total float64
+	var window []float64 // Bytes of the minutes held in the process lists
+	var held float64
+
+	for _, m := range traffic.Minutes {
+		arrivals := float64(m.Messages) * scale
+		bytes := float64(m.Bytes) * scale
+		perMessage := 0.0
+		if m.Messages > 0 {
+			perMessage = float64(m.Bytes) / float64(m.Messages)
+		}
+		late := 0.0
+		if m.Length > traffic.MinuteLength {
+			late = (m.Length - traffic.MinuteLength).Seconds()
+		}
+
+		start := queue
+		queue = math.Max(0, queue+arrivals-p.Throughput*minute)
+		if p.InMsgQueue > 0 && queue > float64(p.InMsgQueue) {
+			report.Dropped += int(queue - float64(p.InMsgQueue))
+			queue = float64(p.InMsgQueue)
+		}
+		if int(queue) > report.PeakQueue {
+			report.PeakQueue = int(queue)
+		}
+
+		if arrivals > 0 {
+			latency := minute/2 + late + (start+queue)/2/p.Throughput
+			samples = append(samples, sample{latency, arrivals})
+			total += arrivals
+		}
+		if p.FaultTimeout > 0 {
+			report.Elections += int((late + queue/p.Throughput) / p.FaultTimeout.Seconds())
+		}
+
+		window = append(window, bytes)
+		held += bytes
+		if len(window) > 20 {
+			held -= window[0]
+			window = window[1:]
+		}
+		memory := int64((held + queue*perMessage) * SimMemoryOverhead)
+		if memory > report.PeakMemory {
+			report.PeakMemory = memory
+		}
+	}
+
+	report.Messages = int(total)
+	report.ElectionsPerDay = float64(report.Elections) / (minute * float64(len(traffic.Minutes))) * 86400
+	if total > 0 {
+		sort.Slice(samples, func(i, j int) bool { return samples[i].latency < samples[j].latency })
+		var sum, seen float64
+		p99 := -1.0
+		for _, s := range samples {
+			sum += s.latency * s.weight
+			seen += s.weight
+			if p99 < 0 && seen >= total*0.99 {
+				p99 = s.latency
+			}
+		}
+		report.MeanLatency = time.Duration(sum / total * float64(time.Second))
+		report.P99Latency = time.Duration(p99 * float64(time.Second))
+	}
+	return report
+}
+
+// SimulateParams implements "factomd simulate-params".  It reads the traffic of a
+// journal, or of the last blocks of a node's database, and reports the minute latency,
+// elections and memory projected under the current parameters and the proposed ones.
+func SimulateParams(args []string) error {
+	flags := flag.NewFlagSet("simulate-params", flag.ContinueOnError)
+	journalPtr := flags.String("journal", "", "Journal to replay, instead of the blocks in the database")
+	blocksPtr := flags.Int("blocks", 1000, "Number of the last directory blocks in the database to replay")
+	blkTimePtr := flags.Int("blktime", 0, "Proposed seconds per block; the configured block time if 0")
+	faultTimeoutPtr := flags.Int("faulttimeout", 0, "Proposed seconds before a leader is faulted; the current if 0")
+	currentFaultTimeoutPtr := flags.Int("currentfaulttimeout", 60, "Seconds before a leader is faulted now")
+	inMsgQueuePtr := flags.Int("inmsgqueue", 0, "Proposed size of the queue of messages from peers; the current if 0")
+	resourcesPtr := flags.String("resources", "", "Resource profile the current queue sizes are taken from; the configured one if empty")
+	throughputPtr := flags.Float64("throughput", 500, "Messages a node processes a second")
+	networkNamePtr := flags.String("network", "", "Network of the database or journal: MAIN, TEST, LOCAL or CUSTOM")
+	dbPtr := flags.String("db", "", "Override the Database in the Config file. Options LDB or Bolt")
+	factomHomePtr := flags.String("factomhome", "", "Set the factom home directory.")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *factomHomePtr != "" {
+		os.Setenv("FACTOM_HOME", *factomHomePtr)
+	}
+
+	s := new(state.State)
+	s.LoadConfig(util.GetConfigFilename("m2"), *networkNamePtr)
+	if len(*dbPtr) > 0 {
+		s.DBType = *dbPtr
+	}
+	resources := s.Resources
+	if *resourcesPtr != "" || resources.Name == "" {
+		var err error
+		resources, err = state.GetResourceProfile(*resourcesPtr)
+		if err != nil {
+			return err
+		}
+	}
+
+	current := SimParams{
+		BlockTime:    time.Duration(s.DirectoryBlockInSeconds) * time.Second,
+		FaultTimeout: time.Duration(*currentFaultTimeoutPtr) * time.Second,
+		InMsgQueue:   resources.InMsgQueue,
+		Throughput:   *throughputPtr,
+	}
+	proposed := current
+	if *blkTimePtr > 0 {
+		proposed.BlockTime = time.Duration(*blkTimePtr) * time.Second
+	}
+	if *faultTimeoutPtr > 0 {
+		proposed.FaultTimeout = time.Duration(*faultTimeoutPtr) * time.Second
+	}
+	if *inMsgQueuePtr > 0 {
+		proposed.InMsgQueue = *inMsgQueuePtr
+	}
+
+	var traffic SimTraffic
+	var source string
+	var err error
+	if *journalPtr != "" {
+		f, err := os.Open(*journalPtr)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		traffic, err = journalTraffic(bufio.NewReaderSize(f, 4*1024), current.BlockTime/10)
+		if err != nil {
+			return err
+		}
+		source = "journal " + *journalPtr
+	} else {
+		switch s.DBType {
+		case "LDB":
+			err = s.InitLevelDB()
+		case "Bolt":
+			err = s.InitBoltDB()
+		case "Badger":
+			err = s.InitBadgerDB()
+		default:
+			return fmt.Errorf("cannot read a %q database", s.DBType)
+		}
+		if err != nil {
+			return err
+		}
+		defer s.DB.Close()
+
+		dbo, ok := s.DB.(*databaseOverlay.Overlay)
+		if !ok {
+			return fmt.Errorf("unexpected database type %T", s.DB)
+		}
+		traffic, err = blockTraffic(dbo, *blocksPtr, current.BlockTime/10)
+		if err != nil {
+			return err
+		}
+		source = fmt.Sprintf("last %d blocks of the %s database", len(traffic.Minutes)/10, s.Network)
+	}
+	if len(traffic.Minutes) == 0 {
+		return fmt.Errorf("no traffic found to replay")
+	}
+
+	fmt.Printf("Replaying %d minutes of traffic from the %s\n\n", len(traffic.Minutes), source)
+	printSimReports(os.Stdout, Simulate(traffic, current), Simulate(traffic, proposed))
+	return nil
+}
+
+func printSimReports(w io.Writer, current, proposed SimReport) {
+	row := func(name string, a, b interface{}) {
+		fmt.Fprintf(w, "%-22s %20v %20v\n", name, a, b)
+	}
+	row("", "current", "proposed")
+	row("Block time", current.Params.BlockTime, proposed.Params.BlockTime)
+	row("Fault timeout", current.Params.FaultTimeout, proposed.Params.FaultTimeout)
+	row("In message queue", current.Params.InMsgQueue, proposed.Params.InMsgQueue)
+	row("Throughput (msg/s)", current.Params.Throughput, proposed.Params.Throughput)
+	fmt.Fprintln(w)
+	row("Messages", current.Messages, proposed.Messages)
+	row("Mean minute latency", current.MeanLatency.Round(time.Millisecond), proposed.MeanLatency.Round(time.Millisecond))
+	row("99% minute latency", current.P99Latency.Round(time.Millisecond), proposed.P99Latency.Round(time.Millisecond))
+	row("Peak queue", current.PeakQueue, proposed.PeakQueue)
+	row("Messages dropped", current.Dropped, proposed.Dropped)
+	row("Elections", current.Elections, proposed.Elections)
+	row("Elections a day", fmt.Sprintf("%.2f", current.ElectionsPerDay), fmt.Sprintf("%.2f", proposed.ElectionsPerDay))
+	row("Peak memory (MB)", fmt.Sprintf("%.1f", float64(current.PeakMemory)/(1<<20)), fmt.Sprintf("%.1f", float64(proposed.PeakMemory)/(1<<20)))
+}
+
+// journalTraffic reads the traffic of a journal.  The first EOM of each minute ends it,
+// and the time between them is how long the minute took.
+func journalTraffic(r *bufio.Reader, minuteLength time.Duration) (SimTraffic, error) {
+	traffic := SimTraffic{MinuteLength: minuteLength}
+	var current SimMinute
+	var last int64 // When the last minute ended, in milliseconds
+	lastMinute := -1
+	for {
+		line, err := r.ReadBytes('\n')
+		if len(line) == 0 {
+			if err == io.EOF {
+				break
+			}
+			return traffic, err
+		}
+
+		adv, word, _ := bufio.ScanWords(line, true)
+		if string(word) != "MsgHex:" {
+			continue
+		}
+		_, data, err := bufio.ScanWords(line[adv:], true)
+		if err != nil {
+			return traffic, err
+		}
+		binary, err := hex.DecodeString(string(data))
+		if err != nil {
+			return traffic, err
+		}
+		msg, err := messages.UnmarshalMessage(binary)
+		if err != nil {
+			return traffic, err
+		}
+
+		current.Messages++
+		current.Bytes += len(binary)
+		eom, ok := msg.(*messages.EOM)
+		if !ok || int(eom.Minute) == lastMinute {
+			continue
+		}
+		now := msg.GetTimestamp().GetTimeMilli()
+		if last != 0 {
+			current.Length = time.Duration(now-last) * time.Millisecond
+		}
+		traffic.Minutes = append(traffic.Minutes, current)
+		current = SimMinute{}
+		last = now
+		lastMinute = int(eom.Minute)
+	}
+	return traffic, nil
+}
+
+// blockTraffic reads the traffic of the last blocks of a database.  Each commit came with
+// its reveal and the acks of both, and each transaction with its ack.  Reveals are taken
+// to be as large as they paid for.  Directory block timestamps are to the minute, so the
+// lateness of a block is spread over its minutes.
+func blockTraffic(dbo *databaseOverlay.Overlay, blocks int, minuteLength time.Duration) (SimTraffic, error) {
+	traffic := SimTraffic{MinuteLength: minuteLength}
+	head, err := dbo.FetchDBlockHead()
+	if err != nil || head == nil {
+		return traffic, fmt.Errorf("no directory blocks in the database: %v", err)
+	}
+	top := int(head.GetDatabaseHeight())
+	from := top - blocks + 1
+	if from < 1 {
+		from = 1
+	}
+
+	prev, err := dbo.FetchDBlockByHeight(uint32(from - 1))
+	if err != nil || prev == nil {
+		return traffic, fmt.Errorf("cannot read directory block %d: %v", from-1, err)
+	}
+	for height := from; height <= top; height++ {
+		dblock, err := dbo.FetchDBlockByHeight(uint32(height))
+		if err != nil || dblock == nil {
+			return traffic, fmt.Errorf("cannot read directory block %d: %v", height, err)
+		}
+		minutes := make([]SimMinute, 10)
+
+		ecblock, err := dbo.FetchECBlockByHeight(uint32(height))
+		if err != nil {
+			return traffic, err
+		}
+		if ecblock != nil {
+			minute := 0
+			for _, entry := range ecblock.GetEntries() {
+				credits := 0
+				switch e := entry.(type) {
+				case *entryCreditBlock.MinuteNumber:
+					if int(e.Number) < 10 {
+						minute = int(e.Number)
+					}
+					continue
+				case *entryCreditBlock.CommitChain:
+					credits = int(e.Credits) - 10
+				case *entryCreditBlock.CommitEntry:
+					credits = int(e.Credits)
+				default:
+					continue
+				}
+				size, _ := entry.MarshalBinary()
+				minutes[minute].Messages += 4
+				minutes[minute].Bytes += len(size) + credits*1024 + 2*ackSize
+			}
+		}
+
+		fblock, err := dbo.FetchFBlockByHeight(uint32(height))
+		if err != nil {
+			return traffic, err
+		}
+		if fblock != nil {
+			ends := fblock.GetEndOfPeriod()
+			minute := 0
+			for i, tx := range fblock.GetTransactions() {
+				if i == 0 {
+					continue // The coinbase
+				}
+				for minute < 9 && ends[minute] > 0 && i >= ends[minute] {
+					minute++
+				}
+				size, _ := tx.MarshalBinary()
+				minutes[minute].Messages += 2
+				minutes[minute].Bytes += len(size) + ackSize
+			}
+		}
+
+		took := time.Duration(dblock.GetHeader().GetTimestamp().GetTimeMilli()-prev.GetHeader().GetTimestamp().GetTimeMilli()) * time.Millisecond
+		for i := range minutes {
+			minutes[i].Length = took / 10
+		}
+		traffic.Minutes = append(traffic.Minutes, minutes...)
+		prev = dblock
+	}
+	return traffic, nil
+}
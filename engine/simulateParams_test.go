@@ -0,0 +1,86 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package engine_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/FactomProject/factomd/engine"
+)
+
+func simTraffic(minutes int, messages int, length time.Duration) SimTraffic {
+	t := SimTraffic{MinuteLength: time.Minute}
+	for i := 0; i < minutes; i++ {
+		t.Minutes = append(t.Minutes, SimMinute{Messages: messages, Bytes: messages * 100, Length: length})
+	}
+	return t
+}
+
+func TestSimulateQuietNetwork(t *testing.T) {
+	params := SimParams{BlockTime: 600 * time.Second, FaultTimeout: time.Minute, InMsgQueue: 1000, Throughput: 100}
+	r := Simulate(simTraffic(100, 600, time.Minute), params)
+	if r.Messages != 60000 || r.Dropped != 0 || r.PeakQueue != 0 || r.Elections != 0 {
+		t.Errorf("Unexpected report %+v", r)
+	}
+	if r.MeanLatency != 30*time.Second || r.P99Latency != 30*time.Second {
+		t.Errorf("Expected a latency of half a minute, got %v and %v", r.MeanLatency, r.P99Latency)
+	}
+	// Two blocks of messages are held
+	if r.PeakMemory != 20*600*100*SimMemoryOverhead {
+		t.Errorf("Unexpected memory %d", r.PeakMemory)
+	}
+
+	// A shorter block has the same rate of traffic, in shorter minutes
+	params.BlockTime = 300 * time.Second
+	r = Simulate(simTraffic(100, 600, time.Minute), params)
+	if r.Messages != 30000 || r.MeanLatency != 15*time.Second || r.PeakMemory != 20*300*100*SimMemoryOverhead {
+		t.Errorf("Unexpected report for the shorter block %+v", r)
+	}
+}
+
+func TestSimulateBacklog(t *testing.T) {
+	// Twice the traffic the node can process backs up the queue until it overflows, and
+	// the backlog makes minutes late enough to cost elections.
+	params := SimParams{BlockTime: 600 * time.Second, FaultTimeout: time.Minute, InMsgQueue: 10000, Throughput: 100}
+	r := Simulate(simTraffic(10, 12000, time.Minute), params)
+	if r.PeakQueue != 10000 {
+		t.Errorf("Expected the queue to fill, peaked at %d", r.PeakQueue)
+	}
+	if r.Dropped != 10*6000-10000 {
+		t.Errorf("Expected %d dropped, got %d", 10*6000-10000, r.Dropped)
+	}
+	if r.Elections == 0 || r.ElectionsPerDay <= 0 {
+		t.Errorf("Expected elections, got %+v", r)
+	}
+
+	// A longer fault timeout rides out the backlog
+	params.FaultTimeout = 200 * time.Second
+	if r := Simulate(simTraffic(10, 12000, time.Minute), params); r.Elections != 0 {
+		t.Errorf("Expected no elections, got %d", r.Elections)
+	}
+}
+
+func TestSimulateLateMinutes(t *testing.T) {
+	// Minutes that ran 90s late in history run as late under any parameters
+	params := SimParams{BlockTime: 600 * time.Second, FaultTimeout: time.Minute, InMsgQueue: 1000, Throughput: 100}
+	r := Simulate(simTraffic(10, 60, 150*time.Second), params)
+	if r.Elections != 10 {
+		t.Errorf("Expected an election a minute, got %d", r.Elections)
+	}
+	if r.MeanLatency != 120*time.Second {
+		t.Errorf("Expected a latency of 120s, got %v", r.MeanLatency)
+	}
+	params.FaultTimeout = 2 * time.Minute
+	if r := Simulate(simTraffic(10, 60, 150*time.Second), params); r.Elections != 0 {
+		t.Errorf("Expected no elections, got %d", r.Elections)
+	}
+}
+
+func TestSimulateParamsBadFlags(t *testing.T) {
+	if err := SimulateParams([]string{"--blktime", "notanumber"}); err == nil {
+		t.Error("Expected an error for a bad block time")
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/FactomProject/factomd/state"
+)
+
+// ExportSnapshot loads the database, then writes a snapshot of the state at the highest
+// saved block to the file
+func ExportSnapshot(s *state.State, path string) error {
+	start := time.Now()
+	s.LoadDatabaseOffline()
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	height, err := s.ExportSnapshot(f)
+	if err != nil {
+		f.Close()
+		os.Remove(path)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Printf("Exported the snapshot at height %d to %s in %s\n", height, path, time.Since(start))
+	return nil
+}
+
+// ImportSnapshot bootstraps the node's empty database from a snapshot file, before the
+// database is loaded
+func ImportSnapshot(s *state.State, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	height, err := s.ImportSnapshot(f)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Imported the snapshot at height %d from %s\n", height, path)
+	return nil
+}
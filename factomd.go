@@ -13,6 +13,53 @@ import (
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "rollback" {
+		if err := engine.Rollback(os.Args[2:]); err != nil {
+			fmt.Println("Rollback failed:", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "p2p-probe" {
+		if err := engine.P2PProbe(os.Args[2:]); err != nil {
+			fmt.Println("Probe failed:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "devnet" {
+		if err := engine.Devnet(os.Args[2:]); err != nil {
+			fmt.Println("Devnet failed:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "simulate-params" {
+		if err := engine.SimulateParams(os.Args[2:]); err != nil {
+			fmt.Println("Simulation failed:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "selftest" {
+		if err := engine.Selftest(os.Args[2:]); err != nil {
+			fmt.Println("Selftest failed:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "capacity" {
+		if err := engine.Capacity(os.Args[2:]); err != nil {
+			fmt.Println("Capacity report failed:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// uncomment StartProfiler() to run the pprof tool (for testing)
 	params := engine.ParseCmdLine(os.Args[1:])
 	sim_Stdin := params.Sim_Stdin
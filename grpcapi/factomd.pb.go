@@ -0,0 +1,780 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: factomd.proto
+
+/*
+Package grpcapi is a generated protocol buffer package.
+
+It is generated from these files:
+
+	factomd.proto
+
+It has these top-level messages:
+
+	CommitRequest
+	CommitResponse
+	RevealRequest
+	RevealResponse
+	ChainHeadRequest
+	ChainHeadResponse
+	BalanceRequest
+	BalanceResponse
+	DirectoryBlockRequest
+	DirectoryBlocksRequest
+	EntryBlockAddress
+	DirectoryBlock
+*/
+package grpcapi
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+import (
+	context "golang.org/x/net/context"
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+// A signed chain or entry commit
+type CommitRequest struct {
+	// The commit, as marshalled
+	Commit []byte `protobuf:"bytes,1,opt,name=commit" json:"commit,omitempty"`
+	// Retries with the same key get the first answer
+	IdempotencyKey string `protobuf:"bytes,2,opt,name=idempotency_key,json=idempotencyKey" json:"idempotency_key,omitempty"`
+}
+
+func (m *CommitRequest) Reset()                    { *m = CommitRequest{} }
+func (m *CommitRequest) String() string            { return proto.CompactTextString(m) }
+func (*CommitRequest) ProtoMessage()               {}
+func (*CommitRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
+func (m *CommitRequest) GetCommit() []byte {
+	if m != nil {
+		return m.Commit
+	}
+	return nil
+}
+
+func (m *CommitRequest) GetIdempotencyKey() string {
+	if m != nil {
+		return m.IdempotencyKey
+	}
+	return ""
+}
+
+type CommitResponse struct {
+	TxId      []byte `protobuf:"bytes,1,opt,name=tx_id,json=txId" json:"tx_id,omitempty"`
+	EntryHash []byte `protobuf:"bytes,2,opt,name=entry_hash,json=entryHash" json:"entry_hash,omitempty"`
+	// Only set for chain commits
+	ChainIdHash []byte `protobuf:"bytes,3,opt,name=chain_id_hash,json=chainIdHash" json:"chain_id_hash,omitempty"`
+}
+
+func (m *CommitResponse) Reset()                    { *m = CommitResponse{} }
+func (m *CommitResponse) String() string            { return proto.CompactTextString(m) }
+func (*CommitResponse) ProtoMessage()               {}
+func (*CommitResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
+func (m *CommitResponse) GetTxId() []byte {
+	if m != nil {
+		return m.TxId
+	}
+	return nil
+}
+
+func (m *CommitResponse) GetEntryHash() []byte {
+	if m != nil {
+		return m.EntryHash
+	}
+	return nil
+}
+
+func (m *CommitResponse) GetChainIdHash() []byte {
+	if m != nil {
+		return m.ChainIdHash
+	}
+	return nil
+}
+
+type RevealRequest struct {
+	// The entry, as marshalled
+	Entry []byte `protobuf:"bytes,1,opt,name=entry" json:"entry,omitempty"`
+}
+
+func (m *RevealRequest) Reset()                    { *m = RevealRequest{} }
+func (m *RevealRequest) String() string            { return proto.CompactTextString(m) }
+func (*RevealRequest) ProtoMessage()               {}
+func (*RevealRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
+func (m *RevealRequest) GetEntry() []byte {
+	if m != nil {
+		return m.Entry
+	}
+	return nil
+}
+
+type RevealResponse struct {
+	EntryHash []byte `protobuf:"bytes,1,opt,name=entry_hash,json=entryHash" json:"entry_hash,omitempty"`
+	ChainId   []byte `protobuf:"bytes,2,opt,name=chain_id,json=chainId" json:"chain_id,omitempty"`
+}
+
+func (m *RevealResponse) Reset()                    { *m = RevealResponse{} }
+func (m *RevealResponse) String() string            { return proto.CompactTextString(m) }
+func (*RevealResponse) ProtoMessage()               {}
+func (*RevealResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+
+func (m *RevealResponse) GetEntryHash() []byte {
+	if m != nil {
+		return m.EntryHash
+	}
+	return nil
+}
+
+func (m *RevealResponse) GetChainId() []byte {
+	if m != nil {
+		return m.ChainId
+	}
+	return nil
+}
+
+type ChainHeadRequest struct {
+	ChainId []byte `protobuf:"bytes,1,opt,name=chain_id,json=chainId" json:"chain_id,omitempty"`
+}
+
+func (m *ChainHeadRequest) Reset()                    { *m = ChainHeadRequest{} }
+func (m *ChainHeadRequest) String() string            { return proto.CompactTextString(m) }
+func (*ChainHeadRequest) ProtoMessage()               {}
+func (*ChainHeadRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+
+func (m *ChainHeadRequest) GetChainId() []byte {
+	if m != nil {
+		return m.ChainId
+	}
+	return nil
+}
+
+type ChainHeadResponse struct {
+	// KeyMR of the last saved entry block, empty if none is saved yet
+	ChainHead []byte `protobuf:"bytes,1,opt,name=chain_head,json=chainHead" json:"chain_head,omitempty"`
+	// The chain has entries in the current block
+	ChainInProcessList bool `protobuf:"varint,2,opt,name=chain_in_process_list,json=chainInProcessList" json:"chain_in_process_list,omitempty"`
+}
+
+func (m *ChainHeadResponse) Reset()                    { *m = ChainHeadResponse{} }
+func (m *ChainHeadResponse) String() string            { return proto.CompactTextString(m) }
+func (*ChainHeadResponse) ProtoMessage()               {}
+func (*ChainHeadResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+
+func (m *ChainHeadResponse) GetChainHead() []byte {
+	if m != nil {
+		return m.ChainHead
+	}
+	return nil
+}
+
+func (m *ChainHeadResponse) GetChainInProcessList() bool {
+	if m != nil {
+		return m.ChainInProcessList
+	}
+	return false
+}
+
+type BalanceRequest struct {
+	// A human readable address, or the hex of its public key
+	Address string `protobuf:"bytes,1,opt,name=address" json:"address,omitempty"`
+}
+
+func (m *BalanceRequest) Reset()                    { *m = BalanceRequest{} }
+func (m *BalanceRequest) String() string            { return proto.CompactTextString(m) }
+func (*BalanceRequest) ProtoMessage()               {}
+func (*BalanceRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+
+func (m *BalanceRequest) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+type BalanceResponse struct {
+	// Factoshis, or entry credits
+	Balance int64 `protobuf:"varint,1,opt,name=balance" json:"balance,omitempty"`
+}
+
+func (m *BalanceResponse) Reset()                    { *m = BalanceResponse{} }
+func (m *BalanceResponse) String() string            { return proto.CompactTextString(m) }
+func (*BalanceResponse) ProtoMessage()               {}
+func (*BalanceResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+
+func (m *BalanceResponse) GetBalance() int64 {
+	if m != nil {
+		return m.Balance
+	}
+	return 0
+}
+
+// A directory block by KeyMR, or by height when no KeyMR is given
+type DirectoryBlockRequest struct {
+	KeyMr  []byte `protobuf:"bytes,1,opt,name=key_mr,json=keyMr" json:"key_mr,omitempty"`
+	Height uint32 `protobuf:"varint,2,opt,name=height" json:"height,omitempty"`
+}
+
+func (m *DirectoryBlockRequest) Reset()                    { *m = DirectoryBlockRequest{} }
+func (m *DirectoryBlockRequest) String() string            { return proto.CompactTextString(m) }
+func (*DirectoryBlockRequest) ProtoMessage()               {}
+func (*DirectoryBlockRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+
+func (m *DirectoryBlockRequest) GetKeyMr() []byte {
+	if m != nil {
+		return m.KeyMr
+	}
+	return nil
+}
+
+func (m *DirectoryBlockRequest) GetHeight() uint32 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+type DirectoryBlocksRequest struct {
+	StartHeight uint32 `protobuf:"varint,1,opt,name=start_height,json=startHeight" json:"start_height,omitempty"`
+	// Keep sending blocks as they are saved, rather than stop at the highest
+	Follow bool `protobuf:"varint,2,opt,name=follow" json:"follow,omitempty"`
+}
+
+func (m *DirectoryBlocksRequest) Reset()                    { *m = DirectoryBlocksRequest{} }
+func (m *DirectoryBlocksRequest) String() string            { return proto.CompactTextString(m) }
+func (*DirectoryBlocksRequest) ProtoMessage()               {}
+func (*DirectoryBlocksRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+
+func (m *DirectoryBlocksRequest) GetStartHeight() uint32 {
+	if m != nil {
+		return m.StartHeight
+	}
+	return 0
+}
+
+func (m *DirectoryBlocksRequest) GetFollow() bool {
+	if m != nil {
+		return m.Follow
+	}
+	return false
+}
+
+type EntryBlockAddress struct {
+	ChainId []byte `protobuf:"bytes,1,opt,name=chain_id,json=chainId" json:"chain_id,omitempty"`
+	KeyMr   []byte `protobuf:"bytes,2,opt,name=key_mr,json=keyMr" json:"key_mr,omitempty"`
+}
+
+func (m *EntryBlockAddress) Reset()                    { *m = EntryBlockAddress{} }
+func (m *EntryBlockAddress) String() string            { return proto.CompactTextString(m) }
+func (*EntryBlockAddress) ProtoMessage()               {}
+func (*EntryBlockAddress) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+
+func (m *EntryBlockAddress) GetChainId() []byte {
+	if m != nil {
+		return m.ChainId
+	}
+	return nil
+}
+
+func (m *EntryBlockAddress) GetKeyMr() []byte {
+	if m != nil {
+		return m.KeyMr
+	}
+	return nil
+}
+
+type DirectoryBlock struct {
+	Height    uint32 `protobuf:"varint,1,opt,name=height" json:"height,omitempty"`
+	KeyMr     []byte `protobuf:"bytes,2,opt,name=key_mr,json=keyMr" json:"key_mr,omitempty"`
+	PrevKeyMr []byte `protobuf:"bytes,3,opt,name=prev_key_mr,json=prevKeyMr" json:"prev_key_mr,omitempty"`
+	// Unix seconds
+	Timestamp   int64                `protobuf:"varint,4,opt,name=timestamp" json:"timestamp,omitempty"`
+	EntryBlocks []*EntryBlockAddress `protobuf:"bytes,5,rep,name=entry_blocks,json=entryBlocks" json:"entry_blocks,omitempty"`
+	// The block, as marshalled
+	Raw []byte `protobuf:"bytes,6,opt,name=raw" json:"raw,omitempty"`
+}
+
+func (m *DirectoryBlock) Reset()                    { *m = DirectoryBlock{} }
+func (m *DirectoryBlock) String() string            { return proto.CompactTextString(m) }
+func (*DirectoryBlock) ProtoMessage()               {}
+func (*DirectoryBlock) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+
+func (m *DirectoryBlock) GetHeight() uint32 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *DirectoryBlock) GetKeyMr() []byte {
+	if m != nil {
+		return m.KeyMr
+	}
+	return nil
+}
+
+func (m *DirectoryBlock) GetPrevKeyMr() []byte {
+	if m != nil {
+		return m.PrevKeyMr
+	}
+	return nil
+}
+
+func (m *DirectoryBlock) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *DirectoryBlock) GetEntryBlocks() []*EntryBlockAddress {
+	if m != nil {
+		return m.EntryBlocks
+	}
+	return nil
+}
+
+func (m *DirectoryBlock) GetRaw() []byte {
+	if m != nil {
+		return m.Raw
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*CommitRequest)(nil), "factomd.CommitRequest")
+	proto.RegisterType((*CommitResponse)(nil), "factomd.CommitResponse")
+	proto.RegisterType((*RevealRequest)(nil), "factomd.RevealRequest")
+	proto.RegisterType((*RevealResponse)(nil), "factomd.RevealResponse")
+	proto.RegisterType((*ChainHeadRequest)(nil), "factomd.ChainHeadRequest")
+	proto.RegisterType((*ChainHeadResponse)(nil), "factomd.ChainHeadResponse")
+	proto.RegisterType((*BalanceRequest)(nil), "factomd.BalanceRequest")
+	proto.RegisterType((*BalanceResponse)(nil), "factomd.BalanceResponse")
+	proto.RegisterType((*DirectoryBlockRequest)(nil), "factomd.DirectoryBlockRequest")
+	proto.RegisterType((*DirectoryBlocksRequest)(nil), "factomd.DirectoryBlocksRequest")
+	proto.RegisterType((*EntryBlockAddress)(nil), "factomd.EntryBlockAddress")
+	proto.RegisterType((*DirectoryBlock)(nil), "factomd.DirectoryBlock")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// Client API for Factomd service
+
+type FactomdClient interface {
+	CommitChain(ctx context.Context, in *CommitRequest, opts ...grpc.CallOption) (*CommitResponse, error)
+	RevealChain(ctx context.Context, in *RevealRequest, opts ...grpc.CallOption) (*RevealResponse, error)
+	CommitEntry(ctx context.Context, in *CommitRequest, opts ...grpc.CallOption) (*CommitResponse, error)
+	RevealEntry(ctx context.Context, in *RevealRequest, opts ...grpc.CallOption) (*RevealResponse, error)
+	ChainHead(ctx context.Context, in *ChainHeadRequest, opts ...grpc.CallOption) (*ChainHeadResponse, error)
+	FactoidBalance(ctx context.Context, in *BalanceRequest, opts ...grpc.CallOption) (*BalanceResponse, error)
+	EntryCreditBalance(ctx context.Context, in *BalanceRequest, opts ...grpc.CallOption) (*BalanceResponse, error)
+	DirectoryBlock(ctx context.Context, in *DirectoryBlockRequest, opts ...grpc.CallOption) (*DirectoryBlock, error)
+	DirectoryBlocks(ctx context.Context, in *DirectoryBlocksRequest, opts ...grpc.CallOption) (Factomd_DirectoryBlocksClient, error)
+}
+
+type factomdClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewFactomdClient(cc *grpc.ClientConn) FactomdClient {
+	return &factomdClient{cc}
+}
+
+func (c *factomdClient) CommitChain(ctx context.Context, in *CommitRequest, opts ...grpc.CallOption) (*CommitResponse, error) {
+	out := new(CommitResponse)
+	err := grpc.Invoke(ctx, "/factomd.Factomd/CommitChain", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *factomdClient) RevealChain(ctx context.Context, in *RevealRequest, opts ...grpc.CallOption) (*RevealResponse, error) {
+	out := new(RevealResponse)
+	err := grpc.Invoke(ctx, "/factomd.Factomd/RevealChain", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *factomdClient) CommitEntry(ctx context.Context, in *CommitRequest, opts ...grpc.CallOption) (*CommitResponse, error) {
+	out := new(CommitResponse)
+	err := grpc.Invoke(ctx, "/factomd.Factomd/CommitEntry", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *factomdClient) RevealEntry(ctx context.Context, in *RevealRequest, opts ...grpc.CallOption) (*RevealResponse, error) {
+	out := new(RevealResponse)
+	err := grpc.Invoke(ctx, "/factomd.Factomd/RevealEntry", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *factomdClient) ChainHead(ctx context.Context, in *ChainHeadRequest, opts ...grpc.CallOption) (*ChainHeadResponse, error) {
+	out := new(ChainHeadResponse)
+	err := grpc.Invoke(ctx, "/factomd.Factomd/ChainHead", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *factomdClient) FactoidBalance(ctx context.Context, in *BalanceRequest, opts ...grpc.CallOption) (*BalanceResponse, error) {
+	out := new(BalanceResponse)
+	err := grpc.Invoke(ctx, "/factomd.Factomd/FactoidBalance", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *factomdClient) EntryCreditBalance(ctx context.Context, in *BalanceRequest, opts ...grpc.CallOption) (*BalanceResponse, error) {
+	out := new(BalanceResponse)
+	err := grpc.Invoke(ctx, "/factomd.Factomd/EntryCreditBalance", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *factomdClient) DirectoryBlock(ctx context.Context, in *DirectoryBlockRequest, opts ...grpc.CallOption) (*DirectoryBlock, error) {
+	out := new(DirectoryBlock)
+	err := grpc.Invoke(ctx, "/factomd.Factomd/DirectoryBlock", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *factomdClient) DirectoryBlocks(ctx context.Context, in *DirectoryBlocksRequest, opts ...grpc.CallOption) (Factomd_DirectoryBlocksClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Factomd_serviceDesc.Streams[0], c.cc, "/factomd.Factomd/DirectoryBlocks", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &factomdDirectoryBlocksClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Factomd_DirectoryBlocksClient interface {
+	Recv() (*DirectoryBlock, error)
+	grpc.ClientStream
+}
+
+type factomdDirectoryBlocksClient struct {
+	grpc.ClientStream
+}
+
+func (x *factomdDirectoryBlocksClient) Recv() (*DirectoryBlock, error) {
+	m := new(DirectoryBlock)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Server API for Factomd service
+
+type FactomdServer interface {
+	CommitChain(context.Context, *CommitRequest) (*CommitResponse, error)
+	RevealChain(context.Context, *RevealRequest) (*RevealResponse, error)
+	CommitEntry(context.Context, *CommitRequest) (*CommitResponse, error)
+	RevealEntry(context.Context, *RevealRequest) (*RevealResponse, error)
+	ChainHead(context.Context, *ChainHeadRequest) (*ChainHeadResponse, error)
+	FactoidBalance(context.Context, *BalanceRequest) (*BalanceResponse, error)
+	EntryCreditBalance(context.Context, *BalanceRequest) (*BalanceResponse, error)
+	DirectoryBlock(context.Context, *DirectoryBlockRequest) (*DirectoryBlock, error)
+	DirectoryBlocks(*DirectoryBlocksRequest, Factomd_DirectoryBlocksServer) error
+}
+
+func RegisterFactomdServer(s *grpc.Server, srv FactomdServer) {
+	s.RegisterService(&_Factomd_serviceDesc, srv)
+}
+
+func _Factomd_CommitChain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CommitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FactomdServer).CommitChain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/factomd.Factomd/CommitChain",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FactomdServer).CommitChain(ctx, req.(*CommitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Factomd_RevealChain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevealRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FactomdServer).RevealChain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/factomd.Factomd/RevealChain",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FactomdServer).RevealChain(ctx, req.(*RevealRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Factomd_CommitEntry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CommitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FactomdServer).CommitEntry(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/factomd.Factomd/CommitEntry",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FactomdServer).CommitEntry(ctx, req.(*CommitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Factomd_RevealEntry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevealRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FactomdServer).RevealEntry(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/factomd.Factomd/RevealEntry",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FactomdServer).RevealEntry(ctx, req.(*RevealRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Factomd_ChainHead_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChainHeadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FactomdServer).ChainHead(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/factomd.Factomd/ChainHead",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FactomdServer).ChainHead(ctx, req.(*ChainHeadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Factomd_FactoidBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BalanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FactomdServer).FactoidBalance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/factomd.Factomd/FactoidBalance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FactomdServer).FactoidBalance(ctx, req.(*BalanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Factomd_EntryCreditBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BalanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FactomdServer).EntryCreditBalance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/factomd.Factomd/EntryCreditBalance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FactomdServer).EntryCreditBalance(ctx, req.(*BalanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Factomd_DirectoryBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DirectoryBlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FactomdServer).DirectoryBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/factomd.Factomd/DirectoryBlock",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FactomdServer).DirectoryBlock(ctx, req.(*DirectoryBlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Factomd_DirectoryBlocks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DirectoryBlocksRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FactomdServer).DirectoryBlocks(m, &factomdDirectoryBlocksServer{stream})
+}
+
+type Factomd_DirectoryBlocksServer interface {
+	Send(*DirectoryBlock) error
+	grpc.ServerStream
+}
+
+type factomdDirectoryBlocksServer struct {
+	grpc.ServerStream
+}
+
+func (x *factomdDirectoryBlocksServer) Send(m *DirectoryBlock) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _Factomd_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "factomd.Factomd",
+	HandlerType: (*FactomdServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CommitChain",
+			Handler:    _Factomd_CommitChain_Handler,
+		},
+		{
+			MethodName: "RevealChain",
+			Handler:    _Factomd_RevealChain_Handler,
+		},
+		{
+			MethodName: "CommitEntry",
+			Handler:    _Factomd_CommitEntry_Handler,
+		},
+		{
+			MethodName: "RevealEntry",
+			Handler:    _Factomd_RevealEntry_Handler,
+		},
+		{
+			MethodName: "ChainHead",
+			Handler:    _Factomd_ChainHead_Handler,
+		},
+		{
+			MethodName: "FactoidBalance",
+			Handler:    _Factomd_FactoidBalance_Handler,
+		},
+		{
+			MethodName: "EntryCreditBalance",
+			Handler:    _Factomd_EntryCreditBalance_Handler,
+		},
+		{
+			MethodName: "DirectoryBlock",
+			Handler:    _Factomd_DirectoryBlock_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "DirectoryBlocks",
+			Handler:       _Factomd_DirectoryBlocks_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "factomd.proto",
+}
+
+func init() { proto.RegisterFile("factomd.proto", fileDescriptor0) }
+
+var fileDescriptor0 = []byte{
+	// 637 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa5, 0x55, 0xdb, 0x6e, 0xd3, 0x40,
+	0x10, 0x95, 0x9b, 0x5b, 0x3d, 0xce, 0xa5, 0x5d, 0x48, 0x70, 0x23, 0x28, 0xc5, 0x12, 0xa2, 0x02,
+	0x51, 0x41, 0x79, 0x06, 0x89, 0x84, 0x96, 0x84, 0x52, 0xa9, 0x32, 0x6f, 0xbc, 0x58, 0x8e, 0xbd,
+	0xad, 0xad, 0xfa, 0x86, 0xbd, 0xb4, 0xcd, 0x4f, 0xf2, 0x37, 0xbc, 0xb3, 0xde, 0x8b, 0x63, 0x3b,
+	0x2d, 0x20, 0xf5, 0xcd, 0x73, 0xf6, 0xcc, 0x39, 0x33, 0xbb, 0x33, 0x32, 0xf4, 0xce, 0x6d, 0x87,
+	0xc4, 0xa1, 0x7b, 0x90, 0xa4, 0x31, 0x89, 0x51, 0x47, 0x84, 0xc6, 0x19, 0xf4, 0xa6, 0x71, 0x18,
+	0xfa, 0xc4, 0xc4, 0x3f, 0x7e, 0xe2, 0x8c, 0xa0, 0x11, 0xb4, 0x1d, 0x06, 0xe8, 0xca, 0x9e, 0xb2,
+	0xdf, 0x35, 0x45, 0x84, 0x5e, 0xc0, 0xc0, 0x77, 0x71, 0x98, 0xc4, 0x04, 0x47, 0xce, 0xd2, 0xba,
+	0xc4, 0x4b, 0x7d, 0x83, 0x12, 0x54, 0xb3, 0x5f, 0x82, 0x4f, 0xf0, 0xd2, 0xf0, 0xa0, 0x2f, 0x15,
+	0xb3, 0x24, 0x8e, 0x32, 0x8c, 0x1e, 0x40, 0x8b, 0xdc, 0x58, 0xbe, 0x2b, 0x14, 0x9b, 0xe4, 0x66,
+	0xee, 0xa2, 0x27, 0x00, 0x38, 0x22, 0xe9, 0xd2, 0xf2, 0xec, 0xcc, 0x63, 0x52, 0x5d, 0x53, 0x65,
+	0xc8, 0x8c, 0x02, 0xc8, 0x80, 0x9e, 0xe3, 0xd9, 0x7e, 0x44, 0xd3, 0x38, 0xa3, 0xc1, 0x18, 0x1a,
+	0x03, 0xe7, 0x6e, 0xce, 0x31, 0x9e, 0x43, 0xcf, 0xc4, 0x57, 0xd8, 0x0e, 0x64, 0xed, 0x0f, 0xa1,
+	0xc5, 0x14, 0x84, 0x11, 0x0f, 0x8c, 0x2f, 0xd0, 0x97, 0x34, 0x51, 0x50, 0xd5, 0x5b, 0xa9, 0x7b,
+	0xef, 0xc0, 0xa6, 0xf4, 0x16, 0x85, 0x75, 0x84, 0xad, 0xf1, 0x1a, 0xb6, 0xa6, 0xf9, 0xe7, 0x0c,
+	0xdb, 0xae, 0x74, 0x2d, 0xd3, 0x95, 0x2a, 0x1d, 0xc3, 0x76, 0x89, 0xbe, 0x72, 0xe7, 0x7c, 0x8f,
+	0xa2, 0xd2, 0xdd, 0x91, 0x34, 0xf4, 0x16, 0x86, 0x42, 0x2e, 0xb2, 0xe8, 0x63, 0x39, 0x38, 0xcb,
+	0xac, 0xc0, 0xcf, 0x08, 0x2b, 0x65, 0xd3, 0x44, 0x5c, 0x3b, 0x3a, 0xe3, 0x47, 0x5f, 0xe9, 0x89,
+	0xf1, 0x12, 0xfa, 0x13, 0x3b, 0xb0, 0x23, 0x07, 0xcb, 0x9a, 0x74, 0xe8, 0xd8, 0xae, 0x9b, 0x52,
+	0x02, 0x33, 0x50, 0x4d, 0x19, 0x1a, 0xaf, 0x60, 0x50, 0x70, 0x45, 0x41, 0x94, 0xbc, 0xe0, 0x10,
+	0x23, 0x37, 0x4c, 0x19, 0x1a, 0xc7, 0x30, 0xfc, 0xe4, 0xa7, 0x98, 0x8e, 0x4a, 0xba, 0x9c, 0x04,
+	0xb1, 0x73, 0x29, 0xf5, 0x87, 0xd0, 0xa6, 0x13, 0x60, 0x85, 0xa9, 0xbc, 0x6a, 0x1a, 0x9d, 0xa6,
+	0xf9, 0xf0, 0x78, 0xd8, 0xbf, 0xf0, 0x78, 0xb1, 0x3d, 0x53, 0x44, 0xc6, 0x37, 0x18, 0x55, 0x75,
+	0x32, 0x29, 0xf4, 0x0c, 0xba, 0x19, 0xb1, 0x53, 0x62, 0x89, 0x3c, 0x85, 0xe5, 0x69, 0x0c, 0x9b,
+	0x31, 0x28, 0x17, 0x3d, 0x8f, 0x83, 0x20, 0xbe, 0x16, 0x37, 0x20, 0x22, 0xe3, 0x08, 0xb6, 0x8f,
+	0xf2, 0x37, 0x63, 0x82, 0x1f, 0x79, 0x7b, 0x7f, 0x79, 0x8c, 0x52, 0xcd, 0x1b, 0xa5, 0x9a, 0x8d,
+	0x5f, 0x0a, 0xf4, 0xab, 0xc5, 0x95, 0xda, 0x50, 0xca, 0x6d, 0xdc, 0xa1, 0x80, 0x76, 0x41, 0x4b,
+	0x52, 0x7c, 0x65, 0x89, 0x33, 0x3e, 0xa9, 0x6a, 0x0e, 0x9d, 0xb0, 0xf3, 0xc7, 0xa0, 0x12, 0x3f,
+	0xa4, 0xdd, 0xda, 0x61, 0xa2, 0x37, 0xd9, 0x0d, 0xaf, 0x00, 0xf4, 0x1e, 0xba, 0x7c, 0x18, 0x17,
+	0xec, 0x62, 0xf4, 0xd6, 0x5e, 0x63, 0x5f, 0x3b, 0x1c, 0x1f, 0xc8, 0x85, 0x5d, 0xeb, 0xd1, 0xd4,
+	0x70, 0x01, 0x65, 0x68, 0x0b, 0x1a, 0xa9, 0x7d, 0xad, 0xb7, 0x99, 0x69, 0xfe, 0x79, 0xf8, 0xbb,
+	0x09, 0x9d, 0x63, 0x9e, 0x8c, 0x3e, 0x80, 0xc6, 0x97, 0x91, 0x8d, 0x21, 0x1a, 0x15, 0xaa, 0x95,
+	0xa5, 0x1f, 0x3f, 0x5a, 0xc3, 0xc5, 0x68, 0xd0, 0x7c, 0xbe, 0x3b, 0xf5, 0xfc, 0xca, 0xe2, 0x95,
+	0xf2, 0x6b, 0x9b, 0x56, 0xf8, 0xb3, 0x2e, 0xee, 0xe1, 0x5f, 0xcf, 0xff, 0x4f, 0xff, 0x09, 0xa8,
+	0xc5, 0x02, 0xa2, 0x9d, 0x95, 0x4b, 0x6d, 0x87, 0xc7, 0xe3, 0xdb, 0x8e, 0x84, 0xc6, 0x14, 0xfa,
+	0xec, 0x3a, 0x7d, 0x57, 0x2c, 0x0e, 0x5a, 0xd9, 0x55, 0xd7, 0x6e, 0xac, 0xaf, 0x1f, 0x08, 0x91,
+	0xcf, 0x80, 0x58, 0x0b, 0xd3, 0x14, 0xbb, 0x3e, 0xb9, 0x87, 0xd0, 0x7c, 0x6d, 0x5a, 0x77, 0x0b,
+	0xee, 0xad, 0xbb, 0x5a, 0xba, 0x9c, 0x5a, 0xe2, 0x29, 0x0c, 0x6a, 0x5b, 0x89, 0x9e, 0xde, 0xc1,
+	0xcd, 0xfe, 0x25, 0xf6, 0x46, 0x99, 0xa8, 0xdf, 0x3b, 0x17, 0x69, 0xe2, 0xd8, 0x89, 0xbf, 0x68,
+	0xb3, 0xbf, 0xcc, 0xbb, 0x3f, 0x4f, 0x4e, 0x06, 0x70, 0x76, 0x06, 0x00, 0x00,
+}
@@ -0,0 +1,295 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+// Package grpcapi serves the entry, chain, balance and directory block calls of the V2
+// API over gRPC, for clients that want typed messages and streams rather than JSON-RPC.
+// The calls go through the same handlers as the V2 API, so they are validated, queued
+// and counted the same way.  factomd.pb.go is generated from factomd.proto with
+//
+//	protoc --go_out=plugins=grpc:. factomd.proto
+package grpcapi
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/wsapi"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var grpcLogger = log.WithFields(log.Fields{"package": "grpcapi"})
+
+// How often a DirectoryBlocks stream that follows the chain looks for a new block
+var FollowInterval = time.Second
+
+// Server implements FactomdServer over the node's state
+type Server struct {
+	state interfaces.IState
+}
+
+var _ FactomdServer = (*Server)(nil)
+
+func NewServer(state interfaces.IState) *Server {
+	s := new(Server)
+	s.state = state
+	return s
+}
+
+// Start serves the gRPC API on the port, with the RPC user, password and TLS certificate
+// of the JSON-RPC API.  It returns once the server stops.
+func Start(state interfaces.IState, port int) {
+	RegisterPrometheus()
+
+	opts := []grpc.ServerOption{
+		grpc.UnaryInterceptor(unaryInterceptor(state)),
+		grpc.StreamInterceptor(streamInterceptor(state)),
+	}
+	if tlsIsEnabled, tlsPrivate, tlsPublic := state.GetTlsInfo(); tlsIsEnabled {
+		creds, err := credentials.NewServerTLSFromFile(tlsPublic, tlsPrivate)
+		if err != nil {
+			grpcLogger.Errorf("Not starting the gRPC API, cannot load the TLS certificate: %v", err)
+			return
+		}
+		opts = append(opts, grpc.Creds(creds))
+	}
+
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		grpcLogger.Errorf("Not starting the gRPC API: %v", err)
+		return
+	}
+	server := grpc.NewServer(opts...)
+	RegisterFactomdServer(server, NewServer(state))
+	grpcLogger.Infof("Starting the gRPC API on port %d", port)
+	if err := server.Serve(listener); err != nil {
+		grpcLogger.Errorf("gRPC API stopped: %v", err)
+	}
+}
+
+// authorize checks the "authorization" metadata against the RPC user and password, as
+// the JSON-RPC API checks the Authorization header
+func authorize(state interfaces.IState, ctx context.Context) error {
+	if state.GetRpcUser() == "" {
+		return nil
+	}
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok || len(md["authorization"]) == 0 {
+		return status.Error(codes.Unauthenticated, "no auth")
+	}
+	auth := "Basic " + base64.StdEncoding.EncodeToString([]byte(state.GetRpcUser()+":"+state.GetRpcPass()))
+	expected := sha256.Sum256([]byte(auth))
+	presented := sha256.Sum256([]byte(md["authorization"][0]))
+	if subtle.ConstantTimeCompare(presented[:], expected[:]) != 1 {
+		return status.Error(codes.Unauthenticated, "bad auth")
+	}
+	return nil
+}
+
+func unaryInterceptor(state interfaces.IState) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := authorize(state, ctx); err != nil {
+			countCall(info.FullMethod, err)
+			return nil, err
+		}
+		resp, err := handler(ctx, req)
+		countCall(info.FullMethod, err)
+		return resp, err
+	}
+}
+
+func streamInterceptor(state interfaces.IState) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := authorize(state, ss.Context()); err != nil {
+			countCall(info.FullMethod, err)
+			return err
+		}
+		err := handler(srv, ss)
+		countCall(info.FullMethod, err)
+		return err
+	}
+}
+
+func countCall(method string, err error) {
+	code := codes.OK
+	if s, ok := status.FromError(err); ok {
+		code = s.Code()
+	}
+	GrpcCallsVec.WithLabelValues(method, code.String()).Inc()
+}
+
+// statusError turns a V2 API error into the gRPC status closest to it
+func statusError(err *primitives.JSONError) error {
+	code := codes.Internal
+	switch err.Code {
+	case -32700, -32600, -32602:
+		code = codes.InvalidArgument
+	case -32601:
+		code = codes.Unimplemented
+	case -32008, -32009:
+		code = codes.NotFound
+	case -32011:
+		code = codes.AlreadyExists
+	}
+	if data, ok := err.Data.(string); ok && data != "" {
+		return status.Errorf(code, "%s: %s", err.Message, data)
+	}
+	return status.Error(code, err.Message)
+}
+
+// hexBytes decodes a hash from a V2 API response, which are always well formed
+func hexBytes(s string) []byte {
+	b, _ := hex.DecodeString(s)
+	return b
+}
+
+func (s *Server) commit(handle func(interfaces.IState, interface{}) (interface{}, *primitives.JSONError), req *CommitRequest) (*CommitResponse, error) {
+	params := map[string]interface{}{
+		"message":        hex.EncodeToString(req.Commit),
+		"idempotencykey": req.IdempotencyKey,
+	}
+	resp, jsonError := handle(s.state, params)
+	if jsonError != nil {
+		return nil, statusError(jsonError)
+	}
+	switch r := resp.(type) {
+	case *wsapi.CommitChainResponse:
+		return &CommitResponse{TxId: hexBytes(r.TxID), EntryHash: hexBytes(r.EntryHash), ChainIdHash: hexBytes(r.ChainIDHash)}, nil
+	case *wsapi.CommitEntryResponse:
+		return &CommitResponse{TxId: hexBytes(r.TxID), EntryHash: hexBytes(r.EntryHash)}, nil
+	}
+	return nil, status.Errorf(codes.Internal, "unexpected response %T", resp)
+}
+
+func (s *Server) reveal(req *RevealRequest) (*RevealResponse, error) {
+	resp, jsonError := wsapi.HandleV2RevealEntry(s.state, map[string]interface{}{"entry": hex.EncodeToString(req.Entry)})
+	if jsonError != nil {
+		return nil, statusError(jsonError)
+	}
+	r := resp.(*wsapi.RevealEntryResponse)
+	return &RevealResponse{EntryHash: hexBytes(r.EntryHash), ChainId: hexBytes(r.ChainID)}, nil
+}
+
+func (s *Server) CommitChain(ctx context.Context, req *CommitRequest) (*CommitResponse, error) {
+	return s.commit(wsapi.HandleV2CommitChain, req)
+}
+
+func (s *Server) RevealChain(ctx context.Context, req *RevealRequest) (*RevealResponse, error) {
+	return s.reveal(req)
+}
+
+func (s *Server) CommitEntry(ctx context.Context, req *CommitRequest) (*CommitResponse, error) {
+	return s.commit(wsapi.HandleV2CommitEntry, req)
+}
+
+func (s *Server) RevealEntry(ctx context.Context, req *RevealRequest) (*RevealResponse, error) {
+	return s.reveal(req)
+}
+
+func (s *Server) ChainHead(ctx context.Context, req *ChainHeadRequest) (*ChainHeadResponse, error) {
+	resp, jsonError := wsapi.HandleV2ChainHead(s.state, map[string]interface{}{"chainid": hex.EncodeToString(req.ChainId)})
+	if jsonError != nil {
+		return nil, statusError(jsonError)
+	}
+	r := resp.(*wsapi.ChainHeadResponse)
+	return &ChainHeadResponse{ChainHead: hexBytes(r.ChainHead), ChainInProcessList: r.ChainInProcessList}, nil
+}
+
+func (s *Server) FactoidBalance(ctx context.Context, req *BalanceRequest) (*BalanceResponse, error) {
+	resp, jsonError := wsapi.HandleV2FactoidBalance(s.state, map[string]interface{}{"address": req.Address})
+	if jsonError != nil {
+		return nil, statusError(jsonError)
+	}
+	return &BalanceResponse{Balance: resp.(*wsapi.FactoidBalanceResponse).Balance}, nil
+}
+
+func (s *Server) EntryCreditBalance(ctx context.Context, req *BalanceRequest) (*BalanceResponse, error) {
+	resp, jsonError := wsapi.HandleV2EntryCreditBalance(s.state, map[string]interface{}{"address": req.Address})
+	if jsonError != nil {
+		return nil, statusError(jsonError)
+	}
+	return &BalanceResponse{Balance: resp.(*wsapi.EntryCreditBalanceResponse).Balance}, nil
+}
+
+func (s *Server) DirectoryBlock(ctx context.Context, req *DirectoryBlockRequest) (*DirectoryBlock, error) {
+	var block interfaces.IDirectoryBlock
+	var err error
+	dbase := s.state.GetAndLockDB()
+	if len(req.KeyMr) > 0 {
+		var keymr interfaces.IHash
+		keymr, err = primitives.NewShaHash(req.KeyMr)
+		if err != nil {
+			s.state.UnlockDB()
+			return nil, statusError(wsapi.NewInvalidHashError())
+		}
+		block, err = dbase.FetchDBlock(keymr)
+	} else {
+		block, err = dbase.FetchDBlockByHeight(req.Height)
+	}
+	s.state.UnlockDB()
+	if err != nil {
+		return nil, statusError(wsapi.NewInternalDatabaseError())
+	}
+	if block == nil {
+		return nil, statusError(wsapi.NewBlockNotFoundError())
+	}
+	return directoryBlock(block)
+}
+
+// DirectoryBlocks sends the saved directory blocks from the start height, then, if asked
+// to follow, each block as it is saved until the client goes away
+func (s *Server) DirectoryBlocks(req *DirectoryBlocksRequest, stream Factomd_DirectoryBlocksServer) error {
+	ctx := stream.Context()
+	for height := req.StartHeight; ; height++ {
+		for height > s.state.GetHighestSavedBlk() {
+			if !req.Follow {
+				return nil
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(FollowInterval):
+			}
+		}
+		block, err := s.DirectoryBlock(ctx, &DirectoryBlockRequest{Height: height})
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(block); err != nil {
+			return err
+		}
+	}
+}
+
+func directoryBlock(block interfaces.IDirectoryBlock) (*DirectoryBlock, error) {
+	raw, err := block.MarshalBinary()
+	if err != nil {
+		return nil, statusError(wsapi.NewInternalError())
+	}
+	header := block.GetHeader()
+	d := &DirectoryBlock{
+		Height:    header.GetDBHeight(),
+		KeyMr:     block.GetKeyMR().Bytes(),
+		PrevKeyMr: header.GetPrevKeyMR().Bytes(),
+		Timestamp: header.GetTimestamp().GetTimeSeconds(),
+		Raw:       raw,
+	}
+	for _, e := range block.GetDBEntries() {
+		d.EntryBlocks = append(d.EntryBlocks, &EntryBlockAddress{ChainId: e.GetChainID().Bytes(), KeyMr: e.GetKeyMR().Bytes()})
+	}
+	return d, nil
+}
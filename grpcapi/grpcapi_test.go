@@ -0,0 +1,125 @@
+package grpcapi_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/primitives"
+	. "github.com/FactomProject/factomd/grpcapi"
+	"github.com/FactomProject/factomd/testHelper"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// blockStream collects what a DirectoryBlocks call sends
+type blockStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	blocks []*DirectoryBlock
+}
+
+func (b *blockStream) Context() context.Context { return b.ctx }
+
+func (b *blockStream) Send(d *DirectoryBlock) error {
+	b.blocks = append(b.blocks, d)
+	return nil
+}
+
+func TestDirectoryBlock(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestState()
+	s := NewServer(state)
+
+	byHeight, err := s.DirectoryBlock(context.Background(), &DirectoryBlockRequest{Height: 2})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if byHeight.Height != 2 || len(byHeight.EntryBlocks) == 0 || len(byHeight.Raw) == 0 {
+		t.Errorf("Wrong block - %v", byHeight)
+	}
+
+	byKeyMR, err := s.DirectoryBlock(context.Background(), &DirectoryBlockRequest{KeyMr: byHeight.KeyMr})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if !bytes.Equal(byKeyMR.Raw, byHeight.Raw) || byKeyMR.Height != 2 {
+		t.Errorf("Fetched by keymr %x, got height %v", byHeight.KeyMr, byKeyMR.Height)
+	}
+
+	prev, err := s.DirectoryBlock(context.Background(), &DirectoryBlockRequest{Height: 1})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if !bytes.Equal(prev.KeyMr, byHeight.PrevKeyMr) {
+		t.Errorf("Prev keymr %x, expected %x", byHeight.PrevKeyMr, prev.KeyMr)
+	}
+
+	_, err = s.DirectoryBlock(context.Background(), &DirectoryBlockRequest{Height: 1000})
+	if st, _ := status.FromError(err); st.Code() != codes.NotFound {
+		t.Errorf("Expected NotFound for a missing block, got %v", err)
+	}
+	_, err = s.DirectoryBlock(context.Background(), &DirectoryBlockRequest{KeyMr: []byte{1, 2, 3}})
+	if st, _ := status.FromError(err); st.Code() != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for a short keymr, got %v", err)
+	}
+}
+
+func TestDirectoryBlocks(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestState()
+	s := NewServer(state)
+
+	stream := &blockStream{ctx: context.Background()}
+	if err := s.DirectoryBlocks(&DirectoryBlocksRequest{StartHeight: 1}, stream); err != nil {
+		t.Fatalf("%v", err)
+	}
+	highest := state.GetHighestSavedBlk()
+	if uint32(len(stream.blocks)) != highest {
+		t.Fatalf("Got %v blocks, expected %v", len(stream.blocks), highest)
+	}
+	for i, d := range stream.blocks {
+		if d.Height != uint32(i+1) {
+			t.Errorf("Block %v has height %v", i, d.Height)
+		}
+	}
+
+	// A stream following the chain stops when the client goes away
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	stream = &blockStream{ctx: ctx}
+	if err := s.DirectoryBlocks(&DirectoryBlocksRequest{StartHeight: highest, Follow: true}, stream); err == nil {
+		t.Errorf("Expected the canceled stream to end with an error")
+	}
+	if len(stream.blocks) != 1 {
+		t.Errorf("Got %v blocks, expected only the highest", len(stream.blocks))
+	}
+}
+
+func TestChainHeadAndBalances(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestState()
+	s := NewServer(state)
+
+	head, err := s.ChainHead(context.Background(), &ChainHeadRequest{ChainId: primitives.NewHash(constants.ADMIN_CHAINID).Bytes()})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if len(head.ChainHead) != constants.HASH_LENGTH {
+		t.Errorf("Bad chain head %x", head.ChainHead)
+	}
+
+	if _, err := s.FactoidBalance(context.Background(), &BalanceRequest{Address: "FA-not-an-address"}); err == nil {
+		t.Errorf("Expected an error for a bad address")
+	} else if st, _ := status.FromError(err); st.Code() != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument, got %v", err)
+	}
+
+	balance, err := s.EntryCreditBalance(context.Background(), &BalanceRequest{Address: testHelper.NewECAddressString(0)})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if balance.Balance < 0 {
+		t.Errorf("Bad balance %v", balance.Balance)
+	}
+}
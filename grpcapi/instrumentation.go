@@ -0,0 +1,25 @@
+package grpcapi
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	GrpcCallsVec = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "factomd_grpcapi_calls_vec",
+		Help: "Counts gRPC API calls by method and status code",
+	}, []string{"method", "code"})
+)
+
+var registered = false
+
+// RegisterPrometheus registers the variables to be exposed. This can only be run once, hence the
+// boolean flag to prevent panics if launched more than once. This is called in Start
+func RegisterPrometheus() {
+	if registered {
+		return
+	}
+	registered = true
+
+	prometheus.MustRegister(GrpcCallsVec)
+}
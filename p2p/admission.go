@@ -0,0 +1,141 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package p2p
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"time"
+)
+
+// Admission control for application messages from regular peers.  Requests that are
+// costly to answer (ie missing message and dbstate requests) must carry a proof of work
+// stamp in their header, and are limited to a rate per peer; other messages are limited
+// to their own rate.  Special peers are never limited.  All of it is off by default.
+var (
+	AdmissionDifficulty  uint8   = 0 // Leading zero bits of the stamp hash required on costly requests, 0 for no stamp
+	AdmissionRequestRate float64 = 0 // Costly requests a second accepted from each regular peer, 0 for no limit
+	AdmissionMessageRate float64 = 0 // Other application messages a second accepted from each regular peer, 0 for no limit
+
+	// Application message types (the AppType of the parcel header) that are costly to answer
+	CostlyAppTypes = map[string]bool{}
+)
+
+// stampDigest is what a stamp's work is done over, so a stamp is only good for one payload
+func stampDigest(payload []byte, stamp uint64) [32]byte {
+	sum := sha256.Sum256(payload)
+	buf := make([]byte, 40)
+	copy(buf, sum[:])
+	binary.BigEndian.PutUint64(buf[32:], stamp)
+	return sha256.Sum256(buf)
+}
+
+func leadingZeroBits(hash [32]byte) uint8 {
+	var n uint8
+	for _, b := range hash {
+		if b == 0 {
+			n += 8
+			continue
+		}
+		for b&0x80 == 0 {
+			n++
+			b <<= 1
+		}
+		break
+	}
+	return n
+}
+
+// ValidStamp is true if the parcel's stamp meets the difficulty given
+func ValidStamp(parcel *Parcel, difficulty uint8) bool {
+	if difficulty == 0 {
+		return true
+	}
+	return leadingZeroBits(stampDigest(parcel.Payload, parcel.Header.Stamp)) >= difficulty
+}
+
+// StampParcel does the work needed for a costly request to be accepted by peers
+// requiring AdmissionDifficulty.  Other parcels are left alone.  Each bit of difficulty
+// doubles the work; at 16 bits it takes around 65,000 hashes.
+func StampParcel(parcel *Parcel) {
+	if AdmissionDifficulty == 0 || !CostlyAppTypes[parcel.Header.AppType] {
+		return
+	}
+	sum := sha256.Sum256(parcel.Payload)
+	buf := make([]byte, 40)
+	copy(buf, sum[:])
+	for stamp := uint64(0); ; stamp++ {
+		binary.BigEndian.PutUint64(buf[32:], stamp)
+		if leadingZeroBits(sha256.Sum256(buf)) >= AdmissionDifficulty {
+			parcel.Header.Stamp = stamp
+			return
+		}
+	}
+}
+
+// rateLimiter is a token bucket, holding up to two seconds' worth of tokens
+type rateLimiter struct {
+	tokens float64
+	last   time.Time
+}
+
+func (r *rateLimiter) allow(rate float64, now time.Time) bool {
+	if rate <= 0 {
+		return true
+	}
+	burst := 2 * rate
+	if burst < 1 {
+		burst = 1
+	}
+	if r.last.IsZero() {
+		r.tokens = burst
+	} else {
+		r.tokens += now.Sub(r.last).Seconds() * rate
+		if r.tokens > burst {
+			r.tokens = burst
+		}
+	}
+	r.last = now
+	if r.tokens < 1 {
+		return false
+	}
+	r.tokens--
+	return true
+}
+
+// admission is the per connection state of admission control.  Only used by the
+// connection's own goroutine.
+type admission struct {
+	requests rateLimiter
+	messages rateLimiter
+}
+
+// These constants are the results of admission control
+const (
+	Admitted         uint8 = iota
+	RejectedNoStamp        // A costly request without a valid stamp
+	RejectedRequests       // Over the rate for costly requests
+	RejectedMessages       // Over the rate for other messages
+)
+
+// admit decides if an application parcel from a peer is passed on
+func (a *admission) admit(peer *Peer, parcel *Parcel, now time.Time) uint8 {
+	if peer.Type == SpecialPeer {
+		return Admitted
+	}
+	if CostlyAppTypes[parcel.Header.AppType] {
+		if !ValidStamp(parcel, AdmissionDifficulty) {
+			return RejectedNoStamp
+		}
+		if !a.requests.allow(AdmissionRequestRate, now) {
+			return RejectedRequests
+		}
+		return Admitted
+	}
+	if !a.messages.allow(AdmissionMessageRate, now) {
+		return RejectedMessages
+	}
+	return Admitted
+}
@@ -0,0 +1,35 @@
+package p2p_test
+
+import (
+	"testing"
+
+	. "github.com/FactomProject/factomd/p2p"
+)
+
+func TestStampParcel(t *testing.T) {
+	defer func(d uint8) { AdmissionDifficulty = d }(AdmissionDifficulty)
+	CostlyAppTypes["16"] = true
+	defer delete(CostlyAppTypes, "16")
+
+	AdmissionDifficulty = 12
+	parcel := NewParcel(TestNet, []byte("missing message request"))
+	parcel.Header.AppType = "16"
+	if ValidStamp(parcel, 12) && parcel.Header.Stamp == 0 {
+		t.Skip("Unstamped payload happens to meet the difficulty")
+	}
+	StampParcel(parcel)
+	if !ValidStamp(parcel, 12) {
+		t.Errorf("Stamp %d is not valid", parcel.Header.Stamp)
+	}
+	if !ValidStamp(parcel, 0) {
+		t.Error("No difficulty should accept any stamp")
+	}
+
+	// Only costly requests are stamped
+	other := NewParcel(TestNet, []byte("an ack"))
+	other.Header.AppType = "1"
+	StampParcel(other)
+	if other.Header.Stamp != 0 {
+		t.Error("Stamped a parcel that isn't a costly request")
+	}
+}
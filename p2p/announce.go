@@ -0,0 +1,199 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package p2p
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// Peer announcements let a node keep its standing with its peers when its address
+// changes, as it does on many home connections.  Each node has a key, kept next to the
+// peers file, and signs an announcement to each peer it connects to.  A peer that finds
+// the key it last saw at another address moves that address's history (quality score,
+// connections, sources) to the address the announcement came from, instead of treating
+// the node as a stranger and waiting on discovery.
+//
+// An announcement names the node ID of the peer it is for and carries the time it was
+// made, so it can't be replayed to another peer, or later to the same one.
+
+// How old an announcement can be and still be accepted
+const MaxAnnouncementAge = 10 * time.Minute
+
+// The key this node signs its announcements with.  Nil turns announcements off.
+var nodeKey *primitives.PrivateKey
+
+// PeerAnnouncement is the payload of a TypePeerAnnounce parcel
+type PeerAnnouncement struct {
+	PublicKey string // Hex of the node's ed25519 public key
+	To        uint64 // NodeID of the peer the announcement is for
+	Timestamp int64  // Unix seconds
+	Signature string // Hex, over the network, public key, To and Timestamp
+}
+
+func (a *PeerAnnouncement) signedData(network NetworkID) []byte {
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.BigEndian, uint32(network))
+	buf.WriteString(a.PublicKey)
+	binary.Write(&buf, binary.BigEndian, a.To)
+	binary.Write(&buf, binary.BigEndian, a.Timestamp)
+	return buf.Bytes()
+}
+
+// NewPeerAnnouncement makes an announcement for the peer with the node ID given, signed
+// with key
+func NewPeerAnnouncement(key *primitives.PrivateKey, to uint64, now time.Time) *PeerAnnouncement {
+	a := new(PeerAnnouncement)
+	a.PublicKey = key.PublicKeyString()
+	a.To = to
+	a.Timestamp = now.Unix()
+	a.Signature = hex.EncodeToString(key.Sign(a.signedData(CurrentNetwork)).Bytes())
+	return a
+}
+
+// Verify checks that the announcement was signed by its key, is for the node with ID
+// to, and is recent
+func (a *PeerAnnouncement) Verify(to uint64, now time.Time) error {
+	if a.To != to {
+		return fmt.Errorf("announcement is for node %x", a.To)
+	}
+	age := now.Sub(time.Unix(a.Timestamp, 0))
+	if age > MaxAnnouncementAge || age < -MaxAnnouncementAge {
+		return fmt.Errorf("announcement is %s old", age)
+	}
+	pub, err := hex.DecodeString(a.PublicKey)
+	if err != nil || len(pub) != 32 {
+		return fmt.Errorf("bad public key %q", a.PublicKey)
+	}
+	sig, err := hex.DecodeString(a.Signature)
+	if err != nil || len(sig) != 64 {
+		return fmt.Errorf("bad signature")
+	}
+	if !primitives.VerifySlice(pub, a.signedData(CurrentNetwork), sig) {
+		return fmt.Errorf("signature does not verify")
+	}
+	return nil
+}
+
+// announcementParcel makes the parcel announcing us to the peer with the node ID given,
+// or nil if we don't announce ourselves
+func announcementParcel(to uint64) *Parcel {
+	if nodeKey == nil {
+		return nil
+	}
+	payload, err := json.Marshal(NewPeerAnnouncement(nodeKey, to, time.Now()))
+	if err != nil {
+		return nil
+	}
+	parcel := NewParcel(CurrentNetwork, payload)
+	parcel.Header.Type = TypePeerAnnounce
+	return parcel
+}
+
+// loadNodeKey reads the node's key from path, making and saving a new one if there isn't
+// one.  An empty path, or a key that can't be saved, gives a key that lasts until the
+// node restarts, which still covers an address changing under a running node.
+func loadNodeKey(path string) *primitives.PrivateKey {
+	if path == "" {
+		return primitives.RandomPrivateKey()
+	}
+	if data, err := ioutil.ReadFile(path); err == nil {
+		key, err := primitives.NewPrivateKeyFromHex(strings.TrimSpace(string(data)))
+		if err == nil {
+			return key
+		}
+		logerror("ctrlr", "Ignoring the bad node key in %s: %v", path, err)
+	}
+	key := primitives.RandomPrivateKey()
+	if err := ioutil.WriteFile(path, []byte(key.PrivateKeyString()+"\n"), 0600); err != nil {
+		logerror("ctrlr", "Cannot save the node key to %s: %v", path, err)
+	}
+	return key
+}
+
+// migratePeer moves what we know of the peer at oldAddress to newAddress.  The entry for
+// the new address, if there was one, is replaced.  Returns the moved peer.
+func (d *Discovery) migratePeer(oldAddress string, newAddress string, port string) Peer {
+	UpdateKnownPeers.Lock()
+	defer UpdateKnownPeers.Unlock()
+	peer := d.knownPeers[oldAddress]
+	current, present := d.knownPeers[newAddress]
+	delete(d.knownPeers, oldAddress)
+
+	peer.Address = newAddress
+	peer.Port = port
+	peer.Location = peer.LocationFromAddress()
+	if present && current.LastContact.After(peer.LastContact) {
+		peer.LastContact = current.LastContact
+	}
+	d.knownPeers[newAddress] = peer
+	return peer
+}
+
+// peerWithKey returns the address we know the key at, if any
+func (d *Discovery) peerWithKey(publicKey string) (string, bool) {
+	UpdateKnownPeers.Lock()
+	defer UpdateKnownPeers.Unlock()
+	for address, peer := range d.knownPeers {
+		if peer.PublicKey == publicKey {
+			return address, true
+		}
+	}
+	return "", false
+}
+
+// handleAnnouncement records the key of the peer on connection, and if the key was last
+// seen at another address, moves the peer's history to its new one
+func (c *Controller) handleAnnouncement(parcel Parcel, connection Connection) {
+	a := new(PeerAnnouncement)
+	if err := json.Unmarshal(parcel.Payload, a); err != nil {
+		note("ctrlr", "handleAnnouncement() bad announcement from %s: %v", connection.peer.PeerIdent(), err)
+		return
+	}
+	if err := a.Verify(NodeID, time.Now()); err != nil {
+		note("ctrlr", "handleAnnouncement() rejected announcement from %s: %v", connection.peer.PeerIdent(), err)
+		p2pAnnouncements.WithLabelValues("rejected").Inc()
+		return
+	}
+	if connection.peerKey != "" && connection.peerKey != a.PublicKey {
+		// The key of an encrypted connection is the peer's; it can't announce another
+		note("ctrlr", "handleAnnouncement() announcement from %s is not of the key it connected with", connection.peer.PeerIdent())
+		p2pAnnouncements.WithLabelValues("rejected").Inc()
+		return
+	}
+	if last := c.lastAnnouncement[a.PublicKey]; a.Timestamp <= last {
+		p2pAnnouncements.WithLabelValues("rejected").Inc()
+		return
+	}
+	c.lastAnnouncement[a.PublicKey] = a.Timestamp
+
+	address := connection.peer.Address
+	peer := connection.peer
+	peer.PublicKey = a.PublicKey
+	oldAddress, known := c.discovery.peerWithKey(a.PublicKey)
+	if known && oldAddress != address {
+		significant("ctrlr", "Peer %s has moved from %s to %s", a.PublicKey[:12], oldAddress, address)
+		peer = c.discovery.migratePeer(oldAddress, address, connection.peer.Port)
+		p2pAnnouncements.WithLabelValues("migrated").Inc()
+		// Whatever connection we had to the old address is dead, or soon will be
+		if old, present := c.connectionsByAddress[oldAddress]; present && !old.IsPersistent() {
+			BlockFreeChannelSend(old.SendChannel, ConnectionCommand{Command: ConnectionShutdownNow})
+		}
+	} else {
+		p2pAnnouncements.WithLabelValues("accepted").Inc()
+	}
+	// The connection keeps its own copy of the peer, and writes it back to discovery
+	if conn, present := c.connections[connection.peer.Hash]; present {
+		BlockFreeChannelSend(conn.SendChannel, ConnectionCommand{Command: ConnectionAdoptPeer, Peer: peer})
+	}
+}
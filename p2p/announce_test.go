@@ -0,0 +1,39 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package p2p_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/FactomProject/factomd/common/primitives"
+	. "github.com/FactomProject/factomd/p2p"
+)
+
+func TestPeerAnnouncement(t *testing.T) {
+	key := primitives.RandomPrivateKey()
+	now := time.Now()
+	a := NewPeerAnnouncement(key, 42, now)
+	if err := a.Verify(42, now); err != nil {
+		t.Errorf("Expected the announcement to verify: %v", err)
+	}
+	if err := a.Verify(43, now); err == nil {
+		t.Errorf("Expected an announcement for another node to be rejected")
+	}
+	if err := a.Verify(42, now.Add(2*MaxAnnouncementAge)); err == nil {
+		t.Errorf("Expected an old announcement to be rejected")
+	}
+
+	forged := *a
+	forged.PublicKey = primitives.RandomPrivateKey().PublicKeyString()
+	if err := forged.Verify(42, now); err == nil {
+		t.Errorf("Expected an announcement with another key to be rejected")
+	}
+	moved := *a
+	moved.Timestamp++
+	if err := moved.Verify(42, now); err == nil {
+		t.Errorf("Expected an announcement with a changed time to be rejected")
+	}
+}
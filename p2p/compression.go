@@ -0,0 +1,111 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package p2p
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/FactomProject/snappy-go"
+)
+
+// Payload compression.  A node says in the header of every parcel it sends which
+// encodings it can take.  The payloads of the application messages it marks, the large
+// ones a node catching up on blocks asks for, go to a peer that takes snappy compressed,
+// and are expanded again by the connection they arrive on.  A peer that doesn't say it
+// takes them, as older nodes don't, gets them as they are.
+
+// Capabilities a node advertises in the header of its parcels
+const (
+	CapabilitySnappy uint32 = 1 << iota // Takes payloads compressed with snappy
+)
+
+// Encodings of a parcel payload
+const (
+	EncodingPlain uint8 = iota
+	EncodingSnappy
+)
+
+var (
+	// Compress payloads for the peers that take them, and take them compressed
+	Compression = true
+
+	// The AppTypes of the messages worth compressing.  Set by the application.
+	CompressedAppTypes = map[string]bool{}
+
+	// Payloads smaller than this go as they are
+	MinCompressSize = 1024
+)
+
+// ParseCompression parses the compression setting: snappy or off
+func ParseCompression(setting string) (bool, error) {
+	switch strings.ToLower(setting) {
+	case "", "snappy":
+		return true, nil
+	case "off":
+		return false, nil
+	}
+	return false, fmt.Errorf("unknown compression %q, use snappy or off", setting)
+}
+
+// capabilities returns the capabilities this node advertises
+func capabilities() uint32 {
+	if Compression {
+		return CapabilitySnappy
+	}
+	return 0
+}
+
+// CompressParcel compresses the payload of the parcel if the peer takes it, and it is
+// one worth compressing.  Returns true if it did.
+func CompressParcel(parcel *Parcel, peerCapabilities uint32) bool {
+	switch {
+	case !Compression || peerCapabilities&CapabilitySnappy == 0:
+		return false
+	case parcel.Header.Type != TypeMessage || parcel.Header.Encoding != EncodingPlain:
+		return false
+	case len(parcel.Payload) < MinCompressSize || !CompressedAppTypes[parcel.Header.AppType]:
+		return false
+	}
+	compressed, err := snappy.Encode(nil, parcel.Payload)
+	if err != nil || len(compressed) >= len(parcel.Payload) {
+		return false
+	}
+	p2pCompressedBytes.WithLabelValues("plain").Add(float64(len(parcel.Payload)))
+	p2pCompressedBytes.WithLabelValues("compressed").Add(float64(len(compressed)))
+	parcel.Payload = compressed
+	parcel.Header.Encoding = EncodingSnappy
+	parcel.UpdateHeader()
+	return true
+}
+
+// ExpandParcel restores the payload of a compressed parcel
+func ExpandParcel(parcel *Parcel) error {
+	switch parcel.Header.Encoding {
+	case EncodingPlain:
+		return nil
+	case EncodingSnappy:
+	default:
+		return fmt.Errorf("unknown encoding %d", parcel.Header.Encoding)
+	}
+	if !Compression {
+		return fmt.Errorf("compressed payload, which we don't take")
+	}
+	size, err := snappy.DecodedLen(parcel.Payload)
+	if err != nil {
+		return err
+	}
+	if size > MaxPayloadSize {
+		return fmt.Errorf("payload expands to %d bytes", size)
+	}
+	payload, err := snappy.Decode(nil, parcel.Payload)
+	if err != nil {
+		return err
+	}
+	parcel.Payload = payload
+	parcel.Header.Encoding = EncodingPlain
+	parcel.UpdateHeader()
+	return nil
+}
@@ -0,0 +1,62 @@
+package p2p_test
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/FactomProject/factomd/p2p"
+)
+
+func newCompressibleParcel(appType string, size int) *Parcel {
+	parcel := NewParcel(CurrentNetwork, bytes.Repeat([]byte("dbstate "), size/8))
+	parcel.Header.Type = TypeMessage
+	parcel.Header.AppType = appType
+	return parcel
+}
+
+func TestCompressParcel(t *testing.T) {
+	CompressedAppTypes["20"] = true
+	defer delete(CompressedAppTypes, "20")
+
+	parcel := newCompressibleParcel("20", 64*1024)
+	payload := append([]byte{}, parcel.Payload...)
+	if !CompressParcel(parcel, CapabilitySnappy) {
+		t.Fatal("Expected a large marked payload to be compressed")
+	}
+	if parcel.Header.Encoding != EncodingSnappy || len(parcel.Payload) >= len(payload)/2 {
+		t.Errorf("Expected the payload compressed to less than half, got %d of %d bytes", len(parcel.Payload), len(payload))
+	}
+	if err := ExpandParcel(parcel); err != nil {
+		t.Fatal(err)
+	}
+	if parcel.Header.Encoding != EncodingPlain || !bytes.Equal(parcel.Payload, payload) || parcel.Header.Length != uint32(len(payload)) {
+		t.Error("Expected the payload expanded as it was")
+	}
+
+	if CompressParcel(newCompressibleParcel("20", 64*1024), 0) {
+		t.Error("Expected no compression for a peer that doesn't take it")
+	}
+	if CompressParcel(newCompressibleParcel("20", 64), CapabilitySnappy) {
+		t.Error("Expected a small payload to go as it is")
+	}
+	if CompressParcel(newCompressibleParcel("21", 64*1024), CapabilitySnappy) {
+		t.Error("Expected a payload not marked to go as it is")
+	}
+
+	unknown := newCompressibleParcel("20", 64)
+	unknown.Header.Encoding = EncodingSnappy + 1
+	if ExpandParcel(unknown) == nil {
+		t.Error("Expected a payload of an unknown encoding to be refused")
+	}
+}
+
+func TestParseCompression(t *testing.T) {
+	for setting, want := range map[string]bool{"": true, "snappy": true, "SNAPPY": true, "off": false} {
+		if got, err := ParseCompression(setting); err != nil || got != want {
+			t.Errorf("ParseCompression(%q) = %v, %v", setting, got, err)
+		}
+	}
+	if _, err := ParseCompression("zip"); err == nil {
+		t.Error("Expected an unknown compression to be refused")
+	}
+}
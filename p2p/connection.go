@@ -47,6 +47,11 @@ type Connection struct {
 	isPersistent    bool              // Persistent connections we always redail.
 	notes           string            // Notes about the connection, for debugging (eg: error)
 	metrics         ConnectionMetrics // Metrics about this connection
+	admission       admission         // Admission control of the messages the peer sends us
+	announced       bool              // We have announced ourselves to the peer
+	peerKey         string            // Hex of the key the peer presented, if the connection is encrypted
+	nonRoutable     bool              // The peer asked to be left out of broadcasts
+	capabilities    uint32            // What the peer said it can take, such as compressed payloads
 	Logger          *log.Entry
 }
 
@@ -105,8 +110,11 @@ type ConnectionMetrics struct {
 	// Red: Below -50
 	// Yellow: -50 - 100
 	// Green: > 100
-	ConnectionState string // Basic state of the connection
-	ConnectionNotes string // Connectivity notes for the connection
+	ConnectionState string  // Basic state of the connection
+	ConnectionNotes string  // Connectivity notes for the connection
+	Reputation      float64 `json:",omitempty"` // Penalty points of the peer's reputation
+	Standing        string  `json:",omitempty"` // The standing its reputation gives the peer
+	Encrypted       bool    `json:",omitempty"` // The connection is encrypted
 }
 
 // ConnectionCommand is used to instruct the Connection to carry out some functionality.
@@ -115,6 +123,7 @@ type ConnectionCommand struct {
 	Peer    Peer
 	Delta   int32
 	Metrics ConnectionMetrics
+	Offense Offense `json:",omitempty"`
 }
 
 func (e *ConnectionCommand) JSONByte() ([]byte, error) {
@@ -137,7 +146,9 @@ const (
 	ConnectionUpdatingPeer
 	ConnectionAdjustPeerQuality
 	ConnectionUpdateMetrics
-	ConnectionGoOffline // Notifies the connection it should go offinline (eg from another goroutine)
+	ConnectionGoOffline     // Notifies the connection it should go offinline (eg from another goroutine)
+	ConnectionAdoptPeer     // Takes on the history of the peer, after it announced itself
+	ConnectionReportOffense // Notifies the controller the peer broke the protocol
 )
 
 //////////////////////////////
@@ -306,10 +317,12 @@ func (c *Connection) dialLoop() {
 		case ConnectionOffline == c.state: // We were online with the peer at one point.
 			c.attempts++
 			if MaxNumberOfRedialAttempts < c.attempts {
+				c.peer.dialFailed(time.Now())
 				c.goShutdown()
 				return
 			}
 		default:
+			c.peer.dialFailed(time.Now())
 			c.goShutdown()
 			return
 		}
@@ -324,6 +337,11 @@ func (c *Connection) dial() bool {
 	// conn, err := net.Dial("tcp", c.peer.Address)
 	conn, err := net.DialTimeout("tcp", address, time.Second*10)
 	if nil == err {
+		conn = secureDialed(conn, address, func() (net.Conn, error) {
+			return net.DialTimeout("tcp", address, time.Second*10)
+		})
+	}
+	if nil == err && nil != conn {
 		c.conn = conn
 		return true
 	}
@@ -336,11 +354,13 @@ func (c *Connection) goOnline() {
 	now := time.Now()
 	c.encoder = gob.NewEncoder(c.conn)
 	c.decoder = gob.NewDecoder(c.conn)
+	c.peerKey = peerKey(c.conn)
 	c.attempts = 0
 	c.timeLastPing = now
 	c.timeLastAttempt = now
 	c.timeLastUpdate = now
 	c.peer.LastContact = now
+	c.peer.dialSucceeded()
 
 	c.state = ConnectionOnline
 
@@ -438,6 +458,16 @@ func (c *Connection) handleCommand() {
 		case ConnectionGoOffline:
 			debug(c.peer.PeerIdent(), "handleCommand() disconnecting peer: %s goOffline command recieved", c.peer.PeerIdent())
 			c.goOffline()
+		case ConnectionAdoptPeer:
+			peer := command.Peer
+			c.peer.PublicKey = peer.PublicKey
+			c.peer.QualityScore = peer.QualityScore
+			c.peer.Connections = peer.Connections
+			c.peer.Source = peer.Source
+			if peer.Type == SpecialPeer {
+				c.peer.Type = SpecialPeer
+			}
+			c.updatePeer()
 		default:
 			logfatal(c.peer.PeerIdent(), "handleCommand() unknown command?: %+v ", command)
 		}
@@ -448,6 +478,9 @@ func (c *Connection) handleCommand() {
 func (c *Connection) sendParcel(parcel Parcel) {
 
 	parcel.Header.NodeID = NodeID // Send it out with our ID for loopback.
+	parcel.Header.NonRoutable = NonRoutable
+	parcel.Header.Capabilities = capabilities()
+	CompressParcel(&parcel, c.capabilities)
 	c.conn.SetWriteDeadline(time.Now().Add(NetworkDeadline * 500))
 
 	//deadline := time.Now().Add(NetworkDeadline)
@@ -538,12 +571,15 @@ func (c *Connection) handleParcel(parcel Parcel) {
 	defer func() {
 		if r := recover(); r != nil {
 			c.peer.demerit() /// so someone DDoS or just incompatible will eventually be cut off after 200+ panics
+			c.reportOffense(OffenseProtocol)
 			fmt.Fprintf(os.Stdout, "Caught Exception in connection %s: %v\n", c.peer.PeerFixedIdent(), r)
 			return
 		}
 	}()
 
 	c.peer.Port = parcel.Header.PeerPort // Peers communicate their port in the header. Could be moved to a handshake
+	c.nonRoutable = parcel.Header.NonRoutable
+	c.capabilities = parcel.Header.Capabilities
 	validity := c.parcelValidity(parcel)
 	switch validity {
 	case InvalidDisconnectPeer:
@@ -558,12 +594,19 @@ func (c *Connection) handleParcel(parcel Parcel) {
 		debug(c.peer.PeerIdent(), "Connection.handleParcel() got invalid message")
 		parcel.Print()
 		c.peer.demerit()
+		c.reportOffense(OffenseProtocol)
 		return
 	case ParcelValid:
 		parcel.Trace("Connection.handleParcel()-ParcelValid", "I")
 		c.peer.LastContact = time.Now() // We only update for valid messages (incluidng pings and heartbeats)
 		c.attempts = 0                  // reset since we are clearly in touch now.
 		c.peer.merit()                  // Increase peer quality score.
+		c.announce(parcel.Header.NodeID)
+		if err := ExpandParcel(&parcel); err != nil {
+			significant(c.peer.PeerIdent(), "Connection.handleParcel() cannot expand the payload: %v", err)
+			c.reportOffense(OffenseProtocol)
+			return
+		}
 		debug(c.peer.PeerIdent(), "Connection.handleParcel() got ParcelValid %s", parcel.MessageType())
 		if Notes <= CurrentLoggingLevel {
 			parcel.PrintMessageType()
@@ -629,13 +672,21 @@ func (c *Connection) handleParcelTypes(parcel Parcel) {
 		BlockFreeChannelSend(c.ReceiveChannel, ConnectionParcel{Parcel: parcel}) // Controller handles these.
 	case TypePeerResponse:
 		BlockFreeChannelSend(c.ReceiveChannel, ConnectionParcel{Parcel: parcel}) // Controller handles these.
+	case TypePeerAnnounce:
+		BlockFreeChannelSend(c.ReceiveChannel, ConnectionParcel{Parcel: parcel}) // Controller handles these.
 	case TypeMessage:
+		if !c.admitParcel(&parcel) {
+			return
+		}
 		c.peer.QualityScore = c.peer.QualityScore + 1
 		// Store our connection ID so the controller can direct response to us.
 		parcel.Header.TargetPeer = c.peer.Hash
 		parcel.Header.NodeID = NodeID
 		BlockFreeChannelSend(c.ReceiveChannel, ConnectionParcel{Parcel: parcel}) // Controller handles these.
 	case TypeMessagePart:
+		if !c.admitParcel(&parcel) {
+			return
+		}
 		c.peer.QualityScore = c.peer.QualityScore + 1
 		// Store our connection ID so the controller can direct response to us.
 		parcel.Header.TargetPeer = c.peer.Hash
@@ -646,6 +697,41 @@ func (c *Connection) handleParcelTypes(parcel Parcel) {
 	}
 }
 
+// admitParcel applies admission control to an application parcel.  A costly request
+// without a valid stamp costs the peer some quality; going over a rate just drops the parcel.
+func (c *Connection) admitParcel(parcel *Parcel) bool {
+	switch c.admission.admit(&c.peer, parcel, time.Now()) {
+	case Admitted:
+		return true
+	case RejectedNoStamp:
+		parcel.Trace("Connection.admitParcel()-no-stamp", "I")
+		p2pAdmissionRejected.WithLabelValues("stamp").Inc()
+		c.peer.demerit()
+	case RejectedRequests:
+		p2pAdmissionRejected.WithLabelValues("request-rate").Inc()
+	case RejectedMessages:
+		p2pAdmissionRejected.WithLabelValues("message-rate").Inc()
+	}
+	return false
+}
+
+// reportOffense charges the peer for an offense against its reputation
+func (c *Connection) reportOffense(offense Offense) {
+	BlockFreeChannelSend(c.ReceiveChannel, ConnectionCommand{Command: ConnectionReportOffense, Offense: offense})
+}
+
+// announce sends our signed announcement once per connection, when we first hear from
+// the peer and so know its node ID
+func (c *Connection) announce(to uint64) {
+	if c.announced {
+		return
+	}
+	c.announced = true
+	if parcel := announcementParcel(to); parcel != nil {
+		BlockFreeChannelSend(c.SendChannel, ConnectionParcel{Parcel: *parcel})
+	}
+}
+
 func (c *Connection) pingPeer() {
 	durationLastContact := time.Since(c.peer.LastContact)
 	durationLastPing := time.Since(c.timeLastPing)
@@ -675,6 +761,7 @@ func (c *Connection) updateStats() {
 		c.metrics.PeerQuality = c.peer.QualityScore
 		c.metrics.ConnectionState = connectionStateStrings[c.state]
 		c.metrics.ConnectionNotes = c.notes
+		c.metrics.Encrypted = c.peerKey != ""
 		verbose(c.peer.PeerIdent(), "updatePeer() SENDING ConnectionUpdateMetrics - Bytes Sent: %d Bytes Received: %d", c.metrics.BytesSent, c.metrics.BytesReceived)
 		BlockFreeChannelSend(c.ReceiveChannel, ConnectionCommand{Command: ConnectionUpdateMetrics, Metrics: c.metrics})
 	}
@@ -18,6 +18,7 @@ import (
 	"time"
 	"unicode"
 
+	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/common/primitives"
 
 	log "github.com/sirupsen/logrus"
@@ -55,9 +56,11 @@ type Controller struct {
 	lastDiscoveryRequest       time.Time
 	NodeID                     uint64
 	lastStatusReport           time.Time
-	lastPeerRequest            time.Time       // Last time we asked peers about the peers they know about.
-	specialPeersString         string          // configuration set special peers
-	partsAssembler             *PartsAssembler // a data structure that assembles full messages from received message parts
+	lastPeerRequest            time.Time        // Last time we asked peers about the peers they know about.
+	specialPeersString         string           // configuration set special peers
+	partsAssembler             *PartsAssembler  // a data structure that assembles full messages from received message parts
+	lastAnnouncement           map[string]int64 // Timestamp of the last announcement accepted, by public key
+	reputations                *Reputations     // How each peer has behaved
 }
 
 type ControllerInit struct {
@@ -70,6 +73,10 @@ type ControllerInit struct {
 	ConnectionMetricsChannel chan interface{} // Channel on which we put the connection metrics map, periodically.
 	LogPath                  string           // Path for logs
 	LogLevel                 string           // Logging level
+	Encryption               string           // Whether to encrypt connections: required, preferred or off
+	NonRoutable              bool             // Ask peers to leave us out of their broadcasts, as a read replica does
+	PeerStore                PeerStore        // Where the known peers are kept between runs; the peers file if nil
+	Compression              string           // Whether to compress payloads for peers that take them: snappy or off
 }
 
 // CommandDialPeer is used to instruct the Controller to dial a peer address
@@ -144,6 +151,11 @@ func (e *CommandDisconnect) String() string {
 	return str
 }
 
+// CommandSavePeers is used to instruct the Controller to save the known peers to the peers file
+type CommandSavePeers struct {
+	_ uint8
+}
+
 // CommandChangeLogging is used to instruct the Controller to takve various actions.
 type CommandChangeLogging struct {
 	Level uint8
@@ -190,11 +202,31 @@ func (c *Controller) Init(ci ControllerInit) *Controller {
 	c.lastPeerRequest = time.Now()
 	CurrentNetwork = ci.Network
 	OnlySpecialPeers = ci.Exclusive
+	NonRoutable = ci.NonRoutable
 	c.specialPeersString = ci.SpecialPeers
 	c.lastDiscoveryRequest = time.Now() // Discovery does its own on startup.
 	c.lastConnectionMetricsUpdate = time.Now()
 	c.partsAssembler = new(PartsAssembler).Init()
-	discovery := new(Discovery).Init(ci.PeersFile, ci.SeedURL)
+	c.lastAnnouncement = make(map[string]int64)
+	c.reputations = NewReputations()
+	if ci.PeersFile != "" {
+		nodeKey = loadNodeKey(ci.PeersFile + ".key")
+	} else {
+		nodeKey = loadNodeKey("")
+	}
+	mode, err := ParseEncryption(ci.Encryption)
+	if err != nil {
+		logerror("ctrlr", "Controller.Init() %v", err)
+	}
+	Encryption = mode
+	if Compression, err = ParseCompression(ci.Compression); err != nil {
+		logerror("ctrlr", "Controller.Init() %v", err)
+	}
+	tlsConfig, err = NewTLSConfig(nodeKey)
+	if err != nil {
+		logerror("ctrlr", "Controller.Init() cannot encrypt connections: %v", err)
+	}
+	discovery := new(Discovery).Init(ci.PeersFile, ci.SeedURL, ci.PeerStore)
 	c.discovery = *discovery
 	// Set this to the past so we will do peer management almost right away after starting up.
 	note("ctrlr", "\n\n\n\n\nController.Init(%s) Controller is: %+v\n\n", ci.Port, c)
@@ -231,6 +263,20 @@ func (c *Controller) DialSpecialPeersString(peersString string) {
 	}
 }
 
+// isSpecialAddress is true if the address is one of the configured special peers
+func (c *Controller) isSpecialAddress(address string) bool {
+	parseFunc := func(c rune) bool {
+		return !unicode.IsLetter(c) && !unicode.IsNumber(c) && !unicode.IsPunct(c)
+	}
+	for _, peerAddress := range strings.FieldsFunc(c.specialPeersString, parseFunc) {
+		ipPort := strings.Split(peerAddress, ":")
+		if len(ipPort) == 2 && ipPort[0] == address {
+			return true
+		}
+	}
+	return false
+}
+
 func (c *Controller) StartLogging(level uint8) {
 	BlockFreeChannelSend(c.commandChannel, CommandChangeLogging{Level: level})
 }
@@ -264,10 +310,26 @@ func (c *Controller) Ban(peerHash string) {
 	BlockFreeChannelSend(c.commandChannel, CommandBan{PeerHash: peerHash})
 }
 
+// SavePeers has the known peers written to the peers file
+func (c *Controller) SavePeers() {
+	BlockFreeChannelSend(c.commandChannel, CommandSavePeers{})
+}
+
 func (c *Controller) Disconnect(peerHash string) {
 	BlockFreeChannelSend(c.commandChannel, CommandDisconnect{PeerHash: peerHash})
 }
 
+// ReportPeer charges a peer for an offense against its reputation, banning it if it
+// has too many
+func (c *Controller) ReportPeer(peerHash string, offense Offense) {
+	c.applyStanding(c.reputations.Report(peerHash, offense, time.Now()))
+}
+
+// GetReputations returns the reputations of the peers, worst first
+func (c *Controller) GetReputations() []interfaces.PeerReputation {
+	return c.reputations.Get()
+}
+
 func (c *Controller) GetNumberConnections() int {
 	return len(c.connections)
 }
@@ -306,8 +368,13 @@ func (c *Controller) acceptLoop(listener net.Listener) {
 		case nil:
 			switch {
 			case c.numberIncommingConnections < MaxNumberIncommingConnections:
-				c.AddPeer(conn) // Sends command to add the peer to the peers list
-				note("ctrlr", "Controller.acceptLoop() new peer: %+v", conn)
+				// The handshake waits on the peer, so it's done off the accept loop
+				go func(conn net.Conn) {
+					if conn = secureAccepted(conn); conn != nil {
+						c.AddPeer(conn) // Sends command to add the peer to the peers list
+						note("ctrlr", "Controller.acceptLoop() new peer: %+v", conn)
+					}
+				}(conn)
 			default:
 				note("ctrlr", "Controller.acceptLoop() new peer, but too many incomming connections. %d", c.numberIncommingConnections)
 				conn.Close()
@@ -448,12 +515,15 @@ func (c *Controller) route() {
 				loopcnt := 0
 				for _, connection := range c.connections {
 					if loopcnt == spot {
-						BlockFreeChannelSend(connection.SendChannel, ConnectionParcel{Parcel: parcel})
+						// Read replicas take no part in consensus, so get no gossip
+						if !connection.nonRoutable {
+							BlockFreeChannelSend(connection.SendChannel, ConnectionParcel{Parcel: parcel})
+							cnt++
+						}
 						spot++
 						if spot >= clen {
 							spot = 0
 						}
-						cnt++
 					}
 					if cnt >= num {
 						break broadcast
@@ -467,6 +537,7 @@ func (c *Controller) route() {
 		case RandomPeerFlag: // Find a random peer, send to that peer.
 			debug("ctrlr", "Controller.route() Directed FINDING RANDOM Target: %s Type: %s #Number Connections: %d", parcel.Header.TargetPeer, parcel.Header.AppType, len(c.connections))
 			bestKey := ""
+			passedOver := ""
 		search:
 			for i := 0; i < len(c.connections)*3; i++ {
 				guess := (rand.Int() % len(c.connections))
@@ -474,7 +545,11 @@ func (c *Controller) route() {
 				for key := range c.connections {
 					if i == guess {
 						connection := c.connections[key]
-						if connection.metrics.BytesReceived > 0 {
+						if connection.metrics.BytesReceived > 0 && !connection.nonRoutable {
+							if (PassOverPeer != nil && PassOverPeer(key)) || c.reputations.Standing(key) != StandingGood {
+								passedOver = key
+								break
+							}
 							bestKey = key
 							break search
 						}
@@ -482,6 +557,9 @@ func (c *Controller) route() {
 					i++
 				}
 			}
+			if bestKey == "" {
+				bestKey = passedOver
+			}
 			parcel.Header.TargetPeer = bestKey
 			c.doDirectedSend(parcel)
 		default: // Check if we're connected to the peer, if not drop message.
@@ -495,6 +573,7 @@ func (c *Controller) doDirectedSend(parcel Parcel) {
 	connection, present := c.connections[parcel.Header.TargetPeer]
 	if present { // We're still connected to the target
 		BlockFreeChannelSend(connection.SendChannel, ConnectionParcel{Parcel: parcel})
+		c.reputations.Sent(parcel.Header.TargetPeer, parcel.Header.AppType, time.Now())
 	}
 }
 
@@ -505,6 +584,10 @@ func (c *Controller) handleParcelReceive(message interface{}, peerHash string, c
 	parcel := parameters.Parcel
 	parcel.Header.TargetPeer = peerHash // Set the connection ID so the application knows which peer the message is from.
 	switch parcel.Header.Type {
+	case TypeMessage, TypeMessagePart:
+		c.applyStanding(c.reputations.Received(peerHash, parcel.Header, time.Now()))
+	}
+	switch parcel.Header.Type {
 	case TypeMessage: // Application message, send it on.
 		ApplicationMessagesRecieved++
 		BlockFreeChannelSend(c.FromNetwork, parcel)
@@ -523,6 +606,8 @@ func (c *Controller) handleParcelReceive(message interface{}, peerHash string, c
 	case TypePeerResponse:
 		// Add these peers to our known peers
 		c.discovery.LearnPeers(parcel)
+	case TypePeerAnnounce:
+		c.handleAnnouncement(parcel, connection)
 	default:
 		logfatal("ctrlr", "handleParcelReceive() unknown parcel.Header.Type?: %+v ", parcel)
 	}
@@ -540,6 +625,8 @@ func (c *Controller) handleConnectionCommand(command ConnectionCommand, connecti
 		go connection.goShutdown()
 	case ConnectionUpdatingPeer:
 		c.discovery.updatePeer(command.Peer)
+	case ConnectionReportOffense:
+		c.ReportPeer(connection.peer.Hash, command.Offense)
 	default:
 		logfatal("ctrlr", "handleParcelReceive() unknown command.command?: %+v ", command.Command)
 	}
@@ -560,7 +647,11 @@ func (c *Controller) handleCommand(command interface{}) {
 		conn := parameters.conn // net.Conn
 		addPort := strings.Split(conn.RemoteAddr().String(), ":")
 		// Port initially stored will be the connection port (not the listen port), but peer will update it on first message.
-		peer := new(Peer).Init(addPort[0], addPort[1], 0, RegularPeer, 0)
+		peerType := RegularPeer
+		if c.isSpecialAddress(addPort[0]) {
+			peerType = SpecialPeer // So they dialing us are treated as well as us dialing them
+		}
+		peer := new(Peer).Init(addPort[0], addPort[1], 0, peerType, 0)
 		peer.Source["Accept()"] = time.Now()
 		connection := new(Connection).InitWithConn(conn, *peer)
 		connection.Start()
@@ -587,6 +678,9 @@ func (c *Controller) handleCommand(command interface{}) {
 		if present {
 			BlockFreeChannelSend(connection.SendChannel, ConnectionCommand{Command: ConnectionShutdownNow})
 		}
+	case CommandSavePeers:
+		c.discovery.SavePeers()
+		c.discovery.PrintPeers() // No-op if debugging off.
 	default:
 		logfatal("ctrlr", "Unkown p2p.Controller command recieved: %+v", commandType)
 	}
@@ -620,8 +714,8 @@ func (c *Controller) managePeers() {
 			c.fillOutgoingSlots(NumberPeersToConnect - c.numberOutgoingConnections)
 		}
 		duration := time.Since(c.discovery.lastPeerSave)
-		// Every so often, tell the discovery service to save peers.
-		if PeerSaveInterval < duration {
+		// Every so often, tell the discovery service to save peers, unless the application does.
+		if !ScheduledPeerSaves && PeerSaveInterval < duration {
 			note("controller", "Saving peers")
 			c.discovery.SavePeers()
 			c.discovery.PrintPeers() // No-op if debugging off.
@@ -693,6 +787,7 @@ func (c *Controller) updateMetrics() {
 	if time.Second < time.Since(c.lastConnectionMetricsUpdate) {
 		dot("@@8\n")
 		c.lastConnectionMetricsUpdate = time.Now()
+		c.updateReputations()
 		// Apparently golang doesn't make a deep copy when sending structs over channels. Bad golang.
 		newMetrics := make(map[string]ConnectionMetrics)
 		for key, value := range c.connections {
@@ -708,6 +803,8 @@ func (c *Controller) updateMetrics() {
 					PeerQuality:      metrics.PeerQuality,
 					ConnectionState:  metrics.ConnectionState,
 					ConnectionNotes:  metrics.ConnectionNotes,
+					Reputation:       metrics.Reputation,
+					Standing:         metrics.Standing,
 				}
 			}
 		}
@@ -717,6 +814,38 @@ func (c *Controller) updateMetrics() {
 	}
 }
 
+// updateReputations charges the peers that left requests unanswered, and puts the
+// reputation of each connected peer in its metrics
+func (c *Controller) updateReputations() {
+	connected := make(map[string]bool)
+	for key, connection := range c.connections {
+		connected[key] = true
+		c.reputations.Identify(key, connection.peer.Address, connection.peer.Type == SpecialPeer)
+	}
+	for _, change := range c.reputations.Expire(connected, time.Now()) {
+		c.applyStanding(&change)
+	}
+	for _, rep := range c.reputations.Get() {
+		if metrics, present := c.connectionMetrics[rep.PeerHash]; present {
+			metrics.Reputation = rep.Score
+			metrics.Standing = rep.Standing
+			c.connectionMetrics[rep.PeerHash] = metrics
+		}
+	}
+}
+
+// applyStanding acts on a peer's change of standing: a banned peer is cut off.  A demoted
+// one is only passed over for requests, in route().
+func (c *Controller) applyStanding(change *StandingChange) {
+	if change == nil {
+		return
+	}
+	significant("ctrlr", "Peer %s moved from %s to %s standing by its reputation", change.PeerHash, change.From, change.To)
+	if change.To == StandingBanned {
+		c.Ban(change.PeerHash)
+	}
+}
+
 func (c *Controller) shutdown() {
 	debug("ctrlr", "Controller.shutdown() ")
 	// Go thru peer list and shut down connections.
@@ -8,6 +8,7 @@ import (
 	"bufio"
 	"bytes"
 	"encoding/json"
+	"io/ioutil"
 	"math/rand"
 	"net/http"
 	"os"
@@ -25,6 +26,14 @@ type Discovery struct {
 	lastPeerSave  time.Time  // Last time we saved known peers.
 	rng           *rand.Rand // RNG = random number generator
 	seedURL       string     // URL to the source of a list of peers
+	store         PeerStore  // Where the known peers are kept; the peers file if nil
+}
+
+// PeerStore keeps the known peers between runs of the node, so it rejoins the network
+// from the peers it knew rather than from the seed.  The peers are JSON encoded.
+type PeerStore interface {
+	LoadPeers() ([]byte, error) // nil if no peers were saved
+	SavePeers(data []byte) error
 }
 
 var UpdateKnownPeers sync.Mutex
@@ -35,18 +44,28 @@ var UpdateKnownPeers sync.Mutex
 // Controller and its routines are called from the Controllers runloop()
 // This ensures that all shared memory is accessed from that goroutine.
 
-func (d *Discovery) Init(peersFile string, seed string) *Discovery {
+func (d *Discovery) Init(peersFile string, seed string, store PeerStore) *Discovery {
 	UpdateKnownPeers.Lock()
 	d.knownPeers = map[string]Peer{}
 	UpdateKnownPeers.Unlock()
 	d.rng = rand.New(rand.NewSource(time.Now().UnixNano()))
 	d.peersFilePath = peersFile
 	d.seedURL = seed
-	//d.LoadPeers()
-	d.DiscoverPeersFromSeed()
+	d.store = store
+	d.LoadPeers()
+	// The seed is only needed if we don't know enough peers to fill our outgoing slots
+	if d.numberKnownPeers() < NumberPeersToConnect {
+		d.DiscoverPeersFromSeed()
+	}
 	return d
 }
 
+func (d *Discovery) numberKnownPeers() int {
+	UpdateKnownPeers.Lock()
+	defer UpdateKnownPeers.Unlock()
+	return len(d.knownPeers)
+}
+
 // Only controller should be able to read this, but we still got
 // a concurrent read/write error, so isolating changes to knownPeers
 
@@ -74,39 +93,67 @@ func (d *Discovery) isPeerPresent(peer Peer) bool {
 	return present
 }
 
-// LoadPeers loads the known peers from disk OVERWRITING PREVIOUS VALUES
+// LoadPeers loads the known peers from the peer store, or the peers file, with their
+// quality scores, when they were last seen and their dial backoff.  Peers of other
+// networks are left out.
 func (d *Discovery) LoadPeers() {
-	file, err := os.Open(d.peersFilePath)
+	data, err := d.readPeers()
 	if nil != err {
-		logerror("discovery", "Discover.LoadPeers() File read error on file: %s, Error: %+v", d.peersFilePath, err)
+		logerror("discovery", "Discover.LoadPeers() read error on %s, Error: %+v", d.peersSource(), err)
+		return
+	}
+	if nil == data {
+		return
+	}
+	saved := map[string]Peer{}
+	if err := json.Unmarshal(data, &saved); nil != err {
+		logerror("discovery", "Discover.LoadPeers() ignoring the peers in %s, Error: %+v", d.peersSource(), err)
 		return
 	}
-	dec := json.NewDecoder(bufio.NewReader(file))
 	UpdateKnownPeers.Lock()
-	dec.Decode(&d.knownPeers)
-	// since this is run at startup, reset quality scores.
-	for _, peer := range d.knownPeers {
-		peer.QualityScore = 0
+	for _, peer := range saved {
+		if CurrentNetwork != peer.Network {
+			continue
+		}
 		peer.Location = peer.LocationFromAddress()
 		d.knownPeers[peer.Address] = peer
 	}
 	UpdateKnownPeers.Unlock()
-	note("discovery", "LoadPeers() found %d peers in peers.josn", len(d.knownPeers))
-	file.Close()
+	note("discovery", "LoadPeers() found %d peers in %s", len(saved), d.peersSource())
 }
 
-// SavePeers just saves our known peers out to disk. Called periodically.
+func (d *Discovery) peersSource() string {
+	if nil != d.store {
+		return "the peer store"
+	}
+	return d.peersFilePath
+}
+
+// readPeers returns the saved peers, nil if there are none
+func (d *Discovery) readPeers() ([]byte, error) {
+	if nil != d.store {
+		return d.store.LoadPeers()
+	}
+	if "" == d.peersFilePath {
+		return nil, nil
+	}
+	data, err := ioutil.ReadFile(d.peersFilePath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return data, err
+}
+
+func (d *Discovery) writePeers(data []byte) error {
+	if nil != d.store {
+		return d.store.SavePeers(data)
+	}
+	return ioutil.WriteFile(d.peersFilePath, data, 0644)
+}
+
+// SavePeers just saves our known peers out to the peer store, or the peers file. Called periodically.
 func (d *Discovery) SavePeers() {
-	// save known peers to peers.json
 	d.lastPeerSave = time.Now()
-	file, err := os.Create(d.peersFilePath)
-	if nil != err {
-		logerror("discovery", "Discover.SavePeers() File write error on file: %s, Error: %+v", d.peersFilePath, err)
-		return
-	}
-	defer file.Close()
-	writer := bufio.NewWriter(file)
-	encoder := json.NewEncoder(writer)
 	var qualityPeers = map[string]Peer{}
 	UpdateKnownPeers.Lock()
 	for _, peer := range d.knownPeers {
@@ -121,13 +168,22 @@ func (d *Discovery) SavePeers() {
 		case MinumumQualityScore > peer.QualityScore:
 			note("discovery", "SavePeers() DID NOT SAVE peer in peers.json. MinumumQualityScore: %d > Peer quality score.  Peer: %+v", MinumumQualityScore, peer)
 			break
+		case MaxPeerDialFailures <= peer.DialFailures:
+			note("discovery", "SavePeers() DID NOT SAVE peer in peers.json. Failed %d dials in a row. Peer: %+v", peer.DialFailures, peer)
+			break
 		default:
 			qualityPeers[peer.AddressPort()] = peer
 		}
 	}
 	UpdateKnownPeers.Unlock()
-	encoder.Encode(qualityPeers)
-	writer.Flush()
+	data, err := json.Marshal(qualityPeers)
+	if nil == err {
+		err = d.writePeers(data)
+	}
+	if nil != err {
+		logerror("discovery", "Discover.SavePeers() write error on %s, Error: %+v", d.peersSource(), err)
+		return
+	}
 	note("discovery", "SavePeers() saved %d peers in peers.json. \n They were: %+v", len(qualityPeers), qualityPeers)
 }
 
@@ -145,6 +201,8 @@ func (d *Discovery) LearnPeers(parcel Parcel) {
 	filteredArray := d.filterPeersFromOtherNetworks(peerArray)
 	for _, value := range filteredArray {
 		value.QualityScore = 0
+		value.PublicKey = ""  // Only trusted from the peer's own announcement
+		value.dialSucceeded() // We haven't dialed it
 		switch d.isPeerPresent(value) {
 		case true:
 			alreadyKnownPeer := d.getPeer(value.Address)
@@ -194,7 +252,7 @@ func (d *Discovery) filterForUniqueIPAdresses(peers []Peer) (filtered []Peer) {
 // GetOutgoingPeers gets a set of peers to connect to on startup
 // For now, this gives a set of 12 of the total known peers.
 // We want peers from diverse networks.  So,method is this:
-//	-- generate list of candidates (if exclusive, only special peers)
+//	-- generate list of candidates (if exclusive, only special peers), leaving out those backed off
 //	-- sort candidates by distance
 //  -- if num canddiates is less than desired set, return all candidates
 //  -- Otherwise,repeatedly take candidates at the 0%, %25, %50, %75, %100 points in the list
@@ -203,9 +261,11 @@ func (d *Discovery) filterForUniqueIPAdresses(peers []Peer) (filtered []Peer) {
 func (d *Discovery) GetOutgoingPeers() []Peer {
 	firstPassPeers := []Peer{}
 	selectedPeers := map[string]Peer{}
+	now := time.Now()
 	UpdateKnownPeers.Lock()
 	for _, peer := range d.knownPeers {
 		switch {
+		case SpecialPeer != peer.Type && !peer.Dialable(now): // Backed off after failed dials
 		case OnlySpecialPeers && SpecialPeer == peer.Type:
 			firstPassPeers = append(firstPassPeers, peer)
 		case !OnlySpecialPeers:
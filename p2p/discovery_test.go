@@ -0,0 +1,78 @@
+package p2p_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	. "github.com/FactomProject/factomd/p2p"
+)
+
+type memoryPeerStore struct {
+	data []byte
+}
+
+func (m *memoryPeerStore) LoadPeers() ([]byte, error) { return m.data, nil }
+func (m *memoryPeerStore) SavePeers(data []byte) error {
+	m.data = data
+	return nil
+}
+
+func TestDiscoveryPeerStore(t *testing.T) {
+	now := time.Now()
+	good := *new(Peer).Init("10.0.0.1", "8108", 30, RegularPeer, 0)
+	good.LastContact = now
+	backedOff := *new(Peer).Init("10.0.0.2", "8108", 0, RegularPeer, 0)
+	backedOff.LastContact = now
+	backedOff.DialFailures = 1
+	backedOff.NextDial = now.Add(time.Hour)
+	failing := *new(Peer).Init("10.0.0.3", "8108", 0, RegularPeer, 0)
+	failing.LastContact = now
+	failing.DialFailures = MaxPeerDialFailures
+	otherNet := *new(Peer).Init("10.0.0.4", "8108", 0, RegularPeer, 0)
+	otherNet.Network = CurrentNetwork + 1
+
+	store := new(memoryPeerStore)
+	store.data, _ = json.Marshal(map[string]Peer{
+		good.AddressPort():      good,
+		backedOff.AddressPort(): backedOff,
+		failing.AddressPort():   failing,
+		otherNet.AddressPort():  otherNet,
+	})
+
+	d := new(Discovery).Init("", "", store)
+	outgoing := map[string]bool{}
+	for _, peer := range d.GetOutgoingPeers() {
+		outgoing[peer.Address] = true
+	}
+	if !outgoing[good.Address] || !outgoing[failing.Address] || len(outgoing) != 2 {
+		t.Errorf("Expected to dial the loaded peers that aren't backed off, got %v", outgoing)
+	}
+
+	d.SavePeers()
+	saved := map[string]Peer{}
+	if err := json.Unmarshal(store.data, &saved); err != nil {
+		t.Fatal(err)
+	}
+	if len(saved) != 2 {
+		t.Errorf("Expected the peer that kept failing and the one of another network to be dropped, saved %v", saved)
+	}
+	if peer := saved[good.AddressPort()]; peer.QualityScore != 30 || !peer.LastContact.Equal(good.LastContact) {
+		t.Errorf("Expected the quality score and last contact of the peer kept, got %+v", peer)
+	}
+	if peer := saved[backedOff.AddressPort()]; peer.DialFailures != 1 || peer.Dialable(now) {
+		t.Errorf("Expected the backoff of the peer kept, got %+v", peer)
+	}
+}
+
+func TestPeerDialable(t *testing.T) {
+	now := time.Now()
+	peer := new(Peer).Init("10.0.0.1", "8108", 0, RegularPeer, 0)
+	if !peer.Dialable(now) {
+		t.Error("Expected a new peer to be dialable")
+	}
+	peer.NextDial = now.Add(PeerDialBackoff)
+	if peer.Dialable(now) || !peer.Dialable(now.Add(PeerDialBackoff)) {
+		t.Error("Expected a backed off peer to be dialable only once its backoff is over")
+	}
+}
@@ -0,0 +1,345 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package p2p
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/FactomProject/ed25519"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// Encrypted connections.  Peers of protocol version 9 and up can run their connection
+// over TLS 1.2, each side presenting a certificate its node key vouches for, so the traffic
+// is private and the peer is known by its key.  The dialer asks for it by sending a
+// preamble before anything else; a peer that can encrypt answers with the same preamble,
+// and the TLS handshake follows.  Older peers don't answer the preamble, they just start
+// sending parcels, so with P2PEncryption "preferred" a dialer falls back to plaintext
+// with them, and a listener takes the parcels of a dialer that sent no preamble.  With
+// "required", neither does.  With "off", the default, the node speaks as older versions
+// did, and accepted connections aren't held up waiting on a preamble.
+//
+// The certificate is of an ECDSA P-256 key made when the node starts, as TLS 1.2 has no
+// ed25519 certificates.  It carries an extension holding the node's ed25519 public key
+// and its signature of the certificate's public key, which binds the two.
+
+// Values of P2PEncryption
+const (
+	EncryptionOff       = "off"
+	EncryptionPreferred = "preferred"
+	EncryptionRequired  = "required"
+)
+
+var (
+	// Encryption is whether connections are encrypted: EncryptionOff, EncryptionPreferred
+	// or EncryptionRequired.  Set by the controller.
+	Encryption = EncryptionOff
+
+	// How long a peer has to answer the preamble, and to finish the TLS handshake
+	HandshakeTimeout = 10 * time.Second
+
+	// How long a peer that didn't answer the preamble is dialed in plaintext before it
+	// is asked again
+	PlaintextPeerMemory = time.Hour
+)
+
+var errPlaintextPeer = errors.New("peer does not encrypt")
+
+// The preamble is a byte no gob stream starts with, "FCTTLS", and the protocol version
+var encryptionMagic = []byte{0xfa, 'F', 'C', 'T', 'T', 'L', 'S'}
+
+func encryptionPreamble() []byte {
+	preamble := make([]byte, len(encryptionMagic)+2)
+	copy(preamble, encryptionMagic)
+	binary.BigEndian.PutUint16(preamble[len(encryptionMagic):], ProtocolVersion)
+	return preamble
+}
+
+// ParseEncryption checks a P2PEncryption setting; empty is EncryptionOff
+func ParseEncryption(mode string) (string, error) {
+	switch mode {
+	case "":
+		return EncryptionOff, nil
+	case EncryptionOff, EncryptionPreferred, EncryptionRequired:
+		return mode, nil
+	}
+	return EncryptionOff, fmt.Errorf("unknown P2PEncryption %q, expected %s, %s or %s", mode, EncryptionRequired, EncryptionPreferred, EncryptionOff)
+}
+
+// plaintextPeers are the addresses that didn't answer the preamble, and when
+var plaintextPeers = struct {
+	sync.Mutex
+	at map[string]time.Time
+}{at: make(map[string]time.Time)}
+
+func isPlaintextPeer(address string) bool {
+	plaintextPeers.Lock()
+	defer plaintextPeers.Unlock()
+	at, ok := plaintextPeers.at[address]
+	if ok && time.Since(at) > PlaintextPeerMemory {
+		delete(plaintextPeers.at, address)
+		return false
+	}
+	return ok
+}
+
+func setPlaintextPeer(address string) {
+	plaintextPeers.Lock()
+	defer plaintextPeers.Unlock()
+	plaintextPeers.at[address] = time.Now()
+}
+
+// tlsConfig is made from the node key by the controller, nil until then
+var tlsConfig *tls.Config
+
+// The extension of a node certificate holding the node key, under the Factom enterprise
+// number
+var nodeKeyExtension = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 52316, 1, 1}
+
+// nodeKeyProof is the node key, and its signature of the certificate's public key
+type nodeKeyProof struct {
+	Key       []byte
+	Signature []byte
+}
+
+// NewTLSConfig makes the TLS config of a node with the given key: a certificate of a new
+// ECDSA key, signed by itself and vouched for by the node key, and a check that the
+// peer's certificate is one too.
+func NewTLSConfig(key *primitives.PrivateKey) (*tls.Config, error) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	spki, err := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	proof, err := asn1.Marshal(nodeKeyProof{Key: key.Pub[:], Signature: ed25519.Sign(key.Key, spki)[:]})
+	if err != nil {
+		return nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber:    big.NewInt(1),
+		NotBefore:       time.Now().Add(-time.Hour),
+		NotAfter:        time.Now().Add(10 * 365 * 24 * time.Hour),
+		KeyUsage:        x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		ExtraExtensions: []pkix.Extension{{Id: nodeKeyExtension, Value: proof}},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &priv.PublicKey, priv)
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{
+		Certificates:          []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: priv}},
+		MinVersion:            tls.VersionTLS12,
+		ClientAuth:            tls.RequireAnyClientCert,
+		InsecureSkipVerify:    true, // There is no authority; peers are known by their keys
+		VerifyPeerCertificate: verifyNodeCertificate,
+	}, nil
+}
+
+// certificateNodeKey returns the node key that vouches for a certificate, checking its
+// signature of the certificate's public key
+func certificateNodeKey(cert *x509.Certificate) ([]byte, error) {
+	for _, ext := range cert.Extensions {
+		if !ext.Id.Equal(nodeKeyExtension) {
+			continue
+		}
+		var proof nodeKeyProof
+		if _, err := asn1.Unmarshal(ext.Value, &proof); err != nil {
+			return nil, err
+		}
+		if len(proof.Key) != ed25519.PublicKeySize || len(proof.Signature) != ed25519.SignatureSize {
+			return nil, fmt.Errorf("malformed node key")
+		}
+		var pub [ed25519.PublicKeySize]byte
+		var sig [ed25519.SignatureSize]byte
+		copy(pub[:], proof.Key)
+		copy(sig[:], proof.Signature)
+		if !ed25519.Verify(&pub, cert.RawSubjectPublicKeyInfo, &sig) {
+			return nil, fmt.Errorf("node key did not sign the certificate key")
+		}
+		return proof.Key, nil
+	}
+	return nil, fmt.Errorf("certificate has no node key")
+}
+
+// verifyNodeCertificate checks that the peer's certificate is signed by itself, and
+// vouched for by a node key
+func verifyNodeCertificate(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+	if len(rawCerts) != 1 {
+		return fmt.Errorf("expected one certificate, got %d", len(rawCerts))
+	}
+	cert, err := x509.ParseCertificate(rawCerts[0])
+	if err != nil {
+		return err
+	}
+	if err := cert.CheckSignatureFrom(cert); err != nil {
+		return err
+	}
+	_, err = certificateNodeKey(cert)
+	return err
+}
+
+// PeerNodeKey returns the node key the peer of an encrypted connection presented, or nil
+// if the connection isn't encrypted
+func PeerNodeKey(conn net.Conn) []byte {
+	tc, ok := conn.(*tls.Conn)
+	if !ok {
+		return nil
+	}
+	certs := tc.ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return nil
+	}
+	key, err := certificateNodeKey(certs[0])
+	if err != nil {
+		return nil
+	}
+	return key
+}
+
+// peerKey returns the hex of the node key the peer of an encrypted connection presented,
+// or "" if the connection isn't encrypted
+func peerKey(conn net.Conn) string {
+	key := PeerNodeKey(conn)
+	if key == nil {
+		return ""
+	}
+	return hex.EncodeToString(key)
+}
+
+// SecureClient asks the peer a dialed connection is to to encrypt it, returning the
+// encrypted connection.  If the peer doesn't answer the preamble, the connection is
+// closed and errPlaintextPeer returned.
+func SecureClient(conn net.Conn, config *tls.Config) (net.Conn, error) {
+	conn.SetDeadline(time.Now().Add(HandshakeTimeout))
+	preamble := encryptionPreamble()
+	answer := make([]byte, len(preamble))
+	_, err := conn.Write(preamble)
+	if err == nil {
+		_, err = io.ReadFull(conn, answer)
+	}
+	if err != nil || !bytes.HasPrefix(answer, encryptionMagic) {
+		conn.Close()
+		p2pHandshakes.WithLabelValues("plaintext").Inc()
+		return nil, errPlaintextPeer
+	}
+	tc := tls.Client(conn, config)
+	if err := tc.Handshake(); err != nil {
+		conn.Close()
+		p2pHandshakes.WithLabelValues("failed").Inc()
+		return nil, err
+	}
+	conn.SetDeadline(time.Time{})
+	p2pHandshakes.WithLabelValues("encrypted").Inc()
+	return tc, nil
+}
+
+// SecureServer encrypts an accepted connection if the dialer sent the preamble.  Without
+// it, the connection is returned as it is, the bytes read put back, unless required.
+func SecureServer(conn net.Conn, config *tls.Config, required bool) (net.Conn, error) {
+	conn.SetDeadline(time.Now().Add(HandshakeTimeout))
+	preamble := encryptionPreamble()
+	first := make([]byte, len(preamble))
+	n, err := io.ReadFull(conn, first)
+	if err != nil || !bytes.HasPrefix(first, encryptionMagic) {
+		if required || (err != nil && n == 0) {
+			conn.Close()
+			p2pHandshakes.WithLabelValues("refused").Inc()
+			return nil, errPlaintextPeer
+		}
+		conn.SetDeadline(time.Time{})
+		p2pHandshakes.WithLabelValues("plaintext").Inc()
+		return &replayConn{Conn: conn, buffered: first[:n]}, nil
+	}
+	if _, err := conn.Write(preamble); err != nil {
+		conn.Close()
+		p2pHandshakes.WithLabelValues("failed").Inc()
+		return nil, err
+	}
+	tc := tls.Server(conn, config)
+	if err := tc.Handshake(); err != nil {
+		conn.Close()
+		p2pHandshakes.WithLabelValues("failed").Inc()
+		return nil, err
+	}
+	conn.SetDeadline(time.Time{})
+	p2pHandshakes.WithLabelValues("encrypted").Inc()
+	return tc, nil
+}
+
+// replayConn is a connection that gives back the bytes read from it to look for the
+// preamble before reading any more
+type replayConn struct {
+	net.Conn
+	buffered []byte
+}
+
+func (c *replayConn) Read(p []byte) (int, error) {
+	if len(c.buffered) > 0 {
+		n := copy(p, c.buffered)
+		c.buffered = c.buffered[n:]
+		return n, nil
+	}
+	return c.Conn.Read(p)
+}
+
+// secureDialed encrypts a connection we dialed as Encryption says, falling back to
+// plaintext with a peer that doesn't encrypt if we may.  Returns nil if the peer can't
+// be talked to.
+func secureDialed(conn net.Conn, address string, redial func() (net.Conn, error)) net.Conn {
+	if Encryption == EncryptionOff || tlsConfig == nil {
+		return conn
+	}
+	if Encryption == EncryptionPreferred && isPlaintextPeer(address) {
+		return conn
+	}
+	secure, err := SecureClient(conn, tlsConfig)
+	switch {
+	case err == nil:
+		return secure
+	case err == errPlaintextPeer && Encryption == EncryptionPreferred:
+		setPlaintextPeer(address)
+		plain, err := redial()
+		if err != nil {
+			return nil
+		}
+		return plain
+	}
+	note(address, "secureDialed() not connecting: %v", err)
+	return nil
+}
+
+// secureAccepted encrypts a connection a peer dialed as Encryption says.  Returns nil if
+// the peer can't be talked to.
+func secureAccepted(conn net.Conn) net.Conn {
+	if Encryption == EncryptionOff || tlsConfig == nil {
+		return conn
+	}
+	secure, err := SecureServer(conn, tlsConfig, Encryption == EncryptionRequired)
+	if err != nil {
+		note("ctrlr", "secureAccepted() not accepting %s: %v", conn.RemoteAddr(), err)
+		return nil
+	}
+	return secure
+}
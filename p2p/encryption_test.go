@@ -0,0 +1,80 @@
+package p2p_test
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/FactomProject/factomd/common/primitives"
+	. "github.com/FactomProject/factomd/p2p"
+)
+
+func TestSecureConnection(t *testing.T) {
+	clientKey := primitives.RandomPrivateKey()
+	serverKey := primitives.RandomPrivateKey()
+	clientConfig, err := NewTLSConfig(clientKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serverConfig, err := NewTLSConfig(serverKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, b := net.Pipe()
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		conn, err := SecureServer(b, serverConfig, true)
+		if err != nil {
+			t.Error(err)
+		}
+		accepted <- conn
+	}()
+	client, err := SecureClient(a, clientConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := <-accepted
+	if server == nil {
+		t.FailNow()
+	}
+
+	if !bytes.Equal(PeerNodeKey(client), serverKey.Pub[:]) {
+		t.Error("Client did not see the server's key")
+	}
+	if !bytes.Equal(PeerNodeKey(server), clientKey.Pub[:]) {
+		t.Error("Server did not see the client's key")
+	}
+	go client.Write([]byte("parcel"))
+	got := make([]byte, 6)
+	if _, err := io.ReadFull(server, got); err != nil || string(got) != "parcel" {
+		t.Errorf("Read %q, %v", got, err)
+	}
+}
+
+func TestPlaintextDialer(t *testing.T) {
+	config, err := NewTLSConfig(primitives.RandomPrivateKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// An older peer starts with its parcels; a listener that prefers encryption takes them
+	a, b := net.Pipe()
+	go a.Write([]byte("an older peer's parcel"))
+	conn, err := SecureServer(b, config, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make([]byte, 22)
+	if _, err := io.ReadFull(conn, got); err != nil || string(got) != "an older peer's parcel" {
+		t.Errorf("Read %q, %v", got, err)
+	}
+
+	// One that requires it hangs up
+	a, b = net.Pipe()
+	go a.Write([]byte("an older peer's parcel"))
+	if _, err := SecureServer(b, config, true); err == nil {
+		t.Error("Accepted a plaintext peer when encryption is required")
+	}
+}
@@ -91,6 +91,46 @@ var (
 		Name: "factomd_p2p_goOffline_total",
 		Help: "Number of times we call goOffline()",
 	})
+
+	//
+	// Admission control
+	p2pAdmissionRejected = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "factomd_p2p_admission_rejected_total",
+		Help: "Number of application messages from peers dropped by admission control, by reason",
+	}, []string{"reason"})
+
+	//
+	// Peer announcements
+	p2pAnnouncements = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "factomd_p2p_peer_announcements_total",
+		Help: "Number of signed announcements from peers, by whether they were accepted, moved a peer to a new address, or rejected",
+	}, []string{"result"})
+
+	//
+	// Compression
+	p2pCompressedBytes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "factomd_p2p_compressed_payload_bytes_total",
+		Help: "Bytes of the payloads compressed for peers, before (plain) and after (compressed)",
+	}, []string{"size"})
+
+	//
+	// Peer reputation
+	p2pPeerOffenses = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "factomd_p2p_peer_offenses_total",
+		Help: "Number of offenses peers were scored for, by offense",
+	}, []string{"offense"})
+
+	p2pPeerStandings = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "factomd_p2p_peer_standing_changes_total",
+		Help: "Number of times a peer's reputation moved it to a standing, by standing",
+	}, []string{"standing"})
+
+	//
+	// Encryption
+	p2pHandshakes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "factomd_p2p_handshakes_total",
+		Help: "Number of connections opened, by whether they were encrypted, left in plaintext, refused for not encrypting, or failed the handshake",
+	}, []string{"result"})
 )
 
 var registered = false
@@ -127,4 +167,17 @@ func RegisterPrometheus() {
 	// Connections
 	prometheus.MustRegister(p2pConnectionCommonInit)
 
+	// Admission control
+	prometheus.MustRegister(p2pAdmissionRejected)
+
+	// Peer announcements
+	prometheus.MustRegister(p2pAnnouncements)
+
+	prometheus.MustRegister(p2pCompressedBytes)
+
+	// Peer reputation
+	prometheus.MustRegister(p2pPeerOffenses)
+	prometheus.MustRegister(p2pPeerStandings)
+	prometheus.MustRegister(p2pHandshakes)
+
 }
@@ -36,6 +36,11 @@ type ParcelHeader struct {
 	PeerPort    string // port of the peer , or we are listening on
 	AppHash     string // Application specific message hash, for tracing
 	AppType     string // Application specific message type, for tracing
+	Stamp       uint64 `json:",omitempty"` // Proof of work over the payload, required by some peers on costly requests
+	NonRoutable bool   `json:",omitempty"` // The sender takes no part in consensus, so leave it out of broadcasts
+
+	Capabilities uint32 `json:",omitempty"` // What the sender can take, such as compressed payloads
+	Encoding     uint8  `json:",omitempty"` // How the payload is encoded, EncodingPlain unless compressed
 }
 
 type ParcelCommandType uint16
@@ -50,6 +55,7 @@ const ( // iota is reset to 0
 	TypeAlert                                 // network wide alerts (used in bitcoin to indicate criticalities)
 	TypeMessage                               // Application level message
 	TypeMessagePart                           // Application level message that was split into multiple parts
+	TypePeerAnnounce                          // "This is who I am, wherever I am now."
 )
 
 // CommandStrings is a Map of command ids to strings for easy printing of network comands
@@ -62,6 +68,7 @@ var CommandStrings = map[ParcelCommandType]string{
 	TypeAlert:        "Alert",         // network wide alerts (used in bitcoin to indicate criticalities)
 	TypeMessage:      "Message",       // Application level message
 	TypeMessagePart:  "MessagePart",   // Application level message that was split into multiple parts
+	TypePeerAnnounce: "Peer-Announce", // "This is who I am, wherever I am now."
 }
 
 // MaxPayloadSize is the maximum bytes a message can be at the networking level.
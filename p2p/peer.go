@@ -26,6 +26,9 @@ type Peer struct {
 	Connections  int                  // Number of successful connections.
 	LastContact  time.Time            // Keep track of how long ago we talked to the peer.
 	Source       map[string]time.Time // source where we heard from the peer.
+	PublicKey    string               `json:",omitempty"` // Key the peer announced itself with, only learned from the peer itself
+	DialFailures int                  `json:",omitempty"` // Failed dials in a row
+	NextDial     time.Time            // Not dialed before this, after a failed dial
 }
 
 const ( // iota is reset to 0
@@ -95,6 +98,28 @@ func (p *Peer) LocationFromAddress() (location uint32) {
 	return location
 }
 
+// dialFailed backs off dialing the peer again, for PeerDialBackoff doubled for each
+// failure in a row, up to PeerDialBackoffMax
+func (p *Peer) dialFailed(now time.Time) {
+	p.DialFailures++
+	backoff := PeerDialBackoffMax
+	if p.DialFailures < 32 && PeerDialBackoff<<uint(p.DialFailures-1) < PeerDialBackoffMax {
+		backoff = PeerDialBackoff << uint(p.DialFailures-1)
+	}
+	p.NextDial = now.Add(backoff)
+}
+
+// dialSucceeded clears the backoff of the peer
+func (p *Peer) dialSucceeded() {
+	p.DialFailures = 0
+	p.NextDial = time.Time{}
+}
+
+// Dialable is true if the peer isn't backed off at now
+func (p *Peer) Dialable(now time.Time) bool {
+	return !now.Before(p.NextDial)
+}
+
 // merit increases a peers reputation
 func (p *Peer) merit() {
 	if 2147483000 > p.QualityScore {
@@ -0,0 +1,162 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package p2p
+
+import (
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"math/rand"
+	"net"
+	"sync"
+	"time"
+)
+
+// A probe is a connection to a single peer made without a controller, so a tool can
+// speak the protocol one parcel at a time and see exactly what the peer sends back (ie
+// factomd p2p-probe).  Every parcel sent and received is kept, in order.
+
+// ErrProbeTimeout is returned by Receive when nothing arrived in time
+var ErrProbeTimeout = errors.New("timed out waiting for the peer")
+
+// CapturedParcel is a parcel sent to or received from a probed peer
+type CapturedParcel struct {
+	Sent    bool          // False if it was received
+	At      time.Duration // Since the connection was made
+	Header  ParcelHeader
+	Payload []byte
+}
+
+type Probe struct {
+	Address     string
+	Network     NetworkID
+	ConnectTime time.Duration // How long the TCP connection took
+
+	conn     net.Conn
+	encoder  *gob.Encoder
+	start    time.Time
+	received chan *Parcel
+	err      error // Why the peer stopped sending, once received is closed
+
+	mutex   sync.Mutex
+	capture []CapturedParcel
+}
+
+// DialProbe connects to the peer at address, on the given network
+func DialProbe(address string, network NetworkID, timeout time.Duration) (*Probe, error) {
+	if NodeID == 0 {
+		NodeID = uint64(rand.New(rand.NewSource(time.Now().UnixNano())).Int63())
+	}
+	start := time.Now()
+	conn, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		return nil, err
+	}
+	p := new(Probe)
+	p.Address = address
+	p.Network = network
+	p.ConnectTime = time.Since(start)
+	p.conn = conn
+	p.encoder = gob.NewEncoder(conn)
+	p.start = time.Now()
+	p.received = make(chan *Parcel, StandardChannelSize)
+	go p.receive(gob.NewDecoder(conn))
+	return p, nil
+}
+
+func (p *Probe) receive(decoder *gob.Decoder) {
+	defer close(p.received)
+	for {
+		parcel := new(Parcel)
+		if err := decoder.Decode(parcel); err != nil {
+			p.err = err
+			return
+		}
+		p.record(false, parcel)
+		p.received <- parcel
+	}
+}
+
+func (p *Probe) record(sent bool, parcel *Parcel) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.capture = append(p.capture, CapturedParcel{Sent: sent, At: p.Elapsed(), Header: parcel.Header, Payload: parcel.Payload})
+}
+
+// Elapsed is the time since the connection was made
+func (p *Probe) Elapsed() time.Duration {
+	return time.Since(p.start)
+}
+
+// Send sends a parcel to the peer, as a connection would: with our node ID, and stamped if
+// it is a costly request
+func (p *Probe) Send(parcel *Parcel) error {
+	parcel.Header.NodeID = NodeID
+	StampParcel(parcel)
+	p.conn.SetWriteDeadline(time.Now().Add(NetworkDeadline))
+	if err := p.encoder.Encode(parcel); err != nil {
+		return err
+	}
+	p.record(true, parcel)
+	return nil
+}
+
+// Receive returns the next parcel from the peer, waiting up to timeout for it
+func (p *Probe) Receive(timeout time.Duration) (*Parcel, error) {
+	select {
+	case parcel, ok := <-p.received:
+		if !ok {
+			return nil, fmt.Errorf("connection closed: %v", p.err)
+		}
+		return parcel, nil
+	case <-time.After(timeout):
+		return nil, ErrProbeTimeout
+	}
+}
+
+// Capture returns every parcel sent and received so far
+func (p *Probe) Capture() []CapturedParcel {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return append([]CapturedParcel(nil), p.capture...)
+}
+
+func (p *Probe) Close() {
+	p.conn.Close()
+}
+
+// ParcelProblems lists the ways a parcel from a peer breaks the protocol: the checks a
+// connection makes before taking a parcel, and the header fields a peer should set
+func ParcelProblems(parcel *Parcel, network NetworkID) (problems []string) {
+	h := parcel.Header
+	if h.Network != network {
+		problems = append(problems, fmt.Sprintf("network %x, expected %x", uint32(h.Network), uint32(network)))
+	}
+	if h.Version < ProtocolVersionMinimum {
+		problems = append(problems, fmt.Sprintf("protocol version %d is below the minimum of %d", h.Version, ProtocolVersionMinimum))
+	}
+	if h.Length != uint32(len(parcel.Payload)) {
+		problems = append(problems, fmt.Sprintf("length %d, but the payload is %d bytes", h.Length, len(parcel.Payload)))
+	}
+	if h.Crc32 != crc32.Checksum(parcel.Payload, CRCKoopmanTable) {
+		problems = append(problems, "checksum does not match the payload")
+	}
+	if _, ok := CommandStrings[h.Type]; !ok {
+		problems = append(problems, fmt.Sprintf("unknown parcel type %d", h.Type))
+	}
+	if h.NodeID == 0 {
+		problems = append(problems, "no node ID")
+	} else if h.NodeID == NodeID {
+		problems = append(problems, "our own node ID (loopback)")
+	}
+	if h.PeerPort == "" {
+		problems = append(problems, "no listening port")
+	}
+	if h.Type == TypeMessagePart && (h.PartsTotal == 0 || h.PartNo >= h.PartsTotal) {
+		problems = append(problems, fmt.Sprintf("part %d of %d", h.PartNo, h.PartsTotal))
+	}
+	return problems
+}
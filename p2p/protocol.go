@@ -54,6 +54,7 @@ var (
 	BannedQualityScore            int32  = -2147000000 // Used to ban a peer
 	MinumumSharingQualityScore    int32  = 20          // if a peer's score is less than this we don't share them.
 	OnlySpecialPeers                     = false
+	NonRoutable                          = false // Tell our peers to leave us out of their broadcasts
 	NetworkDeadline                      = time.Duration(30) * time.Second
 	NumberPeersToConnect                 = 32
 	NumberPeersToBroadcast               = 100
@@ -64,10 +65,19 @@ var (
 	ConnectionStatusInterval             = time.Second * 122
 	PingInterval                         = time.Second * 15
 	TimeBetweenRedials                   = time.Second * 20
+	PeerDialBackoff                      = time.Second * 20 // Wait before dialing a peer that failed again, doubled for each failure in a row
+	PeerDialBackoffMax                   = time.Hour * 4
+	MaxPeerDialFailures                  = 12 // Failures in a row before a peer is no longer saved
 	PeerSaveInterval                     = time.Second * 30
+	ScheduledPeerSaves                   = false // The application saves the peers, with SavePeers()
 	PeerRequestInterval                  = time.Second * 180
 	PeerDiscoveryInterval                = time.Hour * 4
 
+	// Asked of each peer picked for a message to a random peer; those it is true of are
+	// passed over if another peer will do.  The application sets it to pass over peers
+	// that told it they are overloaded.
+	PassOverPeer func(peerHash string) bool
+
 	// Testing metrics
 	TotalMessagesRecieved       uint64
 	TotalMessagesSent           uint64
@@ -80,7 +90,7 @@ var (
 
 const (
 	// ProtocolVersion is the latest version this package supports
-	ProtocolVersion uint16 = 8
+	ProtocolVersion uint16 = 9 // 9 can encrypt connections
 	// ProtocolVersionMinimum is the earliest version this package supports
 	ProtocolVersionMinimum uint16 = 8
 )
@@ -0,0 +1,318 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package p2p
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+)
+
+// Peer reputation.  The quality score rewards a peer for its traffic; reputation keeps
+// score of how it misbehaves: sending invalid messages, sending us the same message over
+// and over, leaving requests unanswered, and sending malformed parcels.  Each offense
+// costs penalty points, which fade with time.  A regular peer with too many is demoted,
+// and passed over for requests; one with far too many is banned.  Special peers are
+// scored, but never demoted or banned.
+
+// Offense is a kind of misbehaviour a peer is scored for
+type Offense uint8
+
+const (
+	OffenseInvalid   Offense = iota // A message that failed validation
+	OffenseDuplicate                // The same message again, past ReputationDuplicateRate
+	OffenseSlow                     // A request left unanswered for ReputationResponseTimeout
+	OffenseProtocol                 // A malformed parcel
+	OffenseOversized                // A message over the size its type is capped at
+	numOffenses
+)
+
+var offenseNames = [numOffenses]string{"invalid", "duplicate", "slow", "protocol", "oversized"}
+
+func (o Offense) String() string {
+	if o < numOffenses {
+		return offenseNames[o]
+	}
+	return fmt.Sprintf("offense-%d", o)
+}
+
+// Standings of a peer
+const (
+	StandingGood    = "good"
+	StandingDemoted = "demoted" // Passed over for requests
+	StandingBanned  = "banned"  // Disconnected
+)
+
+var (
+	// Penalty points of each offense
+	ReputationPenalties = [numOffenses]float64{
+		OffenseInvalid:   5,
+		OffenseDuplicate: 1,
+		OffenseSlow:      1,
+		OffenseProtocol:  50,
+		OffenseOversized: 20,
+	}
+	ReputationHalfLife         = 10 * time.Minute // Penalty points halve in this time
+	ReputationDemoteScore      = 100.0            // A peer with this many points is demoted
+	ReputationBanScore         = 1000.0           // A peer with this many points is banned
+	ReputationDuplicateRate    = 10.0             // Repeats a second a peer may send before they count
+	ReputationResponseTimeout  = 10 * time.Second // A request unanswered for this long counts as slow
+	ReputationRecentMessages   = 4096             // Messages remembered from each peer, to notice repeats
+	ReputationMaxPendingByType = 64               // Requests of a type awaiting an answer from a peer
+
+	// The AppType of the answer expected to requests of each AppType.  A peer sent a
+	// request is expected to answer it in time.  Set by the application.
+	ResponseAppTypes = map[string]string{}
+)
+
+// peerReputation is the record of one peer
+type peerReputation struct {
+	address     string
+	special     bool
+	score       float64
+	updated     time.Time // When the score last faded
+	stand       string    // Standing as of the last update
+	offenses    [numOffenses]int
+	lastOffense time.Time
+
+	// Messages recently received, to notice a peer repeating itself
+	recent     map[string]bool
+	recentRing []string
+	next       int
+	repeats    rateLimiter
+
+	pending map[string][]time.Time // When requests were sent, by the AppType of their answer
+}
+
+// fade lowers the score for the time passed since it was last lowered
+func (p *peerReputation) fade(now time.Time) {
+	if !p.updated.IsZero() && ReputationHalfLife > 0 && now.After(p.updated) {
+		p.score *= math.Pow(0.5, float64(now.Sub(p.updated))/float64(ReputationHalfLife))
+	}
+	p.updated = now
+	p.stand = p.standing()
+}
+
+// standing returns the standing the score gives the peer.  A peer keeps its standing
+// until its score is well below what it took to get it, so it doesn't flap.
+func (p *peerReputation) standing() string {
+	switch {
+	case p.special:
+		return StandingGood
+	case p.score >= ReputationBanScore:
+		return StandingBanned
+	case p.stand == StandingBanned && p.score >= ReputationDemoteScore:
+		return StandingBanned
+	case p.score >= ReputationDemoteScore:
+		return StandingDemoted
+	case p.stand != StandingGood && p.stand != "" && p.score >= ReputationDemoteScore/2:
+		return StandingDemoted
+	}
+	return StandingGood
+}
+
+// repeated records a message received, returning true if the peer sent it recently
+func (p *peerReputation) repeated(key string) bool {
+	if p.recent[key] {
+		return true
+	}
+	if p.recent == nil {
+		p.recent = make(map[string]bool)
+		p.recentRing = make([]string, ReputationRecentMessages)
+	}
+	if len(p.recentRing) == 0 {
+		return false
+	}
+	delete(p.recent, p.recentRing[p.next])
+	p.recentRing[p.next] = key
+	p.recent[key] = true
+	p.next = (p.next + 1) % len(p.recentRing)
+	return false
+}
+
+// StandingChange is a peer moved from one standing to another by its reputation
+type StandingChange struct {
+	PeerHash string
+	From     string
+	To       string
+}
+
+// Reputations keeps the reputation of each peer.  Safe for concurrent use.
+type Reputations struct {
+	mutex sync.Mutex
+	peers map[string]*peerReputation
+}
+
+func NewReputations() *Reputations {
+	return &Reputations{peers: make(map[string]*peerReputation)}
+}
+
+func (r *Reputations) get(peerHash string) *peerReputation {
+	p, ok := r.peers[peerHash]
+	if !ok {
+		p = new(peerReputation)
+		r.peers[peerHash] = p
+	}
+	return p
+}
+
+// score charges a peer for an offense, returning the change of standing if any
+func (r *Reputations) score(peerHash string, p *peerReputation, offense Offense, now time.Time) *StandingChange {
+	p.fade(now)
+	from := p.stand
+	if offense < numOffenses {
+		p.score += ReputationPenalties[offense]
+		p.offenses[offense]++
+	}
+	p.lastOffense = now
+	p2pPeerOffenses.WithLabelValues(offense.String()).Inc()
+	p.stand = p.standing()
+	if to := p.stand; to != from {
+		p2pPeerStandings.WithLabelValues(to).Inc()
+		return &StandingChange{PeerHash: peerHash, From: from, To: to}
+	}
+	return nil
+}
+
+// Identify records the address of a peer, and whether it is special
+func (r *Reputations) Identify(peerHash string, address string, special bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	p := r.get(peerHash)
+	p.address = address
+	p.special = special
+}
+
+// Report charges a peer for an offense, returning the change of standing if any
+func (r *Reputations) Report(peerHash string, offense Offense, now time.Time) *StandingChange {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.score(peerHash, r.get(peerHash), offense, now)
+}
+
+// Sent records a parcel sent to a peer, expecting an answer if it is a request
+func (r *Reputations) Sent(peerHash string, appType string, now time.Time) {
+	answer, ok := ResponseAppTypes[appType]
+	if !ok {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	p := r.get(peerHash)
+	if p.pending == nil {
+		p.pending = make(map[string][]time.Time)
+	}
+	if len(p.pending[answer]) < ReputationMaxPendingByType {
+		p.pending[answer] = append(p.pending[answer], now)
+	}
+}
+
+// Received records an application parcel from a peer: it answers the oldest request of
+// its kind sent to the peer, and if the peer sent it recently, the peer is repeating
+// itself.  Returns the change of standing if any.
+func (r *Reputations) Received(peerHash string, header ParcelHeader, now time.Time) *StandingChange {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	p := r.get(peerHash)
+	if sent := p.pending[header.AppType]; len(sent) > 0 {
+		p.pending[header.AppType] = sent[1:]
+	}
+	key := fmt.Sprintf("%s/%d", header.AppHash, header.PartNo)
+	if !p.repeated(key) || p.repeats.allow(ReputationDuplicateRate, now) {
+		return nil
+	}
+	return r.score(peerHash, p, OffenseDuplicate, now)
+}
+
+// Expire charges the peers with requests left unanswered too long, and forgets the
+// peers with a clean record not in keep.  Returns the changes of standing.
+func (r *Reputations) Expire(keep map[string]bool, now time.Time) []StandingChange {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var changes []StandingChange
+	for peerHash, p := range r.peers {
+		for answer, sent := range p.pending {
+			for len(sent) > 0 && now.Sub(sent[0]) > ReputationResponseTimeout {
+				sent = sent[1:]
+				if change := r.score(peerHash, p, OffenseSlow, now); change != nil {
+					changes = append(changes, *change)
+				}
+			}
+			p.pending[answer] = sent
+		}
+
+		if keep[peerHash] {
+			continue
+		}
+		p.fade(now)
+		if p.score < 1 {
+			delete(r.peers, peerHash)
+		} else {
+			p.pending = nil
+		}
+	}
+	return changes
+}
+
+// Standing returns the standing of a peer
+func (r *Reputations) Standing(peerHash string) string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	p, ok := r.peers[peerHash]
+	if !ok {
+		return StandingGood
+	}
+	p.fade(time.Now())
+	return p.stand
+}
+
+// Get returns the reputations of the peers, worst first
+func (r *Reputations) Get() []interfaces.PeerReputation {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	now := time.Now()
+	list := make([]interfaces.PeerReputation, 0, len(r.peers))
+	for peerHash, p := range r.peers {
+		p.fade(now)
+		rep := interfaces.PeerReputation{
+			PeerHash: peerHash,
+			Address:  p.address,
+			Special:  p.special,
+			Score:    p.score,
+			Standing: p.stand,
+			Offenses: make(map[string]int),
+		}
+		for o, n := range p.offenses {
+			if n > 0 {
+				rep.Offenses[Offense(o).String()] = n
+			}
+		}
+		if !p.lastOffense.IsZero() {
+			rep.LastOffense = p.lastOffense.Unix()
+		}
+		for _, sent := range p.pending {
+			rep.Pending += len(sent)
+		}
+		list = append(list, rep)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Score != list[j].Score {
+			return list[i].Score > list[j].Score
+		}
+		return list[i].PeerHash < list[j].PeerHash
+	})
+	return list
+}
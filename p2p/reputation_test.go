@@ -0,0 +1,103 @@
+package p2p_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/FactomProject/factomd/p2p"
+)
+
+func TestReputationStanding(t *testing.T) {
+	r := NewReputations()
+	now := time.Now()
+	r.Identify("bad", "10.0.0.1", false)
+	r.Identify("special", "10.0.0.2", true)
+
+	var change *StandingChange
+	for i := 0; change == nil; i++ {
+		if i > 100 {
+			t.Fatal("Never demoted a peer sending malformed parcels")
+		}
+		change = r.Report("bad", OffenseProtocol, now)
+		r.Report("special", OffenseProtocol, now)
+	}
+	if change.From != StandingGood || change.To != StandingDemoted {
+		t.Errorf("Moved from %s to %s, expected demotion", change.From, change.To)
+	}
+	for change == nil || change.To != StandingBanned {
+		change = r.Report("bad", OffenseProtocol, now)
+	}
+	if r.Standing("special") != StandingGood {
+		t.Errorf("Special peer was %s", r.Standing("special"))
+	}
+
+	reps := r.Get()
+	if len(reps) != 2 || reps[0].PeerHash != "bad" || reps[0].Standing != StandingBanned {
+		t.Fatalf("Unexpected reputations %+v", reps)
+	}
+	if reps[0].Offenses["protocol"] == 0 || reps[0].Address != "10.0.0.1" {
+		t.Errorf("Unexpected reputation %+v", reps[0])
+	}
+
+	// Penalties fade, so the peer is forgiven in time
+	r.Expire(nil, now.Add(20*ReputationHalfLife))
+	if len(r.Get()) != 0 {
+		t.Errorf("Kept the records of disconnected peers with a clean record: %+v", r.Get())
+	}
+}
+
+func TestReputationDuplicates(t *testing.T) {
+	r := NewReputations()
+	now := time.Now()
+	header := ParcelHeader{AppHash: "abc", AppType: "1"}
+
+	// A message sent again now and then, as when asked for, costs nothing
+	for i := 0; i < int(ReputationDuplicateRate); i++ {
+		r.Received("peer", header, now)
+	}
+	if r.Get()[0].Score != 0 {
+		t.Errorf("Charged for %d repeats, within the allowance", int(ReputationDuplicateRate))
+	}
+	// A flood of it does
+	for i := 0; i < 10*int(ReputationDuplicateRate); i++ {
+		r.Received("peer", header, now)
+	}
+	if r.Get()[0].Offenses["duplicate"] == 0 {
+		t.Errorf("Flood of repeats went unnoticed")
+	}
+
+	// Parts of one message are not repeats of each other
+	before := r.Get()[0].Offenses["duplicate"]
+	for part := uint16(0); part < 100; part++ {
+		r.Received("peer", ParcelHeader{AppHash: "big", PartNo: part}, now)
+	}
+	if r.Get()[0].Offenses["duplicate"] != before {
+		t.Errorf("Parts of a message were taken for repeats")
+	}
+}
+
+func TestReputationUnansweredRequests(t *testing.T) {
+	ResponseAppTypes["16"] = "17"
+	defer delete(ResponseAppTypes, "16")
+
+	r := NewReputations()
+	now := time.Now()
+	r.Sent("quick", "16", now)
+	r.Sent("slow", "16", now)
+	r.Sent("slow", "3", now) // Not a request
+	r.Received("quick", ParcelHeader{AppHash: "answer", AppType: "17"}, now.Add(time.Second))
+
+	keep := map[string]bool{"quick": true, "slow": true}
+	r.Expire(keep, now.Add(ReputationResponseTimeout/2))
+	for _, rep := range r.Get() {
+		if rep.Offenses["slow"] != 0 {
+			t.Errorf("%s charged before the timeout", rep.PeerHash)
+		}
+	}
+	r.Expire(keep, now.Add(2*ReputationResponseTimeout))
+	for _, rep := range r.Get() {
+		if slow := rep.Offenses["slow"]; (rep.PeerHash == "slow") != (slow == 1) || rep.Pending != 0 {
+			t.Errorf("%s charged for %d unanswered requests, %d pending", rep.PeerHash, slow, rep.Pending)
+		}
+	}
+}
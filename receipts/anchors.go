@@ -0,0 +1,140 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package receipts
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/FactomProject/factomd/anchor"
+	"github.com/FactomProject/factomd/common/directoryBlock/dbInfo"
+	"github.com/FactomProject/factomd/common/entryBlock"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+const (
+	AnchorBitcoin  = "bitcoin"
+	AnchorEthereum = "ethereum"
+)
+
+// Anchor is a transaction anchoring the directory block of a receipt into another
+// blockchain.  Data is what the transaction carries: for Bitcoin the OP_RETURN, for
+// Ethereum the call data of the anchor contract.  It is made from the DBHeight and the
+// KeyMR, so an auditor can check that the transaction in the block carries it without
+// trusting the node.
+type Anchor struct {
+	Blockchain  string `json:"blockchain"`
+	DBHeight    uint32 `json:"dbheight"`
+	Address     string `json:"address,omitempty"` // Ethereum anchor contract
+	TXID        string `json:"txid"`
+	BlockHeight int64  `json:"blockheight"`
+	BlockHash   string `json:"blockhash"`
+	Offset      int64  `json:"offset"`
+	Data        string `json:"data"`
+}
+
+// AnchorData returns what the anchor transaction of a directory block carries on a blockchain
+func AnchorData(blockchain string, dbHeight uint32, keyMR interfaces.IHash) ([]byte, error) {
+	switch blockchain {
+	case AnchorBitcoin:
+		return anchor.BitcoinAnchorData(dbHeight, keyMR), nil
+	case AnchorEthereum:
+		return anchor.SetAnchorData(dbHeight, keyMR), nil
+	}
+	return nil, fmt.Errorf("Unknown blockchain %q", blockchain)
+}
+
+// Validate checks the anchor carries the KeyMR of the directory block
+func (a *Anchor) Validate(keyMR interfaces.IHash) error {
+	data, err := AnchorData(a.Blockchain, a.DBHeight, keyMR)
+	if err != nil {
+		return err
+	}
+	if a.Data != hex.EncodeToString(data) {
+		return fmt.Errorf("%s anchor %s does not carry the DirectoryBlockKeyMR", a.Blockchain, a.TXID)
+	}
+	return nil
+}
+
+// fetchAnchors returns the anchors we know of for a directory block
+func fetchAnchors(dbo interfaces.DBOverlaySimple, dBlock interfaces.IDirectoryBlock, dirBlockInfo interfaces.IDirBlockInfo) ([]*Anchor, error) {
+	keyMR := dBlock.DatabasePrimaryIndex()
+	height := dBlock.GetDatabaseHeight()
+	var anchors []*Anchor
+
+	if dbi, ok := dirBlockInfo.(*dbInfo.DirBlockInfo); ok && dbi.BTCTxHash != nil && !dbi.BTCTxHash.IsZero() {
+		a := new(Anchor)
+		a.Blockchain = AnchorBitcoin
+		a.DBHeight = height
+		a.TXID = dbi.BTCTxHash.String()
+		a.BlockHeight = int64(dbi.BTCBlockHeight)
+		if dbi.BTCBlockHash != nil {
+			a.BlockHash = dbi.BTCBlockHash.String()
+		}
+		a.Offset = int64(dbi.BTCTxOffset)
+		a.Data = hex.EncodeToString(anchor.BitcoinAnchorData(height, keyMR))
+		anchors = append(anchors, a)
+	}
+
+	record, err := dbo.FetchEthereumAnchor(keyMR)
+	if err != nil {
+		return nil, err
+	}
+	if ar, ok := record.(*anchor.AnchorRecord); ok && ar != nil && ar.Ethereum != nil {
+		a := new(Anchor)
+		a.Blockchain = AnchorEthereum
+		a.DBHeight = height
+		a.Address = ar.Ethereum.Address
+		a.TXID = ar.Ethereum.TXID
+		a.BlockHeight = ar.Ethereum.BlockHeight
+		a.BlockHash = ar.Ethereum.BlockHash
+		a.Offset = ar.Ethereum.Offset
+		a.Data = hex.EncodeToString(anchor.SetAnchorData(height, keyMR))
+		anchors = append(anchors, a)
+	}
+	return anchors, nil
+}
+
+// IncludeRawEntry adds the entry itself to the receipt, so the EntryHash the Merkle branch
+// starts from can be checked against its content
+func (e *Receipt) IncludeRawEntry(dbo interfaces.DBOverlaySimple) error {
+	entryHash, err := primitives.NewShaHashFromStr(e.Entry.EntryHash)
+	if err != nil {
+		return err
+	}
+	entry, err := dbo.FetchEntry(entryHash)
+	if err != nil {
+		return err
+	}
+	if entry == nil {
+		return fmt.Errorf("Entry not found")
+	}
+	data, err := entry.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	e.Entry.Raw = hex.EncodeToString(data)
+	return nil
+}
+
+// validateRawEntry checks the raw entry of a receipt, if it has one, hashes to its EntryHash
+func (e *Receipt) validateRawEntry(entryHash interfaces.IHash) error {
+	if e.Entry.Raw == "" {
+		return nil
+	}
+	data, err := hex.DecodeString(e.Entry.Raw)
+	if err != nil {
+		return err
+	}
+	entry := new(entryBlock.Entry)
+	if err := entry.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	if !entry.GetHash().IsSameAs(entryHash) {
+		return fmt.Errorf("Raw entry does not hash to %v", entryHash)
+	}
+	return nil
+}
@@ -20,6 +20,7 @@ type Receipt struct {
 	DirectoryBlockKeyMR    *primitives.Hash         `json:"directoryblockkeymr,omitempty"`
 	BitcoinTransactionHash *primitives.Hash         `json:"bitcointransactionhash,omitempty"`
 	BitcoinBlockHash       *primitives.Hash         `json:"bitcoinblockhash,omitempty"`
+	Anchors                []*Anchor                `json:"anchors,omitempty"`
 }
 
 func (e *Receipt) TrimReceipt() {
@@ -57,11 +58,17 @@ func (e *Receipt) Validate() error {
 		return fmt.Errorf("Receipt has no DirectoryBlockKeyMR")
 	}
 	entryHash, err := primitives.NewShaHashFromStr(e.Entry.EntryHash)
-	//TODO: validate entry hashes into EntryHash
-
 	if err != nil {
 		return err
 	}
+	if err := e.validateRawEntry(entryHash); err != nil {
+		return err
+	}
+	for _, a := range e.Anchors {
+		if err := a.Validate(e.DirectoryBlockKeyMR); err != nil {
+			return err
+		}
+	}
 	var left interfaces.IHash
 	var right interfaces.IHash
 	var currentEntry interfaces.IHash
@@ -202,6 +209,15 @@ func (e *Receipt) IsSameAs(r *Receipt) bool {
 		}
 	}
 
+	if len(e.Anchors) != len(r.Anchors) {
+		return false
+	}
+	for i := range e.Anchors {
+		if *e.Anchors[i] != *r.Anchors[i] {
+			return false
+		}
+	}
+
 	return true
 }
 
@@ -391,6 +407,11 @@ func CreateReceipt(dbo interfaces.DBOverlaySimple, entryID interfaces.IHash) (*R
 		receipt.BitcoinBlockHash = dbi.BTCBlockHash.(*primitives.Hash)
 	}
 
+	receipt.Anchors, err = fetchAnchors(dbo, dBlock, dirBlockInfo)
+	if err != nil {
+		return nil, err
+	}
+
 	return receipt, nil
 }
 
@@ -5,6 +5,9 @@
 package receipts_test
 
 import (
+	"encoding/hex"
+
+	"github.com/FactomProject/factomd/anchor"
 	"github.com/FactomProject/factomd/common/primitives"
 	. "github.com/FactomProject/factomd/receipts"
 	. "github.com/FactomProject/factomd/testHelper"
@@ -96,3 +99,66 @@ func TestDecodeReceiptString(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestReceiptAnchors(t *testing.T) {
+	dbo := CreateAndPopulateTestDatabaseOverlay()
+	hash, err := primitives.NewShaHashFromStr("be5fb8c3ba92c0436269fab394ff7277c67e9b2de4431b723ce5d89799c0b93a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	receipt, err := CreateFullReceipt(dbo, hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(receipt.Anchors) != 1 || receipt.Anchors[0].Blockchain != AnchorBitcoin {
+		t.Fatalf("Expected the Bitcoin anchor, got %v", receipt.Anchors)
+	}
+	btc := receipt.Anchors[0]
+	if btc.TXID != receipt.BitcoinTransactionHash.String() {
+		t.Errorf("Wrong Bitcoin transaction %v", btc.TXID)
+	}
+	if btc.Data != hex.EncodeToString(anchor.BitcoinAnchorData(btc.DBHeight, receipt.DirectoryBlockKeyMR)) {
+		t.Errorf("Wrong OP_RETURN data %v", btc.Data)
+	}
+
+	ar := &anchor.AnchorRecord{AnchorRecordVer: 1, DBHeight: btc.DBHeight, KeyMR: receipt.DirectoryBlockKeyMR.String(), RecordHeight: btc.DBHeight}
+	ar.Ethereum = &anchor.EthereumStruct{Address: "0x30aa981f6d2fce81083e584c8ee2f822b548752f", TXID: "0x50ea", BlockHeight: 293003, BlockHash: "0x3b50", Offset: 2}
+	if err := dbo.SaveEthereumAnchor(ar); err != nil {
+		t.Fatal(err)
+	}
+	receipt, err = CreateFullReceipt(dbo, hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(receipt.Anchors) != 2 || receipt.Anchors[1].Blockchain != AnchorEthereum || receipt.Anchors[1].TXID != "0x50ea" {
+		t.Fatalf("Expected the Ethereum anchor, got %v", receipt.Anchors)
+	}
+	if err := receipt.IncludeRawEntry(dbo); err != nil {
+		t.Fatal(err)
+	}
+	if receipt.Entry.Raw == "" {
+		t.Fatal("No raw entry included")
+	}
+
+	// Auditors only need the receipt itself
+	decoded, err := DecodeReceiptString(receipt.CustomMarshalString())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !decoded.IsSameAs(receipt) {
+		t.Error("Receipt changed through JSON")
+	}
+	if err := VerifyFullReceipt(dbo, receipt.CustomMarshalString()); err != nil {
+		t.Error(err)
+	}
+
+	decoded.Anchors[1].Data = decoded.Anchors[0].Data
+	if err := decoded.Validate(); err == nil {
+		t.Error("Anchor not carrying the KeyMR passed")
+	}
+	decoded, _ = DecodeReceiptString(receipt.CustomMarshalString())
+	decoded.Entry.Raw = decoded.Entry.Raw[:len(decoded.Entry.Raw)-2] + "ff"
+	if err := decoded.Validate(); err == nil {
+		t.Error("Raw entry not matching the entry hash passed")
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+	log "github.com/sirupsen/logrus"
+)
+
+// A leader sends out an ack for every message it takes, as many messages again as it
+// acks.  With an AckBatchSize, it holds back the acks of its VM instead, and sends those
+// of a minute that follow each other as one BatchAck under a single signature.  Followers
+// unpack the batch in FollowerExecuteAck and take the acks as if they had come alone.
+// Batches are a new message on the wire, so leaders only send them from the height
+// ACTIVATION_BATCH_ACKS takes effect at.
+
+// sendAck sends out an ack the process list took, or if this leader made it and batches
+// its acks, holds it back to go with the next ones.  The batch goes out once full, with
+// the ack of an EOM or DBSig, or once the messages at hand are worked through.
+func (s *State) sendAck(ack *messages.Ack, m interfaces.IMsg) {
+	if s.AckBatchSize < 2 || !s.Leader || ack.Signature == nil || !ack.LeaderChainID.IsSameAs(s.IdentityChainID) ||
+		!constants.IsActive(constants.ACTIVATION_BATCH_ACKS, s.GetNetworkID(), ack.DBHeight) {
+		ack.SendOut(s, ack)
+		return
+	}
+
+	if n := len(s.ackBatch); n > 0 {
+		last := s.ackBatch[n-1]
+		if last.VMIndex != ack.VMIndex || last.Minute != ack.Minute || last.DBHeight != ack.DBHeight || last.Height+1 != ack.Height {
+			s.flushAckBatch()
+		}
+	}
+	s.ackBatch = append(s.ackBatch, ack)
+
+	switch m.Type() {
+	case constants.EOM_MSG, constants.DIRECTORY_BLOCK_SIGNATURE_MSG:
+		s.flushAckBatch()
+		return
+	}
+	if len(s.ackBatch) >= s.AckBatchSize || len(s.ackBatch) >= messages.MaxBatchAcks {
+		s.flushAckBatch()
+	}
+}
+
+// flushAckBatch sends out the acks held back, as a batch ack, or alone if there is just
+// the one
+func (s *State) flushAckBatch() {
+	acks := s.ackBatch
+	if len(acks) == 0 {
+		return
+	}
+	s.ackBatch = nil
+
+	if len(acks) == 1 {
+		acks[0].SendOut(s, acks[0])
+		return
+	}
+	batch, err := messages.NewBatchAck(s.GetTimestamp(), acks)
+	if err == nil {
+		err = batch.Sign(s)
+	}
+	if err != nil {
+		consenLogger.WithFields(log.Fields{"func": "flushAckBatch", "acks": len(acks)}).Errorf("Sending the acks one by one: %v", err)
+		for _, ack := range acks {
+			ack.SendOut(s, ack)
+		}
+		return
+	}
+
+	BatchAcksSent.Inc()
+	AcksBatched.Add(float64(len(acks)))
+	batch.SendOut(s, batch)
+}
@@ -0,0 +1,604 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/util"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var alertLogger = packageLogger.WithFields(log.Fields{"subpack": "alerts"})
+
+// How often the alert conditions are checked
+var AlertCheckInterval = 10 * time.Second
+
+// Webhooks and commands run by alerts are given this long
+var AlertActionTimeout = 30 * time.Second
+
+// Alert actions
+const (
+	AlertLog     = "log"
+	AlertWebhook = "webhook"
+	AlertExec    = "exec"
+)
+
+// Alert states
+const (
+	AlertOK       = "ok"
+	AlertPending  = "pending"
+	AlertFiring   = "firing"
+	AlertResolved = "resolved"
+	AlertError    = "error"
+)
+
+// AlertRule is an operator defined alert, from an [Alert "name"] section of the config.
+// The condition is a sum of state fields, metrics and numbers compared against a number,
+// i.e. "HighestKnown - HighestSaved > 5".
+type AlertRule struct {
+	Name      string
+	Condition string
+	For       time.Duration
+	Action    string // AlertLog, AlertWebhook or AlertExec
+	Target    string // The webhook URL or command
+
+	terms     []alertTerm
+	op        string
+	threshold float64
+}
+
+// alertTerm is one state field, metric or number of a condition
+type alertTerm struct {
+	negate bool
+	name   string // Empty for a number
+	labels map[string]string
+	value  float64
+}
+
+// AlertValueFunc returns the value of a state field or metric, with the given labels
+type AlertValueFunc func(name string, labels map[string]string) (float64, error)
+
+// ParseAlertRules parses the alert sections of the config, in name order
+func ParseAlertRules(config map[string]*util.AlertConfig) ([]*AlertRule, error) {
+	var names []string
+	for name := range config {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var rules []*AlertRule
+	for _, name := range names {
+		rule, err := ParseAlertRule(name, config[name])
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// ParseAlertRule parses one alert section of the config
+func ParseAlertRule(name string, config *util.AlertConfig) (*AlertRule, error) {
+	if config == nil {
+		return nil, fmt.Errorf("Alert %q is empty", name)
+	}
+	r := new(AlertRule)
+	r.Name = name
+	r.Condition = strings.TrimSpace(config.Condition)
+	if err := r.parseCondition(); err != nil {
+		return nil, fmt.Errorf("Alert %q: %v", name, err)
+	}
+
+	if f := strings.TrimSpace(config.For); f != "" {
+		d, err := time.ParseDuration(f)
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("Alert %q: bad For %q", name, config.For)
+		}
+		r.For = d
+	}
+
+	action := strings.Fields(config.Action)
+	if len(action) == 0 {
+		action = []string{AlertLog}
+	}
+	r.Action = strings.ToLower(action[0])
+	r.Target = strings.Join(action[1:], " ")
+	switch r.Action {
+	case AlertLog:
+		if r.Target != "" {
+			return nil, fmt.Errorf("Alert %q: log takes nothing after it", name)
+		}
+	case AlertWebhook:
+		u, err := url.Parse(r.Target)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("Alert %q: webhook needs an http or https URL", name)
+		}
+	case AlertExec:
+		if r.Target == "" {
+			return nil, fmt.Errorf("Alert %q: exec needs a command", name)
+		}
+	default:
+		return nil, fmt.Errorf("Alert %q: unknown action %q, should be log, webhook or exec", name, action[0])
+	}
+	return r, nil
+}
+
+var alertComparisons = []string{">=", "<=", "==", "!=", ">", "<"}
+
+// parseCondition splits the condition into terms, the comparison and the threshold
+func (r *AlertRule) parseCondition() error {
+	c := r.Condition
+	for _, op := range alertComparisons {
+		if i := strings.Index(c, op); i >= 0 {
+			r.op = op
+			threshold, err := strconv.ParseFloat(strings.TrimSpace(c[i+len(op):]), 64)
+			if err != nil {
+				return fmt.Errorf("the right of %s should be a number", op)
+			}
+			r.threshold = threshold
+			c = c[:i]
+			break
+		}
+	}
+	if r.op == "" {
+		return fmt.Errorf("condition %q has no comparison", r.Condition)
+	}
+
+	negate := false
+	expectTerm := true
+	for c = strings.TrimSpace(c); c != ""; c = strings.TrimSpace(c) {
+		if !expectTerm {
+			switch c[0] {
+			case '+':
+				negate = false
+			case '-':
+				negate = true
+			default:
+				return fmt.Errorf("expected + or - before %q", c)
+			}
+			c = c[1:]
+			expectTerm = true
+			continue
+		}
+		if c[0] == '-' && len(r.terms) == 0 && !negate {
+			negate = true
+			c = c[1:]
+			continue
+		}
+
+		t := alertTerm{negate: negate}
+		n := strings.IndexAny(c, " \t+-{")
+		if n < 0 {
+			n = len(c)
+		}
+		// A leading digit is a number, which may have an exponent with a sign
+		if c[0] >= '0' && c[0] <= '9' || c[0] == '.' {
+			n = 0
+			for n < len(c) && (strings.IndexByte("0123456789.eE", c[n]) >= 0 ||
+				n > 0 && (c[n] == '+' || c[n] == '-') && (c[n-1] == 'e' || c[n-1] == 'E')) {
+				n++
+			}
+			v, err := strconv.ParseFloat(c[:n], 64)
+			if err != nil {
+				return fmt.Errorf("bad number %q", c[:n])
+			}
+			t.value = v
+			c = c[n:]
+		} else {
+			t.name = c[:n]
+			if !validAlertName(t.name) {
+				return fmt.Errorf("bad name %q", t.name)
+			}
+			c = c[n:]
+			if strings.HasPrefix(c, "{") {
+				end := strings.Index(c, "}")
+				if end < 0 {
+					return fmt.Errorf("missing } after %s", t.name)
+				}
+				labels, err := parseAlertLabels(c[1:end])
+				if err != nil {
+					return fmt.Errorf("%s: %v", t.name, err)
+				}
+				t.labels = labels
+				c = c[end+1:]
+			}
+		}
+		r.terms = append(r.terms, t)
+		negate = false
+		expectTerm = false
+	}
+	if expectTerm {
+		return fmt.Errorf("condition %q is missing a value", r.Condition)
+	}
+	return nil
+}
+
+func validAlertName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, c := range name {
+		switch {
+		case c == '_' || c == ':' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+		case c >= '0' && c <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// parseAlertLabels parses a metric's label selector, i.e. kind="long-block",server="x"
+func parseAlertLabels(selector string) (map[string]string, error) {
+	labels := make(map[string]string)
+	for _, pair := range strings.Split(selector, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("label %q should be name=\"value\"", pair)
+		}
+		value, err := strconv.Unquote(strings.TrimSpace(kv[1]))
+		if err != nil {
+			return nil, fmt.Errorf("label %q should be name=\"value\"", pair)
+		}
+		labels[strings.TrimSpace(kv[0])] = value
+	}
+	return labels, nil
+}
+
+// Evaluate returns the left side of the condition, and whether the condition holds
+func (r *AlertRule) Evaluate(lookup AlertValueFunc) (float64, bool, error) {
+	var sum float64
+	for _, t := range r.terms {
+		v := t.value
+		if t.name != "" {
+			var err error
+			if v, err = lookup(t.name, t.labels); err != nil {
+				return 0, false, err
+			}
+		}
+		if t.negate {
+			v = -v
+		}
+		sum += v
+	}
+	switch r.op {
+	case ">":
+		return sum, sum > r.threshold, nil
+	case ">=":
+		return sum, sum >= r.threshold, nil
+	case "<":
+		return sum, sum < r.threshold, nil
+	case "<=":
+		return sum, sum <= r.threshold, nil
+	case "==":
+		return sum, sum == r.threshold, nil
+	}
+	return sum, sum != r.threshold, nil
+}
+
+// AlertTracker keeps where each alert rule stands between checks
+type AlertTracker struct {
+	mutex  sync.Mutex
+	rules  []*AlertRule
+	status []interfaces.AlertStatus
+}
+
+func NewAlertTracker(rules []*AlertRule) *AlertTracker {
+	t := new(AlertTracker)
+	t.rules = rules
+	for _, r := range rules {
+		t.status = append(t.status, interfaces.AlertStatus{
+			Name:      r.Name,
+			Condition: r.Condition,
+			For:       r.For.Seconds(),
+			Action:    strings.TrimSpace(r.Action + " " + r.Target),
+			State:     AlertOK,
+		})
+	}
+	return t
+}
+
+// Len is the number of rules
+func (t *AlertTracker) Len() int {
+	if t == nil {
+		return 0
+	}
+	return len(t.rules)
+}
+
+// Check evaluates every rule, and returns the rules that started firing or stopped, with
+// their status.  The status of a rule that can't be evaluated is left as it was, besides
+// the error.
+func (t *AlertTracker) Check(now time.Time, lookup AlertValueFunc) (changed []*AlertRule, status []interfaces.AlertStatus) {
+	if t == nil {
+		return nil, nil
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	for i, r := range t.rules {
+		st := &t.status[i]
+		value, holds, err := r.Evaluate(lookup)
+		if err != nil {
+			if st.Error != err.Error() {
+				alertLogger.WithFields(log.Fields{"alert": r.Name}).Errorf("Cannot check alert: %v", err)
+			}
+			st.Error = err.Error()
+			if st.State != AlertFiring {
+				st.State = AlertError
+			}
+			continue
+		}
+		st.Error = ""
+		st.Value = value
+
+		switch {
+		case holds && st.State == AlertFiring:
+		case holds:
+			if st.Since == 0 {
+				st.Since = now.Unix()
+			}
+			st.State = AlertPending
+			if !now.Before(time.Unix(st.Since, 0).Add(r.For)) {
+				st.State = AlertFiring
+				changed = append(changed, r)
+				status = append(status, *st)
+			}
+		case st.State == AlertFiring:
+			st.State = AlertResolved
+			changed = append(changed, r)
+			status = append(status, *st)
+			st.State = AlertOK
+			st.Since = 0
+		default:
+			st.State = AlertOK
+			st.Since = 0
+		}
+	}
+	return changed, status
+}
+
+// Status returns where each rule stands, in name order
+func (t *AlertTracker) Status() []interfaces.AlertStatus {
+	if t == nil {
+		return nil
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return append([]interfaces.AlertStatus(nil), t.status...)
+}
+
+// alertFields are the state values alerts may use that aren't plain fields of the state
+var alertFields = map[string]func(s *State) float64{
+	"HighestKnown":          func(s *State) float64 { return float64(s.GetHighestKnownBlock()) },
+	"HighestSaved":          func(s *State) float64 { return float64(s.GetHighestSavedBlk()) },
+	"HighestCompleted":      func(s *State) float64 { return float64(s.GetHighestCompletedBlk()) },
+	"HighestAck":            func(s *State) float64 { return float64(s.GetHighestAck()) },
+	"LeaderHeight":          func(s *State) float64 { return float64(s.GetLeaderHeight()) },
+	"EntryDBHeightComplete": func(s *State) float64 { return float64(s.GetEntryDBHeightComplete()) },
+	"Leader":                func(s *State) float64 { return boolValue(s.IsLeader()) },
+	"InMsgQueue":            func(s *State) float64 { return float64(s.InMsgQueue().Length()) },
+	"AckQueue":              func(s *State) float64 { return float64(len(s.AckQueue())) },
+	"MsgQueue":              func(s *State) float64 { return float64(len(s.MsgQueue())) },
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// AlertValue returns a value an alert may use: one of alertFields, an exported metric
+// (summed over the series the labels select; summaries and histograms also have _sum and
+// _count), or an exported number, flag, map, slice or channel of the state, for which the
+// length is used.
+func (s *State) AlertValue(name string, labels map[string]string, families []*dto.MetricFamily) (float64, error) {
+	if f, ok := alertFields[name]; ok && labels == nil {
+		return f(s), nil
+	}
+	if v, ok := metricValue(name, labels, families); ok {
+		return v, nil
+	}
+	if labels == nil {
+		field := reflect.ValueOf(s).Elem().FieldByName(name)
+		if field.IsValid() && field.CanInterface() {
+			switch field.Kind() {
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				return float64(field.Int()), nil
+			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+				return float64(field.Uint()), nil
+			case reflect.Float32, reflect.Float64:
+				return field.Float(), nil
+			case reflect.Bool:
+				return boolValue(field.Bool()), nil
+			case reflect.Map, reflect.Slice, reflect.Chan:
+				return float64(field.Len()), nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("%s is not a number in the state, nor an exported metric", name)
+}
+
+func metricValue(name string, labels map[string]string, families []*dto.MetricFamily) (float64, bool) {
+	found := false
+	var sum float64
+	for _, family := range families {
+		part := ""
+		switch family.GetName() {
+		case name:
+		case strings.TrimSuffix(name, "_sum"):
+			part = "sum"
+		case strings.TrimSuffix(name, "_count"):
+			part = "count"
+		default:
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			if !metricMatches(m, labels) {
+				continue
+			}
+			found = true
+			switch {
+			case part == "sum" && m.GetSummary() != nil:
+				sum += m.GetSummary().GetSampleSum()
+			case part == "count" && m.GetSummary() != nil:
+				sum += float64(m.GetSummary().GetSampleCount())
+			case part == "sum" && m.GetHistogram() != nil:
+				sum += m.GetHistogram().GetSampleSum()
+			case part == "count" && m.GetHistogram() != nil:
+				sum += float64(m.GetHistogram().GetSampleCount())
+			case part != "":
+				found = false
+			case m.GetGauge() != nil:
+				sum += m.GetGauge().GetValue()
+			case m.GetCounter() != nil:
+				sum += m.GetCounter().GetValue()
+			case m.GetUntyped() != nil:
+				sum += m.GetUntyped().GetValue()
+			}
+		}
+	}
+	return sum, found
+}
+
+func metricMatches(m *dto.Metric, labels map[string]string) bool {
+	for name, value := range labels {
+		matched := false
+		for _, l := range m.GetLabel() {
+			if l.GetName() == name && l.GetValue() == value {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckAlerts evaluates the alert rules, and takes the action of those that started or
+// stopped firing
+func (s *State) CheckAlerts() {
+	if s.Alerts.Len() == 0 {
+		return
+	}
+	// The metrics are only gathered if a rule asks for something that isn't in the state
+	var families []*dto.MetricFamily
+	gathered := false
+	lookup := func(name string, labels map[string]string) (float64, error) {
+		if _, ok := alertFields[name]; !ok && !gathered {
+			gathered = true
+			var err error
+			if families, err = prometheus.DefaultGatherer.Gather(); err != nil {
+				alertLogger.Errorf("Cannot gather the metrics: %v", err)
+			}
+		}
+		return s.AlertValue(name, labels, families)
+	}
+
+	changed, status := s.Alerts.Check(time.Now(), lookup)
+	for i, r := range changed {
+		AlertsVec.WithLabelValues(r.Name, status[i].State).Inc()
+		s.alertAction(r, status[i])
+	}
+}
+
+// alertNotice is what a webhook is sent
+type alertNotice struct {
+	Node string `json:"node"`
+	interfaces.AlertStatus
+}
+
+func (s *State) alertAction(r *AlertRule, status interfaces.AlertStatus) {
+	fields := log.Fields{"alert": r.Name, "condition": r.Condition, "value": status.Value}
+	switch r.Action {
+	case AlertLog:
+		if status.State == AlertFiring {
+			alertLogger.WithFields(fields).Warn("Alert firing")
+		} else {
+			alertLogger.WithFields(fields).Info("Alert resolved")
+		}
+
+	case AlertWebhook:
+		body, err := json.Marshal(alertNotice{Node: s.FactomNodeName, AlertStatus: status})
+		if err != nil {
+			alertLogger.WithFields(fields).Errorf("Cannot encode alert: %v", err)
+			return
+		}
+		go func() {
+			client := &http.Client{Timeout: AlertActionTimeout}
+			resp, err := client.Post(r.Target, "application/json", bytes.NewReader(body))
+			if err != nil {
+				alertLogger.WithFields(fields).Errorf("Cannot send alert %s: %v", status.State, err)
+				return
+			}
+			resp.Body.Close()
+			if resp.StatusCode/100 != 2 {
+				alertLogger.WithFields(fields).Errorf("Webhook answered alert %s with %s", status.State, resp.Status)
+			}
+		}()
+
+	case AlertExec:
+		args := strings.Fields(r.Target)
+		env := append(os.Environ(),
+			"FACTOMD_NODE="+s.FactomNodeName,
+			"FACTOMD_ALERT="+r.Name,
+			"FACTOMD_ALERT_STATE="+status.State,
+			"FACTOMD_ALERT_CONDITION="+r.Condition,
+			"FACTOMD_ALERT_VALUE="+strconv.FormatFloat(status.Value, 'f', -1, 64))
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), AlertActionTimeout)
+			defer cancel()
+			cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+			cmd.Env = env
+			if out, err := cmd.CombinedOutput(); err != nil {
+				alertLogger.WithFields(fields).Errorf("Alert %s command failed: %v: %s", status.State, err, out)
+			}
+		}()
+	}
+}
+
+// GoCheckAlerts checks the alert rules every AlertCheckInterval, once the database is
+// loaded
+func (s *State) GoCheckAlerts() {
+	if s.Alerts.Len() == 0 {
+		return
+	}
+	for !s.DBFinished {
+		time.Sleep(time.Second)
+	}
+	for {
+		s.CheckAlerts()
+		time.Sleep(AlertCheckInterval)
+	}
+}
+
+// GetAlerts returns the operator defined alert rules, and whether they are firing
+func (s *State) GetAlerts() []interfaces.AlertStatus {
+	return s.Alerts.Status()
+}
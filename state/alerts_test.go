@@ -0,0 +1,177 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/FactomProject/factomd/state"
+	"github.com/FactomProject/factomd/testHelper"
+	"github.com/FactomProject/factomd/util"
+)
+
+func TestParseAlertRule(t *testing.T) {
+	good := []util.AlertConfig{
+		{Condition: "HighestKnown - HighestSaved > 5", For: "3m"},
+		{Condition: "-a + 2.5e-1 <= -3", Action: "exec /usr/local/bin/page oncall"},
+		{Condition: `factomd_state_timing_anomalies_vec{kind="long-block"} != 0`, Action: "webhook https://example.com/hook"},
+	}
+	for _, c := range good {
+		if _, err := ParseAlertRule("good", &c); err != nil {
+			t.Errorf("%+v: %v", c, err)
+		}
+	}
+
+	bad := []util.AlertConfig{
+		{Condition: "HighestKnown"},
+		{Condition: "HighestKnown > x"},
+		{Condition: "HighestKnown HighestSaved > 5"},
+		{Condition: "HighestKnown - > 5"},
+		{Condition: "9lives > 5"},
+		{Condition: `m{kind=long} > 5`},
+		{Condition: "a > 5", For: "soon"},
+		{Condition: "a > 5", Action: "email ops"},
+		{Condition: "a > 5", Action: "webhook ftp://example.com"},
+		{Condition: "a > 5", Action: "exec"},
+	}
+	for _, c := range bad {
+		if _, err := ParseAlertRule("bad", &c); err == nil {
+			t.Errorf("%+v parsed", c)
+		}
+	}
+}
+
+func TestAlertEvaluate(t *testing.T) {
+	values := map[string]float64{"a": 10, "b": 4}
+	lookup := func(name string, labels map[string]string) (float64, error) {
+		if labels["kind"] == "x" {
+			return 100, nil
+		}
+		v, ok := values[name]
+		if !ok {
+			return 0, errors.New("unknown")
+		}
+		return v, nil
+	}
+
+	tests := []struct {
+		condition string
+		value     float64
+		holds     bool
+	}{
+		{"a - b > 5", 6, true},
+		{"a - b > 6", 6, false},
+		{"a - b >= 6", 6, true},
+		{"-a + b + 1 < -4", -5, true},
+		{"b == 4", 4, true},
+		{"b != 4", 4, false},
+		{"a + 1e1 <= 20", 20, true},
+		{`m{kind="x"} - a > 50`, 90, true},
+	}
+	for _, test := range tests {
+		r, err := ParseAlertRule("test", &util.AlertConfig{Condition: test.condition})
+		if err != nil {
+			t.Fatalf("%s: %v", test.condition, err)
+		}
+		value, holds, err := r.Evaluate(lookup)
+		if err != nil || value != test.value || holds != test.holds {
+			t.Errorf("%s: got %v %v %v, expected %v %v", test.condition, value, holds, err, test.value, test.holds)
+		}
+	}
+
+	r, _ := ParseAlertRule("test", &util.AlertConfig{Condition: "c > 1"})
+	if _, _, err := r.Evaluate(lookup); err == nil {
+		t.Error("Unknown value evaluated")
+	}
+}
+
+func TestAlertTracker(t *testing.T) {
+	rules, err := ParseAlertRules(map[string]*util.AlertConfig{
+		"slow": {Condition: "behind > 5", For: "3m"},
+		"now":  {Condition: "behind > 0"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := NewAlertTracker(rules)
+	behind := 0.0
+	lookup := func(string, map[string]string) (float64, error) { return behind, nil }
+	start := time.Unix(1500000000, 0)
+
+	names := func(rules []*AlertRule) (n []string) {
+		for _, r := range rules {
+			n = append(n, r.Name)
+		}
+		return
+	}
+
+	if changed, _ := tr.Check(start, lookup); len(changed) != 0 {
+		t.Errorf("Fired when fine: %v", names(changed))
+	}
+
+	// The rule without a For fires at once, the other waits 3 minutes
+	behind = 6
+	changed, status := tr.Check(start.Add(time.Minute), lookup)
+	if len(changed) != 1 || changed[0].Name != "now" || status[0].State != AlertFiring {
+		t.Errorf("Expected now to fire, got %v %+v", names(changed), status)
+	}
+	if st := tr.Status(); st[1].Name != "slow" || st[1].State != AlertPending || st[1].Value != 6 {
+		t.Errorf("Expected slow to be pending, got %+v", st[1])
+	}
+	if changed, _ := tr.Check(start.Add(3*time.Minute), lookup); len(changed) != 0 {
+		t.Errorf("Fired early, or again: %v", names(changed))
+	}
+	changed, _ = tr.Check(start.Add(4*time.Minute), lookup)
+	if len(changed) != 1 || changed[0].Name != "slow" {
+		t.Errorf("Expected slow to fire, got %v", names(changed))
+	}
+
+	// Both resolve, and the wait starts over
+	behind = 0
+	changed, status = tr.Check(start.Add(5*time.Minute), lookup)
+	if len(changed) != 2 || status[0].State != AlertResolved || status[1].State != AlertResolved {
+		t.Errorf("Expected both to resolve, got %v %+v", names(changed), status)
+	}
+	behind = 6
+	changed, _ = tr.Check(start.Add(6*time.Minute), lookup)
+	if len(changed) != 1 || changed[0].Name != "now" {
+		t.Errorf("Expected only now to fire, got %v", names(changed))
+	}
+
+	// A rule that can't be checked keeps firing, with the error
+	lookup = func(string, map[string]string) (float64, error) { return 0, errors.New("gone") }
+	if changed, _ := tr.Check(start.Add(7*time.Minute), lookup); len(changed) != 0 {
+		t.Errorf("Error changed alerts: %v", names(changed))
+	}
+	st := tr.Status()
+	if st[0].State != AlertFiring || st[0].Error != "gone" || st[1].State != AlertError {
+		t.Errorf("Unexpected status after error %+v", st)
+	}
+}
+
+func TestAlertValue(t *testing.T) {
+	s := testHelper.CreateEmptyTestState()
+	s.CurrentMinute = 7
+
+	for name, expected := range map[string]float64{
+		"HighestSaved":  float64(s.GetHighestSavedBlk()),
+		"CurrentMinute": 7,
+		"Holding":       float64(len(s.Holding)),
+		"DBFinished":    1,
+	} {
+		s.DBFinished = true
+		v, err := s.AlertValue(name, nil, nil)
+		if err != nil || v != expected {
+			t.Errorf("%s: got %v %v, expected %v", name, v, err, expected)
+		}
+	}
+	for _, name := range []string{"FactomNodeName", "nothing", "inMsgQueue"} {
+		if _, err := s.AlertValue(name, nil, nil); err == nil {
+			t.Errorf("%s has a value", name)
+		}
+	}
+}
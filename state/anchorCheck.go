@@ -0,0 +1,310 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/FactomProject/factomd/anchor"
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var anchorCheckLogger = packageLogger.WithFields(log.Fields{"subpack": "anchor-check"})
+
+// How often saved blocks are compared against the anchor chain, unless configured
+const DefaultAnchorCheckInterval = 10 * time.Minute
+
+// The first entry of the anchor chain only names it, and holds no record
+const anchorChainFirstEntry = "24674e6bc3094eb773297de955ee095a05830e431da13a37382dcdc89d73c7d7"
+
+// How many records for blocks we haven't saved yet are kept for the next pass
+const maxPendingAnchors = 10000
+
+// AnchorCheckTracker remembers how far through the anchor chain we have compared, so
+// each pass only has to look at the records written since the last.
+type AnchorCheckTracker struct {
+	mutex      sync.Mutex
+	lastEBlock [32]byte // KeyMR of the newest anchor entry block already walked
+	pending    []pendingAnchor
+	status     interfaces.AnchorCheckStatus
+
+	// A pass that stops at an entry we don't have yet resumes from there, so the entries
+	// of the block before it aren't checked again
+	partialEBlock  [32]byte // KeyMR of the anchor entry block the last pass stopped in
+	partialEntries int      // How many of its entries were walked
+
+	// Read without the mutex, which a pass holds while it walks the anchor chain
+	bitcoinHeight uint32 // Highest height a Bitcoin anchor record matched
+	forked        uint32 // 1 once a record didn't match
+}
+
+type pendingAnchor struct {
+	record    *anchor.AnchorRecord
+	entryHash interfaces.IHash
+}
+
+func NewAnchorCheckTracker() *AnchorCheckTracker {
+	return new(AnchorCheckTracker)
+}
+
+// Status returns the progress of the anchor checks, and any mismatches found
+func (t *AnchorCheckTracker) Status() interfaces.AnchorCheckStatus {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	st := t.status
+	st.Pending = len(t.pending)
+	st.Mismatches = append([]interfaces.AnchorMismatch{}, t.status.Mismatches...)
+	return st
+}
+
+// Compare checks one anchor record against the KeyMR we saved at its height.  A nil
+// localKeyMR means we don't have the block yet, and the record is kept for later.
+// Returns the mismatch, if there is one.
+func (t *AnchorCheckTracker) Compare(ar *anchor.AnchorRecord, entryHash interfaces.IHash, localKeyMR interfaces.IHash) *interfaces.AnchorMismatch {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.compare(ar, entryHash, localKeyMR)
+}
+
+func (t *AnchorCheckTracker) compare(ar *anchor.AnchorRecord, entryHash interfaces.IHash, localKeyMR interfaces.IHash) *interfaces.AnchorMismatch {
+	if localKeyMR == nil || localKeyMR.IsZero() {
+		if len(t.pending) < maxPendingAnchors {
+			t.pending = append(t.pending, pendingAnchor{ar, entryHash})
+		}
+		return nil
+	}
+	t.status.Checked++
+	if ar.DBHeight > t.status.HighestHeight {
+		t.status.HighestHeight = ar.DBHeight
+	}
+	if strings.ToLower(ar.KeyMR) == localKeyMR.String() {
+		if ar.Bitcoin != nil && ar.DBHeight > atomic.LoadUint32(&t.bitcoinHeight) {
+			atomic.StoreUint32(&t.bitcoinHeight, ar.DBHeight)
+		}
+		return nil
+	}
+
+	m := interfaces.AnchorMismatch{
+		DBHeight:    ar.DBHeight,
+		LocalKeyMR:  localKeyMR.String(),
+		AnchorKeyMR: ar.KeyMR,
+		EntryHash:   entryHash.String(),
+	}
+	if ar.Bitcoin != nil {
+		m.BitcoinTXID = ar.Bitcoin.TXID
+	}
+	if ar.Ethereum != nil {
+		m.EthereumTXID = ar.Ethereum.TXID
+	}
+	t.status.Forked = true
+	atomic.StoreUint32(&t.forked, 1)
+	t.status.Mismatches = append(t.status.Mismatches, m)
+	return &m
+}
+
+// CheckAnchors makes one pass over the anchor records written since the last pass, and
+// those that were waiting on blocks we didn't have, comparing each against our saved
+// directory blocks.  Returns any mismatches found on this pass.
+func (s *State) CheckAnchors() []interfaces.AnchorMismatch {
+	t := s.AnchorChecks
+	if t == nil {
+		return nil
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	var found []interfaces.AnchorMismatch
+	check := func(ar *anchor.AnchorRecord, entryHash interfaces.IHash) {
+		if m := t.compare(ar, entryHash, s.savedKeyMR(ar.DBHeight)); m != nil {
+			found = append(found, *m)
+		}
+	}
+
+	pending := t.pending
+	t.pending = nil
+	for _, p := range pending {
+		check(p.record, p.entryHash)
+	}
+
+	chainID, _ := primitives.HexToHash(databaseOverlay.AnchorBlockID)
+	eBlock, err := s.DB.FetchEBlockHead(chainID)
+	if err != nil {
+		anchorCheckLogger.Errorf("Cannot fetch the head of the anchor chain: %v", err)
+		return found
+	}
+	var head [32]byte
+	if eBlock != nil {
+		keyMR, _ := eBlock.KeyMR()
+		head = keyMR.Fixed()
+	}
+
+	// Walk back to the entry block we got to last time, then check forward so records
+	// are compared in the order they were written
+	var eBlocks []interfaces.IEntryBlock
+	for eBlock != nil {
+		keyMR, _ := eBlock.KeyMR()
+		if keyMR.Fixed() == t.lastEBlock {
+			break
+		}
+		eBlocks = append(eBlocks, eBlock)
+		prev := eBlock.GetHeader().GetPrevKeyMR()
+		if prev == nil || prev.IsZero() {
+			break
+		}
+		eBlock, err = s.DB.FetchEBlock(prev)
+		if err != nil {
+			anchorCheckLogger.Errorf("Cannot fetch anchor entry block %s: %v", prev.String(), err)
+			return found
+		}
+	}
+	for i := len(eBlocks) - 1; i >= 0; i-- {
+		keyMR, _ := eBlocks[i].KeyMR()
+		entryHashes := eBlocks[i].GetEntryHashes()
+		start := 0
+		if keyMR.Fixed() == t.partialEBlock && t.partialEntries <= len(entryHashes) {
+			start = t.partialEntries
+		}
+		for j := start; j < len(entryHashes); j++ {
+			entryHash := entryHashes[j]
+			if entryHash.IsMinuteMarker() || entryHash.String() == anchorChainFirstEntry {
+				continue
+			}
+			entry, err := s.DB.FetchEntry(entryHash)
+			if err != nil || entry == nil {
+				// The entry sync hasn't got it yet; the next pass starts from it
+				t.partialEBlock = keyMR.Fixed()
+				t.partialEntries = j
+				return found
+			}
+			// Anyone can write to the anchor chain, so only signed records count
+			ar, ok, err := anchor.UnmarshalAndValidateAnchorEntryAnyVersion(entry, databaseOverlay.AnchorSigPublicKeys)
+			if err != nil || !ok || ar == nil {
+				continue
+			}
+			check(ar, entryHash)
+		}
+		t.lastEBlock = keyMR.Fixed()
+		t.partialEBlock = [32]byte{}
+		t.partialEntries = 0
+	}
+	t.lastEBlock = head
+	t.status.LastCheck = time.Now().Unix()
+	return found
+}
+
+// savedKeyMR returns the KeyMR of the directory block we saved at dbheight, or nil if we
+// don't have it yet
+func (s *State) savedKeyMR(dbheight uint32) interfaces.IHash {
+	keyMR, err := s.DB.FetchDBKeyMRByHeight(dbheight)
+	if err != nil || keyMR == nil {
+		return nil
+	}
+	return keyMR
+}
+
+// AnchoredHeight returns the highest directory block height a checkpoint of mainnet, or a
+// Bitcoin anchor record the anchor checks matched, vouches for.  It is 0 once the anchor
+// checks find a fork.
+func (s *State) AnchoredHeight() uint32 {
+	var anchored uint32
+	if t := s.AnchorChecks; t != nil {
+		if atomic.LoadUint32(&t.forked) != 0 {
+			return 0
+		}
+		anchored = atomic.LoadUint32(&t.bitcoinHeight)
+	}
+	// The checkpoints are KeyMRs of mainnet blocks
+	if s.GetNetworkID() == constants.MAIN_NETWORK_ID {
+		if _, highest := constants.CheckPointCount(); highest > anchored {
+			anchored = highest
+		}
+	}
+	return anchored
+}
+
+// anchoredSignatures is true if, with SkipAnchoredSignatures, the factoid signatures of
+// the block at dbheight can go unchecked
+func (s *State) anchoredSignatures(dbheight uint32) bool {
+	if !s.SkipAnchoredSignatures {
+		return false
+	}
+	anchored := s.AnchoredHeight()
+	return anchored > 0 && dbheight <= anchored
+}
+
+// GetAnchorCheck returns how far saved blocks have been checked against the anchors
+func (s *State) GetAnchorCheck() interfaces.AnchorCheckStatus {
+	if s.AnchorChecks == nil {
+		return interfaces.AnchorCheckStatus{}
+	}
+	return s.AnchorChecks.Status()
+}
+
+// GoCheckAnchors periodically compares our saved directory blocks against the KeyMRs
+// anchored into Bitcoin and Ethereum.  A mismatch means we are following a long-range
+// fork, or our database is corrupt; either way nothing we build on it can be trusted, so
+// unless configured to only report it, the node is halted.
+func (s *State) GoCheckAnchors() {
+	interval := s.AnchorCheckInterval
+	if interval < 0 {
+		return
+	}
+	if interval == 0 {
+		interval = DefaultAnchorCheckInterval
+	}
+	for {
+		time.Sleep(interval)
+		if !s.DBFinished {
+			continue
+		}
+		if found := s.CheckAnchors(); len(found) > 0 {
+			s.reportFork(found)
+			if !s.AnchorCheckReportOnly {
+				return
+			}
+		}
+	}
+}
+
+// reportFork logs the mismatches as prominently as we can, and halts the node
+func (s *State) reportFork(found []interfaces.AnchorMismatch) {
+	banner := strings.Repeat("!", 78)
+	lines := []string{
+		banner,
+		"LONG-RANGE FORK OR LOCAL CORRUPTION DETECTED",
+		fmt.Sprintf("Node %s has saved directory blocks that differ from the anchored ones.", s.FactomNodeName),
+	}
+	for _, m := range found {
+		lines = append(lines, fmt.Sprintf("  dbheight %d: local keymr %s, anchored keymr %s (anchor entry %s, btc tx %q, eth tx %q)",
+			m.DBHeight, m.LocalKeyMR, m.AnchorKeyMR, m.EntryHash, m.BitcoinTXID, m.EthereumTXID))
+	}
+	if s.AnchorCheckReportOnly {
+		lines = append(lines, "AnchorCheckReportOnly is set; the node keeps running.")
+	} else {
+		lines = append(lines, "The node is halting.  Rebuild the database from a trusted source before restarting.")
+	}
+	lines = append(lines, banner)
+	report := strings.Join(lines, "\n")
+
+	fmt.Println(report)
+	anchorCheckLogger.WithFields(log.Fields{"node-name": s.FactomNodeName, "mismatches": len(found)}).Error(report)
+	s.PublishStatusEvent(interfaces.StatusEventForkDetected, -1, fmt.Sprintf("dbheight %d", found[0].DBHeight))
+
+	if !s.AnchorCheckReportOnly {
+		select {
+		case s.ShutdownChan <- 1:
+		default:
+		}
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/anchor"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+	. "github.com/FactomProject/factomd/state"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestAnchorCheckTrackerCompare(t *testing.T) {
+	tr := NewAnchorCheckTracker()
+	local := primitives.Sha([]byte("local"))
+	entry := primitives.Sha([]byte("entry"))
+
+	ar := &anchor.AnchorRecord{DBHeight: 5, KeyMR: local.String()}
+	if m := tr.Compare(ar, entry, local); m != nil {
+		t.Errorf("Unexpected mismatch %v", m)
+	}
+	// A block we don't have yet is kept for later
+	if m := tr.Compare(&anchor.AnchorRecord{DBHeight: 9, KeyMR: local.String()}, entry, nil); m != nil {
+		t.Errorf("Unexpected mismatch %v", m)
+	}
+
+	ar = &anchor.AnchorRecord{DBHeight: 7, KeyMR: primitives.Sha([]byte("anchored")).String()}
+	ar.Bitcoin = &anchor.BitcoinStruct{TXID: "abcd"}
+	m := tr.Compare(ar, entry, local)
+	if m == nil {
+		t.Fatal("Expected a mismatch")
+	}
+	if m.DBHeight != 7 || m.LocalKeyMR != local.String() || m.BitcoinTXID != "abcd" {
+		t.Errorf("Unexpected mismatch %v", m)
+	}
+
+	st := tr.Status()
+	if !st.Forked || st.Checked != 2 || st.Pending != 1 || st.HighestHeight != 7 || len(st.Mismatches) != 1 {
+		t.Errorf("Unexpected status %+v", st)
+	}
+}
+
+func TestCheckAnchors(t *testing.T) {
+	s := testHelper.CreateAndPopulateTestState()
+
+	if found := s.CheckAnchors(); len(found) != 0 {
+		t.Errorf("Unexpected mismatches %v", found)
+	}
+	st := s.GetAnchorCheck()
+	if st.Forked || st.Checked == 0 || st.LastCheck == 0 {
+		t.Errorf("Unexpected status %+v", st)
+	}
+
+	// Nothing new has been anchored, so a second pass only retries what was pending
+	checked := st.Checked
+	s.CheckAnchors()
+	if st := s.GetAnchorCheck(); st.Checked > checked+st.Pending {
+		t.Errorf("Records were checked twice, %d then %d", checked, st.Checked)
+	}
+}
+
+func TestCheckAnchorsResumes(t *testing.T) {
+	s := testHelper.CreateAndPopulateTestState()
+	s.CheckAnchors()
+	all := s.GetAnchorCheck().Checked
+
+	// Take away the last entry of the anchor chain, as if the entry sync hadn't got it
+	db := s.DB.(*databaseOverlay.Overlay)
+	chainID, _ := primitives.HexToHash(databaseOverlay.AnchorBlockID)
+	head, err := db.FetchEBlockHead(chainID)
+	if err != nil || head == nil {
+		t.Fatalf("No anchor chain: %v", err)
+	}
+	hashes := head.GetEntryHashes()
+	last := hashes[len(hashes)-1]
+	if last.IsMinuteMarker() {
+		last = hashes[len(hashes)-2]
+	}
+	if err := db.Delete(databaseOverlay.ENTRY, last.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+
+	s.AnchorChecks = NewAnchorCheckTracker()
+	s.CheckAnchors()
+	if s.GetAnchorCheck().Checked >= all {
+		t.Errorf("Checked %d records without the last entry, of %d", s.GetAnchorCheck().Checked, all)
+	}
+
+	// Once it arrives, the next pass checks only what it hadn't
+	if err := db.Put(databaseOverlay.ENTRY, last.Bytes(), chainID); err != nil {
+		t.Fatal(err)
+	}
+	s.CheckAnchors()
+	if st := s.GetAnchorCheck(); st.Checked != all || len(st.Mismatches) != 0 {
+		t.Errorf("Checked %d records of %d, with mismatches %v", st.Checked, all, st.Mismatches)
+	}
+}
+
+func TestAnchoredHeight(t *testing.T) {
+	s := testHelper.CreateEmptyTestState()
+	s.AnchorChecks = NewAnchorCheckTracker()
+	local := primitives.Sha([]byte("local"))
+	entry := primitives.Sha([]byte("entry"))
+
+	if s.AnchoredHeight() != 0 {
+		t.Errorf("Expected nothing anchored, got %d", s.AnchoredHeight())
+	}
+
+	// Only Bitcoin anchors count, and the checkpoints of mainnet don't apply here
+	ar := &anchor.AnchorRecord{DBHeight: 5, KeyMR: local.String()}
+	ar.Bitcoin = &anchor.BitcoinStruct{TXID: "abcd"}
+	s.AnchorChecks.Compare(ar, entry, local)
+	ar = &anchor.AnchorRecord{DBHeight: 8, KeyMR: local.String()}
+	ar.Ethereum = &anchor.EthereumStruct{TXID: "ef01"}
+	s.AnchorChecks.Compare(ar, entry, local)
+	if s.AnchoredHeight() != 5 {
+		t.Errorf("Expected height 5 anchored, got %d", s.AnchoredHeight())
+	}
+
+	// A fork ends the trust in the anchors
+	s.AnchorChecks.Compare(&anchor.AnchorRecord{DBHeight: 9, KeyMR: entry.String()}, entry, local)
+	if s.AnchoredHeight() != 0 {
+		t.Errorf("Expected nothing anchored after a fork, got %d", s.AnchoredHeight())
+	}
+}
@@ -1,6 +1,7 @@
 package state
 
 import (
+	"github.com/FactomProject/factomd/common/constants"
 	"github.com/FactomProject/factomd/common/interfaces"
 )
 
@@ -29,6 +30,18 @@ func (q APIMSGQueue) Enqueue(m interfaces.IMsg) {
 	q <- m
 }
 
+// TryEnqueue adds item to channel unless it is full.  Returns false if it was.
+func (q APIMSGQueue) TryEnqueue(m interfaces.IMsg) bool {
+	select {
+	case q <- m:
+		measureMessage(TotalMessageQueueApiGeneralVec, m, true)
+		measureMessage(CurrentMessageQueueApiGeneralVec, m, true)
+		return true
+	default:
+		return false
+	}
+}
+
 // Dequeue removes an item from channel and instruments based on type. Returns nil if nothing in
 // queue
 func (q APIMSGQueue) Dequeue() interfaces.IMsg {
@@ -47,3 +60,89 @@ func (q APIMSGQueue) BlockingDequeue() interfaces.IMsg {
 	measureMessage(CurrentMessageQueueApiGeneralVec, v, false)
 	return v
 }
+
+// A node further behind the highest block it knows of than this is catching up, and holds
+// what it is given until it has
+const apiSyncingLag = 5
+
+// How long clients are asked to wait before submitting again, in seconds
+const (
+	apiRetryAfterFull       = 1
+	apiRetryAfterSyncing    = 30
+	apiRetryAfterOverloaded = 5
+)
+
+// GetAPIQueueStatus returns what a message submitted through the API now would get
+func (s *State) GetAPIQueueStatus() interfaces.APISubmission {
+	status := interfaces.APISubmission{
+		Status:     interfaces.APISubmitAccepted,
+		QueueDepth: s.apiQueue.Length(),
+		QueueCap:   s.apiQueue.Cap(),
+		Syncing:    s.isSyncingForAPI(),
+		Overloaded: s.GetLoadLevel() == interfaces.LoadOverloaded,
+	}
+	if !status.Syncing {
+		status.EarliestBlock = s.earliestBlock()
+	}
+	switch {
+	case status.Syncing, s.IsStopping():
+		// A node shutting down takes nothing more; by the time it is back, it is syncing
+		status.Status = interfaces.APISubmitRejected
+		status.RetryAfter = apiRetryAfterSyncing
+	case status.QueueDepth >= status.QueueCap:
+		status.Status = interfaces.APISubmitRejected
+		status.RetryAfter = apiRetryAfterFull
+	case status.QueueDepth > 0:
+		status.Status = interfaces.APISubmitQueued
+	}
+	return status
+}
+
+// SubmitAPIMsg puts a message from the API in the API queue, unless the node is syncing or
+// the queue is full, or the message is a new commit and the node is overloaded, in which
+// case the message is dropped and the client is told when to try again.  Submissions used
+// to wait on a full queue, holding the API up behind them.
+func (s *State) SubmitAPIMsg(msg interfaces.IMsg) interfaces.APISubmission {
+	status := s.GetAPIQueueStatus()
+	if status.Overloaded && status.Status != interfaces.APISubmitRejected && isCommit(msg) {
+		status.Status = interfaces.APISubmitRejected
+		status.RetryAfter = apiRetryAfterOverloaded
+	}
+	if status.Status != interfaces.APISubmitRejected && !s.apiQueue.TryEnqueue(msg) {
+		// Filled up since we looked
+		status.Status = interfaces.APISubmitRejected
+		status.QueueDepth = status.QueueCap
+		status.RetryAfter = apiRetryAfterFull
+	}
+	APISubmissions.WithLabelValues(status.Status).Inc()
+	return status
+}
+
+// isCommit is true of the messages that start new work for the network: commits, whose
+// reveals follow them
+func isCommit(msg interfaces.IMsg) bool {
+	switch msg.Type() {
+	case constants.COMMIT_CHAIN_MSG, constants.COMMIT_ENTRY_MSG:
+		return true
+	}
+	return false
+}
+
+// isSubmission is true of the messages users submit: commits, reveals and factoid
+// transactions
+func isSubmission(msg interfaces.IMsg) bool {
+	switch msg.Type() {
+	case constants.COMMIT_CHAIN_MSG, constants.COMMIT_ENTRY_MSG, constants.REVEAL_ENTRY_MSG, constants.FACTOID_TRANSACTION_MSG:
+		return true
+	}
+	return false
+}
+
+// isSyncingForAPI is true while the node is loading its database or catching up on blocks
+func (s *State) isSyncingForAPI() bool {
+	if !s.DBFinished {
+		return true
+	}
+	known, saved := s.GetHighestKnownBlock(), s.GetHighestSavedBlk()
+	return known > saved && known-saved > apiSyncingLag
+}
@@ -0,0 +1,47 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestSubmitAPIMsg(t *testing.T) {
+	s := testHelper.CreateEmptyTestState()
+
+	if got := s.SubmitAPIMsg(newSigTestCommit(0, true)); got.Status != interfaces.APISubmitAccepted || got.QueueDepth != 0 {
+		t.Errorf("First submission got %+v", got)
+	}
+	if got := s.SubmitAPIMsg(newSigTestCommit(1, true)); got.Status != interfaces.APISubmitQueued || got.QueueDepth != 1 {
+		t.Errorf("Second submission got %+v", got)
+	}
+	for i := s.APIQueue().Length(); i < s.APIQueue().Cap(); i++ {
+		s.APIQueue().Enqueue(newSigTestCommit(i, true))
+	}
+	got := s.SubmitAPIMsg(newSigTestCommit(1000, true))
+	if got.Status != interfaces.APISubmitRejected || got.RetryAfter <= 0 || got.Syncing {
+		t.Errorf("Submission to a full queue got %+v", got)
+	}
+	if s.APIQueue().Length() != s.APIQueue().Cap() {
+		t.Errorf("Queue holds %d messages, expected %d", s.APIQueue().Length(), s.APIQueue().Cap())
+	}
+
+	for s.APIQueue().Dequeue() != nil {
+	}
+	s.DBFinished = false
+	got = s.SubmitAPIMsg(newSigTestCommit(1001, true))
+	if got.Status != interfaces.APISubmitRejected || !got.Syncing || got.RetryAfter <= 0 {
+		t.Errorf("Submission while syncing got %+v", got)
+	}
+	if s.APIQueue().Length() != 0 {
+		t.Error("Submission while syncing was queued")
+	}
+	if status := s.GetAPIQueueStatus(); status != got {
+		t.Errorf("Status %+v differs from the rejection %+v", status, got)
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+// What we are missing, we ask our peers for: directory block states when behind, messages
+// missing from the process list, and entries.  Each used to retry on timings of its own;
+// now each kind of ask backs off by its AskPolicy, asking less often the longer it goes
+// unanswered, with jitter so nodes missing the same thing don't all ask at once.
+
+// The kinds of asks, as named in the config and the metrics
+const (
+	AskDBState    = "dbstate"    // Directory block states, when behind
+	AskMissingMsg = "missingmsg" // Messages missing from the process list
+	AskEntry      = "entry"      // Entries missing from the database
+)
+
+// AskPolicy controls how often an ask is sent again while it goes unanswered
+type AskPolicy struct {
+	Initial    int64   // Milliseconds after the first ask before asking again
+	Max        int64   // Longest wait between asks, before jitter
+	Multiplier float64 // Each wait is this many times the one before
+	Jitter     float64 // Up to this fraction of each wait is randomly added to it
+}
+
+// The policies used for asks without one in the config.  The first retries come when
+// they always have.
+var DefaultAskPolicies = map[string]AskPolicy{
+	AskDBState:    {Initial: 6000, Max: 60000, Multiplier: 2, Jitter: 0.2},
+	AskMissingMsg: {Initial: 500, Max: 10000, Multiplier: 2, Jitter: 0.2},
+	AskEntry:      {Initial: 5000, Max: 60000, Multiplier: 2, Jitter: 1},
+}
+
+// Wait returns the milliseconds to wait after the given attempt (1 for the first ask)
+// before asking again
+func (p AskPolicy) Wait(attempt int) int64 {
+	wait := float64(p.Initial)
+	if attempt > 1 && p.Multiplier > 1 {
+		wait *= math.Pow(p.Multiplier, float64(attempt-1))
+	}
+	if p.Max > 0 && wait > float64(p.Max) {
+		wait = float64(p.Max)
+	}
+	if p.Jitter > 0 {
+		wait += rand.Float64() * p.Jitter * wait
+	}
+	return int64(wait)
+}
+
+// GetAskPolicy returns the policy for a kind of ask
+func (s *State) GetAskPolicy(ask string) AskPolicy {
+	if p, ok := s.AskPolicies[ask]; ok {
+		return p
+	}
+	return DefaultAskPolicies[ask]
+}
+
+// AskBackoff tracks the asks for one missing thing.  The zero value is ready to ask.
+type AskBackoff struct {
+	attempts int
+	first    int64 // Time of the first ask (milliseconds)
+	next     int64 // Time the next ask is due (milliseconds)
+}
+
+// Due returns true if it is time to ask (again)
+func (b *AskBackoff) Due(now int64) bool {
+	return now >= b.next
+}
+
+// Attempts returns the asks made so far
+func (b *AskBackoff) Attempts() int {
+	return b.attempts
+}
+
+// Asked records an ask made now, and returns when the next is due
+func (b *AskBackoff) Asked(ask string, policy AskPolicy, now int64) int64 {
+	if b.attempts == 0 {
+		b.first = now
+	}
+	b.attempts++
+	b.next = now + policy.Wait(b.attempts)
+	AskAttemptsVec.WithLabelValues(ask).Inc()
+	return b.next
+}
+
+// Answered records that what was asked for has arrived, and starts over
+func (b *AskBackoff) Answered(ask string, now int64) {
+	if b.attempts > 0 {
+		AskLatencyVec.WithLabelValues(ask).Observe(float64(now-b.first) / 1000)
+	}
+	*b = AskBackoff{}
+}
+
+// ParseAskPolicies parses per ask policies from a comma separated list of
+// ask:initial:max:multiplier:jitter, where the ask is dbstate, missingmsg or entry, and
+// times are milliseconds.
+func ParseAskPolicies(config string) (map[string]AskPolicy, error) {
+	policies := make(map[string]AskPolicy)
+	for _, item := range strings.Split(config, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		fields := strings.Split(item, ":")
+		if len(fields) != 5 {
+			return nil, fmt.Errorf("Ask policy %q should be ask:initial:max:multiplier:jitter", item)
+		}
+		ask := strings.ToLower(strings.TrimSpace(fields[0]))
+		if _, ok := DefaultAskPolicies[ask]; !ok {
+			return nil, fmt.Errorf("Unknown ask %q", fields[0])
+		}
+		var p AskPolicy
+		var err error
+		if p.Initial, err = strconv.ParseInt(strings.TrimSpace(fields[1]), 10, 64); err != nil || p.Initial <= 0 {
+			return nil, fmt.Errorf("Bad initial wait in %q", item)
+		}
+		if p.Max, err = strconv.ParseInt(strings.TrimSpace(fields[2]), 10, 64); err != nil || p.Max < p.Initial {
+			return nil, fmt.Errorf("Bad maximum wait in %q", item)
+		}
+		if p.Multiplier, err = strconv.ParseFloat(strings.TrimSpace(fields[3]), 64); err != nil || p.Multiplier < 1 {
+			return nil, fmt.Errorf("Bad multiplier in %q", item)
+		}
+		if p.Jitter, err = strconv.ParseFloat(strings.TrimSpace(fields[4]), 64); err != nil || p.Jitter < 0 {
+			return nil, fmt.Errorf("Bad jitter in %q", item)
+		}
+		policies[ask] = p
+	}
+	return policies, nil
+}
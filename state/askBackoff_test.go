@@ -0,0 +1,70 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"testing"
+
+	. "github.com/FactomProject/factomd/state"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestAskBackoff(t *testing.T) {
+	policy := AskPolicy{Initial: 1000, Max: 5000, Multiplier: 2}
+	for attempt, want := range []int64{1000, 1000, 2000, 4000, 5000, 5000} {
+		if attempt == 0 {
+			continue
+		}
+		if wait := policy.Wait(attempt); wait != want {
+			t.Errorf("Wait after attempt %d is %d, expected %d", attempt, wait, want)
+		}
+	}
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if wait := policy.Wait(1); wait < 1000 || wait > 1500 {
+			t.Fatalf("Wait %d is out of the jitter", wait)
+		}
+	}
+
+	var b AskBackoff
+	if !b.Due(0) {
+		t.Fatal("A new backoff should be due")
+	}
+	policy.Jitter = 0
+	if next := b.Asked(AskEntry, policy, 10000); next != 11000 || b.Due(10999) || !b.Due(11000) {
+		t.Errorf("First retry due at %d, expected 11000", next)
+	}
+	if next := b.Asked(AskEntry, policy, 11000); next != 13000 || b.Attempts() != 2 {
+		t.Errorf("Second retry due at %d, expected 13000", next)
+	}
+	b.Answered(AskEntry, 12000)
+	if b.Attempts() != 0 || !b.Due(0) {
+		t.Error("Answering should start the backoff over")
+	}
+}
+
+func TestParseAskPolicies(t *testing.T) {
+	policies, err := ParseAskPolicies(" dbstate:1000:8000:1.5:0.1, Entry:200:200:1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p := policies[AskDBState]; p.Initial != 1000 || p.Max != 8000 || p.Multiplier != 1.5 || p.Jitter != 0.1 {
+		t.Errorf("Bad dbstate policy %+v", p)
+	}
+	if p := policies[AskEntry]; p.Initial != 200 || p.Multiplier != 1 {
+		t.Errorf("Bad entry policy %+v", p)
+	}
+	for _, bad := range []string{"dbstate:1000:8000:2", "block:1:1:1:0", "entry:0:1:1:0", "entry:10:5:1:0", "entry:10:10:0.5:0", "entry:10:10:1:-1"} {
+		if _, err := ParseAskPolicies(bad); err == nil {
+			t.Errorf("Expected an error parsing %q", bad)
+		}
+	}
+
+	s := testHelper.CreateEmptyTestState()
+	s.AskPolicies = policies
+	if s.GetAskPolicy(AskDBState) != policies[AskDBState] || s.GetAskPolicy(AskMissingMsg) != DefaultAskPolicies[AskMissingMsg] {
+		t.Error("Expected the configured policy, or the default")
+	}
+}
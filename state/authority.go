@@ -258,6 +258,17 @@ func (st *State) UpdateAuthorityFromABEntry(entry interfaces.IABEntry) error {
 			return err
 		}
 		registerAuthAnchor(b.IdentityChainID, pubKey, b.KeyType, b.KeyPriority, st, "BTC")
+	case constants.TYPE_ADD_ETH_ANCHOR_KEY:
+		e := new(adminBlock.AddFederatedServerEthereumAnchorKey)
+		err := e.UnmarshalBinary(data)
+		if err != nil {
+			return err
+		}
+		address, err := e.EthereumAddress.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		registerAuthAnchor(e.IdentityChainID, address, 0, e.KeyPriority, st, "ETH")
 	}
 	return nil
 }
@@ -0,0 +1,66 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/interfaces"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// On a small network, the operator of a compromised identity could replace the whole
+// authority set within a block or two.  Custom networks limit how many servers a block
+// adds or removes, and how young an identity can be promoted, from the height the limits
+// are activated at; the limits are network constants, so every node holds the same.  Changes past the limits are dropped as they
+// are processed, the same way on every node; they have to be sent again for a later
+// block.
+
+// Why an authority change was dropped
+const (
+	authorityRejectRate = "rate"
+	authorityRejectAge  = "age"
+)
+
+// authorityChangeRejected returns why an AddServer (adding) or RemoveServer of the
+// identity can't take effect in the block at dbheight, or "" if it can
+func (s *State) authorityChangeRejected(dbheight uint32, chainID interfaces.IHash, adding bool) string {
+	limits := constants.NetworkAuthorityLimits(s.GetNetworkID(), dbheight)
+	pl := s.ProcessLists.Get(dbheight)
+	if limits.ChangesPerBlock > 0 && pl != nil && pl.AuthorityChanges >= limits.ChangesPerBlock {
+		return authorityRejectRate
+	}
+	if !adding || limits.MinIdentityAge <= 0 {
+		return ""
+	}
+
+	index := s.isIdentityChain(chainID)
+	if index == -1 {
+		if err := s.AddIdentityFromChainID(chainID); err != nil {
+			return "" // Left to ProcessIdentityToAdminBlock to turn down
+		}
+		if index = s.isIdentityChain(chainID); index == -1 {
+			return ""
+		}
+	}
+	created := s.Identities[index].IdentityCreated
+	if created > dbheight || int(dbheight-created) < limits.MinIdentityAge {
+		return authorityRejectAge
+	}
+	return ""
+}
+
+// rejectAuthorityChange records that a change of the authority set was dropped
+func (s *State) rejectAuthorityChange(dbheight uint32, msg interfaces.IMsg, reason string) {
+	AuthorityChangesRejectedVec.WithLabelValues(reason).Inc()
+	consenLogger.WithFields(msg.LogFields()).WithFields(log.Fields{"dbheight": dbheight, "reason": reason}).Warn("Dropped a change of the authority set")
+}
+
+// authorityChanged counts a server added or removed in the block at dbheight
+func (s *State) authorityChanged(dbheight uint32) {
+	if pl := s.ProcessLists.Get(dbheight); pl != nil {
+		pl.AuthorityChanges++
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/identity"
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+// scheduleActivation sets the height a change takes effect at on custom networks, and
+// returns a func that puts the old height back
+func scheduleActivation(name string, height uint32) func() {
+	for i := range constants.Activations {
+		if constants.Activations[i].Name == name {
+			old := constants.Activations[i].Custom
+			constants.Activations[i].Custom = height
+			return func() { constants.Activations[i].Custom = old }
+		}
+	}
+	return func() {}
+}
+
+func TestAuthorityChangeLimits(t *testing.T) {
+	s := testHelper.CreateEmptyTestState()
+	s.NetworkNumber = constants.NETWORK_CUSTOM
+	s.CustomNetworkID = []byte{1, 2, 3, 4}
+	dbheight := s.LLeaderHeight
+
+	// Custom networks have no limits until they are activated, and have them from the
+	// activation height on
+	if limits := constants.NetworkAuthorityLimits(s.GetNetworkID(), dbheight); limits != (constants.AuthorityLimits{}) {
+		t.Errorf("Custom network limits %+v before they were scheduled", limits)
+	}
+	defer scheduleActivation(constants.ACTIVATION_AUTHORITY_LIMITS, dbheight+1)()
+	if limits := constants.NetworkAuthorityLimits(s.GetNetworkID(), dbheight); limits != (constants.AuthorityLimits{}) {
+		t.Errorf("Custom network limits %+v below the activation height", limits)
+	}
+	limits := constants.NetworkAuthorityLimits(s.GetNetworkID(), dbheight+1)
+	if limits.ChangesPerBlock != 1 || limits.MinIdentityAge != 10 {
+		t.Fatalf("Custom network limits %+v, expected 1 change a block and 10 blocks", limits)
+	}
+	if h := s.GetNetworkParameters().AuthorityChangesPerBlock; h != 0 {
+		t.Errorf("Network parameters give %d changes a block below the activation height", h)
+	}
+
+	// The rest runs at the activation height
+	scheduleActivation(constants.ACTIVATION_AUTHORITY_LIMITS, dbheight)
+	pl := s.ProcessLists.Get(dbheight)
+	s.LeaderPL = pl
+	entries := len(pl.AdminBlock.GetABEntries())
+
+	// An identity created in this very block is too young to be promoted
+	young := primitives.RandomHash()
+	s.Identities = append(s.Identities, &identity.Identity{IdentityChainID: young, IdentityCreated: dbheight})
+	if !s.ProcessAddServer(dbheight, messages.NewAddServerByHashMsg(s, 0, young)) {
+		t.Fatal("Dropped change stalled the process list")
+	}
+	if len(pl.AdminBlock.GetABEntries()) != entries || pl.AuthorityChanges != 0 {
+		t.Error("Promoted an identity younger than MinIdentityAge")
+	}
+
+	// A block that took its changes takes no more, removals included
+	pl.AuthorityChanges = 1
+	fed := primitives.RandomHash()
+	s.Authorities = append(s.Authorities, &identity.Authority{AuthorityChainID: fed, Status: constants.IDENTITY_FEDERATED_SERVER})
+	pl.FedServers = append(pl.FedServers, pl.FedServers[0])
+	if !s.ProcessRemoveServer(dbheight, messages.NewRemoveServerMsg(s, fed, 0)) {
+		t.Fatal("Dropped change stalled the process list")
+	}
+	if len(pl.AdminBlock.GetABEntries()) != entries || pl.AuthorityChanges != 1 {
+		t.Error("Removed a server past ChangesPerBlock")
+	}
+
+	// With room in the block, the removal goes in
+	pl.AuthorityChanges = 0
+	s.ProcessRemoveServer(dbheight, messages.NewRemoveServerMsg(s, fed, 0))
+	if len(pl.AdminBlock.GetABEntries()) != entries+1 || pl.AuthorityChanges != 1 {
+		t.Error("Server not removed within ChangesPerBlock")
+	}
+
+	// Local networks have no limits
+	s.NetworkNumber = constants.NETWORK_LOCAL
+	if limits := constants.NetworkAuthorityLimits(s.GetNetworkID(), dbheight); limits.ChangesPerBlock != 0 || limits.MinIdentityAge != 0 {
+		t.Errorf("Local network limits %+v, expected none", limits)
+	}
+}
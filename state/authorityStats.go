@@ -0,0 +1,132 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+)
+
+// How many message hashes the tracker remembers, so a message validated again isn't
+// counted again
+const authorityStatsSeen = 10000
+
+// AuthorityStatsTracker keeps count of the good and bad signatures we see on messages
+// from each authority, so misconfigured or compromised authority nodes stand out.  Each
+// message is counted once, however often it is validated.
+type AuthorityStatsTracker struct {
+	mutex      sync.Mutex
+	stats      map[[32]byte]*interfaces.AuthorityStats
+	totalDelay map[[32]byte]float64
+	seen       map[[32]byte]bool
+	seenOrder  [][32]byte // The message hashes seen, oldest first
+}
+
+func NewAuthorityStatsTracker() *AuthorityStatsTracker {
+	t := new(AuthorityStatsTracker)
+	t.stats = make(map[[32]byte]*interfaces.AuthorityStats)
+	t.totalDelay = make(map[[32]byte]float64)
+	t.seen = make(map[[32]byte]bool)
+	return t
+}
+
+func (t *AuthorityStatsTracker) get(identity interfaces.IHash) *interfaces.AuthorityStats {
+	k := identity.Fixed()
+	st := t.stats[k]
+	if st == nil {
+		st = new(interfaces.AuthorityStats)
+		st.IdentityChainID = identity.String()
+		t.stats[k] = st
+	}
+	return st
+}
+
+// RecordSignature counts the signature of a message claimed to be from identity, unless
+// the message was counted already
+func (t *AuthorityStatsTracker) RecordSignature(identity interfaces.IHash, msgHash interfaces.IHash, valid bool) {
+	if identity == nil || msgHash == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	h := msgHash.Fixed()
+	if t.seen[h] {
+		return
+	}
+	t.seen[h] = true
+	t.seenOrder = append(t.seenOrder, h)
+	if len(t.seenOrder) > authorityStatsSeen {
+		delete(t.seen, t.seenOrder[0])
+		t.seenOrder = t.seenOrder[1:]
+	}
+
+	st := t.get(identity)
+	if valid {
+		st.ValidSignatures++
+	} else {
+		st.InvalidSignatures++
+	}
+	st.LastMessage = time.Now().Unix()
+}
+
+// RecordDBSigDelay adds how long after the start of a block the DBSig from identity arrived
+func (t *AuthorityStatsTracker) RecordDBSigDelay(identity interfaces.IHash, delay time.Duration) {
+	if identity == nil {
+		return
+	}
+	if delay < 0 {
+		delay = 0
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	k := identity.Fixed()
+	st := t.get(identity)
+	st.DBSigs++
+	t.totalDelay[k] += float64(delay) / float64(time.Millisecond)
+	st.AvgDBSigDelay = t.totalDelay[k] / float64(st.DBSigs)
+}
+
+// Stats returns a copy of the statistics of every identity seen, ordered by identity
+func (t *AuthorityStatsTracker) Stats() []interfaces.AuthorityStats {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	list := make([]interfaces.AuthorityStats, 0, len(t.stats))
+	for _, st := range t.stats {
+		list = append(list, *st)
+	}
+	sort.Sort(byIdentity(list))
+	return list
+}
+
+type byIdentity []interfaces.AuthorityStats
+
+func (a byIdentity) Len() int           { return len(a) }
+func (a byIdentity) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a byIdentity) Less(i, j int) bool { return a[i].IdentityChainID < a[j].IdentityChainID }
+
+// RecordAuthoritySignature counts the signature of a message claimed to be from identity,
+// if it is one of the authorities; anyone can claim to be anyone else
+func (s *State) RecordAuthoritySignature(identity interfaces.IHash, msgHash interfaces.IHash, valid bool) {
+	if s.AuthorityStats == nil || identity == nil {
+		return
+	}
+	if _, kind := s.GetAuthority(identity); kind < 0 {
+		return
+	}
+	s.AuthorityStats.RecordSignature(identity, msgHash, valid)
+}
+
+func (s *State) GetAuthorityStats() []interfaces.AuthorityStats {
+	if s.AuthorityStats == nil {
+		return nil
+	}
+	return s.AuthorityStats.Stats()
+}
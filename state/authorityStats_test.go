@@ -0,0 +1,71 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/identity"
+	"github.com/FactomProject/factomd/common/primitives"
+	. "github.com/FactomProject/factomd/state"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestAuthorityStatsTracker(t *testing.T) {
+	tr := NewAuthorityStatsTracker()
+	a := primitives.Sha([]byte("a"))
+	b := primitives.Sha([]byte("b"))
+
+	tr.RecordSignature(a, primitives.Sha([]byte("1")), true)
+	tr.RecordSignature(a, primitives.Sha([]byte("2")), true)
+	tr.RecordSignature(a, primitives.Sha([]byte("3")), false)
+	tr.RecordSignature(b, primitives.Sha([]byte("4")), false)
+	tr.RecordSignature(nil, primitives.Sha([]byte("5")), false)
+	// A message validated again isn't counted again
+	tr.RecordSignature(a, primitives.Sha([]byte("1")), true)
+	tr.RecordDBSigDelay(a, 100*time.Millisecond)
+	tr.RecordDBSigDelay(a, 300*time.Millisecond)
+	tr.RecordDBSigDelay(b, -time.Second)
+
+	stats := tr.Stats()
+	if len(stats) != 2 {
+		t.Fatalf("Expected stats for 2 identities, found %d", len(stats))
+	}
+	if stats[0].IdentityChainID > stats[1].IdentityChainID {
+		t.Error("Stats are not ordered by identity")
+	}
+	for _, st := range stats {
+		switch st.IdentityChainID {
+		case a.String():
+			if st.ValidSignatures != 2 || st.InvalidSignatures != 1 || st.DBSigs != 2 || st.AvgDBSigDelay != 200 {
+				t.Errorf("Bad stats for a %+v", st)
+			}
+			if st.LastMessage == 0 {
+				t.Error("Last message time not set")
+			}
+		case b.String():
+			if st.ValidSignatures != 0 || st.InvalidSignatures != 1 || st.DBSigs != 1 || st.AvgDBSigDelay != 0 {
+				t.Errorf("Bad stats for b %+v", st)
+			}
+		default:
+			t.Errorf("Unexpected identity %s", st.IdentityChainID)
+		}
+	}
+}
+
+func TestRecordAuthoritySignature(t *testing.T) {
+	s := testHelper.CreateEmptyTestState()
+	fed := primitives.RandomHash()
+	s.Authorities = append(s.Authorities, &identity.Authority{AuthorityChainID: fed, Status: constants.IDENTITY_FEDERATED_SERVER})
+
+	s.RecordAuthoritySignature(fed, primitives.Sha([]byte("1")), true)
+	s.RecordAuthoritySignature(primitives.RandomHash(), primitives.Sha([]byte("2")), false)
+	stats := s.GetAuthorityStats()
+	if len(stats) != 1 || stats[0].IdentityChainID != fed.String() {
+		t.Errorf("Expected stats for the one authority only, got %+v", stats)
+	}
+}
@@ -0,0 +1,189 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+)
+
+// A node too far behind on its inbound queue used to drop missing message requests and
+// responses without a word, so its peers kept asking it.  Now it answers the requests it
+// turns away with a throttle, and peers pass it over for missing message requests until
+// the throttle runs out.  API clients see the same load through the backpressure call,
+// and have new commits rejected while the node is overloaded.
+
+// How long a peer we turn away is asked to go elsewhere, in seconds
+const (
+	throttleRetryBusy       = 5
+	throttleRetryOverloaded = 15
+)
+
+// Backpressure tracks the peers that throttled us, which requests for any peer pass over,
+// and the peers we throttled, so each is told at most once a throttle
+type Backpressure struct {
+	mutex     sync.Mutex
+	throttled map[string]time.Time // Until when each peer that throttled us is passed over
+	told      map[string]time.Time // Until when each peer we throttled stays told
+}
+
+func NewBackpressure() *Backpressure {
+	b := new(Backpressure)
+	b.throttled = make(map[string]time.Time)
+	b.told = make(map[string]time.Time)
+	return b
+}
+
+// expire drops the entries whose time is up
+func expire(until map[string]time.Time, now time.Time) {
+	for peer, t := range until {
+		if !now.Before(t) {
+			delete(until, peer)
+		}
+	}
+}
+
+// Throttle records that a peer asked to be passed over until the time given
+func (b *Backpressure) Throttle(peer string, until time.Time, now time.Time) {
+	if b == nil {
+		return
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	expire(b.throttled, now)
+	b.throttled[peer] = until
+}
+
+// Throttled returns true if a peer asked to be passed over, and the time isn't up.  Always
+// false on a nil tracker.
+func (b *Backpressure) Throttled(peer string) bool {
+	if b == nil {
+		return false
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	until, ok := b.throttled[peer]
+	return ok && time.Now().Before(until)
+}
+
+// Throttling returns how many peers are passed over now
+func (b *Backpressure) Throttling(now time.Time) int {
+	if b == nil {
+		return 0
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	expire(b.throttled, now)
+	return len(b.throttled)
+}
+
+// tell returns true if a peer we turn away is to be sent a throttle running until the time
+// given, false if it was sent one that hasn't run out
+func (b *Backpressure) tell(peer string, until time.Time, now time.Time) bool {
+	if b == nil {
+		return false
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	expire(b.told, now)
+	if _, ok := b.told[peer]; ok {
+		return false
+	}
+	b.told[peer] = until
+	return true
+}
+
+// GetLoadLevel returns how loaded the node is by its inbound queue
+func (s *State) GetLoadLevel() string {
+	switch n := s.inMsgQueue.Length(); {
+	case n > constants.INMSGQUEUE_HIGH:
+		return interfaces.LoadOverloaded
+	case n > constants.INMSGQUEUE_LOW:
+		return interfaces.LoadBusy
+	}
+	return interfaces.LoadNormal
+}
+
+// GetBackpressure returns how saturated the inbound queue is
+func (s *State) GetBackpressure() interfaces.Backpressure {
+	bp := interfaces.Backpressure{
+		Level:          s.GetLoadLevel(),
+		QueueDepth:     s.inMsgQueue.Length(),
+		QueueCap:       s.inMsgQueue.Cap(),
+		BusyAt:         constants.INMSGQUEUE_LOW,
+		OverloadedAt:   constants.INMSGQUEUE_HIGH,
+		ThrottledPeers: s.Backpressure.Throttling(time.Now()),
+	}
+	switch bp.Level {
+	case interfaces.LoadBusy:
+		bp.RetryAfter = throttleRetryBusy
+	case interfaces.LoadOverloaded:
+		bp.RetryAfter = throttleRetryOverloaded
+	}
+	return bp
+}
+
+// ObserveThrottle records a throttle a peer passed us, so missing message requests pass
+// the peer over until it runs out.  Returns true if the message was a throttle.
+func (s *State) ObserveThrottle(peer string, msg interfaces.IMsg) bool {
+	t, ok := msg.(*messages.Throttle)
+	if !ok {
+		return false
+	}
+	if peer == "" || t.Validate(s) < 0 {
+		return true
+	}
+	now := time.Now()
+	s.Backpressure.Throttle(peer, now.Add(time.Duration(t.RetryAfter)*time.Second), now)
+	ThrottlesVec.WithLabelValues("received").Inc()
+	return true
+}
+
+// throttlePeer sends the peer whose request we turn away a throttle, unless it was sent
+// one that hasn't run out yet
+func (s *State) throttlePeer(request interfaces.IMsg) {
+	var level uint8
+	var retry int
+	switch s.GetLoadLevel() {
+	case interfaces.LoadBusy:
+		level, retry = messages.ThrottleBusy, throttleRetryBusy
+	case interfaces.LoadOverloaded:
+		level, retry = messages.ThrottleOverloaded, throttleRetryOverloaded
+	default:
+		return
+	}
+
+	// Peers on the network are known by their hash, simulated ones by their index
+	peer := request.GetNetworkOrigin()
+	if peer == "" {
+		if request.GetOrigin() <= 0 {
+			return
+		}
+		peer = fmt.Sprintf("%d", request.GetOrigin())
+	}
+	now := time.Now()
+	if !s.Backpressure.tell(peer, now.Add(time.Duration(retry)*time.Second), now) {
+		return
+	}
+	s.NetworkOutMsgQueue().Enqueue(messages.NewThrottle(s, request, level, s.inMsgQueue.Length(), retry))
+	ThrottlesVec.WithLabelValues("sent").Inc()
+}
+
+// updateLoadLevel publishes the load level
+func (s *State) updateLoadLevel() {
+	switch s.GetLoadLevel() {
+	case interfaces.LoadOverloaded:
+		BackpressureLevel.Set(2)
+	case interfaces.LoadBusy:
+		BackpressureLevel.Set(1)
+	default:
+		BackpressureLevel.Set(0)
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+	. "github.com/FactomProject/factomd/state"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestBackpressureThrottled(t *testing.T) {
+	b := NewBackpressure()
+	now := time.Now()
+	b.Throttle("a", now.Add(time.Minute), now)
+	b.Throttle("b", now.Add(-time.Second), now)
+
+	if !b.Throttled("a") || b.Throttled("b") || b.Throttled("c") {
+		t.Error("Expected only the peer whose throttle hasn't run out to be passed over")
+	}
+	if n := b.Throttling(now); n != 1 {
+		t.Errorf("Throttling %d peers, expected 1", n)
+	}
+
+	var none *Backpressure
+	if none.Throttled("a") || none.Throttling(now) != 0 {
+		t.Error("A nil tracker passed peers over")
+	}
+}
+
+// fillInMsgQueue puts n messages in the inbound queue of a state
+func fillInMsgQueue(s *State, n int) {
+	for i := s.InMsgQueue().Length(); i < n; i++ {
+		s.InMsgQueue().Enqueue(newSigTestCommit(i, true))
+	}
+}
+
+func TestGetBackpressure(t *testing.T) {
+	s := testHelper.CreateEmptyTestState()
+	if bp := s.GetBackpressure(); bp.Level != interfaces.LoadNormal || bp.RetryAfter != 0 {
+		t.Errorf("Empty queue got %+v", bp)
+	}
+
+	fillInMsgQueue(s, constants.INMSGQUEUE_LOW+1)
+	if bp := s.GetBackpressure(); bp.Level != interfaces.LoadBusy || bp.RetryAfter <= 0 || bp.QueueDepth != constants.INMSGQUEUE_LOW+1 {
+		t.Errorf("Busy queue got %+v", bp)
+	}
+
+	fillInMsgQueue(s, constants.INMSGQUEUE_HIGH+1)
+	if bp := s.GetBackpressure(); bp.Level != interfaces.LoadOverloaded || bp.OverloadedAt != constants.INMSGQUEUE_HIGH {
+		t.Errorf("Overloaded queue got %+v", bp)
+	}
+}
+
+func TestThrottleMissingMsg(t *testing.T) {
+	s := testHelper.CreateEmptyTestState()
+	fillInMsgQueue(s, constants.INMSGQUEUE_LOW+1)
+	for s.NetworkOutMsgQueue().Length() > 0 {
+		s.NetworkOutMsgQueue().Dequeue()
+	}
+
+	request := messages.NewMissingMsg(s, 0, s.LLeaderHeight, 0)
+	request.SetNetworkOrigin("peer")
+	s.FollowerExecuteMissingMsg(request)
+	s.FollowerExecuteMissingMsg(request)
+
+	if n := s.NetworkOutMsgQueue().Length(); n != 1 {
+		t.Fatalf("Sent %d messages, expected one throttle", n)
+	}
+	throttle, ok := s.NetworkOutMsgQueue().Dequeue().(*messages.Throttle)
+	if !ok {
+		t.Fatal("Turned the request away with something other than a throttle")
+	}
+	if throttle.Level != messages.ThrottleBusy || throttle.GetNetworkOrigin() != "peer" || throttle.Validate(s) != 1 {
+		t.Errorf("Unexpected throttle %v to %q", throttle, throttle.GetNetworkOrigin())
+	}
+
+	// The requester takes it, and passes us over
+	if !s.ObserveThrottle("us", throttle) || !s.Backpressure.Throttled("us") {
+		t.Error("Throttle not observed")
+	}
+	if s.ObserveThrottle("us", request) {
+		t.Error("Observed a missing message request as a throttle")
+	}
+}
+
+func TestSubmitAPIMsgOverloaded(t *testing.T) {
+	s := testHelper.CreateEmptyTestState()
+	fillInMsgQueue(s, constants.INMSGQUEUE_HIGH+1)
+
+	got := s.SubmitAPIMsg(newSigTestCommit(2000, true))
+	if got.Status != interfaces.APISubmitRejected || !got.Overloaded || got.RetryAfter <= 0 {
+		t.Errorf("Commit submitted while overloaded got %+v", got)
+	}
+	if s.APIQueue().Length() != 0 {
+		t.Error("Commit submitted while overloaded was queued")
+	}
+
+	// Reveals of commits already taken still go through
+	reveal := messages.NewRevealEntryMsg()
+	reveal.Entry = testHelper.CreateTestEntry(1)
+	reveal.Timestamp = s.GetTimestamp()
+	if got := s.SubmitAPIMsg(reveal); got.Status == interfaces.APISubmitRejected {
+		t.Errorf("Reveal submitted while overloaded got %+v", got)
+	}
+}
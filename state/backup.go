@@ -0,0 +1,62 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync/atomic"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+)
+
+// BackupDatabase writes a consistent copy of the database to path, and returns the
+// directory block height the copy holds.  Saving blocks is held off only long enough to
+// take a snapshot, so the copy always ends on a complete block; the copying itself runs
+// while the node carries on.  Entries are synced separately from blocks, so the copy may
+// be missing some recent entries, which are fetched again when it is booted.  Nodes
+// with cold storage on can't be backed up, as the blocks moved to the cold store would be
+// missing from the copy.
+func (s *State) BackupDatabase(path string) (uint32, error) {
+	if !filepath.IsAbs(path) {
+		return 0, fmt.Errorf("Backup path must be absolute")
+	}
+	if !atomic.CompareAndSwapInt32(&s.backupRunning, 0, 1) {
+		return 0, fmt.Errorf("A backup is already running")
+	}
+	defer atomic.StoreInt32(&s.backupRunning, 0)
+
+	overlay, ok := s.DB.(*databaseOverlay.Overlay)
+	if !ok {
+		return 0, fmt.Errorf("Database does not support backups")
+	}
+	if _, ok := overlay.DB.(interfaces.ITieredDatabase); ok {
+		return 0, fmt.Errorf("Backups are not supported with cold storage on; back up the database and ColdStoragePath while the node is stopped")
+	}
+	snapper, ok := overlay.DB.(interfaces.ISnapshotDatabase)
+	if !ok {
+		return 0, fmt.Errorf("%s databases do not support backups", s.DBType)
+	}
+
+	s.DBSaveMutex.Lock()
+	head, err := overlay.FetchDBlockHead()
+	if err != nil || head == nil {
+		s.DBSaveMutex.Unlock()
+		return 0, fmt.Errorf("Cannot find the directory block head: %v", err)
+	}
+	snap, err := snapper.Snapshot()
+	s.DBSaveMutex.Unlock()
+	if err != nil {
+		return 0, err
+	}
+	defer snap.Release()
+
+	height := head.GetDatabaseHeight()
+	if err := snap.WriteTo(path); err != nil {
+		return 0, err
+	}
+	return height, nil
+}
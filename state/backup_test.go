@@ -0,0 +1,52 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+	"github.com/FactomProject/factomd/database/mapdb"
+	"github.com/FactomProject/factomd/database/tieredDB"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestBackupDatabaseUnsupported(t *testing.T) {
+	s := testHelper.CreateAndPopulateTestState()
+
+	if _, err := s.BackupDatabase("relative/path"); err == nil {
+		t.Error("Expected an error for a relative backup path")
+	}
+	// The test state runs on a map database, which can't be snapshotted
+	if _, err := s.BackupDatabase("/tmp/factomd-backup-test"); err == nil {
+		t.Error("Expected an error backing up a map database")
+	}
+}
+
+func TestBackupDatabaseColdStorage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "backupCold")
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	defer os.RemoveAll(dir)
+	cold, err := tieredDB.NewDirColdStore(dir)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	hot := new(mapdb.MapDB)
+	hot.Init(nil)
+
+	s := testHelper.CreateEmptyTestState()
+	s.DB = databaseOverlay.NewOverlay(tieredDB.NewTieredDB(hot, cold, 0))
+
+	// Refused before any snapshot is attempted, as the copy would be missing the cold store
+	_, err = s.BackupDatabase("/tmp/factomd-backup-test")
+	if err == nil || !strings.Contains(err.Error(), "cold storage") {
+		t.Errorf("Expected cold storage to be refused, got %v", err)
+	}
+}
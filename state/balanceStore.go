@@ -0,0 +1,215 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"sync"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+)
+
+// Balances are split over this many shards by the first byte of the address.  Addresses
+// are hashes, so they spread evenly, and a block only touches a few of the shards.
+const balanceShards = 256
+
+type balanceShard struct {
+	mutex     sync.RWMutex
+	live      map[[32]byte]int64
+	published map[[32]byte]int64 // Never written once published
+	dirty     bool               // live has changed since it was published
+	shared    bool               // live is the published map, so is copied before it is written
+}
+
+// BalanceStore holds factoid or entry credit balances.  Each shard has its own lock, so
+// API reads of one address don't wait on consensus writing another.  Publish hands the
+// shards written since the last snapshot to the new one as they are, and a shard is only
+// copied when it is next written.
+type BalanceStore struct {
+	shards [balanceShards]balanceShard
+}
+
+// BalanceSnapshot is the balances as they were when published.  It is never changed, so
+// it is read without locking.
+type BalanceSnapshot struct {
+	shards [balanceShards]map[[32]byte]int64
+}
+
+func NewBalanceStore() *BalanceStore {
+	b := new(BalanceStore)
+	for i := range b.shards {
+		b.shards[i].live = map[[32]byte]int64{}
+	}
+	return b
+}
+
+func (b *BalanceStore) shard(address [32]byte) *balanceShard {
+	return &b.shards[address[0]]
+}
+
+// Get returns the balance of address, and whether we have one for it
+func (b *BalanceStore) Get(address [32]byte) (v int64, ok bool) {
+	if b == nil {
+		return 0, false
+	}
+	sh := b.shard(address)
+	sh.mutex.RLock()
+	defer sh.mutex.RUnlock()
+	v, ok = sh.live[address]
+	return
+}
+
+func (b *BalanceStore) Put(address [32]byte, v int64) {
+	sh := b.shard(address)
+	sh.mutex.Lock()
+	defer sh.mutex.Unlock()
+	sh.unshare()
+	sh.live[address] = v
+	sh.dirty = true
+}
+
+func (b *BalanceStore) Delete(address [32]byte) {
+	sh := b.shard(address)
+	sh.mutex.Lock()
+	defer sh.mutex.Unlock()
+	sh.unshare()
+	delete(sh.live, address)
+	sh.dirty = true
+}
+
+// unshare copies the live map of a shard if a snapshot holds it, so it can be written.  The
+// caller must hold the write lock.
+func (sh *balanceShard) unshare() {
+	if !sh.shared {
+		return
+	}
+	live := make(map[[32]byte]int64, len(sh.live))
+	for k, v := range sh.live {
+		live[k] = v
+	}
+	sh.live = live
+	sh.shared = false
+}
+
+// Len returns the number of addresses with a balance
+func (b *BalanceStore) Len() (n int) {
+	for i := range b.shards {
+		sh := &b.shards[i]
+		sh.mutex.RLock()
+		n += len(sh.live)
+		sh.mutex.RUnlock()
+	}
+	return n
+}
+
+// lockAll takes the lock of every shard, in order, so the store is seen or changed as a
+// whole.  Put and Delete take only one, so can't deadlock with it.
+func (b *BalanceStore) lockAll(write bool) {
+	for i := range b.shards {
+		if write {
+			b.shards[i].mutex.Lock()
+		} else {
+			b.shards[i].mutex.RLock()
+		}
+	}
+}
+
+func (b *BalanceStore) unlockAll(write bool) {
+	for i := range b.shards {
+		if write {
+			b.shards[i].mutex.Unlock()
+		} else {
+			b.shards[i].mutex.RUnlock()
+		}
+	}
+}
+
+// Copy returns all the balances in one map, as they were at one moment
+func (b *BalanceStore) Copy() map[[32]byte]int64 {
+	m := make(map[[32]byte]int64)
+	if b == nil {
+		return m
+	}
+	b.lockAll(false)
+	defer b.unlockAll(false)
+	for i := range b.shards {
+		for k, v := range b.shards[i].live {
+			m[k] = v
+		}
+	}
+	return m
+}
+
+// Replace sets the balances to those in m, or to none if m is nil.  The shards are built
+// first, then swapped in all at once, so no reader sees the store part way through.
+func (b *BalanceStore) Replace(m map[[32]byte]int64) {
+	var live [balanceShards]map[[32]byte]int64
+	for i := range live {
+		live[i] = map[[32]byte]int64{}
+	}
+	for k, v := range m {
+		live[k[0]][k] = v
+	}
+
+	b.lockAll(true)
+	defer b.unlockAll(true)
+	for i := range b.shards {
+		b.shards[i].live = live[i]
+		b.shards[i].dirty = true
+		b.shards[i].shared = false
+	}
+}
+
+// Clear removes every balance
+func (b *BalanceStore) Clear() {
+	b.Replace(nil)
+}
+
+// Publish returns a snapshot of the balances, as they were at one moment.  Nothing is
+// copied: the snapshot takes the live map of each shard written since the last one, and
+// the shard copies it on its next write.  Only those shards are locked, one at a time; the
+// balances are only written from the consensus loop, which is where Publish is called, so
+// no shard changes while the others are taken.
+func (b *BalanceStore) Publish() *BalanceSnapshot {
+	snap := new(BalanceSnapshot)
+	for i := range b.shards {
+		sh := &b.shards[i]
+		sh.mutex.RLock()
+		changed := sh.dirty || sh.published == nil
+		sh.mutex.RUnlock()
+		if changed {
+			sh.mutex.Lock()
+			sh.published = sh.live
+			sh.shared = true
+			sh.dirty = false
+			sh.mutex.Unlock()
+		}
+		snap.shards[i] = sh.published
+	}
+	return snap
+}
+
+// Hash returns the hash GetMapHash gives for the balances, without copying them into a
+// map first
+func (b *BalanceStore) Hash(dbheight uint32) interfaces.IHash {
+	if b == nil {
+		return getElementsHash(dbheight, nil)
+	}
+	list := make([]*element, 0, b.Len())
+	b.lockAll(false)
+	for i := range b.shards {
+		for k, v := range b.shards[i].live {
+			list = append(list, &element{adr: k, v: v})
+		}
+	}
+	b.unlockAll(false)
+	return getElementsHash(dbheight, list)
+}
+
+func (s *BalanceSnapshot) Get(address [32]byte) int64 {
+	if s == nil {
+		return 0
+	}
+	return s.shards[address[0]][address]
+}
@@ -0,0 +1,177 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/FactomProject/factomd/common/primitives"
+	. "github.com/FactomProject/factomd/state"
+)
+
+func TestBalanceStore(t *testing.T) {
+	b := NewBalanceStore()
+	var addresses [][32]byte
+	for i := 0; i < 1000; i++ {
+		a := primitives.Sha([]byte{byte(i), byte(i >> 8)}).Fixed()
+		addresses = append(addresses, a)
+		b.Put(a, int64(i))
+	}
+	if b.Len() != 1000 {
+		t.Errorf("Expected 1000 balances, found %d", b.Len())
+	}
+	if v, ok := b.Get(addresses[10]); !ok || v != 10 {
+		t.Errorf("Expected 10, found %d %v", v, ok)
+	}
+	missing := primitives.Sha([]byte("missing")).Fixed()
+	if _, ok := b.Get(missing); ok {
+		t.Error("Found a balance never put")
+	}
+
+	first := b.Publish()
+	b.Put(addresses[10], 99)
+	b.Delete(addresses[11])
+	if first.Get(addresses[10]) != 10 || first.Get(addresses[11]) != 11 {
+		t.Error("Published snapshot changed")
+	}
+	second := b.Publish()
+	if second.Get(addresses[10]) != 99 || second.Get(addresses[11]) != 0 || second.Get(addresses[12]) != 12 {
+		t.Errorf("Unexpected snapshot %d %d %d", second.Get(addresses[10]), second.Get(addresses[11]), second.Get(addresses[12]))
+	}
+
+	// The shard the second snapshot took is copied before it is written again
+	b.Put(addresses[12], 42)
+	if second.Get(addresses[12]) != 12 {
+		t.Error("Write after publishing changed the snapshot")
+	}
+	b.Put(addresses[12], 12)
+	if b.Hash(1).String() != GetMapHash(1, b.Copy()).String() {
+		t.Error("Balances hash differently than their copy")
+	}
+
+	m := b.Copy()
+	if len(m) != 999 || m[addresses[10]] != 99 {
+		t.Errorf("Unexpected copy of %d balances", len(m))
+	}
+	restored := NewBalanceStore()
+	restored.Replace(m)
+	if GetMapHash(1, restored.Copy()).String() != GetMapHash(1, m).String() {
+		t.Error("Replaced balances hash differently")
+	}
+	b.Clear()
+	if b.Len() != 0 || second.Get(addresses[12]) != 12 {
+		t.Error("Clear didn't remove only the live balances")
+	}
+}
+
+// mutexBalances is how balances were held before they were sharded: one map, one lock
+type mutexBalances struct {
+	mutex    sync.Mutex
+	balances map[[32]byte]int64
+}
+
+func (m *mutexBalances) Get(a [32]byte) (int64, bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	v, ok := m.balances[a]
+	return v, ok
+}
+
+func (m *mutexBalances) Put(a [32]byte, v int64) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.balances[a] = v
+}
+
+type balances interface {
+	Get([32]byte) (int64, bool)
+	Put([32]byte, int64)
+}
+
+// benchmarkBalances reads and writes random addresses from every goroutine, writing one
+// time in writeEvery
+func benchmarkBalances(b *testing.B, store balances, writeEvery int) {
+	addresses := make([][32]byte, 10000)
+	for i := range addresses {
+		addresses[i] = primitives.Sha([]byte{byte(i), byte(i >> 8)}).Fixed()
+		store.Put(addresses[i], int64(i))
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		r := rand.New(rand.NewSource(rand.Int63()))
+		for i := 0; pb.Next(); i++ {
+			a := addresses[r.Intn(len(addresses))]
+			if i%writeEvery == 0 {
+				store.Put(a, int64(i))
+			} else {
+				store.Get(a)
+			}
+		}
+	})
+}
+
+func BenchmarkBalancesMutex(b *testing.B) {
+	for _, mix := range []struct {
+		name       string
+		writeEvery int
+	}{{"1in2", 2}, {"1in10", 10}, {"1in100", 100}} {
+		b.Run(mix.name, func(b *testing.B) {
+			benchmarkBalances(b, &mutexBalances{balances: map[[32]byte]int64{}}, mix.writeEvery)
+		})
+	}
+}
+
+func BenchmarkBalancesSharded(b *testing.B) {
+	for _, mix := range []struct {
+		name       string
+		writeEvery int
+	}{{"1in2", 2}, {"1in10", 10}, {"1in100", 100}} {
+		b.Run(mix.name, func(b *testing.B) {
+			benchmarkBalances(b, NewBalanceStore(), mix.writeEvery)
+		})
+	}
+}
+
+func TestBalanceStoreReplaceWhole(t *testing.T) {
+	// Two sets of balances over every shard, one all 1s and one all 2s
+	ones, twos := map[[32]byte]int64{}, map[[32]byte]int64{}
+	for i := 0; i < 256; i++ {
+		var a [32]byte
+		a[0], a[1] = byte(i), 1
+		ones[a] = 1
+		a[1] = 2
+		twos[a] = 2
+	}
+	b := NewBalanceStore()
+	b.Replace(ones)
+
+	done := make(chan bool)
+	go func() {
+		for i := 0; i < 200; i++ {
+			if i%2 == 0 {
+				b.Replace(twos)
+			} else {
+				b.Replace(ones)
+			}
+		}
+		close(done)
+	}()
+	for {
+		select {
+		case <-done:
+			return
+		default:
+		}
+		seen := map[int64]bool{}
+		for _, v := range b.Copy() {
+			seen[v] = true
+		}
+		if len(seen) != 1 {
+			t.Fatalf("Copy saw the store part way through a Replace: %v", seen)
+		}
+	}
+}
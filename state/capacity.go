@@ -0,0 +1,206 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/interfaces"
+)
+
+// The capacity tracker samples the entries processed and the size of the database once
+// a minute, and keeps a day of samples
+const (
+	capacitySampleInterval = time.Minute
+	capacitySamples        = 24 * 60
+	capacityRateSamples    = 60 // The entry rate is the sustained rate of the last hour
+)
+
+type capacitySample struct {
+	at      time.Time
+	entries int
+	dbBytes int64
+}
+
+// CapacityTracker gathers what the capacity report needs that isn't there to read when
+// it is asked for: the rate entries come in, the time they take to execute, and how fast
+// the database grows.
+type CapacityTracker struct {
+	mutex       sync.Mutex
+	samples     []capacitySample
+	execTime    time.Duration // Time spent executing commits and reveals
+	execCount   int64
+	dbPath      string
+	dbBytes     int64
+	diskFree    uint64
+	diskFreeSet bool
+}
+
+func NewCapacityTracker(dbPath string) *CapacityTracker {
+	t := new(CapacityTracker)
+	t.dbPath = dbPath
+	return t
+}
+
+// ObserveExecute adds the time a message took to execute, if it is a commit or a reveal
+func (t *CapacityTracker) ObserveExecute(msgType byte, d time.Duration) {
+	if t == nil {
+		return
+	}
+	switch msgType {
+	case constants.COMMIT_CHAIN_MSG, constants.COMMIT_ENTRY_MSG, constants.REVEAL_ENTRY_MSG:
+	default:
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.execTime += d
+	t.execCount++
+}
+
+// Sample records the entries processed so far, and measures the database
+func (t *CapacityTracker) Sample(now time.Time, entries int) {
+	if t == nil {
+		return
+	}
+	dbBytes := dirBytes(t.dbPath)
+	free, ok := diskFree(t.dbPath)
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.samples = append(t.samples, capacitySample{at: now, entries: entries, dbBytes: dbBytes})
+	if len(t.samples) > capacitySamples {
+		t.samples = t.samples[len(t.samples)-capacitySamples:]
+	}
+	t.dbBytes = dbBytes
+	t.diskFree, t.diskFreeSet = free, ok
+}
+
+// dirBytes returns the size of the files under path, 0 if there is no path
+func dirBytes(path string) int64 {
+	if path == "" {
+		return 0
+	}
+	var total int64
+	filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			total += info.Size()
+		}
+		return nil
+	})
+	return total
+}
+
+// Report fills in the rates, the database growth and the projection of the disk from
+// the samples
+func (t *CapacityTracker) Report(r *interfaces.CapacityReport) {
+	r.DaysUntilDiskFull = -1
+	if t == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.execCount > 0 {
+		// An entry is a commit and a reveal
+		perEntry := 2 * t.execTime.Seconds() / float64(t.execCount)
+		r.EntryExecuteMs = perEntry * 1000
+		if perEntry > 0 {
+			r.MaxEntriesPerSecond = 1 / perEntry
+		}
+	}
+
+	n := len(t.samples)
+	if n >= 2 {
+		first, last := t.samples[0], t.samples[n-1]
+		if n > capacityRateSamples {
+			first = t.samples[n-1-capacityRateSamples]
+		}
+		if d := last.at.Sub(first.at); d > 0 {
+			r.WindowSeconds = int64(d.Seconds())
+			r.EntriesPerSecond = float64(last.entries-first.entries) / d.Seconds()
+		}
+
+		first = t.samples[0]
+		if d := last.at.Sub(first.at); d > 0 {
+			r.GrowthWindowSeconds = int64(d.Seconds())
+			r.GrowthBytesPerDay = float64(last.dbBytes-first.dbBytes) / d.Hours() * 24
+		}
+	}
+	if r.MaxEntriesPerSecond > 0 {
+		r.Utilization = r.EntriesPerSecond / r.MaxEntriesPerSecond
+	}
+
+	r.DatabaseBytes = t.dbBytes
+	if t.diskFreeSet {
+		r.DiskFreeBytes = t.diskFree
+		if r.GrowthBytesPerDay > 0 {
+			r.DaysUntilDiskFull = float64(t.diskFree) / r.GrowthBytesPerDay
+		}
+	}
+}
+
+// gcPauses returns the percentiles of the recent pauses of the garbage collector
+func gcPauses() interfaces.GCPauseStats {
+	// 101 quantiles, from the shortest pause to the longest a percent apart
+	stats := debug.GCStats{PauseQuantiles: make([]time.Duration, 101)}
+	debug.ReadGCStats(&stats)
+	ms := func(d time.Duration) float64 { return float64(d) / float64(time.Millisecond) }
+
+	p := interfaces.GCPauseStats{Count: stats.NumGC}
+	if stats.NumGC > 0 {
+		p.P50 = ms(stats.PauseQuantiles[50])
+		p.P90 = ms(stats.PauseQuantiles[90])
+		p.P99 = ms(stats.PauseQuantiles[99])
+		p.Max = ms(stats.PauseQuantiles[100])
+	}
+	return p
+}
+
+func queueHeadroom(name string, depth, capacity int) interfaces.QueueHeadroom {
+	q := interfaces.QueueHeadroom{Name: name, Depth: depth, Cap: capacity}
+	if capacity > 0 {
+		q.Headroom = 1 - float64(depth)/float64(capacity)
+	}
+	return q
+}
+
+// databasePath returns the directory of the database of this node, "" for a map database
+func (s *State) databasePath() string {
+	switch s.DBType {
+	case "LDB":
+		return filepath.Join(s.LdbPath, s.Network)
+	case "Bolt":
+		return filepath.Join(s.BoltDBPath, s.Network)
+	case "Badger":
+		return filepath.Join(s.BadgerPath, s.Network)
+	}
+	return ""
+}
+
+// sampleCapacity records the entries processed so far and the size of the database
+func (s *State) sampleCapacity() {
+	s.Capacity.Sample(time.Now(), s.NewEntryChains+s.NewEntries)
+}
+
+// GetCapacityReport returns the load this node sustains against what it could take, the
+// headroom of its queues, the growth of its database and its garbage collector pauses
+func (s *State) GetCapacityReport() interfaces.CapacityReport {
+	var r interfaces.CapacityReport
+	s.Capacity.Report(&r)
+	r.Queues = []interfaces.QueueHeadroom{
+		queueHeadroom("inmsg", s.InMsgQueue().Length(), s.InMsgQueue().Cap()),
+		queueHeadroom("api", s.APIQueue().Length(), s.APIQueue().Cap()),
+		queueHeadroom("netout", s.NetworkOutMsgQueue().Length(), s.NetworkOutMsgQueue().Cap()),
+		queueHeadroom("msg", len(s.MsgQueue()), cap(s.MsgQueue())),
+	}
+	r.GCPauses = gcPauses()
+	return r
+}
@@ -0,0 +1,59 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/interfaces"
+	. "github.com/FactomProject/factomd/state"
+)
+
+func TestCapacityTracker(t *testing.T) {
+	dir, err := ioutil.TempDir("", "capacity")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ct := NewCapacityTracker(dir)
+	ct.ObserveExecute(constants.COMMIT_ENTRY_MSG, 2*time.Millisecond)
+	ct.ObserveExecute(constants.REVEAL_ENTRY_MSG, 2*time.Millisecond)
+	ct.ObserveExecute(constants.ACK_MSG, time.Second) // Not an entry, so not counted
+
+	now := time.Now()
+	ct.Sample(now, 0)
+	ioutil.WriteFile(filepath.Join(dir, "data"), make([]byte, 1000), 0644)
+	ct.Sample(now.Add(time.Minute), 600)
+
+	var r interfaces.CapacityReport
+	ct.Report(&r)
+	if r.EntryExecuteMs != 4 || r.MaxEntriesPerSecond != 250 {
+		t.Errorf("Expected 4ms an entry and 250 entries/sec at most, got %vms and %v", r.EntryExecuteMs, r.MaxEntriesPerSecond)
+	}
+	if r.EntriesPerSecond != 10 || r.Utilization != 0.04 || r.WindowSeconds != 60 {
+		t.Errorf("Expected 10 entries/sec over 60s, 4%% utilized, got %v over %vs, %v", r.EntriesPerSecond, r.WindowSeconds, r.Utilization)
+	}
+	if r.DatabaseBytes != 1000 || r.GrowthBytesPerDay != 1000*24*60 {
+		t.Errorf("Expected 1000 bytes growing 1000 a minute, got %v growing %v a day", r.DatabaseBytes, r.GrowthBytesPerDay)
+	}
+	if r.DiskFreeBytes > 0 && r.DaysUntilDiskFull != float64(r.DiskFreeBytes)/r.GrowthBytesPerDay {
+		t.Errorf("Expected the disk to fill in %v days, got %v", float64(r.DiskFreeBytes)/r.GrowthBytesPerDay, r.DaysUntilDiskFull)
+	}
+
+	var nilTracker *CapacityTracker
+	nilTracker.ObserveExecute(constants.COMMIT_ENTRY_MSG, time.Millisecond)
+	nilTracker.Sample(now, 1)
+	r = interfaces.CapacityReport{}
+	nilTracker.Report(&r)
+	if r.EntriesPerSecond != 0 || r.DaysUntilDiskFull != -1 {
+		t.Errorf("Expected a nil tracker to report nothing, got %+v", r)
+	}
+}
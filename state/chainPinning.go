@@ -0,0 +1,302 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var pinLogger = packageLogger.WithFields(log.Fields{"subpack": "chain-pinning"})
+
+// An entry a backfill asked for is only asked for again after this long, if it still
+// hasn't arrived
+var PinRequeueAfter = 10 * time.Minute
+
+// PinnedChainTracker holds the chains whose full entry history this node always keeps,
+// even when it is otherwise pruning, and how far their backfill has got.
+type PinnedChainTracker struct {
+	mutex  sync.Mutex
+	chains map[[32]byte]*pinnedChain
+}
+
+// pinnedChain is the backfill of one chain.  The cursor is the newest entry block which,
+// with every block before it, has all its entries, so a pass stops there.
+type pinnedChain struct {
+	status        interfaces.PinnedChainStatus
+	cursor        [32]byte
+	cursorEBlocks int                    // Entry blocks up to and including the cursor
+	cursorEntries int                    // Entries in them
+	queued        map[[32]byte]time.Time // Entries asked for, and when
+}
+
+func NewPinnedChainTracker(chainIDs []interfaces.IHash) *PinnedChainTracker {
+	t := new(PinnedChainTracker)
+	t.chains = make(map[[32]byte]*pinnedChain)
+	for _, id := range chainIDs {
+		t.Pin(id)
+	}
+	return t
+}
+
+// Pin adds a chain to the pinned set
+func (t *PinnedChainTracker) Pin(chainID interfaces.IHash) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if _, ok := t.chains[chainID.Fixed()]; !ok {
+		t.chains[chainID.Fixed()] = &pinnedChain{
+			status: interfaces.PinnedChainStatus{ChainID: chainID.String()},
+			queued: make(map[[32]byte]time.Time),
+		}
+	}
+}
+
+// Unpin removes a chain from the pinned set
+func (t *PinnedChainTracker) Unpin(chainID interfaces.IHash) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	delete(t.chains, chainID.Fixed())
+}
+
+// IsPinned returns true if the chain's history is always retained
+func (t *PinnedChainTracker) IsPinned(chainID interfaces.IHash) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	_, ok := t.chains[chainID.Fixed()]
+	return ok
+}
+
+// ChainIDs returns the pinned chains
+func (t *PinnedChainTracker) ChainIDs() []interfaces.IHash {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	var ids []interfaces.IHash
+	for k := range t.chains {
+		ids = append(ids, primitives.NewHash(k[:]))
+	}
+	return ids
+}
+
+// backfill returns a copy of the backfill of a chain, nil if it isn't pinned
+func (t *PinnedChainTracker) backfill(chainID interfaces.IHash) *pinnedChain {
+	if t == nil {
+		return nil
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	c, ok := t.chains[chainID.Fixed()]
+	if !ok {
+		return nil
+	}
+	cp := *c
+	cp.queued = make(map[[32]byte]time.Time, len(c.queued))
+	for k, v := range c.queued {
+		cp.queued[k] = v
+	}
+	return &cp
+}
+
+// setBackfill records a pass over a chain
+func (t *PinnedChainTracker) setBackfill(chainID interfaces.IHash, c *pinnedChain) {
+	if t == nil || c == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	// Only update chains that were not unpinned while we were scanning them
+	if _, ok := t.chains[chainID.Fixed()]; ok {
+		t.chains[chainID.Fixed()] = c
+	}
+}
+
+// Status returns the backfill status of every pinned chain, ordered by chain id
+func (t *PinnedChainTracker) Status() []interfaces.PinnedChainStatus {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	var list []interfaces.PinnedChainStatus
+	for _, c := range t.chains {
+		list = append(list, c.status)
+	}
+	sort.Sort(byChainID(list))
+	return list
+}
+
+type byChainID []interfaces.PinnedChainStatus
+
+func (b byChainID) Len() int           { return len(b) }
+func (b byChainID) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
+func (b byChainID) Less(i, j int) bool { return b[i].ChainID < b[j].ChainID }
+
+// ParsePinnedChains parses a comma separated list of chain ids
+func ParsePinnedChains(config string) ([]interfaces.IHash, error) {
+	var ids []interfaces.IHash
+	for _, item := range strings.Split(config, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if len(item) != 64 {
+			return nil, fmt.Errorf("Pinned chain %q is not a chain id", item)
+		}
+		id, err := primitives.HexToHash(item)
+		if err != nil {
+			return nil, fmt.Errorf("Pinned chain %q is not a chain id: %v", item, err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// IsChainPinned returns true if the full entry history of the chain must be kept.  Any
+// pruning has to leave the entries and entry blocks of pinned chains alone.
+func (s *State) IsChainPinned(chainID interfaces.IHash) bool {
+	if s.PinnedChains == nil || chainID == nil {
+		return false
+	}
+	return s.PinnedChains.IsPinned(chainID)
+}
+
+// PinChain pins or unpins a chain while the node is running
+func (s *State) PinChain(chainID interfaces.IHash, pin bool) {
+	if s.PinnedChains == nil {
+		return
+	}
+	if pin {
+		s.PinnedChains.Pin(chainID)
+	} else {
+		s.PinnedChains.Unpin(chainID)
+	}
+}
+
+// GetPinnedChains returns the backfill status of each pinned chain
+func (s *State) GetPinnedChains() []interfaces.PinnedChainStatus {
+	if s.PinnedChains == nil {
+		return nil
+	}
+	return s.PinnedChains.Status()
+}
+
+// GoBackfillPinnedChains walks the entry blocks of every pinned chain, asking the network
+// for any entries we don't have.  The general entry sync works through the directory
+// blocks in order, so without this a pinned chain would only be complete once the whole
+// database is.
+func (s *State) GoBackfillPinnedChains() {
+	for {
+		time.Sleep(10 * time.Second)
+		if s.PinnedChains == nil {
+			continue
+		}
+		for _, chainID := range s.PinnedChains.ChainIDs() {
+			s.BackfillPinnedChain(chainID)
+		}
+	}
+}
+
+// BackfillPinnedChain makes one pass over a pinned chain, from its head back to the
+// entry blocks an earlier pass found complete, queuing requests for the entries that are
+// missing and haven't been asked for lately.
+func (s *State) BackfillPinnedChain(chainID interfaces.IHash) interfaces.PinnedChainStatus {
+	c := s.PinnedChains.backfill(chainID)
+	if c == nil {
+		c = &pinnedChain{queued: make(map[[32]byte]time.Time)}
+	}
+	now := time.Now()
+	st := interfaces.PinnedChainStatus{ChainID: chainID.String(), LastScan: now.Unix()}
+
+	// The entry blocks walked, newest first, and whether each had all its entries
+	type walked struct {
+		keyMR    [32]byte
+		entries  int
+		complete bool
+	}
+	var blocks []walked
+	atCursor, atStart := false, false
+
+	eBlock, err := s.DB.FetchEBlockHead(chainID)
+	if err != nil {
+		pinLogger.Errorf("Cannot fetch the head of pinned chain %s: %v", chainID.String(), err)
+		return st
+	}
+	for eBlock != nil {
+		keyMR, _ := eBlock.KeyMR()
+		if keyMR.Fixed() == c.cursor {
+			atCursor = true
+			break
+		}
+		w := walked{keyMR: keyMR.Fixed(), complete: true}
+		for _, entryHash := range eBlock.GetEntryHashes() {
+			if entryHash.IsMinuteMarker() {
+				continue
+			}
+			w.entries++
+			if has(s, entryHash) {
+				delete(c.queued, entryHash.Fixed())
+				continue
+			}
+			w.complete = false
+			st.Missing++
+			if asked, ok := c.queued[entryHash.Fixed()]; ok && now.Sub(asked) < PinRequeueAfter {
+				continue
+			}
+			// Don't block on a full queue; whatever we skip is picked up on the next pass
+			if cap(s.MissingEntries)-len(s.MissingEntries) < 2 {
+				continue
+			}
+			c.queued[entryHash.Fixed()] = now
+			s.MissingEntries <- &MissingEntry{
+				DBHeight:  eBlock.GetHeader().GetDBHeight(),
+				EntryHash: entryHash,
+				EBHash:    keyMR,
+			}
+		}
+		blocks = append(blocks, w)
+
+		prev := eBlock.GetHeader().GetPrevKeyMR()
+		if prev == nil || prev.IsZero() {
+			atStart = true
+			break
+		}
+		eBlock, err = s.DB.FetchEBlock(prev)
+		if err != nil || eBlock == nil {
+			// The entry block itself hasn't arrived yet; the directory block sync will get it
+			break
+		}
+	}
+
+	// Move the cursor up over the blocks just above it that are now complete.  Walking to
+	// the first block without meeting the cursor means it was rolled back.
+	if atStart {
+		c.cursor, c.cursorEBlocks, c.cursorEntries = [32]byte{}, 0, 0
+	}
+	if atCursor || atStart {
+		for i := len(blocks) - 1; i >= 0 && blocks[i].complete; i-- {
+			c.cursor = blocks[i].keyMR
+			c.cursorEBlocks++
+			c.cursorEntries += blocks[i].entries
+		}
+		st.Complete = st.Missing == 0
+	}
+	st.EBlocks = c.cursorEBlocks
+	st.Entries = c.cursorEntries
+	for _, w := range blocks {
+		if w.keyMR == c.cursor {
+			break
+		}
+		st.EBlocks++
+		st.Entries += w.entries
+	}
+
+	c.status = st
+	s.PinnedChains.setBackfill(chainID, c)
+	return st
+}
@@ -0,0 +1,97 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+	. "github.com/FactomProject/factomd/state"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestParsePinnedChains(t *testing.T) {
+	ids, err := ParsePinnedChains(" 888888ae4b6ca1e71ac1a1e5aa2d4a0b1bde4b1a4e84d14a7fb2af8c7cb3ad8b,,000000000000000000000000000000000000000000000000000000000000000c ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 2 || ids[1].String() != "000000000000000000000000000000000000000000000000000000000000000c" {
+		t.Errorf("Unexpected chains %v", ids)
+	}
+	if ids, err := ParsePinnedChains(""); err != nil || len(ids) != 0 {
+		t.Errorf("Expected no chains, got %v %v", ids, err)
+	}
+	if _, err := ParsePinnedChains("888888ae4b6c"); err == nil {
+		t.Error("Expected an error for a short chain id")
+	}
+}
+
+func TestBackfillPinnedChain(t *testing.T) {
+	s := testHelper.CreateAndPopulateTestState()
+
+	chainID := testHelper.GetChainID()
+
+	if s.IsChainPinned(chainID) {
+		t.Error("Chain pinned before it was asked to be")
+	}
+	s.PinChain(chainID, true)
+	if !s.IsChainPinned(chainID) {
+		t.Fatal("Chain was not pinned")
+	}
+
+	st := s.BackfillPinnedChain(chainID)
+	if st.ChainID != chainID.String() || st.EBlocks == 0 || st.Entries == 0 {
+		t.Errorf("Chain was not scanned: %+v", st)
+	}
+	if st.Missing != 0 || !st.Complete {
+		t.Errorf("Fully synced chain reported incomplete: %+v", st)
+	}
+	// A second pass stops at the blocks found complete, but reports the whole chain
+	if again := s.BackfillPinnedChain(chainID); again.EBlocks != st.EBlocks || again.Entries != st.Entries || !again.Complete {
+		t.Errorf("Second pass reported %+v, the first %+v", again, st)
+	}
+	if list := s.GetPinnedChains(); len(list) != 1 || list[0].ChainID != chainID.String() {
+		t.Errorf("Unexpected pinned chains %+v", list)
+	}
+
+	s.PinChain(chainID, false)
+	if s.IsChainPinned(chainID) || len(s.GetPinnedChains()) != 0 {
+		t.Error("Chain still pinned")
+	}
+}
+
+func TestBackfillPinnedChainAsksOnce(t *testing.T) {
+	s := testHelper.CreateAndPopulateTestState()
+	chainID := testHelper.GetChainID()
+	s.PinChain(chainID, true)
+
+	// Take away an entry of the chain's head
+	db := s.DB.(*databaseOverlay.Overlay)
+	head, err := db.FetchEBlockHead(chainID)
+	if err != nil || head == nil {
+		t.Fatalf("No head for the chain: %v", err)
+	}
+	hash := head.GetEntryHashes()[0]
+	if err := db.Delete(databaseOverlay.ENTRY, hash.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+
+	st := s.BackfillPinnedChain(chainID)
+	if st.Missing != 1 || st.Complete || len(s.MissingEntries) != 1 {
+		t.Errorf("Missing entry not asked for: %+v, %d queued", st, len(s.MissingEntries))
+	}
+	// It was asked for lately, so it isn't again
+	st = s.BackfillPinnedChain(chainID)
+	if st.Missing != 1 || len(s.MissingEntries) != 1 {
+		t.Errorf("Missing entry asked for twice: %+v, %d queued", st, len(s.MissingEntries))
+	}
+
+	if err := db.Put(databaseOverlay.ENTRY, hash.Bytes(), chainID); err != nil {
+		t.Fatal(err)
+	}
+	if st = s.BackfillPinnedChain(chainID); st.Missing != 0 || !st.Complete {
+		t.Errorf("Chain incomplete once the entry arrived: %+v", st)
+	}
+}
@@ -0,0 +1,261 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/primitives"
+	log "github.com/sirupsen/logrus"
+)
+
+var checkpointLogger = packageLogger.WithFields(log.Fields{"subpack": "checkpoints"})
+
+// Checkpoints are compiled in, so adding one took a release.  A node can subscribe to a
+// checkpoint service instead: it fetches a set of checkpoints from CheckpointURL, signed
+// by the federated servers, and merges those of a set a majority of the current federated
+// servers signed.  A set that contradicts a known checkpoint, or a block the node has
+// saved, is refused whole.
+
+// Largest checkpoint set fetched
+const maxCheckpointSetSize = 1 << 20
+
+// CheckpointSet is a set of checkpoints, as the checkpoint service serves it
+type CheckpointSet struct {
+	Network     string                `json:"network"`
+	Timestamp   int64                 `json:"timestamp"`   // Unix time the set was made
+	Checkpoints map[string]string     `json:"checkpoints"` // KeyMR of the directory block, by height
+	Signatures  []CheckpointSignature `json:"signatures"`
+}
+
+// CheckpointSignature is a federated server's signature of a checkpoint set
+type CheckpointSignature struct {
+	Identity  string `json:"identity"`  // Chain ID of the server's identity
+	Signature string `json:"signature"` // Hex of the signature of SigningData by its signing key
+}
+
+// SigningData returns what the signatures of the set sign: the network, the timestamp,
+// and the checkpoints in order of height, a line each
+func (c *CheckpointSet) SigningData() []byte {
+	heights := make([]int, 0, len(c.Checkpoints))
+	for h := range c.Checkpoints {
+		if ht, err := strconv.Atoi(h); err == nil {
+			heights = append(heights, ht)
+		}
+	}
+	sort.Ints(heights)
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n%d\n", strings.ToUpper(c.Network), c.Timestamp)
+	for _, ht := range heights {
+		fmt.Fprintf(&b, "%d %s\n", ht, strings.ToLower(c.Checkpoints[strconv.Itoa(ht)]))
+	}
+	return []byte(b.String())
+}
+
+// Points returns the checkpoints of the set by height
+func (c *CheckpointSet) Points() (map[uint32]string, error) {
+	points := make(map[uint32]string, len(c.Checkpoints))
+	for h, keyMR := range c.Checkpoints {
+		ht, err := strconv.ParseUint(h, 10, 32)
+		if err != nil || strconv.FormatUint(ht, 10) != h {
+			return nil, fmt.Errorf("bad height %q", h)
+		}
+		if b, err := hex.DecodeString(keyMR); err != nil || len(b) != 32 {
+			return nil, fmt.Errorf("bad KeyMR %q at %d", keyMR, ht)
+		}
+		points[uint32(ht)] = strings.ToLower(keyMR)
+	}
+	return points, nil
+}
+
+// VerifyCheckpointSet checks that at least required of the signers, keyed by the chain ID
+// of their identity, signed the set
+func VerifyCheckpointSet(set *CheckpointSet, signers map[string]*primitives.PublicKey, required int) error {
+	return verifyCheckpointSignatures(set.SigningData(), set.Signatures, signers, required)
+}
+
+// verifyCheckpointSignatures checks that at least required of the signers signed data
+func verifyCheckpointSignatures(data []byte, signatures []CheckpointSignature, signers map[string]*primitives.PublicKey, required int) error {
+	signed := make(map[string]bool)
+	for _, s := range signatures {
+		key, ok := signers[strings.ToLower(s.Identity)]
+		if !ok || signed[strings.ToLower(s.Identity)] {
+			continue
+		}
+		raw, err := hex.DecodeString(s.Signature)
+		if err != nil || len(raw) != constants.SIGNATURE_LENGTH {
+			continue
+		}
+		var sig [constants.SIGNATURE_LENGTH]byte
+		copy(sig[:], raw)
+		if key.Verify(data, &sig) {
+			signed[strings.ToLower(s.Identity)] = true
+		}
+	}
+	if len(signed) < required {
+		return fmt.Errorf("signed by %d of the federated servers, %d needed", len(signed), required)
+	}
+	return nil
+}
+
+// CheckpointSubscription fetches checkpoint sets from the checkpoint service
+type CheckpointSubscription struct {
+	url      string
+	interval time.Duration
+	client   *http.Client
+
+	mutex     sync.Mutex
+	fetching  bool
+	fetched   *CheckpointSet // Set fetched, waiting to be merged on the consensus loop
+	lastFetch time.Time
+	lastSet   int64 // Timestamp of the last set merged
+}
+
+// newCheckpointSubscription returns the subscription to the checkpoint service, nil if
+// there is none
+func (s *State) newCheckpointSubscription() *CheckpointSubscription {
+	if s.CheckpointURL == "" {
+		return nil
+	}
+	c := new(CheckpointSubscription)
+	c.url = s.CheckpointURL
+	c.interval = time.Duration(s.CheckpointRefreshMinutes) * time.Minute
+	if c.interval <= 0 {
+		c.interval = time.Hour
+	}
+	c.client = &http.Client{Timeout: 30 * time.Second}
+	return c
+}
+
+// fetch gets the set the checkpoint service serves, off the consensus loop
+func (c *CheckpointSubscription) fetch() {
+	set, err := c.get()
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.fetching = false
+	if err != nil {
+		CheckpointSetsVec.WithLabelValues("error").Inc()
+		checkpointLogger.WithField("url", c.url).Warnf("Unable to fetch checkpoints: %v", err)
+		return
+	}
+	c.fetched = set
+}
+
+func (c *CheckpointSubscription) get() (*CheckpointSet, error) {
+	resp, err := c.client.Get(c.url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("checkpoint service returned %s", resp.Status)
+	}
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxCheckpointSetSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxCheckpointSetSize {
+		return nil, fmt.Errorf("checkpoint set is over %d bytes", maxCheckpointSetSize)
+	}
+	set := new(CheckpointSet)
+	if err := json.Unmarshal(data, set); err != nil {
+		return nil, err
+	}
+	return set, nil
+}
+
+// updateCheckpoints merges a set fetched since the last run, and starts the next fetch
+// when it is due.  Run from the consensus loop.
+func (s *State) updateCheckpoints() error {
+	c := s.Checkpoints
+	if c == nil {
+		return nil
+	}
+	c.mutex.Lock()
+	set := c.fetched
+	c.fetched = nil
+	due := !c.fetching && time.Since(c.lastFetch) >= c.interval
+	if due {
+		c.fetching = true
+		c.lastFetch = time.Now()
+	}
+	c.mutex.Unlock()
+
+	if due {
+		go c.fetch()
+	}
+	if set == nil {
+		return nil
+	}
+	return s.mergeCheckpointSet(set)
+}
+
+// mergeCheckpointSet adds the checkpoints of a set the current federated servers signed
+func (s *State) mergeCheckpointSet(set *CheckpointSet) error {
+	c := s.Checkpoints
+	if set.Timestamp <= c.lastSet {
+		return nil // Seen it
+	}
+	if !strings.EqualFold(set.Network, s.Network) {
+		CheckpointSetsVec.WithLabelValues("rejected").Inc()
+		return fmt.Errorf("checkpoints are for the %s network, not %s", set.Network, s.Network)
+	}
+	points, err := set.Points()
+	if err != nil {
+		CheckpointSetsVec.WithLabelValues("rejected").Inc()
+		return err
+	}
+
+	feds := s.GetFedServers(s.LLeaderHeight)
+	signers := make(map[string]*primitives.PublicKey)
+	for _, fed := range feds {
+		if auth, _ := s.GetAuthority(fed.GetChainID()); auth != nil {
+			key := auth.SigningKey
+			signers[strings.ToLower(fed.GetChainID().String())] = &key
+		}
+	}
+	if err := VerifyCheckpointSet(set, signers, len(feds)/2+1); err != nil {
+		CheckpointSetsVec.WithLabelValues("rejected").Inc()
+		return err
+	}
+
+	// A checkpoint we are past has to be of the block we saved
+	highest := s.GetHighestSavedBlk()
+	for ht, keyMR := range points {
+		if ht > highest || s.DB == nil {
+			continue
+		}
+		saved, err := s.DB.FetchDBKeyMRByHeight(ht)
+		if err == nil && saved != nil && saved.String() != keyMR {
+			CheckpointSetsVec.WithLabelValues("rejected").Inc()
+			checkpointLogger.Errorf("Checkpoint at %d is %s, but the saved block is %s", ht, keyMR, saved.String())
+			return fmt.Errorf("checkpoint at %d contradicts the saved block", ht)
+		}
+	}
+
+	added, err := constants.AddCheckPoints(points)
+	if err != nil {
+		CheckpointSetsVec.WithLabelValues("rejected").Inc()
+		checkpointLogger.Errorf("Refused checkpoints: %v", err)
+		return err
+	}
+	c.lastSet = set.Timestamp
+	CheckpointSetsVec.WithLabelValues("merged").Inc()
+	if added > 0 {
+		checkpointLogger.WithField("signatures", len(set.Signatures)).Infof("Added %d checkpoints", added)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/primitives"
+	. "github.com/FactomProject/factomd/state"
+)
+
+func TestVerifyCheckpointSet(t *testing.T) {
+	set := &CheckpointSet{
+		Network:     "MAIN",
+		Timestamp:   1500000000,
+		Checkpoints: map[string]string{"200000": "ab0a2c4b9d3e6e2a0d5c3bf06a5b2b8d2b1e1f87434cc65a6672cc9c8fe19ab2"},
+	}
+	signers := make(map[string]*primitives.PublicKey)
+	var keys []*primitives.PrivateKey
+	for _, id := range []string{"01", "02", "03"} {
+		key := primitives.RandomPrivateKey()
+		keys = append(keys, key)
+		signers[id] = key.Pub
+	}
+	sign := func(id string, key *primitives.PrivateKey) CheckpointSignature {
+		sig := key.Sign(set.SigningData())
+		return CheckpointSignature{Identity: id, Signature: hex.EncodeToString(sig.Bytes())}
+	}
+
+	// One signature twice isn't two
+	set.Signatures = []CheckpointSignature{sign("01", keys[0]), sign("01", keys[0])}
+	if err := VerifyCheckpointSet(set, signers, 2); err == nil {
+		t.Error("Accepted a set one server signed twice")
+	}
+
+	// Nor is one by a key that isn't the server's
+	set.Signatures = append(set.Signatures, sign("02", keys[2]))
+	if err := VerifyCheckpointSet(set, signers, 2); err == nil {
+		t.Error("Accepted a signature by another key")
+	}
+
+	set.Signatures = append(set.Signatures, sign("03", keys[2]))
+	if err := VerifyCheckpointSet(set, signers, 2); err != nil {
+		t.Errorf("Refused a set two servers signed: %v", err)
+	}
+
+	// The signatures cover the checkpoints
+	set.Checkpoints["200001"] = set.Checkpoints["200000"]
+	if err := VerifyCheckpointSet(set, signers, 2); err == nil {
+		t.Error("Accepted a set with a checkpoint added after signing")
+	}
+}
+
+func TestAddCheckPoints(t *testing.T) {
+	known, _ := constants.GetCheckPoint(2)
+	if _, err := constants.AddCheckPoints(map[uint32]string{2: "00", 4000000000: "01"}); err == nil {
+		t.Error("Added checkpoints contradicting a compiled in one")
+	}
+	if _, ok := constants.GetCheckPoint(4000000000); ok {
+		t.Error("Added some of a refused set")
+	}
+
+	added, err := constants.AddCheckPoints(map[uint32]string{2: known, 4000000000: "01"})
+	defer delete(constants.CheckPoints, 4000000000)
+	if err != nil || added != 1 {
+		t.Errorf("Added %d checkpoints, %v", added, err)
+	}
+	if keyMR, _ := constants.GetCheckPoint(4000000000); keyMR != "01" {
+		t.Errorf("Checkpoint is %q", keyMR)
+	}
+}
@@ -0,0 +1,228 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/entryBlock"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var clusterLogger = packageLogger.WithFields(log.Fields{"subpack": "cluster"})
+
+// How long after the last directory block state copied from the cache we leave asking our
+// peers for them to the cache
+const clusterActiveWindow = 30 * time.Second
+
+// Most directory block states fetched from the cache on one pass
+const clusterDBStatesPerPass = 100
+
+// Most missing entries looked up in the cache on one pass of the entry syncing loop
+const clusterEntriesPerPass = 1000
+
+// ClusterCache is the block cache of the operator's cluster: another node of theirs serving
+// its saved directory block states and entries over its API, so the followers copy them
+// locally rather than each fetching them from the network.  What is copied is validated as
+// if it came from a peer.
+type ClusterCache struct {
+	URL    string
+	Client *http.Client
+
+	mutex       sync.Mutex
+	lastDBState time.Time // When a directory block state last came from the cache
+	next        uint32    // Next dbheight to fetch
+	saved       uint32    // Our highest saved when last seen to move
+	savedAt     time.Time
+}
+
+func NewClusterCache(url string) *ClusterCache {
+	c := new(ClusterCache)
+	c.URL = strings.TrimRight(url, "/")
+	c.Client = &http.Client{Timeout: 30 * time.Second}
+	return c
+}
+
+// newClusterCache returns the cache of the configured cluster, or nil if there isn't one
+func (s *State) newClusterCache() *ClusterCache {
+	if s.ClusterCacheURL == "" {
+		return nil
+	}
+	return NewClusterCache(s.ClusterCacheURL)
+}
+
+// IsClusterCacheServer returns true if we serve our saved directory block states and
+// entries to the other followers of the cluster
+func (s *State) IsClusterCacheServer() bool {
+	return s.ClusterCacheServe
+}
+
+// get fetches a path from the cache.  Returns nil, nil if the cache doesn't have it.
+func (c *ClusterCache) get(path string) ([]byte, error) {
+	resp, err := c.Client.Get(c.URL + path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return data, nil
+	case http.StatusNotFound:
+		return nil, nil
+	}
+	return nil, fmt.Errorf("Cluster cache returned %s for %s", resp.Status, path)
+}
+
+// FetchDBState returns the directory block state of a height from the cache, or nil if the
+// cache hasn't saved it
+func (c *ClusterCache) FetchDBState(dbheight uint32) (*messages.DBStateMsg, error) {
+	data, err := c.get(fmt.Sprintf("/v2/cluster/dbstate/%d", dbheight))
+	if err != nil || data == nil {
+		ClusterCacheFetches.WithLabelValues("dbstate", fetchResult(err)).Inc()
+		return nil, err
+	}
+	msg := new(messages.DBStateMsg)
+	if err := msg.UnmarshalBinary(data); err != nil {
+		ClusterCacheFetches.WithLabelValues("dbstate", "error").Inc()
+		return nil, err
+	}
+	if msg.DirectoryBlock.GetDatabaseHeight() != dbheight {
+		ClusterCacheFetches.WithLabelValues("dbstate", "error").Inc()
+		return nil, fmt.Errorf("Cluster cache returned dbheight %d for %d", msg.DirectoryBlock.GetDatabaseHeight(), dbheight)
+	}
+	ClusterCacheFetches.WithLabelValues("dbstate", "hit").Inc()
+	return msg, nil
+}
+
+// FetchEntry returns an entry from the cache, or nil if the cache doesn't have it
+func (c *ClusterCache) FetchEntry(hash interfaces.IHash) (interfaces.IEBEntry, error) {
+	data, err := c.get("/v2/cluster/entry/" + hash.String())
+	if err != nil || data == nil {
+		ClusterCacheFetches.WithLabelValues("entry", fetchResult(err)).Inc()
+		return nil, err
+	}
+	entry := new(entryBlock.Entry)
+	if err := entry.UnmarshalBinary(data); err != nil {
+		ClusterCacheFetches.WithLabelValues("entry", "error").Inc()
+		return nil, err
+	}
+	if !entry.GetHash().IsSameAs(hash) {
+		ClusterCacheFetches.WithLabelValues("entry", "error").Inc()
+		return nil, fmt.Errorf("Cluster cache returned entry %v for %v", entry.GetHash(), hash)
+	}
+	ClusterCacheFetches.WithLabelValues("entry", "hit").Inc()
+	return entry, nil
+}
+
+func fetchResult(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "miss"
+}
+
+// Active returns true if the cache has been feeding us directory block states, so there is
+// no need to ask our peers for them
+func (c *ClusterCache) Active() bool {
+	if c == nil {
+		return false
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return time.Since(c.lastDBState) < clusterActiveWindow
+}
+
+// SyncFromCluster makes one pass: the directory block states above our highest saved, up to
+// the highest known, are fetched from the cache and queued as if a peer had sent them.
+// Returns how many were queued.
+func (s *State) SyncFromCluster() int {
+	c := s.Cluster
+	if c == nil {
+		return 0
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	saved := s.GetHighestSavedBlk()
+	if saved != c.saved || c.savedAt.IsZero() {
+		c.saved = saved
+		c.savedAt = time.Now()
+	}
+	// If what we queued isn't being saved, it was dropped; fetch it again
+	if c.next <= saved || time.Since(c.savedAt) > clusterActiveWindow {
+		c.next = saved + 1
+		c.savedAt = time.Now()
+	}
+	upper := s.GetHighestKnownBlock()
+	if upper > saved+clusterDBStatesPerPass {
+		upper = saved + clusterDBStatesPerPass
+	}
+
+	queued := 0
+	for ; c.next <= upper; c.next++ {
+		// Don't flood the inMsgQueue; what isn't queued now is fetched on the next pass
+		if s.InMsgQueue().Length() > constants.INMSGQUEUE_MED {
+			break
+		}
+		msg, err := c.FetchDBState(c.next)
+		if err != nil {
+			clusterLogger.Warnf("Cannot fetch dbheight %d from the cluster cache: %v", c.next, err)
+			break
+		}
+		if msg == nil {
+			break
+		}
+		s.InMsgQueue().Enqueue(msg)
+		c.lastDBState = time.Now()
+		queued++
+	}
+	return queued
+}
+
+// GoSyncFromCluster copies directory block states from the cluster cache, if one is
+// configured, while we are behind the network
+func (s *State) GoSyncFromCluster() {
+	if s.Cluster == nil {
+		return
+	}
+	for {
+		if !s.DBFinished || s.SyncFromCluster() == 0 {
+			time.Sleep(5 * time.Second)
+			continue
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// fetchEntryFromCluster copies a missing entry from the cluster cache into the write
+// batch.  Returns true if the cache had it.
+func (s *State) fetchEntryFromCluster(hash interfaces.IHash) bool {
+	if s.Cluster == nil {
+		return false
+	}
+	entry, err := s.Cluster.FetchEntry(hash)
+	if err != nil {
+		clusterLogger.Warnf("Cannot fetch entry %v from the cluster cache: %v", hash, err)
+		return false
+	}
+	if entry == nil {
+		return false
+	}
+	s.Writes.AddEntry(entry)
+	return true
+}
@@ -0,0 +1,113 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
+	. "github.com/FactomProject/factomd/state"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+// newTestClusterCache serves a directory block state for the heights up to top, and the
+// entry given
+func newTestClusterCache(t *testing.T, top uint32, entry []byte, entryHash string) *httptest.Server {
+	set := testHelper.CreateTestBlockSet(nil)
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/v2/cluster/dbstate/"):
+			height, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/v2/cluster/dbstate/"))
+			if err != nil || uint32(height) > top {
+				http.NotFound(w, r)
+				return
+			}
+			set.DBlock.GetHeader().SetDBHeight(uint32(height))
+			msg := messages.NewDBStateMsg(primitives.NewTimestampNow(), set.DBlock, set.ABlock, set.FBlock, set.ECBlock, nil, nil, nil)
+			data, err := msg.MarshalBinary()
+			if err != nil {
+				t.Error(err)
+			}
+			w.Write(data)
+		case r.URL.Path == "/v2/cluster/entry/"+entryHash:
+			w.Write(entry)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestClusterCacheFetchEntry(t *testing.T) {
+	entry := testHelper.CreateTestEntry(1)
+	data, err := entry.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := testHelper.CreateTestEntry(2)
+
+	server := newTestClusterCache(t, 0, data, entry.GetHash().String())
+	defer server.Close()
+	c := NewClusterCache(server.URL + "/")
+
+	got, err := c.FetchEntry(entry.GetHash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == nil || !got.GetHash().IsSameAs(entry.GetHash()) {
+		t.Fatalf("Fetched %v, expected entry %v", got, entry.GetHash())
+	}
+
+	got, err = c.FetchEntry(other.GetHash())
+	if err != nil || got != nil {
+		t.Errorf("Fetched %v, %v for an entry the cache doesn't have", got, err)
+	}
+
+	// An entry that doesn't hash to what was asked for is refused
+	bad := newTestClusterCache(t, 0, data, other.GetHash().String())
+	defer bad.Close()
+	got, err = NewClusterCache(bad.URL).FetchEntry(other.GetHash())
+	if err == nil || got != nil {
+		t.Errorf("Accepted entry %v for %v", got, other.GetHash())
+	}
+}
+
+func TestSyncFromCluster(t *testing.T) {
+	s := testHelper.CreateEmptyTestState()
+	s.DBStates.Base = 5
+	saved := s.GetHighestSavedBlk()
+	s.HighestKnown = saved + 10
+
+	server := newTestClusterCache(t, saved+3, nil, "")
+	defer server.Close()
+	s.Cluster = NewClusterCache(server.URL)
+	if s.Cluster.Active() {
+		t.Error("Cluster cache active before it fed us")
+	}
+
+	before := s.InMsgQueue().Length()
+	if n := s.SyncFromCluster(); n != 3 {
+		t.Errorf("Queued %d directory block states, expected 3", n)
+	}
+	if s.InMsgQueue().Length()-before != 3 {
+		t.Errorf("InMsgQueue grew by %d, expected 3", s.InMsgQueue().Length()-before)
+	}
+	if !s.Cluster.Active() {
+		t.Error("Cluster cache not active after it fed us")
+	}
+
+	// What was queued isn't fetched again
+	if n := s.SyncFromCluster(); n != 0 {
+		t.Errorf("Queued %d directory block states again", n)
+	}
+	var nilCache *ClusterCache
+	if nilCache.Active() {
+		t.Error("No cluster cache is active")
+	}
+}
@@ -0,0 +1,201 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/common/factoid"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var coinbaseLogger = packageLogger.WithFields(log.Fields{"subpack": "coinbase-audit"})
+
+// How many discrepancies are kept for the report
+const MaxCoinbaseDiscrepancies = 1000
+
+// CoinbaseExpectation returns the coinbase transaction a factoid block should hold
+type CoinbaseExpectation func(fb interfaces.IFBlock) interfaces.ITransaction
+
+// ExpectedCoinbase is the coinbase this node would build for the block.  The admin
+// blocks of this protocol version carry no coinbase descriptors or server efficiencies,
+// so the payouts are the fixed ones of the factoid package.
+func ExpectedCoinbase(fb interfaces.IFBlock) interfaces.ITransaction {
+	return factoid.GetCoinbase(fb.GetCoinbaseTimestamp())
+}
+
+// CoinbaseAuditor checks the coinbase of each saved factoid block against the payouts
+// expected for it, so anyone running a node can see that the authorities are paid what
+// they should be, and nothing else is created.  Blocks are audited in height order by a
+// scan of the database.
+type CoinbaseAuditor struct {
+	mutex    sync.Mutex
+	expected CoinbaseExpectation
+	next     uint32 // Height of the next factoid block to audit
+	report   interfaces.CoinbaseAuditReport
+}
+
+func NewCoinbaseAuditor(expected CoinbaseExpectation) *CoinbaseAuditor {
+	t := new(CoinbaseAuditor)
+	t.expected = expected
+	return t
+}
+
+func coinbasePayouts(tx interfaces.ITransaction) (total uint64) {
+	for _, out := range tx.GetOutputs() {
+		total += out.GetAmount()
+	}
+	return total
+}
+
+// AuditCoinbase compares the coinbase of a factoid block with the expected one, and
+// lists what differs.  Only the first transaction may be without inputs.
+func AuditCoinbase(fb interfaces.IFBlock, expected interfaces.ITransaction) interfaces.CoinbaseDiscrepancy {
+	d := interfaces.CoinbaseDiscrepancy{DBHeight: fb.GetDBHeight(), Expected: coinbasePayouts(expected)}
+	txs := fb.GetTransactions()
+	if len(txs) == 0 || len(txs[0].GetInputs()) != 0 {
+		d.Problems = append(d.Problems, "no coinbase transaction")
+	} else {
+		cb := txs[0]
+		d.TxID = cb.GetSigHash().String()
+		d.Paid = coinbasePayouts(cb)
+		if len(cb.GetECOutputs()) != 0 {
+			d.Problems = append(d.Problems, fmt.Sprintf("buys entry credits for %d addresses", len(cb.GetECOutputs())))
+		}
+		if len(cb.GetRCDs()) != 0 || len(cb.GetSignatureBlocks()) != 0 {
+			d.Problems = append(d.Problems, "is signed")
+		}
+
+		outs, want := cb.GetOutputs(), expected.GetOutputs()
+		for i := 0; i < len(outs) || i < len(want); i++ {
+			switch {
+			case i >= len(want):
+				d.Problems = append(d.Problems, fmt.Sprintf("unexpected payout %d of %d factoshis to %s", i,
+					outs[i].GetAmount(), primitives.ConvertFctAddressToUserStr(outs[i].GetAddress())))
+			case i >= len(outs):
+				d.Problems = append(d.Problems, fmt.Sprintf("missing payout %d of %d factoshis to %s", i,
+					want[i].GetAmount(), primitives.ConvertFctAddressToUserStr(want[i].GetAddress())))
+			case !outs[i].GetAddress().IsSameAs(want[i].GetAddress()):
+				d.Problems = append(d.Problems, fmt.Sprintf("payout %d is to %s, expected %s", i,
+					primitives.ConvertFctAddressToUserStr(outs[i].GetAddress()), primitives.ConvertFctAddressToUserStr(want[i].GetAddress())))
+			case outs[i].GetAmount() != want[i].GetAmount():
+				d.Problems = append(d.Problems, fmt.Sprintf("payout %d to %s is %d factoshis, expected %d", i,
+					primitives.ConvertFctAddressToUserStr(outs[i].GetAddress()), outs[i].GetAmount(), want[i].GetAmount()))
+			}
+		}
+	}
+	for i, tx := range txs {
+		if i > 0 && len(tx.GetInputs()) == 0 {
+			out, _ := tx.TotalOutputs()
+			d.Problems = append(d.Problems, fmt.Sprintf("transaction %s creates %d factoshis without inputs", tx.GetSigHash().String(), out))
+		}
+	}
+	return d
+}
+
+// Next returns the height of the next factoid block to be audited
+func (t *CoinbaseAuditor) Next() uint32 {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.next
+}
+
+// Add audits a factoid block, which must be the one at Next(), and returns what is wrong
+// with its coinbase, if anything.  The genesis block distributes the initial supply
+// rather than paying a coinbase, so it isn't audited.
+func (t *CoinbaseAuditor) Add(fb interfaces.IFBlock) (*interfaces.CoinbaseDiscrepancy, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if fb.GetDBHeight() != t.next {
+		return nil, fmt.Errorf("Factoid block at height %d audited out of order, expected %d", fb.GetDBHeight(), t.next)
+	}
+	t.next++
+	if fb.GetDBHeight() == 0 {
+		return nil, nil
+	}
+
+	d := AuditCoinbase(fb, t.expected(fb))
+	t.report.AuditedHeight = d.DBHeight
+	t.report.Blocks++
+	t.report.TotalPaid += d.Paid
+	t.report.TotalExpected += d.Expected
+	CoinbaseAudited.Inc()
+	if len(d.Problems) == 0 {
+		return nil, nil
+	}
+	t.report.Discrepancies++
+	t.report.Recent = append(t.report.Recent, d)
+	if len(t.report.Recent) > MaxCoinbaseDiscrepancies {
+		t.report.Recent = append([]interfaces.CoinbaseDiscrepancy{}, t.report.Recent[len(t.report.Recent)-MaxCoinbaseDiscrepancies:]...)
+	}
+	CoinbaseDiscrepancies.Inc()
+	return &d, nil
+}
+
+// SetComplete marks whether every saved block has been audited
+func (t *CoinbaseAuditor) SetComplete(complete bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.report.Complete = complete
+}
+
+// Report returns the totals and the most recent discrepancies
+func (t *CoinbaseAuditor) Report() interfaces.CoinbaseAuditReport {
+	if t == nil {
+		return interfaces.CoinbaseAuditReport{}
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	r := t.report
+	r.Recent = append([]interfaces.CoinbaseDiscrepancy{}, t.report.Recent...)
+	return r
+}
+
+// AuditCoinbases audits the factoid blocks saved since the last scan
+func (s *State) AuditCoinbases() {
+	t := s.CoinbaseAudit
+	if t == nil {
+		return
+	}
+	highest := s.GetHighestSavedBlk()
+	for h := t.Next(); h <= highest; h++ {
+		fb, err := s.DB.FetchFBlockByHeight(h)
+		if err != nil || fb == nil {
+			coinbaseLogger.Errorf("Cannot fetch the factoid block at height %d: %v", h, err)
+			t.SetComplete(false)
+			return
+		}
+		d, err := t.Add(fb)
+		if err != nil {
+			coinbaseLogger.Errorf("Cannot audit the factoid block at height %d: %v", h, err)
+			t.SetComplete(false)
+			return
+		}
+		if d != nil {
+			coinbaseLogger.WithFields(log.Fields{"dbheight": d.DBHeight, "txid": d.TxID}).Warnf("Coinbase paid %d factoshis, expected %d: %v", d.Paid, d.Expected, d.Problems)
+		}
+	}
+	t.SetComplete(true)
+}
+
+// GoAuditCoinbases keeps the coinbase audit up with the saved blocks
+func (s *State) GoAuditCoinbases() {
+	for {
+		if s.DBFinished {
+			s.AuditCoinbases()
+		}
+		time.Sleep(30 * time.Second)
+	}
+}
+
+// GetCoinbaseAudit returns the audit of the coinbase payouts of the saved blocks
+func (s *State) GetCoinbaseAudit() interfaces.CoinbaseAuditReport {
+	return s.CoinbaseAudit.Report()
+}
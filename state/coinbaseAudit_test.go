@@ -0,0 +1,119 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/FactomProject/factomd/common/factoid"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	. "github.com/FactomProject/factomd/state"
+)
+
+var (
+	payee   = factoid.NewAddress(primitives.Sha([]byte("payee")).Bytes())
+	another = factoid.NewAddress(primitives.Sha([]byte("another")).Bytes())
+)
+
+func coinbaseBlock(prev interfaces.IFBlock, payouts ...uint64) interfaces.IFBlock {
+	fb := factoid.NewFBlock(prev).(*factoid.FBlock)
+	coinbase := new(factoid.Transaction)
+	for _, amount := range payouts {
+		coinbase.AddOutput(payee, amount)
+	}
+	fb.Transactions = append(fb.Transactions, coinbase)
+	return fb
+}
+
+// payEach expects one payout of amount to the payee
+func payEach(amount uint64) CoinbaseExpectation {
+	return func(fb interfaces.IFBlock) interfaces.ITransaction {
+		tx := new(factoid.Transaction)
+		tx.AddOutput(payee, amount)
+		return tx
+	}
+}
+
+func TestCoinbaseAuditor(t *testing.T) {
+	a := NewCoinbaseAuditor(payEach(100))
+
+	b0 := coinbaseBlock(nil, 1000000)
+	b1 := coinbaseBlock(b0, 100)
+	b2 := coinbaseBlock(b1, 100, 5)
+	b3 := coinbaseBlock(b2, 100)
+
+	if _, err := a.Add(b1); err == nil {
+		t.Error("Expected an error auditing a block out of order")
+	}
+	// The genesis distribution isn't audited
+	for _, fb := range []interfaces.IFBlock{b0, b1} {
+		if d, err := a.Add(fb); err != nil || d != nil {
+			t.Fatalf("Block %d: %+v %v", fb.GetDBHeight(), d, err)
+		}
+	}
+	d, err := a.Add(b2)
+	if err != nil || d == nil || d.Paid != 105 || d.Expected != 100 || len(d.Problems) != 1 || !strings.Contains(d.Problems[0], "unexpected payout 1 of 5") {
+		t.Fatalf("Unexpected discrepancy %+v %v", d, err)
+	}
+	if d, err := a.Add(b3); err != nil || d != nil {
+		t.Fatalf("Block 3: %+v %v", d, err)
+	}
+
+	r := a.Report()
+	if r.AuditedHeight != 3 || r.Blocks != 3 || r.TotalPaid != 305 || r.TotalExpected != 300 || r.Discrepancies != 1 {
+		t.Errorf("Unexpected report %+v", r)
+	}
+	if len(r.Recent) != 1 || r.Recent[0].DBHeight != 2 {
+		t.Errorf("Unexpected discrepancies %+v", r.Recent)
+	}
+}
+
+func TestAuditCoinbase(t *testing.T) {
+	b0 := coinbaseBlock(nil)
+	expected := payEach(100)(b0)
+
+	for _, test := range []struct {
+		name    string
+		block   func() interfaces.IFBlock
+		problem string
+	}{
+		{"wrong amount", func() interfaces.IFBlock { return coinbaseBlock(b0, 99) }, "is 99 factoshis, expected 100"},
+		{"missing", func() interfaces.IFBlock { return coinbaseBlock(b0) }, "missing payout 0 of 100"},
+		{"wrong address", func() interfaces.IFBlock {
+			fb := coinbaseBlock(b0).(*factoid.FBlock)
+			fb.Transactions[0].AddOutput(another, 100)
+			return fb
+		}, "expected " + primitives.ConvertFctAddressToUserStr(payee)},
+		{"buys entry credits", func() interfaces.IFBlock {
+			fb := coinbaseBlock(b0, 100).(*factoid.FBlock)
+			fb.Transactions[0].AddECOutput(another, 1)
+			return fb
+		}, "buys entry credits"},
+		{"no coinbase", func() interfaces.IFBlock { return factoid.NewFBlock(b0) }, "no coinbase transaction"},
+		{"second issuance", func() interfaces.IFBlock {
+			fb := coinbaseBlock(b0, 100).(*factoid.FBlock)
+			tx := new(factoid.Transaction)
+			tx.AddOutput(another, 7)
+			fb.Transactions = append(fb.Transactions, tx)
+			return fb
+		}, "creates 7 factoshis without inputs"},
+	} {
+		d := AuditCoinbase(test.block(), expected)
+		if len(d.Problems) != 1 || !strings.Contains(d.Problems[0], test.problem) {
+			t.Errorf("%s: expected %q, found %v", test.name, test.problem, d.Problems)
+		}
+	}
+
+	if d := AuditCoinbase(coinbaseBlock(b0, 100), expected); len(d.Problems) != 0 {
+		t.Errorf("Expected coinbase flagged %v", d.Problems)
+	}
+
+	// This version of the protocol pays no coinbase
+	if d := AuditCoinbase(coinbaseBlock(b0, 100), ExpectedCoinbase(b0)); len(d.Problems) != 1 || d.Expected != 0 {
+		t.Errorf("Unexpected audit against the protocol coinbase %+v", d)
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+	"github.com/FactomProject/factomd/database/tieredDB"
+	log "github.com/sirupsen/logrus"
+)
+
+// How many directory blocks are kept out of the cold store if the config doesn't say
+const DefaultColdStorageAfter = 50000
+
+var coldLogger = packageLogger.WithFields(log.Fields{"subpack": "cold-storage"})
+
+// withColdStorage puts the database in front of the cold store, if one is configured
+func (s *State) withColdStorage(dbase interfaces.IDatabase) (interfaces.IDatabase, error) {
+	if s.ColdStoragePath == "" {
+		return dbase, nil
+	}
+	path := s.ColdStoragePath + "/" + s.Network + "/"
+	s.Println("Cold storage:", path)
+	cold, err := tieredDB.NewDirColdStore(path)
+	if err != nil {
+		return nil, err
+	}
+	return tieredDB.NewTieredDB(dbase, cold, s.resources().ColdStorageCache), nil
+}
+
+// GoMoveToColdStorage moves old blocks to the cold store as the chain grows.  Does
+// nothing unless the database is tiered.
+func (s *State) GoMoveToColdStorage() {
+	overlay, ok := s.DB.(*databaseOverlay.Overlay)
+	if !ok {
+		return
+	}
+	if _, ok := overlay.DB.(interfaces.ITieredDatabase); !ok {
+		return
+	}
+	for {
+		time.Sleep(time.Minute)
+		if !s.DBFinished {
+			continue
+		}
+		s.MoveToColdStorage()
+	}
+}
+
+// MoveToColdStorage moves the blocks that have become old enough since the last call
+// to the cold store.  Entries of pinned chains are left in the database.
+func (s *State) MoveToColdStorage() {
+	overlay, ok := s.DB.(*databaseOverlay.Overlay)
+	if !ok {
+		return
+	}
+	tiered, ok := overlay.DB.(interfaces.ITieredDatabase)
+	if !ok {
+		return
+	}
+
+	after := s.ColdStorageAfter
+	if after == 0 {
+		after = DefaultColdStorageAfter
+	}
+	highest := s.GetHighestSavedBlk()
+	if highest < after {
+		return
+	}
+
+	from, err := tiered.GetColdHeight()
+	if err != nil {
+		coldLogger.Errorf("Cannot read the cold storage height: %v", err)
+		return
+	}
+	to := highest - after
+	for h := from; h < to; h++ {
+		if err := overlay.MoveToColdStorage(h, s.IsChainPinned); err != nil {
+			coldLogger.Errorf("Failed moving blocks at height %d to cold storage: %v", h, err)
+			return
+		}
+		// Recorded as we go, so a restart doesn't move the same blocks again
+		if err := tiered.SetColdHeight(h + 1); err != nil {
+			coldLogger.Errorf("Cannot save the cold storage height: %v", err)
+			return
+		}
+	}
+	if to > from {
+		coldLogger.Infof("Moved directory blocks %d to %d to cold storage", from, to-1)
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/factoid"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// A leader takes commits in the order they come, so one buyer flooding it with commits
+// could fill its blocks and starve everyone else.  With a CommitRateLimit, each entry
+// credit address has a bucket of commits that refills at the limit; a commit paid from an
+// address with an empty bucket is held, and taken when holding is next reviewed and the
+// bucket has refilled.
+
+// Addresses tracked before the idle ones are dropped
+const maxCommitRateAddresses = 10000
+
+type commitBucket struct {
+	tokens  float64
+	updated time.Time
+	allowed uint64
+	limited uint64
+}
+
+// CommitRateLimiter is a bucket of commits for each entry credit address.  A nil limiter
+// allows every commit.
+type CommitRateLimiter struct {
+	mutex     sync.Mutex
+	perMinute int
+	burst     int
+	buckets   map[[32]byte]*commitBucket
+}
+
+// NewCommitRateLimiter returns a limiter allowing each address perMinute commits a minute,
+// burst of them at once, or nil if perMinute isn't positive.  A burst under one is one
+// minute's worth.
+func NewCommitRateLimiter(perMinute int, burst int) *CommitRateLimiter {
+	if perMinute <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = perMinute
+	}
+	c := new(CommitRateLimiter)
+	c.perMinute = perMinute
+	c.burst = burst
+	c.buckets = make(map[[32]byte]*commitBucket)
+	return c
+}
+
+// newCommitRateLimiter returns the limiter the config asks for, nil for none
+func (s *State) newCommitRateLimiter() *CommitRateLimiter {
+	return NewCommitRateLimiter(s.CommitRateLimit, s.CommitRateBurst)
+}
+
+// refill tops a bucket up for the time since it was last updated
+func (c *CommitRateLimiter) refill(b *commitBucket, now time.Time) {
+	if elapsed := now.Sub(b.updated); elapsed > 0 {
+		b.tokens += elapsed.Minutes() * float64(c.perMinute)
+		b.updated = now
+	}
+	if b.tokens > float64(c.burst) {
+		b.tokens = float64(c.burst)
+	}
+}
+
+// prune drops the buckets that have refilled, as they are the same as no bucket at all
+func (c *CommitRateLimiter) prune(now time.Time) {
+	for ec, b := range c.buckets {
+		c.refill(b, now)
+		if b.tokens >= float64(c.burst) {
+			delete(c.buckets, ec)
+		}
+	}
+}
+
+// Allow returns true, taking a commit from its bucket, if the address may commit now
+func (c *CommitRateLimiter) Allow(ec [32]byte, now time.Time) bool {
+	if c == nil {
+		return true
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	b, ok := c.buckets[ec]
+	if !ok {
+		if len(c.buckets) >= maxCommitRateAddresses {
+			c.prune(now)
+		}
+		b = &commitBucket{tokens: float64(c.burst), updated: now}
+		c.buckets[ec] = b
+		CommitRateAddresses.Set(float64(len(c.buckets)))
+	}
+	c.refill(b, now)
+	if b.tokens < 1 {
+		b.limited++
+		return false
+	}
+	b.tokens--
+	b.allowed++
+	return true
+}
+
+// Limits returns the limit, and how each address that committed lately stands against it
+func (c *CommitRateLimiter) Limits(now time.Time) interfaces.CommitRateLimits {
+	limits := interfaces.CommitRateLimits{Addresses: []interfaces.CommitRateUsage{}}
+	if c == nil {
+		return limits
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	limits.Enabled = true
+	limits.PerMinute = c.perMinute
+	limits.Burst = c.burst
+	for ec, b := range c.buckets {
+		c.refill(b, now)
+		limits.Addresses = append(limits.Addresses, interfaces.CommitRateUsage{
+			Address:   primitives.ConvertECAddressToUserStr(factoid.NewAddress(ec[:])),
+			Available: b.tokens,
+			Allowed:   b.allowed,
+			Limited:   b.limited,
+		})
+	}
+	sort.Slice(limits.Addresses, func(i, j int) bool {
+		a, b := limits.Addresses[i], limits.Addresses[j]
+		if a.Limited != b.Limited {
+			return a.Limited > b.Limited
+		}
+		return a.Available < b.Available
+	})
+	return limits
+}
+
+// GetCommitRateLimits returns how fast this node takes the commits of each entry credit
+// address as a leader
+func (s *State) GetCommitRateLimits() interfaces.CommitRateLimits {
+	return s.CommitRates.Limits(time.Now())
+}
+
+// holdRateLimitedCommit holds a commit paid from an address that has used up its commits
+// for now, returning true if it did.  Like the block limits, it only applies on custom and
+// test networks.
+func (s *State) holdRateLimitedCommit(m interfaces.IMsg) bool {
+	if s.CommitRates == nil || s.GetNetworkID() == constants.MAIN_NETWORK_ID {
+		return false
+	}
+	var ec [32]byte
+	switch c := m.(type) {
+	case *messages.CommitChainMsg:
+		ec = c.CommitChain.ECPubKey.Fixed()
+	case *messages.CommitEntryMsg:
+		ec = c.CommitEntry.ECPubKey.Fixed()
+	default:
+		return false
+	}
+	if s.CommitRates.Allow(ec, time.Now()) {
+		return false
+	}
+	TotalCommitsRateLimited.Inc()
+	m.FollowerExecute(s)
+	// Nothing to wait on but the bucket, so it is reviewed with the rest of holding
+	s.HoldingDeps.Forget(m.GetMsgHash().Fixed())
+	return true
+}
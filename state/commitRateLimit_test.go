@@ -0,0 +1,48 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/FactomProject/factomd/state"
+)
+
+func TestCommitRateLimiter(t *testing.T) {
+	c := NewCommitRateLimiter(60, 3)
+	now := time.Now()
+	flood, other := [32]byte{1}, [32]byte{2}
+
+	for i := 0; i < 3; i++ {
+		if !c.Allow(flood, now) {
+			t.Fatalf("Commit %d of the burst was limited", i)
+		}
+	}
+	if c.Allow(flood, now) {
+		t.Error("A commit past the burst was allowed")
+	}
+	if !c.Allow(other, now) {
+		t.Error("Another address was limited by the one flooding")
+	}
+
+	// One commit a second comes back
+	if !c.Allow(flood, now.Add(time.Second)) || c.Allow(flood, now.Add(time.Second)) {
+		t.Error("Expected one commit allowed a second later")
+	}
+
+	limits := c.Limits(now.Add(time.Second))
+	if !limits.Enabled || limits.PerMinute != 60 || limits.Burst != 3 || len(limits.Addresses) != 2 {
+		t.Fatalf("Unexpected limits %+v", limits)
+	}
+	if u := limits.Addresses[0]; u.Allowed != 4 || u.Limited != 2 || u.Available >= 1 {
+		t.Errorf("The flooding address isn't first, or its usage is off: %+v", u)
+	}
+
+	var none *CommitRateLimiter
+	if NewCommitRateLimiter(0, 3) != nil || !none.Allow(flood, now) || none.Limits(now).Enabled {
+		t.Error("No limit limited")
+	}
+}
@@ -0,0 +1,234 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
+	log "github.com/sirupsen/logrus"
+)
+
+// Consensus records
+//
+// A consensus record holds every message Process takes off the ack and message queues, in
+// the order it took them, with the time on the node's clock and what the message carries
+// that isn't marshalled.  Replaying one through a node loaded from a copy of the
+// recording node's database takes that node through the same steps, one message at a
+// time, with no timer, network or other goroutines feeding it; a stall seen on a live
+// node can be stepped through in a debugger.  The blocks a node loads from its own
+// database aren't recorded, since the replaying node loads them too.
+
+var recordLogger = packageLogger.WithFields(log.Fields{"subpack": "consensus-record"})
+
+// One message taken by Process, a line of JSON in the record
+type ConsensusRecord struct {
+	Time          int64  `json:"time"`  // Milliseconds, by the node's clock
+	Queue         string `json:"queue"` // "ack" or "msg"
+	DBHeight      uint32 `json:"dbheight"`
+	Minute        int    `json:"minute"`
+	Local         bool   `json:"local,omitempty"`
+	Origin        int    `json:"origin,omitempty"`
+	NetworkOrigin string `json:"networkorigin,omitempty"`
+	Message       string `json:"message"` // Marshalled, in hex
+}
+
+// ConsensusRecorder writes the consensus record of a node.  Each message is written out
+// as it is taken, so the record is complete up to a stall or a crash.
+type ConsensusRecorder struct {
+	file *os.File
+	out  *bufio.Writer
+	enc  *json.Encoder
+	errs int
+}
+
+// NewConsensusRecorder starts a record in the file, replacing what was there
+func NewConsensusRecorder(path string) (*ConsensusRecorder, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	r := new(ConsensusRecorder)
+	r.file = f
+	r.out = bufio.NewWriter(f)
+	r.enc = json.NewEncoder(r.out)
+	return r, nil
+}
+
+// Record adds a message taken off a queue by Process.  A message that can't be written is
+// logged and left out; only the first few failures are logged.
+func (r *ConsensusRecorder) Record(s *State, queue string, msg interfaces.IMsg) {
+	data, err := msg.MarshalBinary()
+	if err == nil {
+		err = r.enc.Encode(&ConsensusRecord{
+			Time:          s.GetTimestamp().GetTimeMilli(),
+			Queue:         queue,
+			DBHeight:      s.LLeaderHeight,
+			Minute:        s.CurrentMinute,
+			Local:         msg.IsLocal(),
+			Origin:        msg.GetOrigin(),
+			NetworkOrigin: msg.GetNetworkOrigin(),
+			Message:       hex.EncodeToString(data),
+		})
+	}
+	if err == nil {
+		err = r.out.Flush()
+	}
+	if err != nil {
+		ConsensusRecordErrors.Inc()
+		if r.errs++; r.errs <= 10 {
+			recordLogger.WithFields(msg.LogFields()).Errorf("Cannot record message: %v", err)
+		}
+		return
+	}
+	ConsensusRecordedMessages.Inc()
+}
+
+func (r *ConsensusRecorder) Close() error {
+	if err := r.out.Flush(); err != nil {
+		r.file.Close()
+		return err
+	}
+	return r.file.Close()
+}
+
+// recordConsensus adds a message Process took to the consensus record, if there is one
+func (s *State) recordConsensus(queue string, msg interfaces.IMsg) {
+	if s.ConsensusRecorder == nil {
+		return
+	}
+	// The replaying node loads these from its own database
+	if msg.Type() == constants.DBSTATE_MSG && msg.IsLocal() {
+		return
+	}
+	s.ConsensusRecorder.Record(s, queue, msg)
+}
+
+// ReadConsensusRecord calls fn with each message of a consensus record, stopping at the
+// first error
+func ReadConsensusRecord(r io.Reader, fn func(rec *ConsensusRecord, msg interfaces.IMsg) error) error {
+	dec := json.NewDecoder(r)
+	for line := 1; ; line++ {
+		rec := new(ConsensusRecord)
+		if err := dec.Decode(rec); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("record %d: %v", line, err)
+		}
+		data, err := hex.DecodeString(rec.Message)
+		if err != nil {
+			return fmt.Errorf("record %d: %v", line, err)
+		}
+		msg, err := messages.UnmarshalMessage(data)
+		if err != nil {
+			return fmt.Errorf("record %d: %v", line, err)
+		}
+		msg.SetLocal(rec.Local)
+		msg.SetOrigin(rec.Origin)
+		msg.SetNetworkOrigin(rec.NetworkOrigin)
+		if err := fn(rec, msg); err != nil {
+			return err
+		}
+	}
+}
+
+// ReplayConsensus loads the database, then gives Process the messages of a consensus
+// record in order, with the clock set to when each was taken, running the node until it
+// has nothing left to do after each.  Nothing else runs.  Messages the node sends or
+// queues for itself are dropped, since those that reached Process in the recording are
+// in the record.  Returns how many messages were replayed.
+func (s *State) ReplayConsensus(r io.Reader) (int, error) {
+	s.replayLoadDatabase()
+
+	s.SetIsReplaying()
+	defer s.SetIsDoneReplaying()
+
+	n := 0
+	err := ReadConsensusRecord(r, func(rec *ConsensusRecord, msg interfaces.IMsg) error {
+		s.ReplayTimestamp = primitives.NewTimestampFromMilliseconds(uint64(rec.Time))
+		switch rec.Queue {
+		case "ack":
+			s.ackQueue <- msg
+		case "msg":
+			s.msgQueue <- msg
+		default:
+			return fmt.Errorf("record %d: unknown queue %q", n+1, rec.Queue)
+		}
+		s.replayRun()
+		for s.inMsgQueue.Dequeue() != nil {
+		}
+		n++
+		return nil
+	})
+	return n, err
+}
+
+// replayLoadDatabase loads the database as LoadDatabase does on boot, passing the blocks
+// it queues on to Process in place of the validator loop.  Anything else the node queues
+// for itself is dropped; it is in the record if it reached Process.
+func (s *State) replayLoadDatabase() {
+	done := make(chan struct{})
+	go func() {
+		LoadDatabase(s)
+		close(done)
+	}()
+
+	for {
+		moved := false
+		for len(s.msgQueue) < cap(s.msgQueue) {
+			msg := s.inMsgQueue.Dequeue()
+			if msg == nil {
+				break
+			}
+			// Nothing comes from peers yet, so every DBState is one being loaded
+			if msg.Type() == constants.DBSTATE_MSG {
+				s.msgQueue <- msg
+				moved = true
+			}
+		}
+		s.replayRun()
+		if moved {
+			continue
+		}
+		select {
+		case <-done:
+			if s.inMsgQueue.Length() == 0 && len(s.msgQueue) == 0 {
+				s.replayRun()
+				return
+			}
+		default:
+			time.Sleep(time.Millisecond)
+		}
+	}
+}
+
+// Most rounds of Process and UpdateState run for one replayed message, as many as the
+// validator loop runs between messages
+const replayMaxRuns = 10
+
+// replayRun runs Process and UpdateState until neither has anything to do, dropping what
+// the node sends to its peers.  Returns true if anything was done.
+func (s *State) replayRun() bool {
+	any := false
+	for i := 0; i < replayMaxRuns; i++ {
+		p, b := s.Process(), s.UpdateState()
+		for s.networkOutMsgQueue.Dequeue() != nil {
+		}
+		if !p && !b {
+			break
+		}
+		any = true
+	}
+	return any
+}
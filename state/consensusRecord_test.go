@@ -0,0 +1,80 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	. "github.com/FactomProject/factomd/state"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestConsensusRecord(t *testing.T) {
+	dir, err := ioutil.TempDir("", "consensus-record")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "record.json")
+
+	s := testHelper.CreateEmptyTestState()
+	r, err := NewConsensusRecorder(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	local := newSigTestCommit(1, true)
+	local.SetLocal(true)
+	peer := newSigTestCommit(2, true)
+	peer.SetOrigin(3)
+	peer.SetNetworkOrigin("peer")
+	r.Record(s, "msg", local)
+	r.Record(s, "msg", peer)
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []interfaces.IMsg
+	err = ReadConsensusRecord(bytes.NewReader(data), func(rec *ConsensusRecord, msg interfaces.IMsg) error {
+		if rec.Queue != "msg" {
+			t.Errorf("Wrong queue %q", rec.Queue)
+		}
+		got = append(got, msg)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("Read %d messages, expected 2", len(got))
+	}
+	if !got[0].GetHash().IsSameAs(local.GetHash()) || !got[0].IsLocal() {
+		t.Error("First message not read back as recorded")
+	}
+	if !got[1].GetHash().IsSameAs(peer.GetHash()) || got[1].IsLocal() || got[1].GetOrigin() != 3 || got[1].GetNetworkOrigin() != "peer" {
+		t.Error("Second message not read back as recorded")
+	}
+
+	if err := ReadConsensusRecord(bytes.NewReader([]byte(`{"queue":"msg","message":"zz"}`)), nil); err == nil {
+		t.Error("Bad record was read")
+	}
+
+	replayer := testHelper.CreateEmptyTestState()
+	n, err := replayer.ReplayConsensus(bytes.NewReader(data))
+	if err != nil || n != 2 {
+		t.Errorf("Replayed %d messages, expected 2: %v", n, err)
+	}
+	if replayer.IsReplaying {
+		t.Error("Still replaying after the record ended")
+	}
+}
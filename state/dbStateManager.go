@@ -454,6 +454,8 @@ type DBStateList struct {
 	Base          uint32
 	Complete      uint32
 	DBStates      []*DBState
+
+	askBackoff AskBackoff // Backoff of the asks for the states past the highest saved
 }
 
 var _ interfaces.BinaryMarshallable = (*DBStateList)(nil)
@@ -648,39 +650,46 @@ func (dbsl *DBStateList) UnmarshalBinary(p []byte) error {
 // Return a -1 on failure.
 //
 func (d *DBState) ValidNext(state *State, next *messages.DBStateMsg) int {
+	valid, _ := d.validNext(state, next)
+	return valid
+}
+
+// validNext is ValidNext, with the reason a block fails.  Blocks we already have fail
+// without one, as there is nothing wrong with them.
+func (d *DBState) validNext(state *State, next *messages.DBStateMsg) (int, string) {
 
 	dirblk := next.DirectoryBlock
 	dbheight := dirblk.GetHeader().GetDBHeight()
 
 	// If we don't have the previous blocks processed yet, then let's wait on this one.
 	if dbheight > state.GetHighestSavedBlk()+1 {
-		return 0
+		return 0, ""
 	}
 
 	if dbheight == 0 && state.GetHighestSavedBlk() == 0 {
 		//state.AddStatus(fmt.Sprintf("DBState.ValidNext: rtn 1 genesis block is valid dbht: %d", dbheight))
 		// The genesis block is valid by definition.
-		return 1
+		return 1, ""
 	}
 
 	if d == nil || !d.Saved {
-		return 0
+		return 0, ""
 	}
 
 	// Don't reload blocks!
 	if dbheight <= state.GetHighestSavedBlk() {
-		return -1
+		return -1, ""
 	}
 
 	if d == nil {
 		//state.AddStatus(fmt.Sprintf("DBState.ValidNext: rtn 0 dbstate is nil or not saved dbht: %d", dbheight))
 		// Must be out of order.  Can't make the call if valid or not yet.
-		return 0
+		return 0, ""
 	}
 
 	valid := next.ValidateSignatures(state)
 	if !next.IsInDB && !next.IgnoreSigs && valid != 1 {
-		return valid
+		return valid, RejectedSignatures
 	}
 
 	// Get the keymr of the Previous DBState
@@ -691,22 +700,22 @@ func (d *DBState) ValidNext(state *State, next *messages.DBStateMsg) int {
 
 		pdir, err := state.DB.FetchDBlockByHeight(dbheight - 1)
 		if err != nil {
-			return -1
+			return -1, RejectedPrevMissing
 		}
 
 		if pkeymr.Fixed() == pdir.GetKeyMR().Fixed() {
 			//state.AddStatus(fmt.Sprintf("DBState.ValidNext: rtn -1 hashes don't match at first. dbht: %d dbstate had prev %x but we expected %x But on disk %x",
 			//	dbheight, prevkeymr.Bytes()[:3], pkeymr.Bytes()[:3], pdir.GetKeyMR().Bytes()[:3]))
-			return 1
+			return 1, ""
 		}
 
 		//state.AddStatus(fmt.Sprintf("DBState.ValidNext: rtn -1 hashes don't match. dbht: %d dbstate had prev %x but we expected %x on disk %x",
 		//	dbheight, prevkeymr.Bytes()[:3], pkeymr.Bytes()[:3], pdir.GetKeyMR().Bytes()[:3]))
 		// If not the same, this is a bad new Directory Block
-		return -1
+		return -1, RejectedPrevKeyMR
 	}
 
-	return 1
+	return 1, ""
 
 }
 
@@ -1124,6 +1133,7 @@ func (list *DBStateList) ProcessBlocks(d *DBState) (progress bool) {
 	// Cleanup Tasks
 	///////////////////////////////
 	list.State.Commits.Cleanup(list.State)
+	list.State.ReconcileHolding(d)
 
 	// s := list.State
 	// // Time out commits every now and again.
@@ -1233,13 +1243,9 @@ func (list *DBStateList) SaveDBStateToDB(d *DBState) (progress bool) {
 	// Past this point, we cannot Return without recording the transactions in the dbstate.  This is because we
 	// have marked them all as saved to disk!  So we gotta save them to disk.  Or panic trying.
 
-	// Only trim when we are really saving.
-	v := dbheight + int(list.State.IdentityChainID.Bytes()[4])
-	if v%4 == 0 {
-		list.State.DB.Trim()
-	}
-
 	// Save
+	list.State.DBSaveMutex.Lock()
+	defer list.State.DBSaveMutex.Unlock()
 	list.State.DB.StartMultiBatch()
 
 	if err := list.State.DB.ProcessABlockMultiBatch(d.AdminBlock); err != nil {
@@ -1345,9 +1351,11 @@ func (list *DBStateList) SaveDBStateToDB(d *DBState) (progress bool) {
 		panic(err.Error())
 	}
 
+	written := time.Now()
 	if err := list.State.DB.ExecuteMultiBatch(); err != nil {
 		panic(err.Error())
 	}
+	list.State.LeaderHealth.DiskWrite(time.Since(written))
 
 	// Not activated.  Set to true if you want extra checking of the data saved to the database.
 	if false {
@@ -1397,6 +1405,9 @@ func (list *DBStateList) SaveDBStateToDB(d *DBState) (progress bool) {
 	progress = true
 	d.ReadyToSave = false
 	d.Saved = true
+	list.State.MessageTraces.Saved(list.State.FactomNodeName, uint32(dbheight))
+	list.State.PublishReadView(uint32(dbheight), d.DirectoryBlock.GetKeyMR())
+	list.State.publishBlockEvents(d)
 
 	return
 }
@@ -1,6 +1,7 @@
 package state
 
 import (
+	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/common/messages"
 )
 
@@ -63,15 +64,38 @@ func (list *DBStateList) Catchup(justDoIt bool) {
 				}
 			}
 
+			// While the cluster cache is feeding us, leave our peers be
+			if list.State.Cluster.Active() {
+				return
+			}
+
+			// Asks that brought in more states are done with, and the next starts over
+			if begin > list.LastBegin {
+				list.askBackoff.Answered(AskDBState, now.GetTimeMilli())
+			}
+
 			if list.State.RunLeader && !list.State.IgnoreMissing {
-				msg := messages.NewDBStateMissing(list.State, uint32(begin), uint32(end+5))
+				// While a stream is feeding us, leave it be
+				if list.State.syncFollow.active(now.GetTimeMilli()) {
+					return
+				}
+
+				// Ask for a stream of all we are missing, or block by block if streams stall
+				var msg interfaces.IMsg
+				if req := list.State.newSyncRequest(uint32(begin), uint32(hk), now.GetTimeMilli()); req != nil {
+					msg = req
+					end = int(req.DBHeightEnd)
+				} else {
+					msg = messages.NewDBStateMissing(list.State, uint32(begin), uint32(end+5))
+				}
 
 				if msg != nil {
 					//		list.State.RunLeader = false
 					//		list.State.StartDelay = list.State.GetTimestamp().GetTimeMilli()
 					msg.SendOut(list.State, msg)
 					list.State.DBStateAskCnt++
-					list.TimeToAsk.SetTimeSeconds(now.GetTimeSeconds() + 6)
+					policy := list.State.GetAskPolicy(AskDBState)
+					list.TimeToAsk.SetTime(uint64(list.askBackoff.Asked(AskDBState, policy, now.GetTimeMilli())))
 					list.LastBegin = begin
 					list.LastEnd = end
 				}
@@ -90,6 +114,7 @@ func (list *DBStateList) Catchup(justDoIt bool) {
 
 	// return if we are caught up, and clear our timer
 	if end-begin < 1 {
+		list.askBackoff.Answered(AskDBState, now.GetTimeMilli())
 		list.TimeToAsk = nil
 		return
 	}
@@ -0,0 +1,10 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+// diskFree isn't measured on Windows
+func diskFree(path string) (uint64, bool) {
+	return 0, false
+}
@@ -0,0 +1,128 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var ecRateLogger = packageLogger.WithFields(log.Fields{"subpack": "ec-rate-history"})
+
+// ECRateHistory holds the exchange rate changes found in the saved factoid blocks.  It
+// is built in the background once the database is loaded, and kept up with the blocks
+// saved since, so the API never scans the chain.  The KeyMR of the last block scanned is
+// kept; if the block at that height has changed, or is gone, the blocks were rolled back
+// and the history is scanned again from the start.
+type ECRateHistory struct {
+	mutex     sync.Mutex
+	built     bool   // Every saved block has been scanned at least once
+	scanned   uint32 // Next height to scan
+	lastKeyMR interfaces.IHash
+	changes   []interfaces.ECRateChange
+}
+
+func NewECRateHistory() *ECRateHistory {
+	return new(ECRateHistory)
+}
+
+// Reset forgets the history, so the next scan reads every saved block again
+func (h *ECRateHistory) Reset() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.built = false
+	h.scanned = 0
+	h.lastKeyMR = nil
+	h.changes = nil
+}
+
+// ScanECRateHistory adds the rate changes of the blocks saved since the last scan to the
+// history.  The blocks are read without holding the lock, so the API keeps answering
+// from the previous history while a full scan runs.  Only the one goroutine scans.
+func (s *State) ScanECRateHistory() error {
+	h := s.ECRateHistory
+	if h == nil {
+		return nil
+	}
+	h.mutex.Lock()
+	scanned, lastKeyMR, changes := h.scanned, h.lastKeyMR, h.changes
+	h.mutex.Unlock()
+
+	if scanned > 0 {
+		fblock, err := s.DB.FetchFBlockByHeight(scanned - 1)
+		if err != nil {
+			return err
+		}
+		if fblock == nil || !fblock.GetKeyMR().IsSameAs(lastKeyMR) {
+			scanned, lastKeyMR, changes = 0, nil, nil
+		}
+	}
+
+	top := s.GetHighestSavedBlk()
+	for ; scanned <= top; scanned++ {
+		fblock, err := s.DB.FetchFBlockByHeight(scanned)
+		if err != nil {
+			return err
+		}
+		if fblock == nil {
+			// Not saved yet, pick up here next time
+			break
+		}
+		lastKeyMR = fblock.GetKeyMR()
+		rate := fblock.GetExchRate()
+		if len(changes) > 0 && changes[len(changes)-1].Rate == rate {
+			continue
+		}
+		change := interfaces.ECRateChange{DBHeight: scanned, Rate: rate}
+		dblock, err := s.DB.FetchDBlockByHeight(scanned)
+		if err != nil {
+			return err
+		}
+		if dblock != nil {
+			change.Timestamp = dblock.GetTimestamp().GetTimeSeconds()
+		}
+		changes = append(changes, change)
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.built = true
+	h.scanned, h.lastKeyMR, h.changes = scanned, lastKeyMR, changes
+	return nil
+}
+
+// GoTrackECRateHistory builds the exchange rate history once the database is loaded,
+// and keeps it up with the saved blocks
+func (s *State) GoTrackECRateHistory() {
+	for {
+		if s.DBFinished {
+			if err := s.ScanECRateHistory(); err != nil {
+				ecRateLogger.Errorf("Cannot scan the exchange rate history: %v", err)
+			}
+		}
+		time.Sleep(10 * time.Second)
+	}
+}
+
+// GetECRateHistory returns every change of the entry credit exchange rate, as recorded in
+// the saved factoid block headers, with the height it took effect.  It is an error to ask
+// before the history has been built.
+func (s *State) GetECRateHistory() ([]interfaces.ECRateChange, error) {
+	h := s.ECRateHistory
+	if h == nil {
+		return nil, nil
+	}
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if !h.built {
+		return nil, fmt.Errorf("The exchange rate history is still being built")
+	}
+	return append([]interfaces.ECRateChange{}, h.changes...), nil
+}
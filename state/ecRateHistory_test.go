@@ -0,0 +1,42 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestGetECRateHistory(t *testing.T) {
+	s := testHelper.CreateAndPopulateTestState()
+
+	fblock, err := s.DB.FetchFBlockByHeight(0)
+	if err != nil || fblock == nil {
+		t.Fatalf("No genesis factoid block: %v", err)
+	}
+
+	// Nothing is served until the history has been built
+	if _, err := s.GetECRateHistory(); err == nil {
+		t.Error("Expected an error before the history was built")
+	}
+
+	// The second scan only reads what was saved since, and a reset scans it all again
+	for i := 0; i < 3; i++ {
+		if i == 2 {
+			s.ECRateHistory.Reset()
+		}
+		if err := s.ScanECRateHistory(); err != nil {
+			t.Fatal(err)
+		}
+		changes, err := s.GetECRateHistory()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(changes) != 1 || changes[0].DBHeight != 0 || changes[0].Rate != fblock.GetExchRate() {
+			t.Errorf("Expected the one rate of the test chain, got %v", changes)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"sort"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// An election replaces a faulted leader with an audit server, in rounds.  Every node ranks
+// the audit servers the same way for the VM, and in each round the audit server ranked next
+// volunteers, and the leaders accept it.  Once a majority of the leaders accept, the
+// volunteer's pledge and their votes make up a FullServerFault, which goes into the System
+// list and is processed in order.  A round that isn't decided in FaultTimeout seconds moves
+// on to the next audit server, so an audit server that is offline only costs a round.
+//
+// Elections take effect at their activation height.  Below it leaders are replaced by the
+// fault negotiation of ServerFault and FullServerFault messages, as before, and the node
+// takes part in both: a complete FullServerFault from either goes into the System list,
+// so a negotiation under way at the activation height can still finish.
+
+// electionsActive returns true if faulted leaders are replaced by elections at the height
+func (s *State) electionsActive(dbheight uint32) bool {
+	return constants.IsActive(constants.ACTIVATION_ELECTIONS, s.GetNetworkID(), dbheight)
+}
+
+type ElectionPhase int
+
+const (
+	ElectionWaiting   ElectionPhase = iota // Waiting on the audit server of the round to volunteer
+	ElectionAccepting                      // The volunteer is waiting on a majority of the leaders
+	ElectionDecided                        // A majority of the leaders accepted the volunteer
+)
+
+func (p ElectionPhase) String() string {
+	switch p {
+	case ElectionWaiting:
+		return "waiting"
+	case ElectionAccepting:
+		return "accepting"
+	case ElectionDecided:
+		return "decided"
+	}
+	return fmt.Sprintf("phase %d", int(p))
+}
+
+// Election replaces the faulted leader of a VM.  It only moves on the messages it is given
+// and the time, so every node that sees the same messages moves it through the same rounds.
+type Election struct {
+	DBHeight     uint32
+	VMIndex      int
+	ServerID     interfaces.IHash   // The faulted leader
+	Ranking      []interfaces.IHash // The audit servers, in the order they volunteer
+	Quorum       int                // The acceptances that decide the election, a majority of the leaders
+	Round        int
+	RoundStarted int64 // Unix seconds
+	Phase        ElectionPhase
+	Volunteer    *messages.ElectionVolunteer // The volunteer of the round, once it volunteers
+	Sent         bool                        // Whether we volunteered or accepted in the round
+
+	acceptances map[int]map[[32]byte]*messages.ElectionAccept // By round, then by the key of the leader
+}
+
+func NewElection(dbheight uint32, vmIndex int, serverID interfaces.IHash, audits []interfaces.IHash, leaders int, now int64) *Election {
+	e := new(Election)
+	e.DBHeight = dbheight
+	e.VMIndex = vmIndex
+	e.ServerID = serverID
+	e.Ranking = RankAudits(dbheight, vmIndex, audits)
+	e.Quorum = leaders/2 + 1
+	e.RoundStarted = now
+	e.acceptances = make(map[int]map[[32]byte]*messages.ElectionAccept)
+	return e
+}
+
+// RankAudits orders the audit servers for the election of a VM.  Each block and VM has its
+// own order, so the same audit server isn't always asked first.
+func RankAudits(dbheight uint32, vmIndex int, audits []interfaces.IHash) []interfaces.IHash {
+	rank := func(id interfaces.IHash) []byte {
+		var buf primitives.Buffer
+		binary.Write(&buf, binary.BigEndian, dbheight)
+		buf.WriteByte(byte(vmIndex))
+		buf.Write(id.Bytes())
+		return primitives.Sha(buf.DeepCopyBytes()).Bytes()
+	}
+	ranked := append([]interfaces.IHash{}, audits...)
+	sort.SliceStable(ranked, func(i, j int) bool { return bytes.Compare(rank(ranked[i]), rank(ranked[j])) < 0 })
+	return ranked
+}
+
+// Candidate returns the audit server whose turn it is to volunteer, or nil if there are none
+func (e *Election) Candidate() interfaces.IHash {
+	return e.candidate(e.Round)
+}
+
+func (e *Election) candidate(round int) interfaces.IHash {
+	if len(e.Ranking) == 0 {
+		return nil
+	}
+	return e.Ranking[round%len(e.Ranking)]
+}
+
+// Tick moves an undecided election on to its next round once the round has run for timeout
+// seconds.  It returns true if it did.
+func (e *Election) Tick(now int64, timeout int64) bool {
+	if e.Phase == ElectionDecided || now-e.RoundStarted < timeout {
+		return false
+	}
+	e.startRound(e.Round+1, now)
+	return true
+}
+
+func (e *Election) startRound(round int, now int64) {
+	e.Round = round
+	e.RoundStarted = now
+	e.Phase = ElectionWaiting
+	e.Volunteer = nil
+	e.Sent = false
+	for r := range e.acceptances {
+		if r < round {
+			delete(e.acceptances, r)
+		}
+	}
+}
+
+// inRound returns true if the core is for this election, in this round or the next; the
+// clocks of the nodes differ a little, so some move on to the next round before others
+func (e *Election) inRound(core *messages.ElectionCore, round int) bool {
+	if e.Phase == ElectionDecided || round < e.Round || round > e.Round+1 {
+		return false
+	}
+	return core.DBHeight == e.DBHeight && int(core.VMIndex) == e.VMIndex && core.ServerID.IsSameAs(e.ServerID)
+}
+
+// AddVolunteer takes the volunteer of a round, if it is the audit server ranked for the
+// round.  A volunteer for the next round moves the election on to that round.  It returns
+// true if the volunteer was taken.
+func (e *Election) AddVolunteer(v *messages.ElectionVolunteer, now int64) bool {
+	round := int(v.Round)
+	if !e.inRound(&v.ElectionCore, round) {
+		return false
+	}
+	if candidate := e.candidate(round); candidate == nil || !candidate.IsSameAs(v.AuditServerID) {
+		return false
+	}
+	if round > e.Round {
+		e.startRound(round, now)
+	}
+	if e.Volunteer != nil {
+		return false
+	}
+	e.Volunteer = v
+	e.Phase = ElectionAccepting
+	e.tally()
+	return true
+}
+
+// AddAcceptance counts the acceptance of a leader, by its signing key.  Acceptances that
+// come in ahead of their volunteer are counted once it does.  It returns true if this
+// acceptance decided the election.
+func (e *Election) AddAcceptance(a *messages.ElectionAccept) bool {
+	round := int(a.Round)
+	if a.Signature == nil || !e.inRound(&a.ElectionCore, round) {
+		return false
+	}
+	byKey := e.acceptances[round]
+	if byKey == nil {
+		byKey = make(map[[32]byte]*messages.ElectionAccept)
+		e.acceptances[round] = byKey
+	}
+	var key [32]byte
+	copy(key[:], a.Signature.GetKey())
+	byKey[key] = a
+	return e.tally()
+}
+
+// tally decides the election once a majority of the leaders accept the volunteer
+func (e *Election) tally() bool {
+	if e.Phase != ElectionAccepting || e.Accepted() < e.Quorum {
+		return false
+	}
+	e.Phase = ElectionDecided
+	return true
+}
+
+// Accepted returns how many leaders accepted the volunteer of the round
+func (e *Election) Accepted() int {
+	return len(e.accepted())
+}
+
+func (e *Election) accepted() []*messages.ElectionAccept {
+	if e.Volunteer == nil {
+		return nil
+	}
+	var keys [][32]byte
+	for key, a := range e.acceptances[e.Round] {
+		if a.IsSameCore(&e.Volunteer.ElectionCore) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return bytes.Compare(keys[i][:], keys[j][:]) < 0 })
+
+	accepted := make([]*messages.ElectionAccept, 0, len(keys))
+	for _, key := range keys {
+		accepted = append(accepted, e.acceptances[e.Round][key])
+	}
+	return accepted
+}
+
+// Signatures returns the pledge of the volunteer and the votes of the leaders that accepted
+// it, for the FullServerFault that records the outcome
+func (e *Election) Signatures() []interfaces.IFullSignature {
+	if e.Volunteer == nil {
+		return nil
+	}
+	sigs := []interfaces.IFullSignature{e.Volunteer.Signature}
+	for _, a := range e.accepted() {
+		sigs = append(sigs, a.Signature)
+	}
+	return sigs
+}
+
+func (e *Election) String() string {
+	candidate := "none"
+	if c := e.Candidate(); c != nil {
+		candidate = fmt.Sprintf("%x", c.Bytes()[3:6])
+	}
+	return fmt.Sprintf("vm%02d %x round %d audit %s %s %d/%d", e.VMIndex, e.ServerID.Bytes()[3:6], e.Round, candidate, e.Phase, e.Accepted(), e.Quorum)
+}
+
+func (e *Election) LogFields() log.Fields {
+	return log.Fields{"dbheight": e.DBHeight, "vm": e.VMIndex, "leaderid": e.ServerID.String(),
+		"round": e.Round, "phase": e.Phase.String(), "accepted": e.Accepted(), "quorum": e.Quorum}
+}
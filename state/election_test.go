@@ -0,0 +1,145 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
+	. "github.com/FactomProject/factomd/state"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+var electionLeader = testHelper.NewRepeatingHash(0x01)
+var electionAudits = []interfaces.IHash{testHelper.NewRepeatingHash(0xA1), testHelper.NewRepeatingHash(0xA2), testHelper.NewRepeatingHash(0xA3)}
+
+func newTestVolunteer(e *Election, round int, audit interfaces.IHash) *messages.ElectionVolunteer {
+	core := messages.ElectionCore{
+		ServerID:      e.ServerID,
+		AuditServerID: audit,
+		VMIndex:       byte(e.VMIndex),
+		DBHeight:      e.DBHeight,
+		Height:        4,
+		Timestamp:     primitives.NewTimestampFromMilliseconds(1500000000000 + uint64(round)),
+	}
+	v := messages.NewElectionVolunteer(core, round)
+	if err := v.Sign(testHelper.NewPrimitivesPrivateKey(100)); err != nil {
+		panic(err)
+	}
+	return v
+}
+
+func newTestAcceptance(v *messages.ElectionVolunteer, leader uint64) *messages.ElectionAccept {
+	a := messages.NewElectionAccept(v)
+	if err := a.Sign(testHelper.NewPrimitivesPrivateKey(leader)); err != nil {
+		panic(err)
+	}
+	return a
+}
+
+func TestRankAudits(t *testing.T) {
+	ranked := RankAudits(10, 1, electionAudits)
+	reversed := RankAudits(10, 1, []interfaces.IHash{electionAudits[2], electionAudits[1], electionAudits[0]})
+	if len(ranked) != len(electionAudits) {
+		t.Fatalf("Ranked %d audit servers of %d", len(ranked), len(electionAudits))
+	}
+	for i := range ranked {
+		if !ranked[i].IsSameAs(reversed[i]) {
+			t.Errorf("Rank %d depends on the order the audit servers are given in", i)
+		}
+	}
+}
+
+func TestElectionRounds(t *testing.T) {
+	e := NewElection(10, 1, electionLeader, electionAudits, 5, 1000)
+	if e.Quorum != 3 || e.Phase != ElectionWaiting || !e.Candidate().IsSameAs(e.Ranking[0]) {
+		t.Fatalf("Unexpected new election: %s", e.String())
+	}
+
+	// Only the audit server ranked for the round can volunteer
+	if e.AddVolunteer(newTestVolunteer(e, 0, e.Ranking[1]), 1001) {
+		t.Error("Took a volunteer out of turn")
+	}
+	if e.AddVolunteer(newTestVolunteer(e, 2, e.Ranking[2]), 1001) {
+		t.Error("Took a volunteer two rounds ahead")
+	}
+
+	// An acceptance ahead of its volunteer is counted once it arrives
+	v := newTestVolunteer(e, 0, e.Ranking[0])
+	if e.AddAcceptance(newTestAcceptance(v, 1)) {
+		t.Error("Decided without a volunteer")
+	}
+	if !e.AddVolunteer(v, 1002) || e.Phase != ElectionAccepting || e.Accepted() != 1 {
+		t.Fatalf("Volunteer not taken: %s", e.String())
+	}
+
+	// The same leader twice, or an acceptance of another volunteer, doesn't count
+	e.AddAcceptance(newTestAcceptance(v, 1))
+	e.AddAcceptance(newTestAcceptance(newTestVolunteer(e, 0, e.Ranking[1]), 2))
+	if e.Accepted() != 1 {
+		t.Errorf("Expected 1 acceptance, got %d", e.Accepted())
+	}
+
+	// The round runs out, and the next audit server is up
+	if e.Tick(1500, 600) {
+		t.Error("Moved on before the round ran out")
+	}
+	if !e.Tick(1600, 600) || e.Round != 1 || e.Phase != ElectionWaiting || e.Volunteer != nil || e.Accepted() != 0 {
+		t.Fatalf("Unexpected round after the timeout: %s", e.String())
+	}
+	if !e.Candidate().IsSameAs(e.Ranking[1]) {
+		t.Error("Expected the second audit server to be up")
+	}
+
+	// A majority of the leaders decides it
+	v = newTestVolunteer(e, 1, e.Ranking[1])
+	e.AddVolunteer(v, 1601)
+	e.AddAcceptance(newTestAcceptance(v, 1))
+	e.AddAcceptance(newTestAcceptance(v, 2))
+	if !e.AddAcceptance(newTestAcceptance(v, 3)) || e.Phase != ElectionDecided {
+		t.Fatalf("Expected a majority to decide it: %s", e.String())
+	}
+	if sigs := e.Signatures(); len(sigs) != 4 || !sigs[0].IsSameAs(v.Signature) {
+		t.Errorf("Expected the pledge and 3 votes, got %d signatures", len(sigs))
+	}
+	if e.Tick(5000, 600) || e.AddAcceptance(newTestAcceptance(v, 4)) {
+		t.Error("A decided election moved on")
+	}
+}
+
+func TestElectionNextRoundVolunteer(t *testing.T) {
+	e := NewElection(10, 1, electionLeader, electionAudits, 3, 1000)
+
+	// Others moved on to the next round ahead of us
+	if !e.AddVolunteer(newTestVolunteer(e, 1, e.Ranking[1]), 1001) || e.Round != 1 || e.Phase != ElectionAccepting {
+		t.Fatalf("Expected the volunteer to move us on to the next round: %s", e.String())
+	}
+	if e.AddVolunteer(newTestVolunteer(e, 0, e.Ranking[0]), 1002) {
+		t.Error("Took a volunteer from a past round")
+	}
+
+	// With no audit servers, nobody can volunteer
+	if NewElection(10, 1, electionLeader, nil, 3, 1000).Candidate() != nil {
+		t.Error("Expected no candidate without audit servers")
+	}
+}
+
+// Below the height elections are activated at, their messages are dropped rather than held
+func TestElectionMessagesBeforeActivation(t *testing.T) {
+	s := testHelper.CreateEmptyTestState()
+	s.NetworkNumber = constants.NETWORK_MAIN
+	e := NewElection(s.LLeaderHeight, 0, electionLeader, electionAudits, 3, 1000)
+	v := newTestVolunteer(e, 0, e.Ranking[0])
+	held := len(s.Holding)
+
+	s.FollowerExecuteElectionVolunteer(v)
+	s.FollowerExecuteElectionAccept(newTestAcceptance(v, 1))
+	if len(s.Holding) != held {
+		t.Error("Held election messages from before elections were activated")
+	}
+}
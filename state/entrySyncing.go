@@ -24,6 +24,9 @@ func has(s *State, entry interfaces.IHash) bool {
 			time.Sleep(30 * time.Millisecond)
 		}
 	}
+	if s.Writes.HasEntry(entry) {
+		return true
+	}
 	exists, _ := s.DB.DoesKeyExist(databaseOverlay.ENTRY, entry.Bytes())
 	return exists
 }
@@ -49,10 +52,17 @@ func (s *State) MakeMissingEntryRequests() {
 		avg := 0
 		highest := 0
 
-		// Look through our map, and remove any entries we now have in our database.
+		// Look through our map, and remove any entries we now have in our database, or no
+		// longer want as they have fallen below the prune floor.
+		floor := s.PruneFloor()
 		for k := range MissingEntryMap {
+			if MissingEntryMap[k].DBHeight < floor {
+				delete(MissingEntryMap, k)
+				continue
+			}
 			if has(s, MissingEntryMap[k].EntryHash) {
 				found++
+				MissingEntryMap[k].backoff.Answered(AskEntry, now.UnixNano()/1e6)
 				delete(MissingEntryMap, k)
 			} else {
 				cnt++
@@ -85,6 +95,7 @@ func (s *State) MakeMissingEntryRequests() {
 		}
 
 		sent := 0
+		cached := 0
 		if s.inMsgQueue.Length() < constants.INMSGQUEUE_MED {
 			// Make requests for entries we don't have.
 			for k := range MissingEntryMap {
@@ -104,13 +115,25 @@ func (s *State) MakeMissingEntryRequests() {
 					max = 1
 				}
 
-				if now.Unix()-et.LastTime.Unix() > 5 && sent < max {
+				// Copy what the cluster cache has rather than asking the network for it
+				if now.Unix()-et.LastTime.Unix() > 5 && s.Cluster != nil && cached < clusterEntriesPerPass {
+					cached++
+					if s.fetchEntryFromCluster(et.EntryHash) {
+						found++
+						et.backoff.Answered(AskEntry, now.UnixNano()/1e6)
+						delete(MissingEntryMap, k)
+						continue
+					}
+				}
+
+				if now.Unix()-et.LastTime.Unix() > 5 && et.backoff.Due(now.UnixNano()/1e6) && sent < max {
 					sent++
 					entryRequest := messages.NewMissingData(s, et.EntryHash)
 					entryRequest.SendOut(s, entryRequest)
 					newrequest++
 					et.LastTime = now.Add(time.Duration((rand.Int() % 5000)) * time.Millisecond)
 					et.Cnt++
+					et.backoff.Asked(AskEntry, s.GetAskPolicy(AskEntry), now.UnixNano()/1e6)
 				}
 
 			}
@@ -129,21 +152,17 @@ func (s *State) MakeMissingEntryRequests() {
 				asked := MissingEntryMap[entry.GetHash().Fixed()] != nil
 
 				if asked {
-					s.DB.StartMultiBatch()
-					err := s.DB.InsertEntryMultiBatch(entry)
-					if err != nil {
-						panic(err)
-					}
-					err = s.DB.ExecuteMultiBatch()
-					if err != nil {
-						panic(err)
-					}
+					s.Writes.AddEntry(entry)
 				}
 
 			default:
 				break InsertLoop
 			}
 		}
+		// The batch is written here, off the consensus loop, once full or a minute old
+		if reason := s.Writes.Due(time.Now(), s.roundWindow()); reason != "" {
+			s.FlushWrites(reason)
+		}
 		if sent == 0 {
 			if s.GetHighestKnownBlock()-s.GetHighestSavedBlk() > 100 {
 				time.Sleep(10 * time.Second)
@@ -182,6 +201,12 @@ func (s *State) GoSyncEntries() {
 
 		entryMissing = 0
 
+		// Entries of the blocks a proof pack covers wait for the blocks
+		if s.deferEntries() {
+			time.Sleep(10 * time.Second)
+			continue
+		}
+
 		for k := range missingMap {
 			if has(s, missingMap[k]) {
 				delete(missingMap, k)
@@ -194,6 +219,15 @@ func (s *State) GoSyncEntries() {
 		// First reset first Missing back to -1 every time.
 		firstMissing = -1
 
+		// A pruning node doesn't want the entries below the prune floor
+		if floor := s.PruneFloor(); start < floor {
+			start = floor
+		}
+		// Nor are there blocks below the snapshot we were bootstrapped from
+		if start < s.SnapshotHeight {
+			start = s.SnapshotHeight
+		}
+
 	dirblkSearch:
 		for scan := start; scan <= s.GetHighestSavedBlk(); scan++ {
 
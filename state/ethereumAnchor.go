@@ -0,0 +1,157 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/anchor"
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var ethAnchorLogger = packageLogger.WithFields(log.Fields{"subpack": "ethereum-anchor"})
+
+// Directory blocks between Ethereum anchors, unless configured
+const DefaultEthereumAnchorInterval = 10
+
+// How many anchors are sent on one pass, so a node catching up doesn't flood the contract
+const maxEthereumAnchorsPerPass = 10
+
+// EthereumAnchorer sends the KeyMRs of saved directory blocks to the anchor contract, and
+// saves the anchor records once the transactions are mined.
+type EthereumAnchorer struct {
+	mutex    sync.Mutex
+	client   *anchor.EthereumClient
+	interval uint32
+	next     uint32 // Next dbheight to anchor
+	started  bool
+	pending  []*anchor.AnchorRecord // Sent, but not yet mined; Ethereum holds only the TXID
+}
+
+func NewEthereumAnchorer(client *anchor.EthereumClient, interval uint32) *EthereumAnchorer {
+	a := new(EthereumAnchorer)
+	a.client = client
+	a.interval = interval
+	if a.interval == 0 {
+		a.interval = DefaultEthereumAnchorInterval
+	}
+	return a
+}
+
+// ethAnchorKeysActive returns true if the Ethereum anchor keys of authorities go in the
+// admin block at the height.  Old nodes can't read the admin block entry, so until then
+// the keys are kept in the identities only.
+func (s *State) ethAnchorKeysActive(dbheight uint32) bool {
+	return constants.IsActive(constants.ACTIVATION_ETH_ANCHOR, s.GetNetworkID(), dbheight)
+}
+
+// newEthereumAnchorer returns the anchorer of the configured node, or nil if there isn't one
+func (s *State) newEthereumAnchorer() *EthereumAnchorer {
+	if s.EthereumAnchorURL == "" {
+		return nil
+	}
+	client := anchor.NewEthereumClient(s.EthereumAnchorURL, s.EthereumAnchorFrom, s.EthereumAnchorContract)
+	return NewEthereumAnchorer(client, s.EthereumAnchorInterval)
+}
+
+// Pending returns how many anchors are waiting to be mined
+func (a *EthereumAnchorer) Pending() int {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	return len(a.pending)
+}
+
+// AnchorEthereum makes one pass: the saved directory blocks due an anchor since the last
+// pass are sent to the contract, and the anchors already sent are checked for receipts.
+func (s *State) AnchorEthereum() {
+	a := s.EthereumAnchors
+	if a == nil {
+		return
+	}
+	overlay, ok := s.DB.(*databaseOverlay.Overlay)
+	if !ok {
+		return
+	}
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	saved := s.GetHighestSavedBlk()
+	if !a.started {
+		// Pick up from the last height due an anchor; one we already have is skipped below
+		a.next = saved / a.interval * a.interval
+		a.started = true
+	}
+	for sent := 0; a.next <= saved && sent < maxEthereumAnchorsPerPass; a.next += a.interval {
+		keyMR := s.savedKeyMR(a.next)
+		if keyMR == nil {
+			break
+		}
+		if have, err := overlay.FetchEthereumAnchor(keyMR); err == nil && have != nil {
+			continue
+		}
+		txID, err := a.client.SubmitAnchor(a.next, keyMR)
+		if err != nil {
+			EthereumAnchorsVec.WithLabelValues("error").Inc()
+			ethAnchorLogger.Errorf("Cannot anchor dbheight %d: %v", a.next, err)
+			break
+		}
+		EthereumAnchorsVec.WithLabelValues("submitted").Inc()
+		ar := new(anchor.AnchorRecord)
+		ar.AnchorRecordVer = 1
+		ar.DBHeight = a.next
+		ar.KeyMR = keyMR.String()
+		ar.RecordHeight = a.next
+		ar.Ethereum = &anchor.EthereumStruct{Address: a.client.Contract, TXID: txID}
+		a.pending = append(a.pending, ar)
+		sent++
+	}
+
+	waiting := a.pending[:0]
+	for _, ar := range a.pending {
+		eth, err := a.client.TransactionReceipt(ar.Ethereum.TXID)
+		if err == anchor.ErrAnchorReverted {
+			// The height isn't retried until we restart
+			EthereumAnchorsVec.WithLabelValues("error").Inc()
+			ethAnchorLogger.Errorf("Anchor of dbheight %d in %s: %v", ar.DBHeight, ar.Ethereum.TXID, err)
+			continue
+		}
+		if err != nil {
+			ethAnchorLogger.Warnf("Cannot fetch the receipt of %s: %v", ar.Ethereum.TXID, err)
+			waiting = append(waiting, ar)
+			continue
+		}
+		if eth == nil {
+			waiting = append(waiting, ar)
+			continue
+		}
+		ar.Ethereum = eth
+		if err := overlay.SaveEthereumAnchor(ar); err != nil {
+			ethAnchorLogger.Errorf("Cannot save the anchor of dbheight %d: %v", ar.DBHeight, err)
+			waiting = append(waiting, ar)
+			continue
+		}
+		EthereumAnchorsVec.WithLabelValues("confirmed").Inc()
+	}
+	a.pending = waiting
+}
+
+// GoAnchorEthereum periodically anchors saved directory blocks into Ethereum, if a node
+// to send them through is configured
+func (s *State) GoAnchorEthereum() {
+	if s.EthereumAnchors == nil {
+		return
+	}
+	for {
+		time.Sleep(time.Minute)
+		if !s.DBFinished {
+			continue
+		}
+		s.AnchorEthereum()
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/FactomProject/factomd/anchor"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+	. "github.com/FactomProject/factomd/state"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+// ethNode sends every anchor as the same transaction, mined once mined is set
+type ethNode struct {
+	mutex sync.Mutex
+	sent  int
+	mined bool
+}
+
+func (n *ethNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	var req struct {
+		ID     uint64 `json:"id"`
+		Method string `json:"method"`
+	}
+	json.NewDecoder(r.Body).Decode(&req)
+	resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": nil}
+	switch req.Method {
+	case "eth_sendTransaction":
+		n.sent++
+		resp["result"] = "0xab"
+	case "eth_getTransactionReceipt":
+		if n.mined {
+			resp["result"] = map[string]string{"blockHash": "0xcd", "blockNumber": "0x10", "transactionIndex": "0x0", "status": "0x1"}
+		}
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+func TestAnchorEthereum(t *testing.T) {
+	node := new(ethNode)
+	server := httptest.NewServer(node)
+	defer server.Close()
+
+	s := testHelper.CreateAndPopulateTestState()
+	s.EthereumAnchors = NewEthereumAnchorer(anchor.NewEthereumClient(server.URL, "0x01", "0x02"), 1)
+	saved := s.GetHighestSavedBlk()
+
+	s.AnchorEthereum()
+	if node.sent != 1 {
+		t.Fatalf("Expected the highest saved block to be anchored, sent %d", node.sent)
+	}
+	if s.EthereumAnchors.Pending() != 1 {
+		t.Errorf("Expected the anchor to wait on its receipt, %d pending", s.EthereumAnchors.Pending())
+	}
+
+	node.mined = true
+	s.AnchorEthereum()
+	if s.EthereumAnchors.Pending() != 0 {
+		t.Errorf("Expected the mined anchor to be saved, %d pending", s.EthereumAnchors.Pending())
+	}
+	if node.sent != 1 {
+		t.Errorf("Anchored a height twice")
+	}
+
+	keyMR, _ := s.DB.FetchDBKeyMRByHeight(saved)
+	record, err := s.DB.(*databaseOverlay.Overlay).FetchEthereumAnchor(keyMR)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ar, ok := record.(*anchor.AnchorRecord)
+	if !ok || ar.DBHeight != saved || ar.Ethereum == nil || ar.Ethereum.BlockHeight != 16 {
+		t.Errorf("Wrong anchor saved: %v", record)
+	}
+}
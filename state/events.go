@@ -0,0 +1,155 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// Number of events buffered for each subscriber.  Saving a block publishes an event per
+// entry and transaction in it, so this is well above statusEventBuffer.
+const eventBuffer = 10000
+
+type eventSubscriber struct {
+	filter interfaces.EventFilter
+	events chan *interfaces.Event
+}
+
+// EventHub fans the events of saved blocks and status changes out to subscribers, each
+// getting only those its filter matches.  Like StatusEventHub, publishing never blocks;
+// slow subscribers lose events.
+type EventHub struct {
+	mutex       sync.Mutex
+	nextID      int
+	subscribers map[int]*eventSubscriber
+	Dropped     int // Count of events not delivered because a subscriber was full
+}
+
+func NewEventHub() *EventHub {
+	h := new(EventHub)
+	h.subscribers = make(map[int]*eventSubscriber)
+	return h
+}
+
+// Subscribe returns an id (used to unsubscribe) and the channel the events matching the
+// filter will arrive on
+func (h *EventHub) Subscribe(filter interfaces.EventFilter) (int, <-chan *interfaces.Event) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.nextID++
+	sub := &eventSubscriber{filter: filter, events: make(chan *interfaces.Event, eventBuffer)}
+	h.subscribers[h.nextID] = sub
+	return h.nextID, sub.events
+}
+
+// Unsubscribe removes the subscriber and closes its channel
+func (h *EventHub) Unsubscribe(id int) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if sub, ok := h.subscribers[id]; ok {
+		delete(h.subscribers, id)
+		close(sub.events)
+	}
+}
+
+// Subscribers returns the number of current subscribers
+func (h *EventHub) Subscribers() int {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return len(h.subscribers)
+}
+
+func (h *EventHub) Publish(events ...*interfaces.Event) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	for _, sub := range h.subscribers {
+		for _, e := range events {
+			if !sub.filter.Matches(e) {
+				continue
+			}
+			select {
+			case sub.events <- e:
+			default:
+				h.Dropped++
+			}
+		}
+	}
+}
+
+func (s *State) SubscribeEvents(filter interfaces.EventFilter) (int, <-chan *interfaces.Event) {
+	return s.Events.Subscribe(filter)
+}
+
+func (s *State) UnsubscribeEvents(id int) {
+	s.Events.Unsubscribe(id)
+}
+
+// publishBlockEvents tells subscribers about a block just saved: the directory block,
+// each entry, and each factoid transaction.  Called from SaveDBStateToDB.
+func (s *State) publishBlockEvents(d *DBState) {
+	if s.Events == nil || s.Events.Subscribers() == 0 {
+		return
+	}
+	dbheight := d.DirectoryBlock.GetHeader().GetDBHeight()
+	now := time.Now().Unix()
+
+	events := []*interfaces.Event{{
+		Type:      interfaces.EventDirectoryBlock,
+		DBHeight:  dbheight,
+		Timestamp: now,
+		KeyMR:     d.DirectoryBlock.GetKeyMR().String(),
+	}}
+
+	for _, eb := range d.EntryBlocks {
+		keymr, err := eb.KeyMR()
+		if err != nil {
+			continue
+		}
+		chainID := eb.GetChainID().String()
+		for _, h := range eb.GetEntryHashes() {
+			if h.IsMinuteMarker() {
+				continue
+			}
+			events = append(events, &interfaces.Event{
+				Type:      interfaces.EventEntry,
+				DBHeight:  dbheight,
+				Timestamp: now,
+				KeyMR:     keymr.String(),
+				ChainID:   chainID,
+				EntryHash: h.String(),
+			})
+		}
+	}
+
+	if d.FactoidBlock != nil {
+		for _, tx := range d.FactoidBlock.GetTransactions() {
+			e := &interfaces.Event{
+				Type:      interfaces.EventFactoidTransaction,
+				DBHeight:  dbheight,
+				Timestamp: now,
+				TxID:      tx.GetSigHash().String(),
+			}
+			for _, in := range tx.GetInputs() {
+				e.Addresses = append(e.Addresses, primitives.ConvertFctAddressToUserStr(in.GetAddress()))
+			}
+			for _, out := range tx.GetOutputs() {
+				e.Addresses = append(e.Addresses, primitives.ConvertFctAddressToUserStr(out.GetAddress()))
+			}
+			for _, out := range tx.GetECOutputs() {
+				e.Addresses = append(e.Addresses, primitives.ConvertECAddressToUserStr(out.GetAddress()))
+			}
+			events = append(events, e)
+		}
+	}
+
+	s.Events.Publish(events...)
+}
@@ -0,0 +1,73 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	. "github.com/FactomProject/factomd/state"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestEventHub(t *testing.T) {
+	h := NewEventHub()
+	_, all := h.Subscribe(interfaces.EventFilter{})
+	_, chain := h.Subscribe(interfaces.EventFilter{Types: []string{interfaces.EventEntry}, ChainIDs: []string{"aa"}})
+	id, addr := h.Subscribe(interfaces.EventFilter{Addresses: []string{"FA1"}})
+	if h.Subscribers() != 3 {
+		t.Errorf("Expected 3 subscribers, found %d", h.Subscribers())
+	}
+
+	h.Publish(
+		&interfaces.Event{Type: interfaces.EventDirectoryBlock, DBHeight: 5},
+		&interfaces.Event{Type: interfaces.EventEntry, ChainID: "aa", EntryHash: "1"},
+		&interfaces.Event{Type: interfaces.EventEntry, ChainID: "bb", EntryHash: "2"},
+		&interfaces.Event{Type: interfaces.EventFactoidTransaction, TxID: "t1", Addresses: []string{"FA2", "EC1"}},
+		&interfaces.Event{Type: interfaces.EventFactoidTransaction, TxID: "t2", Addresses: []string{"FA3", "FA1"}},
+	)
+
+	if n := len(all); n != 5 {
+		t.Errorf("Unfiltered subscriber got %d events, expected 5", n)
+	}
+	if n := len(chain); n != 1 {
+		t.Errorf("Chain subscriber got %d events, expected 1", n)
+	} else if e := <-chain; e.EntryHash != "1" {
+		t.Errorf("Chain subscriber got the wrong entry %+v", e)
+	}
+	// No types, so blocks and both entries come through, but only the one transaction
+	var txs []string
+	for len(addr) > 0 {
+		if e := <-addr; e.Type == interfaces.EventFactoidTransaction {
+			txs = append(txs, e.TxID)
+		}
+	}
+	if len(txs) != 1 || txs[0] != "t2" {
+		t.Errorf("Address subscriber got transactions %v, expected [t2]", txs)
+	}
+
+	h.Unsubscribe(id)
+	if _, ok := <-addr; ok {
+		t.Error("Channel should be closed on unsubscribe")
+	}
+
+	// A subscriber that never reads must not block publishing
+	for i := 0; i < 20000; i++ {
+		h.Publish(&interfaces.Event{Type: interfaces.EventDirectoryBlock})
+	}
+	if h.Dropped == 0 {
+		t.Error("Expected events to be dropped for a full subscriber")
+	}
+}
+
+func TestStatusEventsAsEvents(t *testing.T) {
+	s := testHelper.CreateEmptyTestState()
+	_, events := s.SubscribeEvents(interfaces.EventFilter{Types: []string{interfaces.EventNodeStatus}})
+	s.PublishStatusEvent(interfaces.StatusEventLeader, 2, "")
+	e := <-events
+	if e.Type != interfaces.EventNodeStatus || e.Status == nil || e.Status.Type != interfaces.StatusEventLeader || e.Status.VMIndex != 2 {
+		t.Errorf("Unexpected event %+v", e)
+	}
+}
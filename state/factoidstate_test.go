@@ -56,8 +56,8 @@ func TestBalanceHash(t *testing.T) {
 	fs := new(FactoidState)
 	s.FactoidState = fs
 	fs.State = s
-	s.FactoidBalancesP = map[[32]byte]int64{}
-	s.ECBalancesP = map[[32]byte]int64{}
+	s.FactoidBalancesP = NewBalanceStore()
+	s.ECBalancesP = NewBalanceStore()
 
 	var ec, fct []interfaces.IHash
 	h := primitives.Sha([]byte("testing"))
@@ -78,14 +78,14 @@ func TestBalanceHash(t *testing.T) {
 		t.Errorf("Expected %s but found %s", Expected, hbal.String())
 	}
 
-	x := func(addrArray []interfaces.IHash, balanceArray *map[[32]byte]int64) {
+	x := func(addrArray []interfaces.IHash, balances *BalanceStore) {
 
 		// Add a random address
 		for i := 1; i < 10; i++ {
 			h = primitives.Sha(h.Bytes())
 			adr := h
 			bal := RandBal()
-			(*balanceArray)[adr.Fixed()] = bal
+			balances.Put(adr.Fixed(), bal)
 
 			hbal := fs.GetBalanceHash(false)
 
@@ -93,7 +93,7 @@ func TestBalanceHash(t *testing.T) {
 				t.Errorf("Should not have gotten %s", Expected)
 			}
 
-			delete((*balanceArray), adr.Fixed())
+			balances.Delete(adr.Fixed())
 
 			hbal = fs.GetBalanceHash(false)
 
@@ -106,15 +106,15 @@ func TestBalanceHash(t *testing.T) {
 		for i := 1; i < 10; i++ {
 			indx := rand.Int() % len(addrArray)
 			adr := addrArray[indx].Fixed()
-			bal := (*balanceArray)[adr]
-			delete((*balanceArray), adr)
+			bal, _ := balances.Get(adr)
+			balances.Delete(adr)
 
 			hbal := fs.GetBalanceHash(false)
 
 			if hbal.String() == Expected {
 				t.Errorf("Should not have gotten %s", Expected)
 			}
-			(*balanceArray)[adr] = bal
+			balances.Put(adr, bal)
 
 			hbal = fs.GetBalanceHash(false)
 
@@ -128,15 +128,15 @@ func TestBalanceHash(t *testing.T) {
 			indx := rand.Int() % len(addrArray)
 			adr := addrArray[indx].Fixed()
 
-			bal := (*balanceArray)[adr]
-			(*balanceArray)[adr] = bal ^ RandBit()
+			bal, _ := balances.Get(adr)
+			balances.Put(adr, bal^RandBit())
 
 			hbal := fs.GetBalanceHash(false)
 			if hbal.String() == Expected {
 				t.Errorf("Should not have gotten %s", Expected)
 			}
 
-			(*balanceArray)[adr] = bal
+			balances.Put(adr, bal)
 
 			hbal = fs.GetBalanceHash(false)
 
@@ -148,8 +148,8 @@ func TestBalanceHash(t *testing.T) {
 
 	}
 
-	x(fct, &s.FactoidBalancesP)
-	x(ec, &s.ECBalancesP)
+	x(fct, s.FactoidBalancesP)
+	x(ec, s.ECBalancesP)
 
 }
 
@@ -18,6 +18,7 @@ import (
 )
 
 var faultLogger = packageLogger.WithFields(log.Fields{"subpack": "fault"})
+var electionLogger = packageLogger.WithFields(log.Fields{"subpack": "election"})
 
 type FaultCore struct {
 	// The following 5 fields represent the "Core" of the message
@@ -73,6 +74,9 @@ func (fc *FaultCore) MarshalCore() (data []byte, err error) {
 	return buf.DeepCopyBytes(), nil
 }
 
+// A VM faulted because its leader volunteered to step down
+const faultStepDown = 2
+
 func markFault(pl *ProcessList, vmIndex int, faultReason int) {
 	// We can use the "IgnoreMissing" boolean to track if enough time has elapsed
 	// since bootup to start faulting servers on the network
@@ -84,6 +88,11 @@ func markFault(pl *ProcessList, vmIndex int, faultReason int) {
 		return
 	}
 
+	// A newly promoted leader is given time to catch up
+	if pl.InLeaderGrace(vmIndex) {
+		return
+	}
+
 	now := time.Now().Unix()
 	vm := pl.VMs[vmIndex]
 
@@ -107,13 +116,23 @@ func markFault(pl *ProcessList, vmIndex int, faultReason int) {
 
 func markNoFault(pl *ProcessList, vmIndex int) {
 	vm := pl.VMs[vmIndex]
+	elections := pl.State.electionsActive(pl.DBHeight)
+
+	// The leader came back before the election to replace it was decided
+	if e := pl.Elections[vmIndex]; elections && e != nil && e.Phase != ElectionDecided {
+		delete(pl.Elections, vmIndex)
+		electionLogger.WithFields(e.LogFields()).Info("Election cleared")
+		pl.State.PublishStatusEvent(interfaces.StatusEventElectionEnd, vmIndex, "cleared")
+	}
 
 	vm.WhenFaulted = 0
 	vm.FaultFlag = -1
 
-	nextIndex := (vmIndex + 1) % len(pl.FedServers)
-	if pl.VMs[nextIndex].FaultFlag > 0 {
-		markNoFault(pl, nextIndex)
+	if !elections {
+		nextIndex := (vmIndex + 1) % len(pl.FedServers)
+		if pl.VMs[nextIndex].FaultFlag > 0 && pl.VMs[nextIndex].FaultFlag != faultStepDown {
+			markNoFault(pl, nextIndex)
+		}
 	}
 
 	c := pl.State.CurrentMinute
@@ -125,6 +144,9 @@ func markNoFault(pl *ProcessList, vmIndex int) {
 		pl.FedServers[index].SetOnline(true)
 	}
 
+	if elections {
+		return
+	}
 	cf := pl.CurrentFault()
 	if !cf.IsNil() {
 		if cf.AmINegotiator {
@@ -136,9 +158,10 @@ func markNoFault(pl *ProcessList, vmIndex int) {
 			}
 		}
 	}
-
 }
 
+// NegotiationCheck and FaultCheck drive the fault negotiation that replaces faulted
+// leaders below the activation height of elections
 func NegotiationCheck(pl *ProcessList) {
 	if !pl.State.Leader {
 		//If I'm not a leader, do not attempt to negotiate
@@ -428,6 +451,276 @@ func (s *State) matchFault(sf *messages.ServerFault) {
 // When we execute a FullFault message, it could be complete (includes all
 // necessary signatures + pledge) or incomplete, in which case it is just
 // a negotiation ping
+// matchNegotiation tallies the votes of an incomplete FullFault of the fault negotiation,
+// and matches the fault if we are a leader, or the audit server nominated, and haven't
+// voted for it yet
+func (s *State) matchNegotiation(pl *ProcessList, fullFault *messages.FullServerFault) {
+	// We need to see whether our signature is included, and match the fault if not
+	// (assuming we agree with the basic premise of the fault)
+
+	for _, signature := range fullFault.SignatureList.List {
+		var issuerID [32]byte
+		rawIssuerID := signature.GetKey()
+		for i := 0; i < 32; i++ {
+			if i < len(rawIssuerID) {
+				issuerID[i] = rawIssuerID[i]
+			}
+		}
+
+		lbytes := fullFault.GetCoreHash().Bytes()
+
+		isPledge := false
+		auth, _ := s.GetAuthority(fullFault.AuditServerID)
+		if auth == nil {
+			isPledge = false
+		} else {
+			valid, err := auth.VerifySignature(lbytes, signature.GetSignature())
+			if err == nil && valid {
+				isPledge = true
+				fullFault.SetPledgeDone(true)
+			}
+		}
+
+		sfSigned, err := s.FastVerifyAuthoritySignature(lbytes, signature, fullFault.DBHeight)
+
+		if err == nil && (sfSigned > 0 || (sfSigned == 0 && isPledge)) {
+			fullFault.AddFaultVote(issuerID, fullFault.GetSignature())
+		}
+
+		if s.Leader || s.IdentityChainID.IsSameAs(fullFault.AuditServerID) {
+			if !fullFault.GetMyVoteTallied() {
+				nsf := messages.NewServerFault(fullFault.ServerID, fullFault.AuditServerID, int(fullFault.VMIndex), fullFault.DBHeight,
+					fullFault.Height, int(fullFault.SystemHeight), fullFault.Timestamp)
+				sfbytes, err := nsf.MarshalForSignature()
+				myAuth, _ := s.GetAuthority(s.IdentityChainID)
+				if myAuth == nil || err != nil {
+					continue
+				}
+				valid, err := myAuth.VerifySignature(sfbytes, signature.GetSignature())
+				if err == nil && valid {
+					fullFault.SetMyVoteTallied(true)
+				}
+			}
+		}
+	}
+
+	if s.Leader || s.IdentityChainID.IsSameAs(fullFault.AuditServerID) {
+		if !fullFault.GetMyVoteTallied() {
+			now := time.Now().Unix()
+			if now-fullFault.LastMatch > 5 && int(now-s.LastTiebreak) > s.FaultTimeout/2 {
+				if fullFault.SigTally(s) >= len(pl.FedServers)-1 {
+					s.LastTiebreak = now
+				}
+
+				nsf := messages.NewServerFault(fullFault.ServerID, fullFault.AuditServerID, int(fullFault.VMIndex),
+					fullFault.DBHeight, fullFault.Height, int(fullFault.SystemHeight), fullFault.Timestamp)
+				//s.AddStatus(fmt.Sprintf("Match FullFault: %s", nsf.String()))
+
+				s.matchFault(nsf)
+			}
+		}
+	}
+}
+
+// ElectionCheck opens an election for the first VM that has been faulted for FaultTimeout
+// seconds, moves it through its rounds, and takes our turn in it.  One election runs at a
+// time, as its outcome takes the next place in the System list.
+func ElectionCheck(pl *ProcessList) {
+	s := pl.State
+	pl.SetAmINegotiator(false)
+
+	// An outcome is waiting to be processed
+	if len(pl.System.List) > pl.System.Height {
+		return
+	}
+
+	now := time.Now().Unix()
+	for i := 0; i < len(pl.FedServers); i++ {
+		vm := pl.VMs[i]
+		if vm.WhenFaulted == 0 || now-vm.WhenFaulted < int64(s.FaultTimeout) {
+			continue
+		}
+		e := pl.Elections[i]
+		if e == nil {
+			if e = s.openElection(pl, i, now); e == nil {
+				continue
+			}
+		}
+		if e.Tick(now, int64(s.FaultTimeout)) {
+			TotalElectionRounds.Inc()
+			electionLogger.WithFields(e.LogFields()).Info("Next round")
+		}
+		s.electionTurn(pl, e)
+		return
+	}
+}
+
+// openElection starts the election to replace the leader of a faulted VM, if there are
+// audit servers to replace it with
+func (s *State) openElection(pl *ProcessList, vmIndex int, now int64) *Election {
+	c := s.CurrentMinute
+	if c > 9 {
+		c = 9
+	}
+	index := pl.ServerMap[c][vmIndex]
+	if index >= len(pl.FedServers) || len(pl.AuditServers) == 0 {
+		return nil
+	}
+	var audits []interfaces.IHash
+	for _, audit := range pl.AuditServers {
+		audits = append(audits, audit.GetChainID())
+	}
+	faulted := pl.FedServers[index].GetChainID()
+
+	e := NewElection(pl.DBHeight, vmIndex, faulted, audits, len(pl.FedServers), now)
+	pl.Elections[vmIndex] = e
+	TotalElections.Inc()
+	electionLogger.WithFields(e.LogFields()).Warn("Election opened")
+	s.PublishStatusEvent(interfaces.StatusEventElectionStart, vmIndex, fmt.Sprintf("replacing %x", faulted.Bytes()[3:6]))
+	return e
+}
+
+// electionTurn volunteers if we are the audit server of the round, or accepts the volunteer
+// if we are a leader, once a round
+func (s *State) electionTurn(pl *ProcessList, e *Election) {
+	if e.Sent {
+		pl.SetAmINegotiator(true)
+		return
+	}
+
+	switch e.Phase {
+	case ElectionWaiting:
+		candidate := e.Candidate()
+		if candidate == nil || !candidate.IsSameAs(s.IdentityChainID) {
+			return
+		}
+		core := messages.ElectionCore{
+			ServerID:      e.ServerID,
+			AuditServerID: s.IdentityChainID,
+			VMIndex:       byte(e.VMIndex),
+			DBHeight:      pl.DBHeight,
+			Height:        uint32(pl.VMs[e.VMIndex].Height),
+			SystemHeight:  uint32(pl.System.Height),
+			Timestamp:     s.GetTimestamp(),
+		}
+		v := messages.NewElectionVolunteer(core, e.Round)
+		if err := v.Sign(s.serverPrivKey); err != nil {
+			electionLogger.Errorf("Cannot sign the volunteer: %v", err)
+			return
+		}
+		e.Sent = true
+		pl.SetAmINegotiator(true)
+		v.SendOut(s, v)
+		v.FollowerExecute(s)
+
+	case ElectionAccepting:
+		if found, _ := pl.GetFedServerIndexHash(s.IdentityChainID); !found || s.IdentityChainID.IsSameAs(e.ServerID) {
+			return
+		}
+		// The outcome has to go in the next place in the System list
+		if int(e.Volunteer.SystemHeight) != pl.System.Height {
+			return
+		}
+		a := messages.NewElectionAccept(e.Volunteer)
+		if err := a.Sign(s.serverPrivKey); err != nil {
+			electionLogger.Errorf("Cannot sign the acceptance: %v", err)
+			return
+		}
+		e.Sent = true
+		pl.SetAmINegotiator(true)
+		a.SendOut(s, a)
+		a.FollowerExecute(s)
+	}
+}
+
+// recordElection puts the outcome of a decided election in the System list.  Any leader
+// can record it, and the others take the first they see.
+func (s *State) recordElection(pl *ProcessList, e *Election) {
+	TotalElectionsDecided.Inc()
+	electionLogger.WithFields(e.LogFields()).Warn("Election decided")
+
+	if found, _ := pl.GetFedServerIndexHash(s.IdentityChainID); !found || e.Volunteer == nil {
+		return
+	}
+	var previous *messages.FullServerFault
+	if pl.System.Height > 0 {
+		previous, _ = pl.System.List[pl.System.Height-1].(*messages.FullServerFault)
+	}
+	ff := messages.NewFullServerFault(previous, e.Volunteer.ServerFault(), e.Signatures(), int(e.Volunteer.SystemHeight))
+	if err := ff.Sign(s.serverPrivKey); err != nil {
+		electionLogger.Errorf("Cannot sign the outcome of the election: %v", err)
+		return
+	}
+	ff.SendOut(s, ff)
+	ff.FollowerExecute(s)
+}
+
+// FollowerExecuteElectionVolunteer takes the volunteer of a round into the election of its
+// VM, holding it until we see the fault ourselves.  Below the height elections are
+// activated at, it is dropped.
+func (s *State) FollowerExecuteElectionVolunteer(m interfaces.IMsg) {
+	v, ok := m.(*messages.ElectionVolunteer)
+	if !ok || !s.electionsActive(v.DBHeight) {
+		return
+	}
+	pl := s.ProcessLists.Get(v.DBHeight)
+	if pl == nil || int(v.ElectionCore.VMIndex) >= len(pl.FedServers) {
+		return
+	}
+
+	// The pledge has to be the volunteer's own
+	auth, _ := s.GetAuthority(v.AuditServerID)
+	if auth == nil {
+		return
+	}
+	core, err := v.MarshalCore()
+	if err != nil {
+		return
+	}
+	if valid, err := auth.VerifySignature(core, v.Signature.GetSignature()); err != nil || !valid {
+		return
+	}
+
+	e := pl.Elections[int(v.ElectionCore.VMIndex)]
+	if e == nil {
+		s.Holding[m.GetMsgHash().Fixed()] = m
+		return
+	}
+	if e.AddVolunteer(v, time.Now().Unix()) {
+		electionLogger.WithFields(v.LogFields()).Info("Volunteer")
+		if e.Phase == ElectionDecided {
+			s.recordElection(pl, e)
+		}
+	}
+}
+
+// FollowerExecuteElectionAccept counts the acceptance of a leader in the election of its
+// VM, holding it until we see the fault ourselves.  Below the height elections are
+// activated at, it is dropped.
+func (s *State) FollowerExecuteElectionAccept(m interfaces.IMsg) {
+	a, ok := m.(*messages.ElectionAccept)
+	if !ok || !s.electionsActive(a.DBHeight) {
+		return
+	}
+	pl := s.ProcessLists.Get(a.DBHeight)
+	if pl == nil || int(a.ElectionCore.VMIndex) >= len(pl.FedServers) {
+		return
+	}
+
+	e := pl.Elections[int(a.ElectionCore.VMIndex)]
+	if e == nil {
+		s.Holding[m.GetMsgHash().Fixed()] = m
+		return
+	}
+	if e.AddAcceptance(a) {
+		s.recordElection(pl, e)
+	}
+}
+
+// When we execute a FullFault message, it could be complete (includes all
+// necessary signatures + pledge) or incomplete, in which case it is just
+// a negotiation ping.  Once elections are in effect only complete ones,
+// the outcomes of elections, go in the System list.
 func (s *State) FollowerExecuteFullFault(m interfaces.IMsg) {
 	fullFault, _ := m.(*messages.FullServerFault)
 
@@ -443,15 +736,13 @@ func (s *State) FollowerExecuteFullFault(m interfaces.IMsg) {
 		return
 	}
 
-	//s.AddStatus(fmt.Sprintf("FULL FAULT FOLLOWER EXECUTE Execute Full Fault:  Replacing %x with %x at height %d leader height %d %s",
-	//	fullFault.ServerID.Bytes()[2:6],
-	//	fullFault.AuditServerID.Bytes()[2:6],
-	//	fullFault.DBHeight,
-	//	s.LLeaderHeight,
-	//	fullFault.String()))
-
-	faultLogger.WithField("func", "AddToSystemList").WithFields(fullFault.LogFields()).Warn("Add to System List")
-	pl.AddToSystemList(fullFault)
+	if pl.AddToSystemList(fullFault) {
+		faultLogger.WithField("func", "AddToSystemList").WithFields(fullFault.LogFields()).Warn("Add to System List")
+		if !s.electionsActive(fullFault.DBHeight) {
+			s.PublishStatusEvent(interfaces.StatusEventElectionStart, int(fullFault.VMIndex),
+				fmt.Sprintf("replacing %x with %x", fullFault.ServerID.Bytes()[3:6], fullFault.AuditServerID.Bytes()[3:6]))
+		}
+	}
 }
 
 // If a FullFault message includes a signature from the Audit server
@@ -0,0 +1,256 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"sort"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+)
+
+// What a held message can be waiting on
+const (
+	HoldForAck    = "ack"    // The ack of the message, by its hash
+	HoldForCommit = "commit" // A commit paying for a reveal, by entry hash
+	HoldForChain  = "chain"  // The chain an entry goes in, by chain ID
+)
+
+// How long (milliseconds) a message waits on its dependency before ReviewHolding treats
+// it like any other held message, in case what it waits on came in some way that
+// didn't release it
+const HoldingDependencyTimeout = 5000
+
+type holdingKey struct {
+	kind string
+	hash [32]byte
+}
+
+type heldWait struct {
+	on    holdingKey
+	since int64
+}
+
+// HoldingDependencies indexes the messages in Holding by what they are waiting on, so
+// they can be released as soon as it arrives, rather than reviewed over and over until
+// it does.  Like Holding itself, it is only used from the consensus loop.
+type HoldingDependencies struct {
+	waiting map[[32]byte]heldWait                // Held message -> what it waits on
+	waiters map[holdingKey]map[[32]byte]struct{} // What is waited on -> held messages
+}
+
+func NewHoldingDependencies() *HoldingDependencies {
+	d := new(HoldingDependencies)
+	d.waiting = make(map[[32]byte]heldWait)
+	d.waiters = make(map[holdingKey]map[[32]byte]struct{})
+	return d
+}
+
+// Wait records that the held message msg is waiting on the hash on, of the given kind
+func (d *HoldingDependencies) Wait(msg [32]byte, kind string, on [32]byte, now int64) {
+	key := holdingKey{kind, on}
+	if w, ok := d.waiting[msg]; ok {
+		if w.on == key {
+			return
+		}
+		d.Forget(msg)
+	}
+	d.waiting[msg] = heldWait{on: key, since: now}
+	if d.waiters[key] == nil {
+		d.waiters[key] = make(map[[32]byte]struct{})
+	}
+	d.waiters[key][msg] = struct{}{}
+}
+
+// Release returns the messages waiting on the hash on, and forgets them
+func (d *HoldingDependencies) Release(kind string, on [32]byte) (released [][32]byte) {
+	if d == nil {
+		return nil
+	}
+	key := holdingKey{kind, on}
+	for msg := range d.waiters[key] {
+		delete(d.waiting, msg)
+		released = append(released, msg)
+	}
+	delete(d.waiters, key)
+	return released
+}
+
+// Forget drops what a message was waiting on
+func (d *HoldingDependencies) Forget(msg [32]byte) {
+	if d == nil {
+		return
+	}
+	w, ok := d.waiting[msg]
+	if !ok {
+		return
+	}
+	delete(d.waiting, msg)
+	delete(d.waiters[w.on], msg)
+	if len(d.waiters[w.on]) == 0 {
+		delete(d.waiters, w.on)
+	}
+}
+
+// WaitingOn returns the kind of thing the message is waiting on, if it is waiting and has
+// not waited too long
+func (d *HoldingDependencies) WaitingOn(msg [32]byte, now int64) (kind string, ok bool) {
+	if d == nil {
+		return "", false
+	}
+	w, ok := d.waiting[msg]
+	if !ok || now-w.since >= HoldingDependencyTimeout {
+		return "", false
+	}
+	return w.on.kind, true
+}
+
+// Counts returns how many messages wait on each kind of dependency
+func (d *HoldingDependencies) Counts() map[string]int {
+	counts := map[string]int{HoldForAck: 0, HoldForCommit: 0, HoldForChain: 0}
+	if d == nil {
+		return counts
+	}
+	for _, w := range d.waiting {
+		counts[w.on.kind]++
+	}
+	return counts
+}
+
+// Prune forgets the messages no longer in holding
+func (d *HoldingDependencies) Prune(holding map[[32]byte]interfaces.IMsg) {
+	if d == nil {
+		return
+	}
+	for msg := range d.waiting {
+		if _, ok := holding[msg]; !ok {
+			d.Forget(msg)
+		}
+	}
+}
+
+// HoldingPriority orders the messages reviewed from holding: what moves the block along
+// first, then acks, then everything else, and reveals last as they need their commits.
+func HoldingPriority(msg interfaces.IMsg) int {
+	switch msg.Type() {
+	case constants.DBSTATE_MSG, constants.DIRECTORY_BLOCK_SIGNATURE_MSG, constants.EOM_MSG,
+		constants.FED_SERVER_FAULT_MSG, constants.FULL_SERVER_FAULT_MSG, constants.MISSING_MSG_RESPONSE,
+		constants.ELECTION_VOLUNTEER_MSG, constants.ELECTION_ACCEPT_MSG:
+		return 0
+	case constants.ACK_MSG, constants.BATCH_ACK_MSG:
+		return 1
+	case constants.REVEAL_ENTRY_MSG:
+		return 3
+	}
+	return 2
+}
+
+// holdingOrder sorts messages by priority, and reveals by what their commits pay per
+// credit they need, highest first, so the best paying entries get in when blocks fill up
+type holdingOrder struct {
+	msgs     []interfaces.IMsg
+	priority []int
+	credits  []int
+	needed   []int
+}
+
+func (s *State) newHoldingOrder(msgs []interfaces.IMsg) holdingOrder {
+	o := holdingOrder{msgs, make([]int, len(msgs)), make([]int, len(msgs)), make([]int, len(msgs))}
+	for i, m := range msgs {
+		o.priority[i] = HoldingPriority(m)
+		if re, ok := m.(*messages.RevealEntryMsg); ok && re.Entry != nil {
+			o.credits[i], o.needed[i] = s.revealFee(re)
+		}
+	}
+	return o
+}
+
+func (o holdingOrder) Len() int { return len(o.msgs) }
+func (o holdingOrder) Swap(i, j int) {
+	o.msgs[i], o.msgs[j] = o.msgs[j], o.msgs[i]
+	o.priority[i], o.priority[j] = o.priority[j], o.priority[i]
+	o.credits[i], o.credits[j] = o.credits[j], o.credits[i]
+	o.needed[i], o.needed[j] = o.needed[j], o.needed[i]
+}
+func (o holdingOrder) Less(i, j int) bool {
+	if o.priority[i] != o.priority[j] {
+		return o.priority[i] < o.priority[j]
+	}
+	return paysMore(o.credits[i], o.needed[i], o.credits[j], o.needed[j])
+}
+
+// waitFor records what a message just put in holding is waiting on
+func (s *State) waitFor(m interfaces.IMsg, kind string, on [32]byte) {
+	if s.HoldingDeps == nil {
+		s.HoldingDeps = NewHoldingDependencies()
+	}
+	s.HoldingDeps.Wait(m.GetMsgHash().Fixed(), kind, on, s.GetTimestamp().GetTimeMilli())
+}
+
+// released forgets the waits on the hash on, where what arrived already takes the
+// messages waiting on it out of holding
+func (s *State) released(kind string, on [32]byte) {
+	if n := len(s.HoldingDeps.Release(kind, on)); n > 0 {
+		HoldingReleasesVec.WithLabelValues(kind).Add(float64(n))
+	}
+}
+
+// releaseHeld moves the messages waiting on the hash on from holding to be reviewed now
+func (s *State) releaseHeld(kind string, on [32]byte) {
+	for _, h := range s.HoldingDeps.Release(kind, on) {
+		m := s.Holding[h]
+		if m == nil {
+			continue
+		}
+		HoldingReleasesVec.WithLabelValues(kind).Inc()
+		TotalXReviewQueueInputs.Inc()
+		s.XReview = append(s.XReview, m)
+		TotalHoldingQueueOutputs.Inc()
+		delete(s.Holding, h)
+	}
+}
+
+// leaderHeld corrects what a message the leader put in holding waits on: not an ack, as
+// the leader acks it itself, but whatever holdingDependency finds, if anything
+func (s *State) leaderHeld(m interfaces.IMsg) {
+	h := m.GetMsgHash().Fixed()
+	if _, held := s.Holding[h]; !held {
+		return
+	}
+	s.HoldingDeps.Forget(h)
+	if kind, on, ok := s.holdingDependency(m); ok {
+		s.waitFor(m, kind, on)
+	}
+}
+
+// holdingDependency works out what a message that isn't valid yet is waiting on.  Only
+// reveals wait on something we can name: a commit (or a bigger one), or their chain.
+func (s *State) holdingDependency(m interfaces.IMsg) (kind string, on [32]byte, ok bool) {
+	re, isReveal := m.(*messages.RevealEntryMsg)
+	if !isReveal || re.Entry == nil {
+		return "", on, false
+	}
+	switch c := s.NextCommit(re.Entry.GetHash()).(type) {
+	case nil:
+		return HoldForCommit, re.Entry.GetHash().Fixed(), true
+	case *messages.CommitEntryMsg:
+		if re.Entry.KSize() > int(c.CommitEntry.Credits) {
+			return HoldForCommit, re.Entry.GetHash().Fixed(), true
+		}
+		return HoldForChain, re.Entry.GetChainID().Fixed(), true
+	case *messages.CommitChainMsg:
+		if re.Entry.KSize()+10 > int(c.CommitChain.Credits) {
+			return HoldForCommit, re.Entry.GetHash().Fixed(), true
+		}
+	}
+	return "", on, false
+}
+
+// sortXReview puts the messages to review in priority order, reveals paying the most
+// first, keeping the order of messages otherwise the same
+func (s *State) sortXReview() {
+	sort.Stable(s.newHoldingOrder(s.XReview))
+}
@@ -0,0 +1,107 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/interfaces"
+)
+
+// Time between ReviewHolding passes, unless configured
+const DefaultHoldingReviewInterval = 300 * time.Millisecond
+
+// How long a forced pass is waited for before giving up on the consensus thread
+const holdingReviewTimeout = 10 * time.Second
+
+// HoldingReviews remembers the last ReviewHolding pass, and carries the requests for forced
+// passes to the consensus thread.
+type HoldingReviews struct {
+	mutex    sync.Mutex
+	last     interfaces.HoldingReview
+	requests chan chan interfaces.HoldingReview
+}
+
+func NewHoldingReviews() *HoldingReviews {
+	h := new(HoldingReviews)
+	h.requests = make(chan chan interfaces.HoldingReview, 1)
+	return h
+}
+
+// Last returns the summary of the last pass
+func (h *HoldingReviews) Last() interfaces.HoldingReview {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return h.last
+}
+
+func (h *HoldingReviews) record(r interfaces.HoldingReview) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.last = r
+}
+
+// holdingReviewInterval returns the least time between passes
+func (s *State) holdingReviewInterval() time.Duration {
+	if s.HoldingReviewInterval > 0 {
+		return s.HoldingReviewInterval
+	}
+	return DefaultHoldingReviewInterval
+}
+
+// holdingReviewQueueLow returns the inMsgQueue length above which passes are put off
+func (s *State) holdingReviewQueueLow() int {
+	if s.HoldingReviewQueueLow > 0 {
+		return s.HoldingReviewQueueLow
+	}
+	return constants.INMSGQUEUE_LOW
+}
+
+// forcedHoldingReview runs the pass asked for through ReviewHoldingNow, if there is one.
+// Returns true if it did.
+func (s *State) forcedHoldingReview() bool {
+	if s.HoldingReviews == nil {
+		return false
+	}
+	select {
+	case reply := <-s.HoldingReviews.requests:
+		reply <- s.reviewHolding(true)
+		return true
+	default:
+		return false
+	}
+}
+
+// ReviewHoldingNow has the consensus thread make a ReviewHolding pass, ignoring the time
+// since the last and the length of the inMsgQueue, and returns what it did.  A pass is
+// still skipped while the messages of the last are waiting in XReview.
+func (s *State) ReviewHoldingNow() (interfaces.HoldingReview, error) {
+	if s.HoldingReviews == nil {
+		return interfaces.HoldingReview{}, fmt.Errorf("Holding reviews are not set up")
+	}
+	reply := make(chan interfaces.HoldingReview, 1)
+	select {
+	case s.HoldingReviews.requests <- reply:
+	default:
+		return interfaces.HoldingReview{}, fmt.Errorf("A holding review is already waiting to run")
+	}
+	select {
+	case r := <-reply:
+		return r, nil
+	case <-time.After(holdingReviewTimeout):
+		return interfaces.HoldingReview{}, fmt.Errorf("The consensus thread did not review holding within %v", holdingReviewTimeout)
+	}
+}
+
+// GetLastHoldingReview returns what the last ReviewHolding pass did
+func (s *State) GetLastHoldingReview() interfaces.HoldingReview {
+	if s.HoldingReviews == nil {
+		return interfaces.HoldingReview{}
+	}
+	return s.HoldingReviews.Last()
+}
@@ -0,0 +1,85 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestReviewHoldingNow(t *testing.T) {
+	// A state with no validator running, so only the test makes passes
+	s := testHelper.CreateEmptyTestState()
+	s.DBStates.Base = 5
+	// Passes are only made when forced
+	s.HoldingReviewInterval = time.Hour
+
+	eom := new(messages.EOM)
+	eom.DBHeight = 0
+	s.Holding[primitives.Sha([]byte("eom")).Fixed()] = eom
+	dbsig := new(messages.DirectoryBlockSignature)
+	dbsig.DBHeight = 0
+	s.Holding[primitives.Sha([]byte("dbsig")).Fixed()] = dbsig
+
+	s.ReviewHolding()
+	s.ReviewHolding()
+	if len(s.Holding) != 2 || s.GetLastHoldingReview().Time != 0 {
+		t.Fatalf("Holding reviewed before the interval, %d left", len(s.Holding))
+	}
+
+	done := make(chan interfaces.HoldingReview)
+	go func() {
+		review, err := s.ReviewHoldingNow()
+		if err != nil {
+			t.Error(err)
+		}
+		done <- review
+	}()
+
+	var review interfaces.HoldingReview
+	for waiting := true; waiting; {
+		s.ReviewHolding()
+		select {
+		case review = <-done:
+			waiting = false
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	if !review.Forced || review.Skipped != "" {
+		t.Errorf("Expected a forced pass, got %+v", review)
+	}
+	if review.Reviewed != 2 || review.Stale != 2 || review.Remaining != 0 {
+		t.Errorf("Expected both stale messages to be deleted, got %+v", review)
+	}
+	if len(s.Holding) != 0 {
+		t.Errorf("%d messages left in holding", len(s.Holding))
+	}
+	if last := s.GetLastHoldingReview(); last != review {
+		t.Errorf("Last review %+v is not the forced one %+v", last, review)
+	}
+
+	// The messages of a pass waiting in XReview aren't overwritten
+	s.XReview = append(s.XReview, eom)
+	go func() {
+		review, _ := s.ReviewHoldingNow()
+		done <- review
+	}()
+	for waiting := true; waiting; {
+		s.ReviewHolding()
+		select {
+		case review = <-done:
+			waiting = false
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	if review.Skipped == "" || len(s.XReview) != 1 {
+		t.Errorf("Expected the pass to be skipped, got %+v", review)
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
+	. "github.com/FactomProject/factomd/state"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestHoldingDependencies(t *testing.T) {
+	d := NewHoldingDependencies()
+	a := primitives.Sha([]byte("a")).Fixed()
+	b := primitives.Sha([]byte("b")).Fixed()
+	c := primitives.Sha([]byte("c")).Fixed()
+	chain := primitives.Sha([]byte("chain")).Fixed()
+
+	d.Wait(a, HoldForAck, a, 1000)
+	d.Wait(b, HoldForChain, chain, 1000)
+	d.Wait(c, HoldForChain, chain, 2000)
+
+	if kind, ok := d.WaitingOn(a, 1000); !ok || kind != HoldForAck {
+		t.Errorf("Expected a to wait on its ack, got %q %v", kind, ok)
+	}
+	if _, ok := d.WaitingOn(a, 1000+HoldingDependencyTimeout); ok {
+		t.Error("Still waiting past the timeout")
+	}
+	if counts := d.Counts(); counts[HoldForAck] != 1 || counts[HoldForChain] != 2 || counts[HoldForCommit] != 0 {
+		t.Errorf("Unexpected counts %v", counts)
+	}
+
+	// Waiting on something else replaces the wait
+	d.Wait(a, HoldForCommit, b, 1500)
+	if released := d.Release(HoldForAck, a); len(released) != 0 {
+		t.Errorf("Released %v on a replaced wait", released)
+	}
+
+	released := d.Release(HoldForChain, chain)
+	if len(released) != 2 || released[0] == released[1] || (released[0] != b && released[0] != c) {
+		t.Fatalf("Expected b and c released, got %v", released)
+	}
+	if _, ok := d.WaitingOn(b, 1000); ok {
+		t.Error("b still waiting once released")
+	}
+
+	// Messages gone from holding are forgotten
+	d.Prune(map[[32]byte]interfaces.IMsg{})
+	if counts := d.Counts(); counts[HoldForCommit] != 0 {
+		t.Errorf("Not pruned %v", counts)
+	}
+	var none *HoldingDependencies
+	none.Forget(a)
+	if _, ok := none.WaitingOn(a, 0); ok {
+		t.Error("Nothing waits without dependencies")
+	}
+}
+
+func TestHoldingPriority(t *testing.T) {
+	order := []interfaces.IMsg{new(messages.EOM), new(messages.Ack), new(messages.CommitEntryMsg), new(messages.RevealEntryMsg)}
+	for i := 1; i < len(order); i++ {
+		if HoldingPriority(order[i-1]) >= HoldingPriority(order[i]) {
+			t.Errorf("%T should be reviewed before %T", order[i-1], order[i])
+		}
+	}
+}
+
+func TestFollowerHoldsForAck(t *testing.T) {
+	s := testHelper.CreateEmptyTestState()
+	m := new(messages.RevealEntryMsg)
+	m.Entry = testHelper.CreateTestEntry(1)
+	m.Timestamp = s.GetTimestamp()
+
+	s.FollowerExecuteRevealEntry(m)
+	h := m.GetMsgHash().Fixed()
+	if s.Holding[h] == nil {
+		t.Fatal("Reveal not held")
+	}
+	if kind, ok := s.HoldingDeps.WaitingOn(h, s.GetTimestamp().GetTimeMilli()); !ok || kind != HoldForAck {
+		t.Errorf("Expected the reveal to wait on its ack, got %q %v", kind, ok)
+	}
+}
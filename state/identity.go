@@ -285,6 +285,14 @@ func LoadIdentityByEntry(ent interfaces.IEBEntry, st *State, height uint32, init
 						flog.Warningf("RegisterAnchorKey - %s", err.Error())
 					}
 				}
+			} else if string(ent.ExternalIDs()[1]) == "New Ethereum Key" {
+				// Laid out as a New Bitcoin Key, with the 20 byte account address as the key
+				if len(ent.ExternalIDs()) == 9 {
+					err := RegisterAnchorSigningKey(ent, initial, height, st, "ETH")
+					if err != nil {
+						flog.Warningf("RegisterAnchorKey - %s", err.Error())
+					}
+				}
 			} else if string(ent.ExternalIDs()[1]) == "New Matryoshka Hash" {
 				if len(ent.ExternalIDs()) == 7 {
 					err := UpdateMatryoshkaHash(ent, initial, height, st)
@@ -659,7 +667,7 @@ func RegisterAnchorSigningKey(entry interfaces.IEBEntry, initial bool, height ui
 
 	IdentityIndex := st.isIdentityChain(chainID)
 	if IdentityIndex == -1 {
-		return errors.New("Identity Error: This cannot happen. New Bitcoin Key to nonexistent identity")
+		return errors.New("Identity Error: This cannot happen. New " + BlockChain + " anchor key to nonexistent identity")
 	}
 
 	if !st.Identities[IdentityIndex].ManagementChainID.IsSameAs(subChainID) {
@@ -720,13 +728,20 @@ func RegisterAnchorSigningKey(entry interfaces.IEBEntry, initial bool, height ui
 			} else {
 				st.Identities[IdentityIndex].AnchorKeys = newAsk
 			}
-			// Add to admin block
+			// Add to admin block; an ETH key only once they go there
+			change := constants.TYPE_ADD_BTC_ANCHOR_KEY
+			if BlockChain == "ETH" {
+				change = constants.TYPE_ADD_ETH_ANCHOR_KEY
+				if !st.ethAnchorKeysActive(st.LLeaderHeight) {
+					return nil
+				}
+			}
 			status := st.Identities[IdentityIndex].Status
 			if !initial && statusIsFedOrAudit(status) && st.GetLeaderVM() == st.ComputeVMIndex(entry.GetChainID().Bytes()) {
 				copy(key[:20], extIDs[5][:20])
 				extIDs[5] = append(extIDs[5], []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}...)
 				key := primitives.NewHash(extIDs[5])
-				msg := messages.NewChangeServerKeyMsg(st, chainID, constants.TYPE_ADD_BTC_ANCHOR_KEY, extIDs[3][0], extIDs[4][0], key)
+				msg := messages.NewChangeServerKeyMsg(st, chainID, change, extIDs[3][0], extIDs[4][0], key)
 				err := msg.(*messages.ChangeServerKeyMsg).Sign(st.serverPrivKey)
 				if err != nil {
 					return errors.New("New Block Signing key for identity [" + chainID.String()[:10] + "] Error: cannot sign msg")
@@ -748,6 +763,9 @@ func ProcessIdentityToAdminBlock(st *State, chainID interfaces.IHash, servertype
 	var btcKey [20]byte
 	var btcKeyLevel byte
 	var btcKeyType byte
+	var ethKey [20]byte
+	var ethKeyLevel byte
+	hasEthKey := false
 
 	err := st.AddIdentityFromChainID(chainID)
 	if err != nil {
@@ -782,6 +800,12 @@ func ProcessIdentityToAdminBlock(st *State, chainID interfaces.IHash, servertype
 				if strings.Compare(aKey.BlockChain, "BTC") == 0 {
 					copy(btcKey[:20], aKey.SigningKey[:20])
 				}
+				// Ethereum keys are optional
+				if strings.Compare(aKey.BlockChain, "ETH") == 0 {
+					copy(ethKey[:20], aKey.SigningKey[:20])
+					ethKeyLevel = aKey.KeyLevel
+					hasEthKey = true
+				}
 			}
 		}
 
@@ -816,6 +840,9 @@ func ProcessIdentityToAdminBlock(st *State, chainID interfaces.IHash, servertype
 	st.LeaderPL.AdminBlock.AddFederatedServerSigningKey(chainID, blockSigningKey)
 	st.LeaderPL.AdminBlock.AddMatryoshkaHash(chainID, matryoshkaHash)
 	st.LeaderPL.AdminBlock.AddFederatedServerBitcoinAnchorKey(chainID, btcKeyLevel, btcKeyType, btcKey)
+	if hasEthKey && st.ethAnchorKeysActive(st.LLeaderHeight) {
+		st.LeaderPL.AdminBlock.AddFederatedServerEthereumAnchorKey(chainID, ethKeyLevel, ethKey)
+	}
 	return true
 }
 
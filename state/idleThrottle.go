@@ -0,0 +1,100 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+)
+
+// A follower with nothing to do used to poll the inMsgQueue every 10 milliseconds, and
+// run Process() and ReviewHolding between the polls.  Idle followers now wait on the
+// queues themselves, so a message wakes them as it arrives, and the wait doubles while
+// they stay idle.  Near the end of a minute the EOMs are due, so the wait drops back to
+// the shortest.  Leaders keep polling, as they drive the minutes.
+
+// Shortest and longest waits of an idle follower
+const (
+	idleSleepMin = 10 * time.Millisecond
+	idleSleepMax = 500 * time.Millisecond
+)
+
+// How long before the expected end of a minute idle followers stop backing off
+const idleMinuteEndWindow = time.Second
+
+// NextIdleSleep returns how long to wait after waiting last, with the time left until the
+// end of the minute
+func NextIdleSleep(last time.Duration, untilMinuteEnd time.Duration) time.Duration {
+	if untilMinuteEnd < idleMinuteEndWindow {
+		return idleSleepMin
+	}
+	next := 2 * last
+	if next < idleSleepMin {
+		next = idleSleepMin
+	}
+	if next > idleSleepMax {
+		next = idleSleepMax
+	}
+	// Never sleep through the start of the window
+	if left := untilMinuteEnd - idleMinuteEndWindow; next > left && left >= idleSleepMin {
+		next = left
+	}
+	return next
+}
+
+// Idle returns true if a follower has nothing to process: no messages queued, held or
+// waiting for review, and no directory block states waiting to be saved
+func (s *State) Idle() bool {
+	if s.Leader || s.inMsgQueue.Length() > 0 || len(s.ackQueue) > 0 || len(s.msgQueue) > 0 {
+		return false
+	}
+	if len(s.Holding) > 0 || len(s.XReview) > 0 || len(s.minuteZeroHeld) > 0 {
+		return false
+	}
+	ix := int(s.GetHighestSavedBlk()) - s.DBStatesReceivedBase + 1
+	return ix < 0 || ix >= len(s.DBStatesReceived) || s.DBStatesReceived[ix] == nil
+}
+
+// untilMinuteEnd returns the time left until the current minute is expected to end
+func (s *State) untilMinuteEnd() time.Duration {
+	if s.CurrentMinuteStartTime == 0 {
+		return 0 // Syncing, so no idea
+	}
+	minute := time.Duration(s.DirectoryBlockInSeconds) * time.Second / 10
+	return minute - time.Since(time.Unix(0, s.CurrentMinuteStartTime))
+}
+
+// idleWait waits for the next message of an idle follower, up to the next idle sleep.
+// Returns the message that woke it, if any; a tick is handed to the timer.
+func (s *State) idleWait(timeStruct *Timer) interfaces.IMsg {
+	s.idleSleep = NextIdleSleep(s.idleSleep, s.untilMinuteEnd())
+	start := time.Now()
+	wait := time.NewTimer(s.idleSleep)
+	defer wait.Stop()
+
+	var msg interfaces.IMsg
+	wake := "timeout"
+	select {
+	case msg = <-s.inMsgQueue:
+		measureMessage(CurrentMessageQueueInMsgGeneralVec, msg, false)
+		wake = "message"
+	case msg = <-s.TimerMsgQueue():
+		wake = "message"
+	case min := <-s.tickerQueue:
+		timeStruct.timer(s, min)
+		wake = "tick"
+	case <-wait.C:
+		// A message waiting now arrived while we slept, and so was held up by the sleep
+		if n := s.inMsgQueue.Length(); n > 0 {
+			IdleMessagesDelayed.Add(float64(n))
+		}
+	}
+	IdleSleepVec.WithLabelValues(wake).Observe(time.Since(start).Seconds())
+	if wake != "timeout" {
+		s.idleSleep = 0
+	}
+	return msg
+}
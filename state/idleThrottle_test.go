@@ -0,0 +1,65 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
+	. "github.com/FactomProject/factomd/state"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestNextIdleSleep(t *testing.T) {
+	// Doubles from the shortest wait while idle, up to the longest
+	var sleep time.Duration
+	var waits []time.Duration
+	for i := 0; i < 8; i++ {
+		sleep = NextIdleSleep(sleep, time.Minute)
+		waits = append(waits, sleep)
+	}
+	if waits[0] != 10*time.Millisecond || waits[1] != 20*time.Millisecond {
+		t.Errorf("Waits start %v", waits[:2])
+	}
+	if waits[7] != 500*time.Millisecond {
+		t.Errorf("Waits end at %v", waits[7])
+	}
+
+	// Never into the end of the minute, and the shortest wait once there
+	if d := NextIdleSleep(400*time.Millisecond, 1200*time.Millisecond); d != 200*time.Millisecond {
+		t.Errorf("Waited %v into the end of the minute", d)
+	}
+	if d := NextIdleSleep(400*time.Millisecond, 500*time.Millisecond); d != 10*time.Millisecond {
+		t.Errorf("Waited %v at the end of the minute", d)
+	}
+}
+
+func TestIdle(t *testing.T) {
+	s := testHelper.CreateEmptyTestState()
+	s.Leader = false
+	if !s.Idle() {
+		t.Error("A follower with nothing to do isn't idle")
+	}
+
+	s.Holding[primitives.Sha([]byte("eom")).Fixed()] = new(messages.EOM)
+	if s.Idle() {
+		t.Error("A follower holding a message is idle")
+	}
+	s.Holding = make(map[[32]byte]interfaces.IMsg)
+
+	s.InMsgQueue().Enqueue(new(messages.EOM))
+	if s.Idle() {
+		t.Error("A follower with a message queued is idle")
+	}
+	s.InMsgQueue().Dequeue()
+
+	s.Leader = true
+	if s.Idle() {
+		t.Error("A leader is idle")
+	}
+}
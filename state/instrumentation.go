@@ -133,6 +133,291 @@ var (
 		Name: "factomd_state_holding_queue_total_outputs",
 		Help: "Tally of total messages drained out of Holding (useful for rating)",
 	})
+	HoldingResendsVec = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "factomd_state_holding_resends_vec",
+		Help: "Tally of messages resent from Holding, by message type",
+	}, []string{"message"})
+	HoldingReleasesVec = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "factomd_state_holding_releases_vec",
+		Help: "Tally of messages released from Holding as what they waited on arrived, by dependency (ack, commit, chain)",
+	}, []string{"dependency"})
+	HoldingWaitingVec = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "factomd_state_holding_waiting_vec",
+		Help: "Number of messages in Holding waiting on a dependency, by dependency (ack, commit, chain)",
+	}, []string{"dependency"})
+	NewChainsPerBlock = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "factomd_state_new_chains_per_block",
+		Help: "Number of chains created in the last block built",
+	})
+	NewEBlocksPerBlock = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "factomd_state_new_eblocks_per_block",
+		Help: "Number of entry blocks in the last block built",
+	})
+	TotalNewChains = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "factomd_state_new_chains_total",
+		Help: "Tally of chains created",
+	})
+	TotalNewChainsDeferred = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "factomd_state_new_chains_deferred_total",
+		Help: "Tally of chain creations this leader held for a later block, as MaxNewChainsPerBlock was reached",
+	})
+	TotalEntriesDeferred = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "factomd_state_entries_deferred_total",
+		Help: "Tally of reveals this leader held for a later block, as MaxEntriesPerBlock was reached",
+	})
+	TotalCommitsRateLimited = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "factomd_state_commits_rate_limited_total",
+		Help: "Tally of commits this leader held, as their entry credit address reached CommitRateLimit",
+	})
+	CommitRateAddresses = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "factomd_state_commit_rate_addresses",
+		Help: "Entry credit addresses that committed lately, tracked against CommitRateLimit",
+	})
+	MinuteZeroHeld = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "factomd_state_minute_zero_held",
+		Help: "Submissions this leader set aside while the DBSigs of the block sync, with MinuteZeroAdmission prioritize",
+	})
+	TotalMinuteZeroReleased = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "factomd_state_minute_zero_released_total",
+		Help: "Tally of submissions set aside in minute 0 and reviewed first once the DBSigs were done",
+	})
+	LeaderMinuteLag = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "factomd_state_leader_minute_lag_seconds",
+		Help: "How late the last minute ended, past its length",
+	})
+	LeaderMissingAsks = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "factomd_state_leader_missing_asks",
+		Help: "Asks of our peers for missing messages in the last minute",
+	})
+	LeaderDiskLatency = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "factomd_state_leader_disk_latency_seconds",
+		Help: "The slowest database write in the last minute",
+	})
+	FactoidSignaturesSkipped = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "factomd_state_factoid_signatures_skipped_total",
+		Help: "Tally of factoid blocks loaded without checking their signatures, with SkipAnchoredSignatures",
+	})
+	TotalStepDowns = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "factomd_state_step_downs_total",
+		Help: "Tally of times this leader volunteered to step down, with AutoStepDown",
+	})
+	TotalElections = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "factomd_state_elections_total",
+		Help: "Tally of elections opened to replace a faulted leader",
+	})
+	TotalElectionRounds = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "factomd_state_election_rounds_total",
+		Help: "Tally of election rounds that went undecided, and moved on to the next audit server",
+	})
+	TotalElectionsDecided = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "factomd_state_elections_decided_total",
+		Help: "Tally of elections a majority of the leaders decided",
+	})
+	RejectionsVec = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "factomd_state_rejections_total",
+		Help: "Tally of blocks and messages from peers turned away, by kind and reason",
+	}, []string{"kind", "reason"})
+	SendOutsVec = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "factomd_state_sendouts_total",
+		Help: "Tally of calls to SendOut, by message and outcome (broadcast, repeat, suppressed)",
+	}, []string{"message", "outcome"})
+	AskAttemptsVec = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "factomd_state_ask_attempts_total",
+		Help: "Tally of asks sent to peers for what we are missing, by ask (dbstate, missingmsg, entry)",
+	}, []string{"ask"})
+	AskLatencyVec = prometheus.NewSummaryVec(prometheus.SummaryOpts{
+		Name: "factomd_state_ask_latency_seconds",
+		Help: "Time from the first ask for something missing to having it, by ask",
+	}, []string{"ask"})
+	JobDuration = prometheus.NewSummaryVec(prometheus.SummaryOpts{
+		Name: "factomd_state_job_duration_seconds",
+		Help: "Time taken by each run of a maintenance job",
+	}, []string{"job"})
+	JobRuns = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "factomd_state_job_runs_total",
+		Help: "Tally of maintenance job runs, by job and result",
+	}, []string{"job", "result"})
+	SlowRoundsVec = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "factomd_state_slow_rounds_vec",
+		Help: "Tally of minutes (EOM) and block starts (DBSig) that ran past their window, by the server heard from last",
+	}, []string{"kind", "server"})
+	TimingAnomaliesVec = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "factomd_state_timing_anomalies_vec",
+		Help: "Tally of minutes that ended too soon and blocks that took too long, by kind",
+	}, []string{"kind"})
+	WriteBatchFlushes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "factomd_state_write_batch_flushes_vec",
+		Help: "Tally of batches of entries and entry blocks written, by why they were written (full, age, shutdown)",
+	}, []string{"reason"})
+	WriteBatchSize = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name: "factomd_state_write_batch_size",
+		Help: "Entries and entry blocks in each batch written",
+	})
+	WriteBatchDuration = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name: "factomd_state_write_batch_ns",
+		Help: "Time it takes to write a batch of entries and entry blocks",
+	})
+	AlertsVec = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "factomd_state_alerts_vec",
+		Help: "Tally of operator defined alerts that fired and resolved, by alert and state",
+	}, []string{"alert", "state"})
+	ReceiptNoticesVec = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "factomd_state_receipt_notices_vec",
+		Help: "Tally of receipt subscriptions ended, by result (webhook, websocket, failed, expired)",
+	}, []string{"result"})
+	CoinbaseAudited = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "factomd_state_coinbase_audited_total",
+		Help: "Tally of factoid block coinbases checked against the expected payouts",
+	})
+	CoinbaseDiscrepancies = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "factomd_state_coinbase_discrepancies_total",
+		Help: "Tally of factoid block coinbases that didn't pay what was expected",
+	})
+	PeerQuorumAgreeing = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "factomd_state_peer_quorum_agreeing",
+		Help: "Peers agreeing with our height while waiting on the startup peer threshold",
+	})
+	PrunedHeight = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "factomd_state_pruned_height",
+		Help: "Directory block height below which entry blocks have been pruned",
+	})
+	FastBootSaveDuration = prometheus.NewSummaryVec(prometheus.SummaryOpts{
+		Name: "factomd_state_fastboot_save_seconds",
+		Help: "Time taken by each phase of a fastboot save: snapshot (in the consensus loop), marshal and write",
+	}, []string{"phase"})
+	SigPreVerifications = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "factomd_state_sig_preverifications_total",
+		Help: "Tally of messages from peers through the signature checking pool, by result: valid, invalid or skipped",
+	}, []string{"result"})
+	SigPreVerifyTime = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name: "factomd_state_sig_preverify_seconds",
+		Help: "Time taken to check the signature of a message in the signature checking pool",
+	})
+	InjectedMessages = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "factomd_state_injected_messages_total",
+		Help: "Tally of messages queued through the debug API as though a peer sent them",
+	})
+	ConsensusRecordedMessages = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "factomd_state_consensus_recorded_messages_total",
+		Help: "Tally of messages written to the consensus record",
+	})
+	ConsensusRecordErrors = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "factomd_state_consensus_record_errors_total",
+		Help: "Tally of messages that could not be written to the consensus record",
+	})
+	APISubmissions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "factomd_state_api_submissions_total",
+		Help: "Tally of messages submitted through the API, by status: accepted, queued or rejected",
+	}, []string{"status"})
+	EthereumAnchorsVec = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "factomd_state_ethereum_anchors_total",
+		Help: "Tally of directory blocks anchored into Ethereum, by result: submitted, confirmed or error",
+	}, []string{"result"})
+	MemoryBytesVec = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "factomd_state_memory_bytes",
+		Help: "Approximate bytes held by the structures that grow with traffic, by subsystem",
+	}, []string{"subsystem"})
+	HoldingReviewVec = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "factomd_state_holding_review_messages_total",
+		Help: "Tally of what passes over holding did with the messages, by outcome",
+	}, []string{"outcome"})
+	ClusterCacheFetches = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "factomd_state_cluster_cache_fetches_total",
+		Help: "Tally of directory block states and entries fetched from the cluster cache, by kind and result: hit, miss or error",
+	}, []string{"kind", "result"})
+	MempoolEvictionsVec = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "factomd_state_mempool_evictions_total",
+		Help: "Tally of commits evicted from the mempool, by reason: revealed, replay, age or capacity",
+	}, []string{"reason"})
+	LeaderGraceVec = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "factomd_state_leader_grace_total",
+		Help: "Tally of first EOMs of newly promoted leaders: held for the process list, issued once synced, or issued as the grace period expired",
+	}, []string{"outcome"})
+	HoldingReconciledVec = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "factomd_state_holding_reconciled_total",
+		Help: "Tally of messages taken out of holding as a completed block already held them, by kind (ack, commit, reveal, factoid)",
+	}, []string{"kind"})
+	ThrottlesVec = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "factomd_state_throttles_total",
+		Help: "Tally of throttles, sent to peers whose requests we turned away or received from peers that turned ours away",
+	}, []string{"direction"})
+	MissingResponsesShed = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "factomd_state_missing_responses_shed_total",
+		Help: "Tally of missing message responses dropped while overloaded",
+	})
+	BackpressureLevel = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "factomd_state_load_level",
+		Help: "How loaded the node is by its inbound queue: 0 normal, 1 busy, 2 overloaded",
+	})
+	ExecuteMsgTimeVec = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "factomd_state_execute_msg_seconds",
+		Help:    "Time executeMsg takes, by message type and VM index",
+		Buckets: prometheus.ExponentialBuckets(0.00001, 4, 10),
+	}, []string{"msgtype", "vm"})
+	HoldingDwellVec = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "factomd_state_holding_dwell_seconds",
+		Help:    "Time messages wait in holding before going into a process list, by message type and VM index",
+		Buckets: prometheus.ExponentialBuckets(0.01, 3, 11),
+	}, []string{"msgtype", "vm"})
+	AckToProcessListVec = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "factomd_state_ack_to_process_list_seconds",
+		Help:    "Time from a leader acking a message to it being processed in the process list, by message type and VM index",
+		Buckets: prometheus.ExponentialBuckets(0.01, 3, 11),
+	}, []string{"msgtype", "vm"})
+	CommitToBlockVec = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "factomd_state_commit_to_block_seconds",
+		Help:    "Time from the timestamp of a commit to the block it went in being complete, by message type and VM index",
+		Buckets: prometheus.ExponentialBuckets(1, 2, 12),
+	}, []string{"msgtype", "vm"})
+	AuthorityChangesRejectedVec = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "factomd_state_authority_changes_rejected_total",
+		Help: "Tally of servers not added or removed, as the block had taken as many as the network allows (rate) or the identity was younger than it allows (age)",
+	}, []string{"reason"})
+	CheckpointSetsVec = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "factomd_state_checkpoint_sets_total",
+		Help: "Tally of checkpoint sets from the checkpoint service, by whether they were merged, rejected or could not be fetched",
+	}, []string{"result"})
+	ReplicaDBStateAsks = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "factomd_state_replica_dbstate_asks_total",
+		Help: "Tally of requests a read replica made for the next directory block states",
+	})
+	SyncRequestsVec = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "factomd_state_sync_requests_total",
+		Help: "Tally of requests for streams of blocks, by whether they were sent, served or refused, or a missing directory block state was asked for instead",
+	}, []string{"result"})
+	SyncChunksVec = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "factomd_state_sync_chunks_total",
+		Help: "Tally of chunks of streamed blocks, by whether they were sent, applied, refused or ignored, and of the streams that stalled",
+	}, []string{"result"})
+	ProofPackHeight = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "factomd_state_proof_pack_height",
+		Help: "Height of the last header of the proof pack checked at boot, 0 for none",
+	})
+	IdleSleepVec = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "factomd_state_idle_sleep_seconds",
+		Help:    "Time idle followers wait on their queues, by what woke them: message, tick or timeout",
+		Buckets: prometheus.ExponentialBuckets(0.001, 2, 11),
+	}, []string{"wake"})
+	IdleMessagesDelayed = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "factomd_state_idle_messages_delayed_total",
+		Help: "Tally of messages found waiting when an idle follower's wait timed out, rather than waking it",
+	})
+	BatchAcksSent = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "factomd_state_batch_acks_sent_total",
+		Help: "Tally of batch acks a leader sent out in place of the acks they carry",
+	})
+	AcksBatched = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "factomd_state_acks_batched_total",
+		Help: "Tally of acks a leader sent out in batch acks rather than alone",
+	})
+	BatchAcksReceived = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "factomd_state_batch_acks_received_total",
+		Help: "Tally of batch acks unpacked by a follower",
+	})
+	FastBootSavesSkipped = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "factomd_state_fastboot_saves_skipped_total",
+		Help: "Tally of fastboot saves skipped because the last one was still running",
+	})
 	TotalHoldingQueueRecycles = prometheus.NewCounter(prometheus.CounterOpts{
 		Name: "factomd_state_holding_queue_total_recycles",
 		Help: "Tally of total messages recycled thru Holding (useful for rating)",
@@ -180,6 +465,20 @@ var (
 		Help: "Tally of total messages drained out of Acks (useful for rating)",
 	})
 
+	// DataResponse worker
+	DataResponseQueueInputs = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "factomd_state_data_response_queue_total_inputs",
+		Help: "Tally of entry and entry block responses queued for the DataResponse worker",
+	})
+	DataResponseQueueOutputs = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "factomd_state_data_response_queue_total_outputs",
+		Help: "Tally of entry and entry block responses handled by the DataResponse worker",
+	})
+	DataResponseQueueDrops = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "factomd_state_data_response_queue_total_drops",
+		Help: "Tally of entry and entry block responses dropped because the worker queue, or the entry writer's, was full",
+	})
+
 	// Commits map
 	TotalCommitsInputs = prometheus.NewCounter(prometheus.CounterOpts{
 		Name: "factomd_state_commits_total_inputs",
@@ -313,6 +612,76 @@ func RegisterPrometheus() {
 	prometheus.MustRegister(TotalHoldingQueueInputs)
 	prometheus.MustRegister(TotalHoldingQueueOutputs)
 	prometheus.MustRegister(TotalHoldingQueueRecycles)
+	prometheus.MustRegister(HoldingResendsVec)
+	prometheus.MustRegister(HoldingReleasesVec)
+	prometheus.MustRegister(HoldingWaitingVec)
+	prometheus.MustRegister(SlowRoundsVec)
+	prometheus.MustRegister(TimingAnomaliesVec)
+	prometheus.MustRegister(WriteBatchFlushes)
+	prometheus.MustRegister(WriteBatchSize)
+	prometheus.MustRegister(WriteBatchDuration)
+	prometheus.MustRegister(AlertsVec)
+	prometheus.MustRegister(ReceiptNoticesVec)
+	prometheus.MustRegister(CoinbaseAudited)
+	prometheus.MustRegister(CoinbaseDiscrepancies)
+	prometheus.MustRegister(PeerQuorumAgreeing)
+	prometheus.MustRegister(PrunedHeight)
+	prometheus.MustRegister(FastBootSaveDuration)
+	prometheus.MustRegister(FastBootSavesSkipped)
+	prometheus.MustRegister(SigPreVerifications)
+	prometheus.MustRegister(SigPreVerifyTime)
+	prometheus.MustRegister(InjectedMessages)
+	prometheus.MustRegister(ConsensusRecordedMessages)
+	prometheus.MustRegister(ConsensusRecordErrors)
+	prometheus.MustRegister(APISubmissions)
+	prometheus.MustRegister(EthereumAnchorsVec)
+	prometheus.MustRegister(MemoryBytesVec)
+	prometheus.MustRegister(HoldingReviewVec)
+	prometheus.MustRegister(ClusterCacheFetches)
+	prometheus.MustRegister(MempoolEvictionsVec)
+	prometheus.MustRegister(LeaderGraceVec)
+	prometheus.MustRegister(HoldingReconciledVec)
+	prometheus.MustRegister(ThrottlesVec)
+	prometheus.MustRegister(MissingResponsesShed)
+	prometheus.MustRegister(BackpressureLevel)
+	prometheus.MustRegister(ExecuteMsgTimeVec)
+	prometheus.MustRegister(HoldingDwellVec)
+	prometheus.MustRegister(AckToProcessListVec)
+	prometheus.MustRegister(CommitToBlockVec)
+	prometheus.MustRegister(AuthorityChangesRejectedVec)
+	prometheus.MustRegister(CheckpointSetsVec)
+	prometheus.MustRegister(ReplicaDBStateAsks)
+	prometheus.MustRegister(SyncRequestsVec)
+	prometheus.MustRegister(SyncChunksVec)
+	prometheus.MustRegister(ProofPackHeight)
+	prometheus.MustRegister(IdleSleepVec)
+	prometheus.MustRegister(IdleMessagesDelayed)
+	prometheus.MustRegister(BatchAcksSent)
+	prometheus.MustRegister(AcksBatched)
+	prometheus.MustRegister(BatchAcksReceived)
+	prometheus.MustRegister(JobDuration)
+	prometheus.MustRegister(JobRuns)
+	prometheus.MustRegister(NewChainsPerBlock)
+	prometheus.MustRegister(NewEBlocksPerBlock)
+	prometheus.MustRegister(TotalNewChains)
+	prometheus.MustRegister(TotalNewChainsDeferred)
+	prometheus.MustRegister(TotalEntriesDeferred)
+	prometheus.MustRegister(TotalCommitsRateLimited)
+	prometheus.MustRegister(CommitRateAddresses)
+	prometheus.MustRegister(MinuteZeroHeld)
+	prometheus.MustRegister(TotalMinuteZeroReleased)
+	prometheus.MustRegister(LeaderMinuteLag)
+	prometheus.MustRegister(LeaderMissingAsks)
+	prometheus.MustRegister(LeaderDiskLatency)
+	prometheus.MustRegister(TotalStepDowns)
+	prometheus.MustRegister(FactoidSignaturesSkipped)
+	prometheus.MustRegister(TotalElections)
+	prometheus.MustRegister(TotalElectionRounds)
+	prometheus.MustRegister(TotalElectionsDecided)
+	prometheus.MustRegister(RejectionsVec)
+	prometheus.MustRegister(SendOutsVec)
+	prometheus.MustRegister(AskAttemptsVec)
+	prometheus.MustRegister(AskLatencyVec)
 	prometheus.MustRegister(HoldingQueueDBSigInputs)
 	prometheus.MustRegister(HoldingQueueDBSigOutputs)
 	prometheus.MustRegister(HoldingQueueCommitEntryInputs)
@@ -326,6 +695,11 @@ func RegisterPrometheus() {
 	prometheus.MustRegister(TotalAcksInputs)
 	prometheus.MustRegister(TotalAcksOutputs)
 
+	// DataResponse worker
+	prometheus.MustRegister(DataResponseQueueInputs)
+	prometheus.MustRegister(DataResponseQueueOutputs)
+	prometheus.MustRegister(DataResponseQueueDrops)
+
 	// Execution
 	prometheus.MustRegister(LeaderExecutions)
 	prometheus.MustRegister(FollowerExecutions)
@@ -0,0 +1,273 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	log "github.com/sirupsen/logrus"
+)
+
+var jobsLogger = packageLogger.WithFields(log.Fields{"subpack": "jobs"})
+
+// job is a piece of periodic maintenance work
+type job struct {
+	name     string
+	interval time.Duration // 0 runs the job only when triggered
+	jitter   time.Duration
+	run      func() error
+
+	disabled     bool
+	triggered    bool
+	next         time.Time
+	runs         int
+	failures     int
+	lastRun      time.Time
+	lastDuration time.Duration
+	lastError    error
+}
+
+// JobScheduler runs the node's periodic maintenance jobs from the consensus loop, as
+// the work was done before, and keeps their timings so they can be watched and
+// controlled from the admin API.
+type JobScheduler struct {
+	mutex sync.Mutex
+	jobs  map[string]*job
+}
+
+func NewJobScheduler() *JobScheduler {
+	js := new(JobScheduler)
+	js.jobs = make(map[string]*job)
+	return js
+}
+
+// Add registers a job, replacing any job of the same name.  Its first run is an
+// interval (plus jitter) from now.
+func (js *JobScheduler) Add(name string, interval, jitter time.Duration, run func() error) {
+	if js == nil {
+		return
+	}
+	j := &job{name: name, interval: interval, jitter: jitter, run: run}
+	js.mutex.Lock()
+	defer js.mutex.Unlock()
+	js.schedule(j, time.Now())
+	js.jobs[name] = j
+}
+
+// schedule sets when a job next runs.  Called holding the mutex.
+func (js *JobScheduler) schedule(j *job, now time.Time) {
+	if j.interval <= 0 {
+		j.next = time.Time{}
+		return
+	}
+	j.next = now.Add(j.interval)
+	if j.jitter > 0 {
+		j.next = j.next.Add(time.Duration(rand.Int63n(int64(j.jitter))))
+	}
+}
+
+// RunDue runs the jobs that are due or have been triggered.  Called from the
+// consensus loop, so jobs can work on the state.
+func (js *JobScheduler) RunDue() {
+	if js == nil {
+		return
+	}
+	now := time.Now()
+	var due []*job
+	js.mutex.Lock()
+	for _, j := range js.jobs {
+		if j.triggered || (!j.disabled && !j.next.IsZero() && !now.Before(j.next)) {
+			j.triggered = false
+			js.schedule(j, now)
+			due = append(due, j)
+		}
+	}
+	js.mutex.Unlock()
+
+	for _, j := range due {
+		js.execute(j)
+	}
+}
+
+// RunNow runs a job straight away in the caller, unless it is disabled.  For work that
+// has to happen at a point in the consensus loop, rather than on a timer.
+func (js *JobScheduler) RunNow(name string) error {
+	if js == nil {
+		return nil
+	}
+	js.mutex.Lock()
+	j := js.jobs[name]
+	disabled := j != nil && j.disabled
+	js.mutex.Unlock()
+	if j == nil {
+		return fmt.Errorf("No job named %s", name)
+	}
+	if disabled {
+		return nil
+	}
+	return js.execute(j)
+}
+
+func (js *JobScheduler) execute(j *job) error {
+	start := time.Now()
+	err := j.run()
+	took := time.Since(start)
+
+	JobDuration.WithLabelValues(j.name).Observe(took.Seconds())
+	result := "ok"
+	if err != nil {
+		result = "error"
+		jobsLogger.Errorf("Job %s failed: %v", j.name, err)
+	}
+	JobRuns.WithLabelValues(j.name, result).Inc()
+
+	js.mutex.Lock()
+	defer js.mutex.Unlock()
+	j.runs++
+	if err != nil {
+		j.failures++
+	}
+	j.lastRun = start
+	j.lastDuration = took
+	j.lastError = err
+	return err
+}
+
+// Trigger has a job run the next time the consensus loop looks, even if disabled
+func (js *JobScheduler) Trigger(name string) error {
+	if js == nil {
+		return fmt.Errorf("No job scheduler")
+	}
+	js.mutex.Lock()
+	defer js.mutex.Unlock()
+	j := js.jobs[name]
+	if j == nil {
+		return fmt.Errorf("No job named %s", name)
+	}
+	j.triggered = true
+	return nil
+}
+
+// SetEnabled stops a job from running on its schedule, or starts it again
+func (js *JobScheduler) SetEnabled(name string, enabled bool) error {
+	if js == nil {
+		return fmt.Errorf("No job scheduler")
+	}
+	js.mutex.Lock()
+	defer js.mutex.Unlock()
+	j := js.jobs[name]
+	if j == nil {
+		return fmt.Errorf("No job named %s", name)
+	}
+	if j.disabled && enabled {
+		js.schedule(j, time.Now())
+	}
+	j.disabled = !enabled
+	return nil
+}
+
+// Status returns the jobs, sorted by name
+func (js *JobScheduler) Status() []interfaces.JobStatus {
+	if js == nil {
+		return nil
+	}
+	js.mutex.Lock()
+	defer js.mutex.Unlock()
+	var all []interfaces.JobStatus
+	for _, j := range js.jobs {
+		st := interfaces.JobStatus{
+			Name:         j.name,
+			Interval:     j.interval.Seconds(),
+			Jitter:       j.jitter.Seconds(),
+			Enabled:      !j.disabled,
+			Runs:         j.runs,
+			Failures:     j.failures,
+			LastDuration: j.lastDuration.Seconds(),
+		}
+		if !j.lastRun.IsZero() {
+			st.LastRun = j.lastRun.Unix()
+		}
+		if j.lastError != nil {
+			st.LastError = j.lastError.Error()
+		}
+		if !j.next.IsZero() && !j.disabled {
+			st.NextRun = j.next.Unix()
+		}
+		all = append(all, st)
+	}
+	sort.Sort(jobsByName(all))
+	return all
+}
+
+type jobsByName []interfaces.JobStatus
+
+func (j jobsByName) Len() int           { return len(j) }
+func (j jobsByName) Less(a, b int) bool { return j[a].Name < j[b].Name }
+func (j jobsByName) Swap(a, b int)      { j[a], j[b] = j[b], j[a] }
+
+// GetJobs reports the periodic maintenance jobs
+func (s *State) GetJobs() []interfaces.JobStatus {
+	return s.Jobs.Status()
+}
+
+// TriggerJob runs a maintenance job at the next chance
+func (s *State) TriggerJob(name string) error {
+	return s.Jobs.Trigger(name)
+}
+
+// SetJobEnabled turns the schedule of a maintenance job off or on
+func (s *State) SetJobEnabled(name string, enabled bool) error {
+	return s.Jobs.SetEnabled(name, enabled)
+}
+
+// addMaintenanceJobs registers the state's own periodic work
+func (s *State) addMaintenanceJobs() {
+	s.Jobs.Add("db-trim", time.Minute, 10*time.Second, func() error {
+		s.DB.Trim()
+		return nil
+	})
+	s.Jobs.Add("commit-expiration", time.Minute, 5*time.Second, func() error {
+		s.Commits.RemoveExpired(s)
+		return nil
+	})
+	s.Jobs.Add("memory-accounting", 15*time.Second, 5*time.Second, func() error {
+		s.updateMemoryGauges()
+		return nil
+	})
+	s.Jobs.Add("load-level", 5*time.Second, 0, func() error {
+		s.updateLoadLevel()
+		return nil
+	})
+	s.Jobs.Add("rejections-save", time.Minute, 10*time.Second, s.saveRejections)
+	s.Jobs.Add("capacity-sample", capacitySampleInterval, 5*time.Second, func() error {
+		s.sampleCapacity()
+		return nil
+	})
+	s.Jobs.Add("sync-stream", syncFeedInterval, 0, s.feedSyncStreams)
+	s.Jobs.Add("sendout-expire", time.Minute, 5*time.Second, func() error {
+		s.SendOuts.Expire(time.Now())
+		return nil
+	})
+	// Merges fetched checkpoints, and fetches them as often as configured
+	if s.Checkpoints != nil {
+		s.Jobs.Add("checkpoint-subscription", 10*time.Second, 0, s.updateCheckpoints)
+	}
+	// A read replica hears of new blocks only by asking
+	if s.IsReadReplica() {
+		s.Jobs.Add("replica-follow", replicaFollowInterval, time.Second, s.followDBStates)
+	}
+	// Run as each local dbstate is processed, so not on a schedule
+	s.Jobs.Add("fastboot-save", 0, 0, func() error {
+		if !s.StateSaverStruct.FastBoot {
+			return nil
+		}
+		return s.StateSaverStruct.SaveDBStateList(s.DBStates, s.Network)
+	})
+}
@@ -0,0 +1,69 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/FactomProject/factomd/state"
+)
+
+func TestJobScheduler(t *testing.T) {
+	js := NewJobScheduler()
+	ran := map[string]int{}
+	js.Add("often", time.Millisecond, 0, func() error {
+		ran["often"]++
+		return nil
+	})
+	js.Add("manual", 0, 0, func() error {
+		ran["manual"]++
+		return fmt.Errorf("failed")
+	})
+
+	time.Sleep(5 * time.Millisecond)
+	js.RunDue()
+	if ran["often"] != 1 || ran["manual"] != 0 {
+		t.Errorf("Unexpected runs %v", ran)
+	}
+
+	if err := js.Trigger("manual"); err != nil {
+		t.Error(err)
+	}
+	if err := js.Trigger("missing"); err == nil {
+		t.Error("Triggered a job that doesn't exist")
+	}
+	if err := js.SetEnabled("often", false); err != nil {
+		t.Error(err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	js.RunDue()
+	if ran["often"] != 1 || ran["manual"] != 1 {
+		t.Errorf("Unexpected runs %v", ran)
+	}
+
+	// A disabled job still runs when triggered
+	js.Trigger("often")
+	js.RunDue()
+	if ran["often"] != 2 {
+		t.Errorf("Triggered job didn't run, %v", ran)
+	}
+
+	if err := js.RunNow("manual"); err == nil {
+		t.Error("Expected the job's error")
+	}
+
+	jobs := js.Status()
+	if len(jobs) != 2 || jobs[0].Name != "manual" || jobs[1].Name != "often" {
+		t.Fatalf("Unexpected jobs %v", jobs)
+	}
+	if jobs[0].Runs != 2 || jobs[0].Failures != 2 || jobs[0].LastError != "failed" || jobs[0].NextRun != 0 {
+		t.Errorf("Unexpected status %+v", jobs[0])
+	}
+	if jobs[1].Enabled || jobs[1].Runs != 2 || jobs[1].Failures != 0 {
+		t.Errorf("Unexpected status %+v", jobs[1])
+	}
+}
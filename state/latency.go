@@ -0,0 +1,94 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+)
+
+// The totals of time spent in each loop say how busy a node is, but not how long any one
+// message waits.  These histograms follow messages through: how long each takes to
+// execute, how long it waits in holding, how long from its ack to it being processed, and
+// for commits, how long from being made to being in a complete block.
+
+// latencyLabels returns the labels of the latency histograms for a message in a VM
+func latencyLabels(msg interfaces.IMsg, vm int) (string, string) {
+	return messages.MessageName(msg.Type()), strconv.Itoa(vm)
+}
+
+// secondsSince returns the seconds from a timestamp to now, and false if the timestamp is not
+// before now
+func secondsSince(t interfaces.Timestamp, now interfaces.Timestamp) (float64, bool) {
+	if t == nil || now == nil {
+		return 0, false
+	}
+	d := now.GetTimeMilli() - t.GetTimeMilli()
+	if d < 0 {
+		return 0, false
+	}
+	return float64(d) / 1000, true
+}
+
+// heldSince records when a message first went into holding; going round again through
+// XReview doesn't reset it
+func (s *State) heldSince(m interfaces.IMsg) {
+	if s.HoldingSince == nil {
+		s.HoldingSince = make(map[[32]byte]time.Time)
+	}
+	h := m.GetMsgHash().Fixed()
+	if _, ok := s.HoldingSince[h]; !ok {
+		s.HoldingSince[h] = time.Now()
+	}
+}
+
+// leftHolding records how long a message waited in holding, as it goes into the process
+// list of the VM given
+func (s *State) leftHolding(m interfaces.IMsg, vm int) {
+	h := m.GetMsgHash().Fixed()
+	t, ok := s.HoldingSince[h]
+	if !ok {
+		return
+	}
+	delete(s.HoldingSince, h)
+	msgtype, vmindex := latencyLabels(m, vm)
+	HoldingDwellVec.WithLabelValues(msgtype, vmindex).Observe(time.Since(t).Seconds())
+}
+
+// forgetDropped forgets when the messages dropped from holding went in.  Only called
+// between passes over holding, when every held message is in Holding.
+func (s *State) forgetDropped() {
+	for h := range s.HoldingSince {
+		if _, ok := s.Holding[h]; !ok {
+			delete(s.HoldingSince, h)
+		}
+	}
+}
+
+// observeCommitsToBlock records how long the commits of a process list took to be in a
+// complete block, from their timestamps
+func (s *State) observeCommitsToBlock(pl *ProcessList) {
+	now := s.GetTimestamp()
+	for i, vm := range pl.VMs {
+		for _, msg := range vm.List {
+			if msg == nil {
+				continue
+			}
+			switch msg.Type() {
+			case constants.COMMIT_CHAIN_MSG, constants.COMMIT_ENTRY_MSG:
+			default:
+				continue
+			}
+			if d, ok := secondsSince(msg.GetTimestamp(), now); ok {
+				msgtype, vmindex := latencyLabels(msg, i)
+				CommitToBlockVec.WithLabelValues(msgtype, vmindex).Observe(d)
+			}
+		}
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestHoldingSince(t *testing.T) {
+	s := testHelper.CreateEmptyTestState()
+	set := testHelper.CreateTestBlockSet(nil)
+
+	m := new(messages.CommitEntryMsg)
+	m.CommitEntry = testHelper.NewCommitEntry(set.EBlock)
+	h := m.GetMsgHash().Fixed()
+
+	s.FollowerExecuteMsg(m)
+	first, ok := s.HoldingSince[h]
+	if !ok {
+		t.Fatal("Held message has no time it went into holding")
+	}
+
+	// Going round through holding again doesn't reset the time
+	delete(s.Holding, h)
+	s.FollowerExecuteMsg(m)
+	if s.HoldingSince[h] != first {
+		t.Error("Holding the message again reset its time")
+	}
+
+	// Messages dropped from holding are forgotten on the next pass
+	delete(s.Holding, h)
+	s.ResendHolding = primitives.NewTimestampFromMilliseconds(0)
+	s.ReviewHolding()
+	if _, ok := s.HoldingSince[h]; ok {
+		t.Error("Message dropped from holding is still timed")
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+)
+
+// A freshly promoted leader often starts with a process list lagging the federation's, and
+// misses its first minute.  For LeaderGracePeriod after a promotion, the new leader asks its
+// peers for the messages of every VM of the block and puts off its first EOM until its
+// process list has caught up, while the rest of the federation doesn't fault its VM.
+
+// How long the new leader waits for the answers to its requests before it takes a process
+// list without gaps as caught up
+const leaderGraceSettle = time.Second
+
+// How often a put off EOM is tried again
+const leaderGraceRetry = 200 * time.Millisecond
+
+// leaderGraceSync is where the new leader is in syncing for its first EOM
+type leaderGraceSync struct {
+	dbheight uint32
+	promoted int64 // Unix seconds; identifies the promotion
+	started  time.Time
+	done     bool
+}
+
+// SetPromoted records that a server became a federated server now
+func (p *ProcessList) SetPromoted(identityChainID interfaces.IHash) {
+	// Promotions replayed while booting are history
+	if p.State.IgnoreMissing {
+		return
+	}
+	if p.Promoted == nil {
+		p.Promoted = make(map[[32]byte]int64)
+	}
+	p.Promoted[identityChainID.Fixed()] = time.Now().Unix()
+}
+
+// promotedAt returns when the server leading a VM this minute was promoted, if it was
+// within the grace period
+func (p *ProcessList) promotedAt(vmIndex int) (int64, bool) {
+	grace := p.State.LeaderGracePeriod
+	if grace <= 0 || vmIndex < 0 || vmIndex >= len(p.FedServers) {
+		return 0, false
+	}
+	c := p.State.CurrentMinute
+	if c > 9 {
+		c = 9
+	}
+	index := p.ServerMap[c][vmIndex]
+	if index >= len(p.FedServers) {
+		return 0, false
+	}
+	at, ok := p.Promoted[p.FedServers[index].GetChainID().Fixed()]
+	if !ok || time.Since(time.Unix(at, 0)) >= grace {
+		return 0, false
+	}
+	return at, true
+}
+
+// InLeaderGrace returns true if the server leading a VM this minute was promoted within the
+// grace period, so is not faulted for lagging
+func (p *ProcessList) InLeaderGrace(vmIndex int) bool {
+	_, ok := p.promotedAt(vmIndex)
+	return ok
+}
+
+// vmsCaughtUp returns true if no VM of the federation has gaps left to fill
+func (p *ProcessList) vmsCaughtUp() bool {
+	for i := range p.FedServers {
+		vm := p.VMs[i]
+		if vm.Height < len(vm.List) {
+			return false
+		}
+	}
+	return true
+}
+
+// holdForLeaderGrace puts off the first EOM of a newly promoted leader until the process
+// list of the block has caught up, or the grace period is over.  Returns true if the EOM is
+// put off; it is queued again shortly.
+func (s *State) holdForLeaderGrace(m interfaces.IMsg) bool {
+	pl := s.ProcessLists.Get(s.LLeaderHeight)
+	promoted, ok := pl.promotedAt(s.LeaderVMIndex)
+	g := s.leaderGrace
+	if !ok {
+		if g != nil && !g.done {
+			g.done = true
+			LeaderGraceVec.WithLabelValues("expired").Inc()
+		}
+		return false
+	}
+	if g == nil || g.promoted != promoted {
+		g = &leaderGraceSync{promoted: promoted}
+		s.leaderGrace = g
+	}
+	if g.done {
+		return false
+	}
+	if g.started.IsZero() || g.dbheight != pl.DBHeight {
+		g.dbheight = pl.DBHeight
+		g.started = time.Now()
+		for i := range pl.FedServers {
+			if i != s.LeaderVMIndex {
+				pl.Ask(i, pl.VMs[i].Height, 0, 4)
+			}
+		}
+	}
+	if time.Since(g.started) >= leaderGraceSettle && pl.vmsCaughtUp() {
+		g.done = true
+		LeaderGraceVec.WithLabelValues("synced").Inc()
+		return false
+	}
+
+	LeaderGraceVec.WithLabelValues("held").Inc()
+	time.AfterFunc(leaderGraceRetry, func() { s.TimerMsgQueue() <- m })
+	return true
+}
@@ -0,0 +1,47 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestInLeaderGrace(t *testing.T) {
+	s := testHelper.CreateEmptyTestState()
+	s.IgnoreMissing = false
+	pl := s.ProcessLists.Get(s.LLeaderHeight)
+	leader := pl.FedServers[pl.ServerMap[s.CurrentMinute][0]].GetChainID()
+
+	if pl.InLeaderGrace(0) {
+		t.Error("In grace with no promotion")
+	}
+	pl.SetPromoted(leader)
+	if pl.InLeaderGrace(0) {
+		t.Error("In grace with no grace period set")
+	}
+	s.LeaderGracePeriod = time.Minute
+	if !pl.InLeaderGrace(0) {
+		t.Error("Not in grace just after the promotion")
+	}
+	if pl.InLeaderGrace(len(pl.FedServers)) {
+		t.Error("In grace for a VM without a leader")
+	}
+
+	pl.Promoted[leader.Fixed()] = time.Now().Add(-2 * time.Minute).Unix()
+	if pl.InLeaderGrace(0) {
+		t.Error("In grace after the grace period")
+	}
+
+	// Promotions replayed while booting don't count
+	delete(pl.Promoted, leader.Fixed())
+	s.IgnoreMissing = true
+	pl.SetPromoted(leader)
+	if pl.InLeaderGrace(0) {
+		t.Error("In grace for a promotion replayed while booting")
+	}
+}
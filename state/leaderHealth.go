@@ -0,0 +1,196 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var healthLogger = packageLogger.WithFields(log.Fields{"subpack": "leader-health"})
+
+// A leader that falls behind is faulted by the others once its VM has been missing long
+// enough, and the network waits on it until then.  A leader can often tell sooner that it
+// is struggling: its minutes end late, it is asking its peers for messages it missed, and
+// its database writes are slow.  With AutoStepDown, a leader that has been unhealthy for
+// a few minutes running volunteers to step down, and the others start the election to
+// replace it with an audit server right away.
+
+// LeaderHealthPolicy is how unhealthy a leader may be before it steps down
+type LeaderHealthPolicy struct {
+	MaxLag         time.Duration // Past the length of a minute
+	MaxMissingAsks int           // Asks for missing messages in a minute
+	MaxDiskLatency time.Duration // Of the slowest write in a minute
+	Minutes        int           // Unhealthy minutes running before stepping down
+}
+
+// How many minutes of health are kept
+const MaxLeaderHealthSamples = 60
+
+// LeaderHealthSample is the health of the node over a minute
+type LeaderHealthSample struct {
+	DBHeight    uint32
+	Minute      int
+	Lag         time.Duration
+	MissingAsks int
+	DiskLatency time.Duration
+	Reasons     uint8 // messages.StepDownLag and the rest, for those over the policy
+}
+
+// LeaderHealthMonitor measures the health of the node a minute at a time
+type LeaderHealthMonitor struct {
+	mutex       sync.Mutex
+	asks        int           // Missing message asks this minute
+	disk        time.Duration // Slowest write this minute
+	unhealthy   int           // Unhealthy minutes running
+	steppedDown uint32        // Height we last stepped down at, plus one
+	samples     []LeaderHealthSample
+}
+
+func NewLeaderHealthMonitor() *LeaderHealthMonitor {
+	return new(LeaderHealthMonitor)
+}
+
+// MissingAsked counts an ask of our peers for messages we missed
+func (m *LeaderHealthMonitor) MissingAsked() {
+	if m == nil {
+		return
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.asks++
+}
+
+// DiskWrite notes how long a write to the database took
+func (m *LeaderHealthMonitor) DiskWrite(d time.Duration) {
+	if m == nil {
+		return
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if d > m.disk {
+		m.disk = d
+	}
+}
+
+// MinuteEnded closes the minute, which ran lag past its length, and returns its health.
+// It returns true if the node has been unhealthy for long enough to step down, once a
+// block.
+func (m *LeaderHealthMonitor) MinuteEnded(dbheight uint32, minute int, lag time.Duration, policy LeaderHealthPolicy) (LeaderHealthSample, bool) {
+	if m == nil {
+		return LeaderHealthSample{}, false
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if lag < 0 {
+		lag = 0
+	}
+	sample := LeaderHealthSample{DBHeight: dbheight, Minute: minute, Lag: lag, MissingAsks: m.asks, DiskLatency: m.disk}
+	m.asks, m.disk = 0, 0
+	if policy.MaxLag > 0 && sample.Lag > policy.MaxLag {
+		sample.Reasons |= messages.StepDownLag
+	}
+	if policy.MaxMissingAsks > 0 && sample.MissingAsks > policy.MaxMissingAsks {
+		sample.Reasons |= messages.StepDownMissing
+	}
+	if policy.MaxDiskLatency > 0 && sample.DiskLatency > policy.MaxDiskLatency {
+		sample.Reasons |= messages.StepDownDisk
+	}
+
+	m.samples = append(m.samples, sample)
+	if len(m.samples) > MaxLeaderHealthSamples {
+		m.samples = append([]LeaderHealthSample{}, m.samples[len(m.samples)-MaxLeaderHealthSamples:]...)
+	}
+	LeaderMinuteLag.Set(sample.Lag.Seconds())
+	LeaderMissingAsks.Set(float64(sample.MissingAsks))
+	LeaderDiskLatency.Set(sample.DiskLatency.Seconds())
+
+	if sample.Reasons == 0 {
+		m.unhealthy = 0
+		return sample, false
+	}
+	m.unhealthy++
+	if policy.Minutes <= 0 || m.unhealthy < policy.Minutes || m.steppedDown == dbheight+1 {
+		return sample, false
+	}
+	m.steppedDown = dbheight + 1
+	return sample, true
+}
+
+// Samples returns the health of the last minutes, oldest first
+func (m *LeaderHealthMonitor) Samples() []LeaderHealthSample {
+	if m == nil {
+		return nil
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return append([]LeaderHealthSample{}, m.samples...)
+}
+
+// checkLeaderHealth closes a minute that took elapsed, and if we are a leader that should
+// step down, volunteers to
+func (s *State) checkLeaderHealth(dbheight uint32, minute int, elapsed time.Duration) {
+	lag := elapsed - time.Duration(s.DirectoryBlockInSeconds)*time.Second/10
+	sample, stepDown := s.LeaderHealth.MinuteEnded(dbheight, minute, lag, s.StepDownPolicy)
+	if !stepDown || !s.AutoStepDown || !s.Leader {
+		return
+	}
+
+	msg := messages.NewStepDown(s, s.LeaderVMIndex, sample.Reasons)
+	if err := msg.Sign(s.serverPrivKey); err != nil {
+		healthLogger.Errorf("Cannot sign the step down: %v", err)
+		return
+	}
+	healthLogger.WithFields(msg.LogFields()).WithFields(log.Fields{"lag": sample.Lag, "missing-asks": sample.MissingAsks, "disk-latency": sample.DiskLatency}).Warn("Volunteering to step down")
+	TotalStepDowns.Inc()
+	msg.SendOut(s, msg)
+	msg.FollowerExecute(s)
+}
+
+// FollowerExecuteStepDown takes the VM of a leader that volunteered to step down as
+// faulted, long enough ago for the election to replace it to start
+func (s *State) FollowerExecuteStepDown(m interfaces.IMsg) {
+	sd, ok := m.(*messages.StepDown)
+	if !ok {
+		return
+	}
+	pl := s.ProcessLists.Get(sd.DBHeight)
+	if pl == nil || int(sd.VM) >= len(pl.FedServers) || sd.Minute > 9 {
+		return
+	}
+
+	// Only the leader itself can step down, and only from its own VM
+	auth, _ := s.GetAuthority(sd.IdentityChainID)
+	if auth == nil {
+		return
+	}
+	data, err := sd.MarshalForSignature()
+	if err != nil {
+		return
+	}
+	if valid, err := auth.VerifySignature(data, sd.Signature.GetSignature()); err != nil || !valid {
+		return
+	}
+	index := pl.ServerMap[sd.Minute][sd.VM]
+	if index >= len(pl.FedServers) || !pl.FedServers[index].GetChainID().IsSameAs(sd.IdentityChainID) {
+		return
+	}
+
+	vm := pl.VMs[sd.VM]
+	if vm.WhenFaulted != 0 {
+		return
+	}
+	markFault(pl, int(sd.VM), faultStepDown)
+	if vm.WhenFaulted != 0 {
+		vm.WhenFaulted -= int64(s.FaultTimeout)
+	}
+	healthLogger.WithFields(sd.LogFields()).Info("Leader stepped down")
+}
@@ -0,0 +1,59 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/FactomProject/factomd/common/messages"
+	. "github.com/FactomProject/factomd/state"
+)
+
+func TestLeaderHealthMonitor(t *testing.T) {
+	policy := LeaderHealthPolicy{MaxLag: 5 * time.Second, MaxMissingAsks: 2, MaxDiskLatency: time.Second, Minutes: 3}
+	m := NewLeaderHealthMonitor()
+
+	// Two slow minutes, then a healthy one, start the count over
+	for i := 0; i < 2; i++ {
+		if _, stepDown := m.MinuteEnded(10, i, 6*time.Second, policy); stepDown {
+			t.Errorf("Stepped down after %d unhealthy minutes", i+1)
+		}
+	}
+	if s, stepDown := m.MinuteEnded(10, 2, time.Second, policy); stepDown || s.Reasons != 0 {
+		t.Errorf("Healthy minute gave %d, %v", s.Reasons, stepDown)
+	}
+
+	// Three unhealthy minutes running step down, once a block
+	for i := 0; i < 3; i++ {
+		m.MissingAsked()
+		m.MissingAsked()
+		m.MissingAsked()
+		m.DiskWrite(2 * time.Second)
+		s, stepDown := m.MinuteEnded(10, 3+i, 0, policy)
+		if s.Reasons != messages.StepDownMissing|messages.StepDownDisk {
+			t.Errorf("Minute %d gave reasons %d", 3+i, s.Reasons)
+		}
+		if stepDown != (i == 2) {
+			t.Errorf("Minute %d stepped down %v", 3+i, stepDown)
+		}
+	}
+	if _, stepDown := m.MinuteEnded(10, 6, time.Minute, policy); stepDown {
+		t.Error("Stepped down twice in a block")
+	}
+	if _, stepDown := m.MinuteEnded(11, 0, time.Minute, policy); !stepDown {
+		t.Error("Expected to step down again in the next block")
+	}
+
+	if n := len(m.Samples()); n != 8 {
+		t.Errorf("Expected 8 samples, got %d", n)
+	}
+	var nilMonitor *LeaderHealthMonitor
+	nilMonitor.MissingAsked()
+	nilMonitor.DiskWrite(time.Second)
+	if _, stepDown := nilMonitor.MinuteEnded(1, 0, time.Minute, policy); stepDown {
+		t.Error("A nil monitor stepped down")
+	}
+}
@@ -6,6 +6,7 @@ package state
 
 import (
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"os"
@@ -43,6 +44,22 @@ func LoadDatabase(s *State) {
 	if start > 10 {
 		start = start - 10
 	}
+	// The blocks below the snapshot we were bootstrapped from aren't in the database; the
+	// state at it comes from the fastboot file
+	if s.SnapshotHeight > 0 {
+		if s.DBStates.Base < s.SnapshotHeight {
+			panic(fmt.Sprintf("The database was bootstrapped from the snapshot at %d, and the fastboot file with its state is missing", s.SnapshotHeight))
+		}
+		if start < s.SnapshotHeight {
+			start = s.SnapshotHeight
+		}
+	}
+	var toLoad uint64
+	if blkCnt > start {
+		toLoad = uint64(blkCnt - start)
+	}
+	atomic.StoreUint32(&s.startupLoadedHeight, blkCnt)
+	s.Startup.SetPhase(StartupPhaseDatabase, toLoad)
 
 	for i := int(start); i <= int(blkCnt); i++ {
 		if i > 0 && i%1000 == 0 {
@@ -72,7 +89,9 @@ func LoadDatabase(s *State) {
 		}
 
 		s.Print("\r", "\\|/-"[i%4:i%4+1])
+		s.Startup.Progress(uint64(i-int(start)), toLoad)
 	}
+	s.Startup.SetPhase(StartupPhaseValidation, uint64(blkCnt))
 
 	if blkCnt == 0 {
 		s.Println("\n***********************************")
@@ -84,6 +103,8 @@ func LoadDatabase(s *State) {
 		msg := messages.NewDBStateMsg(s.GetTimestamp(), dblk, ablk, fblk, ecblk, nil, nil, nil)
 		s.InMsgQueue().Enqueue(msg)
 	}
+	s.loadRejections()
+	s.restoreHolding()
 	s.Println(fmt.Sprintf("Loaded %d directory blocks on %s", blkCnt, s.FactomNodeName))
 }
 
@@ -0,0 +1,159 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+)
+
+// Rough cost of keeping a message in a map or list, beyond its marshalled bytes: the key,
+// the interface and the object headers
+const msgOverheadBytes = 64
+
+// Rough cost of one hash remembered by a replay filter: the key, the int and the map slot
+const replayEntryBytes = 48
+
+// msgBytes estimates the memory a message takes as its marshalled size
+func msgBytes(msg interfaces.IMsg) int {
+	if msg == nil {
+		return 0
+	}
+	data, err := msg.MarshalBinary()
+	if err != nil {
+		return msgOverheadBytes
+	}
+	return len(data) + msgOverheadBytes
+}
+
+func msgMapBytes(msgs map[[32]byte]interfaces.IMsg) int {
+	n := 0
+	for _, msg := range msgs {
+		n += msgBytes(msg)
+	}
+	return n
+}
+
+func msgListBytes(msgs []interfaces.IMsg) int {
+	n := 0
+	for _, msg := range msgs {
+		n += msgBytes(msg)
+	}
+	return n
+}
+
+// bytes estimates the memory held by the commits in the pool
+func (m *Mempool) bytes() int {
+	m.RLock()
+	defer m.RUnlock()
+	n := 0
+	for _, c := range m.commits {
+		n += msgBytes(c.msg)
+	}
+	return n
+}
+
+// bytes estimates the memory held by the messages in the map
+func (m *SafeMsgMap) bytes() int {
+	m.RLock()
+	defer m.RUnlock()
+	return msgMapBytes(m.msgmap)
+}
+
+// bytes estimates the memory held by the hashes the filter remembers
+func (r *Replay) bytes() int {
+	if r == nil {
+		return 0
+	}
+	r.Mutex.Lock()
+	defer r.Mutex.Unlock()
+	n := 0
+	for _, bucket := range r.Buckets {
+		n += len(bucket) * replayEntryBytes
+	}
+	return n
+}
+
+// bytes estimates the memory held by the messages, entries and entry blocks of a process
+// list
+func (p *ProcessList) bytes() int {
+	n := 0
+	for _, vm := range p.VMs {
+		n += msgListBytes(vm.List)
+		for _, ack := range vm.ListAck {
+			if ack != nil {
+				n += msgBytes(ack)
+			}
+		}
+	}
+
+	p.oldmsgslock.Lock()
+	n += msgMapBytes(p.OldMsgs)
+	p.oldmsgslock.Unlock()
+	p.oldackslock.Lock()
+	n += msgMapBytes(p.OldAcks)
+	p.oldackslock.Unlock()
+
+	p.NewEntriesMutex.RLock()
+	for _, entry := range p.NewEntries {
+		if data, err := entry.MarshalBinary(); err == nil {
+			n += len(data) + msgOverheadBytes
+		}
+	}
+	p.NewEntriesMutex.RUnlock()
+
+	p.neweblockslock.Lock()
+	for _, eb := range p.NewEBlocks {
+		if data, err := eb.MarshalBinary(); err == nil {
+			n += len(data) + msgOverheadBytes
+		}
+	}
+	p.neweblockslock.Unlock()
+	return n
+}
+
+// MemoryUsage estimates the bytes held by each of the structures that grow with the
+// traffic, by subsystem.  The messages are counted by their marshalled size, so it is an
+// account of what grew rather than an exact figure.  It reads the consensus structures,
+// so must be called from the consensus loop.
+func (s *State) MemoryUsage() map[string]int {
+	usage := map[string]int{
+		"holding":           msgMapBytes(s.Holding),
+		"xreview":           msgListBytes(s.XReview),
+		"acks":              msgMapBytes(s.Acks),
+		"invalid-messages":  msgMapBytes(s.InvalidMessages),
+		"replay":            s.Replay.bytes() + s.FReplay.bytes(),
+		"dbstates-received": 0,
+		"processlists":      0,
+		"commits":           0,
+		"read-cache":        0,
+	}
+	for _, msg := range s.DBStatesReceived {
+		if msg != nil {
+			usage["dbstates-received"] += msgBytes(msg)
+		}
+	}
+	if s.ProcessLists != nil {
+		for _, pl := range s.ProcessLists.Lists {
+			if pl != nil {
+				usage["processlists"] += pl.bytes()
+			}
+		}
+	}
+	if s.Commits != nil {
+		usage["commits"] = s.Commits.bytes()
+	}
+	if overlay, ok := s.DB.(*databaseOverlay.Overlay); ok {
+		usage["read-cache"] = overlay.ReadCacheBytes()
+	}
+	return usage
+}
+
+// updateMemoryGauges sets the memory gauges to the current account
+func (s *State) updateMemoryGauges() {
+	for subsystem, n := range s.MemoryUsage() {
+		MemoryBytesVec.WithLabelValues(subsystem).Set(float64(n))
+	}
+}
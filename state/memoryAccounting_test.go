@@ -0,0 +1,41 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestMemoryUsage(t *testing.T) {
+	s := testHelper.CreateAndPopulateTestState()
+
+	before := s.MemoryUsage()
+	for _, subsystem := range []string{"holding", "xreview", "acks", "processlists", "dbstates-received", "replay", "commits", "read-cache"} {
+		if _, ok := before[subsystem]; !ok {
+			t.Errorf("No account of %s", subsystem)
+		}
+	}
+
+	commit := newSigTestCommit(1, true)
+	data, err := commit.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Holding[commit.GetMsgHash().Fixed()] = commit
+	s.XReview = append(s.XReview, commit)
+
+	after := s.MemoryUsage()
+	if after["holding"]-before["holding"] < len(data) {
+		t.Errorf("Holding grew by %d bytes, expected at least %d", after["holding"]-before["holding"], len(data))
+	}
+	if after["xreview"]-before["xreview"] < len(data) {
+		t.Errorf("XReview grew by %d bytes, expected at least %d", after["xreview"]-before["xreview"], len(data))
+	}
+	if after["acks"] != before["acks"] {
+		t.Errorf("Acks changed without any being added")
+	}
+}
@@ -0,0 +1,353 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"container/heap"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+)
+
+// Why a commit left the mempool other than by its reveal being processed
+const (
+	EvictRevealed = "revealed" // Its entry is already in a block
+	EvictReplay   = "replay"   // Outside the replay window, so its reveal can't come
+	EvictAge      = "age"      // Older than MaxAge
+	EvictCapacity = "capacity" // The pool was full, and it paid the least
+)
+
+type mempoolCommit struct {
+	key      [32]byte // Entry hash
+	msg      interfaces.IMsg
+	received int64 // Unix milliseconds
+	paid     bool  // Acked and processed, so its credits are spent
+	index    int   // Its place in the heap of unpaid commits, -1 if paid
+}
+
+// Mempool holds the commits waiting on their reveals, or on a block to take them.  Commits
+// are evicted once their entry is in a block, and once they are outside the replay window.
+// Those not yet acked and processed, so not yet paid for, are evicted too once older than
+// MaxAge, and, while the pool holds more than MaxCommits of them, the one paying the least
+// first.  A paid commit is kept until its reveal comes or the replay window passes, as the
+// credits it spent are gone.
+type Mempool struct {
+	commits map[[32]byte]*mempoolCommit
+	unpaid  unpaidCommits
+	sync.RWMutex
+
+	MaxAge     time.Duration // 0 leaves expiry to the replay filter
+	MaxCommits int           // Most unpaid commits held, 0 for no limit
+}
+
+func NewMempool() *Mempool {
+	m := new(Mempool)
+	m.commits = make(map[[32]byte]*mempoolCommit)
+	return m
+}
+
+// newMempool returns a mempool with the configured eviction policy
+func (s *State) newMempool() *Mempool {
+	m := NewMempool()
+	m.MaxAge = s.MempoolMaxAge
+	m.MaxCommits = s.MempoolMaxCommits
+	return m
+}
+
+// CommitCredits returns the entry credits a commit pays, 0 if it isn't a commit
+func CommitCredits(msg interfaces.IMsg) int {
+	switch c := msg.(type) {
+	case *messages.CommitEntryMsg:
+		return int(c.CommitEntry.Credits)
+	case *messages.CommitChainMsg:
+		return int(c.CommitChain.Credits)
+	}
+	return 0
+}
+
+// Get returns the paid commit for the entry hash key, nil if there is none
+func (m *Mempool) Get(key [32]byte) (msg interfaces.IMsg) {
+	m.RLock()
+	defer m.RUnlock()
+	if c := m.commits[key]; c != nil && c.paid {
+		return c.msg
+	}
+	return nil
+}
+
+// Put adds a paid commit for the entry hash key, one acked and processed, replacing any
+// there.  Paid commits are never evicted for age or room.
+func (m *Mempool) Put(key [32]byte, msg interfaces.IMsg) {
+	m.Lock()
+	defer m.Unlock()
+	m.remove(key)
+	m.commits[key] = &mempoolCommit{key: key, msg: msg, received: time.Now().UnixNano() / 1e6, paid: true, index: -1}
+}
+
+// Offer adds a commit for the entry hash key that isn't yet acked, unless a paid commit or
+// one paying as much is there.  If that takes the pool over MaxCommits unpaid commits, the
+// unpaid commit paying the least, the oldest of those paying the same, is evicted.
+func (m *Mempool) Offer(key [32]byte, msg interfaces.IMsg) {
+	m.Lock()
+	defer m.Unlock()
+	if c := m.commits[key]; c != nil && (c.paid || CommitCredits(c.msg) >= CommitCredits(msg)) {
+		return
+	}
+	m.remove(key)
+	c := &mempoolCommit{key: key, msg: msg, received: time.Now().UnixNano() / 1e6}
+	m.commits[key] = c
+	heap.Push(&m.unpaid, c)
+	for m.MaxCommits > 0 && len(m.unpaid) > m.MaxCommits {
+		m.evict(m.unpaid[0].key, EvictCapacity)
+	}
+}
+
+// remove takes the commit for key out of the pool, and out of the heap if it is unpaid
+func (m *Mempool) remove(key [32]byte) {
+	c := m.commits[key]
+	if c == nil {
+		return
+	}
+	if !c.paid {
+		heap.Remove(&m.unpaid, c.index)
+	}
+	delete(m.commits, key)
+}
+
+func (m *Mempool) evict(key [32]byte, reason string) {
+	m.remove(key)
+	MempoolEvictionsVec.WithLabelValues(reason).Inc()
+}
+
+func (m *Mempool) Delete(key [32]byte) (msg interfaces.IMsg, found bool) {
+	m.Lock()
+	m.remove(key)
+	m.Unlock()
+	return
+}
+
+func (m *Mempool) Len() int {
+	m.RLock()
+	defer m.RUnlock()
+	return len(m.commits)
+}
+
+// Copy returns a copy of the pool, with the same policy
+func (m *Mempool) Copy() *Mempool {
+	m2 := NewMempool()
+
+	m.RLock()
+	m2.MaxAge = m.MaxAge
+	m2.MaxCommits = m.MaxCommits
+	for k, v := range m.commits {
+		c := *v
+		m2.commits[k] = &c
+		if !c.paid {
+			heap.Push(&m2.unpaid, &c)
+		}
+	}
+	m.RUnlock()
+
+	return m2
+}
+
+// Reset will delete all commits
+func (m *Mempool) Reset() {
+	m.Lock()
+	if len(m.commits) > 0 {
+		m.commits = make(map[[32]byte]*mempoolCommit)
+		m.unpaid = nil
+	}
+	m.Unlock()
+}
+
+// Cleanup evicts the commits whose entries are already in a block, then those RemoveExpired
+// would
+func (m *Mempool) Cleanup(s *State) {
+	m.Lock()
+	now := s.GetTimestamp()
+	for k, c := range m.commits {
+		var entryHash interfaces.IHash
+		switch commit := c.msg.(type) {
+		case *messages.CommitChainMsg:
+			entryHash = commit.CommitChain.EntryHash
+		case *messages.CommitEntryMsg:
+			entryHash = commit.CommitEntry.EntryHash
+		}
+		if entryHash != nil && !s.NoEntryYet(entryHash, now) {
+			m.evict(k, EvictRevealed)
+		}
+	}
+	m.removeExpired(s)
+	m.Unlock()
+}
+
+// RemoveExpired evicts the commits outside the replay window, and the unpaid commits older
+// than MaxAge
+func (m *Mempool) RemoveExpired(s *State) {
+	m.Lock()
+	m.removeExpired(s)
+	m.Unlock()
+}
+
+func (m *Mempool) removeExpired(s *State) {
+	now := s.GetTimestamp()
+	oldest := int64(0)
+	if m.MaxAge > 0 {
+		oldest = time.Now().Add(-m.MaxAge).UnixNano() / 1e6
+	}
+	for k, c := range m.commits {
+		if c.msg == nil {
+			continue
+		}
+		if _, ok := s.Replay.Valid(constants.TIME_TEST, c.msg.GetRepeatHash().Fixed(), c.msg.GetTimestamp(), now); !ok {
+			m.evict(k, EvictReplay)
+			continue
+		}
+		if !c.paid && c.received < oldest {
+			m.evict(k, EvictAge)
+		}
+	}
+}
+
+// Pending lists the commits in the pool, paid or not, highest paying first, the oldest first
+// of those paying the same.  revealed is called with the entry hash of each, to tell whether its
+// reveal is waiting too.
+func (m *Mempool) Pending(revealed func([32]byte) bool) []interfaces.PendingCommit {
+	m.RLock()
+	list := make([]interfaces.PendingCommit, 0, len(m.commits))
+	for k, c := range m.commits {
+		p := interfaces.PendingCommit{Credits: CommitCredits(c.msg), Received: c.received, Paid: c.paid}
+		switch commit := c.msg.(type) {
+		case *messages.CommitChainMsg:
+			p.EntryHash = commit.CommitChain.EntryHash.String()
+			p.ChainIDHash = commit.CommitChain.ChainIDHash.String()
+		case *messages.CommitEntryMsg:
+			p.EntryHash = commit.CommitEntry.EntryHash.String()
+		default:
+			continue
+		}
+		p.Revealed = revealed != nil && revealed(k)
+		list = append(list, p)
+	}
+	m.RUnlock()
+
+	sort.Stable(byCommitFee(list))
+	return list
+}
+
+type byCommitFee []interfaces.PendingCommit
+
+func (p byCommitFee) Len() int      { return len(p) }
+func (p byCommitFee) Swap(i, j int) { p[i], p[j] = p[j], p[i] }
+func (p byCommitFee) Less(i, j int) bool {
+	if p[i].Credits != p[j].Credits {
+		return p[i].Credits > p[j].Credits
+	}
+	return p[i].Received < p[j].Received
+}
+
+// unpaidCommits is a heap of the unpaid commits, the one paying the least, the oldest of
+// those paying the same, on top
+type unpaidCommits []*mempoolCommit
+
+func (h unpaidCommits) Len() int { return len(h) }
+func (h unpaidCommits) Less(i, j int) bool {
+	ci, cj := CommitCredits(h[i].msg), CommitCredits(h[j].msg)
+	if ci != cj {
+		return ci < cj
+	}
+	return h[i].received < h[j].received
+}
+func (h unpaidCommits) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+func (h *unpaidCommits) Push(x interface{}) {
+	c := x.(*mempoolCommit)
+	c.index = len(*h)
+	*h = append(*h, c)
+}
+func (h *unpaidCommits) Pop() interface{} {
+	old := *h
+	c := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	c.index = -1
+	return c
+}
+
+// GetRaw is used in testing and simcontrol. Do no use this in production
+func (m *Mempool) GetRaw() map[[32]byte]interfaces.IMsg {
+	m.RLock()
+	defer m.RUnlock()
+	raw := make(map[[32]byte]interfaces.IMsg, len(m.commits))
+	for k, c := range m.commits {
+		if c.paid {
+			raw[k] = c.msg
+		}
+	}
+	return raw
+}
+
+// GetPendingPool returns what waits to go in a block: the commits in the mempool and the
+// reveals held
+func (s *State) GetPendingPool() interfaces.PendingPool {
+	pool := interfaces.PendingPool{MaxCommits: s.Commits.MaxCommits, MaxAge: int64(s.Commits.MaxAge / time.Second)}
+
+	committed := make(map[[32]byte]bool)
+	for _, msg := range s.LoadHoldingMap() {
+		re, ok := msg.(*messages.RevealEntryMsg)
+		if !ok || re.Entry == nil {
+			continue
+		}
+		eh := re.Entry.GetHash()
+		credits, needed := s.revealFee(re)
+		committed[eh.Fixed()] = credits > 0
+		pool.Reveals = append(pool.Reveals, interfaces.PendingReveal{
+			EntryHash: eh.String(),
+			ChainID:   re.Entry.GetChainID().String(),
+			KSize:     re.Entry.KSize(),
+			Credits:   credits,
+			Needed:    needed,
+		})
+	}
+	pool.Commits = s.Commits.Pending(func(eh [32]byte) bool { return committed[eh] })
+
+	sort.Stable(byRevealFee(pool.Reveals))
+	return pool
+}
+
+type byRevealFee []interfaces.PendingReveal
+
+func (p byRevealFee) Len() int      { return len(p) }
+func (p byRevealFee) Swap(i, j int) { p[i], p[j] = p[j], p[i] }
+func (p byRevealFee) Less(i, j int) bool {
+	return paysMore(p[i].Credits, p[i].Needed, p[j].Credits, p[j].Needed)
+}
+
+// revealFee returns the credits paid by the commit of a reveal, 0 if it has none, and the
+// credits it needs
+func (s *State) revealFee(re *messages.RevealEntryMsg) (credits int, needed int) {
+	needed = re.Entry.KSize()
+	switch c := s.Commits.Get(re.Entry.GetHash().Fixed()).(type) {
+	case *messages.CommitEntryMsg:
+		return int(c.CommitEntry.Credits), needed
+	case *messages.CommitChainMsg:
+		return int(c.CommitChain.Credits), needed + 10
+	}
+	return 0, needed
+}
+
+// paysMore returns true if a reveal paid credits1 for needed1 pays more per credit it needs
+// than one paid credits2 for needed2
+func paysMore(credits1, needed1, credits2, needed2 int) bool {
+	return credits1*needed2 > credits2*needed1
+}
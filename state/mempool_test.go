@@ -0,0 +1,181 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/entryCreditBlock"
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
+	. "github.com/FactomProject/factomd/state"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+// newPoolCommit returns a commit of a random entry paying credits, timestamped now
+func newPoolCommit(credits uint8) *messages.CommitEntryMsg {
+	commit := messages.NewCommitEntryMsg()
+	commit.CommitEntry = entryCreditBlock.NewCommitEntry()
+	commit.CommitEntry.Credits = credits
+	commit.CommitEntry.EntryHash = primitives.RandomHash()
+	ms := time.Now().UnixNano() / 1e6
+	var milli [6]byte
+	for i := 5; i >= 0; i-- {
+		milli[i] = byte(ms)
+		ms >>= 8
+	}
+	commit.CommitEntry.MilliTime = (*primitives.ByteSlice6)(&milli)
+	commit.CommitEntry.Init()
+	return commit
+}
+
+// pooled returns true if the commit is in the pool, paid or not
+func pooled(m *Mempool, c *messages.CommitEntryMsg) bool {
+	for _, p := range m.Pending(nil) {
+		if p.EntryHash == c.CommitEntry.EntryHash.String() {
+			return true
+		}
+	}
+	return false
+}
+
+func TestMempoolCapacity(t *testing.T) {
+	m := NewMempool()
+	m.MaxCommits = 3
+
+	low := newPoolCommit(1)
+	oldMid := newPoolCommit(2)
+	newMid := newPoolCommit(2)
+	high := newPoolCommit(5)
+	for _, c := range []*messages.CommitEntryMsg{low, oldMid, high} {
+		m.Offer(c.CommitEntry.EntryHash.Fixed(), c)
+	}
+	time.Sleep(2 * time.Millisecond)
+
+	// Over capacity, the lowest paying goes
+	m.Offer(newMid.CommitEntry.EntryHash.Fixed(), newMid)
+	if m.Len() != 3 {
+		t.Fatalf("Pool holds %d commits, expected 3", m.Len())
+	}
+	if pooled(m, low) {
+		t.Error("The lowest paying commit wasn't evicted")
+	}
+
+	// Of those paying the same, the oldest goes
+	extra := newPoolCommit(3)
+	m.Offer(extra.CommitEntry.EntryHash.Fixed(), extra)
+	if pooled(m, oldMid) || !pooled(m, newMid) {
+		t.Error("Expected the older of the commits paying the same to be evicted")
+	}
+
+	// Once paid, the highest stays, and the lowest unpaid goes instead
+	m.Put(high.CommitEntry.EntryHash.Fixed(), high)
+	if m.Get(high.CommitEntry.EntryHash.Fixed()) == nil {
+		t.Error("Paid commit not found")
+	}
+	for _, credits := range []uint8{1, 1, 1} {
+		c := newPoolCommit(credits)
+		m.Offer(c.CommitEntry.EntryHash.Fixed(), c)
+	}
+	if m.Get(high.CommitEntry.EntryHash.Fixed()) == nil {
+		t.Error("Paid commit evicted for room")
+	}
+	time.Sleep(2 * time.Millisecond)
+	high2 := newPoolCommit(5)
+	m.Offer(high2.CommitEntry.EntryHash.Fixed(), high2)
+
+	pending := m.Pending(nil)
+	if len(pending) != 4 {
+		t.Fatalf("Listed %d commits, expected 4", len(pending))
+	}
+	for i, credits := range []int{5, 5, 3, 2} {
+		if pending[i].Credits != credits {
+			t.Errorf("Commit %d pays %d, expected %d", i, pending[i].Credits, credits)
+		}
+	}
+	if !pending[0].Paid || pending[1].Paid {
+		t.Errorf("Expected only the first commit to be paid: %v", pending)
+	}
+}
+
+func TestMempoolExpiry(t *testing.T) {
+	s := testHelper.CreateEmptyTestState()
+	m := NewMempool()
+
+	fresh := newPoolCommit(1)
+	stale := newPoolCommit(1)
+	stale.CommitEntry.MilliTime = new(primitives.ByteSlice6)
+	m.Put(fresh.CommitEntry.EntryHash.Fixed(), fresh)
+	m.Put(stale.CommitEntry.EntryHash.Fixed(), stale)
+
+	// Without MaxAge only the replay filter expires commits
+	m.RemoveExpired(s)
+	if m.Get(stale.CommitEntry.EntryHash.Fixed()) != nil {
+		t.Error("Commit outside the replay window kept")
+	}
+	if m.Get(fresh.CommitEntry.EntryHash.Fixed()) == nil {
+		t.Error("Fresh commit evicted with no MaxAge")
+	}
+
+	// Only unpaid commits are expired by age
+	unpaid := newPoolCommit(1)
+	m.Offer(unpaid.CommitEntry.EntryHash.Fixed(), unpaid)
+	m.MaxAge = time.Millisecond
+	time.Sleep(5 * time.Millisecond)
+	m.RemoveExpired(s)
+	if m.Len() != 1 || m.Get(fresh.CommitEntry.EntryHash.Fixed()) == nil {
+		t.Error("Expected the unpaid commit older than MaxAge to go, and the paid one to stay")
+	}
+}
+
+func TestGetPendingPool(t *testing.T) {
+	s := testHelper.CreateEmptyTestState()
+	s.Commits.MaxCommits = 10
+	s.Commits.MaxAge = time.Minute
+
+	for _, credits := range []uint8{1, 4, 2} {
+		c := newPoolCommit(credits)
+		s.PutCommit(c.CommitEntry.EntryHash, c)
+	}
+	pool := s.GetPendingPool()
+	if pool.MaxCommits != 10 || pool.MaxAge != 60 {
+		t.Errorf("Policy %d commits, %d seconds, expected 10, 60", pool.MaxCommits, pool.MaxAge)
+	}
+	if len(pool.Commits) != 3 || pool.Commits[0].Credits != 4 || pool.Commits[2].Credits != 1 {
+		t.Errorf("Commits not highest paying first: %v", pool.Commits)
+	}
+	if len(pool.Reveals) != 0 {
+		t.Errorf("Listed reveals with none held: %v", pool.Reveals)
+	}
+}
+
+func TestIsEntryLimitReached(t *testing.T) {
+	s := testHelper.CreateEmptyTestState()
+	pl := s.ProcessLists.Get(s.LLeaderHeight)
+	for i := 0; i < 3; i++ {
+		e := testHelper.CreateTestEntry(uint32(i))
+		pl.AddNewEntry(e.GetHash(), e)
+	}
+
+	if s.IsEntryLimitReached(s.LLeaderHeight) {
+		t.Error("Limit reached with no limit set")
+	}
+	s.MaxEntriesPerBlock = 3
+	if !s.IsEntryLimitReached(s.LLeaderHeight) {
+		t.Error("Limit not reached at 3 of 3 entries")
+	}
+	s.MaxEntriesPerBlock = 4
+	if s.IsEntryLimitReached(s.LLeaderHeight) {
+		t.Error("Limit reached at 3 of 4 entries")
+	}
+
+	s.MaxEntriesPerBlock = 1
+	s.NetworkNumber = constants.NETWORK_MAIN
+	if s.IsEntryLimitReached(s.LLeaderHeight) {
+		t.Error("Limit applied on mainnet")
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+)
+
+type delayRule struct {
+	min time.Duration
+	max time.Duration
+}
+
+// MessageDelayTracker holds the delays injected into messages from peers by type, so
+// the orderings only seen under latency on a real network can be reproduced in tests
+// and the simulator.  Nothing is delayed unless a delay is set through the debug API.
+type MessageDelayTracker struct {
+	mutex sync.Mutex
+	rules map[byte]delayRule
+}
+
+func NewMessageDelayTracker() *MessageDelayTracker {
+	t := new(MessageDelayTracker)
+	t.rules = make(map[byte]delayRule)
+	return t
+}
+
+// Set delays messages of the type by a random time between min and max.  A max of 0
+// clears the delay.  A min over max makes the delay fixed at min.
+func (t *MessageDelayTracker) Set(msgType byte, min, max time.Duration) {
+	if t == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if min < 0 {
+		min = 0
+	}
+	if max < min {
+		max = min
+	}
+	if max <= 0 {
+		delete(t.rules, msgType)
+		return
+	}
+	t.rules[msgType] = delayRule{min: min, max: max}
+}
+
+// DelayFor returns how long to hold a message of the type, 0 if it isn't delayed
+func (t *MessageDelayTracker) DelayFor(msgType byte) time.Duration {
+	if t == nil {
+		return 0
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	r, ok := t.rules[msgType]
+	if !ok {
+		return 0
+	}
+	if r.max == r.min {
+		return r.min
+	}
+	return r.min + time.Duration(rand.Int63n(int64(r.max-r.min)+1))
+}
+
+// Delays returns the delays set, by message type
+func (t *MessageDelayTracker) Delays() []interfaces.MessageDelay {
+	if t == nil {
+		return nil
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	var answer []interfaces.MessageDelay
+	for k, r := range t.rules {
+		answer = append(answer, interfaces.MessageDelay{
+			MessageType: k,
+			Name:        messages.MessageName(k),
+			MinMs:       int64(r.min / time.Millisecond),
+			MaxMs:       int64(r.max / time.Millisecond),
+		})
+	}
+	sort.Sort(messageDelaysByType(answer))
+	return answer
+}
+
+type messageDelaysByType []interfaces.MessageDelay
+
+func (a messageDelaysByType) Len() int           { return len(a) }
+func (a messageDelaysByType) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a messageDelaysByType) Less(i, j int) bool { return a[i].MessageType < a[j].MessageType }
+
+// SetMessageDelay delays messages of the type from peers by between min and max
+func (s *State) SetMessageDelay(msgType byte, min, max time.Duration) {
+	s.MessageDelays.Set(msgType, min, max)
+}
+
+// GetMessageDelays returns the delays injected into messages from peers
+func (s *State) GetMessageDelays() []interfaces.MessageDelay {
+	return s.MessageDelays.Delays()
+}
+
+// GetMessageDelay returns how long to hold a message of the type from a peer
+func (s *State) GetMessageDelay(msgType byte) time.Duration {
+	return s.MessageDelays.DelayFor(msgType)
+}
+
+// InjectMessage queues a message as though a peer had sent it, for black box tests of
+// consensus.  It passes the same replay checks and delays as a message from a peer, and
+// returns false if the replay checks drop it.
+func (s *State) InjectMessage(msg interfaces.IMsg) bool {
+	_, bv := s.Replay.Valid(constants.BLOCK_REPLAY, msg.GetRepeatHash().Fixed(), msg.GetTimestamp(), s.GetTimestamp())
+	if !bv || !s.Replay.IsTSValid_(constants.NETWORK_REPLAY, msg.GetRepeatHash().Fixed(), msg.GetTimestamp(), s.GetTimestamp()) {
+		return false
+	}
+	InjectedMessages.Inc()
+	if d := s.GetMessageDelay(msg.Type()); d > 0 {
+		time.AfterFunc(d, func() { s.QueuePeerMsg(msg) })
+	} else {
+		s.QueuePeerMsg(msg)
+	}
+	return true
+}
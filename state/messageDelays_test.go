@@ -0,0 +1,76 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/entryCreditBlock"
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
+	. "github.com/FactomProject/factomd/state"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestMessageDelayTracker(t *testing.T) {
+	mt := NewMessageDelayTracker()
+	if d := mt.DelayFor(constants.EOM_MSG); d != 0 {
+		t.Errorf("Expected no delay, got %v", d)
+	}
+
+	mt.Set(constants.EOM_MSG, 100*time.Millisecond, 100*time.Millisecond)
+	mt.Set(constants.ACK_MSG, 10*time.Millisecond, 20*time.Millisecond)
+	if d := mt.DelayFor(constants.EOM_MSG); d != 100*time.Millisecond {
+		t.Errorf("Expected a fixed delay of 100ms, got %v", d)
+	}
+	for i := 0; i < 100; i++ {
+		if d := mt.DelayFor(constants.ACK_MSG); d < 10*time.Millisecond || d > 20*time.Millisecond {
+			t.Fatalf("Delay %v out of range", d)
+		}
+	}
+
+	delays := mt.Delays()
+	if len(delays) != 2 || delays[0].MessageType != constants.EOM_MSG || delays[0].Name != "EOM" || delays[1].MaxMs != 20 {
+		t.Errorf("Unexpected delays %v", delays)
+	}
+
+	mt.Set(constants.EOM_MSG, 0, 0)
+	if d := mt.DelayFor(constants.EOM_MSG); d != 0 || len(mt.Delays()) != 1 {
+		t.Errorf("Expected the EOM delay to be cleared")
+	}
+
+	var nilTracker *MessageDelayTracker
+	nilTracker.Set(constants.EOM_MSG, time.Second, time.Second)
+	if nilTracker.DelayFor(constants.EOM_MSG) != 0 || nilTracker.Delays() != nil {
+		t.Errorf("Expected a nil tracker to delay nothing")
+	}
+}
+
+func TestInjectMessage(t *testing.T) {
+	s := testHelper.CreateEmptyTestState()
+	s.SigVerifier = nil
+
+	ce := entryCreditBlock.NewCommitEntry()
+	ts, _ := primitives.NewTimestampNow().MarshalBinary()
+	ce.MilliTime.UnmarshalBinary(ts)
+	ce.EntryHash = primitives.Sha([]byte("injected"))
+	ce.Credits = 1
+	testHelper.SignCommit(0, ce)
+	msg := new(messages.CommitEntryMsg)
+	msg.CommitEntry = ce
+
+	before := s.InMsgQueue().Length()
+	if !s.InjectMessage(msg) {
+		t.Fatal("Injected message was dropped")
+	}
+	if s.InMsgQueue().Length() != before+1 {
+		t.Error("Injected message was not queued")
+	}
+	if s.InjectMessage(msg) {
+		t.Error("Replayed message was queued")
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var traceLogger = packageLogger.WithFields(log.Fields{"subpack": "trace"})
+
+// The stages of consensus a traced message is logged at
+const (
+	TraceStageSubmitted   = "submitted"   // Accepted by the API
+	TraceStageProcessList = "processlist" // Acknowledged by a leader and added to a process list
+	TraceStageProcessed   = "processed"   // Processed into the block being built
+	TraceStageSaved       = "saved"       // The block holding it has been saved
+)
+
+// Traces are dropped if the message doesn't make it into a block in this time
+const MaxTraceAge = time.Hour
+
+type messageTrace struct {
+	trace     interfaces.TraceContext
+	submitted time.Time
+	dbheight  uint32
+	processed bool
+}
+
+// MessageTraceTracker holds the trace contexts of messages submitted with one, until the
+// block holding them is saved.
+type MessageTraceTracker struct {
+	mutex  sync.Mutex
+	count  int32 // Read without the mutex, so untraced messages cost next to nothing
+	traces map[[32]byte]*messageTrace
+}
+
+func NewMessageTraceTracker() *MessageTraceTracker {
+	t := new(MessageTraceTracker)
+	t.traces = make(map[[32]byte]*messageTrace)
+	return t
+}
+
+// Len returns the number of messages being traced
+func (t *MessageTraceTracker) Len() int {
+	if t == nil {
+		return 0
+	}
+	return int(atomic.LoadInt32(&t.count))
+}
+
+// Add starts tracing a message
+func (t *MessageTraceTracker) Add(nodeName string, msgHash interfaces.IHash, trace *interfaces.TraceContext) {
+	if t == nil || msgHash == nil || trace == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	for k, mt := range t.traces {
+		if time.Since(mt.submitted) > MaxTraceAge {
+			delete(t.traces, k)
+		}
+	}
+	mt := &messageTrace{trace: *trace, submitted: time.Now()}
+	t.traces[msgHash.Fixed()] = mt
+	atomic.StoreInt32(&t.count, int32(len(t.traces)))
+	mt.log(nodeName, msgHash, TraceStageSubmitted, nil)
+}
+
+// Stage logs a message reaching a stage, if it is being traced
+func (t *MessageTraceTracker) Stage(nodeName string, msgHash interfaces.IHash, stage string, dbheight uint32, fields log.Fields) {
+	if t.Len() == 0 || msgHash == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	mt := t.traces[msgHash.Fixed()]
+	if mt == nil {
+		return
+	}
+	mt.dbheight = dbheight
+	if stage == TraceStageProcessed {
+		mt.processed = true
+	}
+	mt.log(nodeName, msgHash, stage, fields)
+}
+
+// Saved logs every processed message in blocks up to dbheight as saved, and stops
+// tracing them
+func (t *MessageTraceTracker) Saved(nodeName string, dbheight uint32) {
+	if t.Len() == 0 {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	for k, mt := range t.traces {
+		if mt.processed && mt.dbheight <= dbheight {
+			mt.log(nodeName, primitives.NewHash(k[:]), TraceStageSaved, nil)
+			delete(t.traces, k)
+		}
+	}
+	atomic.StoreInt32(&t.count, int32(len(t.traces)))
+}
+
+// log writes one span of the message's journey.  Each stage is its own span, a child of
+// the application's span that submitted the message.
+func (mt *messageTrace) log(nodeName string, msgHash interfaces.IHash, stage string, fields log.Fields) {
+	span := primitives.NewSpanID()
+	traceLogger.WithFields(log.Fields{
+		"node-name":   nodeName,
+		"trace-id":    fmt.Sprintf("%x", mt.trace.TraceID),
+		"span-id":     fmt.Sprintf("%x", span),
+		"parent-id":   fmt.Sprintf("%x", mt.trace.ParentID),
+		"traceparent": mt.trace.TraceParent(span),
+		"stage":       stage,
+		"msghash":     msgHash.String(),
+		"dbheight":    mt.dbheight,
+		"elapsed-ms":  int64(time.Since(mt.submitted) / time.Millisecond),
+	}).WithFields(fields).Info("Trace " + stage)
+}
+
+// TraceMessage attaches the trace context an API submission came with to the message,
+// so its progress through consensus is logged against the application's trace
+func (s *State) TraceMessage(msgHash interfaces.IHash, trace *interfaces.TraceContext) {
+	s.MessageTraces.Add(s.FactomNodeName, msgHash, trace)
+}
@@ -0,0 +1,48 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/common/primitives"
+	. "github.com/FactomProject/factomd/state"
+)
+
+func TestMessageTraceTracker(t *testing.T) {
+	tr := NewMessageTraceTracker()
+	tc, err := primitives.ParseTraceParent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := primitives.Sha([]byte("a"))
+	b := primitives.Sha([]byte("b"))
+
+	tr.Add("test", a, tc)
+	tr.Add("test", b, tc)
+	tr.Add("test", primitives.Sha([]byte("c")), nil)
+	if tr.Len() != 2 {
+		t.Fatalf("Tracing %d messages, expected 2", tr.Len())
+	}
+
+	// Only messages that were processed into a saved block are done with
+	tr.Stage("test", a, TraceStageProcessList, 5, nil)
+	tr.Stage("test", a, TraceStageProcessed, 5, nil)
+	tr.Stage("test", b, TraceStageProcessList, 6, nil)
+	tr.Saved("test", 5)
+	if tr.Len() != 1 {
+		t.Errorf("Tracing %d messages after the save, expected 1", tr.Len())
+	}
+	tr.Stage("test", b, TraceStageProcessed, 6, nil)
+	tr.Saved("test", 6)
+	if tr.Len() != 0 {
+		t.Errorf("Tracing %d messages after the last save", tr.Len())
+	}
+
+	var none *MessageTraceTracker
+	none.Add("test", a, tc)
+	none.Stage("test", a, TraceStageProcessed, 1, nil)
+	none.Saved("test", 1)
+}
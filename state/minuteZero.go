@@ -0,0 +1,82 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+)
+
+// Submissions arriving at the start of a block, while the DBSigs are syncing, can't be
+// acked yet.  They go to holding, and often wait there past the end of the block for a
+// review of holding.  With the prioritize policy they are set aside instead, and put at the
+// front of the messages to review as soon as the DBSigs are done, so they make the block.
+
+// Policies for submissions arriving in minute 0 while the DBSigs are syncing
+const (
+	MinuteZeroImmediate  = "immediate"  // Executed as they come, as always
+	MinuteZeroPrioritize = "prioritize" // Held until the DBSigs are done, then reviewed first
+)
+
+// Most submissions set aside; past it they are executed as they come
+const maxMinuteZeroHeld = 5000
+
+// ParseMinuteZeroAdmission checks a minute 0 admission policy, empty being immediate
+func ParseMinuteZeroAdmission(policy string) (string, error) {
+	switch p := strings.ToLower(strings.TrimSpace(policy)); p {
+	case "", MinuteZeroImmediate:
+		return MinuteZeroImmediate, nil
+	case MinuteZeroPrioritize:
+		return p, nil
+	}
+	return "", fmt.Errorf("Unknown minute 0 admission policy %q, expected immediate or prioritize", policy)
+}
+
+// syncingDBSigs is true at the start of a block, until the DBSigs of the block are done
+func (s *State) syncingDBSigs() bool {
+	return s.CurrentMinute == 0 && (s.DBSig || (s.Syncing && !s.EOM))
+}
+
+// holdForMinuteZero sets a submission aside while the DBSigs are syncing, if the policy is
+// to prioritize them and we are a leader to ack them.  Returns true if it did.
+func (s *State) holdForMinuteZero(msg interfaces.IMsg) bool {
+	if s.MinuteZeroAdmission != MinuteZeroPrioritize || !s.Leader || !isSubmission(msg) || !s.syncingDBSigs() {
+		return false
+	}
+	if len(s.minuteZeroHeld) >= maxMinuteZeroHeld {
+		return false
+	}
+	s.minuteZeroHeld = append(s.minuteZeroHeld, msg)
+	MinuteZeroHeld.Set(float64(len(s.minuteZeroHeld)))
+	return true
+}
+
+// releaseMinuteZero puts the submissions set aside at the front of the messages to review,
+// once the DBSigs are done
+func (s *State) releaseMinuteZero() {
+	if len(s.minuteZeroHeld) == 0 || s.syncingDBSigs() {
+		return
+	}
+	TotalMinuteZeroReleased.Add(float64(len(s.minuteZeroHeld)))
+	TotalXReviewQueueInputs.Add(float64(len(s.minuteZeroHeld)))
+	s.XReview = append(s.minuteZeroHeld, s.XReview...)
+	s.minuteZeroHeld = nil
+	MinuteZeroHeld.Set(0)
+}
+
+// earliestBlock returns the first directory block a submission made now can appear in.
+// Those made as the last minute of a block ends go in the next, as do those made while the
+// DBSigs of a block are syncing, unless they are prioritized.
+func (s *State) earliestBlock() uint32 {
+	switch {
+	case s.CurrentMinute >= 9 && s.EOM:
+		return s.LLeaderHeight + 1
+	case s.syncingDBSigs() && s.MinuteZeroAdmission != MinuteZeroPrioritize:
+		return s.LLeaderHeight + 1
+	}
+	return s.LLeaderHeight
+}
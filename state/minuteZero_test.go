@@ -0,0 +1,27 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"testing"
+
+	. "github.com/FactomProject/factomd/state"
+)
+
+func TestParseMinuteZeroAdmission(t *testing.T) {
+	for policy, expected := range map[string]string{
+		"":             MinuteZeroImmediate,
+		"immediate":    MinuteZeroImmediate,
+		" Prioritize ": MinuteZeroPrioritize,
+		"prioritize":   MinuteZeroPrioritize,
+	} {
+		if p, err := ParseMinuteZeroAdmission(policy); err != nil || p != expected {
+			t.Errorf("Policy %q parsed as %q, %v; expected %q", policy, p, err, expected)
+		}
+	}
+	if _, err := ParseMinuteZeroAdmission("later"); err == nil {
+		t.Error("Expected an error for an unknown policy")
+	}
+}
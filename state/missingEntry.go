@@ -79,6 +79,8 @@ type MissingEntry struct {
 	EBHash    interfaces.IHash
 	EntryHash interfaces.IHash
 	DBHeight  uint32
+
+	backoff AskBackoff // Backs off the asks for the entry that go unanswered
 }
 
 var _ interfaces.BinaryMarshallable = (*MissingEntry)(nil)
@@ -0,0 +1,52 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"fmt"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/interfaces"
+)
+
+// GetNetworkParameters returns the consensus parameters in effect on this network
+func (s *State) GetNetworkParameters() interfaces.NetworkParameters {
+	var p interfaces.NetworkParameters
+	p.NetworkName = s.GetNetworkName()
+	p.NetworkID = fmt.Sprintf("%08x", s.GetNetworkID())
+	if id := s.GetNetworkBootStrapIdentity(); id != nil {
+		p.BootstrapIdentity = id.String()
+	}
+	if key := s.GetNetworkBootStrapKey(); key != nil {
+		p.BootstrapKey = key.String()
+	}
+	p.BlockTime = s.GetDirectoryBlockInSeconds()
+	p.MinutesPerBlock = 10
+	p.FactoshisPerEC = s.GetFactoshisPerEC()
+	p.FaultTimeout = s.GetFaultTimeout()
+	p.FaultWait = s.GetFaultWait()
+	limits := constants.NetworkAuthorityLimits(s.GetNetworkID(), s.GetLLeaderHeight())
+	p.AuthorityChangesPerBlock = limits.ChangesPerBlock
+	p.AuthorityMinIdentityAge = limits.MinIdentityAge
+
+	// Checkpoints and late activations only apply to mainnet; everything else has had the
+	// current rules since the first block
+	var overspend uint32
+	if s.GetNetworkID() == constants.MAIN_NETWORK_ID {
+		p.Checkpoints, p.HighestCheckpoint = constants.CheckPointCount()
+		overspend = constants.EC_OVERSPEND_HEIGHT
+	}
+	p.ActivationHeights = []interfaces.ActivationHeight{
+		{Name: "ec-overspend", Height: overspend, Description: "Commits may not take an EC balance below zero"},
+	}
+	for _, a := range constants.Activations {
+		p.ActivationHeights = append(p.ActivationHeights, interfaces.ActivationHeight{
+			Name:        a.Name,
+			Height:      constants.ActivationHeight(a.Name, s.GetNetworkID()),
+			Description: a.Description,
+		})
+	}
+	return p
+}
@@ -0,0 +1,42 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestGetNetworkParameters(t *testing.T) {
+	s := testHelper.CreateEmptyTestState()
+
+	p := s.GetNetworkParameters()
+	if p.NetworkName != "LOCAL" || p.NetworkID != "fa92e5a4" {
+		t.Errorf("Unexpected network %s %s", p.NetworkName, p.NetworkID)
+	}
+	if p.BlockTime != s.GetDirectoryBlockInSeconds() || p.FactoshisPerEC != s.GetFactoshisPerEC() {
+		t.Errorf("Unexpected block time %d or EC price %d", p.BlockTime, p.FactoshisPerEC)
+	}
+	if p.Checkpoints != 0 || len(p.ActivationHeights) == 0 || p.ActivationHeights[0].Height != 0 {
+		t.Errorf("Mainnet rules applied to a local network: %+v", p)
+	}
+
+	s.NetworkNumber = constants.NETWORK_MAIN
+	p = s.GetNetworkParameters()
+	if p.Checkpoints != len(constants.CheckPoints) || p.HighestCheckpoint == 0 {
+		t.Errorf("Unexpected checkpoints %d %d", p.Checkpoints, p.HighestCheckpoint)
+	}
+	if p.ActivationHeights[0].Height != constants.EC_OVERSPEND_HEIGHT {
+		t.Errorf("Unexpected activation height %d", p.ActivationHeights[0].Height)
+	}
+	// Changes old nodes would fork on wait for a release to schedule them on mainnet
+	for _, a := range p.ActivationHeights[1:] {
+		if a.Height != constants.ActivationUnscheduled {
+			t.Errorf("Change %s activates on mainnet at %d before it is scheduled", a.Name, a.Height)
+		}
+	}
+}
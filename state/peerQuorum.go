@@ -0,0 +1,136 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// How long a peer's height counts after the last consensus message it passed us
+var PeerQuorumWindow = 2 * time.Minute
+
+// How far a peer's height may be from ours and still agree with it; leaders are a block
+// ahead of the followers for the first minute of a block.
+const PeerQuorumTolerance = 1
+
+// PeerQuorum holds a node off acting as a leader, or answering missing message requests,
+// after it boots until enough of its peers report the height it is at.  A rebooted
+// authority that only sees part of the network otherwise acts on a view the rest of the
+// network doesn't share.  Once the threshold is met it stays met; it only guards startup.
+type PeerQuorum struct {
+	mutex     sync.Mutex
+	threshold int
+	peers     map[string]peerHeight
+	met       bool
+}
+
+type peerHeight struct {
+	height uint32
+	seen   time.Time
+}
+
+// NewPeerQuorum waits for threshold peers; 0 doesn't wait at all
+func NewPeerQuorum(threshold int) *PeerQuorum {
+	q := new(PeerQuorum)
+	q.threshold = threshold
+	q.peers = make(map[string]peerHeight)
+	q.met = threshold <= 0
+	return q
+}
+
+// Observe records the height a peer reported
+func (q *PeerQuorum) Observe(peer string, height uint32, now time.Time) {
+	if q == nil {
+		return
+	}
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	if q.met {
+		return
+	}
+	q.peers[peer] = peerHeight{height: height, seen: now}
+}
+
+// Agreeing returns how many peers reported a height within the tolerance of ours lately
+func (q *PeerQuorum) Agreeing(height uint32, now time.Time) int {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return q.agreeing(height, now)
+}
+
+func (q *PeerQuorum) agreeing(height uint32, now time.Time) int {
+	cnt := 0
+	for peer, p := range q.peers {
+		if now.Sub(p.seen) > PeerQuorumWindow {
+			delete(q.peers, peer)
+			continue
+		}
+		if p.height+PeerQuorumTolerance >= height && p.height <= height+PeerQuorumTolerance {
+			cnt++
+		}
+	}
+	return cnt
+}
+
+// Check returns true once enough peers agree with our height.  Like Met, it is always
+// true on a nil tracker, so states that were never booted (i.e. in tests) don't wait.
+func (q *PeerQuorum) Check(height uint32, now time.Time) bool {
+	if q == nil {
+		return true
+	}
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	if q.met {
+		return true
+	}
+	cnt := q.agreeing(height, now)
+	PeerQuorumAgreeing.Set(float64(cnt))
+	if cnt < q.threshold {
+		return false
+	}
+	q.met = true
+	q.peers = nil
+	packageLogger.WithFields(log.Fields{"subpack": "peer-quorum", "dbheight": height, "peers": cnt}).Infof("%d of %d peers agree with our height, participating in consensus", cnt, q.threshold)
+	return true
+}
+
+// Met returns true once the threshold has been met
+func (q *PeerQuorum) Met() bool {
+	if q == nil {
+		return true
+	}
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return q.met
+}
+
+// ObservePeerHeight records the height of a consensus message a peer passed us.  A peer
+// relaying the messages of leaders at our height is connected to the same network we
+// are, whether or not it is an authority itself.
+func (s *State) ObservePeerHeight(peer string, msg interfaces.IMsg) {
+	if peer == "" || s.PeerQuorum.Met() {
+		return
+	}
+	var height uint32
+	switch m := msg.(type) {
+	case *messages.Heartbeat:
+		height = m.DBHeight
+	case *messages.Ack:
+		height = m.DBHeight
+	case *messages.EOM:
+		height = m.DBHeight
+	case *messages.DirectoryBlockSignature:
+		height = m.DBHeight
+	default:
+		return
+	}
+	s.PeerQuorum.Observe(peer, height, time.Now())
+}
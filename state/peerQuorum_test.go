@@ -0,0 +1,77 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/FactomProject/factomd/common/messages"
+	. "github.com/FactomProject/factomd/state"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestPeerQuorum(t *testing.T) {
+	now := time.Now()
+
+	// No threshold, nothing to wait on
+	if q := NewPeerQuorum(0); !q.Met() || !q.Check(10, now) {
+		t.Error("A threshold of 0 should always be met")
+	}
+
+	q := NewPeerQuorum(2)
+	if q.Check(10, now) {
+		t.Error("Met with no peers")
+	}
+
+	// One peer at our height, one far behind, one seen too long ago
+	q.Observe("a", 10, now)
+	q.Observe("b", 3, now)
+	q.Observe("c", 10, now.Add(-2*PeerQuorumWindow))
+	if n := q.Agreeing(10, now); n != 1 {
+		t.Errorf("Expected 1 agreeing peer, got %d", n)
+	}
+	if q.Check(10, now) || q.Met() {
+		t.Error("Met with only one agreeing peer")
+	}
+
+	// A peer a block ahead still agrees, and a peer reporting again counts once
+	q.Observe("b", 11, now)
+	q.Observe("a", 10, now)
+	if n := q.Agreeing(10, now); n != 2 {
+		t.Errorf("Expected 2 agreeing peers, got %d", n)
+	}
+	if !q.Check(10, now) || !q.Met() {
+		t.Error("Not met with two agreeing peers")
+	}
+
+	// Once met it stays met
+	if !q.Check(500, now.Add(time.Hour)) {
+		t.Error("Met threshold was lost")
+	}
+}
+
+func TestObservePeerHeight(t *testing.T) {
+	s := testHelper.CreateEmptyTestState()
+	s.PeerQuorum = NewPeerQuorum(1)
+
+	// Only consensus messages carry a height we go by
+	s.ObservePeerHeight("a", new(messages.CommitEntryMsg))
+	if s.PeerQuorum.Check(5, time.Now()) {
+		t.Error("Met on a message with no height")
+	}
+
+	hb := new(messages.Heartbeat)
+	hb.DBHeight = 5
+	s.ObservePeerHeight("", hb)
+	if s.PeerQuorum.Check(5, time.Now()) {
+		t.Error("Met on a message from no peer")
+	}
+
+	s.ObservePeerHeight("a", hb)
+	if !s.PeerQuorum.Check(5, time.Now()) {
+		t.Error("Not met on a heartbeat at our height")
+	}
+}
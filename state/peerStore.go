@@ -0,0 +1,49 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+)
+
+// Where the peers the p2p network knows are kept in the database
+var (
+	PEERS       = []byte("Peers")
+	PEERS_KNOWN = []byte("Known")
+)
+
+// PeerStore keeps the peers the p2p network knows in the database of the node, with
+// their quality scores, when they were last seen and their dial backoff, so the node
+// rejoins the network from them when it restarts rather than from the seed.
+type PeerStore struct {
+	state *State
+}
+
+func NewPeerStore(s *State) *PeerStore {
+	return &PeerStore{state: s}
+}
+
+// LoadPeers returns the saved peers, nil if none were saved
+func (ps *PeerStore) LoadPeers() ([]byte, error) {
+	overlay, ok := ps.state.DB.(*databaseOverlay.Overlay)
+	if !ok {
+		return nil, nil
+	}
+	saved, err := overlay.Get(PEERS, PEERS_KNOWN, new(primitives.ByteSlice))
+	if err != nil || saved == nil {
+		return nil, err
+	}
+	return saved.(*primitives.ByteSlice).Bytes, nil
+}
+
+// SavePeers replaces the saved peers
+func (ps *PeerStore) SavePeers(data []byte) error {
+	overlay, ok := ps.state.DB.(*databaseOverlay.Overlay)
+	if !ok {
+		return nil
+	}
+	return overlay.Put(PEERS, PEERS_KNOWN, &primitives.ByteSlice{Bytes: data})
+}
@@ -38,6 +38,7 @@ func PrintState(state *State) {
 	str = fmt.Sprintf("%s %35s = %+v\n", str, "LogPath", state.LogPath)
 	str = fmt.Sprintf("%s %35s = %+v\n", str, "LdbPath", state.LdbPath)
 	str = fmt.Sprintf("%s %35s = %+v\n", str, "BoltDBPath", state.BoltDBPath)
+	str = fmt.Sprintf("%s %35s = %+v\n", str, "BadgerPath", state.BadgerPath)
 	str = fmt.Sprintf("%s %35s = %+v\n", str, "LogLevel", state.LogLevel)
 	str = fmt.Sprintf("%s %35s = %+v\n", str, "ConsoleLogLevel", state.ConsoleLogLevel)
 	str = fmt.Sprintf("%s %35s = %+v\n", str, "NodeMode", state.NodeMode)
@@ -36,6 +36,7 @@ type Request struct {
 	wait       int64  // How long to wait before we actually request
 	sent       int64  // Last time sent (zero means none have been sent)
 	requestCnt int
+	backoff    AskBackoff // Backs off the asks that go unanswered
 }
 
 var _ interfaces.IRequest = (*Request)(nil)
@@ -60,10 +61,8 @@ type ProcessList struct {
 	DBHeight uint32 // The directory block height for these lists
 
 	// Temporary balances from updating transactions in real time.
-	FactoidBalancesT      map[[32]byte]int64
-	FactoidBalancesTMutex sync.Mutex
-	ECBalancesT           map[[32]byte]int64
-	ECBalancesTMutex      sync.Mutex
+	FactoidBalancesT *BalanceStore
+	ECBalancesT      *BalanceStore
 
 	State        *State
 	VMs          []*VM       // Process list for each server (up to 32)
@@ -89,10 +88,17 @@ type ProcessList struct {
 	// Entry Blocks added within 10 minutes (follower and leader)
 	NewEBlocks     map[[32]byte]interfaces.IEntryBlock
 	neweblockslock *sync.Mutex
+	NewChains      int // How many of them start new chains
 
 	NewEntriesMutex sync.RWMutex
 	NewEntries      map[[32]byte]interfaces.IEntry
 
+	// When fed servers were promoted (Unix seconds), for the new leader's grace period
+	Promoted map[[32]byte]int64
+
+	// Servers added or removed in this block, against the ChangesPerBlock of the network
+	AuthorityChanges int
+
 	// State information about the directory block while it is under construction.  We may
 	// have to start building the next block while still building the previous block.
 	AdminBlock       interfaces.IAdminBlock
@@ -105,10 +111,13 @@ type ProcessList struct {
 	FedServers   []interfaces.IServer // List of Federated Servers
 
 	// AmINegotiator is just used for displaying an "N" next to a node
-	// that is the assigned negotiator for a particular processList
-	// height
+	// that is the assigned negotiator, or that volunteered or accepted
+	// in an election, at a particular processList height
 	AmINegotiator bool
 
+	// Elections to replace faulted leaders, by VM
+	Elections map[int]*Election
+
 	// DB Sigs
 	DBSignatures     []DBSig
 	DBSigAlreadySent bool
@@ -147,18 +156,13 @@ type VM struct {
 	Signed      bool  // We have signed the previous block.
 	WhenFaulted int64 // WhenFaulted is a timestamp of when this VM was faulted
 	// vm.WhenFaulted serves as a bool flag (if > 0, the vm is currently considered faulted)
-	FaultFlag int // FaultFlag tracks what the VM was faulted for (0 = EOM missing, 1 = negotiation issue)
+	FaultFlag int // FaultFlag tracks what the VM was faulted for (0 = EOM missing, 1 = negotiation issue, 2 = stepped down)
 }
 
 func (p *ProcessList) Clear() {
 	return
 	//p.State.AddStatus(fmt.Sprintf("PROCESSLIST.Clear dbht %d", p.DBHeight))
-	p.FactoidBalancesTMutex.Lock()
-	defer p.FactoidBalancesTMutex.Unlock()
 	p.FactoidBalancesT = nil
-
-	p.ECBalancesTMutex.Lock()
-	defer p.ECBalancesTMutex.Unlock()
 	p.ECBalancesT = nil
 
 	p.oldmsgslock.Lock()
@@ -441,6 +445,7 @@ func (p *ProcessList) AddFedServer(identityChainID interfaces.IHash) int {
 	if auditFound {
 		//p.State.AddStatus(fmt.Sprintf("ProcessList.AddFedServer Server %x was an audit server at height %d", identityChainID.Bytes()[2:6], p.DBHeight))
 		p.RemoveAuditServerHash(identityChainID)
+		p.SetPromoted(identityChainID)
 	}
 	p.FedServers = append(p.FedServers, nil)
 	copy(p.FedServers[i+1:], p.FedServers[i:])
@@ -638,7 +643,7 @@ func (p *ProcessList) Ask(vmIndex int, height int, waitSeconds int64, tag int) i
 		return 0
 	}
 
-	if now-r.sent >= waitSeconds*1000+500 && p.State.inMsgQueue.Length() < constants.INMSGQUEUE_MED {
+	if now-r.sent >= waitSeconds*1000+500 && r.backoff.Due(now) && p.State.inMsgQueue.Length() < constants.INMSGQUEUE_MED {
 		missingMsgRequest := messages.NewMissingMsg(p.State, r.vmIndex, p.DBHeight, r.vmheight)
 
 		// The System (handling full faults) is a special VM.  Let's guess it first.
@@ -651,6 +656,7 @@ func (p *ProcessList) Ask(vmIndex int, height int, waitSeconds int64, tag int) i
 		for k := range p.Requests {
 			r2 := p.Requests[k]
 			if r2.vmIndex == vmIndex && int(r2.vmheight) < vm.Height {
+				r2.backoff.Answered(AskMissingMsg, now)
 				delete(p.Requests, k)
 			}
 		}
@@ -671,9 +677,11 @@ func (p *ProcessList) Ask(vmIndex int, height int, waitSeconds int64, tag int) i
 
 		missingMsgRequest.SendOut(p.State, missingMsgRequest)
 		p.State.MissingRequestAskCnt++
+		p.State.LeaderHealth.MissingAsked()
 
 		r.sent = now
 		r.requestCnt++
+		r.backoff.Asked(AskMissingMsg, p.State.GetAskPolicy(AskMissingMsg), now)
 	}
 
 	return r.requestCnt
@@ -725,7 +733,10 @@ func (p *ProcessList) Process(state *State) (progress bool) {
 		vm := p.VMs[i]
 
 		if !p.State.Syncing {
-			markNoFault(p, i)
+			// A leader that stepped down stays faulted until it is replaced
+			if vm.FaultFlag != faultStepDown {
+				markNoFault(p, i)
+			}
 		} else {
 			if !vm.Synced {
 				if vm.WhenFaulted == 0 {
@@ -738,7 +749,9 @@ func (p *ProcessList) Process(state *State) (progress bool) {
 			}
 		}
 
-		FaultCheck(p)
+		if !p.State.electionsActive(p.DBHeight) {
+			FaultCheck(p)
+		}
 
 		if vm.Height == len(vm.List) && p.State.Syncing && !vm.Synced {
 			// means that we are missing an EOM
@@ -831,8 +844,13 @@ func (p *ProcessList) Process(state *State) (progress bool) {
 					p.State.Replay.IsTSValid_(constants.INTERNAL_REPLAY, msg.GetMsgHash().Fixed(), msg.GetTimestamp(), now)
 
 					ack := vm.ListAck[j]
+					if d, ok := secondsSince(ack.Timestamp, now); ok {
+						msgtype, vmindex := latencyLabels(msg, i)
+						AckToProcessListVec.WithLabelValues(msgtype, vmindex).Observe(d)
+					}
 					delete(p.State.Acks, ack.GetMsgHash().Fixed())
 					delete(p.State.Holding, msg.GetMsgHash().Fixed())
+					p.State.MessageTraces.Stage(p.State.FactomNodeName, msg.GetMsgHash(), TraceStageProcessed, p.DBHeight, nil)
 
 				} else {
 					//p.State.AddStatus(fmt.Sprintf("processList.Process(): Could not process entry dbht: %d VM: %d  msg: [[%s]]", p.DBHeight, i, msg.String()))
@@ -845,6 +863,9 @@ func (p *ProcessList) Process(state *State) (progress bool) {
 			}
 		}
 	}
+	if p.State.electionsActive(p.DBHeight) {
+		ElectionCheck(p)
+	}
 	return
 }
 
@@ -861,21 +882,21 @@ func (p *ProcessList) AddToSystemList(m interfaces.IMsg) bool {
 		return false // Should never happen;  Don't pass junk to be added to the System List
 	}
 
+	// Once elections are in effect only a complete FullFault goes in: the votes of a
+	// majority of the leaders, and the pledge of the audit server replacing the faulted one.
+	// Before, the pings of a negotiation under way go in too.
+	elections := p.State.electionsActive(fullFault.DBHeight)
+	if elections && (fullFault.ClearFault || !fullFault.HasEnoughSigs(p.State) || !p.State.pledgedByAudit(fullFault)) {
+		return false
+	}
+
 	// If we have already processed past this fault, just ignore.
 	if p.System.Height > int(fullFault.SystemHeight) {
-		//p.State.AddStatus(fmt.Sprintf("FULL FAULT AddToSystemList Fail (System.Height > fullFault.SystemHeight) (%d > %d) : %s",
-		//	p.System.Height,
-		//	int(fullFault.SystemHeight),
-		//	fullFault.String()))
 		return false
 	}
 
 	// If the fault is in the future, hold it.
 	if p.System.Height < int(fullFault.SystemHeight) {
-		//p.State.AddStatus(fmt.Sprintf("FULL FAULT AddToSystemList Holding (System.Height(%d) < fullFault.SystemHeight(%d)) : %s",
-		//	p.System.Height,
-		//	int(fullFault.SystemHeight),
-		//	fullFault.String()))
 		p.State.Holding[m.GetMsgHash().Fixed()] = m
 		return false
 	}
@@ -884,55 +905,54 @@ func (p *ProcessList) AddToSystemList(m interfaces.IMsg) bool {
 	if len(p.System.List) <= p.System.Height {
 		// Nothing in our list a this slot yet, so insert this FullFault message
 		p.System.List = append(p.System.List, fullFault)
-		//p.State.AddStatus(fmt.Sprintf("FULL FAULT AddToSystemList Success (append) : %s",
-		//	fullFault.String()))
 		return true
 	}
 
+	existingSystemFault, _ := p.System.List[p.System.Height].(*messages.FullServerFault)
+	if !elections {
+		return p.replaceNegotiatedFault(existingSystemFault, fullFault)
+	}
+
+	// Elections decided for the same slot; every node keeps the later one, which a majority
+	// of the leaders moved on to
+	if existingSystemFault.GetCoreHash().IsSameAs(fullFault.GetCoreHash()) {
+		return false
+	}
+	if fullFault.Timestamp.GetTimeMilli() <= existingSystemFault.Timestamp.GetTimeMilli() {
+		return false
+	}
+
+	p.System.List[p.System.Height] = fullFault
+	return true
+}
+
+// replaceNegotiatedFault puts a FullFault of the fault negotiation in the System list in
+// place of the one already at its height, if it takes priority
+func (p *ProcessList) replaceNegotiatedFault(existingSystemFault, fullFault *messages.FullServerFault) bool {
 	// Something is in our SystemList at this height;
 	// We will prioritize the FullFault with the highest VMIndex
-	existingSystemFault, _ := p.System.List[p.System.Height].(*messages.FullServerFault)
 	if existingSystemFault.GetHash().IsSameAs(fullFault.GetHash()) {
 		if p.VMs[existingSystemFault.VMIndex].WhenFaulted > 0 {
-			//p.State.AddStatus(fmt.Sprintf("FULL FAULT AddToSystemList Fail (already have) : %s",
-			//	fullFault.String()))
 			return false
 		}
 	}
 
 	if existingSystemFault.HasEnoughSigs(p.State) && p.State.pledgedByAudit(existingSystemFault) {
-		//p.State.AddStatus(fmt.Sprintf("FULL FAULT AddToSystemList Fail (existingFault is complete) : %s",
-		//	existingSystemFault.String()))
 		return false
 	}
 
 	if fullFault.Priority(p.State) < existingSystemFault.Priority(p.State) {
-		//p.State.AddStatus(fmt.Sprintf("FULL FAULT AddToSystemList Fail (priority %d < %d) :: Exist: %s /// New: %s",
-		//	fullFault.Priority(p.State),
-		//	existingSystemFault.Priority(p.State),
-		//	existingSystemFault.String(),
-		//	fullFault.String()))
 		return false
 	}
 
 	if existingSystemFault.SigTally(p.State) > fullFault.SigTally(p.State) {
 		if fullFault.GetCoreHash().IsSameAs(existingSystemFault.GetCoreHash()) {
-			//p.State.AddStatus(fmt.Sprintf("FULL FAULT AddToSystemList Fail (less sigs than existingFault's) (%d > %d) : %s",
-			//	existingSystemFault.SigTally(p.State),
-			//	fullFault.SigTally(p.State),
-			//	fullFault.String()))
 			return false
 		}
 	}
-	//p.State.regularFullFaultExecution(fullFault, p)
 
 	p.System.List[p.System.Height] = fullFault
-
-	//p.State.AddStatus(fmt.Sprintf("FULL FAULT AddToSystemList Success (create) : %s sigs:%d",
-	//	fullFault.String(), fullFault.SigTally(p.State)))
-
 	return true
-
 }
 
 func (p *ProcessList) AddToProcessList(ack *messages.Ack, m interfaces.IMsg) {
@@ -1022,6 +1042,7 @@ func (p *ProcessList) AddToProcessList(ack *messages.Ack, m interfaces.IMsg) {
 	TotalAcksOutputs.Inc()
 	delete(p.State.Acks, m.GetMsgHash().Fixed())
 	delete(p.State.Holding, m.GetMsgHash().Fixed())
+	p.State.leftHolding(m, ack.VMIndex)
 
 	// Both the ack and the message hash to the same GetHash()
 	m.SetLocal(false)
@@ -1029,7 +1050,7 @@ func (p *ProcessList) AddToProcessList(ack *messages.Ack, m interfaces.IMsg) {
 	ack.SetPeer2Peer(false)
 	m.SetPeer2Peer(false)
 
-	ack.SendOut(p.State, ack)
+	p.State.sendAck(ack, m)
 	m.SendOut(p.State, m)
 
 	for len(vm.List) <= int(ack.Height) {
@@ -1043,6 +1064,8 @@ func (p *ProcessList) AddToProcessList(ack *messages.Ack, m interfaces.IMsg) {
 	p.OldAcks[m.GetMsgHash().Fixed()] = ack
 
 	plLogger.WithFields(log.Fields{"func": "AddToProcessList", "node-name": p.State.GetFactomNodeName(), "plheight": ack.Height, "dbheight": p.DBHeight}).WithFields(m.LogFields()).Info("Add To Process List")
+	p.State.MessageTraces.Stage(p.State.FactomNodeName, m.GetMsgHash(), TraceStageProcessList, p.DBHeight,
+		log.Fields{"vm": ack.VMIndex, "plheight": ack.Height, "leader": ack.LeaderChainID.String()})
 }
 
 func (p *ProcessList) ContainsDBSig(serverID interfaces.IHash) bool {
@@ -1157,8 +1180,8 @@ func (p *ProcessList) Reset() bool {
 	p.Requests = make(map[[32]byte]*Request)
 	//pl.Requests = make(map[[20]byte]*Request)
 
-	p.FactoidBalancesT = map[[32]byte]int64{}
-	p.ECBalancesT = map[[32]byte]int64{}
+	p.FactoidBalancesT = NewBalanceStore()
+	p.ECBalancesT = NewBalanceStore()
 
 	p.FedServers = append(p.FedServers[:0], previous.FedServers...)
 	p.AuditServers = append(p.AuditServers[:0], previous.AuditServers...)
@@ -1182,6 +1205,7 @@ func (p *ProcessList) Reset() bool {
 	p.NewEntries = make(map[[32]byte]interfaces.IEntry)
 
 	p.SetAmINegotiator(false)
+	p.Elections = make(map[int]*Election)
 
 	p.DBSignatures = make([]DBSig, 0)
 
@@ -1265,8 +1289,8 @@ func NewProcessList(state interfaces.IState, previous *ProcessList, dbheight uin
 	pl.Requests = make(map[[32]byte]*Request)
 	//pl.Requests = make(map[[20]byte]*Request)
 
-	pl.FactoidBalancesT = map[[32]byte]int64{}
-	pl.ECBalancesT = map[[32]byte]int64{}
+	pl.FactoidBalancesT = NewBalanceStore()
+	pl.ECBalancesT = NewBalanceStore()
 
 	if previous != nil {
 		pl.FedServers = append(pl.FedServers, previous.FedServers...)
@@ -1309,6 +1333,13 @@ func NewProcessList(state interfaces.IState, previous *ProcessList, dbheight uin
 	pl.NewEBlocks = make(map[[32]byte]interfaces.IEntryBlock)
 	pl.neweblockslock = new(sync.Mutex)
 	pl.NewEntries = make(map[[32]byte]interfaces.IEntry)
+	pl.Promoted = make(map[[32]byte]int64)
+	pl.Elections = make(map[int]*Election)
+	if previous != nil {
+		for k, v := range previous.Promoted {
+			pl.Promoted[k] = v
+		}
+	}
 
 	pl.DBSignatures = make([]DBSig, 0)
 
@@ -0,0 +1,173 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/primitives"
+	log "github.com/sirupsen/logrus"
+)
+
+var proofPackLogger = packageLogger.WithFields(log.Fields{"subpack": "proof-pack"})
+
+// A new node has to check the signatures of every directory block from the genesis block
+// on, and fetch every entry as it goes.  A proof pack lets it trust the chain up to a
+// recent height instead: it holds the KeyMR of a directory block every few blocks, each
+// signed by a majority of the federated servers of its time, and the changes to the
+// federated servers along the way, signed by the servers before them.  The first servers
+// are vouched for by the network's bootstrap identity, so the pack is checked back to
+// the genesis authority, and no one else has to be trusted.
+//
+// Once checked, the KeyMRs are added to the checkpoints, so blocks that contradict them
+// are refused, and a checkpointed directory block state is taken without counting its
+// signatures.  The entries of the blocks the pack covers are only asked for once the
+// node has saved the blocks up to the top of the pack.
+
+// ProofPack is a proof pack, as shipped with a release or supplied by the operator
+type ProofPack struct {
+	Network  string            `json:"network"`
+	Interval uint32            `json:"interval"` // Blocks between headers
+	Headers  []ProofPackHeader `json:"headers"`  // In order of height
+}
+
+// ProofPackHeader is the KeyMR of a directory block, signed by the federated servers
+type ProofPackHeader struct {
+	Height      uint32                `json:"height"`
+	KeyMR       string                `json:"keymr"`
+	Authorities []ProofPackAuthority  `json:"authorities,omitempty"` // The federated servers from here on, if they changed
+	Signatures  []CheckpointSignature `json:"signatures"`            // By the federated servers before any change
+}
+
+// ProofPackAuthority is a federated server, and the key it signs with
+type ProofPackAuthority struct {
+	Identity   string `json:"identity"`   // Chain ID of the server's identity
+	SigningKey string `json:"signingkey"` // Hex of the public key
+}
+
+// SigningData returns what the signatures of a header sign: the network, the height and
+// KeyMR, and any change of the federated servers, a line each
+func (h *ProofPackHeader) SigningData(network string) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n%d %s\n", strings.ToUpper(network), h.Height, strings.ToLower(h.KeyMR))
+	for _, a := range h.Authorities {
+		fmt.Fprintf(&b, "%s %s\n", strings.ToLower(a.Identity), strings.ToLower(a.SigningKey))
+	}
+	return []byte(b.String())
+}
+
+// proofPackSigners returns the signing keys of the federated servers, by identity
+func proofPackSigners(authorities []ProofPackAuthority) (map[string]*primitives.PublicKey, error) {
+	signers := make(map[string]*primitives.PublicKey)
+	for _, a := range authorities {
+		raw, err := hex.DecodeString(a.SigningKey)
+		if err != nil || len(raw) != 32 {
+			return nil, fmt.Errorf("bad signing key %q of %s", a.SigningKey, a.Identity)
+		}
+		key := new(primitives.PublicKey)
+		copy(key[:], raw)
+		signers[strings.ToLower(a.Identity)] = key
+	}
+	return signers, nil
+}
+
+// VerifyProofPack checks the headers of a pack back to the bootstrap identity of the
+// network, and returns the KeyMRs they vouch for by height
+func VerifyProofPack(pack *ProofPack, network string, bootstrapIdentity string, bootstrapKey *primitives.PublicKey) (map[uint32]string, error) {
+	if !strings.EqualFold(pack.Network, network) {
+		return nil, fmt.Errorf("proof pack is for the %s network, not %s", pack.Network, network)
+	}
+	if len(pack.Headers) == 0 {
+		return nil, fmt.Errorf("proof pack has no headers")
+	}
+
+	signers := map[string]*primitives.PublicKey{strings.ToLower(bootstrapIdentity): bootstrapKey}
+	points := make(map[uint32]string, len(pack.Headers))
+	for i, h := range pack.Headers {
+		if i > 0 && h.Height <= pack.Headers[i-1].Height {
+			return nil, fmt.Errorf("header at %d is out of order", h.Height)
+		}
+		if b, err := hex.DecodeString(h.KeyMR); err != nil || len(b) != 32 {
+			return nil, fmt.Errorf("bad KeyMR %q at %d", h.KeyMR, h.Height)
+		}
+		if i == 0 && len(h.Authorities) == 0 {
+			return nil, fmt.Errorf("first header doesn't name the federated servers")
+		}
+		if err := verifyCheckpointSignatures(h.SigningData(pack.Network), h.Signatures, signers, len(signers)/2+1); err != nil {
+			return nil, fmt.Errorf("header at %d: %v", h.Height, err)
+		}
+		if known, ok := constants.GetCheckPoint(h.Height); ok && known != strings.ToLower(h.KeyMR) {
+			return nil, fmt.Errorf("header at %d contradicts the checkpoint", h.Height)
+		}
+		points[h.Height] = strings.ToLower(h.KeyMR)
+
+		if len(h.Authorities) > 0 {
+			next, err := proofPackSigners(h.Authorities)
+			if err != nil {
+				return nil, fmt.Errorf("header at %d: %v", h.Height, err)
+			}
+			signers = next
+		}
+	}
+	return points, nil
+}
+
+// ReadProofPack reads a proof pack from a file
+func ReadProofPack(filename string) (*ProofPack, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	pack := new(ProofPack)
+	if err := json.Unmarshal(data, pack); err != nil {
+		return nil, err
+	}
+	return pack, nil
+}
+
+// loadProofPack checks the proof pack of the network, if there is one, and adds the
+// KeyMRs it vouches for to the checkpoints.  A pack that doesn't check out is left out,
+// and the node syncs without it.
+func (s *State) loadProofPack() {
+	if s.ProofPackFile == "" {
+		return
+	}
+	if _, err := os.Stat(s.ProofPackFile); os.IsNotExist(err) {
+		return
+	}
+	logger := proofPackLogger.WithField("file", s.ProofPackFile)
+	pack, err := ReadProofPack(s.ProofPackFile)
+	if err != nil {
+		logger.Errorf("Unable to read the proof pack: %v", err)
+		return
+	}
+
+	key := new(primitives.PublicKey)
+	copy(key[:], s.GetNetworkBootStrapKey().Bytes())
+	points, err := VerifyProofPack(pack, s.Network, s.GetNetworkBootStrapIdentity().String(), key)
+	if err != nil {
+		logger.Errorf("Refused the proof pack: %v", err)
+		return
+	}
+	if _, err := constants.AddCheckPoints(points); err != nil {
+		logger.Errorf("Refused the proof pack: %v", err)
+		return
+	}
+	s.ProofPackHeight = pack.Headers[len(pack.Headers)-1].Height
+	ProofPackHeight.Set(float64(s.ProofPackHeight))
+	logger.WithField("headers", len(points)).Infof("Checked the proof pack up to %d", s.ProofPackHeight)
+}
+
+// deferEntries returns true while the node is below the top of its proof pack, so the
+// entries of the blocks wait until the headers are followed
+func (s *State) deferEntries() bool {
+	return s.ProofPackHeight > 0 && s.GetHighestSavedBlk() < s.ProofPackHeight
+}
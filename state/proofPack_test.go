@@ -0,0 +1,66 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/FactomProject/factomd/common/primitives"
+	. "github.com/FactomProject/factomd/state"
+)
+
+func TestVerifyProofPack(t *testing.T) {
+	bootstrap := primitives.RandomPrivateKey()
+	feds := []*primitives.PrivateKey{primitives.RandomPrivateKey(), primitives.RandomPrivateKey(), primitives.RandomPrivateKey()}
+	ids := []string{"01", "02", "03"}
+
+	var authorities []ProofPackAuthority
+	for i, key := range feds {
+		authorities = append(authorities, ProofPackAuthority{Identity: ids[i], SigningKey: hex.EncodeToString(key.Pub[:])})
+	}
+	sign := func(h *ProofPackHeader, id string, key *primitives.PrivateKey) {
+		sig := key.Sign(h.SigningData("LOCAL"))
+		h.Signatures = append(h.Signatures, CheckpointSignature{Identity: id, Signature: hex.EncodeToString(sig.Bytes())})
+	}
+
+	first := ProofPackHeader{Height: 1000, KeyMR: primitives.RandomHash().String(), Authorities: authorities}
+	sign(&first, "00", bootstrap)
+	second := ProofPackHeader{Height: 2000, KeyMR: primitives.RandomHash().String()}
+	sign(&second, ids[0], feds[0])
+	sign(&second, ids[1], feds[1])
+	pack := &ProofPack{Network: "LOCAL", Interval: 1000, Headers: []ProofPackHeader{first, second}}
+
+	points, err := VerifyProofPack(pack, "LOCAL", "00", bootstrap.Pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(points) != 2 || points[2000] != second.KeyMR {
+		t.Errorf("Wrong checkpoints %v", points)
+	}
+
+	if _, err := VerifyProofPack(pack, "MAIN", "00", bootstrap.Pub); err == nil {
+		t.Error("Accepted a pack of another network")
+	}
+	// Rooted in another bootstrap key
+	if _, err := VerifyProofPack(pack, "LOCAL", "00", primitives.RandomPrivateKey().Pub); err == nil {
+		t.Error("Accepted a pack of another bootstrap key")
+	}
+
+	// One of three federated servers isn't a majority
+	second.Signatures = second.Signatures[:1]
+	pack.Headers[1] = second
+	if _, err := VerifyProofPack(pack, "LOCAL", "00", bootstrap.Pub); err == nil {
+		t.Error("Accepted a header signed by a minority")
+	}
+
+	// The bootstrap key can't sign past the first servers
+	second.Signatures = nil
+	sign(&second, "00", bootstrap)
+	pack.Headers[1] = second
+	if _, err := VerifyProofPack(pack, "LOCAL", "00", bootstrap.Pub); err == nil {
+		t.Error("Accepted a header signed by the replaced servers")
+	}
+}
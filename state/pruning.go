@@ -0,0 +1,92 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"time"
+
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+	log "github.com/sirupsen/logrus"
+)
+
+// How many directory blocks of entries a pruning node keeps if the config doesn't say
+const DefaultPruneKeepBlocks = 10000
+
+var pruneLogger = packageLogger.WithFields(log.Fields{"subpack": "pruning"})
+
+// pruneKeep returns how many directory blocks of entries are kept
+func (s *State) pruneKeep() uint32 {
+	if s.PruneKeepBlocks == 0 {
+		return DefaultPruneKeepBlocks
+	}
+	return s.PruneKeepBlocks
+}
+
+// PruneFloor returns the lowest directory block height whose entries this node keeps,
+// 0 if it keeps them all.  The entry sync doesn't ask for entries below it.
+func (s *State) PruneFloor() uint32 {
+	if !s.Pruning {
+		return 0
+	}
+	highest := s.GetHighestSavedBlk()
+	if highest < s.pruneKeep() {
+		return 0
+	}
+	return highest - s.pruneKeep()
+}
+
+// The most directory block heights of entry blocks pruned in a pass, so a node catching up
+// on pruning holds the database's prune lock, and the kept entries of the pass in memory,
+// only so long
+const pruneHeightsPerRun = 100
+
+// GoPruneBlocks deletes old entry blocks and entries as the chain grows, once the database
+// is loaded.  It runs on its own, off the consensus loop, when Pruning is set.
+func (s *State) GoPruneBlocks() {
+	if !s.Pruning {
+		return
+	}
+	for {
+		time.Sleep(time.Minute)
+		if !s.DBFinished {
+			continue
+		}
+		s.PruneBlocks()
+	}
+}
+
+// PruneBlocks deletes the entry blocks and entries that have fallen below the prune floor
+// since the last call, up to pruneHeightsPerRun heights of them.  Pinned chains are left
+// alone, as are heights the entry sync is still working through.
+func (s *State) PruneBlocks() {
+	overlay, ok := s.DB.(*databaseOverlay.Overlay)
+	if !ok {
+		return
+	}
+
+	from, err := overlay.FetchPrunedHeight()
+	if err != nil {
+		pruneLogger.Errorf("Cannot read the pruned height: %v", err)
+		return
+	}
+	to := s.PruneFloor()
+	if to > s.EntryDBHeightComplete {
+		to = s.EntryDBHeightComplete
+	}
+	if to > from+pruneHeightsPerRun {
+		to = from + pruneHeightsPerRun
+	}
+	if to <= from {
+		return
+	}
+	// The pruned height is saved with the pass, so a restart doesn't prune the same blocks
+	// again
+	if err := overlay.PruneEntryBlocks(from, to, s.IsChainPinned); err != nil {
+		pruneLogger.Errorf("Failed pruning entry blocks at heights %d to %d: %v", from, to-1, err)
+		return
+	}
+	PrunedHeight.Set(float64(to))
+	pruneLogger.Infof("Pruned entry blocks at directory blocks %d to %d", from, to-1)
+}
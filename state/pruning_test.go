@@ -0,0 +1,51 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestPruneBlocks(t *testing.T) {
+	// A state with no validator running, so its saved height can be set to that of the
+	// populated database
+	s := testHelper.CreateEmptyTestState()
+	overlay := testHelper.CreateAndPopulateTestDatabaseOverlay()
+	s.DB = overlay
+
+	head, err := overlay.FetchDBlockHead()
+	if err != nil || head == nil {
+		t.Fatalf("No directory block head - %v", err)
+	}
+	highest := head.GetDatabaseHeight()
+	s.DBStates.Base = highest
+	if s.GetHighestSavedBlk() != highest {
+		t.Fatalf("Highest saved block is %d, expected %d", s.GetHighestSavedBlk(), highest)
+	}
+
+	if floor := s.PruneFloor(); floor != 0 {
+		t.Errorf("Floor is %d without pruning", floor)
+	}
+	s.Pruning = true
+	s.PruneKeepBlocks = 1
+	if floor := s.PruneFloor(); floor != highest-1 {
+		t.Errorf("Floor is %d, expected %d", floor, highest-1)
+	}
+
+	// Nothing is pruned past what the entry sync has finished with
+	s.EntryDBHeightComplete = 1
+	s.PruneBlocks()
+	if h, err := overlay.FetchPrunedHeight(); err != nil || h != 1 {
+		t.Errorf("Pruned to %d, expected 1 - %v", h, err)
+	}
+
+	s.EntryDBHeightComplete = highest
+	s.PruneBlocks()
+	if h, err := overlay.FetchPrunedHeight(); err != nil || h != highest-1 {
+		t.Errorf("Pruned to %d, expected %d - %v", h, highest-1, err)
+	}
+}
@@ -98,6 +98,12 @@ func measureMessage(counter *prometheus.GaugeVec, msg interfaces.IMsg, increment
 			counter.WithLabelValues("dbstatmissing").Add(amt)
 		case constants.DBSTATE_MSG: // 20
 			counter.WithLabelValues("dbstate").Add(amt)
+		case constants.SYNC_REQUEST_MSG: // 34
+			counter.WithLabelValues("syncrequest").Add(amt)
+		case constants.SYNC_CHUNK_MSG: // 35
+			counter.WithLabelValues("syncchunk").Add(amt)
+		case constants.BATCH_ACK_MSG: // 30
+			counter.WithLabelValues("batchack").Add(amt)
 		default: // 23
 			counter.WithLabelValues("misc").Add(amt)
 		}
@@ -0,0 +1,79 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"time"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// The node modes
+const (
+	RoleFull        = "FULL"    // Follower, which becomes a server if its identity is made one
+	RoleServer      = "SERVER"  // Leader or audit server
+	RoleReadReplica = "REPLICA" // Serves the API from the blocks it follows, and takes no part in consensus
+)
+
+// A read replica takes the API load off the authority nodes.  It follows the network by
+// directory block states alone, asking its peers for the next ones, and keeps its indexes
+// and balances from them.  It sends no acks, faults, heartbeats or missing message
+// requests, and takes none from its peers, nor does it relay their commits, reveals and
+// transactions; only those of its own API clients go out.  Its peers are told it is not
+// routable, so they leave it out of their gossip.
+
+// How often a replica asks for the next directory block states
+const replicaFollowInterval = 5 * time.Second
+
+// Most directory block states a replica asks for at once
+const replicaDBStatesPerAsk = 100
+
+// IsReadReplica returns true if the node is a read replica
+func (s *State) IsReadReplica() bool {
+	return s.NodeMode == RoleReadReplica
+}
+
+// ReplicaTakes returns true for the messages a read replica takes from its peers: the
+// blocks and entries it follows by, and the requests for them
+func ReplicaTakes(msgType byte) bool {
+	switch msgType {
+	case constants.DBSTATE_MSG, constants.DBSTATE_MISSING_MSG,
+		constants.SYNC_REQUEST_MSG, constants.SYNC_CHUNK_MSG,
+		constants.MISSING_DATA, constants.DATA_RESPONSE,
+		constants.MISSING_ENTRY_BLOCKS, constants.ENTRY_BLOCK_RESPONSE,
+		constants.THROTTLE_MSG:
+		return true
+	}
+	return false
+}
+
+// ReplicaSends returns true for the messages a read replica sends: those it takes, and the
+// commits, reveals and transactions of its API clients
+func ReplicaSends(msgType byte) bool {
+	switch msgType {
+	case constants.COMMIT_CHAIN_MSG, constants.COMMIT_ENTRY_MSG,
+		constants.REVEAL_ENTRY_MSG, constants.FACTOID_TRANSACTION_MSG:
+		return true
+	}
+	return ReplicaTakes(msgType)
+}
+
+// becomeReadReplica takes an identity no authority has, so the node is never a server
+func (s *State) becomeReadReplica() {
+	s.Leader = false
+	s.IdentityChainID = primitives.Sha([]byte(s.FactomNodeName + time.Now().String()))
+}
+
+// followDBStates asks a peer for the directory block states past the highest saved, as a
+// replica hears of no new blocks from acks
+func (s *State) followDBStates() error {
+	next := s.GetHighestSavedBlk() + 1
+	msg := messages.NewDBStateMissing(s, next, next+replicaDBStatesPerAsk-1)
+	msg.SendOut(s, msg)
+	ReplicaDBStateAsks.Inc()
+	return nil
+}
@@ -0,0 +1,55 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/common/constants"
+	. "github.com/FactomProject/factomd/state"
+)
+
+func TestReplicaMessages(t *testing.T) {
+	for _, consensus := range []byte{constants.ACK_MSG, constants.EOM_MSG, constants.DIRECTORY_BLOCK_SIGNATURE_MSG,
+		constants.HEARTBEAT_MSG, constants.FED_SERVER_FAULT_MSG, constants.FULL_SERVER_FAULT_MSG,
+		constants.MISSING_MSG, constants.MISSING_MSG_RESPONSE, constants.ADDSERVER_MSG} {
+		if ReplicaTakes(consensus) || ReplicaSends(consensus) {
+			t.Errorf("A replica passes on consensus message type %d", consensus)
+		}
+	}
+
+	// It follows by blocks
+	if !ReplicaTakes(constants.DBSTATE_MSG) || !ReplicaSends(constants.DBSTATE_MISSING_MSG) {
+		t.Error("A replica can't follow by directory block states")
+	}
+
+	// Its clients' writes go out, but it doesn't relay those of its peers
+	if !ReplicaSends(constants.COMMIT_ENTRY_MSG) || ReplicaTakes(constants.COMMIT_ENTRY_MSG) {
+		t.Error("A replica should send its clients' commits, and only those")
+	}
+}
+
+func TestReadReplicaIsNeverLeader(t *testing.T) {
+	s := new(State)
+	s.LoadConfig("", "LOCAL")
+	s.NodeMode = RoleReadReplica
+	s.DBType = "Map"
+	s.Init()
+	if !s.IsReadReplica() || s.Leader {
+		t.Errorf("Replica is a leader")
+	}
+	if s.IdentityChainID.IsSameAs(s.GetNetworkBootStrapIdentity()) {
+		t.Errorf("Replica has the identity of the bootstrap server")
+	}
+	found := false
+	for _, j := range s.GetJobs() {
+		if j.Name == "replica-follow" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("A replica doesn't ask for new blocks")
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+)
+
+// How many unsaved blocks' temporary balances are layered over the permanent ones
+const readViewPendingBlocks = 2
+
+// ReadView is the snapshot of the state the API answers from.  A new view is built as
+// each block is saved, and its pending layer is refreshed at the end of every minute;
+// either way the old view is replaced, never changed.
+type ReadView struct {
+	dbheight  uint32
+	keyMR     interfaces.IHash
+	timestamp int64
+
+	factoids *BalanceSnapshot
+	ecs      *BalanceSnapshot
+
+	// Balances changed by blocks not yet saved, and chains with entries in them
+	pendingFactoids map[[32]byte]int64
+	pendingECs      map[[32]byte]int64
+	pendingChains   map[[32]byte]bool
+
+	fedServers   []interfaces.IServer
+	auditServers []interfaces.IServer
+	authorities  []interfaces.IAuthority
+}
+
+var _ interfaces.IReadView = (*ReadView)(nil)
+
+func (v *ReadView) GetDBHeight() uint32 {
+	return v.dbheight
+}
+
+func (v *ReadView) GetKeyMR() interfaces.IHash {
+	return v.keyMR
+}
+
+func (v *ReadView) GetTimestamp() int64 {
+	return v.timestamp
+}
+
+func (v *ReadView) GetFactoidBalance(address [32]byte) int64 {
+	if b, ok := v.pendingFactoids[address]; ok {
+		return b
+	}
+	return v.factoids.Get(address)
+}
+
+func (v *ReadView) GetECBalance(address [32]byte) int64 {
+	if b, ok := v.pendingECs[address]; ok {
+		return b
+	}
+	return v.ecs.Get(address)
+}
+
+func (v *ReadView) IsChainPending(chainID interfaces.IHash) bool {
+	return v.pendingChains[chainID.Fixed()]
+}
+
+func (v *ReadView) GetFedServers() []interfaces.IServer {
+	return v.fedServers
+}
+
+func (v *ReadView) GetAuditServers() []interfaces.IServer {
+	return v.auditServers
+}
+
+func (v *ReadView) GetAuthorities() []interfaces.IAuthority {
+	return v.authorities
+}
+
+// GetReadView returns the latest snapshot of the state, or nil if no block has been
+// saved since we started
+func (s *State) GetReadView() interfaces.IReadView {
+	v := s.currentReadView()
+	if v == nil {
+		return nil
+	}
+	return v
+}
+
+func (s *State) currentReadView() *ReadView {
+	v, _ := s.readView.Load().(*ReadView)
+	return v
+}
+
+// PublishReadView replaces the read view with one as of the block just saved at
+// dbheight.  Called from the consensus loop.
+func (s *State) PublishReadView(dbheight uint32, keyMR interfaces.IHash) {
+	v := new(ReadView)
+	v.dbheight = dbheight
+	v.keyMR = keyMR
+	v.factoids = s.FactoidBalancesP.Publish()
+	v.ecs = s.ECBalancesP.Publish()
+
+	s.fillReadView(v)
+	s.readView.Store(v)
+}
+
+// RefreshReadView updates the pending layer of the read view, and the authority set.
+// Called from the consensus loop at the end of each minute.
+func (s *State) RefreshReadView() {
+	prev := s.currentReadView()
+	if prev == nil {
+		return
+	}
+	v := *prev
+	s.fillReadView(&v)
+	s.readView.Store(&v)
+}
+
+// fillReadView copies everything but the permanent balances into the view
+func (s *State) fillReadView(v *ReadView) {
+	v.timestamp = time.Now().Unix()
+	v.pendingFactoids = make(map[[32]byte]int64)
+	v.pendingECs = make(map[[32]byte]int64)
+	v.pendingChains = make(map[[32]byte]bool)
+
+	// Later blocks override earlier ones.  Only the last few can have temporary balances;
+	// while catching up, blocks are processed from DBStates straight into the permanent ones.
+	from := v.dbheight + 1
+	if s.LLeaderHeight > readViewPendingBlocks && from < s.LLeaderHeight-readViewPendingBlocks {
+		from = s.LLeaderHeight - readViewPendingBlocks
+	}
+	for h := from; s.ProcessLists != nil && h <= s.LLeaderHeight; h++ {
+		pl := s.ProcessLists.GetSafe(h)
+		if pl == nil {
+			continue
+		}
+		for k, b := range pl.FactoidBalancesT.Copy() {
+			v.pendingFactoids[k] = b
+		}
+		for k, b := range pl.ECBalancesT.Copy() {
+			v.pendingECs[k] = b
+		}
+
+		pl.neweblockslock.Lock()
+		for k := range pl.NewEBlocks {
+			v.pendingChains[k] = true
+		}
+		pl.neweblockslock.Unlock()
+		if pl.PendingChainHeads != nil {
+			for k := range pl.PendingChainHeads.Copy().msgmap {
+				v.pendingChains[k] = true
+			}
+		}
+	}
+
+	if s.ProcessLists != nil {
+		if pl := s.ProcessLists.GetSafe(s.LLeaderHeight); pl != nil {
+			v.fedServers = append([]interfaces.IServer{}, pl.FedServers...)
+			v.auditServers = append([]interfaces.IServer{}, pl.AuditServers...)
+		}
+	}
+	v.authorities = s.GetAuthorities()
+}
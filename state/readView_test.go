@@ -0,0 +1,50 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestReadView(t *testing.T) {
+	s := testHelper.CreateEmptyTestState()
+	a := primitives.Sha([]byte("a")).Fixed()
+	b := primitives.Sha([]byte("b")).Fixed()
+
+	s.PutF(false, a, 100)
+	s.PutE(false, a, 7)
+	keyMR := primitives.Sha([]byte("block 1"))
+	s.PublishReadView(1, keyMR)
+
+	view := s.GetReadView()
+	if view == nil {
+		t.Fatal("No read view published")
+	}
+	if view.GetDBHeight() != 1 || !view.GetKeyMR().IsSameAs(keyMR) {
+		t.Errorf("Unexpected view of block %d %v", view.GetDBHeight(), view.GetKeyMR())
+	}
+	if view.GetFactoidBalance(a) != 100 || view.GetECBalance(a) != 7 {
+		t.Errorf("Unexpected balances %d %d", view.GetFactoidBalance(a), view.GetECBalance(a))
+	}
+
+	// Changes made after the view was published don't show in it
+	s.PutF(false, a, 50)
+	s.PutF(false, b, 25)
+	if view.GetFactoidBalance(a) != 100 || view.GetFactoidBalance(b) != 0 {
+		t.Errorf("Published view changed, %d %d", view.GetFactoidBalance(a), view.GetFactoidBalance(b))
+	}
+
+	s.PublishReadView(2, primitives.Sha([]byte("block 2")))
+	next := s.GetReadView()
+	if next.GetFactoidBalance(a) != 50 || next.GetFactoidBalance(b) != 25 || next.GetECBalance(a) != 7 {
+		t.Errorf("Unexpected balances %d %d %d", next.GetFactoidBalance(a), next.GetFactoidBalance(b), next.GetECBalance(a))
+	}
+	if view.GetFactoidBalance(a) != 100 {
+		t.Errorf("Older view changed to %d", view.GetFactoidBalance(a))
+	}
+}
@@ -0,0 +1,459 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+	"github.com/FactomProject/factomd/receipts"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var receiptLogger = packageLogger.WithFields(log.Fields{"subpack": "receipt-subscriptions"})
+
+const (
+	DefaultReceiptConfirmations = 6
+	MaxReceiptConfirmations     = 100
+	MaxReceiptSubscriptions     = 10000
+	ReceiptSubscriptionTTL      = 7 * 24 * time.Hour
+	MaxReceiptWebhookAttempts   = 10
+	ReceiptWebhookTimeout       = 10 * time.Second
+	ReceiptCheckInterval        = time.Minute
+
+	// How far back from the highest saved block to look for the newest anchor, which
+	// gives the height of the Bitcoin chain
+	receiptTipSearch = 1000
+)
+
+// Where the webhook subscriptions are kept in the database
+var (
+	RECEIPT_SUBSCRIPTIONS      = []byte("ReceiptSubscriptions")
+	RECEIPT_SUBSCRIPTIONS_LIST = []byte("List")
+)
+
+// ReceiptSubscriptionTracker holds the entries clients want receipts pushed for, once the
+// anchors of their directory blocks are deep enough in Bitcoin.  Subscriptions with a
+// webhook are saved in the database, so they outlive a restart; websocket subscriptions
+// end with their connection.
+type ReceiptSubscriptionTracker struct {
+	mutex    sync.Mutex
+	subs     map[string]*interfaces.ReceiptSubscription
+	dirty    bool // Webhook subscriptions changed since last saved
+	watchers map[int]chan *interfaces.ReceiptNotice
+	nextID   int
+	Dropped  int // Count of notices not delivered because a watcher was full
+}
+
+func NewReceiptSubscriptionTracker() *ReceiptSubscriptionTracker {
+	t := new(ReceiptSubscriptionTracker)
+	t.subs = make(map[string]*interfaces.ReceiptSubscription)
+	t.watchers = make(map[int]chan *interfaces.ReceiptNotice)
+	return t
+}
+
+// Add subscribes to the receipt of an entry.  0 confirmations asks for the default.
+func (t *ReceiptSubscriptionTracker) Add(entryHash string, confirmations int, webhook string, now time.Time) (interfaces.ReceiptSubscription, error) {
+	if confirmations == 0 {
+		confirmations = DefaultReceiptConfirmations
+	}
+	if confirmations < 1 || confirmations > MaxReceiptConfirmations {
+		return interfaces.ReceiptSubscription{}, fmt.Errorf("Confirmations must be between 1 and %d", MaxReceiptConfirmations)
+	}
+	if webhook != "" {
+		u, err := url.Parse(webhook)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return interfaces.ReceiptSubscription{}, errors.New("Webhook must be an http or https URL")
+		}
+	}
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return interfaces.ReceiptSubscription{}, err
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if len(t.subs) >= MaxReceiptSubscriptions {
+		return interfaces.ReceiptSubscription{}, errors.New("Too many receipt subscriptions")
+	}
+	sub := &interfaces.ReceiptSubscription{
+		ID:            hex.EncodeToString(id),
+		EntryHash:     entryHash,
+		Confirmations: confirmations,
+		Webhook:       webhook,
+		Created:       now.Unix(),
+		Expires:       now.Add(ReceiptSubscriptionTTL).Unix(),
+	}
+	t.subs[sub.ID] = sub
+	t.dirty = t.dirty || webhook != ""
+	return *sub, nil
+}
+
+// Remove ends a subscription, returning false if there was no such subscription
+func (t *ReceiptSubscriptionTracker) Remove(id string) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	sub, ok := t.subs[id]
+	if ok {
+		delete(t.subs, id)
+		t.dirty = t.dirty || sub.Webhook != ""
+	}
+	return ok
+}
+
+// List returns the subscriptions, oldest first
+func (t *ReceiptSubscriptionTracker) List() []interfaces.ReceiptSubscription {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.list(false)
+}
+
+func (t *ReceiptSubscriptionTracker) list(webhooksOnly bool) []interfaces.ReceiptSubscription {
+	list := make([]interfaces.ReceiptSubscription, 0, len(t.subs))
+	for _, sub := range t.subs {
+		if !webhooksOnly || sub.Webhook != "" {
+			list = append(list, *sub)
+		}
+	}
+	sort.Sort(receiptSubscriptionsByAge(list))
+	return list
+}
+
+type receiptSubscriptionsByAge []interfaces.ReceiptSubscription
+
+func (l receiptSubscriptionsByAge) Len() int      { return len(l) }
+func (l receiptSubscriptionsByAge) Swap(i, j int) { l[i], l[j] = l[j], l[i] }
+func (l receiptSubscriptionsByAge) Less(i, j int) bool {
+	if l[i].Created != l[j].Created {
+		return l[i].Created < l[j].Created
+	}
+	return l[i].ID < l[j].ID
+}
+
+// Expire removes the subscriptions that have expired, and returns them
+func (t *ReceiptSubscriptionTracker) Expire(now time.Time) (expired []interfaces.ReceiptSubscription) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	for id, sub := range t.subs {
+		if sub.Expires <= now.Unix() {
+			expired = append(expired, *sub)
+			delete(t.subs, id)
+			t.dirty = t.dirty || sub.Webhook != ""
+		}
+	}
+	return expired
+}
+
+// Failed records a failed webhook delivery.  It returns true if the subscription has had
+// its last attempt, and has been removed.
+func (t *ReceiptSubscriptionTracker) Failed(id string, err error) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	sub, ok := t.subs[id]
+	if !ok {
+		return false
+	}
+	sub.Attempts++
+	sub.LastError = err.Error()
+	t.dirty = true
+	if sub.Attempts >= MaxReceiptWebhookAttempts {
+		delete(t.subs, id)
+		return true
+	}
+	return false
+}
+
+// Watch returns an id (used to stop watching) and the channel every notice will arrive on
+func (t *ReceiptSubscriptionTracker) Watch() (int, <-chan *interfaces.ReceiptNotice) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.nextID++
+	c := make(chan *interfaces.ReceiptNotice, statusEventBuffer)
+	t.watchers[t.nextID] = c
+	return t.nextID, c
+}
+
+// Unwatch removes the watcher and closes its channel
+func (t *ReceiptSubscriptionTracker) Unwatch(id int) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if c, ok := t.watchers[id]; ok {
+		delete(t.watchers, id)
+		close(c)
+	}
+}
+
+// Notify sends a notice to every watcher, without blocking
+func (t *ReceiptSubscriptionTracker) Notify(notice *interfaces.ReceiptNotice) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	for _, c := range t.watchers {
+		select {
+		case c <- notice:
+		default:
+			t.Dropped++
+		}
+	}
+}
+
+// MarshalIfChanged returns the webhook subscriptions to save, or nil if they haven't
+// changed since the last call
+func (t *ReceiptSubscriptionTracker) MarshalIfChanged() ([]byte, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if !t.dirty {
+		return nil, nil
+	}
+	t.dirty = false
+	return json.Marshal(t.list(true))
+}
+
+// Unmarshal restores saved subscriptions
+func (t *ReceiptSubscriptionTracker) Unmarshal(data []byte) error {
+	var list []interfaces.ReceiptSubscription
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	for i := range list {
+		t.subs[list[i].ID] = &list[i]
+	}
+	return nil
+}
+
+// ReceiptConfirmations is the number of confirmations an anchor in the Bitcoin block at
+// anchorHeight has, when the tip of the chain is at tipHeight
+func ReceiptConfirmations(anchorHeight int32, tipHeight int32) int {
+	if anchorHeight <= 0 || tipHeight < anchorHeight {
+		return 0
+	}
+	return int(tipHeight-anchorHeight) + 1
+}
+
+// bitcoinTip is the Bitcoin block height of the newest anchor we have, which is the best
+// we know of the height of the chain.  It is never ahead of the real tip, so the
+// confirmations it gives are never more than an entry really has.
+func (s *State) bitcoinTip() int32 {
+	head, err := s.DB.FetchDBlockHead()
+	if err != nil || head == nil {
+		return 0
+	}
+	highest := head.GetDatabaseHeight()
+	for h := int64(highest); h >= 0 && h > int64(highest)-receiptTipSearch; h-- {
+		keymr, err := s.DB.FetchDBKeyMRByHeight(uint32(h))
+		if err != nil || keymr == nil {
+			continue
+		}
+		dbi, err := s.DB.FetchDirBlockInfoByKeyMR(keymr)
+		if err != nil || dbi == nil || !dbi.GetBTCConfirmed() {
+			continue
+		}
+		return dbi.GetBTCBlockHeight()
+	}
+	return 0
+}
+
+// receiptNotice returns the notice for a subscription, or nil if its entry's anchor is
+// not yet deep enough
+func (s *State) receiptNotice(sub interfaces.ReceiptSubscription, tip int32) (*interfaces.ReceiptNotice, error) {
+	entryHash, err := primitives.HexToHash(sub.EntryHash)
+	if err != nil {
+		return nil, err
+	}
+	eblockKeyMR, err := s.DB.FetchIncludedIn(entryHash)
+	if err != nil || eblockKeyMR == nil {
+		return nil, err
+	}
+	dblockKeyMR, err := s.DB.FetchIncludedIn(eblockKeyMR)
+	if err != nil || dblockKeyMR == nil {
+		return nil, err
+	}
+	dbi, err := s.DB.FetchDirBlockInfoByKeyMR(dblockKeyMR)
+	if err != nil || dbi == nil || !dbi.GetBTCConfirmed() {
+		return nil, err
+	}
+	confirmations := ReceiptConfirmations(dbi.GetBTCBlockHeight(), tip)
+	if confirmations < sub.Confirmations {
+		return nil, nil
+	}
+
+	receipt, err := receipts.CreateFullReceipt(s.DB, entryHash)
+	if err != nil {
+		return nil, err
+	}
+	data, err := receipt.JSONByte()
+	if err != nil {
+		return nil, err
+	}
+	notice := new(interfaces.ReceiptNotice)
+	notice.SubscriptionID = sub.ID
+	notice.EntryHash = sub.EntryHash
+	notice.DBHeight = dbi.GetDBHeight()
+	notice.BitcoinBlockHeight = dbi.GetBTCBlockHeight()
+	notice.Confirmations = confirmations
+	notice.Receipt = data
+	return notice, nil
+}
+
+// CheckReceiptSubscriptions pushes the receipts of the subscribed entries whose anchors
+// are now deep enough, and drops the subscriptions that have expired
+func (s *State) CheckReceiptSubscriptions() {
+	t := s.ReceiptSubscriptions
+	if t == nil {
+		return
+	}
+	for _, sub := range t.Expire(time.Now()) {
+		ReceiptNoticesVec.WithLabelValues("expired").Inc()
+		receiptLogger.WithFields(log.Fields{"id": sub.ID, "entry": sub.EntryHash}).Info("Receipt subscription expired")
+	}
+
+	subs := t.List()
+	if len(subs) > 0 {
+		tip := s.bitcoinTip()
+		for _, sub := range subs {
+			notice, err := s.receiptNotice(sub, tip)
+			if err != nil {
+				receiptLogger.WithFields(log.Fields{"id": sub.ID, "entry": sub.EntryHash}).Errorf("Cannot build receipt: %v", err)
+			}
+			if notice != nil {
+				s.deliverReceiptNotice(sub, notice)
+			}
+		}
+	}
+	s.saveReceiptSubscriptions()
+}
+
+func (s *State) deliverReceiptNotice(sub interfaces.ReceiptSubscription, notice *interfaces.ReceiptNotice) {
+	t := s.ReceiptSubscriptions
+	if sub.Webhook == "" {
+		t.Notify(notice)
+		t.Remove(sub.ID)
+		ReceiptNoticesVec.WithLabelValues("websocket").Inc()
+		return
+	}
+
+	err := postReceiptNotice(sub.Webhook, notice)
+	if err == nil {
+		t.Remove(sub.ID)
+		ReceiptNoticesVec.WithLabelValues("webhook").Inc()
+		return
+	}
+	fields := log.Fields{"id": sub.ID, "entry": sub.EntryHash, "webhook": sub.Webhook}
+	if t.Failed(sub.ID, err) {
+		ReceiptNoticesVec.WithLabelValues("failed").Inc()
+		receiptLogger.WithFields(fields).Errorf("Giving up on receipt webhook after %d attempts: %v", MaxReceiptWebhookAttempts, err)
+	} else {
+		receiptLogger.WithFields(fields).Warnf("Receipt webhook failed, will retry: %v", err)
+	}
+}
+
+func postReceiptNotice(webhook string, notice *interfaces.ReceiptNotice) error {
+	body, err := json.Marshal(notice)
+	if err != nil {
+		return err
+	}
+	client := &http.Client{Timeout: ReceiptWebhookTimeout}
+	resp, err := client.Post(webhook, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("Webhook answered %s", resp.Status)
+	}
+	return nil
+}
+
+func (s *State) saveReceiptSubscriptions() {
+	overlay, ok := s.DB.(*databaseOverlay.Overlay)
+	if !ok {
+		return
+	}
+	data, err := s.ReceiptSubscriptions.MarshalIfChanged()
+	if err != nil || data == nil {
+		return
+	}
+	if err := overlay.Put(RECEIPT_SUBSCRIPTIONS, RECEIPT_SUBSCRIPTIONS_LIST, &primitives.ByteSlice{Bytes: data}); err != nil {
+		receiptLogger.Errorf("Cannot save the receipt subscriptions: %v", err)
+	}
+}
+
+func (s *State) loadReceiptSubscriptions() {
+	overlay, ok := s.DB.(*databaseOverlay.Overlay)
+	if !ok {
+		return
+	}
+	saved, err := overlay.Get(RECEIPT_SUBSCRIPTIONS, RECEIPT_SUBSCRIPTIONS_LIST, new(primitives.ByteSlice))
+	if err != nil || saved == nil {
+		return
+	}
+	if err := s.ReceiptSubscriptions.Unmarshal(saved.(*primitives.ByteSlice).Bytes); err != nil {
+		receiptLogger.Errorf("Ignoring the saved receipt subscriptions: %v", err)
+	}
+}
+
+// GoCheckReceiptSubscriptions restores the webhook subscriptions saved before, then checks
+// the subscriptions every ReceiptCheckInterval
+func (s *State) GoCheckReceiptSubscriptions() {
+	for !s.DBFinished {
+		time.Sleep(time.Second)
+	}
+	s.loadReceiptSubscriptions()
+	for {
+		s.CheckReceiptSubscriptions()
+		time.Sleep(ReceiptCheckInterval)
+	}
+}
+
+// SubscribeReceipt asks for the receipt of an entry to be pushed once its directory
+// block's anchor has the given number of Bitcoin confirmations.  Without a webhook, the
+// receipt goes to the watchers of receipt notices.
+func (s *State) SubscribeReceipt(entryHash interfaces.IHash, confirmations int, webhook string) (interfaces.ReceiptSubscription, error) {
+	if webhook != "" && !s.ReceiptWebhooksEnabled {
+		return interfaces.ReceiptSubscription{}, errors.New("Receipt webhooks are not enabled on this node")
+	}
+	sub, err := s.ReceiptSubscriptions.Add(entryHash.String(), confirmations, webhook, time.Now())
+	if err == nil && webhook != "" {
+		s.saveReceiptSubscriptions()
+	}
+	return sub, err
+}
+
+func (s *State) UnsubscribeReceipt(id string) bool {
+	removed := s.ReceiptSubscriptions.Remove(id)
+	if removed {
+		s.saveReceiptSubscriptions()
+	}
+	return removed
+}
+
+func (s *State) GetReceiptSubscriptions() []interfaces.ReceiptSubscription {
+	return s.ReceiptSubscriptions.List()
+}
+
+func (s *State) WatchReceiptNotices() (int, <-chan *interfaces.ReceiptNotice) {
+	return s.ReceiptSubscriptions.Watch()
+}
+
+func (s *State) UnwatchReceiptNotices(id int) {
+	s.ReceiptSubscriptions.Unwatch(id)
+}
@@ -0,0 +1,161 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	. "github.com/FactomProject/factomd/state"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestReceiptSubscriptionTracker(t *testing.T) {
+	tr := NewReceiptSubscriptionTracker()
+	now := time.Unix(1500000000, 0)
+
+	for _, bad := range []struct {
+		confirmations int
+		webhook       string
+	}{{-1, ""}, {MaxReceiptConfirmations + 1, ""}, {1, "ftp://example.com"}, {1, "example.com"}} {
+		if _, err := tr.Add("aa", bad.confirmations, bad.webhook, now); err == nil {
+			t.Errorf("Subscribed with %+v", bad)
+		}
+	}
+
+	ws, err := tr.Add("aa", 0, "", now)
+	if err != nil || ws.Confirmations != DefaultReceiptConfirmations || ws.ID == "" {
+		t.Fatalf("Unexpected subscription %+v %v", ws, err)
+	}
+	hook, err := tr.Add("bb", 3, "https://example.com/hook", now.Add(time.Hour))
+	if err != nil || hook.ID == ws.ID {
+		t.Fatalf("Unexpected subscription %+v %v", hook, err)
+	}
+	if list := tr.List(); len(list) != 2 || list[0].ID != ws.ID {
+		t.Errorf("Unexpected list %+v", list)
+	}
+
+	// Only the webhook subscription is saved
+	data, err := tr.MarshalIfChanged()
+	if err != nil || data == nil {
+		t.Fatalf("Nothing to save: %v", err)
+	}
+	if again, _ := tr.MarshalIfChanged(); again != nil {
+		t.Error("Saved again without a change")
+	}
+	restored := NewReceiptSubscriptionTracker()
+	if err := restored.Unmarshal(data); err != nil {
+		t.Fatal(err)
+	}
+	if list := restored.List(); len(list) != 1 || list[0] != hook {
+		t.Errorf("Restored %+v, expected %+v", list, hook)
+	}
+
+	// The webhook is tried so many times, then given up on
+	for i := 1; i < MaxReceiptWebhookAttempts; i++ {
+		if tr.Failed(hook.ID, errors.New("refused")) {
+			t.Fatalf("Gave up after %d attempts", i)
+		}
+	}
+	if !tr.Failed(hook.ID, errors.New("refused")) || len(tr.List()) != 1 {
+		t.Error("Didn't give up on the webhook")
+	}
+
+	if expired := tr.Expire(now.Add(ReceiptSubscriptionTTL - time.Second)); len(expired) != 0 {
+		t.Errorf("Expired early %+v", expired)
+	}
+	if expired := tr.Expire(now.Add(ReceiptSubscriptionTTL)); len(expired) != 1 || expired[0].ID != ws.ID {
+		t.Errorf("Expected %s to expire, got %+v", ws.ID, expired)
+	}
+	if tr.Remove(ws.ID) {
+		t.Error("Removed an expired subscription")
+	}
+}
+
+func TestReceiptConfirmations(t *testing.T) {
+	for _, test := range []struct {
+		anchor, tip int32
+		expected    int
+	}{{500000, 500000, 1}, {500000, 500005, 6}, {500000, 499999, 0}, {0, 500000, 0}} {
+		if got := ReceiptConfirmations(test.anchor, test.tip); got != test.expected {
+			t.Errorf("%d at tip %d: got %d confirmations, expected %d", test.anchor, test.tip, got, test.expected)
+		}
+	}
+}
+
+func TestCheckReceiptSubscriptions(t *testing.T) {
+	s := testHelper.CreateAndPopulateTestState()
+	s.ReceiptWebhooksEnabled = true
+
+	// The test blocks are anchored at Bitcoin heights equal to their own, so the entry of
+	// block 2 has as many confirmations as the newest anchored block is above it
+	dblock, err := s.DB.FetchDBlockByHeight(2)
+	if err != nil || dblock == nil {
+		t.Fatalf("No block 2: %v", err)
+	}
+	eblock, err := s.DB.FetchEBlock(dblock.GetEBlockDBEntries()[0].GetKeyMR())
+	if err != nil || eblock == nil {
+		t.Fatalf("No entry block in block 2: %v", err)
+	}
+	entry := eblock.GetEntryHashes()[0]
+
+	var posted []interfaces.ReceiptNotice
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		var notice interfaces.ReceiptNotice
+		if err := json.Unmarshal(body, &notice); err != nil {
+			t.Errorf("Bad notice %s: %v", body, err)
+		}
+		posted = append(posted, notice)
+	}))
+	defer server.Close()
+
+	id, notices := s.WatchReceiptNotices()
+	defer s.UnwatchReceiptNotices(id)
+
+	soon, _ := s.SubscribeReceipt(entry, 1, "")
+	hook, _ := s.SubscribeReceipt(entry, 2, server.URL)
+	never, _ := s.SubscribeReceipt(entry, MaxReceiptConfirmations, "")
+	cancelled, err := s.SubscribeReceipt(entry, 1, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.UnsubscribeReceipt(cancelled.ID) || s.UnsubscribeReceipt(cancelled.ID) {
+		t.Error("Unsubscribing didn't remove the subscription once")
+	}
+
+	s.CheckReceiptSubscriptions()
+
+	select {
+	case notice := <-notices:
+		if notice.SubscriptionID != soon.ID || notice.DBHeight != 2 || notice.Confirmations < 2 || len(notice.Receipt) == 0 {
+			t.Errorf("Unexpected notice %+v", notice)
+		}
+	default:
+		t.Fatal("No notice for the websocket subscription")
+	}
+	select {
+	case notice := <-notices:
+		t.Errorf("Unexpected second notice %+v", notice)
+	default:
+	}
+	if len(posted) != 1 || posted[0].SubscriptionID != hook.ID {
+		t.Errorf("Unexpected webhook posts %+v", posted)
+	}
+	if left := s.GetReceiptSubscriptions(); len(left) != 1 || left[0].ID != never.ID {
+		t.Errorf("Unexpected subscriptions left %+v", left)
+	}
+
+	s.ReceiptWebhooksEnabled = false
+	if _, err := s.SubscribeReceipt(entry, 1, server.URL); err == nil {
+		t.Error("Subscribed with a webhook when they are disabled")
+	}
+}
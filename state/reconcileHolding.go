@@ -0,0 +1,90 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/entryCreditBlock"
+	"github.com/FactomProject/factomd/common/messages"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// A follower that ends a block with gaps in its process list never gets the acks of the
+// commits, reveals and transactions it holds.  Once the block comes whole in a DBState,
+// they are in it; rather than linger in holding until they expire, being resent all the
+// while, they are taken out and marked as seen.
+
+// blockContents returns the repeat hashes of the commits, reveals and transactions in a
+// block
+func blockContents(d *DBState) map[[32]byte]bool {
+	in := make(map[[32]byte]bool)
+	if d.EntryCreditBlock != nil {
+		for _, tx := range d.EntryCreditBlock.GetEntries() {
+			switch tx.ECID() {
+			case entryCreditBlock.ECIDEntryCommit, entryCreditBlock.ECIDChainCommit:
+				in[tx.GetSigHash().Fixed()] = true
+			}
+		}
+	}
+	if d.FactoidBlock != nil {
+		for _, tx := range d.FactoidBlock.GetTransactions() {
+			in[tx.GetSigHash().Fixed()] = true
+		}
+	}
+	for _, eb := range d.EntryBlocks {
+		for _, eh := range eb.GetBody().GetEBEntries() {
+			in[eh.Fixed()] = true
+		}
+	}
+	return in
+}
+
+// ReconcileHolding takes the messages a processed block holds out of holding, and the acks
+// for its height and those before it.  Returns how many messages it took out.
+func (s *State) ReconcileHolding(d *DBState) int {
+	if len(s.Holding) == 0 {
+		return 0
+	}
+	dbheight := d.DirectoryBlock.GetHeader().GetDBHeight()
+	in := blockContents(d)
+	now := s.GetTimestamp()
+
+	reconciled := 0
+	for k, v := range s.Holding {
+		var kind string
+		switch m := v.(type) {
+		case *messages.Ack:
+			if m.DBHeight > dbheight {
+				continue
+			}
+			kind = "ack"
+		case *messages.CommitEntryMsg, *messages.CommitChainMsg:
+			kind = "commit"
+		case *messages.RevealEntryMsg:
+			kind = "reveal"
+		case *messages.FactoidTransaction:
+			kind = "factoid"
+		default:
+			continue
+		}
+		if kind != "ack" {
+			if !in[v.GetRepeatHash().Fixed()] {
+				continue
+			}
+			// Seen, so it isn't executed again if a peer passes it on late
+			s.Replay.IsTSValid_(constants.INTERNAL_REPLAY, v.GetRepeatHash().Fixed(), v.GetTimestamp(), now)
+		}
+		TotalHoldingQueueOutputs.Inc()
+		delete(s.Holding, k)
+		s.HoldingDeps.Forget(k)
+		HoldingReconciledVec.WithLabelValues(kind).Inc()
+		reconciled++
+	}
+	if reconciled > 0 {
+		packageLogger.WithFields(log.Fields{"subpack": "holding", "dbheight": dbheight, "reconciled": reconciled}).Debug("Took the messages of a completed block out of holding")
+	}
+	return reconciled
+}
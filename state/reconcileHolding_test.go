@@ -0,0 +1,86 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/common/entryCreditBlock"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
+	. "github.com/FactomProject/factomd/state"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+// newHeldAck returns an ack at a height, for a message we don't have
+func newHeldAck(s *State, dbheight uint32) *messages.Ack {
+	ack := new(messages.Ack)
+	ack.Timestamp = s.GetTimestamp()
+	ack.DBHeight = dbheight
+	ack.MessageHash = primitives.RandomHash()
+	ack.SerialHash = primitives.RandomHash()
+	ack.LeaderChainID = primitives.RandomHash()
+	return ack
+}
+
+func TestReconcileHolding(t *testing.T) {
+	s := testHelper.CreateEmptyTestState()
+	set := testHelper.CreateTestBlockSet(nil)
+	set = testHelper.CreateTestBlockSet(set)
+	d := &DBState{
+		DirectoryBlock:   set.DBlock,
+		EntryCreditBlock: set.ECBlock,
+		FactoidBlock:     set.FBlock,
+		EntryBlocks:      []interfaces.IEntryBlock{set.EBlock},
+	}
+	height := set.DBlock.GetHeader().GetDBHeight()
+	hold := func(m interfaces.IMsg) { s.Holding[m.GetMsgHash().Fixed()] = m }
+
+	// In the block
+	reveal := messages.NewRevealEntryMsg()
+	reveal.Entry = set.Entries[0]
+	reveal.Timestamp = s.GetTimestamp()
+	hold(reveal)
+	commit := messages.NewCommitEntryMsg()
+	for _, tx := range set.ECBlock.GetEntries() {
+		if tx.ECID() == entryCreditBlock.ECIDEntryCommit {
+			commit.CommitEntry = tx.(*entryCreditBlock.CommitEntry)
+			break
+		}
+	}
+	if commit.CommitEntry == nil {
+		t.Fatal("No commit in the test block")
+	}
+	hold(commit)
+	fct := new(messages.FactoidTransaction)
+	fct.Transaction = set.FBlock.GetTransactions()[1]
+	hold(fct)
+	oldAck := newHeldAck(s, height)
+	hold(oldAck)
+
+	// Not in the block
+	other := messages.NewRevealEntryMsg()
+	other.Entry = testHelper.CreateTestEntry(999)
+	other.Timestamp = s.GetTimestamp()
+	hold(other)
+	newAck := newHeldAck(s, height+1)
+	hold(newAck)
+
+	if n := s.ReconcileHolding(d); n != 4 {
+		t.Errorf("Reconciled %d messages, expected 4", n)
+	}
+	if len(s.Holding) != 2 {
+		t.Fatalf("Holding has %d messages, expected 2", len(s.Holding))
+	}
+	for _, m := range []interfaces.IMsg{other, newAck} {
+		if s.Holding[m.GetMsgHash().Fixed()] == nil {
+			t.Errorf("%s taken out of holding", m.String())
+		}
+	}
+	if s.ReconcileHolding(d) != 0 {
+		t.Error("Reconciled messages twice")
+	}
+}
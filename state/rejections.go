@@ -0,0 +1,176 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var rejectionsLogger = packageLogger.WithFields(log.Fields{"subpack": "rejections"})
+
+// Why blocks and messages are turned away
+const (
+	RejectedSignatures  = "bad-signatures"      // The block isn't signed by enough of the authority set
+	RejectedPrevKeyMR   = "prev-keymr-mismatch" // The block doesn't follow the one we saved before it
+	RejectedPrevMissing = "prev-block-missing"  // The block before it couldn't be read to check against
+	RejectedNotApplied  = "not-applied"         // The blocks of the state couldn't be applied
+	RejectedDoubleSpend = "double-spend"        // A transaction in the block was already spent
+	RejectedInvalid     = "invalid"             // The message failed validation
+)
+
+// The kinds of rejection
+const (
+	RejectionBlock   = "block"
+	RejectionMessage = "message"
+)
+
+// How many rejections are kept
+const MaxRejections = 1000
+
+// Where the rejections are kept in the database
+var (
+	REJECTIONS        = []byte("Rejections")
+	REJECTIONS_REPORT = []byte("Report")
+)
+
+// RejectionTracker records the blocks and messages from peers this node turned away, and
+// why, so an operator can find out after the fact why a block was ignored.  The
+// rejections are saved in the database, so a node keeps its history over restarts.
+type RejectionTracker struct {
+	mutex  sync.Mutex
+	report interfaces.RejectionReport
+	dirty  bool // Changed since last saved
+}
+
+func NewRejectionTracker() *RejectionTracker {
+	return new(RejectionTracker)
+}
+
+func (t *RejectionTracker) add(r interfaces.Rejection) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.report.Rejections = append(t.report.Rejections, r)
+	if len(t.report.Rejections) > MaxRejections {
+		t.report.Rejections = append([]interfaces.Rejection{}, t.report.Rejections[len(t.report.Rejections)-MaxRejections:]...)
+	}
+	t.dirty = true
+	RejectionsVec.WithLabelValues(r.Kind, r.Reason).Inc()
+	rejectionsLogger.WithFields(log.Fields{"kind": r.Kind, "hash": r.Hash, "dbheight": r.DBHeight, "reason": r.Reason, "peer": r.Peer}).Info("Rejected")
+}
+
+// Block records a directory block state turned away
+func (t *RejectionTracker) Block(msg *messages.DBStateMsg, reason string) {
+	if t == nil {
+		return
+	}
+	t.add(interfaces.Rejection{
+		Kind:      RejectionBlock,
+		Hash:      msg.DirectoryBlock.GetKeyMR().String(),
+		DBHeight:  msg.DirectoryBlock.GetHeader().GetDBHeight(),
+		Message:   messages.MessageName(msg.Type()),
+		Reason:    reason,
+		Peer:      msg.GetNetworkOrigin(),
+		Timestamp: time.Now().Unix(),
+	})
+}
+
+// Message records a message turned away while building the block at dbheight
+func (t *RejectionTracker) Message(msg interfaces.IMsg, dbheight uint32, reason string) {
+	if t == nil {
+		return
+	}
+	t.add(interfaces.Rejection{
+		Kind:      RejectionMessage,
+		Hash:      msg.GetMsgHash().String(),
+		DBHeight:  dbheight,
+		Message:   messages.MessageName(msg.Type()),
+		Reason:    reason,
+		Peer:      msg.GetNetworkOrigin(),
+		Timestamp: time.Now().Unix(),
+	})
+}
+
+// Report returns the rejections recorded, oldest first; just those of hash if it isn't empty
+func (t *RejectionTracker) Report(hash string) interfaces.RejectionReport {
+	r := interfaces.RejectionReport{Rejections: []interfaces.Rejection{}}
+	if t == nil {
+		return r
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	for _, rejection := range t.report.Rejections {
+		if hash == "" || rejection.Hash == hash {
+			r.Rejections = append(r.Rejections, rejection)
+		}
+	}
+	return r
+}
+
+// MarshalIfChanged returns the report to save, or nil if it hasn't changed since the last call
+func (t *RejectionTracker) MarshalIfChanged() ([]byte, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if !t.dirty {
+		return nil, nil
+	}
+	t.dirty = false
+	return json.Marshal(t.report)
+}
+
+// Unmarshal restores a saved report, ahead of anything recorded since the node started
+func (t *RejectionTracker) Unmarshal(data []byte) error {
+	var saved interfaces.RejectionReport
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return err
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.report.Rejections = append(saved.Rejections, t.report.Rejections...)
+	if len(t.report.Rejections) > MaxRejections {
+		t.report.Rejections = t.report.Rejections[len(t.report.Rejections)-MaxRejections:]
+	}
+	return nil
+}
+
+func (s *State) saveRejections() error {
+	overlay, ok := s.DB.(*databaseOverlay.Overlay)
+	if !ok || s.Rejections == nil {
+		return nil
+	}
+	data, err := s.Rejections.MarshalIfChanged()
+	if err != nil || data == nil {
+		return err
+	}
+	return overlay.Put(REJECTIONS, REJECTIONS_REPORT, &primitives.ByteSlice{Bytes: data})
+}
+
+func (s *State) loadRejections() {
+	overlay, ok := s.DB.(*databaseOverlay.Overlay)
+	if !ok || s.Rejections == nil {
+		return
+	}
+	saved, err := overlay.Get(REJECTIONS, REJECTIONS_REPORT, new(primitives.ByteSlice))
+	if err != nil || saved == nil {
+		return
+	}
+	if err := s.Rejections.Unmarshal(saved.(*primitives.ByteSlice).Bytes); err != nil {
+		rejectionsLogger.Errorf("Ignoring the saved rejections: %v", err)
+	}
+}
+
+// GetRejections returns the blocks and messages this node turned away, and why; just those
+// of hash if it isn't empty
+func (s *State) GetRejections(hash string) interfaces.RejectionReport {
+	return s.Rejections.Report(hash)
+}
@@ -0,0 +1,57 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"testing"
+
+	. "github.com/FactomProject/factomd/state"
+)
+
+func TestRejectionTracker(t *testing.T) {
+	tracker := NewRejectionTracker()
+	first := newSigTestCommit(0, false)
+	tracker.Message(first, 10, RejectedInvalid)
+	for i := 1; i <= MaxRejections; i++ {
+		tracker.Message(newSigTestCommit(i, false), 10, RejectedInvalid)
+	}
+
+	all := tracker.Report("")
+	if len(all.Rejections) != MaxRejections {
+		t.Fatalf("Kept %d rejections, expected %d", len(all.Rejections), MaxRejections)
+	}
+	if len(tracker.Report(first.GetMsgHash().String()).Rejections) != 0 {
+		t.Error("The oldest rejection should have been dropped")
+	}
+	last := newSigTestCommit(MaxRejections, false)
+	found := tracker.Report(last.GetMsgHash().String()).Rejections
+	if len(found) != 1 || found[0].Kind != RejectionMessage || found[0].Reason != RejectedInvalid || found[0].DBHeight != 10 {
+		t.Errorf("Unexpected rejections of the last message %+v", found)
+	}
+
+	// Saved rejections come back ahead of those since the restart
+	data, err := tracker.MarshalIfChanged()
+	if err != nil || data == nil {
+		t.Fatalf("Nothing to save - %v", err)
+	}
+	if again, _ := tracker.MarshalIfChanged(); again != nil {
+		t.Error("Saved again without a change")
+	}
+	restarted := NewRejectionTracker()
+	restarted.Message(first, 11, RejectedInvalid)
+	if err := restarted.Unmarshal(data); err != nil {
+		t.Fatal(err)
+	}
+	restored := restarted.Report("").Rejections
+	if len(restored) != MaxRejections || restored[len(restored)-1].DBHeight != 11 {
+		t.Error("The rejection since the restart should be kept, after those saved")
+	}
+
+	var none *RejectionTracker
+	none.Message(first, 10, RejectedInvalid)
+	if len(none.Report("").Rejections) != 0 {
+		t.Error("A nil tracker should record nothing")
+	}
+}
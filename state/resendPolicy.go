@@ -0,0 +1,158 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+)
+
+// ResendPolicy controls how often a message sitting in holding is sent out again.  The
+// resends go through SendOut, which sends a message at most MaxSendOuts times, so a
+// policy can allow no more than MaxResendCount resends.
+type ResendPolicy struct {
+	Interval   int64 // Milliseconds between resends
+	Jitter     int64 // Up to this many milliseconds are randomly added to each interval
+	MaxResends int   // Give up resending after this many, 0 for MaxResendCount
+}
+
+// The most times SendOut sends a message again after it first went out
+const MaxResendCount = messages.MaxSendOuts - 1
+
+// The policy used for message types without one of their own; the same timing we have
+// always used.
+var DefaultResendPolicy = ResendPolicy{Interval: 20000, Jitter: 0, MaxResends: 4}
+
+// heldResend tracks the resends of one message in holding
+type heldResend struct {
+	next  int64 // Time of the next resend (milliseconds)
+	count int
+}
+
+// GetResendPolicy returns the resend policy for a message type
+func (s *State) GetResendPolicy(msgType byte) ResendPolicy {
+	if p, ok := s.ResendPolicies[msgType]; ok {
+		return p
+	}
+	if s.DefaultResendPolicy != nil {
+		return *s.DefaultResendPolicy
+	}
+	return DefaultResendPolicy
+}
+
+// maxResends returns how many resends the policy allows, within what SendOut allows
+func (p ResendPolicy) maxResends() int {
+	if p.MaxResends <= 0 || p.MaxResends > MaxResendCount {
+		return MaxResendCount
+	}
+	return p.MaxResends
+}
+
+func (p ResendPolicy) nextResend(now int64) int64 {
+	next := now + p.Interval
+	if p.Jitter > 0 {
+		next += rand.Int63n(p.Jitter)
+	}
+	return next
+}
+
+// resendHeld decides if a message in holding should be sent out again now, following
+// the policy for its type.
+func (s *State) resendHeld(k [32]byte, msg interfaces.IMsg) bool {
+	if msg.GetNoResend() {
+		return false
+	}
+	if s.heldResends == nil {
+		s.heldResends = make(map[[32]byte]*heldResend)
+	}
+	policy := s.GetResendPolicy(msg.Type())
+	now := s.GetTimestamp().GetTimeMilli()
+
+	r := s.heldResends[k]
+	if r == nil {
+		// Nothing is resent until it has been held for a full interval
+		s.heldResends[k] = &heldResend{next: policy.nextResend(now)}
+		return false
+	}
+	if r.count >= policy.maxResends() {
+		return false
+	}
+	if now < r.next || s.NetworkOutMsgQueue().Length() >= 1000 {
+		return false
+	}
+	// Don't resend if we are behind
+	if s.GetHighestKnownBlock()-s.GetHighestSavedBlk() > 4 {
+		return false
+	}
+	r.count++
+	r.next = policy.nextResend(now)
+	HoldingResendsVec.WithLabelValues(messages.MessageName(msg.Type())).Inc()
+	return true
+}
+
+// pruneHeldResends forgets the resend history of messages no longer in holding
+func (s *State) pruneHeldResends() {
+	for k := range s.heldResends {
+		if _, ok := s.Holding[k]; !ok {
+			delete(s.heldResends, k)
+		}
+	}
+}
+
+// ParseResendPolicies parses per message type resend policies from a comma separated list
+// of type:interval:jitter:max, where the type is either the message type number or its
+// name without spaces (i.e. EOM, DirectoryBlockSignature), and times are milliseconds.  A
+// max over MaxResendCount is an error, as SendOut would never send that many.
+func ParseResendPolicies(config string) (map[byte]ResendPolicy, error) {
+	policies := make(map[byte]ResendPolicy)
+	for _, item := range strings.Split(config, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		fields := strings.Split(item, ":")
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("Resend policy %q should be type:interval:jitter:max", item)
+		}
+		msgType, err := parseMessageType(fields[0])
+		if err != nil {
+			return nil, err
+		}
+		var p ResendPolicy
+		if p.Interval, err = strconv.ParseInt(strings.TrimSpace(fields[1]), 10, 64); err != nil || p.Interval <= 0 {
+			return nil, fmt.Errorf("Bad resend interval in %q", item)
+		}
+		if p.Jitter, err = strconv.ParseInt(strings.TrimSpace(fields[2]), 10, 64); err != nil || p.Jitter < 0 {
+			return nil, fmt.Errorf("Bad resend jitter in %q", item)
+		}
+		if p.MaxResends, err = strconv.Atoi(strings.TrimSpace(fields[3])); err != nil || p.MaxResends < 0 {
+			return nil, fmt.Errorf("Bad resend maximum in %q", item)
+		}
+		if p.MaxResends > MaxResendCount {
+			return nil, fmt.Errorf("Resend maximum in %q is over the %d resends a message gets", item, MaxResendCount)
+		}
+		policies[msgType] = p
+	}
+	return policies, nil
+}
+
+func parseMessageType(name string) (byte, error) {
+	name = strings.TrimSpace(name)
+	if n, err := strconv.Atoi(name); err == nil && n >= 0 && n < 256 {
+		return byte(n), nil
+	}
+	for i := 0; i < 256; i++ {
+		known := strings.Replace(messages.MessageName(byte(i)), " ", "", -1)
+		if strings.EqualFold(known, name) {
+			return byte(i), nil
+		}
+	}
+	return 0, fmt.Errorf("Unknown message type %q", name)
+}
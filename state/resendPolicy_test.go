@@ -0,0 +1,57 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/common/constants"
+	. "github.com/FactomProject/factomd/state"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestParseResendPolicies(t *testing.T) {
+	policies, err := ParseResendPolicies(" EOM:5000:1000:3, directoryblocksignature:3000:0:0 ,13:100:10:1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(policies) != 3 {
+		t.Fatalf("Expected 3 policies, found %d", len(policies))
+	}
+	if p := policies[constants.EOM_MSG]; p.Interval != 5000 || p.Jitter != 1000 || p.MaxResends != 3 {
+		t.Errorf("Bad EOM policy %+v", p)
+	}
+	if p := policies[constants.DIRECTORY_BLOCK_SIGNATURE_MSG]; p.Interval != 3000 || p.Jitter != 0 || p.MaxResends != 0 {
+		t.Errorf("Bad DBSig policy %+v", p)
+	}
+	if p := policies[13]; p.Interval != 100 || p.MaxResends != 1 {
+		t.Errorf("Bad policy for type 13 %+v", p)
+	}
+
+	if policies, err = ParseResendPolicies(""); err != nil || len(policies) != 0 {
+		t.Errorf("Empty config should give no policies - %v %v", policies, err)
+	}
+	for _, bad := range []string{"EOM:5000:1000", "NotAMessage:1:1:1", "EOM:0:0:0", "EOM:10:-1:0", "EOM:10:0:x", "EOM:10:0:5"} {
+		if _, err := ParseResendPolicies(bad); err == nil {
+			t.Errorf("Expected an error parsing %q", bad)
+		}
+	}
+}
+
+func TestGetResendPolicy(t *testing.T) {
+	s := testHelper.CreateEmptyTestState()
+	if s.GetResendPolicy(constants.EOM_MSG) != DefaultResendPolicy {
+		t.Error("Expected the default policy")
+	}
+
+	s.DefaultResendPolicy = &ResendPolicy{Interval: 1000}
+	s.ResendPolicies = map[byte]ResendPolicy{constants.EOM_MSG: {Interval: 5, MaxResends: 2}}
+	if p := s.GetResendPolicy(constants.EOM_MSG); p.Interval != 5 || p.MaxResends != 2 {
+		t.Errorf("Expected the EOM policy, got %+v", p)
+	}
+	if p := s.GetResendPolicy(constants.ACK_MSG); p.Interval != 1000 {
+		t.Errorf("Expected the configured default policy, got %+v", p)
+	}
+}
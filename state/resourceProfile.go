@@ -0,0 +1,92 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+	"github.com/FactomProject/factomd/database/tieredDB"
+)
+
+// ResourceProfile sizes the node's queues and caches.  The standard profile is what the
+// node has always used; the small profile trades some catch up speed for a footprint
+// that fits the 1-2GB ARM boards many follower nodes run on.  See ARM64.md.
+type ResourceProfile struct {
+	Name                 string
+	InMsgQueue           int // Messages from peers waiting to be processed
+	MsgQueue             int // Follower messages
+	UpdateEntryHashQueue int
+	WriteEntryQueue      int // Entries waiting to be written to the database
+	MissingEntryQueue    int
+	ColdStorageCache     int // Records read from the cold store kept in memory
+	LevelDBBlockCache    int // Bytes, 0 for the LevelDB default of 8MB
+	LevelDBWriteBuffer   int // Bytes, 0 for the LevelDB default of 4MB
+	DBReadCache          int // Hot block records and failed lookups kept in memory, 0 for none
+}
+
+var ResourceProfiles = map[string]ResourceProfile{
+	"standard": {
+		Name:                 "standard",
+		InMsgQueue:           10000,
+		MsgQueue:             400,
+		UpdateEntryHashQueue: 10000,
+		WriteEntryQueue:      3000,
+		MissingEntryQueue:    1000,
+		ColdStorageCache:     tieredDB.DefaultCacheSize,
+		DBReadCache:          databaseOverlay.DefaultReadCacheSize,
+	},
+	"small": {
+		Name:                 "small",
+		InMsgQueue:           5000,
+		MsgQueue:             200,
+		UpdateEntryHashQueue: 2000,
+		WriteEntryQueue:      1000,
+		MissingEntryQueue:    500,
+		ColdStorageCache:     512,
+		LevelDBBlockCache:    2 * 1024 * 1024,
+		LevelDBWriteBuffer:   1024 * 1024,
+		DBReadCache:          1024,
+	},
+}
+
+// DefaultResourceProfile is the profile used when none is configured: small on ARM,
+// where most nodes are single board computers or small cloud instances, and standard
+// everywhere else
+func DefaultResourceProfile() string {
+	switch runtime.GOARCH {
+	case "arm", "arm64":
+		return "small"
+	}
+	return "standard"
+}
+
+// GetResourceProfile looks up a profile by name.  An empty name gives the default for
+// this machine.
+func GetResourceProfile(name string) (ResourceProfile, error) {
+	if name == "" {
+		name = DefaultResourceProfile()
+	}
+	p, ok := ResourceProfiles[name]
+	if !ok {
+		var names []string
+		for n := range ResourceProfiles {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return ResourceProfile{}, fmt.Errorf("Unknown resource profile %q, expected one of %v", name, names)
+	}
+	return p, nil
+}
+
+// resources returns the profile the node runs with
+func (s *State) resources() ResourceProfile {
+	if s.Resources.Name == "" {
+		s.Resources, _ = GetResourceProfile("")
+	}
+	return s.Resources
+}
@@ -0,0 +1,37 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"testing"
+
+	. "github.com/FactomProject/factomd/state"
+)
+
+func TestGetResourceProfile(t *testing.T) {
+	p, err := GetResourceProfile("small")
+	if err != nil || p.Name != "small" || p.InMsgQueue >= ResourceProfiles["standard"].InMsgQueue {
+		t.Errorf("Unexpected small profile %+v %v", p, err)
+	}
+	p, err = GetResourceProfile("")
+	if err != nil || p.Name != DefaultResourceProfile() {
+		t.Errorf("Expected the default profile, got %+v %v", p, err)
+	}
+	if _, err := GetResourceProfile("huge"); err == nil {
+		t.Errorf("Expected an error for an unknown profile")
+	}
+}
+
+func TestResourceProfileQueues(t *testing.T) {
+	s := new(State)
+	s.LoadConfig("", "LOCAL")
+	s.NodeMode = "SERVER"
+	s.DBType = "Map"
+	s.Resources, _ = GetResourceProfile("small")
+	s.Init()
+	if s.InMsgQueue().Cap() != ResourceProfiles["small"].InMsgQueue {
+		t.Errorf("InMsgQueue has capacity %d", s.InMsgQueue().Cap())
+	}
+}
@@ -4,9 +4,7 @@ import (
 	"fmt"
 	"sync"
 
-	"github.com/FactomProject/factomd/common/constants"
 	"github.com/FactomProject/factomd/common/interfaces"
-	"github.com/FactomProject/factomd/common/messages"
 )
 
 var _ = fmt.Println
@@ -68,52 +66,6 @@ func (m *SafeMsgMap) Reset() {
 	m.Unlock()
 }
 
-//
-// Used if a Commit Map
-//
-
-// Cleanup will clean old elements out from the commit map.
-func (m *SafeMsgMap) Cleanup(s *State) {
-	m.Lock()
-	// Time out commits every now and again. Also check for entries that have been revealed
-	now := s.GetTimestamp()
-	for k, msg := range m.msgmap {
-		{
-			c, ok := msg.(*messages.CommitChainMsg)
-			if ok && !s.NoEntryYet(c.CommitChain.EntryHash, now) {
-				delete(m.msgmap, k)
-				continue
-			}
-		}
-		c, ok := msg.(*messages.CommitEntryMsg)
-		if ok && !s.NoEntryYet(c.CommitEntry.EntryHash, now) {
-			delete(m.msgmap, k)
-			continue
-		}
-
-		_, ok = s.Replay.Valid(constants.TIME_TEST, msg.GetRepeatHash().Fixed(), msg.GetTimestamp(), now)
-		if !ok {
-			delete(m.msgmap, k)
-		}
-	}
-	m.Unlock()
-}
-
-// RemoveExpired is used when treating this as a commit map. Do not
-func (m *SafeMsgMap) RemoveExpired(s *State) {
-	m.Lock()
-	// Time out commits every now and again.
-	for k, v := range m.msgmap {
-		if v != nil {
-			_, ok := s.Replay.Valid(constants.TIME_TEST, v.GetRepeatHash().Fixed(), v.GetTimestamp(), s.GetTimestamp())
-			if !ok {
-				delete(m.msgmap, k)
-			}
-		}
-	}
-	m.Unlock()
-}
-
 //
 // For tests
 //
@@ -65,14 +65,15 @@ type SaveState struct {
 	Saving  bool // True if we are in the process of saving to the database
 	Syncing bool // Looking for messages from leaders to sync
 
-	Replay *Replay
+	Replay  *Replay
+	FReplay *Replay // Block replay filter, so a restarted node still rejects what it has seen
 
 	LeaderTimestamp interfaces.Timestamp
 
 	Holding map[[32]byte]interfaces.IMsg // Hold Messages
 	XReview []interfaces.IMsg            // After the EOM, we must review the messages in Holding
 	Acks    map[[32]byte]interfaces.IMsg // Hold Acknowledgemets
-	Commits *SafeMsgMap                  // map[[32]byte]interfaces.IMsg // Commit Messages
+	Commits *Mempool                     // map[[32]byte]interfaces.IMsg // Commit Messages
 
 	InvalidMessages map[[32]byte]interfaces.IMsg
 
@@ -121,7 +122,7 @@ func (ss *SaveState) Init() {
 		ss.Acks = map[[32]byte]interfaces.IMsg{}
 	}
 	if ss.Commits == nil {
-		ss.Commits = NewSafeMsgMap() // map[[32]byte]interfaces.IMsg{}
+		ss.Commits = NewMempool() // map[[32]byte]interfaces.IMsg{}
 	}
 	if ss.InvalidMessages == nil {
 		ss.InvalidMessages = map[[32]byte]interfaces.IMsg{}
@@ -245,6 +246,7 @@ func (a *SaveState) IsSameAs(b *SaveState) bool {
 	}
 
 	//Replay *Replay
+	//FReplay *Replay
 
 	if a.LeaderTimestamp.IsSameAs(b.LeaderTimestamp) == false {
 		return false
@@ -323,24 +325,14 @@ func SaveFactomdState(state *State, d *DBState) (ss *SaveState) {
 	// state.AddStatus(fmt.Sprintf("Save state at dbht: %d", ss.DBHeight))
 
 	ss.Replay = state.Replay.Save()
+	ss.FReplay = state.FReplay.Save()
 	ss.LeaderTimestamp = d.DirectoryBlock.GetTimestamp()
 
 	ss.FedServers = append(ss.FedServers, pl.FedServers...)
 	ss.AuditServers = append(ss.AuditServers, pl.AuditServers...)
 
-	state.FactoidBalancesPMutex.Lock()
-	ss.FactoidBalancesP = make(map[[32]byte]int64)
-	for k := range state.FactoidBalancesP {
-		ss.FactoidBalancesP[k] = state.FactoidBalancesP[k]
-	}
-	state.FactoidBalancesPMutex.Unlock()
-
-	state.ECBalancesPMutex.Lock()
-	ss.ECBalancesP = make(map[[32]byte]int64)
-	for k := range state.ECBalancesP {
-		ss.ECBalancesP[k] = state.ECBalancesP[k]
-	}
-	state.ECBalancesPMutex.Unlock()
+	ss.FactoidBalancesP = state.FactoidBalancesP.Copy()
+	ss.ECBalancesP = state.ECBalancesP.Copy()
 
 	ss.Identities = append(ss.Identities, state.Identities...)
 	ss.Authorities = append(ss.Authorities, state.Authorities...)
@@ -466,6 +458,7 @@ func (ss *SaveState) TrimBack(state *State, d *DBState) {
 	state.Syncing = pss.Syncing
 
 	state.Replay = pss.Replay.Save()
+	state.FReplay = pss.FReplay.Save()
 
 	return
 	/*
@@ -526,7 +519,9 @@ func (ss *SaveState) TrimBack(state *State, d *DBState) {
 	// DBlock Height at which node has a complete set of eblocks+entries
 	state.EntryBlockDBHeightComplete = pss.EntryBlockDBHeightComplete
 	state.EntryBlockDBHeightProcessing = pss.EntryBlockDBHeightProcessing
+	state.MissingEntryBlocksMutex.Lock()
 	state.MissingEntryBlocks = append(state.MissingEntryBlocks[:0], pss.MissingEntryBlocks...)
+	state.MissingEntryBlocksMutex.Unlock()
 
 	state.EntryBlockDBHeightComplete = pss.EntryDBHeightComplete
 	state.EntryDBHeightComplete = pss.EntryDBHeightComplete
@@ -584,6 +579,7 @@ func (ss *SaveState) RestoreFactomdState(state *State) { //, d *DBState) {
 	//state.AddStatus(fmt.Sprintf("SAVESTATE Restoring the State to dbht: %d", ss.DBHeight))
 
 	state.Replay = ss.Replay.Save()
+	state.FReplay = ss.FReplay.Save()
 	state.LeaderTimestamp = ss.LeaderTimestamp
 
 	pl.FedServers = []interfaces.IServer{}
@@ -591,19 +587,8 @@ func (ss *SaveState) RestoreFactomdState(state *State) { //, d *DBState) {
 	pl.FedServers = append(pl.FedServers, ss.FedServers...)
 	pl.AuditServers = append(pl.AuditServers, ss.AuditServers...)
 
-	state.FactoidBalancesPMutex.Lock()
-	state.FactoidBalancesP = make(map[[32]byte]int64, 0)
-	for k := range ss.FactoidBalancesP {
-		state.FactoidBalancesP[k] = ss.FactoidBalancesP[k]
-	}
-	state.FactoidBalancesPMutex.Unlock()
-
-	state.ECBalancesPMutex.Lock()
-	state.ECBalancesP = make(map[[32]byte]int64, 0)
-	for k := range ss.ECBalancesP {
-		state.ECBalancesP[k] = ss.ECBalancesP[k]
-	}
-	state.ECBalancesPMutex.Unlock()
+	state.FactoidBalancesP.Replace(ss.FactoidBalancesP)
+	state.ECBalancesP.Replace(ss.ECBalancesP)
 
 	state.Identities = append(state.Identities[:0], ss.Identities...)
 	state.Authorities = append(state.Authorities[:0], ss.Authorities...)
@@ -644,6 +629,7 @@ func (ss *SaveState) RestoreFactomdState(state *State) { //, d *DBState) {
 	}
 
 	state.Commits = ss.Commits.Copy() // make(map[[32]byte]interfaces.IMsg)
+	state.Commits.MaxAge, state.Commits.MaxCommits = state.MempoolMaxAge, state.MempoolMaxCommits
 	// for k, c := range ss.Commits {
 	// 	state.Commits[k] = c
 	// }
@@ -820,6 +806,10 @@ func (ss *SaveState) MarshalBinary() ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
+	err = buf.PushBinaryMarshallable(ss.FReplay)
+	if err != nil {
+		return nil, err
+	}
 
 	err = buf.PushBinaryMarshallable(ss.LeaderTimestamp)
 	if err != nil {
@@ -916,7 +906,7 @@ func (ss *SaveState) UnmarshalBinaryData(p []byte) (newData []byte, err error) {
 	ss.ECBalancesP = map[[32]byte]int64{}
 	ss.Holding = map[[32]byte]interfaces.IMsg{}
 	ss.Acks = map[[32]byte]interfaces.IMsg{}
-	ss.Commits = NewSafeMsgMap()
+	ss.Commits = NewMempool()
 	ss.InvalidMessages = map[[32]byte]interfaces.IMsg{}
 
 	ss.FedServers = []interfaces.IServer{}
@@ -1102,6 +1092,11 @@ func (ss *SaveState) UnmarshalBinaryData(p []byte) (newData []byte, err error) {
 	if err != nil {
 		return
 	}
+	ss.FReplay = new(Replay)
+	err = buf.PopBinaryMarshallable(ss.FReplay)
+	if err != nil {
+		return
+	}
 
 	ss.LeaderTimestamp = primitives.NewTimestampFromMilliseconds(0)
 	err = buf.PopBinaryMarshallable(ss.LeaderTimestamp)
@@ -391,3 +391,26 @@ func TestSaveRestore(t *testing.T) {
 
 
 }
+
+func TestSaveStateReplays(t *testing.T) {
+	ss := new(SaveState)
+	ss.LeaderTimestamp = primitives.NewTimestampNow()
+	ss.Init()
+	ss.Replay = RandomReplay()
+	ss.FReplay = RandomReplay()
+
+	b, err := ss.MarshalBinary()
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	ss2 := new(SaveState)
+	if err := ss2.UnmarshalBinary(b); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if !ss.Replay.IsSameAs(ss2.Replay) {
+		t.Error("Replay filter not restored")
+	}
+	if !ss.FReplay.IsSameAs(ss2.FReplay) {
+		t.Error("Block replay filter not restored")
+	}
+}
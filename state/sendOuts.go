@@ -0,0 +1,106 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+)
+
+// How long the sent state of a message is kept, long past the resends of SendOut
+const sendOutMemory = 10 * time.Minute
+
+// SendOutTracker keeps which messages have been broadcast, and counts the calls to
+// SendOut by message type, so the redundant broadcasts of the FollowerExecute paths
+// that call SendOut more than once for the same message can be measured.
+type SendOutTracker struct {
+	mutex sync.Mutex
+	sent  map[[32]byte]time.Time // When each message was first broadcast
+	stats map[byte]*interfaces.SendOutStats
+}
+
+func NewSendOutTracker() *SendOutTracker {
+	t := new(SendOutTracker)
+	t.sent = make(map[[32]byte]time.Time)
+	t.stats = make(map[byte]*interfaces.SendOutStats)
+	return t
+}
+
+// Note records a call to SendOut for the message, and whether it went out
+func (t *SendOutTracker) Note(msg interfaces.IMsg, sent bool, now time.Time) {
+	if t == nil || msg == nil {
+		return
+	}
+	hash := msg.GetMsgHash()
+	if hash == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	s, ok := t.stats[msg.Type()]
+	if !ok {
+		s = &interfaces.SendOutStats{MessageType: msg.Type(), Name: messages.MessageName(msg.Type())}
+		t.stats[msg.Type()] = s
+	}
+	name := s.Name
+
+	if !sent {
+		s.Suppressed++
+		SendOutsVec.WithLabelValues(name, "suppressed").Inc()
+		return
+	}
+	if _, seen := t.sent[hash.Fixed()]; !seen {
+		t.sent[hash.Fixed()] = now
+		s.Broadcasts++
+		SendOutsVec.WithLabelValues(name, "broadcast").Inc()
+		return
+	}
+	s.Repeats++
+	SendOutsVec.WithLabelValues(name, "repeat").Inc()
+}
+
+// Expire forgets the messages first broadcast over sendOutMemory ago
+func (t *SendOutTracker) Expire(now time.Time) {
+	if t == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	for k, first := range t.sent {
+		if now.Sub(first) > sendOutMemory {
+			delete(t.sent, k)
+		}
+	}
+}
+
+// Stats returns the counts of the calls to SendOut, by message type
+func (t *SendOutTracker) Stats() []interfaces.SendOutStats {
+	if t == nil {
+		return nil
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	answer := make([]interfaces.SendOutStats, 0, len(t.stats))
+	for _, s := range t.stats {
+		answer = append(answer, *s)
+	}
+	sort.Slice(answer, func(i, j int) bool { return answer[i].MessageType < answer[j].MessageType })
+	return answer
+}
+
+// NoteSendOut records a call to SendOut for the message, and whether it went out
+func (s *State) NoteSendOut(msg interfaces.IMsg, sent bool) {
+	s.SendOuts.Note(msg, sent, time.Now())
+}
+
+// GetSendOutStats returns the calls to SendOut by message type, duplicates included
+func (s *State) GetSendOutStats() []interfaces.SendOutStats {
+	return s.SendOuts.Stats()
+}
@@ -0,0 +1,82 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/entryCreditBlock"
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
+	. "github.com/FactomProject/factomd/state"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func newSendOutTestMsg(seed string) *messages.CommitEntryMsg {
+	ce := entryCreditBlock.NewCommitEntry()
+	ts, _ := primitives.NewTimestampNow().MarshalBinary()
+	ce.MilliTime.UnmarshalBinary(ts)
+	ce.EntryHash = primitives.Sha([]byte(seed))
+	ce.Credits = 1
+	testHelper.SignCommit(0, ce)
+	msg := new(messages.CommitEntryMsg)
+	msg.CommitEntry = ce
+	return msg
+}
+
+func TestSendOutTracker(t *testing.T) {
+	st := NewSendOutTracker()
+	now := time.Now()
+	a, b := newSendOutTestMsg("a"), newSendOutTestMsg("b")
+
+	st.Note(a, true, now)
+	st.Note(a, true, now)
+	st.Note(a, false, now)
+	st.Note(b, true, now)
+
+	stats := st.Stats()
+	if len(stats) != 1 || stats[0].MessageType != constants.COMMIT_ENTRY_MSG {
+		t.Fatalf("Unexpected stats %v", stats)
+	}
+	if stats[0].Broadcasts != 2 || stats[0].Repeats != 1 || stats[0].Suppressed != 1 {
+		t.Errorf("Expected 2 broadcasts, 1 repeat and 1 suppressed, got %v", stats[0])
+	}
+
+	// Once forgotten, a message is broadcast again
+	st.Expire(now.Add(time.Hour))
+	st.Note(a, true, now.Add(time.Hour))
+	if s := st.Stats()[0]; s.Broadcasts != 3 || s.Repeats != 1 {
+		t.Errorf("Expected the expired message to count as a broadcast, got %v", s)
+	}
+
+	var nilTracker *SendOutTracker
+	nilTracker.Note(a, true, now)
+	nilTracker.Expire(now)
+	if nilTracker.Stats() != nil {
+		t.Error("Expected a nil tracker to count nothing")
+	}
+}
+
+func TestSendOutDuplicates(t *testing.T) {
+	s := testHelper.CreateEmptyTestState()
+	msg := newSendOutTestMsg("sendout")
+
+	// SendOut sends a message five times at most, and suppresses the calls after that
+	for i := 0; i < 6; i++ {
+		msg.SendOut(s, msg)
+	}
+	for _, stats := range s.GetSendOutStats() {
+		if stats.MessageType != constants.COMMIT_ENTRY_MSG {
+			continue
+		}
+		if stats.Broadcasts != 1 || stats.Repeats != 4 || stats.Suppressed != 1 {
+			t.Errorf("Expected 1 broadcast, 4 repeats and 1 suppressed, got %v", stats)
+		}
+		return
+	}
+	t.Error("No calls to SendOut counted")
+}
@@ -0,0 +1,297 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var shutdownLogger = packageLogger.WithFields(log.Fields{"subpack": "shutdown"})
+
+// A node killed mid-minute could leave a fastboot file half written, and lost the commits,
+// reveals and transactions waiting in holding.  Shutting down now goes through steps: new
+// messages are turned away, those already taken are processed, what is left in holding is
+// saved to be taken again at the next boot, and the writes, the fastboot file and the
+// database are flushed and closed, in that order.
+
+// The steps of shutting a node down, in order
+const (
+	ShutdownStepIntake   = "intake"   // Turning away messages from peers and the API
+	ShutdownStepDrain    = "drain"    // Processing the messages already taken
+	ShutdownStepHolding  = "holding"  // Saving the submissions in holding for the next boot
+	ShutdownStepWrites   = "writes"   // Flushing the entries from peers waiting to be written
+	ShutdownStepFastBoot = "fastboot" // Finishing the fastboot save, and stopping saves
+	ShutdownStepDatabase = "database" // Closing the database
+	ShutdownStepComplete = "complete"
+)
+
+var shutdownSteps = []string{
+	ShutdownStepIntake,
+	ShutdownStepDrain,
+	ShutdownStepHolding,
+	ShutdownStepWrites,
+	ShutdownStepFastBoot,
+	ShutdownStepDatabase,
+	ShutdownStepComplete,
+}
+
+// Longest the messages already taken are processed for, if the config doesn't say
+const DefaultShutdownDrainTimeout = 10 * time.Second
+
+// How long the steps after the drain are given before the node is left to exit anyway
+const shutdownFlushTimeout = 30 * time.Second
+
+// ShutdownTracker follows the node through the steps of shutting down, so operators can
+// see what it is waiting on, and the engine knows when it may exit
+type ShutdownTracker struct {
+	mutex    sync.Mutex
+	nodeName string
+	started  time.Time
+	step     string
+	errors   []string
+	done     chan struct{}
+}
+
+func NewShutdownTracker(nodeName string) *ShutdownTracker {
+	t := new(ShutdownTracker)
+	t.nodeName = nodeName
+	t.done = make(chan struct{})
+	return t
+}
+
+// SetStep moves on to the next step of shutting down.  Like the StartupTracker, does
+// nothing on a nil tracker.
+func (t *ShutdownTracker) SetStep(step string) {
+	if t == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.step == "" {
+		t.started = time.Now()
+	}
+	if step == t.step || t.step == ShutdownStepComplete {
+		return
+	}
+	t.step = step
+	shutdownLogger.WithFields(log.Fields{"node-name": t.nodeName, "step": step}).Infof("Shutdown step %s (%d of %d), %.1fs since it began",
+		step, shutdownStepIndex(step), len(shutdownSteps), time.Since(t.started).Seconds())
+	if step == ShutdownStepComplete {
+		close(t.done)
+	}
+}
+
+// Fail records that the current step failed.  The shutdown carries on with the next.
+func (t *ShutdownTracker) Fail(err error) {
+	if t == nil || err == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	shutdownLogger.WithFields(log.Fields{"node-name": t.nodeName, "step": t.step}).Errorf("Shutdown step %s failed: %v", t.step, err)
+	t.errors = append(t.errors, fmt.Sprintf("%s: %v", t.step, err))
+}
+
+// Wait returns true once the shutdown is complete, or false if it isn't within the timeout
+func (t *ShutdownTracker) Wait(timeout time.Duration) bool {
+	if t == nil {
+		return true
+	}
+	select {
+	case <-t.done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// Status returns where we are in shutting down
+func (t *ShutdownTracker) Status() interfaces.ShutdownStatus {
+	var st interfaces.ShutdownStatus
+	if t == nil {
+		return st
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	st.Step = t.step
+	st.StepIndex = shutdownStepIndex(t.step)
+	st.Steps = len(shutdownSteps)
+	if t.step != "" {
+		st.Elapsed = int64(time.Since(t.started).Seconds())
+	}
+	st.Complete = t.step == ShutdownStepComplete
+	st.Errors = append([]string(nil), t.errors...)
+	return st
+}
+
+func shutdownStepIndex(step string) int {
+	for i, s := range shutdownSteps {
+		if s == step {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// GetShutdownStatus reports how far along shutting down the node is
+func (s *State) GetShutdownStatus() interfaces.ShutdownStatus {
+	return s.Shutdown.Status()
+}
+
+// WaitShutdown returns true once the node has shut down, or false if it is taking longer
+// than it should
+func (s *State) WaitShutdown() bool {
+	return s.Shutdown.Wait(s.drainTimeout() + shutdownFlushTimeout)
+}
+
+// IsStopping is true once the node has begun shutting down, and takes no more messages
+func (s *State) IsStopping() bool {
+	return atomic.LoadInt32(&s.stopping) != 0
+}
+
+// shutdown takes the node through the steps of shutting down.  It is run on the consensus
+// thread, which is left once it returns.
+func (s *State) shutdown() {
+	t := s.Shutdown
+
+	t.SetStep(ShutdownStepIntake)
+	atomic.StoreInt32(&s.stopping, 1)
+
+	t.SetStep(ShutdownStepDrain)
+	s.drain()
+
+	t.SetStep(ShutdownStepHolding)
+	if n, err := s.saveHolding(); err != nil {
+		t.Fail(err)
+	} else if n > 0 {
+		shutdownLogger.WithFields(log.Fields{"node-name": s.FactomNodeName, "messages": n}).Info("Saved the submissions in holding for the next boot")
+	}
+
+	t.SetStep(ShutdownStepWrites)
+	t.Fail(s.FlushWrites("shutdown"))
+
+	t.SetStep(ShutdownStepFastBoot)
+	s.StateSaverStruct.StopSaving()
+	if s.StateSaverStruct.FastBoot {
+		t.Fail(s.StateSaverStruct.Flush(s.Network))
+	}
+
+	t.SetStep(ShutdownStepDatabase)
+	t.Fail(s.saveRejections())
+	t.Fail(s.DB.Close())
+	if s.ConsensusRecorder != nil {
+		s.ConsensusRecorder.Close()
+	}
+
+	t.SetStep(ShutdownStepComplete)
+}
+
+// drain processes the messages already taken off the inbound queue, until there is
+// nothing more to do or the drain timeout runs out
+func (s *State) drain() {
+	deadline := time.Now().Add(s.drainTimeout())
+	for time.Now().Before(deadline) {
+		p, b := s.Process(), s.UpdateState()
+		if !p && !b && len(s.msgQueue) == 0 && len(s.ackQueue) == 0 {
+			break
+		}
+	}
+	s.flushAckBatch()
+}
+
+func (s *State) drainTimeout() time.Duration {
+	if s.ShutdownDrainTimeout <= 0 {
+		return DefaultShutdownDrainTimeout
+	}
+	return s.ShutdownDrainTimeout
+}
+
+// HoldingFilename is where the submissions in holding are saved at shutdown
+func HoldingFilename(networkName string, fileLocation string) string {
+	file := fmt.Sprintf("Holding_%s.db", networkName)
+	if fileLocation != "" {
+		return fmt.Sprintf("%v/%v", fileLocation, file)
+	}
+	return file
+}
+
+// saveHolding writes the submissions in holding to the holding file, each as its length
+// and the message, after a hash of them all.  Returns how many were saved.  Only those
+// submitted by users are saved, as they would otherwise have to be submitted again; acks
+// and the like are stale by the next boot.
+func (s *State) saveHolding() (int, error) {
+	var buf bytes.Buffer
+	n := 0
+	held := append([]interfaces.IMsg(nil), s.minuteZeroHeld...)
+	for _, msg := range s.Holding {
+		held = append(held, msg)
+	}
+	for _, msg := range held {
+		if !isSubmission(msg) {
+			continue
+		}
+		data, err := msg.MarshalBinary()
+		if err != nil {
+			continue
+		}
+		binary.Write(&buf, binary.BigEndian, uint32(len(data)))
+		buf.Write(data)
+		n++
+	}
+	if n == 0 {
+		return 0, nil
+	}
+	h := primitives.Sha(buf.Bytes())
+	return n, SaveToFile(append(h.Bytes(), buf.Bytes()...), HoldingFilename(s.Network, s.StateSaverStruct.FastBootLocation))
+}
+
+// restoreHolding queues up the submissions saved from holding at the last shutdown, and
+// removes the file so they are only taken once.  Those that have since made it into a
+// block, or expired, are turned away like any other repeat.
+func (s *State) restoreHolding() {
+	filename := HoldingFilename(s.Network, s.StateSaverStruct.FastBootLocation)
+	b, err := LoadFromFile(filename)
+	if err != nil {
+		return
+	}
+	defer os.Remove(filename)
+
+	logger := shutdownLogger.WithFields(log.Fields{"node-name": s.FactomNodeName, "file": filename})
+	h := primitives.NewZeroHash()
+	b, err = h.UnmarshalBinaryData(b)
+	if err != nil || !h.IsSameAs(primitives.Sha(b)) {
+		logger.Error("Ignoring the saved holding, its integrity hash does not match")
+		return
+	}
+
+	n := 0
+	for len(b) >= 4 {
+		size := binary.BigEndian.Uint32(b)
+		b = b[4:]
+		if uint32(len(b)) < size {
+			break
+		}
+		msg, err := messages.UnmarshalMessage(b[:size])
+		b = b[size:]
+		if err != nil {
+			continue
+		}
+		s.InMsgQueue().Enqueue(msg)
+		n++
+	}
+	logger.Infof("Restored %d messages saved from holding", n)
+}
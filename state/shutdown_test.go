@@ -0,0 +1,60 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/FactomProject/factomd/state"
+)
+
+func TestShutdownTracker(t *testing.T) {
+	tracker := NewShutdownTracker("FNode0")
+	if st := tracker.Status(); st.Step != "" || st.Complete {
+		t.Errorf("Running node reported as shutting down: %+v", st)
+	}
+	if tracker.Wait(10 * time.Millisecond) {
+		t.Error("Waited out a shutdown that never began")
+	}
+
+	tracker.SetStep(ShutdownStepIntake)
+	tracker.SetStep(ShutdownStepDrain)
+	tracker.Fail(errors.New("boom"))
+	tracker.Fail(nil)
+	st := tracker.Status()
+	if st.Step != ShutdownStepDrain || st.StepIndex != 2 || st.Steps != 7 || st.Complete {
+		t.Errorf("Unexpected status %+v", st)
+	}
+	if len(st.Errors) != 1 || st.Errors[0] != "drain: boom" {
+		t.Errorf("Unexpected errors %v", st.Errors)
+	}
+
+	tracker.SetStep(ShutdownStepComplete)
+	if !tracker.Wait(time.Second) || !tracker.Status().Complete {
+		t.Error("Shutdown did not complete")
+	}
+	// Nothing moves it on once complete
+	tracker.SetStep(ShutdownStepDatabase)
+	if tracker.Status().Step != ShutdownStepComplete {
+		t.Error("Moved on past complete")
+	}
+
+	var none *ShutdownTracker
+	none.SetStep(ShutdownStepDrain)
+	if !none.Wait(0) || none.Status().Step != "" {
+		t.Error("A nil tracker should do nothing")
+	}
+}
+
+func TestHoldingFilename(t *testing.T) {
+	if f := HoldingFilename("LOCAL", ""); f != "Holding_LOCAL.db" {
+		t.Errorf("Unexpected filename %s", f)
+	}
+	if f := HoldingFilename("MAIN", "/tmp"); f != "/tmp/Holding_MAIN.db" {
+		t.Errorf("Unexpected filename %s", f)
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"runtime"
+	"sync/atomic"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+)
+
+// How many messages each worker may have waiting, before Submit blocks
+const sigJobsPerWorker = 64
+
+// SigVerifier checks the signatures of messages from peers on a pool of workers before
+// they are put in the in message queue.  The messages remember a good signature, so when
+// the consensus thread validates them it is left with the checks that need the state.
+// Messages come out in the order they were submitted.
+type SigVerifier struct {
+	workers int
+	jobs    chan *sigJob // Picked up by whichever worker is free
+	ordered chan *sigJob // The same jobs, in the order they were submitted
+	pending int32        // Submitted and not yet queued, accessed atomically
+}
+
+type sigJob struct {
+	msg  interfaces.IMsg
+	done chan struct{}
+}
+
+// NewSigVerifier makes a pool of workers, one per CPU if workers isn't positive.  Nothing
+// runs until Run is called.
+func NewSigVerifier(workers int) *SigVerifier {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	v := new(SigVerifier)
+	v.workers = workers
+	v.jobs = make(chan *sigJob, workers*sigJobsPerWorker)
+	v.ordered = make(chan *sigJob, workers*sigJobsPerWorker)
+	return v
+}
+
+// Workers returns the size of the pool
+func (v *SigVerifier) Workers() int {
+	return v.workers
+}
+
+// Pending returns how many messages have been submitted and not yet put in the queue.
+// They count against the in message queue's capacity, as they will all end up in it.
+func (v *SigVerifier) Pending() int {
+	if v == nil {
+		return 0
+	}
+	return int(atomic.LoadInt32(&v.pending))
+}
+
+// Submit hands a message to the pool, blocking while the pool is full.  Returns false if
+// there is no pool, in which case the caller queues the message itself.
+func (v *SigVerifier) Submit(msg interfaces.IMsg) bool {
+	if v == nil {
+		return false
+	}
+	job := &sigJob{msg: msg, done: make(chan struct{})}
+	atomic.AddInt32(&v.pending, 1)
+	v.ordered <- job
+	v.jobs <- job
+	return true
+}
+
+// Run starts the workers and passes the checked messages on to the queue, in order.  It
+// doesn't return.
+func (v *SigVerifier) Run(queue interfaces.IQueue) {
+	for i := 0; i < v.workers; i++ {
+		go v.work()
+	}
+	for job := range v.ordered {
+		<-job.done
+		queue.Enqueue(job.msg)
+		atomic.AddInt32(&v.pending, -1)
+	}
+}
+
+func (v *SigVerifier) work() {
+	for job := range v.jobs {
+		PreVerifyMessage(job.msg)
+		close(job.done)
+	}
+}
+
+// PreVerifyMessage checks the signature of a message that can be checked without the
+// state.  A bad signature is left for Validate to reject, so the message is handled and
+// logged as it always was.
+func PreVerifyMessage(msg interfaces.IMsg) {
+	p, ok := msg.(messages.PreVerifier)
+	if !ok {
+		SigPreVerifications.WithLabelValues("skipped").Inc()
+		return
+	}
+	start := time.Now()
+	if p.PreVerifySignature() {
+		SigPreVerifications.WithLabelValues("valid").Inc()
+	} else {
+		SigPreVerifications.WithLabelValues("invalid").Inc()
+	}
+	SigPreVerifyTime.Observe(time.Since(start).Seconds())
+}
+
+// GoVerifySignatures runs the signature checking pool in front of the in message queue
+func (s *State) GoVerifySignatures() {
+	s.SigVerifier.Run(s.InMsgQueue())
+}
+
+// InMsgBacklog returns how many messages are waiting to be processed, counting those
+// still having their signatures checked, for the inbound backpressure check
+func (s *State) InMsgBacklog() int {
+	return s.InMsgQueue().Length() + s.SigVerifier.Pending()
+}
+
+// QueuePeerMsg puts a message from a peer in the in message queue, by way of the
+// signature checking pool if there is one
+func (s *State) QueuePeerMsg(msg interfaces.IMsg) {
+	if !s.SigVerifier.Submit(msg) {
+		s.InMsgQueue().Enqueue(msg)
+	}
+}
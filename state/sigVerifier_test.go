@@ -0,0 +1,94 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/common/entryCreditBlock"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
+	. "github.com/FactomProject/factomd/state"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func newSigTestCommit(i int, good bool) *messages.CommitEntryMsg {
+	ce := entryCreditBlock.NewCommitEntry()
+	ce.EntryHash = primitives.Sha([]byte{byte(i), byte(i >> 8)})
+	ce.Credits = 1
+	testHelper.SignCommit(0, ce)
+	if !good {
+		ce.Credits = 2
+	}
+	msg := new(messages.CommitEntryMsg)
+	msg.CommitEntry = ce
+	return msg
+}
+
+func TestSigVerifierKeepsOrder(t *testing.T) {
+	queue := NewInMsgQueue(1000)
+	v := NewSigVerifier(4)
+	go v.Run(queue)
+
+	var sent []*messages.CommitEntryMsg
+	for i := 0; i < 500; i++ {
+		msg := newSigTestCommit(i, i%3 != 0)
+		sent = append(sent, msg)
+		if !v.Submit(msg) {
+			t.Fatal("Submit refused a message")
+		}
+	}
+
+	for i, want := range sent {
+		var got interfaces.IMsg = queue.BlockingDequeue()
+		if got != want {
+			t.Fatalf("Message %d came out of order", i)
+		}
+	}
+	for i, msg := range sent {
+		if msg.PreVerifySignature() != (i%3 != 0) {
+			t.Errorf("Message %d has the wrong signature result", i)
+		}
+	}
+}
+
+func TestSigVerifierPending(t *testing.T) {
+	s := testHelper.CreateEmptyTestState()
+	s.SigVerifier = NewSigVerifier(2)
+
+	// Nothing runs the pool, so the messages wait in it, and count as backlog
+	before := s.InMsgBacklog()
+	for i := 0; i < 10; i++ {
+		s.QueuePeerMsg(newSigTestCommit(i, true))
+	}
+	if s.SigVerifier.Pending() != 10 {
+		t.Errorf("Expected 10 pending, got %d", s.SigVerifier.Pending())
+	}
+	if s.InMsgBacklog() != before+10 {
+		t.Errorf("Backlog is %d, expected %d", s.InMsgBacklog(), before+10)
+	}
+
+	var v *SigVerifier
+	if v.Pending() != 0 {
+		t.Error("A nil pool has pending messages")
+	}
+}
+
+func TestQueuePeerMsgWithoutVerifier(t *testing.T) {
+	s := testHelper.CreateEmptyTestState()
+	s.SigVerifier = nil
+
+	var v *SigVerifier
+	if v.Submit(newSigTestCommit(0, true)) {
+		t.Error("A nil pool took a message")
+	}
+
+	before := s.InMsgQueue().Length()
+	s.QueuePeerMsg(newSigTestCommit(1, true))
+	if s.InMsgQueue().Length() != before+1 {
+		t.Error("Message was not queued")
+	}
+}
@@ -8,6 +8,7 @@ package state
 // Each state has its own set of keys that need to match the ones in the
 // identitiy to properly test identities/authorities
 import (
+	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/common/primitives"
 )
 
@@ -19,3 +20,17 @@ func (s *State) SimSetNewKeys(p *primitives.PrivateKey) {
 func (s *State) SimGetSigKey() string {
 	return s.serverPrivKey.Pub.String()
 }
+
+// A brainswap the simulator scheduled.  Simulated nodes usually run without a config file
+// of their own, to set a ChangeAcksHeight in.
+type simBrainswap struct {
+	height  uint32
+	chainID interfaces.IHash
+	privKey string
+}
+
+// SimScheduleBrainswap has the node take on another identity and its key once it reaches
+// height, as it would with ChangeAcksHeight in its config file
+func (s *State) SimScheduleBrainswap(height uint32, chainID interfaces.IHash, privKey string) {
+	s.simBrainswap = &simBrainswap{height: height, chainID: chainID, privKey: privKey}
+}
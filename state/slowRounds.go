@@ -0,0 +1,166 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+)
+
+// The kinds of round we time
+const (
+	SlowRoundEOM   = "EOM"
+	SlowRoundDBSig = "DBSig"
+)
+
+// How many slow rounds are kept for the rolling report
+const MaxSlowRounds = 200
+
+type roundKey struct {
+	kind     string
+	dbheight uint32
+	minute   int
+}
+
+type roundArrival struct {
+	server interfaces.IHash
+	when   int64 // Unix nanoseconds
+}
+
+// SlowRoundTracker times when each federated server's EOM or DBSig is processed in a
+// round, so that when a round runs long we know whose message held it up.
+type SlowRoundTracker struct {
+	mutex    sync.Mutex
+	arrivals map[roundKey][]roundArrival
+	rounds   []interfaces.SlowRound
+}
+
+func NewSlowRoundTracker() *SlowRoundTracker {
+	t := new(SlowRoundTracker)
+	t.arrivals = make(map[roundKey][]roundArrival)
+	return t
+}
+
+// Record notes that server's message for the round has been processed
+func (t *SlowRoundTracker) Record(kind string, dbheight uint32, minute int, server interfaces.IHash) {
+	if t == nil || server == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	k := roundKey{kind, dbheight, minute}
+	t.arrivals[k] = append(t.arrivals[k], roundArrival{server: server, when: time.Now().UnixNano()})
+}
+
+// Complete closes a round that started at start (Unix nanoseconds) and was expected to
+// take window.  If the last message came in after the window, the round is added to the
+// report, and returned.
+func (t *SlowRoundTracker) Complete(kind string, dbheight uint32, minute int, start int64, window time.Duration) *interfaces.SlowRound {
+	if t == nil {
+		return nil
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	k := roundKey{kind, dbheight, minute}
+	arrivals := t.arrivals[k]
+	delete(t.arrivals, k)
+	// Forget rounds that never completed, i.e. when a block was replaced while syncing
+	for old := range t.arrivals {
+		if old.dbheight+2 < dbheight {
+			delete(t.arrivals, old)
+		}
+	}
+
+	if start <= 0 || len(arrivals) == 0 {
+		return nil
+	}
+	last := arrivals[0]
+	for _, a := range arrivals[1:] {
+		if a.when > last.when {
+			last = a
+		}
+	}
+	excess := time.Duration(last.when - start - int64(window))
+	if excess <= 0 {
+		return nil
+	}
+
+	r := interfaces.SlowRound{
+		Kind:        kind,
+		DBHeight:    dbheight,
+		Minute:      minute,
+		LastServer:  last.server.String(),
+		LastArrival: (last.when - start) / int64(time.Millisecond),
+		Window:      int64(window / time.Millisecond),
+		Excess:      int64(excess / time.Millisecond),
+		Timestamp:   time.Now().Unix(),
+	}
+	t.rounds = append(t.rounds, r)
+	if len(t.rounds) > MaxSlowRounds {
+		t.rounds = append([]interfaces.SlowRound{}, t.rounds[len(t.rounds)-MaxSlowRounds:]...)
+	}
+	SlowRoundsVec.WithLabelValues(kind, r.LastServer).Inc()
+	return &r
+}
+
+// Report returns the recent slow rounds, and totals for each server held responsible
+func (t *SlowRoundTracker) Report() interfaces.SlowRoundReport {
+	var report interfaces.SlowRoundReport
+	if t == nil {
+		return report
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	report.Rounds = append([]interfaces.SlowRound{}, t.rounds...)
+	servers := make(map[string]*interfaces.SlowServerStats)
+	for _, r := range t.rounds {
+		st := servers[r.LastServer]
+		if st == nil {
+			st = &interfaces.SlowServerStats{IdentityChainID: r.LastServer}
+			servers[r.LastServer] = st
+		}
+		if r.Kind == SlowRoundDBSig {
+			st.DBSigLast++
+		} else {
+			st.EOMLast++
+		}
+		st.TotalExcess += r.Excess
+		if r.Excess > st.MaxExcess {
+			st.MaxExcess = r.Excess
+		}
+	}
+	for _, st := range servers {
+		report.Servers = append(report.Servers, *st)
+	}
+	sort.Sort(byTotalExcess(report.Servers))
+	return report
+}
+
+// byTotalExcess puts the servers that held things up the most first
+type byTotalExcess []interfaces.SlowServerStats
+
+func (b byTotalExcess) Len() int      { return len(b) }
+func (b byTotalExcess) Swap(i, j int) { b[i], b[j] = b[j], b[i] }
+func (b byTotalExcess) Less(i, j int) bool {
+	if b[i].TotalExcess != b[j].TotalExcess {
+		return b[i].TotalExcess > b[j].TotalExcess
+	}
+	return b[i].IdentityChainID < b[j].IdentityChainID
+}
+
+// roundWindow is how long a minute is expected to take
+func (s *State) roundWindow() time.Duration {
+	return time.Duration(s.DirectoryBlockInSeconds) * time.Second / 10
+}
+
+// GetSlowRounds returns the rolling report of minutes and block starts that ran long
+func (s *State) GetSlowRounds() interfaces.SlowRoundReport {
+	return s.SlowRounds.Report()
+}
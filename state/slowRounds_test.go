@@ -0,0 +1,67 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/FactomProject/factomd/common/primitives"
+	. "github.com/FactomProject/factomd/state"
+)
+
+func TestSlowRoundTracker(t *testing.T) {
+	tr := NewSlowRoundTracker()
+	a := primitives.Sha([]byte("a"))
+	b := primitives.Sha([]byte("b"))
+
+	// A round that finished inside its window isn't reported
+	start := time.Now().UnixNano()
+	tr.Record(SlowRoundEOM, 10, 1, a)
+	tr.Record(SlowRoundEOM, 10, 1, b)
+	if r := tr.Complete(SlowRoundEOM, 10, 1, start, time.Minute); r != nil {
+		t.Errorf("Fast round reported as slow: %+v", r)
+	}
+
+	// b comes in last, well after the window
+	start = time.Now().Add(-2 * time.Second).UnixNano()
+	tr.Record(SlowRoundEOM, 10, 2, a)
+	time.Sleep(5 * time.Millisecond)
+	tr.Record(SlowRoundEOM, 10, 2, b)
+	r := tr.Complete(SlowRoundEOM, 10, 2, start, time.Second)
+	if r == nil {
+		t.Fatal("Slow round not reported")
+	}
+	if r.LastServer != b.String() || r.Window != 1000 || r.Excess < 1000 || r.Minute != 2 {
+		t.Errorf("Unexpected slow round %+v", r)
+	}
+
+	start = time.Now().Add(-2 * time.Second).UnixNano()
+	tr.Record(SlowRoundDBSig, 11, 0, a)
+	if r := tr.Complete(SlowRoundDBSig, 11, 0, start, time.Second); r == nil || r.LastServer != a.String() {
+		t.Errorf("Unexpected slow DBSig round %+v", r)
+	}
+
+	// Rounds without a start time, or without messages, are ignored
+	if r := tr.Complete(SlowRoundEOM, 11, 3, 0, time.Second); r != nil {
+		t.Errorf("Round with no start reported: %+v", r)
+	}
+
+	report := tr.Report()
+	if len(report.Rounds) != 2 || report.Rounds[0].Kind != SlowRoundEOM || report.Rounds[1].Kind != SlowRoundDBSig {
+		t.Fatalf("Unexpected rounds %+v", report.Rounds)
+	}
+	if len(report.Servers) != 2 {
+		t.Fatalf("Unexpected servers %+v", report.Servers)
+	}
+	for _, st := range report.Servers {
+		if st.IdentityChainID == a.String() && (st.DBSigLast != 1 || st.EOMLast != 0) {
+			t.Errorf("Unexpected totals for a %+v", st)
+		}
+		if st.IdentityChainID == b.String() && (st.DBSigLast != 0 || st.EOMLast != 1) {
+			t.Errorf("Unexpected totals for b %+v", st)
+		}
+	}
+}
@@ -0,0 +1,271 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/FactomProject/factomd/common/entryBlock"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+)
+
+// To be increased whenever what a snapshot holds changes
+const snapshotVersion = 1
+
+// A state snapshot is what a node needs to carry on from a saved height without replaying
+// the blocks below it: the last few DBStates as fastboot saves them, with the balances,
+// identities, authorities and replay filters, the blocks of those DBStates, and the head
+// entry block of every chain.  A node bootstrapped from one gets the entry blocks and
+// entries from the snapshot height on from its peers; those below it aren't in its
+// database.
+
+// snapshotHeader opens a snapshot, ahead of the DBStates, so a snapshot of another network
+// is turned away before anything is restored from it
+type snapshotHeader struct {
+	Version   uint32
+	NetworkID uint32
+	DBHeight  uint32
+}
+
+// LoadDatabaseOffline loads the database as LoadDatabase does on boot, with nothing else
+// running, and returns once the blocks are processed
+func (s *State) LoadDatabaseOffline() {
+	s.replayLoadDatabase()
+}
+
+// snapshotDBStates copies the DBStates up to the last saved one holding the state.
+// Returns nil if there isn't one.
+func (s *State) snapshotDBStates() *DBStateList {
+	list := s.DBStates.snapshot()
+	last := -1
+	for i, dbs := range list.DBStates {
+		if dbs == nil || !dbs.Saved {
+			break
+		}
+		if dbs.SaveStruct != nil {
+			last = i
+		}
+	}
+	if last < 0 {
+		return nil
+	}
+	list.DBStates = list.DBStates[:last+1]
+	if list.Complete > uint32(len(list.DBStates)) {
+		list.Complete = uint32(len(list.DBStates))
+	}
+	return list
+}
+
+// snapshotChainHead returns the last entry block of a chain at or below a height, nil if
+// the chain was started above it
+func snapshotChainHead(overlay *databaseOverlay.Overlay, chainID interfaces.IHash, height uint32) (interfaces.IEntryBlock, error) {
+	eb, err := overlay.FetchEBlockHead(chainID)
+	for err == nil && eb != nil && eb.GetDatabaseHeight() > height {
+		prev := eb.GetHeader().GetPrevKeyMR()
+		if prev == nil || prev.IsZero() {
+			return nil, nil
+		}
+		eb, err = overlay.FetchEBlock(prev)
+	}
+	return eb, err
+}
+
+// ExportSnapshot writes a snapshot of the state at the highest saved height.  Returns the
+// height.
+func (s *State) ExportSnapshot(w io.Writer) (uint32, error) {
+	overlay, ok := s.DB.(*databaseOverlay.Overlay)
+	if !ok {
+		return 0, fmt.Errorf("Snapshots need a database overlay")
+	}
+	list := s.snapshotDBStates()
+	if list == nil {
+		return 0, fmt.Errorf("No saved state to snapshot")
+	}
+	height := list.Base + uint32(len(list.DBStates)) - 1
+
+	buf := primitives.NewBuffer(nil)
+	for _, v := range []uint32{snapshotVersion, s.GetNetworkID(), height} {
+		if err := buf.PushUInt32(v); err != nil {
+			return 0, err
+		}
+	}
+	data, err := list.MarshalBinary()
+	if err != nil {
+		return 0, err
+	}
+	if err := buf.PushBytes(data); err != nil {
+		return 0, err
+	}
+	// Restoring the state drops the last DBState, so the blocks go separately
+	if err := buf.PushVarInt(uint64(len(list.DBStates))); err != nil {
+		return 0, err
+	}
+	for _, d := range list.DBStates {
+		msg := messages.NewDBStateMsg(primitives.NewTimestampFromMilliseconds(0), d.DirectoryBlock, d.AdminBlock, d.FactoidBlock, d.EntryCreditBlock, nil, nil, nil)
+		if err := buf.PushBinaryMarshallable(msg); err != nil {
+			return 0, err
+		}
+	}
+
+	chainIDs, err := overlay.FetchAllEBlockChainIDs()
+	if err != nil {
+		return 0, err
+	}
+	var heads []interfaces.IEntryBlock
+	for _, chainID := range chainIDs {
+		head, err := snapshotChainHead(overlay, chainID, height)
+		if err != nil {
+			return 0, err
+		}
+		// Chains started above the snapshot height come from the peers with their blocks
+		if head != nil {
+			heads = append(heads, head)
+		}
+	}
+	if err := buf.PushVarInt(uint64(len(heads))); err != nil {
+		return 0, err
+	}
+	for _, head := range heads {
+		if err := buf.PushBinaryMarshallable(head); err != nil {
+			return 0, err
+		}
+	}
+
+	//adding an integrity check
+	b := buf.DeepCopyBytes()
+	h := primitives.Sha(b)
+	if _, err := w.Write(append(h.Bytes(), b...)); err != nil {
+		return 0, err
+	}
+	return height, nil
+}
+
+// ImportSnapshot bootstraps an empty database from a snapshot: the blocks of its DBStates
+// and the chain heads are written to the database, and the state is restored from it.  It
+// must run after Init and before LoadDatabase, which then carries on from the snapshot.
+// Returns the height of the snapshot.
+func (s *State) ImportSnapshot(r io.Reader) (uint32, error) {
+	overlay, ok := s.DB.(*databaseOverlay.Overlay)
+	if !ok {
+		return 0, fmt.Errorf("Snapshots need a database overlay")
+	}
+	// Nothing below the snapshot can rebuild the state, so a restart needs it from fastboot
+	if !s.StateSaverStruct.FastBoot {
+		return 0, fmt.Errorf("Snapshots are only imported with fastboot on")
+	}
+	if head, err := s.DB.FetchDBlockHead(); err != nil {
+		return 0, err
+	} else if head != nil {
+		return 0, fmt.Errorf("The database already holds directory blocks up to %d; snapshots are imported into an empty one", head.GetDatabaseHeight())
+	}
+
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return 0, err
+	}
+	h := primitives.NewZeroHash()
+	b, err = h.UnmarshalBinaryData(b)
+	if err != nil {
+		return 0, err
+	}
+	if !h.IsSameAs(primitives.Sha(b)) {
+		return 0, fmt.Errorf("The snapshot integrity hash does not match")
+	}
+
+	buf := primitives.NewBuffer(b)
+	var header snapshotHeader
+	for _, v := range []*uint32{&header.Version, &header.NetworkID, &header.DBHeight} {
+		if *v, err = buf.PopUInt32(); err != nil {
+			return 0, err
+		}
+	}
+	if header.Version != snapshotVersion {
+		return 0, fmt.Errorf("Snapshot version %d, expected %d", header.Version, snapshotVersion)
+	}
+	if header.NetworkID != s.GetNetworkID() {
+		return 0, fmt.Errorf("The snapshot is of network %x, not %x", header.NetworkID, s.GetNetworkID())
+	}
+	data, err := buf.PopBytes()
+	if err != nil {
+		return 0, err
+	}
+	n, err := buf.PopVarInt()
+	if err != nil {
+		return 0, err
+	}
+	if n == 0 {
+		return 0, fmt.Errorf("The snapshot holds no blocks")
+	}
+	blocks := make([]*messages.DBStateMsg, 0, int(n))
+	for i := uint64(0); i < n; i++ {
+		msg := new(messages.DBStateMsg)
+		if err := buf.PopBinaryMarshallable(msg); err != nil {
+			return 0, err
+		}
+		blocks = append(blocks, msg)
+	}
+	n, err = buf.PopVarInt()
+	if err != nil {
+		return 0, err
+	}
+	heads := make([]interfaces.IEntryBlock, 0, int(n))
+	for i := uint64(0); i < n; i++ {
+		eb := entryBlock.NewEBlock()
+		if err := buf.PopBinaryMarshallable(eb); err != nil {
+			return 0, err
+		}
+		heads = append(heads, eb)
+	}
+
+	if blocks[len(blocks)-1].DirectoryBlock.GetDatabaseHeight() != header.DBHeight {
+		return 0, fmt.Errorf("The snapshot blocks do not end at %d", header.DBHeight)
+	}
+
+	s.DB.StartMultiBatch()
+	for _, d := range blocks {
+		if err := s.DB.ProcessABlockMultiBatch(d.AdminBlock); err != nil {
+			return 0, err
+		}
+		if err := s.DB.ProcessFBlockMultiBatch(d.FactoidBlock); err != nil {
+			return 0, err
+		}
+		if err := s.DB.ProcessECBlockMultiBatch(d.EntryCreditBlock, false); err != nil {
+			return 0, err
+		}
+		if err := s.DB.ProcessDBlockMultiBatch(d.DirectoryBlock); err != nil {
+			return 0, err
+		}
+	}
+	for _, eb := range heads {
+		if err := s.DB.ProcessEBlockMultiBatch(eb, false); err != nil {
+			return 0, err
+		}
+	}
+	if err := s.DB.ExecuteMultiBatch(); err != nil {
+		return 0, err
+	}
+
+	// Restores the balances, identities, authorities and replay filters
+	if err := s.DBStates.UnmarshalBinary(data); err != nil {
+		return 0, err
+	}
+	s.SnapshotHeight = blocks[0].DirectoryBlock.GetDatabaseHeight()
+	if err := overlay.SaveSnapshotHeight(s.SnapshotHeight); err != nil {
+		return 0, err
+	}
+
+	// Fastboot restores the state from the list as exported on a restart
+	h = primitives.Sha(data)
+	err = SaveToFile(append(h.Bytes(), data...), NetworkIDToFilename(s.Network, s.StateSaverStruct.FastBootLocation))
+	if err != nil {
+		return 0, err
+	}
+	return header.DBHeight, nil
+}
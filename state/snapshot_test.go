@@ -0,0 +1,124 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestSnapshotExportImport(t *testing.T) {
+	s := testHelper.CreatePopulateAndExecuteTestState()
+
+	var snapshot bytes.Buffer
+	height, err := s.ExportSnapshot(&snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if height != s.GetHighestSavedBlk() {
+		t.Errorf("Snapshot at %d, expected the highest saved %d", height, s.GetHighestSavedBlk())
+	}
+
+	dir, err := ioutil.TempDir("", "snapshot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	n := testHelper.CreateEmptyTestState()
+	n.StateSaverStruct.FastBoot = true
+	n.StateSaverStruct.FastBootLocation = dir
+	if _, err := n.ImportSnapshot(bytes.NewReader(snapshot.Bytes())); err != nil {
+		t.Fatal(err)
+	}
+
+	// The last DBState is loaded from the database again, as on a fastboot
+	if l := n.DBStates.Base + uint32(len(n.DBStates.DBStates)); l != height {
+		t.Errorf("Imported DBStates below %d, expected below %d", l, height)
+	}
+	if n.SnapshotHeight != n.DBStates.Base || n.SnapshotHeight > height {
+		t.Errorf("Snapshot height %d, DBStates base %d", n.SnapshotHeight, n.DBStates.Base)
+	}
+	if h, err := n.DB.(*databaseOverlay.Overlay).FetchSnapshotHeight(); err != nil || h != n.SnapshotHeight {
+		t.Errorf("Saved snapshot height %d, expected %d - %v", h, n.SnapshotHeight, err)
+	}
+	// The balances are those the last DBState was saved with; its block is loaded again
+	saved := s.DBStates.DBStates[height-s.DBStates.Base].SaveStruct
+	if n.FactoidBalancesP.Len() != len(saved.FactoidBalancesP) || n.ECBalancesP.Len() != len(saved.ECBalancesP) {
+		t.Errorf("Restored %d factoid and %d EC balances, expected %d and %d", n.FactoidBalancesP.Len(), n.ECBalancesP.Len(),
+			len(saved.FactoidBalancesP), len(saved.ECBalancesP))
+	}
+	for k, v := range saved.FactoidBalancesP {
+		if got, _ := n.FactoidBalancesP.Get(k); got != v {
+			t.Errorf("Factoid balance of %x is %d, expected %d", k, got, v)
+		}
+	}
+	for k, v := range saved.ECBalancesP {
+		if got, _ := n.ECBalancesP.Get(k); got != v {
+			t.Errorf("EC balance of %x is %d, expected %d", k, got, v)
+		}
+	}
+
+	head, err := n.DB.FetchDBlockHead()
+	if err != nil || head == nil || head.GetDatabaseHeight() != height {
+		t.Errorf("Directory block head %v, expected height %d - %v", head, height, err)
+	}
+	// The chain heads are the last entry blocks at or below the snapshot
+	chainIDs, err := s.DB.(*databaseOverlay.Overlay).FetchAllEBlockChainIDs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	heads := 0
+	for _, chainID := range chainIDs {
+		ebs, err := s.DB.FetchAllEBlocksByChain(chainID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var want interfaces.IHash
+		for _, eb := range ebs {
+			if eb.GetDatabaseHeight() <= height {
+				want = eb.DatabasePrimaryIndex()
+			}
+		}
+		got, err := n.DB.FetchHeadIndexByChainID(chainID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want == nil {
+			if got != nil {
+				t.Errorf("Chain %v started above the snapshot has head %v", chainID, got)
+			}
+			continue
+		}
+		heads++
+		if got == nil || !got.IsSameAs(want) {
+			t.Errorf("Chain head of %v is %v, expected %v", chainID, got, want)
+		}
+	}
+	if heads == 0 {
+		t.Error("No chain heads to compare")
+	}
+
+	// A database that isn't empty is left alone
+	if _, err := n.ImportSnapshot(bytes.NewReader(snapshot.Bytes())); err == nil {
+		t.Error("Imported a snapshot into a database that already has blocks")
+	}
+
+	// So is a snapshot that doesn't check out
+	corrupt := append([]byte{}, snapshot.Bytes()...)
+	corrupt[len(corrupt)-1] ^= 0xff
+	e := testHelper.CreateEmptyTestState()
+	e.StateSaverStruct.FastBoot = true
+	e.StateSaverStruct.FastBootLocation = dir
+	if _, err := e.ImportSnapshot(bytes.NewReader(corrupt)); err == nil {
+		t.Error("Imported a corrupt snapshot")
+	}
+}
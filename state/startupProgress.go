@@ -0,0 +1,200 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var startupLogger = packageLogger.WithFields(log.Fields{"subpack": "startup"})
+
+// The phases of booting a node, in order
+const (
+	StartupPhaseFastBoot   = "fastboot"   // Restoring the saved state
+	StartupPhaseDatabase   = "database"   // Reading the saved blocks from the database
+	StartupPhaseValidation = "validation" // Processing the loaded blocks, which also loads the identities
+	StartupPhasePeers      = "peers"      // Waiting for connections to the network
+	StartupPhaseSyncing    = "syncing"    // Catching up with the network
+	StartupPhaseComplete   = "complete"
+)
+
+var startupPhases = []string{
+	StartupPhaseFastBoot,
+	StartupPhaseDatabase,
+	StartupPhaseValidation,
+	StartupPhasePeers,
+	StartupPhaseSyncing,
+	StartupPhaseComplete,
+}
+
+// How often progress within a phase is logged
+const startupLogInterval = 10 * time.Second
+
+// StartupTracker follows the node through the phases of booting, so operators can see
+// what it is doing and how long it will take.
+type StartupTracker struct {
+	mutex      sync.Mutex
+	nodeName   string
+	started    time.Time
+	phase      string
+	phaseStart time.Time
+	done       uint64
+	total      uint64
+	lastLog    time.Time
+}
+
+func NewStartupTracker(nodeName string) *StartupTracker {
+	t := new(StartupTracker)
+	t.nodeName = nodeName
+	t.started = time.Now()
+	t.SetPhase(StartupPhaseFastBoot, 0)
+	return t
+}
+
+// SetPhase moves to a new phase, of total steps if known.  Phases are only ever moved
+// forward.  Like Progress, does nothing on a nil tracker, so states that were never
+// booted (i.e. in tests) don't need one.
+func (t *StartupTracker) SetPhase(phase string, total uint64) {
+	if t == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if phaseIndex(phase) < phaseIndex(t.phase) {
+		return
+	}
+	if phase != t.phase {
+		startupLogger.WithFields(log.Fields{"node-name": t.nodeName, "phase": phase}).Infof("Startup phase %s (%d of %d), %ds since start",
+			phase, phaseIndex(phase), len(startupPhases), int64(time.Since(t.started).Seconds()))
+	}
+	t.phase = phase
+	t.phaseStart = time.Now()
+	t.done = 0
+	t.total = total
+	t.lastLog = time.Now()
+}
+
+// Progress records that done of total steps of the current phase are finished
+func (t *StartupTracker) Progress(done uint64, total uint64) {
+	if t == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.done = done
+	t.total = total
+	if time.Since(t.lastLog) < startupLogInterval {
+		return
+	}
+	t.lastLog = time.Now()
+	st := t.status()
+	startupLogger.WithFields(log.Fields{"node-name": t.nodeName, "phase": st.Phase}).Infof("Startup %s %d/%d (%.1f%%), eta %ds",
+		st.Phase, st.Done, st.Total, st.Percent, st.ETA)
+}
+
+// Phase returns the current phase
+func (t *StartupTracker) Phase() string {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.phase
+}
+
+// Status returns where we are in booting
+func (t *StartupTracker) Status() interfaces.StartupStatus {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.status()
+}
+
+func (t *StartupTracker) status() interfaces.StartupStatus {
+	var st interfaces.StartupStatus
+	st.Phase = t.phase
+	st.PhaseIndex = phaseIndex(t.phase)
+	st.Phases = len(startupPhases)
+	st.Done = t.done
+	st.Total = t.total
+	st.Elapsed = int64(time.Since(t.started).Seconds())
+	st.Complete = t.phase == StartupPhaseComplete
+	st.ETA = -1
+	if st.Complete {
+		st.Percent = 100
+		st.ETA = 0
+	} else if t.total > 0 {
+		done := t.done
+		if done > t.total {
+			done = t.total
+		}
+		st.Percent = 100 * float64(done) / float64(t.total)
+		// Assume the rest of the phase goes as fast as it has so far
+		if done > 0 {
+			spent := time.Since(t.phaseStart).Seconds()
+			st.ETA = int64(spent * float64(t.total-done) / float64(done))
+		}
+	}
+	return st
+}
+
+func phaseIndex(phase string) int {
+	for i, p := range startupPhases {
+		if p == phase {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// GetStartupStatus reports how far along booting the node is
+func (s *State) GetStartupStatus() interfaces.StartupStatus {
+	if s.Startup == nil {
+		return interfaces.StartupStatus{}
+	}
+	return s.Startup.Status()
+}
+
+// GoTrackStartup follows the phases after the database has been read, until the node is
+// in sync with the network.
+func (s *State) GoTrackStartup() {
+	if s.Startup == nil {
+		return
+	}
+	for {
+		time.Sleep(time.Second)
+
+		switch s.Startup.Phase() {
+		case StartupPhaseFastBoot, StartupPhaseDatabase:
+			// LoadDatabase moves us on to validation
+		case StartupPhaseValidation:
+			loaded := atomic.LoadUint32(&s.startupLoadedHeight)
+			saved := s.GetHighestSavedBlk()
+			s.Startup.Progress(uint64(saved), uint64(loaded))
+			if s.DBFinished && saved >= loaded {
+				s.Startup.SetPhase(StartupPhasePeers, 1)
+			}
+		case StartupPhasePeers:
+			// Simulated networks have no p2p controller to wait on
+			peers := 1
+			if s.NetworkControler != nil {
+				peers = s.NetworkControler.GetNumberConnections()
+			}
+			s.Startup.Progress(uint64(peers), 1)
+			if peers > 0 {
+				s.Startup.SetPhase(StartupPhaseSyncing, uint64(s.GetHighestKnownBlock()))
+			}
+		case StartupPhaseSyncing:
+			s.Startup.Progress(uint64(s.GetHighestSavedBlk()), uint64(s.GetHighestKnownBlock()))
+			if s.IsInSync() {
+				s.Startup.SetPhase(StartupPhaseComplete, 0)
+			}
+		case StartupPhaseComplete:
+			return
+		}
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"testing"
+
+	. "github.com/FactomProject/factomd/state"
+)
+
+func TestStartupTracker(t *testing.T) {
+	tr := NewStartupTracker("test")
+	st := tr.Status()
+	if st.Phase != StartupPhaseFastBoot || st.PhaseIndex != 1 || st.Complete || st.ETA != -1 {
+		t.Errorf("Unexpected initial status %+v", st)
+	}
+
+	tr.SetPhase(StartupPhaseDatabase, 200)
+	tr.Progress(50, 200)
+	st = tr.Status()
+	if st.Phase != StartupPhaseDatabase || st.Percent != 25 || st.ETA < 0 {
+		t.Errorf("Unexpected database status %+v", st)
+	}
+
+	// Phases never go backwards
+	tr.SetPhase(StartupPhaseFastBoot, 0)
+	if tr.Phase() != StartupPhaseDatabase {
+		t.Errorf("Phase moved back to %s", tr.Phase())
+	}
+
+	// Progress past the end is capped
+	tr.Progress(300, 200)
+	if st = tr.Status(); st.Percent != 100 || st.ETA != 0 {
+		t.Errorf("Unexpected overrun status %+v", st)
+	}
+
+	tr.SetPhase(StartupPhaseComplete, 0)
+	if st = tr.Status(); !st.Complete || st.Percent != 100 || st.PhaseIndex != st.Phases {
+		t.Errorf("Unexpected complete status %+v", st)
+	}
+
+	// A nil tracker is ignored
+	var none *StartupTracker
+	none.SetPhase(StartupPhaseDatabase, 1)
+	none.Progress(1, 1)
+}
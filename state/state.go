@@ -10,20 +10,24 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
 	"sync"
+	"sync/atomic"
 
 	"crypto/rand"
 	"encoding/binary"
 
 	"github.com/FactomProject/factomd/common/adminBlock"
 	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/factoid"
 	. "github.com/FactomProject/factomd/common/identity"
 	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/common/messages"
 	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/database/badgerdb"
 	"github.com/FactomProject/factomd/database/boltdb"
 	"github.com/FactomProject/factomd/database/databaseOverlay"
 	"github.com/FactomProject/factomd/database/leveldb"
@@ -58,6 +62,7 @@ type State struct {
 	LogPath           string
 	LdbPath           string
 	BoltDBPath        string
+	BadgerPath        string
 	LogLevel          string
 	ConsoleLogLevel   string
 	NodeMode          string
@@ -66,11 +71,17 @@ type State struct {
 	ExportData        bool
 	ExportDataSubpath string
 
+	// Value log GC of a Badger database; see badgerdb.ValueLogGCInterval
+	BadgerValueLogGCInterval     time.Duration
+	BadgerValueLogGCDiscardRatio float64
+
 	LogBits int64 // Bit zero is for logging the Directory Block on DBSig [5]
 
 	DBStatesSent            []*interfaces.DBStateSent
 	DBStatesReceivedBase    int
 	DBStatesReceived        []*messages.DBStateMsg
+	syncServing             []*syncStream // Streams of blocks to nodes catching up
+	syncFollow              syncFollow    // The stream feeding this node as it catches up
 	LocalServerPrivKey      string
 	DirectoryBlockInSeconds int
 	PortNumber              int
@@ -96,6 +107,8 @@ type State struct {
 	LocalNetworkPort        string
 	LocalSeedURL            string
 	LocalSpecialPeers       string
+	P2PEncryption           string
+	P2PCompression          string
 	CustomNetworkID         []byte
 	CustomBootstrapIdentity string
 	CustomBootstrapKey      string
@@ -142,6 +155,106 @@ type State struct {
 	ResendCnt int
 	ExpireCnt int
 
+	DBSaveMutex   sync.Mutex // Held while a block is saved, so backups fall on a block boundary
+	backupRunning int32
+
+	AuthorityStats *AuthorityStatsTracker // Signature statistics of the authorities' messages
+
+	// Chains whose full history is always kept
+	PinnedChainIDs []interfaces.IHash
+	PinnedChains   *PinnedChainTracker
+
+	// Minutes and block starts that ran long, and whose message came last
+	SlowRounds *SlowRoundTracker
+
+	// Trace contexts of messages submitted with one
+	MessageTraces *MessageTraceTracker
+
+	// Progress through booting
+	Startup             *StartupTracker
+	startupLoadedHeight uint32 // Height of the database when it was loaded, accessed atomically
+
+	// Comparison of saved blocks against the anchor records
+	AnchorCheckInterval   time.Duration // Negative disables the checks
+	AnchorCheckReportOnly bool          // Keep running when a mismatch is found
+	AnchorChecks          *AnchorCheckTracker
+
+	// Factoid signatures of blocks at or below the anchored height go unchecked
+	SkipAnchoredSignatures bool
+
+	// Periodic maintenance work, run from the consensus loop
+	Jobs *JobScheduler
+
+	// Delays injected into messages from peers by type, for testing
+	MessageDelays *MessageDelayTracker
+
+	// Signatures of messages from peers checked in parallel before they are queued
+	SigVerifyWorkers int // One per CPU if not positive
+	SigVerifier      *SigVerifier
+
+	// Factoids issued, burned and paid in fees, and the addresses left out of the
+	// circulating supply
+	Supply                  *SupplyTracker
+	NonCirculatingAddresses []interfaces.IHash
+
+	// Changes of the entry credit exchange rate found in the saved blocks
+	ECRateHistory *ECRateHistory
+
+	// Minutes and blocks that took far more or less time than they should have
+	TimingAnomalies *TimingAnomalyTracker
+
+	// Blocks and messages from peers turned away, and why
+	Rejections *RejectionTracker
+
+	// Calls to SendOut by message type, to measure duplicate broadcasts
+	SendOuts *SendOutTracker
+
+	// Entry rate, execution time and database growth, for the capacity report
+	Capacity *CapacityTracker
+
+	// Coinbase payouts of the saved blocks checked against the expected ones
+	CoinbaseAudit *CoinbaseAuditor
+
+	// Entries and entry blocks from peers, waiting to be written in one batch
+	Writes *WriteBatcher
+
+	// Operator defined alert rules, from the config, and where they stand
+	AlertRules []*AlertRule
+	Alerts     *AlertTracker
+
+	// Entries whose receipts are pushed once their anchors are deep enough in Bitcoin
+	ReceiptSubscriptions   *ReceiptSubscriptionTracker
+	ReceiptWebhooksEnabled bool
+
+	// Port of the gRPC API, 0 when it isn't served
+	GrpcPort int
+
+	// Sizes of the queues and caches
+	Resources ResourceProfile
+
+	// Snapshot the API reads from
+	readView atomic.Value
+
+	// Most chains a block built by this leader may create; 0 for no limit.  Not applied on
+	// mainnet.
+	MaxNewChainsPerBlock int
+
+	// Where blocks older than ColdStorageAfter directory blocks are moved; empty keeps
+	// everything in the database
+	ColdStoragePath  string
+	ColdStorageAfter uint32
+
+	// Resend timing for messages in holding, by message type
+	DefaultResendPolicy *ResendPolicy
+	ResendPolicies      map[byte]ResendPolicy
+	heldResends         map[[32]byte]*heldResend
+
+	// Backoff of the asks to peers for what we are missing, by ask
+	AskPolicies map[string]AskPolicy
+
+	// Wait of an idle follower on its queues, doubling while it stays idle
+	idleSleep time.Duration
+
 	tickerQueue            chan int
 	timerMsgQueue          chan interfaces.IMsg
 	TimeOffset             interfaces.Timestamp
@@ -152,11 +265,21 @@ type State struct {
 	apiQueue               APIMSGQueue
 	ackQueue               chan interfaces.IMsg
 	msgQueue               chan interfaces.IMsg
+	dataResponseQueue      chan *messages.DataResponse
 
 	ShutdownChan chan int // For gracefully halting Factom
+	Shutdown     *ShutdownTracker
+	stopping     int32 // Set once shutting down, when no more messages are taken
+
+	// Longest the messages already taken are processed for when shutting down
+	ShutdownDrainTimeout time.Duration
 	JournalFile  string
 	Journaling   bool
 
+	// Every message Process takes, for replaying through a node; see consensusRecord.go
+	ConsensusRecordFile string
+	ConsensusRecorder   *ConsensusRecorder
+
 	serverPrivKey         *primitives.PrivateKey
 	serverPubKey          *primitives.PublicKey
 	serverPendingPrivKeys []*primitives.PrivateKey
@@ -172,6 +295,12 @@ type State struct {
 	factomdTLSCertFile string
 	FactomdLocations   string
 
+	// Uptime beacon config, the beacon is off unless a chain and EC key are given
+	UptimeBeaconChainID      string
+	UptimeBeaconECPrivateKey string
+	UptimeBeaconInterval     int
+	uptimeBeaconsSent        int64 // Written by the beacon goroutine, so accessed atomically
+
 	// Server State
 	StartDelay      int64 // Time in Milliseconds since the last DBState was applied
 	StartDelayLimit int64
@@ -184,6 +313,31 @@ type State struct {
 	IgnoreDone    bool
 	IgnoreMissing bool
 
+	// Peers that must agree with our height before we lead or answer missing message
+	// requests after booting, 0 to not wait on them
+	StartupPeerThreshold int
+	PeerQuorum           *PeerQuorum
+
+	// Delete the entries of directory blocks more than PruneKeepBlocks old
+	Pruning         bool
+	PruneKeepBlocks uint32
+
+	// Index the entries and chains that saved entries refer to
+	IndexReferences bool
+
+	// Index the factoid transactions by the addresses they touch
+	EnableAddressIndex bool
+
+	// Index the entries of each chain by their external IDs
+	IndexExtIDs bool
+
+	// Directory block KeyMRs anchored into an Ethereum contract, if a node is configured
+	EthereumAnchorURL      string
+	EthereumAnchorFrom     string
+	EthereumAnchorContract string
+	EthereumAnchorInterval uint32
+	EthereumAnchors        *EthereumAnchorer
+
 	LLeaderHeight   uint32
 	Leader          bool
 	LeaderVMIndex   int
@@ -235,9 +389,90 @@ type State struct {
 	// For Follower
 	ResendHolding interfaces.Timestamp         // Timestamp to gate resending holding to neighbors
 	Holding       map[[32]byte]interfaces.IMsg // Hold Messages
+	HoldingDeps   *HoldingDependencies         // What messages in Holding are waiting on
+	HoldingSince  map[[32]byte]time.Time       // When each message in Holding first went in
 	XReview       []interfaces.IMsg            // After the EOM, we must review the messages in Holding
 	Acks          map[[32]byte]interfaces.IMsg // Hold Acknowledgemets
-	Commits       *SafeMsgMap                  //  map[[32]byte]interfaces.IMsg // Commit Messages
+	Commits       *Mempool                     // Commit Messages waiting on their reveals
+
+	// Least time between ReviewHolding passes, and the inMsgQueue length above which they
+	// are put off; the defaults if not positive
+	HoldingReviewInterval time.Duration
+	HoldingReviewQueueLow int
+	HoldingReviews        *HoldingReviews
+
+	// The block cache of the operator's cluster we sync from, and whether we serve one
+	ClusterCacheURL   string
+	ClusterCacheServe bool
+	Cluster           *ClusterCache
+
+	// Height of the snapshot the database was bootstrapped from, 0 if it holds the whole
+	// chain.  The blocks below it aren't in the database.
+	SnapshotHeight uint32
+
+	// Eviction policy of the commit mempool, and the most entries a block built by this
+	// leader may hold; 0 for no limit
+	MempoolMaxAge      time.Duration
+	MempoolMaxCommits  int
+	MaxEntriesPerBlock int
+
+	// Commits a minute, and at once, this leader takes from each entry credit address; 0
+	// for no limit
+	CommitRateLimit int
+	CommitRateBurst int
+	CommitRates     *CommitRateLimiter
+
+	// How long a newly promoted leader may take to sync the process list before its first
+	// EOM, without the federation faulting it; 0 for none
+	LeaderGracePeriod time.Duration
+	leaderGrace       *leaderGraceSync
+
+	// What a leader does with submissions arriving while the DBSigs of a block are syncing,
+	// and those it has set aside until they are done
+	MinuteZeroAdmission string
+	minuteZeroHeld      []interfaces.IMsg
+
+	// The health of the node as a leader, and whether it steps down when unhealthy
+	LeaderHealth   *LeaderHealthMonitor
+	AutoStepDown   bool
+	StepDownPolicy LeaderHealthPolicy
+
+	// Object store for co-located services, and its limits
+	ObjectStore           bool
+	ObjectStoreMaxSize    int
+	ObjectStoreMaxObjects int
+
+	// Checkpoint service to fetch signed checkpoints from, and how often, and the
+	// subscription to it; nil if there is none
+	CheckpointURL            string
+	CheckpointRefreshMinutes int
+	Checkpoints              *CheckpointSubscription
+
+	// Proof pack checked at boot, and the height of its last header; 0 for none
+	ProofPackFile   string
+	ProofPackHeight uint32
+
+	// Most of its own acks a leader sends as one batch ack, and those held back for it
+	AckBatchSize int
+	ackBatch     []*messages.Ack
+
+	// Where and how much of the API calls made are logged
+	APIAccessLog interfaces.APIAccessLogConfig
+
+	// Where the API calls that write to the chain are logged
+	APIAuditLog interfaces.APIAuditLogConfig
+
+	// Who authenticates API clients, and what they may do
+	APIAuth interfaces.APIAuthConfig
+
+	// How the deprecated v1 API is served until its sunset
+	APIV1 interfaces.APIV1Config
+
+	// How long an API request may take before it is abandoned, or 0 for no limit
+	APIRequestTimeout time.Duration
+
+	// Peers that throttled us, and those we throttled
+	Backpressure *Backpressure
 
 	InvalidMessages      map[[32]byte]interfaces.IMsg
 	InvalidMessagesMutex sync.RWMutex
@@ -282,12 +517,10 @@ type State struct {
 	NumTransactions int
 
 	// Permanent balances from processing blocks.
-	FactoidBalancesP      map[[32]byte]int64
-	FactoidBalancesPMutex sync.Mutex
-	ECBalancesP           map[[32]byte]int64
-	ECBalancesPMutex      sync.Mutex
-	TempBalanceHash       interfaces.IHash
-	Balancehash           interfaces.IHash
+	FactoidBalancesP *BalanceStore
+	ECBalancesP      *BalanceStore
+	TempBalanceHash  interfaces.IHash
+	Balancehash      interfaces.IHash
 
 	// Web Services
 	Port int
@@ -302,7 +535,8 @@ type State struct {
 	// DBlock Height at which we have started asking for entry blocks
 	EntryBlockDBHeightProcessing uint32
 	// Entry Blocks we don't have that we are asking our neighbors for
-	MissingEntryBlocks []MissingEntryBlock
+	MissingEntryBlocks      []MissingEntryBlock
+	MissingEntryBlocksMutex sync.Mutex
 
 	MissingEntryRepeat interfaces.Timestamp
 	// DBlock Height at which node has a complete set of eblocks+entries
@@ -337,6 +571,8 @@ type State struct {
 	FERPrioritySetHeight uint32
 
 	AckChange uint32
+	// A change of identity the simulator scheduled
+	simBrainswap *simBrainswap
 
 	StateSaverStruct StateSaverStruct
 
@@ -352,6 +588,11 @@ type State struct {
 	HighestCompletedTorrent uint32
 	FastBoot                bool
 	FastBootLocation        string
+
+	// Status transition events pushed to API subscribers
+	StatusEvents *StatusEventHub
+	statusTrack  statusTracker
+	Events       *EventHub // Saved blocks and status changes, filtered for each subscriber
 }
 
 var _ interfaces.IState = (*State)(nil)
@@ -399,7 +640,13 @@ func (s *State) Clone(cloneNumber int) interfaces.IState {
 	newState.LdbPath = s.LdbPath + "/Sim" + number
 	newState.JournalFile = s.LogPath + "/journal" + number + ".log"
 	newState.Journaling = s.Journaling
+	if s.ConsensusRecordFile != "" {
+		newState.ConsensusRecordFile = s.ConsensusRecordFile + number
+	}
 	newState.BoltDBPath = s.BoltDBPath + "/Sim" + number
+	newState.BadgerPath = s.BadgerPath + "/Sim" + number
+	newState.BadgerValueLogGCInterval = s.BadgerValueLogGCInterval
+	newState.BadgerValueLogGCDiscardRatio = s.BadgerValueLogGCDiscardRatio
 	newState.LogLevel = s.LogLevel
 	newState.ConsoleLogLevel = s.ConsoleLogLevel
 	newState.NodeMode = "FULL"
@@ -418,7 +665,50 @@ func (s *State) Clone(cloneNumber int) interfaces.IState {
 	newState.LocalNetworkPort = s.LocalNetworkPort
 	newState.LocalSeedURL = s.LocalSeedURL
 	newState.LocalSpecialPeers = s.LocalSpecialPeers
+	newState.P2PEncryption = s.P2PEncryption
+	newState.P2PCompression = s.P2PCompression
 	newState.StartDelayLimit = s.StartDelayLimit
+	newState.StartupPeerThreshold = s.StartupPeerThreshold
+	newState.Pruning = s.Pruning
+	newState.PruneKeepBlocks = s.PruneKeepBlocks
+	newState.StateSaverStruct.SaveInterval = s.StateSaverStruct.SaveInterval
+	newState.SigVerifyWorkers = s.SigVerifyWorkers
+	newState.IndexReferences = s.IndexReferences
+	newState.EnableAddressIndex = s.EnableAddressIndex
+	newState.IndexExtIDs = s.IndexExtIDs
+	newState.EthereumAnchorURL = s.EthereumAnchorURL
+	newState.EthereumAnchorFrom = s.EthereumAnchorFrom
+	newState.EthereumAnchorContract = s.EthereumAnchorContract
+	newState.EthereumAnchorInterval = s.EthereumAnchorInterval
+	newState.HoldingReviewInterval = s.HoldingReviewInterval
+	newState.HoldingReviewQueueLow = s.HoldingReviewQueueLow
+	newState.ClusterCacheURL = s.ClusterCacheURL
+	newState.ClusterCacheServe = s.ClusterCacheServe
+	newState.MempoolMaxAge = s.MempoolMaxAge
+	newState.MempoolMaxCommits = s.MempoolMaxCommits
+	newState.MaxEntriesPerBlock = s.MaxEntriesPerBlock
+	newState.CommitRateLimit = s.CommitRateLimit
+	newState.CommitRateBurst = s.CommitRateBurst
+	newState.LeaderGracePeriod = s.LeaderGracePeriod
+	newState.MinuteZeroAdmission = s.MinuteZeroAdmission
+	newState.AutoStepDown = s.AutoStepDown
+	newState.SkipAnchoredSignatures = s.SkipAnchoredSignatures
+	newState.StepDownPolicy = s.StepDownPolicy
+	newState.ShutdownDrainTimeout = s.ShutdownDrainTimeout
+	newState.AskPolicies = s.AskPolicies
+	newState.ObjectStore = s.ObjectStore
+	newState.ObjectStoreMaxSize = s.ObjectStoreMaxSize
+	newState.ObjectStoreMaxObjects = s.ObjectStoreMaxObjects
+	newState.CheckpointURL = s.CheckpointURL
+	newState.CheckpointRefreshMinutes = s.CheckpointRefreshMinutes
+	newState.ProofPackFile = s.ProofPackFile
+	newState.ProofPackHeight = s.ProofPackHeight
+	newState.AckBatchSize = s.AckBatchSize
+	newState.APIAccessLog = s.APIAccessLog
+	newState.APIAuditLog = s.APIAuditLog
+	newState.APIAuth = s.APIAuth
+	newState.APIV1 = s.APIV1
+	newState.APIRequestTimeout = s.APIRequestTimeout
 	newState.CustomNetworkID = s.CustomNetworkID
 
 	newState.DirectoryBlockInSeconds = s.DirectoryBlockInSeconds
@@ -434,6 +724,7 @@ func (s *State) Clone(cloneNumber int) interfaces.IState {
 	newState.FaultTimeout = s.FaultTimeout
 	newState.FaultWait = s.FaultWait
 	newState.EOMfaultIndex = s.EOMfaultIndex
+	newState.Resources = s.Resources
 
 	if !config {
 		newState.IdentityChainID = primitives.Sha([]byte(newState.FactomNodeName))
@@ -473,6 +764,10 @@ func (s *State) Clone(cloneNumber int) interfaces.IState {
 		newState.StateSaverStruct.FastBoot = s.StateSaverStruct.FastBoot
 		newState.StateSaverStruct.FastBootLocation = newState.BoltDBPath
 		break
+	case "Badger":
+		newState.StateSaverStruct.FastBoot = s.StateSaverStruct.FastBoot
+		newState.StateSaverStruct.FastBootLocation = newState.BadgerPath
+		break
 	}
 
 	return newState
@@ -562,6 +857,31 @@ func (s *State) GetFactomdLocations() string {
 	return s.FactomdLocations
 }
 
+// GetAPIAccessLog returns where and how much of the API calls made are logged
+func (s *State) GetAPIAccessLog() interfaces.APIAccessLogConfig {
+	return s.APIAccessLog
+}
+
+// GetAPIAuditLog returns where the API calls that write to the chain are logged
+func (s *State) GetAPIAuditLog() interfaces.APIAuditLogConfig {
+	return s.APIAuditLog
+}
+
+// GetAPIAuth returns who authenticates API clients, and what they may do
+func (s *State) GetAPIAuth() interfaces.APIAuthConfig {
+	return s.APIAuth
+}
+
+// GetAPIV1 returns how the deprecated v1 API is served
+func (s *State) GetAPIV1() interfaces.APIV1Config {
+	return s.APIV1
+}
+
+// GetAPIRequestTimeout returns how long an API request may take, or 0 for no limit
+func (s *State) GetAPIRequestTimeout() time.Duration {
+	return s.APIRequestTimeout
+}
+
 func (s *State) GetCurrentBlockStartTime() int64 {
 	return s.CurrentBlockStartTime
 }
@@ -623,15 +943,22 @@ func (s *State) LoadConfig(filename string, networkFlag string) {
 		// TODO: improve the paths after milestone 1
 		cfg.App.LdbPath = cfg.App.HomeDir + networkName + cfg.App.LdbPath
 		cfg.App.BoltDBPath = cfg.App.HomeDir + networkName + cfg.App.BoltDBPath
+		cfg.App.BadgerPath = cfg.App.HomeDir + networkName + cfg.App.BadgerPath
 		cfg.App.DataStorePath = cfg.App.HomeDir + networkName + cfg.App.DataStorePath
 		cfg.Log.LogPath = cfg.App.HomeDir + networkName + cfg.Log.LogPath
 		cfg.App.ExportDataSubpath = cfg.App.HomeDir + networkName + cfg.App.ExportDataSubpath
 		cfg.App.PeersFile = cfg.App.HomeDir + networkName + cfg.App.PeersFile
+		if cfg.App.ProofPackFile != "" && !filepath.IsAbs(cfg.App.ProofPackFile) {
+			cfg.App.ProofPackFile = cfg.App.HomeDir + networkName + cfg.App.ProofPackFile
+		}
 		cfg.App.ControlPanelFilesPath = cfg.App.HomeDir + cfg.App.ControlPanelFilesPath
 
 		s.LogPath = cfg.Log.LogPath + s.Prefix
 		s.LdbPath = cfg.App.LdbPath + s.Prefix
 		s.BoltDBPath = cfg.App.BoltDBPath + s.Prefix
+		s.BadgerPath = cfg.App.BadgerPath + s.Prefix
+		s.BadgerValueLogGCInterval = time.Duration(cfg.App.BadgerValueLogGCInterval) * time.Second
+		s.BadgerValueLogGCDiscardRatio = cfg.App.BadgerValueLogGCDiscardRatio
 		s.LogLevel = cfg.Log.LogLevel
 		s.ConsoleLogLevel = cfg.Log.ConsoleLogLevel
 		s.NodeMode = cfg.App.NodeMode
@@ -650,6 +977,8 @@ func (s *State) LoadConfig(filename string, networkFlag string) {
 		s.LocalNetworkPort = cfg.App.LocalNetworkPort
 		s.LocalSeedURL = cfg.App.LocalSeedURL
 		s.LocalSpecialPeers = cfg.App.LocalSpecialPeers
+		s.P2PEncryption = cfg.App.P2PEncryption
+		s.P2PCompression = cfg.App.P2PCompression
 		s.LocalServerPrivKey = cfg.App.LocalServerPrivKey
 		s.FactoshisPerEC = cfg.App.ExchangeRate
 		s.DirectoryBlockInSeconds = cfg.App.DirectoryBlockInSeconds
@@ -661,6 +990,172 @@ func (s *State) LoadConfig(filename string, networkFlag string) {
 		s.StateSaverStruct.FastBootLocation = cfg.App.FastBootLocation
 		s.FastBoot = cfg.App.FastBoot
 		s.FastBootLocation = cfg.App.FastBootLocation
+		s.UptimeBeaconChainID = cfg.App.UptimeBeaconChainID
+		s.UptimeBeaconECPrivateKey = cfg.App.UptimeBeaconECPrivateKey
+		s.UptimeBeaconInterval = cfg.App.UptimeBeaconInterval
+		if cfg.App.ResendMaxCount > MaxResendCount {
+			packageLogger.Warnf("ResendMaxCount of %d in config is over the %d resends a message gets", cfg.App.ResendMaxCount, MaxResendCount)
+		}
+		if cfg.App.ResendInterval > 0 {
+			s.DefaultResendPolicy = &ResendPolicy{
+				Interval:   cfg.App.ResendInterval,
+				Jitter:     cfg.App.ResendJitter,
+				MaxResends: cfg.App.ResendMaxCount,
+			}
+		}
+		policies, err := ParseResendPolicies(cfg.App.ResendPolicies)
+		if err != nil {
+			packageLogger.Errorf("Ignoring ResendPolicies in config: %v", err)
+		} else {
+			s.ResendPolicies = policies
+		}
+		asks, err := ParseAskPolicies(cfg.App.AskPolicies)
+		if err != nil {
+			packageLogger.Errorf("Ignoring AskPolicies in config: %v", err)
+		} else {
+			s.AskPolicies = asks
+		}
+		if err := primitives.UseCryptoProvider(cfg.App.CryptoProvider); err != nil {
+			packageLogger.Errorf("Ignoring CryptoProvider in config: %v", err)
+		}
+		pinned, err := ParsePinnedChains(cfg.App.PinnedChains)
+		if err != nil {
+			packageLogger.Errorf("Ignoring PinnedChains in config: %v", err)
+		} else {
+			s.PinnedChainIDs = pinned
+		}
+		s.AnchorCheckInterval = time.Duration(cfg.App.AnchorCheckInterval) * time.Second
+		s.AnchorCheckReportOnly = cfg.App.AnchorCheckReportOnly
+		s.SkipAnchoredSignatures = cfg.App.SkipAnchoredSignatures
+		s.MaxNewChainsPerBlock = cfg.App.MaxNewChainsPerBlock
+		s.ColdStoragePath = cfg.App.ColdStoragePath
+		if cfg.App.ColdStorageAfter > 0 {
+			s.ColdStorageAfter = uint32(cfg.App.ColdStorageAfter)
+		}
+		resources, err := GetResourceProfile(cfg.App.ResourceProfile)
+		if err != nil {
+			packageLogger.Errorf("Ignoring ResourceProfile in config: %v", err)
+		} else {
+			s.Resources = resources
+		}
+		nonCirculating, err := ParseFactoidAddresses(cfg.App.NonCirculatingAddresses)
+		if err != nil {
+			packageLogger.Errorf("Ignoring NonCirculatingAddresses in config: %v", err)
+		} else {
+			s.NonCirculatingAddresses = nonCirculating
+		}
+		alerts, err := ParseAlertRules(cfg.Alert)
+		if err != nil {
+			packageLogger.Errorf("Ignoring Alert sections in config: %v", err)
+		} else {
+			s.AlertRules = alerts
+		}
+		s.ReceiptWebhooksEnabled = cfg.App.ReceiptWebhooksEnabled
+		s.GrpcPort = cfg.App.GrpcPort
+		s.StartupPeerThreshold = cfg.App.StartupPeerThreshold
+		s.Pruning = cfg.App.Pruning
+		if cfg.App.PruneKeepBlocks > 0 {
+			s.PruneKeepBlocks = uint32(cfg.App.PruneKeepBlocks)
+		}
+		if cfg.App.FastBootSaveInterval > 0 {
+			s.StateSaverStruct.SaveInterval = uint32(cfg.App.FastBootSaveInterval)
+		}
+		s.SigVerifyWorkers = cfg.App.SigVerifyWorkers
+		s.IndexReferences = cfg.App.IndexReferences
+		s.EnableAddressIndex = cfg.App.EnableAddressIndex
+		s.IndexExtIDs = cfg.App.IndexExtIDs
+		s.EthereumAnchorURL = cfg.App.EthereumAnchorURL
+		s.EthereumAnchorFrom = cfg.App.EthereumAnchorFrom
+		s.EthereumAnchorContract = cfg.App.EthereumAnchorContract
+		if cfg.App.EthereumAnchorInterval > 0 {
+			s.EthereumAnchorInterval = uint32(cfg.App.EthereumAnchorInterval)
+		}
+		s.HoldingReviewInterval = time.Duration(cfg.App.HoldingReviewInterval) * time.Millisecond
+		s.HoldingReviewQueueLow = cfg.App.HoldingReviewQueueLow
+		s.ClusterCacheURL = cfg.App.ClusterCacheURL
+		s.ClusterCacheServe = cfg.App.ClusterCacheServe
+		s.MempoolMaxAge = time.Duration(cfg.App.MempoolMaxAge) * time.Second
+		s.MempoolMaxCommits = cfg.App.MempoolMaxCommits
+		s.MaxEntriesPerBlock = cfg.App.MaxEntriesPerBlock
+		s.CommitRateLimit = cfg.App.CommitRateLimit
+		s.CommitRateBurst = cfg.App.CommitRateBurst
+		s.LeaderGracePeriod = time.Duration(cfg.App.LeaderGracePeriod) * time.Second
+		if policy, err := ParseMinuteZeroAdmission(cfg.App.MinuteZeroAdmission); err != nil {
+			packageLogger.Errorf("Ignoring MinuteZeroAdmission in config: %v", err)
+		} else {
+			s.MinuteZeroAdmission = policy
+		}
+		s.AutoStepDown = cfg.App.AutoStepDown
+		s.StepDownPolicy = LeaderHealthPolicy{
+			MaxLag:         time.Duration(cfg.App.StepDownLag) * time.Second,
+			MaxMissingAsks: cfg.App.StepDownMissingAsks,
+			MaxDiskLatency: time.Duration(cfg.App.StepDownDiskLatency) * time.Millisecond,
+			Minutes:        cfg.App.StepDownMinutes,
+		}
+		s.ShutdownDrainTimeout = time.Duration(cfg.App.ShutdownDrainTimeout) * time.Second
+		s.ObjectStore = cfg.App.ObjectStore
+		s.ObjectStoreMaxSize = cfg.App.ObjectStoreMaxSize
+		s.ObjectStoreMaxObjects = cfg.App.ObjectStoreMaxObjects
+		s.CheckpointURL = cfg.App.CheckpointURL
+		s.CheckpointRefreshMinutes = cfg.App.CheckpointRefreshMinutes
+		s.ProofPackFile = cfg.App.ProofPackFile
+		s.AckBatchSize = cfg.App.AckBatchSize
+		s.APIAccessLog = interfaces.APIAccessLogConfig{
+			Path:       cfg.App.APIAccessLog,
+			SampleRate: cfg.App.APIAccessLogSampleRate,
+			Anonymize:  cfg.App.APIAccessLogAnonymize,
+			MaxSize:    cfg.App.APIAccessLogMaxSize,
+			MaxFiles:   cfg.App.APIAccessLogMaxFiles,
+		}
+		for _, name := range strings.Split(cfg.App.APIAccessLogRedact, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				s.APIAccessLog.Redact = append(s.APIAccessLog.Redact, name)
+			}
+		}
+		s.APIAuditLog = interfaces.APIAuditLogConfig{
+			Path:     cfg.App.APIAuditLog,
+			MaxSize:  cfg.App.APIAuditLogMaxSize,
+			MaxFiles: cfg.App.APIAuditLogMaxFiles,
+		}
+		s.APIAuth = interfaces.APIAuthConfig{
+			Provider:         cfg.App.APIAuthProvider,
+			IntrospectionURL: cfg.App.OIDCIntrospectionURL,
+			ClientID:         cfg.App.OIDCClientID,
+			ClientSecret:     cfg.App.OIDCClientSecret,
+			RolesClaim:       cfg.App.OIDCRolesClaim,
+			RolePermissions:  make(map[string][]string),
+			CacheSeconds:     cfg.App.OIDCCacheSeconds,
+		}
+		// Roles are separated by semicolons, each a role, a colon, and its permissions
+		for _, grant := range strings.Split(cfg.App.OIDCRolePermissions, ";") {
+			parts := strings.SplitN(grant, ":", 2)
+			role := strings.TrimSpace(parts[0])
+			if role == "" || len(parts) < 2 {
+				continue
+			}
+			for _, permission := range strings.Split(parts[1], ",") {
+				if permission = strings.TrimSpace(permission); permission != "" {
+					s.APIAuth.RolePermissions[role] = append(s.APIAuth.RolePermissions[role], permission)
+				}
+			}
+		}
+
+		s.APIV1 = interfaces.APIV1Config{
+			Notice:            cfg.App.APIV1Notice,
+			RefuseAfterSunset: cfg.App.APIV1RefuseAfterSunset,
+		}
+		if cfg.App.APIV1Sunset != "" {
+			if sunset, err := time.Parse("2006-01-02", cfg.App.APIV1Sunset); err != nil {
+				packageLogger.Errorf("Ignoring APIV1Sunset in config: %v", err)
+			} else {
+				s.APIV1.Sunset = sunset
+			}
+		}
+		if cfg.App.APIRequestTimeout < 0 {
+			packageLogger.Errorf("Ignoring APIRequestTimeout in config: %d seconds", cfg.App.APIRequestTimeout)
+		} else {
+			s.APIRequestTimeout = time.Duration(cfg.App.APIRequestTimeout) * time.Second
+		}
 
 		s.FactomdTLSEnable = cfg.App.FactomdTlsEnabled
 		if cfg.App.FactomdTlsPrivateKey == "/full/path/to/factomdAPIpriv.key" {
@@ -696,6 +1191,9 @@ func (s *State) LoadConfig(filename string, networkFlag string) {
 		s.LogPath = "database/"
 		s.LdbPath = "database/ldb"
 		s.BoltDBPath = "database/bolt"
+		s.BadgerPath = "database/badger"
+		s.BadgerValueLogGCInterval = badgerdb.ValueLogGCInterval
+		s.BadgerValueLogGCDiscardRatio = badgerdb.ValueLogGCDiscardRatio
 		s.LogLevel = "none"
 		s.ConsoleLogLevel = "standard"
 		s.NodeMode = "SERVER"
@@ -713,6 +1211,8 @@ func (s *State) LoadConfig(filename string, networkFlag string) {
 		s.LocalNetworkPort = "8110"
 		s.LocalSeedURL = "https://raw.githubusercontent.com/FactomProject/factomproject.github.io/master/seed/localseed.txt"
 		s.LocalSpecialPeers = ""
+		s.P2PEncryption = "off"
+		s.P2PCompression = "snappy"
 
 		s.LocalServerPrivKey = "4c38c72fc5cdad68f13b74674d3ffb1f3d63a112710868c9b08946553448d26d"
 		s.FactoshisPerEC = 006666
@@ -785,15 +1285,48 @@ func (s *State) Init() {
 	s.TimeOffset = new(primitives.Timestamp)                   //interfaces.Timestamp(int64(rand.Int63() % int64(time.Microsecond*10)))
 	s.networkInvalidMsgQueue = make(chan interfaces.IMsg, 100) //incoming message queue from the network messages
 	s.InvalidMessages = make(map[[32]byte]interfaces.IMsg, 0)
-	s.networkOutMsgQueue = NewNetOutMsgQueue(1000)      //Messages to be broadcast to the network
-	s.inMsgQueue = NewInMsgQueue(10000)                 //incoming message queue for factom application messages
-	s.apiQueue = NewAPIQueue(100)                       //incoming message queue from the API
-	s.ackQueue = make(chan interfaces.IMsg, 100)        //queue of Leadership messages
-	s.msgQueue = make(chan interfaces.IMsg, 400)        //queue of Follower messages
-	s.ShutdownChan = make(chan int, 1)                  //Channel to gracefully shut down.
-	s.MissingEntries = make(chan *MissingEntry, 1000)   //Entries I discover are missing from the database
-	s.UpdateEntryHash = make(chan *EntryUpdate, 10000)  //Handles entry hashes and updating Commit maps.
-	s.WriteEntry = make(chan interfaces.IEBEntry, 3000) //Entries to be written to the database
+	res := s.resources()
+	s.networkOutMsgQueue = NewNetOutMsgQueue(1000)                        //Messages to be broadcast to the network
+	s.inMsgQueue = NewInMsgQueue(res.InMsgQueue)                          //incoming message queue for factom application messages
+	s.apiQueue = NewAPIQueue(100)                                         //incoming message queue from the API
+	s.ackQueue = make(chan interfaces.IMsg, 100)                          //queue of Leadership messages
+	s.msgQueue = make(chan interfaces.IMsg, res.MsgQueue)                 //queue of Follower messages
+	s.ShutdownChan = make(chan int, 1)                                    //Channel to gracefully shut down.
+	s.MissingEntries = make(chan *MissingEntry, res.MissingEntryQueue)    //Entries I discover are missing from the database
+	s.UpdateEntryHash = make(chan *EntryUpdate, res.UpdateEntryHashQueue) //Handles entry hashes and updating Commit maps.
+	s.WriteEntry = make(chan interfaces.IEBEntry, res.WriteEntryQueue)    //Entries to be written to the database
+
+	s.dataResponseQueue = make(chan *messages.DataResponse, 1000) //Entry and EBlock responses waiting on the DataResponse worker
+	s.StatusEvents = NewStatusEventHub()                          //Status transitions pushed to API subscribers
+	s.Events = NewEventHub()                                      //Saved blocks and status changes pushed to API subscribers
+	s.AuthorityStats = NewAuthorityStatsTracker()                 //Signature statistics per authority
+	s.PinnedChains = NewPinnedChainTracker(s.PinnedChainIDs)      //Chains whose history we always keep
+	s.SlowRounds = NewSlowRoundTracker()                          //Rounds that ran long, by server
+	s.MessageTraces = NewMessageTraceTracker()                    //Trace contexts from API submissions
+	s.AnchorChecks = NewAnchorCheckTracker()                      //Saved blocks compared against the anchors
+	s.Jobs = NewJobScheduler()                                    //Periodic maintenance work
+	s.MessageDelays = NewMessageDelayTracker()                    //Delays injected into messages from peers
+	s.Supply = NewSupplyTracker()                                 //Factoid supply counted from the saved blocks
+	s.ECRateHistory = NewECRateHistory()                          //Exchange rate changes found in the saved blocks
+	s.TimingAnomalies = NewTimingAnomalyTracker()                 //Minutes and blocks that took too little or too long
+	s.Writes = NewWriteBatcher()                                  //Entries and entry blocks from peers written in batches
+	s.Alerts = NewAlertTracker(s.AlertRules)                      //Operator defined alert rules
+	s.ReceiptSubscriptions = NewReceiptSubscriptionTracker()      //Receipts pushed once anchors are confirmed
+	s.CoinbaseAudit = NewCoinbaseAuditor(ExpectedCoinbase)        //Coinbase payouts checked against the expected ones
+	s.PeerQuorum = NewPeerQuorum(s.StartupPeerThreshold)          //Peers agreeing with our height at startup
+	s.SigVerifier = NewSigVerifier(s.SigVerifyWorkers)            //Signatures of messages from peers checked off the consensus thread
+	s.EthereumAnchors = s.newEthereumAnchorer()                   //Directory blocks anchored into Ethereum, nil if not configured
+	s.HoldingReviews = NewHoldingReviews()                        //Summaries of the passes over holding, and forced passes
+	s.Cluster = s.newClusterCache()                               //Block cache of the operator's cluster, nil if not configured
+	s.Backpressure = NewBackpressure()                            //Peers that throttled us, and those we throttled
+	s.CommitRates = s.newCommitRateLimiter()                      //Commits taken from each EC address as a leader, nil if not limited
+	s.Rejections = NewRejectionTracker()                          //Blocks and messages turned away, and why
+	s.SendOuts = NewSendOutTracker()                              //Messages broadcast, and duplicate calls to SendOut
+	s.Capacity = NewCapacityTracker(s.databasePath())             //Entry rate, execution time and database growth
+	s.LeaderHealth = NewLeaderHealthMonitor()                     //Lag, missing messages and disk latency a minute at a time
+	s.Checkpoints = s.newCheckpointSubscription()                 //Signed checkpoints from the checkpoint service, nil if not configured
+	s.loadProofPack()                                             //Checkpoints from the proof pack of the network, if there is one
+	s.addMaintenanceJobs()
 
 	if s.Journaling {
 		f, err := os.Create(s.JournalFile)
@@ -803,18 +1336,28 @@ func (s *State) Init() {
 		}
 		f.Close()
 	}
+	if s.ConsensusRecordFile != "" {
+		recorder, err := NewConsensusRecorder(s.ConsensusRecordFile)
+		if err != nil {
+			fmt.Println("Could not create the consensus record:", err)
+		} else {
+			s.ConsensusRecorder = recorder
+		}
+	}
 	// Set up struct to stop replay attacks
 	s.Replay = new(Replay)
 	s.FReplay = new(Replay)
 
 	// Set up maps for the followers
 	s.Holding = make(map[[32]byte]interfaces.IMsg)
+	s.HoldingDeps = NewHoldingDependencies()
+	s.HoldingSince = make(map[[32]byte]time.Time)
 	s.Acks = make(map[[32]byte]interfaces.IMsg)
-	s.Commits = NewSafeMsgMap() //make(map[[32]byte]interfaces.IMsg)
+	s.Commits = s.newMempool()
 
 	// Setup the FactoidState and Validation Service that holds factoid and entry credit balances
-	s.FactoidBalancesP = map[[32]byte]int64{}
-	s.ECBalancesP = map[[32]byte]int64{}
+	s.FactoidBalancesP = NewBalanceStore()
+	s.ECBalancesP = NewBalanceStore()
 
 	fs := new(FactoidState)
 	fs.State = s
@@ -841,8 +1384,13 @@ func (s *State) Init() {
 		s.Println("\n   +-------------------------+")
 		s.Println("   |       Leader Node       |")
 		s.Print("   +-------------------------+\n\n")
+	case RoleReadReplica:
+		s.becomeReadReplica()
+		s.Println("\n   +---------------------------+")
+		s.Println("   +------- Read Replica ------+")
+		s.Print("   +---------------------------+\n\n")
 	default:
-		panic("Bad Node Mode (must be FULL or SERVER)")
+		panic("Bad Node Mode (must be FULL, SERVER or REPLICA)")
 	}
 
 	//Database
@@ -855,6 +1403,10 @@ func (s *State) Init() {
 		if err := s.InitBoltDB(); err != nil {
 			panic(fmt.Sprintf("Error initializing the database: %v", err))
 		}
+	case "Badger":
+		if err := s.InitBadgerDB(); err != nil {
+			panic(fmt.Sprintf("Error initializing the database: %v", err))
+		}
 	case "Map":
 		if err := s.InitMapDB(); err != nil {
 			panic(fmt.Sprintf("Error initializing the database: %v", err))
@@ -881,6 +1433,7 @@ func (s *State) Init() {
 	default:
 		panic("Bad value for Network in factomd.conf")
 	}
+	factoid.SetMultisigHeight(constants.ActivationHeight(constants.ACTIVATION_MULTISIG_RCD, s.GetNetworkID()))
 
 	s.Println("\nRunning on the ", s.Network, "Network")
 	s.Println("\nExchange rate chain id set to ", s.FERChainId)
@@ -902,6 +1455,16 @@ func (s *State) Init() {
 	}
 	// end of FER removal
 	s.starttime = time.Now()
+	s.Startup = NewStartupTracker(s.FactomNodeName)
+	s.Shutdown = NewShutdownTracker(s.FactomNodeName)
+
+	if overlay, ok := s.DB.(*databaseOverlay.Overlay); ok {
+		h, err := overlay.FetchSnapshotHeight()
+		if err != nil {
+			panic(err)
+		}
+		s.SnapshotHeight = h
+	}
 
 	if s.StateSaverStruct.FastBoot {
 		d, err := s.DB.FetchDBlockHead()
@@ -909,7 +1472,8 @@ func (s *State) Init() {
 			panic(err)
 		}
 
-		if d == nil || d.GetDatabaseHeight() < 2000 {
+		// A database bootstrapped from a snapshot can't rebuild the state without fastboot
+		if s.SnapshotHeight == 0 && (d == nil || d.GetDatabaseHeight() < 2000) {
 			//If we have less than 2k blocks, we wipe SaveState
 			//This is to ensure we don't accidentally keep SaveState while deleting a database
 			s.StateSaverStruct.DeleteSaveState(s.Network)
@@ -1032,6 +1596,10 @@ func (s *State) Needed(eb interfaces.IEntryBlock) bool {
 }
 
 func (s *State) ValidatePrevious(dbheight uint32) error {
+	// The blocks below the snapshot we were bootstrapped from aren't in the database
+	if dbheight <= s.SnapshotHeight {
+		return nil
+	}
 	dblk, err := s.DB.FetchDBlockByHeight(dbheight)
 	errs := ""
 	if dblk != nil && err == nil && dbheight > 0 {
@@ -1736,6 +2304,9 @@ func (s *State) UpdateState() (progress bool) {
 	s.fillHoldingMap()
 	s.fillAcksMap()
 
+	// let any status subscribers know if we have changed state
+	s.checkStatusTransitions()
+
 entryHashProcessing:
 	for {
 		select {
@@ -1933,7 +2504,6 @@ func (s *State) SetIsDoneReplaying() {
 // Returns a millisecond timestamp
 func (s *State) GetTimestamp() interfaces.Timestamp {
 	if s.IsReplaying == true {
-		fmt.Println("^^^^^^^^ IsReplying is true")
 		return s.ReplayTimestamp
 	}
 	return primitives.NewTimestampNow()
@@ -1976,6 +2546,15 @@ func (s *State) NetworkInvalidMsgQueue() chan interfaces.IMsg {
 	return s.networkInvalidMsgQueue
 }
 
+// GetPeerReputations returns how each peer of the network has behaved, worst first.  Empty
+// if the node is not on a network.
+func (s *State) GetPeerReputations() []interfaces.PeerReputation {
+	if s.NetworkControler == nil {
+		return []interfaces.PeerReputation{}
+	}
+	return s.NetworkControler.GetReputations()
+}
+
 func (s *State) NetworkOutMsgQueue() interfaces.IQueue {
 	return s.networkOutMsgQueue
 }
@@ -2154,6 +2733,9 @@ func (s *State) InitLevelDB() error {
 
 	s.Println("Database:", path)
 
+	res := s.resources()
+	leveldb.BlockCacheCapacity = res.LevelDBBlockCache
+	leveldb.WriteBuffer = res.LevelDBWriteBuffer
 	dbase, err := leveldb.NewLevelDB(path, false)
 
 	if err != nil || dbase == nil {
@@ -2163,7 +2745,11 @@ func (s *State) InitLevelDB() error {
 		}
 	}
 
-	s.DB = databaseOverlay.NewOverlay(dbase)
+	tiered, err := s.withColdStorage(dbase)
+	if err != nil {
+		return err
+	}
+	s.DB = s.newOverlay(tiered)
 	return nil
 }
 
@@ -2179,7 +2765,34 @@ func (s *State) InitBoltDB() error {
 
 	dbase := new(boltdb.BoltDB)
 	dbase.Init(nil, path+"FactomBolt.db")
-	s.DB = databaseOverlay.NewOverlay(dbase)
+	tiered, err := s.withColdStorage(dbase)
+	if err != nil {
+		return err
+	}
+	s.DB = s.newOverlay(tiered)
+	return nil
+}
+
+func (s *State) InitBadgerDB() error {
+	if s.DB != nil {
+		return nil
+	}
+
+	path := s.BadgerPath + "/" + s.Network + "/"
+
+	s.Println("Database Path for", s.FactomNodeName, "is", path)
+
+	badgerdb.ValueLogGCInterval = s.BadgerValueLogGCInterval
+	badgerdb.ValueLogGCDiscardRatio = s.BadgerValueLogGCDiscardRatio
+	dbase, err := badgerdb.NewBadgerDB(path)
+	if err != nil {
+		return err
+	}
+	tiered, err := s.withColdStorage(dbase)
+	if err != nil {
+		return err
+	}
+	s.DB = s.newOverlay(tiered)
 	return nil
 }
 
@@ -2194,6 +2807,28 @@ func (s *State) InitMapDB() error {
 	return nil
 }
 
+// newOverlay wraps a database opened from disk, with the read cache of the resource profile,
+// and the reference, address and external ID indexes and the object store if they are enabled
+func (s *State) newOverlay(dbase interfaces.IDatabase) *databaseOverlay.Overlay {
+	overlay := databaseOverlay.NewOverlay(dbase)
+	if size := s.resources().DBReadCache; size > 0 {
+		overlay.EnableReadCache(size)
+	}
+	if s.IndexReferences {
+		overlay.EnableReferenceIndex()
+	}
+	if s.EnableAddressIndex {
+		overlay.EnableAddressIndex()
+	}
+	if s.IndexExtIDs {
+		overlay.EnableExtIDIndex()
+	}
+	if s.ObjectStore {
+		overlay.EnableObjectStore(s.ObjectStoreMaxSize, s.ObjectStoreMaxObjects)
+	}
+	return overlay
+}
+
 func (s *State) String() string {
 	str := "\n===============================================================\n" + s.serverPrt
 	str = fmt.Sprintf("\n%s\n  Leader Height: %d\n", str, s.LLeaderHeight)
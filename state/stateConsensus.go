@@ -52,6 +52,10 @@ func (s *State) executeMsg(vm *VM, msg interfaces.IMsg) (ret bool) {
 		}
 	}
 
+	if s.holdForMinuteZero(msg) {
+		return
+	}
+
 	switch msg.Validate(s) {
 	case 1:
 		if s.RunLeader &&
@@ -76,11 +80,17 @@ func (s *State) executeMsg(vm *VM, msg interfaces.IMsg) (ret bool) {
 		TotalHoldingQueueInputs.Inc()
 		TotalHoldingQueueRecycles.Inc()
 		s.Holding[msg.GetMsgHash().Fixed()] = msg
+		s.heldSince(msg)
+		if kind, on, ok := s.holdingDependency(msg); ok {
+			s.waitFor(msg, kind, on)
+		}
 	default:
 		TotalHoldingQueueInputs.Inc()
 		TotalHoldingQueueRecycles.Inc()
 		s.Holding[msg.GetMsgHash().Fixed()] = msg
+		s.heldSince(msg)
 		if !msg.SentInvalid() {
+			s.Rejections.Message(msg, s.LLeaderHeight, RejectedInvalid)
 			msg.MarkSentInvalid(true)
 			s.networkInvalidMsgQueue <- msg
 		}
@@ -88,6 +98,9 @@ func (s *State) executeMsg(vm *VM, msg interfaces.IMsg) (ret bool) {
 
 	executeMsgTime := time.Since(preExecuteMsgTime)
 	TotalExecuteMsgTime.Add(float64(executeMsgTime.Nanoseconds()))
+	s.Capacity.ObserveExecute(msg.Type(), executeMsgTime)
+	msgtype, vmindex := latencyLabels(msg, msg.GetVMIndex())
+	ExecuteMsgTimeVec.WithLabelValues(msgtype, vmindex).Observe(executeMsgTime.Seconds())
 
 	return
 
@@ -107,7 +120,7 @@ func (s *State) Process() (progress bool) {
 	if !s.RunLeader {
 		now := s.GetTimestamp().GetTimeMilli() // Timestamps are in milliseconds, so wait 20
 		if now-s.StartDelay > s.StartDelayLimit {
-			if s.DBFinished == true {
+			if s.DBFinished == true && s.PeerQuorum.Check(s.LLeaderHeight, time.Now()) {
 				s.RunLeader = true
 				if !s.IgnoreDone {
 					s.StartDelay = now // Reset StartDelay for Ignore Missing
@@ -161,6 +174,7 @@ func (s *State) Process() (progress bool) {
 	}
 
 	s.ReviewHolding()
+	s.releaseMinuteZero()
 
 	preAckLoopTime := time.Now()
 	// Process acknowledgements if we have some.
@@ -168,11 +182,18 @@ ackLoop:
 	for room() {
 		select {
 		case ack := <-s.ackQueue:
-			a := ack.(*messages.Ack)
-			if a.DBHeight >= s.LLeaderHeight && ack.Validate(s) == 1 {
+			s.recordConsensus("ack", ack)
+			var dbheight uint32
+			switch a := ack.(type) {
+			case *messages.Ack:
+				dbheight = a.DBHeight
+			case *messages.BatchAck:
+				dbheight = a.DBHeight
+			}
+			if dbheight >= s.LLeaderHeight && ack.Validate(s) == 1 {
 				if s.IgnoreMissing {
 					now := s.GetTimestamp().GetTimeSeconds()
-					if now-a.GetTimestamp().GetTimeSeconds() < 60*15 {
+					if now-ack.GetTimestamp().GetTimeSeconds() < 60*15 {
 						s.executeMsg(vm, ack)
 					}
 				} else {
@@ -195,6 +216,7 @@ emptyLoop:
 	for room() {
 		select {
 		case msg := <-s.msgQueue:
+			s.recordConsensus("msg", msg)
 
 			if s.executeMsg(vm, msg) && !msg.IsPeer2Peer() {
 				msg.SendOut(s, msg)
@@ -250,7 +272,7 @@ func CheckDBKeyMR(s *State, ht uint32, hash string) error {
 	if s.Network != "MAIN" && s.Network != "main" {
 		return nil
 	}
-	if val, ok := constants.CheckPoints[ht]; ok {
+	if val, ok := constants.GetCheckPoint(ht); ok {
 		if val != hash {
 			return fmt.Errorf("%20s CheckPoints at %d DB height failed\n", s.FactomNodeName, ht)
 		}
@@ -266,12 +288,14 @@ func CheckDBKeyMR(s *State, ht uint32, hash string) error {
 // review if this is a leader, and those messages are that leader's
 // responsibility
 func (s *State) ReviewHolding() {
-	preReviewHoldingTime := time.Now()
+	if s.forcedHoldingReview() {
+		return
+	}
 	if len(s.XReview) > 0 {
 		return
 	}
 
-	if s.inMsgQueue.Length() > constants.INMSGQUEUE_LOW {
+	if s.inMsgQueue.Length() > s.holdingReviewQueueLow() {
 		return
 	}
 
@@ -279,24 +303,39 @@ func (s *State) ReviewHolding() {
 	if s.ResendHolding == nil {
 		s.ResendHolding = now
 	}
-	if now.GetTimeMilli()-s.ResendHolding.GetTimeMilli() < 300 {
+	if now.GetTimeMilli()-s.ResendHolding.GetTimeMilli() < s.holdingReviewInterval().Nanoseconds()/1e6 {
 		return
 	}
+	s.reviewHolding(false)
+}
 
-	s.DB.Trim()
+// reviewHolding makes one pass over holding, and returns what it did with the messages
+func (s *State) reviewHolding(forced bool) interfaces.HoldingReview {
+	preReviewHoldingTime := time.Now()
+	now := s.GetTimestamp()
+	review := interfaces.HoldingReview{Time: now.GetTimeMilli(), Forced: forced, Reviewed: len(s.Holding)}
+	if len(s.XReview) > 0 {
+		review.Skipped = "XReview still holds the messages of the last pass"
+		review.Remaining = len(s.Holding)
+		return review
+	}
 
 	s.ResendHolding = now
+	s.forgetDropped()
 	// Anything we are holding, we need to reprocess.
 	s.XReview = make([]interfaces.IMsg, 0)
 
 	highest := s.GetHighestKnownBlock()
 	saved := s.GetHighestSavedBlk()
+	nowMilli := now.GetTimeMilli()
 
 	for k, v := range s.Holding {
 
+		behind := false
 		if int(highest)-int(saved) > 1000 {
 			TotalHoldingQueueOutputs.Inc()
 			delete(s.Holding, k)
+			behind = true
 		}
 
 		mm, ok := v.(*messages.MissingMsgResponse)
@@ -305,6 +344,11 @@ func (s *State) ReviewHolding() {
 			if ok && ff.DBHeight < saved {
 				TotalHoldingQueueOutputs.Inc()
 				delete(s.Holding, k)
+				review.Stale++
+			} else if behind {
+				review.Stale++
+			} else {
+				review.Waiting++
 			}
 			continue
 		}
@@ -313,6 +357,7 @@ func (s *State) ReviewHolding() {
 		if ok && sf.DBHeight < saved {
 			TotalHoldingQueueOutputs.Inc()
 			delete(s.Holding, k)
+			review.Stale++
 			continue
 		}
 
@@ -320,6 +365,7 @@ func (s *State) ReviewHolding() {
 		if ok && ff.DBHeight < saved {
 			TotalHoldingQueueOutputs.Inc()
 			delete(s.Holding, k)
+			review.Stale++
 			continue
 		}
 
@@ -327,6 +373,7 @@ func (s *State) ReviewHolding() {
 		if ok && ((eom.DBHeight <= saved && saved > 0) || (eom.DBHeight < highest-3 && highest > 2)) {
 			TotalHoldingQueueOutputs.Inc()
 			delete(s.Holding, k)
+			review.Stale++
 			continue
 		}
 
@@ -334,6 +381,7 @@ func (s *State) ReviewHolding() {
 		if ok && (dbsmsg.DirectoryBlock.GetHeader().GetDBHeight() < saved-1 && saved > 0) {
 			TotalHoldingQueueOutputs.Inc()
 			delete(s.Holding, k)
+			review.Stale++
 			continue
 		}
 
@@ -341,6 +389,7 @@ func (s *State) ReviewHolding() {
 		if ok && ((dbsigmsg.DBHeight <= saved && saved > 0) || (dbsigmsg.DBHeight < highest-3 && highest > 2)) {
 			TotalHoldingQueueOutputs.Inc()
 			delete(s.Holding, k)
+			review.Stale++
 			continue
 		}
 
@@ -348,6 +397,7 @@ func (s *State) ReviewHolding() {
 		if !ok {
 			TotalHoldingQueueOutputs.Inc()
 			delete(s.Holding, k)
+			review.Expired++
 			continue
 		}
 
@@ -355,29 +405,62 @@ func (s *State) ReviewHolding() {
 			s.ExpireCnt++
 			TotalHoldingQueueOutputs.Inc()
 			delete(s.Holding, k)
+			review.Expired++
 			continue
 		}
 
-		if v.Resend(s) {
+		if s.resendHeld(k, v) {
 			if v.Validate(s) == 1 {
 				s.ResendCnt++
 				v.SendOut(s, v)
+				review.Resent++
 				continue
 			}
 		}
 
+		// Messages waiting on something are released when it arrives.  Unless we now
+		// lead the message's VM, and so are the one to ack it.
+		if kind, ok := s.HoldingDeps.WaitingOn(k, nowMilli); ok && (kind != HoldForAck || !s.Leader || v.GetVMIndex() != s.LeaderVMIndex) {
+			if behind {
+				review.Stale++
+			} else {
+				review.Waiting++
+			}
+			continue
+		}
+
 		if v.Validate(s) < 0 {
 			TotalHoldingQueueOutputs.Inc()
 			delete(s.Holding, k)
+			review.Invalid++
 			continue
 		}
 		TotalXReviewQueueInputs.Inc()
 		s.XReview = append(s.XReview, v)
 		TotalHoldingQueueOutputs.Inc()
 		delete(s.Holding, k)
+		review.Requeued++
 	}
+	s.pruneHeldResends()
+	s.HoldingDeps.Prune(s.Holding)
+	for kind, n := range s.HoldingDeps.Counts() {
+		HoldingWaitingVec.WithLabelValues(kind).Set(float64(n))
+	}
+	s.sortXReview()
 	reviewHoldingTime := time.Since(preReviewHoldingTime)
 	TotalReviewHoldingTime.Add(float64(reviewHoldingTime.Nanoseconds()))
+
+	review.Remaining = len(s.Holding)
+	review.Duration = reviewHoldingTime.Nanoseconds()
+	HoldingReviewVec.WithLabelValues("requeued").Add(float64(review.Requeued))
+	HoldingReviewVec.WithLabelValues("expired").Add(float64(review.Expired))
+	HoldingReviewVec.WithLabelValues("stale").Add(float64(review.Stale))
+	HoldingReviewVec.WithLabelValues("invalid").Add(float64(review.Invalid))
+	HoldingReviewVec.WithLabelValues("resent").Add(float64(review.Resent))
+	if s.HoldingReviews != nil {
+		s.HoldingReviews.record(review)
+	}
+	return review
 }
 
 // Adds blocks that are either pulled locally from a database, or acquired from peers.
@@ -401,7 +484,8 @@ func (s *State) AddDBState(isNew bool,
 
 	err := CheckDBKeyMR(s, ht, DBKeyMR)
 	if err != nil {
-		panic(fmt.Errorf("Found block at height %d that didn't match a checkpoint. Got %s, expected %s", ht, DBKeyMR, constants.CheckPoints[ht])) //TODO make failing when given bad blocks fail more elegantly
+		expected, _ := constants.GetCheckPoint(ht)
+		panic(fmt.Errorf("Found block at height %d that didn't match a checkpoint. Got %s, expected %s", ht, DBKeyMR, expected)) //TODO make failing when given bad blocks fail more elegantly
 	}
 
 	if ht > s.LLeaderHeight {
@@ -420,14 +504,8 @@ func (s *State) AddDBState(isNew bool,
 
 		{
 			// Okay, we have just loaded a new DBState.  The temp balances are no longer valid, if they exist.  Nuke them.
-			s.LeaderPL.FactoidBalancesTMutex.Lock()
-			defer s.LeaderPL.FactoidBalancesTMutex.Unlock()
-
-			s.LeaderPL.ECBalancesTMutex.Lock()
-			defer s.LeaderPL.ECBalancesTMutex.Unlock()
-
-			s.LeaderPL.FactoidBalancesT = map[[32]byte]int64{}
-			s.LeaderPL.ECBalancesT = map[[32]byte]int64{}
+			s.LeaderPL.FactoidBalancesT.Clear()
+			s.LeaderPL.ECBalancesT.Clear()
 		}
 
 		s.Leader, s.LeaderVMIndex = s.LeaderPL.GetVirtualServers(s.CurrentMinute, s.IdentityChainID)
@@ -449,7 +527,11 @@ func (s *State) FollowerExecuteMsg(m interfaces.IMsg) {
 	FollowerExecutions.Inc()
 	TotalHoldingQueueInputs.Inc()
 	s.Holding[m.GetMsgHash().Fixed()] = m
+	s.heldSince(m)
 	ack, _ := s.Acks[m.GetMsgHash().Fixed()].(*messages.Ack)
+	if ack == nil {
+		s.waitFor(m, HoldForAck, m.GetMsgHash().Fixed())
+	}
 
 	if ack != nil {
 		m.SetLeaderChainID(ack.GetLeaderChainID())
@@ -473,18 +555,32 @@ func (s *State) FollowerExecuteEOM(m interfaces.IMsg) {
 	FollowerEOMExecutions.Inc()
 	TotalHoldingQueueInputs.Inc()
 	s.Holding[m.GetMsgHash().Fixed()] = m
+	s.heldSince(m)
 
 	ack, _ := s.Acks[m.GetMsgHash().Fixed()].(*messages.Ack)
 	if ack != nil {
 		pl := s.ProcessLists.Get(ack.DBHeight)
 		pl.AddToProcessList(ack, m)
+	} else {
+		s.waitFor(m, HoldForAck, m.GetMsgHash().Fixed())
 	}
 }
 
 // Ack messages always match some message in the Process List.   That is
 // done here, though the only msg that should call this routine is the Ack
-// message.
+// message.  A batch of acks is passed on as it came, and its acks unpacked and
+// taken one by one.
 func (s *State) FollowerExecuteAck(msg interfaces.IMsg) {
+	if batch, ok := msg.(*messages.BatchAck); ok {
+		BatchAcksReceived.Inc()
+		batch.SendOut(s, batch)
+		for _, ack := range batch.Unpack() {
+			if ack.Validate(s) == 1 {
+				s.FollowerExecuteAck(ack)
+			}
+		}
+		return
+	}
 	ack := msg.(*messages.Ack)
 
 	if ack.DBHeight > s.HighestKnown {
@@ -502,6 +598,7 @@ func (s *State) FollowerExecuteAck(msg interfaces.IMsg) {
 
 	TotalAcksInputs.Inc()
 	s.Acks[ack.GetHash().Fixed()] = ack
+	s.released(HoldForAck, ack.GetHash().Fixed())
 	m, _ := s.Holding[ack.GetHash().Fixed()]
 	if m != nil {
 		m.FollowerExecute(s)
@@ -566,7 +663,8 @@ func (s *State) FollowerExecuteDBState(msg interfaces.IMsg) {
 
 	pdbstate := s.DBStates.Get(int(dbheight - 1))
 
-	switch pdbstate.ValidNext(s, dbstatemsg) {
+	valid, reason := pdbstate.validNext(s, dbstatemsg)
+	switch valid {
 	case 0:
 		//s.AddStatus(fmt.Sprintf("FollowerExecuteDBState(): DBState might be valid %d", dbheight))
 
@@ -593,6 +691,9 @@ func (s *State) FollowerExecuteDBState(msg interfaces.IMsg) {
 		//s.AddStatus(fmt.Sprintf("FollowerExecuteDBState(): DBState is invalid at ht %d", dbheight))
 		// Do nothing because this dbstate looks to be invalid
 		cntFail()
+		if reason != "" && !dbstatemsg.IsInDB {
+			s.Rejections.Block(dbstatemsg, reason)
+		}
 		return
 	}
 
@@ -620,6 +721,9 @@ func (s *State) FollowerExecuteDBState(msg interfaces.IMsg) {
 	if dbstate == nil {
 		//s.AddStatus(fmt.Sprintf("FollowerExecuteDBState(): dbstate fail at ht %d", dbheight))
 		cntFail()
+		if !dbstatemsg.IsInDB {
+			s.Rejections.Block(dbstatemsg, RejectedNotApplied)
+		}
 		return
 	}
 
@@ -635,6 +739,7 @@ func (s *State) FollowerExecuteDBState(msg interfaces.IMsg) {
 		if i > 0 && // Don't test the coinbase TX
 			((dbheight > 0 && dbheight < 2000) || dbheight > 100000) && // Test the first 2000 blks, so we can unit test, then after
 			!valid { // 100K for the running system.  If a TX isn't valid, ignore.
+			s.Rejections.Block(dbstatemsg, RejectedDoubleSpend)
 			return //Totally ignore the block if it has a double spend.
 		}
 	}
@@ -686,7 +791,7 @@ func (s *State) FollowerExecuteDBState(msg interfaces.IMsg) {
 		if s.StateSaverStruct.FastBoot {
 			dbstate.SaveStruct = SaveFactomdState(s, dbstate)
 
-			err := s.StateSaverStruct.SaveDBStateList(s.DBStates, s.Network)
+			err := s.Jobs.RunNow("fastboot-save")
 			if err != nil {
 				panic(err)
 			}
@@ -697,7 +802,12 @@ func (s *State) FollowerExecuteDBState(msg interfaces.IMsg) {
 func (s *State) FollowerExecuteMMR(m interfaces.IMsg) {
 
 	// Just ignore missing messages for a period after going off line or starting up.
-	if s.IgnoreMissing || s.inMsgQueue.Length() > constants.INMSGQUEUE_HIGH {
+	if s.IgnoreMissing {
+		return
+	}
+	// Shed them while overloaded; the peers we turn away are throttled, so fewer come
+	if s.inMsgQueue.Length() > constants.INMSGQUEUE_HIGH {
+		MissingResponsesShed.Inc()
 		return
 	}
 
@@ -718,7 +828,8 @@ func (s *State) FollowerExecuteMMR(m interfaces.IMsg) {
 					pl.AddToSystemList(fullFault)
 				}
 				s.MissingResponseAppliedCnt++
-			} else if pl != nil && int(fullFault.Height) >= pl.System.Height {
+			} else if pl != nil && !s.electionsActive(fullFault.DBHeight) && int(fullFault.Height) >= pl.System.Height {
+				// A negotiation still under way
 				TotalXReviewQueueInputs.Inc()
 				s.XReview = append(s.XReview, fullFault)
 				s.MissingResponseAppliedCnt++
@@ -730,19 +841,28 @@ func (s *State) FollowerExecuteMMR(m interfaces.IMsg) {
 		return
 	}
 
+	msg := mmr.MsgResponse
+	if msg == nil {
+		return
+	}
+
 	ack, ok := mmr.AckResponse.(*messages.Ack)
 
+	// An ack served in its batch is taken once the batch checks out
+	if batch, isBatch := mmr.AckResponse.(*messages.BatchAck); isBatch {
+		ok = false
+		if batch.Validate(s) == 1 {
+			ack = batch.AckOf(msg.GetMsgHash())
+			ok = ack != nil
+		}
+	}
+
 	// If we don't need this message, we don't have to do everything else.
 	if !ok || ack.Validate(s) == -1 {
 		return
 	}
 
 	ack.Response = true
-	msg := mmr.MsgResponse
-
-	if msg == nil {
-		return
-	}
 
 	pl := s.ProcessLists.Get(ack.DBHeight)
 	_, okr := s.Replay.Valid(constants.INTERNAL_REPLAY, ack.GetRepeatHash().Fixed(), ack.GetTimestamp(), s.GetTimestamp())
@@ -774,12 +894,34 @@ func (s *State) FollowerExecuteMMR(m interfaces.IMsg) {
 
 }
 
+// FollowerExecuteDataResponse hands entry and entry block responses off to the
+// DataResponse worker, so the database work they need is kept off the consensus path.
+// If the worker has fallen behind, the response is dropped and counted in
+// DataResponseQueueDrops; it will be asked for again.
 func (s *State) FollowerExecuteDataResponse(m interfaces.IMsg) {
 	msg, ok := m.(*messages.DataResponse)
 	if !ok {
 		return
 	}
 
+	select {
+	case s.dataResponseQueue <- msg:
+		DataResponseQueueInputs.Inc()
+	default:
+		DataResponseQueueDrops.Inc()
+	}
+}
+
+// GoHandleDataResponses is the worker that processes the DataResponses queued by
+// FollowerExecuteDataResponse.
+func (s *State) GoHandleDataResponses() {
+	for msg := range s.dataResponseQueue {
+		DataResponseQueueOutputs.Inc()
+		s.executeDataResponse(msg)
+	}
+}
+
+func (s *State) executeDataResponse(msg *messages.DataResponse) {
 	switch msg.DataType {
 	case 1: // Data is an entryBlock
 		eblock, ok := msg.DataObject.(interfaces.IEntryBlock)
@@ -792,23 +934,30 @@ func (s *State) FollowerExecuteDataResponse(m interfaces.IMsg) {
 			return
 		}
 
+		if !s.isMissingEBlock(ebKeyMR) {
+			return
+		}
+		// The database is read without the lock, so the entry syncing isn't held up by it
+		db, err := s.DB.FetchDBlockByHeight(eblock.GetHeader().GetDBHeight())
+		if err != nil || db == nil {
+			return
+		}
+
+		s.MissingEntryBlocksMutex.Lock()
+		defer s.MissingEntryBlocksMutex.Unlock()
+
+		// The list may have changed while it was unlocked
 		for i, missing := range s.MissingEntryBlocks {
-			eb := missing.EBHash
-			if !eb.IsSameAs(ebKeyMR) {
+			if !missing.EBHash.IsSameAs(ebKeyMR) {
 				continue
 			}
 
-			db, err := s.DB.FetchDBlockByHeight(eblock.GetHeader().GetDBHeight())
-			if err != nil || db == nil {
-				return
-			}
-
 			var missing []MissingEntryBlock
 			missing = append(missing, s.MissingEntryBlocks[:i]...)
 			missing = append(missing, s.MissingEntryBlocks[i+1:]...)
 			s.MissingEntryBlocks = missing
 
-			s.DB.ProcessEBlockBatch(eblock, true)
+			s.Writes.AddEBlock(eblock)
 
 			break
 		}
@@ -818,15 +967,38 @@ func (s *State) FollowerExecuteDataResponse(m interfaces.IMsg) {
 		if !ok {
 			return
 		}
-		if len(s.WriteEntry) < cap(s.WriteEntry) {
-			s.WriteEntry <- entry
+		select {
+		case s.WriteEntry <- entry:
+		default:
+			DataResponseQueueDrops.Inc()
+		}
+	}
+}
+
+// isMissingEBlock is true if the entry block is on the missing list
+func (s *State) isMissingEBlock(keyMR interfaces.IHash) bool {
+	s.MissingEntryBlocksMutex.Lock()
+	defer s.MissingEntryBlocksMutex.Unlock()
+	for _, missing := range s.MissingEntryBlocks {
+		if missing.EBHash.IsSameAs(keyMR) {
+			return true
 		}
 	}
+	return false
 }
 
 func (s *State) FollowerExecuteMissingMsg(msg interfaces.IMsg) {
-	// Don't respond to missing messages if we are behind.
+	// Don't respond to missing messages if we are behind, but say so, so the peer asks
+	// someone else.
 	if s.inMsgQueue.Length() > constants.INMSGQUEUE_LOW {
+		s.MissingRequestIgnoreCnt++
+		s.throttlePeer(msg)
+		return
+	}
+
+	// Nor until enough peers agree with our height after booting
+	if !s.PeerQuorum.Met() {
+		s.MissingRequestIgnoreCnt++
 		return
 	}
 
@@ -853,6 +1025,10 @@ func (s *State) FollowerExecuteMissingMsg(msg interfaces.IMsg) {
 		missingmsg, ackMsg, err := s.LoadSpecificMsgAndAck(m.DBHeight, m.VMIndex, h)
 
 		if missingmsg != nil && ackMsg != nil && err == nil {
+			// An ack that came in a batch has no signature of its own; serve the batch
+			if ack, ok := ackMsg.(*messages.Ack); ok && ack.Signature == nil && ack.GetBatch() != nil {
+				ackMsg = ack.GetBatch()
+			}
 			// If I don't have this message, ignore.
 			msgResponse := messages.NewMissingMsgResponse(s, missingmsg, ackMsg)
 			msgResponse.SetOrigin(m.GetOrigin())
@@ -873,6 +1049,7 @@ func (s *State) FollowerExecuteCommitChain(m interfaces.IMsg) {
 	FollowerExecutions.Inc()
 	s.FollowerExecuteMsg(m)
 	cc := m.(*messages.CommitChainMsg)
+	s.Commits.Offer(cc.CommitChain.EntryHash.Fixed(), m)
 	re := s.Holding[cc.CommitChain.EntryHash.Fixed()]
 	if re != nil {
 		TotalXReviewQueueInputs.Inc()
@@ -885,6 +1062,7 @@ func (s *State) FollowerExecuteCommitEntry(m interfaces.IMsg) {
 	FollowerExecutions.Inc()
 	s.FollowerExecuteMsg(m)
 	ce := m.(*messages.CommitEntryMsg)
+	s.Commits.Offer(ce.CommitEntry.EntryHash.Fixed(), m)
 	re := s.Holding[ce.CommitEntry.EntryHash.Fixed()]
 	if re != nil {
 		s.XReview = append(s.XReview, re)
@@ -896,7 +1074,11 @@ func (s *State) FollowerExecuteRevealEntry(m interfaces.IMsg) {
 	FollowerExecutions.Inc()
 	TotalHoldingQueueInputs.Inc()
 	s.Holding[m.GetMsgHash().Fixed()] = m
+	s.heldSince(m)
 	ack, _ := s.Acks[m.GetMsgHash().Fixed()].(*messages.Ack)
+	if ack == nil {
+		s.waitFor(m, HoldForAck, m.GetMsgHash().Fixed())
+	}
 
 	if ack != nil {
 		m.SendOut(s, m)
@@ -944,6 +1126,10 @@ func (s *State) LeaderExecuteEOM(m interfaces.IMsg) {
 		return
 	}
 
+	if s.holdForLeaderGrace(m) {
+		return
+	}
+
 	// The zero based minute for the message is equal to
 	// the one based "LastMinute".  This way we know we are
 	// generating minutes in order.
@@ -1047,6 +1233,9 @@ func (s *State) LeaderExecuteCommitChain(m interfaces.IMsg) {
 		// This commit is not higher than any previous, so we can discard it and prevent a double spend
 		return
 	}
+	if s.holdRateLimitedCommit(m) {
+		return
+	}
 
 	s.LeaderExecute(m)
 	re := s.Holding[cc.CommitChain.EntryHash.Fixed()]
@@ -1058,6 +1247,9 @@ func (s *State) LeaderExecuteCommitChain(m interfaces.IMsg) {
 }
 
 func (s *State) LeaderExecuteCommitEntry(m interfaces.IMsg) {
+	if s.holdRateLimitedCommit(m) {
+		return
+	}
 	s.LeaderExecute(m)
 	ce := m.(*messages.CommitEntryMsg)
 	re := s.Holding[ce.CommitEntry.EntryHash.Fixed()]
@@ -1077,11 +1269,29 @@ func (s *State) LeaderExecuteRevealEntry(m interfaces.IMsg) {
 	switch rtn {
 	case 0:
 		m.FollowerExecute(s)
+		s.leaderHeld(m)
 		return
 	case -1:
 		return
 	}
 
+	// Hold chain creations past the limit for the next block
+	if !re.IsEntry && s.IsNewChainLimitReached(s.LLeaderHeight) {
+		TotalNewChainsDeferred.Inc()
+		m.FollowerExecute(s)
+		s.leaderHeld(m)
+		return
+	}
+
+	// Hold reveals past the limit for the next block, where the best paying go first
+	if s.IsEntryLimitReached(s.LLeaderHeight) {
+		TotalEntriesDeferred.Inc()
+		m.FollowerExecute(s)
+		// Nothing to wait on but the block, so it is reviewed with the rest of holding
+		s.HoldingDeps.Forget(m.GetMsgHash().Fixed())
+		return
+	}
+
 	commit := s.NextCommit(eh)
 
 	now := s.GetTimestamp()
@@ -1113,10 +1323,21 @@ func (s *State) LeaderExecuteRevealEntry(m interfaces.IMsg) {
 
 func (s *State) ProcessAddServer(dbheight uint32, addServerMsg interfaces.IMsg) bool {
 	as, ok := addServerMsg.(*messages.AddServerMsg)
-	if ok && !ProcessIdentityToAdminBlock(s, as.ServerChainID, as.ServerType) {
+	if !ok {
+		return true
+	}
+	if reason := s.authorityChangeRejected(dbheight, as.ServerChainID, true); reason != "" {
+		s.rejectAuthorityChange(dbheight, as, reason)
+		return true
+	}
+	entries := len(s.LeaderPL.AdminBlock.GetABEntries())
+	if !ProcessIdentityToAdminBlock(s, as.ServerChainID, as.ServerType) {
 		//s.AddStatus(fmt.Sprintf("Failed to add %x as server type %d", as.ServerChainID.Bytes()[2:5], as.ServerType))
 		return false
 	}
+	if len(s.LeaderPL.AdminBlock.GetABEntries()) > entries {
+		s.authorityChanged(dbheight)
+	}
 	return true
 }
 
@@ -1137,7 +1358,12 @@ func (s *State) ProcessRemoveServer(dbheight uint32, removeServerMsg interfaces.
 	if len(s.LeaderPL.FedServers) < 2 && rs.ServerType == 0 {
 		return true
 	}
+	if reason := s.authorityChangeRejected(dbheight, rs.ServerChainID, false); reason != "" {
+		s.rejectAuthorityChange(dbheight, rs, reason)
+		return true
+	}
 	s.LeaderPL.AdminBlock.RemoveFederatedServer(rs.ServerChainID)
+	s.authorityChanged(dbheight)
 
 	return true
 }
@@ -1157,6 +1383,13 @@ func (s *State) ProcessChangeServerKey(dbheight uint32, changeServerKeyMsg inter
 		var btcKey [20]byte
 		copy(btcKey[:], ask.Key.Bytes()[:20])
 		s.LeaderPL.AdminBlock.AddFederatedServerBitcoinAnchorKey(ask.IdentityChainID, ask.KeyPriority, ask.KeyType, btcKey)
+	case constants.TYPE_ADD_ETH_ANCHOR_KEY:
+		if !s.ethAnchorKeysActive(dbheight) {
+			break
+		}
+		var ethKey [20]byte
+		copy(ethKey[:], ask.Key.Bytes()[:20])
+		s.LeaderPL.AdminBlock.AddFederatedServerEthereumAnchorKey(ask.IdentityChainID, ask.KeyPriority, ethKey)
 	case constants.TYPE_ADD_FED_SERVER_KEY:
 		pub := ask.Key.Fixed()
 		s.LeaderPL.AdminBlock.AddFederatedServerSigningKey(ask.IdentityChainID, pub)
@@ -1175,6 +1408,7 @@ func (s *State) ProcessCommitChain(dbheight uint32, commitChain interfaces.IMsg)
 		// save the Commit to match agains the Reveal later
 		h := c.CommitChain.EntryHash
 		s.PutCommit(h, c)
+		s.released(HoldForCommit, h.Fixed())
 		entry := s.Holding[h.Fixed()]
 		if entry != nil {
 			entry.SendOut(s, entry)
@@ -1199,6 +1433,7 @@ func (s *State) ProcessCommitEntry(dbheight uint32, commitEntry interfaces.IMsg)
 		// save the Commit to match agains the Reveal later
 		h := c.CommitEntry.EntryHash
 		s.PutCommit(h, c)
+		s.released(HoldForCommit, h.Fixed())
 		entry := s.Holding[h.Fixed()]
 		if entry != nil {
 			entry.SendOut(s, entry)
@@ -1244,6 +1479,9 @@ func (s *State) ProcessRevealEntry(dbheight uint32, m interfaces.IMsg) bool {
 		// Put it in our list of new Entry Blocks for this Directory Block
 		s.PutNewEBlocks(dbheight, chainID, eb)
 		s.PutNewEntries(dbheight, myhash, msg.Entry)
+		s.ProcessLists.Get(dbheight).NewChains++
+		s.releaseHeld(HoldForChain, chainID.Fixed())
+		TotalNewChains.Inc()
 
 		s.IncEntryChains()
 		s.IncEntries()
@@ -1415,6 +1653,7 @@ func (s *State) ProcessEOM(dbheight uint32, msg interfaces.IMsg) bool {
 		s.EOMProcessed++
 		//fmt.Println(fmt.Sprintf("EOM PROCESS: %10s vm %2d EOMProcessed++ (%2d)", s.FactomNodeName, e.VMIndex, s.EOMProcessed))
 		vm.Synced = true
+		s.SlowRounds.Record(SlowRoundEOM, dbheight, int(e.Minute), e.ChainID)
 		markNoFault(pl, msg.GetVMIndex())
 		if s.LeaderPL.SysHighest < int(e.SysHeight) {
 			s.LeaderPL.SysHighest = int(e.SysHeight)
@@ -1451,8 +1690,14 @@ func (s *State) ProcessEOM(dbheight uint32, msg interfaces.IMsg) bool {
 			s.CurrentMinute = int(e.Minute)
 		}
 
+		s.SlowRounds.Complete(SlowRoundEOM, dbheight, int(e.Minute), s.CurrentMinuteStartTime, s.roundWindow())
+		if s.CurrentMinuteStartTime > 0 {
+			s.TimingAnomalies.MinuteEnded(dbheight, int(e.Minute), time.Duration(time.Now().UnixNano()-s.CurrentMinuteStartTime), time.Duration(s.DirectoryBlockInSeconds)*time.Second)
+			s.checkLeaderHealth(dbheight, int(e.Minute), time.Duration(time.Now().UnixNano()-s.CurrentMinuteStartTime))
+		}
 		s.CurrentMinute++
 		s.CurrentMinuteStartTime = time.Now().UnixNano()
+		s.RefreshReadView()
 
 		switch {
 		case s.CurrentMinute < 10:
@@ -1465,6 +1710,9 @@ func (s *State) ProcessEOM(dbheight uint32, msg interfaces.IMsg) bool {
 			s.LeaderPL = s.ProcessLists.Get(s.LLeaderHeight)
 			s.Leader, s.LeaderVMIndex = s.LeaderPL.GetVirtualServers(s.CurrentMinute, s.IdentityChainID)
 		case s.CurrentMinute == 10:
+			NewEBlocksPerBlock.Set(float64(len(pl.NewEBlocks)))
+			NewChainsPerBlock.Set(float64(pl.NewChains))
+
 			eBlocks := []interfaces.IEntryBlock{}
 			entries := []interfaces.IEBEntry{}
 			for _, v := range pl.NewEBlocks {
@@ -1474,6 +1722,8 @@ func (s *State) ProcessEOM(dbheight uint32, msg interfaces.IMsg) bool {
 				entries = append(entries, v)
 			}
 
+			s.observeCommitsToBlock(pl)
+
 			dbstate := s.AddDBState(true, s.LeaderPL.DirectoryBlock, s.LeaderPL.AdminBlock, s.GetFactoidState().GetCurrentBlock(), s.LeaderPL.EntryCreditBlock, eBlocks, entries)
 			if dbstate == nil {
 				dbstate = s.DBStates.Get(int(s.LeaderPL.DirectoryBlock.GetHeader().GetDBHeight()))
@@ -1529,7 +1779,7 @@ func (s *State) ProcessEOM(dbheight uint32, msg interfaces.IMsg) bool {
 			s.Saving = true
 		}
 
-		s.Commits.RemoveExpired(s)
+		// Expired commits are removed by the commit-expiration job
 		// for k, v := range s.Commits {
 		// 	if v != nil {
 		// 		_, ok := s.Replay.Valid(constants.TIME_TEST, v.GetRepeatHash().Fixed(), v.GetTimestamp(), s.GetTimestamp())
@@ -1555,6 +1805,14 @@ func (s *State) ProcessEOM(dbheight uint32, msg interfaces.IMsg) bool {
 }
 
 func (s *State) CheckForIDChange() {
+	if swap := s.simBrainswap; swap != nil && s.LLeaderHeight >= swap.height {
+		s.simBrainswap = nil
+		s.IdentityChainID = swap.chainID
+		s.LocalServerPrivKey = swap.privKey
+		s.initServerKeys()
+		return
+	}
+
 	var reloadIdentity bool = false
 	if s.AckChange > 0 {
 		if s.LLeaderHeight >= s.AckChange {
@@ -1688,7 +1946,12 @@ func (s *State) ProcessDBSig(dbheight uint32, msg interfaces.IMsg) bool {
 
 		dbs.Matches = true
 		s.AddDBSig(dbheight, dbs.ServerIdentityChainID, dbs.DBSignature)
+		if s.AuthorityStats != nil && s.DBFinished && s.CurrentBlockStartTime > 0 {
+			s.AuthorityStats.RecordDBSigDelay(dbs.ServerIdentityChainID,
+				time.Duration(time.Now().UnixNano()-s.CurrentBlockStartTime))
+		}
 
+		s.SlowRounds.Record(SlowRoundDBSig, dbheight, 0, dbs.ServerIdentityChainID)
 		s.DBSigProcessed++
 		//fmt.Println(fmt.Sprintf("Process DBSig %10s vm %2v DBSigProcessed++ (%2d)", s.FactomNodeName, dbs.VMIndex, s.DBSigProcessed))
 		vm.Synced = true
@@ -1726,6 +1989,7 @@ func (s *State) ProcessDBSig(dbheight uint32, msg interfaces.IMsg) bool {
 		s.ReviewHolding()
 		s.Saving = false
 		s.DBSigDone = true
+		s.SlowRounds.Complete(SlowRoundDBSig, dbheight, 0, s.CurrentBlockStartTime, s.roundWindow())
 	}
 	return false
 	/*
@@ -1737,9 +2001,10 @@ func (s *State) ProcessDBSig(dbheight uint32, msg interfaces.IMsg) bool {
 }
 
 func (s *State) ProcessFullServerFault(dbheight uint32, msg interfaces.IMsg) bool {
-	// If we are here, this means that the FullFault message is complete
-	// and we can execute it as such (replacing the faulted Leader with
-	// the nominated Audit server)
+	// A complete FullFault message, the outcome of an election or of a
+	// fault negotiation, is executed by replacing the faulted Leader with
+	// the nominated Audit server.  Before elections take effect, an
+	// incomplete one is a negotiation ping, which we match if we agree.
 
 	fullFault, ok := msg.(*messages.FullServerFault)
 	if !ok {
@@ -1786,15 +2051,17 @@ func (s *State) ProcessFullServerFault(dbheight uint32, msg interfaces.IMsg) boo
 		return false
 	}
 
+	elections := s.electionsActive(fullFault.DBHeight)
+
 	// If "ClearFault" is set to true, that means the leader came back online so we can "forget" about
 	// the fault (leave it in the SystemList, but consider it processed without having promoted/demoted anyone)
-	if fullFault.ClearFault {
+	if fullFault.ClearFault && !elections {
 		if fullFault.GetVMIndex() < len(pl.VMs) && pl.VMs[fullFault.GetVMIndex()].WhenFaulted == 0 {
 			// If we agree that the server doesn't need to be faulted, we will clear our currentFault
 			// but otherwise do nothing (we do not execute the actual demotion/promotion)
-			//s.AddStatus(fmt.Sprintf("PROCESS Full Fault CLEARING: %s", fullFault.StringWithSigCnt(s)))
 			fullFault.SetAlreadyProcessed()
 			faultLogger.WithField("func", "ClearFault").WithFields(fullFault.LogFields()).Warn("Cleared")
+			s.PublishStatusEvent(interfaces.StatusEventElectionEnd, int(fullFault.VMIndex), "cleared")
 			return true
 		}
 	}
@@ -1821,141 +2088,78 @@ func (s *State) ProcessFullServerFault(dbheight uint32, msg interfaces.IMsg) boo
 		return false
 	}
 
-	if fullFault.HasEnoughSigs(s) && s.pledgedByAudit(fullFault) {
-		// If we are here, this means that the FullFault message is complete
-		// and we can execute it as such (replacing the faulted Leader with
-		// the nominated Audit server)
-
-		rHt := vm.Height
-		ffHt := int(fullFault.Height)
-		if rHt > ffHt {
-			//s.AddStatus(fmt.Sprintf("PROCESS Full Fault: FAIL but reset vm... %s", fullFault.StringWithSigCnt(s)))
-			vm.Height = ffHt
-			return false
-		} else if rHt < ffHt {
-			//s.AddStatus(fmt.Sprintf("PROCESS Full Fault: FAIL, vm not there yet. %s", fullFault.StringWithSigCnt(s)))
-			return false
+	// Only a complete FullFault is executed: the votes of a majority of the leaders,
+	// and the pledge of the audit server being promoted
+	if !fullFault.HasEnoughSigs(s) || !s.pledgedByAudit(fullFault) {
+		if !elections {
+			s.matchNegotiation(pl, fullFault)
 		}
+		return false
+	}
 
-		// Here is where we actually swap out the Leader with the Audit server
-		// being promoted
-		for listIdx, fedServ := range pl.FedServers {
-			if fedServ.GetChainID().IsSameAs(fullFault.ServerID) {
-
-				pl.FedServers[listIdx] = theAuditReplacement
-				pl.FedServers[listIdx].SetOnline(true)
-				audIdx := pl.AddAuditServer(fedServ.GetChainID())
-				pl.AuditServers[audIdx].SetOnline(false)
-
-				s.RemoveAuditServer(fullFault.DBHeight, theAuditReplacement.GetChainID())
-				// After executing the FullFault successfully, we want to reset
-				// to the default state (No One At Fault)
-				s.Leader, s.LeaderVMIndex = s.LeaderPL.GetVirtualServers(s.CurrentMinute, s.IdentityChainID)
-
-				authoritiesString := ""
-				for _, str := range s.ConstructAuthoritySetString() {
-					if len(authoritiesString) > 0 {
-						authoritiesString += "\n"
-					}
-					authoritiesString += str
-				}
-				// Any updates required to the state as established by the AdminBlock are applied here.
-				pl.State.SetAuthoritySetString(authoritiesString)
-				authorityDeltaString := fmt.Sprintf("FULL FAULT SUCCESSFULLY PROCESSED DBHt: %d SysHt: %d ServerID %s AuditServerID %s",
-					fullFault.DBHeight,
-					fullFault.SystemHeight,
-					fullFault.ServerID.String()[4:12],
-					fullFault.AuditServerID.String()[4:12])
-				pl.State.AddAuthorityDelta(authorityDeltaString)
-
-				consenLogger.WithFields(log.Fields{"dbht": fullFault.DBHeight, "sysht": fullFault.SystemHeight,
-					"server": fullFault.ServerID.String()[4:12], "audit": fullFault.AuditServerID.String()[4:12]}).Info("Full fault success")
-				//s.AddStatus(authorityDeltaString)
-
-				pl.State.LastFaultAction = time.Now().Unix()
-				markNoFault(pl, fullFault.GetVMIndex())
-				nextIndex := (int(fullFault.VMIndex) + 1) % len(pl.FedServers)
-				if pl.VMs[nextIndex].FaultFlag > 0 {
-					markNoFault(pl, nextIndex)
-				}
+	rHt := vm.Height
+	ffHt := int(fullFault.Height)
+	if rHt > ffHt {
+		//s.AddStatus(fmt.Sprintf("PROCESS Full Fault: FAIL but reset vm... %s", fullFault.StringWithSigCnt(s)))
+		vm.Height = ffHt
+		return false
+	} else if rHt < ffHt {
+		//s.AddStatus(fmt.Sprintf("PROCESS Full Fault: FAIL, vm not there yet. %s", fullFault.StringWithSigCnt(s)))
+		return false
+	}
 
-				s.LeaderPL = s.ProcessLists.Get(s.LLeaderHeight)
-				s.Leader, s.LeaderVMIndex = s.LeaderPL.GetVirtualServers(s.CurrentMinute, s.IdentityChainID)
+	// Here is where we actually swap out the Leader with the Audit server
+	// being promoted
+	for listIdx, fedServ := range pl.FedServers {
+		if fedServ.GetChainID().IsSameAs(fullFault.ServerID) {
 
-				fullFault.SetAlreadyProcessed()
-				faultLogger.WithField("func", "ProcessFault").WithFields(fullFault.LogFields()).Warn("Fault Processed (Leader Replaced)")
-				return true
-			}
-		}
-	} else {
-		// If we are here, this means that the FullFault message is incomplete
-		// and we cannot execute it (it might not have enough signatures, or it
-		// might lack the pledge from the audit server being promoted)
-
-		// We need to see whether our signature is included, and match the fault if not
-		// (assuming we agree with the basic premise of the fault)
-
-		for _, signature := range fullFault.SignatureList.List {
-			var issuerID [32]byte
-			rawIssuerID := signature.GetKey()
-			for i := 0; i < 32; i++ {
-				if i < len(rawIssuerID) {
-					issuerID[i] = rawIssuerID[i]
-				}
-			}
+			pl.FedServers[listIdx] = theAuditReplacement
+			pl.FedServers[listIdx].SetOnline(true)
+			pl.SetPromoted(theAuditReplacement.GetChainID())
+			audIdx := pl.AddAuditServer(fedServ.GetChainID())
+			pl.AuditServers[audIdx].SetOnline(false)
 
-			lbytes := fullFault.GetCoreHash().Bytes()
+			s.RemoveAuditServer(fullFault.DBHeight, theAuditReplacement.GetChainID())
+			// After executing the FullFault successfully, we want to reset
+			// to the default state (No One At Fault)
+			s.Leader, s.LeaderVMIndex = s.LeaderPL.GetVirtualServers(s.CurrentMinute, s.IdentityChainID)
 
-			isPledge := false
-			auth, _ := s.GetAuthority(fullFault.AuditServerID)
-			if auth == nil {
-				isPledge = false
-			} else {
-				valid, err := auth.VerifySignature(lbytes, signature.GetSignature())
-				if err == nil && valid {
-					isPledge = true
-					fullFault.SetPledgeDone(true)
+			authoritiesString := ""
+			for _, str := range s.ConstructAuthoritySetString() {
+				if len(authoritiesString) > 0 {
+					authoritiesString += "\n"
 				}
+				authoritiesString += str
 			}
-
-			sfSigned, err := s.FastVerifyAuthoritySignature(lbytes, signature, fullFault.DBHeight)
-
-			if err == nil && (sfSigned > 0 || (sfSigned == 0 && isPledge)) {
-				fullFault.AddFaultVote(issuerID, fullFault.GetSignature())
+			// Any updates required to the state as established by the AdminBlock are applied here.
+			pl.State.SetAuthoritySetString(authoritiesString)
+			authorityDeltaString := fmt.Sprintf("FULL FAULT SUCCESSFULLY PROCESSED DBHt: %d SysHt: %d ServerID %s AuditServerID %s",
+				fullFault.DBHeight,
+				fullFault.SystemHeight,
+				fullFault.ServerID.String()[4:12],
+				fullFault.AuditServerID.String()[4:12])
+			pl.State.AddAuthorityDelta(authorityDeltaString)
+
+			consenLogger.WithFields(log.Fields{"dbht": fullFault.DBHeight, "sysht": fullFault.SystemHeight,
+				"server": fullFault.ServerID.String()[4:12], "audit": fullFault.AuditServerID.String()[4:12]}).Info("Full fault success")
+			//s.AddStatus(authorityDeltaString)
+
+			pl.State.LastFaultAction = time.Now().Unix()
+			delete(pl.Elections, fullFault.GetVMIndex())
+			markNoFault(pl, fullFault.GetVMIndex())
+			nextIndex := (int(fullFault.VMIndex) + 1) % len(pl.FedServers)
+			if !elections && pl.VMs[nextIndex].FaultFlag > 0 {
+				markNoFault(pl, nextIndex)
 			}
 
-			if s.Leader || s.IdentityChainID.IsSameAs(fullFault.AuditServerID) {
-				if !fullFault.GetMyVoteTallied() {
-					nsf := messages.NewServerFault(fullFault.ServerID, fullFault.AuditServerID, int(fullFault.VMIndex), fullFault.DBHeight,
-						fullFault.Height, int(fullFault.SystemHeight), fullFault.Timestamp)
-					sfbytes, err := nsf.MarshalForSignature()
-					myAuth, _ := s.GetAuthority(s.IdentityChainID)
-					if myAuth == nil || err != nil {
-						continue
-					}
-					valid, err := myAuth.VerifySignature(sfbytes, signature.GetSignature())
-					if err == nil && valid {
-						fullFault.SetMyVoteTallied(true)
-					}
-				}
-			}
-		}
-
-		if s.Leader || s.IdentityChainID.IsSameAs(fullFault.AuditServerID) {
-			if !fullFault.GetMyVoteTallied() {
-				now := time.Now().Unix()
-				if now-fullFault.LastMatch > 5 && int(now-s.LastTiebreak) > s.FaultTimeout/2 {
-					if fullFault.SigTally(s) >= len(pl.FedServers)-1 {
-						s.LastTiebreak = now
-					}
-
-					nsf := messages.NewServerFault(fullFault.ServerID, fullFault.AuditServerID, int(fullFault.VMIndex),
-						fullFault.DBHeight, fullFault.Height, int(fullFault.SystemHeight), fullFault.Timestamp)
-					//s.AddStatus(fmt.Sprintf("Match FullFault: %s", nsf.String()))
+			s.LeaderPL = s.ProcessLists.Get(s.LLeaderHeight)
+			s.Leader, s.LeaderVMIndex = s.LeaderPL.GetVirtualServers(s.CurrentMinute, s.IdentityChainID)
 
-					s.matchFault(nsf)
-				}
-			}
+			fullFault.SetAlreadyProcessed()
+			faultLogger.WithField("func", "ProcessFault").WithFields(fullFault.LogFields()).Warn("Fault Processed (Leader Replaced)")
+			s.PublishStatusEvent(interfaces.StatusEventElectionEnd, int(fullFault.VMIndex),
+				fmt.Sprintf("replaced %x with %x", fullFault.ServerID.Bytes()[3:6], fullFault.AuditServerID.Bytes()[3:6]))
+			return true
 		}
 	}
 
@@ -2049,6 +2253,27 @@ func (s *State) IsNewOrPendingEBlocks(dbheight uint32, hash interfaces.IHash) bo
 	return false
 }
 
+// IsNewChainLimitReached returns true if the block at dbheight already creates as many
+// chains as the operator allows.  The limit is only for custom and test networks; on
+// mainnet chain creation is just counted.
+func (s *State) IsNewChainLimitReached(dbheight uint32) bool {
+	if s.MaxNewChainsPerBlock <= 0 || s.GetNetworkID() == constants.MAIN_NETWORK_ID {
+		return false
+	}
+	pl := s.ProcessLists.GetSafe(dbheight)
+	return pl != nil && pl.NewChains >= s.MaxNewChainsPerBlock
+}
+
+// IsEntryLimitReached returns true if the block at dbheight already holds as many entries
+// as the operator allows.  Like the chain limit, it is only for custom and test networks.
+func (s *State) IsEntryLimitReached(dbheight uint32) bool {
+	if s.MaxEntriesPerBlock <= 0 || s.GetNetworkID() == constants.MAIN_NETWORK_ID {
+		return false
+	}
+	pl := s.ProcessLists.GetSafe(dbheight)
+	return pl != nil && pl.LenNewEntries() >= s.MaxEntriesPerBlock
+}
+
 func (s *State) PutNewEBlocks(dbheight uint32, hash interfaces.IHash, eb interfaces.IEntryBlock) {
 	pl := s.ProcessLists.Get(dbheight)
 	pl.AddNewEBlocks(hash, eb)
@@ -2137,15 +2362,11 @@ func (s *State) GetF(rt bool, adr [32]byte) (v int64) {
 	if rt {
 		pl := s.ProcessLists.Get(s.LLeaderHeight)
 		if pl != nil {
-			pl.FactoidBalancesTMutex.Lock()
-			defer pl.FactoidBalancesTMutex.Unlock()
-			v, ok = pl.FactoidBalancesT[adr]
+			v, ok = pl.FactoidBalancesT.Get(adr)
 		}
 	}
 	if !ok {
-		s.FactoidBalancesPMutex.Lock()
-		defer s.FactoidBalancesPMutex.Unlock()
-		v = s.FactoidBalancesP[adr]
+		v, _ = s.FactoidBalancesP.Get(adr)
 	}
 
 	return v
@@ -2157,15 +2378,10 @@ func (s *State) PutF(rt bool, adr [32]byte, v int64) {
 	if rt {
 		pl := s.ProcessLists.Get(s.LLeaderHeight)
 		if pl != nil {
-			pl.FactoidBalancesTMutex.Lock()
-			defer pl.FactoidBalancesTMutex.Unlock()
-
-			pl.FactoidBalancesT[adr] = v
+			pl.FactoidBalancesT.Put(adr, v)
 		}
 	} else {
-		s.FactoidBalancesPMutex.Lock()
-		defer s.FactoidBalancesPMutex.Unlock()
-		s.FactoidBalancesP[adr] = v
+		s.FactoidBalancesP.Put(adr, v)
 	}
 }
 
@@ -2174,15 +2390,11 @@ func (s *State) GetE(rt bool, adr [32]byte) (v int64) {
 	if rt {
 		pl := s.ProcessLists.Get(s.LLeaderHeight)
 		if pl != nil {
-			pl.ECBalancesTMutex.Lock()
-			defer pl.ECBalancesTMutex.Unlock()
-			v, ok = pl.ECBalancesT[adr]
+			v, ok = pl.ECBalancesT.Get(adr)
 		}
 	}
 	if !ok {
-		s.ECBalancesPMutex.Lock()
-		defer s.ECBalancesPMutex.Unlock()
-		v = s.ECBalancesP[adr]
+		v, _ = s.ECBalancesP.Get(adr)
 	}
 	return v
 
@@ -2193,14 +2405,10 @@ func (s *State) PutE(rt bool, adr [32]byte, v int64) {
 	if rt {
 		pl := s.ProcessLists.Get(s.LLeaderHeight)
 		if pl != nil {
-			pl.ECBalancesTMutex.Lock()
-			defer pl.ECBalancesTMutex.Unlock()
-			pl.ECBalancesT[adr] = v
+			pl.ECBalancesT.Put(adr, v)
 		}
 	} else {
-		s.ECBalancesPMutex.Lock()
-		defer s.ECBalancesPMutex.Unlock()
-		s.ECBalancesP[adr] = v
+		s.ECBalancesP.Put(adr, v)
 	}
 }
 
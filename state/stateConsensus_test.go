@@ -6,9 +6,13 @@ package state_test
 
 import (
 	"testing"
+	"time"
 
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/entryBlock"
 	"github.com/FactomProject/factomd/common/entryCreditBlock"
 	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
 	. "github.com/FactomProject/factomd/state"
 	"github.com/FactomProject/factomd/testHelper"
 )
@@ -48,3 +52,61 @@ func newCom() *messages.CommitEntryMsg {
 
 	return commit
 }
+
+func TestDataResponseWorker(t *testing.T) {
+	s := testHelper.CreateEmptyTestState()
+
+	entry := entryBlock.NewEntry()
+	entry.Content = primitives.ByteSlice{Bytes: []byte("data response")}
+
+	// With no worker running, the queue fills and responses are dropped rather than
+	// blocking the caller
+	done := make(chan bool)
+	go func() {
+		for i := 0; i < 2000; i++ {
+			s.FollowerExecuteDataResponse(messages.NewDataResponse(s, entry, 0, entry.GetHash()))
+		}
+		done <- true
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("FollowerExecuteDataResponse blocked on a full queue")
+	}
+
+	// Once the worker runs, the queued entries make their way to be written
+	go s.GoHandleDataResponses()
+	select {
+	case e := <-s.WriteEntry:
+		if !e.GetHash().IsSameAs(entry.GetHash()) {
+			t.Error("Wrong entry handed to be written")
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("DataResponse worker did not hand on the entry")
+	}
+}
+
+func TestIsNewChainLimitReached(t *testing.T) {
+	s := testHelper.CreateEmptyTestState()
+	pl := s.ProcessLists.Get(s.LLeaderHeight)
+	pl.NewChains = 3
+
+	if s.IsNewChainLimitReached(s.LLeaderHeight) {
+		t.Error("Limit reached with no limit set")
+	}
+	s.MaxNewChainsPerBlock = 3
+	if !s.IsNewChainLimitReached(s.LLeaderHeight) {
+		t.Error("Limit not reached at 3 of 3 chains")
+	}
+	s.MaxNewChainsPerBlock = 4
+	if s.IsNewChainLimitReached(s.LLeaderHeight) {
+		t.Error("Limit reached at 3 of 4 chains")
+	}
+
+	// Mainnet only counts
+	s.MaxNewChainsPerBlock = 1
+	s.NetworkNumber = constants.NETWORK_MAIN
+	if s.IsNewChainLimitReached(s.LLeaderHeight) {
+		t.Error("Limit applied on mainnet")
+	}
+}
@@ -34,6 +34,7 @@ type DisplayState struct {
 	CurrentEBDBHeight   uint32
 	LeaderHeight        uint32
 	LastDirectoryBlock  interfaces.IDirectoryBlock
+	Startup             interfaces.StartupStatus
 
 	// Identity Info
 	IdentityChainID interfaces.IHash
@@ -109,6 +110,7 @@ func DeepStateDisplayCopy(s *State) (*DisplayState, error) {
 	ds.CurrentLeaderHeight = s.GetLeaderHeight()
 	ds.CurrentEBDBHeight = s.EntryDBHeightComplete
 	ds.LeaderHeight = s.GetTrueLeaderHeight()
+	ds.Startup = s.GetStartupStatus()
 	dir := s.GetDirectoryBlockByHeight(s.GetLeaderHeight())
 	if dir == nil {
 		dir = s.GetDirectoryBlockByHeight(s.GetLeaderHeight() - 1)
@@ -9,21 +9,32 @@ import (
 	"io/ioutil"
 	"os"
 	"sync"
+	"time"
 
+	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/common/primitives"
+	log "github.com/sirupsen/logrus"
 )
 
+// Directory blocks between fastboot saves, if the config doesn't say
+const DefaultFastBootSaveInterval = 1000
+
 type StateSaverStruct struct {
 	FastBoot         bool
 	FastBootLocation string
+	// Directory blocks between saves, 0 for DefaultFastBootSaveInterval
+	SaveInterval uint32
 
 	TmpState []byte
 	Mutex    sync.Mutex
 	Stop     bool
+
+	saving  bool // A save is running in the background
+	pending sync.WaitGroup
 }
 
 //To be increased whenever the data being saved changes from the last verion
-const version = 7
+const version = 8
 
 func (sss *StateSaverStruct) StopSaving() {
 	sss.Mutex.Lock()
@@ -31,43 +42,139 @@ func (sss *StateSaverStruct) StopSaving() {
 	sss.Stop = true
 }
 
+// Wait returns once any save running in the background is done
+func (sss *StateSaverStruct) Wait() {
+	sss.pending.Wait()
+}
+
+// Flush waits on any save running in the background, then writes out the state it cached,
+// as the next save would have, so a node that is shut down boots from as recent a state as
+// it can
+func (sss *StateSaverStruct) Flush(networkName string) error {
+	sss.Wait()
+	sss.Mutex.Lock()
+	defer sss.Mutex.Unlock()
+	if len(sss.TmpState) == 0 {
+		return nil
+	}
+	err := SaveToFile(sss.TmpState, NetworkIDToFilename(networkName, sss.FastBootLocation))
+	if err == nil {
+		sss.TmpState = nil
+	}
+	return err
+}
+
+func (sss *StateSaverStruct) interval() uint32 {
+	if sss.SaveInterval == 0 {
+		return DefaultFastBootSaveInterval
+	}
+	return sss.SaveInterval
+}
+
+// SaveDBStateList saves the state every SaveInterval directory blocks.  Only a copy of
+// the list is taken here, in the consensus loop; it is marshalled and written out in the
+// background, so a save doesn't hold up the blocks being saved around it.  A save that
+// comes due while the last one is still running is skipped.
 func (sss *StateSaverStruct) SaveDBStateList(ss *DBStateList, networkName string) error {
 	//For now, to file. Later - to DB
-	if sss.Stop == true {
+	sss.Mutex.Lock()
+	stop := sss.Stop
+	sss.Mutex.Unlock()
+	if stop {
 		return nil
 	}
-	sss.Mutex.Lock()
-	defer sss.Mutex.Unlock()
 
 	//Don't save States after the server has booted - it might start it in a wrong state
 	if ss.State.DBFinished == true {
 		return nil
 	}
 
-	//Save only every 1000 states
-	if ss.GetHighestSavedBlk()%1000 != 0 || ss.GetHighestSavedBlk() < 1000 {
+	height := ss.GetHighestSavedBlk()
+	interval := sss.interval()
+	if height%interval != 0 || height < interval {
+		return nil
+	}
+
+	sss.Mutex.Lock()
+	if sss.saving {
+		sss.Mutex.Unlock()
+		FastBootSavesSkipped.Inc()
+		packageLogger.WithFields(log.Fields{"subpack": "fastboot", "dbheight": height}).Warn("Skipping a fastboot save, the last one is still running")
 		return nil
 	}
+	sss.saving = true
+	sss.pending.Add(1)
+	sss.Mutex.Unlock()
+
+	start := time.Now()
+	snap := ss.snapshot()
+	FastBootSaveDuration.WithLabelValues("snapshot").Observe(time.Since(start).Seconds())
+
+	go sss.save(snap, height, networkName)
+	return nil
+}
+
+// save writes out the state cached by the last save, then marshals the snapshot to be
+// written by the next.  Writing the previous state rather than this one means a restart
+// never boots from blocks that might yet be rolled back.
+func (sss *StateSaverStruct) save(snap *DBStateList, height uint32, networkName string) {
+	sss.Mutex.Lock()
+	defer sss.Mutex.Unlock()
+	defer sss.pending.Done()
+	defer func() { sss.saving = false }()
+
+	logger := packageLogger.WithFields(log.Fields{"subpack": "fastboot", "dbheight": height})
 
-	//Actually save data from previous cached state to prevent dealing with rollbacks
 	if len(sss.TmpState) > 0 {
+		start := time.Now()
 		err := SaveToFile(sss.TmpState, NetworkIDToFilename(networkName, sss.FastBootLocation))
+		FastBootSaveDuration.WithLabelValues("write").Observe(time.Since(start).Seconds())
 		if err != nil {
-			return err
+			logger.Errorf("Failed writing the fastboot file: %v", err)
+			return
 		}
 	}
 
 	//Marshal state for future saving
-	b, err := ss.MarshalBinary()
+	start := time.Now()
+	b, err := snap.MarshalBinary()
 	if err != nil {
-		return err
+		logger.Errorf("Failed marshalling the state for fastboot: %v", err)
+		return
 	}
 	//adding an integrity check
 	h := primitives.Sha(b)
 	b = append(h.Bytes(), b...)
 	sss.TmpState = b
+	FastBootSaveDuration.WithLabelValues("marshal").Observe(time.Since(start).Seconds())
+}
 
-	return nil
+// snapshot copies the list and its DBStates, so the copy can be marshalled while the
+// consensus loop carries on changing them.  The blocks and SaveStructs are shared; they
+// don't change once they are in a DBState.
+func (dbsl *DBStateList) snapshot() *DBStateList {
+	c := new(DBStateList)
+	c.SrcNetwork = dbsl.SrcNetwork
+	c.LastEnd = dbsl.LastEnd
+	c.LastBegin = dbsl.LastBegin
+	if dbsl.TimeToAsk != nil {
+		c.TimeToAsk = primitives.NewTimestampFromMilliseconds(dbsl.TimeToAsk.GetTimeMilliUInt64())
+	}
+	c.ProcessHeight = dbsl.ProcessHeight
+	c.SavedHeight = dbsl.SavedHeight
+	c.Base = dbsl.Base
+	c.Complete = dbsl.Complete
+	c.DBStates = make([]*DBState, len(dbsl.DBStates))
+	for i, dbs := range dbsl.DBStates {
+		if dbs == nil {
+			continue
+		}
+		d := *dbs
+		d.EntryBlocks = append([]interfaces.IEntryBlock(nil), dbs.EntryBlocks...)
+		d.Entries = append([]interfaces.IEBEntry(nil), dbs.Entries...)
+		c.DBStates[i] = &d
+	}
+	return c
 }
 
 func (sss *StateSaverStruct) DeleteSaveState(networkName string) error {
@@ -105,12 +212,15 @@ func NetworkIDToFilename(networkName string, fileLocation string) string {
 	return file
 }
 
+// SaveToFile writes the file beside where it goes, then moves it there, so a node killed
+// while writing never leaves half a file behind
 func SaveToFile(b []byte, filename string) error {
-	err := ioutil.WriteFile(filename, b, 0644)
+	tmp := filename + ".tmp"
+	err := ioutil.WriteFile(tmp, b, 0644)
 	if err != nil {
 		return err
 	}
-	return nil
+	return os.Rename(tmp, filename)
 }
 
 func LoadFromFile(filename string) ([]byte, error) {
@@ -0,0 +1,87 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	. "github.com/FactomProject/factomd/state"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestSaveDBStateListInterval(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fastboot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s := testHelper.CreateEmptyTestState()
+	s.DBFinished = false
+
+	sss := new(StateSaverStruct)
+	sss.FastBoot = true
+	sss.FastBootLocation = dir
+	sss.SaveInterval = 2
+	filename := NetworkIDToFilename("TEST", dir)
+
+	list := new(DBStateList)
+	list.State = s
+
+	// The first save is only cached, to be written by the next
+	list.Base = 4
+	if err := sss.SaveDBStateList(list, "TEST"); err != nil {
+		t.Fatal(err)
+	}
+	sss.Wait()
+	if len(sss.TmpState) == 0 {
+		t.Fatal("State was not marshalled")
+	}
+	if _, err := os.Stat(filename); err == nil {
+		t.Error("The first save should not write the file")
+	}
+	first := sss.TmpState
+
+	// Off the interval
+	list.Base = 5
+	sss.SaveDBStateList(list, "TEST")
+	sss.Wait()
+	if !bytes.Equal(first, sss.TmpState) {
+		t.Error("Saved off the interval")
+	}
+
+	list.Base = 6
+	sss.SaveDBStateList(list, "TEST")
+	sss.Wait()
+	written, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(first, written) {
+		t.Error("The file should hold the state cached by the previous save")
+	}
+
+	loaded := new(DBStateList)
+	loaded.State = s
+	if err := sss.LoadDBStateList(loaded, "TEST"); err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Base != 4 {
+		t.Errorf("Loaded the state of height %d, expected 4", loaded.Base)
+	}
+
+	// Nothing is saved once the node has booted
+	s.DBFinished = true
+	list.Base = 8
+	sss.SaveDBStateList(list, "TEST")
+	sss.Wait()
+	written, _ = ioutil.ReadFile(filename)
+	if !bytes.Equal(first, written) {
+		t.Error("Saved after booting")
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+)
+
+// Number of events buffered for each subscriber.  A subscriber that falls further
+// behind than this will miss events rather than hold up the consensus process.
+const statusEventBuffer = 100
+
+// StatusEventHub fans status transition events out to any number of subscribers.
+// Publishing never blocks; slow subscribers simply lose events.
+type StatusEventHub struct {
+	mutex       sync.Mutex
+	nextID      int
+	subscribers map[int]chan *interfaces.StatusEvent
+	Dropped     int // Count of events not delivered because a subscriber was full
+}
+
+func NewStatusEventHub() *StatusEventHub {
+	h := new(StatusEventHub)
+	h.subscribers = make(map[int]chan *interfaces.StatusEvent)
+	return h
+}
+
+// Subscribe returns an id (used to unsubscribe) and the channel the events will arrive on
+func (h *StatusEventHub) Subscribe() (int, <-chan *interfaces.StatusEvent) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.nextID++
+	c := make(chan *interfaces.StatusEvent, statusEventBuffer)
+	h.subscribers[h.nextID] = c
+	return h.nextID, c
+}
+
+// Unsubscribe removes the subscriber and closes its channel
+func (h *StatusEventHub) Unsubscribe(id int) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if c, ok := h.subscribers[id]; ok {
+		delete(h.subscribers, id)
+		close(c)
+	}
+}
+
+// Subscribers returns the number of current subscribers
+func (h *StatusEventHub) Subscribers() int {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return len(h.subscribers)
+}
+
+func (h *StatusEventHub) Publish(event *interfaces.StatusEvent) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	for _, c := range h.subscribers {
+		select {
+		case c <- event:
+		default:
+			h.Dropped++
+		}
+	}
+}
+
+// statusTracker remembers the last status we reported, so we only publish transitions
+type statusTracker struct {
+	initialized   bool
+	inSync        bool
+	leader        bool
+	leaderVMIndex int
+	stalled       bool
+}
+
+func (s *State) SubscribeStatusEvents() (int, <-chan *interfaces.StatusEvent) {
+	return s.StatusEvents.Subscribe()
+}
+
+func (s *State) UnsubscribeStatusEvents(id int) {
+	s.StatusEvents.Unsubscribe(id)
+}
+
+// PublishStatusEvent builds a status event from the current state and sends it to
+// all subscribers.
+func (s *State) PublishStatusEvent(eventType string, vmIndex int, detail string) {
+	if s.StatusEvents == nil {
+		return
+	}
+	event := new(interfaces.StatusEvent)
+	event.Type = eventType
+	event.NodeName = s.FactomNodeName
+	event.DBHeight = s.LLeaderHeight
+	event.Minute = s.CurrentMinute
+	event.VMIndex = vmIndex
+	event.Timestamp = time.Now().Unix()
+	event.Detail = detail
+	s.StatusEvents.Publish(event)
+	if s.Events != nil {
+		s.Events.Publish(&interfaces.Event{Type: interfaces.EventNodeStatus, DBHeight: event.DBHeight, Timestamp: event.Timestamp, Status: event})
+	}
+}
+
+// IsInSync returns true once the database is loaded and we are building the highest
+// block we have heard of from the network.
+func (s *State) IsInSync() bool {
+	return s.DBFinished && s.GetHighestKnownBlock() <= s.LLeaderHeight
+}
+
+// checkStatusTransitions compares the current status against the last one we published,
+// and publishes any transitions.  Called from UpdateState.
+func (s *State) checkStatusTransitions() {
+	t := &s.statusTrack
+	inSync := s.IsInSync()
+	stalled := s.IsStalled()
+
+	if !t.initialized {
+		t.initialized = true
+		t.inSync = inSync
+		t.leader = s.Leader
+		t.leaderVMIndex = s.LeaderVMIndex
+		t.stalled = stalled
+		return
+	}
+
+	if inSync != t.inSync {
+		t.inSync = inSync
+		if inSync {
+			s.PublishStatusEvent(interfaces.StatusEventInSync, -1, "")
+		} else {
+			s.PublishStatusEvent(interfaces.StatusEventSyncing, -1,
+				fmt.Sprintf("highest known block %d", s.GetHighestKnownBlock()))
+		}
+	}
+
+	if s.Leader != t.leader || (s.Leader && s.LeaderVMIndex != t.leaderVMIndex) {
+		t.leader = s.Leader
+		t.leaderVMIndex = s.LeaderVMIndex
+		if s.Leader {
+			s.PublishStatusEvent(interfaces.StatusEventLeader, s.LeaderVMIndex, "")
+		} else {
+			s.PublishStatusEvent(interfaces.StatusEventFollower, -1, "")
+		}
+	}
+
+	if stalled != t.stalled {
+		t.stalled = stalled
+		if stalled {
+			s.PublishStatusEvent(interfaces.StatusEventStalled, -1, "")
+		} else {
+			s.PublishStatusEvent(interfaces.StatusEventUnstalled, -1, "")
+		}
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	. "github.com/FactomProject/factomd/state"
+)
+
+func TestStatusEventHub(t *testing.T) {
+	h := NewStatusEventHub()
+	id1, c1 := h.Subscribe()
+	id2, c2 := h.Subscribe()
+	if id1 == id2 {
+		t.Error("Subscribers were given the same id")
+	}
+	if h.Subscribers() != 2 {
+		t.Errorf("Expected 2 subscribers, found %d", h.Subscribers())
+	}
+
+	h.Publish(&interfaces.StatusEvent{Type: interfaces.StatusEventInSync})
+	for _, c := range []<-chan *interfaces.StatusEvent{c1, c2} {
+		e := <-c
+		if e.Type != interfaces.StatusEventInSync {
+			t.Errorf("Expected %s, got %s", interfaces.StatusEventInSync, e.Type)
+		}
+	}
+
+	h.Unsubscribe(id1)
+	if _, ok := <-c1; ok {
+		t.Error("Channel should be closed on unsubscribe")
+	}
+	h.Unsubscribe(id1) // Must be harmless
+
+	// A subscriber that never reads must not block publishing
+	for i := 0; i < 1000; i++ {
+		h.Publish(&interfaces.StatusEvent{Type: interfaces.StatusEventStalled})
+	}
+	if h.Dropped == 0 {
+		t.Error("Expected events to be dropped for a full subscriber")
+	}
+	h.Unsubscribe(id2)
+	if h.Subscribers() != 0 {
+		t.Errorf("Expected 0 subscribers, found %d", h.Subscribers())
+	}
+}
+
+func TestStatusTransitions(t *testing.T) {
+	s := new(State)
+	s.StatusEvents = NewStatusEventHub()
+	s.LeaderVMIndex = 2
+	_, c := s.SubscribeStatusEvents()
+
+	// PublishStatusEvent fills in the node details
+	s.FactomNodeName = "FNode0"
+	s.LLeaderHeight = 10
+	s.PublishStatusEvent(interfaces.StatusEventElectionStart, 1, "test")
+	e := <-c
+	if e.NodeName != "FNode0" || e.DBHeight != 10 || e.VMIndex != 1 || e.Detail != "test" {
+		t.Errorf("Unexpected event %v", e)
+	}
+}
@@ -0,0 +1,198 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var supplyLogger = packageLogger.WithFields(log.Fields{"subpack": "supply"})
+
+// How many blocks of supply history are kept for the burn rate series
+const MaxSupplyHistory = 1000
+
+// SupplyTracker counts the factoids issued, burned for entry credits and paid in fees by
+// each saved factoid block, so the node can answer supply questions from the chain itself.
+// Blocks are counted in height order by a scan of the database.
+type SupplyTracker struct {
+	mutex   sync.Mutex
+	next    uint32 // Height of the next factoid block to count
+	status  interfaces.SupplyStatus
+	history []interfaces.SupplyBlock
+}
+
+func NewSupplyTracker() *SupplyTracker {
+	return new(SupplyTracker)
+}
+
+// BlockSupply totals the factoid movements of a block.  A transaction with no inputs
+// creates factoids (the coinbase, and the genesis distribution); otherwise whatever the
+// inputs hold beyond the outputs and entry credit purchases is the fee.
+func BlockSupply(fb interfaces.IFBlock) (interfaces.SupplyBlock, error) {
+	sb := interfaces.SupplyBlock{DBHeight: fb.GetDBHeight()}
+	if ts := fb.GetCoinbaseTimestamp(); ts != nil {
+		sb.Timestamp = ts.GetTimeSeconds()
+	}
+	for _, tx := range fb.GetTransactions() {
+		in, err := tx.TotalInputs()
+		if err != nil {
+			return sb, err
+		}
+		out, err := tx.TotalOutputs()
+		if err != nil {
+			return sb, err
+		}
+		ecs, err := tx.TotalECs()
+		if err != nil {
+			return sb, err
+		}
+		sb.Burned += ecs
+		if in == 0 {
+			sb.Issued += out
+			continue
+		}
+		if in < out+ecs {
+			return sb, fmt.Errorf("Transaction %s at height %d spends more than its inputs", tx.GetSigHash().String(), sb.DBHeight)
+		}
+		sb.Fees += in - out - ecs
+	}
+	return sb, nil
+}
+
+// Next returns the height of the next factoid block to be counted
+func (t *SupplyTracker) Next() uint32 {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.next
+}
+
+// Add counts a factoid block, which must be the one at Next()
+func (t *SupplyTracker) Add(fb interfaces.IFBlock) error {
+	sb, err := BlockSupply(fb)
+	if err != nil {
+		return err
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if sb.DBHeight != t.next {
+		return fmt.Errorf("Factoid block at height %d counted out of order, expected %d", sb.DBHeight, t.next)
+	}
+	t.status.ScannedHeight = sb.DBHeight
+	t.status.TotalIssued += sb.Issued
+	t.status.TotalBurned += sb.Burned
+	t.status.TotalFees += sb.Fees
+	t.history = append(t.history, sb)
+	if len(t.history) > MaxSupplyHistory {
+		t.history = append([]interfaces.SupplyBlock{}, t.history[len(t.history)-MaxSupplyHistory:]...)
+	}
+	t.next++
+	return nil
+}
+
+// SetComplete marks whether every saved block has been counted
+func (t *SupplyTracker) SetComplete(complete bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.status.Complete = complete
+}
+
+// Status returns the totals, with the history of up to the last blocks blocks
+func (t *SupplyTracker) Status(blocks int) interfaces.SupplyStatus {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	st := t.status
+	st.TotalSupply = st.TotalIssued - st.TotalBurned - st.TotalFees
+	if blocks > len(t.history) {
+		blocks = len(t.history)
+	}
+	if blocks > 0 {
+		st.History = append([]interfaces.SupplyBlock{}, t.history[len(t.history)-blocks:]...)
+	}
+	return st
+}
+
+// ParseFactoidAddresses reads a comma separated list of factoid addresses, as written
+// by users (FA...)
+func ParseFactoidAddresses(config string) ([]interfaces.IHash, error) {
+	var addresses []interfaces.IHash
+	for _, item := range strings.Split(config, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if !primitives.ValidateFUserStr(item) {
+			return nil, fmt.Errorf("%q is not a factoid address", item)
+		}
+		addresses = append(addresses, primitives.NewHash(primitives.ConvertUserStrToAddress(item)))
+	}
+	return addresses, nil
+}
+
+// ScanSupply counts the factoid blocks saved since the last scan
+func (s *State) ScanSupply() {
+	t := s.Supply
+	if t == nil {
+		return
+	}
+	highest := s.GetHighestSavedBlk()
+	for h := t.Next(); h <= highest; h++ {
+		fb, err := s.DB.FetchFBlockByHeight(h)
+		if err != nil || fb == nil {
+			supplyLogger.Errorf("Cannot fetch the factoid block at height %d: %v", h, err)
+			t.SetComplete(false)
+			return
+		}
+		if err := t.Add(fb); err != nil {
+			supplyLogger.Errorf("Cannot count the factoid block at height %d: %v", h, err)
+			t.SetComplete(false)
+			return
+		}
+	}
+	t.SetComplete(true)
+}
+
+// GoTrackSupply keeps the supply totals up with the saved blocks
+func (s *State) GoTrackSupply() {
+	for {
+		if s.DBFinished {
+			s.ScanSupply()
+		}
+		time.Sleep(30 * time.Second)
+	}
+}
+
+// GetSupply returns the factoid supply, with the movements of up to the last blocks
+// blocks.  What is circulating is estimated from the current balances of the addresses
+// configured as not circulating.
+func (s *State) GetSupply(blocks int) interfaces.SupplyStatus {
+	if s.Supply == nil {
+		return interfaces.SupplyStatus{}
+	}
+	st := s.Supply.Status(blocks)
+	view := s.GetReadView()
+	for _, adr := range s.NonCirculatingAddresses {
+		var balance int64
+		if view != nil {
+			balance = view.GetFactoidBalance(adr.Fixed())
+		} else {
+			balance = s.FactoidState.GetFactoidBalance(adr.Fixed())
+		}
+		if balance > 0 {
+			st.NonCirculating += uint64(balance)
+		}
+	}
+	if st.NonCirculating < st.TotalSupply {
+		st.Circulating = st.TotalSupply - st.NonCirculating
+	}
+	return st
+}
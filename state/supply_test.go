@@ -0,0 +1,77 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/common/factoid"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	. "github.com/FactomProject/factomd/state"
+)
+
+func supplyBlock(prev interfaces.IFBlock, issued, spent, sent, burned uint64) interfaces.IFBlock {
+	fb := factoid.NewFBlock(prev).(*factoid.FBlock)
+	adr := factoid.NewAddress(primitives.Sha([]byte("supply")).Bytes())
+
+	coinbase := new(factoid.Transaction)
+	coinbase.AddOutput(adr, issued)
+	fb.Transactions = append(fb.Transactions, coinbase)
+
+	if spent > 0 {
+		tx := new(factoid.Transaction)
+		tx.AddInput(adr, spent)
+		tx.AddOutput(adr, sent)
+		tx.AddECOutput(adr, burned)
+		fb.Transactions = append(fb.Transactions, tx)
+	}
+	return fb
+}
+
+func TestSupplyTracker(t *testing.T) {
+	st := NewSupplyTracker()
+
+	b0 := supplyBlock(nil, 1000, 0, 0, 0)
+	b1 := supplyBlock(b0, 50, 300, 100, 190)
+	b2 := supplyBlock(b1, 50, 0, 0, 0)
+
+	if err := st.Add(b1); err == nil {
+		t.Errorf("Expected an error counting a block out of order")
+	}
+	for _, fb := range []interfaces.IFBlock{b0, b1, b2} {
+		if err := st.Add(fb); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	status := st.Status(2)
+	if status.ScannedHeight != 2 || st.Next() != 3 {
+		t.Errorf("Unexpected heights %d %d", status.ScannedHeight, st.Next())
+	}
+	if status.TotalIssued != 1100 || status.TotalBurned != 190 || status.TotalFees != 10 || status.TotalSupply != 900 {
+		t.Errorf("Unexpected totals %+v", status)
+	}
+	if len(status.History) != 2 || status.History[0].DBHeight != 1 || status.History[0].Burned != 190 || status.History[0].Fees != 10 {
+		t.Errorf("Unexpected history %+v", status.History)
+	}
+}
+
+func TestBlockSupplyOverspend(t *testing.T) {
+	fb := supplyBlock(nil, 0, 100, 100, 1)
+	if _, err := BlockSupply(fb); err == nil {
+		t.Errorf("Expected an error for a transaction spending more than its inputs")
+	}
+}
+
+func TestParseFactoidAddresses(t *testing.T) {
+	addresses, err := ParseFactoidAddresses(" FA2jK2HcLnRdS94dEcU27rF3meoJfpUcZPSinpb7AwQvPRY6RL1Q, ")
+	if err != nil || len(addresses) != 1 {
+		t.Errorf("Unexpected result %v %v", addresses, err)
+	}
+	if _, err := ParseFactoidAddresses("FA123"); err == nil {
+		t.Errorf("Expected an error for a bad address")
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"github.com/FactomProject/factomd/common/interfaces"
+)
+
+// Phases of the consensus state machine, as set by the sync flags
+const (
+	SyncPhaseProcessing = "processing" // Processing the messages of the VMs
+	SyncPhaseEOM        = "eom-sync"   // Collecting the EOMs of the minute
+	SyncPhaseEOMDone    = "eom-done"   // Every EOM processed, unwinding them
+	SyncPhaseDBSig      = "dbsig-sync" // Collecting the DBSigs of the previous block
+	SyncPhaseDBSigDone  = "dbsig-done" // Every DBSig processed, unwinding them
+)
+
+var syncPhases = []interfaces.SyncStateNode{
+	{ID: SyncPhaseProcessing, Label: "Processing"},
+	{ID: SyncPhaseEOM, Label: "Syncing EOMs"},
+	{ID: SyncPhaseEOMDone, Label: "EOMs done"},
+	{ID: SyncPhaseDBSig, Label: "Syncing DBSigs"},
+	{ID: SyncPhaseDBSigDone, Label: "DBSigs done"},
+}
+
+var syncMoves = []interfaces.SyncStateEdge{
+	{From: SyncPhaseProcessing, To: SyncPhaseEOM, Label: "first EOM of the minute"},
+	{From: SyncPhaseEOM, To: SyncPhaseEOMDone, Label: "EOM from every VM, faults processed"},
+	{From: SyncPhaseEOMDone, To: SyncPhaseProcessing, Label: "EOMs unwound, previous block saved"},
+	{From: SyncPhaseProcessing, To: SyncPhaseDBSig, Label: "first DBSig of the block"},
+	{From: SyncPhaseDBSig, To: SyncPhaseDBSigDone, Label: "DBSig from every VM, block matches"},
+	{From: SyncPhaseDBSigDone, To: SyncPhaseProcessing, Label: "DBSigs unwound"},
+}
+
+// SyncPhase names the phase of the consensus state machine the sync flags put the node in
+func (s *State) SyncPhase() string {
+	switch {
+	case s.DBSig && s.DBSigDone:
+		return SyncPhaseDBSigDone
+	case s.DBSig:
+		return SyncPhaseDBSig
+	case s.EOM && s.EOMDone:
+		return SyncPhaseEOMDone
+	case s.EOM:
+		return SyncPhaseEOM
+	}
+	return SyncPhaseProcessing
+}
+
+// GetSyncState returns a snapshot of the consensus state machine, to render the sync
+// flags and VMs for debugging.  Like the other status calls it reads the flags without
+// stopping the consensus loop, so the snapshot may be mid-update.
+func (s *State) GetSyncState() interfaces.SyncState {
+	ss := interfaces.SyncState{
+		DBHeight:      s.LLeaderHeight,
+		Minute:        s.CurrentMinute,
+		Phase:         s.SyncPhase(),
+		Leader:        s.Leader,
+		LeaderVMIndex: s.LeaderVMIndex,
+
+		Syncing:    s.Syncing,
+		Saving:     s.Saving,
+		EOMsyncing: s.EOMsyncing,
+
+		EOM:          s.EOM,
+		EOMMinute:    s.EOMMinute,
+		EOMProcessed: s.EOMProcessed,
+		EOMLimit:     s.EOMLimit,
+		EOMDone:      s.EOMDone,
+		EOMSys:       s.EOMSys,
+
+		DBSig:          s.DBSig,
+		DBSigProcessed: s.DBSigProcessed,
+		DBSigLimit:     s.DBSigLimit,
+		DBSigDone:      s.DBSigDone,
+		DBSigSys:       s.DBSigSys,
+	}
+
+	for _, n := range syncPhases {
+		n.Active = n.ID == ss.Phase
+		ss.Nodes = append(ss.Nodes, n)
+	}
+	ss.Edges = append(ss.Edges, syncMoves...)
+
+	pl := s.LeaderPL
+	if pl == nil {
+		return ss
+	}
+	ss.SystemHeight = pl.System.Height
+	ss.SystemLength = len(pl.System.List)
+	ss.SysHighest = pl.SysHighest
+	ss.FaultPending = ss.SystemLength > ss.SystemHeight
+	ss.FaultsWaiting = ss.SystemHeight < ss.SysHighest
+
+	minute := s.CurrentMinute
+	if minute < 0 || minute >= len(pl.ServerMap) {
+		minute = 0
+	}
+	for i, vm := range pl.VMs {
+		if i >= len(pl.FedServers) {
+			break
+		}
+		v := interfaces.SyncStateVM{
+			VMIndex:      i,
+			Height:       vm.Height,
+			Acked:        len(vm.List),
+			LeaderMinute: vm.LeaderMinute,
+			Synced:       vm.Synced,
+			Signed:       vm.Signed,
+			Faulted:      vm.WhenFaulted > 0,
+			FaultFlag:    vm.FaultFlag,
+		}
+		if fed := pl.ServerMap[minute][i]; fed >= 0 && fed < len(pl.FedServers) {
+			v.Leader = pl.FedServers[fed].GetChainID().String()
+		}
+		ss.VMs = append(ss.VMs, v)
+	}
+	return ss
+}
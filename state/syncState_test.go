@@ -0,0 +1,56 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/FactomProject/factomd/state"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestSyncState(t *testing.T) {
+	s := testHelper.CreateAndPopulateTestState()
+
+	for _, test := range []struct {
+		eom, eomDone, dbsig, dbsigDone bool
+		phase                          string
+	}{
+		{false, false, false, false, SyncPhaseProcessing},
+		{true, false, false, false, SyncPhaseEOM},
+		{true, true, false, false, SyncPhaseEOMDone},
+		{false, false, true, false, SyncPhaseDBSig},
+		{false, false, true, true, SyncPhaseDBSigDone},
+	} {
+		s.EOM, s.EOMDone, s.DBSig, s.DBSigDone = test.eom, test.eomDone, test.dbsig, test.dbsigDone
+		ss := s.GetSyncState()
+		if ss.Phase != test.phase {
+			t.Errorf("Expected phase %s, got %s", test.phase, ss.Phase)
+		}
+		active := 0
+		for _, n := range ss.Nodes {
+			if n.Active {
+				active++
+				if n.ID != test.phase {
+					t.Errorf("Node %s active in phase %s", n.ID, test.phase)
+				}
+			}
+		}
+		if active != 1 {
+			t.Errorf("%d nodes active in phase %s", active, test.phase)
+		}
+	}
+
+	ss := s.GetSyncState()
+	if len(ss.VMs) != len(s.LeaderPL.FedServers) {
+		t.Errorf("Expected %d VMs, got %d", len(s.LeaderPL.FedServers), len(ss.VMs))
+	}
+	dot := ss.DOT()
+	if !strings.HasPrefix(dot, "digraph consensus {") || !strings.Contains(dot, `"dbsig-done" [label="DBSigs done", style=filled`) ||
+		!strings.Contains(dot, `"processing" -> "eom-sync"`) || !strings.Contains(dot, `"vm0" [label="{vm 0|`) {
+		t.Errorf("Unexpected graph\n%s", dot)
+	}
+}
@@ -0,0 +1,262 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+	log "github.com/sirupsen/logrus"
+)
+
+// Streamed sync.  A node catching up asked for directory block states a few at a time,
+// and waited on the answer to each ask before making the next.  Now it asks one peer for
+// a range of heights with a SyncRequest, and the peer streams the blocks back in
+// SyncChunks, compressed on the wire, without being asked again.  Every chunk ends with a
+// resume token, the KeyMR of its last block; a stream that stalls is asked for again
+// from there, of whichever peer the request goes to.  The blocks of each chunk are
+// checked as they come: each must follow on from the block before it, and match any
+// checkpoint at its height.  Peers that don't stream, as older nodes don't, let their
+// streams stall, and once streams have stalled SyncStallsMax times in a row the node
+// asks block by block for a while.
+
+var syncLogger = packageLogger.WithFields(log.Fields{"subpack": "sync"})
+
+const (
+	SyncRangeMax       = 2000             // Most blocks asked for in one request
+	SyncChunkBlocks    = 10               // Most blocks in a chunk
+	SyncChunkBytes     = 1024 * 1024      // A chunk takes no more blocks once this big
+	SyncStreamsMax     = 4                // Most streams served at once
+	SyncStallTimeout   = 20 * time.Second // A stream no chunk comes in on for this long is given up
+	SyncStallsMax      = 3                // Streams given up in a row before asking block by block
+	SyncFallbackPeriod = 5 * time.Minute  // How long to ask block by block for, once streams stall
+
+	syncFeedInterval  = 100 * time.Millisecond // How often the streams served are fed
+	syncChunksPerFeed = 4                      // Most chunks sent on a stream each time
+	syncOutQueueHigh  = 100                    // No chunks are sent while the out queue is longer
+)
+
+// syncStream is a stream of blocks this node is sending to a peer
+type syncStream struct {
+	origin        int
+	networkOrigin string
+	next          uint32 // Height of the next block to send
+	end           uint32 // Height of the last block to send
+}
+
+// syncFollow is the stream feeding this node as it catches up
+type syncFollow struct {
+	streaming     bool
+	next          uint32           // Height of the block expected next
+	end           uint32           // Height of the last block asked for
+	token         interfaces.IHash // KeyMR of the block before next, nil if not known
+	lastHeard     int64            // Milliseconds at the request, or the last chunk applied
+	stalls        int              // Streams given up in a row
+	fallbackUntil int64            // Milliseconds until which to ask block by block
+}
+
+// active returns true while a stream is feeding this node, giving it up once it stalls
+func (f *syncFollow) active(now int64) bool {
+	if !f.streaming {
+		return false
+	}
+	if now-f.lastHeard < int64(SyncStallTimeout/time.Millisecond) {
+		return true
+	}
+	f.streaming = false
+	f.stalls++
+	SyncChunksVec.WithLabelValues("stalled").Inc()
+	if f.stalls >= SyncStallsMax {
+		f.stalls = 0
+		f.fallbackUntil = now + int64(SyncFallbackPeriod/time.Millisecond)
+		syncLogger.Warnf("Block streams stalled %d times in a row, asking block by block for %v", SyncStallsMax, SyncFallbackPeriod)
+	}
+	return false
+}
+
+// newSyncRequest returns a request for a stream of the blocks from begin up to end, and
+// starts following it.  Returns nil while the node is asking block by block instead.
+func (s *State) newSyncRequest(begin, end uint32, now int64) *messages.SyncRequest {
+	f := &s.syncFollow
+	if now < f.fallbackUntil {
+		SyncRequestsVec.WithLabelValues("fallback").Inc()
+		return nil
+	}
+	if end < begin {
+		end = begin
+	}
+	if end-begin >= SyncRangeMax {
+		end = begin + SyncRangeMax - 1
+	}
+	var token interfaces.IHash
+	if begin > 0 {
+		if d := s.GetDirectoryBlockByHeight(begin - 1); d != nil {
+			token = d.GetKeyMR()
+		}
+	}
+	f.streaming, f.next, f.end, f.token, f.lastHeard = true, begin, end, token, now
+	SyncRequestsVec.WithLabelValues("sent").Inc()
+	return messages.NewSyncRequest(s, begin, end, token)
+}
+
+// FollowerExecuteSyncRequest starts a stream of the blocks a peer asked for, if this node
+// has them and isn't serving too many streams already
+func (s *State) FollowerExecuteSyncRequest(msg interfaces.IMsg) {
+	req, ok := msg.(*messages.SyncRequest)
+	if !ok {
+		return
+	}
+	start, end := req.DBHeightStart, req.DBHeightEnd
+	if end-start >= SyncRangeMax {
+		end = start + SyncRangeMax - 1
+	}
+	if highest := s.GetHighestSavedBlk(); end > highest {
+		end = highest
+	}
+	if start > end {
+		SyncRequestsVec.WithLabelValues("refused").Inc()
+		return
+	}
+	// The stream has to follow on from the block the peer has before the start
+	if token := req.GetResumeToken(); start > 0 && !token.IsZero() {
+		d := s.GetDirectoryBlockByHeight(start - 1)
+		if d == nil || !d.GetKeyMR().IsSameAs(token) {
+			SyncRequestsVec.WithLabelValues("refused").Inc()
+			return
+		}
+	}
+
+	// A peer asking again starts its stream over
+	streams := s.syncServing[:0]
+	for _, st := range s.syncServing {
+		if st.networkOrigin != req.GetNetworkOrigin() {
+			streams = append(streams, st)
+		}
+	}
+	s.syncServing = streams
+	if len(s.syncServing) >= SyncStreamsMax {
+		SyncRequestsVec.WithLabelValues("refused").Inc()
+		return
+	}
+	s.syncServing = append(s.syncServing, &syncStream{
+		origin:        req.GetOrigin(),
+		networkOrigin: req.GetNetworkOrigin(),
+		next:          start,
+		end:           end,
+	})
+	SyncRequestsVec.WithLabelValues("served").Inc()
+}
+
+// feedSyncStreams sends the next chunks of the streams served, while the out queue has
+// room for them
+func (s *State) feedSyncStreams() error {
+	var kept []*syncStream
+	for _, st := range s.syncServing {
+		done := false
+		for i := 0; i < syncChunksPerFeed && !done; i++ {
+			if s.NetworkOutMsgQueue().Length() > syncOutQueueHigh {
+				break
+			}
+			chunk, err := s.nextSyncChunk(st)
+			if err != nil {
+				syncLogger.WithField("peer", st.networkOrigin).Errorf("Stream ended at %d: %v", st.next, err)
+				done = true
+				break
+			}
+			chunk.SendOut(s, chunk)
+			SyncChunksVec.WithLabelValues("sent").Inc()
+			done = chunk.Final
+		}
+		if !done {
+			kept = append(kept, st)
+		}
+	}
+	s.syncServing = kept
+	return nil
+}
+
+// nextSyncChunk loads the next blocks of a stream into a chunk
+func (s *State) nextSyncChunk(st *syncStream) (*messages.SyncChunk, error) {
+	chunk := messages.NewSyncChunk(s, st.next)
+	size := 0
+	for st.next <= st.end && len(chunk.DBStates) < SyncChunkBlocks && size < SyncChunkBytes {
+		msg, err := s.LoadDBState(st.next)
+		if err != nil {
+			return nil, err
+		}
+		dbs, ok := msg.(*messages.DBStateMsg)
+		if !ok || dbs == nil {
+			return nil, fmt.Errorf("no directory block state at %d", st.next)
+		}
+		data, err := dbs.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		size += len(data)
+		chunk.DBStates = append(chunk.DBStates, dbs)
+		st.next++
+	}
+	if len(chunk.DBStates) == 0 {
+		return nil, fmt.Errorf("nothing left to send")
+	}
+	chunk.ResumeToken = chunk.DBStates[len(chunk.DBStates)-1].DirectoryBlock.GetKeyMR()
+	chunk.Final = st.next > st.end
+	chunk.SetOrigin(st.origin)
+	chunk.SetNetworkOrigin(st.networkOrigin)
+	return chunk, nil
+}
+
+// FollowerExecuteSyncChunk checks the blocks of a chunk of the stream feeding this node
+// follow on from those before them, and applies them as directory block states.  A
+// chunk that doesn't check out ends the stream, and the next ask goes out again.
+func (s *State) FollowerExecuteSyncChunk(msg interfaces.IMsg) {
+	chunk, ok := msg.(*messages.SyncChunk)
+	if !ok {
+		return
+	}
+	f := &s.syncFollow
+	// Blocks past the one expected next can't be checked against those before them
+	if !f.streaming || chunk.DBHeightStart > f.next {
+		SyncChunksVec.WithLabelValues("ignored").Inc()
+		return
+	}
+	for _, dbs := range chunk.DBStates {
+		height := dbs.DirectoryBlock.GetDatabaseHeight()
+		if height < f.next {
+			continue
+		}
+		if err := f.check(s, dbs); err != nil {
+			syncLogger.WithField("peer", chunk.GetNetworkOrigin()).Warnf("Refused a chunk of streamed blocks: %v", err)
+			SyncChunksVec.WithLabelValues("refused").Inc()
+			f.streaming = false
+			return
+		}
+		dbs.SetOrigin(chunk.GetOrigin())
+		dbs.SetNetworkOrigin(chunk.GetNetworkOrigin())
+		s.executeMsg(nil, dbs)
+		f.next, f.token = height+1, dbs.DirectoryBlock.GetKeyMR()
+	}
+	f.lastHeard = s.GetTimestamp().GetTimeMilli()
+	f.stalls = 0
+	if chunk.Final || f.next > f.end {
+		f.streaming = false
+	}
+	SyncChunksVec.WithLabelValues("applied").Inc()
+}
+
+// check returns an error if the block isn't the one expected next, doesn't follow on
+// from the block before it, or doesn't match the checkpoint at its height
+func (f *syncFollow) check(s *State, dbs *messages.DBStateMsg) error {
+	height := dbs.DirectoryBlock.GetDatabaseHeight()
+	if height != f.next {
+		return fmt.Errorf("block %d where %d was expected", height, f.next)
+	}
+	if f.token != nil && !dbs.DirectoryBlock.GetHeader().GetPrevKeyMR().IsSameAs(f.token) {
+		return fmt.Errorf("block %d doesn't follow on from %s", height, f.token.String())
+	}
+	return CheckDBKeyMR(s, height, dbs.DirectoryBlock.GetKeyMR().String())
+}
@@ -0,0 +1,231 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var timingLogger = packageLogger.WithFields(log.Fields{"subpack": "timing-anomalies"})
+
+// The kinds of timing anomaly
+const (
+	TimingShortMinute        = "short-minute"
+	TimingLongBlock          = "long-block"
+	TimingTimestampBackwards = "timestamp-backwards"
+)
+
+// How many anomalies are kept
+const MaxTimingAnomalies = 1000
+
+// Where the anomalies are kept in the database
+var (
+	TIMING_ANOMALIES        = []byte("TimingAnomalies")
+	TIMING_ANOMALIES_REPORT = []byte("Report")
+)
+
+// TimingAnomalyTracker collects minutes that ended too soon, as this node saw them, and
+// blocks whose header timestamps are too far apart.  On mainnet, with 10 minute blocks,
+// that is minutes shorter than 30 seconds and blocks longer than 15 minutes.  The
+// anomalies are saved in the database, so a node keeps its history over restarts.
+type TimingAnomalyTracker struct {
+	mutex  sync.Mutex
+	report interfaces.TimingAnomalyReport
+	dirty  bool // Changed since last saved
+}
+
+func NewTimingAnomalyTracker() *TimingAnomalyTracker {
+	return new(TimingAnomalyTracker)
+}
+
+// ShortestMinute is the shortest a minute should take with blocks of blockTime
+func ShortestMinute(blockTime time.Duration) time.Duration {
+	return blockTime / 20
+}
+
+// LongestBlock is the longest a block should take with blocks of blockTime
+func LongestBlock(blockTime time.Duration) time.Duration {
+	return blockTime * 3 / 2
+}
+
+func (t *TimingAnomalyTracker) add(a interfaces.TimingAnomaly) {
+	t.report.Anomalies = append(t.report.Anomalies, a)
+	if len(t.report.Anomalies) > MaxTimingAnomalies {
+		t.report.Anomalies = append([]interfaces.TimingAnomaly{}, t.report.Anomalies[len(t.report.Anomalies)-MaxTimingAnomalies:]...)
+	}
+	t.dirty = true
+	TimingAnomaliesVec.WithLabelValues(a.Kind).Inc()
+	timingLogger.WithFields(log.Fields{"kind": a.Kind, "dbheight": a.DBHeight, "minute": a.Minute, "duration": a.Duration}).Warn("Block timing anomaly")
+}
+
+// MinuteEnded checks a minute this node saw end after duration
+func (t *TimingAnomalyTracker) MinuteEnded(dbheight uint32, minute int, duration time.Duration, blockTime time.Duration) *interfaces.TimingAnomaly {
+	if t == nil {
+		return nil
+	}
+	limit := ShortestMinute(blockTime)
+	if duration >= limit {
+		return nil
+	}
+	a := interfaces.TimingAnomaly{
+		Kind:      TimingShortMinute,
+		Source:    "local",
+		DBHeight:  dbheight,
+		Minute:    minute,
+		Duration:  int64(duration / time.Millisecond),
+		Limit:     int64(limit / time.Millisecond),
+		Timestamp: time.Now().Unix(),
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.add(a)
+	return &a
+}
+
+// BlockSaved checks the header timestamp of the block at dbheight against the block
+// before it.  Timestamps are in milliseconds.  Blocks must be checked in order.
+func (t *TimingAnomalyTracker) BlockSaved(dbheight uint32, prev, timestamp int64, blockTime time.Duration) *interfaces.TimingAnomaly {
+	if t == nil {
+		return nil
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if dbheight <= t.report.ScannedHeight && t.report.ScannedHeight > 0 {
+		return nil
+	}
+	t.report.ScannedHeight = dbheight
+	t.dirty = true
+	if dbheight == 0 {
+		return nil
+	}
+
+	gap := time.Duration(timestamp-prev) * time.Millisecond
+	a := interfaces.TimingAnomaly{
+		Source:    "header",
+		DBHeight:  dbheight,
+		Duration:  int64(gap / time.Millisecond),
+		Timestamp: timestamp / 1000,
+	}
+	switch limit := LongestBlock(blockTime); {
+	case gap < 0:
+		a.Kind = TimingTimestampBackwards
+	case gap > limit:
+		a.Kind = TimingLongBlock
+		a.Limit = int64(limit / time.Millisecond)
+	default:
+		return nil
+	}
+	t.add(a)
+	return &a
+}
+
+// Report returns the anomalies found, oldest first
+func (t *TimingAnomalyTracker) Report() interfaces.TimingAnomalyReport {
+	if t == nil {
+		return interfaces.TimingAnomalyReport{}
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	r := t.report
+	r.Anomalies = append([]interfaces.TimingAnomaly{}, t.report.Anomalies...)
+	return r
+}
+
+// MarshalIfChanged returns the report to save, or nil if it hasn't changed since the last call
+func (t *TimingAnomalyTracker) MarshalIfChanged() ([]byte, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if !t.dirty {
+		return nil, nil
+	}
+	t.dirty = false
+	return json.Marshal(t.report)
+}
+
+// Unmarshal restores a saved report
+func (t *TimingAnomalyTracker) Unmarshal(data []byte) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return json.Unmarshal(data, &t.report)
+}
+
+// CheckBlockTiming checks the header timestamps of the blocks saved since the last call,
+// and saves the anomalies found so far
+func (s *State) CheckBlockTiming() {
+	t := s.TimingAnomalies
+	if t == nil {
+		return
+	}
+	blockTime := time.Duration(s.DirectoryBlockInSeconds) * time.Second
+	highest := s.GetHighestSavedBlk()
+	from := t.Report().ScannedHeight
+	var prev int64
+	for h := from; h <= highest; h++ {
+		dblock, err := s.DB.FetchDBlockByHeight(h)
+		if err != nil || dblock == nil {
+			break
+		}
+		ts := dblock.GetHeader().GetTimestamp().GetTimeMilli()
+		if h > from || h == 0 {
+			t.BlockSaved(h, prev, ts, blockTime)
+		}
+		prev = ts
+	}
+	s.saveTimingAnomalies()
+}
+
+func (s *State) saveTimingAnomalies() {
+	overlay, ok := s.DB.(*databaseOverlay.Overlay)
+	if !ok {
+		return
+	}
+	data, err := s.TimingAnomalies.MarshalIfChanged()
+	if err != nil || data == nil {
+		return
+	}
+	if err := overlay.Put(TIMING_ANOMALIES, TIMING_ANOMALIES_REPORT, &primitives.ByteSlice{Bytes: data}); err != nil {
+		timingLogger.Errorf("Cannot save the timing anomalies: %v", err)
+	}
+}
+
+func (s *State) loadTimingAnomalies() {
+	overlay, ok := s.DB.(*databaseOverlay.Overlay)
+	if !ok {
+		return
+	}
+	saved, err := overlay.Get(TIMING_ANOMALIES, TIMING_ANOMALIES_REPORT, new(primitives.ByteSlice))
+	if err != nil || saved == nil {
+		return
+	}
+	if err := s.TimingAnomalies.Unmarshal(saved.(*primitives.ByteSlice).Bytes); err != nil {
+		timingLogger.Errorf("Ignoring the saved timing anomalies: %v", err)
+	}
+}
+
+// GoCheckBlockTiming restores the anomalies saved before, then keeps checking the blocks
+// as they are saved
+func (s *State) GoCheckBlockTiming() {
+	for !s.DBFinished {
+		time.Sleep(time.Second)
+	}
+	s.loadTimingAnomalies()
+	for {
+		s.CheckBlockTiming()
+		time.Sleep(time.Minute)
+	}
+}
+
+// GetTimingAnomalies returns the minutes and blocks that took too little or too long
+func (s *State) GetTimingAnomalies() interfaces.TimingAnomalyReport {
+	return s.TimingAnomalies.Report()
+}
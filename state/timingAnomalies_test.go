@@ -0,0 +1,87 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/FactomProject/factomd/state"
+)
+
+func TestTimingAnomalyTracker(t *testing.T) {
+	tr := NewTimingAnomalyTracker()
+	block := 10 * time.Minute
+
+	// Minutes of a minute, or just over 30 seconds, are fine; 20 seconds isn't
+	if a := tr.MinuteEnded(5, 1, time.Minute, block); a != nil {
+		t.Errorf("Normal minute reported: %+v", a)
+	}
+	if a := tr.MinuteEnded(5, 2, 31*time.Second, block); a != nil {
+		t.Errorf("31 second minute reported: %+v", a)
+	}
+	a := tr.MinuteEnded(5, 3, 20*time.Second, block)
+	if a == nil || a.Kind != TimingShortMinute || a.Source != "local" || a.Minute != 3 || a.Duration != 20000 || a.Limit != 30000 {
+		t.Errorf("Unexpected short minute %+v", a)
+	}
+
+	// Blocks of 10 and 15 minutes are fine, 16 isn't, and neither is going backwards
+	ms := int64(time.Minute / time.Millisecond)
+	ts := int64(1500000000000)
+	tr.BlockSaved(0, 0, ts, block)
+	if a := tr.BlockSaved(1, ts, ts+10*ms, block); a != nil {
+		t.Errorf("Normal block reported: %+v", a)
+	}
+	if a := tr.BlockSaved(2, ts+10*ms, ts+25*ms, block); a != nil {
+		t.Errorf("15 minute block reported: %+v", a)
+	}
+	a = tr.BlockSaved(3, ts+25*ms, ts+41*ms, block)
+	if a == nil || a.Kind != TimingLongBlock || a.Source != "header" || a.DBHeight != 3 || a.Duration != 16*ms || a.Limit != 15*ms {
+		t.Errorf("Unexpected long block %+v", a)
+	}
+	a = tr.BlockSaved(4, ts+41*ms, ts+40*ms, block)
+	if a == nil || a.Kind != TimingTimestampBackwards {
+		t.Errorf("Unexpected backwards block %+v", a)
+	}
+
+	// Blocks already checked are skipped
+	if a := tr.BlockSaved(3, ts, ts+60*ms, block); a != nil {
+		t.Errorf("Block checked twice: %+v", a)
+	}
+
+	r := tr.Report()
+	if r.ScannedHeight != 4 || len(r.Anomalies) != 3 {
+		t.Fatalf("Unexpected report %+v", r)
+	}
+
+	// The report survives being saved and restored
+	data, err := tr.MarshalIfChanged()
+	if err != nil || data == nil {
+		t.Fatalf("Nothing to save: %v", err)
+	}
+	if data, _ := tr.MarshalIfChanged(); data != nil {
+		t.Error("Unchanged report saved again")
+	}
+	restored := NewTimingAnomalyTracker()
+	if err := restored.Unmarshal(data); err != nil {
+		t.Fatal(err)
+	}
+	if rr := restored.Report(); rr.ScannedHeight != 4 || len(rr.Anomalies) != 3 || rr.Anomalies[1].Kind != TimingLongBlock {
+		t.Errorf("Unexpected restored report %+v", rr)
+	}
+
+	// Only the most recent anomalies are kept
+	for i := 0; i < MaxTimingAnomalies+10; i++ {
+		tr.MinuteEnded(6, i, time.Second, block)
+	}
+	if r := tr.Report(); len(r.Anomalies) != MaxTimingAnomalies || r.Anomalies[MaxTimingAnomalies-1].Minute != MaxTimingAnomalies+9 {
+		t.Errorf("Expected the last %d anomalies, have %d", MaxTimingAnomalies, len(r.Anomalies))
+	}
+
+	var none *TimingAnomalyTracker
+	if none.MinuteEnded(1, 1, 0, block) != nil || len(none.Report().Anomalies) != 0 {
+		t.Error("Nil tracker reported anomalies")
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/FactomProject/factomd/common/entryBlock"
+	"github.com/FactomProject/factomd/common/entryCreditBlock"
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/util"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var beaconLogger = packageLogger.WithFields(log.Fields{"subpack": "uptime-beacon"})
+
+// The first ExtID of every uptime beacon entry
+const UptimeBeaconTag = "factomd-uptime-beacon"
+
+// Beacons are rate limited so a misconfigured node can't burn through its entry credits
+const MinUptimeBeaconInterval = 600 // seconds
+
+// UptimeBeacon is the content of a beacon entry.  The entry's ExtIDs are the beacon tag,
+// the identity chain, the server public key, and the server's signature of the content,
+// so anyone can verify the beacon against the identity without trusting this node.
+type UptimeBeacon struct {
+	IdentityChainID string `json:"identitychainid"`
+	NodeName        string `json:"nodename"`
+	DBHeight        uint32 `json:"dbheight"`
+	Timestamp       int64  `json:"timestamp"`
+	Version         string `json:"version"`
+}
+
+// GoUptimeBeacon periodically writes a signed heartbeat entry to the configured beacon
+// chain.  Does nothing unless both a chain and an entry credit key are configured.
+func (s *State) GoUptimeBeacon() {
+	if s.UptimeBeaconChainID == "" || s.UptimeBeaconECPrivateKey == "" {
+		return
+	}
+	interval := s.UptimeBeaconInterval
+	if interval < MinUptimeBeaconInterval {
+		interval = MinUptimeBeaconInterval
+	}
+
+	for {
+		time.Sleep(time.Duration(interval) * time.Second)
+
+		// Only beat while we are actually following the network
+		if !s.IsInSync() || s.IsStalled() {
+			continue
+		}
+		commit, reveal, err := s.NewUptimeBeacon()
+		if err != nil {
+			beaconLogger.Errorf("Cannot build uptime beacon: %v", err)
+			continue
+		}
+		s.APIQueue().Enqueue(commit)
+		s.APIQueue().Enqueue(reveal)
+		atomic.AddInt64(&s.uptimeBeaconsSent, 1)
+	}
+}
+
+// GetUptimeBeaconsSent returns how many beacons have been queued since the node started
+func (s *State) GetUptimeBeaconsSent() int64 {
+	return atomic.LoadInt64(&s.uptimeBeaconsSent)
+}
+
+// NewUptimeBeacon builds the commit and reveal messages for a single beacon entry
+func (s *State) NewUptimeBeacon() (*messages.CommitEntryMsg, *messages.RevealEntryMsg, error) {
+	chainID, err := primitives.HexToHash(s.UptimeBeaconChainID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Invalid uptime beacon chain id: %v", err)
+	}
+	ecKey, err := primitives.HumanReadableECPrivateKeyToPrivateKey(s.UptimeBeaconECPrivateKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Invalid uptime beacon entry credit key: %v", err)
+	}
+	if s.serverPrivKey == nil || s.IdentityChainID == nil {
+		return nil, nil, fmt.Errorf("No server identity to sign the uptime beacon with")
+	}
+
+	beacon := new(UptimeBeacon)
+	beacon.IdentityChainID = s.IdentityChainID.String()
+	beacon.NodeName = s.FactomNodeName
+	beacon.DBHeight = s.LLeaderHeight
+	beacon.Timestamp = time.Now().Unix()
+	beacon.Version = s.GetFactomdVersion()
+	content, err := json.Marshal(beacon)
+	if err != nil {
+		return nil, nil, err
+	}
+	sig := s.Sign(content)
+
+	entry := entryBlock.NewEntry()
+	entry.ChainID = chainID
+	entry.ExtIDs = append(entry.ExtIDs,
+		primitives.ByteSlice{Bytes: []byte(UptimeBeaconTag)},
+		primitives.ByteSlice{Bytes: s.IdentityChainID.Bytes()},
+		primitives.ByteSlice{Bytes: sig.GetKey()},
+		primitives.ByteSlice{Bytes: sig.Bytes()})
+	entry.Content = primitives.ByteSlice{Bytes: content}
+
+	data, err := entry.MarshalBinary()
+	if err != nil {
+		return nil, nil, err
+	}
+	cost, err := util.EntryCost(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	commit := entryCreditBlock.NewCommitEntry()
+	commit.Version = 0
+	var milli [8]byte
+	binary.BigEndian.PutUint64(milli[:], uint64(time.Now().UnixNano()/1e6))
+	if err := commit.MilliTime.UnmarshalBinary(milli[2:]); err != nil {
+		return nil, nil, err
+	}
+	commit.EntryHash = entry.GetHash()
+	commit.Credits = cost
+	if err := commit.Sign(ecKey); err != nil {
+		return nil, nil, err
+	}
+
+	commitMsg := messages.NewCommitEntryMsg()
+	commitMsg.CommitEntry = commit
+
+	reveal := messages.NewRevealEntryMsg()
+	reveal.Entry = entry
+	reveal.Timestamp = s.GetTimestamp()
+	return commitMsg, reveal, nil
+}
@@ -0,0 +1,73 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/FactomProject/factomd/common/primitives"
+	. "github.com/FactomProject/factomd/state"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestNewUptimeBeacon(t *testing.T) {
+	s := testHelper.CreateEmptyTestState()
+
+	ecKey, err := primitives.PrivateKeyStringToHumanReadableECPrivateKey("0000000000000000000000000000000000000000000000000000000000000001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.UptimeBeaconChainID = "zz"
+	s.UptimeBeaconECPrivateKey = ecKey
+	if _, _, err := s.NewUptimeBeacon(); err == nil {
+		t.Error("Expected an error for an invalid chain id")
+	}
+
+	s.UptimeBeaconChainID = "888888ae4b6ca1e71ac1a1e5aa2d4a0b1bde4b1a4e84d14a7fb2af8c7cb3ad8b"
+	commit, reveal, err := s.NewUptimeBeacon()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entry := reveal.Entry
+	if entry.GetChainID().String() != s.UptimeBeaconChainID {
+		t.Errorf("Beacon written to the wrong chain %s", entry.GetChainID().String())
+	}
+	if !commit.CommitEntry.EntryHash.IsSameAs(entry.GetHash()) {
+		t.Error("Commit does not pay for the beacon entry")
+	}
+	if err := commit.CommitEntry.ValidateSignatures(); err != nil {
+		t.Errorf("Bad commit signature: %v", err)
+	}
+
+	extIDs := entry.ExternalIDs()
+	if len(extIDs) != 4 || string(extIDs[0]) != UptimeBeaconTag {
+		t.Fatalf("Unexpected beacon ExtIDs %x", extIDs)
+	}
+	if !bytes.Equal(extIDs[1], s.IdentityChainID.Bytes()) {
+		t.Error("Beacon does not name our identity")
+	}
+	if !bytes.Equal(extIDs[2], s.GetServerPublicKey()[:]) {
+		t.Error("Beacon is not signed with the server key")
+	}
+	sig := new(primitives.Signature)
+	sig.SetPub(extIDs[2])
+	if err := sig.SetSignature(extIDs[3]); err != nil {
+		t.Fatal(err)
+	}
+	if !sig.Verify(entry.GetContent()) {
+		t.Error("Beacon signature does not verify")
+	}
+
+	beacon := new(UptimeBeacon)
+	if err := json.Unmarshal(entry.GetContent(), beacon); err != nil {
+		t.Fatal(err)
+	}
+	if beacon.IdentityChainID != s.IdentityChainID.String() || beacon.DBHeight != s.LLeaderHeight {
+		t.Errorf("Unexpected beacon content %s", entry.GetContent())
+	}
+}
@@ -19,15 +19,16 @@ func (state *State) ValidatorLoop() {
 		// Check if we should shut down.
 		select {
 		case <-state.ShutdownChan:
-			fmt.Println("Closing the Database on", state.GetFactomNodeName())
-			state.DB.Close()
-			state.StateSaverStruct.StopSaving()
+			fmt.Println("Shutting down", state.GetFactomNodeName())
+			state.shutdown()
 			fmt.Println(state.GetFactomNodeName(), "closed")
 			state.IsRunning = false
 			return
 		default:
 		}
 
+		state.Jobs.RunDue()
+
 		// Look for pending messages, and get one if there is one.
 		var msg interfaces.IMsg
 	loop:
@@ -40,6 +41,8 @@ func (state *State) ValidatorLoop() {
 				}
 				//fmt.Printf("dddd %20s %10s --- %10s %10v %10s %10v\n", "Validation", state.FactomNodeName, "Process", p, "Update", b)
 			}
+			// Send the acks held back while processing before waiting on more messages
+			state.flushAckBatch()
 
 			for i := 0; i < 10; i++ {
 				select {
@@ -59,6 +62,12 @@ func (state *State) ValidatorLoop() {
 				if msg != nil {
 					state.JournalMessage(msg)
 					break loop
+				} else if state.Idle() {
+					// Idle followers wait on the queues, rather than polling them
+					if msg = state.idleWait(timeStruct); msg != nil {
+						state.JournalMessage(msg)
+						break loop
+					}
 				} else {
 					// No messages? Sleep for a bit
 					for i := 0; i < 10 && state.InMsgQueue().Length() == 0; i++ {
@@ -73,9 +82,10 @@ func (state *State) ValidatorLoop() {
 			if state.IsReplaying == true {
 				state.ReplayTimestamp = msg.GetTimestamp()
 			}
-			if _, ok := msg.(*messages.Ack); ok {
+			switch msg.(type) {
+			case *messages.Ack, *messages.BatchAck:
 				state.ackQueue <- msg
-			} else {
+			default:
 				state.msgQueue <- msg
 			}
 		}
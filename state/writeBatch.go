@@ -0,0 +1,176 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var writeLogger = packageLogger.WithFields(log.Fields{"subpack": "write-batch"})
+
+// A batch is written early once it holds this many entries and entry blocks
+var MaxWriteBatch = 1000
+
+// WriteBatcher holds the entries and entry blocks of saved blocks that arrive from peers
+// until there are enough of them, or they are as old as a minute, so they reach the
+// database in one batch rather than one batch (and one fsync) each.  The entry sync writes
+// the batch, off the consensus loop.  The entries and entry blocks of the process list
+// don't come through here; they are written with their block when it is saved, so a
+// process list that is reset leaves nothing behind.
+type WriteBatcher struct {
+	mutex   sync.Mutex
+	entries []interfaces.IEBEntry
+	eblocks []interfaces.IEntryBlock
+	started time.Time
+
+	// Entries waiting, or being written, so they aren't asked for again
+	pending map[[32]byte]bool
+
+	flushing sync.Mutex // Held while a batch is written, so batches are written in order
+}
+
+func NewWriteBatcher() *WriteBatcher {
+	b := new(WriteBatcher)
+	b.pending = make(map[[32]byte]bool)
+	return b
+}
+
+func (b *WriteBatcher) AddEntry(entry interfaces.IEBEntry) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.pending[entry.GetHash().Fixed()] {
+		return
+	}
+	if b.size() == 0 {
+		b.started = time.Now()
+	}
+	b.pending[entry.GetHash().Fixed()] = true
+	b.entries = append(b.entries, entry)
+}
+
+func (b *WriteBatcher) AddEBlock(eblock interfaces.IEntryBlock) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.size() == 0 {
+		b.started = time.Now()
+	}
+	b.eblocks = append(b.eblocks, eblock)
+}
+
+// HasEntry is true if the entry is waiting to be written
+func (b *WriteBatcher) HasEntry(hash interfaces.IHash) bool {
+	if b == nil {
+		return false
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.pending[hash.Fixed()]
+}
+
+// size is the number of entries and entry blocks waiting.  The caller must hold the lock.
+func (b *WriteBatcher) size() int {
+	return len(b.entries) + len(b.eblocks)
+}
+
+// Due returns why the batch should be written now, or "" if it can wait for the end of
+// the minute
+func (b *WriteBatcher) Due(now time.Time, minute time.Duration) string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	switch {
+	case b.size() == 0:
+		return ""
+	case b.size() >= MaxWriteBatch:
+		return "full"
+	case now.Sub(b.started) >= minute:
+		return "age"
+	}
+	return ""
+}
+
+// take empties the batch, leaving its entries pending until done is called
+func (b *WriteBatcher) take() ([]interfaces.IEBEntry, []interfaces.IEntryBlock) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	entries, eblocks := b.entries, b.eblocks
+	b.entries, b.eblocks = nil, nil
+	return entries, eblocks
+}
+
+func (b *WriteBatcher) done(entries []interfaces.IEBEntry) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	for _, e := range entries {
+		delete(b.pending, e.GetHash().Fixed())
+	}
+}
+
+// FlushWrites writes the entries and entry blocks waiting in one batch.  The reason is
+// only for the metrics.
+func (s *State) FlushWrites(reason string) error {
+	b := s.Writes
+	if b == nil {
+		return nil
+	}
+	b.flushing.Lock()
+	defer b.flushing.Unlock()
+
+	entries, eblocks := b.take()
+	if len(entries) == 0 && len(eblocks) == 0 {
+		return nil
+	}
+	defer b.done(entries)
+
+	start := time.Now()
+	s.DB.StartMultiBatch()
+	for _, eb := range eblocks {
+		if err := s.DB.ProcessEBlockMultiBatch(eb, true); err != nil {
+			writeLogger.Errorf("Cannot write entry block: %v", err)
+		}
+	}
+	for _, e := range entries {
+		if err := s.DB.InsertEntryMultiBatch(e); err != nil {
+			writeLogger.Errorf("Cannot write entry: %v", err)
+		}
+	}
+	if err := s.DB.ExecuteMultiBatch(); err != nil {
+		writeLogger.Errorf("Cannot write batch of %d entries and %d entry blocks: %v", len(entries), len(eblocks), err)
+		// The entries are asked for again once they are no longer pending, but the entry
+		// blocks were taken off the missing list when they arrived
+		s.restoreMissingEBlocks(eblocks)
+		return err
+	}
+
+	WriteBatchFlushes.WithLabelValues(reason).Inc()
+	WriteBatchSize.Observe(float64(len(entries) + len(eblocks)))
+	WriteBatchDuration.Observe(float64(time.Since(start).Nanoseconds()))
+	s.LeaderHealth.DiskWrite(time.Since(start))
+	return nil
+}
+
+// restoreMissingEBlocks puts entry blocks that weren't written back on the missing list,
+// so they are asked for again
+func (s *State) restoreMissingEBlocks(eblocks []interfaces.IEntryBlock) {
+	s.MissingEntryBlocksMutex.Lock()
+	defer s.MissingEntryBlocksMutex.Unlock()
+
+	missing := make(map[[32]byte]bool, len(s.MissingEntryBlocks))
+	for _, m := range s.MissingEntryBlocks {
+		missing[m.EBHash.Fixed()] = true
+	}
+	for _, eb := range eblocks {
+		keyMR, err := eb.KeyMR()
+		if err != nil || missing[keyMR.Fixed()] {
+			continue
+		}
+		missing[keyMR.Fixed()] = true
+		s.MissingEntryBlocks = append(s.MissingEntryBlocks, MissingEntryBlock{EBHash: keyMR, DBHeight: eb.GetHeader().GetDBHeight()})
+	}
+}
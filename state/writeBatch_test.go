@@ -0,0 +1,65 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	. "github.com/FactomProject/factomd/state"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestWriteBatcher(t *testing.T) {
+	s := testHelper.CreateEmptyTestState()
+	b := s.Writes
+
+	e1 := testHelper.CreateTestEntry(1)
+	e2 := testHelper.CreateTestEntry(2)
+	b.AddEntry(e1)
+	b.AddEntry(e2)
+	b.AddEntry(e1)
+
+	// Waiting entries count as had, but aren't written yet
+	if !b.HasEntry(e1.GetHash()) || !b.HasEntry(e2.GetHash()) {
+		t.Error("Waiting entries not found")
+	}
+	if found, _ := s.DB.FetchEntry(e1.GetHash()); found != nil {
+		t.Error("Entry written before the batch was")
+	}
+	if r := b.Due(time.Now(), time.Minute); r != "" {
+		t.Errorf("New batch due: %s", r)
+	}
+	if r := b.Due(time.Now().Add(2*time.Minute), time.Minute); r != "age" {
+		t.Errorf("Old batch not due: %s", r)
+	}
+
+	if err := s.FlushWrites("age"); err != nil {
+		t.Fatal(err)
+	}
+	if b.HasEntry(e1.GetHash()) {
+		t.Error("Written entry still waiting")
+	}
+	for _, e := range []interfaces.IEBEntry{e1, e2} {
+		found, err := s.DB.FetchEntry(e.GetHash())
+		if err != nil || found == nil {
+			t.Errorf("Entry %s not written: %v", e.GetHash(), err)
+		}
+	}
+	if r := b.Due(time.Now().Add(2*time.Minute), time.Minute); r != "" {
+		t.Errorf("Empty batch due: %s", r)
+	}
+
+	// A full batch is due at once
+	old := MaxWriteBatch
+	MaxWriteBatch = 2
+	defer func() { MaxWriteBatch = old }()
+	b.AddEntry(testHelper.CreateTestEntry(3))
+	b.AddEntry(testHelper.CreateTestEntry(4))
+	if r := b.Due(time.Now(), time.Minute); r != "full" {
+		t.Errorf("Full batch not due: %s", r)
+	}
+}
@@ -0,0 +1,311 @@
+package testHelper
+
+//Fixtures of every message and block type, for checking the serialization formats
+
+import (
+	"reflect"
+	"unicode"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/common/serialization"
+)
+
+// CreateTestFormatCorpus returns a fixture of every message type and every block type
+func CreateTestFormatCorpus() []serialization.Fixture {
+	var fixtures []serialization.Fixture
+	add := func(name string, kind serialization.Kind, obj interfaces.BinaryMarshallable) {
+		fixtures = append(fixtures, serialization.Fixture{Name: name, Kind: kind, Object: obj})
+	}
+
+	set := CreateTestBlockSet(nil)
+	set = CreateTestBlockSet(set)
+	add("test", serialization.KindDirectoryBlock, set.DBlock)
+	add("test", serialization.KindAdminBlock, set.ABlock)
+	add("test", serialization.KindEntryCreditBlock, set.ECBlock)
+	add("test", serialization.KindFactoidBlock, set.FBlock)
+	add("test", serialization.KindEntryBlock, set.EBlock)
+	add("anchor", serialization.KindEntryBlock, set.AnchorEBlock)
+	add("first", serialization.KindEntry, CreateFirstTestEntry())
+	add("test", serialization.KindEntry, CreateTestEntry(7))
+
+	for _, msg := range CreateTestMessages(set) {
+		add(messageFixtureName(msg), serialization.KindMessage, msg)
+	}
+	return fixtures
+}
+
+// messageFixtureName is the message's type name in lower case, i.e. db-state-msg for
+// DBStateMsg
+func messageFixtureName(msg interfaces.IMsg) string {
+	name := reflect.TypeOf(msg).Elem().Name()
+	var out []rune
+	for i, c := range name {
+		if unicode.IsUpper(c) && i > 0 {
+			prev, next := rune(name[i-1]), rune(0)
+			if i+1 < len(name) {
+				next = rune(name[i+1])
+			}
+			if unicode.IsLower(prev) || unicode.IsLower(next) {
+				out = append(out, '-')
+			}
+		}
+		out = append(out, unicode.ToLower(c))
+	}
+	return string(out)
+}
+
+// CreateTestMessages returns a signed message of every type, about the given blocks
+func CreateTestMessages(set *BlockSet) []interfaces.IMsg {
+	key := NewPrimitivesPrivateKey(1)
+	ts := primitives.NewTimestampFromMilliseconds(1500000000000)
+	hash := NewRepeatingHash(0xAB)
+	chainID := NewRepeatingHash(0xCD)
+
+	var msgs []interfaces.IMsg
+
+	eom := new(messages.EOM)
+	eom.Timestamp = ts
+	eom.Minute = 3
+	eom.ChainID = chainID
+	eom.DBHeight = uint32(set.Height)
+	msgs = append(msgs, eom)
+
+	ack := new(messages.Ack)
+	ack.Timestamp = ts
+	ack.MessageHash = hash
+	ack.DBHeight = uint32(set.Height)
+	ack.Height = 4
+	ack.SerialHash = NewRepeatingHash(0x11)
+	ack.LeaderChainID = chainID
+	msgs = append(msgs, ack)
+
+	sf := new(messages.ServerFault)
+	sf.Timestamp = ts
+	sf.ServerID = chainID
+	sf.AuditServerID = NewRepeatingHash(0xEF)
+	sf.DBHeight = uint32(set.Height)
+	sf.Height = 4
+	msgs = append(msgs, sf)
+
+	asf := new(messages.AuditServerFault)
+	asf.Timestamp = ts
+	msgs = append(msgs, asf)
+
+	fsf := new(messages.FullServerFault)
+	fsf.Timestamp = ts
+	fsf.ServerID = chainID
+	fsf.AuditServerID = NewRepeatingHash(0xEF)
+	fsf.DBHeight = uint32(set.Height)
+	fsf.Height = 4
+	fsf.SSerialHash = NewRepeatingHash(0x22)
+	msgs = append(msgs, fsf)
+
+	cc := new(messages.CommitChainMsg)
+	cc.CommitChain = NewCommitChain(set.EBlock)
+	msgs = append(msgs, cc)
+
+	ce := new(messages.CommitEntryMsg)
+	ce.CommitEntry = NewCommitEntry(set.EBlock)
+	msgs = append(msgs, ce)
+
+	dbs := new(messages.DirectoryBlockSignature)
+	dbs.Timestamp = ts
+	dbs.DBHeight = uint32(set.Height)
+	dbs.DirectoryBlockHeader = set.DBlock.GetHeader()
+	dbs.ServerIdentityChainID = chainID
+	dbs.SysHash = NewRepeatingHash(0x33)
+	msgs = append(msgs, dbs)
+
+	eomt := new(messages.EOMTimeout)
+	eomt.Timestamp = ts
+	msgs = append(msgs, eomt)
+
+	ft := new(messages.FactoidTransaction)
+	ft.Transaction = set.FBlock.GetTransactions()[1]
+	msgs = append(msgs, ft)
+
+	hb := new(messages.Heartbeat)
+	hb.Timestamp = ts
+	hb.DBlockHash = hash
+	hb.IdentityChainID = chainID
+	msgs = append(msgs, hb)
+
+	idb := new(messages.InvalidDirectoryBlock)
+	idb.Timestamp = ts
+	msgs = append(msgs, idb)
+
+	re := new(messages.RevealEntryMsg)
+	re.Timestamp = ts
+	re.Entry = set.Entries[0]
+	msgs = append(msgs, re)
+
+	rb := new(messages.RequestBlock)
+	rb.Timestamp = ts
+	msgs = append(msgs, rb)
+
+	st := new(messages.SignatureTimeout)
+	st.Timestamp = ts
+	msgs = append(msgs, st)
+
+	mm := new(messages.MissingMsg)
+	mm.Timestamp = ts
+	mm.Asking = chainID
+	mm.DBHeight = uint32(set.Height)
+	mm.ProcessListHeight = []uint32{1, 2}
+	msgs = append(msgs, mm)
+
+	md := new(messages.MissingData)
+	md.Timestamp = ts
+	md.RequestHash = hash
+	msgs = append(msgs, md)
+
+	dr := new(messages.DataResponse)
+	dr.Timestamp = ts
+	dr.DataType = 0
+	dr.DataObject = set.Entries[0]
+	dr.DataHash = set.Entries[0].GetHash()
+	msgs = append(msgs, dr)
+
+	mmr := new(messages.MissingMsgResponse)
+	mmr.Timestamp = ts
+	mmr.AckResponse = ack
+	mmr.MsgResponse = eom
+	msgs = append(msgs, mmr)
+
+	dbstate := new(messages.DBStateMsg)
+	dbstate.Timestamp = ts
+	dbstate.DirectoryBlock = set.DBlock
+	dbstate.AdminBlock = set.ABlock
+	dbstate.FactoidBlock = set.FBlock
+	dbstate.EntryCreditBlock = set.ECBlock
+	dbstate.EBlocks = []interfaces.IEntryBlock{set.EBlock, set.AnchorEBlock}
+	for _, e := range set.Entries {
+		dbstate.Entries = append(dbstate.Entries, e)
+	}
+	msgs = append(msgs, dbstate)
+
+	dbsm := new(messages.DBStateMissing)
+	dbsm.Timestamp = ts
+	dbsm.DBHeightStart = 1
+	dbsm.DBHeightEnd = 5
+	msgs = append(msgs, dbsm)
+
+	as := new(messages.AddServerMsg)
+	as.Timestamp = ts
+	as.ServerChainID = chainID
+	msgs = append(msgs, as)
+
+	csk := new(messages.ChangeServerKeyMsg)
+	csk.Timestamp = ts
+	csk.IdentityChainID = chainID
+	csk.AdminBlockChange = constants.TYPE_ADD_MATRYOSHKA
+	csk.Key = NewRepeatingHash(0x44)
+	msgs = append(msgs, csk)
+
+	rs := new(messages.RemoveServerMsg)
+	rs.Timestamp = ts
+	rs.ServerChainID = chainID
+	rs.ServerType = 1
+	msgs = append(msgs, rs)
+
+	b := new(messages.Bounce)
+	b.Timestamp = ts
+	b.Name = "bounce"
+	b.Number = 3
+	b.Stamps = []interfaces.Timestamp{ts}
+	b.Data = []byte("data")
+	msgs = append(msgs, b)
+
+	br := new(messages.BounceReply)
+	br.Timestamp = ts
+	br.Name = "bounce"
+	br.Number = 3
+	br.Stamps = []interfaces.Timestamp{ts}
+	msgs = append(msgs, br)
+
+	meb := new(messages.MissingEntryBlocks)
+	meb.Timestamp = ts
+	meb.DBHeightStart = 1
+	meb.DBHeightEnd = 5
+	msgs = append(msgs, meb)
+
+	ebr := new(messages.EntryBlockResponse)
+	ebr.Timestamp = ts
+	ebr.EBlocks = []interfaces.IEntryBlock{set.EBlock}
+	ebr.EBlockCount = 1
+	// An entry runs to the end of the data it is read from, so only one can be read back
+	ebr.Entries = []interfaces.IEBEntry{set.Entries[0]}
+	ebr.EntryCount = 1
+	msgs = append(msgs, ebr)
+
+	next := new(messages.Ack)
+	next.Timestamp = ts
+	next.MessageHash = NewRepeatingHash(0xAC)
+	next.DBHeight = uint32(set.Height)
+	next.Height = 5
+	next.SerialHash = NewRepeatingHash(0x12)
+	next.LeaderChainID = chainID
+	ba, err := messages.NewBatchAck(ts, []*messages.Ack{ack, next})
+	if err != nil {
+		panic(err)
+	}
+	msgs = append(msgs, ba)
+
+	th := new(messages.Throttle)
+	th.Timestamp = ts
+	th.Level = messages.ThrottleBusy
+	th.QueueDepth = 101
+	th.RetryAfter = 5
+	msgs = append(msgs, th)
+
+	sd := new(messages.StepDown)
+	sd.Timestamp = ts
+	sd.DBHeight = 5
+	sd.Minute = 3
+	sd.VM = 1
+	sd.IdentityChainID = chainID
+	sd.Reasons = messages.StepDownLag | messages.StepDownDisk
+	msgs = append(msgs, sd)
+
+	core := messages.ElectionCore{
+		ServerID:      chainID,
+		AuditServerID: NewRepeatingHash(0xEF),
+		VMIndex:       1,
+		DBHeight:      5,
+		Height:        4,
+		Timestamp:     ts,
+	}
+	ev := messages.NewElectionVolunteer(core, 2)
+	msgs = append(msgs, ev)
+	msgs = append(msgs, messages.NewElectionAccept(ev))
+
+	sr := new(messages.SyncRequest)
+	sr.Timestamp = ts
+	sr.DBHeightStart = 1
+	sr.DBHeightEnd = 5
+	sr.ResumeToken = NewRepeatingHash(0x55)
+	msgs = append(msgs, sr)
+
+	sc := new(messages.SyncChunk)
+	sc.Timestamp = ts
+	sc.DBHeightStart = uint32(set.Height)
+	sc.Final = true
+	sc.DBStates = []*messages.DBStateMsg{dbstate}
+	sc.ResumeToken = NewRepeatingHash(0x66)
+	msgs = append(msgs, sc)
+
+	type signer interface {
+		Sign(key interfaces.Signer) error
+	}
+	for _, m := range msgs {
+		if s, ok := m.(signer); ok {
+			if err := s.Sign(key); err != nil {
+				panic(err)
+			}
+		}
+	}
+	return msgs
+}
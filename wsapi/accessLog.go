@@ -0,0 +1,244 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package wsapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// Operators of public endpoints need to see who calls what, and how often, to deal with
+// abuse, without keeping packet captures full of what people submit.  The access log has
+// a line of JSON for each API call; the parameters that are large or private are left out,
+// and client addresses can be cut to their network.
+
+// Longest string parameter logged in full
+const maxAccessLogParam = 256
+
+type accessLogEntry struct {
+	Time       string      `json:"time"`
+	Method     string      `json:"method"`
+	Latency    float64     `json:"latencyms"`
+	Status     int         `json:"status"`
+	Error      int         `json:"error,omitempty"` // JSON-RPC error code
+	Client     string      `json:"client"`
+	User       string      `json:"user,omitempty"`
+	Params     interface{} `json:"params,omitempty"`
+	ParamsHash string      `json:"paramshash,omitempty"` // Matches the line of the call in the audit log
+}
+
+var accessLogMutex sync.Mutex
+var accessLog *rotatingFile // nil if not logging
+var accessLogConfig interfaces.APIAccessLogConfig
+
+// StartAccessLog opens the access log the config asks for, if it isn't open already
+func StartAccessLog(cfg interfaces.APIAccessLogConfig) error {
+	accessLogMutex.Lock()
+	defer accessLogMutex.Unlock()
+
+	if accessLog != nil || cfg.Path == "" {
+		return nil
+	}
+	f, err := openRotatingFile(cfg.Path, int64(cfg.MaxSize)<<20, cfg.MaxFiles)
+	if err != nil {
+		return err
+	}
+	accessLog = f
+	accessLogConfig = cfg
+	return nil
+}
+
+// StopAccessLog closes the access log
+func StopAccessLog() error {
+	accessLogMutex.Lock()
+	defer accessLogMutex.Unlock()
+
+	if accessLog == nil {
+		return nil
+	}
+	err := accessLog.Close()
+	accessLog = nil
+	return err
+}
+
+// logAccess logs an API call, if it is sampled.  j is nil if the request couldn't be read,
+// and principal if the caller wasn't authenticated.
+func logAccess(r *http.Request, principal *Principal, j *primitives.JSON2Request, status int, jsonError *primitives.JSONError, start time.Time) {
+	accessLogMutex.Lock()
+	defer accessLogMutex.Unlock()
+
+	if accessLog == nil {
+		return
+	}
+	if status == http.StatusOK && rand.Float64() >= accessLogConfig.SampleRate {
+		return
+	}
+
+	entry := new(accessLogEntry)
+	entry.Time = start.UTC().Format(time.RFC3339Nano)
+	entry.Latency = float64(time.Since(start).Nanoseconds()) / 1e6
+	entry.Status = status
+	if jsonError != nil {
+		entry.Error = jsonError.Code
+	}
+	entry.Client = accessLogClient(r.RemoteAddr, accessLogConfig.Anonymize)
+	if principal != nil && principal.Subject != "" {
+		entry.User = principal.Subject
+	} else if user, _, ok := r.BasicAuth(); ok {
+		entry.User = user
+	}
+	if j != nil {
+		entry.Method = j.Method
+		entry.Params = redactParams(j.Params, accessLogConfig.Redact)
+		entry.ParamsHash = paramsHash(j.Params)
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	accessLog.Write(append(line, '\n'))
+}
+
+// accessLogClient returns the address of a client, cut to its /24 or /48 network if
+// anonymize
+func accessLogClient(remoteAddr string, anonymize bool) string {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !anonymize {
+		return host
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		return ip4.Mask(net.CIDRMask(24, 32)).String()
+	}
+	return ip.Mask(net.CIDRMask(48, 128)).String()
+}
+
+// redactParams returns a copy of the parameters of a call with the values of those named
+// left out, and long strings cut short
+func redactParams(params interface{}, redact []string) interface{} {
+	switch p := params.(type) {
+	case map[string]interface{}:
+		c := make(map[string]interface{}, len(p))
+	keys:
+		for k, v := range p {
+			for _, name := range redact {
+				if k == name {
+					c[k] = "redacted"
+					continue keys
+				}
+			}
+			c[k] = redactParams(v, redact)
+		}
+		return c
+	case []interface{}:
+		c := make([]interface{}, len(p))
+		for i, v := range p {
+			c[i] = redactParams(v, redact)
+		}
+		return c
+	case string:
+		if len(p) > maxAccessLogParam {
+			return fmt.Sprintf("%s... (%d bytes)", p[:maxAccessLogParam], len(p))
+		}
+	}
+	return params
+}
+
+// rotatingFile is a file that is moved aside when it grows past its size, to name.1,
+// name.2 and so on, oldest last
+type rotatingFile struct {
+	mutex    sync.Mutex
+	path     string
+	maxSize  int64 // 0 for no limit
+	maxFiles int
+	file     *os.File
+	size     int64
+}
+
+func openRotatingFile(path string, maxSize int64, maxFiles int) (*rotatingFile, error) {
+	f := &rotatingFile{path: path, maxSize: maxSize, maxFiles: maxFiles}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *rotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	f.file = file
+	f.size = info.Size()
+	return nil
+}
+
+// rotate moves the file aside, dropping the oldest kept, and starts a new one
+func (f *rotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+	f.file = nil
+	if f.maxFiles < 1 {
+		os.Remove(f.path)
+		return f.open()
+	}
+	os.Remove(fmt.Sprintf("%s.%d", f.path, f.maxFiles))
+	for i := f.maxFiles - 1; i > 0; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", f.path, i), fmt.Sprintf("%s.%d", f.path, i+1))
+	}
+	err := os.Rename(f.path, f.path+".1")
+	if oerr := f.open(); oerr != nil {
+		return oerr
+	}
+	return err
+}
+
+func (f *rotatingFile) Write(p []byte) (int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.file == nil {
+		return 0, os.ErrClosed
+	}
+	if f.maxSize > 0 && f.size > 0 && f.size+int64(len(p)) > f.maxSize {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+func (f *rotatingFile) Close() error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
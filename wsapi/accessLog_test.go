@@ -0,0 +1,121 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package wsapi_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/testHelper"
+	. "github.com/FactomProject/factomd/wsapi"
+	"github.com/FactomProject/web"
+)
+
+func TestAPIAccessLog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "accesslog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "access.log")
+
+	cfg := interfaces.APIAccessLogConfig{Path: path, SampleRate: 0, Redact: []string{"message"}, Anonymize: true}
+	if err := StartAccessLog(cfg); err != nil {
+		t.Fatal(err)
+	}
+	defer StopAccessLog()
+
+	state := testHelper.CreateAndPopulateTestState()
+	server := web.NewServer()
+	server.Env["state"] = state
+	call := func(body string) {
+		r := httptest.NewRequest("POST", "/v2", strings.NewReader(body))
+		r.RemoteAddr = "192.0.2.77:4242"
+		HandleV2(&web.Context{Request: r, ResponseWriter: httptest.NewRecorder(), Server: server})
+	}
+
+	// Successful calls are not sampled, failed ones are always logged
+	call(`{"jsonrpc":"2.0","id":0,"method":"heights"}`)
+	call(`{"jsonrpc":"2.0","id":0,"method":"commit-entry","params":{"message":"00zz"}}`)
+	call(`not json`)
+	StopAccessLog()
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	var entries []map[string]interface{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		entry := map[string]interface{}{}
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, entry)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Logged %d calls, expected 2: %v", len(entries), entries)
+	}
+
+	commit := entries[0]
+	if commit["method"] != "commit-entry" || commit["status"] != float64(400) || commit["error"] == nil {
+		t.Errorf("Unexpected entry %v", commit)
+	}
+	if commit["client"] != "192.0.2.0" {
+		t.Errorf("Client %v not anonymized", commit["client"])
+	}
+	if params, _ := commit["params"].(map[string]interface{}); params["message"] != "redacted" {
+		t.Errorf("Params %v not redacted", commit["params"])
+	}
+	if entries[1]["method"] != "" || entries[1]["status"] != float64(400) {
+		t.Errorf("Unexpected entry %v", entries[1])
+	}
+}
+
+func TestAPIAccessLogRotation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "accesslog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "access.log")
+
+	// Fill a megabyte so each further line rotates the log
+	if err := ioutil.WriteFile(path, make([]byte, 1<<20), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := StartAccessLog(interfaces.APIAccessLogConfig{Path: path, SampleRate: 1, MaxSize: 1, MaxFiles: 2}); err != nil {
+		t.Fatal(err)
+	}
+	defer StopAccessLog()
+
+	state := testHelper.CreateAndPopulateTestState()
+	server := web.NewServer()
+	server.Env["state"] = state
+	// Long strings are cut short in the log, so pad with many short ones
+	long := `{"jsonrpc":"2.0","id":0,"method":"heights","params":{"padding":[` + strings.Repeat(`"aaaa",`, 1<<17) + `"aaaa"]}}`
+	for i := 0; i < 4; i++ {
+		r := httptest.NewRequest("POST", "/v2", strings.NewReader(long))
+		HandleV2(&web.Context{Request: r, ResponseWriter: httptest.NewRecorder(), Server: server})
+	}
+	StopAccessLog()
+
+	for _, name := range []string{"access.log", "access.log.1", "access.log.2"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("%s missing: %v", name, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "access.log.3")); err == nil {
+		t.Errorf("Kept more than MaxFiles rotated logs")
+	}
+}
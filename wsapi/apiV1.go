@@ -0,0 +1,107 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package wsapi
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/web"
+)
+
+// The v1 API is served by translating each call to the v2 method that replaced it.  Every
+// v1 response is marked deprecated, with the date it is to be removed and the v2 method to
+// call instead, and the calls are counted by method, so operators can see who still uses it
+// before they set it to be refused.
+
+// V1Methods maps each v1 call to the v2 method it is translated to
+var V1Methods = map[string]string{
+	"factoid-submit":           "factoid-submit",
+	"commit-chain":             "commit-chain",
+	"reveal-chain":             "reveal-chain",
+	"commit-entry":             "commit-entry",
+	"reveal-entry":             "reveal-entry",
+	"directory-block-head":     "directory-block-head",
+	"get-raw-data":             "raw-data",
+	"get-receipt":              "receipt",
+	"directory-block-by-keymr": "directory-block",
+	"directory-block-height":   "heights",
+	"entry-block-by-keymr":     "entry-block",
+	"entry-by-hash":            "entry",
+	"chain-head":               "chain-head",
+	"entry-credit-balance":     "entry-credit-balance",
+	"factoid-balance":          "factoid-balance",
+	"factoid-get-fee":          "entry-credit-rate",
+	"properties":               "properties",
+	"heights":                  "heights",
+	"dblock-by-height":         "dblock-by-height",
+	"ecblock-by-height":        "ecblock-by-height",
+	"fblock-by-height":         "fblock-by-height",
+	"ablock-by-height":         "ablock-by-height",
+}
+
+// V1DeprecationHeaders returns the headers marking the response to a v1 call as deprecated:
+// when the v1 API goes, where to read about moving off it, and a warning naming the v2
+// method to call instead
+func V1DeprecationHeaders(config interfaces.APIV1Config, call string) map[string]string {
+	headers := map[string]string{"Deprecation": "true"}
+
+	warning := fmt.Sprintf("The v1 %s call is deprecated", call)
+	if !config.Sunset.IsZero() {
+		headers["Sunset"] = config.Sunset.UTC().Format(http.TimeFormat)
+		warning += fmt.Sprintf(" and will be removed after %s", config.Sunset.UTC().Format("2006-01-02"))
+	}
+	if method, ok := V1Methods[call]; ok {
+		warning += fmt.Sprintf("; call the v2 %s method instead", method)
+	}
+	headers["Warning"] = fmt.Sprintf("299 factomd %q", warning)
+
+	if config.Notice != "" {
+		headers["Link"] = fmt.Sprintf("<%s>; rel=\"deprecation\"", config.Notice)
+	}
+	return headers
+}
+
+// v1Served marks the response to a v1 call deprecated and counts the call.  It returns
+// false, having answered 410 Gone, once the v1 API is past its sunset and set to be refused.
+func v1Served(ctx *web.Context, call string) bool {
+	ServersMutex.Lock()
+	state := ctx.Server.Env["state"].(interfaces.IState)
+	ServersMutex.Unlock()
+
+	config := state.GetAPIV1()
+	for name, value := range V1DeprecationHeaders(config, call) {
+		ctx.ResponseWriter.Header().Set(name, value)
+	}
+
+	if config.Refused(time.Now()) {
+		V1CallsVec.WithLabelValues(call, "refused").Inc()
+		ctx.WriteHeader(http.StatusGone)
+		ctx.Write([]byte(fmt.Sprintf("The v1 API has been removed; call the v2 %s method instead", V1Methods[call])))
+		return false
+	}
+	V1CallsVec.WithLabelValues(call, "served").Inc()
+	return true
+}
+
+// v1Route wraps the handler of a v1 call without parameters in the compatibility layer
+func v1Route(call string, handler func(*web.Context)) func(*web.Context) {
+	return func(ctx *web.Context) {
+		if v1Served(ctx, call) {
+			handler(ctx)
+		}
+	}
+}
+
+// v1RouteParam wraps the handler of a v1 call taking a parameter from its path
+func v1RouteParam(call string, handler func(*web.Context, string)) func(*web.Context, string) {
+	return func(ctx *web.Context, param string) {
+		if v1Served(ctx, call) {
+			handler(ctx, param)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package wsapi_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/testHelper"
+	. "github.com/FactomProject/factomd/wsapi"
+)
+
+func TestV1DeprecationHeaders(t *testing.T) {
+	headers := V1DeprecationHeaders(interfaces.APIV1Config{}, "get-raw-data")
+	if headers["Deprecation"] != "true" {
+		t.Error("A v1 response isn't marked deprecated")
+	}
+	if _, ok := headers["Sunset"]; ok {
+		t.Error("Sunset header without a sunset date")
+	}
+	if !strings.Contains(headers["Warning"], "v2 raw-data method") {
+		t.Errorf("Warning doesn't name the v2 method: %s", headers["Warning"])
+	}
+
+	sunset := time.Date(2027, 6, 30, 0, 0, 0, 0, time.UTC)
+	config := interfaces.APIV1Config{Sunset: sunset, Notice: "https://example.com/v1"}
+	headers = V1DeprecationHeaders(config, "heights")
+	if headers["Sunset"] != "Wed, 30 Jun 2027 00:00:00 GMT" {
+		t.Errorf("Sunset header %q", headers["Sunset"])
+	}
+	if !strings.Contains(headers["Warning"], "after 2027-06-30") {
+		t.Errorf("Warning doesn't give the sunset: %s", headers["Warning"])
+	}
+	if headers["Link"] != `<https://example.com/v1>; rel="deprecation"` {
+		t.Errorf("Link header %q", headers["Link"])
+	}
+
+	// Only refused past the sunset, and if set to be
+	if config.Refused(sunset.Add(time.Hour)) {
+		t.Error("Refused without RefuseAfterSunset")
+	}
+	config.RefuseAfterSunset = true
+	if config.Refused(sunset.Add(-time.Hour)) || !config.Refused(sunset.Add(time.Hour)) {
+		t.Error("Refused on the wrong side of the sunset")
+	}
+}
+
+func TestV1MethodsAreV2Methods(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestState()
+	for call, method := range V1Methods {
+		req := primitives.NewJSON2Request(method, 1, nil)
+		_, jsonError := HandleV2Request(state, req)
+		if jsonError != nil && jsonError.Code == NewMethodNotFoundError().Code {
+			t.Errorf("v1 %s call is translated to %s, which isn't a v2 method", call, method)
+		}
+	}
+}
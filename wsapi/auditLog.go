@@ -0,0 +1,134 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package wsapi
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// The access log is sampled and can be anonymized, which suits watching for abuse, but not
+// finding which client submitted a given commit.  The audit log has a line of JSON for
+// every call that writes to the chain, with the client's full address, and the
+// transaction, entry and chain the call was of.  The parameters aren't kept, only their
+// hash, which the access log has too, so the lines of the two logs can be matched up.
+
+type auditLogEntry struct {
+	Time       string  `json:"time"`
+	Method     string  `json:"method"`
+	ParamsHash string  `json:"paramshash,omitempty"`
+	Client     string  `json:"client"`
+	User       string  `json:"user,omitempty"`
+	Latency    float64 `json:"latencyms"`
+	Status     int     `json:"status"`
+	Error      int     `json:"error,omitempty"` // JSON-RPC error code
+	TxID       string  `json:"txid,omitempty"`
+	EntryHash  string  `json:"entryhash,omitempty"`
+	ChainID    string  `json:"chainid,omitempty"`
+}
+
+var auditLogMutex sync.Mutex
+var auditLog *rotatingFile // nil if not logging
+
+// StartAuditLog opens the audit log the config asks for, if it isn't open already
+func StartAuditLog(cfg interfaces.APIAuditLogConfig) error {
+	auditLogMutex.Lock()
+	defer auditLogMutex.Unlock()
+
+	if auditLog != nil || cfg.Path == "" {
+		return nil
+	}
+	f, err := openRotatingFile(cfg.Path, int64(cfg.MaxSize)<<20, cfg.MaxFiles)
+	if err != nil {
+		return err
+	}
+	auditLog = f
+	return nil
+}
+
+// StopAuditLog closes the audit log
+func StopAuditLog() error {
+	auditLogMutex.Lock()
+	defer auditLogMutex.Unlock()
+
+	if auditLog == nil {
+		return nil
+	}
+	err := auditLog.Close()
+	auditLog = nil
+	return err
+}
+
+// logAudit logs an API call if it writes to the chain or operates the node.  principal is nil if the caller
+// wasn't authenticated, and resp if the call failed.
+func logAudit(r *http.Request, principal *Principal, j *primitives.JSON2Request, resp *primitives.JSON2Response, status int, jsonError *primitives.JSONError, start time.Time) {
+	if j == nil || methodPermission(j.Method) == PermissionRead {
+		return
+	}
+	auditLogMutex.Lock()
+	defer auditLogMutex.Unlock()
+
+	if auditLog == nil {
+		return
+	}
+
+	entry := new(auditLogEntry)
+	entry.Time = start.UTC().Format(time.RFC3339Nano)
+	entry.Method = j.Method
+	entry.ParamsHash = paramsHash(j.Params)
+	entry.Client = r.RemoteAddr
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		entry.Client = host
+	}
+	if principal != nil {
+		entry.User = principal.Subject
+	}
+	entry.Latency = float64(time.Since(start).Nanoseconds()) / 1e6
+	entry.Status = status
+	if jsonError != nil {
+		entry.Error = jsonError.Code
+	}
+	if resp != nil {
+		// The results of the writes name what they were of in these fields
+		var ids struct {
+			TxID      string `json:"txid"`
+			EntryHash string `json:"entryhash"`
+			ChainID   string `json:"chainid"`
+		}
+		if data, err := json.Marshal(resp.Result); err == nil && json.Unmarshal(data, &ids) == nil {
+			entry.TxID = ids.TxID
+			entry.EntryHash = ids.EntryHash
+			entry.ChainID = ids.ChainID
+		}
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	auditLog.Write(append(line, '\n'))
+}
+
+// paramsHash returns the hex of the SHA256 of the parameters of a call, as JSON, or "" if
+// it has none
+func paramsHash(params interface{}) string {
+	if params == nil {
+		return ""
+	}
+	data, err := json.Marshal(params)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,84 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package wsapi_test
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/testHelper"
+	. "github.com/FactomProject/factomd/wsapi"
+	"github.com/FactomProject/web"
+)
+
+func TestAPIAuditLog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "auditlog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "audit.log")
+
+	if err := StartAuditLog(interfaces.APIAuditLogConfig{Path: path}); err != nil {
+		t.Fatal(err)
+	}
+	defer StopAuditLog()
+
+	state := testHelper.CreateAndPopulateTestState()
+	server := web.NewServer()
+	server.Env["state"] = state
+	call := func(body string) {
+		r := httptest.NewRequest("POST", "/v2", strings.NewReader(body))
+		r.RemoteAddr = "192.0.2.77:4242"
+		HandleV2(&web.Context{Request: r, ResponseWriter: httptest.NewRecorder(), Server: server})
+	}
+
+	// Only the calls that write are audited, whether or not they succeed
+	call(`{"jsonrpc":"2.0","id":0,"method":"heights"}`)
+	call(`{"jsonrpc":"2.0","id":0,"method":"commit-entry","params":{"message":"00zz"}}`)
+	StopAuditLog()
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	var entries []map[string]interface{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		entry := map[string]interface{}{}
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, entry)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("Audited %d calls, expected 1: %v", len(entries), entries)
+	}
+
+	commit := entries[0]
+	if commit["method"] != "commit-entry" || commit["status"] != float64(400) || commit["error"] == nil {
+		t.Errorf("Unexpected entry %v", commit)
+	}
+	if commit["client"] != "192.0.2.77" {
+		t.Errorf("Client %v, expected the full address", commit["client"])
+	}
+	sum := sha256.Sum256([]byte(`{"message":"00zz"}`))
+	if commit["paramshash"] != hex.EncodeToString(sum[:]) {
+		t.Errorf("Params hash %v, expected the hash of the params", commit["paramshash"])
+	}
+	if _, ok := commit["params"]; ok {
+		t.Errorf("Params kept in the audit log")
+	}
+}
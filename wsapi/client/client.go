@@ -0,0 +1,166 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+// Package client is a Go client for the factomd v2 API.  It has a typed method for every
+// v2 method, retries requests that failed on the way to the node, and helpers for the
+// calls that return their results a page or a block at a time.  Responses use the types
+// of the wsapi package where they can be decoded as they are, so the client can't drift
+// from the server.
+//
+// The API is also described in openapi.yaml, next to this file, for generating clients
+// in other languages.
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// DefaultURL is where factomd serves its API, unless configured otherwise
+const DefaultURL = "http://localhost:8088"
+
+// Client makes requests to a factomd node.  The fields may be changed before the first
+// request is made; a Client is safe for concurrent use after that.
+type Client struct {
+	URL      string // Scheme, host and port of the node, ie http://localhost:8088
+	User     string // For nodes with RpcUser set in their config
+	Password string
+
+	HTTPClient *http.Client
+
+	Retries    int           // How many times a failed request is retried
+	RetryDelay time.Duration // Wait before the first retry, doubled for each after it
+
+	id uint64 // Last request ID used
+}
+
+func NewClient(url string) *Client {
+	c := new(Client)
+	c.URL = strings.TrimRight(url, "/")
+	c.HTTPClient = &http.Client{Timeout: 30 * time.Second}
+	c.Retries = 3
+	c.RetryDelay = 500 * time.Millisecond
+	return c
+}
+
+// APIError is an error returned by the node, as opposed to one getting to it
+type APIError struct {
+	Method string
+	primitives.JSONError
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s: %d %s", e.Method, e.Code, e.JSONError.Error())
+}
+
+// IsAPIError is true if err is an error returned by the node with the code given
+func IsAPIError(err error, code int) bool {
+	e, ok := err.(*APIError)
+	return ok && e.Code == code
+}
+
+// transientError is a failure that may not happen again, so the request can be retried
+type transientError struct {
+	err        error
+	retryAfter time.Duration // How long the node asked us to wait, if it did
+}
+
+func (e *transientError) Error() string {
+	return e.err.Error()
+}
+
+type response struct {
+	JSONRPC string                `json:"jsonrpc"`
+	ID      interface{}           `json:"id"`
+	Result  json.RawMessage       `json:"result"`
+	Error   *primitives.JSONError `json:"error"`
+}
+
+// Call makes a request for any v2 method, decoding the result into result.  Requests
+// that failed on the way to the node, or that the node was too busy to take, are
+// retried when retry is true, waiting at least as long as the node asks.  Only requests
+// that can safely be made twice should be.
+func (c *Client) Call(method string, params interface{}, result interface{}, retry bool) error {
+	delay := c.RetryDelay
+	for attempt := 0; ; attempt++ {
+		err := c.call(method, params, result)
+		t, ok := err.(*transientError)
+		if !ok {
+			return err
+		}
+		if !retry || attempt >= c.Retries {
+			return t.err
+		}
+		wait := delay
+		if t.retryAfter > wait {
+			wait = t.retryAfter
+		}
+		time.Sleep(wait)
+		delay *= 2
+	}
+}
+
+func (c *Client) call(method string, params interface{}, result interface{}) error {
+	req := primitives.NewJSON2Request(method, atomic.AddUint64(&c.id, 1), params)
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	hreq, err := http.NewRequest("POST", c.URL+"/v2", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	hreq.Header.Set("Content-Type", "application/json")
+	if c.User != "" {
+		hreq.SetBasicAuth(c.User, c.Password)
+	}
+
+	hresp, err := c.HTTPClient.Do(hreq)
+	if err != nil {
+		return &transientError{err: err}
+	}
+	defer hresp.Body.Close()
+	data, err := ioutil.ReadAll(hresp.Body)
+	if err != nil {
+		return &transientError{err: err}
+	}
+
+	switch {
+	case hresp.StatusCode == http.StatusUnauthorized:
+		return fmt.Errorf("%s: not authorized, check the user and password", method)
+	case hresp.StatusCode == http.StatusTooManyRequests || hresp.StatusCode >= 500:
+		t := &transientError{err: fmt.Errorf("%s: %s", method, hresp.Status)}
+		if secs, err := strconv.Atoi(hresp.Header.Get("Retry-After")); err == nil {
+			t.retryAfter = time.Duration(secs) * time.Second
+		}
+		// Submissions the node turned away carry the reason
+		resp := new(response)
+		if json.Unmarshal(data, resp) == nil && resp.Error != nil {
+			t.err = &APIError{Method: method, JSONError: *resp.Error}
+		}
+		return t
+	}
+
+	// Errors come back with a 400, and a JSON-RPC error in the body
+	resp := new(response)
+	if err := json.Unmarshal(data, resp); err != nil {
+		return fmt.Errorf("%s: unexpected response (%s): %v", method, hresp.Status, err)
+	}
+	if resp.Error != nil {
+		return &APIError{Method: method, JSONError: *resp.Error}
+	}
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(resp.Result, result)
+}
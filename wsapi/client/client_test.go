@@ -0,0 +1,277 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package client_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/wsapi"
+	. "github.com/FactomProject/factomd/wsapi/client"
+	"github.com/FactomProject/web"
+)
+
+type call struct {
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+}
+
+// fakeNode answers v2 calls with answer, and records the calls made
+type fakeNode struct {
+	sync.Mutex
+	calls  []call
+	answer func(c call) (status int, body string)
+}
+
+func (f *fakeNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+	var c call
+	json.Unmarshal(body, &c)
+	f.Lock()
+	f.calls = append(f.calls, c)
+	f.Unlock()
+	status, resp := f.answer(c)
+	w.WriteHeader(status)
+	w.Write([]byte(resp))
+}
+
+func newTestClient(f http.Handler) (*Client, *httptest.Server) {
+	server := httptest.NewServer(f)
+	c := NewClient(server.URL)
+	c.RetryDelay = time.Millisecond
+	return c, server
+}
+
+func TestMethodsCovered(t *testing.T) {
+	source, err := ioutil.ReadFile("../wsapiV2.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec, err := ioutil.ReadFile("openapi.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	covered := map[string]bool{}
+	for _, m := range Methods {
+		covered[m] = true
+		if !strings.Contains(string(spec), "enum: ["+m+"]") {
+			t.Errorf("%s is missing from openapi.yaml", m)
+		}
+	}
+	served := regexp.MustCompile(`case "([a-z-]+)":`).FindAllStringSubmatch(string(source), -1)
+	if len(served) != len(Methods) {
+		t.Errorf("The node has %d methods, the client %d", len(served), len(Methods))
+	}
+	for _, m := range served {
+		if !covered[m[1]] {
+			t.Errorf("%s isn't covered by the client", m[1])
+		}
+	}
+}
+
+func TestCall(t *testing.T) {
+	f := new(fakeNode)
+	f.answer = func(c call) (int, string) {
+		switch c.Method {
+		case "heights":
+			return 200, `{"jsonrpc":"2.0","id":1,"result":{"directoryblockheight":10,"leaderheight":11,"entryblockheight":10,"entryheight":9}}`
+		case "authorities":
+			return 200, `{"jsonrpc":"2.0","id":1,"result":{"Authorities":[{"chainid":"aa"}]}}`
+		}
+		return 400, `{"jsonrpc":"2.0","id":1,"error":{"code":-32602,"message":"Invalid params","data":"Invalid Hash"}}`
+	}
+	c, server := newTestClient(f)
+	defer server.Close()
+
+	h, err := c.Heights()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.DirectoryBlockHeight != 10 || h.LeaderHeight != 11 || h.EntryHeight != 9 {
+		t.Errorf("Unexpected heights %+v", h)
+	}
+
+	a, err := c.Authorities()
+	if err != nil || len(a.Authorities) != 1 {
+		t.Errorf("Unexpected authorities %+v: %v", a, err)
+	}
+
+	_, err = c.Entry("nothex")
+	if !IsAPIError(err, -32602) {
+		t.Errorf("Expected an invalid params error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "Invalid Hash") {
+		t.Errorf("Error doesn't say what was wrong: %v", err)
+	}
+	if len(f.calls) != 3 {
+		t.Errorf("An API error was retried: %d calls", len(f.calls))
+	}
+	var params wsapi.HashRequest
+	json.Unmarshal(f.calls[2].Params, &params)
+	if params.Hash != "nothex" {
+		t.Errorf("Unexpected params %s", f.calls[2].Params)
+	}
+}
+
+func TestRetries(t *testing.T) {
+	failures := 2
+	f := new(fakeNode)
+	f.answer = func(c call) (int, string) {
+		if failures > 0 {
+			failures--
+			return 503, "busy"
+		}
+		return 200, `{"jsonrpc":"2.0","id":1,"result":{"message":"Chain Commit Success","txid":"ab"}}`
+	}
+	c, server := newTestClient(f)
+	defer server.Close()
+
+	// A commit is retried with the same idempotency key, so it can't be paid for twice
+	resp, err := c.CommitChain("00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.TxID != "ab" || len(f.calls) != 3 {
+		t.Errorf("Unexpected response %+v after %d calls", resp, len(f.calls))
+	}
+	var keys []string
+	for _, call := range f.calls {
+		var req wsapi.MessageRequest
+		json.Unmarshal(call.Params, &req)
+		keys = append(keys, req.IdempotencyKey)
+	}
+	if keys[0] == "" || keys[0] != keys[1] || keys[1] != keys[2] {
+		t.Errorf("Retries didn't repeat the idempotency key: %v", keys)
+	}
+	if _, err := c.CommitChain("00"); err != nil {
+		t.Fatal(err)
+	}
+	var req wsapi.MessageRequest
+	json.Unmarshal(f.calls[3].Params, &req)
+	if req.IdempotencyKey == keys[0] {
+		t.Error("Two commits had the same idempotency key")
+	}
+
+	// Raw messages aren't retried
+	failures = 1
+	f.calls = nil
+	if _, err := c.SendRawMessage("00"); err == nil || len(f.calls) != 1 {
+		t.Errorf("Raw message retried: %d calls, error %v", len(f.calls), err)
+	}
+
+	// Retries run out
+	failures = 10
+	f.calls = nil
+	if _, err := c.Heights(); err == nil || len(f.calls) != c.Retries+1 {
+		t.Errorf("Expected %d calls and an error, had %d calls and %v", c.Retries+1, len(f.calls), err)
+	}
+
+	// A submission the node turned away gives its error once retries run out
+	f.calls = nil
+	f.answer = func(c call) (int, string) {
+		return 503, `{"jsonrpc":"2.0","id":1,"error":{"code":-32012,"message":"Submission rejected, try again later","data":{"status":"rejected","syncing":true,"retryafter":30}}}`
+	}
+	if _, err := c.RevealEntry("00"); !IsAPIError(err, -32012) || len(f.calls) != c.Retries+1 {
+		t.Errorf("Expected %d calls and a rejection, had %d calls and %v", c.Retries+1, len(f.calls), err)
+	}
+}
+
+func TestEachChainEntry(t *testing.T) {
+	f := new(fakeNode)
+	f.answer = func(c call) (int, string) {
+		var req wsapi.ChainEntriesRequest
+		json.Unmarshal(c.Params, &req)
+		resp := wsapi.ChainEntriesResponse{ChainID: req.ChainID}
+		// One entry a block, two blocks a page
+		for h := req.From; h <= req.To; h++ {
+			if len(resp.Entries) == 2 {
+				resp.NextHeight = h
+				break
+			}
+			resp.Entries = append(resp.Entries, wsapi.EntryPosition{DBHeight: h})
+		}
+		data, _ := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "result": resp})
+		return 200, string(data)
+	}
+	c, server := newTestClient(f)
+	defer server.Close()
+
+	var heights []uint32
+	err := c.EachChainEntry("aa", 3, 9, func(e wsapi.EntryPosition) error {
+		heights = append(heights, e.DBHeight)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(heights) != 7 || heights[0] != 3 || heights[6] != 9 || len(f.calls) != 4 {
+		t.Errorf("Unexpected entries %v after %d calls", heights, len(f.calls))
+	}
+
+	// Stop ends the walk without an error
+	f.calls = nil
+	err = c.EachChainEntry("aa", 3, 9, func(e wsapi.EntryPosition) error {
+		if e.DBHeight == 5 {
+			return Stop
+		}
+		return nil
+	})
+	if err != nil || len(f.calls) != 2 {
+		t.Errorf("Walk didn't stop: %d calls, error %v", len(f.calls), err)
+	}
+}
+
+func TestSubscribeStatusEvents(t *testing.T) {
+	sent := []interfaces.StatusEvent{
+		{Type: interfaces.StatusEventSyncing, NodeName: "FNode0", DBHeight: 5, VMIndex: -1},
+		{Type: interfaces.StatusEventInSync, NodeName: "FNode0", DBHeight: 9, VMIndex: -1},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/status-events" {
+			http.NotFound(w, r)
+			return
+		}
+		ws, err := wsapi.UpgradeWebsocket(&web.Context{Request: r, ResponseWriter: w})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		for _, e := range sent {
+			ws.WriteJSON(e)
+		}
+		<-ws.Closed
+	}))
+	defer server.Close()
+
+	stream, err := NewClient(server.URL).SubscribeStatusEvents()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range sent {
+		select {
+		case got := <-stream.Events:
+			if got != want {
+				t.Errorf("Expected %+v, got %+v", want, got)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timed out waiting for an event")
+		}
+	}
+	stream.Close()
+	if _, open := <-stream.Events; open {
+		t.Error("Events still open after Close")
+	}
+	if stream.Err() != nil {
+		t.Errorf("Unexpected error %v", stream.Err())
+	}
+}
@@ -0,0 +1,230 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/wsapi"
+)
+
+// The node pushes events over a websocket.  Only what the node sends is implemented
+// here: unfragmented text frames, pings and close.
+
+const (
+	wsOpText  = 0x1
+	wsOpClose = 0x8
+	wsOpPing  = 0x9
+	wsOpPong  = 0xA
+)
+
+// Events larger than this are taken as a broken connection
+const maxEventSize = 1 << 20
+
+// StatusEventStream receives the status events of a node, from SubscribeStatusEvents.
+// Events is closed when the connection ends; Err then says why.
+type StatusEventStream struct {
+	Events <-chan interfaces.StatusEvent
+
+	conn      net.Conn
+	done      chan struct{}
+	closeOnce sync.Once
+	writeLock sync.Mutex
+	err       error
+}
+
+// SubscribeStatusEvents connects to the node's status-events websocket.  Events that
+// arrive while the last one hasn't been read wait on the connection, so a reader that
+// falls far enough behind is dropped by the node.
+func (c *Client) SubscribeStatusEvents() (*StatusEventStream, error) {
+	u, err := url.Parse(c.URL)
+	if err != nil {
+		return nil, err
+	}
+	host := u.Host
+	if u.Port() == "" {
+		if u.Scheme == "https" {
+			host += ":443"
+		} else {
+			host += ":80"
+		}
+	}
+
+	var conn net.Conn
+	if u.Scheme == "https" {
+		conn, err = tls.Dial("tcp", host, &tls.Config{ServerName: u.Hostname()})
+	} else {
+		conn, err = net.Dial("tcp", host)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	key := make([]byte, 16)
+	rand.Read(key)
+	wsKey := base64.StdEncoding.EncodeToString(key)
+	req, err := http.NewRequest("GET", c.URL+"/v2/status-events", nil)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Sec-WebSocket-Key", wsKey)
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	if c.User != "" {
+		req.SetBasicAuth(c.User, c.Password)
+	}
+	if err := req.Write(conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	r := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(r, req)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		conn.Close()
+		return nil, fmt.Errorf("status-events: %s", resp.Status)
+	}
+	if resp.Header.Get("Sec-WebSocket-Accept") != wsapi.WebsocketAccept(wsKey) {
+		conn.Close()
+		return nil, errors.New("status-events: bad Sec-WebSocket-Accept")
+	}
+
+	events := make(chan interfaces.StatusEvent)
+	s := new(StatusEventStream)
+	s.Events = events
+	s.conn = conn
+	s.done = make(chan struct{})
+	go s.readLoop(r, events)
+	return s, nil
+}
+
+func (s *StatusEventStream) readLoop(r io.Reader, events chan<- interfaces.StatusEvent) {
+	defer close(events)
+	defer s.conn.Close()
+	for {
+		op, payload, err := readFrame(r)
+		if err != nil {
+			select {
+			case <-s.done:
+			default:
+				s.err = err
+			}
+			return
+		}
+		switch op {
+		case wsOpText:
+			var event interfaces.StatusEvent
+			if err := json.Unmarshal(payload, &event); err != nil {
+				s.err = err
+				return
+			}
+			select {
+			case events <- event:
+			case <-s.done:
+				return
+			}
+		case wsOpPing:
+			s.writeFrame(wsOpPong, payload)
+		case wsOpClose:
+			return
+		}
+	}
+}
+
+// Err returns why the stream ended, or nil if it was closed by either end
+func (s *StatusEventStream) Err() error {
+	select {
+	case <-s.done:
+		return nil
+	default:
+	}
+	return s.err
+}
+
+// Close ends the subscription
+func (s *StatusEventStream) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.done)
+		s.writeFrame(wsOpClose, nil)
+	})
+	return s.conn.Close()
+}
+
+// writeFrame sends a frame to the node.  Frames from clients must be masked.
+func (s *StatusEventStream) writeFrame(op byte, payload []byte) error {
+	var mask [4]byte
+	rand.Read(mask[:])
+	frame := []byte{0x80 | op}
+	l := len(payload)
+	switch {
+	case l < 126:
+		frame = append(frame, 0x80|byte(l))
+	case l <= 0xFFFF:
+		frame = append(frame, 0x80|126, byte(l>>8), byte(l))
+	default:
+		var b [8]byte
+		binary.BigEndian.PutUint64(b[:], uint64(l))
+		frame = append(frame, 0x80|127)
+		frame = append(frame, b[:]...)
+	}
+	frame = append(frame, mask[:]...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+	s.writeLock.Lock()
+	defer s.writeLock.Unlock()
+	_, err := s.conn.Write(frame)
+	return err
+}
+
+// readFrame reads one unmasked frame, as sent by the node
+func readFrame(r io.Reader) (op byte, payload []byte, err error) {
+	var hdr [2]byte
+	if _, err = io.ReadFull(r, hdr[:]); err != nil {
+		return
+	}
+	op = hdr[0] & 0x0F
+	l := uint64(hdr[1] & 0x7F)
+	switch l {
+	case 126:
+		var b [2]byte
+		if _, err = io.ReadFull(r, b[:]); err != nil {
+			return
+		}
+		l = uint64(binary.BigEndian.Uint16(b[:]))
+	case 127:
+		var b [8]byte
+		if _, err = io.ReadFull(r, b[:]); err != nil {
+			return
+		}
+		l = binary.BigEndian.Uint64(b[:])
+	}
+	if l > maxEventSize {
+		err = errors.New("websocket frame too large")
+		return
+	}
+	payload = make([]byte, l)
+	_, err = io.ReadFull(r, payload)
+	return
+}
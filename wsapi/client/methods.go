@@ -0,0 +1,711 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/wsapi"
+)
+
+// Methods lists every v2 method the client covers
+var Methods = []string{
+	"ablock-by-height",
+	"ack",
+	"admin-block",
+	"api-queue",
+	"authorities",
+	"authority-stats",
+	"backpressure",
+	"backup-database",
+	"capacity",
+	"chain-entries",
+	"chain-head",
+	"coinbase-audit",
+	"commit-chain",
+	"commit-entry",
+	"commit-rate-limits",
+	"create-chain",
+	"current-minute",
+	"dblock-by-height",
+	"directory-block",
+	"directory-block-head",
+	"ecblock-by-height",
+	"entries-by-extid",
+	"entry",
+	"entry-ack",
+	"entry-block",
+	"entry-credit-balance",
+	"entry-credit-rate",
+	"entry-credit-rate-history",
+	"entrycredit-block",
+	"ethereum-anchor",
+	"ethereum-receipt",
+	"factoid-ack",
+	"factoid-balance",
+	"factoid-block",
+	"factoid-submit",
+	"fblock-by-height",
+	"fct-supply",
+	"heights",
+	"multisig-address",
+	"multisig-compose",
+	"multisig-sign",
+	"network-parameters",
+	"object-delete",
+	"object-get",
+	"object-list",
+	"object-put",
+	"peer-reputation",
+	"pending-entries",
+	"pending-pool",
+	"pending-transactions",
+	"properties",
+	"raw-data",
+	"receipt",
+	"receipt-subscribe",
+	"receipt-unsubscribe",
+	"references",
+	"rejections",
+	"reveal-chain",
+	"reveal-entry",
+	"send-raw-message",
+	"startup-progress",
+	"timing-anomalies",
+	"tps-rate",
+	"transaction",
+	"transactions-by-address",
+}
+
+// newIdempotencyKey makes the key sent with commits, so a commit retried after a timeout
+// isn't paid for twice
+func newIdempotencyKey() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+/*********************************************************************/
+// Submissions.  Commits carry an idempotency key, and reveals and transactions can't be
+// applied twice, so all of them are retried, including when the node is syncing or too
+// busy to take them.  Responses say whether the submission was queued behind others.
+
+// CommitChain submits a chain commit, hex encoded
+func (c *Client) CommitChain(message string) (*wsapi.CommitChainResponse, error) {
+	resp := new(wsapi.CommitChainResponse)
+	req := wsapi.MessageRequest{Message: message, IdempotencyKey: newIdempotencyKey()}
+	if err := c.Call("commit-chain", req, resp, true); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// CommitEntry submits an entry commit, hex encoded
+func (c *Client) CommitEntry(message string) (*wsapi.CommitEntryResponse, error) {
+	resp := new(wsapi.CommitEntryResponse)
+	req := wsapi.MessageRequest{Message: message, IdempotencyKey: newIdempotencyKey()}
+	if err := c.Call("commit-entry", req, resp, true); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// CreateChain submits a chain commit and its first entry, then the commits and entries
+// to append to the chain after it, as one request.  Nothing is submitted unless all of
+// them are valid and paid for.
+func (c *Client) CreateChain(req wsapi.CreateChainRequest) (*wsapi.CreateChainResponse, error) {
+	resp := new(wsapi.CreateChainResponse)
+	if req.IdempotencyKey == "" {
+		req.IdempotencyKey = newIdempotencyKey()
+	}
+	if err := c.Call("create-chain", req, resp, true); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// RevealChain submits the first entry of a chain, hex encoded
+func (c *Client) RevealChain(entry string) (*wsapi.RevealEntryResponse, error) {
+	resp := new(wsapi.RevealEntryResponse)
+	if err := c.Call("reveal-chain", wsapi.EntryRequest{Entry: entry}, resp, true); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// RevealEntry submits an entry, hex encoded
+func (c *Client) RevealEntry(entry string) (*wsapi.RevealEntryResponse, error) {
+	resp := new(wsapi.RevealEntryResponse)
+	if err := c.Call("reveal-entry", wsapi.EntryRequest{Entry: entry}, resp, true); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// FactoidSubmit submits a signed factoid transaction, hex encoded
+func (c *Client) FactoidSubmit(transaction string) (*wsapi.FactoidSubmitResponse, error) {
+	resp := new(wsapi.FactoidSubmitResponse)
+	if err := c.Call("factoid-submit", wsapi.TransactionRequest{Transaction: transaction}, resp, true); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// MultisigAddress returns the factoid address and RCD any required of the hex public
+// keys given redeem
+func (c *Client) MultisigAddress(required int, keys []string) (*wsapi.MultisigAddressResponse, error) {
+	resp := new(wsapi.MultisigAddressResponse)
+	if err := c.Call("multisig-address", wsapi.MultisigAddressRequest{Required: required, Keys: keys}, resp, true); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// MultisigCompose returns an unsigned transaction, and what its signers sign
+func (c *Client) MultisigCompose(req wsapi.MultisigComposeRequest) (*wsapi.MultisigTransactionResponse, error) {
+	resp := new(wsapi.MultisigTransactionResponse)
+	if err := c.Call("multisig-compose", req, resp, true); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// MultisigSign adds the hex signature of one signer of a multisig input to a hex
+// transaction.  Submit it with FactoidSubmit once it is complete.
+func (c *Client) MultisigSign(transaction string, input int, signature string) (*wsapi.MultisigTransactionResponse, error) {
+	resp := new(wsapi.MultisigTransactionResponse)
+	req := wsapi.MultisigSignRequest{Transaction: transaction, Input: input, Signature: signature}
+	if err := c.Call("multisig-sign", req, resp, true); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// SendRawMessage submits any message, hex encoded.  It isn't retried.
+func (c *Client) SendRawMessage(message string) (*wsapi.SendRawMessageResponse, error) {
+	resp := new(wsapi.SendRawMessageResponse)
+	if err := c.Call("send-raw-message", wsapi.SendRawMessageRequest{Message: message}, resp, false); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// APIQueue reports how full the node's API queue is, and whether a submission made now
+// would be taken
+func (c *Client) APIQueue() (*interfaces.APISubmission, error) {
+	resp := new(interfaces.APISubmission)
+	if err := c.Call("api-queue", nil, resp, true); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// Backpressure reports how saturated the node's inbound queue is, and how long to wait
+// before submitting again while it is busy
+func (c *Client) Backpressure() (*interfaces.Backpressure, error) {
+	resp := new(interfaces.Backpressure)
+	if err := c.Call("backpressure", nil, resp, true); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// BackupDatabase has the node write a consistent copy of its database to path, a path
+// on the node.  It needs the admin permission.
+func (c *Client) BackupDatabase(path string) (*BackupDatabaseResponse, error) {
+	resp := new(BackupDatabaseResponse)
+	if err := c.Call("backup-database", wsapi.BackupDatabaseRequest{Path: path}, resp, false); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// CommitRateLimits reports how fast the node, as a leader, takes the commits of each entry
+// credit address, and how those that committed lately stand; just address's if it isn't
+// empty
+func (c *Client) CommitRateLimits(address string) (*interfaces.CommitRateLimits, error) {
+	resp := new(interfaces.CommitRateLimits)
+	req := wsapi.CommitRateLimitsRequest{Address: address}
+	if err := c.Call("commit-rate-limits", req, resp, true); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// Rejections reports the blocks and messages the node turned away, and why; just those of
+// hash, the KeyMR of a directory block or the hash of a message, if it isn't empty
+func (c *Client) Rejections(hash string) (*interfaces.RejectionReport, error) {
+	resp := new(interfaces.RejectionReport)
+	req := wsapi.RejectionsRequest{Hash: hash}
+	if err := c.Call("rejections", req, resp, true); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// PeerReputation reports how each peer of the node's network has behaved, worst first
+func (c *Client) PeerReputation() ([]interfaces.PeerReputation, error) {
+	resp := new(wsapi.PeerReputationResponse)
+	if err := c.Call("peer-reputation", nil, resp, true); err != nil {
+		return nil, err
+	}
+	return resp.Peers, nil
+}
+
+/*********************************************************************/
+// Acknowledgements
+
+// FactoidAck returns the status of a factoid transaction, by its ID or the whole
+// transaction, hex encoded
+func (c *Client) FactoidAck(txID string, fullTransaction string) (*wsapi.FactoidTxStatus, error) {
+	resp := new(wsapi.FactoidTxStatus)
+	req := wsapi.AckRequest{TxID: txID, FullTransaction: fullTransaction}
+	if err := c.Call("factoid-ack", req, resp, true); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// EntryAck returns the status of an entry and its commit, by the commit's ID or the
+// whole commit or entry, hex encoded
+func (c *Client) EntryAck(txID string, fullTransaction string) (*wsapi.EntryStatus, error) {
+	resp := new(wsapi.EntryStatus)
+	req := wsapi.AckRequest{TxID: txID, FullTransaction: fullTransaction}
+	if err := c.Call("entry-ack", req, resp, true); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// Ack returns the status of anything submitted.  The chain ID is "c" for commits, "f"
+// for factoid transactions, or the chain of an entry.
+func (c *Client) Ack(chainID string, hash string, fullTransaction string) (*AckResponse, error) {
+	req := wsapi.EntryAckWithChainRequest{Hash: hash, ChainID: chainID, FullTransaction: fullTransaction}
+	resp := new(AckResponse)
+	if chainID == "f" || chainID == "000000000000000000000000000000000000000000000000000000000000000f" {
+		resp.Factoid = new(wsapi.FactoidTxStatus)
+		if err := c.Call("ack", req, resp.Factoid, true); err != nil {
+			return nil, err
+		}
+		return resp, nil
+	}
+	resp.Entry = new(wsapi.EntryStatus)
+	if err := c.Call("ack", req, resp.Entry, true); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// PendingEntries returns the entries not yet in a saved block.  The chain ID given
+// is ignored by the node.
+func (c *Client) PendingEntries(chainID string) ([]PendingEntry, error) {
+	var resp []PendingEntry
+	if err := c.Call("pending-entries", wsapi.ChainIDRequest{ChainID: chainID}, &resp, true); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// PendingPool returns the commits in the mempool and the reveals held, waiting to go in
+// a block
+func (c *Client) PendingPool() (*interfaces.PendingPool, error) {
+	resp := new(interfaces.PendingPool)
+	if err := c.Call("pending-pool", nil, resp, true); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// PendingTransactions returns the factoid transactions not yet in a saved block, only
+// those involving address if it isn't empty
+func (c *Client) PendingTransactions(address string) ([]PendingTransaction, error) {
+	var resp []PendingTransaction
+	if err := c.Call("pending-transactions", wsapi.AddressRequest{Address: address}, &resp, true); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+/*********************************************************************/
+// Blocks
+
+func (c *Client) DirectoryBlockHead() (*wsapi.DirectoryBlockHeadResponse, error) {
+	resp := new(wsapi.DirectoryBlockHeadResponse)
+	if err := c.Call("directory-block-head", nil, resp, true); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Client) DirectoryBlock(keyMR string) (*wsapi.DirectoryBlockResponse, error) {
+	resp := new(wsapi.DirectoryBlockResponse)
+	if err := c.Call("directory-block", wsapi.KeyMRRequest{KeyMR: keyMR}, resp, true); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Client) EntryBlock(keyMR string) (*wsapi.EntryBlockResponse, error) {
+	resp := new(wsapi.EntryBlockResponse)
+	if err := c.Call("entry-block", wsapi.KeyMRRequest{KeyMR: keyMR}, resp, true); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Client) AdminBlock(keyMR string) (*BlockResponse, error) {
+	resp := new(BlockResponse)
+	if err := c.Call("admin-block", wsapi.KeyMRRequest{KeyMR: keyMR}, resp, true); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Client) FactoidBlock(keyMR string) (*BlockResponse, error) {
+	resp := new(BlockResponse)
+	if err := c.Call("factoid-block", wsapi.KeyMRRequest{KeyMR: keyMR}, resp, true); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Client) EntryCreditBlock(keyMR string) (*BlockResponse, error) {
+	resp := new(BlockResponse)
+	if err := c.Call("entrycredit-block", wsapi.KeyMRRequest{KeyMR: keyMR}, resp, true); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Client) DBlockByHeight(height int64) (*BlockResponse, error) {
+	resp := new(BlockResponse)
+	if err := c.Call("dblock-by-height", wsapi.HeightRequest{Height: height}, resp, true); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Client) ABlockByHeight(height int64) (*BlockResponse, error) {
+	resp := new(BlockResponse)
+	if err := c.Call("ablock-by-height", wsapi.HeightRequest{Height: height}, resp, true); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Client) FBlockByHeight(height int64) (*BlockResponse, error) {
+	resp := new(BlockResponse)
+	if err := c.Call("fblock-by-height", wsapi.HeightRequest{Height: height}, resp, true); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Client) ECBlockByHeight(height int64) (*BlockResponse, error) {
+	resp := new(BlockResponse)
+	if err := c.Call("ecblock-by-height", wsapi.HeightRequest{Height: height}, resp, true); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+/*********************************************************************/
+// Chains and entries
+
+func (c *Client) ChainHead(chainID string) (*wsapi.ChainHeadResponse, error) {
+	resp := new(wsapi.ChainHeadResponse)
+	if err := c.Call("chain-head", wsapi.ChainIDRequest{ChainID: chainID}, resp, true); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Client) Entry(hash string) (*wsapi.EntryResponse, error) {
+	resp := new(wsapi.EntryResponse)
+	if err := c.Call("entry", wsapi.HashRequest{Hash: hash}, resp, true); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// ChainEntries returns one page of the entries of a chain saved in the directory blocks
+// from and to, inclusive.  See EachChainEntry to get them all.
+func (c *Client) ChainEntries(chainID string, from uint32, to uint32) (*wsapi.ChainEntriesResponse, error) {
+	resp := new(wsapi.ChainEntriesResponse)
+	req := wsapi.ChainEntriesRequest{ChainID: chainID, From: from, To: to}
+	if err := c.Call("chain-entries", req, resp, true); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// RawData returns any block, entry or transaction by its hash, hex encoded
+func (c *Client) RawData(hash string) (*wsapi.RawDataResponse, error) {
+	resp := new(wsapi.RawDataResponse)
+	if err := c.Call("raw-data", wsapi.HashRequest{Hash: hash}, resp, true); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// Receipt returns the proof that an entry is in its entry block and directory block, and
+// the transactions anchoring the directory block.  With includeRawEntry, the entry itself
+// is included so its hash can be checked too.
+func (c *Client) Receipt(hash string, includeRawEntry bool) (*wsapi.ReceiptResponse, error) {
+	resp := new(wsapi.ReceiptResponse)
+	if err := c.Call("receipt", wsapi.ReceiptRequest{Hash: hash, IncludeRawEntry: includeRawEntry}, resp, true); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// EthereumReceipt returns an entry's receipt along with where its directory block was
+// anchored into Ethereum
+func (c *Client) EthereumReceipt(hash string) (*wsapi.EthereumReceiptResponse, error) {
+	resp := new(wsapi.EthereumReceiptResponse)
+	if err := c.Call("ethereum-receipt", wsapi.HashRequest{Hash: hash}, resp, true); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// EthereumAnchor returns where the directory block at a height was anchored into Ethereum
+func (c *Client) EthereumAnchor(height int64) (*wsapi.EthereumAnchorResponse, error) {
+	resp := new(wsapi.EthereumAnchorResponse)
+	if err := c.Call("ethereum-anchor", wsapi.HeightRequest{Height: height}, resp, true); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// ReceiptSubscribe asks the node to push the receipt of an entry once its anchor has the
+// given number of Bitcoin confirmations, 0 for the default.  It isn't retried, as each
+// call makes another subscription.
+func (c *Client) ReceiptSubscribe(hash string, confirmations int, webhook string) (*interfaces.ReceiptSubscription, error) {
+	resp := new(interfaces.ReceiptSubscription)
+	req := wsapi.ReceiptSubscribeRequest{Hash: hash, Confirmations: confirmations, Webhook: webhook}
+	if err := c.Call("receipt-subscribe", req, resp, false); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Client) ReceiptUnsubscribe(id string) (*wsapi.ReceiptUnsubscribeResponse, error) {
+	resp := new(wsapi.ReceiptUnsubscribeResponse)
+	if err := c.Call("receipt-unsubscribe", wsapi.ReceiptUnsubscribeRequest{ID: id}, resp, true); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// ObjectPut stores data under a hash key in a namespace of the node's object store
+func (c *Client) ObjectPut(namespace, key string, data []byte) error {
+	req := wsapi.ObjectRequest{Namespace: namespace, Key: key, Data: hex.EncodeToString(data)}
+	return c.Call("object-put", req, new(wsapi.ObjectResponse), true)
+}
+
+// ObjectGet returns the data under a hash key in a namespace of the node's object store
+func (c *Client) ObjectGet(namespace, key string) ([]byte, error) {
+	resp := new(wsapi.ObjectResponse)
+	if err := c.Call("object-get", wsapi.ObjectRequest{Namespace: namespace, Key: key}, resp, true); err != nil {
+		return nil, err
+	}
+	return hex.DecodeString(resp.Data)
+}
+
+// ObjectDelete removes a hash key from a namespace of the node's object store
+func (c *Client) ObjectDelete(namespace, key string) error {
+	return c.Call("object-delete", wsapi.ObjectRequest{Namespace: namespace, Key: key}, new(wsapi.ObjectResponse), true)
+}
+
+// ObjectList returns the keys in a namespace of the node's object store
+func (c *Client) ObjectList(namespace string) ([]string, error) {
+	resp := new(wsapi.ObjectListResponse)
+	if err := c.Call("object-list", wsapi.ObjectRequest{Namespace: namespace}, resp, true); err != nil {
+		return nil, err
+	}
+	return resp.Keys, nil
+}
+
+// References lists the saved entries referring to an entry hash or chain ID, if the node
+// indexes references
+func (c *Client) References(hash string) (*ReferencesResponse, error) {
+	resp := new(ReferencesResponse)
+	if err := c.Call("references", wsapi.HashRequest{Hash: hash}, resp, true); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Client) Transaction(hash string) (*TransactionResponse, error) {
+	resp := new(TransactionResponse)
+	if err := c.Call("transaction", wsapi.HashRequest{Hash: hash}, resp, true); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// TransactionsByAddress returns up to limit of the factoid transactions of a factoid or
+// entry credit address, oldest first from the offset'th, if the node indexes addresses.
+// See EachAddressTransaction to get them all.
+func (c *Client) TransactionsByAddress(address string, offset int, limit int) (*AddressTransactionsResponse, error) {
+	resp := new(AddressTransactionsResponse)
+	req := wsapi.AddressTransactionsRequest{Address: address, Offset: offset, Limit: limit}
+	if err := c.Call("transactions-by-address", req, resp, true); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// EntriesByExtID returns up to limit of the entries of a chain with an external ID
+// starting with prefix, given in hex, from the offset'th, if the node indexes external IDs
+func (c *Client) EntriesByExtID(chainID string, prefix string, offset int, limit int) (*ExtIDEntriesResponse, error) {
+	resp := new(ExtIDEntriesResponse)
+	req := wsapi.ExtIDEntriesRequest{ChainID: chainID, Prefix: prefix, Offset: offset, Limit: limit}
+	if err := c.Call("entries-by-extid", req, resp, true); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+/*********************************************************************/
+// Balances and rates
+
+func (c *Client) EntryCreditBalance(address string) (*wsapi.EntryCreditBalanceResponse, error) {
+	resp := new(wsapi.EntryCreditBalanceResponse)
+	if err := c.Call("entry-credit-balance", wsapi.AddressRequest{Address: address}, resp, true); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Client) FactoidBalance(address string) (*wsapi.FactoidBalanceResponse, error) {
+	resp := new(wsapi.FactoidBalanceResponse)
+	if err := c.Call("factoid-balance", wsapi.AddressRequest{Address: address}, resp, true); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Client) EntryCreditRate() (*wsapi.EntryCreditRateResponse, error) {
+	resp := new(wsapi.EntryCreditRateResponse)
+	if err := c.Call("entry-credit-rate", nil, resp, true); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Client) EntryCreditRateHistory() (*wsapi.EntryCreditRateHistoryResponse, error) {
+	resp := new(wsapi.EntryCreditRateHistoryResponse)
+	if err := c.Call("entry-credit-rate-history", nil, resp, true); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// FctSupply returns the factoid supply, with the movements of the last blocks blocks,
+// or of the node's default if blocks is 0
+func (c *Client) FctSupply(blocks int) (*interfaces.SupplyStatus, error) {
+	resp := new(interfaces.SupplyStatus)
+	if err := c.Call("fct-supply", wsapi.SupplyRequest{Blocks: blocks}, resp, true); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+/*********************************************************************/
+// The node
+
+func (c *Client) Heights() (*wsapi.HeightsResponse, error) {
+	resp := new(wsapi.HeightsResponse)
+	if err := c.Call("heights", nil, resp, true); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Client) CurrentMinute() (*wsapi.CurrentMinuteResponse, error) {
+	resp := new(wsapi.CurrentMinuteResponse)
+	if err := c.Call("current-minute", nil, resp, true); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Client) Properties() (*wsapi.PropertiesResponse, error) {
+	resp := new(wsapi.PropertiesResponse)
+	if err := c.Call("properties", nil, resp, true); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Client) StartupProgress() (*interfaces.StartupStatus, error) {
+	resp := new(interfaces.StartupStatus)
+	if err := c.Call("startup-progress", nil, resp, true); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Client) NetworkParameters() (*interfaces.NetworkParameters, error) {
+	resp := new(interfaces.NetworkParameters)
+	if err := c.Call("network-parameters", nil, resp, true); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Client) TimingAnomalies() (*interfaces.TimingAnomalyReport, error) {
+	resp := new(interfaces.TimingAnomalyReport)
+	if err := c.Call("timing-anomalies", nil, resp, true); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Client) Capacity() (*interfaces.CapacityReport, error) {
+	resp := new(interfaces.CapacityReport)
+	if err := c.Call("capacity", nil, resp, true); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Client) CoinbaseAudit() (*interfaces.CoinbaseAuditReport, error) {
+	resp := new(interfaces.CoinbaseAuditReport)
+	if err := c.Call("coinbase-audit", nil, resp, true); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Client) TransactionRate() (*wsapi.TransactionRateResponse, error) {
+	resp := new(wsapi.TransactionRateResponse)
+	if err := c.Call("tps-rate", nil, resp, true); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Client) Authorities() (*AuthoritiesResponse, error) {
+	resp := new(AuthoritiesResponse)
+	if err := c.Call("authorities", nil, resp, true); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// AuthorityStats returns the signature statistics the node has gathered for each
+// authority identity it has seen messages from
+func (c *Client) AuthorityStats() (*AuthorityStatsResponse, error) {
+	resp := new(AuthorityStatsResponse)
+	if err := c.Call("authority-stats", nil, resp, true); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
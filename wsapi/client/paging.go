@@ -0,0 +1,117 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"errors"
+
+	"github.com/FactomProject/factomd/wsapi"
+)
+
+// The v2 API returns long results a page at a time, or one block at a time.  These
+// helpers make the calls in turn, handing each result to a function until it returns an
+// error.  Returning Stop ends the walk without an error.
+
+// Stop is returned by the function passed to a walk to end it early
+var Stop = errors.New("stop")
+
+const zeroKeyMR = "0000000000000000000000000000000000000000000000000000000000000000"
+
+// EachChainEntry calls fn with each entry of a chain saved in the directory blocks from
+// and to, inclusive, in chain order.  To of 0 is up to the highest saved block.
+func (c *Client) EachChainEntry(chainID string, from uint32, to uint32, fn func(wsapi.EntryPosition) error) error {
+	if to == 0 {
+		heights, err := c.Heights()
+		if err != nil {
+			return err
+		}
+		to = uint32(heights.DirectoryBlockHeight)
+	}
+	for {
+		page, err := c.ChainEntries(chainID, from, to)
+		if err != nil {
+			return err
+		}
+		for _, e := range page.Entries {
+			if err := fn(e); err != nil {
+				return stopped(err)
+			}
+		}
+		// NextHeight is only set when the page was cut short
+		if page.NextHeight == 0 || page.NextHeight <= from {
+			return nil
+		}
+		from = page.NextHeight
+	}
+}
+
+// EachAddressTransaction calls fn with each factoid transaction of an address, oldest
+// first
+func (c *Client) EachAddressTransaction(address string, fn func(AddressTransaction) error) error {
+	for offset := 0; ; {
+		page, err := c.TransactionsByAddress(address, offset, 0)
+		if err != nil {
+			return err
+		}
+		for _, tx := range page.Transactions {
+			if err := fn(tx); err != nil {
+				return stopped(err)
+			}
+		}
+		offset += len(page.Transactions)
+		if len(page.Transactions) == 0 || offset >= page.Total {
+			return nil
+		}
+	}
+}
+
+// EachEntryBlock calls fn with each entry block of a chain, newest first, starting from
+// its head
+func (c *Client) EachEntryBlock(chainID string, fn func(keyMR string, block *wsapi.EntryBlockResponse) error) error {
+	head, err := c.ChainHead(chainID)
+	if err != nil {
+		return err
+	}
+	for keyMR := head.ChainHead; keyMR != "" && keyMR != zeroKeyMR; {
+		block, err := c.EntryBlock(keyMR)
+		if err != nil {
+			return err
+		}
+		if err := fn(keyMR, block); err != nil {
+			return stopped(err)
+		}
+		keyMR = block.Header.PrevKeyMR
+	}
+	return nil
+}
+
+// EachDirectoryBlock calls fn with each directory block from and to, inclusive.  To of
+// 0 is up to the highest saved block.
+func (c *Client) EachDirectoryBlock(from uint32, to uint32, fn func(height uint32, block *BlockResponse) error) error {
+	if to == 0 {
+		heights, err := c.Heights()
+		if err != nil {
+			return err
+		}
+		to = uint32(heights.DirectoryBlockHeight)
+	}
+	for h := from; h <= to; h++ {
+		block, err := c.DBlockByHeight(int64(h))
+		if err != nil {
+			return err
+		}
+		if err := fn(h, block); err != nil {
+			return stopped(err)
+		}
+	}
+	return nil
+}
+
+func stopped(err error) error {
+	if err == Stop {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,120 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/wsapi"
+)
+
+// The server's responses that hold interfaces can't be decoded into its own types, so
+// these take their place.  Blocks and transactions are left as JSON; the RawData of a
+// block can be unmarshalled into the block types of factomd itself.
+
+// BlockResponse is a block as returned by the *-by-height methods, and by admin-block,
+// factoid-block and entrycredit-block.  Only the field for the kind of block asked for
+// is set.
+type BlockResponse struct {
+	DBlock  json.RawMessage `json:"dblock,omitempty"`
+	ABlock  json.RawMessage `json:"ablock,omitempty"`
+	FBlock  json.RawMessage `json:"fblock,omitempty"`
+	ECBlock json.RawMessage `json:"ecblock,omitempty"`
+	RawData string          `json:"rawdata,omitempty"`
+}
+
+// Raw returns the binary block
+func (b *BlockResponse) Raw() ([]byte, error) {
+	return hex.DecodeString(b.RawData)
+}
+
+type TransactionResponse struct {
+	ECTransaction      json.RawMessage `json:"ectransaction,omitempty"`
+	FactoidTransaction json.RawMessage `json:"factoidtransaction,omitempty"`
+	Entry              json.RawMessage `json:"entry,omitempty"`
+
+	IncludedInTransactionBlock     string `json:"includedintransactionblock"`
+	IncludedInDirectoryBlock       string `json:"includedindirectoryblock"`
+	IncludedInDirectoryBlockHeight int64  `json:"includedindirectoryblockheight"`
+}
+
+type PendingEntry struct {
+	EntryHash string `json:"entryhash"`
+	ChainID   string `json:"chainid"`
+	Status    string `json:"status"`
+}
+
+type EntryReference struct {
+	EntryHash string `json:"entryhash"`
+	ChainID   string `json:"chainid"`
+}
+
+type ReferencesResponse struct {
+	Hash       string           `json:"hash"`
+	References []EntryReference `json:"references"`
+}
+
+type AddressTransaction struct {
+	TxID   string `json:"txid"`
+	Height uint32 `json:"height"`
+	Index  uint32 `json:"index"`
+}
+
+type AddressTransactionsResponse struct {
+	Address      string               `json:"address"`
+	Total        int                  `json:"total"`
+	Transactions []AddressTransaction `json:"transactions"`
+}
+
+type ExtIDMatch struct {
+	EntryHash string `json:"entryhash"`
+	ExtID     string `json:"extid"`
+}
+
+type ExtIDEntriesResponse struct {
+	ChainID string       `json:"chainid"`
+	Prefix  string       `json:"prefix"`
+	Total   int          `json:"total"`
+	Entries []ExtIDMatch `json:"entries"`
+}
+
+type TransAddress struct {
+	Amount      uint64 `json:"amount"`
+	Address     string `json:"address"`
+	UserAddress string `json:"useraddress"`
+}
+
+type PendingTransaction struct {
+	TransactionID string         `json:"transactionid"`
+	Status        string         `json:"status"`
+	Inputs        []TransAddress `json:"inputs"`
+	Outputs       []TransAddress `json:"outputs"`
+	ECOutputs     []TransAddress `json:"ecoutputs"`
+	Fees          uint64         `json:"fees"`
+}
+
+type AuthoritiesResponse struct {
+	Authorities []json.RawMessage `json:"Authorities"`
+}
+
+type AuthorityStatsResponse struct {
+	Authorities []interfaces.AuthorityStats `json:"authorities"`
+}
+
+// BackupDatabaseResponse is where a backup was written, and the directory block height
+// it ends at
+type BackupDatabaseResponse struct {
+	Path     string `json:"path"`
+	DBHeight uint32 `json:"dbheight"`
+}
+
+// AckResponse is the answer to an ack.  Factoid transactions have the status of a
+// factoid-ack, everything else the status of an entry-ack.
+type AckResponse struct {
+	Entry   *wsapi.EntryStatus
+	Factoid *wsapi.FactoidTxStatus
+}
@@ -0,0 +1,114 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package wsapi
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/web"
+)
+
+// The cluster cache: a node run with ClusterCacheServe hands its saved directory block
+// states and entries, marshalled as they go between peers, to the other followers of the
+// operator.  They validate what they copy as if a peer had sent it.
+
+// clusterState returns the state of the request if it may be served from the cluster cache,
+// having written the error response if not
+func clusterState(ctx *web.Context) interfaces.IState {
+	ServersMutex.Lock()
+	state := ctx.Server.Env["state"].(interfaces.IState)
+	ServersMutex.Unlock()
+
+	if err := checkAuthHeader(state, ctx.Request); err != nil {
+		ctx.ResponseWriter.Header().Add("WWW-Authenticate", `Basic realm="factomd RPC"`)
+		http.Error(ctx.ResponseWriter, "401 Unauthorized.", http.StatusUnauthorized)
+		return nil
+	}
+	if !state.IsClusterCacheServer() {
+		http.Error(ctx.ResponseWriter, "Not a cluster cache", http.StatusNotFound)
+		return nil
+	}
+	return state
+}
+
+func writeClusterBinary(ctx *web.Context, data []byte) {
+	ctx.ResponseWriter.Header().Set("Content-Type", "application/octet-stream")
+	ctx.ResponseWriter.Write(data)
+}
+
+// loadClusterDBState builds the directory block state of a saved height.  LoadDBState
+// panics if the database doesn't link up, which must not take the API down.
+func loadClusterDBState(state interfaces.IState, dbheight uint32) (msg interfaces.IMsg, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	return state.LoadDBState(dbheight)
+}
+
+func HandleClusterDBState(ctx *web.Context, height string) {
+	state := clusterState(ctx)
+	if state == nil {
+		return
+	}
+	dbheight, err := strconv.ParseUint(height, 10, 32)
+	if err != nil {
+		http.Error(ctx.ResponseWriter, "Invalid height", http.StatusBadRequest)
+		return
+	}
+	if uint32(dbheight) > state.GetHighestSavedBlk() {
+		http.Error(ctx.ResponseWriter, "Height not saved", http.StatusNotFound)
+		return
+	}
+	msg, err := loadClusterDBState(state, uint32(dbheight))
+	if err != nil {
+		http.Error(ctx.ResponseWriter, "Internal error", http.StatusInternalServerError)
+		return
+	}
+	if msg == nil {
+		http.Error(ctx.ResponseWriter, "Height not saved", http.StatusNotFound)
+		return
+	}
+	data, err := msg.MarshalBinary()
+	if err != nil {
+		http.Error(ctx.ResponseWriter, "Internal error", http.StatusInternalServerError)
+		return
+	}
+	writeClusterBinary(ctx, data)
+}
+
+func HandleClusterEntry(ctx *web.Context, hashkey string) {
+	state := clusterState(ctx)
+	if state == nil {
+		return
+	}
+	h, err := primitives.HexToHash(hashkey)
+	if err != nil {
+		http.Error(ctx.ResponseWriter, "Invalid entry hash", http.StatusBadRequest)
+		return
+	}
+	dbase := state.GetAndLockDB()
+	entry, err := dbase.FetchEntry(h)
+	state.UnlockDB()
+	if err != nil {
+		http.Error(ctx.ResponseWriter, "Internal error", http.StatusInternalServerError)
+		return
+	}
+	if entry == nil {
+		http.Error(ctx.ResponseWriter, "Entry not found", http.StatusNotFound)
+		return
+	}
+	data, err := entry.MarshalBinary()
+	if err != nil {
+		http.Error(ctx.ResponseWriter, "Internal error", http.StatusInternalServerError)
+		return
+	}
+	writeClusterBinary(ctx, data)
+}
@@ -0,0 +1,204 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package wsapi
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/FactomProject/factomd/common/entryBlock"
+	"github.com/FactomProject/factomd/common/entryCreditBlock"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// Chain creation with its first entries.  An application bootstrapping a chain would
+// otherwise commit and reveal the chain, then commit and reveal each entry, and clean up
+// after whichever step failed.  create-chain takes the signed commits and the entries
+// together and checks all of them, and that their entry credits are there to pay for
+// them, before it submits any.  The commits and reveals then go out in order; reveals of
+// entries wait on the node until the chain is made.  The node never sees a private key.
+
+// MaxCreateChainEntries is the most entries create-chain takes after the first
+const MaxCreateChainEntries = 100
+
+// createChainSubmission is a commit and its reveal, checked and ready to submit
+type createChainSubmission struct {
+	commit interfaces.IMsg
+	reveal *messages.RevealEntryMsg
+	txid   string
+}
+
+func HandleV2CreateChain(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	n := time.Now()
+	defer func() { HandleV2APICallCreateChain.Observe(float64(time.Since(n).Nanoseconds())) }()
+
+	req := new(CreateChainRequest)
+	if err := MapToObject(params, req); err != nil {
+		return nil, NewInvalidParamsError()
+	}
+	if req.IdempotencyKey != "" {
+		parts := []string{req.Commit, req.Reveal}
+		for _, e := range req.Entries {
+			parts = append(parts, e.Commit, e.Reveal)
+		}
+		return submitIdempotent(state, "create-chain", req.IdempotencyKey, strings.Join(parts, "/"),
+			func() (interface{}, *primitives.JSONError) { return createChain(state, req) })
+	}
+	return createChain(state, req)
+}
+
+func createChain(state interfaces.IState, req *CreateChainRequest) (interface{}, *primitives.JSONError) {
+	subs, jsonError := checkCreateChain(state, req)
+	if jsonError != nil {
+		return nil, jsonError
+	}
+
+	// The whole chain has to fit in the API queue, or it would be cut off part way
+	status := state.GetAPIQueueStatus()
+	if status.Status == interfaces.APISubmitRejected || status.Overloaded {
+		if status.Overloaded && !status.Syncing {
+			return nil, NewNodeOverloadedError(&status)
+		}
+		return nil, NewSubmissionRejectedError(&status)
+	}
+	if status.QueueCap-status.QueueDepth < 2*len(subs) {
+		status.Status = interfaces.APISubmitRejected
+		return nil, NewSubmissionRejectedError(&status)
+	}
+
+	resp := new(CreateChainResponse)
+	resp.ChainID = subs[0].reveal.Entry.GetChainID().String()
+	for _, sub := range subs {
+		submission, jsonError := submitAPIMsg(state, sub.commit)
+		if jsonError != nil {
+			return nil, createChainStopped(resp, jsonError)
+		}
+		if _, ok := sub.commit.(*messages.CommitChainMsg); ok {
+			state.IncECCommits()
+			resp.Submission = submission
+		} else {
+			state.IncECommits()
+		}
+		sub.reveal.Timestamp = state.GetTimestamp()
+		if _, jsonError := submitAPIMsg(state, sub.reveal); jsonError != nil {
+			return nil, createChainStopped(resp, jsonError)
+		}
+		resp.Entries = append(resp.Entries, CreateChainEntryResult{TxID: sub.txid, EntryHash: sub.reveal.Entry.GetHash().String()})
+	}
+	resp.Message = "Chain Creation Success"
+	return resp, nil
+}
+
+// createChainStopped returns the error that stopped the submissions part way, with the
+// entries submitted before it, so the client knows which to finish
+func createChainStopped(resp *CreateChainResponse, jsonError *primitives.JSONError) *primitives.JSONError {
+	if len(resp.Entries) == 0 {
+		return jsonError
+	}
+	resp.Message = fmt.Sprintf("Chain creation stopped after %d entries: %s", len(resp.Entries), jsonError.Message)
+	return primitives.NewJSONError(jsonError.Code, jsonError.Message, resp)
+}
+
+// checkCreateChain decodes and checks the commits and entries of the request, and that
+// the entry credit addresses paying for them hold enough, before any is submitted
+func checkCreateChain(state interfaces.IState, req *CreateChainRequest) ([]createChainSubmission, *primitives.JSONError) {
+	if len(req.Entries) > MaxCreateChainEntries {
+		return nil, NewCustomInvalidParamsError(fmt.Sprintf("A chain may be created with at most %d entries after the first", MaxCreateChainEntries))
+	}
+
+	commitChain := entryCreditBlock.NewCommitChain()
+	if p, err := hex.DecodeString(req.Commit); err != nil {
+		return nil, NewInvalidCommitChainError()
+	} else if _, err := commitChain.UnmarshalBinaryData(p); err != nil || !commitChain.IsValid() {
+		return nil, NewInvalidCommitChainError()
+	}
+	first, jsonError := decodeCreateChainEntry(req.Reveal, "first entry")
+	if jsonError != nil {
+		return nil, jsonError
+	}
+	if !first.GetHash().IsSameAs(commitChain.EntryHash) {
+		return nil, NewCustomInvalidParamsError("The chain commit is not for the first entry")
+	}
+	if !primitives.Shad(first.GetChainID().Bytes()).IsSameAs(commitChain.ChainIDHash) {
+		return nil, NewCustomInvalidParamsError("The chain commit is not for the chain of the first entry")
+	}
+	if first.KSize() > 10 || int(commitChain.Credits) < first.KSize()+10 {
+		return nil, NewCustomInvalidParamsError("The chain commit doesn't pay for the chain and its first entry")
+	}
+
+	chainCommit := new(messages.CommitChainMsg)
+	chainCommit.CommitChain = commitChain
+	firstReveal := new(messages.RevealEntryMsg)
+	firstReveal.Entry = first
+	subs := []createChainSubmission{{commit: chainCommit, reveal: firstReveal, txid: commitChain.GetSigHash().String()}}
+
+	credits := map[[32]byte]uint64{commitChain.ECPubKey.Fixed(): uint64(commitChain.Credits)}
+	seen := map[[32]byte]bool{first.GetHash().Fixed(): true}
+	for i, e := range req.Entries {
+		name := fmt.Sprintf("entry %d", i+1)
+		commit := entryCreditBlock.NewCommitEntry()
+		if p, err := hex.DecodeString(e.Commit); err != nil {
+			return nil, NewCustomInvalidParamsError("Invalid commit of " + name)
+		} else if _, err := commit.UnmarshalBinaryData(p); err != nil || !commit.IsValid() {
+			return nil, NewCustomInvalidParamsError("Invalid commit of " + name)
+		}
+		entry, jsonError := decodeCreateChainEntry(e.Reveal, name)
+		if jsonError != nil {
+			return nil, jsonError
+		}
+		switch {
+		case !entry.GetChainID().IsSameAs(first.GetChainID()):
+			return nil, NewCustomInvalidParamsError(fmt.Sprintf("The %s is not in the chain created", name))
+		case !entry.GetHash().IsSameAs(commit.EntryHash):
+			return nil, NewCustomInvalidParamsError(fmt.Sprintf("The commit of %s is for another entry", name))
+		case seen[entry.GetHash().Fixed()]:
+			return nil, NewCustomInvalidParamsError(fmt.Sprintf("The %s repeats an earlier entry", name))
+		case entry.KSize() > 10 || int(commit.Credits) < entry.KSize():
+			return nil, NewCustomInvalidParamsError(fmt.Sprintf("The commit of %s doesn't pay for it", name))
+		}
+		seen[entry.GetHash().Fixed()] = true
+		credits[commit.ECPubKey.Fixed()] += uint64(commit.Credits)
+
+		entryCommit := new(messages.CommitEntryMsg)
+		entryCommit.CommitEntry = commit
+		reveal := new(messages.RevealEntryMsg)
+		reveal.Entry = entry
+		subs = append(subs, createChainSubmission{commit: entryCommit, reveal: reveal, txid: commit.GetSigHash().String()})
+	}
+
+	for _, sub := range subs {
+		if !state.IsHighestCommit(sub.reveal.Entry.GetHash(), sub.commit) {
+			return nil, NewRepeatCommitError(RepeatedEntryMessage{"A commit with equal or greater payment already exists", sub.reveal.Entry.GetHash().String()})
+		}
+	}
+
+	view := state.GetReadView()
+	for key, spent := range credits {
+		var balance int64
+		if view != nil {
+			balance = view.GetECBalance(key)
+		} else {
+			balance = state.GetFactoidState().GetECBalance(key)
+		}
+		if balance < 0 || uint64(balance) < spent {
+			return nil, NewCustomInvalidParamsError(fmt.Sprintf("Entry credit address %x holds %d credits, the chain needs %d of it", key, balance, spent))
+		}
+	}
+	return subs, nil
+}
+
+func decodeCreateChainEntry(reveal string, name string) (interfaces.IEntry, *primitives.JSONError) {
+	entry := entryBlock.NewEntry()
+	if p, err := hex.DecodeString(reveal); err != nil {
+		return nil, NewCustomInvalidParamsError("Invalid " + name)
+	} else if _, err := entry.UnmarshalBinaryData(p); err != nil || !entry.IsValid() {
+		return nil, NewCustomInvalidParamsError("Invalid " + name)
+	}
+	return entry, nil
+}
@@ -0,0 +1,84 @@
+package wsapi_test
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/FactomProject/factomd/common/entryBlock"
+	"github.com/FactomProject/factomd/common/entryCreditBlock"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/testHelper"
+	. "github.com/FactomProject/factomd/wsapi"
+)
+
+func newCreateChainRequest(entries int) *CreateChainRequest {
+	first := testHelper.CreateFirstTestEntry()
+	commit := entryCreditBlock.NewCommitChain()
+	commit.ChainIDHash = primitives.Shad(first.ChainID.Bytes())
+	weld := primitives.NewZeroHash()
+	weld.SetBytes(primitives.DoubleSha(append(first.GetHash().Bytes(), first.ChainID.Bytes()...)))
+	commit.Weld = weld
+	commit.EntryHash = first.GetHash()
+	commit.Credits = 11
+	testHelper.SignCommit(0, commit)
+
+	req := new(CreateChainRequest)
+	req.Commit = hexOf(commit.MarshalBinary())
+	req.Reveal = hexOf(first.MarshalBinary())
+	for i := 0; i < entries; i++ {
+		entry := entryBlock.NewEntry()
+		entry.ChainID = first.ChainID
+		entry.Content = primitives.ByteSlice{Bytes: []byte(fmt.Sprintf("Entry %d", i))}
+		ce := entryCreditBlock.NewCommitEntry()
+		ce.EntryHash = entry.GetHash()
+		ce.Credits = 1
+		testHelper.SignCommit(0, ce)
+		req.Entries = append(req.Entries, CreateChainEntry{Commit: hexOf(ce.MarshalBinary()), Reveal: hexOf(entry.MarshalBinary())})
+	}
+	return req
+}
+
+func hexOf(data []byte, err error) string {
+	if err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(data)
+}
+
+func TestHandleV2CreateChainChecks(t *testing.T) {
+	state := testHelper.CreateEmptyTestState()
+
+	// The entry credit address holds nothing, so nothing is submitted
+	_, jErr := HandleV2CreateChain(state, newCreateChainRequest(3))
+	if jErr == nil || !strings.Contains(fmt.Sprint(jErr.Data), "holds 0 credits") {
+		t.Errorf("Expected the chain to be refused for want of credits, got %v", jErr)
+	}
+	if state.APIQueue().Length() != 0 {
+		t.Errorf("Expected nothing submitted, %d messages were", state.APIQueue().Length())
+	}
+
+	other := newCreateChainRequest(2)
+	other.Entries[1].Reveal = newCreateChainRequest(3).Entries[2].Reveal
+	if _, jErr := HandleV2CreateChain(state, other); jErr == nil || !strings.Contains(fmt.Sprint(jErr.Data), "for another entry") {
+		t.Errorf("Expected an entry not matching its commit to be refused, got %v", jErr)
+	}
+
+	repeat := newCreateChainRequest(2)
+	repeat.Entries[1] = repeat.Entries[0]
+	if _, jErr := HandleV2CreateChain(state, repeat); jErr == nil || !strings.Contains(fmt.Sprint(jErr.Data), "repeats") {
+		t.Errorf("Expected a repeated entry to be refused, got %v", jErr)
+	}
+
+	tooMany := newCreateChainRequest(MaxCreateChainEntries + 1)
+	if _, jErr := HandleV2CreateChain(state, tooMany); jErr == nil {
+		t.Error("Expected a chain with too many entries to be refused")
+	}
+
+	bad := newCreateChainRequest(0)
+	bad.Commit = bad.Commit[:len(bad.Commit)-2] + "00"
+	if _, jErr := HandleV2CreateChain(state, bad); jErr == nil {
+		t.Error("Expected a chain commit with a bad signature to be refused")
+	}
+}
@@ -5,13 +5,16 @@
 package wsapi
 
 import (
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
 	"github.com/FactomProject/factomd/common/primitives"
 	"github.com/FactomProject/factomd/util"
 	"github.com/FactomProject/web"
@@ -22,7 +25,7 @@ func HandleDebug(ctx *web.Context) {
 	state := ctx.Server.Env["state"].(interfaces.IState)
 	ServersMutex.Unlock()
 
-	if err := checkAuthHeader(state, ctx.Request); err != nil {
+	if _, err := authorize(state, ctx.Request, PermissionDebug); err != nil {
 		remoteIP := ""
 		remoteIP += strings.Split(ctx.Request.RemoteAddr, ":")[0]
 		fmt.Printf(
@@ -81,6 +84,12 @@ func HandleDebugRequest(
 	case "authorities":
 		resp, jsonError = HandleAuthorities(state, params)
 		break
+	case "authority-stats":
+		resp, jsonError = HandleAuthorityStats(state, params)
+		break
+	case "backup-database":
+		resp, jsonError = HandleBackupDatabase(state, params)
+		break
 	case "configuration":
 		resp, jsonError = HandleConfig(state, params)
 		break
@@ -99,18 +108,66 @@ func HandleDebugRequest(
 	case "set-drop-rate":
 		resp, jsonError = HandleSetDropRate(state, params)
 		break
+	case "message-delays":
+		resp, jsonError = HandleMessageDelays(state, params)
+		break
+	case "set-message-delay":
+		resp, jsonError = HandleSetMessageDelay(state, params)
+		break
+	case "send-outs":
+		resp, jsonError = HandleSendOuts(state, params)
+		break
 	case "federated-servers":
 		resp, jsonError = HandleFedServers(state, params)
 		break
 	case "holding-queue":
 		resp, jsonError = HandleHoldingQueue(state, params)
 		break
+	case "holding-review":
+		resp, jsonError = HandleHoldingReview(state, params)
+		break
+	case "review-holding":
+		resp, jsonError = HandleReviewHolding(state, params)
+		break
+	case "inject-message":
+		resp, jsonError = HandleInjectMessage(state, params)
+		break
 	case "messages":
 		resp, jsonError = HandleMessages(state, params)
 		break
+	case "slow-rounds":
+		resp, jsonError = HandleSlowRounds(state, params)
+		break
+	case "sync-state":
+		resp, jsonError = HandleSyncState(state, params)
+		break
+	case "alerts":
+		resp, jsonError = HandleAlerts(state, params)
+		break
+	case "receipt-subscriptions":
+		resp, jsonError = HandleReceiptSubscriptions(state, params)
+		break
+	case "anchor-check":
+		resp, jsonError = HandleAnchorCheck(state, params)
+		break
+	case "jobs":
+		resp, jsonError = HandleJobs(state, params)
+		break
+	case "run-job":
+		resp, jsonError = HandleRunJob(state, params)
+		break
+	case "enable-job":
+		resp, jsonError = HandleEnableJob(state, params)
+		break
 	case "network-info":
 		resp, jsonError = HandleNetworkInfo(state, params)
 		break
+	case "pin-chain":
+		resp, jsonError = HandlePinChain(state, params)
+		break
+	case "pinned-chains":
+		resp, jsonError = HandlePinnedChains(state, params)
+		break
 	case "summary":
 		resp, jsonError = HandleSummary(state, params)
 		break
@@ -151,7 +208,11 @@ func HandleAuditServers(
 	}
 	r := new(ret)
 
-	r.AuditServers = state.GetAuditServers(state.GetLeaderHeight())
+	if view := state.GetReadView(); view != nil {
+		r.AuditServers = view.GetAuditServers()
+	} else {
+		r.AuditServers = state.GetAuditServers(state.GetLeaderHeight())
+	}
 	return r, nil
 }
 
@@ -167,10 +228,78 @@ func HandleAuthorities(
 	}
 	r := new(ret)
 
-	r.Authorities = state.GetAuthorities()
+	if view := state.GetReadView(); view != nil {
+		r.Authorities = view.GetAuthorities()
+	} else {
+		r.Authorities = state.GetAuthorities()
+	}
+	return r, nil
+}
+
+// HandleAuthorityStats returns the signature statistics gathered for each authority
+// identity we have seen messages from.  It is served on the V2 API as well, as the debug
+// API isn't on MAIN.
+func HandleAuthorityStats(
+	state interfaces.IState,
+	params interface{},
+) (
+	interface{},
+	*primitives.JSONError,
+) {
+	type ret struct {
+		Authorities []interfaces.AuthorityStats `json:"authorities"`
+	}
+	r := new(ret)
+
+	r.Authorities = state.GetAuthorityStats()
+	return r, nil
+}
+
+// HandleBackupDatabase writes a consistent copy of the database to the given path, and
+// reports the directory block height the copy ends at.
+func HandleBackupDatabase(
+	state interfaces.IState,
+	params interface{},
+) (
+	interface{},
+	*primitives.JSONError,
+) {
+	type ret struct {
+		Path     string `json:"path"`
+		DBHeight uint32 `json:"dbheight"`
+	}
+	r := new(ret)
+
+	req := new(BackupDatabaseRequest)
+	err := MapToObject(params, req)
+	if err != nil || req.Path == "" {
+		return nil, NewInvalidParamsError()
+	}
+
+	height, err := state.BackupDatabase(req.Path)
+	if err != nil {
+		return nil, NewCustomInternalError(err.Error())
+	}
+	r.Path = req.Path
+	r.DBHeight = height
 	return r, nil
 }
 
+// HandleV2BackupDatabase is backup-database on the V2 API, for clients with the admin
+// permission.  A node anyone may call is not backed up on request.
+func HandleV2BackupDatabase(
+	state interfaces.IState,
+	params interface{},
+) (
+	interface{},
+	*primitives.JSONError,
+) {
+	if !authenticationConfigured(state) {
+		return nil, NewCustomInvalidRequestError("backup-database needs FactomdRpcUser and FactomdRpcPass, or an identity provider, to be set")
+	}
+	return HandleBackupDatabase(state, params)
+}
+
 func HandleConfig(
 	state interfaces.IState,
 	params interface{},
@@ -276,6 +405,95 @@ func HandleSetDropRate(
 	return r, nil
 }
 
+// HandleMessageDelays lists the delays injected into messages from peers, by type
+func HandleMessageDelays(
+	state interfaces.IState,
+	params interface{},
+) (
+	interface{},
+	*primitives.JSONError,
+) {
+	type ret struct {
+		Delays []interfaces.MessageDelay `json:"delays"`
+	}
+	r := new(ret)
+	r.Delays = state.GetMessageDelays()
+	return r, nil
+}
+
+// HandleSetMessageDelay holds messages of a type from peers for a fixed or random time
+// before they are processed.  A max of 0 clears the delay.
+func HandleSetMessageDelay(
+	state interfaces.IState,
+	params interface{},
+) (
+	interface{},
+	*primitives.JSONError,
+) {
+	req := new(SetMessageDelayRequest)
+	err := MapToObject(params, req)
+	if err != nil || req.Type < 0 || req.Type > 255 || req.Min < 0 || req.Max < 0 {
+		return nil, NewInvalidParamsError()
+	}
+	state.SetMessageDelay(byte(req.Type), time.Duration(req.Min)*time.Millisecond, time.Duration(req.Max)*time.Millisecond)
+	return HandleMessageDelays(state, params)
+}
+
+// HandleSendOuts lists the calls to SendOut by message type: the messages broadcast, and
+// the duplicate calls that were sent again or suppressed
+func HandleSendOuts(
+	state interfaces.IState,
+	params interface{},
+) (
+	interface{},
+	*primitives.JSONError,
+) {
+	type ret struct {
+		SendOuts []interfaces.SendOutStats `json:"sendouts"`
+	}
+	r := new(ret)
+	r.SendOuts = state.GetSendOutStats()
+	return r, nil
+}
+
+// HandleInjectMessage takes a marshalled message in hex and queues it as though a peer had
+// sent it, so consensus can be tested without a second node
+func HandleInjectMessage(
+	state interfaces.IState,
+	params interface{},
+) (
+	interface{},
+	*primitives.JSONError,
+) {
+	req := new(InjectMessageRequest)
+	err := MapToObject(params, req)
+	if err != nil {
+		return nil, NewInvalidParamsError()
+	}
+	data, err := hex.DecodeString(req.Message)
+	if err != nil {
+		return nil, NewCustomInvalidParamsError("Message must be hex encoded")
+	}
+	msg, err := messages.UnmarshalMessage(data)
+	if err != nil {
+		return nil, NewCustomInvalidParamsError(err.Error())
+	}
+	if msg.IsLocal() || msg.GetRepeatHash() == nil {
+		return nil, NewCustomInvalidParamsError("Message cannot come from a peer")
+	}
+
+	type ret struct {
+		Type   string `json:"type"`
+		Hash   string `json:"hash"`
+		Queued bool   `json:"queued"` // False if it was dropped as a replay
+	}
+	r := new(ret)
+	r.Type = messages.MessageName(msg.Type())
+	r.Hash = msg.GetMsgHash().String()
+	r.Queued = state.InjectMessage(msg)
+	return r, nil
+}
+
 func HandleFedServers(
 	state interfaces.IState,
 	params interface{},
@@ -288,7 +506,11 @@ func HandleFedServers(
 	}
 	r := new(ret)
 
-	r.FederatedServers = state.GetFedServers(state.GetLeaderHeight())
+	if view := state.GetReadView(); view != nil {
+		r.FederatedServers = view.GetFedServers()
+	} else {
+		r.FederatedServers = state.GetFedServers(state.GetLeaderHeight())
+	}
 	return r, nil
 }
 
@@ -310,6 +532,33 @@ func HandleHoldingQueue(
 	return r, nil
 }
 
+// HandleHoldingReview returns what the last pass over holding did with the messages
+func HandleHoldingReview(
+	state interfaces.IState,
+	params interface{},
+) (
+	interface{},
+	*primitives.JSONError,
+) {
+	review := state.GetLastHoldingReview()
+	return &review, nil
+}
+
+// HandleReviewHolding forces a pass over holding, and returns what it did with the messages
+func HandleReviewHolding(
+	state interfaces.IState,
+	params interface{},
+) (
+	interface{},
+	*primitives.JSONError,
+) {
+	review, err := state.ReviewHoldingNow()
+	if err != nil {
+		return nil, NewCustomInternalError(err.Error())
+	}
+	return &review, nil
+}
+
 func HandleMessages(
 	state interfaces.IState,
 	params interface{},
@@ -346,6 +595,204 @@ func HandleNetworkInfo(
 	return r, nil
 }
 
+// HandlePinChain pins or unpins a chain, so its full history is always kept and backfilled
+func HandlePinChain(
+	state interfaces.IState,
+	params interface{},
+) (
+	interface{},
+	*primitives.JSONError,
+) {
+	type ret struct {
+		ChainID string `json:"chainid"`
+		Pinned  bool   `json:"pinned"`
+	}
+	r := new(ret)
+
+	req := new(PinChainRequest)
+	err := MapToObject(params, req)
+	if err != nil || len(req.ChainID) != 64 {
+		return nil, NewInvalidParamsError()
+	}
+	chainID, err := primitives.HexToHash(req.ChainID)
+	if err != nil {
+		return nil, NewInvalidParamsError()
+	}
+
+	state.PinChain(chainID, req.Pin)
+	r.ChainID = chainID.String()
+	r.Pinned = state.IsChainPinned(chainID)
+	return r, nil
+}
+
+// HandlePinnedChains reports how complete the local history of each pinned chain is
+func HandlePinnedChains(
+	state interfaces.IState,
+	params interface{},
+) (
+	interface{},
+	*primitives.JSONError,
+) {
+	type ret struct {
+		Chains []interfaces.PinnedChainStatus `json:"chains"`
+	}
+	r := new(ret)
+
+	r.Chains = state.GetPinnedChains()
+	return r, nil
+}
+
+// HandleSlowRounds reports the recent minutes and block starts that took longer than
+// expected, and which federated server's EOM or DBSig was processed last in each
+func HandleSlowRounds(
+	state interfaces.IState,
+	params interface{},
+) (
+	interface{},
+	*primitives.JSONError,
+) {
+	report := state.GetSlowRounds()
+	return &report, nil
+}
+
+// HandleSyncState exports the consensus state machine, its sync flags and VMs, as JSON
+// or, given the format "dot", as a Graphviz graph
+func HandleSyncState(
+	state interfaces.IState,
+	params interface{},
+) (
+	interface{},
+	*primitives.JSONError,
+) {
+	type ret struct {
+		DOT string `json:"dot"`
+	}
+
+	req := new(SyncStateRequest)
+	if params != nil {
+		if err := MapToObject(params, req); err != nil {
+			return nil, NewInvalidParamsError()
+		}
+	}
+
+	ss := state.GetSyncState()
+	switch req.Format {
+	case "", "json":
+		return &ss, nil
+	case "dot":
+		return &ret{DOT: ss.DOT()}, nil
+	}
+	return nil, NewInvalidParamsError()
+}
+
+// HandleAlerts lists the operator defined alert rules, and whether they are firing
+func HandleAlerts(
+	state interfaces.IState,
+	params interface{},
+) (
+	interface{},
+	*primitives.JSONError,
+) {
+	type ret struct {
+		Alerts []interfaces.AlertStatus `json:"alerts"`
+	}
+	r := new(ret)
+	r.Alerts = state.GetAlerts()
+	return r, nil
+}
+
+// HandleReceiptSubscriptions lists the entries waiting to have their receipts pushed
+func HandleReceiptSubscriptions(
+	state interfaces.IState,
+	params interface{},
+) (
+	interface{},
+	*primitives.JSONError,
+) {
+	type ret struct {
+		Subscriptions []interfaces.ReceiptSubscription `json:"subscriptions"`
+	}
+	r := new(ret)
+	r.Subscriptions = state.GetReceiptSubscriptions()
+	return r, nil
+}
+
+// HandleAnchorCheck reports how far our saved directory blocks have been compared against
+// the anchor records, and any that didn't match
+func HandleAnchorCheck(
+	state interfaces.IState,
+	params interface{},
+) (
+	interface{},
+	*primitives.JSONError,
+) {
+	status := state.GetAnchorCheck()
+	return &status, nil
+}
+
+// HandleJobs lists the periodic maintenance jobs, with their timings
+func HandleJobs(
+	state interfaces.IState,
+	params interface{},
+) (
+	interface{},
+	*primitives.JSONError,
+) {
+	type ret struct {
+		Jobs []interfaces.JobStatus `json:"jobs"`
+	}
+	r := new(ret)
+	r.Jobs = state.GetJobs()
+	return r, nil
+}
+
+// HandleRunJob has a maintenance job run at the next chance, whether or not it is enabled
+func HandleRunJob(
+	state interfaces.IState,
+	params interface{},
+) (
+	interface{},
+	*primitives.JSONError,
+) {
+	req := new(JobRequest)
+	err := MapToObject(params, req)
+	if err != nil || req.Name == "" {
+		return nil, NewInvalidParamsError()
+	}
+	if err := state.TriggerJob(req.Name); err != nil {
+		return nil, NewCustomInvalidParamsError(err.Error())
+	}
+	return findJob(state, req.Name), nil
+}
+
+// HandleEnableJob turns the schedule of a maintenance job on or off
+func HandleEnableJob(
+	state interfaces.IState,
+	params interface{},
+) (
+	interface{},
+	*primitives.JSONError,
+) {
+	req := new(JobRequest)
+	err := MapToObject(params, req)
+	if err != nil || req.Name == "" {
+		return nil, NewInvalidParamsError()
+	}
+	if err := state.SetJobEnabled(req.Name, req.Enabled); err != nil {
+		return nil, NewCustomInvalidParamsError(err.Error())
+	}
+	return findJob(state, req.Name), nil
+}
+
+func findJob(state interfaces.IState, name string) *interfaces.JobStatus {
+	for _, j := range state.GetJobs() {
+		if j.Name == name {
+			return &j
+		}
+	}
+	return nil
+}
+
 func HandleSummary(
 	state interfaces.IState,
 	params interface{},
@@ -412,3 +859,31 @@ type SetDelayRequest struct {
 type SetDropRateRequest struct {
 	DropRate int `json:"droprate"`
 }
+
+type SetMessageDelayRequest struct {
+	Type int   `json:"type"` // Message type, as in common/constants
+	Min  int64 `json:"min"`  // Milliseconds
+	Max  int64 `json:"max"`  // Milliseconds, 0 to clear the delay
+}
+
+type InjectMessageRequest struct {
+	Message string `json:"message"` // Marshalled message in hex
+}
+
+type BackupDatabaseRequest struct {
+	Path string `json:"path"`
+}
+
+type JobRequest struct {
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+}
+
+type PinChainRequest struct {
+	ChainID string `json:"chainid"`
+	Pin     bool   `json:"pin"`
+}
+
+type SyncStateRequest struct {
+	Format string `json:"format"` // "json" (the default) or "dot"
+}
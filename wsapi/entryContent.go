@@ -0,0 +1,216 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package wsapi
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/web"
+)
+
+// Encodings entry content can be returned in by /v2/entry-content
+const (
+	EntryEncodingRaw    = "raw"
+	EntryEncodingBase64 = "base64"
+	EntryEncodingHex    = "hex"
+)
+
+// The media types asked for in the Accept header for each encoding.  Hex is what the rest
+// of the API returns, so it is also what a client that takes anything gets.
+var entryEncodingTypes = map[string]string{
+	"application/octet-stream": EntryEncodingRaw,
+	"application/base64":       EntryEncodingBase64,
+	"application/x-hex":        EntryEncodingHex,
+	"text/plain":               EntryEncodingHex,
+	"*/*":                      EntryEncodingHex,
+}
+
+var entryEncodingContentTypes = map[string]string{
+	EntryEncodingRaw:    "application/octet-stream",
+	EntryEncodingBase64: "application/base64",
+	EntryEncodingHex:    "application/x-hex",
+}
+
+// Smallest content worth compressing on the way out
+const EntryContentGzipMin = 1024
+
+type acceptedType struct {
+	mediaType string
+	q         float64
+	order     int
+}
+
+type byPreference []acceptedType
+
+func (a byPreference) Len() int      { return len(a) }
+func (a byPreference) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
+func (a byPreference) Less(i, j int) bool {
+	if a[i].q != a[j].q {
+		return a[i].q > a[j].q
+	}
+	return a[i].order < a[j].order
+}
+
+// NegotiateEntryEncoding picks the encoding of entry content from the encoding query
+// parameter, if given, else the Accept header, taking the supported type with the
+// highest q value.  No header at all gets hex.
+func NegotiateEntryEncoding(accept string, encoding string) (string, error) {
+	if encoding != "" {
+		switch encoding {
+		case EntryEncodingRaw, EntryEncodingBase64, EntryEncodingHex:
+			return encoding, nil
+		}
+		return "", fmt.Errorf("Unknown encoding %s", encoding)
+	}
+	if strings.TrimSpace(accept) == "" {
+		return EntryEncodingHex, nil
+	}
+
+	var types []acceptedType
+	for i, part := range strings.Split(accept, ",") {
+		fields := strings.Split(part, ";")
+		t := acceptedType{mediaType: strings.ToLower(strings.TrimSpace(fields[0])), q: 1, order: i}
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if q, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					t.q = q
+				}
+			}
+		}
+		if t.q > 0 {
+			types = append(types, t)
+		}
+	}
+	sort.Sort(byPreference(types))
+	for _, t := range types {
+		if encoding, ok := entryEncodingTypes[t.mediaType]; ok {
+			return encoding, nil
+		}
+	}
+	return "", fmt.Errorf("None of %s can be returned; use application/octet-stream, application/base64 or application/x-hex", accept)
+}
+
+// DetectCompression names the format content was compressed with, going by its magic
+// number, or returns "" if it doesn't look compressed
+func DetectCompression(content []byte) string {
+	switch {
+	case bytes.HasPrefix(content, []byte{0x1f, 0x8b}):
+		return "gzip"
+	case bytes.HasPrefix(content, []byte{0x28, 0xb5, 0x2f, 0xfd}):
+		return "zstd"
+	case bytes.HasPrefix(content, []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}):
+		return "xz"
+	case len(content) >= 4 && bytes.HasPrefix(content, []byte("BZh")) && content[3] >= '1' && content[3] <= '9':
+		return "bzip2"
+	case bytes.HasPrefix(content, []byte{0x04, 0x22, 0x4d, 0x18}):
+		return "lz4"
+	case len(content) >= 2 && content[0] == 0x78 && (uint16(content[0])<<8|uint16(content[1]))%31 == 0:
+		return "zlib"
+	}
+	return ""
+}
+
+// HandleEntryContent returns the content of an entry on its own, in the encoding the
+// client negotiates, rather than hex inside JSON.  The headers give the entry's size and
+// the hash of its content, so a HEAD request learns them without the payload.  Content
+// that isn't already compressed is gzipped for clients that accept it.
+//
+//	/v2/entry-content/<entry hash>?encoding=raw|base64|hex
+func HandleEntryContent(ctx *web.Context, hashkey string) {
+	ServersMutex.Lock()
+	state := ctx.Server.Env["state"].(interfaces.IState)
+	ServersMutex.Unlock()
+
+	if err := checkAuthHeader(state, ctx.Request); err != nil {
+		ctx.ResponseWriter.Header().Add("WWW-Authenticate", `Basic realm="factomd RPC"`)
+		http.Error(ctx.ResponseWriter, "401 Unauthorized.", http.StatusUnauthorized)
+		return
+	}
+
+	header := ctx.ResponseWriter.Header()
+	header.Set("Vary", "Accept, Accept-Encoding")
+	encoding, err := NegotiateEntryEncoding(ctx.Request.Header.Get("Accept"), ctx.Request.URL.Query().Get("encoding"))
+	if err != nil {
+		http.Error(ctx.ResponseWriter, err.Error(), http.StatusNotAcceptable)
+		return
+	}
+
+	h, err := primitives.HexToHash(hashkey)
+	if err != nil {
+		http.Error(ctx.ResponseWriter, "Invalid entry hash", http.StatusBadRequest)
+		return
+	}
+	entry, err := state.FetchEntryByHash(h)
+	if err == nil && entry == nil {
+		dbase := state.GetAndLockDB()
+		entry, err = dbase.FetchEntry(h)
+		state.UnlockDB()
+	}
+	if err != nil {
+		http.Error(ctx.ResponseWriter, "Internal error", http.StatusInternalServerError)
+		return
+	}
+	if entry == nil {
+		http.Error(ctx.ResponseWriter, "Entry not found", http.StatusNotFound)
+		return
+	}
+
+	content := entry.GetContent()
+	var body []byte
+	switch encoding {
+	case EntryEncodingRaw:
+		body = content
+	case EntryEncodingBase64:
+		body = []byte(base64.StdEncoding.EncodeToString(content))
+	default:
+		body = []byte(hex.EncodeToString(content))
+	}
+	data, err := entry.MarshalBinary()
+	if err != nil {
+		http.Error(ctx.ResponseWriter, "Internal error", http.StatusInternalServerError)
+		return
+	}
+	sum := sha256.Sum256(content)
+	compression := DetectCompression(content)
+
+	header.Set("Content-Type", entryEncodingContentTypes[encoding])
+	header.Set("X-Entry-Hash", entry.GetHash().String())
+	header.Set("X-Chain-ID", entry.GetChainIDHash().String())
+	header.Set("X-Entry-Size", strconv.Itoa(len(data)))
+	header.Set("X-Content-Length", strconv.Itoa(len(content)))
+	header.Set("X-Content-SHA256", hex.EncodeToString(sum[:]))
+	if compression != "" {
+		header.Set("X-Content-Compression", compression)
+	}
+
+	// Compressing what is already compressed gains nothing, and HEAD should say how much
+	// GET sends without compressing it to find out
+	if ctx.Request.Method != "HEAD" && compression == "" && len(body) >= EntryContentGzipMin &&
+		strings.Contains(ctx.Request.Header.Get("Accept-Encoding"), "gzip") {
+		header.Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(ctx.ResponseWriter)
+		gz.Write(body)
+		gz.Close()
+		return
+	}
+	header.Set("Content-Length", strconv.Itoa(len(body)))
+	if ctx.Request.Method == "HEAD" {
+		ctx.ResponseWriter.WriteHeader(http.StatusOK)
+		return
+	}
+	ctx.ResponseWriter.Write(body)
+}
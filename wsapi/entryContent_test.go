@@ -0,0 +1,114 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package wsapi_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/FactomProject/factomd/testHelper"
+	. "github.com/FactomProject/factomd/wsapi"
+	"github.com/FactomProject/web"
+)
+
+func TestNegotiateEntryEncoding(t *testing.T) {
+	for _, c := range []struct {
+		accept, encoding, expected string
+	}{
+		{"", "", EntryEncodingHex},
+		{"*/*", "", EntryEncodingHex},
+		{"application/octet-stream", "", EntryEncodingRaw},
+		{"application/base64", "", EntryEncodingBase64},
+		{"image/png, application/base64;q=0.5, application/octet-stream;q=0.9", "", EntryEncodingRaw},
+		{"application/octet-stream;q=0, */*", "", EntryEncodingHex},
+		{"application/octet-stream", "base64", EntryEncodingBase64},
+	} {
+		e, err := NegotiateEntryEncoding(c.accept, c.encoding)
+		if err != nil || e != c.expected {
+			t.Errorf("Accept %q encoding %q gave %s %v, expected %s", c.accept, c.encoding, e, err, c.expected)
+		}
+	}
+
+	if _, err := NegotiateEntryEncoding("image/png", ""); err == nil {
+		t.Error("Expected an error for an unsupported type")
+	}
+	if _, err := NegotiateEntryEncoding("", "base32"); err == nil {
+		t.Error("Expected an error for an unknown encoding")
+	}
+}
+
+func TestDetectCompression(t *testing.T) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	gz.Write([]byte("hello"))
+	gz.Close()
+	zlib, _ := hex.DecodeString("789c4b")
+
+	for _, c := range []struct {
+		content  []byte
+		expected string
+	}{
+		{buf.Bytes(), "gzip"},
+		{zlib, "zlib"},
+		{[]byte{0x28, 0xb5, 0x2f, 0xfd, 0}, "zstd"},
+		{[]byte("BZh91AY"), "bzip2"},
+		{[]byte("BZhello"), ""},
+		{[]byte("plain text"), ""},
+		{nil, ""},
+	} {
+		if got := DetectCompression(c.content); got != c.expected {
+			t.Errorf("%x detected as %q, expected %q", c.content, got, c.expected)
+		}
+	}
+}
+
+func TestHandleEntryContent(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestState()
+	entry := testHelper.CreateFirstTestEntry()
+	server := web.NewServer()
+	server.Env["state"] = state
+
+	get := func(method, accept string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, "/v2/entry-content/"+entry.GetHash().String(), nil)
+		r.Header.Set("Accept", accept)
+		w := httptest.NewRecorder()
+		HandleEntryContent(&web.Context{Request: r, ResponseWriter: w, Server: server}, entry.GetHash().String())
+		return w
+	}
+
+	w := get("GET", "application/octet-stream")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Got status %d: %s", w.Code, w.Body.String())
+	}
+	body, _ := ioutil.ReadAll(w.Body)
+	if !bytes.Equal(body, entry.GetContent()) {
+		t.Errorf("Got content %x, expected %x", body, entry.GetContent())
+	}
+	if w.Header().Get("Content-Type") != "application/octet-stream" || w.Header().Get("X-Entry-Hash") != entry.GetHash().String() {
+		t.Errorf("Unexpected headers %v", w.Header())
+	}
+
+	w = get("GET", "")
+	if w.Body.String() != hex.EncodeToString(entry.GetContent()) {
+		t.Errorf("Got %s, expected the content in hex", w.Body.String())
+	}
+
+	w = get("HEAD", "application/base64")
+	if w.Code != http.StatusOK || w.Body.Len() != 0 {
+		t.Errorf("HEAD gave status %d and a %d byte body", w.Code, w.Body.Len())
+	}
+	if w.Header().Get("X-Content-SHA256") == "" || w.Header().Get("X-Entry-Size") == "" || w.Header().Get("Content-Length") == "" {
+		t.Errorf("HEAD is missing the size and hash headers %v", w.Header())
+	}
+
+	if w = get("GET", "image/png"); w.Code != http.StatusNotAcceptable {
+		t.Errorf("Expected %d for an unsupported type, got %d", http.StatusNotAcceptable, w.Code)
+	}
+}
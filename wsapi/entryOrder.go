@@ -0,0 +1,197 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package wsapi
+
+import (
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// Ordering of entries
+//
+// The only order the protocol gives the entries of a chain is the order of its entry
+// blocks, and within an entry block the order the leader acknowledged them in.  That is
+// the order this API reports them in, as a directory block height and a sequence within
+// the entry block.  Arrival time, entry timestamps and commit order mean nothing for the
+// ordering: entries committed in one order can be revealed, and so acknowledged, in
+// another, and entries acknowledged by different leaders in the same minute only have
+// the order of the leaders' VMs.  An entry has no position until its entry block is
+// saved.  An entry with the same hash written twice to a chain keeps the position of its
+// first appearance.
+
+// Most entries returned by one chain-entries call
+const MaxChainEntries = 1000
+
+// EntryPositions lists the entries of an entry block, in order, with their positions
+func EntryPositions(eblock interfaces.IEntryBlock) []EntryPosition {
+	keyMR, err := eblock.KeyMR()
+	if err != nil {
+		return nil
+	}
+	dbheight := eblock.GetHeader().GetDBHeight()
+
+	var positions []EntryPosition
+	minuteStart := 0 // First entry not yet given a minute
+	for _, h := range eblock.GetBody().GetEBEntries() {
+		if h.IsMinuteMarker() {
+			// The marker closes the minute of the entries since the last one
+			for i := minuteStart; i < len(positions); i++ {
+				positions[i].Minute = int(h.ToMinute())
+			}
+			minuteStart = len(positions)
+			continue
+		}
+		positions = append(positions, EntryPosition{
+			EntryHash:   h.String(),
+			EBlockKeyMR: keyMR.String(),
+			DBHeight:    dbheight,
+			Sequence:    len(positions),
+		})
+	}
+	return positions
+}
+
+// chainEntriesFromIndex fills in the response from the chain index.  It returns false if
+// the index lacks some of the chain's blocks in the range, which are then only found by
+// walking the chain.
+func chainEntriesFromIndex(dbase interfaces.DBOverlaySimple, chainID interfaces.IHash, headSeq uint32, req *ChainEntriesRequest, r *ChainEntriesResponse) bool {
+	seq, ok, err := dbase.FindChainIndexHeight(chainID, req.From)
+	if err != nil || !ok {
+		return false
+	}
+	// An entry block holds at least one entry, so this many blocks fill a response
+	blocks, err := dbase.FetchChainIndex(chainID, seq, MaxChainEntries)
+	if err != nil {
+		return false
+	}
+	if len(blocks) < MaxChainEntries && seq+uint32(len(blocks)) <= headSeq {
+		if len(blocks) == 0 || blocks[len(blocks)-1].DBHeight < req.To {
+			return false
+		}
+	}
+
+	for _, b := range blocks {
+		if b.DBHeight > req.To {
+			return true
+		}
+		positions := indexedEntryPositions(b)
+		if len(r.Entries) > 0 && len(r.Entries)+len(positions) > MaxChainEntries {
+			r.NextHeight = b.DBHeight
+			return true
+		}
+		r.Entries = append(r.Entries, positions...)
+	}
+	if len(blocks) == MaxChainEntries && blocks[len(blocks)-1].DBHeight < req.To {
+		r.NextHeight = blocks[len(blocks)-1].DBHeight + 1
+	}
+	return true
+}
+
+// indexedEntryPositions lists the entries of an entry block from its chain index record
+func indexedEntryPositions(b interfaces.ChainIndexBlock) []EntryPosition {
+	var positions []EntryPosition
+	minuteStart := 0
+	for _, h := range b.EntryHashes {
+		if h.IsMinuteMarker() {
+			for i := minuteStart; i < len(positions); i++ {
+				positions[i].Minute = int(h.ToMinute())
+			}
+			minuteStart = len(positions)
+			continue
+		}
+		positions = append(positions, EntryPosition{
+			EntryHash:   h.String(),
+			EBlockKeyMR: b.KeyMR.String(),
+			DBHeight:    b.DBHeight,
+			Sequence:    len(positions),
+		})
+	}
+	return positions
+}
+
+// entryPosition finds where a saved entry sits in its chain, or returns nil
+func entryPosition(dbase interfaces.DBOverlaySimple, hash interfaces.IHash) *EntryPosition {
+	keyMR, err := dbase.FetchIncludedIn(hash)
+	if err != nil || keyMR == nil {
+		return nil
+	}
+	eblock, err := dbase.FetchEBlock(keyMR)
+	if err != nil || eblock == nil {
+		return nil
+	}
+	want := hash.String()
+	for _, p := range EntryPositions(eblock) {
+		if p.EntryHash == want {
+			return &p
+		}
+	}
+	return nil
+}
+
+// HandleV2ChainEntries returns the entries of a chain in entry blocks between two
+// directory block heights, in chain order.  Whole entry blocks are returned; if the
+// entries won't fit in one response, NextHeight says where to ask from next.
+func HandleV2ChainEntries(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	n := time.Now()
+	defer func() { HandleV2APICallChainEntries.Observe(float64(time.Since(n).Nanoseconds())) }()
+
+	req := new(ChainEntriesRequest)
+	err := MapToObject(params, req)
+	if err != nil || req.To < req.From {
+		return nil, NewInvalidParamsError()
+	}
+	chainID, err := primitives.HexToHash(req.ChainID)
+	if err != nil {
+		return nil, NewInvalidHashError()
+	}
+
+	dbase := state.GetAndLockDB()
+	defer state.UnlockDB()
+
+	eblock, err := dbase.FetchEBlockHead(chainID)
+	if err != nil {
+		return nil, NewInternalError()
+	}
+	if eblock == nil {
+		return nil, NewMissingChainHeadError()
+	}
+
+	r := new(ChainEntriesResponse)
+	r.ChainID = chainID.String()
+	r.Entries = []EntryPosition{}
+
+	// Page through the chain index, if it has the blocks of the range
+	if chainEntriesFromIndex(dbase, chainID, eblock.GetHeader().GetEBSequence(), req, r) {
+		return r, nil
+	}
+
+	// Walk back from the head to the first block in range, then go forward
+	var blocks []interfaces.IEntryBlock
+	for eblock != nil && eblock.GetHeader().GetDBHeight() >= req.From {
+		if eblock.GetHeader().GetDBHeight() <= req.To {
+			blocks = append(blocks, eblock)
+		}
+		prev := eblock.GetHeader().GetPrevKeyMR()
+		if prev.IsZero() {
+			break
+		}
+		eblock, err = dbase.FetchEBlock(prev)
+		if err != nil {
+			return nil, NewInternalError()
+		}
+	}
+
+	for i := len(blocks) - 1; i >= 0; i-- {
+		positions := EntryPositions(blocks[i])
+		if len(r.Entries) > 0 && len(r.Entries)+len(positions) > MaxChainEntries {
+			r.NextHeight = blocks[i].GetHeader().GetDBHeight()
+			break
+		}
+		r.Entries = append(r.Entries, positions...)
+	}
+	return r, nil
+}
@@ -0,0 +1,81 @@
+package wsapi_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/common/entryBlock"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/testHelper"
+	. "github.com/FactomProject/factomd/wsapi"
+)
+
+func TestEntryPositions(t *testing.T) {
+	eb := entryBlock.NewEBlock()
+	eb.GetHeader().SetDBHeight(7)
+	for _, step := range []string{"a", "b", "1", "c", "3"} {
+		if len(step) == 1 && step[0] >= '1' && step[0] <= '9' {
+			eb.AddEndOfMinuteMarker(step[0] - '0')
+			continue
+		}
+		e := entryBlock.NewEntry()
+		e.Content = primitives.ByteSlice{Bytes: []byte(step)}
+		eb.AddEBEntry(e)
+	}
+
+	positions := EntryPositions(eb)
+	if len(positions) != 3 {
+		t.Fatalf("Expected 3 entries, found %d", len(positions))
+	}
+	minutes := []int{1, 1, 3}
+	for i, p := range positions {
+		if p.Sequence != i || p.Minute != minutes[i] || p.DBHeight != 7 {
+			t.Errorf("Unexpected position %+v", p)
+		}
+	}
+}
+
+func TestHandleV2ChainEntries(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestState()
+	chainID := testHelper.GetChainID().String()
+
+	resp, jErr := HandleV2ChainEntries(state, &ChainEntriesRequest{ChainID: chainID, From: 0, To: 1000})
+	if jErr != nil {
+		t.Fatalf("%v", jErr)
+	}
+	r := resp.(*ChainEntriesResponse)
+	if len(r.Entries) == 0 {
+		t.Fatal("No entries returned")
+	}
+	for i := 1; i < len(r.Entries); i++ {
+		a, b := r.Entries[i-1], r.Entries[i]
+		if a.DBHeight > b.DBHeight || (a.DBHeight == b.DBHeight && a.Sequence >= b.Sequence) {
+			t.Errorf("Entries out of order, %+v then %+v", a, b)
+		}
+	}
+
+	// The entry call gives the same position
+	last := r.Entries[len(r.Entries)-1]
+	eresp, jErr := HandleV2Entry(state, &HashRequest{Hash: last.EntryHash})
+	if jErr != nil {
+		t.Fatalf("%v", jErr)
+	}
+	pos := eresp.(*EntryResponse).Position
+	if pos == nil || *pos != last {
+		t.Errorf("Expected position %+v, found %+v", last, pos)
+	}
+
+	// A narrower range only has the blocks in it
+	resp, jErr = HandleV2ChainEntries(state, &ChainEntriesRequest{ChainID: chainID, From: last.DBHeight, To: last.DBHeight})
+	if jErr != nil {
+		t.Fatalf("%v", jErr)
+	}
+	for _, e := range resp.(*ChainEntriesResponse).Entries {
+		if e.DBHeight != last.DBHeight {
+			t.Errorf("Entry outside the range, %+v", e)
+		}
+	}
+
+	if _, jErr = HandleV2ChainEntries(state, &ChainEntriesRequest{ChainID: chainID, From: 5, To: 4}); jErr == nil {
+		t.Error("Accepted a range that ends before it starts")
+	}
+}
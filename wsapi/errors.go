@@ -47,6 +47,9 @@ func NewCustomInternalError(data interface{}) *primitives.JSONError {
 func NewCustomInvalidParamsError(data interface{}) *primitives.JSONError {
 	return primitives.NewJSONError(-32602, "Invalid params", data)
 }
+func NewCustomInvalidRequestError(data interface{}) *primitives.JSONError {
+	return primitives.NewJSONError(-32600, "Invalid Request", data)
+}
 
 /*******************************************************************/
 
@@ -88,6 +91,9 @@ func NewEntryNotFoundError() *primitives.JSONError {
 func NewObjectNotFoundError() *primitives.JSONError {
 	return primitives.NewJSONError(-32008, "Object not found", nil)
 }
+func NewAnchorNotFoundError() *primitives.JSONError {
+	return primitives.NewJSONError(-32008, "Anchor not found", nil)
+}
 func NewMissingChainHeadError() *primitives.JSONError {
 	return primitives.NewJSONError(-32009, "Missing Chain Head", nil)
 }
@@ -97,3 +103,15 @@ func NewReceiptError() *primitives.JSONError {
 func NewRepeatCommitError(data interface{}) *primitives.JSONError {
 	return primitives.NewJSONError(-32011, "Repeated Commit", data)
 }
+func NewSubmissionRejectedError(data interface{}) *primitives.JSONError {
+	return primitives.NewJSONError(-32012, "Submission rejected, try again later", data)
+}
+func NewNodeOverloadedError(data interface{}) *primitives.JSONError {
+	return primitives.NewJSONError(-32013, "Node overloaded, try again later", data)
+}
+func NewPermissionDeniedError(data interface{}) *primitives.JSONError {
+	return primitives.NewJSONError(-32014, "Permission denied", data)
+}
+func NewRequestTimeoutError() *primitives.JSONError {
+	return primitives.NewJSONError(-32015, "Request timed out", nil)
+}
@@ -0,0 +1,101 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package wsapi
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// How long the result of a submission made with an idempotency key is remembered
+var IdempotencyWindow = 10 * time.Minute
+
+// Keys longer than this are refused, so clients can't use the cache to hold data
+const MaxIdempotencyKeyLength = 128
+
+// The most results remembered; past it, the least recently used are forgotten first
+var MaxIdempotentResults = 10000
+
+type idempotentResult struct {
+	key     string
+	message string // The submitted message, so a key can't be reused for another one
+	resp    interface{}
+	time    time.Time
+}
+
+// The results remembered, by key and in order of use, the most recent at the front
+var idempotencyMutex sync.Mutex
+var idempotentResults = map[string]*list.Element{}
+var idempotentOrder = list.New()
+
+// forgetIdempotent drops a result; the mutex must be held
+func forgetIdempotent(e *list.Element) {
+	idempotentOrder.Remove(e)
+	delete(idempotentResults, e.Value.(*idempotentResult).key)
+}
+
+// expireIdempotent drops the results least recently used while they are past the window,
+// or there are more than MaxIdempotentResults; the mutex must be held.  Only the back of
+// the order is looked at, so the work is in proportion to what is dropped.
+func expireIdempotent(now time.Time) {
+	for e := idempotentOrder.Back(); e != nil; e = idempotentOrder.Back() {
+		r := e.Value.(*idempotentResult)
+		if now.Sub(r.time) <= IdempotencyWindow && idempotentOrder.Len() <= MaxIdempotentResults {
+			return
+		}
+		forgetIdempotent(e)
+	}
+}
+
+// submitIdempotent runs submit for a message sent with an idempotency key, unless the same
+// message was already submitted with that key inside the window, in which case the
+// original result is returned.  Clients retrying a commit after a timeout would otherwise
+// race IsHighestCommit, and could pay for the same entry twice.
+func submitIdempotent(
+	state interfaces.IState,
+	method string,
+	key string,
+	message string,
+	submit func() (interface{}, *primitives.JSONError),
+) (interface{}, *primitives.JSONError) {
+	if len(key) > MaxIdempotencyKeyLength {
+		return nil, NewCustomInvalidParamsError("Idempotency key is too long")
+	}
+	k := state.GetFactomNodeName() + "/" + method + "/" + key
+
+	// Submissions are quick, so holding the lock throughout is the simplest way to make
+	// sure two requests with the same key can't both get through.
+	idempotencyMutex.Lock()
+	defer idempotencyMutex.Unlock()
+
+	now := time.Now()
+	expireIdempotent(now)
+
+	if e := idempotentResults[k]; e != nil {
+		r := e.Value.(*idempotentResult)
+		if now.Sub(r.time) <= IdempotencyWindow {
+			if r.message != message {
+				return nil, NewCustomInvalidParamsError("Idempotency key was already used for a different message")
+			}
+			idempotentOrder.MoveToFront(e)
+			return r.resp, nil
+		}
+		// Past the window, though used since results behind it were
+		forgetIdempotent(e)
+	}
+
+	resp, jsonError := submit()
+	// Failures are not remembered; trying again gives the same answer, or a better one
+	if jsonError == nil {
+		r := &idempotentResult{key: k, message: message, resp: resp, time: now}
+		idempotentResults[k] = idempotentOrder.PushFront(r)
+		expireIdempotent(now)
+	}
+	return resp, jsonError
+}
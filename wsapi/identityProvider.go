@@ -0,0 +1,313 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package wsapi
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+)
+
+// Enterprises run their access control through single sign-on, not passwords in config
+// files.  An identity provider says who made an API request and what they may do.  The
+// basic provider is the RPC user and password, which may do anything.  The OIDC provider
+// takes a bearer token, and asks the organisation's OIDC provider about it by token
+// introspection (RFC 7662); the roles of the token grant permissions.
+
+// Permissions of API clients
+const (
+	PermissionRead  = "read"  // Read the chain and the node's state
+	PermissionWrite = "write" // Submit commits, reveals, transactions and objects
+	PermissionDebug = "debug" // Use the debug API
+	PermissionAdmin = "admin" // Operate the node, as in backing up its database
+)
+
+// Values of APIAuthProvider
+const (
+	AuthProviderBasic = "basic"
+	AuthProviderOIDC  = "oidc"
+)
+
+// writeMethods are the V2 methods that need PermissionWrite; the others need PermissionRead
+var writeMethods = map[string]bool{
+	"commit-chain":        true,
+	"commit-entry":        true,
+	"factoid-submit":      true,
+	"object-delete":       true,
+	"object-put":          true,
+	"receipt-subscribe":   true,
+	"receipt-unsubscribe": true,
+	"reveal-chain":        true,
+	"reveal-entry":        true,
+	"send-raw-message":    true,
+}
+
+// adminMethods are the V2 methods that need PermissionAdmin
+var adminMethods = map[string]bool{
+	"backup-database": true,
+}
+
+// methodPermission returns the permission a V2 method needs
+func methodPermission(method string) string {
+	if adminMethods[method] {
+		return PermissionAdmin
+	}
+	if writeMethods[method] {
+		return PermissionWrite
+	}
+	return PermissionRead
+}
+
+// Principal is who made an API request, and what they may do
+type Principal struct {
+	Subject     string
+	Permissions map[string]bool
+}
+
+// Can says if the principal has the permission
+func (p *Principal) Can(permission string) bool {
+	return p != nil && p.Permissions[permission]
+}
+
+// IdentityProvider authenticates API requests
+type IdentityProvider interface {
+	Authenticate(r *http.Request) (*Principal, error)
+}
+
+var identityProviderMutex sync.Mutex
+var identityProvider IdentityProvider // nil for the basic provider
+
+// StartIdentityProvider sets the identity provider the config asks for
+func StartIdentityProvider(cfg interfaces.APIAuthConfig) error {
+	identityProviderMutex.Lock()
+	defer identityProviderMutex.Unlock()
+
+	switch cfg.Provider {
+	case "", AuthProviderBasic:
+		identityProvider = nil
+	case AuthProviderOIDC:
+		provider, err := NewOIDCProvider(cfg)
+		if err != nil {
+			return err
+		}
+		identityProvider = provider
+	default:
+		return fmt.Errorf("unknown APIAuthProvider %q, expected %s or %s", cfg.Provider, AuthProviderBasic, AuthProviderOIDC)
+	}
+	return nil
+}
+
+// SetIdentityProvider replaces the identity provider; nil is the basic provider
+func SetIdentityProvider(provider IdentityProvider) {
+	identityProviderMutex.Lock()
+	defer identityProviderMutex.Unlock()
+	identityProvider = provider
+}
+
+// authenticate returns who made the request, as the identity provider says
+func authenticate(state interfaces.IState, r *http.Request) (*Principal, error) {
+	identityProviderMutex.Lock()
+	provider := identityProvider
+	identityProviderMutex.Unlock()
+
+	if provider == nil {
+		if err := checkBasicAuth(state, r); err != nil {
+			return nil, err
+		}
+		user, _, _ := r.BasicAuth()
+		return &Principal{Subject: user, Permissions: map[string]bool{PermissionRead: true, PermissionWrite: true, PermissionDebug: true, PermissionAdmin: true}}, nil
+	}
+	return provider.Authenticate(r)
+}
+
+// authenticationConfigured says if API clients must authenticate; without a password or
+// an identity provider anyone may call the API
+func authenticationConfigured(state interfaces.IState) bool {
+	identityProviderMutex.Lock()
+	defer identityProviderMutex.Unlock()
+	return identityProvider != nil || state.GetRpcUser() != ""
+}
+
+// authorize returns who made the request if they have the permission
+func authorize(state interfaces.IState, r *http.Request, permission string) (*Principal, error) {
+	principal, err := authenticate(state, r)
+	if err != nil {
+		return nil, err
+	}
+	if !principal.Can(permission) {
+		return nil, fmt.Errorf("%s may not %s", principal.Subject, permission)
+	}
+	return principal, nil
+}
+
+// Most introspected tokens held, so a flood of junk tokens can't fill memory
+const maxOIDCCache = 10000
+
+type oidcCacheEntry struct {
+	principal *Principal // nil if the token is not active
+	expires   time.Time
+}
+
+// OIDCProvider authenticates bearer tokens by introspection at an OIDC provider.  The
+// answer for a token is cached for CacheSeconds, or until the token expires.
+type OIDCProvider struct {
+	config interfaces.APIAuthConfig
+	client *http.Client
+
+	mutex sync.Mutex
+	cache map[[32]byte]oidcCacheEntry // By the hash of the token
+}
+
+// NewOIDCProvider returns an OIDC provider for the config
+func NewOIDCProvider(cfg interfaces.APIAuthConfig) (*OIDCProvider, error) {
+	if _, err := url.ParseRequestURI(cfg.IntrospectionURL); err != nil {
+		return nil, fmt.Errorf("bad OIDCIntrospectionURL: %v", err)
+	}
+	if cfg.RolesClaim == "" {
+		cfg.RolesClaim = "roles"
+	}
+	p := new(OIDCProvider)
+	p.config = cfg
+	p.client = &http.Client{Timeout: 10 * time.Second}
+	p.cache = make(map[[32]byte]oidcCacheEntry)
+	return p, nil
+}
+
+// Authenticate checks the bearer token of the request
+func (p *OIDCProvider) Authenticate(r *http.Request) (*Principal, error) {
+	header := r.Header.Get("Authorization")
+	if !strings.HasPrefix(header, "Bearer ") {
+		return nil, errors.New("no bearer token")
+	}
+	token := strings.TrimSpace(strings.TrimPrefix(header, "Bearer "))
+	if token == "" {
+		return nil, errors.New("no bearer token")
+	}
+
+	key := sha256.Sum256([]byte(token))
+	now := time.Now()
+	p.mutex.Lock()
+	entry, ok := p.cache[key]
+	p.mutex.Unlock()
+	if ok && now.Before(entry.expires) {
+		OIDCIntrospections.WithLabelValues("cached").Inc()
+		if entry.principal == nil {
+			return nil, errors.New("token is not active")
+		}
+		return entry.principal, nil
+	}
+
+	principal, expires, err := p.introspect(token)
+	if err != nil {
+		// The provider couldn't be asked; nothing is cached, so the next request asks again
+		OIDCIntrospections.WithLabelValues("error").Inc()
+		return nil, err
+	}
+	if trust := now.Add(time.Duration(p.config.CacheSeconds) * time.Second); expires.IsZero() || trust.Before(expires) {
+		expires = trust
+	}
+	p.mutex.Lock()
+	if len(p.cache) >= maxOIDCCache {
+		for k, e := range p.cache {
+			if !now.Before(e.expires) {
+				delete(p.cache, k)
+			}
+		}
+		if len(p.cache) >= maxOIDCCache {
+			p.cache = make(map[[32]byte]oidcCacheEntry)
+		}
+	}
+	p.cache[key] = oidcCacheEntry{principal: principal, expires: expires}
+	p.mutex.Unlock()
+
+	if principal == nil {
+		OIDCIntrospections.WithLabelValues("inactive").Inc()
+		return nil, errors.New("token is not active")
+	}
+	OIDCIntrospections.WithLabelValues("active").Inc()
+	return principal, nil
+}
+
+// introspect asks the provider about a token, returning who it is of, or nil if it is not
+// active, and when it expires, if it says
+func (p *OIDCProvider) introspect(token string) (*Principal, time.Time, error) {
+	form := url.Values{"token": {token}, "token_type_hint": {"access_token"}}
+	req, err := http.NewRequest("POST", p.config.IntrospectionURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.config.ClientID != "" {
+		req.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, time.Time{}, fmt.Errorf("introspection returned %s", resp.Status)
+	}
+
+	var claims map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&claims); err != nil {
+		return nil, time.Time{}, err
+	}
+	var expires time.Time
+	if exp, ok := claims["exp"].(float64); ok {
+		expires = time.Unix(int64(exp), 0)
+	}
+	if active, _ := claims["active"].(bool); !active {
+		return nil, expires, nil
+	}
+
+	principal := &Principal{Permissions: make(map[string]bool)}
+	principal.Subject, _ = claims["sub"].(string)
+	if principal.Subject == "" {
+		principal.Subject, _ = claims["username"].(string)
+	}
+	for _, role := range claimRoles(claims, p.config.RolesClaim) {
+		for _, permission := range p.config.RolePermissions[role] {
+			principal.Permissions[permission] = true
+		}
+	}
+	return principal, expires, nil
+}
+
+// claimRoles returns the roles in a claim, following the dots of the name into nested
+// claims.  The roles are a list, or a string of them separated by spaces, as scopes are.
+func claimRoles(claims map[string]interface{}, name string) []string {
+	var value interface{} = claims
+	for _, part := range strings.Split(name, ".") {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = m[part]
+	}
+
+	switch v := value.(type) {
+	case string:
+		return strings.Fields(v)
+	case []interface{}:
+		var roles []string
+		for _, r := range v {
+			if role, ok := r.(string); ok {
+				roles = append(roles, role)
+			}
+		}
+		return roles
+	}
+	return nil
+}
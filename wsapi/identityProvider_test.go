@@ -0,0 +1,111 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package wsapi_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/testHelper"
+	. "github.com/FactomProject/factomd/wsapi"
+	"github.com/FactomProject/web"
+)
+
+func TestOIDCProvider(t *testing.T) {
+	introspections := 0
+	idp := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		introspections++
+		if user, pass, _ := r.BasicAuth(); user != "factomd" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.FormValue("token") {
+		case "reader":
+			fmt.Fprint(w, `{"active":true,"sub":"alice","realm_access":{"roles":["reader"]}}`)
+		case "submitter":
+			fmt.Fprint(w, `{"active":true,"sub":"bob","realm_access":{"roles":["reader","submitter"]}}`)
+		default:
+			fmt.Fprint(w, `{"active":false}`)
+		}
+	}))
+	defer idp.Close()
+
+	cfg := interfaces.APIAuthConfig{
+		Provider:         AuthProviderOIDC,
+		IntrospectionURL: idp.URL,
+		ClientID:         "factomd",
+		ClientSecret:     "secret",
+		RolesClaim:       "realm_access.roles",
+		RolePermissions:  map[string][]string{"reader": {PermissionRead}, "submitter": {PermissionWrite}},
+		CacheSeconds:     60,
+	}
+	if err := StartIdentityProvider(cfg); err != nil {
+		t.Fatal(err)
+	}
+	defer SetIdentityProvider(nil)
+
+	state := testHelper.CreateAndPopulateTestState()
+	server := web.NewServer()
+	server.Env["state"] = state
+	call := func(token string, body string) (int, map[string]interface{}) {
+		r := httptest.NewRequest("POST", "/v2", strings.NewReader(body))
+		if token != "" {
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		HandleV2(&web.Context{Request: r, ResponseWriter: w, Server: server})
+		resp := map[string]interface{}{}
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		return w.Code, resp
+	}
+	heights := `{"jsonrpc":"2.0","id":0,"method":"heights"}`
+	commit := `{"jsonrpc":"2.0","id":0,"method":"commit-entry","params":{"message":"00"}}`
+
+	if code, _ := call("", heights); code != http.StatusUnauthorized {
+		t.Errorf("A call without a token returned %d", code)
+	}
+	if code, _ := call("forged", heights); code != http.StatusUnauthorized {
+		t.Errorf("A call with an inactive token returned %d", code)
+	}
+	if code, resp := call("reader", heights); code != http.StatusOK || resp["error"] != nil {
+		t.Errorf("A reader could not read: %d %v", code, resp)
+	}
+	code, resp := call("reader", commit)
+	if e, _ := resp["error"].(map[string]interface{}); code == http.StatusOK || e == nil || e["code"] != -32014.0 {
+		t.Errorf("A reader could write: %d %v", code, resp)
+	}
+	code, resp = call("submitter", commit)
+	if e, _ := resp["error"].(map[string]interface{}); e != nil && e["code"] == -32014.0 {
+		t.Errorf("A submitter could not write: %d %v", code, resp)
+	}
+	code, resp = call("submitter", `{"jsonrpc":"2.0","id":0,"method":"backup-database","params":{"path":"backup"}}`)
+	if e, _ := resp["error"].(map[string]interface{}); code == http.StatusOK || e == nil || e["code"] != -32014.0 {
+		t.Errorf("A submitter could back up the database: %d %v", code, resp)
+	}
+
+	// Tokens are introspected once while they are trusted
+	if introspections != 3 {
+		t.Errorf("Introspected %d times for 3 tokens", introspections)
+	}
+}
+
+func TestBackupDatabaseNeedsAuthentication(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestState()
+	state.RpcUser = ""
+
+	j := new(primitives.JSON2Request)
+	j.Method = "backup-database"
+	j.Params = map[string]interface{}{"path": "backup"}
+	_, jsonError := HandleV2Request(state, j)
+	if jsonError == nil || jsonError.Code != -32600 {
+		t.Errorf("An open node backed up its database: %v", jsonError)
+	}
+}
@@ -5,6 +5,21 @@ import (
 )
 
 var (
+	OIDCIntrospections = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "factomd_wsapi_oidc_introspections_total",
+		Help: "Bearer tokens checked with the OIDC provider, by whether they were active, inactive, cached or could not be checked",
+	}, []string{"result"})
+
+	V1CallsVec = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "factomd_wsapi_v1_calls_total",
+		Help: "Calls to the deprecated v1 API, by call and whether they were served or refused past the sunset",
+	}, []string{"call", "result"})
+
+	RequestsCancelledVec = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "factomd_wsapi_requests_cancelled_total",
+		Help: "API requests abandoned before they were answered, by whether they timed out or their client went away",
+	}, []string{"reason"})
+
 	GensisFblockCall = prometheus.NewCounter(prometheus.CounterOpts{
 		Name: "factomd_wsapi_v2_gensis_fblock_count",
 		Help: "Number of times the gensis Fblock is asked for",
@@ -60,6 +75,11 @@ var (
 		Help: "Time it takes to compelete a ecrate",
 	})
 
+	HandleV2APICallECRateHistory = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name: "factomd_wsapi_v2_api_call_ecrate_history_ns",
+		Help: "Time it takes to compelete a ecrate history",
+	})
+
 	HandleV2APICallFABal = prometheus.NewSummary(prometheus.SummaryOpts{
 		Name: "factomd_wsapi_v2_api_call_fabal_ns",
 		Help: "Time it takes to compelete a fabal",
@@ -75,6 +95,134 @@ var (
 		Help: "Time it takes to compelete a heights",
 	})
 
+	HandleV2APICallStartupProgress = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name: "factomd_wsapi_v2_api_call_startup_progress_ns",
+		Help: "Time it takes to compelete a startup progress",
+	})
+
+	HandleV2APICallNetworkParameters = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name: "factomd_wsapi_v2_api_call_network_parameters_ns",
+		Help: "Time it takes to compelete a network parameters",
+	})
+
+	HandleV2APICallChainEntries = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name: "factomd_wsapi_v2_api_call_chain_entries_ns",
+		Help: "Time it takes to compelete a chain entries",
+	})
+
+	HandleV2APICallFctSupply = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name: "factomd_wsapi_v2_api_call_fct_supply_ns",
+		Help: "Time it takes to compelete a fct supply",
+	})
+
+	HandleV2APICallTimingAnomalies = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name: "factomd_wsapi_v2_api_call_timing_anomalies_ns",
+		Help: "Time it takes to compelete a timing anomalies",
+	})
+
+	HandleV2APICallCapacity = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name: "factomd_wsapi_v2_api_call_capacity_ns",
+		Help: "Time it takes to compelete a capacity",
+	})
+
+	HandleV2APICallCoinbaseAudit = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name: "factomd_wsapi_v2_api_call_coinbase_audit_ns",
+		Help: "Time it takes to compelete a coinbase audit",
+	})
+
+	HandleV2APICallReferences = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name: "factomd_wsapi_v2_api_call_references_ns",
+		Help: "Time it takes to compelete a references",
+	})
+	HandleV2APICallTransactionsByAddress = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name: "factomd_wsapi_v2_api_call_transactions_by_address_ns",
+		Help: "Time it takes to compelete a transactions-by-address",
+	})
+
+	HandleV2APICallEntriesByExtID = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name: "factomd_wsapi_v2_api_call_entries_by_extid_ns",
+		Help: "Time it takes to compelete an entries-by-extid",
+	})
+
+	HandleV2APICallAPIQueue = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name: "factomd_wsapi_v2_api_call_api_queue_ns",
+		Help: "Time it takes to compelete an api queue",
+	})
+
+	HandleV2APICallEthereumAnchor = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name: "factomd_wsapi_v2_api_call_ethereum_anchor_ns",
+		Help: "Time it takes to compelete an ethereum anchor",
+	})
+
+	HandleV2APICallEthereumReceipt = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name: "factomd_wsapi_v2_api_call_ethereum_receipt_ns",
+		Help: "Time it takes to compelete an ethereum receipt",
+	})
+
+	HandleV2APICallPendingPool = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name: "factomd_wsapi_v2_api_call_pending_pool_ns",
+		Help: "Time it takes to compelete a pending pool",
+	})
+
+	HandleV2APICallObjectPut = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name: "factomd_wsapi_v2_api_call_object_put_ns",
+		Help: "Time it takes to compelete an object put",
+	})
+
+	HandleV2APICallObjectGet = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name: "factomd_wsapi_v2_api_call_object_get_ns",
+		Help: "Time it takes to compelete an object get",
+	})
+
+	HandleV2APICallObjectDelete = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name: "factomd_wsapi_v2_api_call_object_delete_ns",
+		Help: "Time it takes to compelete an object delete",
+	})
+
+	HandleV2APICallObjectList = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name: "factomd_wsapi_v2_api_call_object_list_ns",
+		Help: "Time it takes to compelete an object list",
+	})
+
+	HandleV2APICallBackpressure = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name: "factomd_wsapi_v2_api_call_backpressure_ns",
+		Help: "Time it takes to compelete a backpressure",
+	})
+	HandleV2APICallCommitRateLimits = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name: "factomd_wsapi_v2_api_call_commit_rate_limits_ns",
+		Help: "Time it takes to compelete a commit-rate-limits",
+	})
+
+	HandleV2APICallRejections = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name: "factomd_wsapi_v2_api_call_rejections_ns",
+		Help: "Time it takes to compelete a rejections",
+	})
+
+	HandleV2APICallPeerReputation = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name: "factomd_wsapi_v2_api_call_peer_reputation_ns",
+		Help: "Time it takes to compelete a peer reputation",
+	})
+
+	HandleV2APICallCreateChain = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name: "factomd_wsapi_v2_api_call_create_chain_ns",
+		Help: "Time it takes to compelete a create chain",
+	})
+
+	HandleV2APICallMultisigAddress = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name: "factomd_wsapi_v2_api_call_multisig_address_ns",
+		Help: "Time it takes to compelete a multisig address",
+	})
+
+	HandleV2APICallMultisigCompose = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name: "factomd_wsapi_v2_api_call_multisig_compose_ns",
+		Help: "Time it takes to compelete a multisig compose",
+	})
+
+	HandleV2APICallMultisigSign = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name: "factomd_wsapi_v2_api_call_multisig_sign_ns",
+		Help: "Time it takes to compelete a multisig sign",
+	})
+
 	HandleV2APICallCurrentMinute = prometheus.NewSummary(prometheus.SummaryOpts{
 		Name: "factomd_wsapi_v2_api_call_minute_ns",
 		Help: "Time it takes to compelete a minute",
@@ -95,6 +243,16 @@ var (
 		Help: "Time it takes to compelete a ",
 	})
 
+	HandleV2APICallReceiptSubscribe = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name: "factomd_wsapi_v2_api_call_receipt_subscribe_ns",
+		Help: "Time it takes to compelete a receipt subscribe",
+	})
+
+	HandleV2APICallReceiptUnsubscribe = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name: "factomd_wsapi_v2_api_call_receipt_unsubscribe_ns",
+		Help: "Time it takes to compelete a receipt unsubscribe",
+	})
+
 	HandleV2APICallRevealEntry = prometheus.NewSummary(prometheus.SummaryOpts{
 		Name: "factomd_wsapi_v2_api_call_reventry_ns",
 		Help: "Time it takes to compelete a revealentry",
@@ -187,12 +345,41 @@ func RegisterPrometheus() {
 	prometheus.MustRegister(HandleV2APICallEntry)
 	prometheus.MustRegister(HandleV2APICallECBal)
 	prometheus.MustRegister(HandleV2APICallECRate)
+	prometheus.MustRegister(HandleV2APICallECRateHistory)
 	prometheus.MustRegister(HandleV2APICallFABal)
 	prometheus.MustRegister(HandleV2APICallFctTx)
 	prometheus.MustRegister(HandleV2APICallHeights)
+	prometheus.MustRegister(HandleV2APICallStartupProgress)
+	prometheus.MustRegister(HandleV2APICallNetworkParameters)
+	prometheus.MustRegister(HandleV2APICallChainEntries)
+	prometheus.MustRegister(HandleV2APICallFctSupply)
+	prometheus.MustRegister(HandleV2APICallTimingAnomalies)
+	prometheus.MustRegister(HandleV2APICallCapacity)
+	prometheus.MustRegister(HandleV2APICallCoinbaseAudit)
+	prometheus.MustRegister(HandleV2APICallReferences)
+	prometheus.MustRegister(HandleV2APICallTransactionsByAddress)
+	prometheus.MustRegister(HandleV2APICallEntriesByExtID)
+	prometheus.MustRegister(HandleV2APICallAPIQueue)
+	prometheus.MustRegister(HandleV2APICallEthereumAnchor)
+	prometheus.MustRegister(HandleV2APICallEthereumReceipt)
+	prometheus.MustRegister(HandleV2APICallPendingPool)
+	prometheus.MustRegister(HandleV2APICallObjectPut)
+	prometheus.MustRegister(HandleV2APICallObjectGet)
+	prometheus.MustRegister(HandleV2APICallObjectDelete)
+	prometheus.MustRegister(HandleV2APICallObjectList)
+	prometheus.MustRegister(HandleV2APICallBackpressure)
+	prometheus.MustRegister(HandleV2APICallCommitRateLimits)
+	prometheus.MustRegister(HandleV2APICallRejections)
+	prometheus.MustRegister(HandleV2APICallPeerReputation)
+	prometheus.MustRegister(HandleV2APICallCreateChain)
+	prometheus.MustRegister(HandleV2APICallMultisigAddress)
+	prometheus.MustRegister(HandleV2APICallMultisigCompose)
+	prometheus.MustRegister(HandleV2APICallMultisigSign)
 	prometheus.MustRegister(HandleV2APICallProp)
 	prometheus.MustRegister(HandleV2APICallRawData)
 	prometheus.MustRegister(HandleV2APICallReceipt)
+	prometheus.MustRegister(HandleV2APICallReceiptSubscribe)
+	prometheus.MustRegister(HandleV2APICallReceiptUnsubscribe)
 	prometheus.MustRegister(HandleV2APICallRevealEntry)
 	prometheus.MustRegister(HandleV2APICallFctAck)
 	prometheus.MustRegister(HandleV2APICallEntryAck)
@@ -207,4 +394,7 @@ func RegisterPrometheus() {
 	prometheus.MustRegister(HandleV2APICallABlockByHeight)
 	prometheus.MustRegister(HandleV2APICallAuthorities)
 	prometheus.MustRegister(HandleV2APICallTpsRate)
+	prometheus.MustRegister(OIDCIntrospections)
+	prometheus.MustRegister(V1CallsVec)
+	prometheus.MustRegister(RequestsCancelledVec)
 }
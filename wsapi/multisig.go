@@ -0,0 +1,184 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package wsapi
+
+import (
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/factoid"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// Multisig transactions: custodians spend from an address any m of n keys redeem, so no
+// one hot key can move the funds.  The node composes the transaction and gives out what
+// each signer signs; each signer signs it offline and hands back the signature; once
+// enough have signed, the transaction goes out through factoid-submit.  The node never
+// sees a private key.
+
+func HandleV2MultisigAddress(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	n := time.Now()
+	defer func() { HandleV2APICallMultisigAddress.Observe(float64(time.Since(n).Nanoseconds())) }()
+
+	if !factoid.MultisigActive(state.GetLLeaderHeight()) {
+		return nil, NewCustomInvalidParamsError("Multisig is not yet in effect on this network")
+	}
+
+	req := new(MultisigAddressRequest)
+	if err := MapToObject(params, req); err != nil {
+		return nil, NewInvalidParamsError()
+	}
+	keys := make([]interfaces.IAddress, len(req.Keys))
+	for i, k := range req.Keys {
+		key, err := hex.DecodeString(k)
+		if err != nil || len(key) != constants.ADDRESS_LENGTH {
+			return nil, NewCustomInvalidParamsError(fmt.Sprintf("Key %d is not a hex public key", i))
+		}
+		keys[i] = factoid.NewAddress(key)
+	}
+	rcd, err := factoid.NewMultisigRCD(req.Required, len(keys), keys)
+	if err != nil {
+		return nil, NewCustomInvalidParamsError(err.Error())
+	}
+
+	data, err := rcd.MarshalBinary()
+	if err != nil {
+		return nil, NewInternalError()
+	}
+	address, err := rcd.GetAddress()
+	if err != nil {
+		return nil, NewInternalError()
+	}
+	return &MultisigAddressResponse{Address: primitives.ConvertFctAddressToUserStr(address), RCD: hex.EncodeToString(data)}, nil
+}
+
+func HandleV2MultisigCompose(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	n := time.Now()
+	defer func() { HandleV2APICallMultisigCompose.Observe(float64(time.Since(n).Nanoseconds())) }()
+
+	if !factoid.MultisigActive(state.GetLLeaderHeight()) {
+		return nil, NewCustomInvalidParamsError("Multisig is not yet in effect on this network")
+	}
+
+	req := new(MultisigComposeRequest)
+	if err := MapToObject(params, req); err != nil {
+		return nil, NewInvalidParamsError()
+	}
+	if len(req.Inputs) == 0 {
+		return nil, NewCustomInvalidParamsError("A transaction needs at least one input")
+	}
+	if len(req.Inputs) > 255 || len(req.Outputs) > 255 || len(req.ECOutputs) > 255 {
+		return nil, NewCustomInvalidParamsError("A transaction has at most 255 inputs, outputs and entry credit outputs")
+	}
+
+	tx := new(factoid.Transaction)
+	tx.SetTimestamp(state.GetTimestamp())
+	for i, in := range req.Inputs {
+		data, err := hex.DecodeString(in.RCD)
+		if err != nil {
+			return nil, NewCustomInvalidParamsError(fmt.Sprintf("Input %d has no hex RCD", i))
+		}
+		rcd, rest, err := factoid.UnmarshalBinaryAuth(data)
+		if err != nil || len(rest) != 0 {
+			return nil, NewCustomInvalidParamsError(fmt.Sprintf("Input %d has a bad RCD", i))
+		}
+		address, err := rcd.GetAddress()
+		if err != nil {
+			return nil, NewCustomInvalidParamsError(fmt.Sprintf("Input %d has a bad RCD", i))
+		}
+		tx.AddInput(address, in.Amount)
+		tx.AddAuthorization(rcd)
+	}
+	for i, out := range req.Outputs {
+		if !primitives.ValidateFUserStr(out.Address) {
+			return nil, NewCustomInvalidParamsError(fmt.Sprintf("Output %d is not a factoid address", i))
+		}
+		tx.AddOutput(factoid.NewAddress(primitives.ConvertUserStrToAddress(out.Address)), out.Amount)
+	}
+	for i, out := range req.ECOutputs {
+		if !primitives.ValidateECUserStr(out.Address) {
+			return nil, NewCustomInvalidParamsError(fmt.Sprintf("Entry credit output %d is not an entry credit address", i))
+		}
+		tx.AddECOutput(factoid.NewAddress(primitives.ConvertUserStrToAddress(out.Address)), out.Amount)
+	}
+	if err := tx.Validate(1); err != nil {
+		return nil, NewCustomInvalidParamsError(err.Error())
+	}
+
+	return multisigTransaction(state, tx)
+}
+
+func HandleV2MultisigSign(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	n := time.Now()
+	defer func() { HandleV2APICallMultisigSign.Observe(float64(time.Since(n).Nanoseconds())) }()
+
+	req := new(MultisigSignRequest)
+	if err := MapToObject(params, req); err != nil {
+		return nil, NewInvalidParamsError()
+	}
+	data, err := hex.DecodeString(req.Transaction)
+	if err != nil {
+		return nil, NewUnableToDecodeTransactionError()
+	}
+	tx := new(factoid.Transaction)
+	if err := tx.UnmarshalBinary(data); err != nil {
+		return nil, NewUnableToDecodeTransactionError()
+	}
+	sig, err := hex.DecodeString(req.Signature)
+	if err != nil {
+		return nil, NewInvalidDataPassedError()
+	}
+	if _, err := tx.AddMultisigSignature(req.Input, sig); err != nil {
+		return nil, NewCustomInvalidParamsError(err.Error())
+	}
+
+	return multisigTransaction(state, tx)
+}
+
+// multisigTransaction returns a transaction being signed, and who has signed it
+func multisigTransaction(state interfaces.IState, tx *factoid.Transaction) (interface{}, *primitives.JSONError) {
+	data, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, NewInternalError()
+	}
+	sigdata, err := tx.MarshalBinarySig()
+	if err != nil {
+		return nil, NewInternalError()
+	}
+	fee, err := tx.CalculateFee(state.GetFactoshisPerEC())
+	if err != nil {
+		return nil, NewCustomInvalidParamsError(err.Error())
+	}
+
+	resp := new(MultisigTransactionResponse)
+	resp.Transaction = hex.EncodeToString(data)
+	resp.TxID = tx.GetSigHash().String()
+	resp.SigData = hex.EncodeToString(sigdata)
+	resp.Fee = fee
+	resp.Complete = true
+	sigblks := tx.GetSignatureBlocks()
+	for i, rcd := range tx.GetRCDs() {
+		status := MultisigInputStatus{Required: rcd.NumberOfSignatures(), SignedBy: []int{}}
+		if address, err := rcd.GetAddress(); err == nil {
+			status.Address = primitives.ConvertFctAddressToUserStr(address)
+		}
+		switch r := rcd.(type) {
+		case *factoid.RCD_2:
+			status.SignedBy = append(status.SignedBy, r.SignedBy(tx, sigblks[i])...)
+		default:
+			if rcd.CheckSig(tx, sigblks[i]) {
+				status.SignedBy = append(status.SignedBy, 0)
+			}
+		}
+		if len(status.SignedBy) < status.Required {
+			resp.Complete = false
+		}
+		resp.Inputs = append(resp.Inputs, status)
+	}
+	return resp, nil
+}
@@ -0,0 +1,134 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package wsapi
+
+import (
+	"encoding/hex"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+)
+
+// The object store: services running next to the node keep small objects, such as the
+// indexes they derive from the chains, in its database rather than deploying their own.
+// Anyone able to reach an open API could fill it, so it is only served with RPC
+// credentials set.
+
+// objectRequest decodes the parameters of an object call.  The key is only decoded if
+// withKey.
+func objectRequest(state interfaces.IState, params interface{}, withKey bool) (*ObjectRequest, interfaces.IHash, *primitives.JSONError) {
+	if state.GetRpcUser() == "" {
+		return nil, nil, NewCustomInvalidRequestError("The object store needs FactomdRpcUser and FactomdRpcPass to be set")
+	}
+	req := new(ObjectRequest)
+	if err := MapToObject(params, req); err != nil {
+		return nil, nil, NewInvalidParamsError()
+	}
+	if !withKey {
+		return req, nil, nil
+	}
+	key, err := primitives.HexToHash(req.Key)
+	if err != nil {
+		return nil, nil, NewInvalidHashError()
+	}
+	return req, key, nil
+}
+
+// objectError returns the error response for an error of the object store
+func objectError(err error) *primitives.JSONError {
+	switch err {
+	case databaseOverlay.ErrNoObjectStore, databaseOverlay.ErrInvalidNamespace,
+		databaseOverlay.ErrObjectTooLarge, databaseOverlay.ErrObjectNamespaceFull:
+		return NewCustomInvalidParamsError(err.Error())
+	}
+	return NewInternalDatabaseError()
+}
+
+func HandleV2ObjectPut(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	n := time.Now()
+	defer func() { HandleV2APICallObjectPut.Observe(float64(time.Since(n).Nanoseconds())) }()
+
+	req, key, jsonError := objectRequest(state, params, true)
+	if jsonError != nil {
+		return nil, jsonError
+	}
+	data, err := hex.DecodeString(req.Data)
+	if err != nil {
+		return nil, NewInvalidDataPassedError()
+	}
+
+	dbase := state.GetAndLockDB()
+	defer state.UnlockDB()
+
+	if err := dbase.SaveObject(req.Namespace, key, data); err != nil {
+		return nil, objectError(err)
+	}
+	return &ObjectResponse{Namespace: req.Namespace, Key: key.String()}, nil
+}
+
+func HandleV2ObjectGet(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	n := time.Now()
+	defer func() { HandleV2APICallObjectGet.Observe(float64(time.Since(n).Nanoseconds())) }()
+
+	req, key, jsonError := objectRequest(state, params, true)
+	if jsonError != nil {
+		return nil, jsonError
+	}
+
+	dbase := state.GetAndLockDB()
+	defer state.UnlockDB()
+
+	data, err := dbase.FetchObject(req.Namespace, key)
+	if err != nil {
+		return nil, objectError(err)
+	}
+	if data == nil {
+		return nil, NewObjectNotFoundError()
+	}
+	return &ObjectResponse{Namespace: req.Namespace, Key: key.String(), Data: hex.EncodeToString(data)}, nil
+}
+
+func HandleV2ObjectDelete(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	n := time.Now()
+	defer func() { HandleV2APICallObjectDelete.Observe(float64(time.Since(n).Nanoseconds())) }()
+
+	req, key, jsonError := objectRequest(state, params, true)
+	if jsonError != nil {
+		return nil, jsonError
+	}
+
+	dbase := state.GetAndLockDB()
+	defer state.UnlockDB()
+
+	if err := dbase.DeleteObject(req.Namespace, key); err != nil {
+		return nil, objectError(err)
+	}
+	return &ObjectResponse{Namespace: req.Namespace, Key: key.String()}, nil
+}
+
+func HandleV2ObjectList(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	n := time.Now()
+	defer func() { HandleV2APICallObjectList.Observe(float64(time.Since(n).Nanoseconds())) }()
+
+	req, _, jsonError := objectRequest(state, params, false)
+	if jsonError != nil {
+		return nil, jsonError
+	}
+
+	dbase := state.GetAndLockDB()
+	defer state.UnlockDB()
+
+	keys, err := dbase.FetchObjectKeys(req.Namespace)
+	if err != nil {
+		return nil, objectError(err)
+	}
+	resp := &ObjectListResponse{Namespace: req.Namespace, Keys: make([]string, 0, len(keys))}
+	for _, key := range keys {
+		resp.Keys = append(resp.Keys, key.String())
+	}
+	return resp, nil
+}
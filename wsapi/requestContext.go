@@ -0,0 +1,57 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package wsapi
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// contextState hands the handlers of an API request a database whose reads stop once the
+// request is cancelled, so the handlers don't need to know about the request's context
+type contextState struct {
+	interfaces.IState
+	db interfaces.DBOverlaySimple
+}
+
+func (c *contextState) GetAndLockDB() interfaces.DBOverlaySimple {
+	return c.db
+}
+
+// NewContextState wraps state so its database reads fail once ctx is done.  The state is
+// returned as it is if its database can't be bound to a context.
+func NewContextState(state interfaces.IState, ctx context.Context) interfaces.IState {
+	dbase, ok := state.GetAndLockDB().(interfaces.ContextDBOverlay)
+	if !ok {
+		return state
+	}
+	return &contextState{IState: state, db: dbase.WithContext(ctx)}
+}
+
+// requestContext returns the context to handle an API request in: cancelled when its
+// client goes away, or when the request has taken longer than the node allows
+func requestContext(r *http.Request, state interfaces.IState) (context.Context, context.CancelFunc) {
+	if timeout := state.GetAPIRequestTimeout(); timeout > 0 {
+		return context.WithTimeout(r.Context(), timeout)
+	}
+	return context.WithCancel(r.Context())
+}
+
+// cancelledError returns the error to answer a request that failed once its context was
+// done, counting the request as timed out or abandoned by its client.  The error the
+// handler returned stands if the context wasn't done.
+func cancelledError(ctx context.Context, jsonError *primitives.JSONError) *primitives.JSONError {
+	switch ctx.Err() {
+	case context.DeadlineExceeded:
+		RequestsCancelledVec.WithLabelValues("timeout").Inc()
+		return NewRequestTimeoutError()
+	case context.Canceled:
+		RequestsCancelledVec.WithLabelValues("disconnect").Inc()
+	}
+	return jsonError
+}
@@ -0,0 +1,57 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package wsapi
+
+import (
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/web"
+)
+
+// tracedState hands the trace context of an API request on to every message the request
+// submits, so the submission handlers don't need to know about tracing
+type tracedState struct {
+	interfaces.IState
+	trace *interfaces.TraceContext
+}
+
+func (t *tracedState) APIQueue() interfaces.IQueue {
+	return &tracedQueue{IQueue: t.IState.APIQueue(), state: t}
+}
+
+type tracedQueue struct {
+	interfaces.IQueue
+	state *tracedState
+}
+
+func (q *tracedQueue) Enqueue(msg interfaces.IMsg) {
+	q.state.IState.TraceMessage(msg.GetMsgHash(), q.state.trace)
+	q.IQueue.Enqueue(msg)
+}
+
+func (t *tracedState) SubmitAPIMsg(msg interfaces.IMsg) interfaces.APISubmission {
+	t.IState.TraceMessage(msg.GetMsgHash(), t.trace)
+	return t.IState.SubmitAPIMsg(msg)
+}
+
+// NewTracedState wraps state so the messages submitted through it are traced
+func NewTracedState(state interfaces.IState, trace *interfaces.TraceContext) interfaces.IState {
+	return &tracedState{IState: state, trace: trace}
+}
+
+// requestState returns the state to handle an API request with, tracing its submissions
+// if the request carries a W3C traceparent header.  Malformed headers are ignored, as the
+// trace context spec asks.
+func requestState(ctx *web.Context, state interfaces.IState) interfaces.IState {
+	header := ctx.Request.Header.Get("traceparent")
+	if header == "" {
+		return state
+	}
+	trace, err := primitives.ParseTraceParent(header)
+	if err != nil {
+		return state
+	}
+	return NewTracedState(state, trace)
+}
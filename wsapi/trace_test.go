@@ -0,0 +1,30 @@
+package wsapi_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/common/entryCreditBlock"
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/testHelper"
+	. "github.com/FactomProject/factomd/wsapi"
+)
+
+func TestTracedState(t *testing.T) {
+	s := testHelper.CreateEmptyTestState()
+	tc, err := primitives.ParseTraceParent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg := messages.NewCommitEntryMsg()
+	msg.CommitEntry = entryCreditBlock.NewCommitEntry()
+	NewTracedState(s, tc).APIQueue().Enqueue(msg)
+
+	if s.MessageTraces.Len() != 1 {
+		t.Errorf("Tracing %d messages, expected 1", s.MessageTraces.Len())
+	}
+	if s.APIQueue().Length() != 1 {
+		t.Errorf("Message was not queued")
+	}
+}
@@ -0,0 +1,418 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package wsapi
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/web"
+)
+
+// A minimal server side implementation of RFC 6455, enough to push JSON events to
+// clients.  Frames sent by the client are read and discarded, except for close.
+
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+const (
+	wsOpText  = 0x1
+	wsOpClose = 0x8
+	wsOpPing  = 0x9
+	wsOpPong  = 0xA
+)
+
+// How long we wait on a write to a websocket client before giving up on it
+var WebsocketWriteTimeout = 10 * time.Second
+
+// WebsocketConn is an upgraded connection that we can push text frames down
+type WebsocketConn struct {
+	conn       net.Conn
+	rw         *bufio.ReadWriter
+	writeMutex sync.Mutex    // Pongs from the read loop and pushed frames share the writer
+	Closed     chan struct{} // Closed when the client goes away
+}
+
+// WebsocketAccept computes the Sec-WebSocket-Accept value for a client key
+func WebsocketAccept(key string) string {
+	h := sha1.New()
+	h.Write([]byte(key + websocketGUID))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// UpgradeWebsocket performs the websocket handshake on the request, and hijacks the
+// underlying connection.
+func UpgradeWebsocket(ctx *web.Context) (*WebsocketConn, error) {
+	r := ctx.Request
+	if !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") ||
+		!strings.Contains(strings.ToLower(r.Header.Get("Connection")), "upgrade") {
+		return nil, errors.New("not a websocket handshake")
+	}
+	if r.Header.Get("Sec-Websocket-Version") != "13" {
+		return nil, errors.New("unsupported websocket version")
+	}
+	key := r.Header.Get("Sec-Websocket-Key")
+	if key == "" {
+		return nil, errors.New("missing Sec-WebSocket-Key")
+	}
+
+	hj, ok := ctx.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, errors.New("connection cannot be hijacked")
+	}
+	conn, rw, err := hj.Hijack()
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintf(rw, "HTTP/1.1 101 Switching Protocols\r\n")
+	fmt.Fprintf(rw, "Upgrade: websocket\r\n")
+	fmt.Fprintf(rw, "Connection: Upgrade\r\n")
+	fmt.Fprintf(rw, "Sec-WebSocket-Accept: %s\r\n\r\n", WebsocketAccept(key))
+	if err := rw.Flush(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	ws := new(WebsocketConn)
+	ws.conn = conn
+	ws.rw = rw
+	ws.Closed = make(chan struct{})
+	go ws.readLoop()
+	return ws, nil
+}
+
+// readLoop drains frames from the client, answering pings, until the client closes
+// the connection or it fails.
+func (ws *WebsocketConn) readLoop() {
+	defer close(ws.Closed)
+	for {
+		op, payload, err := readWebsocketFrame(ws.rw.Reader)
+		if err != nil || op == wsOpClose {
+			return
+		}
+		if op == wsOpPing {
+			ws.writeFrame(wsOpPong, payload)
+		}
+	}
+}
+
+func (ws *WebsocketConn) WriteJSON(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return ws.writeFrame(wsOpText, data)
+}
+
+func (ws *WebsocketConn) writeFrame(op byte, payload []byte) error {
+	ws.writeMutex.Lock()
+	defer ws.writeMutex.Unlock()
+	ws.conn.SetWriteDeadline(time.Now().Add(WebsocketWriteTimeout))
+	if _, err := ws.rw.Write(EncodeWebsocketFrame(op, payload)); err != nil {
+		return err
+	}
+	return ws.rw.Flush()
+}
+
+func (ws *WebsocketConn) Close() {
+	ws.writeFrame(wsOpClose, nil)
+	ws.conn.Close()
+}
+
+// EncodeWebsocketFrame builds a single, final, unmasked frame (server to client)
+func EncodeWebsocketFrame(op byte, payload []byte) []byte {
+	frame := []byte{0x80 | op}
+	l := len(payload)
+	switch {
+	case l < 126:
+		frame = append(frame, byte(l))
+	case l <= 0xFFFF:
+		frame = append(frame, 126, byte(l>>8), byte(l))
+	default:
+		var b [8]byte
+		binary.BigEndian.PutUint64(b[:], uint64(l))
+		frame = append(frame, 127)
+		frame = append(frame, b[:]...)
+	}
+	return append(frame, payload...)
+}
+
+// readWebsocketFrame reads one frame, removing the client mask if present
+func readWebsocketFrame(r io.Reader) (op byte, payload []byte, err error) {
+	var hdr [2]byte
+	if _, err = io.ReadFull(r, hdr[:]); err != nil {
+		return
+	}
+	op = hdr[0] & 0x0F
+	masked := hdr[1]&0x80 != 0
+	l := uint64(hdr[1] & 0x7F)
+	switch l {
+	case 126:
+		var b [2]byte
+		if _, err = io.ReadFull(r, b[:]); err != nil {
+			return
+		}
+		l = uint64(binary.BigEndian.Uint16(b[:]))
+	case 127:
+		var b [8]byte
+		if _, err = io.ReadFull(r, b[:]); err != nil {
+			return
+		}
+		l = binary.BigEndian.Uint64(b[:])
+	}
+	// We don't expect clients to send us anything of size
+	if l > 1<<16 {
+		err = errors.New("websocket frame too large")
+		return
+	}
+	var mask [4]byte
+	if masked {
+		if _, err = io.ReadFull(r, mask[:]); err != nil {
+			return
+		}
+	}
+	payload = make([]byte, l)
+	if _, err = io.ReadFull(r, payload); err != nil {
+		return
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+	return
+}
+
+// HandleStatusEvents upgrades the connection to a websocket and pushes node status
+// transitions (sync, leadership, elections, stalls) to the client as JSON.
+func HandleStatusEvents(ctx *web.Context) {
+	ServersMutex.Lock()
+	state := ctx.Server.Env["state"].(interfaces.IState)
+	ServersMutex.Unlock()
+
+	if err := checkAuthHeader(state, ctx.Request); err != nil {
+		remoteIP := ""
+		remoteIP += strings.Split(ctx.Request.RemoteAddr, ":")[0]
+		fmt.Printf("Unauthorized websocket API client connection attempt from %s\n", remoteIP)
+		ctx.ResponseWriter.Header().Add("WWW-Authenticate", `Basic realm="factomd RPC"`)
+		http.Error(ctx.ResponseWriter, "401 Unauthorized.", http.StatusUnauthorized)
+		return
+	}
+
+	ws, err := UpgradeWebsocket(ctx)
+	if err != nil {
+		http.Error(ctx.ResponseWriter, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer ws.Close()
+
+	id, events := state.SubscribeStatusEvents()
+	defer state.UnsubscribeStatusEvents(id)
+
+	for {
+		select {
+		case <-ws.Closed:
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if err := ws.WriteJSON(event); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// Most entries one /v2/receipt-notices connection may wait on
+const MaxReceiptNoticeEntries = 100
+
+// HandleReceiptNotices upgrades the connection to a websocket, subscribes to the receipts
+// of the entries given as entry parameters, and pushes each receipt to the client once
+// the entry's anchor has the confirmations asked for (6 by default).  The connection is
+// closed once every receipt has been sent; closing it first cancels the subscriptions.
+//
+//	/v2/receipt-notices?entry=<hash>&entry=<hash>&confirmations=6
+func HandleReceiptNotices(ctx *web.Context) {
+	ServersMutex.Lock()
+	state := ctx.Server.Env["state"].(interfaces.IState)
+	ServersMutex.Unlock()
+
+	if err := checkAuthHeader(state, ctx.Request); err != nil {
+		remoteIP := ""
+		remoteIP += strings.Split(ctx.Request.RemoteAddr, ":")[0]
+		fmt.Printf("Unauthorized websocket API client connection attempt from %s\n", remoteIP)
+		ctx.ResponseWriter.Header().Add("WWW-Authenticate", `Basic realm="factomd RPC"`)
+		http.Error(ctx.ResponseWriter, "401 Unauthorized.", http.StatusUnauthorized)
+		return
+	}
+
+	query := ctx.Request.URL.Query()
+	entries := query["entry"]
+	if len(entries) == 0 || len(entries) > MaxReceiptNoticeEntries {
+		http.Error(ctx.ResponseWriter, fmt.Sprintf("Between 1 and %d entry parameters are needed", MaxReceiptNoticeEntries), http.StatusBadRequest)
+		return
+	}
+	confirmations := 0
+	if c := query.Get("confirmations"); c != "" {
+		var err error
+		if confirmations, err = strconv.Atoi(c); err != nil {
+			http.Error(ctx.ResponseWriter, "Invalid confirmations", http.StatusBadRequest)
+			return
+		}
+	}
+	var hashes []interfaces.IHash
+	for _, e := range entries {
+		h, err := primitives.HexToHash(e)
+		if err != nil {
+			http.Error(ctx.ResponseWriter, "Invalid entry hash "+e, http.StatusBadRequest)
+			return
+		}
+		hashes = append(hashes, h)
+	}
+
+	// Watch before subscribing, so no notice can be missed
+	id, notices := state.WatchReceiptNotices()
+	defer state.UnwatchReceiptNotices(id)
+
+	waiting := make(map[string]bool)
+	defer func() {
+		for sub := range waiting {
+			state.UnsubscribeReceipt(sub)
+		}
+	}()
+	for _, h := range hashes {
+		sub, err := state.SubscribeReceipt(h, confirmations, "")
+		if err != nil {
+			http.Error(ctx.ResponseWriter, err.Error(), http.StatusBadRequest)
+			return
+		}
+		waiting[sub.ID] = true
+	}
+
+	ws, err := UpgradeWebsocket(ctx)
+	if err != nil {
+		http.Error(ctx.ResponseWriter, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer ws.Close()
+
+	for len(waiting) > 0 {
+		select {
+		case <-ws.Closed:
+			return
+		case notice, ok := <-notices:
+			if !ok {
+				return
+			}
+			if !waiting[notice.SubscriptionID] {
+				continue
+			}
+			delete(waiting, notice.SubscriptionID)
+			if err := ws.WriteJSON(notice); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// Most chains and addresses one /v2/events connection may filter on
+const MaxEventFilterItems = 1000
+
+// HandleEvents upgrades the connection to a websocket and pushes the events the query
+// asks for as JSON: saved directory blocks, entries and factoid transactions, and node
+// status changes.  Entries can be limited to some chains, and transactions to those
+// touching some addresses.
+//
+//	/v2/events?type=entry&type=factoid-transaction&chain=<chainid>&address=<FA or EC address>
+func HandleEvents(ctx *web.Context) {
+	ServersMutex.Lock()
+	state := ctx.Server.Env["state"].(interfaces.IState)
+	ServersMutex.Unlock()
+
+	if err := checkAuthHeader(state, ctx.Request); err != nil {
+		remoteIP := ""
+		remoteIP += strings.Split(ctx.Request.RemoteAddr, ":")[0]
+		fmt.Printf("Unauthorized websocket API client connection attempt from %s\n", remoteIP)
+		ctx.ResponseWriter.Header().Add("WWW-Authenticate", `Basic realm="factomd RPC"`)
+		http.Error(ctx.ResponseWriter, "401 Unauthorized.", http.StatusUnauthorized)
+		return
+	}
+
+	filter, err := ParseEventFilter(ctx.Request.URL.Query())
+	if err != nil {
+		http.Error(ctx.ResponseWriter, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ws, err := UpgradeWebsocket(ctx)
+	if err != nil {
+		http.Error(ctx.ResponseWriter, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer ws.Close()
+
+	id, events := state.SubscribeEvents(filter)
+	defer state.UnsubscribeEvents(id)
+
+	for {
+		select {
+		case <-ws.Closed:
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if err := ws.WriteJSON(event); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// ParseEventFilter reads the type, chain and address parameters of a /v2/events request
+func ParseEventFilter(query url.Values) (interfaces.EventFilter, error) {
+	var filter interfaces.EventFilter
+	for _, t := range query["type"] {
+		switch t {
+		case interfaces.EventDirectoryBlock, interfaces.EventEntry, interfaces.EventFactoidTransaction, interfaces.EventNodeStatus:
+			filter.Types = append(filter.Types, t)
+		default:
+			return filter, fmt.Errorf("Unknown event type %s", t)
+		}
+	}
+	if len(query["chain"])+len(query["address"]) > MaxEventFilterItems {
+		return filter, fmt.Errorf("At most %d chain and address parameters are taken", MaxEventFilterItems)
+	}
+	for _, c := range query["chain"] {
+		h, err := primitives.HexToHash(c)
+		if err != nil {
+			return filter, fmt.Errorf("Invalid chain ID %s", c)
+		}
+		filter.ChainIDs = append(filter.ChainIDs, h.String())
+	}
+	for _, a := range query["address"] {
+		if !primitives.ValidateFUserStr(a) && !primitives.ValidateECUserStr(a) {
+			return filter, fmt.Errorf("Invalid address %s", a)
+		}
+		filter.Addresses = append(filter.Addresses, a)
+	}
+	return filter, nil
+}
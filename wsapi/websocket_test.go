@@ -0,0 +1,62 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package wsapi_test
+
+import (
+	"bytes"
+	"net/url"
+	"testing"
+
+	. "github.com/FactomProject/factomd/wsapi"
+)
+
+func TestWebsocketAccept(t *testing.T) {
+	// Example from RFC 6455 section 1.3
+	if a := WebsocketAccept("dGhlIHNhbXBsZSBub25jZQ=="); a != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Errorf("Wrong accept value %s", a)
+	}
+}
+
+func TestEncodeWebsocketFrame(t *testing.T) {
+	f := EncodeWebsocketFrame(0x1, []byte("Hello"))
+	if !bytes.Equal(f, []byte{0x81, 0x05, 'H', 'e', 'l', 'l', 'o'}) {
+		t.Errorf("Wrong frame %x", f)
+	}
+
+	f = EncodeWebsocketFrame(0x1, make([]byte, 256))
+	if len(f) != 256+4 || f[1] != 126 || f[2] != 1 || f[3] != 0 {
+		t.Errorf("Wrong header for medium frame %x", f[:4])
+	}
+
+	f = EncodeWebsocketFrame(0x1, make([]byte, 70000))
+	if len(f) != 70000+10 || f[1] != 127 {
+		t.Errorf("Wrong header for large frame %x", f[:10])
+	}
+}
+
+func TestParseEventFilter(t *testing.T) {
+	chain := "888888b2e7c7c5bdf6e3b5a4d4d1a4fd5a3f1e9c5d1c3f3d4f3a1e5c2d7d8f9a"
+	q := url.Values{}
+	q.Add("type", "entry")
+	q.Add("chain", chain)
+	q.Add("address", "EC2DKSYyRcNWf7RS963VFYgMExoHRYLHVeCfQ9PGPmNzwrcmgm2r")
+	f, err := ParseEventFilter(q)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if len(f.Types) != 1 || len(f.ChainIDs) != 1 || f.ChainIDs[0] != chain || len(f.Addresses) != 1 {
+		t.Errorf("Unexpected filter %+v", f)
+	}
+
+	for _, bad := range []url.Values{
+		{"type": {"blocks"}},
+		{"chain": {"xyz"}},
+		{"address": {"FA-not-an-address"}},
+	} {
+		if _, err := ParseEventFilter(bad); err == nil {
+			t.Errorf("Expected an error for %v", bad)
+		}
+	}
+}
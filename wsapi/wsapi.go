@@ -59,33 +59,51 @@ func Start(state interfaces.IState) {
 		Servers[state.GetPort()] = server
 		server.Env["state"] = state
 
-		server.Post("/v1/factoid-submit/?", HandleFactoidSubmit)
-		server.Post("/v1/commit-chain/?", HandleCommitChain)
-		server.Post("/v1/reveal-chain/?", HandleRevealChain)
-		server.Post("/v1/commit-entry/?", HandleCommitEntry)
-		server.Post("/v1/reveal-entry/?", HandleRevealEntry)
-		server.Get("/v1/directory-block-head/?", HandleDirectoryBlockHead)
-		server.Get("/v1/get-raw-data/([^/]+)", HandleGetRaw)
-		server.Get("/v1/get-receipt/([^/]+)", HandleGetReceipt)
-		server.Get("/v1/directory-block-by-keymr/([^/]+)", HandleDirectoryBlock)
-		server.Get("/v1/directory-block-height/?", HandleDirectoryBlockHeight)
-		server.Get("/v1/entry-block-by-keymr/([^/]+)", HandleEntryBlock)
-		server.Get("/v1/entry-by-hash/([^/]+)", HandleEntry)
-		server.Get("/v1/chain-head/([^/]+)", HandleChainHead)
-		server.Get("/v1/entry-credit-balance/([^/]+)", HandleEntryCreditBalance)
-		server.Get("/v1/factoid-balance/([^/]+)", HandleFactoidBalance)
-		server.Get("/v1/factoid-get-fee/", HandleGetFee)
-		server.Get("/v1/properties/", HandleProperties)
-		server.Get("/v1/heights/", HandleHeights)
-
-		server.Get("/v1/dblock-by-height/([^/]+)", HandleDBlockByHeight)
-		server.Get("/v1/ecblock-by-height/([^/]+)", HandleECBlockByHeight)
-		server.Get("/v1/fblock-by-height/([^/]+)", HandleFBlockByHeight)
-		server.Get("/v1/ablock-by-height/([^/]+)", HandleABlockByHeight)
+		if err := StartAccessLog(state.GetAPIAccessLog()); err != nil {
+			fmt.Printf("Unable to open the API access log: %v\n", err)
+		}
+		if err := StartAuditLog(state.GetAPIAuditLog()); err != nil {
+			fmt.Printf("Unable to open the API audit log: %v\n", err)
+		}
+		if err := StartIdentityProvider(state.GetAPIAuth()); err != nil {
+			fmt.Printf("Unable to start the API identity provider: %v\n", err)
+		}
+
+		server.Post("/v1/factoid-submit/?", v1Route("factoid-submit", HandleFactoidSubmit))
+		server.Post("/v1/commit-chain/?", v1Route("commit-chain", HandleCommitChain))
+		server.Post("/v1/reveal-chain/?", v1Route("reveal-chain", HandleRevealChain))
+		server.Post("/v1/commit-entry/?", v1Route("commit-entry", HandleCommitEntry))
+		server.Post("/v1/reveal-entry/?", v1Route("reveal-entry", HandleRevealEntry))
+		server.Get("/v1/directory-block-head/?", v1Route("directory-block-head", HandleDirectoryBlockHead))
+		server.Get("/v1/get-raw-data/([^/]+)", v1RouteParam("get-raw-data", HandleGetRaw))
+		server.Get("/v1/get-receipt/([^/]+)", v1RouteParam("get-receipt", HandleGetReceipt))
+		server.Get("/v1/directory-block-by-keymr/([^/]+)", v1RouteParam("directory-block-by-keymr", HandleDirectoryBlock))
+		server.Get("/v1/directory-block-height/?", v1Route("directory-block-height", HandleDirectoryBlockHeight))
+		server.Get("/v1/entry-block-by-keymr/([^/]+)", v1RouteParam("entry-block-by-keymr", HandleEntryBlock))
+		server.Get("/v1/entry-by-hash/([^/]+)", v1RouteParam("entry-by-hash", HandleEntry))
+		server.Get("/v1/chain-head/([^/]+)", v1RouteParam("chain-head", HandleChainHead))
+		server.Get("/v1/entry-credit-balance/([^/]+)", v1RouteParam("entry-credit-balance", HandleEntryCreditBalance))
+		server.Get("/v1/factoid-balance/([^/]+)", v1RouteParam("factoid-balance", HandleFactoidBalance))
+		server.Get("/v1/factoid-get-fee/", v1Route("factoid-get-fee", HandleGetFee))
+		server.Get("/v1/properties/", v1Route("properties", HandleProperties))
+		server.Get("/v1/heights/", v1Route("heights", HandleHeights))
+
+		server.Get("/v1/dblock-by-height/([^/]+)", v1RouteParam("dblock-by-height", HandleDBlockByHeight))
+		server.Get("/v1/ecblock-by-height/([^/]+)", v1RouteParam("ecblock-by-height", HandleECBlockByHeight))
+		server.Get("/v1/fblock-by-height/([^/]+)", v1RouteParam("fblock-by-height", HandleFBlockByHeight))
+		server.Get("/v1/ablock-by-height/([^/]+)", v1RouteParam("ablock-by-height", HandleABlockByHeight))
 
 		server.Post("/v2", HandleV2)
 		server.Get("/v2", HandleV2)
 
+		server.Get("/v2/status-events", HandleStatusEvents)
+		server.Get("/v2/receipt-notices", HandleReceiptNotices)
+		server.Get("/v2/events", HandleEvents)
+		server.Get("/v2/entry-content/([^/]+)", HandleEntryContent)
+		server.Match("HEAD", "/v2/entry-content/([^/]+)", HandleEntryContent)
+		server.Get("/v2/cluster/dbstate/([^/]+)", HandleClusterDBState)
+		server.Get("/v2/cluster/entry/([^/]+)", HandleClusterEntry)
+
 		// start the debugging api if we are not on the main network
 		if state.GetNetworkName() != "MAIN" {
 			server.Post("/debug", HandleDebug)
@@ -136,6 +154,8 @@ func Stop(state interfaces.IState) {
 	defer ServersMutex.Unlock()
 
 	Servers[state.GetPort()].Close()
+	StopAccessLog()
+	StopAuditLog()
 }
 
 func handleV1Error(ctx *web.Context, err *primitives.JSONError) {
@@ -277,7 +297,7 @@ func HandleCommitChain(ctx *web.Context) {
 	}
 	param := MessageRequest{Message: c.CommitChainMsg}
 	req := primitives.NewJSON2Request("commit-chain", 1, param)
-	_, jsonError := HandleV2Request(state, req)
+	_, jsonError := HandleV2Request(requestState(ctx, state), req)
 
 	if jsonError != nil {
 		returnV1(ctx, nil, jsonError)
@@ -324,7 +344,7 @@ func HandleCommitEntry(ctx *web.Context) {
 	param := MessageRequest{Message: c.CommitEntryMsg}
 	req := primitives.NewJSON2Request("commit-entry", 1, param)
 
-	_, jsonError := HandleV2Request(state, req)
+	_, jsonError := HandleV2Request(requestState(ctx, state), req)
 	if jsonError != nil {
 		returnV1(ctx, nil, jsonError)
 		return
@@ -364,7 +384,7 @@ func HandleRevealEntry(ctx *web.Context) {
 	param := EntryRequest{Entry: e.Entry}
 	req := primitives.NewJSON2Request("reveal-entry", 1, param)
 
-	_, jsonError := HandleV2Request(state, req)
+	_, jsonError := HandleV2Request(requestState(ctx, state), req)
 	if jsonError != nil {
 		returnV1(ctx, nil, jsonError)
 		return
@@ -703,7 +723,7 @@ func HandleFactoidSubmit(ctx *web.Context) {
 	param := TransactionRequest{Transaction: t.Transaction}
 	req := primitives.NewJSON2Request("factoid-submit", 1, param)
 
-	jsonResp, jsonError := HandleV2Request(state, req)
+	jsonResp, jsonError := HandleV2Request(requestState(ctx, state), req)
 	if jsonError != nil {
 		returnV1(ctx, nil, jsonError)
 		return
@@ -846,7 +866,14 @@ func httpBasicAuth(username, password string) []byte {
 	return output
 }
 
+// checkAuthHeader checks that the request is of a client that may read
 func checkAuthHeader(state interfaces.IState, r *http.Request) error {
+	_, err := authorize(state, r, PermissionRead)
+	return err
+}
+
+// checkBasicAuth checks the request has the RPC user and password
+func checkBasicAuth(state interfaces.IState, r *http.Request) error {
 	if "" == state.GetRpcUser() {
 		//no username was specified in the config file or command line, meaning factomd API is open access
 		return nil
@@ -871,7 +898,12 @@ func checkAuthHeader(state interfaces.IState, r *http.Request) error {
 }
 
 func checkHttpPasswordOkV1(state interfaces.IState, ctx *web.Context) bool {
-	if err := checkAuthHeader(state, ctx.Request); err != nil {
+	// The V1 API writes with POST
+	permission := PermissionRead
+	if ctx.Request.Method == "POST" {
+		permission = PermissionWrite
+	}
+	if _, err := authorize(state, ctx.Request, permission); err != nil {
 		remoteIP := ""
 		remoteIP += strings.Split(ctx.Request.RemoteAddr, ":")[0]
 		fmt.Printf("Unauthorized V1 API client connection attempt from %s\n", remoteIP)
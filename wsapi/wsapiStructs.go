@@ -5,36 +5,55 @@
 package wsapi
 
 import (
+	"github.com/FactomProject/factomd/anchor"
 	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/common/primitives"
 	"github.com/FactomProject/factomd/receipts"
 )
 
 type FactoidSubmitResponse struct {
-	Message string `json:"message"`
-	TxID    string `json:"txid"`
+	Message    string                    `json:"message"`
+	TxID       string                    `json:"txid"`
+	Submission *interfaces.APISubmission `json:"submission,omitempty"`
 }
 
 type CommitChainResponse struct {
-	Message     string `json:"message"`
-	TxID        string `json:"txid"`
-	EntryHash   string `json:"entryhash,omitempty"`
-	ChainIDHash string `json:"chainidhash,omitempty"`
+	Message     string                    `json:"message"`
+	TxID        string                    `json:"txid"`
+	EntryHash   string                    `json:"entryhash,omitempty"`
+	ChainIDHash string                    `json:"chainidhash,omitempty"`
+	Submission  *interfaces.APISubmission `json:"submission,omitempty"`
 }
 
 type RevealChainResponse struct {
 }
 
 type CommitEntryResponse struct {
-	Message   string `json:"message"`
+	Message    string                    `json:"message"`
+	TxID       string                    `json:"txid"`
+	EntryHash  string                    `json:"entryhash,omitempty"`
+	Submission *interfaces.APISubmission `json:"submission,omitempty"`
+}
+
+// CreateChainResponse gives the chain created and its entries, the first included, in
+// the order they were submitted.  The submission is that of the chain commit.
+type CreateChainResponse struct {
+	Message    string                    `json:"message"`
+	ChainID    string                    `json:"chainid"`
+	Entries    []CreateChainEntryResult  `json:"entries"`
+	Submission *interfaces.APISubmission `json:"submission,omitempty"`
+}
+
+type CreateChainEntryResult struct {
 	TxID      string `json:"txid"`
-	EntryHash string `json:"entryhash,omitempty"`
+	EntryHash string `json:"entryhash"`
 }
 
 type RevealEntryResponse struct {
-	Message   string `json:"message"`
-	EntryHash string `json:"entryhash"`
-	ChainID   string `json:"chainid,omitempty"`
+	Message    string                    `json:"message"`
+	EntryHash  string                    `json:"entryhash"`
+	ChainID    string                    `json:"chainid,omitempty"`
+	Submission *interfaces.APISubmission `json:"submission,omitempty"`
 }
 
 type DirectoryBlockResponse struct {
@@ -85,6 +104,56 @@ type ReceiptResponse struct {
 	Receipt *receipts.Receipt `json:"receipt"`
 }
 
+type EthereumAnchorResponse struct {
+	DBHeight uint32                 `json:"dbheight"`
+	KeyMR    string                 `json:"keymr"`
+	Ethereum *anchor.EthereumStruct `json:"ethereum"`
+}
+
+type EthereumReceiptResponse struct {
+	Receipt  *receipts.Receipt      `json:"receipt"`
+	Ethereum *anchor.EthereumStruct `json:"ethereum"`
+}
+
+type ReceiptUnsubscribeResponse struct {
+	Removed bool `json:"removed"`
+}
+
+type MultisigAddressResponse struct {
+	Address string `json:"address"` // The factoid address the RCD redeems
+	RCD     string `json:"rcd"`     // Hex
+}
+
+type MultisigInputStatus struct {
+	Address  string `json:"address"`
+	Required int    `json:"required"` // Signatures the input needs
+	SignedBy []int  `json:"signedby"` // Indexes of the keys of the RCD that signed
+}
+
+type MultisigTransactionResponse struct {
+	Transaction string                `json:"transaction"` // Hex, for multisig-sign or factoid-submit
+	TxID        string                `json:"txid"`
+	SigData     string                `json:"sigdata"` // Hex of what each signer signs
+	Fee         uint64                `json:"fee"`     // Factoshis the inputs must cover over the outputs
+	Inputs      []MultisigInputStatus `json:"inputs"`
+	Complete    bool                  `json:"complete"` // Every input is signed
+}
+
+type ObjectResponse struct {
+	Namespace string `json:"namespace"`
+	Key       string `json:"key"`
+	Data      string `json:"data,omitempty"` // Hex
+}
+
+type ObjectListResponse struct {
+	Namespace string   `json:"namespace"`
+	Keys      []string `json:"keys"`
+}
+
+type PeerReputationResponse struct {
+	Peers []interfaces.PeerReputation `json:"peers"`
+}
+
 type EntryBlockResponse struct {
 	Header struct {
 		BlockSequenceNumber int64  `json:"blocksequencenumber"`
@@ -107,9 +176,44 @@ type EntryCreditBlockResponse struct {
 }
 
 type EntryResponse struct {
-	ChainID string   `json:"chainid"`
-	Content string   `json:"content"`
-	ExtIDs  []string `json:"extids"`
+	ChainID  string         `json:"chainid"`
+	Content  string         `json:"content"`
+	ExtIDs   []string       `json:"extids"`
+	Position *EntryPosition `json:"position,omitempty"` // Nil until the entry is in a saved entry block
+}
+
+// EntryPosition is where an entry sits in its chain.  Entries of a chain are ordered by
+// the height of their entry block, then by their sequence within it.
+type EntryPosition struct {
+	EntryHash   string `json:"entryhash"`
+	EBlockKeyMR string `json:"eblockkeymr"`
+	DBHeight    uint32 `json:"dbheight"`
+	Sequence    int    `json:"sequence"` // Index among the entries of the entry block, from 0
+	Minute      int    `json:"minute"`   // Minute of the block the entry was acknowledged in, from 1
+}
+
+type ChainEntriesResponse struct {
+	ChainID    string          `json:"chainid"`
+	Entries    []EntryPosition `json:"entries"`
+	NextHeight uint32          `json:"nextheight,omitempty"` // Set if the response was cut short; ask again from here
+}
+
+type ReferencesResponse struct {
+	Hash       string                      `json:"hash"`
+	References []interfaces.EntryReference `json:"references"`
+}
+
+type AddressTransactionsResponse struct {
+	Address      string                          `json:"address"`
+	Total        int                             `json:"total"` // Transactions of the address in all
+	Transactions []interfaces.AddressTransaction `json:"transactions"`
+}
+
+type ExtIDEntriesResponse struct {
+	ChainID string                  `json:"chainid"`
+	Prefix  string                  `json:"prefix"`
+	Total   int                     `json:"total"` // Matches in all
+	Entries []interfaces.ExtIDMatch `json:"entries"`
 }
 
 type ChainHeadResponse struct {
@@ -129,13 +233,26 @@ type EntryCreditRateResponse struct {
 	Rate int64 `json:"rate"`
 }
 
+// EntryCreditRateChange is one change in the exchange rate, in factoshis per entry
+// credit, and the first directory block height it applied to.
+type EntryCreditRateChange struct {
+	DBHeight  uint32 `json:"dbheight"`
+	Rate      uint64 `json:"rate"`
+	Timestamp int64  `json:"timestamp"` // Directory block timestamp, in seconds
+}
+
+type EntryCreditRateHistoryResponse struct {
+	Changes []EntryCreditRateChange `json:"changes"`
+}
+
 type PropertiesResponse struct {
 	FactomdVersion string `json:"factomdversion"`
 	ApiVersion     string `json:"factomdapiversion"`
 }
 
 type SendRawMessageResponse struct {
-	Message string `json:"message"`
+	Message    string                    `json:"message"`
+	Submission *interfaces.APISubmission `json:"submission,omitempty"`
 }
 
 type TransactionRateResponse struct {
@@ -245,6 +362,37 @@ type ChainIDRequest struct {
 	ChainID string `json:"chainid"`
 }
 
+type ChainEntriesRequest struct {
+	ChainID string `json:"chainid"`
+	From    uint32 `json:"from"` // Directory block heights, inclusive
+	To      uint32 `json:"to"`
+}
+
+type CommitRateLimitsRequest struct {
+	Address string `json:"address"` // Entry credit address, or empty for all of them
+}
+
+type ExtIDEntriesRequest struct {
+	ChainID string `json:"chainid"`
+	Prefix  string `json:"prefix"` // Hex of the start of an external ID, empty for all
+	Offset  int    `json:"offset"` // Matches to skip, in external ID order
+	Limit   int    `json:"limit"`  // 0 for the most returned at once
+}
+
+type RejectionsRequest struct {
+	Hash string `json:"hash"` // KeyMR of a directory block or a message hash, or empty for all
+}
+
+type AddressTransactionsRequest struct {
+	Address string `json:"address"` // Factoid or entry credit address, or the hex of one
+	Offset  int    `json:"offset"`  // Transactions of the address to skip, oldest first
+	Limit   int    `json:"limit"`   // 0 for the most returned at once
+}
+
+type SupplyRequest struct {
+	Blocks int `json:"blocks"` // Blocks of history, up to state.MaxSupplyHistory
+}
+
 type EntryRequest struct {
 	Entry string `json:"entry"`
 }
@@ -253,6 +401,64 @@ type HashRequest struct {
 	Hash string `json:"hash"`
 }
 
+type ReceiptRequest struct {
+	Hash            string `json:"hash"`
+	IncludeRawEntry bool   `json:"includerawentry"`
+}
+
+type ReceiptSubscribeRequest struct {
+	Hash          string `json:"hash"`
+	Confirmations int    `json:"confirmations,omitempty"` // Bitcoin confirmations; 0 for the default of 6
+	Webhook       string `json:"webhook,omitempty"`
+}
+
+type ReceiptUnsubscribeRequest struct {
+	ID string `json:"id"`
+}
+
+type MultisigAddressRequest struct {
+	Required int      `json:"required"`
+	Keys     []string `json:"keys"` // Hex ed25519 public keys
+}
+
+type MultisigAmount struct {
+	Address string `json:"address,omitempty"` // User address; for outputs
+	RCD     string `json:"rcd,omitempty"`     // Hex; for inputs
+	Amount  uint64 `json:"amount"`
+}
+
+type MultisigComposeRequest struct {
+	Inputs    []MultisigAmount `json:"inputs"`
+	Outputs   []MultisigAmount `json:"outputs,omitempty"`
+	ECOutputs []MultisigAmount `json:"ecoutputs,omitempty"`
+}
+
+type MultisigSignRequest struct {
+	Transaction string `json:"transaction"` // Hex
+	Input       int    `json:"input"`
+	Signature   string `json:"signature"` // Hex ed25519 signature of the sigdata
+}
+
+// CreateChainRequest is a chain commit and its first entry, and the commits and entries
+// to append to the chain after it, all hex encoded
+type CreateChainRequest struct {
+	Commit         string             `json:"commit"` // Chain commit
+	Reveal         string             `json:"reveal"` // First entry
+	Entries        []CreateChainEntry `json:"entries,omitempty"`
+	IdempotencyKey string             `json:"idempotencykey,omitempty"`
+}
+
+type CreateChainEntry struct {
+	Commit string `json:"commit"` // Entry commit
+	Reveal string `json:"reveal"` // Entry
+}
+
+type ObjectRequest struct {
+	Namespace string `json:"namespace"`
+	Key       string `json:"key,omitempty"`  // Hash; not for object-list
+	Data      string `json:"data,omitempty"` // Hex; for object-put
+}
+
 type KeyMRRequest struct {
 	KeyMR string `json:"keymr"`
 }
@@ -262,7 +468,8 @@ type KeyRequest struct {
 }
 
 type MessageRequest struct {
-	Message string `json:"message"`
+	Message        string `json:"message"`
+	IdempotencyKey string `json:"idempotencykey,omitempty"` // Only used by commit-chain and commit-entry
 }
 
 type PendingEntry struct {
@@ -11,9 +11,11 @@ import (
 	"io/ioutil"
 	"net/http"
 	"reflect"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/FactomProject/factomd/anchor"
 	"github.com/FactomProject/factomd/common/constants"
 	"github.com/FactomProject/factomd/common/entryBlock"
 	"github.com/FactomProject/factomd/common/entryCreditBlock"
@@ -21,6 +23,7 @@ import (
 	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/common/messages"
 	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
 	"github.com/FactomProject/factomd/receipts"
 	"github.com/FactomProject/web"
 )
@@ -34,32 +37,55 @@ func HandleV2(ctx *web.Context) {
 	state := ctx.Server.Env["state"].(interfaces.IState)
 	ServersMutex.Unlock()
 
-	if err := checkAuthHeader(state, ctx.Request); err != nil {
+	var principal *Principal
+	var j *primitives.JSON2Request
+	var jsonResp *primitives.JSON2Response
+	var jsonError *primitives.JSONError
+	status := http.StatusOK
+	defer func() {
+		logAccess(ctx.Request, principal, j, status, jsonError, n)
+		logAudit(ctx.Request, principal, j, jsonResp, status, jsonError, n)
+	}()
+
+	principal, err := authenticate(state, ctx.Request)
+	if err != nil {
 		remoteIP := ""
 		remoteIP += strings.Split(ctx.Request.RemoteAddr, ":")[0]
 		fmt.Printf("Unauthorized V2 API client connection attempt from %s\n", remoteIP)
 		ctx.ResponseWriter.Header().Add("WWW-Authenticate", `Basic realm="factomd RPC"`)
 		http.Error(ctx.ResponseWriter, "401 Unauthorized.", http.StatusUnauthorized)
+		status = http.StatusUnauthorized
 
 		return
 	}
 
 	body, err := ioutil.ReadAll(ctx.Request.Body)
 	if err != nil {
-		HandleV2Error(ctx, nil, NewInvalidRequestError())
+		jsonError = NewInvalidRequestError()
+		status = HandleV2Error(ctx, nil, jsonError)
 		return
 	}
 
-	j, err := primitives.ParseJSON2Request(string(body))
+	j, err = primitives.ParseJSON2Request(string(body))
 	if err != nil {
-		HandleV2Error(ctx, nil, NewInvalidRequestError())
+		jsonError = NewInvalidRequestError()
+		status = HandleV2Error(ctx, nil, jsonError)
+		return
+	}
+
+	if !principal.Can(methodPermission(j.Method)) {
+		jsonError = NewPermissionDeniedError(methodPermission(j.Method))
+		status = HandleV2Error(ctx, j, jsonError)
 		return
 	}
 
-	jsonResp, jsonError := HandleV2Request(state, j)
+	reqCtx, cancel := requestContext(ctx.Request, state)
+	defer cancel()
+	jsonResp, jsonError = HandleV2Request(NewContextState(requestState(ctx, state), reqCtx), j)
 
 	if jsonError != nil {
-		HandleV2Error(ctx, j, jsonError)
+		jsonError = cancelledError(reqCtx, jsonError)
+		status = HandleV2Error(ctx, j, jsonError)
 		return
 	}
 
@@ -71,6 +97,15 @@ func HandleV2Request(state interfaces.IState, j *primitives.JSON2Request) (*prim
 	var jsonError *primitives.JSONError
 	params := j.Params
 	switch j.Method {
+	case "api-queue":
+		resp, jsonError = HandleV2APIQueue(state, params)
+		break
+	case "backpressure":
+		resp, jsonError = HandleV2Backpressure(state, params)
+		break
+	case "commit-rate-limits":
+		resp, jsonError = HandleV2CommitRateLimits(state, params)
+		break
 	case "chain-head":
 		resp, jsonError = HandleV2ChainHead(state, params)
 		break
@@ -80,6 +115,9 @@ func HandleV2Request(state interfaces.IState, j *primitives.JSON2Request) (*prim
 	case "commit-entry":
 		resp, jsonError = HandleV2CommitEntry(state, params)
 		break
+	case "create-chain":
+		resp, jsonError = HandleV2CreateChain(state, params)
+		break
 	case "current-minute":
 		resp, jsonError = HandleV2CurrentMinute(state, params)
 		break
@@ -104,15 +142,36 @@ func HandleV2Request(state interfaces.IState, j *primitives.JSON2Request) (*prim
 	case "entry":
 		resp, jsonError = HandleV2Entry(state, params)
 		break
+	case "chain-entries":
+		resp, jsonError = HandleV2ChainEntries(state, params)
+		break
 	case "entry-credit-balance":
 		resp, jsonError = HandleV2EntryCreditBalance(state, params)
 		break
 	case "entry-credit-rate":
 		resp, jsonError = HandleV2EntryCreditRate(state, params)
 		break
+	case "entry-credit-rate-history":
+		resp, jsonError = HandleV2EntryCreditRateHistory(state, params)
+		break
 	case "factoid-balance":
 		resp, jsonError = HandleV2FactoidBalance(state, params)
 		break
+	case "fct-supply":
+		resp, jsonError = HandleV2FctSupply(state, params)
+		break
+	case "rejections":
+		resp, jsonError = HandleV2Rejections(state, params)
+		break
+	case "capacity":
+		resp, jsonError = HandleV2Capacity(state, params)
+		break
+	case "timing-anomalies":
+		resp, jsonError = HandleV2TimingAnomalies(state, params)
+		break
+	case "coinbase-audit":
+		resp, jsonError = HandleV2CoinbaseAudit(state, params)
+		break
 	case "factoid-submit":
 		resp, jsonError = HandleV2FactoidSubmit(state, params)
 		break
@@ -122,12 +181,36 @@ func HandleV2Request(state interfaces.IState, j *primitives.JSON2Request) (*prim
 	case "properties":
 		resp, jsonError = HandleV2Properties(state, params)
 		break
+	case "startup-progress":
+		resp, jsonError = HandleV2StartupProgress(state, params)
+		break
+	case "network-parameters":
+		resp, jsonError = HandleV2NetworkParameters(state, params)
+		break
 	case "raw-data":
 		resp, jsonError = HandleV2RawData(state, params)
 		break
 	case "receipt":
 		resp, jsonError = HandleV2Receipt(state, params)
 		break
+	case "ethereum-receipt":
+		resp, jsonError = HandleV2EthereumReceipt(state, params)
+		break
+	case "receipt-subscribe":
+		resp, jsonError = HandleV2ReceiptSubscribe(state, params)
+		break
+	case "receipt-unsubscribe":
+		resp, jsonError = HandleV2ReceiptUnsubscribe(state, params)
+		break
+	case "transactions-by-address":
+		resp, jsonError = HandleV2TransactionsByAddress(state, params)
+		break
+	case "entries-by-extid":
+		resp, jsonError = HandleV2EntriesByExtID(state, params)
+		break
+	case "references":
+		resp, jsonError = HandleV2References(state, params)
+		break
 	case "reveal-chain":
 		resp, jsonError = HandleV2RevealChain(state, params)
 		break
@@ -143,6 +226,33 @@ func HandleV2Request(state interfaces.IState, j *primitives.JSON2Request) (*prim
 	case "pending-entries":
 		resp, jsonError = HandleV2GetPendingEntries(state, params)
 		break
+	case "multisig-address":
+		resp, jsonError = HandleV2MultisigAddress(state, params)
+		break
+	case "multisig-compose":
+		resp, jsonError = HandleV2MultisigCompose(state, params)
+		break
+	case "multisig-sign":
+		resp, jsonError = HandleV2MultisigSign(state, params)
+		break
+	case "object-put":
+		resp, jsonError = HandleV2ObjectPut(state, params)
+		break
+	case "object-get":
+		resp, jsonError = HandleV2ObjectGet(state, params)
+		break
+	case "object-delete":
+		resp, jsonError = HandleV2ObjectDelete(state, params)
+		break
+	case "object-list":
+		resp, jsonError = HandleV2ObjectList(state, params)
+		break
+	case "peer-reputation":
+		resp, jsonError = HandleV2PeerReputation(state, params)
+		break
+	case "pending-pool":
+		resp, jsonError = HandleV2GetPendingPool(state, params)
+		break
 	case "pending-transactions":
 		resp, jsonError = HandleV2GetPendingTransactions(state, params)
 		break
@@ -155,6 +265,9 @@ func HandleV2Request(state interfaces.IState, j *primitives.JSON2Request) (*prim
 	case "dblock-by-height":
 		resp, jsonError = HandleV2DBlockByHeight(state, params)
 		break
+	case "ethereum-anchor":
+		resp, jsonError = HandleV2EthereumAnchor(state, params)
+		break
 	case "ecblock-by-height":
 		resp, jsonError = HandleV2ECBlockByHeight(state, params)
 		break
@@ -166,10 +279,14 @@ func HandleV2Request(state interfaces.IState, j *primitives.JSON2Request) (*prim
 		break
 	case "authorities":
 		resp, jsonError = HandleAuthorities(state, params)
+	case "authority-stats":
+		resp, jsonError = HandleAuthorityStats(state, params)
 	case "tps-rate":
 		resp, jsonError = HandleV2TransactionRate(state, params)
 	case "ack":
 		resp, jsonError = HandleV2ACKWithChain(state, params)
+	case "backup-database":
+		resp, jsonError = HandleV2BackupDatabase(state, params)
 	default:
 		jsonError = NewMethodNotFoundError()
 		break
@@ -483,7 +600,8 @@ func aBlockToResp(block interfaces.IAdminBlock) (interface{}, *primitives.JSONEr
 	return resp, nil
 }
 
-func HandleV2Error(ctx *web.Context, j *primitives.JSON2Request, err *primitives.JSONError) {
+// HandleV2Error writes the error response to a request, returning its HTTP status
+func HandleV2Error(ctx *web.Context, j *primitives.JSON2Request, err *primitives.JSONError) int {
 	resp := primitives.NewJSON2Response()
 	if j != nil {
 		resp.ID = j.ID
@@ -492,8 +610,30 @@ func HandleV2Error(ctx *web.Context, j *primitives.JSON2Request, err *primitives
 	}
 	resp.Error = err
 
+	// Submissions the node couldn't take say when to try again
+	if s, ok := err.Data.(*interfaces.APISubmission); ok && s.RetryAfter > 0 {
+		ctx.ResponseWriter.Header().Set("Retry-After", strconv.Itoa(s.RetryAfter))
+		ctx.WriteHeader(http.StatusServiceUnavailable)
+		ctx.Write([]byte(resp.String()))
+		return http.StatusServiceUnavailable
+	}
+
 	ctx.WriteHeader(httpBad)
 	ctx.Write([]byte(resp.String()))
+	return httpBad
+}
+
+// submitAPIMsg hands a submitted message to the node, returning an error saying when to
+// try again if the node couldn't take it
+func submitAPIMsg(state interfaces.IState, msg interfaces.IMsg) (*interfaces.APISubmission, *primitives.JSONError) {
+	submission := state.SubmitAPIMsg(msg)
+	if submission.Status == interfaces.APISubmitRejected {
+		if submission.Overloaded && !submission.Syncing {
+			return nil, NewNodeOverloadedError(&submission)
+		}
+		return nil, NewSubmissionRejectedError(&submission)
+	}
+	return &submission, nil
 }
 
 func MapToObject(source interface{}, dst interface{}) error {
@@ -542,8 +682,16 @@ func HandleV2CommitChain(state interfaces.IState, params interface{}) (interface
 		return nil, NewInvalidParamsError()
 	}
 
+	if commitChainMsg.IdempotencyKey != "" {
+		return submitIdempotent(state, "commit-chain", commitChainMsg.IdempotencyKey, commitChainMsg.Message,
+			func() (interface{}, *primitives.JSONError) { return commitChain(state, commitChainMsg.Message) })
+	}
+	return commitChain(state, commitChainMsg.Message)
+}
+
+func commitChain(state interfaces.IState, message string) (interface{}, *primitives.JSONError) {
 	commit := entryCreditBlock.NewCommitChain()
-	if p, err := hex.DecodeString(commitChainMsg.Message); err != nil {
+	if p, err := hex.DecodeString(message); err != nil {
 		return nil, NewInvalidCommitChainError()
 	} else {
 		_, err := commit.UnmarshalBinaryData(p)
@@ -564,11 +712,15 @@ func HandleV2CommitChain(state interfaces.IState, params interface{}) (interface
 		return nil, NewRepeatCommitError(RepeatedEntryMessage{"A commit with equal or greater payment already exists", msg.CommitChain.GetEntryHash().String()})
 	}
 
-	state.APIQueue().Enqueue(msg)
+	submission, jsonError := submitAPIMsg(state, msg)
+	if jsonError != nil {
+		return nil, jsonError
+	}
 	state.IncECCommits()
 
 	resp := new(CommitChainResponse)
 	resp.Message = "Chain Commit Success"
+	resp.Submission = submission
 	resp.TxID = commit.GetSigHash().String()
 	resp.EntryHash = commit.GetEntryHash().String()
 	resp.ChainIDHash = commit.ChainIDHash.String()
@@ -590,8 +742,16 @@ func HandleV2CommitEntry(state interfaces.IState, params interface{}) (interface
 		return nil, NewInvalidParamsError()
 	}
 
+	if commitEntryMsg.IdempotencyKey != "" {
+		return submitIdempotent(state, "commit-entry", commitEntryMsg.IdempotencyKey, commitEntryMsg.Message,
+			func() (interface{}, *primitives.JSONError) { return commitEntry(state, commitEntryMsg.Message) })
+	}
+	return commitEntry(state, commitEntryMsg.Message)
+}
+
+func commitEntry(state interfaces.IState, message string) (interface{}, *primitives.JSONError) {
 	commit := entryCreditBlock.NewCommitEntry()
-	if p, err := hex.DecodeString(commitEntryMsg.Message); err != nil {
+	if p, err := hex.DecodeString(message); err != nil {
 		return nil, NewInvalidCommitEntryError()
 	} else {
 		_, err := commit.UnmarshalBinaryData(p)
@@ -612,11 +772,15 @@ func HandleV2CommitEntry(state interfaces.IState, params interface{}) (interface
 		return nil, NewRepeatCommitError(RepeatedEntryMessage{"A commit with equal or greater payment already exists", msg.CommitEntry.GetEntryHash().String()})
 	}
 
-	state.APIQueue().Enqueue(msg)
+	submission, jsonError := submitAPIMsg(state, msg)
+	if jsonError != nil {
+		return nil, jsonError
+	}
 	state.IncECommits()
 
 	resp := new(CommitEntryResponse)
 	resp.Message = "Entry Commit Success"
+	resp.Submission = submission
 	resp.TxID = commit.GetSigHash().String()
 	resp.EntryHash = commit.EntryHash.String()
 
@@ -650,10 +814,14 @@ func HandleV2RevealEntry(state interfaces.IState, params interface{}) (interface
 	msg := new(messages.RevealEntryMsg)
 	msg.Entry = entry
 	msg.Timestamp = state.GetTimestamp()
-	state.APIQueue().Enqueue(msg)
+	submission, jsonError := submitAPIMsg(state, msg)
+	if jsonError != nil {
+		return nil, jsonError
+	}
 
 	resp := new(RevealEntryResponse)
 	resp.Message = "Entry Reveal Success"
+	resp.Submission = submission
 	resp.EntryHash = entry.GetHash().String()
 	resp.ChainID = entry.ChainID.String()
 
@@ -730,6 +898,41 @@ func HandleV2Receipt(state interfaces.IState, params interface{}) (interface{},
 	n := time.Now()
 	defer HandleV2APICallReceipt.Observe(float64(time.Since(n).Nanoseconds()))
 
+	receiptRequest := new(ReceiptRequest)
+	err := MapToObject(params, receiptRequest)
+	if err != nil {
+		return nil, NewInvalidParamsError()
+	}
+
+	h, err := primitives.HexToHash(receiptRequest.Hash)
+	if err != nil {
+		return nil, NewInvalidHashError()
+	}
+
+	dbase := state.GetAndLockDB()
+	defer state.UnlockDB()
+
+	receipt, err := receipts.CreateFullReceipt(dbase, h)
+	if err != nil {
+		return nil, NewReceiptError()
+	}
+	if receiptRequest.IncludeRawEntry {
+		if err := receipt.IncludeRawEntry(dbase); err != nil {
+			return nil, NewReceiptError()
+		}
+	}
+	resp := new(ReceiptResponse)
+	resp.Receipt = receipt
+
+	return resp, nil
+}
+
+// HandleV2EthereumReceipt returns an entry's receipt along with where its directory block
+// was anchored into Ethereum
+func HandleV2EthereumReceipt(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	n := time.Now()
+	defer func() { HandleV2APICallEthereumReceipt.Observe(float64(time.Since(n).Nanoseconds())) }()
+
 	hashkey := new(HashRequest)
 	err := MapToObject(params, hashkey)
 	if err != nil {
@@ -748,9 +951,103 @@ func HandleV2Receipt(state interfaces.IState, params interface{}) (interface{},
 	if err != nil {
 		return nil, NewReceiptError()
 	}
-	resp := new(ReceiptResponse)
+	eth, jsonError := fetchEthereumAnchor(dbase, receipt.DirectoryBlockKeyMR)
+	if jsonError != nil {
+		return nil, jsonError
+	}
+
+	resp := new(EthereumReceiptResponse)
 	resp.Receipt = receipt
+	resp.Ethereum = eth.Ethereum
+	return resp, nil
+}
 
+// HandleV2EthereumAnchor returns where the directory block at a height was anchored into
+// Ethereum
+func HandleV2EthereumAnchor(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	n := time.Now()
+	defer func() { HandleV2APICallEthereumAnchor.Observe(float64(time.Since(n).Nanoseconds())) }()
+
+	heightRequest := new(HeightRequest)
+	err := MapToObject(params, heightRequest)
+	if err != nil || heightRequest.Height < 0 {
+		return nil, NewInvalidParamsError()
+	}
+
+	dbase := state.GetAndLockDB()
+	defer state.UnlockDB()
+
+	keyMR, err := dbase.FetchDBKeyMRByHeight(uint32(heightRequest.Height))
+	if err != nil {
+		return nil, NewInternalDatabaseError()
+	}
+	if keyMR == nil {
+		return nil, NewBlockNotFoundError()
+	}
+	eth, jsonError := fetchEthereumAnchor(dbase, keyMR)
+	if jsonError != nil {
+		return nil, jsonError
+	}
+
+	resp := new(EthereumAnchorResponse)
+	resp.DBHeight = eth.DBHeight
+	resp.KeyMR = eth.KeyMR
+	resp.Ethereum = eth.Ethereum
+	return resp, nil
+}
+
+func fetchEthereumAnchor(dbase interfaces.DBOverlaySimple, keyMR interfaces.IHash) (*anchor.AnchorRecord, *primitives.JSONError) {
+	if keyMR == nil {
+		return nil, NewAnchorNotFoundError()
+	}
+	record, err := dbase.FetchEthereumAnchor(keyMR)
+	if err != nil {
+		return nil, NewInternalDatabaseError()
+	}
+	ar, ok := record.(*anchor.AnchorRecord)
+	if !ok || ar == nil || ar.Ethereum == nil {
+		return nil, NewAnchorNotFoundError()
+	}
+	return ar, nil
+}
+
+// HandleV2ReceiptSubscribe asks for an entry's receipt to be posted to a webhook once its
+// directory block's anchor has enough Bitcoin confirmations.  Without a webhook, the
+// receipt is pushed to /v2/receipt-notices clients watching for the subscription.
+func HandleV2ReceiptSubscribe(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	n := time.Now()
+	defer func() { HandleV2APICallReceiptSubscribe.Observe(float64(time.Since(n).Nanoseconds())) }()
+
+	req := new(ReceiptSubscribeRequest)
+	err := MapToObject(params, req)
+	if err != nil {
+		return nil, NewInvalidParamsError()
+	}
+
+	h, err := primitives.HexToHash(req.Hash)
+	if err != nil {
+		return nil, NewInvalidHashError()
+	}
+
+	sub, err := state.SubscribeReceipt(h, req.Confirmations, req.Webhook)
+	if err != nil {
+		return nil, NewCustomInvalidParamsError(err.Error())
+	}
+	return &sub, nil
+}
+
+func HandleV2ReceiptUnsubscribe(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	n := time.Now()
+	defer func() { HandleV2APICallReceiptUnsubscribe.Observe(float64(time.Since(n).Nanoseconds())) }()
+
+	req := new(ReceiptUnsubscribeRequest)
+	err := MapToObject(params, req)
+	if err != nil || req.ID == "" {
+		return nil, NewInvalidParamsError()
+	}
+
+	resp := new(ReceiptUnsubscribeResponse)
+	resp.Removed = state.UnsubscribeReceipt(req.ID)
 	return resp, nil
 }
 
@@ -886,10 +1183,9 @@ func HandleV2Entry(state interfaces.IState, params interface{}) (interface{}, *p
 	if err != nil {
 		return nil, NewInternalError()
 	}
+	dbase := state.GetAndLockDB()
+	defer state.UnlockDB()
 	if entry == nil {
-		dbase := state.GetAndLockDB()
-		defer state.UnlockDB()
-
 		entry, err = dbase.FetchEntry(h)
 		if err != nil {
 			return nil, NewInvalidHashError()
@@ -904,6 +1200,7 @@ func HandleV2Entry(state interfaces.IState, params interface{}) (interface{}, *p
 	for _, v := range entry.ExternalIDs() {
 		e.ExtIDs = append(e.ExtIDs, hex.EncodeToString(v))
 	}
+	e.Position = entryPosition(dbase, h)
 
 	return e, nil
 }
@@ -929,11 +1226,15 @@ func HandleV2ChainHead(state interfaces.IState, params interface{}) (interface{}
 
 	// get the pending chain head from the current or previous process list in
 	// the state
-	lh := state.GetLeaderHeight()
-	pend1 := state.IsNewOrPendingEBlocks(lh, h)
-	pend2 := state.IsNewOrPendingEBlocks(lh-1, h)
-	if pend1 || pend2 {
-		c.ChainInProcessList = true
+	if view := state.GetReadView(); view != nil {
+		c.ChainInProcessList = view.IsChainPending(h)
+	} else {
+		lh := state.GetLeaderHeight()
+		pend1 := state.IsNewOrPendingEBlocks(lh, h)
+		pend2 := state.IsNewOrPendingEBlocks(lh-1, h)
+		if pend1 || pend2 {
+			c.ChainInProcessList = true
+		}
 	}
 
 	// get the chain head from the database
@@ -1004,7 +1305,11 @@ func HandleV2EntryCreditBalance(state interfaces.IState, params interface{}) (in
 		return nil, NewInvalidAddressError()
 	}
 	resp := new(EntryCreditBalanceResponse)
-	resp.Balance = state.GetFactoidState().GetECBalance(address.Fixed())
+	if view := state.GetReadView(); view != nil {
+		resp.Balance = view.GetECBalance(address.Fixed())
+	} else {
+		resp.Balance = state.GetFactoidState().GetECBalance(address.Fixed())
+	}
 	return resp, nil
 }
 
@@ -1018,6 +1323,25 @@ func HandleV2EntryCreditRate(state interfaces.IState, params interface{}) (inter
 	return resp, nil
 }
 
+// HandleV2EntryCreditRateHistory returns every change of the entry credit exchange rate,
+// as recorded in the factoid block headers, with the height it took effect.
+func HandleV2EntryCreditRateHistory(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	n := time.Now()
+	defer HandleV2APICallECRateHistory.Observe(float64(time.Since(n).Nanoseconds()))
+
+	changes, err := state.GetECRateHistory()
+	if err != nil {
+		return nil, NewCustomInternalError(err.Error())
+	}
+
+	resp := new(EntryCreditRateHistoryResponse)
+	resp.Changes = []EntryCreditRateChange{}
+	for _, c := range changes {
+		resp.Changes = append(resp.Changes, EntryCreditRateChange{DBHeight: c.DBHeight, Rate: c.Rate, Timestamp: c.Timestamp})
+	}
+	return resp, nil
+}
+
 func HandleV2FactoidSubmit(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
 	n := time.Now()
 	defer HandleV2APICallFctTx.Observe(float64(time.Since(n).Nanoseconds()))
@@ -1040,12 +1364,15 @@ func HandleV2FactoidSubmit(state interfaces.IState, params interface{}) (interfa
 		return nil, NewUnableToDecodeTransactionError()
 	}
 
+	submission, jsonError := submitAPIMsg(state, msg)
+	if jsonError != nil {
+		return nil, jsonError
+	}
 	state.IncFCTSubmits()
 
-	state.APIQueue().Enqueue(msg)
-
 	resp := new(FactoidSubmitResponse)
 	resp.Message = "Successfully submitted the transaction"
+	resp.Submission = submission
 	resp.TxID = msg.Transaction.GetSigHash().String()
 
 	return resp, nil
@@ -1080,10 +1407,258 @@ func HandleV2FactoidBalance(state interfaces.IState, params interface{}) (interf
 	}
 
 	resp := new(FactoidBalanceResponse)
-	resp.Balance = state.GetFactoidState().GetFactoidBalance(factoid.NewAddress(adr).Fixed())
+	if view := state.GetReadView(); view != nil {
+		resp.Balance = view.GetFactoidBalance(factoid.NewAddress(adr).Fixed())
+	} else {
+		resp.Balance = state.GetFactoidState().GetFactoidBalance(factoid.NewAddress(adr).Fixed())
+	}
 	return resp, nil
 }
 
+// How many blocks of history the fct-supply call returns, unless asked for (a day)
+const DefaultSupplyBlocks = 144
+
+// HandleV2FctSupply returns the factoid supply counted from the saved blocks, and the
+// factoids issued, burned for entry credits and paid in fees by the most recent blocks
+func HandleV2FctSupply(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	n := time.Now()
+	defer func() { HandleV2APICallFctSupply.Observe(float64(time.Since(n).Nanoseconds())) }()
+
+	req := new(SupplyRequest)
+	err := MapToObject(params, req)
+	if err != nil || req.Blocks < 0 {
+		return nil, NewInvalidParamsError()
+	}
+	blocks := req.Blocks
+	if blocks == 0 {
+		blocks = DefaultSupplyBlocks
+	}
+	status := state.GetSupply(blocks)
+	return &status, nil
+}
+
+// HandleV2TimingAnomalies returns the minutes this node saw end too soon, and the saved
+// blocks whose timestamps are too far apart
+func HandleV2TimingAnomalies(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	n := time.Now()
+	defer func() { HandleV2APICallTimingAnomalies.Observe(float64(time.Since(n).Nanoseconds())) }()
+
+	report := state.GetTimingAnomalies()
+	return &report, nil
+}
+
+// HandleV2Capacity returns the load the node sustains against what it could take, the
+// headroom of its queues, the growth of its database and its garbage collector pauses
+func HandleV2Capacity(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	n := time.Now()
+	defer func() { HandleV2APICallCapacity.Observe(float64(time.Since(n).Nanoseconds())) }()
+
+	report := state.GetCapacityReport()
+	return &report, nil
+}
+
+// HandleV2CoinbaseAudit returns the audit of the coinbase payouts of the saved blocks,
+// with the most recent blocks found paying other than expected
+func HandleV2CoinbaseAudit(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	n := time.Now()
+	defer func() { HandleV2APICallCoinbaseAudit.Observe(float64(time.Since(n).Nanoseconds())) }()
+
+	report := state.GetCoinbaseAudit()
+	return &report, nil
+}
+
+// HandleV2References lists the saved entries whose external IDs or content refer to an
+// entry hash or chain ID.  The node has to be indexing references.
+func HandleV2References(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	n := time.Now()
+	defer func() { HandleV2APICallReferences.Observe(float64(time.Since(n).Nanoseconds())) }()
+
+	req := new(HashRequest)
+	err := MapToObject(params, req)
+	if err != nil {
+		return nil, NewInvalidParamsError()
+	}
+	h, err := primitives.HexToHash(req.Hash)
+	if err != nil {
+		return nil, NewInvalidHashError()
+	}
+
+	dbase := state.GetAndLockDB()
+	defer state.UnlockDB()
+
+	refs, err := dbase.FetchReferencingEntries(h)
+	if err != nil {
+		return nil, NewCustomInternalError(err.Error())
+	}
+	r := new(ReferencesResponse)
+	r.Hash = h.String()
+	r.References = refs
+	return r, nil
+}
+
+// Most transactions transactions-by-address returns at once
+const MaxAddressTransactions = 1000
+
+// HandleV2TransactionsByAddress pages through the factoid transactions spending from or
+// paying to a factoid or entry credit address, oldest first.  The node has to be indexing
+// addresses.
+func HandleV2TransactionsByAddress(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	n := time.Now()
+	defer func() { HandleV2APICallTransactionsByAddress.Observe(float64(time.Since(n).Nanoseconds())) }()
+
+	req := new(AddressTransactionsRequest)
+	err := MapToObject(params, req)
+	if err != nil || req.Offset < 0 || req.Limit < 0 {
+		return nil, NewInvalidParamsError()
+	}
+	if req.Limit == 0 || req.Limit > MaxAddressTransactions {
+		req.Limit = MaxAddressTransactions
+	}
+
+	var adr []byte
+	if primitives.ValidateFUserStr(req.Address) || primitives.ValidateECUserStr(req.Address) {
+		adr = primitives.ConvertUserStrToAddress(req.Address)
+	} else {
+		adr, err = hex.DecodeString(req.Address)
+		if err != nil {
+			return nil, NewInvalidAddressError()
+		}
+	}
+	if len(adr) != constants.HASH_LENGTH {
+		return nil, NewInvalidAddressError()
+	}
+
+	dbase := state.GetAndLockDB()
+	defer state.UnlockDB()
+
+	txs, total, err := dbase.FetchAddressTransactions(primitives.NewHash(adr), req.Offset, req.Limit)
+	if err != nil {
+		return nil, NewCustomInternalError(err.Error())
+	}
+	r := new(AddressTransactionsResponse)
+	r.Address = req.Address
+	r.Total = total
+	r.Transactions = txs
+	return r, nil
+}
+
+// Most entries entries-by-extid returns at once
+const MaxExtIDEntries = 1000
+
+// HandleV2EntriesByExtID pages through the entries of a chain with an external ID starting
+// with a prefix, in external ID order.  The node has to be indexing external IDs.
+func HandleV2EntriesByExtID(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	n := time.Now()
+	defer func() { HandleV2APICallEntriesByExtID.Observe(float64(time.Since(n).Nanoseconds())) }()
+
+	req := new(ExtIDEntriesRequest)
+	err := MapToObject(params, req)
+	if err != nil || req.Offset < 0 || req.Limit < 0 {
+		return nil, NewInvalidParamsError()
+	}
+	if req.Limit == 0 || req.Limit > MaxExtIDEntries {
+		req.Limit = MaxExtIDEntries
+	}
+	chainID, err := primitives.HexToHash(req.ChainID)
+	if err != nil {
+		return nil, NewInvalidHashError()
+	}
+	prefix, err := hex.DecodeString(req.Prefix)
+	if err != nil || len(prefix) > databaseOverlay.MaxIndexedExtIDLength {
+		return nil, NewInvalidParamsError()
+	}
+
+	dbase := state.GetAndLockDB()
+	defer state.UnlockDB()
+
+	entries, total, err := dbase.FetchEntriesByExtID(chainID, prefix, req.Offset, req.Limit)
+	if err != nil {
+		return nil, NewCustomInternalError(err.Error())
+	}
+	r := new(ExtIDEntriesResponse)
+	r.ChainID = chainID.String()
+	r.Prefix = req.Prefix
+	r.Total = total
+	r.Entries = entries
+	return r, nil
+}
+
+// HandleV2APIQueue reports how full the API queue is, and what a message submitted now
+// would get, so clients can back off before their submissions are turned away
+func HandleV2APIQueue(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	n := time.Now()
+	defer HandleV2APICallAPIQueue.Observe(float64(time.Since(n).Nanoseconds()))
+
+	status := state.GetAPIQueueStatus()
+	return &status, nil
+}
+
+// HandleV2Backpressure reports how saturated the node's inbound queue is, and how long
+// clients should wait before submitting again while it is busy
+func HandleV2Backpressure(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	n := time.Now()
+	defer func() { HandleV2APICallBackpressure.Observe(float64(time.Since(n).Nanoseconds())) }()
+
+	bp := state.GetBackpressure()
+	return &bp, nil
+}
+
+// HandleV2CommitRateLimits reports how fast the node, as a leader, takes the commits paid
+// from each entry credit address, and how the addresses that committed lately stand; just
+// the one address if asked about one
+func HandleV2CommitRateLimits(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	n := time.Now()
+	defer func() { HandleV2APICallCommitRateLimits.Observe(float64(time.Since(n).Nanoseconds())) }()
+
+	req := new(CommitRateLimitsRequest)
+	if err := MapToObject(params, req); err != nil {
+		return nil, NewInvalidParamsError()
+	}
+	if req.Address != "" && !primitives.ValidateECUserStr(req.Address) {
+		return nil, NewInvalidAddressError()
+	}
+
+	limits := state.GetCommitRateLimits()
+	if req.Address != "" {
+		usage := []interfaces.CommitRateUsage{}
+		for _, u := range limits.Addresses {
+			if u.Address == req.Address {
+				usage = append(usage, u)
+			}
+		}
+		limits.Addresses = usage
+	}
+	return &limits, nil
+}
+
+// HandleV2Rejections returns the blocks and messages this node turned away, and why
+func HandleV2Rejections(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	n := time.Now()
+	defer func() { HandleV2APICallRejections.Observe(float64(time.Since(n).Nanoseconds())) }()
+
+	req := new(RejectionsRequest)
+	if err := MapToObject(params, req); err != nil {
+		return nil, NewInvalidParamsError()
+	}
+	if req.Hash != "" {
+		h, err := primitives.HexToHash(req.Hash)
+		if err != nil {
+			return nil, NewInvalidHashError()
+		}
+		req.Hash = h.String()
+	}
+
+	report := state.GetRejections(req.Hash)
+	return &report, nil
+}
+
+func HandleV2PeerReputation(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	n := time.Now()
+	defer func() { HandleV2APICallPeerReputation.Observe(float64(time.Since(n).Nanoseconds())) }()
+
+	return &PeerReputationResponse{Peers: state.GetPeerReputations()}, nil
+}
+
 func HandleV2Heights(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
 	n := time.Now()
 	defer HandleV2APICallHeights.Observe(float64(time.Since(n).Nanoseconds()))
@@ -1101,6 +1676,24 @@ func HandleV2Heights(state interfaces.IState, params interface{}) (interface{},
 	return h, nil
 }
 
+// HandleV2StartupProgress reports which phase of booting the node is in, and how long
+// the phase is expected to take
+func HandleV2StartupProgress(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	n := time.Now()
+	defer HandleV2APICallStartupProgress.Observe(float64(time.Since(n).Nanoseconds()))
+
+	status := state.GetStartupStatus()
+	return &status, nil
+}
+
+func HandleV2NetworkParameters(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	n := time.Now()
+	defer HandleV2APICallNetworkParameters.Observe(float64(time.Since(n).Nanoseconds()))
+
+	p := state.GetNetworkParameters()
+	return &p, nil
+}
+
 func HandleV2GetPendingEntries(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
 	n := time.Now()
 	defer HandleV2APICallPendingEntries.Observe(float64(time.Since(n).Nanoseconds()))
@@ -1115,6 +1708,14 @@ func HandleV2GetPendingEntries(state interfaces.IState, params interface{}) (int
 	return pending, nil
 }
 
+func HandleV2GetPendingPool(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	n := time.Now()
+	defer func() { HandleV2APICallPendingPool.Observe(float64(time.Since(n).Nanoseconds())) }()
+
+	pool := state.GetPendingPool()
+	return &pool, nil
+}
+
 func HandleV2GetPendingTransactions(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
 	n := time.Now()
 	defer HandleV2APICallPendingTxs.Observe(float64(time.Since(n).Nanoseconds()))
@@ -1159,10 +1760,14 @@ func HandleV2SendRawMessage(state interfaces.IState, params interface{}) (interf
 		return nil, NewInvalidParamsError()
 	}
 
-	state.APIQueue().Enqueue(msg)
+	submission, jsonError := submitAPIMsg(state, msg)
+	if jsonError != nil {
+		return nil, jsonError
+	}
 
 	resp := new(SendRawMessageResponse)
 	resp.Message = "Successfully sent the message"
+	resp.Submission = submission
 
 	return resp, nil
 }
@@ -2,6 +2,7 @@ package wsapi_test
 
 import (
 	"bytes"
+	"encoding/hex"
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
@@ -9,8 +10,13 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/FactomProject/factomd/anchor"
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/factoid"
 	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
 	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
 	"github.com/FactomProject/factomd/receipts"
 	"github.com/FactomProject/factomd/testHelper"
 	. "github.com/FactomProject/factomd/wsapi"
@@ -481,3 +487,303 @@ func Test_ecBlockToResp(t *testing.T) {
 		})
 	}
 }
+
+func TestHandleV2EntryCreditRateHistory(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestState()
+
+	fblock, err := state.DB.FetchFBlockByHeight(0)
+	if err != nil || fblock == nil {
+		t.Fatalf("No genesis factoid block - %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		resp, jerr := HandleV2EntryCreditRateHistory(state, nil)
+		if jerr != nil {
+			t.Fatalf("%v", jerr)
+		}
+		changes := resp.(*EntryCreditRateHistoryResponse).Changes
+		if len(changes) != 1 {
+			t.Fatalf("Expected one rate, the test chain never changes it - %v", changes)
+		}
+		if changes[0].DBHeight != 0 || changes[0].Rate != fblock.GetExchRate() {
+			t.Errorf("Unexpected rate change %v", changes[0])
+		}
+	}
+}
+
+func TestHandleV2CommitChainIdempotent(t *testing.T) {
+	state := testHelper.CreateEmptyTestState()
+
+	msg := new(MessageRequest)
+	msg.Message = "00015507b2f70bd0165d9fa19a28cfaafb6bc82f538955a98c7b7e60d79fbf92655c1bff1c76466cb3bc3f3cc68d8b2c111f4f24c88d9c031b4124395c940e5e2c5ea496e8aaa2f5c956749fc3eba4acc60fd485fb100e601070a44fcce54ff358d606698547340b3b6a27bcceb6a42d62a3a8d02a6f0d73653215771de243a63ac048a18b59da2946c901273e616bdbb166c535b26d0d446bc69b22c887c534297c7d01b2ac120237086112b5ef34fc6474e5e941d60aa054b465d4d770d7f850169170ef39150b"
+	msg.IdempotencyKey = "retry-me"
+
+	first, jErr := HandleV2CommitChain(state, msg)
+	if jErr != nil {
+		t.Fatalf("%v", jErr)
+	}
+	second, jErr := HandleV2CommitChain(state, msg)
+	if jErr != nil {
+		t.Fatalf("%v", jErr)
+	}
+	if first != second {
+		t.Error("Repeated submission did not return the original result")
+	}
+	if state.APIQueue().Length() != 1 {
+		t.Errorf("Commit was queued %d times", state.APIQueue().Length())
+	}
+
+	other := new(MessageRequest)
+	other.Message = msg.Message[:len(msg.Message)-2] + "00"
+	other.IdempotencyKey = msg.IdempotencyKey
+	if _, jErr := HandleV2CommitChain(state, other); jErr == nil {
+		t.Error("Key was accepted for a different message")
+	}
+}
+
+func TestHandleV2CommitChainRejected(t *testing.T) {
+	state := testHelper.CreateEmptyTestState()
+	state.DBFinished = false
+
+	msg := new(MessageRequest)
+	msg.Message = "00015507b2f70bd0165d9fa19a28cfaafb6bc82f538955a98c7b7e60d79fbf92655c1bff1c76466cb3bc3f3cc68d8b2c111f4f24c88d9c031b4124395c940e5e2c5ea496e8aaa2f5c956749fc3eba4acc60fd485fb100e601070a44fcce54ff358d606698547340b3b6a27bcceb6a42d62a3a8d02a6f0d73653215771de243a63ac048a18b59da2946c901273e616bdbb166c535b26d0d446bc69b22c887c534297c7d01b2ac120237086112b5ef34fc6474e5e941d60aa054b465d4d770d7f850169170ef39150b"
+
+	_, jErr := HandleV2CommitChain(state, msg)
+	if jErr == nil || jErr.Code != -32012 {
+		t.Fatalf("Expected a rejection while syncing, got %v", jErr)
+	}
+	if s, ok := jErr.Data.(*interfaces.APISubmission); !ok || !s.Syncing || s.RetryAfter <= 0 {
+		t.Errorf("Rejection doesn't say when to retry: %v", jErr.Data)
+	}
+	if state.APIQueue().Length() != 0 {
+		t.Error("Rejected commit was queued")
+	}
+
+	state.DBFinished = true
+	resp, jErr := HandleV2CommitChain(state, msg)
+	if jErr != nil {
+		t.Fatalf("%v", jErr)
+	}
+	if s := resp.(*CommitChainResponse).Submission; s == nil || s.Status != interfaces.APISubmitAccepted {
+		t.Errorf("Unexpected submission status %v", s)
+	}
+}
+
+func TestHandleV2CommitChainOverloaded(t *testing.T) {
+	state := testHelper.CreateEmptyTestState()
+	for i := 0; i <= constants.INMSGQUEUE_HIGH; i++ {
+		state.InMsgQueue().Enqueue(new(messages.EOM))
+	}
+
+	resp, jErr := HandleV2Backpressure(state, nil)
+	if jErr != nil {
+		t.Fatalf("%v", jErr)
+	}
+	if bp := resp.(*interfaces.Backpressure); bp.Level != interfaces.LoadOverloaded || bp.RetryAfter <= 0 {
+		t.Errorf("Unexpected backpressure %+v", bp)
+	}
+
+	msg := new(MessageRequest)
+	msg.Message = "00015507b2f70bd0165d9fa19a28cfaafb6bc82f538955a98c7b7e60d79fbf92655c1bff1c76466cb3bc3f3cc68d8b2c111f4f24c88d9c031b4124395c940e5e2c5ea496e8aaa2f5c956749fc3eba4acc60fd485fb100e601070a44fcce54ff358d606698547340b3b6a27bcceb6a42d62a3a8d02a6f0d73653215771de243a63ac048a18b59da2946c901273e616bdbb166c535b26d0d446bc69b22c887c534297c7d01b2ac120237086112b5ef34fc6474e5e941d60aa054b465d4d770d7f850169170ef39150b"
+	_, jErr = HandleV2CommitChain(state, msg)
+	if jErr == nil || jErr.Code != -32013 {
+		t.Fatalf("Expected a rejection while overloaded, got %v", jErr)
+	}
+	if s, ok := jErr.Data.(*interfaces.APISubmission); !ok || !s.Overloaded || s.RetryAfter <= 0 {
+		t.Errorf("Rejection doesn't say when to retry: %v", jErr.Data)
+	}
+	if state.APIQueue().Length() != 0 {
+		t.Error("Rejected commit was queued")
+	}
+}
+
+func TestHandleV2Multisig(t *testing.T) {
+	state := testHelper.CreateEmptyTestState()
+
+	var privs [][]byte
+	req := MultisigAddressRequest{Required: 2}
+	for i := uint64(0); i < 3; i++ {
+		privs = append(privs, testHelper.NewPrivKey(i))
+		req.Keys = append(req.Keys, hex.EncodeToString(testHelper.PrivateKeyToEDPub(privs[i])))
+	}
+	resp, jErr := HandleV2MultisigAddress(state, req)
+	if jErr != nil {
+		t.Fatalf("%v", jErr)
+	}
+	address := resp.(*MultisigAddressResponse)
+	if !primitives.ValidateFUserStr(address.Address) {
+		t.Fatalf("Bad multisig address %v", address.Address)
+	}
+
+	compose := MultisigComposeRequest{
+		Inputs:  []MultisigAmount{{RCD: address.RCD, Amount: 1000000}},
+		Outputs: []MultisigAmount{{Address: primitives.ConvertFctAddressToUserStr(testHelper.NewFactoidAddress(5)), Amount: 900000}},
+	}
+	resp, jErr = HandleV2MultisigCompose(state, compose)
+	if jErr != nil {
+		t.Fatalf("%v", jErr)
+	}
+	tx := resp.(*MultisigTransactionResponse)
+	if tx.Complete || len(tx.Inputs) != 1 || tx.Inputs[0].Address != address.Address || tx.Inputs[0].Required != 2 {
+		t.Fatalf("Unexpected composed transaction %+v", tx)
+	}
+	sigdata, _ := hex.DecodeString(tx.SigData)
+
+	for n, i := range []int{1, 2} {
+		sig := hex.EncodeToString(factoid.NewED25519Signature(privs[i], sigdata).Bytes())
+		resp, jErr = HandleV2MultisigSign(state, MultisigSignRequest{Transaction: tx.Transaction, Signature: sig})
+		if jErr != nil {
+			t.Fatalf("%v", jErr)
+		}
+		tx = resp.(*MultisigTransactionResponse)
+		if len(tx.Inputs[0].SignedBy) != n+1 || tx.Complete != (n == 1) {
+			t.Errorf("Unexpected transaction after %d signatures %+v", n+1, tx)
+		}
+	}
+
+	// Once enough have signed, no more signatures are taken
+	sig := hex.EncodeToString(factoid.NewED25519Signature(privs[0], sigdata).Bytes())
+	if _, jErr = HandleV2MultisigSign(state, MultisigSignRequest{Transaction: tx.Transaction, Signature: sig}); jErr == nil {
+		t.Error("Took a signature past the number required")
+	}
+
+	raw, _ := hex.DecodeString(tx.Transaction)
+	signed := new(factoid.Transaction)
+	if err := signed.UnmarshalBinary(raw); err != nil {
+		t.Fatal(err)
+	}
+	if err := signed.ValidateSignatures(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestHandleV2EthereumAnchor(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestState()
+
+	heightRequest := new(HeightRequest)
+	heightRequest.Height = 1
+	if _, jErr := HandleV2EthereumAnchor(state, heightRequest); jErr == nil || jErr.Message != "Anchor not found" {
+		t.Errorf("Expected the anchor not to be found, got %v", jErr)
+	}
+
+	keyMR, err := state.DB.FetchDBKeyMRByHeight(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ar := &anchor.AnchorRecord{AnchorRecordVer: 1, DBHeight: 1, KeyMR: keyMR.String(), RecordHeight: 1}
+	ar.Ethereum = &anchor.EthereumStruct{Address: "0x02", TXID: "0xab", BlockHeight: 16}
+	if err := state.DB.(*databaseOverlay.Overlay).SaveEthereumAnchor(ar); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, jErr := HandleV2EthereumAnchor(state, heightRequest)
+	if jErr != nil {
+		t.Fatal(jErr)
+	}
+	r := resp.(*EthereumAnchorResponse)
+	if r.DBHeight != 1 || r.KeyMR != keyMR.String() || r.Ethereum.TXID != "0xab" {
+		t.Errorf("Wrong anchor returned: %+v", r)
+	}
+}
+
+func TestHandleV2ReceiptRawEntry(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestState()
+
+	request := new(ReceiptRequest)
+	request.Hash = "be5fb8c3ba92c0436269fab394ff7277c67e9b2de4431b723ce5d89799c0b93a"
+	resp, jErr := HandleV2Receipt(state, request)
+	if jErr != nil {
+		t.Fatal(jErr)
+	}
+	if resp.(*ReceiptResponse).Receipt.Entry.Raw != "" {
+		t.Error("Raw entry included without being asked for")
+	}
+
+	request.IncludeRawEntry = true
+	resp, jErr = HandleV2Receipt(state, request)
+	if jErr != nil {
+		t.Fatal(jErr)
+	}
+	receipt := resp.(*ReceiptResponse).Receipt
+	if receipt.Entry.Raw == "" {
+		t.Error("Raw entry not included")
+	}
+	if len(receipt.Anchors) == 0 {
+		t.Error("No anchors in the receipt")
+	}
+	if err := receipt.Validate(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestHandleV2GetPendingPool(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestState()
+	state.Commits.MaxCommits = 5
+
+	resp, jerr := HandleV2GetPendingPool(state, nil)
+	if jerr != nil {
+		t.Fatalf("%v", jerr)
+	}
+	pool := resp.(*interfaces.PendingPool)
+	if pool.MaxCommits != 5 {
+		t.Errorf("MaxCommits %d, expected 5", pool.MaxCommits)
+	}
+	if len(pool.Commits) != state.Commits.Len() {
+		t.Errorf("Listed %d commits of %d", len(pool.Commits), state.Commits.Len())
+	}
+}
+
+func TestHandleV2PeerReputation(t *testing.T) {
+	state := testHelper.CreateEmptyTestState()
+
+	resp, jerr := HandleV2PeerReputation(state, nil)
+	if jerr != nil {
+		t.Fatalf("%v", jerr)
+	}
+	// Off the network there are no peers, and the list is empty rather than null
+	data, err := json.Marshal(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"peers":[]}` {
+		t.Errorf("Got %s", data)
+	}
+}
+
+func TestHandleV2ObjectStore(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestState()
+	state.DB.(*databaseOverlay.Overlay).EnableObjectStore(1024, 10)
+	key := primitives.RandomHash().String()
+	put := map[string]interface{}{"namespace": "index", "key": key, "data": "0102"}
+
+	// The store needs credentials
+	if _, jerr := HandleV2ObjectPut(state, put); jerr == nil {
+		t.Error("Object stored on an open API")
+	}
+	state.RpcUser = "user"
+
+	if _, jerr := HandleV2ObjectPut(state, put); jerr != nil {
+		t.Fatalf("%v", jerr)
+	}
+	resp, jerr := HandleV2ObjectGet(state, map[string]interface{}{"namespace": "index", "key": key})
+	if jerr != nil {
+		t.Fatalf("%v", jerr)
+	}
+	if data := resp.(*ObjectResponse).Data; data != "0102" {
+		t.Errorf("Got data %s, expected 0102", data)
+	}
+	resp, jerr = HandleV2ObjectList(state, map[string]interface{}{"namespace": "index"})
+	if jerr != nil || len(resp.(*ObjectListResponse).Keys) != 1 {
+		t.Errorf("Listed %v - %v", resp, jerr)
+	}
+
+	if _, jerr := HandleV2ObjectPut(state, map[string]interface{}{"namespace": "bad/ns", "key": key}); jerr == nil {
+		t.Error("Object stored in an invalid namespace")
+	}
+	if _, jerr := HandleV2ObjectDelete(state, map[string]interface{}{"namespace": "index", "key": key}); jerr != nil {
+		t.Fatalf("%v", jerr)
+	}
+	if _, jerr := HandleV2ObjectGet(state, map[string]interface{}{"namespace": "index", "key": key}); jerr == nil {
+		t.Error("Got an object after deleting it")
+	}
+}